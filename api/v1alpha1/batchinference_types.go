@@ -0,0 +1,214 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=batchinferences,scope=Namespaced,shortName=bi
+// +kubebuilder:printcolumn:name="Workspace",type=string,JSONPath=`.status.workspaceName`
+// +kubebuilder:printcolumn:name="Shards",type=integer,JSONPath=`.spec.shards`
+// +kubebuilder:printcolumn:name="Processed",type=integer,JSONPath=`.status.processed`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// BatchInference runs offline inference over a dataset: a Job streams every request of the
+// input dataset through the model served by a Workspace and writes the responses to the output.
+type BatchInference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BatchInferenceSpec   `json:"spec,omitempty"`
+	Status            BatchInferenceStatus `json:"status,omitempty"`
+}
+
+type BatchInferenceSpec struct {
+	// Workspace is the name of a Workspace in the same namespace that serves the model.
+	// Exactly one of Workspace and Preset must be set.
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+	// Preset serves the model from a Workspace that the controller creates for this batch and
+	// deletes once the batch finishes. Exactly one of Workspace and Preset must be set.
+	// +optional
+	Preset *BatchInferencePreset `json:"preset,omitempty"`
+	// Input locates the dataset. It is a JSONL file, or a directory of JSONL files, in the
+	// OpenAI batch format: one {"custom_id", "url", "body"} request per line.
+	// +kubebuilder:validation:Required
+	Input BatchInferenceInput `json:"input"`
+	// Output locates the directory the responses are written to, one output-<shard>.jsonl
+	// file per shard.
+	// +kubebuilder:validation:Required
+	Output BatchInferenceOutput `json:"output"`
+	// Shards is the number of pieces the dataset is split into. Every shard is an index of
+	// the batch Job that is retried on its own.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Shards int32 `json:"shards,omitempty"`
+	// Parallelism is the number of shards processed at the same time. Defaults to Shards.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// Concurrency is the number of requests every shard keeps in flight.
+	// +kubebuilder:default=8
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+	// MaxRetries is the number of times a request that failed with a connection error, a 429
+	// or a 5xx response is retried, with exponential backoff, before it is recorded as failed.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+	// ShardRetries is the number of times a shard whose pod failed is restarted. A restarted
+	// shard resumes after the requests it already wrote to the output.
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	ShardRetries int32 `json:"shardRetries,omitempty"`
+	// RequestTimeoutSeconds bounds a single inference request.
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	RequestTimeoutSeconds int32 `json:"requestTimeoutSeconds,omitempty"`
+}
+
+// BatchInferencePreset describes the Workspace created to serve a batch.
+type BatchInferencePreset struct {
+	// Name of the supported preset model.
+	// +kubebuilder:validation:Required
+	Name v1beta1.ModelName `json:"name"`
+	// InstanceType is the GPU node SKU the Workspace provisions.
+	// +kubebuilder:validation:Required
+	InstanceType string `json:"instanceType"`
+}
+
+// BatchInferenceInput locates the input dataset.
+type BatchInferenceInput struct {
+	// Volume holds the dataset, e.g. a PVC or a CSI volume backed by a storage bucket. It is
+	// mounted read-only.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Required
+	Volume *corev1.VolumeSource `json:"volumeSource"`
+	// Path of the JSONL file, or the directory of JSONL files, relative to the volume root.
+	// Empty reads every JSONL file at the root.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// BatchInferenceOutput locates the directory the responses are written to.
+type BatchInferenceOutput struct {
+	// Volume receives the responses, e.g. a PVC or a CSI volume backed by a storage bucket.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Required
+	Volume *corev1.VolumeSource `json:"volumeSource"`
+	// Path of the output directory relative to the volume root. It is created if missing.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// GetParallelism returns the number of shards processed at the same time.
+func (s *BatchInferenceSpec) GetParallelism() int32 {
+	if s.Parallelism != nil {
+		return *s.Parallelism
+	}
+	return s.Shards
+}
+
+type BatchInferencePhase string
+
+const (
+	// BatchInferencePhasePending waits for the Workspace to become ready.
+	BatchInferencePhasePending BatchInferencePhase = "Pending"
+	// BatchInferencePhaseRunning processes the dataset.
+	BatchInferencePhaseRunning BatchInferencePhase = "Running"
+	// BatchInferencePhaseSucceeded finished every shard.
+	BatchInferencePhaseSucceeded BatchInferencePhase = "Succeeded"
+	// BatchInferencePhaseFailed gave up on at least one shard.
+	BatchInferencePhaseFailed BatchInferencePhase = "Failed"
+)
+
+// BatchInferenceShardPhase is the state of a single shard.
+type BatchInferenceShardPhase string
+
+const (
+	BatchInferenceShardPhasePending   BatchInferenceShardPhase = "Pending"
+	BatchInferenceShardPhaseRunning   BatchInferenceShardPhase = "Running"
+	BatchInferenceShardPhaseSucceeded BatchInferenceShardPhase = "Succeeded"
+	BatchInferenceShardPhaseFailed    BatchInferenceShardPhase = "Failed"
+)
+
+// BatchInferenceShardStatus reports the progress of a shard.
+type BatchInferenceShardStatus struct {
+	// Index of the shard.
+	Index int32 `json:"index"`
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase BatchInferenceShardPhase `json:"phase"`
+	// Attempts is the number of pods that ran the shard.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// Processed is the number of requests the last finished attempt wrote to the output,
+	// including the ones written by earlier attempts.
+	// +optional
+	Processed int64 `json:"processed,omitempty"`
+	// Failed is the number of requests that were recorded as failed after all retries.
+	// +optional
+	Failed int64 `json:"failed,omitempty"`
+}
+
+type BatchInferenceStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase BatchInferencePhase `json:"phase,omitempty"`
+	// WorkspaceName is the Workspace that serves the batch.
+	WorkspaceName string `json:"workspaceName,omitempty"`
+	// JobName is the Job that processes the dataset.
+	JobName string `json:"jobName,omitempty"`
+	// Processed is the total of the shards' processed requests.
+	Processed int64 `json:"processed,omitempty"`
+	// Failed is the total of the shards' failed requests.
+	Failed int64 `json:"failed,omitempty"`
+	// Shards reports the progress of every shard.
+	// +listType=map
+	// +listMapKey=index
+	Shards         []BatchInferenceShardStatus `json:"shards,omitempty"`
+	StartTime      *metav1.Time                `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time                `json:"completionTime,omitempty"`
+	Conditions     []metav1.Condition          `json:"conditions,omitempty"`
+	FailureMessage string                      `json:"failureMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type BatchInferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BatchInference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BatchInference{}, &BatchInferenceList{})
+}
@@ -0,0 +1,119 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+)
+
+// batchInferencePathRegex restricts dataset paths to the characters of ordinary file names; the
+// paths end up in the arguments of the batch Job.
+var batchInferencePathRegex = regexp.MustCompile(`^[A-Za-z0-9._/-]*$`)
+
+func (b *BatchInference) SupportedVerbs() []admissionregistrationv1.OperationType {
+	return []admissionregistrationv1.OperationType{
+		admissionregistrationv1.Create,
+		admissionregistrationv1.Update,
+	}
+}
+
+func (b *BatchInference) Validate(ctx context.Context) (errs *apis.FieldError) {
+	base := apis.GetBaseline(ctx)
+	if base != nil {
+		old := base.(*BatchInference)
+		if !reflect.DeepEqual(b.Spec, old.Spec) {
+			errs = errs.Also(apis.ErrGeneric("BatchInference spec is immutable; delete and recreate to change", "spec"))
+		}
+		return errs
+	}
+
+	// The name is shared by the batch Job and, for a preset, the Workspace and its Service.
+	for _, msg := range validation.IsDNS1123Label(b.Name) {
+		errs = errs.Also(apis.ErrInvalidValue(msg, "name"))
+	}
+	return errs.Also(b.Spec.validateCreate().ViaField("spec"))
+}
+
+func (s *BatchInferenceSpec) validateCreate() (errs *apis.FieldError) {
+	switch {
+	case s.Workspace == "" && s.Preset == nil:
+		errs = errs.Also(apis.ErrMissingOneOf("workspace", "preset"))
+	case s.Workspace != "" && s.Preset != nil:
+		errs = errs.Also(apis.ErrMultipleOneOf("workspace", "preset"))
+	case s.Workspace != "":
+		for _, msg := range validation.IsDNS1123Label(s.Workspace) {
+			errs = errs.Also(apis.ErrInvalidValue(msg, "workspace"))
+		}
+	default:
+		if presetName := string(s.Preset.Name); presetName == "" {
+			errs = errs.Also(apis.ErrMissingField("preset.name"))
+		} else if !plugin.IsValidPreset(presetName) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported preset name %s", presetName), "preset.name"))
+		}
+		if s.Preset.InstanceType == "" {
+			errs = errs.Also(apis.ErrMissingField("preset.instanceType"))
+		}
+	}
+
+	errs = errs.Also(validateBatchInferenceVolume(s.Input.Volume, false).ViaField("input"))
+	errs = errs.Also(validateBatchInferencePath(s.Input.Path).ViaField("input"))
+	errs = errs.Also(validateBatchInferenceVolume(s.Output.Volume, true).ViaField("output"))
+	errs = errs.Also(validateBatchInferencePath(s.Output.Path).ViaField("output"))
+
+	if s.Parallelism != nil && s.Shards > 0 && *s.Parallelism > s.Shards {
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("parallelism %d exceeds the %d shards", *s.Parallelism, s.Shards), "parallelism"))
+	}
+	return errs
+}
+
+// validateBatchInferenceVolume rejects volumes the batch Job must not mount: host paths, which
+// would expose the node to the namespace, and read-only output volumes.
+func validateBatchInferenceVolume(volume *corev1.VolumeSource, writable bool) (errs *apis.FieldError) {
+	if volume == nil {
+		return apis.ErrMissingField("volumeSource")
+	}
+	if volume.HostPath != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("volumeSource.hostPath"))
+	}
+	if writable && volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ReadOnly {
+		errs = errs.Also(apis.ErrInvalidValue("the output volume must be writable", "volumeSource.persistentVolumeClaim.readOnly"))
+	}
+	return errs
+}
+
+// validateBatchInferencePath requires a path that stays inside its volume.
+func validateBatchInferencePath(p string) *apis.FieldError {
+	if p == "" {
+		return nil
+	}
+	if !batchInferencePathRegex.MatchString(p) || path.IsAbs(p) || slices.Contains(strings.Split(p, "/"), "..") {
+		return apis.ErrInvalidValue(
+			fmt.Sprintf("%q must be a relative path inside the volume", p), "path")
+	}
+	return nil
+}
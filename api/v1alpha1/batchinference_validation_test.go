@@ -0,0 +1,172 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+)
+
+func pvcVolume(name string, readOnly bool) *corev1.VolumeSource {
+	return &corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: name, ReadOnly: readOnly},
+	}
+}
+
+func validBatchInference() *BatchInference {
+	return &BatchInference{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-eval", Namespace: "default"},
+		Spec: BatchInferenceSpec{
+			Workspace:             "phi-4",
+			Input:                 BatchInferenceInput{Volume: pvcVolume("datasets", true), Path: "eval/prompts.jsonl"},
+			Output:                BatchInferenceOutput{Volume: pvcVolume("results", false), Path: "eval"},
+			Shards:                4,
+			Concurrency:           8,
+			MaxRetries:            3,
+			ShardRetries:          2,
+			RequestTimeoutSeconds: 600,
+		},
+	}
+}
+
+func TestBatchInferenceValidate(t *testing.T) {
+	RegisterValidationTestModels()
+
+	tests := []struct {
+		name       string
+		mutate     func(b *BatchInference)
+		errContent string
+	}{
+		{
+			name:   "workspace reference",
+			mutate: func(b *BatchInference) {},
+		},
+		{
+			name: "preset",
+			mutate: func(b *BatchInference) {
+				b.Spec.Workspace = ""
+				b.Spec.Preset = &BatchInferencePreset{Name: "test-validation", InstanceType: "Standard_NC24ads_A100_v4"}
+			},
+		},
+		{
+			name:       "neither workspace nor preset",
+			mutate:     func(b *BatchInference) { b.Spec.Workspace = "" },
+			errContent: "expected exactly one, got neither",
+		},
+		{
+			name: "both workspace and preset",
+			mutate: func(b *BatchInference) {
+				b.Spec.Preset = &BatchInferencePreset{Name: "test-validation", InstanceType: "Standard_NC24ads_A100_v4"}
+			},
+			errContent: "expected exactly one, got both",
+		},
+		{
+			name: "unsupported preset",
+			mutate: func(b *BatchInference) {
+				b.Spec.Workspace = ""
+				b.Spec.Preset = &BatchInferencePreset{Name: "no-such-model", InstanceType: "Standard_NC24ads_A100_v4"}
+			},
+			errContent: "Unsupported preset name no-such-model",
+		},
+		{
+			name: "preset without instance type",
+			mutate: func(b *BatchInference) {
+				b.Spec.Workspace = ""
+				b.Spec.Preset = &BatchInferencePreset{Name: "test-validation"}
+			},
+			errContent: "spec.preset.instanceType",
+		},
+		{
+			name:       "name too long for a workspace",
+			mutate:     func(b *BatchInference) { b.Name = strings.Repeat("a", 64) },
+			errContent: "name",
+		},
+		{
+			name:       "missing input volume",
+			mutate:     func(b *BatchInference) { b.Spec.Input.Volume = nil },
+			errContent: "spec.input.volumeSource",
+		},
+		{
+			name: "host path input",
+			mutate: func(b *BatchInference) {
+				b.Spec.Input.Volume = &corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc"}}
+			},
+			errContent: "spec.input.volumeSource.hostPath",
+		},
+		{
+			name:       "read-only output",
+			mutate:     func(b *BatchInference) { b.Spec.Output.Volume = pvcVolume("results", true) },
+			errContent: "the output volume must be writable",
+		},
+		{
+			name:       "absolute input path",
+			mutate:     func(b *BatchInference) { b.Spec.Input.Path = "/eval/prompts.jsonl" },
+			errContent: "spec.input.path",
+		},
+		{
+			name:       "output path escapes the volume",
+			mutate:     func(b *BatchInference) { b.Spec.Output.Path = "eval/../../etc" },
+			errContent: "spec.output.path",
+		},
+		{
+			name:       "output path with shell characters",
+			mutate:     func(b *BatchInference) { b.Spec.Output.Path = "eval;rm -rf" },
+			errContent: "spec.output.path",
+		},
+		{
+			name:       "parallelism above shards",
+			mutate:     func(b *BatchInference) { b.Spec.Parallelism = ptr.To(int32(5)) },
+			errContent: "parallelism 5 exceeds the 4 shards",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := validBatchInference()
+			tc.mutate(b)
+			errs := b.Validate(context.Background())
+			if tc.errContent == "" {
+				if errs != nil {
+					t.Errorf("unexpected error: %v", errs)
+				}
+				return
+			}
+			if errs == nil || !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("expected error containing %q, got %v", tc.errContent, errs)
+			}
+		})
+	}
+}
+
+func TestBatchInferenceValidateUpdate(t *testing.T) {
+	old := validBatchInference()
+
+	unchanged := old.DeepCopy()
+	unchanged.Status.Phase = BatchInferencePhaseRunning
+	if errs := unchanged.Validate(apis.WithinUpdate(context.Background(), old)); errs != nil {
+		t.Errorf("unexpected error for a status-only change: %v", errs)
+	}
+
+	changed := old.DeepCopy()
+	changed.Spec.Concurrency = 16
+	if errs := changed.Validate(apis.WithinUpdate(context.Background(), old)); errs == nil || !strings.Contains(errs.Error(), "immutable") {
+		t.Errorf("expected an immutable spec error, got %v", errs)
+	}
+}
@@ -61,11 +61,12 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 
 	// Check if the ConfigMap exists
 	var cm corev1.ConfigMap
-	if k8sclient.Client == nil {
+	kubeClient := k8sclient.FromContext(ctx)
+	if kubeClient == nil {
 		errs = errs.Also(apis.ErrGeneric("Failed to obtain client from context.Context"))
 		return errs
 	}
-	err = k8sclient.Client.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNS}, &cm)
+	err = kubeClient.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNS}, &cm)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("ConfigMap '%s' specified in 'config' not found in namespace '%s'", cmName, cmNS), "config"))
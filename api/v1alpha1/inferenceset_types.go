@@ -16,6 +16,7 @@ package v1alpha1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 )
@@ -39,6 +40,11 @@ type InferenceSetTemplate struct {
 	// +optional
 	Resource  InferenceSetResourceSpec   `json:"resource"`
 	Inference kaitov1beta1.InferenceSpec `json:"inference"`
+	// MaintenanceWindow restricts disruptive operations on the replicas, such as instance
+	// type migrations and node replacements, to the given days and hours. It is set on every
+	// replica.
+	// +optional
+	MaintenanceWindow *kaitov1beta1.WorkspaceMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AutoUpgradePolicy configures automatic base image upgrade behavior.
@@ -100,6 +106,12 @@ type InferenceSetSpec struct {
 	// +kubebuilder:default:=1
 	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
+	// MinReadyReplicas is the number of ready replicas at which the InferenceSet reports the
+	// Ready condition, either an absolute number or a percentage of spec.replicas rounded
+	// up, e.g. "50%". Defaults to all replicas.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MinReadyReplicas *intstr.IntOrString `json:"minReadyReplicas,omitempty"`
 	// NodeCountLimit is the maximum number of GPU nodes that can be created for the InferenceSet.
 	// If not specified, there is no limit on the number of GPU nodes that can be created.
 	// +optional
@@ -119,6 +131,18 @@ type InferenceSetSpec struct {
 	// Workspace StatefulSets.
 	// +optional
 	AutoUpgrade *AutoUpgradePolicy `json:"autoUpgrade,omitempty"`
+	// Autoscaling makes the controller manage a KEDA ScaledObject that scales the replicas
+	// of the InferenceSet on external events. Requires KEDA to be installed in the cluster.
+	// +optional
+	Autoscaling *kaitov1beta1.InferenceSetAutoscalingSpec `json:"autoscaling,omitempty"`
+	// ShadowTo mirrors a share of the requests the gateway routes to this InferenceSet to a
+	// candidate InferenceSet. Requires the gatewayAPIInferenceExtension feature gate.
+	// +optional
+	ShadowTo *kaitov1beta1.ShadowSpec `json:"shadowTo,omitempty"`
+	// TrafficClasses gives interactive and batch requests their own priority lane in the
+	// endpoint picker of the InferencePool. Requires the gatewayAPIInferenceExtension feature gate.
+	// +optional
+	TrafficClasses *kaitov1beta1.TrafficClassesSpec `json:"trafficClasses,omitempty"`
 }
 
 // Metric holds an aggregated benchmark measurement across workspace replicas.
@@ -24,6 +24,7 @@ import (
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
@@ -60,12 +61,25 @@ func (is *InferenceSet) validateCreate() (errs *apis.FieldError) {
 	}
 	errs = errs.Also(is.validateInstanceType().ViaField("template"))
 	errs = errs.Also(validateMaintenanceWindow(is.Spec.AutoUpgrade))
+	errs = errs.Also(kaitov1beta1.ValidateAutoscaling(is.Spec.Autoscaling, is.Annotations).ViaField("autoscaling"))
+	errs = errs.Also(kaitov1beta1.ValidateShadow(is.Spec.ShadowTo, is.Name).ViaField("shadowTo"))
+	errs = errs.Also(kaitov1beta1.ValidateTrafficClasses(is.Spec.TrafficClasses).ViaField("trafficClasses"))
+	errs = errs.Also(kaitov1beta1.ValidateMinReadyReplicas(is.Spec.MinReadyReplicas).ViaField("minReadyReplicas"))
+	errs = errs.Also(is.Spec.Template.MaintenanceWindow.Validate().ViaField("template.maintenanceWindow"))
+	if preset := is.Spec.Template.Inference.Preset; preset != nil {
+		errs = errs.Also(kaitov1beta1.ValidatePresetEndOfLife(string(preset.Name)).ViaField("template.inference.preset"))
+	}
 	return errs
 }
 
 func (is *InferenceSet) validateUpdate(_ *InferenceSet) (errs *apis.FieldError) {
 	errs = errs.Also(is.validateInstanceType().ViaField("template"))
 	errs = errs.Also(validateMaintenanceWindow(is.Spec.AutoUpgrade))
+	errs = errs.Also(kaitov1beta1.ValidateAutoscaling(is.Spec.Autoscaling, is.Annotations).ViaField("autoscaling"))
+	errs = errs.Also(kaitov1beta1.ValidateShadow(is.Spec.ShadowTo, is.Name).ViaField("shadowTo"))
+	errs = errs.Also(kaitov1beta1.ValidateTrafficClasses(is.Spec.TrafficClasses).ViaField("trafficClasses"))
+	errs = errs.Also(kaitov1beta1.ValidateMinReadyReplicas(is.Spec.MinReadyReplicas).ViaField("minReadyReplicas"))
+	errs = errs.Also(is.Spec.Template.MaintenanceWindow.Validate().ViaField("template.maintenanceWindow"))
 	return errs
 }
 
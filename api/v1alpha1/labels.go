@@ -72,6 +72,10 @@ const (
 	// Set on child InferenceSets and propagated to workspace pods.
 	LabelInferenceRole = KAITOPrefix + "inference-role"
 
+	// LabelInferenceTier identifies the tier of a heterogeneous MultiRoleInference.
+	// Set on child InferenceSets and propagated to workspace pods.
+	LabelInferenceTier = KAITOPrefix + "inference-tier"
+
 	// LabelUpgradeToVersion signals to the Workspace controller that this Workspace
 	// should be upgraded to the specified base image version. Set by the AutoUpgradeRunner;
 	// retained after upgrade completes as an audit trail.
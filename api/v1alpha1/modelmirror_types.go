@@ -76,22 +76,49 @@ const (
 // Supported ModelMirror source registries.
 const (
 	RegistryHuggingFace = "huggingface"
+	// RegistryAzureML downloads a model registered in an Azure Machine Learning workspace.
+	RegistryAzureML = "azureml"
+	// RegistrySageMaker downloads the model data of a SageMaker model package.
+	RegistrySageMaker = "sagemaker"
 )
 
 // SupportedRegistries is the set of accepted ModelMirrorSource.Registry values.
-var SupportedRegistries = []string{RegistryHuggingFace}
+var SupportedRegistries = []string{RegistryHuggingFace, RegistryAzureML, RegistrySageMaker}
 
 type ModelMirrorSource struct {
 	// Registry is the source registry to download the model weights from.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=huggingface
+	// +kubebuilder:validation:Enum=huggingface;azureml;sagemaker
 	Registry string `json:"registry"`
-	// ModelID is the model identifier (e.g. "Qwen/Qwen2.5-Coder-32B-Instruct").
+	// ModelID is the model identifier: the Hugging Face repository (e.g. "Qwen/Qwen2.5-Coder-32B-Instruct"),
+	// the Azure ML model as "<name>:<version>" (e.g. "llama-ft:3"), or the SageMaker model package
+	// ARN (e.g. "arn:aws:sagemaker:us-west-2:123456789012:model-package/llama-ft/3").
 	// +kubebuilder:validation:Required
 	ModelID string `json:"modelID"`
-	// AccessSecret references a secret containing authentication credentials.
+	// AccessSecret references a secret containing authentication credentials: HF_TOKEN for
+	// huggingface, AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET for azureml, and
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and optionally AWS_SESSION_TOKEN for sagemaker.
+	// Omit it to authenticate azureml and sagemaker downloads with the workload identity (IRSA on
+	// EKS) of ServiceAccountName.
 	// +optional
 	AccessSecret *corev1.ObjectReference `json:"accessSecret,omitempty"`
+	// AzureML locates the Azure Machine Learning workspace of an azureml model. Required for the
+	// azureml registry and disallowed otherwise.
+	// +optional
+	AzureML *AzureMLModelSource `json:"azureML,omitempty"`
+}
+
+// AzureMLModelSource locates the Azure Machine Learning workspace a model is registered in.
+type AzureMLModelSource struct {
+	// SubscriptionID is the Azure subscription of the workspace.
+	// +kubebuilder:validation:Required
+	SubscriptionID string `json:"subscriptionID"`
+	// ResourceGroup is the resource group of the workspace.
+	// +kubebuilder:validation:Required
+	ResourceGroup string `json:"resourceGroup"`
+	// Workspace is the name of the Azure Machine Learning workspace.
+	// +kubebuilder:validation:Required
+	Workspace string `json:"workspace"`
 }
 
 type ModelMirrorStorage struct {
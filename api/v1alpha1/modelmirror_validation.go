@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -85,6 +86,7 @@ func (m *ModelMirror) validateManagedMirror(ctx context.Context) (errs *apis.Fie
 		if m.Spec.Source.ModelID == "" {
 			errs = errs.Also(apis.ErrMissingField("spec.source.modelID"))
 		}
+		errs = errs.Also(m.Spec.Source.validateRegistryModel())
 	}
 
 	if m.Spec.Storage == nil {
@@ -101,9 +103,52 @@ func (m *ModelMirror) validateManagedMirror(ctx context.Context) (errs *apis.Fie
 		errs = errs.Also(apis.ErrMissingField("spec.storage.storageClassName"))
 	} else {
 		sc := &storagev1.StorageClass{}
-		if err := k8sclient.Client.Get(ctx, types.NamespacedName{Name: *m.Spec.Storage.StorageClassName}, sc); err != nil {
+		if err := k8sclient.FromContext(ctx).Get(ctx, types.NamespacedName{Name: *m.Spec.Storage.StorageClassName}, sc); err != nil {
 			errs = errs.Also(apis.ErrInvalidValue(*m.Spec.Storage.StorageClassName, "spec.storage.storageClassName"))
 		}
 	}
 	return errs
 }
+
+var (
+	// azureMLModelIDRegex matches "<name>:<version>" of a registered Azure ML model.
+	azureMLModelIDRegex      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,254}:[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+	azureSubscriptionIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	azureResourceGroupRegex  = regexp.MustCompile(`^[A-Za-z0-9_.()-]{0,89}[A-Za-z0-9_()-]$`)
+	azureMLWorkspaceRegex    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{2,32}$`)
+	// sageMakerModelPackageRegex matches the ARN of a versioned or unversioned SageMaker model package.
+	sageMakerModelPackageRegex = regexp.MustCompile(`^arn:aws[a-z-]*:sagemaker:[a-z0-9-]+:[0-9]{12}:model-package/[A-Za-z0-9](-*[A-Za-z0-9]){0,62}(/[0-9]+)?$`)
+)
+
+// validateRegistryModel checks the registry-specific shape of the model reference. The values
+// end up in the download Job, so they are restricted to the characters the registries allow.
+func (s *ModelMirrorSource) validateRegistryModel() (errs *apis.FieldError) {
+	if s.Registry != RegistryAzureML && s.AzureML != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("spec.source.azureML"))
+	}
+	switch s.Registry {
+	case RegistryAzureML:
+		if s.ModelID != "" && !azureMLModelIDRegex.MatchString(s.ModelID) {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("%q must be <name>:<version> of a registered Azure ML model", s.ModelID), "spec.source.modelID"))
+		}
+		if s.AzureML == nil {
+			return errs.Also(apis.ErrMissingField("spec.source.azureML"))
+		}
+		if !azureSubscriptionIDRegex.MatchString(s.AzureML.SubscriptionID) {
+			errs = errs.Also(apis.ErrInvalidValue(s.AzureML.SubscriptionID, "spec.source.azureML.subscriptionID"))
+		}
+		if !azureResourceGroupRegex.MatchString(s.AzureML.ResourceGroup) {
+			errs = errs.Also(apis.ErrInvalidValue(s.AzureML.ResourceGroup, "spec.source.azureML.resourceGroup"))
+		}
+		if !azureMLWorkspaceRegex.MatchString(s.AzureML.Workspace) {
+			errs = errs.Also(apis.ErrInvalidValue(s.AzureML.Workspace, "spec.source.azureML.workspace"))
+		}
+	case RegistrySageMaker:
+		if s.ModelID != "" && !sageMakerModelPackageRegex.MatchString(s.ModelID) {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("%q must be the ARN of a SageMaker model package", s.ModelID), "spec.source.modelID"))
+		}
+	}
+	return errs
+}
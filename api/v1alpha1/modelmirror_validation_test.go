@@ -302,3 +302,81 @@ func TestModelMirrorValidate_Managed_BadSize_ReportsInvalid(t *testing.T) {
 		t.Errorf("expected 'invalid value' for a non-empty bad size, got: %v", err)
 	}
 }
+
+// TestModelMirrorValidate_RegistrySources: azureml and sagemaker sources must reference their
+// models in the registry's own format, and only azureml may set spec.source.azureML.
+func TestModelMirrorValidate_RegistrySources(t *testing.T) {
+	client := fake.NewClientBuilder().
+		WithScheme(newStorageScheme()).
+		WithRuntimeObjects(storageClass("blob-fuse")).
+		Build()
+	k8sclient.SetGlobalClient(client)
+
+	workspace := &AzureMLModelSource{
+		SubscriptionID: "00000000-1111-2222-3333-444444444444",
+		ResourceGroup:  "ml-rg",
+		Workspace:      "ml-ws",
+	}
+	cases := []struct {
+		name    string
+		source  *ModelMirrorSource
+		wantErr string
+	}{
+		{
+			name:   "azureml model",
+			source: &ModelMirrorSource{Registry: RegistryAzureML, ModelID: "llama-ft:3", AzureML: workspace},
+		},
+		{
+			name:    "azureml model without version",
+			source:  &ModelMirrorSource{Registry: RegistryAzureML, ModelID: "llama-ft", AzureML: workspace},
+			wantErr: "spec.source.modelID",
+		},
+		{
+			name:    "azureml model without workspace",
+			source:  &ModelMirrorSource{Registry: RegistryAzureML, ModelID: "llama-ft:3"},
+			wantErr: "spec.source.azureML",
+		},
+		{
+			name: "azureml workspace with a path",
+			source: &ModelMirrorSource{Registry: RegistryAzureML, ModelID: "llama-ft:3", AzureML: &AzureMLModelSource{
+				SubscriptionID: workspace.SubscriptionID, ResourceGroup: "ml-rg", Workspace: "../ws",
+			}},
+			wantErr: "spec.source.azureML.workspace",
+		},
+		{
+			name:   "sagemaker model package",
+			source: &ModelMirrorSource{Registry: RegistrySageMaker, ModelID: "arn:aws:sagemaker:us-west-2:123456789012:model-package/llama-ft/3"},
+		},
+		{
+			name:    "sagemaker model ARN",
+			source:  &ModelMirrorSource{Registry: RegistrySageMaker, ModelID: "arn:aws:sagemaker:us-west-2:123456789012:model/llama-ft"},
+			wantErr: "spec.source.modelID",
+		},
+		{
+			name:    "huggingface with azureML",
+			source:  &ModelMirrorSource{Registry: RegistryHuggingFace, ModelID: "org/model", AzureML: workspace},
+			wantErr: "spec.source.azureML",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &ModelMirror{
+				Spec: ModelMirrorSpec{
+					Source:       tc.source,
+					Storage:      &ModelMirrorStorage{StorageClassName: ptr.To("blob-fuse"), Size: "20Gi"},
+					JobNamespace: "default",
+				},
+			}
+			err := m.Validate(context.Background())
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error mentioning %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
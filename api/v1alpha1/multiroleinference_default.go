@@ -19,7 +19,7 @@ import (
 
 // SetDefaults for the MultiRoleInference.
 func (m *MultiRoleInference) SetDefaults(_ context.Context) {
-	// Default replicas to 1 for each role if not set.
+	// Default replicas to 1 for each role and tier if not set.
 	// When replicas is nil, autoscaling is assumed and the controller
 	// will not reconcile the replica count.
 	for i := range m.Spec.Roles {
@@ -28,4 +28,10 @@ func (m *MultiRoleInference) SetDefaults(_ context.Context) {
 			m.Spec.Roles[i].Replicas = &one
 		}
 	}
+	for i := range m.Spec.Tiers {
+		if m.Spec.Tiers[i].Replicas == nil {
+			one := int32(1)
+			m.Spec.Tiers[i].Replicas = &one
+		}
+	}
 }
@@ -70,7 +70,46 @@ type MultiRoleInferenceRoleSpec struct {
 	RuntimeConfig string `json:"runtimeConfig,omitempty"`
 }
 
+// MultiRoleInferenceTierSpec defines one tier of a heterogeneous deployment, where every
+// tier serves the whole request on its own instance type and requests are placed by
+// prompt length.
+type MultiRoleInferenceTierSpec struct {
+	// Name identifies the tier. The tier's InferenceSet and Service are named
+	// <multiroleinference name>-<tier name>.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +required
+	Name string `json:"name"`
+
+	// Replicas is the number of workspaces (InferenceSet replicas) for this tier.
+	// When nil, the controller does not reconcile the replica count, allowing
+	// external autoscalers to manage scaling independently.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// InstanceType specifies the GPU node SKU for this tier.
+	// This field is required when node auto-provisioning is enabled.
+	// This field must be empty when node auto-provisioning is disabled (BYO scenario).
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// RuntimeConfig references a ConfigMap with tier-specific vLLM runtime arguments,
+	// for example a larger max-model-len on the long-context tier.
+	// +optional
+	RuntimeConfig string `json:"runtimeConfig,omitempty"`
+
+	// MaxPromptTokens is the longest prompt, in tokens, that this tier serves. Longer
+	// prompts are forwarded to the tier with the next larger limit. Exactly one tier
+	// leaves it unset; that tier serves prompts of any length.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPromptTokens *int32 `json:"maxPromptTokens,omitempty"`
+}
+
 // MultiRoleInferenceSpec defines the desired state of MultiRoleInference.
+// +kubebuilder:validation:XValidation:rule="has(self.roles) != has(self.tiers)",message="exactly one of roles or tiers must be set"
 type MultiRoleInferenceSpec struct {
 	// LabelSelector is propagated to generated child workloads (InferenceSets, Workspaces).
 	// The InferencePool uses this selector (plus apps.kubernetes.io/pod-index: "0" for
@@ -91,13 +130,42 @@ type MultiRoleInferenceSpec struct {
 	// +optional
 	EPPPluginsConfig string `json:"eppPluginsConfig,omitempty"`
 
-	// Roles defines the role topology of this inference service.
+	// Roles defines the prefill/decode topology of this inference service.
 	// Exactly two roles are required: one prefill and one decode.
-	// +required
+	// Either roles or tiers must be set.
+	// +optional
 	// +kubebuilder:validation:MinItems=2
 	// +kubebuilder:validation:MaxItems=2
 	// +kubebuilder:validation:XValidation:rule="self.exists(r, r.type == 'prefill') && self.exists(r, r.type == 'decode')",message="exactly one prefill and one decode role required"
-	Roles []MultiRoleInferenceRoleSpec `json:"roles"`
+	Roles []MultiRoleInferenceRoleSpec `json:"roles,omitempty"`
+
+	// Tiers defines a heterogeneous topology in which each tier runs the full model on
+	// its own instance type, e.g. one H100 replica for long-context traffic and two A10
+	// replicas for short prompts. Requests enter the tier with the smallest
+	// maxPromptTokens and are forwarded to the next tier while the prompt is too long.
+	// Either roles or tiers must be set.
+	// +optional
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:XValidation:rule="self.all(t, self.exists_one(o, o.name == t.name))",message="tier names must be unique"
+	// +kubebuilder:validation:XValidation:rule="self.exists_one(t, !has(t.maxPromptTokens))",message="exactly one tier must leave maxPromptTokens unset"
+	Tiers []MultiRoleInferenceTierSpec `json:"tiers,omitempty"`
+}
+
+// MultiRoleInferenceTierStatus reports the replicas of one tier.
+type MultiRoleInferenceTierStatus struct {
+	// Name is the tier name.
+	Name string `json:"name"`
+
+	// InstanceType is the GPU node SKU of the tier.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// Replicas is the number of workspaces of the tier.
+	Replicas int `json:"replicas"`
+
+	// ReadyReplicas is the number of ready workspaces of the tier.
+	ReadyReplicas int `json:"readyReplicas"`
 }
 
 // MultiRoleInferenceStatus defines the observed state of MultiRoleInference.
@@ -109,6 +177,10 @@ type MultiRoleInferenceStatus struct {
 	// ObservedGeneration is the most recent generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Tiers reports the replicas of each tier when spec.tiers is set.
+	// +optional
+	Tiers []MultiRoleInferenceTierStatus `json:"tiers,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -65,8 +65,8 @@ func (m *MultiRoleInference) validateCreate() (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrInvalidValue("labelSelector must have at least one matchLabels or matchExpressions entry", "labelSelector"))
 	}
 
-	// Validate roles.
-	errs = errs.Also(m.validateRoles())
+	// Validate the topology: prefill/decode roles or heterogeneous tiers.
+	errs = errs.Also(m.validateTopology())
 
 	return errs
 }
@@ -80,12 +80,103 @@ func (m *MultiRoleInference) validateUpdate(old *MultiRoleInference) (errs *apis
 		))
 	}
 
-	// Validate roles (same as create).
-	errs = errs.Also(m.validateRoles())
+	// Switching between roles and tiers would replace every child InferenceSet.
+	if (len(m.Spec.Tiers) > 0) != (len(old.Spec.Tiers) > 0) {
+		errs = errs.Also(apis.ErrGeneric("cannot switch between roles and tiers", "roles", "tiers"))
+	}
+
+	// Validate the topology (same as create).
+	errs = errs.Also(m.validateTopology())
+
+	return errs
+}
+
+func (m *MultiRoleInference) validateTopology() *apis.FieldError {
+	switch {
+	case len(m.Spec.Roles) > 0 && len(m.Spec.Tiers) > 0:
+		return apis.ErrMultipleOneOf("roles", "tiers")
+	case len(m.Spec.Tiers) > 0:
+		return m.validateTiers()
+	default:
+		return m.validateRoles()
+	}
+}
+
+// maxTiers bounds the number of tiers, and with it the number of forwarding hops.
+const maxTiers = 4
+
+func (m *MultiRoleInference) validateTiers() (errs *apis.FieldError) {
+	if len(m.Spec.Tiers) < 2 || len(m.Spec.Tiers) > maxTiers {
+		return apis.ErrInvalidValue(
+			fmt.Sprintf("between 2 and %d tiers required, got %d", maxTiers, len(m.Spec.Tiers)),
+			"tiers",
+		)
+	}
+
+	names := make(map[string]bool, len(m.Spec.Tiers))
+	limits := make(map[int32]bool, len(m.Spec.Tiers))
+	unbounded := 0
+	for i, tier := range m.Spec.Tiers {
+		field := fmt.Sprintf("tiers[%d]", i)
+
+		// The tier name becomes part of the InferenceSet and Service names.
+		if errmsgs := validation.IsDNS1123Label(tier.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), field+".name"))
+		} else if errmsgs := validation.IsDNS1035Label(m.Name + "-" + tier.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("%s-%s is not a valid Service name: %s", m.Name, tier.Name, strings.Join(errmsgs, ", ")),
+				field+".name",
+			))
+		}
+		if names[tier.Name] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("duplicate tier name %q", tier.Name), field+".name"))
+		}
+		names[tier.Name] = true
+
+		if tier.MaxPromptTokens == nil {
+			unbounded++
+		} else if *tier.MaxPromptTokens < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(*tier.MaxPromptTokens, field+".maxPromptTokens", "must be at least 1"))
+		} else if limits[*tier.MaxPromptTokens] {
+			errs = errs.Also(apis.ErrInvalidValue(*tier.MaxPromptTokens, field+".maxPromptTokens", "must differ from the other tiers"))
+		} else {
+			limits[*tier.MaxPromptTokens] = true
+		}
+
+		errs = errs.Also(validateInstanceTypeForProvisioner(tier.InstanceType, field))
+
+		if tier.Replicas != nil && *tier.Replicas < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(*tier.Replicas, field+".replicas", "must be at least 1"))
+		}
+	}
+	if unbounded != 1 {
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("exactly one tier must leave maxPromptTokens unset, got %d", unbounded),
+			"tiers",
+		))
+	}
 
 	return errs
 }
 
+// validateInstanceTypeForProvisioner checks instanceType based on the active node provisioner.
+func validateInstanceTypeForProvisioner(instanceType, field string) *apis.FieldError {
+	switch consts.ActiveNodeProvisioner {
+	case consts.NodeProvisionerBYO:
+		if instanceType != "" {
+			return apis.ErrInvalidValue(instanceType, field+".instanceType",
+				"instanceType must be empty when nodeProvisioner is byo")
+		}
+	case consts.NodeProvisionerKarpenter, consts.NodeProvisionerAzureGPU:
+		if instanceType == "" {
+			return apis.ErrMissingField(field + ".instanceType")
+		}
+	default:
+		// Unknown or unset provisioner: no validation (backward compat).
+	}
+	return nil
+}
+
 func (m *MultiRoleInference) validateRoles() (errs *apis.FieldError) {
 	// Validate exactly 2 roles.
 	if len(m.Spec.Roles) != 2 {
@@ -121,19 +212,7 @@ func (m *MultiRoleInference) validateRoles() (errs *apis.FieldError) {
 		}
 
 		// Validate instanceType based on active node provisioner.
-		switch consts.ActiveNodeProvisioner {
-		case consts.NodeProvisionerBYO:
-			if role.InstanceType != "" {
-				errs = errs.Also(apis.ErrInvalidValue(role.InstanceType, field+".instanceType",
-					"instanceType must be empty when nodeProvisioner is byo"))
-			}
-		case consts.NodeProvisionerKarpenter, consts.NodeProvisionerAzureGPU:
-			if role.InstanceType == "" {
-				errs = errs.Also(apis.ErrMissingField(field + ".instanceType"))
-			}
-		default:
-			// Unknown or unset provisioner: no validation (backward compat).
-		}
+		errs = errs.Also(validateInstanceTypeForProvisioner(role.InstanceType, field))
 
 		// Validate replicas >= 1 when specified (nil means autoscaling).
 		if role.Replicas != nil && *role.Replicas < 1 {
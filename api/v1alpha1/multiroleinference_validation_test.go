@@ -272,3 +272,136 @@ func TestMultiRoleInference_validateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiRoleInference_validateTiers(t *testing.T) {
+	orig := consts.ActiveNodeProvisioner
+	consts.ActiveNodeProvisioner = consts.NodeProvisionerKarpenter
+	defer func() { consts.ActiveNodeProvisioner = orig }()
+	tieredMRI := func() *MultiRoleInference {
+		return &MultiRoleInference{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "phi",
+				Namespace: "default",
+			},
+			Spec: MultiRoleInferenceSpec{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "phi"},
+				},
+				Model: MultiRoleInferenceModelSpec{Name: "phi-4-mini-instruct"},
+				Tiers: []MultiRoleInferenceTierSpec{
+					{Name: "short", Replicas: int32Ptr(2), InstanceType: "Standard_NV36ads_A10_v5", MaxPromptTokens: int32Ptr(4096)},
+					{Name: "long", Replicas: int32Ptr(1), InstanceType: "Standard_NC40ads_H100_v5"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		mri         func() *MultiRoleInference
+		old         *MultiRoleInference
+		errContains string
+	}{
+		{
+			name: "valid tiers",
+			mri:  tieredMRI,
+		},
+		{
+			name: "roles and tiers",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Roles = []MultiRoleInferenceRoleSpec{
+					{Type: MultiRoleInferenceRolePrefill, InstanceType: "Standard_NC24ads_A100_v4"},
+					{Type: MultiRoleInferenceRoleDecode, InstanceType: "Standard_NC24ads_A100_v4"},
+				}
+				return m
+			},
+			errContains: "expected exactly one, got both",
+		},
+		{
+			name: "single tier",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers = m.Spec.Tiers[1:]
+				return m
+			},
+			errContains: "between 2 and 4 tiers required",
+		},
+		{
+			name: "two unbounded tiers",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers[0].MaxPromptTokens = nil
+				return m
+			},
+			errContains: "exactly one tier must leave maxPromptTokens unset, got 2",
+		},
+		{
+			name: "equal limits",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers = append(m.Spec.Tiers, MultiRoleInferenceTierSpec{
+					Name: "medium", InstanceType: "Standard_NC24ads_A100_v4", MaxPromptTokens: int32Ptr(4096),
+				})
+				return m
+			},
+			errContains: "must differ from the other tiers",
+		},
+		{
+			name: "duplicate names",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers[1].Name = "short"
+				return m
+			},
+			errContains: "duplicate tier name",
+		},
+		{
+			name: "invalid name",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers[0].Name = "Short"
+				return m
+			},
+			errContains: "tiers[0].name",
+		},
+		{
+			name: "missing instanceType",
+			mri: func() *MultiRoleInference {
+				m := tieredMRI()
+				m.Spec.Tiers[1].InstanceType = ""
+				return m
+			},
+			errContains: "tiers[1].instanceType",
+		},
+		{
+			name: "switch from roles",
+			mri:  tieredMRI,
+			old: &MultiRoleInference{Spec: MultiRoleInferenceSpec{
+				Model: MultiRoleInferenceModelSpec{Name: "phi-4-mini-instruct"},
+				Roles: []MultiRoleInferenceRoleSpec{
+					{Type: MultiRoleInferenceRolePrefill, InstanceType: "Standard_NC24ads_A100_v4"},
+					{Type: MultiRoleInferenceRoleDecode, InstanceType: "Standard_NC24ads_A100_v4"},
+				},
+			}},
+			errContains: "cannot switch between roles and tiers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.old != nil {
+				ctx = apis.WithinUpdate(ctx, tt.old)
+			}
+			err := tt.mri().Validate(ctx)
+			if tt.errContains == "" {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), tt.errContains)
+			}
+		})
+	}
+}
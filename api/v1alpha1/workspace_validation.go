@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
@@ -115,6 +116,13 @@ func (w *Workspace) validateCreate() (errs *apis.FieldError) {
 		errs = errs.Also(errmsgs)
 	}
 
+	if w.Inference != nil && w.Inference.Preset != nil {
+		errs = errs.Also(kaitov1beta1.ValidatePresetEndOfLife(string(w.Inference.Preset.Name)).ViaField("inference.preset"))
+	}
+	if w.Tuning != nil && w.Tuning.Preset != nil {
+		errs = errs.Also(kaitov1beta1.ValidatePresetEndOfLife(string(w.Tuning.Preset.Name)).ViaField("tuning.preset"))
+	}
+
 	return errs
 }
 
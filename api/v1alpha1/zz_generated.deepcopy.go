@@ -18,9 +18,11 @@
 package v1alpha1
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -92,6 +94,212 @@ func (in *AutoUpgradeStatus) DeepCopy() *AutoUpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMLModelSource) DeepCopyInto(out *AzureMLModelSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMLModelSource.
+func (in *AzureMLModelSource) DeepCopy() *AzureMLModelSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMLModelSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInference) DeepCopyInto(out *BatchInference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInference.
+func (in *BatchInference) DeepCopy() *BatchInference {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceInput) DeepCopyInto(out *BatchInferenceInput) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceInput.
+func (in *BatchInferenceInput) DeepCopy() *BatchInferenceInput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceList) DeepCopyInto(out *BatchInferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BatchInference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceList.
+func (in *BatchInferenceList) DeepCopy() *BatchInferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceOutput) DeepCopyInto(out *BatchInferenceOutput) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceOutput.
+func (in *BatchInferenceOutput) DeepCopy() *BatchInferenceOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferencePreset) DeepCopyInto(out *BatchInferencePreset) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferencePreset.
+func (in *BatchInferencePreset) DeepCopy() *BatchInferencePreset {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferencePreset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceShardStatus) DeepCopyInto(out *BatchInferenceShardStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceShardStatus.
+func (in *BatchInferenceShardStatus) DeepCopy() *BatchInferenceShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceSpec) DeepCopyInto(out *BatchInferenceSpec) {
+	*out = *in
+	if in.Preset != nil {
+		in, out := &in.Preset, &out.Preset
+		*out = new(BatchInferencePreset)
+		**out = **in
+	}
+	in.Input.DeepCopyInto(&out.Input)
+	in.Output.DeepCopyInto(&out.Output)
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceSpec.
+func (in *BatchInferenceSpec) DeepCopy() *BatchInferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceStatus) DeepCopyInto(out *BatchInferenceStatus) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]BatchInferenceShardStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceStatus.
+func (in *BatchInferenceStatus) DeepCopy() *BatchInferenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -113,7 +321,7 @@ func (in *DataDestination) DeepCopyInto(out *DataDestination) {
 	*out = *in
 	if in.Volume != nil {
 		in, out := &in.Volume, &out.Volume
-		*out = new(corev1.VolumeSource)
+		*out = new(v1.VolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -138,7 +346,7 @@ func (in *DataSource) DeepCopyInto(out *DataSource) {
 	}
 	if in.Volume != nil {
 		in, out := &in.Volume, &out.Volume
-		*out = new(corev1.VolumeSource)
+		*out = new(v1.VolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ImagePullSecrets != nil {
@@ -303,9 +511,14 @@ func (in *InferenceSetSpec) DeepCopyInto(out *InferenceSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MinReadyReplicas != nil {
+		in, out := &in.MinReadyReplicas, &out.MinReadyReplicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
-		*out = new(v1.LabelSelector)
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
@@ -314,6 +527,21 @@ func (in *InferenceSetSpec) DeepCopyInto(out *InferenceSetSpec) {
 		*out = new(AutoUpgradePolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(v1beta1.InferenceSetAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShadowTo != nil {
+		in, out := &in.ShadowTo, &out.ShadowTo
+		*out = new(v1beta1.ShadowSpec)
+		**out = **in
+	}
+	if in.TrafficClasses != nil {
+		in, out := &in.TrafficClasses, &out.TrafficClasses
+		*out = new(v1beta1.TrafficClassesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetSpec.
@@ -331,7 +559,7 @@ func (in *InferenceSetStatus) DeepCopyInto(out *InferenceSetStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -364,6 +592,11 @@ func (in *InferenceSetTemplate) DeepCopyInto(out *InferenceSetTemplate) {
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Resource = in.Resource
 	in.Inference.DeepCopyInto(&out.Inference)
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(v1beta1.WorkspaceMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetTemplate.
@@ -386,7 +619,7 @@ func (in *InferenceSpec) DeepCopyInto(out *InferenceSpec) {
 	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
-		*out = new(corev1.PodTemplateSpec)
+		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Adapters != nil {
@@ -428,7 +661,7 @@ func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
 	*out = *in
 	if in.Duration != nil {
 		in, out := &in.Duration, &out.Duration
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
@@ -522,7 +755,12 @@ func (in *ModelMirrorSource) DeepCopyInto(out *ModelMirrorSource) {
 	*out = *in
 	if in.AccessSecret != nil {
 		in, out := &in.AccessSecret, &out.AccessSecret
-		*out = new(corev1.ObjectReference)
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.AzureML != nil {
+		in, out := &in.AzureML, &out.AzureML
+		*out = new(AzureMLModelSource)
 		**out = **in
 	}
 }
@@ -567,7 +805,7 @@ func (in *ModelMirrorStatus) DeepCopyInto(out *ModelMirrorStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -707,7 +945,7 @@ func (in *MultiRoleInferenceSpec) DeepCopyInto(out *MultiRoleInferenceSpec) {
 	*out = *in
 	if in.LabelSelector != nil {
 		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 	out.Model = in.Model
@@ -718,6 +956,13 @@ func (in *MultiRoleInferenceSpec) DeepCopyInto(out *MultiRoleInferenceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]MultiRoleInferenceTierSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiRoleInferenceSpec.
@@ -735,11 +980,16 @@ func (in *MultiRoleInferenceStatus) DeepCopyInto(out *MultiRoleInferenceStatus)
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]MultiRoleInferenceTierStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiRoleInferenceStatus.
@@ -752,6 +1002,46 @@ func (in *MultiRoleInferenceStatus) DeepCopy() *MultiRoleInferenceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiRoleInferenceTierSpec) DeepCopyInto(out *MultiRoleInferenceTierSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPromptTokens != nil {
+		in, out := &in.MaxPromptTokens, &out.MaxPromptTokens
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiRoleInferenceTierSpec.
+func (in *MultiRoleInferenceTierSpec) DeepCopy() *MultiRoleInferenceTierSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiRoleInferenceTierSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiRoleInferenceTierStatus) DeepCopyInto(out *MultiRoleInferenceTierStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiRoleInferenceTierStatus.
+func (in *MultiRoleInferenceTierStatus) DeepCopy() *MultiRoleInferenceTierStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiRoleInferenceTierStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Performance) DeepCopyInto(out *Performance) {
 	*out = *in
@@ -934,7 +1224,7 @@ func (in *RAGEngineStatus) DeepCopyInto(out *RAGEngineStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -976,7 +1266,7 @@ func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
 	}
 	if in.LabelSelector != nil {
 		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.PreferredNodes != nil {
@@ -1177,7 +1467,7 @@ func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
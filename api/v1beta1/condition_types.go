@@ -23,6 +23,10 @@ const (
 	// ConditionTypeNodeStatus is the state when checking node status.
 	ConditionTypeNodeStatus = ConditionType("NodesReady")
 
+	// ConditionTypeNodeClaimProvisionTimeout is set when NodeClaims are not ready within the
+	// workspace's resource.provisioningTimeout.
+	ConditionTypeNodeClaimProvisionTimeout = ConditionType("NodeClaimProvisionTimeout")
+
 	// ConditionTypeResourceStatus is the state when Resource has been created.
 	ConditionTypeResourceStatus = ConditionType("ResourceReady")
 
@@ -53,6 +57,15 @@ const (
 	// InferenceSetConditionTypeBenchmarkCompleted is set when benchmark is enabled (default).
 	InferenceSetConditionTypeBenchmarkCompleted = ConditionType("BenchmarkCompleted")
 
+	// InferenceSetConditionTypeMigration is True while the InferenceSet rolls its workspaces
+	// over to a new template instance type, and False once all replicas run on it.
+	InferenceSetConditionTypeMigration = ConditionType("Migration")
+
+	// InferenceSetConditionTypeShadowing is set on InferenceSets with spec.shadowTo. It is True
+	// while the HTTPRoutes of the InferenceSet mirror requests to the candidate, and False
+	// with the reason when they cannot.
+	InferenceSetConditionTypeShadowing = ConditionType("Shadowing")
+
 	//WorkspaceConditionTypeSucceeded is the Workspace state that summarizes all operations' states.
 	//For inference, the "True" condition means the inference service is ready to serve requests.
 	//For fine tuning, the "True" condition means the tuning job completes successfully.
@@ -60,6 +73,9 @@ const (
 
 	RAGEngineConditionTypeSucceeded ConditionType = ConditionType("RAGEngineSucceeded")
 
+	// RAGEngineConditionTypeBackupSucceeded reports whether the most recent scheduled backup succeeded.
+	RAGEngineConditionTypeBackupSucceeded ConditionType = ConditionType("BackupSucceeded")
+
 	// ConditionTypeScalingDownStatus is the state when scaling down nodeClaim.
 	ConditionTypeScalingDownStatus = ConditionType("ScalingDownCompleted")
 
@@ -68,6 +84,111 @@ const (
 	// Set by default; omitted when kaito.sh/disable-benchmark is "true".
 	WorkspaceConditionTypeBenchmarkCompleted = ConditionType("BenchmarkCompleted")
 
+	// WorkspaceConditionTypeWorkloadAdopted is set while the Workspace adopts an existing workload
+	// named by the kaito.sh/adopt-workload annotation. The reason reports whether the adopted
+	// workload drifted from the rendered spec.
+	WorkspaceConditionTypeWorkloadAdopted = ConditionType("WorkloadAdopted")
+
+	// WorkspaceConditionTypeAccessGated is True while the preset model is gated and cannot be
+	// downloaded, because its license has not been accepted or the access secret is missing.
+	// No nodes or workloads are created until the gate is cleared.
+	WorkspaceConditionTypeAccessGated = ConditionType("AccessGated")
+
+	// WorkspaceConditionTypeImageVerificationFailed is True while a preset image of the Workspace
+	// fails signature verification. Nothing is rolled out until the image verifies.
+	WorkspaceConditionTypeImageVerificationFailed = ConditionType("ImageVerificationFailed")
+
+	// WorkspaceConditionTypeWorkloadIdentityGated is True while the workload identity of the
+	// Workspace cannot be used to access its storage, e.g. because the federation or a role
+	// assignment is missing. Nothing is rolled out until the identity works.
+	WorkspaceConditionTypeWorkloadIdentityGated = ConditionType("WorkloadIdentityGated")
+
+	// WorkspaceConditionTypeGangAdmitted is set on gang scheduled Workspaces and is True once
+	// the scheduler has admitted all pods of the PodGroup together.
+	WorkspaceConditionTypeGangAdmitted = ConditionType("GangAdmitted")
+
 	// WorkspaceConditionTypeModelMirrorReady indicates the ModelMirror download is complete and model is ready for streaming.
 	WorkspaceConditionTypeModelMirrorReady = ConditionType("ModelMirrorReady")
+
+	// ConditionTypeProgressing is set on Workspaces, InferenceSets and RAGEngines. It is True
+	// while the controller works toward the spec of the current generation, with the phase
+	// as reason, and False once the object is ready, has succeeded or has failed.
+	ConditionTypeProgressing = ConditionType("Progressing")
+
+	// WorkspaceConditionTypeAdapterDeployed is set on tuning Workspaces with tuning.deploy. It is
+	// True once the output adapter has been added to the inference Workspace, and False with
+	// the reason if it cannot be.
+	WorkspaceConditionTypeAdapterDeployed = ConditionType("AdapterDeployed")
+
+	// WorkspaceConditionTypeDiskTooSmall is True when inference pods were evicted for
+	// exhausting their disk or failed with "no space left on device". The message
+	// recommends a resource.storage size. It is cleared when the workspace spec changes.
+	WorkspaceConditionTypeDiskTooSmall = ConditionType("DiskTooSmall")
+
+	// WorkspaceConditionTypeRightSizingRecommended is True while the GPUs of the Workspace have
+	// been underused for the right-sizing window and a smaller instance type fits the model.
+	// It is advisory: the spec is never changed. See status.rightSizing.
+	WorkspaceConditionTypeRightSizingRecommended = ConditionType("RightSizingRecommended")
+
+	// WorkspaceConditionTypeDeprecated is True while the preset of the Workspace is deprecated.
+	// The message names the replacement and the end of life date, after which new workspaces
+	// cannot use the preset. The Workspace itself keeps running.
+	WorkspaceConditionTypeDeprecated = ConditionType("Deprecated")
+
+	// ConditionTypePaused is set on Workspaces and InferenceSets and is True while their
+	// reconciliation is paused by the kaito.sh/reconcile annotation. Its observed generation
+	// is the generation that was current when the pause started; later generations are
+	// applied once the annotation is removed, which also removes the condition.
+	ConditionTypePaused = ConditionType("Paused")
 )
+
+// Phase summarizes the status of a Workspace, InferenceSet or RAGEngine for GitOps tools.
+// +kubebuilder:validation:Enum=Pending;Progressing;Ready;Succeeded;Failed;Deleting
+type Phase string
+
+const (
+	// PhasePending means the controller is waiting for resources or for a precondition,
+	// such as nodes or access to a gated model.
+	PhasePending Phase = "Pending"
+	// PhaseProgressing means the resources exist and are rolling out to the current spec.
+	PhaseProgressing Phase = "Progressing"
+	// PhaseReady means the object serves the current spec.
+	PhaseReady Phase = "Ready"
+	// PhaseSucceeded means the tuning job of a Workspace completed.
+	PhaseSucceeded Phase = "Succeeded"
+	// PhaseFailed means the controller cannot reach the current spec without a change,
+	// such as a failed tuning job or a provisioning timeout.
+	PhaseFailed Phase = "Failed"
+	// PhaseDeleting means the object is being deleted.
+	PhaseDeleting Phase = "Deleting"
+)
+
+// HealthState is the health of a Workspace, InferenceSet or RAGEngine in the vocabulary
+// of Argo CD health checks.
+// +kubebuilder:validation:Enum=Healthy;Progressing;Degraded
+type HealthState string
+
+const (
+	// HealthStateHealthy means the object is ready or its tuning job succeeded.
+	HealthStateHealthy HealthState = "Healthy"
+	// HealthStateProgressing means the object is pending, rolling out or being deleted.
+	HealthStateProgressing HealthState = "Progressing"
+	// HealthStateDegraded means the object failed and needs a spec change.
+	HealthStateDegraded HealthState = "Degraded"
+)
+
+// HealthStatus is the health of an object for GitOps tools. The controllers derive it
+// from the phase on every status update; its fields are a stable contract.
+type HealthStatus struct {
+	// State is the health of the object.
+	State HealthState `json:"state"`
+
+	// Message is a human readable explanation of the state.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that the health was derived for.
+	// A health with an older generation than the object must be treated as Progressing.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
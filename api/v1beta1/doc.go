@@ -15,4 +15,5 @@
 // +kubebuilder:object:generate=true
 // +k8s:defaulter-gen=TypeMeta
 // +groupName=kaito.sh
+// +groupGoName=Kaito
 package v1beta1 // doc.go is discovered by codegen, but not by go build
@@ -22,11 +22,14 @@ import (
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"github.com/kaito-project/kaito/presets/workspace/models"
 )
@@ -55,11 +58,12 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 
 	// Check if the ConfigMap exists
 	var cm corev1.ConfigMap
-	if k8sclient.Client == nil {
+	kubeClient := k8sclient.FromContext(ctx)
+	if kubeClient == nil {
 		errs = errs.Also(apis.ErrGeneric("Failed to obtain client from context.Context"))
 		return errs
 	}
-	err = k8sclient.Client.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNS}, &cm)
+	err = kubeClient.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNS}, &cm)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("ConfigMap '%s' specified in 'config' not found in namespace '%s'", cmName, cmNS), "config"))
@@ -90,7 +94,7 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 			if w.Inference != nil && w.Inference.Preset != nil {
 				presetName := strings.ToLower(string(w.Inference.Preset.Name))
 				if plugin.IsValidPreset(presetName) {
-					modelPreset, err := models.GetModelByName(ctx, presetName, w.Inference.Preset.PresetOptions.ModelAccessSecret, w.Namespace, k8sclient.Client)
+					modelPreset, err := models.GetModelByName(ctx, presetName, w.Inference.Preset.PresetOptions.ModelAccessSecret, w.Namespace, kubeClient)
 					if err != nil {
 						return apis.ErrInvalidValue(fmt.Sprintf("failed to get model preset: %v", err), "preset")
 					}
@@ -107,6 +111,9 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 							)
 						}
 					}
+					if params != nil {
+						errs = errs.Also(w.validateMaxModelLenFitsGPUMemory(rawMaxModelLen, inferenceConfig.VLLM, params, modelPreset.SupportDistributedInference()))
+					}
 				}
 			}
 		}
@@ -114,3 +121,76 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 
 	return errs
 }
+
+// validateMaxModelLenFitsGPUMemory rejects an explicit max-model-len whose KV cache cannot
+// fit the GPUs of the instance type next to the model weights, which makes vLLM fail its
+// startup probe and the pod crash-loop. It uses the same memory model as the node
+// estimator, without the guided decoding reserve, so it only rejects configurations that
+// are certain to fail.
+func (w *Workspace) validateMaxModelLenFitsGPUMemory(rawMaxModelLen string, vllm map[string]string, params *model.PresetParam, distributed bool) *apis.FieldError {
+	// The instance type is only known up front for provisioned, unpartitioned nodes.
+	if w.Resource.InstanceType == "" || w.Resource.nodeAutoProvisioningDisabled() || w.Resource.Partition != nil {
+		return nil
+	}
+	if _, bypass := w.GetAnnotations()[AnnotationBypassResourceChecks]; bypass {
+		return nil
+	}
+	maxModelLen, err := strconv.Atoi(strings.TrimSpace(rawMaxModelLen))
+	if err != nil || params.BytesPerToken <= 0 || params.TotalSafeTensorFileSize == "" {
+		return nil
+	}
+	modelSize, err := resource.ParseQuantity(params.TotalSafeTensorFileSize)
+	if err != nil {
+		return nil
+	}
+	skuHandler, err := sku.GetSKUHandler()
+	if err != nil {
+		return nil
+	}
+	skuConfig := skuHandler.GetGPUConfigBySKU(w.Resource.InstanceType)
+	if skuConfig == nil || skuConfig.GPUCount <= 0 || skuConfig.GPUMem.IsZero() {
+		return nil
+	}
+
+	utilization := model.GPUMemoryUtilization
+	if v, err := strconv.ParseFloat(strings.TrimSpace(vllm["gpu-memory-utilization"]), 64); err == nil && v > 0 && v <= 1 {
+		utilization = v
+	}
+	// An fp8 KV cache stores each token in half the bytes of the model dtype.
+	bytesPerToken := params.BytesPerToken
+	fp8 := strings.HasPrefix(strings.TrimSpace(vllm["kv-cache-dtype"]), "fp8")
+	if fp8 {
+		bytesPerToken /= 2
+	}
+	// Models that support distributed inference get more nodes when the weights do not
+	// fit, so only the KV cache has to fit next to the base overhead.
+	weights := float64(modelSize.Value())
+	if distributed {
+		weights = 0
+	}
+	gpuMemPerGPU := float64(skuConfig.GPUMem.Value()) / float64(skuConfig.GPUCount)
+	limit := model.MaxContextLen(gpuMemPerGPU, skuConfig.GPUCount, utilization, weights, bytesPerToken)
+	if maxModelLen <= limit {
+		return nil
+	}
+
+	if limit <= 0 {
+		return apis.ErrInvalidValue(
+			fmt.Sprintf("max-model-len %d does not fit instance type %s: no GPU memory is left for the KV cache after the model weights and the vLLM runtime overhead; choose an instance type with more GPU memory",
+				maxModelLen, w.Resource.InstanceType),
+			"max-model-len")
+	}
+	// Suggest a round value, as vLLM allocates the KV cache in blocks.
+	if limit >= 1024 {
+		limit = limit / 1024 * 1024
+	}
+	suggestion := fmt.Sprintf("set max-model-len to %d or less", limit)
+	if !fp8 {
+		suggestion += ", set kv-cache-dtype to fp8"
+	}
+	kvCacheGiB := float64(maxModelLen) * float64(bytesPerToken) / float64(skuConfig.GPUCount) / float64(consts.GiBToBytes)
+	return apis.ErrInvalidValue(
+		fmt.Sprintf("max-model-len %d needs %.1fGiB of KV cache per GPU, which does not fit the %s of GPU memory per GPU of instance type %s next to the model weights and the vLLM runtime overhead; %s, or choose an instance type with more GPU memory",
+			maxModelLen, kvCacheGiB, resource.NewQuantity(int64(gpuMemPerGPU), resource.BinarySI), w.Resource.InstanceType, suggestion),
+		"max-model-len")
+}
@@ -16,6 +16,7 @@ package v1beta1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type InferenceSetResourceSpec struct {
@@ -30,6 +31,12 @@ type InferenceSetResourceSpec struct {
 	// Propagated verbatim to each child Workspace.
 	// +optional
 	Partition *PartitionSpec `json:"partition,omitempty"`
+
+	// Placement controls the co-location of each replica with other pods. With
+	// SpreadAcrossHosts, replicas are scheduled on distinct nodes.
+	// Propagated verbatim to each child Workspace.
+	// +optional
+	Placement *PlacementSpec `json:"placement,omitempty"`
 }
 
 // InferenceSetTemplate defines the template for creating InferenceSet instances.
@@ -43,6 +50,11 @@ type InferenceSetTemplate struct {
 	// +optional
 	Resource  InferenceSetResourceSpec `json:"resource"`
 	Inference InferenceSpec            `json:"inference"`
+	// MaintenanceWindow restricts disruptive operations on the replicas, such as instance
+	// type migrations and node replacements, to the given days and hours. It is set on every
+	// replica.
+	// +optional
+	MaintenanceWindow *WorkspaceMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AutoUpgradePolicy configures automatic base image upgrade behavior.
@@ -104,6 +116,12 @@ type InferenceSetSpec struct {
 	// +kubebuilder:default:=1
 	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
+	// MinReadyReplicas is the number of ready replicas at which the InferenceSet reports the
+	// Ready condition, either an absolute number or a percentage of spec.replicas rounded
+	// up, e.g. "50%". Defaults to all replicas.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MinReadyReplicas *intstr.IntOrString `json:"minReadyReplicas,omitempty"`
 	// NodeCountLimit is the maximum number of GPU nodes that can be created for the InferenceSet.
 	// If not specified, there is no limit on the number of GPU nodes that can be created.
 	// +optional
@@ -123,6 +141,176 @@ type InferenceSetSpec struct {
 	// Workspace StatefulSets.
 	// +optional
 	AutoUpgrade *AutoUpgradePolicy `json:"autoUpgrade,omitempty"`
+	// Autoscaling makes the controller manage a KEDA ScaledObject that scales the replicas
+	// of the InferenceSet on external events. Each replica is a Workspace with its own
+	// nodes, so nodes are provisioned and released as KEDA changes the replica count.
+	// Requires KEDA to be installed in the cluster.
+	// +optional
+	Autoscaling *InferenceSetAutoscalingSpec `json:"autoscaling,omitempty"`
+	// ShadowTo mirrors a share of the requests the gateway routes to this InferenceSet to a
+	// candidate InferenceSet, e.g. a new model version, so its quality and latency can be
+	// compared before cutover. The responses of the candidate are discarded. Requires the
+	// gatewayAPIInferenceExtension feature gate and a Gateway implementation that supports
+	// the HTTPRoute RequestMirror filter.
+	// +optional
+	ShadowTo *ShadowSpec `json:"shadowTo,omitempty"`
+	// TrafficClasses gives interactive and batch requests their own priority lane in the
+	// endpoint picker of the InferencePool, so batch traffic cannot starve chat latency.
+	// Requires the gatewayAPIInferenceExtension feature gate.
+	// +optional
+	TrafficClasses *TrafficClassesSpec `json:"trafficClasses,omitempty"`
+}
+
+// ShadowSpec names the candidate InferenceSet that requests are mirrored to.
+type ShadowSpec struct {
+	// InferenceSet is the name of the candidate InferenceSet, in the same namespace.
+	// +kubebuilder:validation:MinLength=1
+	InferenceSet string `json:"inferenceSet"`
+	// Percent is the share of requests to mirror, from 1 to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+}
+
+// TrafficClassesSpec configures the interactive and batch traffic classes of an InferenceSet.
+// Each class is an InferenceObjective of the InferencePool, named <inferenceset>-interactive
+// and <inferenceset>-batch, that requests select with the x-gateway-inference-objective
+// header. Once the replicas are saturated, the endpoint picker queues requests per class and
+// always dispatches the queue of the higher priority first. Requests without the header are
+// queued with priority 0.
+type TrafficClassesSpec struct {
+	// Interactive configures latency sensitive requests such as chat. Its priority defaults
+	// to 10.
+	// +optional
+	Interactive *TrafficClass `json:"interactive,omitempty"`
+	// Batch configures throughput oriented requests such as offline inference. Its priority
+	// defaults to -10.
+	// +optional
+	Batch *TrafficClass `json:"batch,omitempty"`
+	// MaxWaitingRequestsPerReplica is the number of requests waiting in the inference server
+	// of a replica at which the replica is saturated. Requests are queued in the endpoint
+	// picker while every replica is saturated. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaxWaitingRequestsPerReplica *int32 `json:"maxWaitingRequestsPerReplica,omitempty"`
+	// MaxKVCacheUtilizationPercent is the KV cache utilization at which a replica is
+	// saturated. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	MaxKVCacheUtilizationPercent *int32 `json:"maxKVCacheUtilizationPercent,omitempty"`
+}
+
+// TrafficClass configures one traffic class of an InferenceSet.
+type TrafficClass struct {
+	// Priority of the class. When the replicas are saturated, queued requests of a higher
+	// priority are always dispatched first.
+	// +kubebuilder:validation:Minimum=-1000
+	// +kubebuilder:validation:Maximum=1000
+	Priority int32 `json:"priority"`
+}
+
+// Default priorities of the traffic classes.
+const (
+	DefaultInteractivePriority = int32(10)
+	DefaultBatchPriority       = int32(-10)
+)
+
+// InteractivePriority returns the priority of the interactive class.
+func (t *TrafficClassesSpec) InteractivePriority() int32 {
+	if t.Interactive != nil {
+		return t.Interactive.Priority
+	}
+	return DefaultInteractivePriority
+}
+
+// BatchPriority returns the priority of the batch class.
+func (t *TrafficClassesSpec) BatchPriority() int32 {
+	if t.Batch != nil {
+		return t.Batch.Priority
+	}
+	return DefaultBatchPriority
+}
+
+// InferenceSetAutoscalingSpec describes the KEDA ScaledObject of an InferenceSet.
+type InferenceSetAutoscalingSpec struct {
+	// MinReplicas is the lowest number of replicas KEDA scales to. 0 scales the
+	// InferenceSet to zero when no trigger is active. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the highest number of replicas KEDA scales to.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// PollingIntervalSeconds is how often KEDA checks the triggers. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PollingIntervalSeconds *int32 `json:"pollingIntervalSeconds,omitempty"`
+	// CooldownSeconds is how long KEDA waits after the last active trigger before
+	// scaling to MinReplicas when it is 0. Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownSeconds *int32 `json:"cooldownSeconds,omitempty"`
+	// Triggers are the events that scale the InferenceSet. KEDA uses the trigger that
+	// asks for the most replicas.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	Triggers []ScaleTrigger `json:"triggers"`
+}
+
+// ScaleTrigger is a KEDA trigger. Exactly one of its fields must be set.
+type ScaleTrigger struct {
+	// Prometheus scales on the result of a Prometheus query.
+	// +optional
+	Prometheus *PrometheusScaleTrigger `json:"prometheus,omitempty"`
+	// AzureServiceBus scales on the length of an Azure Service Bus queue or subscription.
+	// +optional
+	AzureServiceBus *AzureServiceBusScaleTrigger `json:"azureServiceBus,omitempty"`
+}
+
+// PrometheusScaleTrigger describes a KEDA prometheus trigger.
+type PrometheusScaleTrigger struct {
+	// ServerAddress is the http or https URL of the Prometheus server.
+	// +kubebuilder:validation:MaxLength=2048
+	ServerAddress string `json:"serverAddress"`
+	// Query is the PromQL query. It must return a single element vector or a scalar.
+	// +kubebuilder:validation:MaxLength=4096
+	Query string `json:"query"`
+	// Threshold is the query value per replica KEDA targets, e.g. "10".
+	Threshold string `json:"threshold"`
+	// ActivationThreshold is the query value above which the InferenceSet is scaled up
+	// from zero. Defaults to 0.
+	// +optional
+	ActivationThreshold string `json:"activationThreshold,omitempty"`
+	// AuthenticationRef is the name of a KEDA TriggerAuthentication in the namespace of
+	// the InferenceSet, for Prometheus servers that require authentication.
+	// +optional
+	AuthenticationRef string `json:"authenticationRef,omitempty"`
+}
+
+// AzureServiceBusScaleTrigger describes a KEDA azure-servicebus trigger. Either QueueName,
+// or TopicName and SubscriptionName, must be set.
+type AzureServiceBusScaleTrigger struct {
+	// Namespace is the name of the Service Bus namespace.
+	// +kubebuilder:validation:MaxLength=50
+	Namespace string `json:"namespace"`
+	// QueueName is the queue to scale on.
+	// +optional
+	QueueName string `json:"queueName,omitempty"`
+	// TopicName is the topic of SubscriptionName.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
+	// SubscriptionName is the subscription to scale on.
+	// +optional
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+	// MessageCount is the number of messages per replica KEDA targets. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MessageCount *int32 `json:"messageCount,omitempty"`
+	// AuthenticationRef is the name of a KEDA TriggerAuthentication in the namespace of
+	// the InferenceSet, e.g. one using workload identity, that grants access to the queue.
+	AuthenticationRef string `json:"authenticationRef"`
 }
 
 // Metric and Performance types are defined in workspace_types.go and shared.
@@ -145,9 +333,21 @@ type InferenceSetStatus struct {
 	// AutoUpgrade reports the observed state of automatic base image upgrades.
 	// +optional
 	AutoUpgrade *AutoUpgradeStatus `json:"autoUpgrade,omitempty"`
+	// ObservedGeneration is the generation of the spec that the status reflects.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase summarizes the status. Together with observedGeneration and the Progressing
+	// condition, it lets GitOps tools wait for a change to roll out.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+	// Health is the machine readable health of the object for GitOps tools such as
+	// Argo CD and Flux.
+	// +optional
+	Health *HealthStatus `json:"health,omitempty"`
 }
 
 // InferenceSet is the Schema for the InferenceSet API
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
@@ -155,6 +355,7 @@ type InferenceSetStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description=""
 // +kubebuilder:printcolumn:name="ReadyReplicas",type="integer",JSONPath=".status.readyReplicas",description=""
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description=""
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 type InferenceSet struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -16,15 +16,20 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/robfig/cron/v3"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 
+	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
@@ -60,12 +65,29 @@ func (is *InferenceSet) validateCreate() (errs *apis.FieldError) {
 	}
 	errs = errs.Also(is.validateInstanceType().ViaField("template"))
 	errs = errs.Also(validateInferenceSetMaintenanceWindow(is.Spec.AutoUpgrade))
+	errs = errs.Also(ValidateAutoscaling(is.Spec.Autoscaling, is.Annotations).ViaField("autoscaling"))
+	errs = errs.Also(ValidateShadow(is.Spec.ShadowTo, is.Name).ViaField("shadowTo"))
+	errs = errs.Also(ValidateTrafficClasses(is.Spec.TrafficClasses).ViaField("trafficClasses"))
+	errs = errs.Also(ValidateMinReadyReplicas(is.Spec.MinReadyReplicas).ViaField("minReadyReplicas"))
+	errs = errs.Also(is.validateServiceDNS())
+	errs = errs.Also(is.Spec.Template.Resource.Placement.validate().ViaField("template.resource.placement"))
+	errs = errs.Also(is.Spec.Template.MaintenanceWindow.Validate().ViaField("template.maintenanceWindow"))
+	if preset := is.Spec.Template.Inference.Preset; preset != nil {
+		errs = errs.Also(ValidatePresetEndOfLife(string(preset.Name)).ViaField("template.inference.preset"))
+	}
 	return errs
 }
 
 func (is *InferenceSet) validateUpdate(old *InferenceSet) (errs *apis.FieldError) {
 	errs = errs.Also(is.validateInstanceType().ViaField("template"))
 	errs = errs.Also(validateInferenceSetMaintenanceWindow(is.Spec.AutoUpgrade))
+	errs = errs.Also(ValidateAutoscaling(is.Spec.Autoscaling, is.Annotations).ViaField("autoscaling"))
+	errs = errs.Also(ValidateShadow(is.Spec.ShadowTo, is.Name).ViaField("shadowTo"))
+	errs = errs.Also(ValidateTrafficClasses(is.Spec.TrafficClasses).ViaField("trafficClasses"))
+	errs = errs.Also(ValidateMinReadyReplicas(is.Spec.MinReadyReplicas).ViaField("minReadyReplicas"))
+	errs = errs.Also(is.validateServiceDNS())
+	errs = errs.Also(is.Spec.Template.Resource.Placement.validate().ViaField("template.resource.placement"))
+	errs = errs.Also(is.Spec.Template.MaintenanceWindow.Validate().ViaField("template.maintenanceWindow"))
 	// Partition config is immutable once set.
 	if !apiequality.Semantic.DeepEqual(is.Spec.Template.Resource.Partition, old.Spec.Template.Resource.Partition) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "template", "resource", "partition"))
@@ -93,6 +115,15 @@ func validateInferenceSetMaintenanceWindow(autoUpgrade *AutoUpgradePolicy) (errs
 	return errs
 }
 
+// validateServiceDNS rejects inference.service.dns, since every replica of an InferenceSet
+// gets its own Service and they would publish the same name.
+func (is *InferenceSet) validateServiceDNS() *apis.FieldError {
+	if service := is.Spec.Template.Inference.Service; service != nil && service.DNS != nil {
+		return apis.ErrGeneric("dns is not supported for InferenceSet", "template.inference.service.dns")
+	}
+	return nil
+}
+
 // validateInstanceType ensures instanceType is set when node auto-provisioning
 // is enabled, and is empty when using BYO (Bring Your Own) nodes.
 func (is *InferenceSet) validateInstanceType() (errs *apis.FieldError) {
@@ -114,3 +145,170 @@ func (is *InferenceSet) validateInstanceType() (errs *apis.FieldError) {
 	}
 	return errs
 }
+
+// ValidateShadow checks the shadowing settings of the InferenceSet named name. It is shared
+// by the served InferenceSet versions. A nil spec is valid.
+func ValidateShadow(s *ShadowSpec, name string) (errs *apis.FieldError) {
+	if s == nil {
+		return nil
+	}
+	if !featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("shadowTo requires the %s feature gate", consts.FeatureFlagGatewayAPIInferenceExtension)))
+	}
+	if msgs := validation.IsDNS1123Label(s.InferenceSet); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "inferenceSet"))
+	} else if s.InferenceSet == name {
+		errs = errs.Also(apis.ErrInvalidValue(s.InferenceSet, "inferenceSet", "an InferenceSet cannot shadow itself"))
+	}
+	if s.Percent < 1 || s.Percent > 100 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(s.Percent, 1, 100, "percent"))
+	}
+	return errs
+}
+
+// ValidateMinReadyReplicas checks that the minimum number of ready replicas of an InferenceSet
+// is a positive number or a percentage from 1% to 100%. It is shared by the v1alpha1 and
+// v1beta1 InferenceSet webhooks.
+func ValidateMinReadyReplicas(v *intstr.IntOrString) *apis.FieldError {
+	if v == nil {
+		return nil
+	}
+	if v.Type == intstr.Int {
+		if v.IntVal < 1 {
+			return apis.ErrInvalidValue(v.IntVal, apis.CurrentField, "must be at least 1")
+		}
+		return nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(v.StrVal, "%"))
+	if err != nil || !strings.HasSuffix(v.StrVal, "%") || percent < 1 || percent > 100 {
+		return apis.ErrInvalidValue(v.StrVal, apis.CurrentField, "must be a percentage from 1% to 100%")
+	}
+	return nil
+}
+
+// ValidateTrafficClasses checks the traffic classes of an InferenceSet. It is shared by the
+// v1alpha1 and v1beta1 InferenceSet webhooks.
+func ValidateTrafficClasses(t *TrafficClassesSpec) (errs *apis.FieldError) {
+	if t == nil {
+		return nil
+	}
+	if !featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("trafficClasses requires the %s feature gate", consts.FeatureFlagGatewayAPIInferenceExtension)))
+	}
+	if t.InteractivePriority() <= t.BatchPriority() {
+		errs = errs.Also(apis.ErrInvalidValue(t.BatchPriority(), "batch.priority",
+			fmt.Sprintf("must be lower than the interactive priority %d", t.InteractivePriority())))
+	}
+	if t.MaxWaitingRequestsPerReplica != nil && (*t.MaxWaitingRequestsPerReplica < 1 || *t.MaxWaitingRequestsPerReplica > 1000) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*t.MaxWaitingRequestsPerReplica, 1, 1000, "maxWaitingRequestsPerReplica"))
+	}
+	if t.MaxKVCacheUtilizationPercent != nil && (*t.MaxKVCacheUtilizationPercent < 1 || *t.MaxKVCacheUtilizationPercent > 99) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*t.MaxKVCacheUtilizationPercent, 1, 99, "maxKVCacheUtilizationPercent"))
+	}
+	return errs
+}
+
+// AnnotationKEDAKaitoScalerAutoProvision asks the KEDA KAITO scaler to create the ScaledObject
+// of an InferenceSet, which would compete with the one managed for spec.autoscaling.
+const AnnotationKEDAKaitoScalerAutoProvision = "scaledobject.kaito.sh/auto-provision"
+
+// serviceBusNamespaceRegex matches Azure Service Bus namespace names.
+var serviceBusNamespaceRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]{4,48}[A-Za-z0-9]$`)
+
+// ValidateAutoscaling checks the autoscaling settings of an InferenceSet. It is shared by
+// the served InferenceSet versions. A nil spec is valid.
+func ValidateAutoscaling(a *InferenceSetAutoscalingSpec, annotations map[string]string) (errs *apis.FieldError) {
+	if a == nil {
+		return nil
+	}
+	if annotations[AnnotationKEDAKaitoScalerAutoProvision] == "true" {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("autoscaling cannot be combined with the %s annotation", AnnotationKEDAKaitoScalerAutoProvision)))
+	}
+	if a.MaxReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(a.MaxReplicas, "maxReplicas", "must be at least 1"))
+	}
+	if a.MinReplicas != nil && (*a.MinReplicas < 0 || *a.MinReplicas > a.MaxReplicas) {
+		errs = errs.Also(apis.ErrInvalidValue(*a.MinReplicas, "minReplicas", "must be between 0 and maxReplicas"))
+	}
+	if a.PollingIntervalSeconds != nil && *a.PollingIntervalSeconds < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*a.PollingIntervalSeconds, "pollingIntervalSeconds", "must be at least 1"))
+	}
+	if a.CooldownSeconds != nil && *a.CooldownSeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*a.CooldownSeconds, "cooldownSeconds", "must be non-negative"))
+	}
+	if len(a.Triggers) == 0 {
+		errs = errs.Also(apis.ErrMissingField("triggers"))
+	}
+	for i := range a.Triggers {
+		errs = errs.Also(a.Triggers[i].validate().ViaFieldIndex("triggers", i))
+	}
+	return errs
+}
+
+func (t *ScaleTrigger) validate() (errs *apis.FieldError) {
+	switch {
+	case t.Prometheus != nil && t.AzureServiceBus != nil:
+		return apis.ErrMultipleOneOf("prometheus", "azureServiceBus")
+	case t.Prometheus != nil:
+		return t.Prometheus.validate().ViaField("prometheus")
+	case t.AzureServiceBus != nil:
+		return t.AzureServiceBus.validate().ViaField("azureServiceBus")
+	default:
+		return apis.ErrMissingOneOf("prometheus", "azureServiceBus")
+	}
+}
+
+func (p *PrometheusScaleTrigger) validate() (errs *apis.FieldError) {
+	u, err := url.Parse(p.ServerAddress)
+	switch {
+	case p.ServerAddress == "":
+		errs = errs.Also(apis.ErrMissingField("serverAddress"))
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "":
+		errs = errs.Also(apis.ErrInvalidValue(p.ServerAddress, "serverAddress", "must be an http or https URL"))
+	case u.User != nil:
+		errs = errs.Also(apis.ErrInvalidValue("<redacted>", "serverAddress", "must not contain credentials, use authenticationRef instead"))
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		errs = errs.Also(apis.ErrMissingField("query"))
+	}
+	if v, err := strconv.ParseFloat(p.Threshold, 64); err != nil || v <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(p.Threshold, "threshold", "must be a positive number"))
+	}
+	if p.ActivationThreshold != "" {
+		if v, err := strconv.ParseFloat(p.ActivationThreshold, 64); err != nil || v < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(p.ActivationThreshold, "activationThreshold", "must be a non-negative number"))
+		}
+	}
+	errs = errs.Also(validateTriggerAuthenticationRef(p.AuthenticationRef, false))
+	return errs
+}
+
+func (s *AzureServiceBusScaleTrigger) validate() (errs *apis.FieldError) {
+	if !serviceBusNamespaceRegex.MatchString(s.Namespace) {
+		errs = errs.Also(apis.ErrInvalidValue(s.Namespace, "namespace", "must be an Azure Service Bus namespace name"))
+	}
+	switch {
+	case s.QueueName != "" && (s.TopicName != "" || s.SubscriptionName != ""):
+		errs = errs.Also(apis.ErrGeneric("either queueName, or topicName and subscriptionName, must be set", "queueName", "topicName", "subscriptionName"))
+	case s.QueueName == "" && (s.TopicName == "" || s.SubscriptionName == ""):
+		errs = errs.Also(apis.ErrGeneric("either queueName, or topicName and subscriptionName, must be set", "queueName", "topicName", "subscriptionName"))
+	}
+	if s.MessageCount != nil && *s.MessageCount < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.MessageCount, "messageCount", "must be at least 1"))
+	}
+	errs = errs.Also(validateTriggerAuthenticationRef(s.AuthenticationRef, true))
+	return errs
+}
+
+func validateTriggerAuthenticationRef(name string, required bool) *apis.FieldError {
+	if name == "" {
+		if required {
+			return apis.ErrMissingField("authenticationRef")
+		}
+		return nil
+	}
+	if errmsgs := validation.IsDNS1123Subdomain(name); len(errmsgs) > 0 {
+		return apis.ErrInvalidValue(name, "authenticationRef", strings.Join(errmsgs, ", "))
+	}
+	return nil
+}
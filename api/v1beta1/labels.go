@@ -14,6 +14,10 @@
 package v1beta1
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kaito-project/kaito/pkg/featuregates"
@@ -34,9 +38,42 @@ const (
 	// LabelWorkspaceName is the label for workspace name.
 	LabelWorkspaceName = KAITOPrefix + "workspace"
 
+	// LabelTunedBy is set on inference Workspaces created by tuning.deploy, with the name of
+	// the tuning Workspace.
+	LabelTunedBy = KAITOPrefix + "tuned-by"
+
+	// LabelDatasetLineagePrefix prefixes the lineage label of a dataset. Inference Workspaces
+	// created or updated by tuning.deploy carry one label per dataset their adapters were
+	// trained on; see DatasetLineageLabel.
+	LabelDatasetLineagePrefix = "dataset.lineage." + KAITOPrefix
+
+	// AnnotationLineage is set on the ControllerRevision of a tuning Workspace with the JSON
+	// encoded LineageStatus of the output produced by that revision.
+	AnnotationLineage = KAITOPrefix + "lineage"
+
+	// LabelConfidentialCompute marks nodes that run in a hardware TEE with the GPU in confidential
+	// computing mode. KAITO sets it on the NodeClaims it creates; BYO nodes must be labeled by the admin.
+	LabelConfidentialCompute = KAITOPrefix + "confidential-compute"
+
 	// LabelRAGEngineName is the label for ragengine name.
 	LabelRAGEngineName = KAITOPrefix + "ragengine"
 
+	// LabelRAGEngineShard is the label for the index worker of a sharded ragengine. Shard pods
+	// do not carry LabelRAGEngineName, so the ragengine Service only selects the router.
+	LabelRAGEngineShard = KAITOPrefix + "ragengine-shard"
+
+	// LabelRAGEngineMemory is the label for the Redis server of the conversation memory of a
+	// ragengine. Like shard pods, its pods do not carry LabelRAGEngineName.
+	LabelRAGEngineMemory = KAITOPrefix + "ragengine-memory"
+
+	// LabelRAGEngineIndexEviction is the label for the index eviction Jobs of a ragengine. Its
+	// Jobs do not carry LabelRAGEngineName, so they are not mistaken for backups.
+	LabelRAGEngineIndexEviction = KAITOPrefix + "ragengine-index-eviction"
+
+	// AnnotationRefreshRequestedAt is set by the RAGEngine controller on AutoIndexers, in
+	// RFC 3339 format, when spec.indexPolicies schedules a refresh of their index.
+	AnnotationRefreshRequestedAt = KAITOPrefix + "refresh-requested-at"
+
 	// LabelWorkspaceName is the label for workspace namespace.
 	LabelWorkspaceNamespace = KAITOPrefix + "workspacenamespace"
 
@@ -49,9 +86,24 @@ const (
 	// RAGEngineRevisionAnnotation is the Annotations for revision number
 	RAGEngineRevisionAnnotation = "ragengine.kaito.io/revision"
 
+	// AnnotationExternalDNSHostname and AnnotationExternalDNSTTL are the ExternalDNS
+	// annotations set on the inference Service from inference.service.dns.
+	AnnotationExternalDNSHostname = "external-dns.alpha.kubernetes.io/hostname"
+	AnnotationExternalDNSTTL      = "external-dns.alpha.kubernetes.io/ttl"
+
+	// AnnotationAzureLoadBalancerIdleTimeout and AnnotationAWSLoadBalancerIdleTimeout are the
+	// cloud provider annotations set on a LoadBalancer inference Service from
+	// inference.service.timeouts.idle.
+	AnnotationAzureLoadBalancerIdleTimeout = "service.beta.kubernetes.io/azure-load-balancer-tcp-idle-timeout"
+	AnnotationAWSLoadBalancerIdleTimeout   = "service.beta.kubernetes.io/aws-load-balancer-connection-idle-timeout"
+
 	// AnnotationWorkspaceRuntime is the annotation for runtime selection.
 	AnnotationWorkspaceRuntime = KAITOPrefix + "runtime"
 
+	// AnnotationAllowNamespaceDeletion on a namespace lets it be deleted while KAITO still
+	// manages NodeClaims for resources in it, when namespace deletion protection is enabled.
+	AnnotationAllowNamespaceDeletion = KAITOPrefix + "allow-namespace-deletion"
+
 	// AnnotationBypassResourceChecks allows bypassing resource requirement checks like GPU memory.
 	AnnotationBypassResourceChecks = KAITOPrefix + "bypass-resource-checks"
 
@@ -79,6 +131,17 @@ const (
 	// InferenceRoleDecode is the decode role value for token generation in P/D disaggregated serving.
 	InferenceRoleDecode = "decode"
 
+	// LabelInferenceTier identifies the tier of a workspace in a heterogeneous MultiRoleInference.
+	// Propagated onto the workspace pods and NodeClaims.
+	LabelInferenceTier = KAITOPrefix + "inference-tier"
+
+	// AnnotationTierMaxPromptTokens is the longest prompt, in tokens, served by a tiered workspace.
+	// When set, the tier router sidecar forwards longer prompts to AnnotationTierOverflowService.
+	AnnotationTierMaxPromptTokens = KAITOPrefix + "tier-max-prompt-tokens"
+
+	// AnnotationTierOverflowService is the Service, in the workspace namespace, of the next tier.
+	AnnotationTierOverflowService = KAITOPrefix + "tier-overflow-service"
+
 	// AnnotationPerformanceMode selects the vLLM performance preset.
 	// Valid values are "balanced" (default), "interactivity", and "throughput".
 	//   - "interactivity": optimizes for low per-request latency (fine-grained CUDA
@@ -88,6 +151,139 @@ const (
 	//     aggressive batching, throughput-oriented kernels).
 	// Only supported when the vLLM runtime is used.
 	AnnotationPerformanceMode = KAITOPrefix + "performance-mode"
+
+	// AnnotationManageNodeLabels opts a BYO Workspace into node label reconciliation when set
+	// to "true": the controller adds the labelSelector matchLabels to the Nodes listed in
+	// resource.preferredNodes and removes them again when the Node is released.
+	AnnotationManageNodeLabels = KAITOPrefix + "manage-node-labels"
+
+	// AnnotationNodeLabelsOwner is set on a Node whose labels were added by KAITO and records
+	// the owning Workspace as <namespace>/<name>. Nodes owned by another Workspace are skipped.
+	AnnotationNodeLabelsOwner = KAITOPrefix + "labels-owner"
+
+	// AnnotationNodeManagedLabels is set on a Node next to AnnotationNodeLabelsOwner and lists
+	// the comma-separated label keys KAITO added, so only those are removed on release.
+	AnnotationNodeManagedLabels = KAITOPrefix + "managed-labels"
+
+	// LabelNodeLabelsOwnerUID is set on a Node next to AnnotationNodeLabelsOwner to the UID of
+	// the owning Workspace, so the Nodes a Workspace owns can be listed with a label selector.
+	LabelNodeLabelsOwnerUID = KAITOPrefix + "labels-owner-uid"
+
+	// LabelNvidiaDevicePluginBootstrap is set to "true" on BYO Nodes of a Workspace that have an
+	// NVIDIA GPU but no nvidia.com/gpu capacity when the controller bootstraps the device plugin.
+	// The bootstrap DaemonSets of the chart only run on Nodes with this label.
+	LabelNvidiaDevicePluginBootstrap = KAITOPrefix + "nvidia-device-plugin-bootstrap"
+
+	// AnnotationPropagatedLabels and AnnotationPropagatedAnnotations are set on generated
+	// Services and pod templates and list the comma-separated keys copied from the workspace
+	// through metadataPropagation, so keys that are no longer propagated can be removed.
+	AnnotationPropagatedLabels      = KAITOPrefix + "propagated-labels"
+	AnnotationPropagatedAnnotations = KAITOPrefix + "propagated-annotations"
+
+	// AnnotationServiceAnnotations is set on the inference Service and lists the
+	// comma-separated keys of the annotations KAITO set from inference.service, so keys
+	// that are removed from the spec can be removed from the Service.
+	AnnotationServiceAnnotations = KAITOPrefix + "service-annotations"
+
+	// AnnotationScaleDownDisabledBy is set on a Node whose cluster autoscaler scale down KAITO
+	// disabled and records the Workspace as <namespace>/<name>; only that Workspace enables
+	// the scale down again.
+	AnnotationScaleDownDisabledBy = KAITOPrefix + "scale-down-disabled-by"
+
+	// AnnotationRuntimeChannel enrolls a standalone Workspace in fleet-wide base image
+	// upgrades rolled out by the FleetUpgradeRunner. Valid values are "rapid", "stable",
+	// "pinned" and "pinned@sha256:<digest>". Workspaces without the annotation, or owned
+	// by an InferenceSet, are not managed by the fleet rollout.
+	AnnotationRuntimeChannel = KAITOPrefix + "runtime-channel"
+
+	// AnnotationRuntimeUpgradePaused pauses fleet upgrades for a Workspace when set to "true".
+	// Removing the annotation resumes the rollout.
+	AnnotationRuntimeUpgradePaused = KAITOPrefix + "runtime-upgrade-paused"
+
+	// AnnotationAdoptWorkload names an existing inference workload in the Workspace namespace
+	// that the controller adopts instead of creating a duplicate, as "StatefulSet/<name>" or
+	// "Deployment/<name>". An adopted StatefulSet must be named after the Workspace; it is
+	// kept as is and drift from the rendered spec is reported until the annotation is
+	// removed, after which it is rolled to the rendered spec. An adopted Deployment keeps
+	// serving until the Workspace StatefulSet is ready and is then deleted.
+	AnnotationAdoptWorkload = KAITOPrefix + "adopt-workload"
+
+	// AnnotationReconcile set to ReconcileDisabled pauses the reconciliation of a Workspace or
+	// an InferenceSet, e.g. while an operator hand-tunes its workload for debugging. The
+	// controller then leaves its nodes, workloads and status as they are, except for the
+	// Paused condition, until the annotation is removed. Deletion is not paused.
+	AnnotationReconcile = KAITOPrefix + "reconcile"
+
+	// ReconcileDisabled is the value of AnnotationReconcile that pauses reconciliation.
+	ReconcileDisabled = "disabled"
+
+	// AnnotationGangScheduler opts a Workspace into gang scheduling. KAITO creates a PodGroup
+	// for the given scheduler, either "coscheduling" (scheduler-plugins) or "volcano", so
+	// all pods of the workload are admitted together or not at all.
+	AnnotationGangScheduler = KAITOPrefix + "gang-scheduler"
+
+	// AnnotationSchedulerName overrides the schedulerName set on the pods of a gang scheduled
+	// Workspace. It defaults to the name the gang scheduler is usually installed under.
+	AnnotationSchedulerName = KAITOPrefix + "scheduler-name"
+
+	// AnnotationNodeClaimNaming selects how the NodeClaims of a Workspace are named, either
+	// "Random" (the default, "ws" followed by a hash) or "WorkspaceIndex"
+	// ("<workspace-name>-<namespace-hash>-<index>", reusing the lowest free index).
+	AnnotationNodeClaimNaming = KAITOPrefix + "nodeclaim-naming"
+
+	// AnnotationNodeClaimRequirements is set on each NodeClaim the controller creates and
+	// summarizes its requirements, e.g. the instance type, zone and capacity type.
+	AnnotationNodeClaimRequirements = KAITOPrefix + "nodeclaim-requirements"
+
+	// AnnotationSimulate puts a Workspace in simulation mode when set to "true": the controller
+	// writes the nodes it would provision into status.provisioningPlan and creates no nodes
+	// or workloads. It is used for capacity reviews before a model is deployed.
+	AnnotationSimulate = KAITOPrefix + "simulate"
+
+	// AnnotationCloneTo asks the controller to clone a Workspace. Its value is a JSON encoded
+	// WorkspaceCloneRequest. The controller removes the annotation once the clone is created
+	// or the request failed, and reports the outcome as an event of the Workspace.
+	AnnotationCloneTo = KAITOPrefix + "clone-to"
+
+	// AnnotationClonedFrom is set on a cloned Workspace, and on the ConfigMaps copied for it,
+	// with the source Workspace as <namespace>/<name>.
+	AnnotationClonedFrom = KAITOPrefix + "cloned-from"
+
+	// AnnotationAllowCloneFrom on a namespace lists, separated by commas, the namespaces whose
+	// Workspaces may be cloned into it. Clones within a namespace are always allowed.
+	AnnotationAllowCloneFrom = KAITOPrefix + "allow-clone-from"
+)
+
+// Valid values for AnnotationNodeClaimNaming.
+const (
+	NodeClaimNamingRandom         = "Random"
+	NodeClaimNamingWorkspaceIndex = "WorkspaceIndex"
+)
+
+// Valid values for AnnotationGangScheduler.
+const (
+	GangSchedulerCoscheduling = "coscheduling"
+	GangSchedulerVolcano      = "volcano"
+)
+
+// Workload kinds accepted by AnnotationAdoptWorkload.
+const (
+	AdoptWorkloadKindStatefulSet = "StatefulSet"
+	AdoptWorkloadKindDeployment  = "Deployment"
+)
+
+// Valid values for AnnotationRuntimeChannel.
+const (
+	// RuntimeChannelRapid upgrades the Workspace as soon as a new base image is available.
+	// Rapid Workspaces act as canaries for the stable channel.
+	RuntimeChannelRapid = "rapid"
+	// RuntimeChannelStable upgrades the Workspace one at a time after every rapid
+	// Workspace has upgraded and become ready.
+	RuntimeChannelStable = "stable"
+	// RuntimeChannelPinned keeps the Workspace on its current base image. Followed by
+	// "@sha256:<digest>", it moves the Workspace to that digest of the base image
+	// repository and keeps it there.
+	RuntimeChannelPinned = "pinned"
 )
 
 // Valid values for AnnotationPerformanceMode.
@@ -158,6 +354,7 @@ var reservedSelectorLabelKeys = map[string]struct{}{
 	// KAITO workspace/ragengine identity labels.
 	LabelWorkspaceName:      {},
 	LabelRAGEngineName:      {},
+	LabelRAGEngineShard:     {},
 	LabelWorkspaceNamespace: {},
 	LabelRAGEngineNamespace: {},
 
@@ -197,6 +394,115 @@ func SanitizedMatchLabels(selector *metav1.LabelSelector) map[string]string {
 	return out
 }
 
+// GetServiceAccountName returns the ServiceAccount the workspace pods run as: the one named in
+// spec.serviceAccount, or the one the controller generates, named after the workspace.
+func GetServiceAccountName(ws *Workspace) string {
+	if ws.ServiceAccount != nil && ws.ServiceAccount.Name != "" {
+		return ws.ServiceAccount.Name
+	}
+	return ws.Name
+}
+
+// ServiceAccountGenerated reports whether the controller generates the ServiceAccount of the
+// workspace pods, i.e. spec.serviceAccount names no existing one.
+func ServiceAccountGenerated(ws *Workspace) bool {
+	return ws.ServiceAccount == nil || ws.ServiceAccount.Name == ""
+}
+
+// GetAdoptedWorkload parses AnnotationAdoptWorkload and returns the kind and name of the
+// workload to adopt. ok is false when the annotation is absent or malformed.
+func GetAdoptedWorkload(ws *Workspace) (kind, name string, ok bool) {
+	value, exists := ws.GetAnnotations()[AnnotationAdoptWorkload]
+	if !exists {
+		return "", "", false
+	}
+	kind, name, found := strings.Cut(value, "/")
+	if !found || name == "" || (kind != AdoptWorkloadKindStatefulSet && kind != AdoptWorkloadKindDeployment) {
+		return "", "", false
+	}
+	return kind, name, true
+}
+
+// GetCloneRequest parses AnnotationCloneTo. ok is false when the annotation is absent; err
+// is set when its value is not a WorkspaceCloneRequest.
+func GetCloneRequest(ws *Workspace) (req *WorkspaceCloneRequest, ok bool, err error) {
+	value, exists := ws.GetAnnotations()[AnnotationCloneTo]
+	if !exists {
+		return nil, false, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	req = &WorkspaceCloneRequest{}
+	if err := decoder.Decode(req); err != nil {
+		return nil, true, fmt.Errorf("invalid clone request: %w", err)
+	}
+	return req, true, nil
+}
+
+// sha256DigestPrefix prefixes the digests recorded in LineageStatus.
+const sha256DigestPrefix = "sha256:"
+
+// IsSHA256Digest reports whether digest is "sha256:" followed by 64 lowercase hex characters.
+func IsSHA256Digest(digest string) bool {
+	hexPart, ok := strings.CutPrefix(digest, sha256DigestPrefix)
+	if !ok || len(hexPart) != 64 {
+		return false
+	}
+	for _, r := range hexPart {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsRuntimeChannelPinned reports whether ws is on the pinned runtime channel, with or
+// without a digest.
+func IsRuntimeChannelPinned(ws *Workspace) bool {
+	channel := ws.GetAnnotations()[AnnotationRuntimeChannel]
+	return channel == RuntimeChannelPinned || strings.HasPrefix(channel, RuntimeChannelPinned+"@")
+}
+
+// GetPinnedRuntimeDigest returns the base image digest ws is pinned to with
+// "pinned@sha256:<digest>". ok is false when ws does not pin a valid digest.
+func GetPinnedRuntimeDigest(ws *Workspace) (digest string, ok bool) {
+	digest, ok = strings.CutPrefix(ws.GetAnnotations()[AnnotationRuntimeChannel], RuntimeChannelPinned+"@")
+	if !ok || !IsSHA256Digest(digest) {
+		return "", false
+	}
+	return digest, true
+}
+
+// DatasetLineageLabel returns the label key that marks a Workspace serving an adapter
+// trained on the dataset with the given digest, e.g.
+// "dataset.lineage.kaito.sh/sha256-<first 32 hex characters>". Label names are limited to
+// 63 characters, so the digest is shortened. ok is false when digest is malformed.
+func DatasetLineageLabel(digest string) (key string, ok bool) {
+	if !IsSHA256Digest(digest) {
+		return "", false
+	}
+	return LabelDatasetLineagePrefix + "sha256-" + digest[len(sha256DigestPrefix):len(sha256DigestPrefix)+32], true
+}
+
+// GetGangScheduler returns the gang scheduler named by AnnotationGangScheduler and the
+// scheduler name its pods use. ok is false when gang scheduling is not requested.
+func GetGangScheduler(ws *Workspace) (scheduler, schedulerName string, ok bool) {
+	scheduler, exists := ws.GetAnnotations()[AnnotationGangScheduler]
+	if !exists {
+		return "", "", false
+	}
+	schedulerName = ws.GetAnnotations()[AnnotationSchedulerName]
+	if schedulerName == "" {
+		switch scheduler {
+		case GangSchedulerCoscheduling:
+			schedulerName = "scheduler-plugins-scheduler"
+		case GangSchedulerVolcano:
+			schedulerName = "volcano"
+		}
+	}
+	return scheduler, schedulerName, true
+}
+
 // GetInferenceSetRuntimeName returns the runtime name for an InferenceSet.
 func GetInferenceSetRuntimeName(iObj *InferenceSet) model.RuntimeName {
 	if iObj == nil {
@@ -0,0 +1,82 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+)
+
+// cpuEmbeddingModels are the embedding models small enough to run on CPU, by lowercased
+// model ID. The values are parameter counts in millions.
+var cpuEmbeddingModels = map[string]int{
+	"baai/bge-small-en-v1.5":                  33,
+	"baai/bge-base-en-v1.5":                   109,
+	"baai/bge-large-en-v1.5":                  335,
+	"sentence-transformers/all-minilm-l6-v2":  23,
+	"sentence-transformers/all-minilm-l12-v2": 33,
+	"sentence-transformers/all-mpnet-base-v2": 109,
+	"intfloat/e5-small-v2":                    33,
+	"intfloat/e5-base-v2":                     109,
+	"intfloat/e5-large-v2":                    335,
+	"intfloat/multilingual-e5-small":          118,
+	"intfloat/multilingual-e5-base":           278,
+	"thenlper/gte-small":                      33,
+	"thenlper/gte-base":                       109,
+	"thenlper/gte-large":                      335,
+	"nomic-ai/nomic-embed-text-v1.5":          137,
+	"mixedbread-ai/mxbai-embed-large-v1":      335,
+}
+
+// EmbeddingModelFitsCPU reports whether the embedding model with the given ID is known to
+// be small enough to run on CPU.
+func EmbeddingModelFitsCPU(modelID string) bool {
+	_, ok := cpuEmbeddingModels[strings.ToLower(modelID)]
+	return ok
+}
+
+// EmbeddingOnCPU reports whether the local embedding model of the RAGEngine runs on CPU.
+// It is false when the RAGEngine has no local embedding model.
+func (r *RAGEngine) EmbeddingOnCPU() bool {
+	if r.Spec == nil || r.Spec.Embedding == nil || r.Spec.Embedding.Local == nil {
+		return false
+	}
+	local := r.Spec.Embedding.Local
+	switch local.Accelerator {
+	case EmbeddingAcceleratorCPU:
+		return true
+	case EmbeddingAcceleratorGPU:
+		return false
+	}
+	if r.Spec.Compute != nil && r.Spec.Compute.InstanceType != "" {
+		if _, err := sku.GetGPUConfigBySKU(r.Spec.Compute.InstanceType); err == nil {
+			return false
+		}
+	}
+	return EmbeddingModelFitsCPU(local.ModelID)
+}
+
+// InstanceType returns the instance type of the nodes of the RAGEngine: compute.instanceType,
+// or the default CPU instance type of the cloud provider when it is empty and the local
+// embedding model runs on CPU. It is empty when the RAGEngine has no compute spec.
+func (r *RAGEngine) InstanceType() string {
+	if r.Spec == nil || r.Spec.Compute == nil {
+		return ""
+	}
+	if r.Spec.Compute.InstanceType == "" && r.EmbeddingOnCPU() {
+		return sku.DefaultCPUInstanceType()
+	}
+	return r.Spec.Compute.InstanceType
+}
@@ -0,0 +1,90 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestRAGEngineEmbeddingOnCPU(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             *RAGEngineSpec
+		wantCPU          bool
+		wantInstanceType string
+	}{
+		{
+			name:             "small model without instance type",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}}},
+			wantCPU:          true,
+			wantInstanceType: "Standard_D8s_v5",
+		},
+		{
+			name:             "small model on a GPU instance type",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{InstanceType: "Standard_NV36ads_A10_v5"}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}}},
+			wantCPU:          false,
+			wantInstanceType: "Standard_NV36ads_A10_v5",
+		},
+		{
+			name:             "small model on a CPU instance type",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{InstanceType: "Standard_D4s_v5"}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "sentence-transformers/all-MiniLM-L6-v2"}}},
+			wantCPU:          true,
+			wantInstanceType: "Standard_D4s_v5",
+		},
+		{
+			name:             "unknown model",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "example/large-embedding-model"}}},
+			wantCPU:          false,
+			wantInstanceType: "",
+		},
+		{
+			name:             "small model forced to GPU",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5", Accelerator: EmbeddingAcceleratorGPU}}},
+			wantCPU:          false,
+			wantInstanceType: "",
+		},
+		{
+			name:             "unknown model forced to CPU",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{}, Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{Image: "example.azurecr.io/embedding:1.0", Accelerator: EmbeddingAcceleratorCPU}}},
+			wantCPU:          true,
+			wantInstanceType: "Standard_D8s_v5",
+		},
+		{
+			name:             "remote embedding",
+			spec:             &RAGEngineSpec{Compute: &ResourceSpec{InstanceType: "Standard_D4s_v5"}, Embedding: &EmbeddingSpec{Remote: &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"}}},
+			wantCPU:          false,
+			wantInstanceType: "Standard_D4s_v5",
+		},
+		{
+			name:             "no compute",
+			spec:             &RAGEngineSpec{Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}}},
+			wantCPU:          true,
+			wantInstanceType: "",
+		},
+	}
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RAGEngine{Spec: tt.spec}
+			if got := r.EmbeddingOnCPU(); got != tt.wantCPU {
+				t.Errorf("EmbeddingOnCPU() = %v, want %v", got, tt.wantCPU)
+			}
+			if got := r.InstanceType(); got != tt.wantInstanceType {
+				t.Errorf("InstanceType() = %q, want %q", got, tt.wantInstanceType)
+			}
+		})
+	}
+}
@@ -14,12 +14,23 @@
 package v1beta1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	DefaultGuardrailsPolicyConfigMapName = "ragengine-guardrails-policy-template"
 	GuardrailsPolicyFileName             = "guardrails.yaml"
+	// IndexAuthPolicyFileName is the key of the rendered authorization policy in the
+	// API key Secret of a RAGEngine. It cannot be used as an API key name.
+	IndexAuthPolicyFileName = "policy.json"
+	// IndexAuthMaintenanceKeyName is the key of the API key the controller generates for
+	// the backup and index eviction jobs in the API key Secret. It grants admin access and
+	// cannot be used as an API key name.
+	IndexAuthMaintenanceKeyName = "kaito-maintenance"
+	// BackupSASTokenKey is the key of the SAS token in a backup credentials Secret.
+	BackupSASTokenKey = "AZURE_STORAGE_SAS_TOKEN"
 )
 
 type ConfigMapReference struct {
@@ -74,12 +85,52 @@ type StorageSpec struct {
 	VectorDB *VectorDBConfig `json:"vectorDB,omitempty"`
 }
 
+// RemoteEmbeddingAPIFormat is the request format of a remote embedding service.
+// +kubebuilder:validation:Enum=HuggingFace;OpenAI
+type RemoteEmbeddingAPIFormat string
+
+const (
+	// RemoteEmbeddingAPIFormatHuggingFace posts {"inputs": ...} as the Hugging Face Inference API expects.
+	RemoteEmbeddingAPIFormatHuggingFace RemoteEmbeddingAPIFormat = "HuggingFace"
+	// RemoteEmbeddingAPIFormatOpenAI posts {"model": ..., "input": [...]} to an OpenAI-compatible /v1/embeddings endpoint.
+	RemoteEmbeddingAPIFormatOpenAI RemoteEmbeddingAPIFormat = "OpenAI"
+)
+
 type RemoteEmbeddingSpec struct {
 	// URL points to a publicly available embedding service, such as OpenAI.
+	// For the OpenAI format this is the full embeddings endpoint, e.g. https://api.openai.com/v1/embeddings.
 	URL string `json:"url"`
-	// AccessSecret is the name of the secret that contains the service access token.
+	// AccessSecret is the name of the secret that contains the service access token
+	// under the key REMOTE_EMBEDDING_ACCESS_SECRET. It is sent as a bearer token.
 	// +optional
 	AccessSecret string `json:"accessSecret,omitempty"`
+	// APIFormat is the request format of the embedding service. Defaults to HuggingFace.
+	// +kubebuilder:default=HuggingFace
+	// +optional
+	APIFormat RemoteEmbeddingAPIFormat `json:"apiFormat,omitempty"`
+	// Model is the embedding model name sent with every request. Required for the OpenAI format.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// BatchSize is the maximum number of texts embedded in one request. Defaults to 32.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2048
+	// +optional
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// Timeout bounds a single request to the embedding service. Defaults to 60s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// MaxRetries is how often a request that failed with a connection error, 429 or 5xx
+	// response is retried with exponential backoff. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// HeadersSecret is the name of a secret whose keys are HTTP header names and whose values
+	// are header value templates added to every request, e.g. an api-key header for Azure OpenAI.
+	// Templates may reference {access_token} for the AccessSecret token and {request_id} for an
+	// ID generated per request. An empty value removes the header, e.g. the default Authorization.
+	// +optional
+	HeadersSecret string `json:"headersSecret,omitempty"`
 }
 
 type LocalEmbeddingSpec struct {
@@ -97,8 +148,25 @@ type LocalEmbeddingSpec struct {
 	// ModelAccessSecret is the name of the secret that contains the huggingface access token.
 	// +optional
 	ModelAccessSecret string `json:"modelAccessSecret,omitempty"`
+	// Accelerator selects the hardware the embedding model runs on. Auto, the default, runs
+	// the model on a GPU when compute.instanceType is a GPU instance type, and otherwise on
+	// CPU when the model is small enough, e.g. BAAI/bge-small-en-v1.5. When the model runs on
+	// CPU, compute.instanceType may be left empty to use the default CPU instance type of the
+	// cloud provider.
+	// +optional
+	Accelerator EmbeddingAccelerator `json:"accelerator,omitempty"`
 }
 
+// EmbeddingAccelerator is the hardware a local embedding model runs on.
+// +kubebuilder:validation:Enum=Auto;GPU;CPU
+type EmbeddingAccelerator string
+
+const (
+	EmbeddingAcceleratorAuto EmbeddingAccelerator = "Auto"
+	EmbeddingAcceleratorGPU  EmbeddingAccelerator = "GPU"
+	EmbeddingAcceleratorCPU  EmbeddingAccelerator = "CPU"
+)
+
 type EmbeddingSpec struct {
 	// Remote specifies how to generate embeddings for index data using a remote service.
 	// Note that either Remote or Local needs to be specified, not both.
@@ -132,6 +200,246 @@ type InferenceServiceSpec struct {
 	ContextWindowSize int `json:"contextWindowSize"`
 }
 
+// IndexAuthorizationSpec restricts access to the indexes of a RAGEngine.
+type IndexAuthorizationSpec struct {
+	// Indexes maps an index name to the clients allowed to access it. Indexes that are not
+	// listed cannot be accessed.
+	// +kubebuilder:validation:MinProperties=1
+	Indexes map[string]IndexAccessSpec `json:"indexes"`
+	// Admins lists the clients allowed to call the endpoints that do not target a single
+	// index, such as /backup and /evict, and to access every index. Other clients are denied
+	// these endpoints. KAITO's own backup and index eviction jobs are always allowed.
+	// +optional
+	Admins *IndexAccessSpec `json:"admins,omitempty"`
+}
+
+// IndexAccessSpec lists the clients allowed to access an index. Clients send their
+// credential as a bearer token in the Authorization header.
+type IndexAccessSpec struct {
+	// ServiceAccounts allowed to access the index, as "<namespace>/<name>". The RAG service
+	// verifies service account tokens with a TokenReview, so the controller binds its own
+	// service account to the system:auth-delegator ClusterRole.
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+	// APIKeys names the API keys allowed to access the index. The controller generates a key
+	// for each name and stores it under that name in the Secret "<ragengine>-api-keys".
+	// Removing a name from every index revokes the key.
+	// +optional
+	APIKeys []string `json:"apiKeys,omitempty"`
+}
+
+// BackupCronJobName returns the name of the CronJob that takes the backups of a RAGEngine.
+// Backups are named after the Jobs it creates.
+func BackupCronJobName(ragEngineName string) string {
+	return ragEngineName + "-backup"
+}
+
+// IndexAuthSecretName returns the name of the Secret holding the API keys of a RAGEngine.
+func IndexAuthSecretName(ragEngineName string) string {
+	return ragEngineName + "-api-keys"
+}
+
+// IndexAuthServiceAccountName returns the name of the ServiceAccount the RAG pods run as
+// when authorization is enabled. It may create TokenReviews to verify service account clients.
+func IndexAuthServiceAccountName(ragEngineName string) string {
+	return ragEngineName + "-index-auth"
+}
+
+// BackupLocation is an Azure Blob Storage location for RAGEngine backups.
+type BackupLocation struct {
+	// URL is the container URL with an optional path prefix,
+	// e.g. https://<account>.blob.core.windows.net/<container>/<prefix>.
+	URL string `json:"url"`
+	// CredentialsSecret is the name of a Secret in the same namespace holding a SAS token
+	// for the container under the key "AZURE_STORAGE_SAS_TOKEN". The token needs read and
+	// list permissions for restores, plus write and delete permissions for backups.
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// RAGBackupSpec configures scheduled backups of a RAGEngine.
+type RAGBackupSpec struct {
+	// Schedule is a cron expression (5-field, UTC) defining when backups are taken.
+	Schedule string `json:"schedule"`
+	// Destination is where backups are stored. Each backup is uploaded as
+	// "<destination url>/<backup name>.tar.gz".
+	Destination BackupLocation `json:"destination"`
+	// Retain is the number of most recent backups of this RAGEngine kept in the destination.
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Retain *int32 `json:"retain,omitempty"`
+}
+
+// RAGRestoreSpec selects the backup a new RAGEngine starts from.
+type RAGRestoreSpec struct {
+	// Source is where the backup is stored.
+	Source BackupLocation `json:"source"`
+	// BackupName is the name of the backup to restore, as reported in
+	// status.backup.lastSuccessfulBackup of the RAGEngine that took it.
+	BackupName string `json:"backupName"`
+}
+
+// ShardingStrategy selects the shard a document is indexed on.
+// +kubebuilder:validation:Enum=Hash;MetadataField
+type ShardingStrategy string
+
+const (
+	// ShardingStrategyHash spreads documents evenly by the hash of their document ID.
+	ShardingStrategyHash ShardingStrategy = "Hash"
+	// ShardingStrategyMetadataField keeps documents with the same value of a metadata
+	// field on the same shard.
+	ShardingStrategyMetadataField ShardingStrategy = "MetadataField"
+)
+
+// ShardingSpec splits every index of a RAGEngine across several index workers, so
+// corpora larger than the memory of a single pod can be served.
+type ShardingSpec struct {
+	// Shards is the number of index workers. It cannot be changed after creation.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=32
+	Shards int32 `json:"shards"`
+	// Strategy selects the shard a document is indexed on. Defaults to Hash.
+	// +kubebuilder:default=Hash
+	// +optional
+	Strategy ShardingStrategy `json:"strategy,omitempty"`
+	// MetadataField is the document metadata field routed on with the MetadataField strategy.
+	// Documents without the field are routed by the hash of their document ID.
+	// +optional
+	MetadataField string `json:"metadataField,omitempty"`
+}
+
+// RAGEngineShardName returns the name of the Deployment and Service of an index worker.
+func RAGEngineShardName(ragEngineName string, shard int) string {
+	return fmt.Sprintf("%s-shard-%d", ragEngineName, shard)
+}
+
+// RetrievalMode selects how /retrieve matches documents to a query.
+// +kubebuilder:validation:Enum=Vector;Keyword;Hybrid
+type RetrievalMode string
+
+const (
+	// RetrievalModeVector ranks documents by embedding similarity only.
+	RetrievalModeVector RetrievalMode = "Vector"
+	// RetrievalModeKeyword ranks documents by BM25 keyword relevance only, which suits
+	// exact-match queries such as identifiers or error codes.
+	RetrievalModeKeyword RetrievalMode = "Keyword"
+	// RetrievalModeHybrid fuses vector and BM25 keyword rankings.
+	RetrievalModeHybrid RetrievalMode = "Hybrid"
+)
+
+// RetrievalSpec configures query-time retrieval.
+type RetrievalSpec struct {
+	// Mode selects how documents are matched to a query. Defaults to Hybrid.
+	// +kubebuilder:default=Hybrid
+	// +optional
+	Mode RetrievalMode `json:"mode,omitempty"`
+	// VectorWeightPercent is the share of the fused score given to vector similarity in
+	// Hybrid mode; the remainder goes to keyword relevance. Defaults to 70.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	VectorWeightPercent *int32 `json:"vectorWeightPercent,omitempty"`
+}
+
+// ResponseOptionsSpec configures the sources returned with answers.
+type ResponseOptionsSpec struct {
+	// IncludeCitations numbers the source chunks given to the model as context, asks the
+	// model to cite them as [n] in its answer and returns the number of each chunk in the
+	// "citation" field of the chat completion source_nodes.
+	// +optional
+	IncludeCitations bool `json:"includeCitations,omitempty"`
+	// MaxSourceChunks bounds the number of chunks used as context for an answer and
+	// returned as its sources. When omitted, as many chunks as fit in the context are used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxSourceChunks *int32 `json:"maxSourceChunks,omitempty"`
+	// MetadataFields lists the document metadata fields returned with sources by chat
+	// completions and /retrieve. When omitted, all metadata is returned.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=set
+	// +optional
+	MetadataFields []string `json:"metadataFields,omitempty"`
+}
+
+// ConversationMemoryBackend selects where the chat history of a session is kept.
+// +kubebuilder:validation:Enum=Local;Redis
+type ConversationMemoryBackend string
+
+const (
+	// ConversationMemoryBackendLocal keeps the history in the RAG engine pod next to the
+	// in-memory indexes. It is lost when the pod restarts.
+	ConversationMemoryBackendLocal ConversationMemoryBackend = "Local"
+	// ConversationMemoryBackendRedis keeps the history in a Redis server.
+	ConversationMemoryBackendRedis ConversationMemoryBackend = "Redis"
+)
+
+// ConversationMemorySpec configures the chat history that /v1/chat/completions keeps per
+// session. Clients opt in by sending a "session_id" in the request body.
+type ConversationMemorySpec struct {
+	// Backend stores the chat history. Defaults to Local.
+	// +kubebuilder:default=Local
+	// +optional
+	Backend ConversationMemoryBackend `json:"backend,omitempty"`
+	// Redis configures the Redis backend. When it is omitted with the Redis backend, the
+	// controller deploys a Redis server "<ragengine>-memory" for the RAGEngine.
+	// +optional
+	Redis *RedisMemorySpec `json:"redis,omitempty"`
+	// MaxTurns is the number of previous turns, a user message and its answer, that are
+	// replayed to the model. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxTurns *int32 `json:"maxTurns,omitempty"`
+	// TTL is how long the history of an idle session is kept. Defaults to 24h.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// RedisMemorySpec points the conversation memory at an existing Redis server.
+type RedisMemorySpec struct {
+	// URL of the Redis server, as redis://<host>:<port>[/<db>] or rediss:// for TLS.
+	// +kubebuilder:validation:MaxLength=512
+	URL string `json:"url"`
+	// AccessSecret is the name of a Secret in the same namespace as the RAGEngine with the
+	// Redis password in its "REDIS_PASSWORD" key.
+	// +optional
+	AccessSecret string `json:"accessSecret,omitempty"`
+}
+
+// ConversationMemoryName returns the name of the Deployment, Service and Secret of the
+// Redis server the controller deploys for the conversation memory of a RAGEngine.
+func ConversationMemoryName(ragEngineName string) string {
+	return ragEngineName + "-memory"
+}
+
+// IndexPolicySpec configures the lifecycle of the documents of one index.
+type IndexPolicySpec struct {
+	// Name is the name of the index the policy applies to.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+	// DocumentTTL evicts documents that were added or last changed longer ago than this.
+	// Expired documents are evicted every 15 minutes. Documents whose write time the RAG
+	// service does not know, e.g. after a restart with an external vector database, are
+	// treated as written when the next eviction runs. Must be at least 1h.
+	// +optional
+	DocumentTTL *metav1.Duration `json:"documentTTL,omitempty"`
+	// RefreshSchedule is a cron expression, in UTC, on which the controller asks the
+	// AutoIndexers that write to the index to index their data sources again. It sets the
+	// kaito.sh/refresh-requested-at annotation on every AutoIndexer whose spec.ragEngine
+	// and spec.indexName match.
+	// +optional
+	RefreshSchedule string `json:"refreshSchedule,omitempty"`
+}
+
+// IndexEvictionCronJobName returns the name of the CronJob that evicts expired documents
+// from the indexes of a RAGEngine.
+func IndexEvictionCronJobName(ragEngineName string) string {
+	return ragEngineName + "-index-eviction"
+}
+
 type RAGEngineSpec struct {
 	// Compute specifies the dedicated GPU resource used by an embedding model running locally if required.
 	// +optional
@@ -152,6 +460,40 @@ type RAGEngineSpec struct {
 	// Guardrails configures output guardrails for chat completions.
 	// +optional
 	Guardrails *GuardrailsSpec `json:"guardrails,omitempty"`
+	// Authorization enables per-index access control so multiple teams can share one
+	// RAGEngine. When omitted, every index is accessible without credentials.
+	// +optional
+	Authorization *IndexAuthorizationSpec `json:"authorization,omitempty"`
+	// Backup periodically snapshots all indexes to object storage.
+	// +optional
+	Backup *RAGBackupSpec `json:"backup,omitempty"`
+	// Restore loads the indexes of a backup when the RAGEngine is created. It cannot be
+	// changed after creation.
+	// +optional
+	Restore *RAGRestoreSpec `json:"restore,omitempty"`
+	// Sharding runs one index worker per shard behind a router that assigns documents
+	// to shards and fans queries out to all of them. It cannot be changed after creation.
+	// +optional
+	Sharding *ShardingSpec `json:"sharding,omitempty"`
+	// Retrieval configures how /retrieve matches documents to a query. When omitted,
+	// vector and keyword rankings are fused with a 70/30 weighting.
+	// +optional
+	Retrieval *RetrievalSpec `json:"retrieval,omitempty"`
+	// ConversationMemory keeps the chat history of sessions so clients only send the new
+	// message of each turn. When omitted, chat completions are stateless.
+	// +optional
+	ConversationMemory *ConversationMemorySpec `json:"conversationMemory,omitempty"`
+	// ResponseOptions configures the sources returned with answers. When omitted, all
+	// chunks used as context are returned with all their metadata and no citations.
+	// +optional
+	ResponseOptions *ResponseOptionsSpec `json:"responseOptions,omitempty"`
+	// IndexPolicies configures document expiry and scheduled AutoIndexer refreshes per
+	// index. Indexes without a policy keep their documents until they are deleted.
+	// +kubebuilder:validation:MaxItems=64
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	IndexPolicies []IndexPolicySpec `json:"indexPolicies,omitempty"`
 }
 
 // RAGEngineStatus defines the observed state of RAGEngine
@@ -161,9 +503,95 @@ type RAGEngineStatus struct {
 	WorkerNodes []string `json:"workerNodes,omitempty"`
 
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Backup reports the scheduled backups of the RAGEngine.
+	// +optional
+	Backup *RAGBackupStatus `json:"backup,omitempty"`
+
+	// Restore reports the progress of restoring spec.restore.
+	// +optional
+	Restore *RAGRestoreStatus `json:"restore,omitempty"`
+
+	// Indexes reports the documents of each index and the last eviction and refresh of
+	// the indexes with a policy in spec.indexPolicies.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Indexes []RAGIndexStatus `json:"indexes,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that the status reflects.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status. Together with observedGeneration and the Progressing
+	// condition, it lets GitOps tools wait for a change to roll out.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Health is the machine readable health of the object for GitOps tools such as
+	// Argo CD and Flux.
+	// +optional
+	Health *HealthStatus `json:"health,omitempty"`
+}
+
+// RAGBackupStatus reports the scheduled backups of a RAGEngine.
+type RAGBackupStatus struct {
+	// LastSuccessfulBackup is the name of the most recent backup that completed.
+	// +optional
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+	// LastSuccessfulBackupTime is when the most recent successful backup completed.
+	// +optional
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+}
+
+// RAGIndexStatus reports the documents of an index.
+type RAGIndexStatus struct {
+	// Name is the name of the index.
+	Name string `json:"name"`
+	// DocumentCount is the number of documents in the index after the last eviction.
+	// +optional
+	DocumentCount int64 `json:"documentCount"`
+	// LastEvictionTime is when expired documents were last evicted from the index.
+	// +optional
+	LastEvictionTime *metav1.Time `json:"lastEvictionTime,omitempty"`
+	// EvictedDocuments is the number of documents the last eviction removed.
+	// +optional
+	EvictedDocuments int64 `json:"evictedDocuments"`
+	// LastRefreshTime is when the AutoIndexers of the index were last asked to refresh.
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+}
+
+// RestorePhase is the progress of a RAGEngine restore.
+// +kubebuilder:validation:Enum=Pending;InProgress;Succeeded;Failed
+type RestorePhase string
+
+const (
+	RestorePhasePending    RestorePhase = "Pending"
+	RestorePhaseInProgress RestorePhase = "InProgress"
+	RestorePhaseSucceeded  RestorePhase = "Succeeded"
+	RestorePhaseFailed     RestorePhase = "Failed"
+)
+
+// RAGRestoreStatus reports the progress of a RAGEngine restore.
+type RAGRestoreStatus struct {
+	// BackupName is the backup being restored.
+	BackupName string `json:"backupName"`
+	// Phase is the progress of the restore.
+	Phase RestorePhase `json:"phase"`
+	// Indexes lists the indexes found in the backup once it has been downloaded.
+	// +optional
+	Indexes []string `json:"indexes,omitempty"`
+	// Message describes the last failure, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is when the backup was downloaded and handed to the RAG service.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // RAGEngine is the Schema for the ragengine API
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=ragengines,scope=Namespaced,categories=ragengine,shortName=rag
@@ -171,6 +599,7 @@ type RAGEngineStatus struct {
 // +kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".spec.compute.instanceType",description=""
 // +kubebuilder:printcolumn:name="ResourceReady",type="string",JSONPath=".status.conditions[?(@.type==\"ResourceReady\")].status",description=""
 // +kubebuilder:printcolumn:name="ServiceReady",type="string",JSONPath=".status.conditions[?(@.type==\"ServiceReady\")].status",description=""
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description=""
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 type RAGEngine struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -18,12 +18,18 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,9 +37,24 @@ import (
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
-	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
+// maxCronJobNameLength is the longest CronJob name the API server accepts.
+const maxCronJobNameLength = 52
+
+// maxRAGEngineShards bounds spec.sharding.shards.
+const maxRAGEngineShards = 32
+
+// minConversationMemoryTTL and maxConversationMemoryTTL bound spec.conversationMemory.ttl.
+const (
+	minConversationMemoryTTL = time.Minute
+	maxConversationMemoryTTL = 30 * 24 * time.Hour
+)
+
+// minIndexDocumentTTL bounds spec.indexPolicies[].documentTTL. Expired documents are only
+// evicted every 15 minutes, so shorter TTLs would not be honored.
+const minIndexDocumentTTL = time.Hour
+
 func (w *RAGEngine) SupportedVerbs() []admissionregistrationv1.OperationType {
 	return []admissionregistrationv1.OperationType{
 		admissionregistrationv1.Create,
@@ -78,9 +99,41 @@ func (w *RAGEngine) validateCreate() (errs *apis.FieldError) {
 	}
 
 	if w.Spec.Compute != nil {
-		errs = errs.Also(w.Spec.Compute.validateRAGCreate())
+		errs = errs.Also(w.Spec.Compute.validateRAGCreate(w.EmbeddingOnCPU()))
 	}
 
+	errs = errs.Also(w.Spec.Authorization.validate().ViaField("authorization"))
+	if w.Spec.Backup != nil {
+		errs = errs.Also(w.Spec.Backup.validate().ViaField("backup"))
+		// CronJob names are limited so that the names of their Jobs stay valid labels.
+		if len(BackupCronJobName(w.Name)) > maxCronJobNameLength {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("backups require a RAGEngine name of at most %d characters",
+				maxCronJobNameLength-len(BackupCronJobName(""))), "backup"))
+		}
+	}
+	if w.Spec.Restore != nil {
+		errs = errs.Also(w.Spec.Restore.validate().ViaField("restore"))
+	}
+
+	if w.Spec.Sharding != nil {
+		errs = errs.Also(w.validateSharding().ViaField("sharding"))
+	}
+	errs = errs.Also(w.Spec.Retrieval.validate().ViaField("retrieval"))
+	if w.Spec.ConversationMemory != nil {
+		if w.Spec.InferenceService == nil {
+			errs = errs.Also(apis.ErrGeneric("conversation memory requires an inference service", "conversationMemory"))
+		}
+		errs = errs.Also(w.Spec.ConversationMemory.validate().ViaField("conversationMemory"))
+		if w.Spec.ConversationMemory.Backend == ConversationMemoryBackendRedis && w.Spec.ConversationMemory.Redis == nil {
+			if name := ConversationMemoryName(w.Name); len(validation.IsDNS1035Label(name)) > 0 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("redis service name %q is not a valid service name; use a shorter RAGEngine name or set redis.url", name), "conversationMemory"))
+			}
+		}
+	}
+
+	errs = errs.Also(w.Spec.ResponseOptions.validate().ViaField("responseOptions"))
+	errs = errs.Also(w.validateIndexPolicies())
+
 	if w.Spec.Embedding.Local != nil {
 		errs = errs.Also(w.Spec.Embedding.Local.validateCreate().ViaField("embedding"))
 	}
@@ -100,7 +153,8 @@ func (w *RAGEngine) validateGuardrails(ctx context.Context) (errs *apis.FieldErr
 	if !guardrails.Enabled {
 		return nil
 	}
-	if k8sclient.Client == nil {
+	kubeClient := k8sclient.FromContext(ctx)
+	if kubeClient == nil {
 		return apis.ErrGeneric("Failed to obtain client from context.Context")
 	}
 
@@ -124,7 +178,7 @@ func (w *RAGEngine) validateGuardrails(ctx context.Context) (errs *apis.FieldErr
 	}
 
 	var cm corev1.ConfigMap
-	err := k8sclient.Client.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNamespace}, &cm)
+	err := kubeClient.Get(ctx, client.ObjectKey{Name: cmName, Namespace: cmNamespace}, &cm)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if usesDefaultPolicy {
@@ -169,12 +223,225 @@ func (w *RAGEngine) validateUpdate(old *RAGEngine) (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrGeneric("Compute resources cannot be removed after creation", "compute"))
 	}
 	if w.Spec.Compute != nil && old.Spec.Compute != nil {
-		errs = errs.Also(w.Spec.Compute.validateUpdate(old.Spec.Compute).ViaField("resource"))
+		// Compare the resolved instance types, which are only empty in compute when the
+		// embedding model runs on the default CPU instance type.
+		compute, oldCompute := w.Spec.Compute.DeepCopy(), old.Spec.Compute.DeepCopy()
+		compute.InstanceType, oldCompute.InstanceType = w.InstanceType(), old.InstanceType()
+		errs = errs.Also(compute.validateUpdate(oldCompute).ViaField("resource"))
+		if w.EmbeddingOnCPU() != old.EmbeddingOnCPU() {
+			errs = errs.Also(apis.ErrGeneric("the embedding model cannot be moved between CPU and GPU after creation", "embedding.local"))
+		}
+	}
+	if !reflect.DeepEqual(w.Spec.Restore, old.Spec.Restore) {
+		errs = errs.Also(apis.ErrGeneric("restore cannot be changed after creation", "restore"))
+	}
+	if !reflect.DeepEqual(w.Spec.Sharding, old.Spec.Sharding) {
+		errs = errs.Also(apis.ErrGeneric("sharding cannot be changed after creation", "sharding"))
 	}
 	return errs
 }
 
-func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
+// validateSharding checks the sharding spec and rejects the features that rely on all
+// indexes living in a single pod.
+func (w *RAGEngine) validateSharding() (errs *apis.FieldError) {
+	sharding := w.Spec.Sharding
+	if sharding.Shards < 2 || sharding.Shards > maxRAGEngineShards {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(sharding.Shards, 2, maxRAGEngineShards, "shards"))
+	} else if name := RAGEngineShardName(w.Name, int(sharding.Shards)-1); len(validation.IsDNS1035Label(name)) > 0 {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("shard name %q is not a valid service name; use a shorter RAGEngine name", name), "shards"))
+	} else {
+		errs = errs.Also(w.validateShardGPUs())
+	}
+	switch sharding.Strategy {
+	case "", ShardingStrategyHash:
+	case ShardingStrategyMetadataField:
+		if sharding.MetadataField == "" {
+			errs = errs.Also(apis.ErrMissingField("metadataField"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(sharding.Strategy, "strategy"))
+	}
+
+	unsupported := func(field string) *apis.FieldError {
+		return apis.ErrGeneric(fmt.Sprintf("sharding is not supported together with %s", field), apis.CurrentField)
+	}
+	if w.Spec.Storage != nil && w.Spec.Storage.VectorDB != nil {
+		errs = errs.Also(unsupported("an external vector database"))
+	}
+	if w.Spec.Storage != nil && w.Spec.Storage.PersistentVolume != nil && w.Spec.Storage.PersistentVolume.PersistentVolumeClaim != "" {
+		errs = errs.Also(unsupported("a persistent volume claim"))
+	}
+	if w.Spec.Authorization != nil {
+		errs = errs.Also(unsupported("authorization"))
+	}
+	if w.Spec.Backup != nil || w.Spec.Restore != nil {
+		errs = errs.Also(unsupported("backup and restore"))
+	}
+	if w.Spec.ConversationMemory != nil {
+		errs = errs.Also(unsupported("conversation memory"))
+	}
+	if len(w.Spec.IndexPolicies) > 0 {
+		errs = errs.Also(unsupported("index policies"))
+	}
+	return errs
+}
+
+// validateShardGPUs checks that every index worker of a local GPU embedding model gets at
+// least one GPU of the resource.count compute nodes.
+func (w *RAGEngine) validateShardGPUs() (errs *apis.FieldError) {
+	if w.Spec.Compute == nil || w.Spec.Embedding == nil || w.Spec.Embedding.Local == nil || w.EmbeddingOnCPU() {
+		return errs
+	}
+	gpuConfig, err := sku.GetGPUConfigBySKU(w.InstanceType())
+	if err != nil || gpuConfig == nil {
+		// Reported by the instance type validation.
+		return errs
+	}
+	nodeCount := 1
+	if w.Spec.Compute.Count != nil && *w.Spec.Compute.Count > 1 {
+		nodeCount = *w.Spec.Compute.Count
+	}
+	if gpus := nodeCount * int(gpuConfig.GPUCount); int(w.Spec.Sharding.Shards) > gpus {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("every shard runs the local embedding model on its own GPU, but %d %s node(s) of resource.count have %d GPU(s) for %d shards; lower shards or raise resource.count",
+			nodeCount, w.InstanceType(), gpus, w.Spec.Sharding.Shards), "shards"))
+	}
+	return errs
+}
+
+// validateIndexPolicies checks spec.indexPolicies.
+func (w *RAGEngine) validateIndexPolicies() (errs *apis.FieldError) {
+	if len(w.Spec.IndexPolicies) > 64 {
+		errs = errs.Also(apis.ErrGeneric("at most 64 index policies are supported", "indexPolicies"))
+	}
+	seen := map[string]bool{}
+	evicts := false
+	for i := range w.Spec.IndexPolicies {
+		policy := &w.Spec.IndexPolicies[i]
+		if seen[policy.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate index %q", policy.Name), "name").ViaFieldIndex("indexPolicies", i))
+		}
+		seen[policy.Name] = true
+		errs = errs.Also(policy.validate().ViaFieldIndex("indexPolicies", i))
+		evicts = evicts || policy.DocumentTTL != nil
+	}
+	if evicts && len(IndexEvictionCronJobName(w.Name)) > maxCronJobNameLength {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("document TTLs require a RAGEngine name of at most %d characters",
+			maxCronJobNameLength-len(IndexEvictionCronJobName(""))), "indexPolicies"))
+	}
+	return errs
+}
+
+func (p *IndexPolicySpec) validate() (errs *apis.FieldError) {
+	if p.Name == "" || len(p.Name) > 253 {
+		errs = errs.Also(apis.ErrInvalidValue("must be between 1 and 253 characters", "name"))
+	}
+	if p.DocumentTTL == nil && p.RefreshSchedule == "" {
+		errs = errs.Also(apis.ErrMissingOneOf("documentTTL", "refreshSchedule"))
+	}
+	if p.DocumentTTL != nil && p.DocumentTTL.Duration < minIndexDocumentTTL {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("documentTTL must be at least %s", minIndexDocumentTTL), "documentTTL"))
+	}
+	if p.RefreshSchedule != "" {
+		if _, err := cron.ParseStandard(p.RefreshSchedule); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid cron expression: %v", err), "refreshSchedule"))
+		}
+	}
+	return errs
+}
+
+func (r *RetrievalSpec) validate() (errs *apis.FieldError) {
+	if r == nil {
+		return nil
+	}
+	switch r.Mode {
+	case "", RetrievalModeHybrid:
+		if r.VectorWeightPercent != nil && (*r.VectorWeightPercent < 0 || *r.VectorWeightPercent > 100) {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*r.VectorWeightPercent, 0, 100, "vectorWeightPercent"))
+		}
+	case RetrievalModeVector, RetrievalModeKeyword:
+		if r.VectorWeightPercent != nil {
+			errs = errs.Also(apis.ErrGeneric("vectorWeightPercent is only supported in Hybrid mode", "vectorWeightPercent"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(r.Mode, "mode"))
+	}
+	return errs
+}
+
+// validate checks the response options. Metadata field names are passed to the RAG
+// service as a comma-separated list, so they must not contain commas.
+func (o *ResponseOptionsSpec) validate() (errs *apis.FieldError) {
+	if o == nil {
+		return nil
+	}
+	if o.MaxSourceChunks != nil && (*o.MaxSourceChunks < 1 || *o.MaxSourceChunks > 100) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*o.MaxSourceChunks, 1, 100, "maxSourceChunks"))
+	}
+	if len(o.MetadataFields) > 32 {
+		errs = errs.Also(apis.ErrGeneric("at most 32 metadata fields are supported", "metadataFields"))
+	}
+	seen := map[string]bool{}
+	for i, field := range o.MetadataFields {
+		switch {
+		case field == "" || len(field) > 128:
+			errs = errs.Also(apis.ErrInvalidValue("must be between 1 and 128 characters", apis.CurrentField).ViaFieldIndex("metadataFields", i))
+		case strings.ContainsAny(field, ",\n\r"):
+			errs = errs.Also(apis.ErrInvalidValue("must not contain commas or line breaks", apis.CurrentField).ViaFieldIndex("metadataFields", i))
+		case seen[field]:
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate metadata field %q", field), apis.CurrentField).ViaFieldIndex("metadataFields", i))
+		}
+		seen[field] = true
+	}
+	return errs
+}
+
+func (m *ConversationMemorySpec) validate() (errs *apis.FieldError) {
+	if m.TTL != nil && (m.TTL.Duration < minConversationMemoryTTL || m.TTL.Duration > maxConversationMemoryTTL) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("ttl must be between %s and %s", minConversationMemoryTTL, maxConversationMemoryTTL), "ttl"))
+	}
+	if m.MaxTurns != nil && (*m.MaxTurns < 1 || *m.MaxTurns > 100) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*m.MaxTurns, 1, 100, "maxTurns"))
+	}
+	switch m.Backend {
+	case "", ConversationMemoryBackendLocal:
+		if m.Redis != nil {
+			errs = errs.Also(apis.ErrGeneric("redis is only supported with the Redis backend", "redis"))
+		}
+	case ConversationMemoryBackendRedis:
+		if m.Redis != nil {
+			errs = errs.Also(m.Redis.validate().ViaField("redis"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(m.Backend, "backend"))
+	}
+	return errs
+}
+
+// validate checks the Redis server of the conversation memory. The password must come
+// from the Secret so it never shows up in the RAGEngine spec.
+func (r *RedisMemorySpec) validate() (errs *apis.FieldError) {
+	u, err := url.Parse(r.URL)
+	switch {
+	case err != nil:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid URL: %v", err), "url"))
+	case (u.Scheme != "redis" && u.Scheme != "rediss") || u.Hostname() == "":
+		errs = errs.Also(apis.ErrInvalidValue("url must be redis://<host>:<port>[/<db>] or rediss://<host>:<port>[/<db>]", "url"))
+	case u.User != nil || u.RawQuery != "" || u.Fragment != "":
+		errs = errs.Also(apis.ErrInvalidValue("url must not contain credentials, a query or a fragment; use accessSecret", "url"))
+	case strings.Trim(u.Path, "/") != "":
+		if db, err := strconv.Atoi(strings.Trim(u.Path, "/")); err != nil || db < 0 || db > 15 {
+			errs = errs.Also(apis.ErrInvalidValue("url database must be a number between 0 and 15", "url"))
+		}
+	}
+	if r.AccessSecret != "" {
+		if msgs := validation.IsDNS1123Subdomain(r.AccessSecret); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "accessSecret"))
+		}
+	}
+	return errs
+}
+
+func (r *ResourceSpec) validateRAGCreate(embeddingOnCPU bool) (errs *apis.FieldError) {
 	instanceType := string(r.InstanceType)
 
 	skuHandler, err := sku.GetSKUHandler()
@@ -183,12 +450,15 @@ func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
 		return errs
 	}
 
-	if skuConfig := skuHandler.GetGPUConfigBySKU(instanceType); skuConfig == nil {
-		provider := os.Getenv("CLOUD_PROVIDER")
-		// Check for other instance types pattern matches if cloud provider is Azure
-		if provider != consts.AzureCloudName || !sku.HasSKUNamePrefix(instanceType, N_SERIES_PREFIX, D_SERIES_PREFIX) {
-			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), "instanceType"))
-		}
+	switch {
+	case embeddingOnCPU:
+		errs = errs.Also(validateCPUInstanceType(skuHandler, instanceType).ViaField("instanceType"))
+	case instanceType == "":
+		missing := apis.ErrMissingField("instanceType")
+		missing.Details = "instanceType may only be omitted when the local embedding model runs on CPU"
+		errs = errs.Also(missing)
+	default:
+		errs = errs.Also(unsupportedInstanceTypeError(skuHandler, instanceType).ViaField("instanceType"))
 	}
 
 	// Validate labelSelector
@@ -196,9 +466,53 @@ func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
 	}
 
+	// provisioningPolicy, provisioningTimeout, fallbackInstanceTypes, zones, confidentialCompute, storage, compute and placement are only honored by Workspaces.
+	if r.ProvisioningPolicy != "" && r.ProvisioningPolicy != ProvisioningPolicyAuto {
+		errs = errs.Also(apis.ErrInvalidValue("provisioningPolicy is not supported for RAGEngine", "provisioningPolicy"))
+	}
+	if r.ProvisioningTimeout != nil || len(r.FallbackInstanceTypes) != 0 {
+		errs = errs.Also(apis.ErrGeneric("provisioningTimeout and fallbackInstanceTypes are not supported for RAGEngine", "provisioningTimeout", "fallbackInstanceTypes"))
+	}
+	if len(r.Zones) != 0 {
+		errs = errs.Also(apis.ErrGeneric("zones is not supported for RAGEngine", "zones"))
+	}
+	if r.ConfidentialCompute != nil {
+		errs = errs.Also(apis.ErrGeneric("confidentialCompute is not supported for RAGEngine", "confidentialCompute"))
+	}
+	if r.Storage != nil {
+		errs = errs.Also(apis.ErrGeneric("storage is not supported for RAGEngine", "storage"))
+	}
+	if r.Compute != nil {
+		errs = errs.Also(apis.ErrGeneric("compute is not supported for RAGEngine", "compute"))
+	}
+	if r.Placement != nil {
+		errs = errs.Also(apis.ErrGeneric("placement is not supported for RAGEngine", "placement"))
+	}
+
 	return errs
 }
 
+// validateCPUInstanceType checks the instance type of a RAGEngine whose embedding model runs
+// on CPU. CPU instance types are not in the SKU catalog, so only GPU instance types, which
+// would sit idle, and names that cannot be a node label value are rejected.
+func validateCPUInstanceType(skuHandler sku.CloudSKUHandler, instanceType string) *apis.FieldError {
+	if instanceType == "" {
+		if sku.DefaultCPUInstanceType() == "" {
+			return apis.ErrGeneric(fmt.Sprintf("instanceType must be specified, cloud provider %s has no default CPU instance type",
+				os.Getenv("CLOUD_PROVIDER")), apis.CurrentField)
+		}
+		return nil
+	}
+	if skuHandler.GetGPUConfigBySKU(instanceType) != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s is a GPU instance type, but the embedding model runs on CPU; set embedding.local.accelerator to Auto or GPU to use the GPU",
+			instanceType), apis.CurrentField)
+	}
+	if msgs := validation.IsValidLabelValue(instanceType); len(msgs) > 0 {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s is not a valid instance type: %s", instanceType, strings.Join(msgs, "; ")), apis.CurrentField)
+	}
+	return nil
+}
+
 func (e *LocalEmbeddingSpec) validateCreate() (errs *apis.FieldError) {
 	if e.Image == "" && e.ModelID == "" {
 		errs = errs.Also(apis.ErrGeneric("Either image or modelID must be specified, not neither", ""))
@@ -221,10 +535,42 @@ func (e *LocalEmbeddingSpec) validateCreate() (errs *apis.FieldError) {
 	return errs
 }
 
+// maxRemoteEmbeddingTimeout bounds spec.embedding.remote.timeout.
+const maxRemoteEmbeddingTimeout = 10 * time.Minute
+
 func (e *RemoteEmbeddingSpec) validateCreate() (errs *apis.FieldError) {
-	_, err := url.ParseRequestURI(e.URL)
+	u, err := url.ParseRequestURI(e.URL)
 	if err != nil {
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("URL input error: %v", err), "remote url"))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("URL scheme %q is not supported, use http or https", u.Scheme), "remote url"))
+	}
+
+	switch e.APIFormat {
+	case "", RemoteEmbeddingAPIFormatHuggingFace:
+	case RemoteEmbeddingAPIFormatOpenAI:
+		if e.Model == "" {
+			errs = errs.Also(apis.ErrMissingField("model").ViaField("remote"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(e.APIFormat, "apiFormat").ViaField("remote"))
+	}
+	if e.BatchSize != nil && (*e.BatchSize < 1 || *e.BatchSize > 2048) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*e.BatchSize, 1, 2048, "batchSize").ViaField("remote"))
+	}
+	if e.MaxRetries != nil && (*e.MaxRetries < 0 || *e.MaxRetries > 10) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*e.MaxRetries, 0, 10, "maxRetries").ViaField("remote"))
+	}
+	if e.Timeout != nil && (e.Timeout.Duration <= 0 || e.Timeout.Duration > maxRemoteEmbeddingTimeout) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("timeout must be greater than 0 and at most %s", maxRemoteEmbeddingTimeout), "timeout").ViaField("remote"))
+	}
+	for field, name := range map[string]string{"accessSecret": e.AccessSecret, "headersSecret": e.HeadersSecret} {
+		if name == "" {
+			continue
+		}
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s: %s", name, strings.Join(msgs, ", ")), field).ViaField("remote"))
+		}
 	}
 	return errs
 }
@@ -244,3 +590,77 @@ func (e *InferenceServiceSpec) validateCreate() (errs *apis.FieldError) {
 
 	return errs
 }
+
+// validate checks the index authorization policy. A nil spec is valid.
+func (a *IndexAuthorizationSpec) validate() (errs *apis.FieldError) {
+	if a == nil {
+		return nil
+	}
+	if len(a.Indexes) == 0 {
+		return apis.ErrMissingField("indexes")
+	}
+	for index, access := range a.Indexes {
+		if index == "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(index, "indexes", "index name must not be empty"))
+			continue
+		}
+		errs = errs.Also(access.validate().ViaKey(index).ViaField("indexes"))
+	}
+	if a.Admins != nil {
+		errs = errs.Also(a.Admins.validate().ViaField("admins"))
+	}
+	return errs
+}
+
+func (a *IndexAccessSpec) validate() (errs *apis.FieldError) {
+	if len(a.ServiceAccounts) == 0 && len(a.APIKeys) == 0 {
+		errs = errs.Also(apis.ErrGeneric("at least one of serviceAccounts or apiKeys is required", apis.CurrentField))
+	}
+	for i, sa := range a.ServiceAccounts {
+		namespace, name, ok := strings.Cut(sa, "/")
+		if !ok || len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1123Subdomain(name)) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q must be <namespace>/<name>", sa), apis.CurrentField).
+				ViaFieldIndex("serviceAccounts", i))
+		}
+	}
+	for i, key := range a.APIKeys {
+		if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), apis.CurrentField).ViaFieldIndex("apiKeys", i))
+		} else if key == IndexAuthPolicyFileName || key == IndexAuthMaintenanceKeyName {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q is reserved", key), apis.CurrentField).ViaFieldIndex("apiKeys", i))
+		}
+	}
+	return errs
+}
+
+func (b *RAGBackupSpec) validate() (errs *apis.FieldError) {
+	if _, err := cron.ParseStandard(b.Schedule); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid cron expression: %v", err), "schedule"))
+	}
+	return errs.Also(b.Destination.validate().ViaField("destination"))
+}
+
+func (r *RAGRestoreSpec) validate() (errs *apis.FieldError) {
+	if msgs := validation.IsDNS1123Subdomain(r.BackupName); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "backupName"))
+	}
+	return errs.Also(r.Source.validate().ViaField("source"))
+}
+
+// validate checks that l points to a blob container. Credentials must come from the
+// Secret so they never show up in the RAGEngine spec.
+func (l *BackupLocation) validate() (errs *apis.FieldError) {
+	u, err := url.Parse(l.URL)
+	switch {
+	case err != nil:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid URL: %v", err), "url"))
+	case u.Scheme != "https" || u.Host == "" || strings.Trim(u.Path, "/") == "":
+		errs = errs.Also(apis.ErrInvalidValue("url must be https://<host>/<container>[/<prefix>]", "url"))
+	case u.RawQuery != "" || u.Fragment != "" || u.User != nil:
+		errs = errs.Also(apis.ErrInvalidValue("url must not contain credentials, a query or a fragment; use credentialsSecret", "url"))
+	}
+	if msgs := validation.IsDNS1123Subdomain(l.CredentialsSecret); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "credentialsSecret"))
+	}
+	return errs
+}
@@ -17,10 +17,12 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kaito-project/kaito/pkg/k8sclient"
@@ -144,6 +146,87 @@ func TestRAGEngineValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Small local embedding model without instance type",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{},
+					Embedding: &EmbeddingSpec{
+						Local: &LocalEmbeddingSpec{
+							ModelID: "BAAI/bge-small-en-v1.5",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Local embedding on a CPU instance type outside the SKU catalog",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_F8s_v2",
+					},
+					Embedding: &EmbeddingSpec{
+						Local: &LocalEmbeddingSpec{
+							ModelID: "BAAI/bge-small-en-v1.5",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Large local embedding model without instance type",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{},
+					Embedding: &EmbeddingSpec{
+						Local: &LocalEmbeddingSpec{
+							ModelID: "example/large-embedding-model",
+						},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "instanceType may only be omitted when the local embedding model runs on CPU",
+		},
+		{
+			name: "CPU accelerator on a GPU instance type",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NV36ads_A10_v5",
+					},
+					Embedding: &EmbeddingSpec{
+						Local: &LocalEmbeddingSpec{
+							ModelID:     "BAAI/bge-small-en-v1.5",
+							Accelerator: EmbeddingAcceleratorCPU,
+						},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "Standard_NV36ads_A10_v5 is a GPU instance type, but the embedding model runs on CPU",
+		},
+		{
+			name: "CPU accelerator with an invalid instance type",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard D8s v5",
+					},
+					Embedding: &EmbeddingSpec{
+						Local: &LocalEmbeddingSpec{
+							ModelID:     "BAAI/bge-small-en-v1.5",
+							Accelerator: EmbeddingAcceleratorCPU,
+						},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "is not a valid instance type",
+		},
 		{
 			name: "Only Remote Embedding with invalid context window size",
 			ragEngine: &RAGEngine{
@@ -262,6 +345,49 @@ func TestRemoteEmbeddingValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:            "Unsupported URL scheme",
+			remoteEmbedding: &RemoteEmbeddingSpec{URL: "file:///etc/passwd"},
+			wantErr:         true,
+			errField:        "use http or https",
+		},
+		{
+			name: "Valid OpenAI endpoint",
+			remoteEmbedding: &RemoteEmbeddingSpec{
+				URL:           "https://api.openai.com/v1/embeddings",
+				APIFormat:     RemoteEmbeddingAPIFormatOpenAI,
+				Model:         "text-embedding-3-small",
+				BatchSize:     ptr.To[int32](64),
+				Timeout:       &metav1.Duration{Duration: 30 * time.Second},
+				MaxRetries:    ptr.To[int32](5),
+				AccessSecret:  "openai-key",
+				HeadersSecret: "openai-headers",
+			},
+		},
+		{
+			name:            "OpenAI format requires a model",
+			remoteEmbedding: &RemoteEmbeddingSpec{URL: "https://api.openai.com/v1/embeddings", APIFormat: RemoteEmbeddingAPIFormatOpenAI},
+			wantErr:         true,
+			errField:        "remote.model",
+		},
+		{
+			name:            "Batch size out of range",
+			remoteEmbedding: &RemoteEmbeddingSpec{URL: "http://example.com", BatchSize: ptr.To[int32](0)},
+			wantErr:         true,
+			errField:        "remote.batchSize",
+		},
+		{
+			name:            "Timeout out of range",
+			remoteEmbedding: &RemoteEmbeddingSpec{URL: "http://example.com", Timeout: &metav1.Duration{Duration: time.Hour}},
+			wantErr:         true,
+			errField:        "remote.timeout",
+		},
+		{
+			name:            "Invalid headers secret name",
+			remoteEmbedding: &RemoteEmbeddingSpec{URL: "http://example.com", HeadersSecret: "Bad_Name"},
+			wantErr:         true,
+			errField:        "remote.headersSecret",
+		},
 	}
 
 	for _, tt := range tests {
@@ -431,3 +557,574 @@ func TestInferenceServiceValidateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexAuthorizationValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     *IndexAuthorizationSpec
+		errField string
+	}{
+		{name: "nil spec"},
+		{
+			name: "valid policy",
+			auth: &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{
+				"team-a": {APIKeys: []string{"team-a-reader"}},
+				"team-b": {ServiceAccounts: []string{"team-b/indexer"}, APIKeys: []string{"team-b.writer"}},
+			}},
+		},
+		{name: "no indexes", auth: &IndexAuthorizationSpec{}, errField: "missing field(s): indexes"},
+		{
+			name:     "index without clients",
+			auth:     &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{"team-a": {}}},
+			errField: "at least one of serviceAccounts or apiKeys is required",
+		},
+		{
+			name:     "service account without namespace",
+			auth:     &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{"team-a": {ServiceAccounts: []string{"indexer"}}}},
+			errField: "must be <namespace>/<name>",
+		},
+		{
+			name:     "invalid api key name",
+			auth:     &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{"team-a": {APIKeys: []string{"team a"}}}},
+			errField: "indexes[team-a].apiKeys[0]",
+		},
+		{
+			name:     "reserved api key name",
+			auth:     &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{"team-a": {APIKeys: []string{IndexAuthPolicyFileName}}}},
+			errField: "is reserved",
+		},
+		{
+			name: "admins",
+			auth: &IndexAuthorizationSpec{
+				Indexes: map[string]IndexAccessSpec{"team-a": {APIKeys: []string{"team-a"}}},
+				Admins:  &IndexAccessSpec{APIKeys: []string{"ops"}, ServiceAccounts: []string{"ops/backup"}},
+			},
+		},
+		{
+			name: "empty admins",
+			auth: &IndexAuthorizationSpec{
+				Indexes: map[string]IndexAccessSpec{"team-a": {APIKeys: []string{"team-a"}}},
+				Admins:  &IndexAccessSpec{},
+			},
+			errField: "admins",
+		},
+		{
+			name: "reserved admin api key name",
+			auth: &IndexAuthorizationSpec{
+				Indexes: map[string]IndexAccessSpec{"team-a": {APIKeys: []string{"team-a"}}},
+				Admins:  &IndexAccessSpec{APIKeys: []string{IndexAuthMaintenanceKeyName}},
+			},
+			errField: "admins.apiKeys[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.validate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
+
+func TestRAGEngineValidateBackupRestore(t *testing.T) {
+	location := BackupLocation{URL: "https://acct.blob.core.windows.net/backups/team-a", CredentialsSecret: "backup-sas"}
+	newRAGEngine := func(name string, backup *RAGBackupSpec, restore *RAGRestoreSpec) *RAGEngine {
+		return &RAGEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: &RAGEngineSpec{
+				Embedding: &EmbeddingSpec{Remote: &RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+				Backup:    backup,
+				Restore:   restore,
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		rag      *RAGEngine
+		errField string
+	}{
+		{name: "no backup", rag: newRAGEngine("rag", nil, nil)},
+		{
+			name: "valid backup and restore",
+			rag: newRAGEngine("rag", &RAGBackupSpec{Schedule: "0 */6 * * *", Destination: location},
+				&RAGRestoreSpec{Source: location, BackupName: "old-rag-backup-29348160"}),
+		},
+		{
+			name:     "invalid schedule",
+			rag:      newRAGEngine("rag", &RAGBackupSpec{Schedule: "every hour", Destination: location}, nil),
+			errField: "invalid cron expression",
+		},
+		{
+			name: "http destination",
+			rag: newRAGEngine("rag", &RAGBackupSpec{Schedule: "@daily", Destination: BackupLocation{
+				URL: "http://acct.blob.core.windows.net/backups", CredentialsSecret: "backup-sas"}}, nil),
+			errField: "backup.destination.url",
+		},
+		{
+			name: "destination without container",
+			rag: newRAGEngine("rag", &RAGBackupSpec{Schedule: "@daily", Destination: BackupLocation{
+				URL: "https://acct.blob.core.windows.net/", CredentialsSecret: "backup-sas"}}, nil),
+			errField: "backup.destination.url",
+		},
+		{
+			name: "SAS token in url",
+			rag: newRAGEngine("rag", &RAGBackupSpec{Schedule: "@daily", Destination: BackupLocation{
+				URL: "https://acct.blob.core.windows.net/backups?sig=secret", CredentialsSecret: "backup-sas"}}, nil),
+			errField: "must not contain credentials",
+		},
+		{
+			name:     "missing credentials secret",
+			rag:      newRAGEngine("rag", &RAGBackupSpec{Schedule: "@daily", Destination: BackupLocation{URL: location.URL}}, nil),
+			errField: "backup.destination.credentialsSecret",
+		},
+		{
+			name:     "name too long for backups",
+			rag:      newRAGEngine(strings.Repeat("r", 46), &RAGBackupSpec{Schedule: "@daily", Destination: location}, nil),
+			errField: "at most 45 characters",
+		},
+		{
+			name:     "invalid backup name",
+			rag:      newRAGEngine("rag", nil, &RAGRestoreSpec{Source: location, BackupName: "../other"}),
+			errField: "restore.backupName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rag.validateCreate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validateCreate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validateCreate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
+
+func TestRAGEngineValidateUpdateRestore(t *testing.T) {
+	restore := &RAGRestoreSpec{
+		Source:     BackupLocation{URL: "https://acct.blob.core.windows.net/backups", CredentialsSecret: "backup-sas"},
+		BackupName: "old-rag-backup-29348160",
+	}
+	old := &RAGEngine{Spec: &RAGEngineSpec{Restore: restore}}
+
+	if err := (&RAGEngine{Spec: &RAGEngineSpec{Restore: restore.DeepCopy()}}).validateUpdate(old); err != nil {
+		t.Errorf("validateUpdate() unexpected error = %v", err)
+	}
+	changed := restore.DeepCopy()
+	changed.BackupName = "old-rag-backup-29348220"
+	if err := (&RAGEngine{Spec: &RAGEngineSpec{Restore: changed}}).validateUpdate(old); err == nil ||
+		!strings.Contains(err.Error(), "restore cannot be changed after creation") {
+		t.Errorf("validateUpdate() expected restore change to be rejected, but got %v", err)
+	}
+	if err := (&RAGEngine{Spec: &RAGEngineSpec{}}).validateUpdate(old); err == nil {
+		t.Errorf("validateUpdate() expected restore removal to be rejected")
+	}
+}
+
+func TestRAGEngineValidateUpdateEmbeddingAccelerator(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	newRAGEngine := func(accelerator EmbeddingAccelerator) *RAGEngine {
+		return &RAGEngine{Spec: &RAGEngineSpec{
+			Compute:   &ResourceSpec{},
+			Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5", Accelerator: accelerator}},
+		}}
+	}
+	old := newRAGEngine("")
+
+	if err := newRAGEngine(EmbeddingAcceleratorCPU).validateUpdate(old); err != nil {
+		t.Errorf("validateUpdate() unexpected error = %v", err)
+	}
+	if err := newRAGEngine(EmbeddingAcceleratorGPU).validateUpdate(old); err == nil ||
+		!strings.Contains(err.Error(), "the embedding model cannot be moved between CPU and GPU after creation") {
+		t.Errorf("validateUpdate() expected the move to GPU to be rejected, but got %v", err)
+	}
+}
+
+func TestRAGEngineValidateSharding(t *testing.T) {
+	newRAGEngine := func(name string, sharding *ShardingSpec, mutate func(*RAGEngineSpec)) *RAGEngine {
+		rag := &RAGEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: &RAGEngineSpec{
+				Embedding: &EmbeddingSpec{Remote: &RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+				Sharding:  sharding,
+			},
+		}
+		if mutate != nil {
+			mutate(rag.Spec)
+		}
+		return rag
+	}
+	tests := []struct {
+		name     string
+		rag      *RAGEngine
+		errField string
+	}{
+		{name: "hash", rag: newRAGEngine("rag", &ShardingSpec{Shards: 4, Strategy: ShardingStrategyHash}, nil)},
+		{name: "default strategy", rag: newRAGEngine("rag", &ShardingSpec{Shards: 2}, nil)},
+		{
+			name: "metadata field",
+			rag:  newRAGEngine("rag", &ShardingSpec{Shards: 4, Strategy: ShardingStrategyMetadataField, MetadataField: "tenant"}, nil),
+		},
+		{
+			name:     "missing metadata field",
+			rag:      newRAGEngine("rag", &ShardingSpec{Shards: 4, Strategy: ShardingStrategyMetadataField}, nil),
+			errField: "sharding.metadataField",
+		},
+		{
+			name:     "invalid strategy",
+			rag:      newRAGEngine("rag", &ShardingSpec{Shards: 4, Strategy: "Random"}, nil),
+			errField: "sharding.strategy",
+		},
+		{
+			name:     "single shard",
+			rag:      newRAGEngine("rag", &ShardingSpec{Shards: 1}, nil),
+			errField: "sharding.shards",
+		},
+		{
+			name:     "too many shards",
+			rag:      newRAGEngine("rag", &ShardingSpec{Shards: 33}, nil),
+			errField: "sharding.shards",
+		},
+		{
+			name:     "name too long for shard services",
+			rag:      newRAGEngine(strings.Repeat("r", 56), &ShardingSpec{Shards: 10}, nil),
+			errField: "use a shorter RAGEngine name",
+		},
+		{
+			name: "external vector database",
+			rag: newRAGEngine("rag", &ShardingSpec{Shards: 2}, func(spec *RAGEngineSpec) {
+				spec.Storage = &StorageSpec{VectorDB: &VectorDBConfig{Engine: "qdrant", URL: "http://qdrant:6333"}}
+			}),
+			errField: "an external vector database",
+		},
+		{
+			name: "persistent volume claim",
+			rag: newRAGEngine("rag", &ShardingSpec{Shards: 2}, func(spec *RAGEngineSpec) {
+				spec.Storage = &StorageSpec{PersistentVolume: &PersistentVolumeConfig{PersistentVolumeClaim: "data"}}
+			}),
+			errField: "a persistent volume claim",
+		},
+		{
+			name: "authorization",
+			rag: newRAGEngine("rag", &ShardingSpec{Shards: 2}, func(spec *RAGEngineSpec) {
+				spec.Authorization = &IndexAuthorizationSpec{Indexes: map[string]IndexAccessSpec{"docs": {APIKeys: []string{"team-a"}}}}
+			}),
+			errField: "authorization",
+		},
+		{
+			name: "conversation memory",
+			rag: newRAGEngine("rag", &ShardingSpec{Shards: 2}, func(spec *RAGEngineSpec) {
+				spec.ConversationMemory = &ConversationMemorySpec{}
+			}),
+			errField: "conversation memory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rag.validateCreate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validateCreate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validateCreate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+
+	old := &RAGEngine{Spec: &RAGEngineSpec{Sharding: &ShardingSpec{Shards: 4, Strategy: ShardingStrategyHash}}}
+	if err := (&RAGEngine{Spec: &RAGEngineSpec{Sharding: &ShardingSpec{Shards: 8, Strategy: ShardingStrategyHash}}}).validateUpdate(old); err == nil ||
+		!strings.Contains(err.Error(), "sharding cannot be changed after creation") {
+		t.Errorf("validateUpdate() expected shard count change to be rejected, but got %v", err)
+	}
+}
+
+func TestRAGEngineValidateShardGPUs(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	newRAGEngine := func(instanceType string, count, shards int) *RAGEngine {
+		return &RAGEngine{Spec: &RAGEngineSpec{
+			Compute:   &ResourceSpec{InstanceType: instanceType, Count: &count},
+			Embedding: &EmbeddingSpec{Local: &LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}},
+			Sharding:  &ShardingSpec{Shards: int32(shards)},
+		}}
+	}
+	tests := []struct {
+		name      string
+		rag       *RAGEngine
+		expectErr bool
+	}{
+		{name: "one GPU per shard", rag: newRAGEngine("Standard_NC48ads_A100_v4", 1, 2)},
+		{name: "GPUs of several nodes", rag: newRAGEngine("Standard_NC24ads_A100_v4", 2, 2)},
+		{name: "more shards than GPUs", rag: newRAGEngine("Standard_NC24ads_A100_v4", 1, 2), expectErr: true},
+		{
+			name: "remote embedding",
+			rag: func() *RAGEngine {
+				rag := newRAGEngine("Standard_NC24ads_A100_v4", 1, 4)
+				rag.Spec.Embedding = &EmbeddingSpec{Remote: &RemoteEmbeddingSpec{URL: "https://embedding.example.com"}}
+				return rag
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rag.validateShardGPUs()
+			if tt.expectErr != (err != nil) {
+				t.Errorf("validateShardGPUs() expectErr=%v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestRetrievalSpecValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		retrieval *RetrievalSpec
+		errField  string
+	}{
+		{name: "omitted"},
+		{name: "default mode", retrieval: &RetrievalSpec{}},
+		{name: "keyword", retrieval: &RetrievalSpec{Mode: RetrievalModeKeyword}},
+		{name: "hybrid with weight", retrieval: &RetrievalSpec{Mode: RetrievalModeHybrid, VectorWeightPercent: ptr.To[int32](50)}},
+		{
+			name:      "weight out of range",
+			retrieval: &RetrievalSpec{Mode: RetrievalModeHybrid, VectorWeightPercent: ptr.To[int32](101)},
+			errField:  "vectorWeightPercent",
+		},
+		{
+			name:      "weight outside hybrid mode",
+			retrieval: &RetrievalSpec{Mode: RetrievalModeVector, VectorWeightPercent: ptr.To[int32](50)},
+			errField:  "only supported in Hybrid mode",
+		},
+		{name: "invalid mode", retrieval: &RetrievalSpec{Mode: "Semantic"}, errField: "mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.retrieval.validate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
+
+func TestRAGEngineValidateConversationMemory(t *testing.T) {
+	newRAGEngine := func(name string, memory *ConversationMemorySpec, mutate func(*RAGEngineSpec)) *RAGEngine {
+		rag := &RAGEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: &RAGEngineSpec{
+				Embedding:          &EmbeddingSpec{Remote: &RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+				InferenceService:   &InferenceServiceSpec{URL: "http://inference.example.com/v1/chat/completions", ContextWindowSize: 4096},
+				ConversationMemory: memory,
+			},
+		}
+		if mutate != nil {
+			mutate(rag.Spec)
+		}
+		return rag
+	}
+	tests := []struct {
+		name     string
+		rag      *RAGEngine
+		errField string
+	}{
+		{name: "local", rag: newRAGEngine("rag", &ConversationMemorySpec{}, nil)},
+		{
+			name: "local with limits",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend:  ConversationMemoryBackendLocal,
+				MaxTurns: ptr.To[int32](20),
+				TTL:      &metav1.Duration{Duration: time.Hour},
+			}, nil),
+		},
+		{name: "managed redis", rag: newRAGEngine("rag", &ConversationMemorySpec{Backend: ConversationMemoryBackendRedis}, nil)},
+		{
+			name: "external redis",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend: ConversationMemoryBackendRedis,
+				Redis:   &RedisMemorySpec{URL: "rediss://redis.example.com:6380/2", AccessSecret: "redis-password"},
+			}, nil),
+		},
+		{
+			name:     "without inference service",
+			rag:      newRAGEngine("rag", &ConversationMemorySpec{}, func(spec *RAGEngineSpec) { spec.InferenceService = nil }),
+			errField: "requires an inference service",
+		},
+		{
+			name:     "redis with local backend",
+			rag:      newRAGEngine("rag", &ConversationMemorySpec{Redis: &RedisMemorySpec{URL: "redis://redis:6379"}}, nil),
+			errField: "conversationMemory.redis",
+		},
+		{
+			name:     "invalid backend",
+			rag:      newRAGEngine("rag", &ConversationMemorySpec{Backend: "Postgres"}, nil),
+			errField: "conversationMemory.backend",
+		},
+		{
+			name:     "max turns out of range",
+			rag:      newRAGEngine("rag", &ConversationMemorySpec{MaxTurns: ptr.To[int32](0)}, nil),
+			errField: "conversationMemory.maxTurns",
+		},
+		{
+			name:     "ttl too short",
+			rag:      newRAGEngine("rag", &ConversationMemorySpec{TTL: &metav1.Duration{Duration: time.Second}}, nil),
+			errField: "conversationMemory.ttl",
+		},
+		{
+			name: "http url",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend: ConversationMemoryBackendRedis,
+				Redis:   &RedisMemorySpec{URL: "http://redis:6379"},
+			}, nil),
+			errField: "conversationMemory.redis.url",
+		},
+		{
+			name: "password in url",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend: ConversationMemoryBackendRedis,
+				Redis:   &RedisMemorySpec{URL: "redis://:secret@redis:6379"},
+			}, nil),
+			errField: "use accessSecret",
+		},
+		{
+			name: "invalid database",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend: ConversationMemoryBackendRedis,
+				Redis:   &RedisMemorySpec{URL: "redis://redis:6379/cache"},
+			}, nil),
+			errField: "url database",
+		},
+		{
+			name: "invalid access secret",
+			rag: newRAGEngine("rag", &ConversationMemorySpec{
+				Backend: ConversationMemoryBackendRedis,
+				Redis:   &RedisMemorySpec{URL: "redis://redis:6379", AccessSecret: "Redis_Password"},
+			}, nil),
+			errField: "conversationMemory.redis.accessSecret",
+		},
+		{
+			name:     "name too long for managed redis",
+			rag:      newRAGEngine(strings.Repeat("r", 60), &ConversationMemorySpec{Backend: ConversationMemoryBackendRedis}, nil),
+			errField: "use a shorter RAGEngine name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rag.validateCreate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validateCreate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validateCreate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
+
+func TestResponseOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *ResponseOptionsSpec
+		errField string
+	}{
+		{name: "omitted"},
+		{
+			name:    "all options",
+			options: &ResponseOptionsSpec{IncludeCitations: true, MaxSourceChunks: ptr.To[int32](5), MetadataFields: []string{"title", "url"}},
+		},
+		{name: "max source chunks out of range", options: &ResponseOptionsSpec{MaxSourceChunks: ptr.To[int32](0)}, errField: "maxSourceChunks"},
+		{name: "empty metadata field", options: &ResponseOptionsSpec{MetadataFields: []string{""}}, errField: "metadataFields[0]"},
+		{name: "metadata field with comma", options: &ResponseOptionsSpec{MetadataFields: []string{"title", "a,b"}}, errField: "metadataFields[1]"},
+		{name: "duplicate metadata field", options: &ResponseOptionsSpec{MetadataFields: []string{"url", "url"}}, errField: "duplicate metadata field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.validate()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validate() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
+
+func TestRAGEngineValidateIndexPolicies(t *testing.T) {
+	day := &metav1.Duration{Duration: 24 * time.Hour}
+	tests := []struct {
+		name     string
+		ragName  string
+		policies []IndexPolicySpec
+		errField string
+	}{
+		{name: "omitted"},
+		{
+			name:     "ttl and refresh",
+			policies: []IndexPolicySpec{{Name: "docs", DocumentTTL: day, RefreshSchedule: "0 2 * * *"}, {Name: "wiki", RefreshSchedule: "@daily"}},
+		},
+		{name: "empty policy", policies: []IndexPolicySpec{{Name: "docs"}}, errField: "indexPolicies[0]"},
+		{name: "ttl too short", policies: []IndexPolicySpec{{Name: "docs", DocumentTTL: &metav1.Duration{Duration: time.Minute}}}, errField: "indexPolicies[0].documentTTL"},
+		{name: "invalid schedule", policies: []IndexPolicySpec{{Name: "docs", RefreshSchedule: "every day"}}, errField: "indexPolicies[0].refreshSchedule"},
+		{name: "duplicate index", policies: []IndexPolicySpec{{Name: "docs", DocumentTTL: day}, {Name: "docs", DocumentTTL: day}}, errField: "duplicate index"},
+		{
+			name:     "name too long for eviction cronjob",
+			ragName:  strings.Repeat("a", 40),
+			policies: []IndexPolicySpec{{Name: "docs", DocumentTTL: day}},
+			errField: "indexPolicies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.ragName
+			if name == "" {
+				name = "rag"
+			}
+			rag := &RAGEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       &RAGEngineSpec{IndexPolicies: tt.policies},
+			}
+			err := rag.validateIndexPolicies()
+			if tt.errField == "" {
+				if err != nil {
+					t.Errorf("validateIndexPolicies() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errField) {
+				t.Errorf("validateIndexPolicies() expected error to contain %s, but got %v", tt.errField, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReconcilePaused reports whether the reconciliation of obj is paused by AnnotationReconcile.
+func ReconcilePaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AnnotationReconcile] == ReconcileDisabled
+}
+
+// ApplyPausedCondition sets the Paused condition in conditions while the reconciliation of
+// obj is paused by the kaito.sh/reconcile annotation, and removes it otherwise. The observed
+// generation of the condition stays at the generation that was current when the pause
+// started, so the message can tell that newer generations wait for the resume.
+func ApplyPausedCondition(conditions *[]metav1.Condition, obj metav1.Object) {
+	if !ReconcilePaused(obj) {
+		meta.RemoveStatusCondition(conditions, string(ConditionTypePaused))
+		return
+	}
+	pausedAt := obj.GetGeneration()
+	if cond := meta.FindStatusCondition(*conditions, string(ConditionTypePaused)); cond != nil && cond.Status == metav1.ConditionTrue {
+		pausedAt = cond.ObservedGeneration
+	}
+	message := fmt.Sprintf("Reconciliation is paused at generation %d by the %s=%s annotation",
+		pausedAt, AnnotationReconcile, ReconcileDisabled)
+	if obj.GetGeneration() > pausedAt {
+		message += fmt.Sprintf(", generation %d is applied once it is removed", obj.GetGeneration())
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               string(ConditionTypePaused),
+		Status:             metav1.ConditionTrue,
+		Reason:             "ReconcileDisabled",
+		Message:            message,
+		ObservedGeneration: pausedAt,
+	})
+}
+
+// PauseTransition returns the reason and message of the event to record when the
+// reconciliation of obj, whose status has conditions, is paused or resumed. It returns an
+// empty reason when the pause did not change.
+func PauseTransition(conditions []metav1.Condition, obj metav1.Object) (reason, message string) {
+	paused := ReconcilePaused(obj)
+	wasPaused := meta.IsStatusConditionTrue(conditions, string(ConditionTypePaused))
+	switch {
+	case paused && !wasPaused:
+		return "ReconcilePaused", fmt.Sprintf("Reconciliation paused by the %s annotation", AnnotationReconcile)
+	case !paused && wasPaused:
+		return "ReconcileResumed", fmt.Sprintf("Reconciliation resumed at generation %d", obj.GetGeneration())
+	}
+	return "", ""
+}
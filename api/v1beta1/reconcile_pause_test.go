@@ -0,0 +1,77 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyPausedCondition(t *testing.T) {
+	ws := &Workspace{ObjectMeta: metav1.ObjectMeta{
+		Generation:  2,
+		Annotations: map[string]string{AnnotationReconcile: ReconcileDisabled},
+	}}
+	var conditions []metav1.Condition
+
+	ApplyPausedCondition(&conditions, ws)
+	cond := meta.FindStatusCondition(conditions, string(ConditionTypePaused))
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != 2 {
+		t.Fatalf("expected Paused at generation 2, got %+v", cond)
+	}
+
+	// Spec changes made during the pause are reported as pending.
+	ws.Generation = 4
+	ApplyPausedCondition(&conditions, ws)
+	cond = meta.FindStatusCondition(conditions, string(ConditionTypePaused))
+	if cond.ObservedGeneration != 2 || !strings.Contains(cond.Message, "generation 4 is applied once it is removed") {
+		t.Errorf("expected the pause to stay at generation 2 with generation 4 pending, got %+v", cond)
+	}
+
+	ws.Annotations = nil
+	ApplyPausedCondition(&conditions, ws)
+	if meta.FindStatusCondition(conditions, string(ConditionTypePaused)) != nil {
+		t.Errorf("expected the Paused condition to be removed on resume")
+	}
+}
+
+func TestPauseTransition(t *testing.T) {
+	paused := []metav1.Condition{{Type: string(ConditionTypePaused), Status: metav1.ConditionTrue}}
+	tests := []struct {
+		name       string
+		annotation string
+		conditions []metav1.Condition
+		wantReason string
+	}{
+		{name: "not paused"},
+		{name: "pause starts", annotation: ReconcileDisabled, wantReason: "ReconcilePaused"},
+		{name: "stays paused", annotation: ReconcileDisabled, conditions: paused},
+		{name: "resumes", conditions: paused, wantReason: "ReconcileResumed"},
+		{name: "other values do not pause", annotation: "enabled", conditions: paused, wantReason: "ReconcileResumed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &Workspace{}
+			if tt.annotation != "" {
+				ws.Annotations = map[string]string{AnnotationReconcile: tt.annotation}
+			}
+			if reason, _ := PauseTransition(tt.conditions, ws); reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
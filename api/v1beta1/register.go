@@ -25,4 +25,12 @@ var (
 	AddToScheme = SchemeBuilder.AddToScheme
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+	// SchemeGroupVersion is the name the generated clientset in pkg/client uses for GroupVersion.
+	SchemeGroupVersion = GroupVersion
 )
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource.
+// The generated listers use it for their NotFound errors.
+func Resource(resource string) schema.GroupResource {
+	return GroupVersion.WithResource(resource).GroupResource()
+}
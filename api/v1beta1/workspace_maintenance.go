@@ -0,0 +1,100 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"slices"
+	"time"
+)
+
+// location returns the time zone of the window. Unknown time zones are rejected by the
+// webhook, so they fall back to UTC.
+func (w *WorkspaceMaintenanceWindow) location() *time.Location {
+	if w.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseClock returns the hours and minutes of an HH:MM time of day.
+func parseClock(clock string) (int, int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// opening returns when the window opens on the day of t, and whether it opens on that day.
+func (w *WorkspaceMaintenanceWindow) opening(t time.Time) (time.Time, bool) {
+	hour, minute, err := parseClock(w.StartTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	open := time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location())
+	return open, len(w.Days) == 0 || slices.Contains(w.Days, MaintenanceDay(open.Weekday().String()))
+}
+
+// length returns how long the window stays open. An end time that is not after the start
+// time closes the window on the next day.
+func (w *WorkspaceMaintenanceWindow) length() time.Duration {
+	startHour, startMinute, err := parseClock(w.StartTime)
+	if err != nil {
+		return 0
+	}
+	endHour, endMinute, err := parseClock(w.EndTime)
+	if err != nil {
+		return 0
+	}
+	length := time.Duration(endHour-startHour)*time.Hour + time.Duration(endMinute-startMinute)*time.Minute
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+	return length
+}
+
+// IsOpen reports whether disruptive operations may start at now. A nil window is always open.
+func (w *WorkspaceMaintenanceWindow) IsOpen(now time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t := now.In(w.location())
+	// A window that crosses midnight may have opened on the previous day.
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		open, ok := w.opening(day)
+		if ok && !t.Before(open) && t.Before(open.Add(w.length())) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns when the window opens next after now. A nil window returns now.
+func (w *WorkspaceMaintenanceWindow) NextOpen(now time.Time) time.Time {
+	if w == nil {
+		return now
+	}
+	t := now.In(w.location())
+	for d := 0; d <= 7; d++ {
+		open, ok := w.opening(t.AddDate(0, 0, d))
+		if ok && open.After(t) {
+			return open
+		}
+	}
+	return now
+}
@@ -0,0 +1,95 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"slices"
+	"strings"
+)
+
+// reservedPropagationKey reports whether a label or annotation key is never propagated:
+// keys of the kaito.sh domain steer KAITO itself, and kubectl annotations such as
+// last-applied-configuration describe the workspace only.
+func reservedPropagationKey(key string) bool {
+	domain, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return domain == "kaito.sh" || strings.HasSuffix(domain, ".kaito.sh") || domain == "kubectl.kubernetes.io"
+}
+
+// matchesPropagationKey reports whether key is selected by one of patterns.
+func matchesPropagationKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if pattern == key {
+			return true
+		}
+	}
+	return false
+}
+
+// propagates reports whether the spec copies metadata to target.
+func (p *MetadataPropagationSpec) propagates(target MetadataPropagationTarget) bool {
+	return p != nil && (len(p.Targets) == 0 || slices.Contains(p.Targets, target))
+}
+
+func propagated(patterns []string, from map[string]string) map[string]string {
+	var out map[string]string
+	for key, value := range from {
+		if reservedPropagationKey(key) || !matchesPropagationKey(patterns, key) {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// PropagatedLabels returns the labels of the workspace that are copied to target, or nil.
+func (w *Workspace) PropagatedLabels(target MetadataPropagationTarget) map[string]string {
+	if !w.MetadataPropagation.propagates(target) {
+		return nil
+	}
+	return propagated(w.MetadataPropagation.Labels, w.Labels)
+}
+
+// PropagatedAnnotations returns the annotations of the workspace that are copied to target,
+// or nil.
+func (w *Workspace) PropagatedAnnotations(target MetadataPropagationTarget) map[string]string {
+	if !w.MetadataPropagation.propagates(target) {
+		return nil
+	}
+	return propagated(w.MetadataPropagation.Annotations, w.Annotations)
+}
+
+// MergePropagated adds the propagated entries to dst and returns it. Entries already in
+// dst, set by KAITO or by another field of the workspace, win.
+func MergePropagated(dst, propagated map[string]string) map[string]string {
+	for key, value := range propagated {
+		if _, exists := dst[key]; exists {
+			continue
+		}
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		dst[key] = value
+	}
+	return dst
+}
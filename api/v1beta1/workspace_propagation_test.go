@@ -0,0 +1,58 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkspacePropagatedMetadata(t *testing.T) {
+	w := &Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"cost-center":           "42",
+				"cost.example.com/team": "search",
+				"app":                   "phi",
+				"kaito.sh/workspace":    "ws",
+				LabelInferenceTier:      "long",
+			},
+			Annotations: map[string]string{
+				"sidecar.istio.io/inject":                          "true",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+	}
+	assert.Nil(t, w.PropagatedLabels(MetadataPropagationTargetPod), "nothing is propagated without a spec")
+
+	w.MetadataPropagation = &MetadataPropagationSpec{
+		Labels:      []string{"cost-center", "cost.example.com/*", "kaito.sh/*"},
+		Annotations: []string{"*"},
+		Targets:     []MetadataPropagationTarget{MetadataPropagationTargetPod, MetadataPropagationTargetService},
+	}
+	assert.Equal(t, map[string]string{"cost-center": "42", "cost.example.com/team": "search"}, w.PropagatedLabels(MetadataPropagationTargetPod))
+	assert.Equal(t, map[string]string{"sidecar.istio.io/inject": "true"}, w.PropagatedAnnotations(MetadataPropagationTargetService))
+	assert.Nil(t, w.PropagatedLabels(MetadataPropagationTargetNodeClaim), "NodeClaims are not a target")
+
+	w.MetadataPropagation.Targets = nil
+	assert.Equal(t, map[string]string{"cost-center": "42", "cost.example.com/team": "search"}, w.PropagatedLabels(MetadataPropagationTargetNodeClaim))
+}
+
+func TestMergePropagated(t *testing.T) {
+	assert.Nil(t, MergePropagated(nil, nil))
+	assert.Equal(t, map[string]string{"a": "1"}, MergePropagated(nil, map[string]string{"a": "1"}))
+	assert.Equal(t, map[string]string{"a": "kaito", "b": "2"}, MergePropagated(map[string]string{"a": "kaito"}, map[string]string{"a": "1", "b": "2"}))
+}
@@ -14,7 +14,10 @@
 package v1beta1
 
 import (
+	"slices"
+
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -50,6 +53,220 @@ type ResourceSpec struct {
 	// Requires the enableMIG feature gate and BYO nodes.
 	// +optional
 	Partition *PartitionSpec `json:"partition,omitempty"`
+
+	// ProvisioningPolicy controls whether the controller may provision nodes for this workspace.
+	// Auto (default) follows the controller-wide node provisioner. PreferredOnly restricts the
+	// workspace to existing nodes matching LabelSelector and waits for them to become ready.
+	// Never behaves like PreferredOnly but states explicitly that nodes will never be
+	// provisioned: the NodeStatus condition reports NodeProvisioningDisabled until enough
+	// matching nodes are added.
+	// PreferredOnly and Never require InstanceType to be empty. This field is immutable.
+	// +kubebuilder:validation:Enum=Auto;PreferredOnly;Never
+	// +optional
+	ProvisioningPolicy ProvisioningPolicy `json:"provisioningPolicy,omitempty"`
+
+	// ProvisioningTimeout bounds how long NodeClaims may stay not ready. When it expires the
+	// NodeClaimProvisionTimeout condition is set with the provisioning failure reported by
+	// Karpenter, and a warning event is emitted. When unset, the controller waits indefinitely.
+	// +optional
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+
+	// FallbackInstanceTypes is an ordered list of GPU SKUs to try when ProvisioningTimeout
+	// expires. On each timeout the controller deletes the pending nodes, records the next
+	// entry in status.activeInstanceType and provisions again. InstanceType is left unchanged.
+	// Requires ProvisioningTimeout.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
+	// Zones restricts provisioned nodes to these availability zones, e.g. eastus-1 or
+	// us-west-2a. The provisioner picks a zone with capacity among them, and status.zones
+	// reports where the nodes landed. On AWS, the subnets of the EC2NodeClass must cover every
+	// zone listed. Zones must be in the region of the cluster. Zones do not apply to BYO nodes
+	// and only affect nodes provisioned after they change, except that Karpenter may replace
+	// the nodes of its NodePool that are outside the new zones.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=set
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// ConfidentialCompute runs the workload on confidential GPU VMs, e.g. the Azure NCC H100
+	// series, whose CPU and GPU memory is encrypted and isolated in a trusted execution
+	// environment. InstanceType and FallbackInstanceTypes must be confidential SKUs; BYO nodes
+	// must carry the kaito.sh/confidential-compute=true label. It is not supported with an
+	// inference template. This field is immutable.
+	// +optional
+	ConfidentialCompute *ConfidentialComputeSpec `json:"confidentialCompute,omitempty"`
+
+	// Storage sizes the disk space of the workload. When omitted, provisioned nodes get the
+	// OS disk size required by the preset model and pods request no ephemeral storage.
+	// +optional
+	Storage *WorkloadStorageSpec `json:"storage,omitempty"`
+
+	// Compute sets the CPU and memory requests of the inference container of a preset
+	// workspace, e.g. to give tokenization more CPU under load. When omitted, the container
+	// requests no CPU or memory.
+	// +optional
+	Compute *WorkloadComputeSpec `json:"compute,omitempty"`
+
+	// Placement controls which other pods may run on the nodes of the workload. When
+	// omitted, the policy is Shared: provisioned GPU nodes carry the sku=gpu:NoSchedule
+	// taint, so only pods that tolerate it, e.g. low-priority batch jobs, are co-located.
+	// +optional
+	Placement *PlacementSpec `json:"placement,omitempty"`
+}
+
+// PlacementSpec controls the co-location of a workload with other pods.
+type PlacementSpec struct {
+	// Policy is Shared (default) to let pods that tolerate the GPU node taint run next to
+	// the workload, or Dedicated to keep its nodes exclusive. Dedicated adds a required pod
+	// anti-affinity, so no pod of another workload is scheduled on the nodes, and the
+	// workload is not scheduled on nodes that already run one. DaemonSet pods are exempt.
+	// +kubebuilder:validation:Enum=Shared;Dedicated
+	// +kubebuilder:default:=Shared
+	// +optional
+	Policy PlacementPolicy `json:"policy,omitempty"`
+
+	// AllowColocationWith lists pod labels that are exempt from a Dedicated policy: pods
+	// carrying any of these key and value pairs, e.g. batch jobs of a trusted team, may
+	// still run on the nodes. Only valid with the Dedicated policy.
+	// +kubebuilder:validation:MaxProperties=16
+	// +optional
+	AllowColocationWith map[string]string `json:"allowColocationWith,omitempty"`
+
+	// SpreadAcrossHosts requires the pods of the workload, or of all replicas of its
+	// InferenceSet, to run on distinct nodes, so a single node failure takes down at most
+	// one of them.
+	// +optional
+	SpreadAcrossHosts bool `json:"spreadAcrossHosts,omitempty"`
+}
+
+// PlacementPolicy controls whether other workloads may run on the nodes of a workload.
+type PlacementPolicy string
+
+const (
+	// PlacementPolicyShared lets pods that tolerate the GPU node taint share the nodes.
+	PlacementPolicyShared PlacementPolicy = "Shared"
+	// PlacementPolicyDedicated keeps the nodes exclusive to the workload.
+	PlacementPolicyDedicated PlacementPolicy = "Dedicated"
+)
+
+// WorkloadComputeSpec sets the CPU and memory requests of a workload.
+type WorkloadComputeSpec struct {
+	// CPU is the CPU request of the inference container.
+	CPU resource.Quantity `json:"cpu"`
+
+	// Memory is the memory request of the inference container.
+	Memory resource.Quantity `json:"memory"`
+
+	// ResizePolicy controls how running pods pick up changed requests. InPlace (default)
+	// resizes the running pods without restarting them, so the model stays loaded, and
+	// falls back to Recreate when the cluster does not support in-place pod resize.
+	// Recreate rolls the pods out with the new requests.
+	// +kubebuilder:validation:Enum=InPlace;Recreate
+	// +kubebuilder:default:=InPlace
+	// +optional
+	ResizePolicy ResizePolicy `json:"resizePolicy,omitempty"`
+}
+
+// ResizePolicy controls how running pods pick up changed CPU and memory requests.
+type ResizePolicy string
+
+const (
+	// ResizePolicyInPlace resizes running pods through the pod resize subresource.
+	ResizePolicyInPlace ResizePolicy = "InPlace"
+	// ResizePolicyRecreate rolls the pods out with the new requests.
+	ResizePolicyRecreate ResizePolicy = "Recreate"
+)
+
+// WorkloadStorageSpec sizes the disk space of a workload. When the workload runs out of
+// disk, the DiskTooSmall condition reports the size to use instead.
+type WorkloadStorageSpec struct {
+	// NodeDiskSize is the OS disk size of the GPU nodes provisioned for the workspace,
+	// overriding the size required by the preset model. It only applies to nodes
+	// provisioned after it is set.
+	// +optional
+	NodeDiskSize *resource.Quantity `json:"nodeDiskSize,omitempty"`
+
+	// EphemeralStorage is the ephemeral-storage request and limit of the inference
+	// container of a preset workspace. It keeps the pod off nodes without enough free
+	// disk and bounds its disk usage.
+	// +optional
+	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty"`
+}
+
+// ConfidentialComputeSpec configures confidential computing for a workload.
+type ConfidentialComputeSpec struct {
+	// RuntimeClassName is the RuntimeClass the pods run with, e.g. a Kata Confidential
+	// Containers runtime. When empty, pods use the default runtime of the confidential VM.
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// Attestation runs an init container that attests the CPU and GPU TEE before the model
+	// is loaded. The pod does not start serving if attestation fails.
+	// +optional
+	Attestation *AttestationSpec `json:"attestation,omitempty"`
+}
+
+// AttestationSpec configures the attestation init container.
+type AttestationSpec struct {
+	// Image is the attestation client image. It must be pinned to a digest or to a tag
+	// other than latest. The container runs with the GPUs of the pod and must exit with a
+	// non-zero code if the evidence cannot be verified.
+	Image string `json:"image"`
+
+	// Endpoint is the https URL of the attestation service the evidence is sent to, e.g. a
+	// Microsoft Azure Attestation provider. It is passed in the KAITO_ATTESTATION_ENDPOINT
+	// environment variable.
+	// +kubebuilder:validation:Pattern=`^https://`
+	Endpoint string `json:"endpoint"`
+}
+
+// ProvisioningPolicy controls node provisioning for a single workspace.
+type ProvisioningPolicy string
+
+const (
+	// ProvisioningPolicyAuto follows the controller-wide node provisioner.
+	ProvisioningPolicyAuto ProvisioningPolicy = "Auto"
+	// ProvisioningPolicyPreferredOnly runs the workspace on existing matching nodes only.
+	ProvisioningPolicyPreferredOnly ProvisioningPolicy = "PreferredOnly"
+	// ProvisioningPolicyNever runs the workspace on existing matching nodes only and
+	// reports NodeProvisioningDisabled while they are missing.
+	ProvisioningPolicyNever ProvisioningPolicy = "Never"
+)
+
+// OptsOutOfProvisioning reports whether the workload opted out of node provisioning through
+// ProvisioningPolicy. Node auto-provisioning can also be disabled controller-wide with the
+// disableNodeAutoProvisioning feature gate, which callers check on their own.
+func (r *ResourceSpec) OptsOutOfProvisioning() bool {
+	return r.ProvisioningPolicy == ProvisioningPolicyPreferredOnly || r.ProvisioningPolicy == ProvisioningPolicyNever
+}
+
+// NextFallbackInstanceType returns the FallbackInstanceTypes entry that follows current, the
+// instance type the workspace is provisioned with. It returns false when the list is
+// exhausted or current is neither InstanceType nor one of the fallbacks.
+func (r *ResourceSpec) NextFallbackInstanceType(current string) (string, bool) {
+	next := 0
+	if current != r.InstanceType {
+		i := slices.Index(r.FallbackInstanceTypes, current)
+		if i < 0 {
+			return "", false
+		}
+		next = i + 1
+	}
+	if next >= len(r.FallbackInstanceTypes) {
+		return "", false
+	}
+	return r.FallbackInstanceTypes[next], true
+}
+
+// EffectiveInstanceType returns the instance type the workspace is provisioned with: the
+// fallback recorded in status.activeInstanceType while it is still listed in
+// resource.fallbackInstanceTypes, otherwise resource.instanceType.
+func (w *Workspace) EffectiveInstanceType() string {
+	if active := w.Status.ActiveInstanceType; active != "" && slices.Contains(w.Resource.FallbackInstanceTypes, active) {
+		return active
+	}
+	return w.Resource.InstanceType
 }
 
 // PartitionMode identifies the GPU partitioning technology.
@@ -106,6 +323,40 @@ type PresetOptions struct {
 	// ModelAccessSecret is the name of the secret that contains the huggingface access token.
 	// +optional
 	ModelAccessSecret string `json:"modelAccessSecret,omitempty"`
+	// AcceptLicense acknowledges the license of a gated model, e.g. "llama3.1". Gated
+	// presets are only deployed when it names the license of the model and the license
+	// has been accepted on HuggingFace by the account of the modelAccessSecret token.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9._-]*$`
+	// +optional
+	AcceptLicense string `json:"acceptLicense,omitempty"`
+	// Proxy configures the HTTP proxy used to download the model weights, for
+	// clusters without direct internet access. The settings are passed to the
+	// download init containers and to the runtime containers.
+	// +optional
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+}
+
+// ProxySpec describes an HTTP proxy and the CA bundle used to reach model registries.
+type ProxySpec struct {
+	// HTTPProxy is the proxy URL for HTTP requests, e.g. "http://proxy.example.com:3128".
+	// It must not contain credentials.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the proxy URL for HTTPS requests. IPv6 addresses must be enclosed in
+	// brackets, e.g. "http://[fd00::1]:3128". It must not contain credentials.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs that bypass the proxy.
+	// Loopback addresses and cluster-local service domains are always added.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+	// CABundleSecret is the name of a Secret in the workspace namespace with a "ca.crt"
+	// key holding a PEM bundle. The bundle replaces the default trust store of the
+	// download and runtime containers, so it must include the public root CAs as well
+	// as the CA of a TLS-intercepting proxy.
+	// +optional
+	CABundleSecret string `json:"caBundleSecret,omitempty"`
 }
 
 // PresetSpec provides the information for rendering preset configurations to run the model inference service.
@@ -134,6 +385,595 @@ type InferenceSpec struct {
 	// Users can specify multiple adapters for the model and the respective weight of using each of them.
 	// +optional
 	Adapters []AdapterSpec `json:"adapters,omitempty"`
+	// Logging configures how the inference server formats its logs and, optionally,
+	// forwards them to an external log store through a fluent-bit sidecar.
+	// +optional
+	Logging *LoggingSpec `json:"logging,omitempty"`
+	// Service customizes the Service that exposes the inference endpoint. Settings applied
+	// here are kept by the controller, unlike manual edits to the generated Service.
+	// +optional
+	Service *EndpointServiceSpec `json:"service,omitempty"`
+	// ToolCalling configures OpenAI-compatible tool calling of the vLLM runtime. Presets
+	// of model families with a known tool call parser enable tool calling by default;
+	// this field overrides the parser and chat template or turns tool calling off.
+	// +optional
+	ToolCalling *ToolCallingSpec `json:"toolCalling,omitempty"`
+	// StructuredOutputs configures the guided decoding backend the vLLM runtime uses for
+	// JSON schema, regex and grammar constrained requests. The memory the backend needs
+	// is accounted for when estimating the node count.
+	// +optional
+	StructuredOutputs *StructuredOutputsSpec `json:"structuredOutputs,omitempty"`
+	// Shutdown configures how the inference pods are stopped. Large models may need
+	// more than the default 30 seconds to finish in-flight requests and release the GPUs.
+	// +optional
+	Shutdown *ShutdownSpec `json:"shutdown,omitempty"`
+	// Probes tunes the startup, liveness and readiness probes of the inference container,
+	// e.g. to give a large model more time to load its weights. Fields that are not set
+	// keep the defaults of the preset.
+	// +optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
+	// Distributed configures multi-node inference, which is used when the model does not
+	// fit on the GPUs of a single node.
+	// +optional
+	Distributed *DistributedInferenceSpec `json:"distributed,omitempty"`
+	// ResponseCache adds a sidecar in front of the inference server that answers repeated
+	// completion and chat completion requests from a cache. Only non-streaming requests
+	// are cached, and cached responses are shared by all clients of the workspace.
+	// +optional
+	ResponseCache *ResponseCacheSpec `json:"responseCache,omitempty"`
+	// APINormalization adds a sidecar in front of the inference server that adapts OpenAI
+	// API requests to the variant the preset serves, so that clients can send the same
+	// requests to every workspace.
+	// +optional
+	APINormalization *APINormalizationSpec `json:"apiNormalization,omitempty"`
+	// Tracing exports OpenTelemetry traces of the inference requests, so that the latency
+	// of a request can be followed from the gateway to the model. Only supported by the
+	// vLLM runtime. Tracing cannot be added to or removed from an existing workspace, but
+	// its settings can be changed.
+	// +optional
+	Tracing *TracingSpec `json:"tracing,omitempty"`
+	// Tokenizer exposes the tokenizer of the model on a separate port of the Service, so that
+	// clients can count the tokens of a request, e.g. for budgeting, without sending a
+	// generation request. Only supported by the vLLM runtime.
+	// +optional
+	Tokenizer *TokenizerEndpointSpec `json:"tokenizer,omitempty"`
+	// ImagePull configures how the nodes pull the inference image. The image holds the
+	// runtime and its CUDA libraries and takes minutes to pull on a fresh node.
+	// +optional
+	ImagePull *ImagePullSpec `json:"imagePull,omitempty"`
+	// RuntimeOverrides passes env vars and command-line flags to the inference server of a
+	// preset, e.g. to select another vLLM attention backend, without switching to a custom
+	// template. Settings that KAITO manages itself are rejected.
+	// +optional
+	RuntimeOverrides *RuntimeOverridesSpec `json:"runtimeOverrides,omitempty"`
+}
+
+// DistributedRestartPolicy describes how a multi-node inference group reacts to a restart
+// of one of its pods.
+// +kubebuilder:validation:Enum=RecreateGroupOnPodRestart;None
+type DistributedRestartPolicy string
+
+const (
+	// DistributedRestartPolicyRecreateGroupOnPodRestart recreates all pods of the group when
+	// a container of any of them restarts, so the leader and workers initialize together.
+	DistributedRestartPolicyRecreateGroupOnPodRestart DistributedRestartPolicy = "RecreateGroupOnPodRestart"
+	// DistributedRestartPolicyNone only restarts the failed container.
+	DistributedRestartPolicyNone DistributedRestartPolicy = "None"
+)
+
+// DistributedInferenceSpec describes the leader and worker pods of multi-node inference.
+// Pod 0 is the leader; it runs the Ray head and serves the API, and the other pods join it
+// as workers. Every pod gets the KAITO_LEADER_ADDRESS, KAITO_GROUP_SIZE and POD_INDEX
+// environment variables. The group semantics only cover restarts; the pods are scheduled
+// together only with the kaito.sh/gang-scheduler annotation.
+type DistributedInferenceSpec struct {
+	// RestartPolicy defines what happens when a container of a pod in the group restarts.
+	// Defaults to RecreateGroupOnPodRestart.
+	// +kubebuilder:default=RecreateGroupOnPodRestart
+	// +optional
+	RestartPolicy DistributedRestartPolicy `json:"restartPolicy,omitempty"`
+	// SocketInterface is the network interface NCCL and Gloo use for communication between
+	// the nodes, e.g. "eth0". Defaults to the interface they detect.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]{1,15}$`
+	// +optional
+	SocketInterface string `json:"socketInterface,omitempty"`
+}
+
+// GetRestartPolicy returns the restart policy of the group, RecreateGroupOnPodRestart
+// unless set otherwise.
+func (d *DistributedInferenceSpec) GetRestartPolicy() DistributedRestartPolicy {
+	if d == nil || d.RestartPolicy == "" {
+		return DistributedRestartPolicyRecreateGroupOnPodRestart
+	}
+	return d.RestartPolicy
+}
+
+// ShutdownSpec describes the termination of the inference pods.
+type ShutdownSpec struct {
+	// TerminationGracePeriodSeconds is the time the inference pods get to stop, including
+	// the preStop hook, before they are killed. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// PreStop configures the hook run in the inference container before it is stopped.
+	// +optional
+	PreStop *PreStopSpec `json:"preStop,omitempty"`
+}
+
+// ProbesSpec overrides the probes of the inference container.
+type ProbesSpec struct {
+	// Startup tunes the startup probe, which covers the download and loading of the model.
+	// Liveness and readiness are only probed after it succeeds. By default it is probed
+	// every 10 seconds for 30 minutes, or 60 minutes for presets larger than 300Gi. When
+	// only periodSeconds is set, failureThreshold is derived to keep that window.
+	// +optional
+	Startup *ProbeSpec `json:"startup,omitempty"`
+	// Liveness tunes the liveness probe. The container is restarted when it fails.
+	// +optional
+	Liveness *ProbeSpec `json:"liveness,omitempty"`
+	// Readiness tunes the readiness probe. The pod is removed from the Service endpoints
+	// while it fails.
+	// +optional
+	Readiness *ProbeSpec `json:"readiness,omitempty"`
+}
+
+// ProbeSpec holds the timing of a probe. See corev1.Probe for the semantics of the fields.
+type ProbeSpec struct {
+	// InitialDelaySeconds is the time after the container started before the first probe.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is the interval between two probes.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the time after which a probe fails.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failed probes after which the probe fails.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// PreStopSpec describes the preStop hook of the inference container. The hook first
+// waits DrainSeconds, then calls UnloadPath, and the server is stopped afterwards.
+type PreStopSpec struct {
+	// DrainSeconds is how long to wait before stopping the server, so the pod is removed
+	// from the Service endpoints and in-flight requests can complete.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	DrainSeconds int32 `json:"drainSeconds,omitempty"`
+	// UnloadPath is an HTTP path on the inference server that is called with POST after
+	// draining, e.g. "/sleep?level=1" to release the model weights and KV cache of vLLM.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9/_.~-]*(\?[A-Za-z0-9_.~=&-]*)?$`
+	// +optional
+	UnloadPath string `json:"unloadPath,omitempty"`
+}
+
+// ResponseCacheBackend is where cached responses are stored.
+// +kubebuilder:validation:Enum=Memory;Redis
+type ResponseCacheBackend string
+
+const (
+	// ResponseCacheBackendMemory keeps an LRU cache in the memory of the sidecar of each pod.
+	ResponseCacheBackendMemory ResponseCacheBackend = "Memory"
+	// ResponseCacheBackendRedis stores the responses in a Redis server shared by all pods.
+	ResponseCacheBackendRedis ResponseCacheBackend = "Redis"
+)
+
+// ResponseCacheKeyPolicy describes which requests are considered the same.
+// +kubebuilder:validation:Enum=ExactPrompt;NormalizedPrompt
+type ResponseCacheKeyPolicy string
+
+const (
+	// ResponseCacheKeyPolicyExactPrompt matches requests with the same path and JSON body,
+	// regardless of the order of the JSON keys.
+	ResponseCacheKeyPolicyExactPrompt ResponseCacheKeyPolicy = "ExactPrompt"
+	// ResponseCacheKeyPolicyNormalizedPrompt also ignores leading, trailing and repeated
+	// whitespace in the prompt and message contents.
+	ResponseCacheKeyPolicyNormalizedPrompt ResponseCacheKeyPolicy = "NormalizedPrompt"
+)
+
+// ResponseCacheSpec describes the response cache sidecar of the inference pods.
+type ResponseCacheSpec struct {
+	// Backend stores the cached responses. Defaults to Memory.
+	// +kubebuilder:default=Memory
+	// +optional
+	Backend ResponseCacheBackend `json:"backend,omitempty"`
+	// KeyPolicy decides which requests share a cached response. Defaults to ExactPrompt.
+	// +kubebuilder:default=ExactPrompt
+	// +optional
+	KeyPolicy ResponseCacheKeyPolicy `json:"keyPolicy,omitempty"`
+	// TTL is how long a response is served from the cache. Defaults to 10m.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// MaxEntries bounds the number of responses kept by the Memory backend. Defaults to 1000.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	MaxEntries *int32 `json:"maxEntries,omitempty"`
+	// Redis configures the Redis backend. It is required when Backend is Redis.
+	// +optional
+	Redis *RedisCacheSpec `json:"redis,omitempty"`
+	// IdentityHeaders are request headers that identify the caller, such as a tenant header
+	// set by a gateway. Responses are only shared by requests with the same values of these
+	// headers and of the Authorization header, which is always part of the cache key.
+	// +kubebuilder:validation:MaxItems=8
+	// +listType=set
+	// +optional
+	IdentityHeaders []string `json:"identityHeaders,omitempty"`
+}
+
+// RedisCacheSpec describes the Redis server of the response cache.
+type RedisCacheSpec struct {
+	// Address is the host:port of the Redis server.
+	// +kubebuilder:validation:MaxLength=261
+	Address string `json:"address"`
+	// PasswordSecret is the name of a Secret in the same namespace as the Workspace with
+	// the Redis password in its "password" key.
+	// +optional
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+	// TLS connects to Redis over TLS.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+}
+
+// APITranslation serves one OpenAI endpoint through the other.
+// +kubebuilder:validation:Enum=None;ChatToCompletions;CompletionsToChat
+type APITranslation string
+
+const (
+	// APITranslationNone forwards requests to the endpoint they were sent to.
+	APITranslationNone APITranslation = "None"
+	// APITranslationChatToCompletions answers /v1/chat/completions through /v1/completions,
+	// for models without a chat template.
+	APITranslationChatToCompletions APITranslation = "ChatToCompletions"
+	// APITranslationCompletionsToChat answers /v1/completions through /v1/chat/completions,
+	// for models that only serve chat.
+	APITranslationCompletionsToChat APITranslation = "CompletionsToChat"
+)
+
+// MaxTokensField is the name of the output token limit in a request.
+// +kubebuilder:validation:Enum=max_tokens;max_completion_tokens
+type MaxTokensField string
+
+const (
+	MaxTokensFieldMaxTokens           MaxTokensField = "max_tokens"
+	MaxTokensFieldMaxCompletionTokens MaxTokensField = "max_completion_tokens"
+)
+
+// APINormalizationSpec describes the API normalization sidecar of the inference pods.
+type APINormalizationSpec struct {
+	// Translate serves one OpenAI endpoint through the other. With ChatToCompletions the
+	// messages are rendered as a prompt of "<role>: <content>" lines followed by
+	// "assistant:". With CompletionsToChat the prompt is sent as a single user message.
+	// Responses, including streamed ones, are converted back. Defaults to None.
+	// +kubebuilder:default=None
+	// +optional
+	Translate APITranslation `json:"translate,omitempty"`
+	// MaxTokensField is the name the inference server expects for the output token limit.
+	// max_tokens and max_completion_tokens in requests are renamed to it. Defaults to
+	// max_tokens.
+	// +kubebuilder:default=max_tokens
+	// +optional
+	MaxTokensField MaxTokensField `json:"maxTokensField,omitempty"`
+	// DefaultModel is set as the model of requests that do not name one. Defaults to the
+	// first model listed by the inference server at /v1/models.
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._:/@-]*$`
+	// +optional
+	DefaultModel string `json:"defaultModel,omitempty"`
+	// AdapterHeader is the name of a request header that selects the LoRA adapter of a
+	// completion or chat completion request, e.g. x-adapter. vLLM serves each adapter of
+	// spec.inference.adapters under the name of its source. When the header names one of
+	// them, it replaces the model of the request, so gateways can route a header to an
+	// adapter without changing the request body. Other values are rejected with 404, and
+	// requests without the header are not changed.
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9-]*$`
+	// +optional
+	AdapterHeader string `json:"adapterHeader,omitempty"`
+}
+
+// TokenizerEndpointSpec describes the tokenizer endpoint of the inference pods. A sidecar
+// answers POST /tokenize and /detokenize with the tokenizer and chat template of the
+// inference server and rejects all other requests, so no generation can be run through it.
+type TokenizerEndpointSpec struct {
+	// Port is the port of the Service the tokenizer endpoint is exposed on. It must differ
+	// from the other ports of the Service: 80, 6379 and 8265. Defaults to 8080.
+	// +kubebuilder:default=8080
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// GetPort returns the Service port of the tokenizer endpoint, 8080 unless set otherwise.
+func (t *TokenizerEndpointSpec) GetPort() int32 {
+	if t == nil || t.Port == 0 {
+		return DefaultTokenizerServicePort
+	}
+	return t.Port
+}
+
+// DefaultTokenizerServicePort is the Service port of the tokenizer endpoint when
+// TokenizerEndpointSpec.Port is not set.
+const DefaultTokenizerServicePort = int32(8080)
+
+// ImagePullSpec configures how the nodes pull the inference image.
+type ImagePullSpec struct {
+	// Policy is the image pull policy of the containers that run the inference image.
+	// When empty, Kubernetes pulls the tagged image only if it is not present on the node.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	Policy v1.PullPolicy `json:"policy,omitempty"`
+	// LazyPull starts the inference pods before the image is fully pulled, so the pull
+	// overlaps with the download of the model weights.
+	// +optional
+	LazyPull *LazyPullSpec `json:"lazyPull,omitempty"`
+}
+
+// LazyPullSpec configures lazy pulling of the inference image.
+type LazyPullSpec struct {
+	// RuntimeClassName is the RuntimeClass the inference pods run with. Its containerd
+	// runtime handler must use a lazy-pulling snapshotter, such as the stargz snapshotter,
+	// which fetches the files of eStargz images on first access and falls back to a
+	// regular pull for other images.
+	// +kubebuilder:validation:MaxLength=253
+	RuntimeClassName string `json:"runtimeClassName"`
+}
+
+// WorkspaceCloneRequest describes the clone of a Workspace requested with the
+// kaito.sh/clone-to annotation. The clone gets the inference spec, the resource spec and
+// the runtime pins of the source, with the overrides below.
+type WorkspaceCloneRequest struct {
+	// Name of the clone.
+	Name string `json:"name"`
+	// Namespace of the clone. Defaults to the namespace of the source. Another namespace
+	// must list the namespace of the source in its kaito.sh/allow-clone-from annotation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// InstanceType replaces the instance type of the source.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// Adapters replace the adapters of the source. An empty list removes them; when unset,
+	// the adapters of the source are kept.
+	// +optional
+	Adapters *[]AdapterSpec `json:"adapters,omitempty"`
+}
+
+// RuntimeOverridesSpec holds the env vars and flags passed to the inference server.
+type RuntimeOverridesSpec struct {
+	// Env is added to the env of the inference container. Names of env vars that KAITO
+	// sets or that select the GPUs of the container, such as CUDA_VISIBLE_DEVICES, are
+	// rejected.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Env []RuntimeEnvVar `json:"env,omitempty"`
+	// ExtraArgs are appended to the command of the vLLM server, keyed by the flag name
+	// without the leading dashes, e.g. "max-num-seqs": "128". An empty value passes the
+	// flag without a value. Flags that KAITO sets, such as the model, the port and the
+	// parallelism, are rejected. Only supported by the vLLM runtime.
+	// +kubebuilder:validation:MaxProperties=32
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+}
+
+// RuntimeEnvVar is an env var of the inference container.
+type RuntimeEnvVar struct {
+	// Name of the env var. It must be a C identifier.
+	// +kubebuilder:validation:MaxLength=128
+	Name string `json:"name"`
+	// Value of the env var.
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// OTLPProtocol is the transport used to export OpenTelemetry traces.
+// +kubebuilder:validation:Enum=grpc;http/protobuf
+type OTLPProtocol string
+
+const (
+	OTLPProtocolGRPC         OTLPProtocol = "grpc"
+	OTLPProtocolHTTPProtobuf OTLPProtocol = "http/protobuf"
+)
+
+// TracingSpec describes how the inference server exports OpenTelemetry traces.
+type TracingSpec struct {
+	// Endpoint is the URL of the OTLP collector, e.g. "http://otel-collector.monitoring:4317".
+	// +kubebuilder:validation:MaxLength=512
+	// +kubebuilder:validation:Pattern=`^https?://[^\s]+$`
+	Endpoint string `json:"endpoint"`
+	// Protocol is the OTLP transport of the endpoint. Defaults to grpc.
+	// +kubebuilder:default=grpc
+	// +optional
+	Protocol OTLPProtocol `json:"protocol,omitempty"`
+	// SampleRatio is the fraction of the requests without a sampled parent span that are
+	// traced, between 0 and 1. Requests that carry a sampled traceparent header are always
+	// traced. It is defined as a string to be language agnostic. Defaults to "1".
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	SampleRatio *string `json:"sampleRatio,omitempty"`
+	// ServiceName is the service.name resource attribute of the spans. Defaults to the
+	// name of the workspace.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._-]*$`
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// StructuredOutputsBackend is a vLLM guided decoding backend.
+// +kubebuilder:validation:Enum=auto;xgrammar;guidance;outlines;lm-format-enforcer
+type StructuredOutputsBackend string
+
+const (
+	StructuredOutputsBackendAuto             StructuredOutputsBackend = "auto"
+	StructuredOutputsBackendXGrammar         StructuredOutputsBackend = "xgrammar"
+	StructuredOutputsBackendGuidance         StructuredOutputsBackend = "guidance"
+	StructuredOutputsBackendOutlines         StructuredOutputsBackend = "outlines"
+	StructuredOutputsBackendLMFormatEnforcer StructuredOutputsBackend = "lm-format-enforcer"
+)
+
+// StructuredOutputsSpec describes the default guided decoding backend of the vLLM runtime.
+type StructuredOutputsSpec struct {
+	// Backend used for requests with a response_format or structured_outputs
+	// parameter. "auto" lets vLLM choose per request.
+	// +kubebuilder:default=auto
+	// +optional
+	Backend StructuredOutputsBackend `json:"backend,omitempty"`
+}
+
+// ToolCallingSpec describes how the vLLM runtime parses tool calls.
+type ToolCallingSpec struct {
+	// Disabled turns off automatic tool choice even if the preset enables it by default.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Parser is the vLLM tool call parser, e.g. "llama3_json", "mistral" or "hermes".
+	// Defaults to the parser of the preset's model family.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9_-]*$`
+	// +optional
+	Parser string `json:"parser,omitempty"`
+	// ChatTemplate is the file name of a chat template shipped in the KAITO inference
+	// image under /workspace/chat_templates, e.g. "tool-chat-mistral.jinja".
+	// Defaults to the template of the preset's model family, if any.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._-]*\.jinja$`
+	// +optional
+	ChatTemplate string `json:"chatTemplate,omitempty"`
+}
+
+// EndpointServiceSpec describes the Service that exposes the inference endpoint.
+type EndpointServiceSpec struct {
+	// Type of the Service. Defaults to ClusterIP, or LoadBalancer when the
+	// kaito.sh/enablelb annotation is "True".
+	// +kubebuilder:validation:Enum=ClusterIP;LoadBalancer;NodePort
+	// +optional
+	Type v1.ServiceType `json:"type,omitempty"`
+	// IPFamilyPolicy of the Service, e.g. PreferDualStack or RequireDualStack for
+	// dual-stack clusters. Defaults to the cluster default.
+	// +optional
+	IPFamilyPolicy *v1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// IPFamilies of the Service in order of preference, e.g. ["IPv6", "IPv4"].
+	// This field is immutable.
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+	// InternalTrafficPolicy of the Service. Local only routes in-cluster traffic to the
+	// endpoint when it runs on the client's node, e.g. for a gateway that runs on the GPU
+	// nodes, and drops it otherwise. Defaults to Cluster.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	InternalTrafficPolicy *v1.ServiceInternalTrafficPolicy `json:"internalTrafficPolicy,omitempty"`
+	// TrafficDistribution of the Service. PreferSameZone routes clients to endpoints in
+	// their own zone when there are any, which avoids cross-zone data charges and latency
+	// when streaming tokens, and PreferSameNode to endpoints on their own node. Requires
+	// Kubernetes 1.33 or later; PreferClose is the older name of PreferSameZone.
+	// +kubebuilder:validation:Enum=PreferClose;PreferSameZone;PreferSameNode
+	// +optional
+	TrafficDistribution *string `json:"trafficDistribution,omitempty"`
+	// Annotations added to the Service, e.g.
+	// service.beta.kubernetes.io/azure-load-balancer-internal: "true" for an internal Azure
+	// load balancer. Keys with the kaito.sh/ prefix are reserved.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// DNS publishes the Service under a stable DNS name, so that consumers outside the
+	// cluster can reach the model.
+	// +optional
+	DNS *ServiceDNSSpec `json:"dns,omitempty"`
+	// Timeouts of the connections to the inference endpoint. Raise them so that proxies
+	// do not cut long streaming generations.
+	// +optional
+	Timeouts *ServiceTimeoutsSpec `json:"timeouts,omitempty"`
+}
+
+// ServiceTimeoutsSpec configures the timeouts of the proxies in front of the inference
+// server and of the server itself.
+type ServiceTimeoutsSpec struct {
+	// Request is the maximum duration of a request, including the streamed response. It is
+	// set as timeouts.request on the HTTPRoute rules that send requests to the
+	// InferencePool of an InferenceSet, and has no effect on a Workspace outside an
+	// InferenceSet. 0s disables the timeout. Fractions of a millisecond are not supported.
+	// +optional
+	Request *metav1.Duration `json:"request,omitempty"`
+	// Idle is how long the cloud load balancer keeps an idle connection open. It is set as
+	// the idle timeout annotation of a LoadBalancer Service on Azure, rounded up to whole
+	// minutes between 4 and 100, and on AWS, rounded up to whole seconds.
+	// +optional
+	Idle *metav1.Duration `json:"idle,omitempty"`
+	// KeepAlive is how long the inference server keeps an idle HTTP keep-alive connection
+	// open, rounded up to whole seconds. Keep it longer than the idle timeout of the proxies
+	// in front of the server, so that they close idle connections first. Only supported by
+	// the vLLM runtime, which defaults to 5s.
+	// +optional
+	KeepAlive *metav1.Duration `json:"keepAlive,omitempty"`
+}
+
+// ServiceDNSSpec publishes the inference Service through ExternalDNS and multi-cluster
+// services. At least one of Hostname and Export must be set.
+type ServiceDNSSpec struct {
+	// Hostname is the fully qualified DNS name ExternalDNS creates for the Service, e.g.
+	// phi-4.models.example.com. It is set as the external-dns.alpha.kubernetes.io/hostname
+	// annotation of the Service, and ExternalDNS must be installed and manage the zone.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// TTL of the DNS record in seconds. Defaults to the TTL of the ExternalDNS provider.
+	// Requires Hostname.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int32 `json:"ttl,omitempty"`
+	// Export creates a ServiceExport for the Service, so that the clusters of the same
+	// ClusterSet reach it at <name>.<namespace>.svc.clusterset.local. Requires a
+	// multi-cluster services implementation that serves the multicluster.x-k8s.io API.
+	// +optional
+	Export bool `json:"export,omitempty"`
+}
+
+// LogFormat is the output format of the inference server logs.
+// +kubebuilder:validation:Enum=text;json
+type LogFormat string
+
+const (
+	// LogFormatText emits human-readable log lines (default).
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON emits one JSON object per log line on stdout.
+	LogFormatJSON LogFormat = "json"
+)
+
+// LoggingSpec describes the logging behavior of the inference workload.
+type LoggingSpec struct {
+	// Format selects the log output format of the inference server.
+	// Defaults to "text" when not specified.
+	// +optional
+	Format LogFormat `json:"format,omitempty"`
+	// Forwarder, if set, adds a fluent-bit sidecar that ships the inference server logs to
+	// the destination described in the referenced Secret. The server also writes its logs
+	// to a volume shared with the sidecar; the sidecar does not read node log files.
+	// +optional
+	Forwarder *LogForwarderSpec `json:"forwarder,omitempty"`
+}
+
+// LogForwarderSpec describes the fluent-bit sidecar used to forward inference logs.
+type LogForwarderSpec struct {
+	// DestinationSecret is the name of a Secret in the same namespace as the Workspace.
+	// It must contain an "output.conf" key holding one or more fluent-bit [OUTPUT]
+	// sections, e.g. an Elasticsearch, Loki or Azure Log Analytics output.
+	DestinationSecret string `json:"destinationSecret"`
+	// Image overrides the default fluent-bit image used by the sidecar.
+	// +optional
+	Image string `json:"image,omitempty"`
 }
 
 type AdapterSpec struct {
@@ -205,6 +1045,192 @@ type TuningSpec struct {
 	Input *DataSource `json:"input"`
 	// Output specified where to store the tuning output.
 	Output *DataDestination `json:"output"`
+	// Deploy, if set, serves the tuned adapter from an inference Workspace once the
+	// tuning job succeeds.
+	// +optional
+	Deploy *TuningDeploySpec `json:"deploy,omitempty"`
+	// Retention bounds how long the tuning Job and the intermediate checkpoints of a run
+	// are kept. Changing it does not restart tuning.
+	// +optional
+	Retention *TuningRetentionSpec `json:"retention,omitempty"`
+}
+
+// TuningRetentionSpec configures the cleanup of the objects and artifacts of a tuning run.
+type TuningRetentionSpec struct {
+	// TTLSecondsAfterFinished deletes the tuning Job and its pods this many seconds after
+	// the Job succeeds or fails and its outcome is recorded in the workspace status. The
+	// Job is kept until the workspace is deleted when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// KeepLastN is the number of intermediate checkpoints kept in the output directory
+	// once tuning succeeds. Older checkpoints are deleted before the output is pushed; the
+	// tuned adapter is always kept. All checkpoints are kept when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLastN *int32 `json:"keepLastN,omitempty"`
+}
+
+// TuningDeploySpec describes the inference Workspace that serves the output adapter of a
+// tuning Workspace.
+type TuningDeploySpec struct {
+	// Workspace is the name of the inference Workspace in the same namespace. If it does not
+	// exist, it is created with the tuned preset and the resource spec of the tuning
+	// Workspace. An existing Workspace must serve the tuned preset; the adapter is added to
+	// it, or replaces its adapter with the same name.
+	// +kubebuilder:validation:MaxLength=63
+	Workspace string `json:"workspace"`
+	// AdapterName is the name of the adapter in the inference Workspace. Defaults to the name
+	// of the tuning Workspace.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	AdapterName string `json:"adapterName,omitempty"`
+	// Strength is the default strength of the adapter, between 0 and 1. Defaults to "1.0".
+	// +optional
+	Strength *string `json:"strength,omitempty"`
+}
+
+// ServiceAccountSpec configures the ServiceAccount the workspace pods run as.
+type ServiceAccountSpec struct {
+	// Name is an existing ServiceAccount in the workspace namespace to run the pods as. When
+	// empty, the controller generates a ServiceAccount named after the workspace. It cannot be
+	// combined with identity, which annotates the generated ServiceAccount.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// ReadConfigMaps are ConfigMaps of the workspace namespace the pods may get and watch
+	// through the Kubernetes API, e.g. to reload adapters or configuration.
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	ReadConfigMaps []string `json:"readConfigMaps,omitempty"`
+	// ReadSecrets are Secrets of the workspace namespace the pods may get and watch through
+	// the Kubernetes API. Without ReadConfigMaps and ReadSecrets the generated ServiceAccount
+	// has no permissions and its API token is not mounted into the pods.
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	ReadSecrets []string `json:"readSecrets,omitempty"`
+}
+
+// MaintenanceDay is a day of the week of a maintenance window.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceDay string
+
+// WorkspaceMaintenanceWindow is a recurring time window in which disruptive operations on a
+// workspace may start.
+type WorkspaceMaintenanceWindow struct {
+	// Days are the days of the week the window opens on. The window opens every day when
+	// empty.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=7
+	Days []MaintenanceDay `json:"days,omitempty"`
+	// StartTime is the time of day the window opens, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+	// EndTime is the time of day the window closes, as HH:MM. When it is not after
+	// StartTime, the window closes on the next day, e.g. 22:00 to 04:00.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	EndTime string `json:"endTime"`
+	// TimeZone is the IANA time zone of StartTime and EndTime, e.g. Europe/Berlin.
+	// Defaults to UTC.
+	// +optional
+	// +kubebuilder:default:="UTC"
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MetadataPropagationTarget is a kind of resource generated for a workspace.
+// +kubebuilder:validation:Enum=Pod;NodeClaim;Service
+type MetadataPropagationTarget string
+
+const (
+	// MetadataPropagationTargetPod is the pods of the inference workload or tuning job.
+	MetadataPropagationTargetPod MetadataPropagationTarget = "Pod"
+	// MetadataPropagationTargetNodeClaim is the NodeClaims of the workspace. The labels are
+	// also set on the nodes created for them.
+	MetadataPropagationTargetNodeClaim MetadataPropagationTarget = "NodeClaim"
+	// MetadataPropagationTargetService is the inference Service and its headless Service.
+	MetadataPropagationTargetService MetadataPropagationTarget = "Service"
+)
+
+// MetadataPropagationSpec selects the labels and annotations of a workspace that are copied
+// to the resources generated for it. Keys in the kaito.sh domain and kubectl annotations
+// are never copied, and keys set by KAITO on a generated resource are not overwritten.
+type MetadataPropagationSpec struct {
+	// Labels are the keys of the labels to copy. A key ending in "*" matches every key
+	// with that prefix, e.g. "cost.example.com/*".
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=32
+	Labels []string `json:"labels,omitempty"`
+	// Annotations are the keys of the annotations to copy, with the same wildcard as Labels.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=32
+	Annotations []string `json:"annotations,omitempty"`
+	// Targets are the generated resources the labels and annotations are copied to. All of
+	// them when empty.
+	// +optional
+	// +listType=set
+	Targets []MetadataPropagationTarget `json:"targets,omitempty"`
+}
+
+// PendingOperationType is a disruptive operation that waits for the maintenance window.
+type PendingOperationType string
+
+const (
+	// PendingOperationRollout is the rollout of a new revision of the inference workload.
+	PendingOperationRollout PendingOperationType = "Rollout"
+	// PendingOperationNodeReplacement is the replacement of drifted nodes.
+	PendingOperationNodeReplacement PendingOperationType = "NodeReplacement"
+	// PendingOperationInstanceTypeMigration is the replacement of a workspace of an
+	// InferenceSet by one of the instance type of the InferenceSet template.
+	PendingOperationInstanceTypeMigration PendingOperationType = "InstanceTypeMigration"
+)
+
+// PendingOperation is a disruptive operation deferred until the maintenance window opens.
+type PendingOperation struct {
+	// Type of the operation.
+	Type PendingOperationType `json:"type"`
+	// Message describes the operation.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// NextWindow is when the maintenance window opens next.
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+}
+
+// WorkloadIdentityProvider is the cloud identity system the workspace pods authenticate with.
+type WorkloadIdentityProvider string
+
+const (
+	// WorkloadIdentityProviderAzure uses AKS Workload Identity with a Microsoft Entra
+	// application or user-assigned managed identity.
+	WorkloadIdentityProviderAzure WorkloadIdentityProvider = "Azure"
+	// WorkloadIdentityProviderAWS uses EKS IAM Roles for Service Accounts (IRSA).
+	WorkloadIdentityProviderAWS WorkloadIdentityProvider = "AWS"
+)
+
+// WorkloadIdentitySpec lets the workspace pods access private model and data storage with a
+// cloud workload identity instead of static secrets. The controller runs the pods under a
+// ServiceAccount annotated for the identity and mounts a projected token for it.
+type WorkloadIdentitySpec struct {
+	// Provider selects the cloud identity system.
+	// +kubebuilder:validation:Enum=Azure;AWS
+	Provider WorkloadIdentityProvider `json:"provider"`
+	// ClientID is the client ID of the Microsoft Entra application or managed identity that
+	// trusts the workspace ServiceAccount. Required for Azure.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+	// TenantID is the Microsoft Entra tenant of ClientID. Required for Azure.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+	// RoleARN is the IAM role the workspace ServiceAccount assumes. Required for AWS.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+	// StorageURLs lists the object storage the workloads read, as Azure Blob container URLs
+	// (https://<account>.blob.core.windows.net/<container>) or S3 URLs (s3://<bucket>/<prefix>).
+	// The controller verifies that the identity can list each of them before rolling out.
+	// +optional
+	StorageURLs []string `json:"storageURLs,omitempty"`
 }
 
 // WorkspaceState indicates the high-level state of the workspace.
@@ -264,13 +1290,299 @@ type WorkspaceStatus struct {
 	// This field remains immutable after being set by NodesEstimator.
 	TargetNodeCount int32 `json:"targetNodeCount,omitempty"`
 
+	// ActiveInstanceType is the entry of resource.fallbackInstanceTypes the workspace is
+	// provisioned with after resource.provisioningTimeout expired. It is empty while
+	// resource.instanceType is used.
+	// +optional
+	ActiveInstanceType string `json:"activeInstanceType,omitempty"`
+
 	// Performance holds the metrics from the post-load inference benchmark.
 	// Populated by default; omitted when kaito.sh/disable-benchmark is set to "true".
 	// +optional
 	Performance *Performance `json:"performance,omitempty"`
+
+	// FinishedTuningRevision is the workspace revision whose tuning Job last succeeded or
+	// failed. The outcome of that Job stays in the status after the Job is deleted, and the
+	// Job is not recreated until the revision changes.
+	// +optional
+	FinishedTuningRevision string `json:"finishedTuningRevision,omitempty"`
+
+	// ColdStart records when an inference Workspace reached each stage of its first startup.
+	// +optional
+	ColdStart *ColdStartStatus `json:"coldStart,omitempty"`
+
+	// GPUUtilization is the latest utilization of the GPUs of the workspace, read from the
+	// DCGM exporter. It is only set when the gpuUtilizationCollection feature gate is enabled.
+	// +optional
+	GPUUtilization *GPUUtilizationStatus `json:"gpuUtilization,omitempty"`
+
+	// RightSizing is the instance type recommended for the workspace when its GPUs have been
+	// underused. It is advisory and is never applied by the controller.
+	// +optional
+	RightSizing *RightSizingRecommendation `json:"rightSizing,omitempty"`
+
+	// Zones counts the nodes of the workspace per availability zone, showing which zones
+	// satisfied its NodeClaims when troubleshooting capacity.
+	// +optional
+	Zones []ZoneNodeCount `json:"zones,omitempty"`
+
+	// ProvisioningPlan is the node provisioning the controller would do for the workspace. It
+	// is only set while the workspace has the kaito.sh/simulate annotation.
+	// +optional
+	ProvisioningPlan *ProvisioningPlan `json:"provisioningPlan,omitempty"`
+
+	// Tuning reports the training progress of a tuning workspace.
+	// +optional
+	Tuning *TuningStatus `json:"tuning,omitempty"`
+
+	// Lineage records what the output adapter of a tuning workspace was derived from.
+	// +optional
+	Lineage *LineageStatus `json:"lineage,omitempty"`
+
+	// Replicas reports the readiness and node binding of each inference pod of the workspace,
+	// sorted by pod name.
+	// +optional
+	// +listType=map
+	// +listMapKey=podName
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+
+	// PendingOperations are the disruptive operations deferred until the maintenance
+	// window opens.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	PendingOperations []PendingOperation `json:"pendingOperations,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that the status reflects.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase summarizes the status. Together with observedGeneration and the Progressing
+	// condition, it lets GitOps tools wait for a change to roll out.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Health is the machine readable health of the object for GitOps tools such as
+	// Argo CD and Flux.
+	// +optional
+	Health *HealthStatus `json:"health,omitempty"`
+
+	// TeardownStep is the step the controller has reached in deleting the workspace. The
+	// steps run in order, and a step starts only once the previous one has finished.
+	// +optional
+	TeardownStep TeardownStep `json:"teardownStep,omitempty"`
+}
+
+// TeardownStep is a step of deleting a Workspace.
+// +kubebuilder:validation:Enum=GatewayDetach;Drain;WorkloadDelete;NodeClaimDelete;AuxiliaryCleanup
+type TeardownStep string
+
+const (
+	// TeardownStepGatewayDetach stops the Gateway API HTTPRoutes from sending new requests
+	// to the Service of the workspace.
+	TeardownStepGatewayDetach TeardownStep = "GatewayDetach"
+	// TeardownStepDrain scales the inference workload to zero and waits for its pods to
+	// finish the requests in flight and exit.
+	TeardownStepDrain TeardownStep = "Drain"
+	// TeardownStepWorkloadDelete deletes the workloads, Jobs and Services of the workspace.
+	TeardownStepWorkloadDelete TeardownStep = "WorkloadDelete"
+	// TeardownStepNodeClaimDelete deletes the nodes provisioned for the workspace.
+	TeardownStepNodeClaimDelete TeardownStep = "NodeClaimDelete"
+	// TeardownStepAuxiliaryCleanup deletes the ControllerRevisions of the workspace. The
+	// other objects it owns are garbage collected once it is gone.
+	TeardownStepAuxiliaryCleanup TeardownStep = "AuxiliaryCleanup"
+)
+
+// ReplicaStatus is the status of one inference pod of a Workspace.
+type ReplicaStatus struct {
+	// PodName is the name of the pod.
+	PodName string `json:"podName"`
+
+	// NodeName is the node the pod is bound to. It is empty while the pod is not scheduled.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Ready is true when the pod is Ready to serve requests.
+	Ready bool `json:"ready"`
+
+	// Restarts is the number of times the inference container has restarted.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// LastError is the most recent reason the pod is not serving, such as a container
+	// waiting in CrashLoopBackOff, a failed container exit or an unschedulable pod.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ColdStartStatus holds the time each startup stage of an inference Workspace completed.
+// Every timestamp is set once and never updated, so later restarts do not change it.
+type ColdStartStatus struct {
+	// NodeClaimsCreatedTime is when the first NodeClaim of the Workspace was created.
+	// It is not set when node auto-provisioning is disabled.
+	// +optional
+	NodeClaimsCreatedTime *metav1.Time `json:"nodeClaimsCreatedTime,omitempty"`
+
+	// NodesReadyTime is when the last worker node of the Workspace became Ready.
+	// +optional
+	NodesReadyTime *metav1.Time `json:"nodesReadyTime,omitempty"`
+
+	// ImagePulledTime is when the inference container had started on every pod, which
+	// happens once its image is pulled.
+	// +optional
+	ImagePulledTime *metav1.Time `json:"imagePulledTime,omitempty"`
+
+	// InferenceReadyTime is when every pod became Ready: the model weights are loaded and
+	// the server answers requests. It includes the post-load benchmark when enabled.
+	// +optional
+	InferenceReadyTime *metav1.Time `json:"inferenceReadyTime,omitempty"`
+
+	// FirstTokenTime is when the inference server first served a generated token, to a
+	// one-token completion request the controller sends once every pod is Ready. It is only
+	// set for the vLLM runtime.
+	// +optional
+	FirstTokenTime *metav1.Time `json:"firstTokenTime,omitempty"`
+}
+
+// GPUUtilizationStatus rolls up the DCGM metrics of the GPUs a Workspace runs on.
+type GPUUtilizationStatus struct {
+	// GPUCount is the number of GPUs the utilization was read from.
+	GPUCount int32 `json:"gpuCount"`
+
+	// UtilizationPercent is the average utilization of the GPUs, from 0 to 100.
+	UtilizationPercent int32 `json:"utilizationPercent"`
+
+	// MemoryUsed is the GPU memory in use, summed over the GPUs.
+	MemoryUsed resource.Quantity `json:"memoryUsed"`
+
+	// MemoryTotal is the GPU memory of the GPUs, summed over the GPUs.
+	MemoryTotal resource.Quantity `json:"memoryTotal"`
+
+	// LastSampleTime is when the metrics were read.
+	LastSampleTime metav1.Time `json:"lastSampleTime"`
+
+	// LowUtilizationSince is when the utilization dropped below the right-sizing threshold.
+	// It is cleared by the first sample at or above the threshold.
+	// +optional
+	LowUtilizationSince *metav1.Time `json:"lowUtilizationSince,omitempty"`
+}
+
+// ZoneNodeCount is the number of nodes of a Workspace in an availability zone.
+type ZoneNodeCount struct {
+	// Zone is the topology.kubernetes.io/zone label of the nodes.
+	Zone string `json:"zone"`
+
+	// Nodes is the number of nodes of the workspace in the zone.
+	Nodes int32 `json:"nodes"`
+}
+
+// RightSizingRecommendation is a smaller instance type that fits the model of a Workspace
+// whose GPUs have been underused.
+type RightSizingRecommendation struct {
+	// InstanceType is the recommended instance type.
+	InstanceType string `json:"instanceType"`
+
+	// Message explains the recommendation, with the utilization it is based on.
+	Message string `json:"message"`
+
+	// GeneratedTime is when the recommendation was made.
+	GeneratedTime metav1.Time `json:"generatedTime"`
+}
+
+// ProvisioningPlan is the node provisioning computed for a Workspace in simulation mode.
+type ProvisioningPlan struct {
+	// InstanceType is the GPU node SKU of the NodeClaims to create. It is empty when node
+	// auto-provisioning is disabled for the workspace.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// TargetNodeCount is the number of nodes the workload needs.
+	TargetNodeCount int32 `json:"targetNodeCount"`
+
+	// ReadyNodes is the number of ready nodes that already match the workspace.
+	ReadyNodes int32 `json:"readyNodes"`
+
+	// ExistingNodeClaims is the number of NodeClaims the workspace already has.
+	ExistingNodeClaims int32 `json:"existingNodeClaims"`
+
+	// NodeClaimsToCreate is the number of NodeClaims the controller would create.
+	NodeClaimsToCreate int32 `json:"nodeClaimsToCreate"`
+
+	// GPUsToCreate is the number of GPUs of the NodeClaims to create. It is omitted when the
+	// GPU count of the instance type is unknown.
+	// +optional
+	GPUsToCreate int32 `json:"gpusToCreate,omitempty"`
+
+	// Message summarizes the plan.
+	Message string `json:"message"`
+
+	// GeneratedTime is when the plan was last changed.
+	GeneratedTime metav1.Time `json:"generatedTime"`
+}
+
+// TuningStatus is the training progress of a tuning Workspace. The trainer logs it at
+// every logging step, and the controller refreshes it while the tuning Job runs.
+type TuningStatus struct {
+	// Epoch is the current epoch, with the fraction of it completed, e.g. "1.25".
+	// +optional
+	Epoch string `json:"epoch,omitempty"`
+
+	// Step is the number of optimizer steps completed.
+	// +optional
+	Step int64 `json:"step,omitempty"`
+
+	// TotalSteps is the number of optimizer steps of the whole run.
+	// +optional
+	TotalSteps int64 `json:"totalSteps,omitempty"`
+
+	// Loss is the last training loss, formatted as a string.
+	// +optional
+	Loss string `json:"loss,omitempty"`
+
+	// EstimatedCompletionTime is when the training is expected to finish at the pace so far.
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+
+	// GPUUtilization is the average utilization of the GPUs of the trainer, in percent.
+	// +optional
+	GPUUtilization *int32 `json:"gpuUtilization,omitempty"`
+
+	// LastUpdateTime is when the trainer reported this progress.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// LineageStatus records the inputs and the output of the tuning run of a Workspace
+// revision, so that every deployed adapter can be traced back to the data it was
+// trained on. The digests are "sha256:" followed by 64 hex characters.
+type LineageStatus struct {
+	// Revision is the workspace revision whose tuning Job produced the output.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// DatasetDigest is the digest of the dataset file the adapter was trained on.
+	// +optional
+	DatasetDigest string `json:"datasetDigest,omitempty"`
+
+	// BaseModel is the version of the tuned preset model, e.g. the HuggingFace commit URL.
+	// +optional
+	BaseModel string `json:"baseModel,omitempty"`
+
+	// ParametersDigest is the digest of the tuning configuration.
+	// +optional
+	ParametersDigest string `json:"parametersDigest,omitempty"`
+
+	// OutputDigest is the digest of the files of the output adapter.
+	// +optional
+	OutputDigest string `json:"outputDigest,omitempty"`
+
+	// RecordedTime is when the trainer reported the digests.
+	// +optional
+	RecordedTime *metav1.Time `json:"recordedTime,omitempty"`
 }
 
 // Workspace is the Schema for the workspaces API
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=workspaces,scope=Namespaced,categories=workspace,shortName={wk,wks}
@@ -281,6 +1593,8 @@ type WorkspaceStatus struct {
 // +kubebuilder:printcolumn:name="JobStarted",type="string",JSONPath=".status.conditions[?(@.type==\"JobStarted\")].status",description=""
 // +kubebuilder:printcolumn:name="WorkspaceSucceeded",type="string",JSONPath=".status.conditions[?(@.type==\"WorkspaceSucceeded\")].status",description=""
 // +kubebuilder:printcolumn:name="TargetNodeCount",type="integer",JSONPath=".status.targetNodeCount",description=""
+// +kubebuilder:printcolumn:name="Step",type="integer",JSONPath=".status.tuning.step",description="",priority=1
+// +kubebuilder:printcolumn:name="Loss",type="string",JSONPath=".status.tuning.loss",description="",priority=1
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description=""
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 type Workspace struct {
@@ -291,6 +1605,25 @@ type Workspace struct {
 	Inference *InferenceSpec  `json:"inference,omitempty"`
 	Tuning    *TuningSpec     `json:"tuning,omitempty"`
 	Status    WorkspaceStatus `json:"status,omitempty"`
+
+	// Identity lets the workspace pods access private storage with a cloud workload identity.
+	// +optional
+	Identity *WorkloadIdentitySpec `json:"identity,omitempty"`
+	// ServiceAccount selects the ServiceAccount the workspace pods run as and the API
+	// permissions of the generated one.
+	// +optional
+	ServiceAccount *ServiceAccountSpec `json:"serviceAccount,omitempty"`
+	// MaintenanceWindow restricts disruptive operations on the workspace, such as rolling out
+	// a new revision of the inference workload or replacing its nodes, to the given days and
+	// hours. Operations that come up outside the window are deferred until it opens and are
+	// listed in status.pendingOperations. Operations run at any time when it is not set.
+	// +optional
+	MaintenanceWindow *WorkspaceMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// MetadataPropagation copies labels and annotations of the workspace, such as
+	// cost-allocation or service mesh labels, to the pods, NodeClaims and Services generated
+	// for it.
+	// +optional
+	MetadataPropagation *MetadataPropagationSpec `json:"metadataPropagation,omitempty"`
 }
 
 // WorkspaceList contains a list of Workspace
@@ -16,10 +16,17 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/distribution/reference"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -54,6 +61,8 @@ const (
 	DefaultQloraConfigMapTemplate  = "qlora-params-template"
 	DefaultInferenceConfigTemplate = "inference-params-template"
 	MaxAdaptersNumber              = 10
+
+	maxTerminationGracePeriodSeconds = 3600
 )
 
 func (w *Workspace) SupportedVerbs() []admissionregistrationv1.OperationType {
@@ -74,6 +83,16 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 		klog.InfoS("Validate creation", "workspace", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
 		errs = errs.Also(w.validateCreate().ViaField("spec"))
 		errs = errs.Also(w.validateAnnotations())
+		errs = errs.Also(w.validateRuntimeChannelAnnotation())
+		errs = errs.Also(w.validateAdoptWorkloadAnnotation())
+		errs = errs.Also(w.validateCloneAnnotation())
+		errs = errs.Also(w.validateGangSchedulerAnnotations())
+		errs = errs.Also(w.validateTierAnnotations())
+		errs = errs.Also(w.validateNodeClaimNamingAnnotation())
+		errs = errs.Also(w.Identity.validate().ViaField("identity"))
+		errs = errs.Also(w.validateServiceAccount().ViaField("serviceAccount"))
+		errs = errs.Also(w.MaintenanceWindow.Validate().ViaField("maintenanceWindow"))
+		errs = errs.Also(w.MetadataPropagation.validate().ViaField("metadataPropagation"))
 		if w.Inference != nil {
 			// Check if the bypass resource checks annotation is set
 			bypassResourceChecks := false
@@ -89,6 +108,8 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 				w.Resource.validateCreateWithInference(ctx, w.Inference, bypassResourceChecks, runtime, w.Namespace).ViaField("resource"),
 				w.Inference.validateCreate(ctx, runtime, w.Namespace).ViaField("inference"),
 				w.validateInferenceConfig(ctx),
+				w.validateInferenceSidecars(),
+				w.validateLazyPull(),
 			)
 			if featuregates.FeatureGates[consts.FeatureFlagModelStreaming] {
 				errs = errs.Also(w.validateStreamingCSIDriver(ctx))
@@ -97,7 +118,9 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 		if w.Tuning != nil {
 			// TODO: Add validate resource based on Tuning Spec
 			errs = errs.Also(w.Resource.validateCreateWithTuning(w.Tuning).ViaField("resource"),
-				w.Tuning.validateCreate(ctx, w.Namespace).ViaField("tuning"))
+				w.Tuning.validateCreate(ctx, w.Namespace).ViaField("tuning"),
+				w.Tuning.Deploy.validate(w.Name).ViaField("tuning.deploy"),
+				w.Tuning.Retention.validate().ViaField("tuning.retention"))
 		}
 	} else {
 		klog.InfoS("Validate update", "workspace", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
@@ -105,20 +128,127 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(
 			w.validateUpdate(old).ViaField("spec"),
 			w.Resource.validateUpdate(&old.Resource).ViaField("resource"),
+			w.validateRuntimeChannelAnnotation(),
+			w.validateAdoptWorkloadAnnotation(),
+			w.validateCloneAnnotation(),
+			w.validateGangSchedulerAnnotationsImmutable(old),
+			w.validateTierAnnotations(),
+			w.validateNodeClaimNamingAnnotation(),
+			w.validateStorage().ViaField("spec.resource.storage"),
+			w.validateCompute().ViaField("spec.resource.compute"),
+			w.Resource.Placement.validate().ViaField("spec.resource.placement"),
+			w.Identity.validate().ViaField("identity"),
+			w.validateServiceAccount().ViaField("serviceAccount"),
+			w.MaintenanceWindow.Validate().ViaField("maintenanceWindow"),
+			w.MetadataPropagation.validate().ViaField("metadataPropagation"),
 		)
 		if featuregates.FeatureGates[consts.FeatureFlagModelStreaming] {
 			errs = errs.Also(w.validateModelStreamingAnnotationImmutable(old))
 		}
 		if w.Inference != nil {
-			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"))
+			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"), w.validateInferenceSidecars(), w.validateLazyPull(),
+				w.Inference.validateAcceptLicenseUpdate(ctx, old.Inference, w.Namespace).ViaField("inference"))
 		}
 		if w.Tuning != nil {
-			errs = errs.Also(w.Tuning.validateUpdate(old.Tuning).ViaField("tuning"))
+			errs = errs.Also(w.Tuning.validateUpdate(old.Tuning).ViaField("tuning"),
+				w.Tuning.Deploy.validate(w.Name).ViaField("tuning.deploy"),
+				w.Tuning.Retention.validate().ViaField("tuning.retention"))
+		}
+	}
+	return errs
+}
+
+// validateInferenceSidecars rejects the response cache and the API normalizer on prefill
+// and decode workspaces, where the routing sidecar already fronts the inference server.
+// Only one sidecar can take the inference port, so the two cannot be combined either.
+func (w *Workspace) validateInferenceSidecars() (errs *apis.FieldError) {
+	if w.Inference == nil {
+		return nil
+	}
+	_, hasRole := w.Labels[LabelInferenceRole]
+	if w.Inference.ResponseCache != nil && hasRole {
+		errs = errs.Also(apis.ErrGeneric("the response cache is not supported for prefill and decode workspaces", "spec.inference.responseCache"))
+	}
+	if w.Inference.APINormalization != nil {
+		if hasRole {
+			errs = errs.Also(apis.ErrGeneric("API normalization is not supported for prefill and decode workspaces", "spec.inference.apiNormalization"))
+		}
+		if w.Inference.ResponseCache != nil {
+			errs = errs.Also(apis.ErrGeneric("API normalization cannot be combined with the response cache",
+				"spec.inference.apiNormalization", "spec.inference.responseCache"))
 		}
 	}
 	return errs
 }
 
+// maxIndexedNodeClaimNameLength leaves room for "-<namespace-hash>-<index>" when NodeClaims
+// are named after the Workspace, so that the names stay valid label values.
+const maxIndexedNodeClaimNameLength = 52
+
+// validateNodeClaimNamingAnnotation is checked on both create and update. A new strategy
+// only applies to NodeClaims created afterwards.
+func (w *Workspace) validateNodeClaimNamingAnnotation() (errs *apis.FieldError) {
+	naming, ok := w.GetAnnotations()[AnnotationNodeClaimNaming]
+	if !ok {
+		return nil
+	}
+	field := fmt.Sprintf("metadata.annotations[%s]", AnnotationNodeClaimNaming)
+	switch naming {
+	case NodeClaimNamingRandom:
+		return nil
+	case NodeClaimNamingWorkspaceIndex:
+	default:
+		return apis.ErrInvalidValue(fmt.Sprintf("%q is not a valid NodeClaim naming strategy; choose one of: %s, %s",
+			naming, NodeClaimNamingRandom, NodeClaimNamingWorkspaceIndex), field)
+	}
+	// The Azure gpu-provisioner turns each NodeClaim into an agent pool, whose name is at
+	// most 12 lowercase alphanumeric characters.
+	if os.Getenv("CLOUD_PROVIDER") == consts.AzureCloudName {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s is not supported on Azure, where NodeClaim names become agent pool names", NodeClaimNamingWorkspaceIndex), field))
+	}
+	if len(w.Name) > maxIndexedNodeClaimNameLength {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s requires a workspace name of at most %d characters", NodeClaimNamingWorkspaceIndex, maxIndexedNodeClaimNameLength), field))
+	}
+	return errs
+}
+
+// validateTierAnnotations is checked on both create and update. The annotations are set by
+// the MultiRoleInference controller on tiered workspaces and become tier router arguments.
+func (w *Workspace) validateTierAnnotations() (errs *apis.FieldError) {
+	annotations := w.GetAnnotations()
+	limit, hasLimit := annotations[AnnotationTierMaxPromptTokens]
+	service, hasService := annotations[AnnotationTierOverflowService]
+	if !hasLimit && !hasService {
+		return nil
+	}
+	limitField := fmt.Sprintf("metadata.annotations[%s]", AnnotationTierMaxPromptTokens)
+	serviceField := fmt.Sprintf("metadata.annotations[%s]", AnnotationTierOverflowService)
+	if hasLimit != hasService {
+		return apis.ErrGeneric(fmt.Sprintf("%s and %s must be set together", AnnotationTierMaxPromptTokens, AnnotationTierOverflowService),
+			limitField, serviceField)
+	}
+	if n, err := strconv.ParseInt(limit, 10, 32); err != nil || n < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(limit, limitField, "must be a positive integer"))
+	}
+	if msgs := validation.IsDNS1035Label(service); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), serviceField))
+	}
+	switch {
+	case w.Inference == nil || w.Inference.Preset == nil:
+		errs = errs.Also(apis.ErrGeneric("tier routing requires a preset inference workspace", limitField))
+	case GetWorkspaceRuntimeName(w) != model.RuntimeNameVLLM:
+		errs = errs.Also(apis.ErrGeneric("tier routing is only supported with the vLLM runtime", limitField))
+	case w.Inference.ResponseCache != nil:
+		errs = errs.Also(apis.ErrGeneric("tier routing cannot be combined with the response cache", limitField))
+	case w.Inference.APINormalization != nil:
+		errs = errs.Also(apis.ErrGeneric("tier routing cannot be combined with API normalization", limitField))
+	}
+	if _, ok := w.Labels[LabelInferenceRole]; ok {
+		errs = errs.Also(apis.ErrGeneric("tier routing is not supported for prefill and decode workspaces", limitField))
+	}
+	return errs
+}
+
 func (w *Workspace) validateAnnotations() (errs *apis.FieldError) {
 	annotations := w.GetAnnotations()
 	if annotations == nil {
@@ -138,6 +268,136 @@ func (w *Workspace) validateAnnotations() (errs *apis.FieldError) {
 	return errs
 }
 
+func (w *Workspace) validateGangSchedulerAnnotations() (errs *apis.FieldError) {
+	annotations := w.GetAnnotations()
+	scheduler, ok := annotations[AnnotationGangScheduler]
+	if !ok {
+		if _, ok := annotations[AnnotationSchedulerName]; ok {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s requires %s", AnnotationSchedulerName, AnnotationGangScheduler),
+				fmt.Sprintf("metadata.annotations[%s]", AnnotationSchedulerName)))
+		}
+		return errs
+	}
+	switch scheduler {
+	case GangSchedulerCoscheduling, GangSchedulerVolcano:
+		// valid
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("%q is not a valid gang scheduler; choose one of: coscheduling, volcano", scheduler),
+			fmt.Sprintf("metadata.annotations[%s]", AnnotationGangScheduler),
+		))
+	}
+	if name, ok := annotations[AnnotationSchedulerName]; ok {
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "),
+				fmt.Sprintf("metadata.annotations[%s]", AnnotationSchedulerName)))
+		}
+	}
+	if w.Inference != nil && w.Inference.Template != nil {
+		errs = errs.Also(apis.ErrGeneric("gang scheduling is not supported with a custom inference template",
+			fmt.Sprintf("metadata.annotations[%s]", AnnotationGangScheduler)))
+	}
+	return errs
+}
+
+// validateGangSchedulerAnnotationsImmutable rejects changes to the gang scheduling
+// annotations, since running pods cannot move to another scheduler or PodGroup.
+func (w *Workspace) validateGangSchedulerAnnotationsImmutable(old *Workspace) (errs *apis.FieldError) {
+	for _, key := range []string{AnnotationGangScheduler, AnnotationSchedulerName} {
+		if w.GetAnnotations()[key] != old.GetAnnotations()[key] {
+			errs = errs.Also(apis.ErrGeneric("field is immutable", fmt.Sprintf("metadata.annotations[%s]", key)))
+		}
+	}
+	return errs
+}
+
+// validateRuntimeChannelAnnotation is checked on both create and update because the
+// channel may be switched at any time to speed up, slow down or freeze upgrades.
+func (w *Workspace) validateRuntimeChannelAnnotation() (errs *apis.FieldError) {
+	v, ok := w.GetAnnotations()[AnnotationRuntimeChannel]
+	if !ok {
+		return nil
+	}
+	switch v {
+	case RuntimeChannelRapid, RuntimeChannelStable, RuntimeChannelPinned:
+		// valid
+	default:
+		if _, ok := GetPinnedRuntimeDigest(w); ok {
+			return nil
+		}
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("%q is not a valid runtime channel; choose one of: rapid, stable, pinned, pinned@sha256:<digest>", v),
+			fmt.Sprintf("metadata.annotations[%s]", AnnotationRuntimeChannel),
+		))
+	}
+	return errs
+}
+
+// validateAdoptWorkloadAnnotation is checked on both create and update so an existing
+// Workspace can adopt a workload that was deployed after it.
+func (w *Workspace) validateAdoptWorkloadAnnotation() (errs *apis.FieldError) {
+	value, ok := w.GetAnnotations()[AnnotationAdoptWorkload]
+	if !ok {
+		return nil
+	}
+	field := fmt.Sprintf("metadata.annotations[%s]", AnnotationAdoptWorkload)
+	kind, name, ok := GetAdoptedWorkload(w)
+	if !ok {
+		return apis.ErrInvalidValue(fmt.Sprintf("%q must be StatefulSet/<name> or Deployment/<name>", value), field)
+	}
+	if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), field))
+	}
+	if kind == AdoptWorkloadKindStatefulSet && name != w.Name {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("an adopted StatefulSet must be named after the workspace %q", w.Name), field))
+	}
+	if w.Inference == nil || w.Inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("workload adoption is only supported for preset inference workspaces", field))
+	}
+	return errs
+}
+
+// validateCloneAnnotation is checked on both create and update, so that a malformed clone
+// request is rejected before the controller picks it up.
+func (w *Workspace) validateCloneAnnotation() (errs *apis.FieldError) {
+	req, ok, err := GetCloneRequest(w)
+	if !ok {
+		return nil
+	}
+	field := fmt.Sprintf("metadata.annotations[%s]", AnnotationCloneTo)
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), field)
+	}
+	if msgs := validation.IsDNS1123Label(req.Name); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("name: %s", strings.Join(msgs, ", ")), field))
+	}
+	if req.Namespace != "" {
+		if msgs := validation.IsDNS1123Label(req.Namespace); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("namespace: %s", strings.Join(msgs, ", ")), field))
+		}
+	}
+	if req.Name == w.Name && (req.Namespace == "" || req.Namespace == w.Namespace) {
+		errs = errs.Also(apis.ErrInvalidValue("a workspace cannot be cloned onto itself", field))
+	}
+	if req.InstanceType != "" && len(req.InstanceType) > 253 {
+		errs = errs.Also(apis.ErrInvalidValue("instanceType must be at most 253 characters", field))
+	}
+	if req.Adapters != nil {
+		adapters := make([]AdapterSpec, len(*req.Adapters))
+		for i := range *req.Adapters {
+			(*req.Adapters)[i].DeepCopyInto(&adapters[i])
+			errs = errs.Also(adapters[i].validateCreateorUpdate().ViaField(field))
+		}
+		if !slices.ContainsFunc(adapters, func(a AdapterSpec) bool { return a.Source == nil }) {
+			errs = errs.Also(validateDuplicateName(adapters, map[string]bool{}).ViaField(field))
+		}
+	}
+	if w.Inference == nil {
+		errs = errs.Also(apis.ErrGeneric("only inference workspaces can be cloned", field))
+	}
+	return errs
+}
+
 func (w *Workspace) validateCreate() (errs *apis.FieldError) {
 	if w.Inference == nil && w.Tuning == nil {
 		errs = errs.Also(apis.ErrGeneric("Either Inference or Tuning must be specified, not neither", ""))
@@ -148,7 +408,7 @@ func (w *Workspace) validateCreate() (errs *apis.FieldError) {
 
 	// Check node auto-provisioning feature gate and validate instanceType accordingly
 	// This validation only applies to CREATE operations, not UPDATE (since instanceType is immutable)
-	if featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+	if w.Resource.nodeAutoProvisioningDisabled() {
 		// When NAP is disabled, instanceType must be empty (BYO scenario)
 		if w.Resource.InstanceType != "" {
 			errs = errs.Also(apis.ErrInvalidValue("instanceType must be empty when node auto-provisioning is disabled (BYO scenario)", "resource.instanceType"))
@@ -165,9 +425,47 @@ func (w *Workspace) validateCreate() (errs *apis.FieldError) {
 		errs = errs.Also(errmsgs)
 	}
 
+	errs = errs.Also(w.Resource.validateProvisioningTimeout().ViaField("resource"))
+	errs = errs.Also(w.Resource.validateZones().ViaField("resource"))
+	errs = errs.Also(w.Resource.validateConfidentialCompute().ViaField("resource"))
+	// Template pods are applied as written, so KAITO cannot place them in a confidential runtime.
+	if w.Resource.ConfidentialCompute != nil && w.Inference != nil && w.Inference.Template != nil {
+		errs = errs.Also(apis.ErrGeneric("confidentialCompute is not supported with inference.template", "resource.confidentialCompute", "inference.template"))
+	}
+	errs = errs.Also(w.Resource.Placement.validate().ViaField("resource.placement"))
+	errs = errs.Also(w.validateStorage().ViaField("resource.storage"))
+	errs = errs.Also(w.validateCompute().ViaField("resource.compute"))
+
+	// Replicas of an existing InferenceSet keep the preset it was created with.
+	if _, ok := w.Labels[consts.WorkspaceCreatedByInferenceSetLabel]; !ok {
+		if w.Inference != nil && w.Inference.Preset != nil {
+			errs = errs.Also(ValidatePresetEndOfLife(string(w.Inference.Preset.Name)).ViaField("inference.preset"))
+		}
+		if w.Tuning != nil && w.Tuning.Preset != nil {
+			errs = errs.Also(ValidatePresetEndOfLife(string(w.Tuning.Preset.Name)).ViaField("tuning.preset"))
+		}
+	}
+
 	return errs
 }
 
+// ValidatePresetEndOfLife rejects a preset that reached the end of life of its deprecation.
+// It only applies to new workspaces, existing ones keep running.
+func ValidatePresetEndOfLife(name string) *apis.FieldError {
+	m, deprecated := metadata.DeprecatedPreset(name)
+	if !deprecated {
+		return nil
+	}
+	return validateEndOfLife(m, name, time.Now())
+}
+
+func validateEndOfLife(m model.Metadata, name string, now time.Time) *apis.FieldError {
+	if !m.EndOfLife(now) {
+		return nil
+	}
+	return apis.ErrInvalidValue(name, "name", m.DeprecationMessage(name, now))
+}
+
 func (w *Workspace) validateNodeImageFamilyAnnotation() (errs *apis.FieldError) {
 	if w.GetAnnotations() == nil {
 		return nil
@@ -273,6 +571,48 @@ func (r *TuningSpec) validateCreate(ctx context.Context, workspaceNamespace stri
 		errs = errs.Also(apis.ErrMissingField("Preset"))
 	} else if presetName := string(r.Preset.Name); !plugin.IsValidPreset(presetName) {
 		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported tuning preset name %s", presetName), "presetName"))
+	} else {
+		errs = errs.Also(r.Preset.Proxy.validate().ViaField("Preset.presetOptions.proxy"))
+	}
+	return errs
+}
+
+// validate checks the deployment of the tuned adapter of the tuning Workspace named
+// tuningWorkspace. A nil spec is valid.
+func (d *TuningDeploySpec) validate(tuningWorkspace string) (errs *apis.FieldError) {
+	if d == nil {
+		return nil
+	}
+	if d.Workspace == "" {
+		errs = errs.Also(apis.ErrMissingField("workspace"))
+	} else if msgs := validation.IsDNS1123Label(d.Workspace); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "workspace"))
+	} else if d.Workspace == tuningWorkspace {
+		errs = errs.Also(apis.ErrInvalidValue("must name another Workspace", "workspace"))
+	}
+	if d.AdapterName != "" {
+		if msgs := validation.IsDNS1123Subdomain(d.AdapterName); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "adapterName"))
+		}
+	}
+	if d.Strength != nil {
+		if strength, err := strconv.ParseFloat(*d.Strength, 64); err != nil || strength < 0 || strength > 1 {
+			errs = errs.Also(apis.ErrInvalidValue("must be a number between 0 and 1", "strength"))
+		}
+	}
+	return errs
+}
+
+// validate checks the retention of tuning runs. A nil spec is valid.
+func (r *TuningRetentionSpec) validate() (errs *apis.FieldError) {
+	if r == nil {
+		return nil
+	}
+	if r.TTLSecondsAfterFinished != nil && *r.TTLSecondsAfterFinished < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("must not be negative", "ttlSecondsAfterFinished"))
+	}
+	if r.KeepLastN != nil && *r.KeepLastN < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("must not be negative", "keepLastN"))
 	}
 	return errs
 }
@@ -449,7 +789,7 @@ func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inferenc
 		return errs
 	}
 
-	napDisabled := featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning]
+	napDisabled := r.nodeAutoProvisioningDisabled()
 
 	if napDisabled {
 		// MIG uses a single non-shardable slice, so the node-label/multi-node GPU
@@ -466,7 +806,7 @@ func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inferenc
 
 		if presetName != "" { // If the user is using a custom pod template instead of a preset, we don't need to list the BYO nodes to get GPU info as we don't know the GPU requirements of a custom model.
 			// Note: for tests like aikit.yaml, it creates nodes with kind that do not have GPU labels, so we need to account for that case.
-			kClient := k8sclient.GetGlobalClient()
+			kClient := k8sclient.FromContext(ctx)
 
 			// List matching nodes (KAITO-reserved label keys are stripped to avoid
 			// matching nodes that belong to other Workspaces or RAGEngines).
@@ -510,6 +850,10 @@ func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inferenc
 						errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Non-uniform GPU memory: node %s has %s memory, but previous node has %s memory", node.Name, gpuConfig.GPUMem.String(), skuConfig.GPUMem.String())))
 						return errs
 					}
+					if gpuConfig.Architecture() != skuConfig.Architecture() {
+						errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Non-uniform CPU architecture: node %s is %s, but previous node is %s", node.Name, gpuConfig.Architecture(), skuConfig.Architecture())))
+						return errs
+					}
 				}
 			}
 
@@ -534,21 +878,33 @@ func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inferenc
 		skuConfig = skuHandler.GetGPUConfigBySKU(instanceType)
 
 		if skuConfig == nil {
-			provider := os.Getenv("CLOUD_PROVIDER")
-			// Check for other instance types pattern matches if cloud provider is Azure
-			if provider != consts.AzureCloudName || !sku.HasSKUNamePrefix(instanceType, N_SERIES_PREFIX, D_SERIES_PREFIX) {
-				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), "instanceType"))
-			}
+			errs = errs.Also(unsupportedInstanceTypeError(skuHandler, instanceType).ViaField("instanceType"))
+		}
+	}
+
+	// Preset inference runs the KAITO base image, which may not be published for the
+	// architecture of the nodes, e.g. arm64 for Grace-based GH200 and GB200 nodes.
+	if presetName != "" && skuConfig != nil {
+		if base := metadata.MustGet("base"); !base.SupportsArchitecture(skuConfig.Architecture()) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("the KAITO base image is not published for %s nodes; supported architectures: %s",
+				skuConfig.Architecture(), strings.Join(base.ImagePlatforms(), ", ")), "instanceType"))
+			return errs
 		}
 	}
 
 	if presetName != "" && skuConfig != nil {
+		modelPreset, err := models.GetModelByName(ctx, presetName, secretName, wsNamespace, k8sclient.FromContext(ctx)) // InferenceSpec has been validated so the name is valid.
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("failed to get model preset: %v", err), "preset"))
+			return errs
+		}
+
+		// Count is only honored as a node count when NAP is enabled; BYO placement is driven by the label selector.
+		if !napDisabled {
+			errs = errs.Also(r.validateCountParallelism(presetName, modelPreset, skuConfig, runtime, bypassResourceChecks))
+		}
+
 		if napDisabled || (runtime != model.RuntimeNameVLLM && !napDisabled) {
-			modelPreset, err := models.GetModelByName(context.TODO(), presetName, secretName, wsNamespace, k8sclient.Client) // InferenceSpec has been validated so the name is valid.
-			if err != nil {
-				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("failed to get model preset: %v", err), "preset"))
-				return errs
-			}
 			params := modelPreset.GetInferenceParameters()
 
 			machineTotalGPUMem := resource.NewQuantity(int64(machineCount)*skuConfig.GPUMem.Value(), resource.BinarySI) // Total GPU memory
@@ -599,6 +955,68 @@ func (r *ResourceSpec) validateCreateWithInference(ctx context.Context, inferenc
 	return errs
 }
 
+// validateCountParallelism cross-checks the requested node count against the range of
+// nodes a preset can be sharded across on the given SKU. The lower bound is the number
+// of nodes needed to hold the raw model weights; the upper bound is 1 for presets that
+// do not support distributed inference. Both are only logged when resource checks are
+// bypassed.
+func (r *ResourceSpec) validateCountParallelism(presetName string, modelPreset model.Model, skuConfig *sku.GPUConfig, runtime model.RuntimeName, bypassResourceChecks bool) (errs *apis.FieldError) {
+	count := 1
+	if r.Count != nil {
+		count = *r.Count
+	}
+	distributed := modelPreset.SupportDistributedInference()
+	if count > 1 && !distributed {
+		if bypassResourceChecks {
+			klog.Warningf("Bypassing resource check: preset %s does not support distributed inference, but count is %d", presetName, count)
+			return errs
+		}
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("preset %s does not support distributed inference and must run on a single node, but count is %d; set count to 1", presetName, count),
+			"count"))
+		return errs
+	}
+
+	params := modelPreset.GetInferenceParameters()
+	if params == nil || params.TotalSafeTensorFileSize == "" || skuConfig.GPUMem.IsZero() {
+		return errs
+	}
+	modelSize, err := resource.ParseQuantity(params.TotalSafeTensorFileSize)
+	if err != nil {
+		// Reported by the GPU memory check in validateCreateWithInference.
+		return errs
+	}
+	minNodes := int((modelSize.Value() + skuConfig.GPUMem.Value() - 1) / skuConfig.GPUMem.Value())
+	if minNodes <= 1 {
+		return errs
+	}
+
+	if !distributed {
+		// The Transformers runtime already reports this as insufficient GPU memory.
+		if runtime == model.RuntimeNameVLLM && !bypassResourceChecks {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("preset %s requires %s of GPU memory, which needs at least %d nodes of instance type %s, but the preset does not support distributed inference; choose an instance type with at least %s of GPU memory",
+					presetName, modelSize.String(), minNodes, r.InstanceType, modelSize.String()),
+				"instanceType"))
+		}
+		return errs
+	}
+
+	// The Transformers runtime already reports this as insufficient total GPU memory.
+	if count < minNodes && runtime == model.RuntimeNameVLLM {
+		if bypassResourceChecks {
+			klog.Warningf("Bypassing resource check: preset %s needs at least %d nodes of instance type %s, but count is %d",
+				presetName, minNodes, r.InstanceType, count)
+			return errs
+		}
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("preset %s requires %s of GPU memory, which needs at least %d nodes of instance type %s, but count is %d; set count to at least %d",
+				presetName, modelSize.String(), minNodes, r.InstanceType, count, minNodes),
+			"count"))
+	}
+	return errs
+}
+
 // validatePartition validates the GPU partitioning configuration for an inference
 // workload and dispatches on the partition mode. Callers should return early after
 // invoking this helper because partitioned workloads use a different resource type
@@ -640,7 +1058,7 @@ func (r *ResourceSpec) validateMIGModelFit(ctx context.Context, presetName, secr
 	if err != nil {
 		return apis.ErrInvalidValue(err.Error(), "partition.profile")
 	}
-	modelPreset, err := models.GetModelByName(ctx, presetName, secretName, wsNamespace, k8sclient.Client)
+	modelPreset, err := models.GetModelByName(ctx, presetName, secretName, wsNamespace, k8sclient.FromContext(ctx))
 	if err != nil {
 		return apis.ErrInvalidValue(fmt.Sprintf("failed to get model preset: %v", err), "preset")
 	}
@@ -668,6 +1086,216 @@ func (r *ResourceSpec) validateMIGModelFit(ctx context.Context, presetName, secr
 	return errs
 }
 
+// validateStorage runs on both create and update so the sizes can be raised after the
+// workspace reports DiskTooSmall.
+func (w *Workspace) validateStorage() (errs *apis.FieldError) {
+	storage := w.Resource.Storage
+	if storage == nil {
+		return nil
+	}
+	if storage.NodeDiskSize != nil {
+		if storage.NodeDiskSize.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue("nodeDiskSize must be positive", "nodeDiskSize"))
+		}
+		if w.Resource.nodeAutoProvisioningDisabled() {
+			errs = errs.Also(apis.ErrGeneric("nodeDiskSize is not supported when node auto-provisioning is disabled", "nodeDiskSize"))
+		}
+	}
+	if storage.EphemeralStorage != nil {
+		if storage.EphemeralStorage.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue("ephemeralStorage must be positive", "ephemeralStorage"))
+		}
+		if w.Inference == nil || w.Inference.Preset == nil {
+			errs = errs.Also(apis.ErrGeneric("ephemeralStorage is only supported for preset inference", "ephemeralStorage"))
+		}
+	}
+	return errs
+}
+
+// validateCompute runs on both create and update so the requests can be resized while
+// the workspace is serving.
+func (w *Workspace) validateCompute() (errs *apis.FieldError) {
+	compute := w.Resource.Compute
+	if compute == nil {
+		return nil
+	}
+	if compute.CPU.Sign() <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue("cpu must be positive", "cpu"))
+	}
+	if compute.Memory.Sign() <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue("memory must be positive", "memory"))
+	}
+	if w.Inference == nil || w.Inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("compute is only supported for preset inference", apis.CurrentField))
+	}
+	return errs
+}
+
+// validateProvisioningTimeout runs on both create and update since provisioningTimeout and
+// fallbackInstanceTypes may be tuned while a workspace is waiting for nodes.
+func (r *ResourceSpec) validateProvisioningTimeout() (errs *apis.FieldError) {
+	if r.ProvisioningTimeout != nil && r.ProvisioningTimeout.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue("provisioningTimeout must be a positive duration", "provisioningTimeout"))
+	}
+	if len(r.FallbackInstanceTypes) == 0 {
+		return errs
+	}
+	if r.ProvisioningTimeout == nil {
+		errs = errs.Also(apis.ErrGeneric("fallbackInstanceTypes requires provisioningTimeout to be set", "fallbackInstanceTypes"))
+	}
+	if r.nodeAutoProvisioningDisabled() {
+		return errs.Also(apis.ErrGeneric("fallbackInstanceTypes is not supported when node auto-provisioning is disabled", "fallbackInstanceTypes"))
+	}
+
+	skuHandler, err := sku.GetSKUHandler()
+	if err != nil {
+		return errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to get SKU handler: %v", err), "fallbackInstanceTypes"))
+	}
+	seen := make(map[string]bool, len(r.FallbackInstanceTypes))
+	for i, instanceType := range r.FallbackInstanceTypes {
+		if seen[instanceType] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("duplicate instance type %s", instanceType), apis.CurrentField).ViaFieldIndex("fallbackInstanceTypes", i))
+			continue
+		}
+		seen[instanceType] = true
+		errs = errs.Also(unsupportedInstanceTypeError(skuHandler, instanceType).ViaFieldIndex("fallbackInstanceTypes", i))
+	}
+	return errs
+}
+
+// unsupportedInstanceTypeError returns nil when instanceType is accepted by the SKU catalog
+// of the cloud provider, and otherwise an error naming its near-matches and, as details,
+// the SKUs the catalog supports.
+func unsupportedInstanceTypeError(skuHandler sku.CloudSKUHandler, instanceType string) *apis.FieldError {
+	err := sku.CheckInstanceType(os.Getenv("CLOUD_PROVIDER"), skuHandler, instanceType)
+	if err == nil {
+		return nil
+	}
+	supported := skuHandler.GetSupportedSKUs()
+	slices.Sort(supported)
+	return apis.ErrInvalidValue(err.Error(), apis.CurrentField, fmt.Sprintf("Supported SKUs: %s", strings.Join(supported, ", ")))
+}
+
+// nodeAutoProvisioningDisabled reports whether nodes must not be provisioned for the
+// workload, either because node auto-provisioning is disabled controller-wide or because
+// the workspace opted out through ProvisioningPolicy.
+func (r *ResourceSpec) nodeAutoProvisioningDisabled() bool {
+	return featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] || r.OptsOutOfProvisioning()
+}
+
+// validateZones runs on both create and update; zones may be widened while a workspace is
+// waiting for capacity.
+func (r *ResourceSpec) validateZones() (errs *apis.FieldError) {
+	if len(r.Zones) == 0 {
+		return nil
+	}
+	if r.nodeAutoProvisioningDisabled() {
+		return apis.ErrGeneric("zones is not supported when node auto-provisioning is disabled", "zones")
+	}
+	seen := make(map[string]bool, len(r.Zones))
+	for i, zone := range r.Zones {
+		if seen[zone] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("duplicate zone %s", zone), apis.CurrentField).ViaFieldIndex("zones", i))
+			continue
+		}
+		seen[zone] = true
+		if zone == "" {
+			errs = errs.Also(apis.ErrInvalidValue("zone must not be empty", apis.CurrentField).ViaFieldIndex("zones", i))
+		} else if errmsgs := validation.IsValidLabelValue(zone); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), apis.CurrentField).ViaFieldIndex("zones", i))
+		}
+	}
+	return errs
+}
+
+// validate runs on both create and update; placement may be changed on a running workload
+// and rolls its pods out with the new affinity.
+func (p *PlacementSpec) validate() (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	if len(p.AllowColocationWith) > 0 && p.Policy != PlacementPolicyDedicated {
+		errs = errs.Also(apis.ErrGeneric("allowColocationWith is only supported with the Dedicated policy", "allowColocationWith"))
+	}
+	for _, key := range slices.Sorted(maps.Keys(p.AllowColocationWith)) {
+		if errmsgs := validation.IsQualifiedName(key); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "allowColocationWith", errmsgs...))
+		}
+		if errmsgs := validation.IsValidLabelValue(p.AllowColocationWith[key]); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), apis.CurrentField).ViaKey(key).ViaField("allowColocationWith"))
+		}
+	}
+	return errs
+}
+
+// validateConfidentialCompute checks that confidential workloads only land on confidential
+// SKUs and that the attestation container is pinned, since it gates what the pod trusts.
+func (r *ResourceSpec) validateConfidentialCompute() (errs *apis.FieldError) {
+	cc := r.ConfidentialCompute
+	if cc == nil {
+		return nil
+	}
+	if r.Partition != nil {
+		errs = errs.Also(apis.ErrGeneric("confidentialCompute cannot be combined with partition", "confidentialCompute", "partition"))
+	}
+	if cc.RuntimeClassName != nil {
+		if errmsgs := validation.IsDNS1123Subdomain(*cc.RuntimeClassName); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "confidentialCompute.runtimeClassName"))
+		}
+	}
+	if a := cc.Attestation; a != nil {
+		if a.Image == "" {
+			errs = errs.Also(apis.ErrMissingField("confidentialCompute.attestation.image"))
+		} else if ref, err := reference.ParseNormalizedNamed(a.Image); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unable to parse attestation image reference: %s", err), "confidentialCompute.attestation.image"))
+		} else if !isPinnedImage(ref) {
+			errs = errs.Also(apis.ErrInvalidValue("attestation image must be pinned to a digest or to a tag other than latest", "confidentialCompute.attestation.image"))
+		}
+		u, err := url.Parse(a.Endpoint)
+		switch {
+		case a.Endpoint == "":
+			errs = errs.Also(apis.ErrMissingField("confidentialCompute.attestation.endpoint"))
+		case err != nil:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid attestation endpoint: %v", err), "confidentialCompute.attestation.endpoint"))
+		case u.Scheme != "https" || u.Hostname() == "":
+			errs = errs.Also(apis.ErrInvalidValue("attestation endpoint must be an https URL", "confidentialCompute.attestation.endpoint"))
+		case u.User != nil:
+			errs = errs.Also(apis.ErrInvalidValue("attestation endpoint must not contain credentials", "confidentialCompute.attestation.endpoint"))
+		}
+	}
+
+	// BYO nodes are matched by the confidential compute label at scheduling time.
+	if r.nodeAutoProvisioningDisabled() || r.InstanceType == "" {
+		return errs
+	}
+	skuHandler, err := sku.GetSKUHandler()
+	if err != nil {
+		return errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to get SKU handler: %v", err), "confidentialCompute"))
+	}
+	isConfidential := func(instanceType string) bool {
+		gpuConfig := skuHandler.GetGPUConfigBySKU(instanceType)
+		return gpuConfig != nil && gpuConfig.ConfidentialCompute
+	}
+	if !isConfidential(r.InstanceType) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("instance type %s does not support confidential compute", r.InstanceType), "instanceType"))
+	}
+	for i, instanceType := range r.FallbackInstanceTypes {
+		if !isConfidential(instanceType) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("instance type %s does not support confidential compute", instanceType), apis.CurrentField).ViaFieldIndex("fallbackInstanceTypes", i))
+		}
+	}
+	return errs
+}
+
+// isPinnedImage reports whether ref names a digest or an explicit tag other than latest.
+func isPinnedImage(ref reference.Named) bool {
+	if _, ok := ref.(reference.Digested); ok {
+		return true
+	}
+	tagged, ok := ref.(reference.Tagged)
+	return ok && tagged.Tag() != "latest"
+}
+
 func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError) {
 	// We disable changing node count for now.
 	if r.Count != nil && old.Count != nil && *r.Count != *old.Count {
@@ -679,8 +1307,19 @@ func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError)
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "partition"))
 	}
 
+	if r.ProvisioningPolicy != old.ProvisioningPolicy {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "provisioningPolicy"))
+	}
+
+	if !apiequality.Semantic.DeepEqual(r.ConfidentialCompute, old.ConfidentialCompute) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "confidentialCompute"))
+	}
+
+	errs = errs.Also(r.validateProvisioningTimeout())
+	errs = errs.Also(r.validateZones())
+
 	// Check node auto-provisioning feature gate and validate instanceType accordingly
-	if featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+	if r.nodeAutoProvisioningDisabled() {
 		// When NAP is disabled, instanceType must be empty (BYO scenario)
 		if old.InstanceType == "" {
 			if r.InstanceType != "" {
@@ -697,7 +1336,7 @@ func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError)
 		if r.InstanceType == "" {
 			errs = errs.Also(apis.ErrMissingField("instanceType is required when node auto-provisioning is enabled", "instanceType"))
 		} else if old.InstanceType != "" && old.InstanceType != r.InstanceType {
-			errs = errs.Also(apis.ErrGeneric("instanceType cannot be changed once set when node auto-provisioning is enabled", "instanceType"))
+			errs = errs.Also(apis.ErrGeneric("instanceType cannot be changed once set when node auto-provisioning is enabled; change the instance type of an InferenceSet template to migrate replicas without downtime", "instanceType"))
 		}
 	}
 
@@ -732,7 +1371,7 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, runtime model.Runtim
 			// Need to return here. Otherwise, a panic will be hit when doing following checks.
 			return errs
 		}
-		modelPreset, err := models.GetModelByName(ctx, string(i.Preset.Name), i.Preset.PresetOptions.ModelAccessSecret, wsNamespace, k8sclient.Client)
+		modelPreset, err := models.GetModelByName(ctx, string(i.Preset.Name), i.Preset.PresetOptions.ModelAccessSecret, wsNamespace, k8sclient.FromContext(ctx))
 		if err != nil {
 			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("failed to get model preset: %v", err), "preset"))
 			return errs
@@ -755,12 +1394,42 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, runtime model.Runtim
 		if err != nil {
 			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Runtime validation: %v", err)))
 		}
-		// For models that require downloading at runtime, we need to check if the modelAccessSecret is provided
-		if params.DownloadAtRuntime {
-			if params.DownloadAuthRequired && i.Preset.PresetOptions.ModelAccessSecret == "" {
-				errs = errs.Also(apis.ErrGeneric("This preset requires authentication and needs a modelAccessSecret with HF_TOKEN key under presetOptions to download the model"))
-			}
-		} else if i.Preset.PresetOptions.ModelAccessSecret != "" {
+		if i.ResponseCache != nil && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("the response cache is only supported by the vLLM runtime", "responseCache"))
+		}
+		if i.APINormalization != nil && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("API normalization is only supported by the vLLM runtime", "apiNormalization"))
+		}
+		if i.RuntimeOverrides != nil && len(i.RuntimeOverrides.ExtraArgs) > 0 && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("extra args are only supported by the vLLM runtime", "runtimeOverrides.extraArgs"))
+		}
+		if i.Tracing != nil && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("tracing is only supported by the vLLM runtime", "tracing"))
+		}
+		if i.Tokenizer != nil && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("the tokenizer endpoint is only supported by the vLLM runtime", "tokenizer"))
+		}
+		if i.StructuredOutputs != nil && runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("structured outputs are only supported by the vLLM runtime", "structuredOutputs"))
+		}
+		if tc := i.ToolCalling; tc != nil && !tc.Disabled {
+			if runtime != model.RuntimeNameVLLM {
+				errs = errs.Also(apis.ErrGeneric("tool calling is only supported by the vLLM runtime", "toolCalling"))
+			} else if _, ok := params.VLLM.ModelRunParams["tool-call-parser"]; !ok && tc.Parser == "" {
+				errs = errs.Also(apis.ErrMissingField("toolCalling.parser").Also(
+					apis.ErrGeneric(fmt.Sprintf("preset %s has no default tool call parser", presetName))))
+			}
+		}
+		errs = errs.Also(i.Preset.Proxy.validate().ViaField("preset.presetOptions.proxy"))
+		if license := params.GatedLicense; license != "" && i.Preset.PresetOptions.AcceptLicense != license {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("preset %s is gated by the %s license, accept it on HuggingFace and set acceptLicense to %q", presetName, license, license), "preset.presetOptions.acceptLicense"))
+		}
+		// For models that require downloading at runtime, we need to check if the modelAccessSecret is provided
+		if params.DownloadAtRuntime {
+			if params.DownloadAuthRequired && i.Preset.PresetOptions.ModelAccessSecret == "" {
+				errs = errs.Also(apis.ErrGeneric("This preset requires authentication and needs a modelAccessSecret with HF_TOKEN key under presetOptions to download the model"))
+			}
+		} else if i.Preset.PresetOptions.ModelAccessSecret != "" {
 			errs = errs.Also(apis.ErrGeneric("This preset does not require a modelAccessSecret with HF_TOKEN key under presetOptions"))
 		}
 	}
@@ -774,6 +1443,38 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, runtime model.Runtim
 		errs = errs.Also(validateDuplicateName(i.Adapters, nameMap))
 	}
 
+	errs = errs.Also(i.Logging.validate().ViaField("logging"))
+	errs = errs.Also(i.Service.validate().ViaField("service"))
+	errs = errs.Also(i.ToolCalling.validate(i.Template != nil).ViaField("toolCalling"))
+	errs = errs.Also(i.StructuredOutputs.validate(i.Template != nil).ViaField("structuredOutputs"))
+	errs = errs.Also(i.Shutdown.validate(i.Template != nil).ViaField("shutdown"))
+	errs = errs.Also(i.Probes.validate(i.Template != nil).ViaField("probes"))
+	errs = errs.Also(i.Distributed.validate(i.Template != nil).ViaField("distributed"))
+	errs = errs.Also(i.ResponseCache.validate(i.Template != nil).ViaField("responseCache"))
+	errs = errs.Also(i.APINormalization.validate(i.Template != nil).ViaField("apiNormalization"))
+	errs = errs.Also(i.Tracing.validate(i.Template != nil).ViaField("tracing"))
+	errs = errs.Also(i.Tokenizer.validate(i.Template != nil).ViaField("tokenizer"))
+	errs = errs.Also(i.ImagePull.validate(i.Template != nil).ViaField("imagePull"))
+	errs = errs.Also(i.RuntimeOverrides.validate(i.Template != nil).ViaField("runtimeOverrides"))
+
+	return errs
+}
+
+// validateAcceptLicenseUpdate checks acceptLicense against the license of a gated preset when
+// it is changed. Unchanged values are not checked, so gated workspaces created before
+// acceptLicense existed can still be updated.
+func (i *InferenceSpec) validateAcceptLicenseUpdate(ctx context.Context, old *InferenceSpec, wsNamespace string) (errs *apis.FieldError) {
+	if old == nil || i.Preset == nil || old.Preset == nil || i.Preset.PresetOptions.AcceptLicense == old.Preset.PresetOptions.AcceptLicense {
+		return errs
+	}
+	modelPreset, err := models.GetModelByName(ctx, string(i.Preset.Name), i.Preset.PresetOptions.ModelAccessSecret, wsNamespace, k8sclient.FromContext(ctx))
+	if err != nil {
+		// The preset is immutable and was validated on create.
+		return errs
+	}
+	if license := modelPreset.GetInferenceParameters().GatedLicense; license != "" && i.Preset.PresetOptions.AcceptLicense != license {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("preset %s is gated by the %s license, accept it on HuggingFace and set acceptLicense to %q", i.Preset.Name, license, license), "preset.presetOptions.acceptLicense"))
+	}
 	return errs
 }
 
@@ -783,13 +1484,31 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 		return errs
 	}
 
-	if !reflect.DeepEqual(i.Preset, old.Preset) {
+	// The proxy may be changed, e.g. to rotate the CA bundle or move to another proxy,
+	// and the license of a gated model may be accepted after creation.
+	if !reflect.DeepEqual(presetWithoutMutableOptions(i.Preset), presetWithoutMutableOptions(old.Preset)) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "preset"))
 	}
+	if i.Preset != nil {
+		errs = errs.Also(i.Preset.Proxy.validate().ViaField("preset.presetOptions.proxy"))
+	}
 	// inference.template can be changed, but cannot be set/unset.
 	if (i.Template != nil && old.Template == nil) || (i.Template == nil && old.Template != nil) {
 		errs = errs.Also(apis.ErrGeneric("field cannot be unset/set if it was set/unset", "template"))
 	}
+	// The API server does not allow changing the IP families of an existing Service.
+	if !slices.Equal(i.Service.ipFamilies(), old.Service.ipFamilies()) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "service.ipFamilies"))
+	}
+	// The API normalizer moves vLLM to another port, so it can be tuned but not set/unset.
+	if (i.APINormalization != nil) != (old.APINormalization != nil) {
+		errs = errs.Also(apis.ErrGeneric("field cannot be unset/set if it was set/unset", "apiNormalization"))
+	}
+	// Tracing turns on a vLLM flag, and the command of an existing workload is not updated.
+	// The settings are passed through env vars, so they can be tuned but not set/unset.
+	if (i.Tracing != nil) != (old.Tracing != nil) {
+		errs = errs.Also(apis.ErrGeneric("field cannot be unset/set if it was set/unset", "tracing"))
+	}
 
 	// check if adapter names are duplicate
 	for _, adapter := range i.Adapters {
@@ -802,6 +1521,647 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 		nameMap := make(map[string]bool)
 		errs = errs.Also(validateDuplicateName(i.Adapters, nameMap))
 	}
+
+	errs = errs.Also(i.Logging.validate().ViaField("logging"))
+	errs = errs.Also(i.Service.validate().ViaField("service"))
+	errs = errs.Also(i.ToolCalling.validate(i.Template != nil).ViaField("toolCalling"))
+	errs = errs.Also(i.StructuredOutputs.validate(i.Template != nil).ViaField("structuredOutputs"))
+	errs = errs.Also(i.Shutdown.validate(i.Template != nil).ViaField("shutdown"))
+	errs = errs.Also(i.Probes.validate(i.Template != nil).ViaField("probes"))
+	errs = errs.Also(i.Distributed.validate(i.Template != nil).ViaField("distributed"))
+	errs = errs.Also(i.ResponseCache.validate(i.Template != nil).ViaField("responseCache"))
+	errs = errs.Also(i.APINormalization.validate(i.Template != nil).ViaField("apiNormalization"))
+	errs = errs.Also(i.Tracing.validate(i.Template != nil).ViaField("tracing"))
+	errs = errs.Also(i.Tokenizer.validate(i.Template != nil).ViaField("tokenizer"))
+	errs = errs.Also(i.ImagePull.validate(i.Template != nil).ViaField("imagePull"))
+	errs = errs.Also(i.RuntimeOverrides.validate(i.Template != nil).ViaField("runtimeOverrides"))
+	return errs
+}
+
+// validate checks the logging configuration. A nil spec is valid.
+func (l *LoggingSpec) validate() (errs *apis.FieldError) {
+	if l == nil {
+		return nil
+	}
+	switch l.Format {
+	case "", LogFormatText, LogFormatJSON:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported log format %q, supported values are text, json", l.Format), "format"))
+	}
+	if l.Forwarder != nil {
+		if l.Forwarder.DestinationSecret == "" {
+			errs = errs.Also(apis.ErrMissingField("forwarder.destinationSecret"))
+		} else if errmsgs := validation.IsDNS1123Subdomain(l.Forwarder.DestinationSecret); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "forwarder.destinationSecret"))
+		}
+		if l.Forwarder.Image != "" {
+			if _, err := reference.ParseDockerRef(l.Forwarder.Image); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unable to parse forwarder image reference: %s", err), "forwarder.image"))
+			}
+		}
+	}
+	return errs
+}
+
+var (
+	// toolCallParserRegex matches the tool call parser names accepted by vLLM.
+	toolCallParserRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// chatTemplateRegex matches the file names of bundled chat templates.
+	chatTemplateRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*\.jinja$`)
+	// socketInterfaceRegex matches Linux network interface names.
+	socketInterfaceRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,15}$`)
+	// unloadPathRegex matches the HTTP paths accepted for the preStop unload call.
+	unloadPathRegex = regexp.MustCompile(`^/[A-Za-z0-9/_.~-]*(\?[A-Za-z0-9_.~=&-]*)?$`)
+)
+
+// validate checks the tool calling options. The values end up on the vLLM command
+// line, so they are restricted to plain names. A nil spec is valid.
+func (t *ToolCallingSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if t == nil {
+		return nil
+	}
+	if customTemplate {
+		errs = errs.Also(apis.ErrGeneric("toolCalling cannot be used with a custom inference template"))
+	}
+	if t.Parser != "" && (len(t.Parser) > 63 || !toolCallParserRegex.MatchString(t.Parser)) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid tool call parser %q", t.Parser), "parser"))
+	}
+	if t.ChatTemplate != "" && (len(t.ChatTemplate) > 253 || !chatTemplateRegex.MatchString(t.ChatTemplate)) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("chat template %q must be the file name of a .jinja template in %s", t.ChatTemplate, model.ChatTemplateDir), "chatTemplate"))
+	}
+	return errs
+}
+
+// validate checks the structured outputs options. A nil spec is valid.
+func (s *StructuredOutputsSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if s == nil {
+		return nil
+	}
+	if customTemplate {
+		errs = errs.Also(apis.ErrGeneric("structuredOutputs cannot be used with a custom inference template"))
+	}
+	switch s.Backend {
+	case "", StructuredOutputsBackendAuto, StructuredOutputsBackendXGrammar, StructuredOutputsBackendGuidance,
+		StructuredOutputsBackendOutlines, StructuredOutputsBackendLMFormatEnforcer:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported structured outputs backend %q, supported values are auto, xgrammar, guidance, outlines, lm-format-enforcer", s.Backend), "backend"))
+	}
+	return errs
+}
+
+// validate checks the shutdown settings. A nil spec is valid.
+func (s *ShutdownSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if s == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("shutdown settings are not supported with a custom inference template, set them in the template instead")
+	}
+	gracePeriod := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	if s.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *s.TerminationGracePeriodSeconds
+		if gracePeriod < 1 || gracePeriod > maxTerminationGracePeriodSeconds {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("must be between 1 and %d", maxTerminationGracePeriodSeconds), "terminationGracePeriodSeconds"))
+		}
+	}
+	if p := s.PreStop; p != nil {
+		if p.DrainSeconds < 0 || int64(p.DrainSeconds) >= gracePeriod {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("must be at least 0 and less than the termination grace period of %d seconds", gracePeriod), "preStop.drainSeconds"))
+		}
+		if p.UnloadPath != "" && !unloadPathRegex.MatchString(p.UnloadPath) {
+			errs = errs.Also(apis.ErrInvalidValue("must be an absolute HTTP path with an optional query", "preStop.unloadPath"))
+		}
+	}
+	return errs
+}
+
+const (
+	maxProbeSeconds          = 3600
+	maxProbeFailureThreshold = 10000
+)
+
+// validate checks the probe settings. A nil spec is valid.
+func (p *ProbesSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("probe settings are not supported with a custom inference template, set them in the template instead")
+	}
+	errs = errs.Also(p.Startup.validate().ViaField("startup"))
+	errs = errs.Also(p.Liveness.validate().ViaField("liveness"))
+	errs = errs.Also(p.Readiness.validate().ViaField("readiness"))
+	return errs
+}
+
+// validate checks the timing of a probe. A nil spec is valid.
+func (p *ProbeSpec) validate() (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	check := func(value *int32, minimum, maximum int32, field string) {
+		if value != nil && (*value < minimum || *value > maximum) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("must be between %d and %d", minimum, maximum), field))
+		}
+	}
+	check(p.InitialDelaySeconds, 0, maxProbeSeconds, "initialDelaySeconds")
+	check(p.PeriodSeconds, 1, maxProbeSeconds, "periodSeconds")
+	check(p.TimeoutSeconds, 1, maxProbeSeconds, "timeoutSeconds")
+	check(p.FailureThreshold, 1, maxProbeFailureThreshold, "failureThreshold")
+	return errs
+}
+
+// apiNormalizationModelRegex matches the model names the API normalizer may inject; they
+// are passed on its command line.
+var apiNormalizationModelRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@-]*$`)
+
+// validate checks the API normalization settings. A nil spec is valid.
+func (n *APINormalizationSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if n == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("API normalization is not supported with a custom inference template")
+	}
+	switch n.Translate {
+	case "", APITranslationNone, APITranslationChatToCompletions, APITranslationCompletionsToChat:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported translation %q, supported values are None, ChatToCompletions, CompletionsToChat", n.Translate), "translate"))
+	}
+	switch n.MaxTokensField {
+	case "", MaxTokensFieldMaxTokens, MaxTokensFieldMaxCompletionTokens:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported maxTokensField %q, supported values are max_tokens, max_completion_tokens", n.MaxTokensField), "maxTokensField"))
+	}
+	if n.DefaultModel != "" && (len(n.DefaultModel) > 256 || !apiNormalizationModelRegex.MatchString(n.DefaultModel)) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid model name %q", n.DefaultModel), "defaultModel"))
+	}
+	if h := strings.ToLower(n.AdapterHeader); h != "" {
+		switch {
+		case len(h) > 64 || !headerNameRegex.MatchString(h):
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid header name %q", n.AdapterHeader), "adapterHeader"))
+		case slices.Contains(reservedAdapterHeaders, h) || strings.HasPrefix(h, "x-gateway-"):
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("header %q is reserved", n.AdapterHeader), "adapterHeader"))
+		}
+	}
+	return errs
+}
+
+// headerNameRegex matches the lower case header names that the sidecars accept.
+var headerNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// reservedAdapterHeaders are headers with a meaning to HTTP or to the inference server.
+// Headers starting with x-gateway- are used by the Gateway API Inference Extension.
+var reservedAdapterHeaders = []string{
+	"authorization", "connection", "content-length", "content-type", "host",
+	"keep-alive", "te", "trailer", "transfer-encoding", "upgrade", "traceparent", "tracestate",
+}
+
+// validate checks the image pull settings. A nil spec is valid.
+func (p *ImagePullSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("imagePull is not supported with a custom inference template, set the image pull policy in the template instead")
+	}
+	switch p.Policy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported image pull policy %q, supported values are Always, IfNotPresent, Never", p.Policy), "policy"))
+	}
+	if p.LazyPull != nil {
+		if p.LazyPull.RuntimeClassName == "" {
+			errs = errs.Also(apis.ErrMissingField("lazyPull.runtimeClassName"))
+		} else if errmsgs := validation.IsDNS1123Subdomain(p.LazyPull.RuntimeClassName); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "lazyPull.runtimeClassName"))
+		}
+	}
+	return errs
+}
+
+// validateLazyPull rejects lazy pulling on confidential workloads, whose pods must run with
+// the RuntimeClass of the confidential runtime.
+func (w *Workspace) validateLazyPull() *apis.FieldError {
+	if w.Inference == nil || w.Inference.ImagePull == nil || w.Inference.ImagePull.LazyPull == nil {
+		return nil
+	}
+	if w.Resource.ConfidentialCompute != nil && w.Resource.ConfidentialCompute.RuntimeClassName != nil {
+		return apis.ErrGeneric("lazy pulling cannot be combined with the RuntimeClass of confidential compute",
+			"spec.inference.imagePull.lazyPull", "spec.resource.confidentialCompute.runtimeClassName")
+	}
+	return nil
+}
+
+// blockedRuntimeEnvNames are the env vars that RuntimeOverrides cannot set: the ones that
+// select the GPUs or the libraries of the container, and the ones KAITO sets itself.
+var blockedRuntimeEnvNames = []string{
+	"CUDA_VISIBLE_DEVICES",
+	"NVIDIA_VISIBLE_DEVICES",
+	"NVIDIA_DRIVER_CAPABILITIES",
+	"LD_PRELOAD",
+	"LD_LIBRARY_PATH",
+	"PATH",
+	"PYTHONPATH",
+	"PYTHONHOME",
+	"HF_TOKEN",
+	"POD_INDEX",
+	"POD_IP",
+	"POD_NAME",
+	"POD_NAMESPACE",
+	"VLLM_HOST_IP",
+	"VLLM_NIXL_SIDE_CHANNEL_HOST",
+	"NCCL_SOCKET_IFNAME",
+	"GLOO_SOCKET_IFNAME",
+	"REDIS_PASSWORD",
+	consts.VLLMHTTPTimeoutKeepAliveEnvName,
+	consts.VLLMUseFlashInferSamplerEnvName,
+	consts.VLLMUseDeepGEMMEnvName,
+	consts.VLLMUseFlashInferMoeFP16EnvName,
+	consts.VLLMUseFlashInferMoeFP8EnvName,
+	consts.VLLMUseFlashInferMoeFP4EnvName,
+	consts.VLLMUseFlashInferMoeMXFP4BF16EnvName,
+	consts.VLLMUseFlashInferMoeMXFP4MXFP8EnvName,
+	consts.VLLMUseFlashInferMoeMXFP4MXFP8CutlassEnvName,
+}
+
+// blockedRuntimeEnvPrefixes are the prefixes of env vars owned by KAITO and by the
+// tracing and model streaming settings.
+var blockedRuntimeEnvPrefixes = []string{"KAITO_", "OTEL_", "STREAM_"}
+
+// blockedRuntimeArgs are the vLLM flags that KAITO sets from the preset, the resource
+// spec or other InferenceSpec fields. Names are compared with underscores replaced by
+// dashes, as vLLM accepts both spellings.
+var blockedRuntimeArgs = []string{
+	"model",
+	"served-model-name",
+	"host",
+	"port",
+	"download-dir",
+	"code-revision",
+	"load-format",
+	"model-loader-extra-config",
+	"kaito-config-file",
+	"max-model-len",
+	"gpu-memory-utilization",
+	"kaito-kv-cache-cpu-memory-utilization",
+	"tensor-parallel-size",
+	"pipeline-parallel-size",
+	"data-parallel-size",
+	"distributed-executor-backend",
+	"enable-lora",
+	"chat-template",
+	"tool-call-parser",
+	"enable-auto-tool-choice",
+	"structured-outputs-config.backend",
+	"otlp-traces-endpoint",
+	"performance-mode",
+}
+
+// runtimeArgNameRegex matches the flag names of RuntimeOverrides.ExtraArgs. They are
+// written unquoted into the shell command of the inference container.
+var runtimeArgNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// validate checks the env vars and flags passed to the inference server. A nil spec is valid.
+func (r *RuntimeOverridesSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if r == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("runtimeOverrides is not supported with a custom inference template, set the env and command in the template instead")
+	}
+	seen := map[string]bool{}
+	for idx, env := range r.Env {
+		if errmsgs := validation.IsCIdentifier(env.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "name").ViaFieldIndex("env", idx))
+			continue
+		}
+		if seen[env.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate env var %q", env.Name), "name").ViaFieldIndex("env", idx))
+		}
+		seen[env.Name] = true
+		if slices.Contains(blockedRuntimeEnvNames, env.Name) ||
+			slices.ContainsFunc(blockedRuntimeEnvPrefixes, func(prefix string) bool { return strings.HasPrefix(env.Name, prefix) }) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("env var %q is managed by KAITO and cannot be overridden", env.Name), "name").ViaFieldIndex("env", idx))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.ExtraArgs)) {
+		if strings.HasPrefix(name, "-") {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extraArgs", "flag names are given without the leading dashes"))
+			continue
+		}
+		if len(name) > 128 || !runtimeArgNameRegex.MatchString(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extraArgs", "flag names may only contain letters, digits, '.', '_' and '-'"))
+			continue
+		}
+		if slices.Contains(blockedRuntimeArgs, strings.ReplaceAll(name, "_", "-")) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extraArgs", "the flag is managed by KAITO and cannot be overridden"))
+			continue
+		}
+		if value := r.ExtraArgs[name]; len(value) > 4096 || strings.ContainsFunc(value, unicode.IsControl) {
+			errs = errs.Also(apis.ErrInvalidValue("values must be at most 4096 characters without control characters", "extraArgs["+name+"]"))
+		}
+	}
+	return errs
+}
+
+// reservedServicePorts are the ports of the inference Service other than the tokenizer port.
+var reservedServicePorts = []int32{80, 6379, 8265}
+
+// validate checks the tokenizer endpoint settings. A nil spec is valid.
+func (t *TokenizerEndpointSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if t == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("the tokenizer endpoint is not supported with a custom inference template")
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(t.Port, 1, 65535, "port"))
+	} else if slices.Contains(reservedServicePorts, t.GetPort()) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("port %d is already used by the Service", t.GetPort()), "port"))
+	}
+	return errs
+}
+
+// tracingServiceNameRegex matches the service names passed to the OpenTelemetry SDK
+// through OTEL_SERVICE_NAME.
+var tracingServiceNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// validate checks the tracing settings. A nil spec is valid.
+func (t *TracingSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if t == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("tracing is not supported with a custom inference template, set the OTEL_* environment variables in the template instead")
+	}
+	if t.Endpoint == "" {
+		errs = errs.Also(apis.ErrMissingField("endpoint"))
+	} else if u, err := url.Parse(t.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		len(t.Endpoint) > 512 || strings.ContainsAny(t.Endpoint, " \t\n,") {
+		errs = errs.Also(apis.ErrInvalidValue("must be an http or https URL of an OTLP collector", "endpoint"))
+	}
+	switch t.Protocol {
+	case "", OTLPProtocolGRPC, OTLPProtocolHTTPProtobuf:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported protocol %q, supported values are grpc, http/protobuf", t.Protocol), "protocol"))
+	}
+	if t.SampleRatio != nil {
+		if ratio, err := strconv.ParseFloat(*t.SampleRatio, 64); err != nil || ratio < 0 || ratio > 1 {
+			errs = errs.Also(apis.ErrInvalidValue("must be a number between 0 and 1", "sampleRatio"))
+		}
+	}
+	if t.ServiceName != "" && (len(t.ServiceName) > 253 || !tracingServiceNameRegex.MatchString(t.ServiceName)) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid service name %q", t.ServiceName), "serviceName"))
+	}
+	return errs
+}
+
+// validate checks the response cache settings. A nil spec is valid.
+func (c *ResponseCacheSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if c == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("the response cache is not supported with a custom inference template")
+	}
+	if c.TTL != nil && c.TTL.Duration < time.Second {
+		errs = errs.Also(apis.ErrInvalidValue("ttl must be at least 1s", "ttl"))
+	}
+	switch c.Backend {
+	case "", ResponseCacheBackendMemory:
+		if c.Redis != nil {
+			errs = errs.Also(apis.ErrGeneric("redis is only supported with the Redis backend", "redis"))
+		}
+	case ResponseCacheBackendRedis:
+		if c.MaxEntries != nil {
+			errs = errs.Also(apis.ErrGeneric("maxEntries is only supported with the Memory backend", "maxEntries"))
+		}
+		if c.Redis == nil {
+			return errs.Also(apis.ErrMissingField("redis"))
+		}
+		errs = errs.Also(c.Redis.validate().ViaField("redis"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported backend %q, supported values are Memory, Redis", c.Backend), "backend"))
+	}
+	switch c.KeyPolicy {
+	case "", ResponseCacheKeyPolicyExactPrompt, ResponseCacheKeyPolicyNormalizedPrompt:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported key policy %q, supported values are ExactPrompt, NormalizedPrompt", c.KeyPolicy), "keyPolicy"))
+	}
+	// The header names become command line arguments of the sidecar.
+	for i, h := range c.IdentityHeaders {
+		if lower := strings.ToLower(h); len(lower) > 64 || !headerNameRegex.MatchString(lower) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid header name %q", h), apis.CurrentField).ViaFieldIndex("identityHeaders", i))
+		}
+	}
+	return errs
+}
+
+// validate checks the Redis server settings. The address becomes a command line argument
+// of the sidecar, so it must be a plain host:port.
+func (r *RedisCacheSpec) validate() (errs *apis.FieldError) {
+	host, port, err := net.SplitHostPort(r.Address)
+	if err != nil || host == "" {
+		errs = errs.Also(apis.ErrInvalidValue("address must be host:port", "address"))
+	} else {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			errs = errs.Also(apis.ErrInvalidValue("address port must be between 1 and 65535", "address"))
+		}
+		if net.ParseIP(host) == nil && len(validation.IsDNS1123Subdomain(strings.ToLower(host))) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue("address host must be an IP address or a DNS name", "address"))
+		}
+	}
+	if r.PasswordSecret != "" {
+		if errmsgs := validation.IsDNS1123Subdomain(r.PasswordSecret); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "passwordSecret"))
+		}
+	}
+	return errs
+}
+
+// validate checks the multi-node settings. A nil spec is valid.
+func (d *DistributedInferenceSpec) validate(customTemplate bool) (errs *apis.FieldError) {
+	if d == nil {
+		return nil
+	}
+	if customTemplate {
+		return apis.ErrGeneric("distributed settings are not supported with a custom inference template")
+	}
+	switch d.RestartPolicy {
+	case "", DistributedRestartPolicyRecreateGroupOnPodRestart, DistributedRestartPolicyNone:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(d.RestartPolicy, "restartPolicy"))
+	}
+	if d.SocketInterface != "" && !socketInterfaceRegex.MatchString(d.SocketInterface) {
+		errs = errs.Also(apis.ErrInvalidValue("must be a network interface name", "socketInterface"))
+	}
+	return errs
+}
+
+// presetWithoutMutableOptions returns a copy of p without the preset options that may
+// be changed after creation.
+func presetWithoutMutableOptions(p *PresetSpec) *PresetSpec {
+	if p == nil {
+		return nil
+	}
+	c := p.DeepCopy()
+	c.Proxy = nil
+	c.AcceptLicense = ""
+	return c
+}
+
+// validate checks the proxy settings. They are passed to the containers as
+// environment variables, so credentials are rejected. A nil spec is valid.
+func (p *ProxySpec) validate() (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	for field, value := range map[string]string{"httpProxy": p.HTTPProxy, "httpsProxy": p.HTTPSProxy} {
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		switch {
+		case err != nil:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid proxy URL: %v", err), field))
+		case u.Scheme != "http" && u.Scheme != "https":
+			errs = errs.Also(apis.ErrInvalidValue("proxy URL scheme must be http or https", field))
+		case u.Hostname() == "":
+			errs = errs.Also(apis.ErrInvalidValue("proxy URL must have a host", field))
+		case u.User != nil:
+			errs = errs.Also(apis.ErrInvalidValue("proxy URL must not contain credentials", field))
+		case (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "":
+			errs = errs.Also(apis.ErrInvalidValue("proxy URL must not have a path, query or fragment", field))
+		}
+	}
+	for _, entry := range strings.Split(p.NoProxy, ",") {
+		if strings.ContainsAny(strings.TrimSpace(entry), " \t\n\"'$`\\") {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("invalid noProxy entry %q", entry), "noProxy"))
+		}
+	}
+	if p.CABundleSecret != "" {
+		if msgs := validation.IsDNS1123Subdomain(p.CABundleSecret); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "caBundleSecret"))
+		}
+	}
+	return errs
+}
+
+// validate checks the Service options. A nil spec is valid.
+func (s *EndpointServiceSpec) ipFamilies() []corev1.IPFamily {
+	if s == nil {
+		return nil
+	}
+	return s.IPFamilies
+}
+
+func (s *EndpointServiceSpec) validate() (errs *apis.FieldError) {
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported service type %q, supported values are ClusterIP, LoadBalancer, NodePort", s.Type), "type"))
+	}
+	if s.IPFamilyPolicy != nil {
+		switch *s.IPFamilyPolicy {
+		case corev1.IPFamilyPolicySingleStack, corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicyRequireDualStack:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported ipFamilyPolicy %q", *s.IPFamilyPolicy), "ipFamilyPolicy"))
+		}
+	}
+	if len(s.IPFamilies) > 2 {
+		errs = errs.Also(apis.ErrInvalidValue("at most two ipFamilies may be specified", "ipFamilies"))
+	}
+	seen := map[corev1.IPFamily]bool{}
+	for idx, family := range s.IPFamilies {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported ip family %q, supported values are IPv4, IPv6", family), apis.CurrentField).ViaFieldIndex("ipFamilies", idx))
+		} else if seen[family] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("duplicate ip family %q", family), apis.CurrentField).ViaFieldIndex("ipFamilies", idx))
+		}
+		seen[family] = true
+	}
+	if len(s.IPFamilies) == 2 && s.IPFamilyPolicy != nil && *s.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack {
+		errs = errs.Also(apis.ErrGeneric("two ipFamilies require a dual-stack ipFamilyPolicy", "ipFamilies", "ipFamilyPolicy"))
+	}
+	if s.InternalTrafficPolicy != nil {
+		switch *s.InternalTrafficPolicy {
+		case corev1.ServiceInternalTrafficPolicyCluster, corev1.ServiceInternalTrafficPolicyLocal:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported internalTrafficPolicy %q, supported values are Cluster, Local", *s.InternalTrafficPolicy), "internalTrafficPolicy"))
+		}
+	}
+	if s.TrafficDistribution != nil {
+		switch *s.TrafficDistribution {
+		case corev1.ServiceTrafficDistributionPreferClose, corev1.ServiceTrafficDistributionPreferSameZone, corev1.ServiceTrafficDistributionPreferSameNode:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("unsupported trafficDistribution %q, supported values are PreferClose, PreferSameZone, PreferSameNode", *s.TrafficDistribution), "trafficDistribution"))
+		}
+	}
+	for key := range s.Annotations {
+		if msgs := validation.IsQualifiedName(strings.ToLower(key)); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "annotations", msgs...))
+		} else if strings.HasPrefix(key, KAITOPrefix) {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "annotations", "the kaito.sh/ prefix is reserved"))
+		} else if s.DNS != nil && s.DNS.Hostname != "" && (key == AnnotationExternalDNSHostname || key == AnnotationExternalDNSTTL) {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "annotations", "set through dns when dns.hostname is set"))
+		} else if s.Timeouts != nil && s.Timeouts.Idle != nil && (key == AnnotationAzureLoadBalancerIdleTimeout || key == AnnotationAWSLoadBalancerIdleTimeout) {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "annotations", "set through timeouts.idle when it is set"))
+		}
+	}
+	errs = errs.Also(s.DNS.validate().ViaField("dns"))
+	errs = errs.Also(s.Timeouts.validate().ViaField("timeouts"))
+	return errs
+}
+
+// validate checks the timeouts. A nil spec is valid.
+func (t *ServiceTimeoutsSpec) validate() (errs *apis.FieldError) {
+	if t == nil {
+		return nil
+	}
+	if t.Request != nil {
+		if t.Request.Duration < 0 {
+			errs = errs.Also(apis.ErrInvalidValue("request must not be negative", "request"))
+		} else if t.Request.Duration%time.Millisecond != 0 {
+			errs = errs.Also(apis.ErrInvalidValue("request must be a whole number of milliseconds", "request"))
+		}
+	}
+	if t.Idle != nil && t.Idle.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue("idle must be positive", "idle"))
+	}
+	if t.KeepAlive != nil && t.KeepAlive.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue("keepAlive must be positive", "keepAlive"))
+	}
+	return errs
+}
+
+// validate checks the DNS publishing options. A nil spec is valid.
+func (d *ServiceDNSSpec) validate() (errs *apis.FieldError) {
+	if d == nil {
+		return nil
+	}
+	if d.Hostname == "" && !d.Export {
+		errs = errs.Also(apis.ErrGeneric("at least one of hostname or export is required", "hostname", "export"))
+	}
+	if d.Hostname != "" {
+		if msgs := validation.IsDNS1123Subdomain(d.Hostname); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "hostname"))
+		} else if !strings.Contains(d.Hostname, ".") {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q must be a fully qualified domain name", d.Hostname), "hostname"))
+		}
+	}
+	if d.TTL != nil {
+		if *d.TTL <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue("ttl must be positive", "ttl"))
+		}
+		if d.Hostname == "" {
+			errs = errs.Also(apis.ErrGeneric("ttl requires hostname", "ttl"))
+		}
+	}
 	return errs
 }
 
@@ -853,7 +2213,7 @@ func (w *Workspace) validateStreamingCSIDriver(ctx context.Context) *apis.FieldE
 	}
 
 	csiDriver := &storagev1.CSIDriver{}
-	if err := k8sclient.GetGlobalClient().Get(ctx, client.ObjectKey{Name: expectedDriver}, csiDriver); err != nil {
+	if err := k8sclient.FromContext(ctx).Get(ctx, client.ObjectKey{Name: expectedDriver}, csiDriver); err != nil {
 		return apis.ErrGeneric(
 			fmt.Sprintf("CSI driver %s not found; required for model streaming. "+
 				"Ensure the CSI driver is enabled on your cluster",
@@ -863,3 +2223,165 @@ func (w *Workspace) validateStreamingCSIDriver(ctx context.Context) *apis.FieldE
 	}
 	return nil
 }
+
+// maxIdentityStorageURLs bounds the storage the controller probes for every workspace.
+const maxIdentityStorageURLs = 16
+
+var (
+	identityGUIDRegex    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	identityRoleARNRegex = regexp.MustCompile(`^arn:aws[a-z-]*:iam::[0-9]{12}:role/[A-Za-z0-9+=,.@_/-]{1,512}$`)
+	azureBlobHostRegex   = regexp.MustCompile(`^[a-z0-9]{3,24}\.blob\.core\.windows\.net$`)
+	blobContainerRegex   = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{1,61}[a-z0-9])$`)
+	s3BucketRegex        = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+)
+
+// validate checks the workload identity settings. A nil spec is valid.
+// Validate checks the times and the time zone of a maintenance window. It is exported for
+// the InferenceSet webhooks, which validate the window of their template.
+func (w *WorkspaceMaintenanceWindow) Validate() (errs *apis.FieldError) {
+	if w == nil {
+		return nil
+	}
+	if _, _, err := parseClock(w.StartTime); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(w.StartTime, "startTime", "must be a time of day as HH:MM"))
+	}
+	if _, _, err := parseClock(w.EndTime); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(w.EndTime, "endTime", "must be a time of day as HH:MM"))
+	}
+	if w.TimeZone != "" {
+		if _, err := time.LoadLocation(w.TimeZone); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(w.TimeZone, "timeZone", "must be an IANA time zone"))
+		}
+	}
+	for i, day := range w.Days {
+		if !slices.Contains(maintenanceDays, day) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(day, "days", i))
+		}
+	}
+	return errs
+}
+
+// validate checks the label and annotation keys of a metadata propagation spec. A nil spec
+// is valid.
+func (p *MetadataPropagationSpec) validate() (errs *apis.FieldError) {
+	if p == nil {
+		return nil
+	}
+	for i, pattern := range p.Labels {
+		errs = errs.Also(validatePropagationPattern(pattern).ViaFieldIndex("labels", i))
+	}
+	for i, pattern := range p.Annotations {
+		errs = errs.Also(validatePropagationPattern(pattern).ViaFieldIndex("annotations", i))
+	}
+	return errs
+}
+
+// validatePropagationPattern checks that a propagated key is a qualified name, optionally
+// cut short by a trailing "*", outside the reserved domains.
+func validatePropagationPattern(pattern string) *apis.FieldError {
+	prefix, wildcard := strings.CutSuffix(pattern, "*")
+	name := prefix
+	if wildcard {
+		// Complete the prefix to a name, so "team-*" and "cost.example.com/*" are valid.
+		name += "x"
+	}
+	if msgs := validation.IsQualifiedName(name); len(msgs) > 0 {
+		return apis.ErrInvalidValue(fmt.Sprintf("%q must be a label or annotation key, optionally ending in *: %s", pattern, strings.Join(msgs, "; ")), apis.CurrentField)
+	}
+	if reservedPropagationKey(name) {
+		return apis.ErrInvalidValue(fmt.Sprintf("keys of %q cannot be propagated", pattern), apis.CurrentField)
+	}
+	return nil
+}
+
+// maintenanceDays are the valid days of a maintenance window.
+var maintenanceDays = []MaintenanceDay{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+func (i *WorkloadIdentitySpec) validate() (errs *apis.FieldError) {
+	if i == nil {
+		return nil
+	}
+	switch i.Provider {
+	case WorkloadIdentityProviderAzure:
+		if !identityGUIDRegex.MatchString(i.ClientID) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q must be the client ID (a GUID) of the Azure identity", i.ClientID), "clientID"))
+		}
+		if !identityGUIDRegex.MatchString(i.TenantID) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q must be the tenant ID (a GUID) of the Azure identity", i.TenantID), "tenantID"))
+		}
+		if i.RoleARN != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("roleARN"))
+		}
+	case WorkloadIdentityProviderAWS:
+		if !identityRoleARNRegex.MatchString(i.RoleARN) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q must be the ARN of an IAM role", i.RoleARN), "roleARN"))
+		}
+		if i.ClientID != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("clientID"))
+		}
+		if i.TenantID != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("tenantID"))
+		}
+	default:
+		return apis.ErrInvalidValue(fmt.Sprintf("unsupported provider %q, supported values are Azure, AWS", i.Provider), "provider")
+	}
+
+	if len(i.StorageURLs) > maxIdentityStorageURLs {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("at most %d storage URLs are supported", maxIdentityStorageURLs), "storageURLs"))
+	}
+	for idx, raw := range i.StorageURLs {
+		if err := i.validateStorageURL(raw); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(fmt.Sprintf("%q %s", raw, err), "storageURLs", idx))
+		}
+	}
+	return errs
+}
+
+// validateStorageURL checks that a storage URL names a blob container or S3 bucket of the
+// identity's cloud. URLs carrying credentials, such as SAS tokens, are rejected.
+func (i *WorkloadIdentitySpec) validateStorageURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.User != nil || u.RawQuery != "" || u.Fragment != "" || len(raw) > 1024 {
+		return fmt.Errorf("must be a storage URL without credentials or query")
+	}
+	switch i.Provider {
+	case WorkloadIdentityProviderAzure:
+		container, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+		if u.Scheme != "https" || !azureBlobHostRegex.MatchString(u.Host) || !blobContainerRegex.MatchString(container) {
+			return fmt.Errorf("must be an Azure Blob container URL, https://<account>.blob.core.windows.net/<container>")
+		}
+	case WorkloadIdentityProviderAWS:
+		if u.Scheme != "s3" || !s3BucketRegex.MatchString(u.Host) {
+			return fmt.Errorf("must be an S3 URL, s3://<bucket>/<prefix>")
+		}
+	}
+	return nil
+}
+
+// validateServiceAccount checks spec.serviceAccount. Read permissions are only granted to the
+// generated ServiceAccount, so they cannot be combined with an existing one.
+func (w *Workspace) validateServiceAccount() (errs *apis.FieldError) {
+	sa := w.ServiceAccount
+	if sa == nil {
+		return nil
+	}
+	if sa.Name != "" {
+		if msgs := validation.IsDNS1123Subdomain(sa.Name); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%q: %s", sa.Name, strings.Join(msgs, "; ")), "name"))
+		}
+		if w.Identity != nil {
+			errs = errs.Also(apis.ErrGeneric("an existing ServiceAccount cannot be combined with identity, which annotates the generated one", "name"))
+		}
+		if len(sa.ReadConfigMaps) > 0 || len(sa.ReadSecrets) > 0 {
+			errs = errs.Also(apis.ErrGeneric("read permissions are only granted to the generated ServiceAccount, grant them to the existing one instead", "name"))
+		}
+	}
+	for field, names := range map[string][]string{"readConfigMaps": sa.ReadConfigMaps, "readSecrets": sa.ReadSecrets} {
+		for idx, name := range names {
+			if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+				errs = errs.Also(apis.ErrInvalidArrayValue(fmt.Sprintf("%q: %s", name, strings.Join(msgs, "; ")), field, idx))
+			}
+		}
+	}
+	return errs
+}
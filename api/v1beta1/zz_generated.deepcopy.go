@@ -21,8 +21,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APINormalizationSpec) DeepCopyInto(out *APINormalizationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APINormalizationSpec.
+func (in *APINormalizationSpec) DeepCopy() *APINormalizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APINormalizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdapterSpec) DeepCopyInto(out *AdapterSpec) {
 	*out = *in
@@ -48,6 +64,21 @@ func (in *AdapterSpec) DeepCopy() *AdapterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttestationSpec) DeepCopyInto(out *AttestationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttestationSpec.
+func (in *AttestationSpec) DeepCopy() *AttestationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AttestationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoUpgradePolicy) DeepCopyInto(out *AutoUpgradePolicy) {
 	*out = *in
@@ -92,6 +123,101 @@ func (in *AutoUpgradeStatus) DeepCopy() *AutoUpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureServiceBusScaleTrigger) DeepCopyInto(out *AzureServiceBusScaleTrigger) {
+	*out = *in
+	if in.MessageCount != nil {
+		in, out := &in.MessageCount, &out.MessageCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureServiceBusScaleTrigger.
+func (in *AzureServiceBusScaleTrigger) DeepCopy() *AzureServiceBusScaleTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureServiceBusScaleTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupLocation) DeepCopyInto(out *BackupLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupLocation.
+func (in *BackupLocation) DeepCopy() *BackupLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColdStartStatus) DeepCopyInto(out *ColdStartStatus) {
+	*out = *in
+	if in.NodeClaimsCreatedTime != nil {
+		in, out := &in.NodeClaimsCreatedTime, &out.NodeClaimsCreatedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NodesReadyTime != nil {
+		in, out := &in.NodesReadyTime, &out.NodesReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ImagePulledTime != nil {
+		in, out := &in.ImagePulledTime, &out.ImagePulledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InferenceReadyTime != nil {
+		in, out := &in.InferenceReadyTime, &out.InferenceReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FirstTokenTime != nil {
+		in, out := &in.FirstTokenTime, &out.FirstTokenTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColdStartStatus.
+func (in *ColdStartStatus) DeepCopy() *ColdStartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ColdStartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfidentialComputeSpec) DeepCopyInto(out *ConfidentialComputeSpec) {
+	*out = *in
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Attestation != nil {
+		in, out := &in.Attestation, &out.Attestation
+		*out = new(AttestationSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfidentialComputeSpec.
+func (in *ConfidentialComputeSpec) DeepCopy() *ConfidentialComputeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfidentialComputeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -123,6 +249,36 @@ func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversationMemorySpec) DeepCopyInto(out *ConversationMemorySpec) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisMemorySpec)
+		**out = **in
+	}
+	if in.MaxTurns != nil {
+		in, out := &in.MaxTurns, &out.MaxTurns
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversationMemorySpec.
+func (in *ConversationMemorySpec) DeepCopy() *ConversationMemorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversationMemorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataDestination) DeepCopyInto(out *DataDestination) {
 	*out = *in
@@ -173,13 +329,28 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DistributedInferenceSpec) DeepCopyInto(out *DistributedInferenceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistributedInferenceSpec.
+func (in *DistributedInferenceSpec) DeepCopy() *DistributedInferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DistributedInferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddingSpec) DeepCopyInto(out *EmbeddingSpec) {
 	*out = *in
 	if in.Remote != nil {
 		in, out := &in.Remote, &out.Remote
 		*out = new(RemoteEmbeddingSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Local != nil {
 		in, out := &in.Local, &out.Local
@@ -198,6 +369,80 @@ func (in *EmbeddingSpec) DeepCopy() *EmbeddingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointServiceSpec) DeepCopyInto(out *EndpointServiceSpec) {
+	*out = *in
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(corev1.ServiceInternalTrafficPolicy)
+		**out = **in
+	}
+	if in.TrafficDistribution != nil {
+		in, out := &in.TrafficDistribution, &out.TrafficDistribution
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(ServiceDNSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(ServiceTimeoutsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointServiceSpec.
+func (in *EndpointServiceSpec) DeepCopy() *EndpointServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUUtilizationStatus) DeepCopyInto(out *GPUUtilizationStatus) {
+	*out = *in
+	out.MemoryUsed = in.MemoryUsed.DeepCopy()
+	out.MemoryTotal = in.MemoryTotal.DeepCopy()
+	in.LastSampleTime.DeepCopyInto(&out.LastSampleTime)
+	if in.LowUtilizationSince != nil {
+		in, out := &in.LowUtilizationSince, &out.LowUtilizationSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUUtilizationStatus.
+func (in *GPUUtilizationStatus) DeepCopy() *GPUUtilizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUUtilizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GuardrailsSpec) DeepCopyInto(out *GuardrailsSpec) {
 	*out = *in
@@ -218,6 +463,113 @@ func (in *GuardrailsSpec) DeepCopy() *GuardrailsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthStatus) DeepCopyInto(out *HealthStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthStatus.
+func (in *HealthStatus) DeepCopy() *HealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullSpec) DeepCopyInto(out *ImagePullSpec) {
+	*out = *in
+	if in.LazyPull != nil {
+		in, out := &in.LazyPull, &out.LazyPull
+		*out = new(LazyPullSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSpec.
+func (in *ImagePullSpec) DeepCopy() *ImagePullSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAccessSpec) DeepCopyInto(out *IndexAccessSpec) {
+	*out = *in
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIKeys != nil {
+		in, out := &in.APIKeys, &out.APIKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAccessSpec.
+func (in *IndexAccessSpec) DeepCopy() *IndexAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAuthorizationSpec) DeepCopyInto(out *IndexAuthorizationSpec) {
+	*out = *in
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make(map[string]IndexAccessSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = new(IndexAccessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAuthorizationSpec.
+func (in *IndexAuthorizationSpec) DeepCopy() *IndexAuthorizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAuthorizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexPolicySpec) DeepCopyInto(out *IndexPolicySpec) {
+	*out = *in
+	if in.DocumentTTL != nil {
+		in, out := &in.DocumentTTL, &out.DocumentTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexPolicySpec.
+func (in *IndexPolicySpec) DeepCopy() *IndexPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceConfig) DeepCopyInto(out *InferenceConfig) {
 	*out = *in
@@ -282,6 +634,43 @@ func (in *InferenceSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceSetAutoscalingSpec) DeepCopyInto(out *InferenceSetAutoscalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollingIntervalSeconds != nil {
+		in, out := &in.PollingIntervalSeconds, &out.PollingIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownSeconds != nil {
+		in, out := &in.CooldownSeconds, &out.CooldownSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]ScaleTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetAutoscalingSpec.
+func (in *InferenceSetAutoscalingSpec) DeepCopy() *InferenceSetAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceSetAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceSetList) DeepCopyInto(out *InferenceSetList) {
 	*out = *in
@@ -322,6 +711,11 @@ func (in *InferenceSetResourceSpec) DeepCopyInto(out *InferenceSetResourceSpec)
 		*out = new(PartitionSpec)
 		**out = **in
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetResourceSpec.
@@ -343,6 +737,11 @@ func (in *InferenceSetSpec) DeepCopyInto(out *InferenceSetSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MinReadyReplicas != nil {
+		in, out := &in.MinReadyReplicas, &out.MinReadyReplicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(v1.LabelSelector)
@@ -354,6 +753,21 @@ func (in *InferenceSetSpec) DeepCopyInto(out *InferenceSetSpec) {
 		*out = new(AutoUpgradePolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(InferenceSetAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShadowTo != nil {
+		in, out := &in.ShadowTo, &out.ShadowTo
+		*out = new(ShadowSpec)
+		**out = **in
+	}
+	if in.TrafficClasses != nil {
+		in, out := &in.TrafficClasses, &out.TrafficClasses
+		*out = new(TrafficClassesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetSpec.
@@ -386,6 +800,11 @@ func (in *InferenceSetStatus) DeepCopyInto(out *InferenceSetStatus) {
 		*out = new(AutoUpgradeStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HealthStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetStatus.
@@ -404,6 +823,11 @@ func (in *InferenceSetTemplate) DeepCopyInto(out *InferenceSetTemplate) {
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Resource.DeepCopyInto(&out.Resource)
 	in.Inference.DeepCopyInto(&out.Inference)
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(WorkspaceMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSetTemplate.
@@ -436,6 +860,71 @@ func (in *InferenceSpec) DeepCopyInto(out *InferenceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(EndpointServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToolCalling != nil {
+		in, out := &in.ToolCalling, &out.ToolCalling
+		*out = new(ToolCallingSpec)
+		**out = **in
+	}
+	if in.StructuredOutputs != nil {
+		in, out := &in.StructuredOutputs, &out.StructuredOutputs
+		*out = new(StructuredOutputsSpec)
+		**out = **in
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(ShutdownSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Distributed != nil {
+		in, out := &in.Distributed, &out.Distributed
+		*out = new(DistributedInferenceSpec)
+		**out = **in
+	}
+	if in.ResponseCache != nil {
+		in, out := &in.ResponseCache, &out.ResponseCache
+		*out = new(ResponseCacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APINormalization != nil {
+		in, out := &in.APINormalization, &out.APINormalization
+		*out = new(APINormalizationSpec)
+		**out = **in
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tokenizer != nil {
+		in, out := &in.Tokenizer, &out.Tokenizer
+		*out = new(TokenizerEndpointSpec)
+		**out = **in
+	}
+	if in.ImagePull != nil {
+		in, out := &in.ImagePull, &out.ImagePull
+		*out = new(ImagePullSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeOverrides != nil {
+		in, out := &in.RuntimeOverrides, &out.RuntimeOverrides
+		*out = new(RuntimeOverridesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceSpec.
@@ -448,6 +937,40 @@ func (in *InferenceSpec) DeepCopy() *InferenceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazyPullSpec) DeepCopyInto(out *LazyPullSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazyPullSpec.
+func (in *LazyPullSpec) DeepCopy() *LazyPullSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LazyPullSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LineageStatus) DeepCopyInto(out *LineageStatus) {
+	*out = *in
+	if in.RecordedTime != nil {
+		in, out := &in.RecordedTime, &out.RecordedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LineageStatus.
+func (in *LineageStatus) DeepCopy() *LineageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LineageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalEmbeddingSpec) DeepCopyInto(out *LocalEmbeddingSpec) {
 	*out = *in
@@ -463,6 +986,41 @@ func (in *LocalEmbeddingSpec) DeepCopy() *LocalEmbeddingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogForwarderSpec) DeepCopyInto(out *LogForwarderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogForwarderSpec.
+func (in *LogForwarderSpec) DeepCopy() *LogForwarderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogForwarderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
+	*out = *in
+	if in.Forwarder != nil {
+		in, out := &in.Forwarder, &out.Forwarder
+		*out = new(LogForwarderSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
 	*out = *in
@@ -483,6 +1041,36 @@ func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPropagationSpec) DeepCopyInto(out *MetadataPropagationSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]MetadataPropagationTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPropagationSpec.
+func (in *MetadataPropagationSpec) DeepCopy() *MetadataPropagationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPropagationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metric) DeepCopyInto(out *Metric) {
 	*out = *in
@@ -520,6 +1108,25 @@ func (in *PartitionSpec) DeepCopy() *PartitionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingOperation) DeepCopyInto(out *PendingOperation) {
+	*out = *in
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingOperation.
+func (in *PendingOperation) DeepCopy() *PendingOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Performance) DeepCopyInto(out *Performance) {
 	*out = *in
@@ -557,6 +1164,43 @@ func (in *PersistentVolumeConfig) DeepCopy() *PersistentVolumeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.AllowColocationWith != nil {
+		in, out := &in.AllowColocationWith, &out.AllowColocationWith
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreStopSpec) DeepCopyInto(out *PreStopSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreStopSpec.
+func (in *PreStopSpec) DeepCopy() *PreStopSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreStopSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PresetMeta) DeepCopyInto(out *PresetMeta) {
 	*out = *in
@@ -580,6 +1224,11 @@ func (in *PresetOptions) DeepCopyInto(out *PresetOptions) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetOptions.
@@ -609,6 +1258,157 @@ func (in *PresetSpec) DeepCopy() *PresetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSpec.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesSpec) DeepCopyInto(out *ProbesSpec) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesSpec.
+func (in *ProbesSpec) DeepCopy() *ProbesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusScaleTrigger) DeepCopyInto(out *PrometheusScaleTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusScaleTrigger.
+func (in *PrometheusScaleTrigger) DeepCopy() *PrometheusScaleTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusScaleTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningPlan) DeepCopyInto(out *ProvisioningPlan) {
+	*out = *in
+	in.GeneratedTime.DeepCopyInto(&out.GeneratedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningPlan.
+func (in *ProvisioningPlan) DeepCopy() *ProvisioningPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAGBackupSpec) DeepCopyInto(out *RAGBackupSpec) {
+	*out = *in
+	out.Destination = in.Destination
+	if in.Retain != nil {
+		in, out := &in.Retain, &out.Retain
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGBackupSpec.
+func (in *RAGBackupSpec) DeepCopy() *RAGBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAGBackupStatus) DeepCopyInto(out *RAGBackupStatus) {
+	*out = *in
+	if in.LastSuccessfulBackupTime != nil {
+		in, out := &in.LastSuccessfulBackupTime, &out.LastSuccessfulBackupTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGBackupStatus.
+func (in *RAGBackupStatus) DeepCopy() *RAGBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RAGEngine) DeepCopyInto(out *RAGEngine) {
 	*out = *in
@@ -700,12 +1500,54 @@ func (in *RAGEngineSpec) DeepCopyInto(out *RAGEngineSpec) {
 		*out = new(GuardrailsSpec)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGEngineSpec.
-func (in *RAGEngineSpec) DeepCopy() *RAGEngineSpec {
-	if in == nil {
-		return nil
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(IndexAuthorizationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(RAGBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RAGRestoreSpec)
+		**out = **in
+	}
+	if in.Sharding != nil {
+		in, out := &in.Sharding, &out.Sharding
+		*out = new(ShardingSpec)
+		**out = **in
+	}
+	if in.Retrieval != nil {
+		in, out := &in.Retrieval, &out.Retrieval
+		*out = new(RetrievalSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConversationMemory != nil {
+		in, out := &in.ConversationMemory, &out.ConversationMemory
+		*out = new(ConversationMemorySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseOptions != nil {
+		in, out := &in.ResponseOptions, &out.ResponseOptions
+		*out = new(ResponseOptionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexPolicies != nil {
+		in, out := &in.IndexPolicies, &out.IndexPolicies
+		*out = make([]IndexPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGEngineSpec.
+func (in *RAGEngineSpec) DeepCopy() *RAGEngineSpec {
+	if in == nil {
+		return nil
 	}
 	out := new(RAGEngineSpec)
 	in.DeepCopyInto(out)
@@ -713,103 +1555,691 @@ func (in *RAGEngineSpec) DeepCopy() *RAGEngineSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RAGEngineStatus) DeepCopyInto(out *RAGEngineStatus) {
+func (in *RAGEngineStatus) DeepCopyInto(out *RAGEngineStatus) {
+	*out = *in
+	if in.WorkerNodes != nil {
+		in, out := &in.WorkerNodes, &out.WorkerNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(RAGBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RAGRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]RAGIndexStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HealthStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGEngineStatus.
+func (in *RAGEngineStatus) DeepCopy() *RAGEngineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGEngineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAGIndexStatus) DeepCopyInto(out *RAGIndexStatus) {
+	*out = *in
+	if in.LastEvictionTime != nil {
+		in, out := &in.LastEvictionTime, &out.LastEvictionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGIndexStatus.
+func (in *RAGIndexStatus) DeepCopy() *RAGIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAGRestoreSpec) DeepCopyInto(out *RAGRestoreSpec) {
+	*out = *in
+	out.Source = in.Source
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGRestoreSpec.
+func (in *RAGRestoreSpec) DeepCopy() *RAGRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAGRestoreStatus) DeepCopyInto(out *RAGRestoreStatus) {
+	*out = *in
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGRestoreStatus.
+func (in *RAGRestoreStatus) DeepCopy() *RAGRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RAGRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisCacheSpec) DeepCopyInto(out *RedisCacheSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisCacheSpec.
+func (in *RedisCacheSpec) DeepCopy() *RedisCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisMemorySpec) DeepCopyInto(out *RedisMemorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisMemorySpec.
+func (in *RedisMemorySpec) DeepCopy() *RedisMemorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisMemorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteEmbeddingSpec) DeepCopyInto(out *RemoteEmbeddingSpec) {
+	*out = *in
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteEmbeddingSpec.
+func (in *RemoteEmbeddingSpec) DeepCopy() *RemoteEmbeddingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteEmbeddingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaStatus) DeepCopyInto(out *ReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaStatus.
+func (in *ReplicaStatus) DeepCopy() *ReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreferredNodes != nil {
+		in, out := &in.PreferredNodes, &out.PreferredNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(PartitionSpec)
+		**out = **in
+	}
+	if in.ProvisioningTimeout != nil {
+		in, out := &in.ProvisioningTimeout, &out.ProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FallbackInstanceTypes != nil {
+		in, out := &in.FallbackInstanceTypes, &out.FallbackInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfidentialCompute != nil {
+		in, out := &in.ConfidentialCompute, &out.ConfidentialCompute
+		*out = new(ConfidentialComputeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(WorkloadStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compute != nil {
+		in, out := &in.Compute, &out.Compute
+		*out = new(WorkloadComputeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSpec.
+func (in *ResourceSpec) DeepCopy() *ResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseCacheSpec) DeepCopyInto(out *ResponseCacheSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxEntries != nil {
+		in, out := &in.MaxEntries, &out.MaxEntries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisCacheSpec)
+		**out = **in
+	}
+	if in.IdentityHeaders != nil {
+		in, out := &in.IdentityHeaders, &out.IdentityHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseCacheSpec.
+func (in *ResponseCacheSpec) DeepCopy() *ResponseCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseOptionsSpec) DeepCopyInto(out *ResponseOptionsSpec) {
+	*out = *in
+	if in.MaxSourceChunks != nil {
+		in, out := &in.MaxSourceChunks, &out.MaxSourceChunks
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MetadataFields != nil {
+		in, out := &in.MetadataFields, &out.MetadataFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseOptionsSpec.
+func (in *ResponseOptionsSpec) DeepCopy() *ResponseOptionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseOptionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetrievalSpec) DeepCopyInto(out *RetrievalSpec) {
+	*out = *in
+	if in.VectorWeightPercent != nil {
+		in, out := &in.VectorWeightPercent, &out.VectorWeightPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetrievalSpec.
+func (in *RetrievalSpec) DeepCopy() *RetrievalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetrievalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RightSizingRecommendation) DeepCopyInto(out *RightSizingRecommendation) {
+	*out = *in
+	in.GeneratedTime.DeepCopyInto(&out.GeneratedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RightSizingRecommendation.
+func (in *RightSizingRecommendation) DeepCopy() *RightSizingRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(RightSizingRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeEnvVar) DeepCopyInto(out *RuntimeEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeEnvVar.
+func (in *RuntimeEnvVar) DeepCopy() *RuntimeEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeOverridesSpec) DeepCopyInto(out *RuntimeOverridesSpec) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]RuntimeEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeOverridesSpec.
+func (in *RuntimeOverridesSpec) DeepCopy() *RuntimeOverridesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeOverridesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleTrigger) DeepCopyInto(out *ScaleTrigger) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusScaleTrigger)
+		**out = **in
+	}
+	if in.AzureServiceBus != nil {
+		in, out := &in.AzureServiceBus, &out.AzureServiceBus
+		*out = new(AzureServiceBusScaleTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleTrigger.
+func (in *ScaleTrigger) DeepCopy() *ScaleTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountSpec) DeepCopyInto(out *ServiceAccountSpec) {
+	*out = *in
+	if in.ReadConfigMaps != nil {
+		in, out := &in.ReadConfigMaps, &out.ReadConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadSecrets != nil {
+		in, out := &in.ReadSecrets, &out.ReadSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountSpec.
+func (in *ServiceAccountSpec) DeepCopy() *ServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDNSSpec) DeepCopyInto(out *ServiceDNSSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDNSSpec.
+func (in *ServiceDNSSpec) DeepCopy() *ServiceDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTimeoutsSpec) DeepCopyInto(out *ServiceTimeoutsSpec) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Idle != nil {
+		in, out := &in.Idle, &out.Idle
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.KeepAlive != nil {
+		in, out := &in.KeepAlive, &out.KeepAlive
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTimeoutsSpec.
+func (in *ServiceTimeoutsSpec) DeepCopy() *ServiceTimeoutsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTimeoutsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowSpec) DeepCopyInto(out *ShadowSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowSpec.
+func (in *ShadowSpec) DeepCopy() *ShadowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingSpec) DeepCopyInto(out *ShardingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingSpec.
+func (in *ShardingSpec) DeepCopy() *ShardingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShutdownSpec) DeepCopyInto(out *ShutdownSpec) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = new(PreStopSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownSpec.
+func (in *ShutdownSpec) DeepCopy() *ShutdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShutdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.PersistentVolume != nil {
+		in, out := &in.PersistentVolume, &out.PersistentVolume
+		*out = new(PersistentVolumeConfig)
+		**out = **in
+	}
+	if in.VectorDB != nil {
+		in, out := &in.VectorDB, &out.VectorDB
+		*out = new(VectorDBConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StructuredOutputsSpec) DeepCopyInto(out *StructuredOutputsSpec) {
 	*out = *in
-	if in.WorkerNodes != nil {
-		in, out := &in.WorkerNodes, &out.WorkerNodes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StructuredOutputsSpec.
+func (in *StructuredOutputsSpec) DeepCopy() *StructuredOutputsSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(StructuredOutputsSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAGEngineStatus.
-func (in *RAGEngineStatus) DeepCopy() *RAGEngineStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenizerEndpointSpec) DeepCopyInto(out *TokenizerEndpointSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenizerEndpointSpec.
+func (in *TokenizerEndpointSpec) DeepCopy() *TokenizerEndpointSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RAGEngineStatus)
+	out := new(TokenizerEndpointSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteEmbeddingSpec) DeepCopyInto(out *RemoteEmbeddingSpec) {
+func (in *ToolCallingSpec) DeepCopyInto(out *ToolCallingSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteEmbeddingSpec.
-func (in *RemoteEmbeddingSpec) DeepCopy() *RemoteEmbeddingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolCallingSpec.
+func (in *ToolCallingSpec) DeepCopy() *ToolCallingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteEmbeddingSpec)
+	out := new(ToolCallingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
+func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 	*out = *in
-	if in.Count != nil {
-		in, out := &in.Count, &out.Count
-		*out = new(int)
+	if in.SampleRatio != nil {
+		in, out := &in.SampleRatio, &out.SampleRatio
+		*out = new(string)
 		**out = **in
 	}
-	if in.LabelSelector != nil {
-		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PreferredNodes != nil {
-		in, out := &in.PreferredNodes, &out.PreferredNodes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Partition != nil {
-		in, out := &in.Partition, &out.Partition
-		*out = new(PartitionSpec)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
+func (in *TracingSpec) DeepCopy() *TracingSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(TracingSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSpec.
-func (in *ResourceSpec) DeepCopy() *ResourceSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficClass) DeepCopyInto(out *TrafficClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficClass.
+func (in *TrafficClass) DeepCopy() *TrafficClass {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSpec)
+	out := new(TrafficClass)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+func (in *TrafficClassesSpec) DeepCopyInto(out *TrafficClassesSpec) {
 	*out = *in
-	if in.PersistentVolume != nil {
-		in, out := &in.PersistentVolume, &out.PersistentVolume
-		*out = new(PersistentVolumeConfig)
+	if in.Interactive != nil {
+		in, out := &in.Interactive, &out.Interactive
+		*out = new(TrafficClass)
 		**out = **in
 	}
-	if in.VectorDB != nil {
-		in, out := &in.VectorDB, &out.VectorDB
-		*out = new(VectorDBConfig)
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(TrafficClass)
+		**out = **in
+	}
+	if in.MaxWaitingRequestsPerReplica != nil {
+		in, out := &in.MaxWaitingRequestsPerReplica, &out.MaxWaitingRequestsPerReplica
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxKVCacheUtilizationPercent != nil {
+		in, out := &in.MaxKVCacheUtilizationPercent, &out.MaxKVCacheUtilizationPercent
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
-func (in *StorageSpec) DeepCopy() *StorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficClassesSpec.
+func (in *TrafficClassesSpec) DeepCopy() *TrafficClassesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSpec)
+	out := new(TrafficClassesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -871,6 +2301,51 @@ func (in *TrainingConfig) DeepCopy() *TrainingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningDeploySpec) DeepCopyInto(out *TuningDeploySpec) {
+	*out = *in
+	if in.Strength != nil {
+		in, out := &in.Strength, &out.Strength
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningDeploySpec.
+func (in *TuningDeploySpec) DeepCopy() *TuningDeploySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningDeploySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningRetentionSpec) DeepCopyInto(out *TuningRetentionSpec) {
+	*out = *in
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepLastN != nil {
+		in, out := &in.KeepLastN, &out.KeepLastN
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningRetentionSpec.
+func (in *TuningRetentionSpec) DeepCopy() *TuningRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TuningSpec) DeepCopyInto(out *TuningSpec) {
 	*out = *in
@@ -889,6 +2364,16 @@ func (in *TuningSpec) DeepCopyInto(out *TuningSpec) {
 		*out = new(DataDestination)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Deploy != nil {
+		in, out := &in.Deploy, &out.Deploy
+		*out = new(TuningDeploySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(TuningRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningSpec.
@@ -901,6 +2386,34 @@ func (in *TuningSpec) DeepCopy() *TuningSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningStatus) DeepCopyInto(out *TuningStatus) {
+	*out = *in
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.GPUUtilization != nil {
+		in, out := &in.GPUUtilization, &out.GPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningStatus.
+func (in *TuningStatus) DeepCopy() *TuningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VectorDBConfig) DeepCopyInto(out *VectorDBConfig) {
 	*out = *in
@@ -916,6 +2429,68 @@ func (in *VectorDBConfig) DeepCopy() *VectorDBConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadComputeSpec) DeepCopyInto(out *WorkloadComputeSpec) {
+	*out = *in
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadComputeSpec.
+func (in *WorkloadComputeSpec) DeepCopy() *WorkloadComputeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadComputeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentitySpec) DeepCopyInto(out *WorkloadIdentitySpec) {
+	*out = *in
+	if in.StorageURLs != nil {
+		in, out := &in.StorageURLs, &out.StorageURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentitySpec.
+func (in *WorkloadIdentitySpec) DeepCopy() *WorkloadIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadStorageSpec) DeepCopyInto(out *WorkloadStorageSpec) {
+	*out = *in
+	if in.NodeDiskSize != nil {
+		in, out := &in.NodeDiskSize, &out.NodeDiskSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EphemeralStorage != nil {
+		in, out := &in.EphemeralStorage, &out.EphemeralStorage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStorageSpec.
+func (in *WorkloadStorageSpec) DeepCopy() *WorkloadStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workspace) DeepCopyInto(out *Workspace) {
 	*out = *in
@@ -933,6 +2508,26 @@ func (in *Workspace) DeepCopyInto(out *Workspace) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Status.DeepCopyInto(&out.Status)
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(WorkloadIdentitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(WorkspaceMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(MetadataPropagationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
@@ -953,6 +2548,32 @@ func (in *Workspace) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceCloneRequest) DeepCopyInto(out *WorkspaceCloneRequest) {
+	*out = *in
+	if in.Adapters != nil {
+		in, out := &in.Adapters, &out.Adapters
+		*out = new([]AdapterSpec)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]AdapterSpec, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceCloneRequest.
+func (in *WorkspaceCloneRequest) DeepCopy() *WorkspaceCloneRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceCloneRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
 	*out = *in
@@ -985,6 +2606,26 @@ func (in *WorkspaceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMaintenanceWindow) DeepCopyInto(out *WorkspaceMaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMaintenanceWindow.
+func (in *WorkspaceMaintenanceWindow) DeepCopy() *WorkspaceMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 	*out = *in
@@ -1005,6 +2646,58 @@ func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 		*out = new(Performance)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ColdStart != nil {
+		in, out := &in.ColdStart, &out.ColdStart
+		*out = new(ColdStartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUUtilization != nil {
+		in, out := &in.GPUUtilization, &out.GPUUtilization
+		*out = new(GPUUtilizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RightSizing != nil {
+		in, out := &in.RightSizing, &out.RightSizing
+		*out = new(RightSizingRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]ZoneNodeCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisioningPlan != nil {
+		in, out := &in.ProvisioningPlan, &out.ProvisioningPlan
+		*out = new(ProvisioningPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(TuningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lineage != nil {
+		in, out := &in.Lineage, &out.Lineage
+		*out = new(LineageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingOperations != nil {
+		in, out := &in.PendingOperations, &out.PendingOperations
+		*out = make([]PendingOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HealthStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceStatus.
@@ -1016,3 +2709,18 @@ func (in *WorkspaceStatus) DeepCopy() *WorkspaceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneNodeCount) DeepCopyInto(out *ZoneNodeCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneNodeCount.
+func (in *ZoneNodeCount) DeepCopy() *ZoneNodeCount {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneNodeCount)
+	in.DeepCopyInto(out)
+	return out
+}
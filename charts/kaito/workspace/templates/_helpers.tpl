@@ -92,6 +92,13 @@ kaito-logging-config
 {{- end -}}
 {{- end -}}
 
+{{/*
+Image verification policy ConfigMap name
+*/}}
+{{- define "kaito.imageVerificationConfigMapName" -}}
+{{- include "kaito.fullname" . }}-image-verification
+{{- end -}}
+
 {{/*
 ClusterRole name
 */}}
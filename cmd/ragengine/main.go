@@ -17,24 +17,30 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
-	"os/signal"
+	"slices"
 	"strconv"
-	"syscall"
-	"time"
 
 	//+kubebuilder:scaffold:imports
 	azurev1beta1 "github.com/Azure/karpenter-provider-azure/pkg/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/system"
 	"knative.dev/pkg/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	runtimecache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -44,17 +50,24 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/ragengine/controllers"
 	"github.com/kaito-project/kaito/pkg/ragengine/webhooks"
 	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
 	karpenterutils "github.com/kaito-project/kaito/pkg/utils/karpenter"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/watchscope"
 	"github.com/kaito-project/kaito/pkg/version"
+	"github.com/kaito-project/kaito/pkg/webhookcert"
 )
 
 const (
 	WebhookServiceName = "WEBHOOK_SERVICE"
 	WebhookServicePort = "WEBHOOK_PORT"
+
+	WebhookCertSecretName = "ragengine-webhook-cert"
 )
 
 var (
@@ -89,6 +102,8 @@ func main() {
 	var kubeClientQPS int = 30
 	var kubeClientBurst int = 50
 	var printVersionAndExit bool
+	var watchNamespaces string
+	var watchNamespaceSelector string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.IntVar(&kubeClientQPS, "kube-client-qps", kubeClientQPS, "the rate of qps to kube-apiserver.")
@@ -98,8 +113,10 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&enableWebhook, "webhook", true,
 		"Enable webhook for controller manager. Default is true.")
-	flag.StringVar(&featureGates, "feature-gates", "vLLM=true", "Enable Kaito feature gates. Default,	vLLM=true.")
+	flag.StringVar(&featureGates, "feature-gates", "", "Enable Kaito feature gates, e.g. disableNodeAutoProvisioning=true.")
 	flag.BoolVar(&printVersionAndExit, "version", false, "Print version and exit.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces the controller watches. Empty watches all namespaces. The release namespace is always watched.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "", "Label selector of additional namespaces the controller watches, e.g. business-unit=finance. Evaluated at startup.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -112,9 +129,15 @@ func main() {
 	}
 	klog.Info("version: ", version.VersionInfo())
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if err := featuregates.ParseAndValidateFeatureGates(featureGates); err != nil {
+		klog.ErrorS(err, "unable to set `feature-gates` flag")
+		exitWithErrorFunc()
+	}
+	if ineffective := featuregates.Ineffective(featuregates.ComponentRAGEngine); len(ineffective) > 0 {
+		klog.InfoS("feature gates have no effect on this manager", "featureGates", ineffective)
+	}
 
-	ctx := withShutdownSignal(context.Background())
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	cfg := ctrl.GetConfigOrDie()
 	cfg.UserAgent = ragengineController
@@ -127,10 +150,29 @@ func main() {
 	}
 	sku.DefaultSKUHandler = skuHandler
 
+	cacheNamespaces, err := watchscope.ManagerNamespaces(context.Background(), cfg, scheme, watchNamespaces, watchNamespaceSelector)
+	if err != nil {
+		klog.ErrorS(err, "unable to set `watch-namespaces` and `watch-namespace-selector` flags")
+		exitWithErrorFunc()
+	}
+	if cacheNamespaces != nil {
+		klog.InfoS("watching a subset of namespaces", "namespaces", slices.Sorted(maps.Keys(cacheNamespaces)))
+	}
+
+	ownedObject, err := labels.NewRequirement(kaitov1beta1.LabelRAGEngineName, selection.Exists, nil)
+	if err != nil {
+		klog.ErrorS(err, "unable to build the owned object label selector")
+		exitWithErrorFunc()
+	}
+	ownedObjectSelector := labels.NewSelector().Add(*ownedObject)
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
+			ExtraHandlers: map[string]http.Handler{
+				"/featuregates": featuregates.Handler(featuregates.ComponentRAGEngine),
+			},
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -147,7 +189,19 @@ func main() {
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
 		Cache: runtimecache.Options{
-			DefaultTransform: runtimecache.TransformStripManagedFields(),
+			DefaultNamespaces: cacheNamespaces,
+			DefaultTransform:  runtimecache.TransformStripManagedFields(),
+			// The controller only reads the Secrets and ServiceAccounts it creates, which are
+			// all labeled with their RAGEngine, so it does not need to watch all of them.
+			ByObject: map[client.Object]runtimecache.ByObject{
+				&corev1.Secret{}:         {Label: ownedObjectSelector},
+				&corev1.ServiceAccount{}: {Label: ownedObjectSelector},
+			},
+		},
+		// The controller only reads the ClusterRoleBindings of its RAGEngines, which are
+		// cluster-scoped and so cannot be restricted to the watched namespaces.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&rbacv1.ClusterRoleBinding{}}},
 		},
 	})
 	if err != nil {
@@ -155,8 +209,7 @@ func main() {
 		exitWithErrorFunc()
 	}
 
-	k8sclient.SetGlobalClient(mgr.GetClient())
-	kClient := k8sclient.GetGlobalClient()
+	kClient := mgr.GetClient()
 
 	ragengineReconciler := controllers.NewRAGEngineReconciler(
 		kClient,
@@ -164,6 +217,16 @@ func main() {
 		log.Log.WithName("controllers").WithName("RAGEngine"),
 		mgr.GetEventRecorderFor("KAITO-RAGEngine-controller"),
 	)
+	// Without the NodeClaim CRD only existing nodes are used until it is installed.
+	ragengineReconciler.NodeClaimCRD = crdwatch.New(cfg, nodeclaim.GVK)
+	if _, err := ragengineReconciler.NodeClaimCRD.Check(context.Background()); err != nil {
+		klog.ErrorS(err, "unable to check whether the NodeClaim CRD is installed")
+		exitWithErrorFunc()
+	}
+	if err := mgr.Add(ragengineReconciler.NodeClaimCRD); err != nil {
+		klog.ErrorS(err, "unable to register the NodeClaim CRD watcher")
+		exitWithErrorFunc()
+	}
 
 	if err = ragengineReconciler.SetupWithManager(mgr); err != nil {
 		klog.ErrorS(err, "unable to create controller", "controller", "RAG Eingine")
@@ -181,23 +244,36 @@ func main() {
 	}
 
 	if enableWebhook {
-		klog.InfoS("starting webhook reconcilers")
 		p, err := strconv.Atoi(os.Getenv(WebhookServicePort))
 		if err != nil {
 			klog.ErrorS(err, "unable to parse the webhook port number")
 			exitWithErrorFunc()
 		}
-		ctx := webhook.WithOptions(ctx, webhook.Options{
+		options := webhook.Options{
 			ServiceName: os.Getenv(WebhookServiceName),
 			Port:        p,
-			SecretName:  "ragengine-webhook-cert",
-		})
-		ctx = sharedmain.WithHealthProbesDisabled(ctx)
-		ctx = sharedmain.WithHADisabled(ctx)
-		go sharedmain.MainWithConfig(ctx, "webhook", ctrl.GetConfigOrDie(), webhooks.NewRAGEngineWebhooks()...)
-
-		// wait 2 seconds to allow reconciling webhookconfiguration and service endpoint.
-		time.Sleep(2 * time.Second)
+			SecretName:  WebhookCertSecretName,
+		}
+		// The webhook serves on every replica, not only on the leader, so it is added
+		// as a runnable that does not need leader election.
+		if err := mgr.Add(&webhookServer{options: options, config: ctrl.GetConfigOrDie(), client: kClient}); err != nil {
+			klog.ErrorS(err, "unable to set up webhook server")
+			exitWithErrorFunc()
+		}
+		// The pod is not Ready, and so receives no admission requests, until the
+		// certificate is issued, trusted by the webhook configuration and served.
+		readiness := &webhookcert.Readiness{
+			Reader:       mgr.GetAPIReader(),
+			Namespace:    system.Namespace(),
+			ServiceName:  options.ServiceName,
+			SecretName:   options.SecretName,
+			Port:         options.Port,
+			WebhookNames: []string{webhooks.ValidationWebhookName},
+		}
+		if err := mgr.AddReadyzCheck("webhook", readiness.Check); err != nil {
+			klog.ErrorS(err, "unable to set up webhook ready check")
+			exitWithErrorFunc()
+		}
 	}
 
 	klog.InfoS("starting manager")
@@ -207,20 +283,27 @@ func main() {
 	}
 }
 
-// withShutdownSignal returns a copy of the parent context that will close if
-// the process receives termination signals.
-func withShutdownSignal(ctx context.Context) context.Context {
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+// webhookServer runs the knative webhook and its certificate reconciler until the
+// manager stops.
+type webhookServer struct {
+	options webhook.Options
+	config  *rest.Config
+	client  client.Client
+}
 
-	nctx, cancel := context.WithCancel(ctx)
+func (w *webhookServer) Start(ctx context.Context) error {
+	klog.InfoS("starting webhook reconcilers")
+	ctx = k8sclient.WithClient(ctx, w.client)
+	ctx = webhook.WithOptions(ctx, w.options)
+	ctx = sharedmain.WithHealthProbesDisabled(ctx)
+	ctx = sharedmain.WithHADisabled(ctx)
+	sharedmain.MainWithConfig(ctx, "webhook", w.config, webhooks.NewRAGEngineWebhooks()...)
+	return nil
+}
 
-	go func() {
-		<-signalChan
-		klog.Info("received shutdown signal")
-		cancel()
-	}()
-	return nctx
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (w *webhookServer) NeedLeaderElection() bool {
+	return false
 }
 
 func setRestConfig(c *rest.Config, kubeClientQPS, kubeClientBurst int) {
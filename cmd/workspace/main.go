@@ -17,11 +17,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"syscall"
-	"time"
+	// Embed the time zone database so the time zones of Workspace maintenance windows
+	// resolve regardless of the base image.
+	_ "time/tzdata"
 
 	//+kubebuilder:scaffold:imports
 	azurev1beta1 "github.com/Azure/karpenter-provider-azure/pkg/apis/v1beta1"
@@ -36,6 +41,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/system"
 	"knative.dev/pkg/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	runtimecache "sigs.k8s.io/controller-runtime/pkg/cache"
@@ -44,31 +50,48 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	gaiev1alpha2 "sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	bicontrollers "github.com/kaito-project/kaito/pkg/batchinference/controllers"
 	autoupgrade "github.com/kaito-project/kaito/pkg/controllers/autoupgrade"
 	drift "github.com/kaito-project/kaito/pkg/controllers/drift"
+	"github.com/kaito-project/kaito/pkg/controllers/gpuutilization"
+	"github.com/kaito-project/kaito/pkg/controllers/inferencemetrics"
 	multiroleinference "github.com/kaito-project/kaito/pkg/controllers/multiroleinference"
+	"github.com/kaito-project/kaito/pkg/controllers/orphangc"
+	"github.com/kaito-project/kaito/pkg/controllers/presetmetadata"
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/imageverify"
 	"github.com/kaito-project/kaito/pkg/inferenceset"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	mmconsts "github.com/kaito-project/kaito/pkg/modelmirror/consts"
 	mmcontrollers "github.com/kaito-project/kaito/pkg/modelmirror/controllers"
 	nodeprovisionmanager "github.com/kaito-project/kaito/pkg/nodeprovision/manager"
+	"github.com/kaito-project/kaito/pkg/preflight"
 	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/breaker"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
 	karpenterutils "github.com/kaito-project/kaito/pkg/utils/karpenter"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/watchscope"
 	"github.com/kaito-project/kaito/pkg/version"
+	"github.com/kaito-project/kaito/pkg/webhookcert"
 	"github.com/kaito-project/kaito/pkg/workspace/controllers"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming/registry"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	"github.com/kaito-project/kaito/pkg/workspace/webhooks"
+	"github.com/kaito-project/kaito/presets/workspace/models"
 )
 
 const (
 	WebhookServiceName = "WEBHOOK_SERVICE"
 	WebhookServicePort = "WEBHOOK_PORT"
+	WebhookSecretName  = "workspace-webhook-cert"
 )
 
 var (
@@ -92,6 +115,7 @@ func init() {
 	utilruntime.Must(helmv2.AddToScheme(scheme))
 	utilruntime.Must(sourcev1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(gaiev1alpha2.Install(scheme))
 
 	//+kubebuilder:scaffold:scheme
 	klog.InitFlags(nil)
@@ -116,6 +140,17 @@ func main() {
 	var defaultStreamingServiceAccount string
 	var modelMirrorDownloadCPU string
 	var modelMirrorDownloadMemory string
+	var imageVerificationPolicy string
+	var watchNamespaces string
+	var watchNamespaceSelector string
+	var dcgmExporterNamespace string
+	var dcgmExporterSelector string
+	var dcgmExporterPort int
+	var modelRegistryMirrors string
+	var modelPullerAttempts int
+	var clusterCIDRs string
+	var publishPresetMetadata bool
+	var bootstrapDevicePlugin bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.IntVar(&kubeClientQPS, "kube-client-qps", kubeClientQPS, "the rate of qps to kube-apiserver.")
@@ -127,7 +162,7 @@ func main() {
 		"Enable webhook for controller manager. Default is true.")
 	flag.StringVar(&featureGates, "feature-gates", "vLLM=true,disableNodeAutoProvisioning=false", "Enable Kaito feature gates. Default: vLLM=true,disableNodeAutoProvisioning=false.")
 	flag.StringVar(&defaultNodeImageFamily, "default-node-image-family", "", "Default node image family annotation for generated NodeClaims. Supported values: azurelinux, ubuntu. Empty means ubuntu. Unsupported values cause startup failure.")
-	flag.StringVar(&nodeProvisionerType, "node-provisioner", "azure-gpu-provisioner", "Node provisioner type. Supported values: azure-gpu-provisioner, karpenter, byo. Default: azure-gpu-provisioner.")
+	flag.StringVar(&nodeProvisionerType, "node-provisioner", "azure-gpu-provisioner", "Node provisioner type. Supported values: azure-gpu-provisioner, karpenter, byo, cluster-autoscaler, or the name of a node provisioner plugin built into the controller. Default: azure-gpu-provisioner, which falls back to cluster-autoscaler when the NodeClaim CRD is not installed.")
 	flag.StringVar(&karpenterNodeClassGroup, "karpenter-node-class-group", "karpenter.azure.com", "Karpenter NodeClass API group. Only used when node-provisioner=karpenter.")
 	flag.StringVar(&karpenterNodeClassKind, "karpenter-node-class-kind", "AKSNodeClass", "Karpenter NodeClass API kind. Only used when node-provisioner=karpenter.")
 	flag.StringVar(&karpenterNodeClassVersion, "karpenter-node-class-version", "v1beta1", "Karpenter NodeClass API version. Only used when node-provisioner=karpenter.")
@@ -137,6 +172,17 @@ func main() {
 	flag.StringVar(&defaultStreamingServiceAccount, "default-streaming-service-account", "", "Default ServiceAccount for streaming inference pods.")
 	flag.StringVar(&modelMirrorDownloadCPU, "model-mirror-download-cpu", "", "CPU request==limit for the ModelMirror download Job container. Empty uses the built-in default (3).")
 	flag.StringVar(&modelMirrorDownloadMemory, "model-mirror-download-memory", "", "Memory request==limit for the ModelMirror download Job container. Empty uses the built-in default (8Gi).")
+	flag.StringVar(&imageVerificationPolicy, "image-verification-policy", "", "Path to the signature policy used to verify preset images. Only used when the imageVerification feature gate is enabled.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces the controller watches. Empty watches all namespaces. The release namespace is always watched.")
+	flag.StringVar(&dcgmExporterNamespace, "dcgm-exporter-namespace", gpuutilization.DefaultExporterNamespace, "Namespace of the DCGM exporter pods. Only used when the gpuUtilizationCollection feature gate is enabled.")
+	flag.StringVar(&dcgmExporterSelector, "dcgm-exporter-selector", gpuutilization.DefaultExporterSelector, "Label selector of the DCGM exporter pods. Only used when the gpuUtilizationCollection feature gate is enabled.")
+	flag.StringVar(&modelRegistryMirrors, "model-registry-mirrors", "", "Comma separated registries, optionally with a repository prefix, that mirror the preset images. The model weights downloader tries them in order before the registry of the preset.")
+	flag.StringVar(&clusterCIDRs, "cluster-cidrs", "", "Comma separated pod and service CIDRs of the cluster. They are added to NO_PROXY of the workloads of workspaces that set presetOptions.proxy.")
+	flag.IntVar(&modelPullerAttempts, "model-puller-attempts", manifests.ModelPullerDefaults.Attempts, "How many times the model weights downloader tries the registry mirrors and the registry of the preset before it fails.")
+	flag.IntVar(&dcgmExporterPort, "dcgm-exporter-port", gpuutilization.DefaultExporterPort, "Port the DCGM exporter pods serve their metrics on. Only used when the gpuUtilizationCollection feature gate is enabled.")
+	flag.BoolVar(&bootstrapDevicePlugin, "bootstrap-device-plugin", false, "Label BYO nodes that have an NVIDIA GPU but no nvidia.com/gpu capacity with kaito.sh/nvidia-device-plugin-bootstrap=true, so the bootstrap DaemonSets of the chart install the device plugin, and wait until they advertise their GPUs.")
+	flag.BoolVar(&publishPresetMetadata, "publish-preset-metadata", false, "Publish the preset metadata to the kaito-preset-metadata ConfigMap in the release namespace, to be used as params by ValidatingAdmissionPolicies.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "", "Label selector of additional namespaces the controller watches, e.g. business-unit=finance. Evaluated at startup.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -153,36 +199,41 @@ func main() {
 		klog.ErrorS(err, "unable to set `feature-gates` flag")
 		exitWithErrorFunc()
 	}
+	if ineffective := featuregates.Ineffective(featuregates.ComponentWorkspace); len(ineffective) > 0 {
+		klog.InfoS("feature gates have no effect on this manager", "featureGates", ineffective)
+	}
 
-	skuHandler, err := sku.GetSKUHandler()
+	if featuregates.FeatureGates[consts.FeatureFlagImageVerification] {
+		policy, err := imageverify.LoadPolicy(imageVerificationPolicy)
+		if err != nil {
+			klog.ErrorS(err, "unable to load `image-verification-policy`")
+			exitWithErrorFunc()
+		}
+		imageverify.SetDefault(imageverify.NewVerifier(policy, imageverify.NewRegistry()))
+	}
+
+	mirrors, err := manifests.ParseRegistryMirrors(modelRegistryMirrors)
 	if err != nil {
-		klog.ErrorS(err, "unable to initialize SKU handler")
+		klog.ErrorS(err, "unable to set `model-registry-mirrors` flag")
 		exitWithErrorFunc()
 	}
-	sku.DefaultSKUHandler = skuHandler
-
-	// ModelStreaming requires ModelMirror
-	if featuregates.FeatureGates[consts.FeatureFlagModelStreaming] && !featuregates.FeatureGates[consts.FeatureFlagModelMirror] {
-		klog.ErrorS(fmt.Errorf("ModelStreaming feature gate requires ModelMirror to be enabled"),
-			"set --feature-gates=ModelMirror=true,ModelStreaming=true")
+	manifests.ModelPullerDefaults.RegistryMirrors = mirrors
+	if modelPullerAttempts < 1 {
+		klog.ErrorS(nil, "`model-puller-attempts` flag must be at least 1", "value", modelPullerAttempts)
+		exitWithErrorFunc()
+	}
+	manifests.ModelPullerDefaults.Attempts = modelPullerAttempts
+	if manifests.ClusterCIDRs, err = manifests.ParseCIDRs(clusterCIDRs); err != nil {
+		klog.ErrorS(err, "unable to set `cluster-cidrs` flag")
 		exitWithErrorFunc()
 	}
 
-	// Expose the resolved provisioner type for downstream scheduling logic.
-	consts.ActiveNodeProvisioner = nodeProvisionerType
-
-	// Sync feature gate internal state based on --node-provisioner for downstream consumers.
-	switch nodeProvisionerType {
-	case consts.NodeProvisionerBYO:
-		featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = true
-	case consts.NodeProvisionerKarpenter:
-		featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = false
-	case consts.NodeProvisionerAzureGPU:
-		featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = false
-	default:
-		klog.ErrorS(fmt.Errorf("unsupported node provisioner type %q", nodeProvisionerType), "unable to set --node-provisioner")
+	skuHandler, err := sku.GetSKUHandler()
+	if err != nil {
+		klog.ErrorS(err, "unable to initialize SKU handler")
 		exitWithErrorFunc()
 	}
+	sku.DefaultSKUHandler = skuHandler
 
 	if defaultNodeImageFamily == "" {
 		defaultNodeImageFamily = consts.NodeImageFamilyUbuntu
@@ -203,10 +254,66 @@ func main() {
 	cfg.UserAgent = workspaceController
 	setRestConfig(cfg, kubeClientQPS, kubeClientBurst)
 
+	// Clusters without Karpenter scale GPU node groups with the cluster autoscaler. Fall back
+	// to it when the gpu-provisioner is selected but its NodeClaim CRD is not installed, since
+	// the controller could not watch NodeClaims.
+	// Other provisioners that need the CRD only use existing nodes until it is installed.
+	nodeClaimCRD := crdwatch.New(cfg, nodeclaim.GVK)
+	nodeClaimCRDInstalled, err := nodeClaimCRD.Check(ctx)
+	if err != nil {
+		klog.ErrorS(err, "unable to check whether the NodeClaim CRD is installed")
+		exitWithErrorFunc()
+	}
+	if nodeProvisionerType == consts.NodeProvisionerAzureGPU {
+		if !nodeClaimCRDInstalled {
+			klog.InfoS("NodeClaim CRD is not installed, scaling GPU node groups with the cluster autoscaler",
+				"requestedNodeProvisioner", nodeProvisionerType, "nodeProvisioner", consts.NodeProvisionerClusterAutoscaler)
+			nodeProvisionerType = consts.NodeProvisionerClusterAutoscaler
+		}
+	}
+
+	// Expose the resolved provisioner type for downstream scheduling logic.
+	consts.ActiveNodeProvisioner = nodeProvisionerType
+
+	// Sync feature gate internal state based on --node-provisioner for downstream consumers.
+	switch nodeProvisionerType {
+	case consts.NodeProvisionerBYO:
+		featuregates.Override(consts.FeatureFlagDisableNodeAutoProvisioning, true, "node-provisioner")
+	case consts.NodeProvisionerKarpenter:
+		featuregates.Override(consts.FeatureFlagDisableNodeAutoProvisioning, false, "node-provisioner")
+	case consts.NodeProvisionerAzureGPU, consts.NodeProvisionerClusterAutoscaler:
+		featuregates.Override(consts.FeatureFlagDisableNodeAutoProvisioning, false, "node-provisioner")
+	default:
+		if nodeprovisionmanager.IsPlugin(nodeProvisionerType) {
+			featuregates.Override(consts.FeatureFlagDisableNodeAutoProvisioning, false, "node-provisioner")
+			break
+		}
+		klog.ErrorS(fmt.Errorf("unsupported node provisioner type %q", nodeProvisionerType), "unable to set --node-provisioner")
+		exitWithErrorFunc()
+	}
+
+	// The preflight checks are registered once the manager exists; the runner is created
+	// first so that the metrics server can serve its results.
+	preflightRunner := &preflight.Runner{Interval: preflight.DefaultInterval}
+
+	cacheNamespaces, err := watchscope.ManagerNamespaces(ctx, cfg, scheme, watchNamespaces, watchNamespaceSelector)
+	if err != nil {
+		klog.ErrorS(err, "unable to set `watch-namespaces` and `watch-namespace-selector` flags")
+		exitWithErrorFunc()
+	}
+	if cacheNamespaces != nil {
+		klog.InfoS("watching a subset of namespaces", "namespaces", slices.Sorted(maps.Keys(cacheNamespaces)))
+	}
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
+			ExtraHandlers: map[string]http.Handler{
+				"/featuregates": featuregates.Handler(featuregates.ComponentWorkspace),
+				"/preflight":    preflightRunner,
+				"/presets":      models.PresetsHandler(),
+			},
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -223,7 +330,8 @@ func main() {
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
 		Cache: runtimecache.Options{
-			DefaultTransform: runtimecache.TransformStripManagedFields(),
+			DefaultNamespaces: cacheNamespaces,
+			DefaultTransform:  runtimecache.TransformStripManagedFields(),
 		},
 	})
 	if err != nil {
@@ -231,15 +339,13 @@ func main() {
 		exitWithErrorFunc()
 	}
 
-	k8sclient.SetGlobalClient(mgr.GetClient())
-	kClient := k8sclient.GetGlobalClient()
+	kClient := mgr.GetClient()
 
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		klog.ErrorS(err, "unable to create kubernetes client")
 		exitWithErrorFunc()
 	}
-	k8sclient.SetGlobalClientGoClient(kubeClient)
 
 	// Create a direct (non-cached) client for provisioner initialization.
 	// This is necessary because nodeProvisioner.Start() runs before mgr.Start(),
@@ -254,7 +360,7 @@ func main() {
 
 	// Select and initialize the node provisioner based on feature gates.
 	recorder := mgr.GetEventRecorderFor("KAITO-Workspace-controller")
-	nodeProvisioner := nodeprovisionmanager.NewNodeProvisioner(nodeprovisionmanager.ProvisionerConfig{
+	nodeProvisioner, err := nodeprovisionmanager.NewNodeProvisioner(nodeprovisionmanager.ProvisionerConfig{
 		KClient:                kClient,
 		DirectClient:           directClient,
 		Recorder:               recorder,
@@ -264,7 +370,13 @@ func main() {
 		NodeClassKind:          karpenterNodeClassKind,
 		NodeClassVersion:       karpenterNodeClassVersion,
 		NodeClassResourceName:  karpenterNodeClassResourceName,
+		NodeClaimCRD:           nodeClaimCRD,
+		BootstrapDevicePlugin:  bootstrapDevicePlugin,
 	})
+	if err != nil {
+		klog.ErrorS(err, "unable to create node provisioner")
+		exitWithErrorFunc()
+	}
 	klog.InfoS("Node provisioner selected", "name", nodeProvisioner.Name())
 	if err := nodeProvisioner.Start(ctx); err != nil {
 		klog.ErrorS(err, "failed to start node provisioner")
@@ -289,7 +401,21 @@ func main() {
 		log.Log.WithName("controllers").WithName("Workspace"),
 		recorder,
 		nodeProvisioner,
+		kubeClient,
 	)
+	workspaceReconciler.NodeClaimCRD = nodeClaimCRD
+	// The PodGroups of a gang scheduler are watched once its CRD is installed.
+	for _, scheduler := range []string{kaitov1beta1.GangSchedulerCoscheduling, kaitov1beta1.GangSchedulerVolcano} {
+		podGroupCRD := crdwatch.New(cfg, manifests.PodGroupGVK(scheduler))
+		if _, err := podGroupCRD.Check(ctx); err != nil {
+			klog.ErrorS(err, "unable to check whether the PodGroup CRD is installed, checking again later", "scheduler", scheduler)
+		}
+		if err := mgr.Add(podGroupCRD); err != nil {
+			klog.ErrorS(err, "unable to register the PodGroup CRD watcher", "scheduler", scheduler)
+			exitWithErrorFunc()
+		}
+		workspaceReconciler.PodGroupCRDs = append(workspaceReconciler.PodGroupCRDs, podGroupCRD)
+	}
 
 	if err = workspaceReconciler.SetupWithManager(mgr); err != nil {
 		klog.ErrorS(err, "unable to create controller", "controller", "Workspace")
@@ -316,9 +442,16 @@ func main() {
 				mgr.GetEventRecorderFor("drift-controller"),
 				nodeProvisioner,
 			)
-			if err = driftReconciler.SetupWithManager(mgr); err != nil {
-				klog.ErrorS(err, "unable to create controller", "controller", "Drift")
-				exitWithErrorFunc()
+			// The drift controller watches NodeClaims, so it is only set up once their CRD is installed.
+			if nodeClaimCRD.Available() {
+				if err = driftReconciler.SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "unable to create controller", "controller", "Drift")
+					exitWithErrorFunc()
+				}
+			} else {
+				nodeClaimCRD.OnAvailable(func(context.Context) error {
+					return driftReconciler.SetupWithManager(mgr)
+				})
 			}
 		}
 
@@ -334,6 +467,84 @@ func main() {
 		}
 	}
 
+	// Register FleetUpgradeRunner for standalone Workspaces enrolled in a runtime channel.
+	if featuregates.FeatureGates[consts.FeatureFlagEnableBaseImageAutoUpgrade] {
+		if err = mgr.Add(&autoupgrade.FleetUpgradeRunner{
+			Client:        kClient,
+			Interval:      autoupgrade.DefaultInterval,
+			HealthTimeout: autoupgrade.DefaultUpgradeHealthTimeout,
+		}); err != nil {
+			klog.ErrorS(err, "unable to register FleetUpgradeRunner")
+			exitWithErrorFunc()
+		}
+	}
+
+	// OrphanSweeper removes generated objects whose Workspace or InferenceSet is gone.
+	if err = mgr.Add(&orphangc.OrphanSweeper{
+		Client:      kClient,
+		Interval:    orphangc.DefaultInterval,
+		GracePeriod: orphangc.DefaultGracePeriod,
+		SweepInferencePools: featuregates.FeatureGates[consts.FeatureFlagEnableInferenceSetController] &&
+			featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension],
+	}); err != nil {
+		klog.ErrorS(err, "unable to register OrphanSweeper")
+		exitWithErrorFunc()
+	}
+
+	// PresetMetadataPublisher exposes the preset metadata to ValidatingAdmissionPolicies.
+	if publishPresetMetadata {
+		namespace, err := utils.GetReleaseNamespace()
+		if err != nil {
+			klog.ErrorS(err, "failed to get release namespace")
+			exitWithErrorFunc()
+		}
+		if err = mgr.Add(&presetmetadata.Publisher{Client: kClient, Namespace: namespace}); err != nil {
+			klog.ErrorS(err, "unable to register PresetMetadataPublisher")
+			exitWithErrorFunc()
+		}
+	}
+
+	// GPUUtilizationCollector rolls up the DCGM metrics of workspace nodes.
+	if featuregates.FeatureGates[consts.FeatureFlagGPUUtilizationCollection] {
+		exporterPods, err := gpuutilization.NewExporterCache(cfg, scheme, dcgmExporterNamespace, dcgmExporterSelector)
+		if err != nil {
+			klog.ErrorS(err, "unable to create the DCGM exporter cache")
+			exitWithErrorFunc()
+		}
+		if err = mgr.Add(exporterPods); err != nil {
+			klog.ErrorS(err, "unable to register the DCGM exporter cache")
+			exitWithErrorFunc()
+		}
+		if err = mgr.Add(&gpuutilization.Collector{
+			Client:            kClient,
+			ExporterPods:      exporterPods,
+			ExporterNamespace: dcgmExporterNamespace,
+			ExporterSelector:  dcgmExporterSelector,
+			ExporterPort:      dcgmExporterPort,
+			Interval:          gpuutilization.DefaultInterval,
+
+			LowUtilizationThreshold: gpuutilization.DefaultLowUtilizationThreshold,
+			RightSizingWindow:       gpuutilization.DefaultRightSizingWindow,
+			SKUHandler:              skuHandler,
+			Recorder:                mgr.GetEventRecorderFor("gpu-utilization-collector"),
+		}); err != nil {
+			klog.ErrorS(err, "unable to register GPUUtilizationCollector")
+			exitWithErrorFunc()
+		}
+	}
+
+	// InferenceMetricsCollector republishes the vLLM metrics of workspace pods per workspace.
+	if featuregates.FeatureGates[consts.FeatureFlagInferenceMetricsCollection] {
+		if err = mgr.Add(&inferencemetrics.Collector{
+			Client:   kClient,
+			Port:     int(consts.PortInferenceServer),
+			Interval: inferencemetrics.DefaultInterval,
+		}); err != nil {
+			klog.ErrorS(err, "unable to register InferenceMetricsCollector")
+			exitWithErrorFunc()
+		}
+	}
+
 	// MultiRoleInference controller — requires enableMultiRoleInferenceController.
 	if featuregates.FeatureGates[consts.FeatureFlagEnableMultiRoleInferenceController] {
 		mriReconciler := multiroleinference.NewMultiRoleInferenceReconciler(
@@ -370,35 +581,92 @@ func main() {
 		}
 	}
 
-	//+kubebuilder:scaffold:builder
+	// BatchInference controller — requires batchInference feature gate.
+	if featuregates.FeatureGates[consts.FeatureFlagBatchInference] {
+		biReconciler := bicontrollers.NewBatchInferenceReconciler(
+			kClient,
+			log.Log.WithName("controllers").WithName("BatchInference"),
+		)
+		if err = biReconciler.SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "BatchInference")
+			exitWithErrorFunc()
+		}
+	}
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		klog.ErrorS(err, "unable to set up health check")
+	preflightRunner.Checks = preflightChecks(mgr.GetAPIReader(), nodeProvisionerType,
+		karpenterNodeClassResourceName+"."+karpenterNodeClassGroup, enableWebhook)
+	if err := mgr.Add(preflightRunner); err != nil {
+		klog.ErrorS(err, "unable to register preflight checks")
 		exitWithErrorFunc()
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		klog.ErrorS(err, "unable to set up ready check")
+
+	// Enables node auto-provisioning and the NodeClaim watches once the NodeClaim CRD is installed.
+	if err := mgr.Add(nodeClaimCRD); err != nil {
+		klog.ErrorS(err, "unable to register the NodeClaim CRD watcher")
 		exitWithErrorFunc()
 	}
 
+	//+kubebuilder:scaffold:builder
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		klog.ErrorS(err, "unable to set up health check")
+		exitWithErrorFunc()
+	}
+	// The pod is not Ready, and so receives no admission requests, until the webhook
+	// certificate is issued, trusted by the webhook configurations and served.
+	readyzCheck := healthz.Ping
+	var webhookOptions webhook.Options
 	if enableWebhook {
-		klog.InfoS("starting webhook reconcilers")
 		p, err := strconv.Atoi(os.Getenv(WebhookServicePort))
 		if err != nil {
 			klog.ErrorS(err, "unable to parse the webhook port number")
 			exitWithErrorFunc()
 		}
-		ctx := webhook.WithOptions(ctx, webhook.Options{
+		webhookOptions = webhook.Options{
 			ServiceName: os.Getenv(WebhookServiceName),
 			Port:        p,
-			SecretName:  "workspace-webhook-cert",
-		})
-		ctx = sharedmain.WithHealthProbesDisabled(ctx)
-		ctx = sharedmain.WithHADisabled(ctx)
-		go sharedmain.MainWithConfig(ctx, "webhook", ctrl.GetConfigOrDie(), webhooks.NewControllerWebhooks()...)
+			SecretName:  WebhookSecretName,
+		}
+		readiness := &webhookcert.Readiness{
+			Reader:       mgr.GetAPIReader(),
+			Namespace:    system.Namespace(),
+			ServiceName:  webhookOptions.ServiceName,
+			SecretName:   webhookOptions.SecretName,
+			Port:         webhookOptions.Port,
+			WebhookNames: webhooks.ValidationWebhookNames(),
+		}
+		readyzCheck = readiness.Check
+	}
+	if err := mgr.AddReadyzCheck("readyz", readyzCheck); err != nil {
+		klog.ErrorS(err, "unable to set up ready check")
+		exitWithErrorFunc()
+	}
+	// Each external dependency is reported at /readyz/dependency-<name>. The pod readiness
+	// probe only checks /readyz/readyz, so an unavailable cloud API does not take the
+	// webhook out of service.
+	for _, b := range breaker.All() {
+		if err := mgr.AddReadyzCheck("dependency-"+b.Name(), b.Check); err != nil {
+			klog.ErrorS(err, "unable to set up dependency check", "dependency", b.Name())
+			exitWithErrorFunc()
+		}
+	}
 
-		// wait 2 seconds to allow reconciling webhookconfiguration and service endpoint.
-		time.Sleep(2 * time.Second)
+	// Like the dependency checks, failed preflight checks are reported at
+	// /readyz/preflight-<name> without taking the pod out of service.
+	for _, check := range preflightRunner.Checks {
+		if err := mgr.AddReadyzCheck("preflight-"+check.Name, preflightRunner.Checker(check.Name)); err != nil {
+			klog.ErrorS(err, "unable to set up preflight check", "check", check.Name)
+			exitWithErrorFunc()
+		}
+	}
+
+	if enableWebhook {
+		// The webhook serves on every replica, not only on the leader, so it is added
+		// as a runnable that does not need leader election.
+		if err := mgr.Add(&webhookServer{options: webhookOptions, config: ctrl.GetConfigOrDie(), client: kClient, clientGoClient: kubeClient}); err != nil {
+			klog.ErrorS(err, "unable to set up webhook server")
+			exitWithErrorFunc()
+		}
 	}
 
 	klog.InfoS("starting manager")
@@ -408,6 +676,62 @@ func main() {
 	}
 }
 
+// webhookServer runs the knative webhook and its certificate reconciler until the
+// manager stops. With HA disabled every replica reconciles the certificate and the
+// webhook configurations; concurrent updates conflict and are retried, so no replica
+// depends on the leader.
+type webhookServer struct {
+	options        webhook.Options
+	config         *rest.Config
+	client         client.Client
+	clientGoClient kubernetes.Interface
+}
+
+func (w *webhookServer) Start(ctx context.Context) error {
+	klog.InfoS("starting webhook reconcilers")
+	ctx = k8sclient.WithClientGoClient(k8sclient.WithClient(ctx, w.client), w.clientGoClient)
+	ctx = webhook.WithOptions(ctx, w.options)
+	ctx = sharedmain.WithHealthProbesDisabled(ctx)
+	ctx = sharedmain.WithHADisabled(ctx)
+	sharedmain.MainWithConfig(ctx, "webhook", w.config, webhooks.NewControllerWebhooks()...)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (w *webhookServer) NeedLeaderElection() bool {
+	return false
+}
+
+// preflightChecks returns the prerequisites of the workspace manager to verify.
+func preflightChecks(reader client.Reader, nodeProvisionerType, nodeClassCRD string, enableWebhook bool) []preflight.Check {
+	crds := []string{"workspaces.kaito.sh"}
+	if featuregates.FeatureGates[consts.FeatureFlagEnableInferenceSetController] {
+		crds = append(crds, "inferencesets.kaito.sh")
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagEnableMultiRoleInferenceController] {
+		crds = append(crds, "multiroleinferences.kaito.sh")
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagModelMirror] {
+		crds = append(crds, "modelmirrors.kaito.sh")
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagBatchInference] {
+		crds = append(crds, "batchinferences.kaito.sh")
+	}
+	checks := []preflight.Check{
+		preflight.CRDsCheck(reader, crds...),
+		preflight.NodeProvisionerCheck(reader, nodeProvisionerType, nodeClassCRD),
+		preflight.CloudProviderCheck(),
+	}
+	if enableWebhook {
+		if namespace, err := utils.GetReleaseNamespace(); err != nil {
+			klog.ErrorS(err, "unable to resolve the release namespace, skipping the webhook certificate preflight check")
+		} else {
+			checks = append(checks, preflight.WebhookCertCheck(reader, namespace, WebhookSecretName, os.Getenv(WebhookServiceName)))
+		}
+	}
+	return append(checks, preflight.GPUCapacityCheck(reader, nodeProvisionerType))
+}
+
 // withShutdownSignal returns a copy of the parent context that will close if
 // the process receives termination signals.
 func withShutdownSignal(ctx context.Context) context.Context {
@@ -0,0 +1,252 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Streams one shard of an OpenAI batch format dataset through a KAITO workspace.
+
+Every input line is a {"custom_id", "url", "body"} request. The shard takes the lines whose
+number modulo --shards equals its Job completion index, posts them to the workspace with
+--concurrency requests in flight and appends {"id", "custom_id", "response", "error"} records
+to <output>/output-<index>.jsonl. A restarted shard skips the custom_ids already written, so a
+retry resumes where the previous attempt stopped. Progress is kept in the termination message
+for the controller to report in the BatchInference status.
+
+Only the standard library is used, so the worker image is a stock python image with this script.
+"""
+
+import argparse
+import json
+import os
+import random
+import re
+import sys
+import threading
+import time
+import urllib.error
+import urllib.request
+from concurrent.futures import ThreadPoolExecutor
+
+DEFAULT_URL = "/v1/chat/completions"
+# Requests may only address the OpenAI compatible API of the workspace.
+URL_RE = re.compile(r"^/v1/[a-z][a-z/_-]*$")
+RETRYABLE_STATUS = {408, 429, 500, 502, 503, 504}
+TERMINATION_LOG = "/dev/termination-log"
+PROGRESS_INTERVAL = 10
+
+
+class ShardAborted(Exception):
+    """The workspace is unreachable; the Job retries the shard."""
+
+
+class Shard:
+    def __init__(self, out, processed, failed):
+        self.out = out
+        self.lock = threading.Lock()
+        self.processed = processed
+        self.failed = failed
+        self.abort = None
+
+    def record(self, n, custom_id, status, body, error):
+        record = {
+            "id": f"batch_req_{n}",
+            "custom_id": custom_id,
+            "response": None if status is None else {"status_code": status, "body": body},
+            "error": None if error is None else {"message": error},
+        }
+        with self.lock:
+            self.out.write(json.dumps(record) + "\n")
+            self.out.flush()
+            self.processed += 1
+            if error is not None:
+                self.failed += 1
+
+    def report(self):
+        with self.lock:
+            progress = {"processed": self.processed, "failed": self.failed}
+        try:
+            with open(TERMINATION_LOG, "w") as f:
+                json.dump(progress, f)
+        except OSError:
+            pass
+        return progress
+
+
+def parse_args():
+    parser = argparse.ArgumentParser()
+    parser.add_argument("--endpoint", required=True)
+    parser.add_argument("--input", required=True)
+    parser.add_argument("--output", required=True)
+    parser.add_argument("--shards", type=int, default=1)
+    parser.add_argument("--concurrency", type=int, default=8)
+    parser.add_argument("--max-retries", type=int, default=3)
+    parser.add_argument("--request-timeout", type=int, default=600)
+    return parser.parse_args()
+
+
+def input_files(path):
+    if not os.path.isdir(path):
+        return [path]
+    return sorted(
+        os.path.join(path, name)
+        for name in os.listdir(path)
+        if name.endswith(".jsonl") and os.path.isfile(os.path.join(path, name))
+    )
+
+
+def shard_lines(files, shards, index):
+    """Yields the (line number, line) pairs of the shard, numbering non-empty lines across files."""
+    n = 0
+    for name in files:
+        with open(name, encoding="utf-8") as f:
+            for line in f:
+                line = line.strip()
+                if not line:
+                    continue
+                if n % shards == index:
+                    yield n, line
+                n += 1
+
+
+def load_done(path):
+    """Returns the custom_ids already written by earlier attempts and their counts."""
+    done, failed = set(), 0
+    if not os.path.exists(path):
+        return done, 0
+    with open(path, encoding="utf-8") as f:
+        for line in f:
+            try:
+                record = json.loads(line)
+            except ValueError:
+                # A torn write of an attempt that was killed; the request is sent again.
+                continue
+            done.add(record.get("custom_id"))
+            if record.get("error") is not None:
+                failed += 1
+    return done, failed
+
+
+def backoff(attempt):
+    time.sleep(min(60, 2**attempt) + random.random())
+
+
+def call(method, url, body, timeout):
+    data = None if body is None else json.dumps(body).encode("utf-8")
+    req = urllib.request.Request(url, data=data, method=method, headers={"Content-Type": "application/json"})
+    try:
+        with urllib.request.urlopen(req, timeout=timeout) as resp:
+            return resp.status, resp.read()
+    except urllib.error.HTTPError as e:
+        return e.code, e.read()
+
+
+def decode(payload):
+    try:
+        return json.loads(payload)
+    except ValueError:
+        return payload.decode("utf-8", errors="replace")
+
+
+def served_model(args):
+    """Returns the first model served by the workspace, used for requests without a model."""
+    for attempt in range(args.max_retries + 1):
+        try:
+            status, payload = call("GET", args.endpoint + "/v1/models", None, args.request_timeout)
+            if status == 200:
+                return json.loads(payload)["data"][0]["id"]
+        except (OSError, ValueError, KeyError, IndexError):
+            pass
+        if attempt < args.max_retries:
+            backoff(attempt)
+    raise ShardAborted(f"unable to list the models served by {args.endpoint}")
+
+
+def process(args, shard, model, n, custom_id, path, body):
+    body = dict(body)
+    body.setdefault("model", model)
+    status, payload, error = None, b"", None
+    for attempt in range(args.max_retries + 1):
+        if shard.abort is not None:
+            return
+        try:
+            status, payload = call("POST", args.endpoint + path, body, args.request_timeout)
+            error = None if status < 400 else f"HTTP {status}"
+            if status not in RETRYABLE_STATUS:
+                break
+        except TimeoutError:
+            status, error = None, f"timed out after {args.request_timeout}s"
+        except OSError as e:
+            if attempt == args.max_retries:
+                raise ShardAborted(f"request {custom_id}: {e}") from e
+        if attempt < args.max_retries:
+            backoff(attempt)
+    shard.record(n, custom_id, status, decode(payload) if status is not None else None, error)
+
+
+def main():
+    args = parse_args()
+    index = int(os.environ.get("JOB_COMPLETION_INDEX", "0"))
+    os.makedirs(args.output, exist_ok=True)
+    out_path = os.path.join(args.output, f"output-{index}.jsonl")
+    done, failed = load_done(out_path)
+
+    with open(out_path, "a", encoding="utf-8") as out:
+        shard = Shard(out, len(done), failed)
+        stop = threading.Event()
+
+        def reporter():
+            while not stop.wait(PROGRESS_INTERVAL):
+                print(json.dumps(shard.report()), flush=True)
+
+        threading.Thread(target=reporter, daemon=True).start()
+        try:
+            model = served_model(args)
+            slots = threading.BoundedSemaphore(args.concurrency * 2)
+
+            def finished(future):
+                slots.release()
+                if future.exception() is not None and shard.abort is None:
+                    shard.abort = future.exception()
+
+            with ThreadPoolExecutor(max_workers=args.concurrency) as pool:
+                for n, line in shard_lines(input_files(args.input), args.shards, index):
+                    if shard.abort is not None:
+                        break
+                    try:
+                        request = json.loads(line)
+                    except ValueError:
+                        request = None
+                    if not isinstance(request, dict) or not isinstance(request.get("body"), dict):
+                        custom_id = f"line-{n}"
+                        if custom_id not in done:
+                            shard.record(n, custom_id, None, None, "not a JSON object with a JSON object body")
+                        continue
+                    custom_id = str(request.get("custom_id") or f"line-{n}")
+                    if custom_id in done:
+                        continue
+                    path = request.get("url") or DEFAULT_URL
+                    if not isinstance(path, str) or not URL_RE.match(path):
+                        shard.record(n, custom_id, None, None, "url must be an OpenAI API path such as /v1/chat/completions")
+                        continue
+                    slots.acquire()
+                    pool.submit(process, args, shard, model, n, custom_id, path, request["body"]).add_done_callback(finished)
+            if shard.abort is not None:
+                raise shard.abort
+        except ShardAborted as e:
+            print(f"shard {index} aborted: {e}", file=sys.stderr)
+            sys.exit(1)
+        finally:
+            stop.set()
+            print(json.dumps(shard.report()), flush=True)
+
+
+if __name__ == "__main__":
+    main()
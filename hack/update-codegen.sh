@@ -0,0 +1,62 @@
+#!/usr/bin/env bash
+
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Generates the typed clientset, listers and informers in pkg/client from the
+# +genclient types in api/v1beta1. Run it through `make generate-client`.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+REPO_ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+BIN_DIR=${BIN_DIR:-${REPO_ROOT}/bin}
+MODULE=github.com/kaito-project/kaito
+INPUT_PKG=${MODULE}/api/v1beta1
+OUTPUT_PKG=${MODULE}/pkg/client
+OUTPUT_DIR=${REPO_ROOT}/pkg/client
+HEADER=${REPO_ROOT}/hack/boilerplate.go.txt
+
+for bin in client-gen lister-gen informer-gen; do
+  if [[ ! -x "${BIN_DIR}/${bin}" ]]; then
+    echo "${BIN_DIR}/${bin} not found, run make code-generator first"
+    exit 1
+  fi
+done
+
+rm -rf "${OUTPUT_DIR}/clientset" "${OUTPUT_DIR}/listers" "${OUTPUT_DIR}/informers"
+
+cd "${REPO_ROOT}"
+
+"${BIN_DIR}/client-gen" \
+  --go-header-file "${HEADER}" \
+  --clientset-name versioned \
+  --input-base "" \
+  --input "${INPUT_PKG}" \
+  --output-pkg "${OUTPUT_PKG}/clientset" \
+  --output-dir "${OUTPUT_DIR}/clientset"
+
+"${BIN_DIR}/lister-gen" \
+  --go-header-file "${HEADER}" \
+  --output-pkg "${OUTPUT_PKG}/listers" \
+  --output-dir "${OUTPUT_DIR}/listers" \
+  "${INPUT_PKG}"
+
+"${BIN_DIR}/informer-gen" \
+  --go-header-file "${HEADER}" \
+  --versioned-clientset-package "${OUTPUT_PKG}/clientset/versioned" \
+  --listers-package "${OUTPUT_PKG}/listers" \
+  --output-pkg "${OUTPUT_PKG}/informers" \
+  --output-dir "${OUTPUT_DIR}/informers" \
+  "${INPUT_PKG}"
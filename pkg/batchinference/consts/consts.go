@@ -0,0 +1,36 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consts
+
+const (
+	// Labels
+	LabelBatchInferenceName = "kaito.sh/batch-inference-name"
+
+	// Conditions
+	ConditionTypeWorkspaceReady = "WorkspaceReady"
+	ConditionTypeJobCreated     = "JobCreated"
+	ConditionTypeCompleted      = "Completed"
+
+	// WorkerImageName and WorkerImageTag name the batch worker image, built from
+	// docker/batchinference and published to the preset registry. The tag must match
+	// BATCH_WORKER_IMG_TAG in the Makefile.
+	WorkerImageName = "batch-worker"
+	WorkerImageTag  = "0.1.0"
+
+	// CPU/memory of a shard pod. The worker only streams requests, the model runs in the
+	// workspace.
+	WorkerCPU           = "500m"
+	WorkerMemoryRequest = "256Mi"
+	WorkerMemoryLimit   = "1Gi"
+)
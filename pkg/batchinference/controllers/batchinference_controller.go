@@ -0,0 +1,378 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	biconsts "github.com/kaito-project/kaito/pkg/batchinference/consts"
+	"github.com/kaito-project/kaito/pkg/batchinference/job"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// workspaceRetryInterval polls a referenced Workspace, which the controller does not own
+	// and therefore does not watch, until it is ready.
+	workspaceRetryInterval = 30 * time.Second
+	// progressInterval refreshes the shard progress while the Job runs.
+	progressInterval = 30 * time.Second
+)
+
+// BatchInferenceReconciler reconciles BatchInference objects.
+type BatchInferenceReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// NewBatchInferenceReconciler creates a new reconciler instance.
+func NewBatchInferenceReconciler(c client.Client, log logr.Logger) *BatchInferenceReconciler {
+	return &BatchInferenceReconciler{
+		Client: c,
+		Log:    log,
+	}
+}
+
+func (r *BatchInferenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("batchinference", req.NamespacedName)
+
+	bi := &kaitov1alpha1.BatchInference{}
+	if err := r.Get(ctx, req.NamespacedName, bi); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The Job and the preset Workspace are owned by the BatchInference and garbage collected
+	// with it.
+	if !bi.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// A finished batch only releases the Workspace it created.
+	if isFinished(bi) {
+		return ctrl.Result{}, r.releaseWorkspace(ctx, bi, log)
+	}
+
+	batchJob := &batchv1.Job{}
+	err := r.Get(ctx, req.NamespacedName, batchJob)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if errors.IsNotFound(err) {
+		// The Workspace only has to be ready to start the batch; shards that lose it later
+		// are retried by the Job.
+		workspace, ready, err := r.ensureWorkspace(ctx, bi)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !ready {
+			return ctrl.Result{RequeueAfter: workspaceRetryInterval}, r.Status().Update(ctx, bi)
+		}
+		batchJob = job.BuildBatchJob(bi, workspace)
+		log.Info("Creating batch Job", "workspace", workspace, "shards", bi.Spec.Shards)
+		if err := r.Create(ctx, batchJob); err != nil {
+			return ctrl.Result{}, err
+		}
+		bi.Status.JobName = batchJob.Name
+		bi.Status.StartTime = ptr.To(metav1.Now())
+		setCondition(bi, biconsts.ConditionTypeJobCreated, metav1.ConditionTrue, "JobCreated",
+			fmt.Sprintf("Job %s processes %d shards", batchJob.Name, bi.Spec.Shards))
+	} else if !metav1.IsControlledBy(batchJob, bi) {
+		return ctrl.Result{}, r.fail(ctx, bi, "JobConflict",
+			fmt.Sprintf("Job %s already exists and is not owned by the BatchInference", batchJob.Name))
+	}
+
+	if err := r.syncShards(ctx, bi, batchJob); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case jobCondition(batchJob, batchv1.JobComplete) != nil:
+		bi.Status.Phase = kaitov1alpha1.BatchInferencePhaseSucceeded
+		bi.Status.FailureMessage = ""
+		bi.Status.CompletionTime = ptr.To(metav1.Now())
+		setCondition(bi, biconsts.ConditionTypeCompleted, metav1.ConditionTrue, "Succeeded",
+			fmt.Sprintf("Processed %d requests, %d failed", bi.Status.Processed, bi.Status.Failed))
+		log.Info("BatchInference succeeded", "processed", bi.Status.Processed, "failed", bi.Status.Failed)
+	case jobCondition(batchJob, batchv1.JobFailed) != nil:
+		msg := "Batch job failed: " + jobCondition(batchJob, batchv1.JobFailed).Message
+		if batchJob.Status.FailedIndexes != nil && *batchJob.Status.FailedIndexes != "" {
+			msg = fmt.Sprintf("Shards %s failed after %d retries", *batchJob.Status.FailedIndexes, bi.Spec.ShardRetries)
+		}
+		bi.Status.Phase = kaitov1alpha1.BatchInferencePhaseFailed
+		bi.Status.FailureMessage = msg
+		bi.Status.CompletionTime = ptr.To(metav1.Now())
+		setCondition(bi, biconsts.ConditionTypeCompleted, metav1.ConditionFalse, "Failed", msg)
+		log.Info("BatchInference failed", "reason", msg)
+	default:
+		bi.Status.Phase = kaitov1alpha1.BatchInferencePhaseRunning
+		setCondition(bi, biconsts.ConditionTypeCompleted, metav1.ConditionFalse, "Running",
+			fmt.Sprintf("Processed %d requests", bi.Status.Processed))
+	}
+	if err := r.Status().Update(ctx, bi); err != nil {
+		return ctrl.Result{}, err
+	}
+	if isFinished(bi) {
+		return ctrl.Result{}, r.releaseWorkspace(ctx, bi, log)
+	}
+	return ctrl.Result{RequeueAfter: progressInterval}, nil
+}
+
+// ensureWorkspace returns the Workspace that serves the batch and whether it is ready,
+// creating it first for a preset. The reason it is not ready is recorded in the status.
+func (r *BatchInferenceReconciler) ensureWorkspace(ctx context.Context, bi *kaitov1alpha1.BatchInference) (string, bool, error) {
+	name := bi.Spec.Workspace
+	if bi.Spec.Preset != nil {
+		name = bi.Name
+	}
+	bi.Status.WorkspaceName = name
+	bi.Status.Phase = kaitov1alpha1.BatchInferencePhasePending
+
+	ws := &kaitov1beta1.Workspace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: bi.Namespace}, ws); err != nil {
+		if !errors.IsNotFound(err) {
+			return name, false, err
+		}
+		if bi.Spec.Preset == nil {
+			setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionFalse, "WorkspaceNotFound",
+				fmt.Sprintf("Workspace %s does not exist", name))
+			return name, false, nil
+		}
+		if err := r.Create(ctx, presetWorkspace(bi)); err != nil {
+			return name, false, err
+		}
+		setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionFalse, "WorkspaceCreated",
+			fmt.Sprintf("Created Workspace %s for preset %s", name, bi.Spec.Preset.Name))
+		return name, false, nil
+	}
+
+	if bi.Spec.Preset != nil && !metav1.IsControlledBy(ws, bi) {
+		setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionFalse, "WorkspaceConflict",
+			fmt.Sprintf("Workspace %s already exists and is not owned by the BatchInference", name))
+		return name, false, nil
+	}
+	if ws.Inference == nil {
+		setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionFalse, "NotInferenceWorkspace",
+			fmt.Sprintf("Workspace %s does not serve inference", name))
+		return name, false, nil
+	}
+	if !meta.IsStatusConditionTrue(ws.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeInferenceStatus)) {
+		setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionFalse, "WorkspaceNotReady",
+			fmt.Sprintf("Waiting for the inference of Workspace %s to be ready", name))
+		return name, false, nil
+	}
+	setCondition(bi, biconsts.ConditionTypeWorkspaceReady, metav1.ConditionTrue, "WorkspaceReady",
+		fmt.Sprintf("Workspace %s is ready", name))
+	return name, true, nil
+}
+
+// presetWorkspace returns the Workspace that serves the preset of bi. It is named after and
+// owned by the BatchInference.
+func presetWorkspace(bi *kaitov1alpha1.BatchInference) *kaitov1beta1.Workspace {
+	labels := map[string]string{biconsts.LabelBatchInferenceName: bi.Name}
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bi.Name,
+			Namespace: bi.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(bi, kaitov1alpha1.GroupVersion.WithKind("BatchInference")),
+			},
+		},
+		Resource: kaitov1beta1.ResourceSpec{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+		},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{
+				PresetMeta: kaitov1beta1.PresetMeta{Name: bi.Spec.Preset.Name},
+			},
+		},
+	}
+	// Only set InstanceType when node auto-provisioning is enabled.
+	// In BYO mode, the Workspace webhook rejects instanceType.
+	if consts.ActiveNodeProvisioner != consts.NodeProvisionerBYO {
+		ws.Resource.InstanceType = bi.Spec.Preset.InstanceType
+	}
+	return ws
+}
+
+// releaseWorkspace deletes the Workspace created for the preset of a finished batch so its
+// GPU nodes are released. A referenced Workspace is left alone.
+func (r *BatchInferenceReconciler) releaseWorkspace(ctx context.Context, bi *kaitov1alpha1.BatchInference, log logr.Logger) error {
+	if bi.Spec.Preset == nil {
+		return nil
+	}
+	ws := &kaitov1beta1.Workspace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: bi.Name, Namespace: bi.Namespace}, ws); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(ws, bi) || !ws.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	log.Info("Deleting the preset Workspace of the finished batch", "workspace", ws.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, ws))
+}
+
+// syncShards derives the per-shard progress from the completed and failed indexes of the
+// Job and the termination messages the worker leaves on the shard pods.
+func (r *BatchInferenceReconciler) syncShards(ctx context.Context, bi *kaitov1alpha1.BatchInference, batchJob *batchv1.Job) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods,
+		client.InNamespace(bi.Namespace),
+		client.MatchingLabels{biconsts.LabelBatchInferenceName: bi.Name},
+	); err != nil {
+		return err
+	}
+
+	shards := make([]kaitov1alpha1.BatchInferenceShardStatus, bi.Spec.Shards)
+	for i := range shards {
+		shards[i] = kaitov1alpha1.BatchInferenceShardStatus{Index: int32(i), Phase: kaitov1alpha1.BatchInferenceShardPhasePending}
+	}
+	// Keep the progress of earlier pods the Job controller may have removed since.
+	for _, s := range bi.Status.Shards {
+		if s.Index >= 0 && s.Index < bi.Spec.Shards {
+			shards[s.Index].Attempts, shards[s.Index].Processed, shards[s.Index].Failed = s.Attempts, s.Processed, s.Failed
+		}
+	}
+
+	attempts := make([]int32, len(shards))
+	lastFinished := make([]time.Time, len(shards))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !metav1.IsControlledBy(pod, batchJob) {
+			continue
+		}
+		index, err := strconv.Atoi(pod.Annotations[batchv1.JobCompletionIndexAnnotation])
+		if err != nil || index < 0 || index >= len(shards) {
+			continue
+		}
+		attempts[index]++
+		switch pod.Status.Phase {
+		case corev1.PodPending, corev1.PodRunning:
+			shards[index].Phase = kaitov1alpha1.BatchInferenceShardPhaseRunning
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			terminated := cs.State.Terminated
+			if terminated == nil || terminated.Message == "" || terminated.FinishedAt.Time.Before(lastFinished[index]) {
+				continue
+			}
+			var progress struct {
+				Processed int64 `json:"processed"`
+				Failed    int64 `json:"failed"`
+			}
+			if json.Unmarshal([]byte(terminated.Message), &progress) == nil {
+				lastFinished[index] = terminated.FinishedAt.Time
+				shards[index].Processed, shards[index].Failed = progress.Processed, progress.Failed
+			}
+		}
+	}
+
+	completed := parseIndexes(batchJob.Status.CompletedIndexes)
+	failed := parseIndexes(ptr.Deref(batchJob.Status.FailedIndexes, ""))
+	bi.Status.Processed, bi.Status.Failed = 0, 0
+	for i := range shards {
+		shards[i].Attempts = max(shards[i].Attempts, attempts[i])
+		if completed[i] {
+			shards[i].Phase = kaitov1alpha1.BatchInferenceShardPhaseSucceeded
+		} else if failed[i] {
+			shards[i].Phase = kaitov1alpha1.BatchInferenceShardPhaseFailed
+		}
+		bi.Status.Processed += shards[i].Processed
+		bi.Status.Failed += shards[i].Failed
+	}
+	bi.Status.Shards = shards
+	return nil
+}
+
+// parseIndexes parses the completed and failed indexes of an Indexed Job, a comma separated
+// list of indexes and ranges such as "1,3-5,7".
+func parseIndexes(s string) map[int]bool {
+	indexes := map[int]bool{}
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		lo, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(last); err != nil {
+				continue
+			}
+		}
+		for i := lo; i <= hi; i++ {
+			indexes[i] = true
+		}
+	}
+	return indexes
+}
+
+func (r *BatchInferenceReconciler) fail(ctx context.Context, bi *kaitov1alpha1.BatchInference, reason, msg string) error {
+	bi.Status.Phase = kaitov1alpha1.BatchInferencePhaseFailed
+	bi.Status.FailureMessage = msg
+	setCondition(bi, biconsts.ConditionTypeCompleted, metav1.ConditionFalse, reason, msg)
+	return r.Status().Update(ctx, bi)
+}
+
+func isFinished(bi *kaitov1alpha1.BatchInference) bool {
+	return bi.Status.Phase == kaitov1alpha1.BatchInferencePhaseSucceeded ||
+		bi.Status.Phase == kaitov1alpha1.BatchInferencePhaseFailed
+}
+
+func jobCondition(j *batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range j.Status.Conditions {
+		if j.Status.Conditions[i].Type == condType && j.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &j.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func setCondition(bi *kaitov1alpha1.BatchInference, condType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&bi.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: bi.Generation,
+	})
+}
+
+// SetupWithManager registers the controller with the manager.
+func (r *BatchInferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kaitov1alpha1.BatchInference{}).
+		Owns(&batchv1.Job{}).
+		Owns(&kaitov1beta1.Workspace{}).
+		Complete(r)
+}
@@ -0,0 +1,258 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	biconsts "github.com/kaito-project/kaito/pkg/batchinference/consts"
+	"github.com/kaito-project/kaito/pkg/batchinference/job"
+)
+
+var biKey = types.NamespacedName{Name: "nightly-eval", Namespace: "default"}
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = kaitov1alpha1.AddToScheme(scheme)
+	_ = kaitov1beta1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return scheme
+}
+
+func newBatchInference() *kaitov1alpha1.BatchInference {
+	return &kaitov1alpha1.BatchInference{
+		ObjectMeta: metav1.ObjectMeta{Name: biKey.Name, Namespace: biKey.Namespace, UID: "bi-uid"},
+		Spec: kaitov1alpha1.BatchInferenceSpec{
+			Workspace: "phi-4",
+			Input: kaitov1alpha1.BatchInferenceInput{
+				Volume: &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "datasets"}},
+			},
+			Output: kaitov1alpha1.BatchInferenceOutput{
+				Volume: &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "results"}},
+			},
+			Shards:       3,
+			Concurrency:  8,
+			ShardRetries: 2,
+		},
+	}
+}
+
+func inferenceWorkspace(name string, ready bool) *kaitov1beta1.Workspace {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: biKey.Namespace},
+		Inference:  &kaitov1beta1.InferenceSpec{},
+		Status: kaitov1beta1.WorkspaceStatus{
+			Conditions: []metav1.Condition{{
+				Type: string(kaitov1beta1.WorkspaceConditionTypeInferenceStatus), Status: status, Reason: "test",
+			}},
+		},
+	}
+}
+
+func shardPod(bi *kaitov1alpha1.BatchInference, j *batchv1.Job, name, index string, phase corev1.PodPhase, message string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       bi.Namespace,
+			Labels:          map[string]string{biconsts.LabelBatchInferenceName: bi.Name},
+			Annotations:     map[string]string{batchv1.JobCompletionIndexAnnotation: index},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(j, batchv1.SchemeGroupVersion.WithKind("Job"))},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	if message != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: "worker",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Message: message, FinishedAt: metav1.NewTime(time.Now()),
+			}},
+		}}
+	}
+	return pod
+}
+
+func newReconciler(objs ...client.Object) (*BatchInferenceReconciler, client.Client) {
+	c := fake.NewClientBuilder().WithScheme(newScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(&kaitov1alpha1.BatchInference{}).
+		Build()
+	return NewBatchInferenceReconciler(c, zap.New(zap.UseDevMode(true))), c
+}
+
+func TestReconcile_WaitsForWorkspace(t *testing.T) {
+	r, c := newReconciler(newBatchInference(), inferenceWorkspace("phi-4", false))
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+	assert.Equal(t, workspaceRetryInterval, result.RequeueAfter)
+
+	bi := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, bi))
+	assert.Equal(t, kaitov1alpha1.BatchInferencePhasePending, bi.Status.Phase)
+	assert.Equal(t, "phi-4", bi.Status.WorkspaceName)
+	cond := meta.FindStatusCondition(bi.Status.Conditions, biconsts.ConditionTypeWorkspaceReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, "WorkspaceNotReady", cond.Reason)
+
+	err = c.Get(context.Background(), biKey, &batchv1.Job{})
+	assert.True(t, errors.IsNotFound(err), "no Job before the workspace is ready")
+}
+
+func TestReconcile_CreatesJob(t *testing.T) {
+	r, c := newReconciler(newBatchInference(), inferenceWorkspace("phi-4", true))
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+	assert.Equal(t, progressInterval, result.RequeueAfter)
+
+	j := &batchv1.Job{}
+	require.NoError(t, c.Get(context.Background(), biKey, j))
+	assert.Equal(t, int32(3), *j.Spec.Completions)
+
+	bi := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, bi))
+	assert.Equal(t, kaitov1alpha1.BatchInferencePhaseRunning, bi.Status.Phase)
+	assert.Equal(t, "nightly-eval", bi.Status.JobName)
+	assert.NotNil(t, bi.Status.StartTime)
+	require.Len(t, bi.Status.Shards, 3)
+	for _, s := range bi.Status.Shards {
+		assert.Equal(t, kaitov1alpha1.BatchInferenceShardPhasePending, s.Phase)
+	}
+}
+
+func TestReconcile_CreatesPresetWorkspace(t *testing.T) {
+	bi := newBatchInference()
+	bi.Spec.Workspace = ""
+	bi.Spec.Preset = &kaitov1alpha1.BatchInferencePreset{Name: "phi-4-mini-instruct", InstanceType: "Standard_NC24ads_A100_v4"}
+	r, c := newReconciler(bi)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+
+	ws := &kaitov1beta1.Workspace{}
+	require.NoError(t, c.Get(context.Background(), biKey, ws))
+	assert.True(t, metav1.IsControlledBy(ws, bi))
+	assert.Equal(t, kaitov1beta1.ModelName("phi-4-mini-instruct"), ws.Inference.Preset.Name)
+	assert.Equal(t, map[string]string{biconsts.LabelBatchInferenceName: bi.Name}, ws.Resource.LabelSelector.MatchLabels)
+
+	updated := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, updated))
+	assert.Equal(t, bi.Name, updated.Status.WorkspaceName)
+	assert.Equal(t, "WorkspaceCreated", meta.FindStatusCondition(updated.Status.Conditions, biconsts.ConditionTypeWorkspaceReady).Reason)
+}
+
+func TestReconcile_ReportsShardProgress(t *testing.T) {
+	bi := newBatchInference()
+	j := job.BuildBatchJob(bi, "phi-4")
+	j.Status.CompletedIndexes = "0"
+	j.Status.Active = 1
+	pods := []client.Object{
+		shardPod(bi, j, "s0", "0", corev1.PodSucceeded, `{"processed": 40, "failed": 1}`),
+		shardPod(bi, j, "s1-a", "1", corev1.PodFailed, `{"processed": 10, "failed": 0}`),
+		shardPod(bi, j, "s1-b", "1", corev1.PodRunning, ""),
+	}
+	r, c := newReconciler(append(pods, bi, j, inferenceWorkspace("phi-4", true))...)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+
+	updated := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, updated))
+	assert.Equal(t, kaitov1alpha1.BatchInferencePhaseRunning, updated.Status.Phase)
+	assert.Equal(t, []kaitov1alpha1.BatchInferenceShardStatus{
+		{Index: 0, Phase: kaitov1alpha1.BatchInferenceShardPhaseSucceeded, Attempts: 1, Processed: 40, Failed: 1},
+		{Index: 1, Phase: kaitov1alpha1.BatchInferenceShardPhaseRunning, Attempts: 2, Processed: 10},
+		{Index: 2, Phase: kaitov1alpha1.BatchInferenceShardPhasePending},
+	}, updated.Status.Shards)
+	assert.Equal(t, int64(50), updated.Status.Processed)
+	assert.Equal(t, int64(1), updated.Status.Failed)
+}
+
+func TestReconcile_FailedJobReleasesPresetWorkspace(t *testing.T) {
+	bi := newBatchInference()
+	bi.Spec.Workspace = ""
+	bi.Spec.Preset = &kaitov1alpha1.BatchInferencePreset{Name: "phi-4-mini-instruct", InstanceType: "Standard_NC24ads_A100_v4"}
+	ws := presetWorkspace(bi)
+	j := job.BuildBatchJob(bi, bi.Name)
+	j.Status.CompletedIndexes = "0-1"
+	j.Status.FailedIndexes = ptr.To("2")
+	j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has failed indexes"}}
+	r, c := newReconciler(bi, ws, j)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	updated := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, updated))
+	assert.Equal(t, kaitov1alpha1.BatchInferencePhaseFailed, updated.Status.Phase)
+	assert.Equal(t, "Shards 2 failed after 2 retries", updated.Status.FailureMessage)
+	assert.NotNil(t, updated.Status.CompletionTime)
+	assert.Equal(t, kaitov1alpha1.BatchInferenceShardPhaseFailed, updated.Status.Shards[2].Phase)
+
+	err = c.Get(context.Background(), biKey, &kaitov1beta1.Workspace{})
+	assert.True(t, errors.IsNotFound(err), "the preset workspace is deleted once the batch finishes")
+}
+
+func TestReconcile_KeepsReferencedWorkspace(t *testing.T) {
+	bi := newBatchInference()
+	j := job.BuildBatchJob(bi, "phi-4")
+	j.Status.CompletedIndexes = "0-2"
+	j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	r, c := newReconciler(bi, j, inferenceWorkspace("phi-4", true))
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: biKey})
+	require.NoError(t, err)
+
+	updated := &kaitov1alpha1.BatchInference{}
+	require.NoError(t, c.Get(context.Background(), biKey, updated))
+	assert.Equal(t, kaitov1alpha1.BatchInferencePhaseSucceeded, updated.Status.Phase)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "phi-4", Namespace: biKey.Namespace}, &kaitov1beta1.Workspace{}))
+}
+
+func TestParseIndexes(t *testing.T) {
+	tests := map[string]map[int]bool{
+		"":        {},
+		"0":       {0: true},
+		"1,3-5,7": {1: true, 3: true, 4: true, 5: true, 7: true},
+		"x,2":     {2: true},
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, parseIndexes(in), in)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	biconsts "github.com/kaito-project/kaito/pkg/batchinference/consts"
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+const (
+	inputVolumeName  = "input"
+	inputMountPath   = "/mnt/input"
+	outputVolumeName = "output"
+	outputMountPath  = "/mnt/output"
+)
+
+// WorkspaceEndpoint returns the in-cluster URL of the inference Service of a workspace.
+func WorkspaceEndpoint(workspace, namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:80", workspace, namespace)
+}
+
+// WorkerImage returns the batch worker image in the preset registry.
+func WorkerImage() string {
+	return utils.GetPresetImageName("", biconsts.WorkerImageName, biconsts.WorkerImageTag)
+}
+
+// BuildBatchJob constructs the Indexed Job that streams the dataset of bi through the model
+// served by workspace. Every completion index is a shard, retried on its own up to
+// spec.shardRetries times.
+func BuildBatchJob(bi *kaitov1alpha1.BatchInference, workspace string) *batchv1.Job {
+	args := []string{
+		"--endpoint=" + WorkspaceEndpoint(workspace, bi.Namespace),
+		"--input=" + path.Join(inputMountPath, bi.Spec.Input.Path),
+		"--output=" + path.Join(outputMountPath, bi.Spec.Output.Path),
+		"--shards=" + strconv.Itoa(int(bi.Spec.Shards)),
+		"--concurrency=" + strconv.Itoa(int(bi.Spec.Concurrency)),
+		"--max-retries=" + strconv.Itoa(int(bi.Spec.MaxRetries)),
+		"--request-timeout=" + strconv.Itoa(int(bi.Spec.RequestTimeoutSeconds)),
+	}
+
+	container := corev1.Container{
+		Name:  "worker",
+		Image: WorkerImage(),
+		Args:  args,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(biconsts.WorkerCPU),
+				corev1.ResourceMemory: resource.MustParse(biconsts.WorkerMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(biconsts.WorkerMemoryLimit),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: inputVolumeName, MountPath: inputMountPath, ReadOnly: true},
+			{Name: outputVolumeName, MountPath: outputMountPath},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}
+
+	labels := map[string]string{biconsts.LabelBatchInferenceName: bi.Name}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bi.Name,
+			Namespace: bi.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(bi, kaitov1alpha1.GroupVersion.WithKind("BatchInference")),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			CompletionMode:       ptr.To(batchv1.IndexedCompletion),
+			Completions:          ptr.To(bi.Spec.Shards),
+			Parallelism:          ptr.To(bi.Spec.GetParallelism()),
+			BackoffLimitPerIndex: ptr.To(bi.Spec.ShardRetries),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{container},
+					Volumes: []corev1.Volume{
+						{Name: inputVolumeName, VolumeSource: *bi.Spec.Input.Volume.DeepCopy()},
+						{Name: outputVolumeName, VolumeSource: *bi.Spec.Output.Volume.DeepCopy()},
+					},
+				},
+			},
+		},
+	}
+}
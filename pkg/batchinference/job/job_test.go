@@ -0,0 +1,100 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	biconsts "github.com/kaito-project/kaito/pkg/batchinference/consts"
+)
+
+func testBatchInference() *kaitov1alpha1.BatchInference {
+	return &kaitov1alpha1.BatchInference{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-eval", Namespace: "team-a", UID: "uid-1"},
+		Spec: kaitov1alpha1.BatchInferenceSpec{
+			Workspace: "phi-4",
+			Input: kaitov1alpha1.BatchInferenceInput{
+				Volume: &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "datasets"}},
+				Path:   "eval/prompts.jsonl",
+			},
+			Output: kaitov1alpha1.BatchInferenceOutput{
+				Volume: &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "results"}},
+			},
+			Shards:                4,
+			Concurrency:           16,
+			MaxRetries:            3,
+			ShardRetries:          2,
+			RequestTimeoutSeconds: 300,
+		},
+	}
+}
+
+func TestBuildBatchJob(t *testing.T) {
+	t.Setenv("PRESET_REGISTRY_NAME", "test-registry")
+	bi := testBatchInference()
+	job := BuildBatchJob(bi, "phi-4")
+
+	assert.Equal(t, "nightly-eval", job.Name)
+	assert.Equal(t, "team-a", job.Namespace)
+	assert.Equal(t, "nightly-eval", job.Labels[biconsts.LabelBatchInferenceName])
+	assert.Equal(t, "nightly-eval", job.Spec.Template.Labels[biconsts.LabelBatchInferenceName])
+	assert.True(t, metav1.IsControlledBy(job, bi))
+
+	assert.Equal(t, batchv1.IndexedCompletion, *job.Spec.CompletionMode)
+	assert.Equal(t, int32(4), *job.Spec.Completions)
+	assert.Equal(t, int32(4), *job.Spec.Parallelism, "parallelism defaults to the shards")
+	assert.Equal(t, int32(2), *job.Spec.BackoffLimitPerIndex)
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+
+	require.Len(t, job.Spec.Template.Spec.Containers, 1)
+	c := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "test-registry/kaito-batch-worker:"+biconsts.WorkerImageTag, c.Image)
+	assert.Empty(t, c.Command, "the image entrypoint runs the worker")
+	assert.Equal(t, []string{
+		"--endpoint=http://phi-4.team-a.svc.cluster.local:80",
+		"--input=/mnt/input/eval/prompts.jsonl",
+		"--output=/mnt/output",
+		"--shards=4",
+		"--concurrency=16",
+		"--max-retries=3",
+		"--request-timeout=300",
+	}, c.Args)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "input", MountPath: "/mnt/input", ReadOnly: true},
+		{Name: "output", MountPath: "/mnt/output"},
+	}, c.VolumeMounts)
+	assert.False(t, *c.SecurityContext.AllowPrivilegeEscalation)
+
+	volumes := job.Spec.Template.Spec.Volumes
+	require.Len(t, volumes, 2)
+	assert.Equal(t, "datasets", volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, "results", volumes[1].PersistentVolumeClaim.ClaimName)
+}
+
+func TestBuildBatchJobParallelism(t *testing.T) {
+	bi := testBatchInference()
+	bi.Spec.Parallelism = ptr.To(int32(2))
+	job := BuildBatchJob(bi, "phi-4")
+
+	assert.Equal(t, int32(4), *job.Spec.Completions)
+	assert.Equal(t, int32(2), *job.Spec.Parallelism)
+}
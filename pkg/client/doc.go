@@ -0,0 +1,25 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client holds the generated Go client for the kaito.sh/v1beta1 API, for
+// operators and tools that work with Workspaces, InferenceSets and RAGEngines
+// outside of controller-runtime.
+//
+//   - clientset/versioned is the typed clientset, and clientset/versioned/fake is an
+//     in-memory version of it for tests.
+//   - informers/externalversions is the shared informer factory.
+//   - listers reads the objects from an informer cache.
+//
+// The code is generated by hack/update-codegen.sh from the +genclient types in
+// api/v1beta1; run `make generate-client` after changing them.
+package client
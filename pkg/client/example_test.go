@@ -0,0 +1,100 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/client/clientset/versioned"
+	"github.com/kaito-project/kaito/pkg/client/clientset/versioned/fake"
+	"github.com/kaito-project/kaito/pkg/client/informers/externalversions"
+)
+
+// This example builds a clientset from a kubeconfig file and lists the Workspaces in a
+// namespace.
+func Example_kubeconfig() {
+	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+	if err != nil {
+		panic(err)
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		panic(err)
+	}
+	workspaces, err := clientset.KaitoV1beta1().Workspaces("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		panic(err)
+	}
+	for _, ws := range workspaces.Items {
+		fmt.Println(ws.Name, ws.Status.State)
+	}
+}
+
+// This example creates a Workspace with the fake clientset, which tests can use in
+// place of a cluster.
+func Example_fake() {
+	//nolint:staticcheck //SA1019: NewClientset needs OpenAPI models, which are not generated for the KAITO types
+	clientset := fake.NewSimpleClientset()
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-phi-4", Namespace: "default"},
+		Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+		},
+	}
+	if _, err := clientset.KaitoV1beta1().Workspaces("default").Create(context.Background(), ws, metav1.CreateOptions{}); err != nil {
+		panic(err)
+	}
+
+	got, err := clientset.KaitoV1beta1().Workspaces("default").Get(context.Background(), "workspace-phi-4", metav1.GetOptions{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(got.Name, got.Inference.Preset.Name)
+	// Output: workspace-phi-4 phi-4
+}
+
+// This example watches Workspaces through a shared informer and reads them from its
+// cache with a lister.
+func Example_informer() {
+	//nolint:staticcheck //SA1019: NewClientset needs OpenAPI models, which are not generated for the KAITO types
+	clientset := fake.NewSimpleClientset(&kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-phi-4", Namespace: "default"},
+	})
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(clientset, 10*time.Minute,
+		externalversions.WithNamespace("default"))
+	lister := factory.Kaito().V1beta1().Workspaces().Lister()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	workspaces, err := lister.Workspaces("default").List(labels.Everything())
+	if err != nil {
+		panic(err)
+	}
+	for _, ws := range workspaces {
+		fmt.Println(ws.Name)
+	}
+	// Output: workspace-phi-4
+}
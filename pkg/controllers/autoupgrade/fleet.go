@@ -0,0 +1,206 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoupgrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+// DefaultUpgradeHealthTimeout is how long an upgrading Workspace may stay unready before
+// the fleet rollout is halted.
+const DefaultUpgradeHealthTimeout = 30 * time.Minute
+
+// FleetUpgradeRunner rolls base image upgrades through standalone Workspaces that opt in
+// via the kaito.sh/runtime-channel annotation. Rapid Workspaces are upgraded as soon as
+// drift is detected; stable Workspaces are upgraded one at a time, only after every rapid
+// Workspace is running the new image and ready. A Workspace that does not become ready
+// within HealthTimeout halts the rollout until it recovers, is paused or is pinned.
+// Paused and pinned Workspaces are left out of the rollout entirely, including the
+// health gate.
+type FleetUpgradeRunner struct {
+	Client        client.Client
+	Interval      time.Duration
+	HealthTimeout time.Duration
+}
+
+// Start implements manager.Runnable.
+func (r *FleetUpgradeRunner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reconcileFleet(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (r *FleetUpgradeRunner) NeedLeaderElection() bool { return true }
+
+// fleetState groups the enrolled Workspaces by channel and upgrade progress.
+type fleetState struct {
+	rapidToUpgrade  []kaitov1beta1.Workspace
+	stableToUpgrade []kaitov1beta1.Workspace
+	rapidUpgrading  []kaitov1beta1.Workspace
+	upgrading       []kaitov1beta1.Workspace
+	unhealthy       []kaitov1beta1.Workspace
+}
+
+func (r *FleetUpgradeRunner) reconcileFleet(ctx context.Context, now time.Time) {
+	desiredImage := inference.GetBaseImageName()
+	desiredTag := inference.GetBaseImageTag()
+
+	wsList := &kaitov1beta1.WorkspaceList{}
+	if err := r.Client.List(ctx, wsList); err != nil {
+		klog.ErrorS(err, "FleetUpgradeRunner: failed to list Workspaces")
+		return
+	}
+	sort.SliceStable(wsList.Items, func(i, j int) bool {
+		return wsList.Items[i].UID < wsList.Items[j].UID
+	})
+
+	state, err := r.categorizeFleet(ctx, wsList.Items, desiredImage, desiredTag, now)
+	if err != nil {
+		klog.ErrorS(err, "FleetUpgradeRunner: failed to categorize workspaces")
+		return
+	}
+	klog.InfoS("FleetUpgradeRunner: categorized workspaces", "desiredTag", desiredTag,
+		"rapidToUpgrade", len(state.rapidToUpgrade), "stableToUpgrade", len(state.stableToUpgrade),
+		"upgrading", len(state.upgrading), "unhealthy", len(state.unhealthy))
+
+	// Health gate: never widen a rollout while an upgraded Workspace is failing.
+	if len(state.unhealthy) > 0 {
+		for i := range state.unhealthy {
+			klog.InfoS("FleetUpgradeRunner: rollout halted, workspace did not become ready after upgrade",
+				"workspace", klog.KObj(&state.unhealthy[i]), "targetVersion", desiredTag)
+		}
+		return
+	}
+
+	// Rapid Workspaces upgrade together.
+	for i := range state.rapidToUpgrade {
+		r.tagWorkspace(ctx, &state.rapidToUpgrade[i], desiredTag)
+	}
+	if len(state.rapidToUpgrade) > 0 || len(state.rapidUpgrading) > 0 {
+		return
+	}
+
+	// Stable Workspaces upgrade one at a time.
+	if len(state.upgrading) > 0 || len(state.stableToUpgrade) == 0 {
+		return
+	}
+	r.tagWorkspace(ctx, &state.stableToUpgrade[0], desiredTag)
+}
+
+// categorizeFleet sorts enrolled Workspaces into the fleetState buckets. Workspaces that
+// are pinned, paused, owned by an InferenceSet or already in the desired state are ignored.
+func (r *FleetUpgradeRunner) categorizeFleet(ctx context.Context, workspaces []kaitov1beta1.Workspace, desiredImage, desiredTag string, now time.Time) (*fleetState, error) {
+	state := &fleetState{}
+	for i := range workspaces {
+		ws := &workspaces[i]
+		channel := ws.Annotations[kaitov1beta1.AnnotationRuntimeChannel]
+		if !isFleetManaged(ws) || kaitov1beta1.IsRuntimeChannelPinned(ws) ||
+			ws.Annotations[kaitov1beta1.AnnotationRuntimeUpgradePaused] == "true" || kaitov1beta1.ReconcilePaused(ws) {
+			continue
+		}
+
+		ss := &appsv1.StatefulSet{}
+		if err := resources.GetResource(ctx, ws.Name, ws.Namespace, r.Client, ss); err != nil {
+			if apierrors.IsNotFound(err) {
+				// Not deployed yet; it will be created with the current base image.
+				continue
+			}
+			return nil, fmt.Errorf("failed to get StatefulSet for workspace %s: %w", ws.Name, err)
+		}
+		if isWorkspaceInDesiredState(ss, desiredImage) {
+			continue
+		}
+
+		tagged := ws.Labels[kaitov1alpha1.LabelUpgradeToVersion] == desiredTag
+		switch {
+		case tagged:
+			if r.upgradeTimedOut(ws, now) {
+				state.unhealthy = append(state.unhealthy, *ws)
+				continue
+			}
+			state.upgrading = append(state.upgrading, *ws)
+			if channel == kaitov1beta1.RuntimeChannelRapid {
+				state.rapidUpgrading = append(state.rapidUpgrading, *ws)
+			}
+		case imageverify.Unpin(workspace.GetInferenceContainerImage(ss)) != desiredImage:
+			if channel == kaitov1beta1.RuntimeChannelRapid {
+				state.rapidToUpgrade = append(state.rapidToUpgrade, *ws)
+			} else {
+				state.stableToUpgrade = append(state.stableToUpgrade, *ws)
+			}
+		}
+	}
+	return state, nil
+}
+
+// upgradeTimedOut reports whether ws has been upgrading for longer than the health timeout.
+func (r *FleetUpgradeRunner) upgradeTimedOut(ws *kaitov1beta1.Workspace, now time.Time) bool {
+	started, err := time.Parse(time.RFC3339, ws.Annotations[AnnotationUpgradeStartTime])
+	if err != nil {
+		return false
+	}
+	timeout := r.HealthTimeout
+	if timeout == 0 {
+		timeout = DefaultUpgradeHealthTimeout
+	}
+	return now.Sub(started) > timeout
+}
+
+func (r *FleetUpgradeRunner) tagWorkspace(ctx context.Context, ws *kaitov1beta1.Workspace, desiredTag string) {
+	if err := setUpgradeLabel(ctx, r.Client, ws, desiredTag); err != nil {
+		klog.ErrorS(err, "FleetUpgradeRunner: failed to tag workspace for upgrade",
+			"workspace", klog.KObj(ws), "targetVersion", desiredTag)
+		return
+	}
+	klog.InfoS("FleetUpgradeRunner: tagged workspace for upgrade",
+		"workspace", klog.KObj(ws), "targetVersion", desiredTag,
+		"channel", ws.Annotations[kaitov1beta1.AnnotationRuntimeChannel])
+}
+
+// isFleetManaged reports whether ws opted into fleet upgrades and is not already covered
+// by its InferenceSet's autoUpgrade policy.
+func isFleetManaged(ws *kaitov1beta1.Workspace) bool {
+	if ws.DeletionTimestamp != nil || ws.Inference == nil {
+		return false
+	}
+	if _, ok := ws.Labels[consts.WorkspaceCreatedByInferenceSetLabel]; ok {
+		return false
+	}
+	_, ok := ws.Annotations[kaitov1beta1.AnnotationRuntimeChannel]
+	return ok
+}
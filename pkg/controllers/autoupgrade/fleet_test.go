@@ -0,0 +1,188 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoupgrade
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+func makeChannelWorkspace(name, channel string, annotations, labels map[string]string) *kaitov1beta1.Workspace {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[kaitov1beta1.AnnotationRuntimeChannel] = channel
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			UID:         types.UID(name),
+			Annotations: annotations,
+			Labels:      labels,
+		},
+		Inference: &kaitov1beta1.InferenceSpec{},
+	}
+}
+
+func upgradeLabel(tag string) map[string]string {
+	return map[string]string{kaitov1alpha1.LabelUpgradeToVersion: tag}
+}
+
+func startedAt(ts time.Time) map[string]string {
+	return map[string]string{AnnotationUpgradeStartTime: ts.Format(time.RFC3339)}
+}
+
+func TestFleetUpgradeRunnerReconcileFleet(t *testing.T) {
+	desiredImage := setTestRegistry(t)
+	desiredTag := inference.GetBaseImageTag()
+	oldImage := "mcr.microsoft.com/aks/kaito/kaito-base:0.0.1"
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name         string
+		workspaces   []*kaitov1beta1.Workspace
+		statefulSets []*appsv1.StatefulSet
+		expectTagged []string
+	}{
+		{
+			name: "rapid workspaces upgrade together before stable",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-rapid", kaitov1beta1.RuntimeChannelRapid, nil, nil),
+				makeChannelWorkspace("b-rapid", kaitov1beta1.RuntimeChannelRapid, nil, nil),
+				makeChannelWorkspace("c-stable", kaitov1beta1.RuntimeChannelStable, nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-rapid", "default", oldImage),
+				makeStatefulSet("b-rapid", "default", oldImage),
+				makeStatefulSet("c-stable", "default", oldImage),
+			},
+			expectTagged: []string{"a-rapid", "b-rapid"},
+		},
+		{
+			name: "stable workspaces upgrade one at a time once rapid is done",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-rapid", kaitov1beta1.RuntimeChannelRapid, nil, nil),
+				makeChannelWorkspace("b-stable", kaitov1beta1.RuntimeChannelStable, nil, nil),
+				makeChannelWorkspace("c-stable", kaitov1beta1.RuntimeChannelStable, nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-rapid", "default", desiredImage),
+				makeStatefulSet("b-stable", "default", oldImage),
+				makeStatefulSet("c-stable", "default", oldImage),
+			},
+			expectTagged: []string{"b-stable"},
+		},
+		{
+			name: "stable waits while a rapid upgrade is in progress",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-rapid", kaitov1beta1.RuntimeChannelRapid, startedAt(now), upgradeLabel(desiredTag)),
+				makeChannelWorkspace("b-stable", kaitov1beta1.RuntimeChannelStable, nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-rapid", "default", oldImage),
+				makeStatefulSet("b-stable", "default", oldImage),
+			},
+			expectTagged: []string{"a-rapid"},
+		},
+		{
+			name: "unhealthy upgrade halts the rollout",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-rapid", kaitov1beta1.RuntimeChannelRapid, startedAt(now.Add(-time.Hour)), upgradeLabel(desiredTag)),
+				makeChannelWorkspace("b-rapid", kaitov1beta1.RuntimeChannelRapid, nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-rapid", "default", oldImage),
+				makeStatefulSet("b-rapid", "default", oldImage),
+			},
+			expectTagged: []string{"a-rapid"},
+		},
+		{
+			name: "pinned and paused workspaces are skipped",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-pinned", kaitov1beta1.RuntimeChannelPinned, nil, nil),
+				makeChannelWorkspace("b-paused", kaitov1beta1.RuntimeChannelRapid,
+					map[string]string{kaitov1beta1.AnnotationRuntimeUpgradePaused: "true"}, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-pinned", "default", oldImage),
+				makeStatefulSet("b-paused", "default", oldImage),
+			},
+			expectTagged: nil,
+		},
+		{
+			name: "digest-pinned workspaces are skipped",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-pinned", "pinned@sha256:"+strings.Repeat("a", 64), nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-pinned", "default", oldImage),
+			},
+			expectTagged: nil,
+		},
+		{
+			name: "paused unhealthy workspace does not halt the rollout",
+			workspaces: []*kaitov1beta1.Workspace{
+				makeChannelWorkspace("a-rapid", kaitov1beta1.RuntimeChannelRapid, map[string]string{
+					AnnotationUpgradeStartTime:                  now.Add(-time.Hour).Format(time.RFC3339),
+					kaitov1beta1.AnnotationRuntimeUpgradePaused: "true",
+				}, upgradeLabel(desiredTag)),
+				makeChannelWorkspace("b-rapid", kaitov1beta1.RuntimeChannelRapid, nil, nil),
+			},
+			statefulSets: []*appsv1.StatefulSet{
+				makeStatefulSet("a-rapid", "default", oldImage),
+				makeStatefulSet("b-rapid", "default", oldImage),
+			},
+			expectTagged: []string{"a-rapid", "b-rapid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			for _, ws := range tt.workspaces {
+				objs = append(objs, ws)
+			}
+			for _, ss := range tt.statefulSets {
+				objs = append(objs, ss)
+			}
+			cl := newFakeClient(objs...)
+			r := &FleetUpgradeRunner{Client: cl, HealthTimeout: 30 * time.Minute}
+
+			r.reconcileFleet(context.Background(), now)
+
+			var tagged []string
+			for _, ws := range tt.workspaces {
+				got := &kaitov1beta1.Workspace{}
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(ws), got))
+				if got.Labels[kaitov1alpha1.LabelUpgradeToVersion] == desiredTag {
+					tagged = append(tagged, got.Name)
+				}
+			}
+			assert.Equal(t, tt.expectTagged, tagged)
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
 	inferencesetutil "github.com/kaito-project/kaito/pkg/utils/inferenceset"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/utils/workspace"
@@ -82,7 +83,7 @@ func (r *AutoUpgradeRunner) reconcileAll(ctx context.Context) {
 
 	for i := range inferenceSetList.Items {
 		inferenceSetObj := &inferenceSetList.Items[i]
-		if inferenceSetObj.DeletionTimestamp != nil {
+		if inferenceSetObj.DeletionTimestamp != nil || kaitov1beta1.ReconcilePaused(inferenceSetObj) {
 			continue
 		}
 		r.reconcileInferenceSet(ctx, inferenceSetObj)
@@ -160,7 +161,8 @@ func (r *AutoUpgradeRunner) reconcileInferenceSet(ctx context.Context, inference
 //   - upgrading: has the upgrade label for the current desired version but not yet fully ready.
 //
 // Workspaces that are running the desired image AND are inference ready
-// are considered complete and excluded from both lists.
+// are considered complete and excluded from both lists. Workspaces whose
+// reconciliation is paused are not tagged for an upgrade.
 func (r *AutoUpgradeRunner) categorizeWorkspaces(ctx context.Context, workspaces []kaitov1beta1.Workspace, desiredImage, desiredTag string) (toUpgrade, upgrading []kaitov1beta1.Workspace, err error) {
 	for i := range workspaces {
 		ws := &workspaces[i]
@@ -177,7 +179,7 @@ func (r *AutoUpgradeRunner) categorizeWorkspaces(ctx context.Context, workspaces
 			continue
 		} else if ws.Labels[kaitov1alpha1.LabelUpgradeToVersion] == desiredTag {
 			upgrading = append(upgrading, workspaces[i])
-		} else {
+		} else if !kaitov1beta1.ReconcilePaused(ws) {
 			toUpgrade = append(toUpgrade, workspaces[i])
 		}
 	}
@@ -266,10 +268,21 @@ func isWithinWindow(schedule cron.Schedule, duration time.Duration, now time.Tim
 
 // tagWorkspaceForUpgrade adds the upgrade-to-version label and start-time annotation to a Workspace.
 func (r *AutoUpgradeRunner) tagWorkspaceForUpgrade(ctx context.Context, isObj *kaitov1beta1.InferenceSet, ws *kaitov1beta1.Workspace, desiredTag string) {
-	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	if err := setUpgradeLabel(ctx, r.Client, ws, desiredTag); err != nil {
+		klog.ErrorS(err, "AutoUpgradeRunner: failed to tag workspace for upgrade",
+			"workspace", klog.KObj(ws), "targetVersion", desiredTag)
+		return
+	}
+	klog.InfoS("AutoUpgradeRunner: tagged workspace for upgrade",
+		"workspace", klog.KObj(ws), "targetVersion", desiredTag, "inferenceset", klog.KObj(isObj))
+}
+
+// setUpgradeLabel patches the upgrade-to-version label and start-time annotation onto ws.
+func setUpgradeLabel(ctx context.Context, c client.Client, ws *kaitov1beta1.Workspace, desiredTag string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Re-read the latest version to avoid conflicts.
 		latestWs := &kaitov1beta1.Workspace{}
-		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(ws), latestWs); err != nil {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(ws), latestWs); err != nil {
 			return err
 		}
 		patch := client.MergeFrom(latestWs.DeepCopy())
@@ -281,21 +294,15 @@ func (r *AutoUpgradeRunner) tagWorkspaceForUpgrade(ctx context.Context, isObj *k
 			latestWs.Annotations = make(map[string]string)
 		}
 		latestWs.Annotations[AnnotationUpgradeStartTime] = time.Now().UTC().Format(time.RFC3339)
-		return r.Client.Patch(ctx, latestWs, patch)
+		return c.Patch(ctx, latestWs, patch)
 	})
-	if err != nil {
-		klog.ErrorS(err, "AutoUpgradeRunner: failed to tag workspace for upgrade",
-			"workspace", klog.KObj(ws), "targetVersion", desiredTag)
-		return
-	}
-	klog.InfoS("AutoUpgradeRunner: tagged workspace for upgrade",
-		"workspace", klog.KObj(ws), "targetVersion", desiredTag, "inferenceset", klog.KObj(isObj))
 }
 
 // isWorkspaceInDesiredState returns true if the workspace's StatefulSet is running
 // the desired image and all replicas are ready with no pending rollout.
 func isWorkspaceInDesiredState(ss *appsv1.StatefulSet, desiredImage string) bool {
-	if workspace.GetInferenceContainerImage(ss) != desiredImage {
+	// The workloads run the image pinned to its verified digest when image verification is on.
+	if imageverify.Unpin(workspace.GetInferenceContainerImage(ss)) != desiredImage {
 		return false
 	}
 	replicas := int32(1)
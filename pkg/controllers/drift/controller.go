@@ -36,6 +36,7 @@ import (
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
 )
 
 const (
@@ -102,6 +103,10 @@ func (r *DriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 		return ctrl.Result{}, err
 	}
+	// Paused InferenceSets keep their nodes until the kaito.sh/reconcile annotation is removed.
+	if kaitov1beta1.ReconcilePaused(inferenceSet) {
+		return ctrl.Result{}, nil
+	}
 
 	// 2. List NodePools for this InferenceSet.
 	nodePoolList := &karpenterv1.NodePoolList{}
@@ -229,6 +234,33 @@ func (r *DriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
+	// Replacing the nodes of a workspace restarts its pods, so it waits for the maintenance
+	// window of the workspace.
+	candidate := &kaitov1beta1.Workspace{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: nextCandidate.workspaceNamespace,
+		Name:      nextCandidate.workspaceName,
+	}, candidate); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("getting workspace %s/%s: %w",
+			nextCandidate.workspaceNamespace, nextCandidate.workspaceName, err)
+	}
+	if now := time.Now(); !candidate.MaintenanceWindow.IsOpen(now) {
+		nextWindow := metav1.NewTime(candidate.MaintenanceWindow.NextOpen(now))
+		klog.V(2).InfoS("Deferring drift replacement to the maintenance window",
+			"workspace", klog.KObj(candidate), "nextWindow", nextWindow)
+		if err := workspace.SetPendingOperation(ctx, r.Client, candidate, kaitov1beta1.PendingOperation{
+			Type:       kaitov1beta1.PendingOperationNodeReplacement,
+			Message:    fmt.Sprintf("replacement of the drifted nodes of NodePool %s", nextCandidate.nodePoolName),
+			NextWindow: &nextWindow,
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: nextWindow.Sub(now)}, nil
+	}
+	if err := workspace.RemovePendingOperation(ctx, r.Client, candidate, kaitov1beta1.PendingOperationNodeReplacement); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Enable drift remediation on the next candidate.
 	if err := r.Provisioner.EnableDriftRemediation(ctx, nextCandidate.workspaceNamespace, nextCandidate.workspaceName); err != nil {
 		return ctrl.Result{}, fmt.Errorf("enabling drift remediation for workspace %s/%s: %w",
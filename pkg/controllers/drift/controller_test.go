@@ -16,6 +16,7 @@ package drift
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/awslabs/operatorpkg/status"
 	"github.com/stretchr/testify/mock"
@@ -372,6 +373,8 @@ func TestReconcile_OneDrifted_EnablesDrift(t *testing.T) {
 		mock.IsType(&karpenterv1.NodePoolList{}), mock.Anything).Return(nil)
 	mockClient.On("List", mock.IsType(context.Background()),
 		mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything,
+		mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
 
 	mockProv := &mockProvisioner{}
 	mockProv.On("EnableDriftRemediation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -387,6 +390,54 @@ func TestReconcile_OneDrifted_EnablesDrift(t *testing.T) {
 	mockProv.AssertNumberOfCalls(t, "EnableDriftRemediation", 1)
 }
 
+func TestReconcile_OneDrifted_OutsideMaintenanceWindow_Defers(t *testing.T) {
+	mockClient := test.NewClient()
+
+	infSet := newInferenceSet("default", "my-infset")
+	ws := newWorkspaceForInferenceSet("default", "ws-0", "my-infset")
+	// A window that opens tomorrow is closed for the whole of today.
+	now := time.Now().UTC()
+	ws.MaintenanceWindow = &kaitov1beta1.WorkspaceMaintenanceWindow{
+		Days:      []kaitov1beta1.MaintenanceDay{kaitov1beta1.MaintenanceDay(now.AddDate(0, 0, 1).Weekday().String())},
+		StartTime: "00:00",
+		EndTime:   "00:01",
+		TimeZone:  "UTC",
+	}
+	np := newNodePoolWithDriftBudget("default-ws-0", "0", "ws-0", "default", "my-infset", "default")
+	nc := newNodeClaimWithDriftCondition("nc-0", "default-ws-0", "my-infset", "default", true) // drifted
+
+	mockClient.CreateOrUpdateObjectInMap(infSet)
+	mockClient.CreateOrUpdateObjectInMap(ws)
+	npMap := mockClient.CreateMapWithType(&karpenterv1.NodePoolList{})
+	npMap[client.ObjectKeyFromObject(np)] = np
+	ncMap := mockClient.CreateMapWithType(&karpenterv1.NodeClaimList{})
+	ncMap[client.ObjectKeyFromObject(nc)] = nc
+
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything,
+		mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(nil)
+	mockClient.On("List", mock.IsType(context.Background()),
+		mock.IsType(&karpenterv1.NodePoolList{}), mock.Anything).Return(nil)
+	mockClient.On("List", mock.IsType(context.Background()),
+		mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything,
+		mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
+	mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
+		mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
+
+	mockProv := &mockProvisioner{}
+
+	recorder := record.NewFakeRecorder(10)
+	r := NewDriftReconciler(mockClient, nil, recorder, mockProv)
+	result, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "my-infset", Namespace: "default"},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, result.RequeueAfter > 0)
+
+	mockProv.AssertNotCalled(t, "EnableDriftRemediation", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Patch", 1)
+}
+
 func TestReconcile_UpgradingStillDrifted_Requeues(t *testing.T) {
 	mockClient := test.NewClient()
 
@@ -523,6 +574,8 @@ func TestReconcile_MultipleDrifted_OnlyOneEnabled(t *testing.T) {
 		mock.IsType(&karpenterv1.NodePoolList{}), mock.Anything).Return(nil)
 	mockClient.On("List", mock.IsType(context.Background()),
 		mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything,
+		mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
 
 	mockProv := &mockProvisioner{}
 	mockProv.On("EnableDriftRemediation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
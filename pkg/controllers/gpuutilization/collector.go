@@ -0,0 +1,428 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gpuutilization reads the DCGM exporter metrics of the nodes workspaces run on and
+// rolls them up per workspace into the workspace status and the controller metrics. When
+// the GPUs of a workspace stay underused, it recommends a smaller instance type.
+package gpuutilization
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
+)
+
+const (
+	// DefaultInterval is the default interval between two collections.
+	DefaultInterval = time.Minute
+
+	// DefaultExporterNamespace is the namespace the NVIDIA GPU operator deploys the DCGM
+	// exporter to.
+	DefaultExporterNamespace = "gpu-operator"
+
+	// DefaultExporterSelector selects the DCGM exporter pods deployed by the NVIDIA GPU operator.
+	DefaultExporterSelector = "app=nvidia-dcgm-exporter"
+
+	// DefaultExporterPort is the port the DCGM exporter serves its metrics on.
+	DefaultExporterPort = 9400
+
+	// DCGM fields read from the exporter. The framebuffer fields are in MiB.
+	metricGPUUtil = "DCGM_FI_DEV_GPU_UTIL"
+	metricFBUsed  = "DCGM_FI_DEV_FB_USED"
+	metricFBFree  = "DCGM_FI_DEV_FB_FREE"
+
+	// scrapeTimeout bounds the scrape of one exporter, and maxScrapeBytes the size of its
+	// response, since a pod matching the selector is not necessarily a DCGM exporter.
+	scrapeTimeout  = 10 * time.Second
+	maxScrapeBytes = 16 << 20
+	// maxConcurrentScrapes is the number of exporters scraped at the same time.
+	maxConcurrentScrapes = 16
+
+	mib = 1 << 20
+)
+
+var (
+	workspaceGPUUtilization = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_workspace_gpu_utilization_ratio",
+			Help: "Average utilization of the GPUs of a Workspace, from 0 to 1, read from the DCGM exporter",
+		},
+		metriclabels.PerWorkspace,
+	)
+
+	workspaceGPUMemoryUsed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_workspace_gpu_memory_used_bytes",
+			Help: "GPU memory in use by a Workspace, summed over its GPUs, read from the DCGM exporter",
+		},
+		metriclabels.PerWorkspace,
+	)
+
+	workspaceGPUMemoryTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_workspace_gpu_memory_total_bytes",
+			Help: "GPU memory of the GPUs of a Workspace, read from the DCGM exporter",
+		},
+		metriclabels.PerWorkspace,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(workspaceGPUUtilization)
+	metrics.Registry.MustRegister(workspaceGPUMemoryUsed)
+	metrics.Registry.MustRegister(workspaceGPUMemoryTotal)
+}
+
+// Collector periodically scrapes the DCGM exporter on every node a Workspace runs on and
+// writes the GPU utilization of each Workspace into status.gpuUtilization and the
+// kaito_workspace_gpu_* gauges.
+type Collector struct {
+	// Client reads and updates the Workspaces.
+	Client client.Client
+	// ExporterPods lists the exporter pods. The exporter usually runs in a namespace the
+	// manager cache does not watch, so it is read from the cache of NewExporterCache.
+	ExporterPods client.Reader
+	// ExporterNamespace is the namespace of the DCGM exporter pods.
+	ExporterNamespace string
+	// ExporterSelector is the label selector of the DCGM exporter pods.
+	ExporterSelector string
+	// ExporterPort is the port the exporter pods serve their metrics on.
+	ExporterPort int
+	Interval     time.Duration
+	// HTTPClient scrapes the exporters. http.DefaultClient is used when it is nil.
+	HTTPClient *http.Client
+
+	// LowUtilizationThreshold is the utilization, in percent, below which a Workspace is
+	// underused, and RightSizingWindow how long it must stay underused before a smaller
+	// instance type from SKUHandler is recommended. No recommendation is made without a
+	// SKUHandler.
+	LowUtilizationThreshold int32
+	RightSizingWindow       time.Duration
+	SKUHandler              sku.CloudSKUHandler
+	// Recorder emits an event on the Workspace when a new recommendation is made.
+	Recorder record.EventRecorder
+}
+
+// Start implements manager.Runnable.
+func (c *Collector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.collect(ctx, time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *Collector) NeedLeaderElection() bool { return true }
+
+// gpuSample holds the DCGM fields of one GPU.
+type gpuSample struct {
+	// pod and namespace are the pod the GPU is allocated to. They are only set when the
+	// exporter maps GPUs to pods.
+	pod, namespace string
+	util           float64
+	fbUsed, fbFree float64
+}
+
+// rollup accumulates the GPUs of one Workspace.
+type rollup struct {
+	gpus                    int32
+	utilSum                 float64
+	memoryUsed, memoryTotal float64
+}
+
+func (c *Collector) collect(ctx context.Context, now time.Time) {
+	workspaces := &kaitov1beta1.WorkspaceList{}
+	if err := c.Client.List(ctx, workspaces); err != nil {
+		klog.ErrorS(err, "GPUUtilizationCollector: failed to list workspaces")
+		return
+	}
+
+	// Index the workspaces by the nodes and the pods they run on.
+	byNode := map[string][]*kaitov1beta1.Workspace{}
+	byPod := map[client.ObjectKey]*kaitov1beta1.Workspace{}
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		nodes := map[string]bool{}
+		for _, node := range ws.Status.WorkerNodes {
+			nodes[node] = true
+		}
+		for _, replica := range ws.Status.Replicas {
+			byPod[client.ObjectKey{Namespace: ws.Namespace, Name: replica.PodName}] = ws
+			if replica.NodeName != "" {
+				nodes[replica.NodeName] = true
+			}
+		}
+		for node := range nodes {
+			byNode[node] = append(byNode[node], ws)
+		}
+	}
+
+	samples := c.scrapeNodes(ctx, byNode)
+
+	rollups := map[*kaitov1beta1.Workspace]*rollup{}
+	for node, gpus := range samples {
+		for _, gpu := range gpus {
+			ws := owner(gpu, byNode[node], byPod)
+			if ws == nil {
+				continue
+			}
+			r := rollups[ws]
+			if r == nil {
+				r = &rollup{}
+				rollups[ws] = r
+			}
+			r.gpus++
+			r.utilSum += gpu.util
+			r.memoryUsed += gpu.fbUsed * mib
+			r.memoryTotal += (gpu.fbUsed + gpu.fbFree) * mib
+		}
+	}
+
+	workspaceGPUUtilization.Reset()
+	workspaceGPUMemoryUsed.Reset()
+	workspaceGPUMemoryTotal.Reset()
+	for ws, r := range rollups {
+		labels := metriclabels.PerWorkspaceValues(ws)
+		workspaceGPUUtilization.WithLabelValues(labels...).Set(r.utilSum / float64(r.gpus) / 100)
+		workspaceGPUMemoryUsed.WithLabelValues(labels...).Set(r.memoryUsed)
+		workspaceGPUMemoryTotal.WithLabelValues(labels...).Set(r.memoryTotal)
+
+		var recommended *kaitov1beta1.RightSizingRecommendation
+		err := statuspatch.Update(ctx, c.Client, client.ObjectKeyFromObject(ws), &kaitov1beta1.Workspace{}, func(latest *kaitov1beta1.Workspace) error {
+			utilization := r.status(now)
+			utilization.LowUtilizationSince = lowUtilizationSince(latest.Status.GPUUtilization,
+				utilization.UtilizationPercent, c.LowUtilizationThreshold, utilization.LastSampleTime)
+			latest.Status.GPUUtilization = utilization
+
+			rec := c.recommend(latest, utilization, utilization.LastSampleTime)
+			recommended = nil
+			if rec != nil && (latest.Status.RightSizing == nil || latest.Status.RightSizing.InstanceType != rec.InstanceType) {
+				recommended = rec
+			}
+			applyRecommendation(&latest.Status, latest.Generation, rec)
+			return nil
+		})
+		if err != nil {
+			klog.ErrorS(err, "GPUUtilizationCollector: failed to update workspace status", "workspace", klog.KObj(ws))
+			continue
+		}
+		if recommended != nil && c.Recorder != nil {
+			c.Recorder.Event(ws, corev1.EventTypeNormal, string(kaitov1beta1.WorkspaceConditionTypeRightSizingRecommended), recommended.Message)
+		}
+	}
+}
+
+func (r *rollup) status(now time.Time) *kaitov1beta1.GPUUtilizationStatus {
+	return &kaitov1beta1.GPUUtilizationStatus{
+		GPUCount:           r.gpus,
+		UtilizationPercent: int32(r.utilSum/float64(r.gpus) + 0.5),
+		MemoryUsed:         *resource.NewQuantity(int64(r.memoryUsed), resource.BinarySI),
+		MemoryTotal:        *resource.NewQuantity(int64(r.memoryTotal), resource.BinarySI),
+		LastSampleTime:     metav1.NewTime(now.Truncate(time.Second)),
+	}
+}
+
+// owner returns the Workspace a GPU belongs to: the owner of the pod the exporter mapped
+// it to, or else the only Workspace on its node. A GPU of a node shared by several
+// Workspaces cannot be attributed without the pod mapping and is skipped.
+func owner(gpu gpuSample, onNode []*kaitov1beta1.Workspace, byPod map[client.ObjectKey]*kaitov1beta1.Workspace) *kaitov1beta1.Workspace {
+	if gpu.pod != "" {
+		return byPod[client.ObjectKey{Namespace: gpu.namespace, Name: gpu.pod}]
+	}
+	if len(onNode) == 1 {
+		return onNode[0]
+	}
+	return nil
+}
+
+// scrapeNodes scrapes the exporter pod of each of the nodes and returns the GPUs by node.
+func (c *Collector) scrapeNodes(ctx context.Context, nodes map[string][]*kaitov1beta1.Workspace) map[string][]gpuSample {
+	if len(nodes) == 0 {
+		return nil
+	}
+	selector, err := labels.Parse(c.ExporterSelector)
+	if err != nil {
+		klog.ErrorS(err, "GPUUtilizationCollector: invalid DCGM exporter selector", "selector", c.ExporterSelector)
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := c.ExporterPods.List(ctx, pods, client.InNamespace(c.ExporterNamespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		klog.ErrorS(err, "GPUUtilizationCollector: failed to list DCGM exporter pods", "namespace", c.ExporterNamespace, "selector", c.ExporterSelector)
+		return nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	samples := map[string][]gpuSample{}
+	sem := make(chan struct{}, maxConcurrentScrapes)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, ok := nodes[pod.Spec.NodeName]; !ok || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			gpus, err := c.scrape(ctx, pod.Status.PodIP)
+			if err != nil {
+				klog.ErrorS(err, "GPUUtilizationCollector: failed to scrape DCGM exporter", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			samples[pod.Spec.NodeName] = gpus
+		}()
+	}
+	wg.Wait()
+	return samples
+}
+
+// NewExporterCache returns a cache of the DCGM exporter pods in namespace that match
+// selector. It must be added to the manager, which starts it with its other caches.
+func NewExporterCache(cfg *rest.Config, scheme *runtime.Scheme, namespace, selector string) (cache.Cache, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("the namespace of the DCGM exporter is required")
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DCGM exporter selector %q: %w", selector, err)
+	}
+	return cache.New(cfg, cache.Options{
+		Scheme:            scheme,
+		DefaultNamespaces: map[string]cache.Config{namespace: {}},
+		ByObject:          map[client.Object]cache.ByObject{&corev1.Pod{}: {Label: parsed}},
+		DefaultTransform:  cache.TransformStripManagedFields(),
+	})
+}
+
+// scrape reads the GPUs reported by the exporter serving on podIP.
+func (c *Collector) scrape(ctx context.Context, podIP string) ([]gpuSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, scrapeTimeout)
+	defer cancel()
+	url := "http://" + net.JoinHostPort(podIP, strconv.Itoa(c.ExporterPort)) + "/metrics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return parseGPUSamples(io.LimitReader(resp.Body, maxScrapeBytes))
+}
+
+// parseGPUSamples parses the exposition of a DCGM exporter into one sample per GPU. GPUs
+// that do not report their utilization are left out.
+func parseGPUSamples(in io.Reader) ([]gpuSample, error) {
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DCGM exporter metrics: %w", err)
+	}
+
+	byGPU := map[string]*gpuSample{}
+	var order []string
+	read := func(name string, set func(*gpuSample, float64)) {
+		family, ok := families[name]
+		if !ok {
+			return
+		}
+		for _, m := range family.GetMetric() {
+			labels := labelMap(m)
+			key := labels["UUID"]
+			if key == "" {
+				key = labels["gpu"]
+			}
+			sample, ok := byGPU[key]
+			if !ok {
+				sample = &gpuSample{pod: labels["pod"], namespace: labels["namespace"]}
+				byGPU[key] = sample
+				order = append(order, key)
+			}
+			set(sample, value(m))
+		}
+	}
+	hasUtil := map[*gpuSample]bool{}
+	read(metricGPUUtil, func(s *gpuSample, v float64) { s.util, hasUtil[s] = v, true })
+	read(metricFBUsed, func(s *gpuSample, v float64) { s.fbUsed = v })
+	read(metricFBFree, func(s *gpuSample, v float64) { s.fbFree = v })
+
+	gpus := make([]gpuSample, 0, len(order))
+	for _, key := range order {
+		if sample := byGPU[key]; hasUtil[sample] {
+			gpus = append(gpus, *sample)
+		}
+	}
+	return gpus, nil
+}
+
+// value returns the value of a sample. The exporter declares the fields as gauges, but
+// the type comment may be missing, in which case they are parsed as untyped.
+func value(m *dto.Metric) float64 {
+	if m.GetGauge() != nil {
+		return m.GetGauge().GetValue()
+	}
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
+
+func labelMap(m *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(m.GetLabel()))
+	for _, l := range m.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
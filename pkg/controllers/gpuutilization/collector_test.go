@@ -0,0 +1,135 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpuutilization
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+const exposition = `# TYPE DCGM_FI_DEV_GPU_UTIL gauge
+DCGM_FI_DEV_GPU_UTIL{gpu="0",UUID="GPU-a",Hostname="node-a"} 80
+DCGM_FI_DEV_GPU_UTIL{gpu="1",UUID="GPU-b",Hostname="node-a"} 20
+DCGM_FI_DEV_GPU_UTIL{gpu="2",UUID="GPU-c",Hostname="node-a",pod="other",namespace="default"} 100
+# TYPE DCGM_FI_DEV_FB_USED gauge
+DCGM_FI_DEV_FB_USED{gpu="0",UUID="GPU-a",Hostname="node-a"} 1024
+DCGM_FI_DEV_FB_USED{gpu="1",UUID="GPU-b",Hostname="node-a"} 2048
+DCGM_FI_DEV_FB_USED{gpu="3",UUID="GPU-d",Hostname="node-a"} 2048
+# TYPE DCGM_FI_DEV_FB_FREE gauge
+DCGM_FI_DEV_FB_FREE{gpu="0",UUID="GPU-a",Hostname="node-a"} 3072
+DCGM_FI_DEV_FB_FREE{gpu="1",UUID="GPU-b",Hostname="node-a"} 2048
+`
+
+func TestParseGPUSamples(t *testing.T) {
+	gpus, err := parseGPUSamples(strings.NewReader(exposition))
+	require.NoError(t, err)
+	// GPU-d does not report its utilization and is left out.
+	assert.Equal(t, []gpuSample{
+		{util: 80, fbUsed: 1024, fbFree: 3072},
+		{util: 20, fbUsed: 2048, fbFree: 2048},
+		{pod: "other", namespace: "default", util: 100},
+	}, gpus)
+
+	_, err = parseGPUSamples(strings.NewReader("not metrics {"))
+	assert.Error(t, err)
+}
+
+func TestOwner(t *testing.T) {
+	a := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+	b := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}}
+	byPod := map[client.ObjectKey]*kaitov1beta1.Workspace{{Namespace: "default", Name: "b-0"}: b}
+
+	assert.Equal(t, a, owner(gpuSample{}, []*kaitov1beta1.Workspace{a}, byPod))
+	assert.Nil(t, owner(gpuSample{}, []*kaitov1beta1.Workspace{a, b}, byPod), "a shared node needs the pod mapping")
+	assert.Equal(t, b, owner(gpuSample{pod: "b-0", namespace: "default"}, []*kaitov1beta1.Workspace{a, b}, byPod))
+	assert.Nil(t, owner(gpuSample{pod: "other", namespace: "default"}, []*kaitov1beta1.Workspace{a}, byPod))
+}
+
+func TestCollect(t *testing.T) {
+	var scrapes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scrapes.Add(1)
+		_, _ = w.Write([]byte(exposition))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	exporterPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+		},
+		Status: kaitov1beta1.WorkspaceStatus{WorkerNodes: []string{"node-a"}},
+	}
+	idle := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"}}
+	kClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, idle).WithStatusSubresource(ws, idle).Build()
+
+	exporter := func(name, namespace, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "nvidia-dcgm-exporter"}},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: host},
+		}
+	}
+	// Only the exporter on the node of the workspace, in the exporter namespace, is scraped.
+	pods := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		exporter("exporter-a", DefaultExporterNamespace, "node-a"),
+		exporter("exporter-b", DefaultExporterNamespace, "node-b"),
+		exporter("impostor", "default", "node-a"),
+	).Build()
+
+	c := &Collector{Client: kClient, ExporterPods: pods, ExporterNamespace: DefaultExporterNamespace, ExporterSelector: DefaultExporterSelector, ExporterPort: exporterPort}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.collect(context.Background(), now)
+	assert.Equal(t, int32(1), scrapes.Load())
+
+	got := &kaitov1beta1.Workspace{}
+	require.NoError(t, kClient.Get(context.Background(), client.ObjectKeyFromObject(ws), got))
+	require.NotNil(t, got.Status.GPUUtilization)
+	assert.Equal(t, int32(2), got.Status.GPUUtilization.GPUCount)
+	assert.Equal(t, int32(50), got.Status.GPUUtilization.UtilizationPercent)
+	assert.True(t, resource.MustParse("3Gi").Equal(got.Status.GPUUtilization.MemoryUsed))
+	assert.True(t, resource.MustParse("8Gi").Equal(got.Status.GPUUtilization.MemoryTotal))
+	assert.True(t, got.Status.GPUUtilization.LastSampleTime.Equal(&metav1.Time{Time: now}))
+	assert.Equal(t, 0.5, testutil.ToFloat64(workspaceGPUUtilization.WithLabelValues("ws", "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4")))
+
+	require.NoError(t, kClient.Get(context.Background(), client.ObjectKeyFromObject(idle), got))
+	assert.Nil(t, got.Status.GPUUtilization, "workspaces without GPU samples are left alone")
+}
@@ -0,0 +1,167 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpuutilization
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+)
+
+const (
+	// DefaultLowUtilizationThreshold is the GPU utilization, in percent, below which a
+	// Workspace counts as underused.
+	DefaultLowUtilizationThreshold = 15
+
+	// DefaultRightSizingWindow is how long a Workspace must stay underused before a
+	// smaller instance type is recommended.
+	DefaultRightSizingWindow = 7 * 24 * time.Hour
+
+	// minRecommendedContextLen is the number of tokens of KV cache a recommended instance
+	// type must hold next to the model weights.
+	minRecommendedContextLen = 4096
+
+	reasonLowGPUUtilization = "LowGPUUtilization"
+)
+
+// lowUtilizationSince returns when the current run of samples below threshold started,
+// or nil if the latest sample is at or above it.
+func lowUtilizationSince(previous *kaitov1beta1.GPUUtilizationStatus, utilizationPercent, threshold int32, now metav1.Time) *metav1.Time {
+	if utilizationPercent >= threshold {
+		return nil
+	}
+	if previous != nil && previous.LowUtilizationSince != nil {
+		return previous.LowUtilizationSince.DeepCopy()
+	}
+	return &now
+}
+
+// recommend returns the right-sizing recommendation of ws given its latest GPU
+// utilization, or nil if none applies. Only single-node Workspaces serving a built-in
+// preset on a known instance type are considered, since the memory the model needs can
+// only be estimated for them.
+func (c *Collector) recommend(ws *kaitov1beta1.Workspace, utilization *kaitov1beta1.GPUUtilizationStatus, now metav1.Time) *kaitov1beta1.RightSizingRecommendation {
+	since := utilization.LowUtilizationSince
+	if since == nil || now.Sub(since.Time) < c.RightSizingWindow || c.SKUHandler == nil {
+		return nil
+	}
+	if ws.Inference == nil || ws.Inference.Preset == nil || ws.Resource.InstanceType == "" ||
+		ws.Resource.Partition != nil || ws.Status.TargetNodeCount > 1 {
+		return nil
+	}
+	preset := plugin.KaitoModelRegister.MustGet(string(ws.Inference.Preset.Name))
+	if preset == nil {
+		return nil
+	}
+	params := preset.GetInferenceParameters()
+	if params == nil || params.TotalSafeTensorFileSize == "" || params.BytesPerToken <= 0 {
+		return nil
+	}
+	modelSize, err := resource.ParseQuantity(params.TotalSafeTensorFileSize)
+	if err != nil {
+		return nil
+	}
+	current := c.SKUHandler.GetGPUConfigBySKU(ws.EffectiveInstanceType())
+	if current == nil {
+		return nil
+	}
+	candidate := smallestFittingSKU(c.SKUHandler, current, float64(modelSize.Value()), params.BytesPerToken)
+	if candidate == nil {
+		return nil
+	}
+	return &kaitov1beta1.RightSizingRecommendation{
+		InstanceType: candidate.SKU,
+		Message: fmt.Sprintf("%s utilization below %d%% for %s, consider %s (%d x %s, %s)",
+			current.GPUModel, c.LowUtilizationThreshold, formatWindow(c.RightSizingWindow),
+			candidate.SKU, candidate.GPUCount, candidate.GPUModel, candidate.GPUMem.String()),
+		GeneratedTime: now,
+	}
+}
+
+// smallestFittingSKU returns the instance type with the least GPU memory that is smaller
+// than current, has the same CPU architecture, confidential computing mode and bfloat16
+// support, and holds the weights of the model with minRecommendedContextLen tokens of KV
+// cache on a single node. Ties go to fewer GPUs, then to the SKU name.
+func smallestFittingSKU(handler sku.CloudSKUHandler, current *sku.GPUConfig, modelSize float64, bytesPerToken int) *sku.GPUConfig {
+	var best *sku.GPUConfig
+	for _, name := range handler.GetSupportedSKUs() {
+		cfg := handler.GetGPUConfigBySKU(name)
+		if cfg == nil || cfg.IsMIG || cfg.GPUCount <= 0 || cfg.GPUMem.Cmp(current.GPUMem) >= 0 ||
+			cfg.Architecture() != current.Architecture() || cfg.ConfidentialCompute != current.ConfidentialCompute ||
+			(current.SupportsBFloat16() && !cfg.SupportsBFloat16()) {
+			continue
+		}
+		perGPU := float64(cfg.GPUMem.Value()) / float64(cfg.GPUCount)
+		if model.MaxContextLen(perGPU, cfg.GPUCount, model.GPUMemoryUtilization, modelSize, bytesPerToken) < minRecommendedContextLen {
+			continue
+		}
+		if best == nil || smallerSKU(cfg, best) {
+			best = cfg
+		}
+	}
+	return best
+}
+
+func smallerSKU(a, b *sku.GPUConfig) bool {
+	if c := a.GPUMem.Cmp(b.GPUMem); c != 0 {
+		return c < 0
+	}
+	if a.GPUCount != b.GPUCount {
+		return a.GPUCount < b.GPUCount
+	}
+	return a.SKU < b.SKU
+}
+
+// applyRecommendation sets status.rightSizing and the RightSizingRecommended condition
+// from rec, or removes them when rec is nil. A recommendation of the same instance type
+// keeps its original time, so the status only changes when the advice does.
+func applyRecommendation(status *kaitov1beta1.WorkspaceStatus, generation int64, rec *kaitov1beta1.RightSizingRecommendation) {
+	conditionType := string(kaitov1beta1.WorkspaceConditionTypeRightSizingRecommended)
+	if rec == nil {
+		status.RightSizing = nil
+		meta.RemoveStatusCondition(&status.Conditions, conditionType)
+		return
+	}
+	if status.RightSizing == nil || status.RightSizing.InstanceType != rec.InstanceType || status.RightSizing.Message != rec.Message {
+		status.RightSizing = rec.DeepCopy()
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonLowGPUUtilization,
+		Message:            rec.Message,
+		ObservedGeneration: generation,
+	})
+}
+
+// formatWindow formats a right-sizing window in days when it is a whole number of them.
+func formatWindow(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d == day:
+		return "1 day"
+	case d > 0 && d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	default:
+		return d.String()
+	}
+}
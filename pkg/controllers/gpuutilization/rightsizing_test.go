@@ -0,0 +1,205 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpuutilization
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+)
+
+var testSKUs = sku.NewGeneralSKUHandler([]sku.GPUConfig{
+	{SKU: "Standard_NC24ads_A100_v4", GPUCount: 1, GPUMem: resource.MustParse("80Gi"), GPUModel: "NVIDIA A100", CUDAComputeCapability: 8.0},
+	{SKU: "Standard_NV36ads_A10_v5", GPUCount: 1, GPUMem: resource.MustParse("24Gi"), GPUModel: "NVIDIA A10", CUDAComputeCapability: 8.6},
+	{SKU: "Standard_NV72ads_A10_v5", GPUCount: 2, GPUMem: resource.MustParse("48Gi"), GPUModel: "NVIDIA A10", CUDAComputeCapability: 8.6},
+	{SKU: "Standard_NC4as_T4_v3", GPUCount: 1, GPUMem: resource.MustParse("16Gi"), GPUModel: "NVIDIA T4", CUDAComputeCapability: 7.5},
+	{SKU: "Standard_ND40rs_v2", GPUCount: 8, GPUMem: resource.MustParse("256Gi"), GPUModel: "NVIDIA V100", CUDAComputeCapability: 7.0},
+})
+
+func TestLowUtilizationSince(t *testing.T) {
+	now := metav1.NewTime(time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(now.Add(-24 * time.Hour))
+	low := &kaitov1beta1.GPUUtilizationStatus{LowUtilizationSince: &earlier}
+
+	assert.Nil(t, lowUtilizationSince(low, 15, 15, now), "a sample at the threshold ends the run")
+	assert.Equal(t, &now, lowUtilizationSince(nil, 3, 15, now))
+	assert.Equal(t, &now, lowUtilizationSince(&kaitov1beta1.GPUUtilizationStatus{}, 3, 15, now))
+	assert.Equal(t, &earlier, lowUtilizationSince(low, 3, 15, now))
+}
+
+func TestSmallestFittingSKU(t *testing.T) {
+	a100 := testSKUs.GetGPUConfigBySKU("Standard_NC24ads_A100_v4")
+
+	got := smallestFittingSKU(testSKUs, a100, 13.44*(1<<30), 8192)
+	require.NotNil(t, got)
+	// The T4 is smaller but has no bfloat16 support.
+	assert.Equal(t, "Standard_NV36ads_A10_v5", got.SKU)
+
+	got = smallestFittingSKU(testSKUs, a100, 30*(1<<30), 8192)
+	require.NotNil(t, got)
+	assert.Equal(t, "Standard_NV72ads_A10_v5", got.SKU)
+
+	assert.Nil(t, smallestFittingSKU(testSKUs, a100, 60*(1<<30), 8192), "no smaller SKU holds the model")
+}
+
+func TestRecommend(t *testing.T) {
+	test.RegisterTestModel()
+	now := metav1.NewTime(time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC))
+	eightDaysAgo := metav1.NewTime(now.Add(-8 * 24 * time.Hour))
+	sixDaysAgo := metav1.NewTime(now.Add(-6 * 24 * time.Hour))
+	newWorkspace := func(preset, instanceType string) *kaitov1beta1.Workspace {
+		return &kaitov1beta1.Workspace{
+			Resource: kaitov1beta1.ResourceSpec{InstanceType: instanceType},
+			Inference: &kaitov1beta1.InferenceSpec{
+				Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: kaitov1beta1.ModelName(preset)}},
+			},
+		}
+	}
+	c := &Collector{LowUtilizationThreshold: 15, RightSizingWindow: DefaultRightSizingWindow, SKUHandler: testSKUs}
+
+	tests := []struct {
+		name         string
+		ws           *kaitov1beta1.Workspace
+		since        *metav1.Time
+		instanceType string
+	}{
+		{name: "underused for the window", ws: newWorkspace("test-falcon-7b", "Standard_NC24ads_A100_v4"), since: &eightDaysAgo, instanceType: "Standard_NV36ads_A10_v5"},
+		{name: "underused for less than the window", ws: newWorkspace("test-falcon-7b", "Standard_NC24ads_A100_v4"), since: &sixDaysAgo},
+		{name: "not underused", ws: newWorkspace("test-falcon-7b", "Standard_NC24ads_A100_v4")},
+		{name: "unknown preset", ws: newWorkspace("not-a-preset", "Standard_NC24ads_A100_v4"), since: &eightDaysAgo},
+		{name: "unknown instance type", ws: newWorkspace("test-falcon-7b", "Standard_Unknown"), since: &eightDaysAgo},
+		{name: "BYO nodes", ws: newWorkspace("test-falcon-7b", ""), since: &eightDaysAgo},
+		{name: "already the smallest", ws: newWorkspace("test-falcon-7b", "Standard_NV36ads_A10_v5"), since: &eightDaysAgo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := c.recommend(tt.ws, &kaitov1beta1.GPUUtilizationStatus{LowUtilizationSince: tt.since}, now)
+			if tt.instanceType == "" {
+				assert.Nil(t, rec)
+				return
+			}
+			require.NotNil(t, rec)
+			assert.Equal(t, tt.instanceType, rec.InstanceType)
+			assert.Equal(t, "NVIDIA A100 utilization below 15% for 7 days, consider Standard_NV36ads_A10_v5 (1 x NVIDIA A10, 24Gi)", rec.Message)
+			assert.Equal(t, now, rec.GeneratedTime)
+		})
+	}
+}
+
+func TestApplyRecommendation(t *testing.T) {
+	first := metav1.NewTime(time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(first.Add(time.Hour))
+	rec := func(instanceType string, at metav1.Time) *kaitov1beta1.RightSizingRecommendation {
+		return &kaitov1beta1.RightSizingRecommendation{InstanceType: instanceType, Message: "consider " + instanceType, GeneratedTime: at}
+	}
+	status := &kaitov1beta1.WorkspaceStatus{}
+
+	applyRecommendation(status, 2, rec("a", first))
+	cond := meta.FindStatusCondition(status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeRightSizingRecommended))
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "consider a", cond.Message)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+
+	applyRecommendation(status, 2, rec("a", later))
+	assert.Equal(t, first, status.RightSizing.GeneratedTime, "the same advice keeps its time")
+
+	applyRecommendation(status, 2, rec("b", later))
+	assert.Equal(t, rec("b", later), status.RightSizing)
+
+	applyRecommendation(status, 2, nil)
+	assert.Nil(t, status.RightSizing)
+	assert.Empty(t, status.Conditions)
+}
+
+func TestCollectRecommends(t *testing.T) {
+	test.RegisterTestModel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("DCGM_FI_DEV_GPU_UTIL{gpu=\"0\",UUID=\"GPU-a\"} 4\n"))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	exporterPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+	since := metav1.NewTime(now.Add(-8 * 24 * time.Hour))
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "test-falcon-7b"}},
+		},
+		Status: kaitov1beta1.WorkspaceStatus{
+			WorkerNodes:    []string{"node-a"},
+			GPUUtilization: &kaitov1beta1.GPUUtilizationStatus{LowUtilizationSince: &since},
+		},
+	}
+	kClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).WithStatusSubresource(ws).Build()
+	pods := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "exporter", Namespace: DefaultExporterNamespace, Labels: map[string]string{"app": "nvidia-dcgm-exporter"}},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: host},
+	}).Build()
+	recorder := record.NewFakeRecorder(10)
+	c := &Collector{
+		Client:                  kClient,
+		ExporterPods:            pods,
+		ExporterNamespace:       DefaultExporterNamespace,
+		ExporterSelector:        DefaultExporterSelector,
+		ExporterPort:            exporterPort,
+		LowUtilizationThreshold: DefaultLowUtilizationThreshold,
+		RightSizingWindow:       DefaultRightSizingWindow,
+		SKUHandler:              testSKUs,
+		Recorder:                recorder,
+	}
+
+	c.collect(context.Background(), now)
+	got := &kaitov1beta1.Workspace{}
+	require.NoError(t, kClient.Get(context.Background(), client.ObjectKeyFromObject(ws), got))
+	assert.True(t, since.Equal(got.Status.GPUUtilization.LowUtilizationSince))
+	require.NotNil(t, got.Status.RightSizing)
+	assert.Equal(t, "Standard_NV36ads_A10_v5", got.Status.RightSizing.InstanceType)
+	assert.Equal(t, "Standard_NC24ads_A100_v4", got.Resource.InstanceType, "the recommendation is not applied")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal RightSizingRecommended NVIDIA A100 utilization below 15% for 7 days")
+
+	// The same advice is not announced again.
+	c.collect(context.Background(), now.Add(time.Minute))
+	assert.Empty(t, recorder.Events)
+}
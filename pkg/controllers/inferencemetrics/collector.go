@@ -0,0 +1,475 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inferencemetrics scrapes the metrics of the vLLM servers of inference workspaces
+// and republishes them per workspace under the kaito_workspace_inference_* names, so
+// dashboards get token throughput, latency and queue depth without scraping every pod.
+package inferencemetrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
+)
+
+const (
+	// DefaultInterval is the default interval between two collections.
+	DefaultInterval = 30 * time.Second
+
+	// scrapeTimeout bounds the scrape of one pod, and maxScrapeBytes the size of its response.
+	scrapeTimeout  = 10 * time.Second
+	maxScrapeBytes = 16 << 20
+	// maxConcurrentScrapes is the number of pods scraped at the same time.
+	maxConcurrentScrapes = 16
+)
+
+// republished maps vLLM metrics to the metrics republished per workspace. Sources lists the
+// vLLM names in order of preference, since vLLM renamed some of them between releases.
+type republished struct {
+	sources []string
+	desc    *prometheus.Desc
+}
+
+func newRepublished(name, help string, sources ...string) republished {
+	return republished{sources: sources, desc: prometheus.NewDesc(name, help, metriclabels.PerWorkspace, nil)}
+}
+
+var (
+	// counters are summed over the pods of a workspace. Counter resets of restarted pods are
+	// folded in, so the republished counters only go down when the controller restarts.
+	counters = []republished{
+		newRepublished("kaito_workspace_inference_prompt_tokens_total",
+			"Prompt tokens processed by the inference servers of a Workspace", "vllm:prompt_tokens_total"),
+		newRepublished("kaito_workspace_inference_generation_tokens_total",
+			"Tokens generated by the inference servers of a Workspace", "vllm:generation_tokens_total"),
+		newRepublished("kaito_workspace_inference_requests_total",
+			"Requests completed by the inference servers of a Workspace", "vllm:request_success_total"),
+	}
+
+	// gauges are summed over the pods of a workspace as of the last collection.
+	gauges = []republished{
+		newRepublished("kaito_workspace_inference_requests_running",
+			"Requests being processed by the inference servers of a Workspace", "vllm:num_requests_running"),
+		newRepublished("kaito_workspace_inference_requests_waiting",
+			"Requests queued by the inference servers of a Workspace", "vllm:num_requests_waiting"),
+	}
+
+	// histograms are merged over the pods of a workspace like counters.
+	histograms = []republished{
+		newRepublished("kaito_workspace_inference_time_to_first_token_seconds",
+			"Time to the first generated token of the requests of a Workspace", "vllm:time_to_first_token_seconds"),
+		newRepublished("kaito_workspace_inference_inter_token_latency_seconds",
+			"Latency between two generated tokens of the requests of a Workspace",
+			"vllm:inter_token_latency_seconds", "vllm:time_per_output_token_seconds"),
+		newRepublished("kaito_workspace_inference_request_latency_seconds",
+			"End-to-end latency of the requests of a Workspace", "vllm:e2e_request_latency_seconds"),
+		newRepublished("kaito_workspace_inference_queue_time_seconds",
+			"Time the requests of a Workspace spent queued", "vllm:request_queue_time_seconds"),
+	}
+)
+
+// histogram is a histogram with cumulative bucket counts by upper bound.
+type histogram struct {
+	count, sum float64
+	buckets    map[float64]float64
+}
+
+// sample holds the metrics of one pod, or the totals of one workspace, by republished name.
+type sample struct {
+	values     map[string]float64
+	histograms map[string]*histogram
+}
+
+func newSample() *sample {
+	return &sample{values: map[string]float64{}, histograms: map[string]*histogram{}}
+}
+
+// series holds the republished metrics of one workspace.
+type series struct {
+	labels []string
+	totals *sample
+	gauges map[string]float64
+}
+
+// Collector periodically scrapes the vLLM metrics endpoint of the serving pods of every
+// inference Workspace and exports the kaito_workspace_inference_* metrics. It is a
+// prometheus.Collector registered while the collector runs, i.e. on the leader only.
+type Collector struct {
+	// Client lists the Workspaces and their pods.
+	Client   client.Client
+	Interval time.Duration
+	// Port is the port the inference servers serve their metrics on. The inference server
+	// port is used when it is zero.
+	Port int
+	// HTTPClient scrapes the pods. http.DefaultClient is used when it is nil.
+	HTTPClient *http.Client
+
+	mu sync.Mutex
+	// started is when the collector started. The counters of pods that started earlier
+	// are only counted from their first scrape on.
+	started time.Time
+	// last is the previous scrape of each pod.
+	last map[types.UID]*sample
+	// workspaces holds the republished metrics by Workspace.
+	workspaces map[client.ObjectKey]*series
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Start implements manager.Runnable.
+func (c *Collector) Start(ctx context.Context) error {
+	c.mu.Lock()
+	c.started = time.Now()
+	c.mu.Unlock()
+	if err := metrics.Registry.Register(c); err != nil {
+		return fmt.Errorf("failed to register the inference metrics: %w", err)
+	}
+	defer metrics.Registry.Unregister(c)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.collect(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *Collector) NeedLeaderElection() bool { return true }
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, group := range [][]republished{counters, gauges, histograms} {
+		for _, r := range group {
+			ch <- r.desc
+		}
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.workspaces {
+		for _, r := range counters {
+			ch <- prometheus.MustNewConstMetric(r.desc, prometheus.CounterValue, s.totals.values[r.desc.String()], s.labels...)
+		}
+		for _, r := range gauges {
+			ch <- prometheus.MustNewConstMetric(r.desc, prometheus.GaugeValue, s.gauges[r.desc.String()], s.labels...)
+		}
+		for _, r := range histograms {
+			h := s.totals.histograms[r.desc.String()]
+			if h == nil {
+				continue
+			}
+			buckets := make(map[float64]uint64, len(h.buckets))
+			for bound, count := range h.buckets {
+				buckets[bound] = uint64(count)
+			}
+			ch <- prometheus.MustNewConstHistogram(r.desc, uint64(h.count), h.sum, buckets, s.labels...)
+		}
+	}
+}
+
+// servingPod is a pod whose inference server is scraped.
+type servingPod struct {
+	workspace client.ObjectKey
+	pod       *corev1.Pod
+}
+
+func (c *Collector) collect(ctx context.Context) {
+	workspaces := &kaitov1beta1.WorkspaceList{}
+	if err := c.Client.List(ctx, workspaces); err != nil {
+		klog.ErrorS(err, "InferenceMetricsCollector: failed to list workspaces")
+		return
+	}
+
+	labels := map[client.ObjectKey][]string{}
+	var pods []servingPod
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		if ws.Inference == nil || kaitov1beta1.GetWorkspaceRuntimeName(ws) != pkgmodel.RuntimeNameVLLM {
+			continue
+		}
+		key := client.ObjectKeyFromObject(ws)
+		labels[key] = metriclabels.PerWorkspaceValues(ws)
+
+		podList := &corev1.PodList{}
+		if err := c.Client.List(ctx, podList, client.InNamespace(ws.Namespace),
+			client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: ws.Name}); err != nil {
+			klog.ErrorS(err, "InferenceMetricsCollector: failed to list pods", "workspace", klog.KObj(ws))
+			continue
+		}
+		for j := range podList.Items {
+			if pod := &podList.Items[j]; isServing(pod) {
+				pods = append(pods, servingPod{workspace: key, pod: pod})
+			}
+		}
+	}
+
+	scraped := c.scrapePods(ctx, pods)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.workspaces == nil {
+		c.workspaces = map[client.ObjectKey]*series{}
+	}
+	// Forget the Workspaces that no longer exist or no longer serve with vLLM.
+	for key := range c.workspaces {
+		if _, ok := labels[key]; !ok {
+			delete(c.workspaces, key)
+		}
+	}
+	for key, values := range labels {
+		s := c.workspaces[key]
+		if s == nil {
+			s = &series{totals: newSample()}
+			c.workspaces[key] = s
+		}
+		s.labels = values
+		s.gauges = map[string]float64{}
+	}
+
+	last := map[types.UID]*sample{}
+	for _, p := range pods {
+		uid := p.pod.UID
+		cur, ok := scraped[uid]
+		if !ok {
+			// Keep the previous scrape, so the next successful one is compared to it.
+			if prev, ok := c.last[uid]; ok {
+				last[uid] = prev
+			}
+			continue
+		}
+		last[uid] = cur
+		s := c.workspaces[p.workspace]
+		for name, v := range cur.values {
+			if isGauge(name) {
+				s.gauges[name] += v
+			}
+		}
+		prev, ok := c.last[uid]
+		if !ok {
+			if startedBefore(p.pod, c.started) {
+				// Count pods that were already running from now on, not from their start.
+				continue
+			}
+			prev = newSample()
+		}
+		addDelta(s.totals, prev, cur)
+	}
+	c.last = last
+}
+
+// isServing reports whether pod serves inference requests. Only the leader, with pod index
+// 0, serves in multi-node inference.
+func isServing(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if index, ok := pod.Labels[appsv1.PodIndexLabel]; ok && index != "0" {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func startedBefore(pod *corev1.Pod, t time.Time) bool {
+	return pod.Status.StartTime == nil || pod.Status.StartTime.Time.Before(t)
+}
+
+func isGauge(name string) bool {
+	for _, r := range gauges {
+		if r.desc.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addDelta adds the increase of the counters and histograms of a pod from prev to cur to
+// totals. A value lower than before means the pod restarted, so all of it is new.
+func addDelta(totals, prev, cur *sample) {
+	delta := func(cur, prev float64) float64 {
+		if cur < prev {
+			return cur
+		}
+		return cur - prev
+	}
+	for name, v := range cur.values {
+		if !isGauge(name) {
+			totals.values[name] += delta(v, prev.values[name])
+		}
+	}
+	for name, h := range cur.histograms {
+		p := prev.histograms[name]
+		if p == nil || h.count < p.count {
+			p = &histogram{buckets: map[float64]float64{}}
+		}
+		total := totals.histograms[name]
+		if total == nil {
+			total = &histogram{buckets: map[float64]float64{}}
+			totals.histograms[name] = total
+		}
+		total.count += h.count - p.count
+		total.sum += h.sum - p.sum
+		for bound, count := range h.buckets {
+			total.buckets[bound] += count - p.buckets[bound]
+		}
+	}
+}
+
+// scrapePods scrapes the inference server of each of the pods and returns the samples by pod.
+func (c *Collector) scrapePods(ctx context.Context, pods []servingPod) map[types.UID]*sample {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	samples := map[types.UID]*sample{}
+	sem := make(chan struct{}, maxConcurrentScrapes)
+	for _, p := range pods {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			s, err := c.scrape(ctx, p.pod.Status.PodIP)
+			if err != nil {
+				klog.ErrorS(err, "InferenceMetricsCollector: failed to scrape inference server", "pod", klog.KObj(p.pod))
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			samples[p.pod.UID] = s
+		}()
+	}
+	wg.Wait()
+	return samples
+}
+
+// scrape reads the metrics of the inference server serving on podIP.
+func (c *Collector) scrape(ctx context.Context, podIP string) (*sample, error) {
+	ctx, cancel := context.WithTimeout(ctx, scrapeTimeout)
+	defer cancel()
+	port := c.Port
+	if port == 0 {
+		port = int(consts.PortInferenceServer)
+	}
+	url := "http://" + net.JoinHostPort(podIP, strconv.Itoa(port)) + "/metrics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	return parseSample(io.LimitReader(resp.Body, maxScrapeBytes))
+}
+
+// parseSample parses the exposition of a vLLM server. Every metric is summed over its
+// label sets, e.g. over the engines of a data-parallel server.
+func parseSample(in io.Reader) (*sample, error) {
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inference server metrics: %w", err)
+	}
+
+	s := newSample()
+	for _, r := range append(append([]republished{}, counters...), gauges...) {
+		if family := find(families, r.sources); family != nil {
+			for _, m := range family.GetMetric() {
+				s.values[r.desc.String()] += value(m)
+			}
+		}
+	}
+	for _, r := range histograms {
+		family := find(families, r.sources)
+		if family == nil {
+			continue
+		}
+		h := &histogram{buckets: map[float64]float64{}}
+		for _, m := range family.GetMetric() {
+			hist := m.GetHistogram()
+			if hist == nil {
+				continue
+			}
+			h.count += float64(hist.GetSampleCount())
+			h.sum += hist.GetSampleSum()
+			for _, b := range hist.GetBucket() {
+				if !math.IsInf(b.GetUpperBound(), 1) {
+					h.buckets[b.GetUpperBound()] += float64(b.GetCumulativeCount())
+				}
+			}
+		}
+		s.histograms[r.desc.String()] = h
+	}
+	return s, nil
+}
+
+// find returns the first of the families named in names.
+func find(families map[string]*dto.MetricFamily, names []string) *dto.MetricFamily {
+	for _, name := range names {
+		if family, ok := families[name]; ok {
+			return family
+		}
+	}
+	return nil
+}
+
+// value returns the value of a sample of a counter, a gauge or an untyped metric.
+func value(m *dto.Metric) float64 {
+	if m.GetGauge() != nil {
+		return m.GetGauge().GetValue()
+	}
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
@@ -0,0 +1,218 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferencemetrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// vllmExposition returns the metrics of a vLLM server with two engines, scaled by n.
+func vllmExposition(n int) string {
+	v := func(x int) string { return strconv.Itoa(x * n) }
+	return `# TYPE vllm:prompt_tokens_total counter
+vllm:prompt_tokens_total{engine="0",model_name="phi-4"} ` + v(100) + `
+vllm:prompt_tokens_total{engine="1",model_name="phi-4"} ` + v(50) + `
+# TYPE vllm:generation_tokens_total counter
+vllm:generation_tokens_total{engine="0",model_name="phi-4"} ` + v(400) + `
+# TYPE vllm:request_success_total counter
+vllm:request_success_total{engine="0",finished_reason="stop",model_name="phi-4"} ` + v(8) + `
+vllm:request_success_total{engine="0",finished_reason="length",model_name="phi-4"} ` + v(2) + `
+# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{engine="0",model_name="phi-4"} 3
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{engine="0",model_name="phi-4"} 1
+# TYPE vllm:time_to_first_token_seconds histogram
+vllm:time_to_first_token_seconds_bucket{engine="0",model_name="phi-4",le="0.1"} ` + v(4) + `
+vllm:time_to_first_token_seconds_bucket{engine="0",model_name="phi-4",le="1.0"} ` + v(10) + `
+vllm:time_to_first_token_seconds_bucket{engine="0",model_name="phi-4",le="+Inf"} ` + v(10) + `
+vllm:time_to_first_token_seconds_count{engine="0",model_name="phi-4"} ` + v(10) + `
+vllm:time_to_first_token_seconds_sum{engine="0",model_name="phi-4"} ` + v(2) + `
+# TYPE vllm:time_per_output_token_seconds histogram
+vllm:time_per_output_token_seconds_bucket{engine="0",model_name="phi-4",le="0.05"} ` + v(300) + `
+vllm:time_per_output_token_seconds_bucket{engine="0",model_name="phi-4",le="+Inf"} ` + v(390) + `
+vllm:time_per_output_token_seconds_count{engine="0",model_name="phi-4"} ` + v(390) + `
+vllm:time_per_output_token_seconds_sum{engine="0",model_name="phi-4"} ` + v(20) + `
+`
+}
+
+func TestParseSample(t *testing.T) {
+	s, err := parseSample(strings.NewReader(vllmExposition(1)))
+	require.NoError(t, err)
+	assert.Equal(t, 150.0, s.values[counters[0].desc.String()], "engines are summed")
+	assert.Equal(t, 10.0, s.values[counters[2].desc.String()], "finish reasons are summed")
+	assert.Equal(t, 3.0, s.values[gauges[0].desc.String()])
+
+	ttft := s.histograms[histograms[0].desc.String()]
+	require.NotNil(t, ttft)
+	assert.Equal(t, &histogram{count: 10, sum: 2, buckets: map[float64]float64{0.1: 4, 1: 10}}, ttft)
+	// Older vLLM releases name the inter-token latency time_per_output_token_seconds.
+	assert.Equal(t, 390.0, s.histograms[histograms[1].desc.String()].count)
+	assert.Nil(t, s.histograms[histograms[2].desc.String()], "metrics the server does not expose are left out")
+
+	_, err = parseSample(strings.NewReader("not metrics {"))
+	assert.Error(t, err)
+}
+
+func TestAddDelta(t *testing.T) {
+	name := counters[0].desc.String()
+	totals := newSample()
+	prev := &sample{values: map[string]float64{name: 100}, histograms: map[string]*histogram{}}
+
+	addDelta(totals, prev, &sample{values: map[string]float64{name: 150}, histograms: map[string]*histogram{}})
+	assert.Equal(t, 50.0, totals.values[name])
+
+	// The pod restarted and counts from zero again.
+	addDelta(totals, prev, &sample{values: map[string]float64{name: 30}, histograms: map[string]*histogram{}})
+	assert.Equal(t, 80.0, totals.values[name])
+}
+
+func TestCollect(t *testing.T) {
+	var scale atomic.Int32
+	scale.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(vllmExposition(int(scale.Load()))))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+		},
+	}
+	started := time.Now()
+	pod := func(name, index string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name),
+				Labels: map[string]string{kaitov1beta1.LabelWorkspaceName: "ws", appsv1.PodIndexLabel: index}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      host,
+				StartTime:  &metav1.Time{Time: started.Add(time.Minute)},
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	// Only the leader of a multi-node workspace serves, so the worker is not scraped.
+	kClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, pod("ws-0", "0"), pod("ws-1", "1")).Build()
+
+	c := &Collector{Client: kClient, Port: serverPort, started: started}
+	c.collect(context.Background())
+	got := gather(t, c)
+	assert.Equal(t, 150.0, got["kaito_workspace_inference_prompt_tokens_total"].GetCounter().GetValue())
+	assert.Equal(t, 3.0, got["kaito_workspace_inference_requests_running"].GetGauge().GetValue())
+	assert.Equal(t, uint64(10), got["kaito_workspace_inference_time_to_first_token_seconds"].GetHistogram().GetSampleCount())
+	assert.Equal(t, []string{"Standard_NC24ads_A100_v4", "default", "phi-4", "vllm", "ws"}, labelValues(got["kaito_workspace_inference_prompt_tokens_total"]))
+
+	scale.Store(3)
+	c.collect(context.Background())
+	got = gather(t, c)
+	assert.Equal(t, 450.0, got["kaito_workspace_inference_prompt_tokens_total"].GetCounter().GetValue())
+	assert.Equal(t, 1200.0, got["kaito_workspace_inference_generation_tokens_total"].GetCounter().GetValue())
+	assert.Equal(t, 3.0, got["kaito_workspace_inference_requests_running"].GetGauge().GetValue())
+
+	require.NoError(t, kClient.Delete(context.Background(), ws))
+	c.collect(context.Background())
+	assert.Empty(t, gather(t, c), "deleted workspaces are no longer reported")
+}
+
+func TestCollectCountsRunningPodsFromFirstScrape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(vllmExposition(1)))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-0", Namespace: "default", UID: types.UID("uid-ws-0"),
+			Labels: map[string]string{kaitov1beta1.LabelWorkspaceName: "ws"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      host,
+			StartTime:  &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	kClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, pod).Build()
+
+	c := &Collector{Client: kClient, Port: serverPort, started: time.Now()}
+	c.collect(context.Background())
+	got := gather(t, c)
+	// The tokens the pod served before the collector started are not attributed to this interval.
+	assert.Equal(t, 0.0, got["kaito_workspace_inference_prompt_tokens_total"].GetCounter().GetValue())
+	assert.Equal(t, 1.0, got["kaito_workspace_inference_requests_waiting"].GetGauge().GetValue())
+}
+
+// gather returns the metrics of c by name. Every test has a single workspace.
+func gather(t *testing.T, c prometheus.Collector) map[string]*dto.Metric {
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(c))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	metrics := map[string]*dto.Metric{}
+	for _, family := range families {
+		require.Len(t, family.GetMetric(), 1)
+		metrics[family.GetName()] = family.GetMetric()[0]
+	}
+	return metrics
+}
+
+func labelValues(m *dto.Metric) []string {
+	var values []string
+	for _, label := range m.GetLabel() {
+		values = append(values, label.GetValue())
+	}
+	return values
+}
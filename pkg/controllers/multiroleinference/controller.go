@@ -78,6 +78,7 @@ func NewMultiRoleInferenceReconciler(client client.Client, scheme *runtime.Schem
 // +kubebuilder:rbac:groups=kaito.sh,resources=multiroleinferences/finalizers,verbs=update
 // +kubebuilder:rbac:groups=kaito.sh,resources=inferencesets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=ocirepositories,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleases,verbs=get;list;watch;create;update;patch;delete
 
@@ -192,6 +193,26 @@ func (r *MultiRoleInferenceReconciler) garbageCollectMultiRoleInference(ctx cont
 func (r *MultiRoleInferenceReconciler) addOrUpdateMultiRoleInference(ctx context.Context, log logr.Logger, mri *kaitov1alpha1.MultiRoleInference) (ctrl.Result, error) {
 	log.Info("Reconciling MultiRoleInference", "name", mri.Name)
 
+	// Heterogeneous tiers replace the prefill/decode roles.
+	if len(mri.Spec.Tiers) > 0 {
+		if err := r.reconcileTiers(ctx, mri); err != nil {
+			log.Error(err, "Failed to reconcile tiers")
+			r.Recorder.Eventf(mri, "Warning", "ReconcileFailed", "Failed to reconcile tiers: %v", err)
+
+			meta.SetStatusCondition(&mri.Status.Conditions, metav1.Condition{
+				Type:               string(kaitov1alpha1.MultiRoleInferenceConditionTypeReady),
+				Status:             metav1.ConditionFalse,
+				Reason:             "ReconcileFailed",
+				Message:            fmt.Sprintf("Failed to reconcile tiers: %v", err),
+				ObservedGeneration: mri.Generation,
+			})
+			if statusErr := r.Status().Update(ctx, mri); statusErr != nil {
+				log.Error(statusErr, "Failed to update status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Create or update child InferenceSets for each role.
 	for _, role := range mri.Spec.Roles {
 		if err := r.reconcileInferenceSet(ctx, mri, role); err != nil {
@@ -213,7 +234,7 @@ func (r *MultiRoleInferenceReconciler) addOrUpdateMultiRoleInference(ctx context
 		}
 	}
 
-	// Clean up stale InferenceSets (roles removed from spec).
+	// Clean up stale InferenceSets (roles or tiers removed from spec).
 	if err := r.cleanupStaleInferenceSets(ctx, mri); err != nil {
 		log.Error(err, "Failed to cleanup stale InferenceSets")
 		return ctrl.Result{}, err
@@ -261,6 +282,10 @@ func (r *MultiRoleInferenceReconciler) aggregateStatus(ctx context.Context, log
 		return err
 	}
 
+	if len(mri.Spec.Tiers) > 0 {
+		return r.aggregateTierStatus(ctx, mri, isList.Items)
+	}
+
 	// Build a map from role → InferenceSet.
 	roleISMap := make(map[string]*kaitov1beta1.InferenceSet)
 	for i := range isList.Items {
@@ -372,13 +397,16 @@ func (r *MultiRoleInferenceReconciler) isInferenceSetReady(is *kaitov1beta1.Infe
 	return false
 }
 
-// cleanupStaleInferenceSets deletes InferenceSets whose role has been removed from the MRI spec.
+// cleanupStaleInferenceSets deletes InferenceSets whose role or tier has been removed from the MRI spec.
 func (r *MultiRoleInferenceReconciler) cleanupStaleInferenceSets(ctx context.Context, mri *kaitov1alpha1.MultiRoleInference) error {
 	// Build set of expected InferenceSet names.
-	expectedNames := make(map[string]bool, len(mri.Spec.Roles))
+	expectedNames := make(map[string]bool, len(mri.Spec.Roles)+len(mri.Spec.Tiers))
 	for _, role := range mri.Spec.Roles {
 		expectedNames[fmt.Sprintf("%s-%s", mri.Name, role.Type)] = true
 	}
+	for _, tier := range mri.Spec.Tiers {
+		expectedNames[tierName(mri, tier.Name)] = true
+	}
 
 	// List all child InferenceSets.
 	isList := &kaitov1beta1.InferenceSetList{}
@@ -400,14 +428,42 @@ func (r *MultiRoleInferenceReconciler) cleanupStaleInferenceSets(ctx context.Con
 	return nil
 }
 
+// childInferenceSet describes the child InferenceSet of a role or a tier.
+type childInferenceSet struct {
+	// suffix is appended to the MRI name to name the InferenceSet.
+	suffix string
+	// labelKey and labelValue identify the role or tier on the InferenceSet, its selector and its workspaces.
+	labelKey, labelValue string
+	replicas             *int32
+	instanceType         string
+	runtimeConfig        string
+	// annotations are added to the workspace template on top of the MRI annotations.
+	annotations map[string]string
+}
+
 // reconcileInferenceSet creates or updates a child InferenceSet for the given role.
 func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 	ctx context.Context,
 	mri *kaitov1alpha1.MultiRoleInference,
 	role kaitov1alpha1.MultiRoleInferenceRoleSpec,
 ) error {
-	isName := fmt.Sprintf("%s-%s", mri.Name, role.Type)
-	roleStr := string(role.Type)
+	return r.reconcileChildInferenceSet(ctx, mri, childInferenceSet{
+		suffix:        string(role.Type),
+		labelKey:      kaitov1alpha1.LabelInferenceRole,
+		labelValue:    string(role.Type),
+		replicas:      role.Replicas,
+		instanceType:  role.InstanceType,
+		runtimeConfig: role.RuntimeConfig,
+	})
+}
+
+// reconcileChildInferenceSet creates or updates a child InferenceSet of the MRI.
+func (r *MultiRoleInferenceReconciler) reconcileChildInferenceSet(
+	ctx context.Context,
+	mri *kaitov1alpha1.MultiRoleInference,
+	child childInferenceSet,
+) error {
+	isName := fmt.Sprintf("%s-%s", mri.Name, child.suffix)
 
 	// Build the desired InferenceSet.
 	desired := &kaitov1beta1.InferenceSet{
@@ -428,17 +484,17 @@ func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 			desired.Labels = make(map[string]string)
 		}
 		desired.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mri.Name
-		desired.Labels[kaitov1alpha1.LabelInferenceRole] = roleStr
+		desired.Labels[child.labelKey] = child.labelValue
 
 		// Spec — only reconcile replicas when explicitly set (non-nil).
 		// When nil, autoscaling is assumed and the controller skips replica reconciliation.
-		if role.Replicas != nil {
-			desired.Spec.Replicas = role.Replicas
+		if child.replicas != nil {
+			desired.Spec.Replicas = child.replicas
 		}
 
-		// LabelSelector — start from the MRI's labelSelector and inject role info.
+		// LabelSelector — start from the MRI's labelSelector and inject role or tier info.
 		// The InferenceSet controller propagates Spec.Selector to workspace.Resource.LabelSelector,
-		// so role-specific labels must be in the selector to ensure correct node selection.
+		// so role- or tier-specific labels must be in the selector to ensure correct node selection.
 		desired.Spec.Selector = mri.Spec.LabelSelector.DeepCopy()
 		if desired.Spec.Selector == nil {
 			desired.Spec.Selector = &metav1.LabelSelector{}
@@ -447,9 +503,9 @@ func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 			desired.Spec.Selector.MatchLabels = make(map[string]string)
 		}
 		desired.Spec.Selector.MatchLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mri.Name
-		desired.Spec.Selector.MatchLabels[kaitov1alpha1.LabelInferenceRole] = roleStr
+		desired.Spec.Selector.MatchLabels[child.labelKey] = child.labelValue
 
-		// Template metadata labels: propagate selector matchLabels (includes role labels).
+		// Template metadata labels: propagate selector matchLabels (includes role or tier labels).
 		templateLabels := make(map[string]string)
 		if mri.Spec.LabelSelector != nil && mri.Spec.LabelSelector.MatchLabels != nil {
 			for k, v := range mri.Spec.LabelSelector.MatchLabels {
@@ -457,23 +513,31 @@ func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 			}
 		}
 		templateLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mri.Name
-		templateLabels[kaitov1alpha1.LabelInferenceRole] = roleStr
+		templateLabels[child.labelKey] = child.labelValue
 		desired.Spec.Template.Labels = templateLabels
 
 		// Template metadata annotations: propagate the MRI's own annotations so opt-outs
 		// (e.g. kaito.sh/model-streaming, kaito.sh/disable-benchmark) reach child workspaces.
 		// The InferenceSet controller clones Spec.Template.Annotations onto each workspace.
-		if len(mri.Annotations) > 0 {
-			templateAnnotations := make(map[string]string, len(mri.Annotations))
+		// Tier routing annotations are added on top and always win.
+		if len(mri.Annotations) > 0 || len(child.annotations) > 0 {
+			templateAnnotations := make(map[string]string, len(mri.Annotations)+len(child.annotations))
 			for k, v := range mri.Annotations {
 				templateAnnotations[k] = v
 			}
+			for k, v := range child.annotations {
+				templateAnnotations[k] = v
+			}
 			desired.Spec.Template.Annotations = templateAnnotations
+		} else if desired.Spec.Template.Annotations != nil {
+			// The last tier forwards nothing; drop routing annotations left by a reordering.
+			delete(desired.Spec.Template.Annotations, kaitov1beta1.AnnotationTierMaxPromptTokens)
+			delete(desired.Spec.Template.Annotations, kaitov1beta1.AnnotationTierOverflowService)
 		}
 
 		// Resource.
 		desired.Spec.Template.Resource = kaitov1beta1.InferenceSetResourceSpec{
-			InstanceType: role.InstanceType,
+			InstanceType: child.instanceType,
 		}
 
 		// Inference — preset with shared model config.
@@ -488,9 +552,9 @@ func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 			},
 		}
 
-		// Role-specific runtime config.
-		if role.RuntimeConfig != "" {
-			desired.Spec.Template.Inference.Config = role.RuntimeConfig
+		// Role- or tier-specific runtime config.
+		if child.runtimeConfig != "" {
+			desired.Spec.Template.Inference.Config = child.runtimeConfig
 		}
 
 		return nil
@@ -502,7 +566,7 @@ func (r *MultiRoleInferenceReconciler) reconcileInferenceSet(
 
 	klog.V(2).InfoS("Reconciled InferenceSet",
 		"name", isName,
-		"role", roleStr,
+		child.labelKey, child.labelValue,
 		"result", result,
 	)
 
@@ -562,6 +626,23 @@ schedulingProfiles:
       - pluginRef: max-score-picker
 `
 
+// defaultTierPluginsConfig is the default EPP plugins YAML for tiered serving. The pool
+// only selects the smallest tier, so the EPP simply picks its least loaded replica.
+const defaultTierPluginsConfig = `apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+  - type: load-aware-scorer
+    parameters:
+      threshold: 10
+  - type: max-score-picker
+schedulingProfiles:
+  - name: default
+    plugins:
+      - pluginRef: load-aware-scorer
+        weight: 10
+      - pluginRef: max-score-picker
+`
+
 // defaultPDPluginsConfig returns the default P/D plugins config.
 func defaultPDPluginsConfig() string {
 	return defaultPDPluginsConfigTemplate
@@ -620,6 +701,11 @@ func (r *MultiRoleInferenceReconciler) reconcileInferencePool(
 		kaitov1alpha1.LabelMultiRoleInferenceParent: mri.Name,
 		appsv1.PodIndexLabel:                        "0", // Only leader pod (ordinal 0) serves inference traffic
 	}
+	// With tiers the EPP balances over the smallest tier only; its tier routers
+	// forward long prompts to the larger tiers.
+	if len(mri.Spec.Tiers) > 0 {
+		matchLabels[kaitov1alpha1.LabelInferenceTier] = sortedTiers(mri)[0].Name
+	}
 
 	// Build EPP extension values with llm-d image and P/D plugins config.
 	eppValues := map[string]any{
@@ -633,6 +719,9 @@ func (r *MultiRoleInferenceReconciler) reconcileInferencePool(
 
 	// Load plugins config: either from user-provided ConfigMap or auto-generated default.
 	pluginsYAML := defaultPDPluginsConfig()
+	if len(mri.Spec.Tiers) > 0 {
+		pluginsYAML = defaultTierPluginsConfig
+	}
 	if mri.Spec.EPPPluginsConfig != "" {
 		cm := &corev1.ConfigMap{}
 		if err := r.Get(ctx, client.ObjectKey{Name: mri.Spec.EPPPluginsConfig, Namespace: mri.Namespace}, cm); err != nil {
@@ -740,7 +829,8 @@ func (r *MultiRoleInferenceReconciler) isInferencePoolReady(ctx context.Context,
 func (r *MultiRoleInferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&kaitov1alpha1.MultiRoleInference{}).
-		Owns(&kaitov1beta1.InferenceSet{})
+		Owns(&kaitov1beta1.InferenceSet{}).
+		Owns(&corev1.Service{})
 
 	// Only watch Flux resources when Gateway API Inference Extension is enabled,
 	// because the Flux CRDs are only installed under that feature gate.
@@ -19,8 +19,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -64,3 +66,48 @@ func TestReconcileInferenceSetPropagatesAnnotations(t *testing.T) {
 	assert.Equal(t, "disabled", got.Spec.Template.Annotations["kaito.sh/model-streaming"])
 	assert.Equal(t, "true", got.Spec.Template.Annotations["kaito.sh/disable-benchmark"])
 }
+
+func TestReconcileTiers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1alpha1.AddToScheme(scheme))
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	mri := &kaitov1alpha1.MultiRoleInference{
+		ObjectMeta: metav1.ObjectMeta{Name: "mri-test", Namespace: "default", UID: "uid"},
+		Spec: kaitov1alpha1.MultiRoleInferenceSpec{
+			Model: kaitov1alpha1.MultiRoleInferenceModelSpec{Name: "llama-3.1-8b-instruct"},
+			Tiers: []kaitov1alpha1.MultiRoleInferenceTierSpec{
+				{Name: "long", InstanceType: "Standard_NC40ads_H100_v5", Replicas: ptr.To[int32](1)},
+				{Name: "short", InstanceType: "Standard_NV36ads_A10_v5", Replicas: ptr.To[int32](2), MaxPromptTokens: ptr.To[int32](4096)},
+			},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mri).Build()
+	r := &MultiRoleInferenceReconciler{Client: cl, Scheme: scheme}
+	ctx := context.Background()
+	require.NoError(t, r.reconcileTiers(ctx, mri))
+
+	short := &kaitov1beta1.InferenceSet{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "mri-test-short", Namespace: "default"}, short))
+	assert.Equal(t, "short", short.Labels[kaitov1alpha1.LabelInferenceTier])
+	assert.Equal(t, "short", short.Spec.Template.Labels[kaitov1alpha1.LabelInferenceTier])
+	assert.Equal(t, "Standard_NV36ads_A10_v5", short.Spec.Template.Resource.InstanceType)
+	assert.Equal(t, int32(2), *short.Spec.Replicas)
+	assert.Equal(t, "4096", short.Spec.Template.Annotations[kaitov1beta1.AnnotationTierMaxPromptTokens])
+	assert.Equal(t, "mri-test-long", short.Spec.Template.Annotations[kaitov1beta1.AnnotationTierOverflowService])
+
+	long := &kaitov1beta1.InferenceSet{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Name: "mri-test-long", Namespace: "default"}, long))
+	assert.Equal(t, "Standard_NC40ads_H100_v5", long.Spec.Template.Resource.InstanceType)
+	assert.NotContains(t, long.Spec.Template.Annotations, kaitov1beta1.AnnotationTierMaxPromptTokens)
+
+	for name, tier := range map[string]string{"mri-test": "short", "mri-test-short": "short", "mri-test-long": "long"} {
+		svc := &corev1.Service{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, svc), name)
+		assert.Equal(t, tier, svc.Spec.Selector[kaitov1alpha1.LabelInferenceTier], name)
+		assert.Equal(t, "mri-test", svc.Spec.Selector[kaitov1alpha1.LabelMultiRoleInferenceParent], name)
+		assert.Equal(t, int32(5000), svc.Spec.Ports[0].Port, name)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiroleinference
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// sortedTiers returns the tiers of the MRI ordered by prompt limit, shortest first.
+// The unbounded tier serves the longest prompts and is always last.
+func sortedTiers(mri *kaitov1alpha1.MultiRoleInference) []kaitov1alpha1.MultiRoleInferenceTierSpec {
+	tiers := append([]kaitov1alpha1.MultiRoleInferenceTierSpec(nil), mri.Spec.Tiers...)
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if tiers[j].MaxPromptTokens == nil {
+			return tiers[i].MaxPromptTokens != nil
+		}
+		return tiers[i].MaxPromptTokens != nil && *tiers[i].MaxPromptTokens < *tiers[j].MaxPromptTokens
+	})
+	return tiers
+}
+
+// tierName returns the name shared by the child InferenceSet and the Service of a tier.
+func tierName(mri *kaitov1alpha1.MultiRoleInference, tier string) string {
+	return fmt.Sprintf("%s-%s", mri.Name, tier)
+}
+
+// reconcileTiers creates or updates the child InferenceSet and Service of every tier.
+// Each tier forwards prompts above its limit to the Service of the next larger tier;
+// the MRI Service is the entry point and sends all traffic to the smallest tier.
+func (r *MultiRoleInferenceReconciler) reconcileTiers(ctx context.Context, mri *kaitov1alpha1.MultiRoleInference) error {
+	tiers := sortedTiers(mri)
+	for i, tier := range tiers {
+		var annotations map[string]string
+		if i < len(tiers)-1 {
+			annotations = map[string]string{
+				kaitov1beta1.AnnotationTierMaxPromptTokens: strconv.Itoa(int(*tier.MaxPromptTokens)),
+				kaitov1beta1.AnnotationTierOverflowService: tierName(mri, tiers[i+1].Name),
+			}
+		}
+		if err := r.reconcileChildInferenceSet(ctx, mri, childInferenceSet{
+			suffix:        tier.Name,
+			labelKey:      kaitov1alpha1.LabelInferenceTier,
+			labelValue:    tier.Name,
+			replicas:      tier.Replicas,
+			instanceType:  tier.InstanceType,
+			runtimeConfig: tier.RuntimeConfig,
+			annotations:   annotations,
+		}); err != nil {
+			return fmt.Errorf("tier %s: %w", tier.Name, err)
+		}
+		if err := r.reconcileTierService(ctx, mri, tierName(mri, tier.Name), tier.Name); err != nil {
+			return fmt.Errorf("tier %s: %w", tier.Name, err)
+		}
+	}
+	return r.reconcileTierService(ctx, mri, mri.Name, tiers[0].Name)
+}
+
+// reconcileTierService creates or updates a ClusterIP Service that selects the
+// leader pods of the given tier on the inference port.
+func (r *MultiRoleInferenceReconciler) reconcileTierService(ctx context.Context, mri *kaitov1alpha1.MultiRoleInference, name, tier string) error {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: mri.Namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		if err := controllerutil.SetControllerReference(mri, svc, r.Scheme); err != nil {
+			return err
+		}
+		if svc.Labels == nil {
+			svc.Labels = make(map[string]string)
+		}
+		svc.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mri.Name
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		svc.Spec.Selector = map[string]string{
+			kaitov1alpha1.LabelMultiRoleInferenceParent: mri.Name,
+			kaitov1alpha1.LabelInferenceTier:            tier,
+			appsv1.PodIndexLabel:                        "0", // Only leader pod (ordinal 0) serves inference traffic
+		}
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       consts.PortInferenceServer,
+			TargetPort: intstr.FromInt32(consts.PortInferenceServer),
+		}}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("CreateOrUpdate Service %s: %w", name, err)
+	}
+	klog.V(2).InfoS("Reconciled tier Service", "name", name, "tier", tier, "result", result)
+	return nil
+}
+
+// aggregateTierStatus reports the replicas of every tier and sets the Ready condition
+// once all tier InferenceSets and the InferencePool are ready.
+func (r *MultiRoleInferenceReconciler) aggregateTierStatus(ctx context.Context, mri *kaitov1alpha1.MultiRoleInference, children []kaitov1beta1.InferenceSet) error {
+	tierISMap := make(map[string]*kaitov1beta1.InferenceSet, len(children))
+	for i := range children {
+		if tier, ok := children[i].Labels[kaitov1alpha1.LabelInferenceTier]; ok {
+			tierISMap[tier] = &children[i]
+		}
+	}
+
+	allReady := true
+	var notReady []string
+	mri.Status.Tiers = nil
+	for _, tier := range sortedTiers(mri) {
+		tierStatus := kaitov1alpha1.MultiRoleInferenceTierStatus{Name: tier.Name, InstanceType: tier.InstanceType}
+		is := tierISMap[tier.Name]
+		if is != nil {
+			tierStatus.Replicas = is.Status.Replicas
+			tierStatus.ReadyReplicas = is.Status.ReadyReplicas
+		}
+		if !r.isInferenceSetReady(is) {
+			allReady = false
+			notReady = append(notReady, tier.Name)
+		}
+		mri.Status.Tiers = append(mri.Status.Tiers, tierStatus)
+	}
+
+	// Prefill/decode conditions do not apply to tiers.
+	meta.RemoveStatusCondition(&mri.Status.Conditions, string(kaitov1alpha1.MultiRoleInferenceConditionTypePrefillReady))
+	meta.RemoveStatusCondition(&mri.Status.Conditions, string(kaitov1alpha1.MultiRoleInferenceConditionTypeDecodeReady))
+
+	inferencePoolReady := r.isInferencePoolReady(ctx, mri)
+	condStatus := metav1.ConditionFalse
+	reason := "InferencePoolNotReady"
+	message := "InferencePool is not ready"
+	if inferencePoolReady {
+		condStatus = metav1.ConditionTrue
+		reason = "InferencePoolReady"
+		message = "InferencePool is ready"
+	}
+	meta.SetStatusCondition(&mri.Status.Conditions, metav1.Condition{
+		Type:               string(kaitov1alpha1.MultiRoleInferenceConditionTypeInferencePoolReady),
+		Status:             condStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: mri.Generation,
+	})
+
+	condStatus = metav1.ConditionFalse
+	reason = "NotReady"
+	message = fmt.Sprintf("Tiers not ready: %s", strings.Join(notReady, ", "))
+	if allReady {
+		message = "InferencePool is not ready"
+	}
+	if allReady && inferencePoolReady {
+		condStatus = metav1.ConditionTrue
+		reason = "Ready"
+		message = "All components are ready"
+	}
+	meta.SetStatusCondition(&mri.Status.Conditions, metav1.Condition{
+		Type:               string(kaitov1alpha1.MultiRoleInferenceConditionTypeReady),
+		Status:             condStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: mri.Generation,
+	})
+
+	mri.Status.ObservedGeneration = mri.Generation
+	return r.Status().Update(ctx, mri)
+}
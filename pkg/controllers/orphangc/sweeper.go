@@ -0,0 +1,181 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc
+
+import (
+	"context"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// DefaultInterval is the default interval between two sweeps.
+	DefaultInterval = 10 * time.Minute
+
+	// DefaultGracePeriod is the minimum age of an object before it can be swept. It
+	// covers the window in which an owner is being created and the cache is catching up.
+	DefaultGracePeriod = 10 * time.Minute
+)
+
+// OrphanSweeper periodically deletes auxiliary objects generated for Workspaces and
+// InferenceSets whose owner no longer exists. Owner references normally let the
+// Kubernetes garbage collector remove them, but objects created while their owner was
+// being deleted, or by versions that did not set owner references, can be left behind.
+type OrphanSweeper struct {
+	Client      client.Client
+	Interval    time.Duration
+	GracePeriod time.Duration
+	// SweepInferencePools enables sweeping the Flux OCIRepositories and HelmReleases
+	// generated for the Gateway API Inference Extension. Their CRDs are only
+	// installed when that feature is enabled.
+	SweepInferencePools bool
+}
+
+// Start implements manager.Runnable.
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.sweep(ctx, time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *OrphanSweeper) NeedLeaderElection() bool { return true }
+
+// ownerKind describes how generated objects are linked to the object that owns them.
+type ownerKind struct {
+	kind     string
+	label    string
+	newOwner func() client.Object
+}
+
+var (
+	workspaceOwner = ownerKind{
+		kind:     "Workspace",
+		label:    kaitov1beta1.LabelWorkspaceName,
+		newOwner: func() client.Object { return &kaitov1beta1.Workspace{} },
+	}
+	inferenceSetOwner = ownerKind{
+		kind:     "InferenceSet",
+		label:    consts.WorkspaceCreatedByInferenceSetLabel,
+		newOwner: func() client.Object { return &kaitov1beta1.InferenceSet{} },
+	}
+)
+
+func (s *OrphanSweeper) sweep(ctx context.Context, now time.Time) {
+	services := &corev1.ServiceList{}
+	s.sweepList(ctx, now, services, workspaceOwner, func() []client.Object {
+		objs := make([]client.Object, 0, len(services.Items))
+		for i := range services.Items {
+			objs = append(objs, &services.Items[i])
+		}
+		return objs
+	})
+
+	configMaps := &corev1.ConfigMapList{}
+	s.sweepList(ctx, now, configMaps, workspaceOwner, func() []client.Object {
+		objs := make([]client.Object, 0, len(configMaps.Items))
+		for i := range configMaps.Items {
+			objs = append(objs, &configMaps.Items[i])
+		}
+		return objs
+	})
+
+	if !s.SweepInferencePools {
+		return
+	}
+	helmReleases := &helmv2.HelmReleaseList{}
+	s.sweepList(ctx, now, helmReleases, inferenceSetOwner, func() []client.Object {
+		objs := make([]client.Object, 0, len(helmReleases.Items))
+		for i := range helmReleases.Items {
+			objs = append(objs, &helmReleases.Items[i])
+		}
+		return objs
+	})
+
+	ociRepositories := &sourcev1.OCIRepositoryList{}
+	s.sweepList(ctx, now, ociRepositories, inferenceSetOwner, func() []client.Object {
+		objs := make([]client.Object, 0, len(ociRepositories.Items))
+		for i := range ociRepositories.Items {
+			objs = append(objs, &ociRepositories.Items[i])
+		}
+		return objs
+	})
+}
+
+// sweepList lists the objects carrying the owner label and deletes the orphaned ones.
+func (s *OrphanSweeper) sweepList(ctx context.Context, now time.Time, list client.ObjectList, owner ownerKind, items func() []client.Object) {
+	if err := s.Client.List(ctx, list, client.HasLabels{owner.label}); err != nil {
+		klog.ErrorS(err, "OrphanSweeper: failed to list objects", "owner", owner.kind)
+		return
+	}
+	for _, obj := range items() {
+		orphaned, err := s.isOrphaned(ctx, now, obj, owner)
+		if err != nil {
+			klog.ErrorS(err, "OrphanSweeper: failed to check owner", "object", klog.KObj(obj), "owner", owner.kind)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+		klog.InfoS("OrphanSweeper: deleting orphaned object", "object", klog.KObj(obj), "owner", owner.kind, "ownerName", obj.GetLabels()[owner.label])
+		if err := s.Client.Delete(ctx, obj, client.Preconditions{UID: ptr.To(obj.GetUID())}); client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "OrphanSweeper: failed to delete orphaned object", "object", klog.KObj(obj))
+		}
+	}
+}
+
+// isOrphaned reports whether obj was generated for an owner that no longer exists.
+// Objects controlled by anything other than the owner named in the label, or owned
+// by other objects, are left alone.
+func (s *OrphanSweeper) isOrphaned(ctx context.Context, now time.Time, obj client.Object, owner ownerKind) (bool, error) {
+	if obj.GetDeletionTimestamp() != nil || now.Sub(obj.GetCreationTimestamp().Time) < s.GracePeriod {
+		return false, nil
+	}
+	ownerName := obj.GetLabels()[owner.label]
+	if ownerName == "" {
+		return false, nil
+	}
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		if ref.Kind != owner.kind || ref.Name != ownerName {
+			return false, nil
+		}
+	} else if len(obj.GetOwnerReferences()) > 0 {
+		// Owned by something else, e.g. a Pod or a Job.
+		return false, nil
+	}
+
+	err := s.Client.Get(ctx, client.ObjectKey{Name: ownerName, Namespace: obj.GetNamespace()}, owner.newOwner())
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
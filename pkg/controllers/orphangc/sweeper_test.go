@@ -0,0 +1,89 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestOrphanSweeperSweep(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, helmv2.AddToScheme(scheme))
+	require.NoError(t, sourcev1.AddToScheme(scheme))
+
+	now := time.Now()
+	old := metav1.NewTime(now.Add(-time.Hour))
+	meta := func(name string, labels map[string]string, owners ...metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, OwnerReferences: owners, CreationTimestamp: old}
+	}
+	controllerRef := func(kind, name string) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "kaito.sh/v1beta1", Kind: kind, Name: name, UID: "stale-uid", Controller: ptr.To(true)}
+	}
+	wsLabel := func(name string) map[string]string { return map[string]string{kaitov1beta1.LabelWorkspaceName: name} }
+	isLabel := map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: "gone"}
+
+	recent := &corev1.Service{ObjectMeta: meta("recent", wsLabel("gone"))}
+	recent.CreationTimestamp = metav1.NewTime(now)
+	objs := []client.Object{
+		&kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"}},
+		&corev1.Service{ObjectMeta: meta("live", wsLabel("live"), controllerRef("Workspace", "live"))},
+		&corev1.Service{ObjectMeta: meta("orphan-owned", wsLabel("gone"), controllerRef("Workspace", "gone"))},
+		&corev1.Service{ObjectMeta: meta("orphan-unowned", wsLabel("gone"))},
+		&corev1.Service{ObjectMeta: meta("other-controller", wsLabel("gone"), controllerRef("Deployment", "gone"))},
+		recent,
+		&corev1.ConfigMap{ObjectMeta: meta("orphan-cm", wsLabel("gone"), controllerRef("Workspace", "gone"))},
+		&helmv2.HelmRelease{ObjectMeta: meta("orphan-hr", isLabel, controllerRef("InferenceSet", "gone"))},
+		&sourcev1.OCIRepository{ObjectMeta: meta("orphan-oci", isLabel, controllerRef("InferenceSet", "gone"))},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	sweeper := &OrphanSweeper{Client: cl, GracePeriod: DefaultGracePeriod, SweepInferencePools: true}
+	sweeper.sweep(context.Background(), now)
+
+	exists := func(obj client.Object, name string) bool {
+		err := cl.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "default"}, obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+	assert.True(t, exists(&corev1.Service{}, "live"))
+	assert.False(t, exists(&corev1.Service{}, "orphan-owned"))
+	assert.False(t, exists(&corev1.Service{}, "orphan-unowned"))
+	assert.True(t, exists(&corev1.Service{}, "other-controller"))
+	assert.True(t, exists(&corev1.Service{}, "recent"), "objects within the grace period must be kept")
+	assert.False(t, exists(&corev1.ConfigMap{}, "orphan-cm"))
+	assert.False(t, exists(&helmv2.HelmRelease{}, "orphan-hr"))
+	assert.False(t, exists(&sourcev1.OCIRepository{}, "orphan-oci"))
+}
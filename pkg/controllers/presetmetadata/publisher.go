@@ -0,0 +1,128 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package presetmetadata publishes the metadata of the preset models to a ConfigMap, so
+// that ValidatingAdmissionPolicies can read it as their params.
+package presetmetadata
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the release namespace that the preset
+	// metadata is published to.
+	ConfigMapName = "kaito-preset-metadata"
+
+	// retryInterval is how long the Publisher waits before retrying a failed publish.
+	retryInterval = 10 * time.Second
+)
+
+// Key returns the ConfigMap key of a field of the preset name. ConfigMap keys cannot
+// contain a slash, so the name is lowercased and its slashes are replaced by underscores,
+// e.g. microsoft_phi-4-mini-instruct.deprecated.
+func Key(name, field string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "/", "_") + "." + field
+}
+
+// Data flattens presets into ConfigMap data. Every preset and every alias of a preset has
+// a key per field; fields without a value are left out.
+func Data(presets []models.Preset) map[string]string {
+	data := make(map[string]string)
+	for _, p := range presets {
+		runtimes := make([]string, 0, len(p.Runtimes))
+		for _, r := range p.Runtimes {
+			runtimes = append(runtimes, string(r))
+		}
+		fields := map[string]string{
+			"runtimes":             strings.Join(runtimes, ","),
+			"minGPUMemory":         p.MinGPUMemory,
+			"imageTag":             p.ImageTag,
+			"downloadAuthRequired": strconv.FormatBool(p.DownloadAuthRequired),
+			"tuning":               strconv.FormatBool(p.Tuning),
+			"deprecated":           strconv.FormatBool(p.Deprecated),
+			"endOfLifeDate":        p.EndOfLifeDate,
+			"replacement":          p.Replacement,
+		}
+		if p.ModelTokenLimit > 0 {
+			fields["modelTokenLimit"] = strconv.Itoa(p.ModelTokenLimit)
+		}
+		for _, name := range append([]string{p.Name}, p.Aliases...) {
+			for field, value := range fields {
+				if value != "" {
+					data[Key(name, field)] = value
+				}
+			}
+		}
+	}
+	return data
+}
+
+// Publisher creates or updates the preset metadata ConfigMap once at startup. The
+// metadata is embedded in the controller, so it only changes when the controller is
+// upgraded.
+type Publisher struct {
+	Client    client.Client
+	Namespace string
+}
+
+func (p *Publisher) Start(ctx context.Context) error {
+	presets, err := models.ListPresets()
+	if err != nil {
+		return fmt.Errorf("failed to list presets: %w", err)
+	}
+	data := Data(presets)
+	return wait.PollUntilContextCancel(ctx, retryInterval, true, func(ctx context.Context) (bool, error) {
+		if err := p.publish(ctx, data); err != nil {
+			klog.ErrorS(err, "failed to publish preset metadata", "configmap", klog.KRef(p.Namespace, ConfigMapName))
+			return false, nil
+		}
+		klog.InfoS("Published preset metadata", "configmap", klog.KRef(p.Namespace, ConfigMapName), "keys", len(data))
+		return true, nil
+	})
+}
+
+func (p *Publisher) NeedLeaderElection() bool { return true }
+
+func (p *Publisher) publish(ctx context.Context, data map[string]string) error {
+	cm := &corev1.ConfigMap{}
+	err := p.Client.Get(ctx, client.ObjectKey{Namespace: p.Namespace, Name: ConfigMapName}, cm)
+	if apierrors.IsNotFound(err) {
+		return p.Client.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: p.Namespace},
+			Data:       data,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if maps.Equal(cm.Data, data) {
+		return nil
+	}
+	cm.Data = data
+	return p.Client.Update(ctx, cm)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package presetmetadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "microsoft_phi-4-mini-instruct.deprecated", Key("microsoft/Phi-4-mini-instruct", "deprecated"))
+	assert.Equal(t, "phi-4-mini-instruct.runtimes", Key("phi-4-mini-instruct", "runtimes"))
+}
+
+func TestData(t *testing.T) {
+	data := Data([]models.Preset{
+		{
+			Name:            "microsoft/Phi-4-mini-instruct",
+			Aliases:         []string{"phi-4-mini-instruct"},
+			Runtimes:        []model.RuntimeName{model.RuntimeNameVLLM, model.RuntimeNameHuggingfaceTransformers},
+			MinGPUMemory:    "13Gi",
+			ModelTokenLimit: 131072,
+			ImageTag:        "0.4.4",
+			Tuning:          true,
+		},
+		{
+			Name:          "tiiuae/falcon-7b",
+			ImageTag:      "0.2.0",
+			Deprecated:    true,
+			EndOfLifeDate: "2026-12-31",
+			Replacement:   "microsoft/Phi-4-mini-instruct",
+		},
+	})
+
+	for _, name := range []string{"microsoft_phi-4-mini-instruct", "phi-4-mini-instruct"} {
+		assert.Equal(t, "vllm,transformers", data[name+".runtimes"])
+		assert.Equal(t, "13Gi", data[name+".minGPUMemory"])
+		assert.Equal(t, "131072", data[name+".modelTokenLimit"])
+		assert.Equal(t, "true", data[name+".tuning"])
+		assert.Equal(t, "false", data[name+".deprecated"])
+		assert.NotContains(t, data, name+".endOfLifeDate")
+	}
+	assert.Equal(t, "true", data["tiiuae_falcon-7b.deprecated"])
+	assert.Equal(t, "2026-12-31", data["tiiuae_falcon-7b.endOfLifeDate"])
+	assert.Equal(t, "microsoft/Phi-4-mini-instruct", data["tiiuae_falcon-7b.replacement"])
+	assert.NotContains(t, data, "tiiuae_falcon-7b.runtimes")
+	assert.NotContains(t, data, "tiiuae_falcon-7b.modelTokenLimit")
+}
+
+func TestPublish(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	key := client.ObjectKey{Namespace: "kaito-workspace", Name: ConfigMapName}
+
+	tests := []struct {
+		name     string
+		existing []client.Object
+	}{
+		{
+			name: "creates the ConfigMap",
+		},
+		{
+			name: "updates stale data",
+			existing: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Data:       map[string]string{"old.deprecated": "true"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.existing...).Build()
+			p := &Publisher{Client: c, Namespace: key.Namespace}
+			data := map[string]string{"phi-4-mini-instruct.deprecated": "false"}
+
+			require.NoError(t, p.publish(context.Background(), data))
+			cm := &corev1.ConfigMap{}
+			require.NoError(t, c.Get(context.Background(), key, cm))
+			assert.Equal(t, data, cm.Data)
+
+			// Publishing the same data again does not update the ConfigMap.
+			version := cm.ResourceVersion
+			require.NoError(t, p.publish(context.Background(), data))
+			require.NoError(t, c.Get(context.Background(), key, cm))
+			assert.Equal(t, version, cm.ResourceVersion)
+		})
+	}
+}
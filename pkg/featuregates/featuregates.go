@@ -14,31 +14,116 @@
 package featuregates
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	cliflag "k8s.io/component-base/cli/flag"
 
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
+// Stage is the maturity of a feature gate.
+type Stage string
+
+const (
+	Alpha Stage = "Alpha"
+	Beta  Stage = "Beta"
+	GA    Stage = "GA"
+)
+
+// Components that read feature gates, i.e. the KAITO managers.
+const (
+	ComponentWorkspace = "workspace"
+	ComponentRAGEngine = "ragengine"
+)
+
+// Sources of the current value of a feature gate, as reported by Status.
+const (
+	SourceDefault = "default"
+	SourceFlag    = "flag"
+)
+
+// FeatureSpec describes a feature gate.
+type FeatureSpec struct {
+	Default     bool
+	Stage       Stage
+	Description string
+	// Components lists the managers whose behavior the gate changes. Setting the gate
+	// on any other manager has no effect.
+	Components []string
+	// Requires lists the gates that must be enabled whenever this one is.
+	Requires []string
+}
+
 var (
-	// FeatureGates is a map that holds the feature gate names and their default values for KAITO.
-	FeatureGates = map[string]bool{
-		consts.FeatureFlagVLLM:                               true,
-		consts.FeatureFlagDisableNodeAutoProvisioning:        false,
-		consts.FeatureFlagGatewayAPIInferenceExtension:       false,
-		consts.FeatureFlagEnableInferenceSetController:       true,
-		consts.FeatureFlagEnableMIG:                          false,
-		consts.FeatureFlagEnableMultiRoleInferenceController: false,
-		consts.FeatureFlagModelMirror:                        false,
-		consts.FeatureFlagModelStreaming:                     false,
-		consts.FeatureFlagEnableBaseImageAutoUpgrade:         false,
-		//	Add more feature gates here
-	}
+	// Specs holds every known feature gate. Use Register to add one.
+	Specs = map[string]FeatureSpec{}
+
+	// FeatureGates is a map that holds the feature gate names and their current values for KAITO.
+	FeatureGates = map[string]bool{}
+
+	// sources records where the current value of each gate came from.
+	sources = map[string]string{}
 )
 
-// ParseAndValidateFeatureGates parses the feature gates flag and sets the environment variables for each feature.
+func init() {
+	both := []string{ComponentWorkspace, ComponentRAGEngine}
+	workspace := []string{ComponentWorkspace}
+	Register(consts.FeatureFlagVLLM, FeatureSpec{Default: true, Stage: GA, Components: workspace,
+		Description: "Serve preset models with the vLLM runtime instead of transformers."})
+	Register(consts.FeatureFlagDisableNodeAutoProvisioning, FeatureSpec{Default: false, Stage: Beta, Components: both,
+		Description: "Do not create nodes; run workloads on existing GPU nodes. Set from --node-provisioner on the workspace manager."})
+	Register(consts.FeatureFlagGatewayAPIInferenceExtension, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Create Gateway API Inference Extension resources for InferenceSets."})
+	Register(consts.FeatureFlagEnableInferenceSetController, FeatureSpec{Default: true, Stage: Beta, Components: workspace,
+		Description: "Run the InferenceSet controller."})
+	Register(consts.FeatureFlagEnableMIG, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Schedule workloads on NVIDIA MIG partitions."})
+	Register(consts.FeatureFlagEnableMultiRoleInferenceController, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Run the multi-role inference controller."})
+	Register(consts.FeatureFlagModelMirror, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Mirror model weights into cluster storage."})
+	Register(consts.FeatureFlagModelStreaming, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Requires:    []string{consts.FeatureFlagModelMirror},
+		Description: "Stream mirrored model weights into the inference server at startup."})
+	Register(consts.FeatureFlagEnableBaseImageAutoUpgrade, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Upgrade the base image of running workloads to the one shipped with the controller."})
+	Register(consts.FeatureFlagNamespaceDeletionProtection, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Reject the deletion of namespaces whose workspaces or RAGEngines still own NodeClaims."})
+	Register(consts.FeatureFlagWorkspacePriorityQueue, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Reconcile workspaces that have never been ready before the resyncs of steady-state ones."})
+	Register(consts.FeatureFlagImageVerification, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Verify the cosign signatures of preset images against --image-verification-policy and pin them to digests."})
+	Register(consts.FeatureFlagGPUUtilizationCollection, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Read GPU utilization of workspace nodes from the DCGM exporter into the workspace status and metrics."})
+	Register(consts.FeatureFlagBatchInference, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Run BatchInference jobs that stream a dataset through a workspace model."})
+	Register(consts.FeatureFlagInferenceMetricsCollection, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Republish the token throughput, latency and queue metrics of vLLM workspaces per workspace."})
+	Register(consts.FeatureFlagInferenceProfileCache, FeatureSpec{Default: false, Stage: Alpha, Components: workspace,
+		Description: "Cache the max-model-len profiled by vLLM per model, instance type and runtime version, and reuse it for new pods."})
+	//	Add more feature gates here
+}
+
+// Register adds a feature gate and sets it to its default. It panics if the gate is
+// already registered, since two owners of one gate would silently disagree.
+func Register(name string, spec FeatureSpec) {
+	if _, ok := Specs[name]; ok {
+		panic(fmt.Sprintf("feature gate %s registered twice", name))
+	}
+	Specs[name] = spec
+	FeatureGates[name] = spec.Default
+	sources[name] = SourceDefault
+}
+
+// ParseAndValidateFeatureGates applies a comma separated list of name=bool pairs. The
+// gates are left unchanged if any name is unknown or a gate is enabled without the
+// gates it requires.
 func ParseAndValidateFeatureGates(featureGates string) error {
 	gateMap := map[string]bool{}
 	if err := cliflag.NewMapStringBool(&gateMap).Set(featureGates); err != nil {
@@ -49,18 +134,117 @@ func ParseAndValidateFeatureGates(featureGates string) error {
 		return nil
 	}
 
-	var invalidFeatures string
+	var invalidFeatures []string
+	for key := range gateMap {
+		if _, ok := Specs[key]; !ok {
+			invalidFeatures = append(invalidFeatures, key)
+		}
+	}
+	if len(invalidFeatures) > 0 {
+		sort.Strings(invalidFeatures)
+		return errors.New("invalid feature gate(s) " + strings.Join(invalidFeatures, ", "))
+	}
+
+	gates := make(map[string]bool, len(FeatureGates))
+	for key, val := range FeatureGates {
+		gates[key] = val
+	}
+	for key, val := range gateMap {
+		gates[key] = val
+	}
+	if err := validateRequirements(gates); err != nil {
+		return err
+	}
+
 	for key, val := range gateMap {
-		if _, ok := FeatureGates[key]; !ok {
-			invalidFeatures = fmt.Sprintf("%s, %s", invalidFeatures, key)
+		FeatureGates[key] = val
+		sources[key] = SourceFlag
+	}
+	return nil
+}
+
+func validateRequirements(gates map[string]bool) error {
+	var errs []error
+	for _, name := range sortedNames() {
+		if !gates[name] {
 			continue
 		}
-		FeatureGates[key] = val
+		for _, required := range Specs[name].Requires {
+			if !gates[required] {
+				errs = append(errs, fmt.Errorf("feature gate %s requires %s to be enabled", name, required))
+			}
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	if invalidFeatures != "" {
-		return errors.New("invalid feature gate(s) " + invalidFeatures)
+// Override sets a gate from another setting of the manager, e.g. a dedicated flag.
+// source names that setting in the Status of the gate.
+func Override(name string, value bool, source string) {
+	if _, ok := Specs[name]; !ok {
+		panic(fmt.Sprintf("unknown feature gate %s", name))
 	}
+	FeatureGates[name] = value
+	sources[name] = source
+}
 
-	return nil
+// Ineffective returns the gates set on the command line that do not change the behavior
+// of component, so that the manager can warn about them.
+func Ineffective(component string) []string {
+	var names []string
+	for _, name := range sortedNames() {
+		if sources[name] == SourceFlag && !slices.Contains(Specs[name].Components, component) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GateStatus is the state of one feature gate as seen by a component.
+type GateStatus struct {
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Default     bool   `json:"default"`
+	Stage       Stage  `json:"stage"`
+	Source      string `json:"source"`
+	Description string `json:"description"`
+	// Effective is false when the gate does not change the behavior of the component.
+	Effective bool `json:"effective"`
+}
+
+// Status returns the state of every feature gate, sorted by name.
+func Status(component string) []GateStatus {
+	status := make([]GateStatus, 0, len(Specs))
+	for _, name := range sortedNames() {
+		spec := Specs[name]
+		status = append(status, GateStatus{
+			Name:        name,
+			Enabled:     FeatureGates[name],
+			Default:     spec.Default,
+			Stage:       spec.Stage,
+			Source:      sources[name],
+			Description: spec.Description,
+			Effective:   slices.Contains(spec.Components, component),
+		})
+	}
+	return status
+}
+
+// Handler serves the Status of component as JSON.
+func Handler(component string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Status(component)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func sortedNames() []string {
+	names := make([]string, 0, len(Specs))
+	for name := range Specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -14,11 +14,28 @@
 package featuregates
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"gotest.tools/assert"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
+// restoreFeatureGates resets the gates to their values before the test.
+func restoreFeatureGates(t *testing.T) {
+	gates, src := map[string]bool{}, map[string]string{}
+	for name, val := range FeatureGates {
+		gates[name] = val
+		src[name] = sources[name]
+	}
+	t.Cleanup(func() {
+		FeatureGates, sources = gates, src
+	})
+}
+
 func TestParseFeatureGates(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -72,6 +89,18 @@ func TestParseFeatureGates(t *testing.T) {
 			targetFeature: "enableInferenceSetController",
 			expectedValue: false,
 		},
+		{
+			name:          "WithModelStreamingWithoutModelMirror",
+			featureGates:  "ModelStreaming=true,ModelMirror=false",
+			expectedError: true,
+		},
+		{
+			name:          "WithModelStreamingAndModelMirror",
+			featureGates:  "ModelStreaming=true,ModelMirror=true",
+			expectedError: false,
+			targetFeature: "ModelStreaming",
+			expectedValue: true,
+		},
 		{
 			name:          "WithEmptyFeatureGates",
 			featureGates:  "",
@@ -81,6 +110,7 @@ func TestParseFeatureGates(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			restoreFeatureGates(t)
 			err := ParseAndValidateFeatureGates(tt.featureGates)
 			if tt.expectedError {
 				assert.Check(t, err != nil, "expected error but got nil")
@@ -93,3 +123,50 @@ func TestParseFeatureGates(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFeatureGatesIsAtomic(t *testing.T) {
+	restoreFeatureGates(t)
+	err := ParseAndValidateFeatureGates("vLLM=false,ModelStreaming=true")
+	assert.ErrorContains(t, err, "feature gate ModelStreaming requires ModelMirror to be enabled")
+	assert.Equal(t, FeatureGates[consts.FeatureFlagVLLM], true)
+	assert.Equal(t, FeatureGates[consts.FeatureFlagModelStreaming], false)
+
+	err = ParseAndValidateFeatureGates("b=true,a=true,vLLM=false")
+	assert.Error(t, err, "invalid feature gate(s) a, b")
+	assert.Equal(t, FeatureGates[consts.FeatureFlagVLLM], true)
+}
+
+func TestStatus(t *testing.T) {
+	restoreFeatureGates(t)
+	assert.NilError(t, ParseAndValidateFeatureGates("vLLM=false,enableMIG=true"))
+	Override(consts.FeatureFlagDisableNodeAutoProvisioning, true, "node-provisioner")
+
+	assert.DeepEqual(t, Ineffective(ComponentRAGEngine), []string{consts.FeatureFlagEnableMIG, consts.FeatureFlagVLLM})
+	assert.Equal(t, len(Ineffective(ComponentWorkspace)), 0)
+
+	recorder := httptest.NewRecorder()
+	Handler(ComponentRAGEngine).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/featuregates", nil))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	var status []GateStatus
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.Equal(t, len(status), len(Specs))
+
+	byName := map[string]GateStatus{}
+	for _, s := range status {
+		byName[s.Name] = s
+	}
+	assert.DeepEqual(t, byName[consts.FeatureFlagVLLM], GateStatus{
+		Name:        consts.FeatureFlagVLLM,
+		Enabled:     false,
+		Default:     true,
+		Stage:       GA,
+		Source:      SourceFlag,
+		Description: Specs[consts.FeatureFlagVLLM].Description,
+		Effective:   false,
+	})
+	nap := byName[consts.FeatureFlagDisableNodeAutoProvisioning]
+	assert.Equal(t, nap.Enabled, true)
+	assert.Equal(t, nap.Source, "node-provisioner")
+	assert.Equal(t, nap.Effective, true)
+	assert.Equal(t, byName[consts.FeatureFlagModelMirror].Source, SourceDefault)
+}
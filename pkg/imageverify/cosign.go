@@ -0,0 +1,273 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	protorekor "github.com/sigstore/protobuf-specs/gen/pb-go/rekor/v1"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/tlog"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	sigsig "github.com/sigstore/sigstore/pkg/signature"
+)
+
+// Media type and annotations of the signature layers written by cosign.
+const (
+	simpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	signatureAnnotation    = "dev.cosignproject.cosign/signature"
+	certificateAnnotation  = "dev.sigstore.cosign/certificate"
+	chainAnnotation        = "dev.sigstore.cosign/chain"
+	bundleAnnotation       = "dev.sigstore.cosign/bundle"
+
+	simpleSigningType = "cosign container image signature"
+)
+
+// signature is one cosign signature of an image.
+type signature struct {
+	payload     []byte
+	sig         []byte
+	certificate string
+	chain       string
+	bundle      string
+}
+
+// simpleSigning is the payload signed by cosign.
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the transparency log entry cosign attaches to a keyless signature.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte             `json:"SignedEntryTimestamp"`
+	Payload              rekorBundlePayload `json:"Payload"`
+}
+
+type rekorBundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// signatures returns the cosign signatures stored for digest in the repository of ref.
+func (r *Registry) signatures(ctx context.Context, ref Reference, digest string, creds Credentials) ([]signature, error) {
+	tag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	m, err := r.manifest(ctx, ref, tag, creds)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%s has no cosign signature", ref.Name()+"@"+digest)
+		}
+		return nil, fmt.Errorf("failed to read the signatures of %s: %w", ref.Name(), err)
+	}
+	var sigs []signature
+	for _, layer := range m.Layers {
+		if layer.MediaType != simpleSigningMediaType {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[signatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		payload, err := r.blob(ctx, ref, layer.Digest, creds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature payload %s: %w", layer.Digest, err)
+		}
+		sigs = append(sigs, signature{
+			payload:     payload,
+			sig:         sig,
+			certificate: layer.Annotations[certificateAnnotation],
+			chain:       layer.Annotations[chainAnnotation],
+			bundle:      layer.Annotations[bundleAnnotation],
+		})
+	}
+	return sigs, nil
+}
+
+// verifySignatures checks that one of sigs signs digest with a key or identity of rule.
+func verifySignatures(sigs []signature, digest string, rule *Rule, p *Policy) error {
+	if len(sigs) == 0 {
+		return errors.New("no cosign signature found")
+	}
+	var errs []error
+	for _, s := range sigs {
+		err := verifyPayload(s.payload, digest)
+		if err == nil {
+			err = verifySigner(s, rule, p)
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("no signature matches the policy: %w", errors.Join(errs...))
+}
+
+func verifyPayload(payload []byte, digest string) error {
+	var ss simpleSigning
+	if err := json.Unmarshal(payload, &ss); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if ss.Critical.Type != simpleSigningType {
+		return fmt.Errorf("unexpected signature type %q", ss.Critical.Type)
+	}
+	if ss.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for %s", ss.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+func verifySigner(s signature, rule *Rule, p *Policy) error {
+	for _, key := range rule.keys {
+		if verifyWithKey(key, s.payload, s.sig) == nil {
+			return nil
+		}
+	}
+	if len(rule.Identities) == 0 || s.certificate == "" {
+		return errors.New("signature does not verify with any configured key")
+	}
+	return verifyKeyless(s, rule.Identities, p)
+}
+
+// verifyKeyless checks a signature made with a short-lived Fulcio certificate with the
+// sigstore-go verifier. The certificate must chain to the configured roots at the time
+// the transparency log recorded the signature, and name one of the identities.
+func verifyKeyless(s signature, identities []Identity, p *Policy) error {
+	certs, err := parseCertificates([]byte(s.certificate))
+	if err != nil {
+		return fmt.Errorf("invalid signing certificate: %w", err)
+	}
+	if s.bundle == "" {
+		return errors.New("keyless signature has no transparency log bundle")
+	}
+	entry, err := parseBundle([]byte(s.bundle))
+	if err != nil {
+		return err
+	}
+	var chain []*x509.Certificate
+	if s.chain != "" {
+		if chain, err = parseCertificates([]byte(s.chain)); err != nil {
+			return fmt.Errorf("invalid certificate chain: %w", err)
+		}
+	}
+	trusted, err := p.trustedRoot(chain)
+	if err != nil {
+		return err
+	}
+	verifier, err := verify.NewVerifier(trusted, verify.WithTransparencyLog(1), verify.WithIntegratedTimestamps(1))
+	if err != nil {
+		return err
+	}
+
+	var opts []verify.PolicyOption
+	for _, id := range identities {
+		identity, err := verify.NewShortCertificateIdentity(id.Issuer, "", id.Subject, "")
+		if err != nil {
+			return err
+		}
+		opts = append(opts, verify.WithCertificateIdentity(identity))
+	}
+	sum := sha256.Sum256(s.payload)
+	entity := &cosignEntity{
+		certificate: bundle.NewCertificate(certs[0]),
+		signature:   bundle.NewMessageSignature(sum[:], "SHA2_256", s.sig),
+		entry:       entry,
+	}
+	if _, err := verifier.Verify(entity, verify.NewPolicy(verify.WithArtifactDigest("sha256", sum[:]), opts...)); err != nil {
+		return fmt.Errorf("keyless signature does not verify: %w", err)
+	}
+	return nil
+}
+
+// parseBundle converts the transparency log bundle of a cosign signature into a log entry.
+func parseBundle(data []byte) (*tlog.Entry, error) {
+	var b rekorBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid transparency log bundle: %w", err)
+	}
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	logID, err := hex.DecodeString(b.Payload.LogID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transparency log ID: %w", err)
+	}
+	entry, err := tlog.ParseTransparencyLogEntry(&protorekor.TransparencyLogEntry{
+		LogIndex:          b.Payload.LogIndex,
+		LogId:             &protocommon.LogId{KeyId: logID},
+		KindVersion:       &protorekor.KindVersion{Kind: "hashedrekord", Version: "0.0.1"},
+		IntegratedTime:    b.Payload.IntegratedTime,
+		InclusionPromise:  &protorekor.InclusionPromise{SignedEntryTimestamp: b.SignedEntryTimestamp},
+		CanonicalizedBody: body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	return entry, nil
+}
+
+// cosignEntity presents a cosign signature layer to the sigstore-go verifier, which
+// otherwise reads signatures from sigstore bundles.
+type cosignEntity struct {
+	certificate *bundle.Certificate
+	signature   *bundle.MessageSignature
+	entry       *tlog.Entry
+}
+
+var _ verify.SignedEntity = &cosignEntity{}
+
+func (e *cosignEntity) HasInclusionPromise() bool { return e.entry.HasInclusionPromise() }
+
+func (e *cosignEntity) HasInclusionProof() bool { return e.entry.HasInclusionProof() }
+
+func (e *cosignEntity) VerificationContent() (verify.VerificationContent, error) {
+	return e.certificate, nil
+}
+
+func (e *cosignEntity) SignatureContent() (verify.SignatureContent, error) {
+	return e.signature, nil
+}
+
+func (e *cosignEntity) Timestamps() ([][]byte, error) { return nil, nil }
+
+func (e *cosignEntity) TlogEntries() ([]*tlog.Entry, error) { return []*tlog.Entry{e.entry}, nil }
+
+// Version reports the bundle version cosign signatures correspond to.
+func (e *cosignEntity) Version() (string, error) { return "v0.1", nil }
+
+// verifyWithKey checks sig over payload with the SHA-256 digest cosign signs with.
+func verifyWithKey(key crypto.PublicKey, payload, sig []byte) error {
+	verifier, err := sigsig.LoadVerifier(key, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("unsupported public key: %w", err)
+	}
+	return verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(payload))
+}
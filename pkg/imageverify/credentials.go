@@ -0,0 +1,108 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Credentials are registry logins by registry host, read from image pull secrets.
+type Credentials map[string]Login
+
+// Login is a registry username and password.
+type Login struct {
+	Username string
+	Password string
+}
+
+// dockerConfigEntry is a registry login in a docker config file.
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// ParseDockerConfig reads the logins of a kubernetes.io/dockerconfigjson secret, or of the
+// legacy kubernetes.io/dockercfg format that has no "auths" wrapper.
+func ParseDockerConfig(data []byte) (Credentials, error) {
+	var config struct {
+		Auths map[string]dockerConfigEntry `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+	entries := config.Auths
+	if entries == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid docker config: %w", err)
+		}
+	}
+
+	creds := Credentials{}
+	for server, entry := range entries {
+		login := Login{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", server, err)
+			}
+			user, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth of registry %s: expected username:password", server)
+			}
+			login = Login{Username: user, Password: password}
+		}
+		if login.Username == "" && login.Password == "" {
+			continue
+		}
+		creds[registryHost(server)] = login
+	}
+	return creds, nil
+}
+
+// Merge adds the logins of other for registries that have none yet.
+func (c Credentials) Merge(other Credentials) {
+	for host, login := range other {
+		if _, ok := c[host]; !ok {
+			c[host] = login
+		}
+	}
+}
+
+// login returns the login for registry, or nil.
+func (c Credentials) login(registry string) *Login {
+	if login, ok := c[registry]; ok {
+		return &login
+	}
+	return nil
+}
+
+func (l *Login) basic() string {
+	return base64.StdEncoding.EncodeToString([]byte(l.Username + ":" + l.Password))
+}
+
+// registryHost returns the registry of a docker config server, which may be a URL such as
+// "https://index.docker.io/v1/".
+func registryHost(server string) string {
+	server = strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ := strings.Cut(server, "/")
+	switch host {
+	case "index.docker.io", dockerHubAPIRegistry:
+		return dockerHubRegistry
+	}
+	return host
+}
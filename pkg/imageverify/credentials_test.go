@@ -0,0 +1,102 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDockerConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   Credentials
+		errMsg string
+	}{
+		{
+			name:   "dockerconfigjson with auth",
+			config: `{"auths":{"myregistry.azurecr.io":{"auth":"dXNlcjpwYXNz"}}}`,
+			want:   Credentials{"myregistry.azurecr.io": {Username: "user", Password: "pass"}},
+		},
+		{
+			name:   "docker hub URL with username and password",
+			config: `{"auths":{"https://index.docker.io/v1/":{"username":"user","password":"pass"}}}`,
+			want:   Credentials{"docker.io": {Username: "user", Password: "pass"}},
+		},
+		{
+			name:   "legacy dockercfg",
+			config: `{"ghcr.io":{"auth":"dXNlcjpwYXNz"}}`,
+			want:   Credentials{"ghcr.io": {Username: "user", Password: "pass"}},
+		},
+		{
+			name:   "entry without login is skipped",
+			config: `{"auths":{"ghcr.io":{}}}`,
+			want:   Credentials{},
+		},
+		{name: "invalid auth", config: `{"auths":{"ghcr.io":{"auth":"dXNlcg=="}}}`, errMsg: "expected username:password"},
+		{name: "invalid json", config: `{`, errMsg: "invalid docker config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDockerConfig([]byte(tt.config))
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistryLogin(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2}`)
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"private"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer private" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(manifest)
+	}))
+	t.Cleanup(server.Close)
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	ref, err := ParseReference(host + "/kaito/private:v1")
+	require.NoError(t, err)
+	registry := &Registry{Client: server.Client()}
+
+	_, err = registry.Resolve(context.Background(), ref, nil)
+	assert.ErrorContains(t, err, "token endpoint returned 401")
+
+	digest, err := registry.Resolve(context.Background(), ref, Credentials{host: {Username: "user", Password: "pass"}})
+	require.NoError(t, err)
+	assert.Equal(t, sha256Digest(manifest), digest)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"sigs.k8s.io/yaml"
+)
+
+// Policy lists which signatures the preset images must carry. It is loaded from the
+// file passed to --image-verification-policy.
+type Policy struct {
+	// Rules are matched in order against the image name; the first match applies.
+	// Images that match no rule cannot be verified and are refused.
+	Rules []Rule `json:"rules"`
+
+	// FulcioRoots is the PEM bundle of the certificate authorities that issue keyless
+	// signing certificates. Required by rules with identities.
+	FulcioRoots string `json:"fulcioRoots,omitempty"`
+
+	// RekorPublicKey is the PEM ECDSA public key of the transparency log that timestamps
+	// keyless signatures. Required by rules with identities.
+	RekorPublicKey string `json:"rekorPublicKey,omitempty"`
+
+	roots         []*x509.Certificate
+	intermediates []*x509.Certificate
+	rekorLogs     map[string]*root.TransparencyLog
+}
+
+// Rule lists the keys and identities accepted for a set of images. A signature made
+// with any of the keys, or by any of the identities, verifies the image.
+type Rule struct {
+	// Images is a path.Match pattern of registry/repository, e.g. "mcr.microsoft.com/aks/kaito/*".
+	Images string `json:"images"`
+
+	// Keys are PEM public keys of key-pair signatures (cosign sign --key).
+	Keys []string `json:"keys,omitempty"`
+
+	// Identities are the signers of keyless signatures (cosign sign with OIDC).
+	Identities []Identity `json:"identities,omitempty"`
+
+	keys []crypto.PublicKey
+}
+
+// Identity is the signer recorded in a keyless signing certificate.
+type Identity struct {
+	// Issuer is the OIDC issuer, e.g. "https://token.actions.githubusercontent.com".
+	Issuer string `json:"issuer"`
+	// Subject is the email or URI of the signer, e.g. the workflow that published the image.
+	Subject string `json:"subject"`
+}
+
+// LoadPolicy reads and validates the policy file at path.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image verification policy: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses and validates a YAML or JSON policy.
+func ParsePolicy(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse image verification policy: %w", err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, errors.New("image verification policy has no rules")
+	}
+
+	keyless := false
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if _, err := path.Match(r.Images, ""); err != nil || r.Images == "" {
+			return nil, fmt.Errorf("rules[%d]: invalid images pattern %q", i, r.Images)
+		}
+		if len(r.Keys) == 0 && len(r.Identities) == 0 {
+			return nil, fmt.Errorf("rules[%d]: at least one key or identity is required", i)
+		}
+		for j, k := range r.Keys {
+			key, err := parsePublicKey([]byte(k))
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d].keys[%d]: %w", i, j, err)
+			}
+			r.keys = append(r.keys, key)
+		}
+		for j, id := range r.Identities {
+			if id.Issuer == "" || id.Subject == "" {
+				return nil, fmt.Errorf("rules[%d].identities[%d]: issuer and subject are required", i, j)
+			}
+			keyless = true
+		}
+	}
+
+	if keyless {
+		certs, err := parseCertificates([]byte(p.FulcioRoots))
+		if err != nil {
+			return nil, fmt.Errorf("fulcioRoots: %w", err)
+		}
+		for _, cert := range certs {
+			if cert.CheckSignatureFrom(cert) == nil {
+				p.roots = append(p.roots, cert)
+			} else {
+				p.intermediates = append(p.intermediates, cert)
+			}
+		}
+		if len(p.roots) == 0 {
+			return nil, errors.New("fulcioRoots must contain at least one self-signed root certificate when identities are used")
+		}
+		key, err := parsePublicKey([]byte(p.RekorPublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("rekorPublicKey: %w", err)
+		}
+		if _, ok := key.(*ecdsa.PublicKey); !ok {
+			return nil, errors.New("rekorPublicKey must be an ECDSA key")
+		}
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("rekorPublicKey: %w", err)
+		}
+		// The log is identified by the SHA-256 of its key, as in the entries it signs.
+		logID := sha256.Sum256(der)
+		p.rekorLogs = map[string]*root.TransparencyLog{
+			hex.EncodeToString(logID[:]): {
+				ID:                  logID[:],
+				PublicKey:           key,
+				HashFunc:            crypto.SHA256,
+				SignatureHashFunc:   crypto.SHA256,
+				ValidityPeriodStart: time.Unix(0, 0),
+			},
+		}
+	}
+	return p, nil
+}
+
+// trustedRoot returns the trust material of keyless signatures: a certificate authority
+// per configured root, with the configured intermediates and those sent with the signature.
+func (p *Policy) trustedRoot(chain []*x509.Certificate) (*root.TrustedRoot, error) {
+	intermediates := append(append([]*x509.Certificate{}, p.intermediates...), chain...)
+	var authorities []root.CertificateAuthority
+	for _, r := range p.roots {
+		authorities = append(authorities, &root.FulcioCertificateAuthority{Root: r, Intermediates: intermediates})
+	}
+	return root.NewTrustedRoot(root.TrustedRootMediaType01, authorities, nil, nil, p.rekorLogs)
+}
+
+// ruleFor returns the first rule matching ref, or nil.
+func (p *Policy) ruleFor(ref Reference) *Rule {
+	for i := range p.Rules {
+		if ok, _ := path.Match(p.Rules[i].Images, ref.Name()); ok {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// parseCertificates returns the certificates of a PEM bundle, which must hold at least one.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("expected at least one PEM CERTIFICATE block")
+	}
+	return certs, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("expected a PEM PUBLIC KEY block")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
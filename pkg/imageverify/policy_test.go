@@ -0,0 +1,65 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	_, keyPEM := newKey(t)
+	indent := func(s string) string {
+		out := ""
+		for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+			out += "          " + line + "\n"
+		}
+		return out
+	}
+
+	p, err := ParsePolicy([]byte("rules:\n  - images: mcr.microsoft.com/aks/kaito/*\n    keys:\n      - |\n" + indent(keyPEM)))
+	require.NoError(t, err)
+	require.Len(t, p.Rules, 1)
+	assert.Len(t, p.Rules[0].keys, 1)
+
+	ref, err := ParseReference("mcr.microsoft.com/aks/kaito/kaito-base:0.1.0")
+	require.NoError(t, err)
+	assert.NotNil(t, p.ruleFor(ref))
+	ref, err = ParseReference("mcr.microsoft.com/other/kaito-base:0.1.0")
+	require.NoError(t, err)
+	assert.Nil(t, p.ruleFor(ref))
+
+	tests := []struct {
+		name   string
+		policy string
+		errMsg string
+	}{
+		{name: "no rules", policy: "rules: []", errMsg: "has no rules"},
+		{name: "unknown field", policy: "rules: []\nkey: x", errMsg: "failed to parse"},
+		{name: "bad pattern", policy: "rules:\n  - images: '['\n    keys: [x]", errMsg: "invalid images pattern"},
+		{name: "no keys or identities", policy: "rules:\n  - images: '*'", errMsg: "at least one key or identity"},
+		{name: "bad key", policy: "rules:\n  - images: '*'\n    keys: [not-a-key]", errMsg: "expected a PEM PUBLIC KEY block"},
+		{name: "identity without subject", policy: "rules:\n  - images: '*'\n    identities: [{issuer: https://issuer}]", errMsg: "issuer and subject are required"},
+		{name: "identities without roots", policy: "rules:\n  - images: '*'\n    identities: [{issuer: https://issuer, subject: me}]", errMsg: "fulcioRoots: expected at least one PEM CERTIFICATE block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
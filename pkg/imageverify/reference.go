@@ -0,0 +1,99 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry    = "docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+)
+
+// Reference is a parsed container image reference.
+type Reference struct {
+	// Registry is the registry host, e.g. "mcr.microsoft.com".
+	Registry string
+	// Repository is the repository in the registry, e.g. "aks/kaito/kaito-base".
+	Repository string
+	// Tag is the tag of the image, empty if it is referenced by digest only.
+	Tag string
+	// Digest is the manifest digest, e.g. "sha256:...", empty if it is referenced by tag.
+	Digest string
+}
+
+// ParseReference parses an image reference of the form [registry/]repository[:tag][@digest].
+// Images without a registry are looked up on Docker Hub, like the container runtime does.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.Digest, "sha256:") || len(ref.Digest) != len("sha256:")+64 {
+			return Reference{}, fmt.Errorf("image %q has an unsupported digest", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i:], "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if ref.Tag == "" {
+			return Reference{}, fmt.Errorf("invalid image reference %q", image)
+		}
+	}
+	if name == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || !(strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		registry, repository = dockerHubRegistry, name
+	}
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	if repository == "" || strings.Contains(repository, "//") || strings.ToLower(repository) != repository {
+		return Reference{}, fmt.Errorf("invalid repository in image reference %q", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	ref.Registry, ref.Repository = registry, repository
+	return ref, nil
+}
+
+// Name returns registry/repository.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the reference, with the tag and the digest when they are set.
+func (r Reference) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// apiHost returns the host serving the registry API.
+func (r Reference) apiHost() string {
+	if r.Registry == dockerHubRegistry {
+		return dockerHubAPIRegistry
+	}
+	return r.Registry
+}
@@ -0,0 +1,73 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		image   string
+		want    Reference
+		wantErr bool
+	}{
+		{
+			image: "mcr.microsoft.com/aks/kaito/kaito-base:0.1.0",
+			want:  Reference{Registry: "mcr.microsoft.com", Repository: "aks/kaito/kaito-base", Tag: "0.1.0"},
+		},
+		{
+			image: "localhost:5000/kaito-base@" + digest,
+			want:  Reference{Registry: "localhost:5000", Repository: "kaito-base", Digest: digest},
+		},
+		{
+			image: "myregistry.azurecr.io/kaito-base:0.1.0@" + digest,
+			want:  Reference{Registry: "myregistry.azurecr.io", Repository: "kaito-base", Tag: "0.1.0", Digest: digest},
+		},
+		{
+			image: "busybox",
+			want:  Reference{Registry: "docker.io", Repository: "library/busybox", Tag: "latest"},
+		},
+		{
+			image: "kaito/base:1",
+			want:  Reference{Registry: "docker.io", Repository: "kaito/base", Tag: "1"},
+		},
+		{image: "mcr.microsoft.com/kaito@sha256:abc", wantErr: true},
+		{image: "mcr.microsoft.com/kaito:", wantErr: true},
+		{image: "mcr.microsoft.com/Kaito:1", wantErr: true},
+		{image: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := ParseReference(tt.image)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	ref, err := ParseReference("mcr.microsoft.com/aks/kaito/kaito-base:0.1.0")
+	require.NoError(t, err)
+	ref.Digest = digest
+	assert.Equal(t, "mcr.microsoft.com/aks/kaito/kaito-base:0.1.0@"+digest, ref.String())
+	assert.Equal(t, "registry-1.docker.io", Reference{Registry: "docker.io"}.apiHost())
+}
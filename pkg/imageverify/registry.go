@@ -0,0 +1,252 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maxManifestBytes and maxBlobBytes bound what is read from a registry. Signature
+	// manifests and simple signing payloads are a few KiB.
+	maxManifestBytes = 4 << 20
+	maxBlobBytes     = 1 << 20
+
+	manifestAccept = "application/vnd.oci.image.index.v1+json, " +
+		"application/vnd.docker.distribution.manifest.list.v2+json, " +
+		"application/vnd.oci.image.manifest.v1+json, " +
+		"application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// errNotFound is returned for manifests and blobs the registry does not have.
+var errNotFound = errors.New("not found")
+
+// Registry reads manifests and blobs through the OCI distribution API. Requests are
+// anonymous unless the caller passes a login for the registry.
+type Registry struct {
+	Client *http.Client
+}
+
+// NewRegistry returns a Registry with a bounded request timeout.
+func NewRegistry() *Registry {
+	return &Registry{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Resolve returns the digest of the manifest ref points to. The digest is computed from
+// the manifest content instead of being taken from a response header.
+func (r *Registry) Resolve(ctx context.Context, ref Reference, creds Credentials) (string, error) {
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+	data, err := r.get(ctx, ref, "/manifests/"+url.PathEscape(ref.Tag), manifestAccept, maxManifestBytes, creds)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// manifest returns the manifest with the given tag or digest in the repository of ref.
+func (r *Registry) manifest(ctx context.Context, ref Reference, reference string, creds Credentials) (*ociManifest, error) {
+	data, err := r.get(ctx, ref, "/manifests/"+url.PathEscape(reference), manifestAccept, maxManifestBytes, creds)
+	if err != nil {
+		return nil, err
+	}
+	m := &ociManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", reference, err)
+	}
+	return m, nil
+}
+
+// blob returns the blob with the given digest in the repository of ref and checks its content.
+func (r *Registry) blob(ctx context.Context, ref Reference, digest string, creds Credentials) ([]byte, error) {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return nil, fmt.Errorf("unsupported blob digest %q", digest)
+	}
+	data, err := r.get(ctx, ref, "/blobs/"+url.PathEscape(digest), "", maxBlobBytes, creds)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("blob %s does not match its digest", digest)
+	}
+	return data, nil
+}
+
+func (r *Registry) get(ctx context.Context, ref Reference, suffix, accept string, limit int64, creds Credentials) ([]byte, error) {
+	endpoint := "https://" + ref.apiHost() + "/v2/" + ref.Repository + suffix
+	resp, err := r.do(ctx, endpoint, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := r.authorize(ctx, challenge, ref.Repository, creds.login(ref.Registry))
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = r.do(ctx, endpoint, accept, authorization); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("registry returned %s for %s", resp.Status, endpoint)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("response for %s exceeds %d bytes", endpoint, limit)
+	}
+	return data, nil
+}
+
+func (r *Registry) do(ctx context.Context, endpoint, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return r.Client.Do(req)
+}
+
+// authorize answers the WWW-Authenticate challenge of the registry and returns the
+// Authorization header of the retried request. Basic challenges need a login; Bearer
+// challenges get a pull token, anonymously when there is no login.
+func (r *Registry) authorize(ctx context.Context, challenge, repository string, login *Login) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic"):
+		if login == nil {
+			return "", errors.New("registry requires a login, but no image pull secret has one")
+		}
+		return "Basic " + login.basic(), nil
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := r.token(ctx, params, repository, login)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+	return "", fmt.Errorf("registry requires unsupported authentication %q", scheme)
+}
+
+// token requests a pull token for repository from the realm of a Bearer challenge.
+func (r *Registry) token(ctx context.Context, params, repository string, login *Login) (string, error) {
+	attrs := parseChallengeParams(params)
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || realm.Scheme != "https" || realm.Host == "" {
+		return "", fmt.Errorf("registry returned an invalid token realm %q", attrs["realm"])
+	}
+	q := realm.Query()
+	if service := attrs["service"]; service != "" {
+		q.Set("service", service)
+	}
+	q.Set("scope", "repository:"+repository+":pull")
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if login != nil {
+		req.SetBasicAuth(login.Username, login.Password)
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBlobBytes)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", errors.New("token endpoint returned no token")
+}
+
+// parseChallengeParams parses the comma separated key="value" pairs of a WWW-Authenticate header.
+func parseChallengeParams(s string) map[string]string {
+	attrs := map[string]string{}
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+			rest = "," + rest
+		}
+		attrs[key] = strings.TrimSpace(value)
+		rest = strings.TrimSpace(rest)
+		s = strings.TrimPrefix(rest, ",")
+	}
+	return attrs
+}
+
+// ociManifest is the part of an image manifest read for cosign signatures.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
@@ -0,0 +1,162 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imageverify verifies the cosign signatures of preset images and pins them to
+// the digests that were verified.
+package imageverify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultResolveInterval is how long a verified digest is used before the tag is
+	// resolved again, so a moved tag is verified before it is rolled out.
+	defaultResolveInterval = 10 * time.Minute
+
+	// defaultFailureInterval is how long a failed verification is reported before it is retried.
+	defaultFailureInterval = time.Minute
+)
+
+// Verifier resolves image tags to digests and verifies their signatures against a Policy.
+// Results are cached per image reference.
+type Verifier struct {
+	policy   *Policy
+	registry *Registry
+	now      func() time.Time
+
+	mu      sync.Mutex
+	results map[string]*result
+}
+
+type result struct {
+	pinned  string
+	err     error
+	checked time.Time
+	// verified is the last successfully verified pinned reference, kept while the
+	// registry is unreachable.
+	verified string
+}
+
+// NewVerifier returns a Verifier that enforces policy.
+func NewVerifier(policy *Policy, registry *Registry) *Verifier {
+	return &Verifier{policy: policy, registry: registry, now: time.Now, results: map[string]*result{}}
+}
+
+// Verify returns image pinned to the digest whose signature was verified, as
+// registry/repository:tag@digest, or an error if the image cannot be verified. creds
+// are the logins used for private registries; nil reads the registry anonymously.
+func (v *Verifier) Verify(ctx context.Context, image string, creds Credentials) (string, error) {
+	v.mu.Lock()
+	cached := v.results[image]
+	v.mu.Unlock()
+	if cached != nil {
+		interval := defaultResolveInterval
+		if cached.err != nil {
+			interval = defaultFailureInterval
+		}
+		if v.now().Sub(cached.checked) < interval {
+			return cached.pinned, cached.err
+		}
+	}
+
+	pinned, transient, err := v.verify(ctx, image, creds)
+	r := &result{pinned: pinned, err: err, checked: v.now(), verified: pinned}
+	if err != nil {
+		r.verified = ""
+		if cached != nil && transient && cached.verified != "" {
+			// The digest verified before is still trusted; the tag cannot be checked for moves.
+			klog.InfoS("Keeping the verified digest while the registry is unreachable", "image", image, "err", err)
+			r = &result{pinned: cached.verified, checked: v.now(), verified: cached.verified}
+		}
+	}
+	v.mu.Lock()
+	v.results[image] = r
+	v.mu.Unlock()
+	return r.pinned, r.err
+}
+
+// verify reports whether a failure is transient, i.e. the registry could not be read.
+func (v *Verifier) verify(ctx context.Context, image string, creds Credentials) (pinned string, transient bool, err error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", false, err
+	}
+	rule := v.policy.ruleFor(ref)
+	if rule == nil {
+		return "", false, fmt.Errorf("no image verification rule matches %s", ref.Name())
+	}
+	digest, err := v.registry.Resolve(ctx, ref, creds)
+	if err != nil {
+		return "", true, err
+	}
+	sigs, err := v.registry.signatures(ctx, ref, digest, creds)
+	if err != nil {
+		return "", false, err
+	}
+	if err := verifySignatures(sigs, digest, rule, v.policy); err != nil {
+		return "", false, fmt.Errorf("%s@%s: %w", ref.Name(), digest, err)
+	}
+	ref.Digest = digest
+	return ref.String(), false, nil
+}
+
+// Pinned returns the verified pinned reference of image from the cache, and false if
+// image has not been verified.
+func (v *Verifier) Pinned(image string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if r := v.results[image]; r != nil && r.err == nil && r.pinned != "" {
+		return r.pinned, true
+	}
+	return "", false
+}
+
+var defaultVerifier atomic.Pointer[Verifier]
+
+// SetDefault installs the Verifier used by the controllers. It is set at startup when the
+// imageVerification feature gate is enabled.
+func SetDefault(v *Verifier) {
+	defaultVerifier.Store(v)
+}
+
+// Default returns the installed Verifier, or nil when image verification is off.
+func Default() *Verifier {
+	return defaultVerifier.Load()
+}
+
+// Pin returns image pinned to its verified digest. It returns image unchanged when image
+// verification is off or image has not been verified. Pin is applied where workloads are
+// rendered, so the image names compared elsewhere do not depend on the cache.
+func Pin(image string) string {
+	if v := Default(); v != nil {
+		if pinned, ok := v.Pinned(image); ok {
+			return pinned
+		}
+	}
+	return image
+}
+
+// Unpin returns image without the digest Pin added, for comparing a running image with
+// the tag it was rendered from.
+func Unpin(image string) string {
+	name, _, _ := strings.Cut(image, "@")
+	return name
+}
@@ -0,0 +1,359 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageverify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry serves one repository with anonymous token authentication.
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	requests  atomic.Int32
+	down      atomic.Bool
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	f := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	f.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+		if f.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:kaito/base:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+f.server.URL+`/token",service="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		kind, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/kaito/base/"), "/")
+		var data []byte
+		switch kind {
+		case "manifests":
+			data = f.manifests[name]
+		case "blobs":
+			data = f.blobs[name]
+		}
+		if data == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeRegistry) image(tag string) string {
+	return strings.TrimPrefix(f.server.URL, "https://") + "/kaito/base:" + tag
+}
+
+func (f *fakeRegistry) registry() *Registry {
+	return &Registry{Client: f.server.Client()}
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// push stores an image manifest under tag and returns its digest.
+func (f *fakeRegistry) push(tag string) string {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{},"layers":[],"annotations":{"tag":"` + tag + `"}}`)
+	f.manifests[tag] = manifest
+	return sha256Digest(manifest)
+}
+
+// sign stores a cosign signature of digest made by signer.
+func (f *fakeRegistry) sign(t *testing.T, digest string, key *ecdsa.PrivateKey, annotations map[string]string) []byte {
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"kaito/base"},"image":{"docker-manifest-digest":"` + digest + `"},"type":"cosign container image signature"},"optional":null}`)
+	sum := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	require.NoError(t, err)
+	f.blobs[sha256Digest(payload)] = payload
+
+	layerAnnotations := map[string]string{signatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	for k, v := range annotations {
+		layerAnnotations[k] = v
+	}
+	manifest, err := json.Marshal(ociManifest{
+		MediaType: "application/vnd.oci.image.manifest.v1+json",
+		Layers: []ociDescriptor{{
+			MediaType:   simpleSigningMediaType,
+			Digest:      sha256Digest(payload),
+			Size:        int64(len(payload)),
+			Annotations: layerAnnotations,
+		}},
+	})
+	require.NoError(t, err)
+	f.manifests[strings.Replace(digest, ":", "-", 1)+".sig"] = manifest
+	return sig
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func policyYAML(t *testing.T, p Policy) *Policy {
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	parsed, err := ParsePolicy(data)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestVerifyKeyPair(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRegistry(t)
+	signer, signerPEM := newKey(t)
+	other, _ := newKey(t)
+	digest := f.push("v1")
+	f.sign(t, digest, signer, nil)
+	unsignedDigest := f.push("unsigned")
+	wrongDigest := f.push("wrong-key")
+	f.sign(t, wrongDigest, other, nil)
+
+	host := strings.TrimPrefix(f.server.URL, "https://")
+	v := NewVerifier(policyYAML(t, Policy{Rules: []Rule{{Images: host + "/kaito/*", Keys: []string{signerPEM}}}}), f.registry())
+
+	pinned, err := v.Verify(ctx, f.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, f.image("v1")+"@"+digest, pinned)
+
+	_, err = v.Verify(ctx, f.image("unsigned"), nil)
+	assert.ErrorContains(t, err, "has no cosign signature")
+	assert.NotEmpty(t, unsignedDigest)
+
+	_, err = v.Verify(ctx, f.image("wrong-key"), nil)
+	assert.ErrorContains(t, err, "does not verify with any configured key")
+
+	// A signature of another image does not verify this one.
+	f.manifests[strings.Replace(unsignedDigest, ":", "-", 1)+".sig"] = f.manifests[strings.Replace(digest, ":", "-", 1)+".sig"]
+	v.results = map[string]*result{}
+	_, err = v.Verify(ctx, f.image("unsigned"), nil)
+	assert.ErrorContains(t, err, "signature is for "+digest)
+
+	_, err = v.Verify(ctx, "mcr.microsoft.com/other/image:1", nil)
+	assert.ErrorContains(t, err, "no image verification rule matches")
+}
+
+func TestVerifyCachesAndPins(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRegistry(t)
+	signer, signerPEM := newKey(t)
+	digest := f.push("v1")
+	f.sign(t, digest, signer, nil)
+
+	host := strings.TrimPrefix(f.server.URL, "https://")
+	v := NewVerifier(policyYAML(t, Policy{Rules: []Rule{{Images: host + "/kaito/base", Keys: []string{signerPEM}}}}), f.registry())
+	now := time.Now()
+	v.now = func() time.Time { return now }
+
+	SetDefault(nil)
+	assert.Equal(t, f.image("v1"), Pin(f.image("v1")))
+	SetDefault(v)
+	defer SetDefault(nil)
+	assert.Equal(t, f.image("v1"), Pin(f.image("v1")), "not verified yet")
+
+	pinned, err := v.Verify(ctx, f.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, pinned, Pin(f.image("v1")))
+
+	requests := f.requests.Load()
+	_, err = v.Verify(ctx, f.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, requests, f.requests.Load(), "served from the cache")
+
+	// After the interval the tag is resolved again. While the registry is down the verified
+	// digest is kept.
+	now = now.Add(defaultResolveInterval)
+	f.down.Store(true)
+	got, err := v.Verify(ctx, f.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, pinned, got)
+
+	// A moved tag is verified again and refused when unsigned.
+	f.down.Store(false)
+	f.manifests["v1"] = []byte(`{"schemaVersion":2,"moved":true}`)
+	now = now.Add(defaultResolveInterval)
+	_, err = v.Verify(ctx, f.image("v1"), nil)
+	assert.ErrorContains(t, err, "has no cosign signature")
+	assert.Equal(t, f.image("v1"), Pin(f.image("v1")))
+}
+
+// oidFulcioIssuerV2 is the OIDC issuer extension of Fulcio certificates.
+var oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+// keylessFixture is a Fulcio-like CA, a signing certificate and a Rekor-like log key.
+type keylessFixture struct {
+	rootPEM  string
+	rekor    *ecdsa.PrivateKey
+	rekorPEM string
+	leaf     *ecdsa.PrivateKey
+	leafPEM  []byte
+	signedAt time.Time
+}
+
+func newKeylessFixture(t *testing.T, subject, issuer string) *keylessFixture {
+	rootKey, _ := newKey(t)
+	root := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, root, root, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err = x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	require.NoError(t, err)
+	leafKey, _ := newKey(t)
+	signedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	leaf := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-time.Minute),
+		NotAfter:        signedAt.Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses:  []string{subject},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuerExt}},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, root, &leafKey.PublicKey, rootKey)
+	require.NoError(t, err)
+
+	rekor, rekorPEM := newKey(t)
+	return &keylessFixture{
+		rootPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})),
+		rekor:    rekor,
+		rekorPEM: rekorPEM,
+		leaf:     leafKey,
+		leafPEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+		signedAt: signedAt,
+	}
+}
+
+// sign stores a keyless signature of digest with a transparency log bundle.
+func (k *keylessFixture) sign(t *testing.T, f *fakeRegistry, digest string) {
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	sig := f.sign(t, digest, k.leaf, map[string]string{certificateAnnotation: string(k.leafPEM)})
+
+	var m ociManifest
+	require.NoError(t, json.Unmarshal(f.manifests[sigTag], &m))
+	payload := f.blobs[m.Layers[0].Digest]
+	payloadSum := sha256.Sum256(payload)
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(payloadSum[:])}},
+			"signature": map[string]any{
+				"content":   base64.StdEncoding.EncodeToString(sig),
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(k.leafPEM)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	rekorDER, err := x509.MarshalPKIXPublicKey(&k.rekor.PublicKey)
+	require.NoError(t, err)
+	logID := sha256.Sum256(rekorDER)
+	entry := rekorBundlePayload{Body: base64.StdEncoding.EncodeToString(body), IntegratedTime: k.signedAt.Unix(), LogID: hex.EncodeToString(logID[:]), LogIndex: 7}
+	canonical, err := json.Marshal(entry)
+	require.NoError(t, err)
+	sum := sha256.Sum256(canonical)
+	set, err := ecdsa.SignASN1(rand.Reader, k.rekor, sum[:])
+	require.NoError(t, err)
+	bundle, err := json.Marshal(rekorBundle{SignedEntryTimestamp: set, Payload: entry})
+	require.NoError(t, err)
+
+	m.Layers[0].Annotations[bundleAnnotation] = string(bundle)
+	f.manifests[sigTag], err = json.Marshal(m)
+	require.NoError(t, err)
+}
+
+func TestVerifyKeyless(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRegistry(t)
+	fixture := newKeylessFixture(t, "release@kaito.sh", "https://accounts.example.com")
+	host := strings.TrimPrefix(f.server.URL, "https://")
+
+	newVerifier := func(identity Identity, rekorPEM string) *Verifier {
+		return NewVerifier(policyYAML(t, Policy{
+			Rules:          []Rule{{Images: host + "/kaito/*", Identities: []Identity{identity}}},
+			FulcioRoots:    fixture.rootPEM,
+			RekorPublicKey: rekorPEM,
+		}), f.registry())
+	}
+	identity := Identity{Issuer: "https://accounts.example.com", Subject: "release@kaito.sh"}
+
+	digest := f.push("v1")
+	fixture.sign(t, f, digest)
+
+	// The signing certificate has expired, but it was valid when the log recorded the signature.
+	pinned, err := newVerifier(identity, fixture.rekorPEM).Verify(ctx, f.image("v1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, f.image("v1")+"@"+digest, pinned)
+
+	_, err = newVerifier(Identity{Issuer: identity.Issuer, Subject: "someone@kaito.sh"}, fixture.rekorPEM).Verify(ctx, f.image("v1"), nil)
+	assert.ErrorContains(t, err, "no matching CertificateIdentity")
+
+	_, otherLogPEM := newKey(t)
+	_, err = newVerifier(identity, otherLogPEM).Verify(ctx, f.image("v1"), nil)
+	assert.ErrorContains(t, err, "not enough verified log entries")
+
+	// Without the bundle the expired certificate cannot be trusted.
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	var m ociManifest
+	require.NoError(t, json.Unmarshal(f.manifests[sigTag], &m))
+	delete(m.Layers[0].Annotations, bundleAnnotation)
+	f.manifests[sigTag], _ = json.Marshal(m)
+	_, err = newVerifier(identity, fixture.rekorPEM).Verify(ctx, f.image("v1"), nil)
+	assert.ErrorContains(t, err, "no transparency log bundle")
+}
@@ -0,0 +1,101 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/inferenceset"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// reconcileScaledObject reconciles the ScaledObject of an InferenceSet and marks the
+// InferenceSet not ready when that fails.
+func (c *InferenceSetReconciler) reconcileScaledObject(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	err := c.ensureScaledObject(ctx, iObj)
+	if err == nil {
+		return nil
+	}
+	klog.ErrorS(err, "failed to reconcile ScaledObject", "inferenceset", klog.KObj(iObj))
+	if updateErr := inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionFalse,
+		"inferencesetFailed", err.Error()); updateErr != nil {
+		klog.ErrorS(updateErr, "failed to update inferenceset status", "inferenceset", klog.KObj(iObj))
+		return updateErr
+	}
+	return err
+}
+
+// ensureScaledObject reconciles the KEDA ScaledObject of an InferenceSet with spec.autoscaling.
+// KEDA scales the InferenceSet through its scale subresource, and every replica it adds or
+// removes is a Workspace that provisions or releases its own NodeClaims. When autoscaling is
+// removed from the spec, the ScaledObject created for it is deleted.
+func (c *InferenceSetReconciler) ensureScaledObject(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	desired := manifests.GenerateScaledObjectManifest(iObj)
+	if desired == nil {
+		return c.deleteScaledObject(ctx, iObj)
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(manifests.ScaledObjectGVK)
+	err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	switch {
+	case meta.IsNoMatchError(err):
+		return fmt.Errorf("KEDA is not installed, ScaledObject CRD %s not found: %w",
+			manifests.ScaledObjectGVK.GroupKind(), err)
+	case apierrors.IsNotFound(err):
+		klog.InfoS("Creating ScaledObject", "inferenceset", klog.KObj(iObj))
+		return c.Create(ctx, desired)
+	case err != nil:
+		return fmt.Errorf("failed to get ScaledObject %s: %w", desired.GetName(), err)
+	}
+
+	if !metav1.IsControlledBy(existing, iObj) {
+		return fmt.Errorf("ScaledObject %s already exists and is not managed by InferenceSet %s", existing.GetName(), iObj.Name)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	existing.Object["spec"] = desired.Object["spec"]
+	klog.InfoS("Updating ScaledObject", "inferenceset", klog.KObj(iObj))
+	return c.Update(ctx, existing)
+}
+
+func (c *InferenceSetReconciler) deleteScaledObject(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(manifests.ScaledObjectGVK)
+	err := c.Get(ctx, client.ObjectKey{Name: iObj.Name, Namespace: iObj.Namespace}, existing)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ScaledObject %s: %w", iObj.Name, err)
+	}
+	// Leave ScaledObjects that were not created for spec.autoscaling, such as the ones
+	// provisioned by the KEDA KAITO scaler, alone.
+	if !metav1.IsControlledBy(existing, iObj) {
+		return nil
+	}
+	klog.InfoS("Deleting ScaledObject", "inferenceset", klog.KObj(iObj))
+	return client.IgnoreNotFound(c.Delete(ctx, existing))
+}
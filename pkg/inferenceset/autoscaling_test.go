@@ -0,0 +1,177 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestEnsureScaledObject(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(manifests.ScaledObjectGVK, meta.RESTScopeNamespace)
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithRESTMapper(mapper).Build()}
+	ctx := context.Background()
+	key := client.ObjectKey{Name: "phi", Namespace: "default"}
+	getScaledObject := func() (*unstructured.Unstructured, error) {
+		so := &unstructured.Unstructured{}
+		so.SetGroupVersionKind(manifests.ScaledObjectGVK)
+		return so, c.Get(ctx, key, so)
+	}
+
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"}}
+	require.NoError(t, c.ensureScaledObject(ctx, iObj))
+	_, err := getScaledObject()
+	assert.True(t, apierrors.IsNotFound(err))
+
+	iObj.Spec.Autoscaling = &kaitov1beta1.InferenceSetAutoscalingSpec{
+		MaxReplicas: 3,
+		Triggers: []kaitov1beta1.ScaleTrigger{{Prometheus: &kaitov1beta1.PrometheusScaleTrigger{
+			ServerAddress: "http://prometheus:9090",
+			Query:         "sum(vllm:num_requests_waiting)",
+			Threshold:     "10",
+		}}},
+	}
+	require.NoError(t, c.ensureScaledObject(ctx, iObj))
+	so, err := getScaledObject()
+	require.NoError(t, err)
+	maxReplicas, _, _ := unstructured.NestedInt64(so.Object, "spec", "maxReplicaCount")
+	assert.Equal(t, int64(3), maxReplicas)
+
+	iObj.Spec.Autoscaling.MaxReplicas = 6
+	require.NoError(t, c.ensureScaledObject(ctx, iObj))
+	so, err = getScaledObject()
+	require.NoError(t, err)
+	maxReplicas, _, _ = unstructured.NestedInt64(so.Object, "spec", "maxReplicaCount")
+	assert.Equal(t, int64(6), maxReplicas)
+
+	iObj.Spec.Autoscaling = nil
+	require.NoError(t, c.ensureScaledObject(ctx, iObj))
+	_, err = getScaledObject()
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestEnsureScaledObjectForeignObject(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(manifests.ScaledObjectGVK, meta.RESTScopeNamespace)
+	foreign := &unstructured.Unstructured{}
+	foreign.SetGroupVersionKind(manifests.ScaledObjectGVK)
+	foreign.SetName("phi")
+	foreign.SetNamespace("default")
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithRESTMapper(mapper).
+		WithObjects(foreign).Build()}
+	ctx := context.Background()
+
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"}}
+	// Without spec.autoscaling a ScaledObject the InferenceSet does not own is left in place.
+	require.NoError(t, c.ensureScaledObject(ctx, iObj))
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(manifests.ScaledObjectGVK)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(foreign), existing))
+
+	iObj.Spec.Autoscaling = &kaitov1beta1.InferenceSetAutoscalingSpec{
+		MaxReplicas: 2,
+		Triggers: []kaitov1beta1.ScaleTrigger{{Prometheus: &kaitov1beta1.PrometheusScaleTrigger{
+			ServerAddress: "http://prometheus:9090", Query: "up", Threshold: "1",
+		}}},
+	}
+	assert.ErrorContains(t, c.ensureScaledObject(ctx, iObj), "not managed by InferenceSet")
+}
+
+func TestEnsureScaledObjectKEDANotInstalled(t *testing.T) {
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return &meta.NoKindMatchError{GroupKind: manifests.ScaledObjectGVK.GroupKind()}
+			},
+		}).Build()}
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default"}}
+
+	assert.NoError(t, c.ensureScaledObject(context.Background(), iObj))
+
+	iObj.Spec.Autoscaling = &kaitov1beta1.InferenceSetAutoscalingSpec{
+		MaxReplicas: 2,
+		Triggers: []kaitov1beta1.ScaleTrigger{{Prometheus: &kaitov1beta1.PrometheusScaleTrigger{
+			ServerAddress: "http://prometheus:9090", Query: "up", Threshold: "1",
+		}}},
+	}
+	assert.ErrorContains(t, c.ensureScaledObject(context.Background(), iObj), "KEDA is not installed")
+}
+
+func TestReconcileScaledObjectWhenRolloutFails(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for gvk := range scheme.AllKnownTypes() {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	mapper.Add(manifests.ScaledObjectGVK, meta.RESTScopeNamespace)
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid", Finalizers: []string{consts.InferenceSetFinalizer}},
+		Spec: kaitov1beta1.InferenceSetSpec{
+			Replicas: ptr.To[int32](1),
+			Autoscaling: &kaitov1beta1.InferenceSetAutoscalingSpec{
+				MaxReplicas: 3,
+				Triggers: []kaitov1beta1.ScaleTrigger{{Prometheus: &kaitov1beta1.PrometheusScaleTrigger{
+					ServerAddress: "http://prometheus:9090", Query: "up", Threshold: "1",
+				}}},
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithStatusSubresource(&kaitov1beta1.InferenceSet{}).WithObjects(iObj).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*kaitov1beta1.Workspace); ok {
+					return errors.New("workspace create failed")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+	c := &InferenceSetReconciler{Client: cl, Scheme: scheme, klogger: klog.Background(), expectations: utils.NewControllerExpectations()}
+	ctx := context.Background()
+
+	_, err := c.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "phi", Namespace: "default"}})
+	assert.ErrorContains(t, err, "workspace create failed")
+
+	// The ScaledObject is created although no workspace could be.
+	so := &unstructured.Unstructured{}
+	so.SetGroupVersionKind(manifests.ScaledObjectGVK)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), so))
+	maxReplicas, _, _ := unstructured.NestedInt64(so.Object, "spec", "maxReplicaCount")
+	assert.Equal(t, int64(3), maxReplicas)
+}
@@ -15,6 +15,7 @@ package inferenceset
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"sort"
@@ -25,11 +26,13 @@ import (
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -104,11 +107,20 @@ func (c *InferenceSetReconciler) Reconcile(ctx context.Context, req reconcile.Re
 		return c.deleteInferenceSet(ctx, iObj)
 	}
 
+	// Nothing is changed while reconciliation is paused, neither the workspaces nor the
+	// routing. The annotation change that resumes it triggers the next reconcile.
+	if paused, err := c.syncPause(ctx, iObj); err != nil || paused {
+		return reconcile.Result{}, err
+	}
+
 	if err := c.syncControllerRevision(ctx, iObj); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return c.addOrUpdateInferenceSet(ctx, iObj)
+	// The ScaledObject is reconciled whatever the outcome of the workspace rollout, so a
+	// failing rollout does not leave KEDA scaling the InferenceSet with a stale spec.
+	result, err := c.addOrUpdateInferenceSet(ctx, iObj)
+	return result, errors.Join(err, c.reconcileScaledObject(ctx, iObj))
 }
 
 func (c *InferenceSetReconciler) ensureFinalizer(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
@@ -153,6 +165,14 @@ func (c *InferenceSetReconciler) garbageCollectInferenceSet(ctx context.Context,
 		}
 	}
 
+	// The shadow Service goes away with its owner, but the RequestMirror filters live on
+	// HTTPRoutes the InferenceSet does not own.
+	if featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] && iObj.Spec.ShadowTo != nil {
+		if _, err := c.syncRequestMirrors(ctx, iObj, 0); err != nil && !errors.Is(err, errGatewayAPINotInstalled) {
+			return ctrl.Result{}, err
+		}
+	}
+
 	updateErr := inferenceset.UpdateInferenceSetWithRetry(ctx, c.Client, iObj, func(ws *kaitov1beta1.InferenceSet) error {
 		controllerutil.RemoveFinalizer(ws, consts.InferenceSetFinalizer)
 		return nil
@@ -214,14 +234,23 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 	}
 	klog.InfoS("Found workspaces for inference set", "name", iObj.Name, "current", len(wsList.Items), "desired", desiredReplicas)
 
+	// Roll workspaces over to a changed template instance type. While a migration
+	// step is in flight it owns the replica count and the scaling below is skipped.
+	migrating, err := c.migrateInstanceType(ctx, iObj, wsList, desiredReplicas)
+	if err != nil {
+		klog.ErrorS(err, "failed to migrate instance type", "inferenceset", klog.KObj(iObj))
+		return ctrl.Result{}, err
+	}
+
 	replicaNumToDelete := len(wsList.Items) - int(desiredReplicas)
-	if replicaNumToDelete > 0 {
+	if replicaNumToDelete > 0 && !migrating {
 		klog.InfoS("Found extra workspaces, deleting...", "current", len(wsList.Items), "desired", desiredReplicas)
 
 		// Partition workspaces into those already being deleted, those that are
 		// not ready, and those that are ready. Workspaces already being deleted
 		// count toward the target without issuing a new delete; among the rest,
-		// prefer deleting non-ready workspaces before ready ones.
+		// prefer deleting non-ready workspaces before ready ones, and workspaces
+		// on an outdated instance type before current ones.
 		var notReady, ready []*kaitov1beta1.Workspace
 		for i := range wsList.Items {
 			ws := &wsList.Items[i]
@@ -234,6 +263,11 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 				ready = append(ready, ws)
 			}
 		}
+		for _, group := range [][]*kaitov1beta1.Workspace{notReady, ready} {
+			sort.SliceStable(group, func(i, j int) bool {
+				return isOutdatedInstanceType(iObj, group[i]) && !isOutdatedInstanceType(iObj, group[j])
+			})
+		}
 
 		var toDelete []*kaitov1beta1.Workspace
 		for _, ws := range append(notReady, ready...) {
@@ -275,7 +309,7 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 	}
 
 	replicaNumToCreate := int(desiredReplicas) - len(wsList.Items)
-	if replicaNumToCreate > 0 {
+	if replicaNumToCreate > 0 && !migrating {
 		klog.InfoS("Need to create more workspaces...", "current", len(wsList.Items), "desired", desiredReplicas)
 		// Set creation expectations before issuing any create so that a stale
 		// cache read in a subsequent reconcile does not create duplicate
@@ -286,51 +320,7 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 			return reconcile.Result{}, err
 		}
 		for i := range replicaNumToCreate {
-			workspaceObj := &kaitov1beta1.Workspace{}
-			workspaceObj.GenerateName = iObj.Name + "-"
-			workspaceObj.Namespace = iObj.Namespace
-
-			// Start with labels from the template metadata, then add controller labels.
-			workspaceLabels := maps.Clone(iObj.Spec.Template.Labels)
-			if workspaceLabels == nil {
-				workspaceLabels = make(map[string]string)
-			}
-			// Also propagate select labels from the InferenceSet's own metadata,
-			// in case template.metadata.labels was pruned by the API server.
-			if role, ok := iObj.Labels[kaitov1beta1.LabelInferenceRole]; ok {
-				workspaceLabels[kaitov1beta1.LabelInferenceRole] = role
-			}
-			if mriParent, ok := iObj.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent]; ok {
-				workspaceLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mriParent
-			}
-			workspaceLabels[consts.WorkspaceCreatedByInferenceSetLabel] = iObj.Name
-			workspaceObj.Labels = workspaceLabels
-
-			// Start with annotations from the template metadata.
-			workspaceAnnotations := maps.Clone(iObj.Spec.Template.Annotations)
-			// Propagate the disable-benchmark opt-out so each child workspace inherits it.
-			// Benchmark is on by default; only propagate when explicitly disabled.
-			if !kaitov1beta1.IsInferenceSetBenchmarkEnabled(iObj) {
-				if workspaceAnnotations == nil {
-					workspaceAnnotations = make(map[string]string)
-				}
-				workspaceAnnotations[kaitov1beta1.AnnotationDisableBenchmark] = "true"
-			}
-			workspaceObj.Annotations = workspaceAnnotations
-			workspaceObj.OwnerReferences = []metav1.OwnerReference{
-				*metav1.NewControllerRef(iObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
-			}
-			workspaceObj.Resource = kaitov1beta1.ResourceSpec{
-				LabelSelector: iObj.Spec.Selector,
-				Partition:     iObj.Spec.Template.Resource.Partition,
-			}
-			// Only set InstanceType when node auto-provisioning is enabled.
-			// In BYO mode, the Workspace webhook rejects instanceType.
-			if consts.ActiveNodeProvisioner != consts.NodeProvisionerBYO {
-				workspaceObj.Resource.InstanceType = iObj.Spec.Template.Resource.InstanceType
-			}
-			workspaceObj.Inference = &iObj.Spec.Template.Inference
-
+			workspaceObj := generateWorkspace(iObj)
 			klog.InfoS("creating workspace", "workspace", workspaceObj.Name, "index", i)
 			if err := c.Client.Create(ctx, workspaceObj); err != nil {
 				// The create failed, so no create event will be observed for it;
@@ -351,10 +341,13 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 	for k, v := range iObj.Spec.Template.Labels {
 		desiredLabels[k] = v
 	}
-	// Propagate inference-role from InferenceSet metadata (reliable even if template labels are pruned).
+	// Propagate inference-role and inference-tier from InferenceSet metadata (reliable even if template labels are pruned).
 	if role, ok := iObj.Labels[kaitov1beta1.LabelInferenceRole]; ok {
 		desiredLabels[kaitov1beta1.LabelInferenceRole] = role
 	}
+	if tier, ok := iObj.Labels[kaitov1beta1.LabelInferenceTier]; ok {
+		desiredLabels[kaitov1beta1.LabelInferenceTier] = tier
+	}
 	if mriParent, ok := iObj.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent]; ok {
 		desiredLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mriParent
 	}
@@ -381,6 +374,19 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 		}
 	}
 
+	// Propagate the template fields that existing workspaces take over in place.
+	for i := range wsList.Items {
+		ws := &wsList.Items[i]
+		if !applyInPlaceTemplateFields(iObj, ws) {
+			continue
+		}
+		klog.InfoS("Reconciling workspace maintenance window and placement", "workspace", klog.KObj(ws))
+		if err := c.Client.Update(ctx, ws); err != nil {
+			klog.ErrorS(err, "failed to update workspace maintenance window and placement", "workspace", klog.KObj(ws))
+			return ctrl.Result{}, err
+		}
+	}
+
 	// check whether all the workspaces are ready
 	totalTPM, readyReplicas, benchmarkedReplicas, hasBenchmarkTPMResult := aggregateBenchmarkResults(wsList.Items)
 
@@ -438,7 +444,9 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 		return reconcile.Result{}, err
 	}
 
-	if readyReplicas == int(desiredReplicas) {
+	// A migration surge replica may briefly make more replicas ready than desired.
+	minReady := minReadyReplicas(iObj, desiredReplicas)
+	if readyReplicas >= minReady {
 		if err = inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionTrue,
 			"inferencesetReady", "inferenceset is ready"); err != nil {
 			klog.ErrorS(err, "failed to update inferenceset status", "inferenceset", klog.KObj(iObj))
@@ -446,7 +454,7 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 		}
 	} else {
 		if err = inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionFalse,
-			"inferencesetNotReady", fmt.Sprintf("inferenceset is not ready, %d/%d replicas are ready", readyReplicas, desiredReplicas)); err != nil {
+			"inferencesetNotReady", fmt.Sprintf("inferenceset is not ready, %d/%d replicas are ready, %d required", readyReplicas, desiredReplicas, minReady)); err != nil {
 			klog.ErrorS(err, "failed to update inferenceset status", "inferenceset", klog.KObj(iObj))
 			return reconcile.Result{}, err
 		}
@@ -477,7 +485,120 @@ func (c *InferenceSetReconciler) addOrUpdateInferenceSet(ctx context.Context, iO
 		}
 	}
 
-	return reconcile.Result{}, nil
+	if err = c.ensureShadow(ctx, iObj); err != nil {
+		klog.ErrorS(err, "failed to reconcile traffic shadowing", "inferenceset", klog.KObj(iObj))
+		if updateErr := inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeShadowing, metav1.ConditionFalse,
+			"ShadowingFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update inferenceset status", "inferenceset", klog.KObj(iObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+	if err = c.ensureTrafficClasses(ctx, iObj); err != nil {
+		klog.ErrorS(err, "failed to reconcile traffic classes", "inferenceset", klog.KObj(iObj))
+		return reconcile.Result{}, err
+	}
+	if err = c.ensureRouteTimeouts(ctx, iObj); err != nil {
+		klog.ErrorS(err, "failed to reconcile HTTPRoute timeouts", "inferenceset", klog.KObj(iObj))
+		return reconcile.Result{}, err
+	}
+	var requeueAfter time.Duration
+	// HTTPRoutes are not watched, so routes created after the InferenceSet are picked up on resync.
+	if iObj.Spec.ShadowTo != nil || requestTimeout(iObj) != nil {
+		requeueAfter = shadowResyncPeriod
+	}
+	// A migration deferred to the maintenance window resumes when the window opens.
+	if resumeAfter := migrationResumeAfter(iObj, wsList, time.Now()); resumeAfter > 0 && (requeueAfter == 0 || resumeAfter < requeueAfter) {
+		requeueAfter = resumeAfter
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// minReadyReplicas returns the number of ready replicas at which iObj is Ready: all desired
+// replicas unless spec.minReadyReplicas is set, in which case it is capped at the desired
+// replicas.
+func minReadyReplicas(iObj *kaitov1beta1.InferenceSet, desiredReplicas int32) int {
+	if iObj.Spec.MinReadyReplicas == nil {
+		return int(desiredReplicas)
+	}
+	minReady, err := intstr.GetScaledValueFromIntOrPercent(iObj.Spec.MinReadyReplicas, int(desiredReplicas), true)
+	if err != nil {
+		// Rejected by the webhook; fall back to requiring all replicas.
+		return int(desiredReplicas)
+	}
+	return min(minReady, int(desiredReplicas))
+}
+
+// generateWorkspace returns a new workspace replica rendered from the InferenceSet template.
+// applyInPlaceTemplateFields copies the maintenance window and the placement of the template
+// of iObj to ws and reports whether ws changed. The maintenance window does not change the
+// workload, and a placement change is rolled out by the workspace itself, so neither needs
+// new workspaces.
+func applyInPlaceTemplateFields(iObj *kaitov1beta1.InferenceSet, ws *kaitov1beta1.Workspace) bool {
+	changed := false
+	if !apiequality.Semantic.DeepEqual(ws.MaintenanceWindow, iObj.Spec.Template.MaintenanceWindow) {
+		ws.MaintenanceWindow = iObj.Spec.Template.MaintenanceWindow.DeepCopy()
+		changed = true
+	}
+	if !apiequality.Semantic.DeepEqual(ws.Resource.Placement, iObj.Spec.Template.Resource.Placement) {
+		ws.Resource.Placement = iObj.Spec.Template.Resource.Placement.DeepCopy()
+		changed = true
+	}
+	return changed
+}
+
+func generateWorkspace(iObj *kaitov1beta1.InferenceSet) *kaitov1beta1.Workspace {
+	workspaceObj := &kaitov1beta1.Workspace{}
+	workspaceObj.GenerateName = iObj.Name + "-"
+	workspaceObj.Namespace = iObj.Namespace
+
+	// Start with labels from the template metadata, then add controller labels.
+	workspaceLabels := maps.Clone(iObj.Spec.Template.Labels)
+	if workspaceLabels == nil {
+		workspaceLabels = make(map[string]string)
+	}
+	// Also propagate select labels from the InferenceSet's own metadata,
+	// in case template.metadata.labels was pruned by the API server.
+	if role, ok := iObj.Labels[kaitov1beta1.LabelInferenceRole]; ok {
+		workspaceLabels[kaitov1beta1.LabelInferenceRole] = role
+	}
+	if tier, ok := iObj.Labels[kaitov1beta1.LabelInferenceTier]; ok {
+		workspaceLabels[kaitov1beta1.LabelInferenceTier] = tier
+	}
+	if mriParent, ok := iObj.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent]; ok {
+		workspaceLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = mriParent
+	}
+	workspaceLabels[consts.WorkspaceCreatedByInferenceSetLabel] = iObj.Name
+	workspaceObj.Labels = workspaceLabels
+
+	// Start with annotations from the template metadata.
+	workspaceAnnotations := maps.Clone(iObj.Spec.Template.Annotations)
+	// Propagate the disable-benchmark opt-out so each child workspace inherits it.
+	// Benchmark is on by default; only propagate when explicitly disabled.
+	if !kaitov1beta1.IsInferenceSetBenchmarkEnabled(iObj) {
+		if workspaceAnnotations == nil {
+			workspaceAnnotations = make(map[string]string)
+		}
+		workspaceAnnotations[kaitov1beta1.AnnotationDisableBenchmark] = "true"
+	}
+	workspaceObj.Annotations = workspaceAnnotations
+	workspaceObj.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(iObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
+	}
+	workspaceObj.Resource = kaitov1beta1.ResourceSpec{
+		LabelSelector: iObj.Spec.Selector,
+		Partition:     iObj.Spec.Template.Resource.Partition,
+		Placement:     iObj.Spec.Template.Resource.Placement,
+	}
+	// Only set InstanceType when node auto-provisioning is enabled.
+	// In BYO mode, the Workspace webhook rejects instanceType.
+	if consts.ActiveNodeProvisioner != consts.NodeProvisionerBYO {
+		workspaceObj.Resource.InstanceType = iObj.Spec.Template.Resource.InstanceType
+	}
+	workspaceObj.Inference = &iObj.Spec.Template.Inference
+	workspaceObj.MaintenanceWindow = iObj.Spec.Template.MaintenanceWindow.DeepCopy()
+	return workspaceObj
 }
 
 // ensureGatewayAPIInferenceExtension reconciles Gateway API Inference Extension components for a InferenceSet.
@@ -542,11 +663,15 @@ func (c *InferenceSetReconciler) ensureGatewayAPIInferenceExtension(ctx context.
 			return err
 		}
 	} else {
+		adopted, err := resources.AdoptResource(existingOCIRepo, ociRepository)
+		if err != nil {
+			return err
+		}
 		equal, err := utils.ClientObjectSpecEqual(ociRepository, existingOCIRepo)
 		if err != nil {
 			return err
 		}
-		if !equal {
+		if !equal || adopted {
 			existingOCIRepo.Spec = ociRepository.Spec
 			if err := c.Update(ctx, existingOCIRepo); err != nil {
 				return err
@@ -565,11 +690,15 @@ func (c *InferenceSetReconciler) ensureGatewayAPIInferenceExtension(ctx context.
 			return err
 		}
 	} else {
+		adopted, err := resources.AdoptResource(existingHelmRelease, helmRelease)
+		if err != nil {
+			return err
+		}
 		equal, err := utils.ClientObjectSpecEqual(helmRelease, existingHelmRelease)
 		if err != nil {
 			return err
 		}
-		if !equal {
+		if !equal || adopted {
 			existingHelmRelease.Spec = helmRelease.Spec
 			if err := c.Update(ctx, existingHelmRelease); err != nil {
 				return err
@@ -713,7 +842,8 @@ func (c *InferenceSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// We don't need to own InferencePool and InferenceModel because they are managed by Flux's HelmRelease
 		builder = builder.
 			Owns(&helmv2.HelmRelease{}).
-			Owns(&sourcev1.OCIRepository{})
+			Owns(&sourcev1.OCIRepository{}).
+			Owns(&gaiev1alpha2.InferenceObjective{})
 	}
 
 	go monitorInferenceSets(context.Background(), c.Client)
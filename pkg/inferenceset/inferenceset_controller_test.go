@@ -31,6 +31,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kaito-project/kaito/api/v1beta1"
@@ -506,3 +507,43 @@ func TestInferenceSetBenchmarkAggregation(t *testing.T) {
 		})
 	}
 }
+
+func TestMinReadyReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		minReady *intstr.IntOrString
+		desired  int32
+		expected int
+	}{
+		{name: "unset requires all replicas", desired: 4, expected: 4},
+		{name: "absolute number", minReady: lo.ToPtr(intstr.FromInt32(2)), desired: 4, expected: 2},
+		{name: "absolute number capped at desired replicas", minReady: lo.ToPtr(intstr.FromInt32(5)), desired: 3, expected: 3},
+		{name: "percentage rounds up", minReady: lo.ToPtr(intstr.FromString("50%")), desired: 3, expected: 2},
+		{name: "percentage of zero replicas", minReady: lo.ToPtr(intstr.FromString("50%")), desired: 0, expected: 0},
+		{name: "invalid value requires all replicas", minReady: lo.ToPtr(intstr.FromString("half")), desired: 4, expected: 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			iObj := &v1beta1.InferenceSet{Spec: v1beta1.InferenceSetSpec{MinReadyReplicas: tc.minReady}}
+			assert.Equal(t, tc.expected, minReadyReplicas(iObj, tc.desired))
+		})
+	}
+}
+
+func TestApplyInPlaceTemplateFields(t *testing.T) {
+	iObj := &v1beta1.InferenceSet{}
+	ws := &v1beta1.Workspace{}
+	assert.False(t, applyInPlaceTemplateFields(iObj, ws))
+
+	iObj.Spec.Template.Resource.Placement = &v1beta1.PlacementSpec{Policy: v1beta1.PlacementPolicyDedicated, SpreadAcrossHosts: true}
+	iObj.Spec.Template.MaintenanceWindow = &v1beta1.WorkspaceMaintenanceWindow{StartTime: "22:00", EndTime: "04:00"}
+	assert.True(t, applyInPlaceTemplateFields(iObj, ws))
+	assert.Equal(t, iObj.Spec.Template.Resource.Placement, ws.Resource.Placement)
+	assert.Equal(t, iObj.Spec.Template.MaintenanceWindow, ws.MaintenanceWindow)
+	assert.False(t, applyInPlaceTemplateFields(iObj, ws))
+
+	// Removing the placement from the template removes it from the workspaces.
+	iObj.Spec.Template.Resource.Placement = nil
+	assert.True(t, applyInPlaceTemplateFields(iObj, ws))
+	assert.Nil(t, ws.Resource.Placement)
+}
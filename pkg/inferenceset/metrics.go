@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
 )
 
 var (
@@ -32,7 +33,7 @@ var (
 			Name: "kaito_inferenceset_count",
 			Help: "Number of InferenceSets in a certain phase (succeeded, error, pending, deleting)",
 		},
-		[]string{"phase"},
+		append([]string{"phase"}, metriclabels.Fleet...),
 	)
 )
 
@@ -57,25 +58,31 @@ func monitorInferenceSets(ctx context.Context, k8sClient client.Client) {
 				continue
 			}
 
-			phaseCounts := map[string]float64{
-				"succeeded": 0,
-				"error":     0,
-				"pending":   0,
-				"deleting":  0,
-			}
+			setInferenceSetCounts(isList.Items)
+		}
+	}
+}
 
-			for _, is := range isList.Items {
-				phase := determineInferenceSetPhase(&is)
-				if _, ok := phaseCounts[phase]; !ok {
-					phaseCounts[phase] = 0
-				}
-				phaseCounts[phase]++
-			}
+// setInferenceSetCounts replaces the phase counts with those of inferenceSets. The gauge is
+// reset first so label combinations without InferenceSets left are dropped.
+func setInferenceSetCounts(inferenceSets []kaitov1beta1.InferenceSet) {
+	type key struct{ phase, namespace, preset, runtime, instanceType string }
+	phaseCounts := map[key]float64{}
+	for i := range inferenceSets {
+		is := &inferenceSets[i]
+		values := metriclabels.InferenceSetFleetValues(is)
+		phaseCounts[key{
+			phase:        determineInferenceSetPhase(is),
+			namespace:    values[0],
+			preset:       values[1],
+			runtime:      values[2],
+			instanceType: values[3],
+		}]++
+	}
 
-			for phase, count := range phaseCounts {
-				inferencesetPhaseCount.WithLabelValues(phase).Set(count)
-			}
-		}
+	inferencesetPhaseCount.Reset()
+	for k, count := range phaseCounts {
+		inferencesetPhaseCount.WithLabelValues(k.phase, k.namespace, k.preset, k.runtime, k.instanceType).Set(count)
 	}
 }
 
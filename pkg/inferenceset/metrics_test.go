@@ -16,6 +16,7 @@ package inferenceset
 import (
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -120,3 +121,23 @@ func TestDetermineInferenceSetPhase(t *testing.T) {
 		})
 	}
 }
+
+func TestSetInferenceSetCounts(t *testing.T) {
+	newInferenceSet := func(name, preset string) kaitov1beta1.InferenceSet {
+		is := kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		is.Spec.Template.Resource.InstanceType = "Standard_NC24ads_A100_v4"
+		is.Spec.Template.Inference.Preset = &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: kaitov1beta1.ModelName(preset)}}
+		return is
+	}
+
+	setInferenceSetCounts([]kaitov1beta1.InferenceSet{
+		newInferenceSet("a", "phi-4"),
+		newInferenceSet("b", "phi-4"),
+		newInferenceSet("c", "qwen2.5-coder-7b-instruct"),
+	})
+	assert.Equal(t, 2, testutil.CollectAndCount(inferencesetPhaseCount))
+	assert.Equal(t, float64(2), testutil.ToFloat64(inferencesetPhaseCount.WithLabelValues("pending", "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4")))
+
+	setInferenceSetCounts(nil)
+	assert.Equal(t, 0, testutil.CollectAndCount(inferencesetPhaseCount))
+}
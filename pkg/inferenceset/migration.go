@@ -0,0 +1,194 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/inferenceset"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers"
+)
+
+// isOutdatedInstanceType returns true if the workspace runs on an instance type other
+// than the one in the InferenceSet template. BYO workspaces have no instance type and
+// are never outdated.
+func isOutdatedInstanceType(iObj *kaitov1beta1.InferenceSet, ws *kaitov1beta1.Workspace) bool {
+	if consts.ActiveNodeProvisioner == consts.NodeProvisionerBYO {
+		return false
+	}
+	target := iObj.Spec.Template.Resource.InstanceType
+	return target != "" && ws.Resource.InstanceType != "" && ws.Resource.InstanceType != target
+}
+
+// migrateInstanceType rolls the workspaces of the InferenceSet over to the instance type
+// of its template, one replica at a time, without dropping below the desired number of
+// ready replicas:
+//
+//  1. A surge workspace of the new instance type is created; it provisions its own nodes.
+//  2. Once every workspace of the new instance type is ready, one outdated workspace is
+//     deleted, which releases its nodes.
+//
+// Outdated workspaces that are not ready are replaced right away, since they serve no
+// traffic. Progress is reported in the Migration condition. It returns true while it
+// manages the replica count, in which case the regular scaling must be skipped.
+func (c *InferenceSetReconciler) migrateInstanceType(ctx context.Context, iObj *kaitov1beta1.InferenceSet, wsList *kaitov1beta1.WorkspaceList, desiredReplicas int32) (bool, error) {
+	var active, outdated, outdatedNotReady []*kaitov1beta1.Workspace
+	deleting, currentReady := 0, 0
+	for i := range wsList.Items {
+		ws := &wsList.Items[i]
+		if !ws.DeletionTimestamp.IsZero() {
+			deleting++
+			continue
+		}
+		active = append(active, ws)
+		ready := controllers.DetermineWorkspacePhase(ws) == "succeeded"
+		switch {
+		case !isOutdatedInstanceType(iObj, ws):
+			if ready {
+				currentReady++
+			}
+		case ready:
+			outdated = append(outdated, ws)
+		default:
+			outdated = append(outdated, ws)
+			outdatedNotReady = append(outdatedNotReady, ws)
+		}
+	}
+
+	if len(outdated) == 0 {
+		if cond := meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeMigration)); cond != nil && cond.Status == metav1.ConditionTrue {
+			if err := inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeMigration, metav1.ConditionFalse,
+				"MigrationCompleted", fmt.Sprintf("all replicas run on instance type %s", iObj.Spec.Template.Resource.InstanceType)); err != nil {
+				return false, err
+			}
+			klog.InfoS("instance type migration completed", "inferenceset", klog.KObj(iObj), "instanceType", iObj.Spec.Template.Resource.InstanceType)
+		}
+		return false, nil
+	}
+
+	desired := int(desiredReplicas)
+	if len(active) < desired || len(active) > desired+1 {
+		// Scaling takes precedence; scale-down removes outdated workspaces first.
+		return false, nil
+	}
+
+	// Replacing a ready replica waits for the maintenance window. Replicas that are not ready
+	// serve no traffic and are replaced right away, and a surge replica created in the window
+	// runs to completion.
+	if len(active) == desired && len(outdatedNotReady) == 0 && deleting == 0 {
+		if now := time.Now(); !iObj.Spec.Template.MaintenanceWindow.IsOpen(now) {
+			return true, c.deferMigration(ctx, iObj, outdated, now)
+		}
+	}
+	for _, ws := range outdated {
+		if err := workspace.RemovePendingOperation(ctx, c.Client, ws, kaitov1beta1.PendingOperationInstanceTypeMigration); err != nil {
+			return true, err
+		}
+	}
+
+	if err := inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeMigration, metav1.ConditionTrue,
+		"MigrationInProgress", fmt.Sprintf("migrating to instance type %s, %d/%d replicas migrated",
+			iObj.Spec.Template.Resource.InstanceType, desired-min(len(outdated), desired), desired)); err != nil {
+		return true, err
+	}
+
+	// Wait for earlier deletions to finish releasing their nodes.
+	if deleting > 0 {
+		return true, nil
+	}
+
+	isKey := client.ObjectKeyFromObject(iObj).String()
+	var victim *kaitov1beta1.Workspace
+	switch {
+	case len(outdatedNotReady) > 0 && len(active) == desired:
+		victim = outdatedNotReady[0]
+	case len(active) == desired:
+		// Surge a replica of the new instance type before removing an outdated one.
+		if err := c.expectations.ExpectCreations(c.klogger, isKey, 1); err != nil {
+			return true, err
+		}
+		workspaceObj := generateWorkspace(iObj)
+		klog.InfoS("creating workspace to migrate instance type", "inferenceset", klog.KObj(iObj), "instanceType", workspaceObj.Resource.InstanceType)
+		if err := c.Client.Create(ctx, workspaceObj); err != nil {
+			c.expectations.CreationObserved(c.klogger, isKey)
+			return true, err
+		}
+		return true, nil
+	case currentReady == len(active)-len(outdated):
+		// The surge replica is ready; retire an outdated one, preferring one that is not ready.
+		victim = outdated[0]
+		if len(outdatedNotReady) > 0 {
+			victim = outdatedNotReady[0]
+		}
+	default:
+		return true, nil
+	}
+
+	if err := c.expectations.ExpectDeletions(c.klogger, isKey, 1); err != nil {
+		return true, err
+	}
+	klog.InfoS("deleting workspace to migrate instance type", "workspace", klog.KObj(victim), "instanceType", victim.Resource.InstanceType)
+	if err := c.Client.Delete(ctx, victim, &client.DeleteOptions{}); err != nil {
+		c.expectations.DeletionObserved(c.klogger, isKey)
+		if !apierrors.IsNotFound(err) {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// deferMigration records the replacement of the outdated workspaces as pending until the
+// maintenance window of the InferenceSet opens.
+func (c *InferenceSetReconciler) deferMigration(ctx context.Context, iObj *kaitov1beta1.InferenceSet, outdated []*kaitov1beta1.Workspace, now time.Time) error {
+	instanceType := iObj.Spec.Template.Resource.InstanceType
+	nextWindow := metav1.NewTime(iObj.Spec.Template.MaintenanceWindow.NextOpen(now))
+	for _, ws := range outdated {
+		if err := workspace.SetPendingOperation(ctx, c.Client, ws, kaitov1beta1.PendingOperation{
+			Type:       kaitov1beta1.PendingOperationInstanceTypeMigration,
+			Message:    fmt.Sprintf("replacement by a workspace of instance type %s", instanceType),
+			NextWindow: &nextWindow,
+		}); err != nil {
+			return err
+		}
+	}
+	return inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeMigration, metav1.ConditionTrue,
+		"MigrationDeferred", fmt.Sprintf("migration to instance type %s is deferred to the maintenance window opening at %s",
+			instanceType, nextWindow.UTC().Format(time.RFC3339)))
+}
+
+// migrationResumeAfter returns how long until a migration deferred to the maintenance window
+// of iObj may resume, or 0 if no migration is deferred.
+func migrationResumeAfter(iObj *kaitov1beta1.InferenceSet, wsList *kaitov1beta1.WorkspaceList, now time.Time) time.Duration {
+	window := iObj.Spec.Template.MaintenanceWindow
+	if window.IsOpen(now) {
+		return 0
+	}
+	for i := range wsList.Items {
+		if isOutdatedInstanceType(iObj, &wsList.Items[i]) {
+			return window.NextOpen(now).Sub(now)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/inferenceset"
+)
+
+func migrationWorkspace(name, instanceType string, ready bool) *kaitov1beta1.Workspace {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: "phi"},
+		},
+		Resource: kaitov1beta1.ResourceSpec{InstanceType: instanceType},
+		Status: kaitov1beta1.WorkspaceStatus{Conditions: []metav1.Condition{{
+			Type:   string(kaitov1beta1.WorkspaceConditionTypeSucceeded),
+			Status: status,
+			Reason: "test",
+		}}},
+	}
+}
+
+func TestMigrateInstanceType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec: kaitov1beta1.InferenceSetSpec{
+			Replicas: ptr.To[int32](2),
+			Template: kaitov1beta1.InferenceSetTemplate{Resource: kaitov1beta1.InferenceSetResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"}},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&kaitov1beta1.InferenceSet{}).
+		WithObjects(iObj,
+			migrationWorkspace("phi-a", "Standard_NC6s_v3", true),
+			migrationWorkspace("phi-b", "Standard_NC6s_v3", true)).
+		Build()
+	c := &InferenceSetReconciler{Client: cl, klogger: klog.Background(), expectations: utils.NewControllerExpectations()}
+	ctx := context.Background()
+	isKey := client.ObjectKeyFromObject(iObj).String()
+
+	step := func() (bool, *kaitov1beta1.WorkspaceList) {
+		t.Helper()
+		c.expectations.DeleteExpectations(c.klogger, isKey)
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), iObj))
+		wsList, err := inferenceset.ListWorkspaces(ctx, iObj, cl)
+		require.NoError(t, err)
+		migrating, err := c.migrateInstanceType(ctx, iObj, wsList, *iObj.Spec.Replicas)
+		require.NoError(t, err)
+		wsList, err = inferenceset.ListWorkspaces(ctx, iObj, cl)
+		require.NoError(t, err)
+		return migrating, wsList
+	}
+	countByType := func(wsList *kaitov1beta1.WorkspaceList) map[string]int {
+		counts := map[string]int{}
+		for _, ws := range wsList.Items {
+			counts[ws.Resource.InstanceType]++
+		}
+		return counts
+	}
+	markReady := func(wsList *kaitov1beta1.WorkspaceList) {
+		for i := range wsList.Items {
+			ws := &wsList.Items[i]
+			if meta.IsStatusConditionTrue(ws.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeSucceeded)) {
+				continue
+			}
+			meta.SetStatusCondition(&ws.Status.Conditions, metav1.Condition{
+				Type: string(kaitov1beta1.WorkspaceConditionTypeSucceeded), Status: metav1.ConditionTrue, Reason: "test",
+			})
+			require.NoError(t, cl.Update(ctx, ws))
+		}
+	}
+
+	// A surge replica of the new instance type is created first.
+	migrating, wsList := step()
+	assert.True(t, migrating)
+	assert.Equal(t, map[string]int{"Standard_NC6s_v3": 2, "Standard_NC24ads_A100_v4": 1}, countByType(wsList))
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), iObj))
+	cond := meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeMigration))
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	// Nothing is removed until the surge replica is ready.
+	migrating, wsList = step()
+	assert.True(t, migrating)
+	assert.Len(t, wsList.Items, 3)
+
+	markReady(wsList)
+	migrating, wsList = step()
+	assert.True(t, migrating)
+	assert.Equal(t, map[string]int{"Standard_NC6s_v3": 1, "Standard_NC24ads_A100_v4": 1}, countByType(wsList))
+
+	// Second round: surge, become ready, retire the last outdated replica.
+	_, wsList = step()
+	markReady(wsList)
+	_, wsList = step()
+	assert.Equal(t, map[string]int{"Standard_NC24ads_A100_v4": 2}, countByType(wsList))
+
+	migrating, _ = step()
+	assert.False(t, migrating)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), iObj))
+	cond = meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeMigration))
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "MigrationCompleted", cond.Reason)
+}
+
+func TestMigrateInstanceTypeReplacesNotReadyReplica(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec: kaitov1beta1.InferenceSetSpec{
+			Replicas: ptr.To[int32](1),
+			Template: kaitov1beta1.InferenceSetTemplate{Resource: kaitov1beta1.InferenceSetResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"}},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&kaitov1beta1.InferenceSet{}).
+		WithObjects(iObj, migrationWorkspace("phi-a", "Standard_NC6s_v3", false)).
+		Build()
+	c := &InferenceSetReconciler{Client: cl, klogger: klog.Background(), expectations: utils.NewControllerExpectations()}
+	ctx := context.Background()
+
+	wsList, err := inferenceset.ListWorkspaces(ctx, iObj, cl)
+	require.NoError(t, err)
+	migrating, err := c.migrateInstanceType(ctx, iObj, wsList, 1)
+	require.NoError(t, err)
+	assert.True(t, migrating)
+
+	// The replica serves no traffic, so it is deleted without a surge; regular scaling recreates it.
+	wsList, err = inferenceset.ListWorkspaces(ctx, iObj, cl)
+	require.NoError(t, err)
+	assert.Empty(t, wsList.Items)
+	migrating, err = c.migrateInstanceType(ctx, iObj, wsList, 1)
+	require.NoError(t, err)
+	assert.False(t, migrating)
+}
+
+func TestMigrateInstanceTypeDefersToMaintenanceWindow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	// A window that opens tomorrow is closed for the whole of today.
+	now := time.Now().UTC()
+	window := &kaitov1beta1.WorkspaceMaintenanceWindow{
+		Days:      []kaitov1beta1.MaintenanceDay{kaitov1beta1.MaintenanceDay(now.AddDate(0, 0, 1).Weekday().String())},
+		StartTime: "00:00",
+		EndTime:   "00:01",
+		TimeZone:  "UTC",
+	}
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec: kaitov1beta1.InferenceSetSpec{
+			Replicas: ptr.To[int32](1),
+			Template: kaitov1beta1.InferenceSetTemplate{
+				Resource:          kaitov1beta1.InferenceSetResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+				MaintenanceWindow: window,
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&kaitov1beta1.InferenceSet{}, &kaitov1beta1.Workspace{}).
+		WithObjects(iObj, migrationWorkspace("phi-a", "Standard_NC6s_v3", true)).
+		Build()
+	c := &InferenceSetReconciler{Client: cl, klogger: klog.Background(), expectations: utils.NewControllerExpectations()}
+	ctx := context.Background()
+
+	wsList, err := inferenceset.ListWorkspaces(ctx, iObj, cl)
+	require.NoError(t, err)
+	migrating, err := c.migrateInstanceType(ctx, iObj, wsList, *iObj.Spec.Replicas)
+	require.NoError(t, err)
+	assert.True(t, migrating)
+
+	// No surge replica is created and the replacement is recorded as pending.
+	wsList, err = inferenceset.ListWorkspaces(ctx, iObj, cl)
+	require.NoError(t, err)
+	require.Len(t, wsList.Items, 1)
+	require.Len(t, wsList.Items[0].Status.PendingOperations, 1)
+	assert.Equal(t, kaitov1beta1.PendingOperationInstanceTypeMigration, wsList.Items[0].Status.PendingOperations[0].Type)
+
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), iObj))
+	cond := meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeMigration))
+	require.NotNil(t, cond)
+	assert.Equal(t, "MigrationDeferred", cond.Reason)
+	assert.Positive(t, migrationResumeAfter(iObj, wsList, now))
+
+	// Once the window opens, the migration proceeds and the pending operation is cleared.
+	iObj.Spec.Template.MaintenanceWindow = nil
+	migrating, err = c.migrateInstanceType(ctx, iObj, wsList, *iObj.Spec.Replicas)
+	require.NoError(t, err)
+	assert.True(t, migrating)
+	wsList, err = inferenceset.ListWorkspaces(ctx, iObj, cl)
+	require.NoError(t, err)
+	assert.Len(t, wsList.Items, 2)
+	for _, ws := range wsList.Items {
+		assert.Empty(t, ws.Status.PendingOperations)
+	}
+}
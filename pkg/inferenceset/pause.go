@@ -0,0 +1,44 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/inferenceset"
+)
+
+// syncPause reports whether the reconciliation of iObj is paused by the kaito.sh/reconcile
+// annotation. It keeps the Paused condition up to date and records an event when the
+// pause starts or ends.
+func (c *InferenceSetReconciler) syncPause(ctx context.Context, iObj *kaitov1beta1.InferenceSet) (bool, error) {
+	paused := kaitov1beta1.ReconcilePaused(iObj)
+	reason, message := kaitov1beta1.PauseTransition(iObj.Status.Conditions, iObj)
+	if !paused && reason == "" {
+		return false, nil
+	}
+	if reason != "" && c.Recorder != nil {
+		c.Recorder.Event(iObj, corev1.EventTypeNormal, reason, message)
+	}
+	key := client.ObjectKeyFromObject(iObj)
+	err := inferenceset.UpdateInferenceSetStatus(ctx, c.Client, &key, func(status *kaitov1beta1.InferenceSetStatus) error {
+		kaitov1beta1.ApplyPausedCondition(&status.Conditions, iObj)
+		return nil
+	})
+	return paused, err
+}
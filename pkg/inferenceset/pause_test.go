@@ -0,0 +1,64 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestSyncPause(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "phi", Namespace: "default", Generation: 5,
+		Annotations: map[string]string{kaitov1beta1.AnnotationReconcile: kaitov1beta1.ReconcileDisabled},
+	}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(iObj).WithStatusSubresource(&kaitov1beta1.InferenceSet{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	c := &InferenceSetReconciler{Client: cl, Recorder: recorder}
+	ctx := context.Background()
+	get := func() *kaitov1beta1.InferenceSet {
+		got := &kaitov1beta1.InferenceSet{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(iObj), got))
+		return got
+	}
+
+	paused, err := c.syncPause(ctx, iObj)
+	require.NoError(t, err)
+	assert.True(t, paused)
+	cond := meta.FindStatusCondition(get().Status.Conditions, string(kaitov1beta1.ConditionTypePaused))
+	require.NotNil(t, cond)
+	assert.Equal(t, int64(5), cond.ObservedGeneration)
+	assert.Contains(t, <-recorder.Events, "ReconcilePaused")
+
+	resumed := get()
+	resumed.Annotations = nil
+	paused, err = c.syncPause(ctx, resumed)
+	require.NoError(t, err)
+	assert.False(t, paused)
+	assert.Nil(t, meta.FindStatusCondition(get().Status.Conditions, string(kaitov1beta1.ConditionTypePaused)))
+	assert.Contains(t, <-recorder.Events, "ReconcileResumed")
+}
@@ -0,0 +1,79 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// requestTimeout returns inference.service.timeouts.request of the InferenceSet template.
+func requestTimeout(iObj *kaitov1beta1.InferenceSet) *string {
+	inference := iObj.Spec.Template.Inference
+	if inference.Service == nil || inference.Service.Timeouts == nil || inference.Service.Timeouts.Request == nil {
+		return nil
+	}
+	timeout := manifests.GatewayDuration(inference.Service.Timeouts.Request.Duration)
+	return &timeout
+}
+
+// ensureRouteTimeouts sets inference.service.timeouts.request of the template on the HTTPRoute
+// rules in the namespace of iObj that send requests to its InferencePool, so that the Gateway
+// does not cut long streaming responses. The timeouts are left alone when the field is unset.
+func (c *InferenceSetReconciler) ensureRouteTimeouts(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	if !featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+		return nil
+	}
+	timeout := requestTimeout(iObj)
+	if timeout == nil {
+		return nil
+	}
+
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(manifests.HTTPRouteGVK.GroupVersion().WithKind(manifests.HTTPRouteGVK.Kind + "List"))
+	if err := c.List(ctx, routes, client.InNamespace(iObj.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			klog.V(4).InfoS("Gateway API is not installed, skipping HTTPRoute timeouts", "inferenceset", klog.KObj(iObj))
+			return nil
+		}
+		return fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		_, changed, err := manifests.SetRequestTimeout(route, utils.InferencePoolName(iObj.Name), *timeout)
+		if err != nil {
+			return fmt.Errorf("failed to set request timeout on HTTPRoute %s: %w", route.GetName(), err)
+		}
+		if !changed {
+			continue
+		}
+		klog.InfoS("Updating HTTPRoute request timeout", "inferenceset", klog.KObj(iObj), "httproute", route.GetName(), "timeout", *timeout)
+		if err := c.Update(ctx, route); err != nil {
+			return fmt.Errorf("failed to update HTTPRoute %s: %w", route.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestEnsureRouteTimeouts(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension]
+	featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = true
+	t.Cleanup(func() { featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = original })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(manifests.HTTPRouteGVK, meta.RESTScopeNamespace)
+
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default"}}
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+		"spec": map[string]any{"rules": []any{map[string]any{
+			"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "phi-inferencepool"}},
+		}}},
+	}}
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(route).Build()}
+	ctx := context.Background()
+
+	requestTimeoutOf := func() string {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(manifests.HTTPRouteGVK)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(route), current))
+		rules, _, _ := unstructured.NestedSlice(current.Object, "spec", "rules")
+		timeout, _, _ := unstructured.NestedString(rules[0].(map[string]any), "timeouts", "request")
+		return timeout
+	}
+
+	// Without timeouts.request the route is left alone.
+	require.NoError(t, c.ensureRouteTimeouts(ctx, iObj))
+	assert.Empty(t, requestTimeoutOf())
+
+	iObj.Spec.Template.Inference.Service = &kaitov1beta1.EndpointServiceSpec{
+		Timeouts: &kaitov1beta1.ServiceTimeoutsSpec{Request: &metav1.Duration{Duration: 15 * time.Minute}},
+	}
+	require.NoError(t, c.ensureRouteTimeouts(ctx, iObj))
+	assert.Equal(t, "15m", requestTimeoutOf())
+
+	iObj.Spec.Template.Inference.Service.Timeouts.Request = &metav1.Duration{}
+	require.NoError(t, c.ensureRouteTimeouts(ctx, iObj))
+	assert.Equal(t, "0s", requestTimeoutOf())
+}
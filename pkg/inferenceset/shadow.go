@@ -0,0 +1,180 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/inferenceset"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// shadowResyncPeriod is how often an InferenceSet with spec.shadowTo is requeued. HTTPRoutes
+// are not watched, so a route created after the InferenceSet picks up the mirror on resync.
+const shadowResyncPeriod = time.Minute
+
+// errGatewayAPINotInstalled is returned by syncRequestMirrors when the HTTPRoute CRD is absent.
+var errGatewayAPINotInstalled = fmt.Errorf("Gateway API is not installed, HTTPRoute CRD %s not found", manifests.HTTPRouteGVK.GroupKind())
+
+// ensureShadow reconciles spec.shadowTo of an InferenceSet. The requests the Gateway sends to
+// the InferencePool of the InferenceSet are mirrored to a Service over the candidate
+// InferenceSet by a RequestMirror filter on the HTTPRoutes that reference the pool. The
+// Gateway discards the responses of mirrored requests, so clients only ever see the
+// responses of the InferenceSet itself. When shadowTo is removed, the filters and the
+// Service are removed as well.
+func (c *InferenceSetReconciler) ensureShadow(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	if !featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+		return nil
+	}
+	if iObj.Spec.ShadowTo == nil {
+		return c.removeShadow(ctx, iObj)
+	}
+	shadow := iObj.Spec.ShadowTo
+
+	candidate := &kaitov1beta1.InferenceSet{}
+	if err := c.Get(ctx, client.ObjectKey{Name: shadow.InferenceSet, Namespace: iObj.Namespace}, candidate); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := c.syncRequestMirrors(ctx, iObj, 0); err != nil && !errors.Is(err, errGatewayAPINotInstalled) {
+			return err
+		}
+		return inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeShadowing, metav1.ConditionFalse,
+			"CandidateNotFound", fmt.Sprintf("candidate InferenceSet %s not found", shadow.InferenceSet))
+	}
+
+	if err := c.ensureShadowService(ctx, iObj); err != nil {
+		return err
+	}
+
+	routes, err := c.syncRequestMirrors(ctx, iObj, shadow.Percent)
+	switch {
+	case errors.Is(err, errGatewayAPINotInstalled):
+		return inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeShadowing, metav1.ConditionFalse,
+			"GatewayAPINotInstalled", err.Error())
+	case err != nil:
+		return err
+	case len(routes) == 0:
+		return inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeShadowing, metav1.ConditionFalse,
+			"NoHTTPRoute", fmt.Sprintf("no HTTPRoute sends requests to InferencePool %s", utils.InferencePoolName(iObj.Name)))
+	}
+	return inferenceset.UpdateStatusConditionIfNotMatch(ctx, c.Client, iObj, kaitov1beta1.InferenceSetConditionTypeShadowing, metav1.ConditionTrue,
+		"Mirroring", fmt.Sprintf("mirroring %d%% of requests to InferenceSet %s through HTTPRoutes %s",
+			shadow.Percent, shadow.InferenceSet, strings.Join(routes, ", ")))
+}
+
+// ensureShadowService creates or updates the Service that receives the mirrored requests.
+func (c *InferenceSetReconciler) ensureShadowService(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	desired := manifests.GenerateShadowServiceManifest(iObj)
+	existing := &corev1.Service{}
+	err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		klog.InfoS("Creating shadow Service", "inferenceset", klog.KObj(iObj), "service", desired.Name)
+		return c.Create(ctx, desired)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get Service %s: %w", desired.Name, err)
+	}
+	if !metav1.IsControlledBy(existing, iObj) {
+		return fmt.Errorf("Service %s already exists and is not managed by InferenceSet %s", existing.Name, iObj.Name)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) &&
+		apiequality.Semantic.DeepEqual(existing.Spec.Ports, desired.Spec.Ports) {
+		return nil
+	}
+	existing.Spec.Selector = desired.Spec.Selector
+	existing.Spec.Ports = desired.Spec.Ports
+	klog.InfoS("Updating shadow Service", "inferenceset", klog.KObj(iObj), "service", existing.Name)
+	return c.Update(ctx, existing)
+}
+
+// removeShadow removes the RequestMirror filters, the shadow Service and the Shadowing
+// condition of an InferenceSet without spec.shadowTo.
+func (c *InferenceSetReconciler) removeShadow(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	if meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeShadowing)) == nil {
+		return nil
+	}
+	if _, err := c.syncRequestMirrors(ctx, iObj, 0); err != nil && !errors.Is(err, errGatewayAPINotInstalled) {
+		return err
+	}
+
+	existing := &corev1.Service{}
+	err := c.Get(ctx, client.ObjectKey{Name: manifests.ShadowServiceName(iObj), Namespace: iObj.Namespace}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Service %s: %w", manifests.ShadowServiceName(iObj), err)
+	}
+	if err == nil && metav1.IsControlledBy(existing, iObj) {
+		klog.InfoS("Deleting shadow Service", "inferenceset", klog.KObj(iObj), "service", existing.Name)
+		if err := client.IgnoreNotFound(c.Delete(ctx, existing)); err != nil {
+			return err
+		}
+	}
+
+	return inferenceset.UpdateInferenceSetStatus(ctx, c.Client, &client.ObjectKey{Name: iObj.Name, Namespace: iObj.Namespace},
+		func(status *kaitov1beta1.InferenceSetStatus) error {
+			meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeShadowing))
+			return nil
+		})
+}
+
+// syncRequestMirrors sets the RequestMirror filter to the shadow Service on the HTTPRoutes in
+// the namespace of iObj that send requests to its InferencePool, and returns the names of
+// those routes. A percent of 0 removes the filter.
+func (c *InferenceSetReconciler) syncRequestMirrors(ctx context.Context, iObj *kaitov1beta1.InferenceSet, percent int32) ([]string, error) {
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(manifests.HTTPRouteGVK.GroupVersion().WithKind(manifests.HTTPRouteGVK.Kind + "List"))
+	if err := c.List(ctx, routes, client.InNamespace(iObj.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, errGatewayAPINotInstalled
+		}
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	var names []string
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		matched, changed, err := manifests.SetRequestMirror(route, utils.InferencePoolName(iObj.Name), manifests.ShadowServiceName(iObj), percent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set request mirror on HTTPRoute %s: %w", route.GetName(), err)
+		}
+		if matched > 0 {
+			names = append(names, route.GetName())
+		}
+		if !changed {
+			continue
+		}
+		klog.InfoS("Updating HTTPRoute request mirror", "inferenceset", klog.KObj(iObj), "httproute", route.GetName(), "percent", percent)
+		if err := c.Update(ctx, route); err != nil {
+			return nil, fmt.Errorf("failed to update HTTPRoute %s: %w", route.GetName(), err)
+		}
+	}
+	return names, nil
+}
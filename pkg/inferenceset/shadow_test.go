@@ -0,0 +1,146 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestEnsureShadow(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension]
+	featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = true
+	t.Cleanup(func() { featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = original })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(kaitov1beta1.GroupVersion.WithKind("InferenceSet"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(manifests.HTTPRouteGVK, meta.RESTScopeNamespace)
+
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec:       kaitov1beta1.InferenceSetSpec{ShadowTo: &kaitov1beta1.ShadowSpec{InferenceSet: "phi-candidate", Percent: 10}},
+	}
+	candidate := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi-candidate", Namespace: "default"}}
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+		"spec": map[string]any{"rules": []any{map[string]any{
+			"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "phi-inferencepool"}},
+		}}},
+	}}
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithStatusSubresource(&kaitov1beta1.InferenceSet{}).WithObjects(iObj, route).Build()}
+	ctx := context.Background()
+
+	shadowing := func() *metav1.Condition {
+		current := &kaitov1beta1.InferenceSet{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(iObj), current))
+		iObj.Status = current.Status
+		return meta.FindStatusCondition(current.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeShadowing))
+	}
+	mirrorPercent := func() int64 {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(manifests.HTTPRouteGVK)
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(route), current))
+		rules, _, _ := unstructured.NestedSlice(current.Object, "spec", "rules")
+		filters, _, _ := unstructured.NestedSlice(rules[0].(map[string]any), "filters")
+		for _, f := range filters {
+			percent, _, _ := unstructured.NestedInt64(f.(map[string]any), "requestMirror", "percent")
+			return percent
+		}
+		return 0
+	}
+
+	// The candidate does not exist yet.
+	require.NoError(t, c.ensureShadow(ctx, iObj))
+	if cond := shadowing(); assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, "CandidateNotFound", cond.Reason)
+	}
+	assert.Zero(t, mirrorPercent())
+
+	require.NoError(t, c.Create(ctx, candidate))
+	require.NoError(t, c.ensureShadow(ctx, iObj))
+	if cond := shadowing(); assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Contains(t, cond.Message, "mirroring 10% of requests to InferenceSet phi-candidate through HTTPRoutes llm")
+	}
+	assert.Equal(t, int64(10), mirrorPercent())
+	svc := &corev1.Service{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "phi-shadow", Namespace: "default"}, svc))
+	assert.Equal(t, "phi-candidate", svc.Spec.Selector[consts.WorkspaceCreatedByInferenceSetLabel])
+
+	iObj.Spec.ShadowTo = nil
+	require.NoError(t, c.ensureShadow(ctx, iObj))
+	assert.Nil(t, shadowing())
+	assert.Zero(t, mirrorPercent())
+	err := c.Get(ctx, client.ObjectKey{Name: "phi-shadow", Namespace: "default"}, &corev1.Service{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestEnsureShadowWithoutGatewayAPI(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension]
+	featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = true
+	t.Cleanup(func() { featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = original })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec:       kaitov1beta1.InferenceSetSpec{ShadowTo: &kaitov1beta1.ShadowSpec{InferenceSet: "phi-candidate", Percent: 10}},
+	}
+	candidate := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi-candidate", Namespace: "default"}}
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&kaitov1beta1.InferenceSet{}).WithObjects(iObj, candidate).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*unstructured.UnstructuredList); ok {
+					return &meta.NoKindMatchError{GroupKind: manifests.HTTPRouteGVK.GroupKind()}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()}
+	ctx := context.Background()
+
+	require.NoError(t, c.ensureShadow(ctx, iObj))
+	current := &kaitov1beta1.InferenceSet{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(iObj), current))
+	if cond := meta.FindStatusCondition(current.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeShadowing)); assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, "GatewayAPINotInstalled", cond.Reason)
+	}
+}
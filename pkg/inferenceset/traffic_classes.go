@@ -0,0 +1,95 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gaiev1alpha2 "sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// ensureTrafficClasses reconciles spec.trafficClasses of an InferenceSet into one
+// InferenceObjective per traffic class. The priority lanes themselves are enabled in the EPP
+// by the values of the InferencePool HelmRelease. When trafficClasses is removed, the
+// InferenceObjectives are removed as well.
+func (c *InferenceSetReconciler) ensureTrafficClasses(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	if !featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+		return nil
+	}
+	if iObj.Spec.TrafficClasses == nil {
+		return c.removeTrafficClasses(ctx, iObj)
+	}
+
+	for _, desired := range manifests.GenerateTrafficClassObjectives(iObj) {
+		existing := &gaiev1alpha2.InferenceObjective{}
+		err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+		if apierrors.IsNotFound(err) {
+			klog.InfoS("Creating InferenceObjective", "inferenceset", klog.KObj(iObj), "inferenceobjective", desired.Name)
+			if err := c.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create InferenceObjective %s: %w", desired.Name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get InferenceObjective %s: %w", desired.Name, err)
+		}
+		if !metav1.IsControlledBy(existing, iObj) {
+			return fmt.Errorf("InferenceObjective %s already exists and is not managed by InferenceSet %s", existing.Name, iObj.Name)
+		}
+		if apiequality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+			continue
+		}
+		existing.Spec = desired.Spec
+		klog.InfoS("Updating InferenceObjective", "inferenceset", klog.KObj(iObj), "inferenceobjective", existing.Name)
+		if err := c.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update InferenceObjective %s: %w", existing.Name, err)
+		}
+	}
+	return nil
+}
+
+// removeTrafficClasses deletes the InferenceObjectives of an InferenceSet without
+// spec.trafficClasses.
+func (c *InferenceSetReconciler) removeTrafficClasses(ctx context.Context, iObj *kaitov1beta1.InferenceSet) error {
+	for _, class := range []string{manifests.TrafficClassInteractive, manifests.TrafficClassBatch} {
+		name := manifests.InferenceObjectiveName(iObj, class)
+		existing := &gaiev1alpha2.InferenceObjective{}
+		err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: iObj.Namespace}, existing)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get InferenceObjective %s: %w", name, err)
+		}
+		if !metav1.IsControlledBy(existing, iObj) {
+			continue
+		}
+		klog.InfoS("Deleting InferenceObjective", "inferenceset", klog.KObj(iObj), "inferenceobjective", name)
+		if err := client.IgnoreNotFound(c.Delete(ctx, existing)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gaiev1alpha2 "sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestEnsureTrafficClasses(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension]
+	featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = true
+	t.Cleanup(func() { featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = original })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, gaiev1alpha2.Install(scheme))
+
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec:       kaitov1beta1.InferenceSetSpec{TrafficClasses: &kaitov1beta1.TrafficClassesSpec{}},
+	}
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(iObj).Build()}
+	ctx := context.Background()
+
+	priority := func(name string) *int {
+		o := &gaiev1alpha2.InferenceObjective{}
+		err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, o)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return o.Spec.Priority
+	}
+
+	// Both objectives are created with the default priorities.
+	require.NoError(t, c.ensureTrafficClasses(ctx, iObj))
+	require.NotNil(t, priority("phi-interactive"))
+	assert.Equal(t, 10, *priority("phi-interactive"))
+	require.NotNil(t, priority("phi-batch"))
+	assert.Equal(t, -10, *priority("phi-batch"))
+
+	// A changed priority is rolled out to the existing objective.
+	iObj.Spec.TrafficClasses.Batch = &kaitov1beta1.TrafficClass{Priority: -100}
+	require.NoError(t, c.ensureTrafficClasses(ctx, iObj))
+	assert.Equal(t, -100, *priority("phi-batch"))
+
+	// Removing trafficClasses removes the objectives.
+	iObj.Spec.TrafficClasses = nil
+	require.NoError(t, c.ensureTrafficClasses(ctx, iObj))
+	assert.Nil(t, priority("phi-interactive"))
+	assert.Nil(t, priority("phi-batch"))
+}
+
+func TestEnsureTrafficClassesNotManaged(t *testing.T) {
+	original := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension]
+	featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = true
+	t.Cleanup(func() { featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] = original })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	require.NoError(t, gaiev1alpha2.Install(scheme))
+
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec:       kaitov1beta1.InferenceSetSpec{TrafficClasses: &kaitov1beta1.TrafficClassesSpec{}},
+	}
+	userObjective := &gaiev1alpha2.InferenceObjective{ObjectMeta: metav1.ObjectMeta{Name: "phi-interactive", Namespace: "default"}}
+	c := &InferenceSetReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(iObj, userObjective).Build()}
+	ctx := context.Background()
+
+	err := c.ensureTrafficClasses(ctx, iObj)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not managed by InferenceSet phi")
+
+	// Objectives not owned by the InferenceSet are left alone on removal.
+	iObj.Spec.TrafficClasses = nil
+	require.NoError(t, c.ensureTrafficClasses(ctx, iObj))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(userObjective), &gaiev1alpha2.InferenceObjective{}))
+}
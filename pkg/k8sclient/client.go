@@ -11,29 +11,87 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package k8sclient carries the Kubernetes clients of a manager to the code that cannot
+// receive them as parameters, such as the webhook validation of the API types. Such code
+// reads the client from its context with FromContext, so that several managers can run in
+// one process. The deprecated global clients remain as a fallback for embedders that still
+// set them; the KAITO managers do not.
 package k8sclient
 
 import (
+	"context"
+
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+type clientKey struct{}
+
+type clientGoClientKey struct{}
+
+// WithClient returns a copy of ctx that carries c.
+func WithClient(ctx context.Context, c client.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromContext returns the client carried by ctx, or the global client if ctx carries none.
+func FromContext(ctx context.Context) client.Client {
+	if c, ok := ctx.Value(clientKey{}).(client.Client); ok && c != nil {
+		return c
+	}
+	return Client
+}
+
+// Decorator returns a function that carries the clients of ctx to another context. The
+// knative admission controllers apply it to the context of every request, which does not
+// derive from the context the controllers are constructed with.
+func Decorator(ctx context.Context) func(context.Context) context.Context {
+	c, clientGoClient := FromContext(ctx), ClientGoClientFromContext(ctx)
+	return func(reqCtx context.Context) context.Context {
+		return WithClientGoClient(WithClient(reqCtx, c), clientGoClient)
+	}
+}
+
+// WithClientGoClient returns a copy of ctx that carries c.
+func WithClientGoClient(ctx context.Context, c kubernetes.Interface) context.Context {
+	return context.WithValue(ctx, clientGoClientKey{}, c)
+}
+
+// ClientGoClientFromContext returns the client-go client carried by ctx, or the global
+// client-go client if ctx carries none.
+func ClientGoClientFromContext(ctx context.Context) kubernetes.Interface {
+	if c, ok := ctx.Value(clientGoClientKey{}).(kubernetes.Interface); ok && c != nil {
+		return c
+	}
+	return ClientGoClient
+}
+
+// Client is the client of the only manager of the process.
+//
+// Deprecated: inject the client, or carry it on the context with WithClient.
 var Client client.Client
 
+// Deprecated: inject the client, or carry it on the context with WithClient.
 func SetGlobalClient(c client.Client) {
 	Client = c
 }
 
+// Deprecated: inject the client, or read it from the context with FromContext.
 func GetGlobalClient() client.Client {
 	return Client
 }
 
+// ClientGoClient is the client-go client of the only manager of the process.
+//
+// Deprecated: inject the client, or carry it on the context with WithClientGoClient.
 var ClientGoClient kubernetes.Interface
 
+// Deprecated: inject the client, or carry it on the context with WithClientGoClient.
 func SetGlobalClientGoClient(c kubernetes.Interface) {
 	ClientGoClient = c
 }
 
+// Deprecated: inject the client, or read it from the context with ClientGoClientFromContext.
 func GetGlobalClientGoClient() kubernetes.Interface {
 	return ClientGoClient
 }
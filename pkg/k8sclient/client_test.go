@@ -0,0 +1,42 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFromContext(t *testing.T) {
+	global, injected := fake.NewClientBuilder().Build(), fake.NewClientBuilder().Build()
+	globalGo, injectedGo := kubefake.NewClientset(), kubefake.NewClientset()
+	SetGlobalClient(global)
+	SetGlobalClientGoClient(globalGo)
+	defer SetGlobalClient(nil)
+	defer SetGlobalClientGoClient(nil)
+
+	ctx := context.Background()
+	assert.Same(t, global, FromContext(ctx))
+	assert.Same(t, globalGo, ClientGoClientFromContext(ctx))
+
+	ctx = WithClientGoClient(WithClient(ctx, injected), injectedGo)
+	assert.Same(t, injected, FromContext(ctx))
+	assert.Same(t, injectedGo, ClientGoClientFromContext(ctx))
+
+	assert.Same(t, global, FromContext(WithClient(context.Background(), nil)))
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"maps"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -53,6 +54,8 @@ const (
 
 	DefaultTuningMainFile = "/workspace/tfs/fine_tuning.py"
 	ConfigfileNameVLLM    = "inference_config.yaml"
+	// ChatTemplateDir holds the chat templates shipped in the inference image.
+	ChatTemplateDir = "/workspace/chat_templates"
 
 	// PortRayCluster is the default port for communication between the head and worker nodes in a Ray cluster.
 	PortRayCluster = 6379
@@ -90,6 +93,11 @@ type Metadata struct {
 	// +optional
 	DownloadAuthRequired bool `yaml:"downloadAuthRequired,omitempty"`
 
+	// GatedLicense is the license that has to be accepted on HuggingFace before the
+	// model weights can be downloaded, e.g. "llama3.1". Empty for models that are not gated.
+	// +optional
+	GatedLicense string `yaml:"gatedLicense,omitempty"`
+
 	// Tag is the tag of the container image used to run the model.
 	// If the model uses the KAITO base image, the tag field can be ignored
 	// +optional
@@ -100,10 +108,29 @@ type Metadata struct {
 	// +optional
 	Registry string `yaml:"registry,omitempty"`
 
+	// Platforms lists the CPU architectures the container image is published for,
+	// e.g. [amd64, arm64] for a multi-arch image. If this is empty, the image is
+	// only published for amd64.
+	// +optional
+	Platforms []string `yaml:"platforms,omitempty"`
+
 	// Deprecated indicates if the model is deprecated.
 	// +optional
 	Deprecated bool `yaml:"deprecated,omitempty"`
 
+	// DeprecationDate is the date the model was deprecated, e.g. 2026-01-31.
+	// +optional
+	DeprecationDate string `yaml:"deprecationDate,omitempty"`
+
+	// EndOfLifeDate is the date from which new workspaces can no longer use a deprecated
+	// model, e.g. 2026-07-31. Existing workspaces keep running.
+	// +optional
+	EndOfLifeDate string `yaml:"endOfLifeDate,omitempty"`
+
+	// Replacement is the preset that replaces a deprecated model.
+	// +optional
+	Replacement string `yaml:"replacement,omitempty"`
+
 	// Architectures specifies the supported architectures for the model
 	// This field is only for best effort supported vLLM models.
 	// +optional
@@ -191,6 +218,9 @@ type RuntimeVersion struct {
 
 // Validate checks if the Metadata is valid.
 func (m *Metadata) Validate() error {
+	if err := m.validateLifecycle(); err != nil {
+		return fmt.Errorf("model %s: %w", m.Name, err)
+	}
 	// Some models requiring authentication may not have a version URL, so we allow it to be empty until
 	// we remove support for preset models requiring authentication.
 	if m.Version == "" {
@@ -201,6 +231,74 @@ func (m *Metadata) Validate() error {
 	return err
 }
 
+func (m *Metadata) validateLifecycle() error {
+	if !m.Deprecated && (m.DeprecationDate != "" || m.EndOfLifeDate != "" || m.Replacement != "") {
+		return fmt.Errorf("deprecationDate, endOfLifeDate and replacement require deprecated")
+	}
+	var deprecated, eol time.Time
+	var err error
+	if m.DeprecationDate != "" {
+		if deprecated, err = time.Parse(time.DateOnly, m.DeprecationDate); err != nil {
+			return fmt.Errorf("invalid deprecationDate: %w", err)
+		}
+	}
+	if m.EndOfLifeDate != "" {
+		if eol, err = time.Parse(time.DateOnly, m.EndOfLifeDate); err != nil {
+			return fmt.Errorf("invalid endOfLifeDate: %w", err)
+		}
+	}
+	if !deprecated.IsZero() && !eol.IsZero() && eol.Before(deprecated) {
+		return fmt.Errorf("endOfLifeDate %s is before deprecationDate %s", m.EndOfLifeDate, m.DeprecationDate)
+	}
+	return nil
+}
+
+// EndOfLife reports whether a deprecated model reached its end of life at now. The end of
+// life starts at midnight UTC of EndOfLifeDate.
+func (m *Metadata) EndOfLife(now time.Time) bool {
+	if !m.Deprecated || m.EndOfLifeDate == "" {
+		return false
+	}
+	eol, err := time.Parse(time.DateOnly, m.EndOfLifeDate)
+	return err == nil && !now.Before(eol)
+}
+
+// DeprecationMessage describes the deprecation of the model for the users of the preset
+// name, or returns an empty string if the model is not deprecated.
+func (m *Metadata) DeprecationMessage(name string, now time.Time) string {
+	if !m.Deprecated {
+		return ""
+	}
+	msg := fmt.Sprintf("preset %s is deprecated", name)
+	if m.DeprecationDate != "" {
+		msg += " since " + m.DeprecationDate
+	}
+	switch {
+	case m.EndOfLife(now):
+		msg += fmt.Sprintf(" and reached end of life on %s, new workspaces cannot use it", m.EndOfLifeDate)
+	case m.EndOfLifeDate != "":
+		msg += fmt.Sprintf(" and reaches end of life on %s", m.EndOfLifeDate)
+	}
+	if m.Replacement != "" {
+		msg += fmt.Sprintf(", migrate to %s", m.Replacement)
+	}
+	return msg
+}
+
+// ImagePlatforms returns the CPU architectures the container image is published for.
+func (m *Metadata) ImagePlatforms() []string {
+	if len(m.Platforms) == 0 {
+		return []string{consts.ArchitectureAMD64}
+	}
+	return m.Platforms
+}
+
+// SupportsArchitecture reports whether the container image is published for the given
+// CPU architecture, as in the kubernetes.io/arch node label.
+func (m *Metadata) SupportsArchitecture(arch string) bool {
+	return slices.Contains(m.ImagePlatforms(), arch)
+}
+
 // PresetParam defines the preset inference parameters for a model.
 type PresetParam struct {
 	Metadata
@@ -330,6 +428,22 @@ type RuntimeContextExtraArguments struct {
 	AdapterStrengthEnabled bool
 	PerformanceMode        string // vLLM --performance-mode; defaults to "balanced"
 
+	// Tool calling overrides; empty values keep the preset defaults.
+	ToolCallingDisabled bool
+	ToolCallParser      string // vLLM --tool-call-parser
+	ChatTemplate        string // file name in ChatTemplateDir
+
+	StructuredOutputsBackend string // vLLM --structured-outputs-config.backend
+
+	// OTLPTracesEndpoint enables OpenTelemetry tracing in vLLM. It is usually a shell
+	// reference to the OTEL_EXPORTER_OTLP_TRACES_ENDPOINT env var, which keeps the URL
+	// out of the shell command line.
+	OTLPTracesEndpoint string // vLLM --otlp-traces-endpoint
+
+	// ExtraArgs are user flags of InferenceSpec.RuntimeOverrides, keyed by the flag name
+	// without the leading dashes. Values are shell-quoted when the command is built.
+	ExtraArgs map[string]string
+
 	// When set, streaming fields override --model and --load-format.
 	// Distributed streaming (--model-loader-extra-config) is handled automatically
 	// inside buildVLLMInferenceCommand based on the resolved tensor-parallel-size.
@@ -396,7 +510,7 @@ func (p *PresetParam) buildVLLMInferenceCommand(rc RuntimeContext) []string {
 	} else if rc.MaxModelLen > 0 {
 		p.VLLM.ModelRunParams["max-model-len"] = strconv.Itoa(rc.MaxModelLen)
 	}
-	p.VLLM.ModelRunParams["gpu-memory-utilization"] = "0.84"
+	p.VLLM.ModelRunParams["gpu-memory-utilization"] = strconv.FormatFloat(GPUMemoryUtilization, 'f', -1, 64)
 
 	// Disable the allreduce + RMSNorm fusion pass. Since vLLM 0.22.1 this pass is
 	// enabled by default and routes through FlashInfer's TRT-LLM MNNVL kernel, which
@@ -442,6 +556,22 @@ func (p *PresetParam) buildVLLMInferenceCommand(rc RuntimeContext) []string {
 	if rc.PerformanceMode != "" && rc.PerformanceMode != "balanced" {
 		p.VLLM.ModelRunParams["performance-mode"] = rc.PerformanceMode
 	}
+	if rc.ChatTemplate != "" {
+		p.VLLM.ModelRunParams["chat-template"] = path.Join(ChatTemplateDir, rc.ChatTemplate)
+	}
+	if rc.StructuredOutputsBackend != "" {
+		p.VLLM.ModelRunParams["structured-outputs-config.backend"] = rc.StructuredOutputsBackend
+	}
+	if rc.OTLPTracesEndpoint != "" {
+		p.VLLM.ModelRunParams["otlp-traces-endpoint"] = rc.OTLPTracesEndpoint
+	}
+	if rc.ToolCallingDisabled {
+		delete(p.VLLM.ModelRunParams, "tool-call-parser")
+		delete(p.VLLM.ModelRunParams, "enable-auto-tool-choice")
+	} else if rc.ToolCallParser != "" {
+		p.VLLM.ModelRunParams["tool-call-parser"] = rc.ToolCallParser
+		p.VLLM.ModelRunParams["enable-auto-tool-choice"] = ""
+	}
 
 	// Disable LMCache KV cache CPU offloading for models where it is known to be
 	// problematic, either because:
@@ -474,6 +604,14 @@ func (p *PresetParam) buildVLLMInferenceCommand(rc RuntimeContext) []string {
 		}
 	}
 
+	// User flags come last; the webhook rejects the ones KAITO sets above.
+	for name, value := range rc.ExtraArgs {
+		if value != "" {
+			value = utils.ShellQuote(value)
+		}
+		p.VLLM.ModelRunParams[name] = value
+	}
+
 	// Single-node path: no Ray cluster needed.
 	if !rc.DistributedInference || rc.NumNodes == 1 {
 		modelCommand := utils.BuildCmdStr(p.VLLM.BaseCommand, p.VLLM.ModelRunParams)
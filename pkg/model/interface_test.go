@@ -15,6 +15,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -411,6 +412,83 @@ func TestGetInferenceCommandVLLMPerformanceMode(t *testing.T) {
 	})
 }
 
+func TestGetInferenceCommandVLLMToolCalling(t *testing.T) {
+	newPreset := func() *PresetParam {
+		return &PresetParam{
+			RuntimeParam: RuntimeParam{
+				VLLM: VLLMParam{
+					BaseCommand: "vllm serve",
+					ModelRunParams: map[string]string{
+						"tool-call-parser":        "llama3_json",
+						"enable-auto-tool-choice": "",
+						"chat-template":           "/workspace/chat_templates/tool-chat-llama3.1-json.jinja",
+					},
+				},
+			},
+		}
+	}
+	baseRC := RuntimeContext{
+		RuntimeName: RuntimeNameVLLM,
+		SKUNumGPUs:  1,
+		NumNodes:    1,
+	}
+
+	t.Run("preset defaults are kept", func(t *testing.T) {
+		cmd := newPreset().GetInferenceCommand(baseRC)
+		require.Len(t, cmd, 3)
+		assert.Contains(t, cmd[2], "--tool-call-parser=llama3_json")
+		assert.Contains(t, cmd[2], "--enable-auto-tool-choice")
+	})
+
+	t.Run("parser and chat template are overridden", func(t *testing.T) {
+		rc := baseRC
+		rc.RuntimeContextExtraArguments = RuntimeContextExtraArguments{ToolCallParser: "pythonic", ChatTemplate: "tool-chat-hermes.jinja"}
+		cmd := newPreset().GetInferenceCommand(rc)
+		require.Len(t, cmd, 3)
+		assert.Contains(t, cmd[2], "--tool-call-parser=pythonic")
+		assert.Contains(t, cmd[2], "--chat-template=/workspace/chat_templates/tool-chat-hermes.jinja")
+	})
+
+	t.Run("structured outputs backend is set", func(t *testing.T) {
+		rc := baseRC
+		rc.RuntimeContextExtraArguments = RuntimeContextExtraArguments{StructuredOutputsBackend: "xgrammar"}
+		cmd := newPreset().GetInferenceCommand(rc)
+		require.Len(t, cmd, 3)
+		assert.Contains(t, cmd[2], "--structured-outputs-config.backend=xgrammar")
+	})
+
+	t.Run("tracing endpoint is set", func(t *testing.T) {
+		rc := baseRC
+		rc.RuntimeContextExtraArguments = RuntimeContextExtraArguments{OTLPTracesEndpoint: "$OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"}
+		cmd := newPreset().GetInferenceCommand(rc)
+		require.Len(t, cmd, 3)
+		assert.Contains(t, cmd[2], "--otlp-traces-endpoint=$OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	})
+
+	t.Run("extra args are appended and quoted", func(t *testing.T) {
+		rc := baseRC
+		rc.RuntimeContextExtraArguments = RuntimeContextExtraArguments{ExtraArgs: map[string]string{
+			"max-num-seqs":       "128",
+			"enforce-eager":      "",
+			"compilation-config": `{"level": 3}`,
+		}}
+		cmd := newPreset().GetInferenceCommand(rc)
+		require.Len(t, cmd, 3)
+		assert.Contains(t, cmd[2], "--max-num-seqs=128")
+		assert.Contains(t, cmd[2], "--enforce-eager")
+		assert.Contains(t, cmd[2], `--compilation-config='{"level": 3}'`)
+	})
+
+	t.Run("disabled removes automatic tool choice", func(t *testing.T) {
+		rc := baseRC
+		rc.RuntimeContextExtraArguments = RuntimeContextExtraArguments{ToolCallingDisabled: true, ToolCallParser: "pythonic"}
+		cmd := newPreset().GetInferenceCommand(rc)
+		require.Len(t, cmd, 3)
+		assert.NotContains(t, cmd[2], "tool-call-parser")
+		assert.NotContains(t, cmd[2], "enable-auto-tool-choice")
+	})
+}
+
 func TestPresetParamValidate(t *testing.T) {
 	t.Run("vllm with lora disallowed and adapters enabled", func(t *testing.T) {
 		p := &PresetParam{
@@ -819,3 +897,43 @@ func TestBuildVLLMInferenceCommandNoKVCacheOverrideForNonHybrid(t *testing.T) {
 	require.Len(t, cmd, 3)
 	assert.NotContains(t, cmd[2], "kaito-kv-cache-cpu-memory-utilization")
 }
+
+func TestMetadataSupportsArchitecture(t *testing.T) {
+	amd64Only := &Metadata{}
+	assert.True(t, amd64Only.SupportsArchitecture(consts.ArchitectureAMD64))
+	assert.False(t, amd64Only.SupportsArchitecture(consts.ArchitectureARM64))
+	assert.Equal(t, []string{consts.ArchitectureAMD64}, amd64Only.ImagePlatforms())
+
+	multiArch := &Metadata{Platforms: []string{consts.ArchitectureAMD64, consts.ArchitectureARM64}}
+	assert.True(t, multiArch.SupportsArchitecture(consts.ArchitectureARM64))
+	assert.True(t, multiArch.SupportsArchitecture(consts.ArchitectureAMD64))
+}
+
+func TestMetadataLifecycle(t *testing.T) {
+	m := Metadata{Name: "phi-3-mini-4k-instruct", Deprecated: true, DeprecationDate: "2026-01-31",
+		EndOfLifeDate: "2026-07-31", Replacement: "microsoft/Phi-4-mini-instruct"}
+	assert.NoError(t, m.Validate())
+
+	before := time.Date(2026, 7, 30, 23, 59, 0, 0, time.UTC)
+	assert.False(t, m.EndOfLife(before))
+	assert.Equal(t, "preset phi-3-mini-4k-instruct is deprecated since 2026-01-31 and reaches end of life on 2026-07-31, migrate to microsoft/Phi-4-mini-instruct",
+		m.DeprecationMessage("phi-3-mini-4k-instruct", before))
+
+	after := time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC)
+	assert.True(t, m.EndOfLife(after))
+	assert.Equal(t, "preset phi-3-mini-4k-instruct is deprecated since 2026-01-31 and reached end of life on 2026-07-31, new workspaces cannot use it, migrate to microsoft/Phi-4-mini-instruct",
+		m.DeprecationMessage("phi-3-mini-4k-instruct", after))
+
+	assert.Equal(t, "preset phi-2 is deprecated", (&Metadata{Deprecated: true}).DeprecationMessage("phi-2", after))
+	assert.Empty(t, (&Metadata{}).DeprecationMessage("phi-4", after))
+	assert.False(t, (&Metadata{EndOfLifeDate: "2026-07-31"}).EndOfLife(after))
+
+	for _, invalid := range []Metadata{
+		{Name: "a", EndOfLifeDate: "2026-07-31"},
+		{Name: "b", Deprecated: true, DeprecationDate: "31/01/2026"},
+		{Name: "c", Deprecated: true, EndOfLifeDate: "2026-13-01"},
+		{Name: "d", Deprecated: true, DeprecationDate: "2026-07-31", EndOfLifeDate: "2026-01-31"},
+	} {
+		assert.Error(t, invalid.Validate(), invalid.Name)
+	}
+}
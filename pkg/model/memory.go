@@ -0,0 +1,69 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// GPUMemoryUtilization is the --gpu-memory-utilization value vLLM is launched with.
+	// vLLM treats it as the hard cap on the fraction of total GPU memory used for
+	// weights + activations + CUDA graphs + KV cache, so memory estimates must use the
+	// same value to predict the per-GPU memory budget vLLM will actually have.
+	GPUMemoryUtilization = 0.84
+
+	// WeightExpansionFactor accounts for the ~2% expansion of model weights once
+	// loaded by vLLM relative to the on-disk safetensor size.
+	WeightExpansionFactor = 1.02
+
+	// BaseOverheadGiB is the model-independent part of vLLM's fixed per-GPU
+	// overhead: non-torch allocations such as the CUDA context and NCCL buffers
+	// (~0.6 GiB) plus a baseline for small-model activations and CUDA graphs
+	// (~1.7 GiB). Larger models add to this via OverheadWeightFactor below.
+	BaseOverheadGiB = 2.3
+
+	// OverheadWeightFactor scales the runtime overhead with the per-GPU model
+	// weight share. Peak activation memory and CUDA graph capture both grow with
+	// hidden size / layer count and are sharded across TP ranks the same way
+	// weights are, so the per-GPU weight share is a good proxy for them. vLLM
+	// measures these empirically in determine_available_memory() and
+	// profile_cudagraph_memory(). We approximate at best effort here.
+	OverheadWeightFactor = 0.05
+)
+
+// MaxContextLen returns the largest max-model-len whose KV cache fits a node of gpuCount
+// GPUs with gpuMemPerGPU bytes each, once vLLM has set aside its base overhead and, when
+// modelSize is not zero, the weights of a model of modelSize bytes sharded across the
+// GPUs of the node. Pass a zero modelSize for models that may be spread across more
+// nodes. It returns 0 when nothing is left for the KV cache.
+func MaxContextLen(gpuMemPerGPU float64, gpuCount int, utilization, modelSize float64, bytesPerToken int) int {
+	if gpuCount <= 0 || bytesPerToken <= 0 {
+		return 0
+	}
+	weightsPerGPU := modelSize * WeightExpansionFactor / float64(gpuCount)
+	left := gpuMemPerGPU*utilization - BaseOverheadGiB*float64(consts.GiBToBytes) - weightsPerGPU*(1+OverheadWeightFactor)
+	if left <= 0 {
+		return 0
+	}
+	return int(left * float64(gpuCount) / float64(bytesPerToken))
+}
+
+// MinGPUMemory returns the GPU memory a single GPU needs to serve a model of modelSize bytes
+// with a KV cache of contextLen tokens. It is the inverse of MaxContextLen for one GPU.
+func MinGPUMemory(modelSize float64, bytesPerToken, contextLen int) float64 {
+	need := BaseOverheadGiB*float64(consts.GiBToBytes) + modelSize*WeightExpansionFactor*(1+OverheadWeightFactor) +
+		float64(contextLen)*float64(bytesPerToken)
+	return need / GPUMemoryUtilization
+}
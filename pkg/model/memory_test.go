@@ -0,0 +1,56 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestMaxContextLen(t *testing.T) {
+	gib := float64(consts.GiBToBytes)
+	tests := []struct {
+		name      string
+		gpuMem    float64
+		gpuCount  int
+		modelSize float64
+		want      int
+	}{
+		// 24GiB * 0.84 - 2.3GiB - 15GiB * 1.02 * 1.05 leaves ~1.795GiB, 14704 tokens of 128KiB.
+		{name: "weights on a single GPU", gpuMem: 24 * gib, gpuCount: 1, modelSize: 15 * gib, want: 14704},
+		// The weights are halved per GPU and the KV cache is sharded over both GPUs.
+		{name: "weights sharded across two GPUs", gpuMem: 24 * gib, gpuCount: 2, modelSize: 15 * gib, want: 161013},
+		{name: "weights on other nodes", gpuMem: 24 * gib, gpuCount: 1, want: 146309},
+		{name: "weights do not fit", gpuMem: 16 * gib, gpuCount: 1, modelSize: 15 * gib},
+		{name: "no GPUs", gpuMem: 24 * gib, modelSize: 15 * gib},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaxContextLen(tt.gpuMem, tt.gpuCount, GPUMemoryUtilization, tt.modelSize, 131072))
+		})
+	}
+}
+
+func TestMinGPUMemory(t *testing.T) {
+	gib := float64(consts.GiBToBytes)
+	// (2.3GiB + 15GiB * 1.02 * 1.05 + 2048 * 128KiB) / 0.84 = ~22.16GiB.
+	assert.InDelta(t, 22.16, MinGPUMemory(15*gib, 131072, 2048)/gib, 0.01)
+
+	// A GPU of the minimum memory fits the requested context.
+	need := MinGPUMemory(15*gib, 131072, 4096)
+	assert.GreaterOrEqual(t, MaxContextLen(need, 1, GPUMemoryUtilization, 15*gib, 131072), 4095)
+}
@@ -36,6 +36,11 @@ const (
 	// huggingface-hub version
 	HuggingFaceHubVersion = "1.18.0"
 
+	// Azure ML and SageMaker SDK versions used to fetch registry models
+	AzureAIMLVersion     = "1.27.1"
+	AzureIdentityVersion = "1.23.0"
+	Boto3Version         = "1.39.4"
+
 	// Default CPU/memory request==limit for the download Job container. Sized for fast
 	// parallel HF downloads in production
 	DefaultDownloadJobCPU    = "3"
@@ -230,7 +230,7 @@ func (r *ModelMirrorReconciler) ensureDownloadJob(ctx context.Context, cr *kaito
 		return nil
 	}
 
-	job := download.BuildDownloadJob(cr, r.DownloadResources, download.WorkloadIdentityPodLabels(os.Getenv("CLOUD_PROVIDER")))
+	job := download.BuildDownloadJob(cr, r.DownloadResources, download.WorkloadIdentityPodLabels(os.Getenv("CLOUD_PROVIDER"), cr.Spec.Source.Registry))
 	log.Info("Creating download Job", "namespace", cr.Spec.JobNamespace)
 	return r.Create(ctx, job)
 }
@@ -294,9 +294,8 @@ func (r *ModelMirrorReconciler) checkJobStatus(ctx context.Context, cr *kaitov1a
 }
 
 func (r *ModelMirrorReconciler) handleJobSuccess(ctx context.Context, cr *kaitov1alpha1.ModelMirror, log logr.Logger) (ctrl.Result, error) {
-	modelID := cr.Spec.Source.ModelID
 	cr.Status.Phase = kaitov1alpha1.ModelMirrorPhaseReady
-	cr.Status.ModelPath = "/models/" + modelID
+	cr.Status.ModelPath = "/models/" + download.ModelDir(cr.Spec.Source)
 	cr.Status.FailureMessage = ""
 	cr.Status.LastDownloadTime = ptr.To(metav1.Now())
 
@@ -317,6 +316,7 @@ func setCondition(cr *kaitov1alpha1.ModelMirror, condType string, status metav1.
 			cr.Status.Conditions[i].Status = status
 			cr.Status.Conditions[i].Reason = reason
 			cr.Status.Conditions[i].Message = message
+			cr.Status.Conditions[i].ObservedGeneration = cr.Generation
 			return
 		}
 	}
@@ -325,6 +325,7 @@ func setCondition(cr *kaitov1alpha1.ModelMirror, condType string, status metav1.
 		Status:             status,
 		Reason:             reason,
 		Message:            message,
+		ObservedGeneration: cr.Generation,
 		LastTransitionTime: now,
 	})
 }
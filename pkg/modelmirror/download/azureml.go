@@ -0,0 +1,56 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"fmt"
+
+	mmconsts "github.com/kaito-project/kaito/pkg/modelmirror/consts"
+)
+
+// azureMLFetchScript downloads the registered Azure ML model MODEL_ID ("<name>:<version>")
+// from the AZUREML_WORKSPACE workspace. DefaultAzureCredential picks up the workload identity
+// injected for the ServiceAccount, or the service principal of the access secret.
+func azureMLFetchScript() string {
+	return fmt.Sprintf(`set -e
+pip install -q "azure-ai-ml==%s" "azure-identity==%s"
+
+python3 - <<'PY'
+%s
+from azure.ai.ml import MLClient
+from azure.identity import DefaultAzureCredential
+
+name, version = os.environ["MODEL_ID"].rsplit(":", 1)
+client = MLClient(
+    DefaultAzureCredential(),
+    os.environ["AZUREML_SUBSCRIPTION_ID"],
+    os.environ["AZUREML_RESOURCE_GROUP"],
+    os.environ["AZUREML_WORKSPACE"],
+)
+staging = tempfile.mkdtemp(dir="/models", prefix=".fetch-")
+client.models.download(name=name, version=version, download_path=staging)
+
+# The SDK nests the files under <name>/ and sometimes the artifact directory; unwrap them.
+root = staging
+entries = os.listdir(root)
+while len(entries) == 1 and os.path.isdir(os.path.join(root, entries[0])):
+    root = os.path.join(root, entries[0])
+    entries = os.listdir(root)
+install(root, staging)
+PY`,
+		mmconsts.AzureAIMLVersion,
+		mmconsts.AzureIdentityVersion,
+		installModelPython,
+	)
+}
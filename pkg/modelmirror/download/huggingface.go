@@ -0,0 +1,51 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"fmt"
+
+	mmconsts "github.com/kaito-project/kaito/pkg/modelmirror/consts"
+)
+
+// huggingFaceFetchScript downloads the Hugging Face repository MODEL_ID to /models/MODEL_ID.
+func huggingFaceFetchScript() string {
+	// Build --exclude flags from DownloadExcludePatterns
+	excludeFlags := ""
+	for _, pattern := range mmconsts.DownloadExcludePatterns {
+		excludeFlags += fmt.Sprintf("\n  --exclude %q \\", pattern)
+	}
+
+	// Post-download cleanup: empty directories left on the PVC become zero-byte
+	// blob objects on Azure Blob NFS. RunAI model streamer iterates all objects in
+	// the container and crashes on directories (IsADirectoryError). We remove all
+	// subdirectories as a safety net.
+	return fmt.Sprintf(`set -e
+export HF_HUB_ENABLE_HF_TRANSFER=1
+export HF_HUB_DOWNLOAD_TIMEOUT=300
+
+pip install -q "huggingface-hub==%s" hf_transfer
+
+hf download "${MODEL_ID}" \
+  --max-workers 4 \%s
+  --local-dir "/models/${MODEL_ID}"
+
+# Remove all subdirectories — on HNS-enabled blob (NFS), directories become
+# zero-byte objects that cause RunAI model streamer to fail with FileExistsError.
+rm -rf "/models/${MODEL_ID}/.cache" 2>/dev/null || true
+find "/models/${MODEL_ID}/" -mindepth 1 -type d -exec rm -rf {} + 2>/dev/null || true`,
+		mmconsts.HuggingFaceHubVersion,
+		excludeFlags,
+	)
+}
@@ -14,8 +14,6 @@
 package download
 
 import (
-	"fmt"
-
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -26,59 +24,13 @@ import (
 	mmconsts "github.com/kaito-project/kaito/pkg/modelmirror/consts"
 )
 
-// BuildDownloadJob constructs the Job that downloads model files to the PVC.
+// BuildDownloadJob constructs the Job that downloads model files to the PVC. The fetch script
+// depends on the source registry, see fetchScript.
 // resources sets the CPU/memory request==limit on the downloader container.
 // podLabels are applied to the Job pod template when a ServiceAccount is set (e.g. the
 // cloud workload-identity label); pass nil to add none.
 func BuildDownloadJob(cr *kaitov1alpha1.ModelMirror, resources mmconsts.DownloadJobResources, podLabels map[string]string) *batchv1.Job {
-	modelID := cr.Spec.Source.ModelID
-
-	// Build --exclude flags from DownloadExcludePatterns
-	excludeFlags := ""
-	for _, pattern := range mmconsts.DownloadExcludePatterns {
-		excludeFlags += fmt.Sprintf("\n  --exclude %q \\", pattern)
-	}
-
-	// Post-download cleanup: empty directories left on the PVC become zero-byte
-	// blob objects on Azure Blob NFS. RunAI model streamer iterates all objects in
-	// the container and crashes on directories (IsADirectoryError). We remove all
-	// subdirectories as a safety net.
-	script := fmt.Sprintf(`set -e
-export HF_HUB_ENABLE_HF_TRANSFER=1
-export HF_HUB_DOWNLOAD_TIMEOUT=300
-
-pip install -q "huggingface-hub==%s" hf_transfer
-
-hf download "${MODEL_ID}" \
-  --max-workers 4 \%s
-  --local-dir "/models/${MODEL_ID}"
-
-# Remove all subdirectories — on HNS-enabled blob (NFS), directories become
-# zero-byte objects that cause RunAI model streamer to fail with FileExistsError.
-rm -rf "/models/${MODEL_ID}/.cache" 2>/dev/null || true
-find "/models/${MODEL_ID}/" -mindepth 1 -type d -exec rm -rf {} + 2>/dev/null || true`,
-		mmconsts.HuggingFaceHubVersion,
-		excludeFlags,
-	)
-
-	envVars := []corev1.EnvVar{
-		{Name: "MODEL_ID", Value: modelID},
-	}
-
-	// Always declare HF_TOKEN — optional:true means Kubernetes silently skips it
-	// if the secret doesn't exist.
-	if cr.Spec.Source.AccessSecret != nil {
-		envVars = append(envVars, corev1.EnvVar{
-			Name: "HF_TOKEN",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{Name: cr.Spec.Source.AccessSecret.Name},
-					Key:                  "HF_TOKEN",
-					Optional:             ptr.To(true),
-				},
-			},
-		})
-	}
+	script, envVars := fetchScript(cr.Spec.Source)
 
 	container := corev1.Container{
 		Name:    "downloader",
@@ -114,3 +114,66 @@ func TestBuildDownloadJobServiceAccount(t *testing.T) {
 		assert.Empty(t, job.Spec.Template.Labels, "no pod labels expected when provider supplies none (non-Azure cloud)")
 	})
 }
+
+func TestBuildDownloadJobRegistrySources(t *testing.T) {
+	envValue := func(env []corev1.EnvVar, name string) (string, bool) {
+		for _, e := range env {
+			if e.Name == name {
+				if e.ValueFrom != nil {
+					return e.ValueFrom.SecretKeyRef.Name + "/" + e.ValueFrom.SecretKeyRef.Key, true
+				}
+				return e.Value, true
+			}
+		}
+		return "", false
+	}
+
+	t.Run("azureml passes the workspace and service principal", func(t *testing.T) {
+		cr := newTestModelMirror()
+		cr.Spec.Source = &kaitov1alpha1.ModelMirrorSource{
+			Registry:     kaitov1alpha1.RegistryAzureML,
+			ModelID:      "llama-ft:3",
+			AccessSecret: &corev1.ObjectReference{Name: "aml-sp"},
+			AzureML: &kaitov1alpha1.AzureMLModelSource{
+				SubscriptionID: "00000000-1111-2222-3333-444444444444",
+				ResourceGroup:  "ml-rg",
+				Workspace:      "ml-ws",
+			},
+		}
+		container := BuildDownloadJob(cr, mmconsts.DefaultDownloadJobResources(), nil).Spec.Template.Spec.Containers[0]
+		assert.Contains(t, container.Args[0], "azure-ai-ml=="+mmconsts.AzureAIMLVersion)
+		assert.Contains(t, container.Args[0], "client.models.download(")
+
+		dir, _ := envValue(container.Env, "MODEL_DIR")
+		assert.Equal(t, "azureml/ml-ws/llama-ft/3", dir)
+		workspace, _ := envValue(container.Env, "AZUREML_WORKSPACE")
+		assert.Equal(t, "ml-ws", workspace)
+		secret, _ := envValue(container.Env, "AZURE_CLIENT_SECRET")
+		assert.Equal(t, "aml-sp/AZURE_CLIENT_SECRET", secret)
+		_, ok := envValue(container.Env, "HF_TOKEN")
+		assert.False(t, ok)
+	})
+
+	t.Run("sagemaker without a secret uses the ServiceAccount identity", func(t *testing.T) {
+		cr := newTestModelMirror()
+		cr.Spec.Source = &kaitov1alpha1.ModelMirrorSource{
+			Registry: kaitov1alpha1.RegistrySageMaker,
+			ModelID:  "arn:aws:sagemaker:us-west-2:123456789012:model-package/llama-ft/3",
+		}
+		container := BuildDownloadJob(cr, mmconsts.DefaultDownloadJobResources(), nil).Spec.Template.Spec.Containers[0]
+		assert.Contains(t, container.Args[0], "describe_model_package")
+		assert.Contains(t, container.Args[0], `filter="data"`)
+
+		dir, _ := envValue(container.Env, "MODEL_DIR")
+		assert.Equal(t, "sagemaker/123456789012/us-west-2/llama-ft/3", dir)
+		for _, e := range container.Env {
+			assert.Nil(t, e.ValueFrom, "no secret env expected without an access secret, got %s", e.Name)
+		}
+	})
+
+	t.Run("huggingface keeps the repository layout", func(t *testing.T) {
+		job := BuildDownloadJob(newTestModelMirror(), mmconsts.DefaultDownloadJobResources(), nil)
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], `--local-dir "/models/${MODEL_ID}"`)
+		assert.Equal(t, "Qwen/Qwen3-8B-AWQ", ModelDir(newTestModelMirror().Spec.Source))
+	})
+}
@@ -14,15 +14,18 @@
 package download
 
 import (
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/modelmirror/download/azure"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
 // WorkloadIdentityPodLabels returns the cloud-specific pod labels that let the download Job
-// mount a workload-identity-authenticated StorageClass.
-func WorkloadIdentityPodLabels(cloud string) map[string]string {
-	switch cloud {
-	case consts.AzureCloudName:
+// mount a workload-identity-authenticated StorageClass. Azure ML downloads authenticate with
+// Azure Workload Identity on any cloud, so they get the Azure labels too. SageMaker downloads
+// need no label: IRSA keys on the ServiceAccount annotation alone.
+func WorkloadIdentityPodLabels(cloud, registry string) map[string]string {
+	switch {
+	case cloud == consts.AzureCloudName, registry == kaitov1alpha1.RegistryAzureML:
 		return azure.WorkloadIdentityPodLabels()
 	default:
 		return nil
@@ -18,17 +18,24 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
 func TestWorkloadIdentityPodLabels(t *testing.T) {
 	t.Run("azure returns the WI label", func(t *testing.T) {
-		labels := WorkloadIdentityPodLabels(consts.AzureCloudName)
+		labels := WorkloadIdentityPodLabels(consts.AzureCloudName, kaitov1alpha1.RegistryHuggingFace)
+		assert.Equal(t, "true", labels["azure.workload.identity/use"])
+	})
+
+	t.Run("azureml returns the WI label on any cloud", func(t *testing.T) {
+		labels := WorkloadIdentityPodLabels("aws", kaitov1alpha1.RegistryAzureML)
 		assert.Equal(t, "true", labels["azure.workload.identity/use"])
 	})
 
 	t.Run("non-azure returns nil", func(t *testing.T) {
-		assert.Nil(t, WorkloadIdentityPodLabels("aws"))
-		assert.Nil(t, WorkloadIdentityPodLabels(""))
+		assert.Nil(t, WorkloadIdentityPodLabels("aws", kaitov1alpha1.RegistryHuggingFace))
+		assert.Nil(t, WorkloadIdentityPodLabels("aws", kaitov1alpha1.RegistrySageMaker))
+		assert.Nil(t, WorkloadIdentityPodLabels("", ""))
 	})
 }
@@ -0,0 +1,93 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"fmt"
+
+	mmconsts "github.com/kaito-project/kaito/pkg/modelmirror/consts"
+)
+
+// sageMakerFetchScript downloads the model data of the SageMaker model package MODEL_ID (an
+// ARN) from S3. boto3 picks up the IRSA or EKS Pod Identity credentials of the ServiceAccount,
+// or the access keys of the access secret. Archives are extracted with tarfile's "data" filter
+// and S3 keys are checked, so nothing is written outside the model directory.
+func sageMakerFetchScript() string {
+	return fmt.Sprintf(`set -e
+pip install -q "boto3==%s"
+
+python3 - <<'PY'
+%s
+import tarfile
+
+import boto3
+
+arn = os.environ["MODEL_ID"]
+region = arn.split(":")[3]
+package = boto3.client("sagemaker", region_name=region).describe_model_package(ModelPackageName=arn)
+containers = package.get("InferenceSpecification", {}).get("Containers", [])
+if not containers:
+    raise SystemExit(f"model package {arn} has no inference containers")
+container = containers[0]
+s3 = boto3.client("s3", region_name=region)
+
+staging = tempfile.mkdtemp(dir="/models", prefix=".fetch-")
+root = os.path.realpath(os.path.join(staging, "model"))
+os.makedirs(root)
+
+
+def split_s3(uri):
+    bucket, _, key = uri.removeprefix("s3://").partition("/")
+    return bucket, key
+
+
+def download(bucket, key, rel):
+    target = os.path.realpath(os.path.join(root, rel))
+    if not target.startswith(root + os.sep):
+        raise SystemExit(f"refusing to write s3://{bucket}/{key} outside the model directory")
+    os.makedirs(os.path.dirname(target), exist_ok=True)
+    s3.download_file(bucket, key, target)
+    return target
+
+
+def extract(bucket, key):
+    archive = download(bucket, key, "model.tar.gz")
+    with tarfile.open(archive) as tar:
+        tar.extractall(root, filter="data")
+    os.remove(archive)
+
+
+source = container.get("ModelDataSource", {}).get("S3DataSource")
+if container.get("ModelDataUrl"):
+    extract(*split_s3(container["ModelDataUrl"]))
+elif source and source.get("CompressionType") == "Gzip":
+    extract(*split_s3(source["S3Uri"]))
+elif source and source.get("S3DataType") == "S3Object":
+    bucket, key = split_s3(source["S3Uri"])
+    download(bucket, key, os.path.basename(key))
+elif source:
+    bucket, prefix = split_s3(source["S3Uri"])
+    for page in s3.get_paginator("list_objects_v2").paginate(Bucket=bucket, Prefix=prefix):
+        for obj in page.get("Contents", []):
+            rel = obj["Key"][len(prefix):].lstrip("/")
+            if rel and not rel.endswith("/"):
+                download(bucket, obj["Key"], rel)
+else:
+    raise SystemExit(f"model package {arn} has no model data")
+install(root, staging)
+PY`,
+		mmconsts.Boto3Version,
+		installModelPython,
+	)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+)
+
+// ModelDir returns the directory, relative to the root of the mirror PVC, that the source
+// model is downloaded to. Hugging Face models keep their repository ID; registry models are
+// laid out by workspace or account so that equally named models do not collide.
+func ModelDir(source *kaitov1alpha1.ModelMirrorSource) string {
+	switch source.Registry {
+	case kaitov1alpha1.RegistryAzureML:
+		name, version, _ := strings.Cut(source.ModelID, ":")
+		return path.Join("azureml", source.AzureML.Workspace, name, version)
+	case kaitov1alpha1.RegistrySageMaker:
+		// arn:aws:sagemaker:<region>:<account>:model-package/<group>[/<version>]
+		parts := strings.SplitN(source.ModelID, ":", 6)
+		if len(parts) < 6 {
+			return path.Join("sagemaker", source.ModelID)
+		}
+		return path.Join("sagemaker", parts[4], parts[3], strings.TrimPrefix(parts[5], "model-package/"))
+	default:
+		return source.ModelID
+	}
+}
+
+// fetchScript returns the shell script the download Job runs for the source and the
+// environment it reads. Model references reach the script only through the environment.
+func fetchScript(source *kaitov1alpha1.ModelMirrorSource) (string, []corev1.EnvVar) {
+	switch source.Registry {
+	case kaitov1alpha1.RegistryAzureML:
+		return azureMLFetchScript(), append(registryEnv(source),
+			corev1.EnvVar{Name: "AZUREML_SUBSCRIPTION_ID", Value: source.AzureML.SubscriptionID},
+			corev1.EnvVar{Name: "AZUREML_RESOURCE_GROUP", Value: source.AzureML.ResourceGroup},
+			corev1.EnvVar{Name: "AZUREML_WORKSPACE", Value: source.AzureML.Workspace},
+		)
+	case kaitov1alpha1.RegistrySageMaker:
+		return sageMakerFetchScript(), registryEnv(source)
+	default:
+		return huggingFaceFetchScript(), append([]corev1.EnvVar{{Name: "MODEL_ID", Value: source.ModelID}},
+			secretEnv(source.AccessSecret, "HF_TOKEN")...)
+	}
+}
+
+// registryEnv returns the environment shared by the registry fetch scripts.
+func registryEnv(source *kaitov1alpha1.ModelMirrorSource) []corev1.EnvVar {
+	keys := []string{"AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET"}
+	if source.Registry == kaitov1alpha1.RegistrySageMaker {
+		keys = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"}
+	}
+	return append([]corev1.EnvVar{
+		{Name: "MODEL_ID", Value: source.ModelID},
+		{Name: "MODEL_DIR", Value: ModelDir(source)},
+	}, secretEnv(source.AccessSecret, keys...)...)
+}
+
+// secretEnv declares each key of the access secret as an optional env var of the same name,
+// so Kubernetes silently skips keys the secret doesn't have. Without a secret the Job
+// authenticates with the workload identity of its ServiceAccount.
+func secretEnv(secret *corev1.ObjectReference, keys ...string) []corev1.EnvVar {
+	if secret == nil {
+		return nil
+	}
+	envVars := make([]corev1.EnvVar, 0, len(keys))
+	for _, key := range keys {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  key,
+					Optional:             ptr.To(true),
+				},
+			},
+		})
+	}
+	return envVars
+}
+
+// installModelPython defines install(root, staging), which moves a fetched model from its
+// staging directory on the PVC to /models/MODEL_DIR, replacing an earlier partial download.
+const installModelPython = `import os
+import shutil
+import tempfile
+
+
+def install(root, staging):
+    dest = os.path.join("/models", os.environ["MODEL_DIR"])
+    shutil.rmtree(dest, ignore_errors=True)
+    os.makedirs(os.path.dirname(dest), exist_ok=True)
+    os.rename(root, dest)
+    shutil.rmtree(staging, ignore_errors=True)
+    print(f"Model {os.environ['MODEL_ID']} downloaded to {dest}")
+
+`
@@ -23,15 +23,18 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
-	"github.com/kaito-project/kaito/pkg/utils/nodes"
 )
 
 // BYOProvisioner is a no-op NodeProvisioner for BYO (Bring Your Own) node
 // scenarios where node auto-provisioning is disabled. ProvisionNodes and
-// DeleteNodes are no-ops. EnsureNodesReady only checks that enough
+// DeleteNodes never create or delete nodes; they only reconcile labels on
+// preferred nodes for Workspaces that opt in. EnsureNodesReady only checks that enough
 // matching Nodes are ready (no instance type validation, no GPU plugin checks).
 type BYOProvisioner struct {
 	client client.Client
+	// bootstrapDevicePlugin labels Nodes that lack the NVIDIA device plugin for the bootstrap
+	// DaemonSets of the chart; see SetBootstrapDevicePlugin.
+	bootstrapDevicePlugin bool
 }
 
 var _ nodeprovision.NodeProvisioner = (*BYOProvisioner)(nil)
@@ -46,12 +49,23 @@ func (n *BYOProvisioner) Name() string { return "BYOProvisioner" }
 // Start is a no-op for BYOProvisioner.
 func (n *BYOProvisioner) Start(ctx context.Context) error { return nil }
 
+// ProvisionNodes never creates nodes. When the Workspace sets kaito.sh/manage-node-labels,
+// it labels the preferred nodes so they match the labelSelector. When the device plugin is
+// bootstrapped, it labels the nodes that lack it.
 func (n *BYOProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
-	return nil
+	if err := n.reconcileNodeLabels(ctx, ws); err != nil {
+		return err
+	}
+	return n.bootstrapDevicePlugins(ctx, ws)
 }
 
+// DeleteNodes never deletes nodes; it only removes the labels KAITO added to them.
+// Workspaces that do not opt in had their nodes released by ProvisionNodes already.
 func (n *BYOProvisioner) DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
-	return nil
+	if !manageNodeLabelsEnabled(ws) {
+		return nil
+	}
+	return n.releaseNodeLabels(ctx, ws, nil)
 }
 
 func (n *BYOProvisioner) EnableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
@@ -72,12 +86,7 @@ func (n *BYOProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.
 	}
 
 	targetNodeCount := int(ws.Status.TargetNodeCount)
-	readyCount := 0
-	for i := range nodeList.Items {
-		if nodes.NodeIsReadyAndNotDeleting(&nodeList.Items[i]) {
-			readyCount++
-		}
-	}
+	readyCount, awaiting := n.countReadyNodes(nodeList)
 
 	if readyCount >= targetNodeCount {
 		return true, false, nil
@@ -85,7 +94,7 @@ func (n *BYOProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.
 
 	klog.InfoS("Not enough Nodes are ready for workspace (BYO mode)",
 		"workspace", client.ObjectKeyFromObject(ws).String(),
-		"targetNodes", targetNodeCount, "currentReadyNodes", readyCount)
+		"targetNodes", targetNodeCount, "currentReadyNodes", readyCount, "awaitingDevicePlugin", awaiting)
 	return false, true, nil
 }
 
@@ -105,11 +114,10 @@ func (n *BYOProvisioner) CollectNodeStatusInfo(ctx context.Context, ws *kaitov1b
 	if err != nil {
 		return nil, err
 	}
-	readyCount := 0
-	for i := range nodeList.Items {
-		if nodes.NodeIsReadyAndNotDeleting(&nodeList.Items[i]) {
-			readyCount++
-		}
+	readyCount, awaiting := n.countReadyNodes(nodeList)
+	if len(awaiting) > 0 {
+		nodeCond.Reason = "DevicePluginNotReady"
+		nodeCond.Message = devicePluginMessage(awaiting)
 	}
 	if readyCount >= int(ws.Status.TargetNodeCount) {
 		nodeCond.Status = metav1.ConditionTrue
@@ -0,0 +1,110 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package byoprovisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/nodes"
+)
+
+// gpuPresenceLabels are set on Nodes with an NVIDIA GPU by Node Feature Discovery, GPU
+// Feature Discovery or KAITO, whether or not a device plugin advertises the GPU.
+var gpuPresenceLabels = map[string]string{
+	"feature.node.kubernetes.io/pci-10de.present": "true",
+	"nvidia.com/gpu.present":                      "true",
+	nodes.LabelKeyNvidia:                          nodes.LabelValueNvidia,
+}
+
+// SetBootstrapDevicePlugin enables labeling the Workspace Nodes that have an NVIDIA GPU but
+// no nvidia.com/gpu capacity with kaito.sh/nvidia-device-plugin-bootstrap, so the bootstrap
+// DaemonSets of the chart install the device plugin on them. Such Nodes are not counted as
+// ready until they advertise their GPUs.
+func (n *BYOProvisioner) SetBootstrapDevicePlugin(enabled bool) {
+	n.bootstrapDevicePlugin = enabled
+}
+
+// awaitsDevicePlugin reports whether node has an NVIDIA GPU that no device plugin advertises.
+func awaitsDevicePlugin(node *corev1.Node) bool {
+	hasGPU := false
+	for k, v := range gpuPresenceLabels {
+		if node.Labels[k] == v {
+			hasGPU = true
+			break
+		}
+	}
+	capacity := node.Status.Capacity[nodes.CapacityNvidiaGPU]
+	return hasGPU && capacity.IsZero()
+}
+
+// bootstrapDevicePlugins labels the ready Nodes of ws that await the device plugin. The label
+// is never removed by KAITO: once the device plugin runs, removing it would take the GPUs
+// away from the workloads on the Node.
+func (n *BYOProvisioner) bootstrapDevicePlugins(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	if !n.bootstrapDevicePlugin {
+		return nil
+	}
+	nodeList, err := nodeprovision.ListWorkspaceNodes(ctx, n.client, n, ws)
+	if err != nil {
+		return err
+	}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !nodes.NodeIsReadyAndNotDeleting(node) || !awaitsDevicePlugin(node) ||
+			node.Labels[kaitov1beta1.LabelNvidiaDevicePluginBootstrap] == "true" {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Labels[kaitov1beta1.LabelNvidiaDevicePluginBootstrap] = "true"
+		if err := n.client.Patch(ctx, node, patch); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to label node %s for the device plugin bootstrap: %w", node.Name, err)
+		}
+		klog.InfoS("Bootstrapping the NVIDIA device plugin", "workspace", klog.KObj(ws), "node", node.Name)
+	}
+	return nil
+}
+
+// countReadyNodes returns the number of ready Nodes in nodeList and, when the device plugin
+// is bootstrapped, the names of the ready Nodes that are not counted because they await it.
+func (n *BYOProvisioner) countReadyNodes(nodeList *corev1.NodeList) (int, []string) {
+	readyCount := 0
+	var awaiting []string
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !nodes.NodeIsReadyAndNotDeleting(node) {
+			continue
+		}
+		if n.bootstrapDevicePlugin && awaitsDevicePlugin(node) {
+			awaiting = append(awaiting, node.Name)
+			continue
+		}
+		readyCount++
+	}
+	return readyCount, awaiting
+}
+
+// devicePluginMessage describes the Nodes that await the device plugin.
+func devicePluginMessage(awaiting []string) string {
+	return fmt.Sprintf("Not enough Nodes are ready; waiting for the NVIDIA device plugin to advertise the GPUs of %s",
+		strings.Join(awaiting, ", "))
+}
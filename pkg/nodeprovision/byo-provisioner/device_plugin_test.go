@@ -0,0 +1,128 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package byoprovisioner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newDevicePluginTestNode(name string, gpuPresent bool, gpus int64) *corev1.Node {
+	labels := map[string]string{"apps": "llm"}
+	if gpuPresent {
+		labels["feature.node.kubernetes.io/pci-10de.present"] = "true"
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if gpus > 0 {
+		node.Status.Capacity = corev1.ResourceList{"nvidia.com/gpu": *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return node
+}
+
+func newDevicePluginTestProvisioner(t *testing.T, bootstrap bool, nodes ...*corev1.Node) (*BYOProvisioner, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, node := range nodes {
+		builder = builder.WithObjects(node)
+	}
+	cl := builder.Build()
+	p := NewBYOProvisioner(cl)
+	p.SetBootstrapDevicePlugin(bootstrap)
+	return p, cl
+}
+
+func TestBootstrapDevicePlugins(t *testing.T) {
+	tests := []struct {
+		name      string
+		bootstrap bool
+		node      *corev1.Node
+		expected  bool
+	}{
+		{
+			name:      "GPU node without device plugin is labeled",
+			bootstrap: true,
+			node:      newDevicePluginTestNode("node-1", true, 0),
+			expected:  true,
+		},
+		{
+			name:      "GPU node with device plugin is not labeled",
+			bootstrap: true,
+			node:      newDevicePluginTestNode("node-1", true, 1),
+		},
+		{
+			name:      "CPU node is not labeled",
+			bootstrap: true,
+			node:      newDevicePluginTestNode("node-1", false, 0),
+		},
+		{
+			name: "bootstrap disabled",
+			node: newDevicePluginTestNode("node-1", true, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, cl := newDevicePluginTestProvisioner(t, tt.bootstrap, tt.node)
+			require.NoError(t, p.ProvisionNodes(context.Background(), newLabelTestWorkspace()))
+
+			node := &corev1.Node{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: tt.node.Name}, node))
+			_, labeled := node.Labels[kaitov1beta1.LabelNvidiaDevicePluginBootstrap]
+			assert.Equal(t, tt.expected, labeled)
+		})
+	}
+}
+
+func TestEnsureNodesReadyAwaitsDevicePlugin(t *testing.T) {
+	ws := newLabelTestWorkspace()
+	ws.Status.TargetNodeCount = 2
+
+	p, _ := newDevicePluginTestProvisioner(t, true,
+		newDevicePluginTestNode("node-1", true, 1), newDevicePluginTestNode("node-2", true, 0))
+	ready, needRequeue, err := p.EnsureNodesReady(context.Background(), ws)
+	require.NoError(t, err)
+	assert.False(t, ready)
+	assert.True(t, needRequeue)
+
+	conds, err := p.CollectNodeStatusInfo(context.Background(), ws)
+	require.NoError(t, err)
+	require.NotEmpty(t, conds)
+	assert.Equal(t, metav1.ConditionFalse, conds[0].Status)
+	assert.Equal(t, "DevicePluginNotReady", conds[0].Reason)
+	assert.Contains(t, conds[0].Message, "node-2")
+
+	// Without the bootstrap, nodes are counted whether or not they advertise their GPUs.
+	p, _ = newDevicePluginTestProvisioner(t, false,
+		newDevicePluginTestNode("node-1", true, 1), newDevicePluginTestNode("node-2", true, 0))
+	ready, _, err = p.EnsureNodesReady(context.Background(), ws)
+	require.NoError(t, err)
+	assert.True(t, ready)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package byoprovisioner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// manageNodeLabelsEnabled reports whether the Workspace opted into node label reconciliation.
+func manageNodeLabelsEnabled(ws *kaitov1beta1.Workspace) bool {
+	return ws.Annotations[kaitov1beta1.AnnotationManageNodeLabels] == "true"
+}
+
+func labelsOwner(ws *kaitov1beta1.Workspace) string {
+	return ws.Namespace + "/" + ws.Name
+}
+
+// reconcileNodeLabels adopts the Workspace's preferred nodes by adding the labelSelector
+// matchLabels to them, and releases nodes the Workspace owns but no longer prefers.
+// Labels that already exist with a different value, and nodes owned by another Workspace,
+// are left untouched.
+func (n *BYOProvisioner) reconcileNodeLabels(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	preferred := sets.New[string]()
+	if manageNodeLabelsEnabled(ws) {
+		//nolint:staticcheck //SA1019: deprecate Resource.PreferredNodes field
+		preferred.Insert(ws.Resource.PreferredNodes...)
+	}
+
+	if err := n.releaseNodeLabels(ctx, ws, preferred); err != nil {
+		return err
+	}
+
+	desired := kaitov1beta1.SanitizedMatchLabels(ws.Resource.LabelSelector)
+	if len(desired) == 0 {
+		return nil
+	}
+	for _, name := range sets.List(preferred) {
+		if err := n.adoptNode(ctx, ws, name, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *BYOProvisioner) adoptNode(ctx context.Context, ws *kaitov1beta1.Workspace, nodeName string, desired map[string]string) error {
+	node := &corev1.Node{}
+	if err := n.client.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.InfoS("Preferred node not found, skipping label reconciliation", "workspace", klog.KObj(ws), "node", nodeName)
+			return nil
+		}
+		return fmt.Errorf("failed to get preferred node %s: %w", nodeName, err)
+	}
+
+	owner := node.Annotations[kaitov1beta1.AnnotationNodeLabelsOwner]
+	if owner != "" && owner != labelsOwner(ws) {
+		klog.InfoS("Preferred node labels are owned by another workspace, skipping", "workspace", klog.KObj(ws), "node", nodeName, "owner", owner)
+		return nil
+	}
+
+	managed := parseManagedLabels(node.Annotations[kaitov1beta1.AnnotationNodeManagedLabels])
+	patch := client.MergeFrom(node.DeepCopy())
+	changed := false
+	for k, v := range desired {
+		current, exists := node.Labels[k]
+		switch {
+		case exists && current == v:
+			continue
+		case exists && !managed.Has(k):
+			klog.InfoS("Preferred node already has a conflicting label, not overwriting", "workspace", klog.KObj(ws), "node", nodeName, "label", k)
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[k] = v
+		managed.Insert(k)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Labels[kaitov1beta1.LabelNodeLabelsOwnerUID] = string(ws.UID)
+	node.Annotations[kaitov1beta1.AnnotationNodeLabelsOwner] = labelsOwner(ws)
+	node.Annotations[kaitov1beta1.AnnotationNodeManagedLabels] = strings.Join(sets.List(managed), ",")
+	if err := n.client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("failed to label preferred node %s: %w", nodeName, err)
+	}
+	klog.InfoS("Adopted preferred node", "workspace", klog.KObj(ws), "node", nodeName)
+	return nil
+}
+
+// releaseNodeLabels removes the labels KAITO added to nodes owned by ws that are not in keep.
+// Owned nodes are selected by the owner UID label rather than by listing every node.
+func (n *BYOProvisioner) releaseNodeLabels(ctx context.Context, ws *kaitov1beta1.Workspace, keep sets.Set[string]) error {
+	nodeList := &corev1.NodeList{}
+	if err := n.client.List(ctx, nodeList, client.MatchingLabels{kaitov1beta1.LabelNodeLabelsOwnerUID: string(ws.UID)}); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if node.Annotations[kaitov1beta1.AnnotationNodeLabelsOwner] != labelsOwner(ws) || keep.Has(node.Name) {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		for k := range parseManagedLabels(node.Annotations[kaitov1beta1.AnnotationNodeManagedLabels]) {
+			delete(node.Labels, k)
+		}
+		delete(node.Labels, kaitov1beta1.LabelNodeLabelsOwnerUID)
+		delete(node.Annotations, kaitov1beta1.AnnotationNodeLabelsOwner)
+		delete(node.Annotations, kaitov1beta1.AnnotationNodeManagedLabels)
+		if err := n.client.Patch(ctx, node, patch); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to release node %s: %w", node.Name, err)
+		}
+		klog.InfoS("Released node labels", "workspace", klog.KObj(ws), "node", node.Name)
+	}
+	return nil
+}
+
+func parseManagedLabels(value string) sets.Set[string] {
+	keys := sets.New[string]()
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys.Insert(k)
+		}
+	}
+	return keys
+}
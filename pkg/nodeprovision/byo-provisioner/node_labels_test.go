@@ -0,0 +1,173 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package byoprovisioner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newLabelTestWorkspace(preferred ...string) *kaitov1beta1.Workspace {
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Namespace:   "default",
+			UID:         "ws-uid",
+			Annotations: map[string]string{kaitov1beta1.AnnotationManageNodeLabels: "true"},
+		},
+		Resource: kaitov1beta1.ResourceSpec{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"apps": "llm"},
+			},
+			PreferredNodes: preferred,
+		},
+	}
+}
+
+func newLabelTestNode(name string, labels, annotations map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+	}
+}
+
+func TestReconcileNodeLabels(t *testing.T) {
+	owned := map[string]string{
+		kaitov1beta1.AnnotationNodeLabelsOwner:   "default/ws",
+		kaitov1beta1.AnnotationNodeManagedLabels: "apps",
+	}
+
+	tests := []struct {
+		name            string
+		ws              *kaitov1beta1.Workspace
+		nodes           []*corev1.Node
+		expectedLabels  map[string]map[string]string
+		expectedOwners  map[string]string
+		deleteWorkspace bool
+	}{
+		{
+			name:  "adopts preferred node",
+			ws:    newLabelTestWorkspace("node-1"),
+			nodes: []*corev1.Node{newLabelTestNode("node-1", nil, nil)},
+			expectedLabels: map[string]map[string]string{
+				"node-1": {"apps": "llm", kaitov1beta1.LabelNodeLabelsOwnerUID: "ws-uid"},
+			},
+			expectedOwners: map[string]string{"node-1": "default/ws"},
+		},
+		{
+			name:  "does not overwrite conflicting unmanaged label",
+			ws:    newLabelTestWorkspace("node-1"),
+			nodes: []*corev1.Node{newLabelTestNode("node-1", map[string]string{"apps": "other"}, nil)},
+			expectedLabels: map[string]map[string]string{
+				"node-1": {"apps": "other"},
+			},
+			expectedOwners: map[string]string{"node-1": ""},
+		},
+		{
+			name: "skips node owned by another workspace",
+			ws:   newLabelTestWorkspace("node-1"),
+			nodes: []*corev1.Node{newLabelTestNode("node-1", nil, map[string]string{
+				kaitov1beta1.AnnotationNodeLabelsOwner: "other/ws",
+			})},
+			expectedLabels: map[string]map[string]string{
+				"node-1": nil,
+			},
+			expectedOwners: map[string]string{"node-1": "other/ws"},
+		},
+		{
+			name:  "only releases nodes selected by the owner uid label",
+			ws:    newLabelTestWorkspace(),
+			nodes: []*corev1.Node{newLabelTestNode("node-1", map[string]string{"apps": "llm"}, owned)},
+			expectedLabels: map[string]map[string]string{
+				"node-1": {"apps": "llm"},
+			},
+			expectedOwners: map[string]string{"node-1": "default/ws"},
+		},
+		{
+			name: "releases node removed from preferred nodes",
+			ws:   newLabelTestWorkspace("node-2"),
+			nodes: []*corev1.Node{
+				newLabelTestNode("node-1", map[string]string{"apps": "llm", "pool": "gpu", kaitov1beta1.LabelNodeLabelsOwnerUID: "ws-uid"}, owned),
+				newLabelTestNode("node-2", nil, nil),
+			},
+			expectedLabels: map[string]map[string]string{
+				"node-1": {"pool": "gpu"},
+				"node-2": {"apps": "llm", kaitov1beta1.LabelNodeLabelsOwnerUID: "ws-uid"},
+			},
+			expectedOwners: map[string]string{"node-1": "", "node-2": "default/ws"},
+		},
+		{
+			name: "releases nodes when opt-in annotation is removed",
+			ws: func() *kaitov1beta1.Workspace {
+				ws := newLabelTestWorkspace("node-1")
+				ws.Annotations = nil
+				return ws
+			}(),
+			nodes: []*corev1.Node{newLabelTestNode("node-1", map[string]string{"apps": "llm", kaitov1beta1.LabelNodeLabelsOwnerUID: "ws-uid"}, owned)},
+			expectedLabels: map[string]map[string]string{
+				"node-1": {},
+			},
+			expectedOwners: map[string]string{"node-1": ""},
+		},
+		{
+			name:            "releases nodes on delete",
+			ws:              newLabelTestWorkspace("node-1"),
+			nodes:           []*corev1.Node{newLabelTestNode("node-1", map[string]string{"apps": "llm", kaitov1beta1.LabelNodeLabelsOwnerUID: "ws-uid"}, owned)},
+			deleteWorkspace: true,
+			expectedLabels: map[string]map[string]string{
+				"node-1": {},
+			},
+			expectedOwners: map[string]string{"node-1": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, node := range tt.nodes {
+				builder = builder.WithObjects(node.DeepCopy())
+			}
+			cl := builder.Build()
+			p := NewBYOProvisioner(cl)
+
+			if tt.deleteWorkspace {
+				require.NoError(t, p.DeleteNodes(context.Background(), tt.ws))
+			} else {
+				require.NoError(t, p.ProvisionNodes(context.Background(), tt.ws))
+			}
+
+			for name, labels := range tt.expectedLabels {
+				node := &corev1.Node{}
+				require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: name}, node))
+				if len(labels) == 0 {
+					assert.Empty(t, node.Labels, "node %s", name)
+				} else {
+					assert.Equal(t, labels, node.Labels, "node %s", name)
+				}
+				assert.Equal(t, tt.expectedOwners[name], node.Annotations[kaitov1beta1.AnnotationNodeLabelsOwner], "node %s", name)
+			}
+		})
+	}
+}
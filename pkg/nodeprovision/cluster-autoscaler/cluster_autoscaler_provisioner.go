@@ -0,0 +1,348 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clusterautoscaler provisions workspace nodes in clusters that scale pre-created GPU
+// node groups with the cluster autoscaler instead of Karpenter.
+package clusterautoscaler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/nodes"
+)
+
+const (
+	// AnnotationScaleDownDisabled stops the cluster autoscaler from removing a node.
+	AnnotationScaleDownDisabled = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	// LabelScaleUp labels the placeholder pods of a workspace with its name.
+	LabelScaleUp = kaitov1beta1.KAITOPrefix + "scale-up"
+
+	// placeholderImage runs the placeholder pods; they only hold a node slot.
+	placeholderImage = "registry.k8s.io/pause:3.10"
+	// scaleUpSuffix is appended to the workspace name to name its placeholder Deployment.
+	scaleUpSuffix = "-scale-up"
+)
+
+// ClusterAutoscalerProvisioner scales GPU node groups through the cluster autoscaler, which
+// adds nodes when pods cannot be scheduled. While a workspace lacks ready nodes, it runs
+// one placeholder pod per target node that only fits on nodes of the workspace instance
+// type and labels, one per node. The cluster autoscaler scales the matching node group up,
+// from zero when the group carries the node-template annotations. Once the nodes are ready
+// the placeholders are removed, and the nodes are protected from scale down until the
+// workspace is deleted.
+type ClusterAutoscalerProvisioner struct {
+	client client.Client
+}
+
+var _ nodeprovision.NodeProvisioner = (*ClusterAutoscalerProvisioner)(nil)
+
+func NewClusterAutoscalerProvisioner(c client.Client) *ClusterAutoscalerProvisioner {
+	return &ClusterAutoscalerProvisioner{client: c}
+}
+
+// Name returns the provisioner name.
+func (p *ClusterAutoscalerProvisioner) Name() string { return "ClusterAutoscalerProvisioner" }
+
+// Start is a no-op: the cluster autoscaler needs no CRDs or global resources.
+func (p *ClusterAutoscalerProvisioner) Start(ctx context.Context) error { return nil }
+
+// ProvisionNodes runs the placeholder pods until enough matching nodes are ready.
+func (p *ClusterAutoscalerProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	readyNodes, err := p.readyNodes(ctx, ws)
+	if err != nil {
+		return err
+	}
+	if len(readyNodes) >= int(ws.Status.TargetNodeCount) {
+		return p.deletePlaceholders(ctx, ws)
+	}
+	return p.ensurePlaceholders(ctx, ws)
+}
+
+// DeleteNodes removes the placeholder pods and lets the cluster autoscaler scale the nodes
+// of the workspace down once they are empty. Nodes are never deleted directly.
+func (p *ClusterAutoscalerProvisioner) DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	if err := p.deletePlaceholders(ctx, ws); err != nil {
+		return err
+	}
+	nodeList, err := nodeprovision.ListWorkspaceNodes(ctx, p.client, p, ws)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	owner := protectedBy(ws)
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if node.Annotations[kaitov1beta1.AnnotationScaleDownDisabledBy] != owner {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Annotations, AnnotationScaleDownDisabled)
+		delete(node.Annotations, kaitov1beta1.AnnotationScaleDownDisabledBy)
+		if err := p.client.Patch(ctx, node, patch); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to enable scale down of node %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// EnableDriftRemediation is a no-op: the cluster autoscaler does not replace drifted nodes.
+func (p *ClusterAutoscalerProvisioner) EnableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return nil
+}
+
+// DisableDriftRemediation is a no-op: the cluster autoscaler does not replace drifted nodes.
+func (p *ClusterAutoscalerProvisioner) DisableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return nil
+}
+
+// EnsureNodesReady checks that enough nodes of the instance type are ready and report
+// their GPUs. Once they do, it removes the placeholder pods and protects the nodes from
+// scale down. Scaling a node group up takes minutes, so it requeues while nodes are missing.
+func (p *ClusterAutoscalerProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.Workspace) (bool, bool, error) {
+	readyNodes, err := p.readyNodes(ctx, ws)
+	if err != nil {
+		return false, true, err
+	}
+	targetNodeCount := int(ws.Status.TargetNodeCount)
+	if len(readyNodes) < targetNodeCount {
+		klog.InfoS("Waiting for the cluster autoscaler to add nodes",
+			"workspace", klog.KObj(ws), "targetNodes", targetNodeCount, "currentReadyNodes", len(readyNodes))
+		return false, true, nil
+	}
+
+	if err := p.deletePlaceholders(ctx, ws); err != nil {
+		return false, true, err
+	}
+	owner := protectedBy(ws)
+	for _, node := range readyNodes {
+		if _, ok := node.Annotations[kaitov1beta1.AnnotationScaleDownDisabledBy]; ok {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[AnnotationScaleDownDisabled] = "true"
+		node.Annotations[kaitov1beta1.AnnotationScaleDownDisabledBy] = owner
+		if err := p.client.Patch(ctx, node, patch); err != nil {
+			return false, true, fmt.Errorf("failed to disable scale down of node %s: %w", node.Name, err)
+		}
+	}
+	return true, false, nil
+}
+
+// CollectNodeStatusInfo gathers status conditions for workspace status. There are no
+// NodeClaims, so no NodeClaimStatus condition is returned.
+func (p *ClusterAutoscalerProvisioner) CollectNodeStatusInfo(ctx context.Context, ws *kaitov1beta1.Workspace) ([]metav1.Condition, error) {
+	readyNodes, err := p.readyNodes(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+	targetNodeCount := int(ws.Status.TargetNodeCount)
+	if len(readyNodes) >= targetNodeCount {
+		return []metav1.Condition{
+			{
+				Type: string(kaitov1beta1.ConditionTypeNodeStatus), Status: metav1.ConditionTrue,
+				Reason: "NodesReady", Message: "Enough Nodes are ready",
+			},
+			{
+				Type: string(kaitov1beta1.ConditionTypeResourceStatus), Status: metav1.ConditionTrue,
+				Reason: "workspaceResourceStatusSuccess", Message: "workspace resource is ready",
+			},
+		}, nil
+	}
+	message := fmt.Sprintf("waiting for the cluster autoscaler to add nodes of instance type %s, %d of %d are ready",
+		ws.EffectiveInstanceType(), len(readyNodes), targetNodeCount)
+	return []metav1.Condition{
+		{
+			Type: string(kaitov1beta1.ConditionTypeNodeStatus), Status: metav1.ConditionFalse,
+			Reason: "NodeNotReady", Message: message,
+		},
+		{
+			Type: string(kaitov1beta1.ConditionTypeResourceStatus), Status: metav1.ConditionFalse,
+			Reason: "NodeNotReady", Message: message,
+		},
+	}, nil
+}
+
+// BuildNodeSelector pins workloads to nodes of the workspace instance type, which the
+// node groups label with node.kubernetes.io/instance-type.
+func (p *ClusterAutoscalerProvisioner) BuildNodeSelector(ctx context.Context, ws *kaitov1beta1.Workspace) []corev1.NodeSelectorRequirement {
+	if ws.EffectiveInstanceType() == "" {
+		return nil
+	}
+	return []corev1.NodeSelectorRequirement{{
+		Key:      corev1.LabelInstanceTypeStable,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{ws.EffectiveInstanceType()},
+	}}
+}
+
+// readyNodes returns the ready nodes of the workspace. Nodes of GPU instance types only
+// count once the device plugin reports their GPUs.
+func (p *ClusterAutoscalerProvisioner) readyNodes(ctx context.Context, ws *kaitov1beta1.Workspace) ([]*corev1.Node, error) {
+	nodeList, err := nodeprovision.ListWorkspaceNodes(ctx, p.client, p, ws)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	gpuConfig, _ := sku.GetGPUConfigBySKU(ws.EffectiveInstanceType())
+	needsGPUs := gpuConfig != nil && gpuConfig.GPUCount > 0
+	var readyNodes []*corev1.Node
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !nodes.NodeIsReadyAndNotDeleting(node) {
+			continue
+		}
+		if gpus := node.Status.Allocatable.Name(nodes.CapacityNvidiaGPU, resource.DecimalSI); needsGPUs && gpus.IsZero() {
+			continue
+		}
+		readyNodes = append(readyNodes, node)
+	}
+	return readyNodes, nil
+}
+
+func (p *ClusterAutoscalerProvisioner) ensurePlaceholders(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	desired := GeneratePlaceholderDeployment(ws)
+	existing := &appsv1.Deployment{}
+	err := p.client.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		klog.InfoS("Creating placeholder pods to scale up nodes", "workspace", klog.KObj(ws), "replicas", *desired.Spec.Replicas)
+		if err := p.client.Create(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create placeholder Deployment %s: %w", desired.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get placeholder Deployment %s: %w", desired.Name, err)
+	}
+	if ptr.Deref(existing.Spec.Replicas, 0) == *desired.Spec.Replicas {
+		return nil
+	}
+	existing.Spec.Replicas = desired.Spec.Replicas
+	if err := p.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to scale placeholder Deployment %s: %w", desired.Name, err)
+	}
+	return nil
+}
+
+func (p *ClusterAutoscalerProvisioner) deletePlaceholders(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: ws.Name + scaleUpSuffix, Namespace: ws.Namespace}}
+	if err := p.client.Delete(ctx, deployment, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete placeholder Deployment %s: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// GeneratePlaceholderDeployment returns the Deployment of placeholder pods that makes the
+// cluster autoscaler add the nodes of the workspace. Each pod requires a node of the
+// instance type, labels and zones of the workspace, and the pods repel each other so each
+// one needs its own node.
+func GeneratePlaceholderDeployment(ws *kaitov1beta1.Workspace) *appsv1.Deployment {
+	podLabels := map[string]string{LabelScaleUp: ws.Name}
+	requirements := []corev1.NodeSelectorRequirement{{
+		Key:      corev1.LabelInstanceTypeStable,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{ws.EffectiveInstanceType()},
+	}}
+	matchLabels := kaitov1beta1.SanitizedMatchLabels(ws.Resource.LabelSelector)
+	for _, key := range slices.Sorted(maps.Keys(matchLabels)) {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{matchLabels[key]},
+		})
+	}
+	if len(ws.Resource.Zones) > 0 {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelTopologyZone,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   ws.Resource.Zones,
+		})
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ws.Name + scaleUpSuffix,
+			Namespace: ws.Namespace,
+			Labels:    podLabels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ws, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(ws.Status.TargetNodeCount),
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+								NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: requirements}},
+							},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+								TopologyKey:   corev1.LabelHostname,
+							}},
+						},
+					},
+					Tolerations: []corev1.Toleration{
+						{Key: consts.SKUString, Operator: corev1.TolerationOpEqual, Value: consts.GPUString, Effect: corev1.TaintEffectNoSchedule},
+						{Key: nodes.CapacityNvidiaGPU, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+					},
+					AutomountServiceAccountToken:  ptr.To(false),
+					TerminationGracePeriodSeconds: ptr.To(int64(0)),
+					Containers: []corev1.Container{{
+						Name:  "placeholder",
+						Image: placeholderImage,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("10m"),
+								corev1.ResourceMemory: resource.MustParse("16Mi"),
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							ReadOnlyRootFilesystem:   ptr.To(true),
+							RunAsNonRoot:             ptr.To(true),
+							RunAsUser:                ptr.To(int64(65535)),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// protectedBy identifies the workspace in AnnotationScaleDownDisabledBy.
+func protectedBy(ws *kaitov1beta1.Workspace) string {
+	return ws.Namespace + "/" + ws.Name
+}
@@ -0,0 +1,167 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterautoscaler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/nodes"
+)
+
+const testInstanceType = "Standard_NC24ads_A100_v4"
+
+func newTestWorkspace(targetNodeCount int32) *kaitov1beta1.Workspace {
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", UID: "uid"},
+		Resource: kaitov1beta1.ResourceSpec{
+			InstanceType:  testInstanceType,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"apps": "llm"}},
+		},
+		Status: kaitov1beta1.WorkspaceStatus{TargetNodeCount: targetNodeCount},
+	}
+}
+
+func newTestNode(name string, gpus int64) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"apps": "llm", corev1.LabelInstanceTypeStable: testInstanceType},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Allocatable: corev1.ResourceList{
+				nodes.CapacityNvidiaGPU: *resource.NewQuantity(gpus, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func newTestProvisioner(t *testing.T, objs ...client.Object) (*ClusterAutoscalerProvisioner, client.Client) {
+	t.Helper()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return NewClusterAutoscalerProvisioner(c), c
+}
+
+func TestGeneratePlaceholderDeployment(t *testing.T) {
+	ws := newTestWorkspace(2)
+	ws.Resource.Zones = []string{"eastus-1"}
+	deployment := GeneratePlaceholderDeployment(ws)
+
+	assert.Equal(t, "ws-scale-up", deployment.Name)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	require.Len(t, deployment.OwnerReferences, 1)
+	assert.Equal(t, "ws", deployment.OwnerReferences[0].Name)
+
+	terms := deployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{testInstanceType}},
+		{Key: "apps", Operator: corev1.NodeSelectorOpIn, Values: []string{"llm"}},
+		{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"eastus-1"}},
+	}, terms[0].MatchExpressions)
+
+	antiAffinity := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	require.Len(t, antiAffinity, 1)
+	assert.Equal(t, corev1.LabelHostname, antiAffinity[0].TopologyKey)
+	_, hasWorkspaceLabel := deployment.Spec.Template.Labels[kaitov1beta1.LabelWorkspaceName]
+	assert.False(t, hasWorkspaceLabel, "placeholder pods must not count as workspace pods")
+}
+
+func TestProvisionNodes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates placeholders while nodes are missing", func(t *testing.T) {
+		p, c := newTestProvisioner(t, newTestNode("node-1", 1))
+		require.NoError(t, p.ProvisionNodes(ctx, newTestWorkspace(2)))
+
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws-scale-up"}, deployment))
+		assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	})
+
+	t.Run("nodes without GPUs are not ready", func(t *testing.T) {
+		p, c := newTestProvisioner(t, newTestNode("node-1", 0))
+		require.NoError(t, p.ProvisionNodes(ctx, newTestWorkspace(1)))
+
+		err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws-scale-up"}, &appsv1.Deployment{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("removes placeholders once nodes are ready", func(t *testing.T) {
+		ws := newTestWorkspace(1)
+		p, c := newTestProvisioner(t, newTestNode("node-1", 1), GeneratePlaceholderDeployment(ws))
+		require.NoError(t, p.ProvisionNodes(ctx, ws))
+
+		err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws-scale-up"}, &appsv1.Deployment{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestEnsureNodesReadyAndDeleteNodes(t *testing.T) {
+	ctx := context.Background()
+	ws := newTestWorkspace(2)
+	other := newTestNode("node-other", 1)
+	other.Annotations = map[string]string{
+		AnnotationScaleDownDisabled:                "true",
+		kaitov1beta1.AnnotationScaleDownDisabledBy: "default/other",
+	}
+	p, c := newTestProvisioner(t, newTestNode("node-1", 1), other, GeneratePlaceholderDeployment(ws))
+
+	ready, requeue, err := p.EnsureNodesReady(ctx, ws)
+	require.NoError(t, err)
+	assert.True(t, ready)
+	assert.False(t, requeue)
+
+	node := &corev1.Node{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "node-1"}, node))
+	assert.Equal(t, "true", node.Annotations[AnnotationScaleDownDisabled])
+	assert.Equal(t, "default/ws", node.Annotations[kaitov1beta1.AnnotationScaleDownDisabledBy])
+	err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws-scale-up"}, &appsv1.Deployment{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	require.NoError(t, p.DeleteNodes(ctx, ws))
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "node-1"}, node))
+	assert.NotContains(t, node.Annotations, AnnotationScaleDownDisabled)
+	assert.NotContains(t, node.Annotations, kaitov1beta1.AnnotationScaleDownDisabledBy)
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "node-other"}, node))
+	assert.Equal(t, "default/other", node.Annotations[kaitov1beta1.AnnotationScaleDownDisabledBy], "nodes protected by another workspace are kept")
+}
+
+func TestCollectNodeStatusInfo(t *testing.T) {
+	p, _ := newTestProvisioner(t, newTestNode("node-1", 1))
+	conditions, err := p.CollectNodeStatusInfo(context.Background(), newTestWorkspace(2))
+	require.NoError(t, err)
+	require.Len(t, conditions, 2)
+	assert.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+	assert.Contains(t, conditions[0].Message, "1 of 2 are ready")
+}
@@ -121,7 +121,7 @@ func (g *AzureGPUProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1b
 		node := &nodeList.Items[i]
 		if nodes.NodeIsReadyAndNotDeleting(node) {
 			readyNodes = append(readyNodes, node)
-			if instanceType, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok && instanceType == ws.Resource.InstanceType {
+			if instanceType, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok && instanceType == ws.EffectiveInstanceType() {
 				readyWithInstanceType++
 			}
 		}
@@ -192,7 +192,7 @@ func (g *AzureGPUProvisioner) CollectNodeStatusInfo(ctx context.Context, ws *kai
 		node := &nodeList.Items[i]
 		if nodes.NodeIsReadyAndNotDeleting(node) {
 			readyNodes = append(readyNodes, node)
-			if it, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok && it == ws.Resource.InstanceType {
+			if it, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok && it == ws.EffectiveInstanceType() {
 				readyWithInstanceType++
 			}
 		}
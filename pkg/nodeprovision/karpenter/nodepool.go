@@ -68,17 +68,24 @@ func isInferenceSetWorkspace(ws *kaitov1beta1.Workspace) bool {
 }
 
 // nodePoolRequirements builds the NodePool requirements list.
-// The instance-type requirement is always included. Provider-specific
-// requirements (e.g. Azure placement scope) are added based on the
-// NodeClassConfig group.
+// The instance-type requirement is always included, and a zone requirement
+// when the workspace lists zones. Provider-specific requirements (e.g. Azure
+// placement scope) are added based on the NodeClassConfig group.
 func nodePoolRequirements(ws *kaitov1beta1.Workspace, cfg NodeClassConfig) []karpenterv1.NodeSelectorRequirementWithMinValues {
 	reqs := []karpenterv1.NodeSelectorRequirementWithMinValues{
 		{
 			Key:      corev1.LabelInstanceTypeStable,
 			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{ws.Resource.InstanceType},
+			Values:   []string{ws.EffectiveInstanceType()},
 		},
 	}
+	if len(ws.Resource.Zones) > 0 {
+		reqs = append(reqs, karpenterv1.NodeSelectorRequirementWithMinValues{
+			Key:      corev1.LabelTopologyZone,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   ws.Resource.Zones,
+		})
+	}
 	// Azure Karpenter requires regional placement scope.
 	if cfg.Group == "karpenter.azure.com" {
 		reqs = append(reqs, karpenterv1.NodeSelectorRequirementWithMinValues{
@@ -119,6 +126,8 @@ func generateNodePool(ws *kaitov1beta1.Workspace, cfg NodeClassConfig) *karpente
 		templateLabels[consts.KarpenterInferenceSetKey] = ws.Labels[consts.WorkspaceCreatedByInferenceSetLabel]
 		templateLabels[consts.KarpenterInferenceSetNamespaceKey] = ws.Namespace
 	}
+	// Labels the workspace propagates to its NodeClaims never replace the ones above.
+	templateLabels = kaitov1beta1.MergePropagated(templateLabels, ws.PropagatedLabels(kaitov1beta1.MetadataPropagationTargetNodeClaim))
 
 	// NodePool-level labels for management and lookup.
 	nodePoolLabels := map[string]string{
@@ -142,7 +151,8 @@ func generateNodePool(ws *kaitov1beta1.Workspace, cfg NodeClassConfig) *karpente
 			Replicas: lo.ToPtr(int64(ws.Status.TargetNodeCount)),
 			Template: karpenterv1.NodeClaimTemplate{
 				ObjectMeta: karpenterv1.ObjectMeta{
-					Labels: templateLabels,
+					Labels:      templateLabels,
+					Annotations: ws.PropagatedAnnotations(kaitov1beta1.MetadataPropagationTargetNodeClaim),
 				},
 				Spec: karpenterv1.NodeClaimTemplateSpec{
 					NodeClassRef: &karpenterv1.NodeClassReference{
@@ -235,6 +235,32 @@ func TestGenerateNodePool_WithAnnotation(t *testing.T) {
 	assert.Equal(t, "image-family-azure-linux", np.Spec.Template.Spec.NodeClassRef.Name)
 }
 
+func TestGenerateNodePool_MetadataPropagation(t *testing.T) {
+	ws := newTestWorkspace("default", "ws1", "Standard_NC24ads_A100_v4", 1, nil, map[string]string{"owner": "search"})
+	ws.Labels = map[string]string{"cost-center": "42", consts.KarpenterWorkspaceNameKey: "other"}
+	ws.MetadataPropagation = &kaitov1beta1.MetadataPropagationSpec{
+		Labels:      []string{"cost-center", consts.KarpenterWorkspaceNameKey},
+		Annotations: []string{"owner"},
+	}
+	np := generateNodePool(ws, testConfig)
+
+	assert.Equal(t, "42", np.Spec.Template.Labels["cost-center"])
+	assert.Equal(t, "ws1", np.Spec.Template.Labels[consts.KarpenterWorkspaceNameKey])
+	assert.Equal(t, "search", np.Spec.Template.Annotations["owner"])
+}
+
+func TestGenerateNodePool_Zones(t *testing.T) {
+	ws := newTestWorkspace("default", "ws1", "Standard_NC24ads_A100_v4", 1, nil, nil)
+	ws.Resource.Zones = []string{"eastus-1", "eastus-3"}
+	np := generateNodePool(ws, testConfig)
+
+	assert.Equal(t, 3, len(np.Spec.Template.Spec.Requirements))
+	zoneReq := np.Spec.Template.Spec.Requirements[1]
+	assert.Equal(t, corev1.LabelTopologyZone, zoneReq.Key)
+	assert.Equal(t, corev1.NodeSelectorOpIn, zoneReq.Operator)
+	assert.DeepEqual(t, []string{"eastus-1", "eastus-3"}, zoneReq.Values)
+}
+
 func TestGenerateNodePool_CustomCloudConfig(t *testing.T) {
 	cfg := NodeClassConfig{
 		Group:       "karpenter.k8s.aws",
@@ -21,11 +21,13 @@ import (
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -42,6 +44,9 @@ import (
 	"github.com/kaito-project/kaito/pkg/workspace/resource"
 )
 
+// awsNodeClassGroup is the API group of the AWS EC2NodeClass.
+const awsNodeClassGroup = "karpenter.k8s.aws"
+
 // NodeClassConfig holds cloud-specific NodeClass reference info.
 // Group, Kind, Version, and ResourceName are injected via CLI flags.
 // DefaultName is derived by Start() from ConfigMap labels.
@@ -193,6 +198,47 @@ func (p *KarpenterProvisioner) checkNodeClassReady(ctx context.Context, name str
 	return fmt.Errorf("NodeClass %q exists but is not Ready", name)
 }
 
+// checkNodeClassZones verifies that an AWS EC2NodeClass selects a subnet in each of the
+// zones. Karpenter on AWS can only launch nodes into the zones of the subnets of the
+// NodeClass, so a zone without one would never be used. Other providers have no subnets
+// per zone, and their NodeClasses are not checked.
+func (p *KarpenterProvisioner) checkNodeClassZones(ctx context.Context, name string, zones []string) error {
+	if p.nodeClassConfig.Group != awsNodeClassGroup || len(zones) == 0 {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   p.nodeClassConfig.Group,
+		Version: p.nodeClassConfig.Version,
+		Kind:    p.nodeClassConfig.Kind,
+	})
+	if err := p.client.Get(ctx, types.NamespacedName{Name: name}, obj); err != nil {
+		return fmt.Errorf("getting NodeClass %q: %w", name, err)
+	}
+	subnets, _, err := unstructured.NestedSlice(obj.Object, "status", "subnets")
+	if err != nil {
+		return fmt.Errorf("reading subnets for NodeClass %q: %w", name, err)
+	}
+	covered := sets.New[string]()
+	for _, s := range subnets {
+		if subnet, ok := s.(map[string]interface{}); ok {
+			if zone, ok := subnet["zone"].(string); ok {
+				covered.Insert(zone)
+			}
+		}
+	}
+	var missing []string
+	for _, zone := range zones {
+		if !covered.Has(zone) {
+			missing = append(missing, zone)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("NodeClass %q selects no subnet in zones %v", name, missing)
+	}
+	return nil
+}
+
 // waitForNodeClassReady polls until the NodeClass has a Ready=True condition.
 func (p *KarpenterProvisioner) waitForNodeClassReady(ctx context.Context, name string) error {
 	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 120*time.Second, true, func(ctx context.Context) (bool, error) {
@@ -258,7 +304,7 @@ func (p *KarpenterProvisioner) countCoveredNodes(ctx context.Context, ws *kaitov
 		if !nodes.NodeIsReadyAndNotDeleting(node) {
 			continue
 		}
-		if node.Labels[corev1.LabelInstanceTypeStable] != ws.Resource.InstanceType {
+		if node.Labels[corev1.LabelInstanceTypeStable] != ws.EffectiveInstanceType() {
 			continue
 		}
 		// Skip nodes provisioned for a different workspace that shares this
@@ -306,13 +352,17 @@ func (p *KarpenterProvisioner) countCoveredNodes(ctx context.Context, ws *kaitov
 // ProvisionNodes creates or updates a NodePool for the Workspace.
 // Computes delta-based replicas: desiredReplicas = max(0, targetNodeCount - coveredByNonKarpenterCount).
 // If no NodePool exists and desiredReplicas is 0, no NodePool is created.
-// If a NodePool exists, replicas are only increased (never decreased) to avoid
-// disrupting running karpenter nodes when BYO nodes appear.
+// If a NodePool exists, its requirements are synced with the workspace, so that a change
+// of zones applies to the NodeClaims created afterwards, and replicas are only increased
+// (never decreased) to avoid disrupting running karpenter nodes when BYO nodes appear.
 func (p *KarpenterProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
 	nodeClassName := resolveNodeClassName(ws, p.nodeClassConfig)
 	if err := p.checkNodeClassReady(ctx, nodeClassName); err != nil {
 		return fmt.Errorf("NodeClass %q is not ready: %w", nodeClassName, err)
 	}
+	if err := p.checkNodeClassZones(ctx, nodeClassName, ws.Resource.Zones); err != nil {
+		return err
+	}
 
 	// Count non-karpenter ready nodes to compute delta.
 	coveredCount, _, err := p.countCoveredNodes(ctx, ws)
@@ -321,9 +371,6 @@ func (p *KarpenterProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1be
 	}
 
 	desiredReplicas := int64(ws.Status.TargetNodeCount) - int64(coveredCount)
-	if desiredReplicas <= 0 {
-		return nil
-	}
 
 	nodePoolName := NodePoolName(ws.Namespace, ws.Name)
 	existing := &karpenterv1.NodePool{}
@@ -332,6 +379,9 @@ func (p *KarpenterProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1be
 		if !apierrors.IsNotFound(err) {
 			return fmt.Errorf("getting NodePool %q: %w", nodePoolName, err)
 		}
+		if desiredReplicas <= 0 {
+			return nil
+		}
 		np := generateNodePool(ws, p.nodeClassConfig)
 		np.Spec.Replicas = lo.ToPtr(desiredReplicas)
 		if err := p.client.Create(ctx, np); err != nil {
@@ -344,22 +394,31 @@ func (p *KarpenterProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1be
 		return nil
 	}
 
-	// NodePool exists — only increase replicas, never decrease.
+	// NodePool exists — sync the requirements, and only increase replicas, never decrease.
 	// This protects running karpenter nodes when BYO nodes appear after provisioning.
+	// Karpenter applies changed requirements to new NodeClaims and replaces the existing
+	// ones that no longer match them through drift.
+	requirements := nodePoolRequirements(ws, p.nodeClassConfig)
+	requirementsChanged := !equality.Semantic.DeepEqual(existing.Spec.Template.Spec.Requirements, requirements)
 	currentReplicas := int64(0)
 	if existing.Spec.Replicas != nil {
 		currentReplicas = *existing.Spec.Replicas
 	}
-	if desiredReplicas <= currentReplicas {
+	replicasIncreased := desiredReplicas > currentReplicas
+	if !requirementsChanged && !replicasIncreased {
 		return nil
 	}
-	existing.Spec.Replicas = lo.ToPtr(desiredReplicas)
+	existing.Spec.Template.Spec.Requirements = requirements
+	if replicasIncreased {
+		existing.Spec.Replicas = lo.ToPtr(desiredReplicas)
+	}
 	if err := p.client.Update(ctx, existing); err != nil {
-		return fmt.Errorf("updating NodePool %q replicas to %d: %w", nodePoolName, desiredReplicas, err)
+		return fmt.Errorf("updating NodePool %q: %w", nodePoolName, err)
 	}
-	klog.InfoS("Updated NodePool replicas",
+	klog.InfoS("Updated NodePool",
 		"nodePool", nodePoolName,
-		"desiredReplicas", desiredReplicas,
+		"requirementsChanged", requirementsChanged,
+		"replicas", lo.FromPtr(existing.Spec.Replicas),
 		"coveredByNonKarpenter", coveredCount,
 		"targetNodeCount", ws.Status.TargetNodeCount)
 	return nil
@@ -382,6 +382,78 @@ func TestProvisionNodes_UsesAnnotationNodeClassName(t *testing.T) {
 	assert.Equal(t, "image-family-azure-linux", np.Spec.Template.Spec.NodeClassRef.Name)
 }
 
+func TestProvisionNodes_SyncsRequirementsOfExistingNodePool(t *testing.T) {
+	nodeClass := makeNodeClassUnstructured("image-family-ubuntu")
+	byo1 := makeReadyNode("byo-1", "Standard_NC24ads_A100_v4", nil)
+	byo2 := makeReadyNode("byo-2", "Standard_NC24ads_A100_v4", nil)
+	existingNP := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-ws1"},
+		Spec:       karpenterv1.NodePoolSpec{Replicas: lo.ToPtr(int64(2))},
+	}
+	c := newFakeClient(nodeClass, byo1, byo2, existingNP)
+
+	p := NewKarpenterProvisioner(c, testConfig)
+	// The BYO nodes cover the target, so only the zones change.
+	ws := newTestWorkspace("default", "ws1", "Standard_NC24ads_A100_v4", 2, nil, nil)
+	ws.Resource.Zones = []string{"eastus-2"}
+
+	err := p.ProvisionNodes(context.Background(), ws)
+	require.NoError(t, err)
+
+	np := &karpenterv1.NodePool{}
+	err = c.Get(context.Background(), client.ObjectKey{Name: "default-ws1"}, np)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), *np.Spec.Replicas)
+	assert.Equal(t, nodePoolRequirements(ws, testConfig), np.Spec.Template.Spec.Requirements)
+}
+
+func TestProvisionNodes_AWSNodeClassZones(t *testing.T) {
+	awsConfig := NodeClassConfig{
+		Group:        "karpenter.k8s.aws",
+		Kind:         "EC2NodeClass",
+		Version:      "v1",
+		ResourceName: "ec2nodeclasses",
+		DefaultName:  "default",
+	}
+	nodeClass := &unstructured.Unstructured{}
+	nodeClass.SetGroupVersionKind(schema.GroupVersionKind{Group: awsConfig.Group, Version: awsConfig.Version, Kind: awsConfig.Kind})
+	nodeClass.SetName("default")
+	nodeClass.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		},
+		"subnets": []interface{}{
+			map[string]interface{}{"id": "subnet-a", "zone": "us-west-2a"},
+			map[string]interface{}{"id": "subnet-b", "zone": "us-west-2b"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		zones   []string
+		wantErr string
+	}{
+		{name: "no zones"},
+		{name: "zones with subnets", zones: []string{"us-west-2a", "us-west-2b"}},
+		{name: "zone without subnet", zones: []string{"us-west-2a", "us-west-2c"}, wantErr: `NodeClass "default" selects no subnet in zones [us-west-2c]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(nodeClass.DeepCopy())
+			p := NewKarpenterProvisioner(c, awsConfig)
+			ws := newTestWorkspace("default", "ws1", "g5.2xlarge", 1, nil, nil)
+			ws.Resource.Zones = tt.zones
+
+			err := p.ProvisionNodes(context.Background(), ws)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 // --- DeleteNodes tests ---
 
 func TestDeleteNodes_Success(t *testing.T) {
@@ -0,0 +1,63 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/breaker"
+)
+
+// breakerProvisioner stops calling an auto-provisioner while its cloud-dependent calls keep
+// failing. Calls that create or delete nodes are not retried in place, since a timed out
+// request may still have taken effect; the reconciler requeues them once it has observed
+// the result. Read-only calls go straight to the provisioner.
+type breakerProvisioner struct {
+	nodeprovision.NodeProvisioner
+	breaker *breaker.Breaker
+}
+
+var _ nodeprovision.NodeProvisioner = (*breakerProvisioner)(nil)
+
+func (p *breakerProvisioner) Start(ctx context.Context) error {
+	return p.breaker.Do(ctx, p.NodeProvisioner.Start)
+}
+
+func (p *breakerProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.breaker.DoOnce(ctx, func(ctx context.Context) error {
+		return p.NodeProvisioner.ProvisionNodes(ctx, ws)
+	})
+}
+
+func (p *breakerProvisioner) DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.breaker.DoOnce(ctx, func(ctx context.Context) error {
+		return p.NodeProvisioner.DeleteNodes(ctx, ws)
+	})
+}
+
+func (p *breakerProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.Workspace) (ready bool, needRequeue bool, err error) {
+	err = p.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		ready, needRequeue, err = p.NodeProvisioner.EnsureNodesReady(ctx, ws)
+		return err
+	})
+	return ready, needRequeue, err
+}
+
+// withCircuitBreaker guards the auto-provisioner with the shared node-provisioner breaker.
+func withCircuitBreaker(auto nodeprovision.NodeProvisioner) nodeprovision.NodeProvisioner {
+	return &breakerProvisioner{NodeProvisioner: auto, breaker: breaker.Get(nodeprovision.BreakerName)}
+}
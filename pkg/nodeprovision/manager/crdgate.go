@@ -0,0 +1,129 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
+)
+
+// ReasonNodeClaimCRDNotInstalled is the NodeStatus reason reported for workspaces that do
+// not have enough matching nodes while the NodeClaim CRD is not installed.
+const ReasonNodeClaimCRDNotInstalled = "NodeClaimCRDNotInstalled"
+
+// crdGateProvisioner routes workspaces to the BYO provisioner until the NodeClaim CRD is
+// installed and the auto-provisioner has started, so the controller keeps serving
+// workspaces on existing nodes in clusters without Karpenter.
+type crdGateProvisioner struct {
+	auto    nodeprovision.NodeProvisioner
+	byo     nodeprovision.NodeProvisioner
+	crd     *crdwatch.Watcher
+	started atomic.Bool
+}
+
+var _ nodeprovision.NodeProvisioner = (*crdGateProvisioner)(nil)
+
+func (p *crdGateProvisioner) current() nodeprovision.NodeProvisioner {
+	if p.started.Load() {
+		return p.auto
+	}
+	return p.byo
+}
+
+// Name returns the name of the underlying auto-provisioner.
+func (p *crdGateProvisioner) Name() string { return p.auto.Name() }
+
+// Start starts the auto-provisioner if the NodeClaim CRD is installed. Otherwise it
+// defers the start until the CRD watcher sees the CRD.
+func (p *crdGateProvisioner) Start(ctx context.Context) error {
+	installed, err := p.crd.Check(ctx)
+	if err != nil {
+		return err
+	}
+	if installed {
+		return p.startAuto(ctx)
+	}
+	klog.InfoS("NodeClaim CRD is not installed, only existing nodes are used until it is",
+		"nodeProvisioner", p.auto.Name())
+	p.crd.OnAvailable(p.startAuto)
+	return nil
+}
+
+func (p *crdGateProvisioner) startAuto(ctx context.Context) error {
+	if err := p.auto.Start(ctx); err != nil {
+		return err
+	}
+	p.started.Store(true)
+	klog.InfoS("Node auto-provisioning is enabled", "nodeProvisioner", p.auto.Name())
+	return nil
+}
+
+func (p *crdGateProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.current().ProvisionNodes(ctx, ws)
+}
+
+func (p *crdGateProvisioner) DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.current().DeleteNodes(ctx, ws)
+}
+
+func (p *crdGateProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.Workspace) (bool, bool, error) {
+	return p.current().EnsureNodesReady(ctx, ws)
+}
+
+func (p *crdGateProvisioner) EnableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return p.current().EnableDriftRemediation(ctx, workspaceNamespace, workspaceName)
+}
+
+func (p *crdGateProvisioner) DisableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return p.current().DisableDriftRemediation(ctx, workspaceNamespace, workspaceName)
+}
+
+func (p *crdGateProvisioner) CollectNodeStatusInfo(ctx context.Context, ws *kaitov1beta1.Workspace) ([]metav1.Condition, error) {
+	if p.started.Load() {
+		return p.auto.CollectNodeStatusInfo(ctx, ws)
+	}
+	conds, err := p.byo.CollectNodeStatusInfo(ctx, ws)
+	if err != nil {
+		return conds, err
+	}
+	for i := range conds {
+		if conds[i].Type == string(kaitov1beta1.ConditionTypeNodeStatus) && conds[i].Status == metav1.ConditionFalse {
+			conds[i].Reason = ReasonNodeClaimCRDNotInstalled
+			conds[i].Message = "Not enough Nodes match the label selector and nodes cannot be auto-provisioned because the NodeClaim CRD is not installed; " +
+				"add matching Nodes or install Karpenter to continue"
+		}
+	}
+	return conds, nil
+}
+
+func (p *crdGateProvisioner) BuildNodeSelector(ctx context.Context, ws *kaitov1beta1.Workspace) []corev1.NodeSelectorRequirement {
+	return p.current().BuildNodeSelector(ctx, ws)
+}
+
+// withNodeClaimCRD gates an auto-provisioner that needs the NodeClaim CRD. Without a CRD
+// watcher the auto-provisioner is returned as is.
+func withNodeClaimCRD(auto nodeprovision.NodeProvisioner, cfg ProvisionerConfig) nodeprovision.NodeProvisioner {
+	if cfg.NodeClaimCRD == nil {
+		return auto
+	}
+	return &crdGateProvisioner{auto: auto, byo: newBYOProvisioner(cfg), crd: cfg.NodeClaimCRD}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+)
+
+func TestCRDGateProvisioner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	installed := false
+	crd := crdwatch.NewWithCheck(nodeclaim.GVK, func(context.Context) (bool, error) {
+		return installed, nil
+	})
+	auto := &fakeAutoProvisioner{}
+	p := withNodeClaimCRD(auto, ProvisionerConfig{KClient: cl, NodeClaimCRD: crd})
+	assert.Equal(t, "fake-auto", p.Name())
+
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource: kaitov1beta1.ResourceSpec{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+		},
+		Status: kaitov1beta1.WorkspaceStatus{TargetNodeCount: 1},
+	}
+
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.ProvisionNodes(context.Background(), ws))
+	assert.Empty(t, auto.provisioned, "workspaces should use existing nodes until the CRD is installed")
+
+	conds, err := p.CollectNodeStatusInfo(context.Background(), ws)
+	require.NoError(t, err)
+	found := false
+	for _, c := range conds {
+		if c.Type == string(kaitov1beta1.ConditionTypeNodeStatus) {
+			found = true
+			assert.Equal(t, metav1.ConditionFalse, c.Status)
+			assert.Equal(t, ReasonNodeClaimCRDNotInstalled, c.Reason)
+		}
+	}
+	assert.True(t, found)
+
+	// The watcher starts the auto-provisioner once the CRD is installed.
+	installed = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, crd.Start(ctx))
+	assert.True(t, crd.Available())
+
+	require.NoError(t, p.ProvisionNodes(context.Background(), ws))
+	assert.Equal(t, []string{"ws"}, auto.provisioned)
+}
+
+func TestCRDGateProvisionerInstalledAtStart(t *testing.T) {
+	crd := crdwatch.NewWithCheck(nodeclaim.GVK, func(context.Context) (bool, error) {
+		return true, nil
+	})
+	auto := &fakeAutoProvisioner{}
+	p := withNodeClaimCRD(auto, ProvisionerConfig{NodeClaimCRD: crd})
+
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, p.ProvisionNodes(context.Background(), &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}))
+	assert.Equal(t, []string{"ws"}, auto.provisioned)
+}
+
+func TestWithNodeClaimCRDWithoutWatcher(t *testing.T) {
+	auto := &fakeAutoProvisioner{}
+	assert.Same(t, auto, withNodeClaimCRD(auto, ProvisionerConfig{}))
+}
@@ -19,10 +19,12 @@ import (
 
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
 	byoprovisioner "github.com/kaito-project/kaito/pkg/nodeprovision/byo-provisioner"
+	clusterautoscaler "github.com/kaito-project/kaito/pkg/nodeprovision/cluster-autoscaler"
 	gpuprovisioner "github.com/kaito-project/kaito/pkg/nodeprovision/gpu-provisioner"
 	karpenterprov "github.com/kaito-project/kaito/pkg/nodeprovision/karpenter"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
 	"github.com/kaito-project/kaito/pkg/workspace/resource"
 )
 
@@ -37,14 +39,22 @@ type ProvisionerConfig struct {
 	NodeClassKind          string
 	NodeClassVersion       string
 	NodeClassResourceName  string
+	// NodeClaimCRD tracks whether the NodeClaim CRD is installed. When set, provisioners
+	// that need it only use existing nodes until it is.
+	NodeClaimCRD *crdwatch.Watcher
+	// BootstrapDevicePlugin makes BYO nodes without the NVIDIA device plugin get it from the
+	// bootstrap DaemonSets of the chart.
+	BootstrapDevicePlugin bool
 }
 
 // NewNodeProvisioner creates and returns a NodeProvisioner based on the provisionerType parameter.
 //
 //   - karpenter: KarpenterProvisioner (cloud-agnostic karpenter NodePool CRUD).
 //   - byo: BYOProvisioner (all provisioning ops are no-ops).
-//   - azure-gpu-provisioner (default): AzureGPUProvisioner (creates/deletes NodeClaims).
-func NewNodeProvisioner(cfg ProvisionerConfig) nodeprovision.NodeProvisioner {
+//   - azure-gpu-provisioner: AzureGPUProvisioner (creates/deletes NodeClaims).
+//   - cluster-autoscaler: ClusterAutoscalerProvisioner (scales node groups with placeholder pods).
+//   - any other name: the provisioner plugin registered under that name (see RegisterPlugin).
+func NewNodeProvisioner(cfg ProvisionerConfig) (nodeprovision.NodeProvisioner, error) {
 	switch cfg.ProvisionerType {
 	case consts.NodeProvisionerKarpenter:
 		ncCfg := karpenterprov.NodeClassConfig{
@@ -53,14 +63,32 @@ func NewNodeProvisioner(cfg ProvisionerConfig) nodeprovision.NodeProvisioner {
 			Version:      cfg.NodeClassVersion,
 			ResourceName: cfg.NodeClassResourceName,
 		}
-		return karpenterprov.NewKarpenterProvisioner(cfg.DirectClient, ncCfg)
+		return withProvisioningPolicy(withNodeClaimCRD(withCircuitBreaker(karpenterprov.NewKarpenterProvisioner(cfg.DirectClient, ncCfg)), cfg), cfg), nil
 	case consts.NodeProvisionerBYO:
-		return byoprovisioner.NewBYOProvisioner(cfg.KClient)
-	default: // consts.NodeProvisionerAzureGPU
+		return newBYOProvisioner(cfg), nil
+	case consts.NodeProvisionerAzureGPU:
 		expectations := utils.NewControllerExpectations()
 		ncm := resource.NewNodeClaimManager(cfg.KClient, cfg.Recorder, expectations)
 		ncm.SetDefaultNodeImageFamily(cfg.DefaultNodeImageFamily)
 		nm := resource.NewNodeManager(cfg.KClient)
-		return gpuprovisioner.NewAzureGPUProvisioner(ncm, nm)
+		return withProvisioningPolicy(withNodeClaimCRD(withCircuitBreaker(gpuprovisioner.NewAzureGPUProvisioner(ncm, nm)), cfg), cfg), nil
+	case consts.NodeProvisionerClusterAutoscaler:
+		return withProvisioningPolicy(clusterautoscaler.NewClusterAutoscalerProvisioner(cfg.KClient), cfg), nil
+	default:
+		return newPluginProvisioner(cfg)
 	}
 }
+
+// withProvisioningPolicy lets workspaces with a non-Auto ProvisioningPolicy fall back to
+// BYO behavior while other workspaces keep using the auto-provisioner.
+func withProvisioningPolicy(auto nodeprovision.NodeProvisioner, cfg ProvisionerConfig) nodeprovision.NodeProvisioner {
+	return &policyProvisioner{auto: auto, byo: newBYOProvisioner(cfg)}
+}
+
+// newBYOProvisioner returns the BYOProvisioner used on its own and as the fallback of the
+// auto-provisioners.
+func newBYOProvisioner(cfg ProvisionerConfig) *byoprovisioner.BYOProvisioner {
+	p := byoprovisioner.NewBYOProvisioner(cfg.KClient)
+	p.SetBootstrapDevicePlugin(cfg.BootstrapDevicePlugin)
+	return p
+}
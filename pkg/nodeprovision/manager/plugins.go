@@ -0,0 +1,92 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// PluginFactory creates an out-of-tree NodeProvisioner from the controller configuration.
+type PluginFactory func(cfg ProvisionerConfig) (nodeprovision.NodeProvisioner, error)
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   = map[string]PluginFactory{}
+)
+
+// RegisterPlugin registers an out-of-tree node provisioner, which is then selected with
+// --node-provisioner=<name>. Plugins register themselves from an init function in a
+// package that is linked into the controller with a build tag, e.g.
+//
+//	//go:build maas
+//
+//	package main
+//
+//	import _ "example.com/kaito-maas-provisioner"
+//
+// Plugin provisioners are auto-provisioners: they are guarded by the node-provisioner
+// circuit breaker and workspaces with a non-Auto provisioning policy keep using BYO nodes.
+// RegisterPlugin panics if the name is empty, built in or already registered.
+func RegisterPlugin(name string, factory PluginFactory) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	switch name {
+	case "", consts.NodeProvisionerAzureGPU, consts.NodeProvisionerKarpenter, consts.NodeProvisionerBYO, consts.NodeProvisionerClusterAutoscaler:
+		panic(fmt.Sprintf("invalid node provisioner plugin name %q", name))
+	}
+	if _, ok := plugins[name]; ok {
+		panic(fmt.Sprintf("node provisioner plugin %q is already registered", name))
+	}
+	plugins[name] = factory
+}
+
+// IsPlugin reports whether name is a registered node provisioner plugin.
+func IsPlugin(name string) bool {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	_, ok := plugins[name]
+	return ok
+}
+
+// Plugins returns the names of the registered node provisioner plugins, sorted.
+func Plugins() []string {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newPluginProvisioner(cfg ProvisionerConfig) (nodeprovision.NodeProvisioner, error) {
+	pluginsMu.RLock()
+	factory, ok := plugins[cfg.ProvisionerType]
+	pluginsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported node provisioner %q; built in: %s, %s, %s, %s; plugins: %v",
+			cfg.ProvisionerType, consts.NodeProvisionerAzureGPU, consts.NodeProvisionerKarpenter, consts.NodeProvisionerBYO, consts.NodeProvisionerClusterAutoscaler, Plugins())
+	}
+	p, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating node provisioner plugin %q: %w", cfg.ProvisionerType, err)
+	}
+	return withProvisioningPolicy(withCircuitBreaker(p), cfg), nil
+}
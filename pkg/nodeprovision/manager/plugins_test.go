@@ -0,0 +1,74 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestRegisterPlugin(t *testing.T) {
+	factory := func(ProvisionerConfig) (nodeprovision.NodeProvisioner, error) { return &fakeAutoProvisioner{}, nil }
+
+	RegisterPlugin("test-register", factory)
+	assert.True(t, IsPlugin("test-register"))
+	assert.Contains(t, Plugins(), "test-register")
+	assert.False(t, IsPlugin(consts.NodeProvisionerKarpenter))
+
+	assert.Panics(t, func() { RegisterPlugin("test-register", factory) })
+	assert.Panics(t, func() { RegisterPlugin("", factory) })
+	assert.Panics(t, func() { RegisterPlugin(consts.NodeProvisionerBYO, factory) })
+}
+
+func TestNewNodeProvisionerPlugin(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	plugin := &fakeAutoProvisioner{}
+	RegisterPlugin("test-maas", func(cfg ProvisionerConfig) (nodeprovision.NodeProvisioner, error) {
+		assert.Equal(t, "test-maas", cfg.ProvisionerType)
+		return plugin, nil
+	})
+	RegisterPlugin("test-broken", func(ProvisionerConfig) (nodeprovision.NodeProvisioner, error) {
+		return nil, errors.New("MAAS endpoint is not configured")
+	})
+
+	p, err := NewNodeProvisioner(ProvisionerConfig{KClient: cl, ProvisionerType: "test-maas"})
+	require.NoError(t, err)
+	assert.Equal(t, "fake-auto", p.Name())
+
+	// Workspaces with the Auto policy go to the plugin.
+	ws := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+	require.NoError(t, p.ProvisionNodes(context.Background(), ws))
+	assert.Equal(t, []string{"ws"}, plugin.provisioned)
+
+	_, err = NewNodeProvisioner(ProvisionerConfig{KClient: cl, ProvisionerType: "test-broken"})
+	assert.ErrorContains(t, err, "MAAS endpoint is not configured")
+
+	_, err = NewNodeProvisioner(ProvisionerConfig{KClient: cl, ProvisionerType: "openstack"})
+	assert.ErrorContains(t, err, `unsupported node provisioner "openstack"`)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+)
+
+// ReasonNodeProvisioningDisabled is the NodeStatus reason reported for workspaces with
+// ProvisioningPolicy Never that do not have enough matching nodes.
+const ReasonNodeProvisioningDisabled = "NodeProvisioningDisabled"
+
+// policyProvisioner routes each Workspace to the auto-provisioner or the BYO provisioner
+// based on its ProvisioningPolicy, so individual workspaces can opt out of node
+// auto-provisioning while the rest of the cluster keeps it.
+type policyProvisioner struct {
+	auto nodeprovision.NodeProvisioner
+	byo  nodeprovision.NodeProvisioner
+}
+
+var _ nodeprovision.NodeProvisioner = (*policyProvisioner)(nil)
+
+func (p *policyProvisioner) forWorkspace(ws *kaitov1beta1.Workspace) nodeprovision.NodeProvisioner {
+	if workspace.IsNodeAutoProvisioningDisabled(&ws.Resource) {
+		return p.byo
+	}
+	return p.auto
+}
+
+// Name returns the name of the underlying auto-provisioner.
+func (p *policyProvisioner) Name() string { return p.auto.Name() }
+
+// Start starts the underlying auto-provisioner.
+func (p *policyProvisioner) Start(ctx context.Context) error { return p.auto.Start(ctx) }
+
+func (p *policyProvisioner) ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.forWorkspace(ws).ProvisionNodes(ctx, ws)
+}
+
+func (p *policyProvisioner) DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error {
+	return p.forWorkspace(ws).DeleteNodes(ctx, ws)
+}
+
+func (p *policyProvisioner) EnsureNodesReady(ctx context.Context, ws *kaitov1beta1.Workspace) (bool, bool, error) {
+	return p.forWorkspace(ws).EnsureNodesReady(ctx, ws)
+}
+
+// EnableDriftRemediation only applies to auto-provisioned nodes; the workspace identity
+// is not enough to route, so it always goes to the auto-provisioner, which ignores
+// workspaces it does not manage.
+func (p *policyProvisioner) EnableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return p.auto.EnableDriftRemediation(ctx, workspaceNamespace, workspaceName)
+}
+
+// DisableDriftRemediation mirrors EnableDriftRemediation.
+func (p *policyProvisioner) DisableDriftRemediation(ctx context.Context, workspaceNamespace, workspaceName string) error {
+	return p.auto.DisableDriftRemediation(ctx, workspaceNamespace, workspaceName)
+}
+
+func (p *policyProvisioner) CollectNodeStatusInfo(ctx context.Context, ws *kaitov1beta1.Workspace) ([]metav1.Condition, error) {
+	conds, err := p.forWorkspace(ws).CollectNodeStatusInfo(ctx, ws)
+	if err != nil || ws.Resource.ProvisioningPolicy != kaitov1beta1.ProvisioningPolicyNever {
+		return conds, err
+	}
+	for i := range conds {
+		if conds[i].Type == string(kaitov1beta1.ConditionTypeNodeStatus) && conds[i].Status == metav1.ConditionFalse {
+			conds[i].Reason = ReasonNodeProvisioningDisabled
+			conds[i].Message = "Not enough Nodes match the label selector and provisioningPolicy is Never; add matching Nodes to continue"
+		}
+	}
+	return conds, nil
+}
+
+func (p *policyProvisioner) BuildNodeSelector(ctx context.Context, ws *kaitov1beta1.Workspace) []corev1.NodeSelectorRequirement {
+	return p.forWorkspace(ws).BuildNodeSelector(ctx, ws)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	byoprovisioner "github.com/kaito-project/kaito/pkg/nodeprovision/byo-provisioner"
+)
+
+// fakeAutoProvisioner records calls so tests can tell which backend handled a workspace.
+type fakeAutoProvisioner struct {
+	byoprovisioner.BYOProvisioner
+	provisioned []string
+}
+
+func (f *fakeAutoProvisioner) Name() string { return "fake-auto" }
+
+func (f *fakeAutoProvisioner) ProvisionNodes(_ context.Context, ws *kaitov1beta1.Workspace) error {
+	f.provisioned = append(f.provisioned, ws.Name)
+	return nil
+}
+
+func TestPolicyProvisionerRouting(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	auto := &fakeAutoProvisioner{}
+	p := withProvisioningPolicy(auto, ProvisionerConfig{KClient: cl})
+	assert.Equal(t, "fake-auto", p.Name())
+
+	newWorkspace := func(name string, policy kaitov1beta1.ProvisioningPolicy) *kaitov1beta1.Workspace {
+		return &kaitov1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Resource: kaitov1beta1.ResourceSpec{
+				ProvisioningPolicy: policy,
+				LabelSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "byo"}},
+			},
+			Status: kaitov1beta1.WorkspaceStatus{TargetNodeCount: 1},
+		}
+	}
+
+	for _, ws := range []*kaitov1beta1.Workspace{
+		newWorkspace("default-policy", ""),
+		newWorkspace("auto", kaitov1beta1.ProvisioningPolicyAuto),
+		newWorkspace("preferred-only", kaitov1beta1.ProvisioningPolicyPreferredOnly),
+		newWorkspace("never", kaitov1beta1.ProvisioningPolicyNever),
+	} {
+		require.NoError(t, p.ProvisionNodes(context.Background(), ws))
+	}
+	assert.Equal(t, []string{"default-policy", "auto"}, auto.provisioned)
+
+	t.Run("Never reports NodeProvisioningDisabled", func(t *testing.T) {
+		conds, err := p.CollectNodeStatusInfo(context.Background(), newWorkspace("never", kaitov1beta1.ProvisioningPolicyNever))
+		require.NoError(t, err)
+		found := false
+		for _, c := range conds {
+			if c.Type == string(kaitov1beta1.ConditionTypeNodeStatus) {
+				found = true
+				assert.Equal(t, metav1.ConditionFalse, c.Status)
+				assert.Equal(t, ReasonNodeProvisioningDisabled, c.Reason)
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("PreferredOnly keeps the BYO reason", func(t *testing.T) {
+		conds, err := p.CollectNodeStatusInfo(context.Background(), newWorkspace("preferred", kaitov1beta1.ProvisioningPolicyPreferredOnly))
+		require.NoError(t, err)
+		for _, c := range conds {
+			if c.Type == string(kaitov1beta1.ConditionTypeNodeStatus) {
+				assert.Equal(t, "NodeNotReady", c.Reason)
+			}
+		}
+	})
+}
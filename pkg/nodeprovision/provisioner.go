@@ -22,6 +22,10 @@ import (
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 )
 
+// BreakerName names the circuit breaker guarding the auto-provisioner, which creates node
+// classes and nodes through the cloud provider.
+const BreakerName = "node-provisioner"
+
 // NodeProvisioner abstracts node provisioning for a Workspace.
 // Callers pass the Workspace object directly — all internal resources
 // (NodePool, NodeClaim, AKSNodeClass) are managed by the implementation.
@@ -33,6 +37,9 @@ import (
 //   - AzureGPUProvisioner: wraps Azure gpu-provisioner (https://github.com/Azure/gpu-provisioner) logic.
 //   - AzureKarpenterProvisioner: uses Azure Karpenter (https://github.com/Azure/karpenter-provider-azure) for node provisioning.
 //   - BYOProvisioner: no-op for BYO mode.
+//
+// Out-of-tree implementations, e.g. for MAAS or OpenStack, are registered as plugins with
+// manager.RegisterPlugin.
 type NodeProvisioner interface {
 	// Name returns the name of this provisioner implementation.
 	Name() string
@@ -48,14 +55,14 @@ type NodeProvisioner interface {
 	//
 	// AzureGPUProvisioner: creates NodeClaims via Azure gpu-provisioner.
 	// KarpenterProvisioner (future): creates NodePool with replicas.
-	// BYOProvisioner: no-op.
+	// BYOProvisioner: labels preferred nodes when kaito.sh/manage-node-labels is set.
 	ProvisionNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error
 
 	// DeleteNodes removes all node resources for the Workspace.
 	//
 	// AzureGPUProvisioner: deletes NodeClaims.
 	// KarpenterProvisioner (future): deletes NodePool (cascades).
-	// BYOProvisioner: no-op.
+	// BYOProvisioner: removes the labels it added to preferred nodes.
 	DeleteNodes(ctx context.Context, ws *kaitov1beta1.Workspace) error
 
 	// EnsureNodesReady checks whether all nodes are ready and fully
@@ -0,0 +1,237 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/awslabs/operatorpkg/status"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/webhook/certificates/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/breaker"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// certExpiryWarning is how long before expiry a webhook certificate fails the check.
+const certExpiryWarning = 24 * time.Hour
+
+// CRDsCheck verifies that the named CRDs are installed and established.
+func CRDsCheck(reader client.Reader, names ...string) Check {
+	return Check{
+		Name:   "crds",
+		Reason: "CRDsInstalled",
+		Run: func(ctx context.Context) (string, error) {
+			if err := crdsEstablished(ctx, reader, names); err != nil {
+				return "", fmt.Errorf("%w; install the KAITO chart with its CRDs", err)
+			}
+			return fmt.Sprintf("CRDs %s are installed", strings.Join(names, ", ")), nil
+		},
+	}
+}
+
+// NodeProvisionerCheck verifies that the CRDs of the selected built-in node provisioner are
+// installed. nodeClassCRD is only checked for the karpenter provisioner.
+func NodeProvisionerCheck(reader client.Reader, provisioner, nodeClassCRD string) Check {
+	return Check{
+		Name:   "node-provisioner",
+		Reason: "NodeProvisionerInstalled",
+		Run: func(ctx context.Context) (string, error) {
+			var crds []string
+			switch provisioner {
+			case consts.NodeProvisionerBYO:
+				return "node auto-provisioning is disabled", nil
+			case consts.NodeProvisionerClusterAutoscaler:
+				return "nodes are added by the cluster autoscaler, which needs no CRDs", nil
+			case consts.NodeProvisionerKarpenter:
+				crds = []string{"nodepools.karpenter.sh", "nodeclaims.karpenter.sh", nodeClassCRD}
+			case consts.NodeProvisionerAzureGPU:
+				crds = []string{"nodeclaims.karpenter.sh"}
+			default:
+				// Plugins verify their own prerequisites when they are started.
+				return fmt.Sprintf("node provisioner plugin %s is started", provisioner), nil
+			}
+			if err := crdsEstablished(ctx, reader, crds); err != nil {
+				return "", fmt.Errorf("%w; install %s before KAITO or set nodeProvisioner to byo or cluster-autoscaler", err, provisioner)
+			}
+			return fmt.Sprintf("%s CRDs are installed", provisioner), nil
+		},
+	}
+}
+
+// CloudProviderCheck verifies that CLOUD_PROVIDER names a supported cloud.
+func CloudProviderCheck() Check {
+	return Check{
+		Name:   "cloud-provider",
+		Reason: "CloudProviderConfigured",
+		Run: func(ctx context.Context) (string, error) {
+			if _, err := sku.GetSKUHandler(); err != nil {
+				return "", fmt.Errorf("%v; set cloudProviderName in the chart values", err)
+			}
+			return fmt.Sprintf("cloud provider is %s", os.Getenv("CLOUD_PROVIDER")), nil
+		},
+	}
+}
+
+// WebhookCertCheck verifies that the webhook certificate in secretName is valid for the
+// webhook Service and is not about to expire.
+func WebhookCertCheck(reader client.Reader, namespace, secretName, serviceName string) Check {
+	return Check{
+		Name:   "webhook-cert",
+		Reason: "WebhookCertValid",
+		Run: func(ctx context.Context) (string, error) {
+			secret := &corev1.Secret{}
+			if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, secret); err != nil {
+				return "", fmt.Errorf("failed to get webhook certificate secret %s/%s: %w", namespace, secretName, err)
+			}
+			cert, err := parseCertificate(secret.Data[resources.ServerCert])
+			if err != nil {
+				return "", fmt.Errorf("webhook certificate secret %s/%s: %w", namespace, secretName, err)
+			}
+			host := fmt.Sprintf("%s.%s.svc", serviceName, namespace)
+			if err := cert.VerifyHostname(host); err != nil {
+				return "", fmt.Errorf("webhook certificate is not valid for %s: %w", host, err)
+			}
+			now := time.Now()
+			if now.Before(cert.NotBefore) {
+				return "", fmt.Errorf("webhook certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+			}
+			if now.Add(certExpiryWarning).After(cert.NotAfter) {
+				return "", fmt.Errorf("webhook certificate expires at %s; delete secret %s/%s to have it regenerated",
+					cert.NotAfter.UTC().Format(time.RFC3339), namespace, secretName)
+			}
+			return fmt.Sprintf("webhook certificate is valid until %s", cert.NotAfter.UTC().Format(time.RFC3339)), nil
+		},
+	}
+}
+
+// GPUCapacityCheck verifies that GPUs can be obtained. Without node auto-provisioning,
+// some node must advertise GPUs. With the cluster autoscaler, nothing is checked since its
+// node groups may scale from zero. Otherwise the node provisioner must be reachable, and
+// when it uses NodeClaims no NodeClaim of KAITO may have failed to launch for lack of quota.
+// Without the NodeClaim CRD, GPUs can only come from nodes running the device plugin.
+func GPUCapacityCheck(reader client.Reader, provisioner string) Check {
+	return Check{
+		Name:   "gpu-capacity",
+		Reason: "GPUCapacityAvailable",
+		Run: func(ctx context.Context) (string, error) {
+			if provisioner == consts.NodeProvisionerBYO {
+				return byoGPUCapacity(ctx, reader)
+			}
+			if provisioner == consts.NodeProvisionerClusterAutoscaler {
+				// Node groups may scale from zero, so no GPU node has to exist yet.
+				return "GPU nodes are added by the cluster autoscaler on demand", nil
+			}
+			if err := breaker.Get(nodeprovision.BreakerName).Check(nil); err != nil {
+				return "", err
+			}
+			if provisioner != consts.NodeProvisionerAzureGPU && provisioner != consts.NodeProvisionerKarpenter {
+				// Plugins provision nodes without NodeClaims.
+				return fmt.Sprintf("node provisioner plugin %s is reachable", provisioner), nil
+			}
+			nodeClaims := &karpenterv1.NodeClaimList{}
+			if err := reader.List(ctx, nodeClaims, client.HasLabels{kaitov1beta1.LabelWorkspaceName}); err != nil {
+				if meta.IsNoMatchError(err) {
+					// The missing CRD is reported by the node provisioner check.
+					return byoGPUCapacity(ctx, reader)
+				}
+				return "", fmt.Errorf("failed to list NodeClaims: %w", err)
+			}
+			for i := range nodeClaims.Items {
+				nc := &nodeClaims.Items[i]
+				if cond := launchedCondition(nc); cond != nil && cond.Status == metav1.ConditionFalse && strings.Contains(strings.ToLower(cond.Message), "quota") {
+					return "", fmt.Errorf("NodeClaim %s failed to launch: %s; request more GPU quota or use another instance type", nc.Name, cond.Message)
+				}
+			}
+			return fmt.Sprintf("%s is reachable and no NodeClaim is short of quota", provisioner), nil
+		},
+	}
+}
+
+func byoGPUCapacity(ctx context.Context, reader client.Reader) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes); err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var gpuNodes int
+	for i := range nodes.Items {
+		if q, ok := nodes.Items[i].Status.Allocatable[corev1.ResourceName(consts.NvidiaGPU)]; ok && !q.IsZero() {
+			gpuNodes++
+		}
+	}
+	if gpuNodes == 0 {
+		return "", fmt.Errorf("no node advertises %s; add GPU nodes and install the NVIDIA device plugin", consts.NvidiaGPU)
+	}
+	return fmt.Sprintf("%d nodes advertise %s", gpuNodes, consts.NvidiaGPU), nil
+}
+
+func launchedCondition(nc *karpenterv1.NodeClaim) *status.Condition {
+	for _, cond := range nc.GetConditions() {
+		if cond.Type == karpenterv1.ConditionTypeLaunched {
+			return &cond
+		}
+	}
+	return nil
+}
+
+func crdsEstablished(ctx context.Context, reader client.Reader, names []string) error {
+	var missing []string
+	for _, name := range names {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return fmt.Errorf("failed to get CRD %s: %w", name, err)
+		}
+		established := false
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
+				established = true
+			}
+		}
+		if !established {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("CRDs %s are not installed", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate in key %s", resources.ServerCert)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
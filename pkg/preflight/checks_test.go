@@ -0,0 +1,221 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/webhook/certificates/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	karpenterutils "github.com/kaito-project/kaito/pkg/utils/karpenter"
+)
+
+func newFakeReader(t *testing.T, objs ...client.Object) client.Reader {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	require.NoError(t, karpenterutils.KarpenterSchemeBuilder.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func crd(name string, established bool) *apiextensionsv1.CustomResourceDefinition {
+	status := apiextensionsv1.ConditionFalse
+	if established {
+		status = apiextensionsv1.ConditionTrue
+	}
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: status}},
+		},
+	}
+}
+
+func TestCRDsCheck(t *testing.T) {
+	reader := newFakeReader(t, crd("workspaces.kaito.sh", true), crd("inferencesets.kaito.sh", false))
+
+	_, err := CRDsCheck(reader, "workspaces.kaito.sh").Run(context.Background())
+	assert.NoError(t, err)
+
+	_, err = CRDsCheck(reader, "workspaces.kaito.sh", "inferencesets.kaito.sh", "modelmirrors.kaito.sh").Run(context.Background())
+	assert.ErrorContains(t, err, "CRDs inferencesets.kaito.sh, modelmirrors.kaito.sh are not installed")
+}
+
+func TestNodeProvisionerCheck(t *testing.T) {
+	reader := newFakeReader(t, crd("nodeclaims.karpenter.sh", true))
+
+	_, err := NodeProvisionerCheck(reader, consts.NodeProvisionerAzureGPU, "").Run(context.Background())
+	assert.NoError(t, err)
+
+	_, err = NodeProvisionerCheck(reader, consts.NodeProvisionerKarpenter, "aksnodeclasses.karpenter.azure.com").Run(context.Background())
+	assert.ErrorContains(t, err, "nodepools.karpenter.sh, aksnodeclasses.karpenter.azure.com are not installed")
+
+	msg, err := NodeProvisionerCheck(newFakeReader(t), consts.NodeProvisionerBYO, "").Run(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, msg, "disabled")
+
+	msg, err = NodeProvisionerCheck(newFakeReader(t), consts.NodeProvisionerClusterAutoscaler, "").Run(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, msg, "cluster autoscaler")
+}
+
+func TestCloudProviderCheck(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	_, err := CloudProviderCheck().Run(context.Background())
+	assert.NoError(t, err)
+
+	t.Setenv("CLOUD_PROVIDER", "")
+	_, err = CloudProviderCheck().Run(context.Background())
+	assert.ErrorContains(t, err, "CLOUD_PROVIDER")
+}
+
+func newCertSecret(t *testing.T, dnsName string, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-webhook-cert", Namespace: "kaito-workspace"},
+		Data:       map[string][]byte{resources.ServerCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func TestWebhookCertCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     *corev1.Secret
+		errContent string
+	}{
+		{
+			name:   "valid certificate",
+			secret: newCertSecret(t, "kaito-workspace.kaito-workspace.svc", time.Now().Add(7*24*time.Hour)),
+		},
+		{
+			name:       "missing secret",
+			errContent: "failed to get webhook certificate secret",
+		},
+		{
+			name:       "wrong host",
+			secret:     newCertSecret(t, "other.kaito-workspace.svc", time.Now().Add(7*24*time.Hour)),
+			errContent: "not valid for kaito-workspace.kaito-workspace.svc",
+		},
+		{
+			name:       "about to expire",
+			secret:     newCertSecret(t, "kaito-workspace.kaito-workspace.svc", time.Now().Add(time.Hour)),
+			errContent: "to have it regenerated",
+		},
+		{
+			name: "no certificate",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "workspace-webhook-cert", Namespace: "kaito-workspace"},
+			},
+			errContent: "no PEM certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			if tt.secret != nil {
+				objs = append(objs, tt.secret)
+			}
+			check := WebhookCertCheck(newFakeReader(t, objs...), "kaito-workspace", "workspace-webhook-cert", "kaito-workspace")
+			_, err := check.Run(context.Background())
+			if tt.errContent == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errContent)
+		})
+	}
+}
+
+func TestGPUCapacityCheck(t *testing.T) {
+	gpuNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-node"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceName(consts.NvidiaGPU): resource.MustParse("1"),
+		}},
+	}
+	cpuNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-node"}}
+
+	msg, err := GPUCapacityCheck(newFakeReader(t, gpuNode, cpuNode), consts.NodeProvisionerBYO).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1 nodes advertise nvidia.com/gpu", msg)
+
+	_, err = GPUCapacityCheck(newFakeReader(t, cpuNode), consts.NodeProvisionerBYO).Run(context.Background())
+	assert.ErrorContains(t, err, "install the NVIDIA device plugin")
+
+	msg, err = GPUCapacityCheck(newFakeReader(t, cpuNode), "maas").Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "node provisioner plugin maas is reachable", msg)
+
+	// Without the NodeClaim CRD, only nodes running the device plugin provide GPUs.
+	noNodeClaimCRD := interceptor.NewClient(newFakeReader(t, gpuNode).(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*karpenterv1.NodeClaimList); ok {
+				return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "karpenter.sh", Kind: "NodeClaim"}}
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+	msg, err = GPUCapacityCheck(noNodeClaimCRD, consts.NodeProvisionerAzureGPU).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1 nodes advertise nvidia.com/gpu", msg)
+
+	launched := func(name, message string) *karpenterv1.NodeClaim {
+		nc := &karpenterv1.NodeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: map[string]string{kaitov1beta1.LabelWorkspaceName: "ws"},
+		}}
+		nc.SetConditions([]status.Condition{{Type: karpenterv1.ConditionTypeLaunched, Status: metav1.ConditionFalse, Message: message}})
+		return nc
+	}
+	_, err = GPUCapacityCheck(newFakeReader(t, launched("ws1", "creating instance: capacity not available")), consts.NodeProvisionerAzureGPU).Run(context.Background())
+	assert.NoError(t, err)
+
+	_, err = GPUCapacityCheck(newFakeReader(t, launched("ws2", "Operation could not be completed as it results in exceeding approved standardNCADSA100v4Family Cores quota")),
+		consts.NodeProvisionerAzureGPU).Run(context.Background())
+	assert.ErrorContains(t, err, "NodeClaim ws2 failed to launch")
+}
@@ -0,0 +1,188 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight verifies the prerequisites of a KAITO installation, such as CRDs, the
+// node provisioner and the webhook certificate, so that a broken setup is reported as a
+// failed check instead of surfacing later as a stuck Workspace.
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ConditionTypePreflightReady is the condition type reported for every check.
+	ConditionTypePreflightReady = "PreflightReady"
+
+	// DefaultInterval is how often the checks are run again after startup, so that
+	// prerequisites installed after KAITO are picked up.
+	DefaultInterval = 5 * time.Minute
+
+	// checkTimeout bounds a single run of one check.
+	checkTimeout = 30 * time.Second
+)
+
+var preflightCheckReady = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kaito_preflight_check_ready",
+		Help: "Whether a preflight check of the KAITO installation passed (1) or failed (0), by check",
+	},
+	[]string{"check"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(preflightCheckReady)
+}
+
+// Check verifies one prerequisite of the installation.
+type Check struct {
+	// Name identifies the check in logs, metrics and endpoints.
+	Name string
+	// Reason is the CamelCase reason reported when the check passes, e.g. CRDsInstalled.
+	Reason string
+	// Run returns a short description of what was found, or an error explaining what is
+	// missing and how to fix it.
+	Run func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of the latest run of a check.
+type Result struct {
+	Name      string           `json:"name"`
+	Condition metav1.Condition `json:"condition"`
+}
+
+// Runner runs the checks at startup and every Interval afterwards, and serves their results.
+// It runs on every replica since each replica may be misconfigured on its own.
+type Runner struct {
+	Checks   []Check
+	Interval time.Duration
+
+	mu      sync.RWMutex
+	results map[string]metav1.Condition
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (r *Runner) NeedLeaderElection() bool { return false }
+
+// Start runs the checks until ctx is done.
+func (r *Runner) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs every check once and records the results.
+func (r *Runner) RunOnce(ctx context.Context) {
+	for _, check := range r.Checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		message, err := check.Run(checkCtx)
+		cancel()
+
+		cond := metav1.Condition{
+			Type:    ConditionTypePreflightReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  check.Reason,
+			Message: message,
+		}
+		if err != nil {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "CheckFailed"
+			cond.Message = err.Error()
+			klog.ErrorS(err, "preflight check failed", "check", check.Name)
+			preflightCheckReady.WithLabelValues(check.Name).Set(0)
+		} else {
+			preflightCheckReady.WithLabelValues(check.Name).Set(1)
+		}
+		r.record(check.Name, cond)
+	}
+}
+
+func (r *Runner) record(name string, cond metav1.Condition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.results == nil {
+		r.results = map[string]metav1.Condition{}
+	}
+	cond.LastTransitionTime = metav1.Now()
+	if prev, ok := r.results[name]; ok && prev.Status == cond.Status {
+		cond.LastTransitionTime = prev.LastTransitionTime
+	}
+	r.results[name] = cond
+}
+
+// Results returns the latest result of each check that has run, in check order.
+func (r *Runner) Results() []Result {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := make([]Result, 0, len(r.Checks))
+	for _, check := range r.Checks {
+		if cond, ok := r.results[check.Name]; ok {
+			results = append(results, Result{Name: check.Name, Condition: cond})
+		}
+	}
+	return results
+}
+
+// Checker returns a health checker for the named check, for use as a readyz check. It
+// passes until the check has run for the first time.
+func (r *Runner) Checker(name string) func(*http.Request) error {
+	return func(_ *http.Request) error {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if cond, ok := r.results[name]; ok && cond.Status != metav1.ConditionTrue {
+			return fmt.Errorf("preflight check %s failed: %s", name, cond.Message)
+		}
+		return nil
+	}
+}
+
+// ServeHTTP serves the results as JSON. The status code is 503 until every check has
+// passed, so the endpoint can be polled after an installation.
+func (r *Runner) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	results := r.Results()
+	code := http.StatusOK
+	if len(results) < len(r.Checks) {
+		code = http.StatusServiceUnavailable
+	}
+	for _, res := range results {
+		if res.Condition.Status != metav1.ConditionTrue {
+			code = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		klog.ErrorS(err, "failed to write preflight results")
+	}
+}
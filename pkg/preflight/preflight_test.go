@@ -0,0 +1,74 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunner(t *testing.T) {
+	var crdErr error
+	r := &Runner{Checks: []Check{
+		{Name: "crds", Reason: "CRDsInstalled", Run: func(context.Context) (string, error) { return "installed", crdErr }},
+		{Name: "cloud-provider", Reason: "CloudProviderConfigured", Run: func(context.Context) (string, error) { return "azure", nil }},
+	}}
+
+	serve := func() (int, []Result) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/preflight", nil))
+		var results []Result
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+		return rec.Code, results
+	}
+
+	// Nothing has run yet.
+	code, results := serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Empty(t, results)
+	assert.NoError(t, r.Checker("crds")(nil))
+
+	crdErr = errors.New("CRDs workspaces.kaito.sh are not installed")
+	r.RunOnce(context.Background())
+	code, results = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	require.Len(t, results, 2)
+	assert.Equal(t, "crds", results[0].Name)
+	assert.Equal(t, ConditionTypePreflightReady, results[0].Condition.Type)
+	assert.Equal(t, metav1.ConditionFalse, results[0].Condition.Status)
+	assert.Equal(t, "CheckFailed", results[0].Condition.Reason)
+	assert.Equal(t, crdErr.Error(), results[0].Condition.Message)
+	assert.Equal(t, metav1.ConditionTrue, results[1].Condition.Status)
+	assert.Equal(t, "CloudProviderConfigured", results[1].Condition.Reason)
+	assert.ErrorContains(t, r.Checker("crds")(nil), "not installed")
+	assert.NoError(t, r.Checker("cloud-provider")(nil))
+	cloudTransition := r.Results()[1].Condition.LastTransitionTime
+
+	crdErr = nil
+	r.RunOnce(context.Background())
+	code, results = serve()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, metav1.ConditionTrue, results[0].Condition.Status)
+	assert.Equal(t, "CRDsInstalled", results[0].Condition.Reason)
+	assert.NoError(t, r.Checker("crds")(nil))
+	assert.Equal(t, cloudTransition, r.Results()[1].Condition.LastTransitionTime)
+}
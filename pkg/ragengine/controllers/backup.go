@@ -0,0 +1,239 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
+)
+
+// ensureBackupCronJob keeps the backup CronJob of ragEngineObj in sync with spec.backup.
+// When backups are disabled, the CronJob is deleted; status.backup tells whether one was
+// created, so RAGEngines that never enabled backups are not looked up.
+func (c *RAGEngineReconciler) ensureBackupCronJob(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	name := v1beta1.BackupCronJobName(ragEngineObj.Name)
+	if ragEngineObj.Spec.Backup == nil {
+		if ragEngineObj.Status.Backup == nil {
+			return nil
+		}
+		klog.InfoS("Backups disabled, deleting backup cronjob", "ragengine", klog.KObj(ragEngineObj), "cronjob", name)
+		if err := client.IgnoreNotFound(c.Client.Delete(ctx, &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ragEngineObj.Namespace},
+		})); err != nil {
+			return fmt.Errorf("failed to delete backup cronjob %s: %w", name, err)
+		}
+		return c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+			status.Backup = nil
+			meta.RemoveStatusCondition(&status.Conditions, string(v1beta1.RAGEngineConditionTypeBackupSucceeded))
+		})
+	}
+
+	desired := manifests.GenerateBackupCronJobManifest(ragEngineObj, getImageConfig().GetImage())
+	existing := &batchv1.CronJob{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, existing)
+	if apierrors.IsNotFound(err) {
+		if err := resources.CreateResource(ctx, desired, c.Client); err != nil {
+			return fmt.Errorf("failed to create backup cronjob %s: %w", name, err)
+		}
+		existing = desired
+	} else if err != nil {
+		return fmt.Errorf("failed to get backup cronjob %s: %w", name, err)
+	} else {
+		if !metav1.IsControlledBy(existing, ragEngineObj) {
+			return fmt.Errorf("cronjob %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+		}
+		if existing.Spec.Schedule != desired.Spec.Schedule ||
+			!apiequality.Semantic.DeepEqual(existing.Spec.JobTemplate.Spec.Template.Spec.Containers, desired.Spec.JobTemplate.Spec.Template.Spec.Containers) {
+			existing.Spec = desired.Spec
+			if err := c.Client.Update(ctx, existing); err != nil {
+				return fmt.Errorf("failed to update backup cronjob %s: %w", name, err)
+			}
+		}
+	}
+	return c.syncBackupStatus(ctx, ragEngineObj, existing)
+}
+
+// syncBackupStatus reports the most recent backups in status.backup. Backups are named
+// after the Jobs of the backup CronJob, so the finished Jobs tell which backups exist.
+// Other Jobs carrying the RAGEngine label are ignored. status.backup is set as soon as the
+// CronJob exists, even before the first backup.
+func (c *RAGEngineReconciler) syncBackupStatus(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, cronJob *batchv1.CronJob) error {
+	jobs := &batchv1.JobList{}
+	if err := c.Client.List(ctx, jobs, client.InNamespace(ragEngineObj.Namespace),
+		client.MatchingLabels{v1beta1.LabelRAGEngineName: ragEngineObj.Name}); err != nil {
+		return fmt.Errorf("failed to list backup jobs: %w", err)
+	}
+	backup := ragEngineObj.Status.Backup.DeepCopy()
+	if backup == nil {
+		backup = &v1beta1.RAGBackupStatus{}
+	}
+	condition := meta.FindStatusCondition(ragEngineObj.Status.Conditions, string(v1beta1.RAGEngineConditionTypeBackupSucceeded)).DeepCopy()
+	var latestFinish *metav1.Time
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !metav1.IsControlledBy(job, cronJob) {
+			continue
+		}
+		finished, failed := jobFinished(job)
+		if finished == nil || (latestFinish != nil && !latestFinish.Before(finished)) {
+			continue
+		}
+		latestFinish = finished
+		if failed != nil {
+			condition = &metav1.Condition{
+				Type:    string(v1beta1.RAGEngineConditionTypeBackupSucceeded),
+				Status:  metav1.ConditionFalse,
+				Reason:  "BackupFailed",
+				Message: fmt.Sprintf("backup %s failed: %s", job.Name, failed.Message),
+			}
+			continue
+		}
+		condition = &metav1.Condition{
+			Type:    string(v1beta1.RAGEngineConditionTypeBackupSucceeded),
+			Status:  metav1.ConditionTrue,
+			Reason:  "BackupSucceeded",
+			Message: fmt.Sprintf("backup %s succeeded", job.Name),
+		}
+		if backup.LastSuccessfulBackupTime == nil || backup.LastSuccessfulBackupTime.Before(finished) {
+			backup.LastSuccessfulBackup = job.Name
+			backup.LastSuccessfulBackupTime = finished
+		}
+	}
+
+	current := meta.FindStatusCondition(ragEngineObj.Status.Conditions, string(v1beta1.RAGEngineConditionTypeBackupSucceeded))
+	conditionChanged := condition != nil && (current == nil || current.Status != condition.Status || current.Message != condition.Message)
+	if ragEngineObj.Status.Backup != nil && apiequality.Semantic.DeepEqual(ragEngineObj.Status.Backup, backup) && !conditionChanged {
+		return nil
+	}
+	return c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+		status.Backup = backup
+		if conditionChanged {
+			condition.ObservedGeneration = ragEngineObj.Generation
+			meta.SetStatusCondition(&status.Conditions, *condition)
+		}
+	})
+}
+
+// jobFinished returns the time a Job finished and its Failed condition if it failed.
+func jobFinished(job *batchv1.Job) (*metav1.Time, *batchv1.JobCondition) {
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			if job.Status.CompletionTime != nil {
+				return job.Status.CompletionTime, nil
+			}
+			return &cond.LastTransitionTime, nil
+		case batchv1.JobFailed:
+			return &cond.LastTransitionTime, cond
+		}
+	}
+	return nil, nil
+}
+
+// restoreResult is the termination message of the restore init container.
+type restoreResult struct {
+	Indexes []string `json:"indexes"`
+}
+
+// syncRestoreStatus reports the progress of spec.restore in status.restore, based on the
+// restore init container of the RAG pod. It stops looking once the restore succeeded.
+func (c *RAGEngineReconciler) syncRestoreStatus(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	if ragEngineObj.Spec.Restore == nil {
+		return nil
+	}
+	if r := ragEngineObj.Status.Restore; r != nil && r.Phase == v1beta1.RestorePhaseSucceeded {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.Client.List(ctx, pods, client.InNamespace(ragEngineObj.Namespace),
+		client.MatchingLabels{v1beta1.LabelRAGEngineName: ragEngineObj.Name}); err != nil {
+		return fmt.Errorf("failed to list ragengine pods: %w", err)
+	}
+	restore := restoreStatusFromPods(ragEngineObj.Spec.Restore.BackupName, pods.Items)
+	if apiequality.Semantic.DeepEqual(ragEngineObj.Status.Restore, restore) {
+		return nil
+	}
+	klog.InfoS("Restore progress", "ragengine", klog.KObj(ragEngineObj), "backup", restore.BackupName, "phase", restore.Phase)
+	return c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+		status.Restore = restore
+	})
+}
+
+// restoreStatusFromPods derives the restore progress from the restore init container of
+// the newest RAG pod.
+func restoreStatusFromPods(backupName string, pods []corev1.Pod) *v1beta1.RAGRestoreStatus {
+	restore := &v1beta1.RAGRestoreStatus{BackupName: backupName, Phase: v1beta1.RestorePhasePending}
+	pods = slices.Clone(pods)
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+	})
+	for _, pod := range pods {
+		idx := slices.IndexFunc(pod.Status.InitContainerStatuses, func(s corev1.ContainerStatus) bool {
+			return s.Name == manifests.RestoreInitContainerName
+		})
+		if idx < 0 {
+			continue
+		}
+		state := pod.Status.InitContainerStatuses[idx]
+		terminated := state.State.Terminated
+		if terminated == nil {
+			terminated = state.LastTerminationState.Terminated
+		}
+		switch {
+		case state.State.Terminated != nil && state.State.Terminated.ExitCode == 0:
+			restore.Phase = v1beta1.RestorePhaseSucceeded
+			restore.CompletionTime = &state.State.Terminated.FinishedAt
+			var result restoreResult
+			if err := json.Unmarshal([]byte(state.State.Terminated.Message), &result); err == nil {
+				restore.Indexes = result.Indexes
+			}
+		case terminated != nil && terminated.ExitCode != 0:
+			restore.Phase = v1beta1.RestorePhaseFailed
+			restore.Message = terminated.Message
+		case state.State.Running != nil:
+			restore.Phase = v1beta1.RestorePhaseInProgress
+		}
+		return restore
+	}
+	return restore
+}
+
+// updateRAGEngineStatusWith applies mutate to the latest status of ragEngineObj.
+func (c *RAGEngineReconciler) updateRAGEngineStatusWith(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, mutate func(*v1beta1.RAGEngineStatus)) error {
+	return statuspatch.Update(ctx, c.Client, client.ObjectKeyFromObject(ragEngineObj), &v1beta1.RAGEngine{}, func(latest *v1beta1.RAGEngine) error {
+		mutate(&latest.Status)
+		return nil
+	})
+}
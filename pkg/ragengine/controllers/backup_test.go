@@ -0,0 +1,180 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+)
+
+func newBackupTestJob(name string, owner *batchv1.CronJob, finished time.Time, condType batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{v1beta1.LabelRAGEngineName: "rag"},
+		},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+			Type:               condType,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(finished),
+			Message:            "BackoffLimitExceeded",
+		}}},
+	}
+	if condType == batchv1.JobComplete {
+		job.Status.CompletionTime = &metav1.Time{Time: finished}
+	}
+	if owner != nil {
+		job.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, batchv1.SchemeGroupVersion.WithKind("CronJob"))}
+	}
+	return job
+}
+
+func TestEnsureBackupCronJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			Backup: &v1beta1.RAGBackupSpec{
+				Schedule: "0 */6 * * *",
+				Destination: v1beta1.BackupLocation{
+					URL:               "https://acct.blob.core.windows.net/backups",
+					CredentialsSecret: "backup-sas",
+				},
+			},
+		},
+	}
+	existing := manifests.GenerateBackupCronJobManifest(ragEngine, getImageConfig().GetImage())
+	existing.UID = "cronjob-uid"
+	now := time.Now().Truncate(time.Second)
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ragEngine.DeepCopy(), existing,
+			newBackupTestJob("rag-backup-1", existing, now.Add(-2*time.Hour), batchv1.JobComplete),
+			newBackupTestJob("rag-backup-2", existing, now.Add(-time.Hour), batchv1.JobComplete),
+			newBackupTestJob("rag-backup-3", existing, now, batchv1.JobFailed),
+			// Jobs that carry the RAGEngine label but are not run by the backup CronJob are ignored.
+			newBackupTestJob("rag-index", nil, now.Add(time.Minute), batchv1.JobComplete)).
+		WithStatusSubresource(&v1beta1.RAGEngine{}).
+		Build()
+	reconciler := &RAGEngineReconciler{Client: kubeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	key := ctrlclient.ObjectKey{Name: "rag-backup", Namespace: "default"}
+	latest := func() *v1beta1.RAGEngine {
+		obj := &v1beta1.RAGEngine{}
+		require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKeyFromObject(ragEngine), obj))
+		return obj
+	}
+
+	require.NoError(t, reconciler.ensureBackupCronJob(ctx, ragEngine))
+	cronJob := &batchv1.CronJob{}
+	require.NoError(t, kubeClient.Get(ctx, key, cronJob))
+	assert.Equal(t, "0 */6 * * *", cronJob.Spec.Schedule)
+
+	obj := latest()
+	require.NotNil(t, obj.Status.Backup)
+	assert.Equal(t, "rag-backup-2", obj.Status.Backup.LastSuccessfulBackup)
+	assert.True(t, obj.Status.Backup.LastSuccessfulBackupTime.Time.Equal(now.Add(-time.Hour)))
+	cond := meta.FindStatusCondition(obj.Status.Conditions, string(v1beta1.RAGEngineConditionTypeBackupSucceeded))
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Contains(t, cond.Message, "rag-backup-3")
+
+	// Schedule changes are applied to the existing CronJob.
+	obj.Spec.Backup.Schedule = "@daily"
+	require.NoError(t, reconciler.ensureBackupCronJob(ctx, obj))
+	require.NoError(t, kubeClient.Get(ctx, key, cronJob))
+	assert.Equal(t, "@daily", cronJob.Spec.Schedule)
+
+	// Disabling backups deletes the CronJob and clears the status.
+	obj = latest()
+	obj.Spec.Backup = nil
+	require.NoError(t, reconciler.ensureBackupCronJob(ctx, obj))
+	assert.True(t, apierrors.IsNotFound(kubeClient.Get(ctx, key, &batchv1.CronJob{})))
+	obj = latest()
+	assert.Nil(t, obj.Status.Backup)
+	assert.Nil(t, meta.FindStatusCondition(obj.Status.Conditions, string(v1beta1.RAGEngineConditionTypeBackupSucceeded)))
+}
+
+func TestRestoreStatusFromPods(t *testing.T) {
+	now := metav1.Now()
+	newPod := func(created time.Time, status corev1.ContainerStatus) corev1.Pod {
+		status.Name = manifests.RestoreInitContainerName
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Status:     corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	running := corev1.ContainerStatus{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	succeeded := corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode: 0, FinishedAt: now, Message: `{"indexes": ["index1", "index2"]}`,
+	}}}
+	failed := corev1.ContainerStatus{
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1, Message: "Restore of backup old failed: GET https://acct.blob.core.windows.net/backups/old.tar.gz failed with status 404",
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		pods    []corev1.Pod
+		phase   v1beta1.RestorePhase
+		indexes []string
+		message string
+	}{
+		{name: "no pods", phase: v1beta1.RestorePhasePending},
+		{name: "downloading", pods: []corev1.Pod{newPod(now.Time, running)}, phase: v1beta1.RestorePhaseInProgress},
+		{
+			name:    "succeeded",
+			pods:    []corev1.Pod{newPod(now.Time, succeeded)},
+			phase:   v1beta1.RestorePhaseSucceeded,
+			indexes: []string{"index1", "index2"},
+		},
+		{name: "failed", pods: []corev1.Pod{newPod(now.Time, failed)}, phase: v1beta1.RestorePhaseFailed, message: "status 404"},
+		{
+			name:  "newest pod wins",
+			pods:  []corev1.Pod{newPod(now.Add(-time.Minute), failed), newPod(now.Time, running)},
+			phase: v1beta1.RestorePhaseInProgress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := restoreStatusFromPods("old", tt.pods)
+			assert.Equal(t, "old", status.BackupName)
+			assert.Equal(t, tt.phase, status.Phase)
+			assert.Equal(t, tt.indexes, status.Indexes)
+			assert.Contains(t, status.Message, tt.message)
+		})
+	}
+}
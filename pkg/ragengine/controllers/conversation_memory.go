@@ -0,0 +1,154 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+// redisPasswordBytes is the number of random bytes in a generated Redis password.
+const redisPasswordBytes = 32
+
+// managesConversationMemory reports whether the controller deploys the Redis server of
+// the conversation memory of ragEngineObj.
+func managesConversationMemory(ragEngineObj *v1beta1.RAGEngine) bool {
+	memory := ragEngineObj.Spec.ConversationMemory
+	return memory != nil && memory.Backend == v1beta1.ConversationMemoryBackendRedis && memory.Redis == nil
+}
+
+// ensureConversationMemory deploys the Redis server of the conversation memory, with a
+// generated password, when the Redis backend is used without a URL. It is a no-op
+// otherwise.
+func (c *RAGEngineReconciler) ensureConversationMemory(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	if !managesConversationMemory(ragEngineObj) {
+		return nil
+	}
+	name := v1beta1.ConversationMemoryName(ragEngineObj.Name)
+
+	if err := ensureConversationMemorySecret(ctx, ragEngineObj, c.Client); err != nil {
+		return err
+	}
+
+	svc := &corev1.Service{}
+	if err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get conversation memory service %s: %w", name, err)
+		}
+		if err := resources.CreateResource(ctx, manifests.GenerateConversationMemoryServiceManifest(ragEngineObj), c.Client); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to create conversation memory service %s: %w", name, err)
+		}
+	} else if !metav1.IsControlledBy(svc, ragEngineObj) {
+		return fmt.Errorf("service %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+	}
+
+	desired := manifests.GenerateConversationMemoryDeploymentManifest(ragEngineObj)
+	existing := &appsv1.Deployment{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := resources.CreateResource(ctx, desired, c.Client); err != nil {
+			return fmt.Errorf("failed to create conversation memory deployment %s: %w", name, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get conversation memory deployment %s: %w", name, err)
+	case !metav1.IsControlledBy(existing, ragEngineObj):
+		return fmt.Errorf("deployment %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+	case !apiequality.Semantic.DeepEqual(existing.Spec.Template.Spec.Containers, desired.Spec.Template.Spec.Containers):
+		existing.Spec.Template.Spec.Containers = desired.Spec.Template.Spec.Containers
+		if err := c.Client.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update conversation memory deployment %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ensureConversationMemorySecret creates the Secret holding the password of the Redis
+// server. An existing password is kept so the RAG service and Redis never disagree.
+func ensureConversationMemorySecret(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, kubeClient client.Client) error {
+	name := v1beta1.ConversationMemoryName(ragEngineObj.Name)
+	existing := &corev1.Secret{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, kubeClient, existing)
+	if err == nil {
+		if !metav1.IsControlledBy(existing, ragEngineObj) {
+			return fmt.Errorf("secret %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+		}
+		if len(existing.Data[manifests.ConversationMemoryPasswordKey]) > 0 {
+			return nil
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get conversation memory secret %s: %w", name, err)
+	}
+
+	buf := make([]byte, redisPasswordBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("failed to generate redis password: %w", err)
+	}
+	data := map[string][]byte{manifests.ConversationMemoryPasswordKey: []byte(hex.EncodeToString(buf))}
+	if err == nil {
+		existing.Data = data
+		return kubeClient.Update(ctx, existing)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ragEngineObj.Namespace,
+			Labels:    map[string]string{v1beta1.LabelRAGEngineName: ragEngineObj.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ragEngineObj, v1beta1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	return resources.CreateResource(ctx, secret, kubeClient)
+}
+
+// deleteConversationMemory removes the Redis server and its password once the RAG
+// service no longer uses it. Objects the RAGEngine does not own are left alone.
+func (c *RAGEngineReconciler) deleteConversationMemory(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	name := v1beta1.ConversationMemoryName(ragEngineObj.Name)
+	deployment := &appsv1.Deployment{}
+	if err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, deployment); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(deployment, ragEngineObj) {
+		return nil
+	}
+	klog.InfoS("Managed conversation memory disabled, deleting redis", "ragengine", klog.KObj(ragEngineObj), "deployment", name)
+	for _, obj := range []client.Object{
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ragEngineObj.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ragEngineObj.Namespace}},
+		deployment,
+	} {
+		if err := client.IgnoreNotFound(c.Client.Delete(ctx, obj)); err != nil {
+			return fmt.Errorf("failed to delete conversation memory %T %s: %w", obj, name, err)
+		}
+	}
+	return nil
+}
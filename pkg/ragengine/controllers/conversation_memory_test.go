@@ -0,0 +1,83 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+)
+
+func TestEnsureConversationMemory(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			ConversationMemory: &v1beta1.ConversationMemorySpec{Backend: v1beta1.ConversationMemoryBackendRedis},
+		},
+	}
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &RAGEngineReconciler{Client: kubeClient}
+	key := ctrlclient.ObjectKey{Name: "rag-memory", Namespace: "default"}
+
+	require.NoError(t, reconciler.ensureConversationMemory(ctx, ragEngine))
+	secret := &corev1.Secret{}
+	require.NoError(t, kubeClient.Get(ctx, key, secret))
+	assert.True(t, metav1.IsControlledBy(secret, ragEngine))
+	password := secret.Data[manifests.ConversationMemoryPasswordKey]
+	assert.Len(t, password, 2*redisPasswordBytes)
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, kubeClient.Get(ctx, key, deployment))
+	assert.Equal(t, manifests.ConversationMemoryRedisImage, deployment.Spec.Template.Spec.Containers[0].Image)
+	require.NoError(t, kubeClient.Get(ctx, key, &corev1.Service{}))
+
+	// The password survives later reconciles.
+	require.NoError(t, reconciler.ensureConversationMemory(ctx, ragEngine))
+	require.NoError(t, kubeClient.Get(ctx, key, secret))
+	assert.Equal(t, password, secret.Data[manifests.ConversationMemoryPasswordKey])
+
+	// Once the RAG service stops using the managed server, it is removed.
+	ragEngine.Spec.ConversationMemory.Redis = &v1beta1.RedisMemorySpec{URL: "redis://redis.example.com:6379"}
+	require.NoError(t, reconciler.ensureConversationMemory(ctx, ragEngine))
+	require.NoError(t, kubeClient.Get(ctx, key, &appsv1.Deployment{}))
+	require.NoError(t, reconciler.deleteConversationMemory(ctx, ragEngine))
+	for _, obj := range []ctrlclient.Object{&corev1.Secret{}, &corev1.Service{}, &appsv1.Deployment{}} {
+		assert.True(t, apierrors.IsNotFound(kubeClient.Get(ctx, key, obj)), "%T should be deleted", obj)
+	}
+
+	// Objects with the same name that the RAGEngine does not own are left alone.
+	foreign := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rag-memory", Namespace: "default"}}
+	require.NoError(t, kubeClient.Create(ctx, foreign))
+	require.NoError(t, reconciler.deleteConversationMemory(ctx, ragEngine))
+	require.NoError(t, kubeClient.Get(ctx, key, &appsv1.Deployment{}))
+	ragEngine.Spec.ConversationMemory.Redis = nil
+	assert.ErrorContains(t, reconciler.ensureConversationMemory(ctx, ragEngine), "not owned by ragengine")
+}
@@ -0,0 +1,259 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+const (
+	// apiKeyBytes is the number of random bytes in a generated API key.
+	apiKeyBytes = 32
+	// tokenReviewClusterRole lets the RAG service create the TokenReviews that verify
+	// the tokens of service account clients.
+	tokenReviewClusterRole = "system:auth-delegator"
+)
+
+// indexAuthPolicy is the policy file read by the RAG service. It holds SHA-256 digests
+// of the API keys rather than the keys themselves.
+type indexAuthPolicy struct {
+	Indexes map[string]indexAuthPolicyEntry `json:"indexes"`
+	Admins  indexAuthPolicyEntry            `json:"admins"`
+}
+
+type indexAuthPolicyEntry struct {
+	APIKeySHA256    []string `json:"apiKeySHA256,omitempty"`
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+// ensureIndexAuthSecret keeps the API key Secret of ragEngineObj in sync with
+// spec.authorization. Existing keys are kept, keys are generated for new names and keys
+// whose names are no longer referenced are dropped. It is a no-op when authorization is
+// disabled.
+func ensureIndexAuthSecret(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, kubeClient client.Client) error {
+	if ragEngineObj.Spec.Authorization == nil {
+		return nil
+	}
+	name := v1beta1.IndexAuthSecretName(ragEngineObj.Name)
+	existing := &corev1.Secret{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, kubeClient, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get API key secret %s: %w", name, err)
+	}
+	found := err == nil
+	if found && !metav1.IsControlledBy(existing, ragEngineObj) {
+		return fmt.Errorf("secret %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+	}
+
+	data, err := renderIndexAuthSecretData(ragEngineObj.Spec.Authorization, existing.Data)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ragEngineObj.Namespace,
+				Labels:    map[string]string{v1beta1.LabelRAGEngineName: ragEngineObj.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(ragEngineObj, v1beta1.GroupVersion.WithKind("RAGEngine")),
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		return resources.CreateResource(ctx, secret, kubeClient)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Data, data) {
+		return nil
+	}
+	existing.Data = data
+	return kubeClient.Update(ctx, existing)
+}
+
+// deleteIndexAuthSecret revokes all API keys once authorization has been disabled, so
+// re-enabling it later does not bring old keys back.
+func deleteIndexAuthSecret(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, kubeClient client.Client) error {
+	name := v1beta1.IndexAuthSecretName(ragEngineObj.Name)
+	klog.InfoS("Authorization disabled, deleting API key secret", "ragengine", klog.KObj(ragEngineObj), "secret", name)
+	return client.IgnoreNotFound(kubeClient.Delete(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ragEngineObj.Namespace},
+	}))
+}
+
+// indexAuthClusterRoleBindingName returns the name of the ClusterRoleBinding granting the
+// ServiceAccount of ragEngineObj TokenReview access. ClusterRoleBindings are cluster-scoped,
+// so the name includes the namespace.
+func indexAuthClusterRoleBindingName(ragEngineObj *v1beta1.RAGEngine) string {
+	return fmt.Sprintf("kaito:ragengine:%s:%s", ragEngineObj.Namespace, ragEngineObj.Name)
+}
+
+// ensureIndexAuthServiceAccount creates the ServiceAccount the RAG pods run as when
+// authorization is enabled and binds it to system:auth-delegator, so the RAG service can
+// verify service account clients with a TokenReview. It is a no-op when authorization is
+// disabled.
+func ensureIndexAuthServiceAccount(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, kubeClient client.Client) error {
+	if ragEngineObj.Spec.Authorization == nil {
+		return nil
+	}
+	labels := map[string]string{v1beta1.LabelRAGEngineName: ragEngineObj.Name}
+	name := v1beta1.IndexAuthServiceAccountName(ragEngineObj.Name)
+	serviceAccount := &corev1.ServiceAccount{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, kubeClient, serviceAccount)
+	if apierrors.IsNotFound(err) {
+		serviceAccount = &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ragEngineObj.Namespace,
+				Labels:    labels,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(ragEngineObj, v1beta1.GroupVersion.WithKind("RAGEngine")),
+				},
+			},
+		}
+		if err = resources.CreateResource(ctx, serviceAccount, kubeClient); err != nil {
+			return fmt.Errorf("failed to create service account %s: %w", name, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get service account %s: %w", name, err)
+	} else if !metav1.IsControlledBy(serviceAccount, ragEngineObj) {
+		return fmt.Errorf("service account %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+	}
+
+	bindingName := indexAuthClusterRoleBindingName(ragEngineObj)
+	binding := &rbacv1.ClusterRoleBinding{}
+	err = kubeClient.Get(ctx, client.ObjectKey{Name: bindingName}, binding)
+	if err == nil {
+		if binding.Labels[v1beta1.LabelRAGEngineName] != ragEngineObj.Name {
+			return fmt.Errorf("cluster role binding %s already exists and is not managed by ragengine %s", bindingName, ragEngineObj.Name)
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get cluster role binding %s: %w", bindingName, err)
+	}
+	binding = &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: bindingName, Labels: labels},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     tokenReviewClusterRole,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: ragEngineObj.Namespace,
+		}},
+	}
+	if err := resources.CreateResource(ctx, binding, kubeClient); err != nil {
+		return fmt.Errorf("failed to create cluster role binding %s: %w", bindingName, err)
+	}
+	return nil
+}
+
+// deleteIndexAuthClusterRoleBinding revokes the TokenReview access of the RAG pods. The
+// ServiceAccount is owned by the RAGEngine, but the cluster-scoped binding cannot be, so
+// it is deleted explicitly once authorization is disabled or the RAGEngine is deleted.
+func deleteIndexAuthClusterRoleBinding(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, kubeClient client.Client) error {
+	name := indexAuthClusterRoleBindingName(ragEngineObj)
+	binding := &rbacv1.ClusterRoleBinding{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: name}, binding); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if binding.Labels[v1beta1.LabelRAGEngineName] != ragEngineObj.Name {
+		return nil
+	}
+	klog.InfoS("Deleting token review cluster role binding", "ragengine", klog.KObj(ragEngineObj), "clusterRoleBinding", name)
+	return client.IgnoreNotFound(kubeClient.Delete(ctx, binding))
+}
+
+// renderIndexAuthSecretData returns the Secret data for auth: one entry per API key name,
+// the maintenance key of the backup and index eviction jobs and the rendered policy file.
+// Keys found in current are reused.
+func renderIndexAuthSecretData(auth *v1beta1.IndexAuthorizationSpec, current map[string][]byte) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	policyEntry := func(access v1beta1.IndexAccessSpec) (indexAuthPolicyEntry, error) {
+		entry := indexAuthPolicyEntry{ServiceAccounts: slices.Sorted(slices.Values(access.ServiceAccounts))}
+		for _, keyName := range access.APIKeys {
+			key, ok := data[keyName]
+			if !ok {
+				key = current[keyName]
+				if len(key) == 0 {
+					var err error
+					if key, err = generateAPIKey(); err != nil {
+						return entry, err
+					}
+				}
+				data[keyName] = key
+			}
+			digest := sha256.Sum256(key)
+			entry.APIKeySHA256 = append(entry.APIKeySHA256, hex.EncodeToString(digest[:]))
+		}
+		slices.Sort(entry.APIKeySHA256)
+		entry.APIKeySHA256 = slices.Compact(entry.APIKeySHA256)
+		return entry, nil
+	}
+
+	policy := indexAuthPolicy{Indexes: map[string]indexAuthPolicyEntry{}}
+	for index, access := range auth.Indexes {
+		entry, err := policyEntry(access)
+		if err != nil {
+			return nil, err
+		}
+		policy.Indexes[index] = entry
+	}
+	admins := v1beta1.IndexAccessSpec{APIKeys: []string{v1beta1.IndexAuthMaintenanceKeyName}}
+	if auth.Admins != nil {
+		admins.ServiceAccounts = auth.Admins.ServiceAccounts
+		admins.APIKeys = append(admins.APIKeys, auth.Admins.APIKeys...)
+	}
+	var err error
+	if policy.Admins, err = policyEntry(admins); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render index authorization policy: %w", err)
+	}
+	data[v1beta1.IndexAuthPolicyFileName] = raw
+	return data, nil
+}
+
+func generateAPIKey() ([]byte, error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return []byte(base64.RawURLEncoding.EncodeToString(buf)), nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestEnsureIndexAuthSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			Authorization: &v1beta1.IndexAuthorizationSpec{Indexes: map[string]v1beta1.IndexAccessSpec{
+				"team-a": {APIKeys: []string{"team-a-key", "shared-key"}},
+				"team-b": {APIKeys: []string{"shared-key"}, ServiceAccounts: []string{"team-b/indexer"}},
+			}},
+		},
+	}
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).Build()
+	key := ctrlclient.ObjectKey{Name: "rag-api-keys", Namespace: "default"}
+
+	readSecret := func() (*corev1.Secret, indexAuthPolicy) {
+		secret := &corev1.Secret{}
+		require.NoError(t, kubeClient.Get(ctx, key, secret))
+		var policy indexAuthPolicy
+		require.NoError(t, json.Unmarshal(secret.Data[v1beta1.IndexAuthPolicyFileName], &policy))
+		return secret, policy
+	}
+	digest := func(key []byte) string {
+		sum := sha256.Sum256(key)
+		return hex.EncodeToString(sum[:])
+	}
+
+	require.NoError(t, ensureIndexAuthSecret(ctx, ragEngine, kubeClient))
+	secret, policy := readSecret()
+	assert.True(t, metav1.IsControlledBy(secret, ragEngine))
+	assert.Len(t, secret.Data, 4)
+	maintenanceKey := secret.Data[v1beta1.IndexAuthMaintenanceKeyName]
+	assert.NotEmpty(t, maintenanceKey)
+	assert.Equal(t, []string{digest(maintenanceKey)}, policy.Admins.APIKeySHA256, "only the maintenance key is an admin by default")
+	sharedKey := secret.Data["shared-key"]
+	assert.NotEmpty(t, sharedKey)
+	assert.NotEqual(t, sharedKey, secret.Data["team-a-key"])
+	assert.ElementsMatch(t, []string{digest(sharedKey), digest(secret.Data["team-a-key"])}, policy.Indexes["team-a"].APIKeySHA256)
+	assert.Equal(t, []string{digest(sharedKey)}, policy.Indexes["team-b"].APIKeySHA256)
+	assert.Equal(t, []string{"team-b/indexer"}, policy.Indexes["team-b"].ServiceAccounts)
+	assert.NotContains(t, string(secret.Data[v1beta1.IndexAuthPolicyFileName]), string(sharedKey), "policy must not contain API keys")
+
+	// Removing a key name revokes it while other keys are kept.
+	ragEngine.Spec.Authorization.Indexes["team-a"] = v1beta1.IndexAccessSpec{APIKeys: []string{"shared-key"}}
+	require.NoError(t, ensureIndexAuthSecret(ctx, ragEngine, kubeClient))
+	secret, policy = readSecret()
+	assert.Equal(t, sharedKey, secret.Data["shared-key"])
+	assert.NotContains(t, secret.Data, "team-a-key")
+	assert.Equal(t, []string{digest(sharedKey)}, policy.Indexes["team-a"].APIKeySHA256)
+
+	// Admins share API keys with the indexes and keep the maintenance key.
+	ragEngine.Spec.Authorization.Admins = &v1beta1.IndexAccessSpec{APIKeys: []string{"shared-key"}, ServiceAccounts: []string{"ops/backup"}}
+	require.NoError(t, ensureIndexAuthSecret(ctx, ragEngine, kubeClient))
+	secret, policy = readSecret()
+	assert.Equal(t, maintenanceKey, secret.Data[v1beta1.IndexAuthMaintenanceKeyName])
+	assert.ElementsMatch(t, []string{digest(maintenanceKey), digest(sharedKey)}, policy.Admins.APIKeySHA256)
+	assert.Equal(t, []string{"ops/backup"}, policy.Admins.ServiceAccounts)
+
+	require.NoError(t, deleteIndexAuthSecret(ctx, ragEngine, kubeClient))
+	assert.True(t, apierrors.IsNotFound(kubeClient.Get(ctx, key, &corev1.Secret{})))
+}
+
+func TestEnsureIndexAuthSecretNotOwned(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag-api-keys", Namespace: "default"},
+	}).Build()
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			Authorization: &v1beta1.IndexAuthorizationSpec{Indexes: map[string]v1beta1.IndexAccessSpec{
+				"team-a": {APIKeys: []string{"team-a-key"}},
+			}},
+		},
+	}
+
+	err := ensureIndexAuthSecret(context.Background(), ragEngine, kubeClient)
+	assert.ErrorContains(t, err, "is not owned by ragengine rag")
+}
+
+func TestEnsureIndexAuthServiceAccount(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, rbacv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			Authorization: &v1beta1.IndexAuthorizationSpec{Indexes: map[string]v1beta1.IndexAccessSpec{
+				"team-b": {ServiceAccounts: []string{"team-b/indexer"}},
+			}},
+		},
+	}
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).Build()
+	bindingKey := ctrlclient.ObjectKey{Name: "kaito:ragengine:default:rag"}
+
+	// Applying twice must not fail on the existing objects.
+	require.NoError(t, ensureIndexAuthServiceAccount(ctx, ragEngine, kubeClient))
+	require.NoError(t, ensureIndexAuthServiceAccount(ctx, ragEngine, kubeClient))
+	serviceAccount := &corev1.ServiceAccount{}
+	require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKey{Name: "rag-index-auth", Namespace: "default"}, serviceAccount))
+	assert.True(t, metav1.IsControlledBy(serviceAccount, ragEngine))
+	binding := &rbacv1.ClusterRoleBinding{}
+	require.NoError(t, kubeClient.Get(ctx, bindingKey, binding))
+	assert.Equal(t, "system:auth-delegator", binding.RoleRef.Name)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "rag-index-auth", Namespace: "default"}}, binding.Subjects)
+
+	require.NoError(t, deleteIndexAuthClusterRoleBinding(ctx, ragEngine, kubeClient))
+	assert.True(t, apierrors.IsNotFound(kubeClient.Get(ctx, bindingKey, &rbacv1.ClusterRoleBinding{})))
+
+	// A binding of the same name that the RAGEngine does not manage is left alone.
+	require.NoError(t, kubeClient.Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: bindingKey.Name},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	}))
+	assert.ErrorContains(t, ensureIndexAuthServiceAccount(ctx, ragEngine, kubeClient), "is not managed by ragengine rag")
+	require.NoError(t, deleteIndexAuthClusterRoleBinding(ctx, ragEngine, kubeClient))
+	assert.NoError(t, kubeClient.Get(ctx, bindingKey, &rbacv1.ClusterRoleBinding{}))
+}
@@ -0,0 +1,266 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+// autoIndexerListGVK is the list kind of the AutoIndexer CRD, which is installed by the
+// separate AutoIndexer chart and therefore accessed without typed clients.
+var autoIndexerListGVK = schema.GroupVersionKind{Group: "autoindexer.kaito.sh", Version: "v1alpha1", Kind: "AutoIndexerList"}
+
+// ensureIndexEvictionCronJob keeps the index eviction CronJob of ragEngineObj in sync with
+// the document TTLs of spec.indexPolicies. When no index has a TTL, the CronJob is deleted;
+// status.indexes tells whether one ran, so RAGEngines that never set a TTL are not looked up.
+func (c *RAGEngineReconciler) ensureIndexEvictionCronJob(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	name := v1beta1.IndexEvictionCronJobName(ragEngineObj.Name)
+	if !manifests.HasIndexDocumentTTL(ragEngineObj) {
+		if !slices.ContainsFunc(ragEngineObj.Status.Indexes, func(s v1beta1.RAGIndexStatus) bool { return s.LastEvictionTime != nil }) {
+			return nil
+		}
+		klog.InfoS("Document TTLs removed, deleting index eviction cronjob", "ragengine", klog.KObj(ragEngineObj), "cronjob", name)
+		if err := client.IgnoreNotFound(c.Client.Delete(ctx, &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ragEngineObj.Namespace},
+		})); err != nil {
+			return fmt.Errorf("failed to delete index eviction cronjob %s: %w", name, err)
+		}
+		return c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+			status.Indexes = refreshOnlyIndexStatus(ragEngineObj, status.Indexes)
+		})
+	}
+
+	desired := manifests.GenerateIndexEvictionCronJobManifest(ragEngineObj, getImageConfig().GetImage())
+	existing := &batchv1.CronJob{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, existing)
+	if apierrors.IsNotFound(err) {
+		if err := resources.CreateResource(ctx, desired, c.Client); err != nil {
+			return fmt.Errorf("failed to create index eviction cronjob %s: %w", name, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get index eviction cronjob %s: %w", name, err)
+	} else {
+		if !metav1.IsControlledBy(existing, ragEngineObj) {
+			return fmt.Errorf("cronjob %s already exists and is not owned by ragengine %s", name, ragEngineObj.Name)
+		}
+		if !apiequality.Semantic.DeepEqual(existing.Spec.JobTemplate.Spec.Template.Spec.Containers, desired.Spec.JobTemplate.Spec.Template.Spec.Containers) {
+			existing.Spec = desired.Spec
+			if err := c.Client.Update(ctx, existing); err != nil {
+				return fmt.Errorf("failed to update index eviction cronjob %s: %w", name, err)
+			}
+		}
+	}
+	return c.syncIndexEvictionStatus(ctx, ragEngineObj)
+}
+
+// indexEvictionResult is the termination message of the index eviction container.
+type indexEvictionResult struct {
+	Indexes []struct {
+		Name      string `json:"name"`
+		Documents int64  `json:"documents"`
+		Evicted   int64  `json:"evicted"`
+	} `json:"indexes"`
+}
+
+// syncIndexEvictionStatus copies the document counts reported by the most recent
+// successful index eviction to status.indexes.
+func (c *RAGEngineReconciler) syncIndexEvictionStatus(ctx context.Context, ragEngineObj *v1beta1.RAGEngine) error {
+	pods := &corev1.PodList{}
+	if err := c.Client.List(ctx, pods, client.InNamespace(ragEngineObj.Namespace),
+		client.MatchingLabels{v1beta1.LabelRAGEngineIndexEviction: ragEngineObj.Name}); err != nil {
+		return fmt.Errorf("failed to list index eviction pods: %w", err)
+	}
+	indexes := indexStatusFromEvictionPods(ragEngineObj, ragEngineObj.Status.Indexes, pods.Items)
+	if apiequality.Semantic.DeepEqual(ragEngineObj.Status.Indexes, indexes) {
+		return nil
+	}
+	return c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+		status.Indexes = indexes
+	})
+}
+
+// indexStatusFromEvictionPods merges the report of the newest successful eviction pod into
+// current. The report lists every index of the RAG service, so indexes it does not list
+// were deleted and are dropped unless a refresh is scheduled for them.
+func indexStatusFromEvictionPods(ragEngineObj *v1beta1.RAGEngine, current []v1beta1.RAGIndexStatus, pods []corev1.Pod) []v1beta1.RAGIndexStatus {
+	var latest *corev1.ContainerStateTerminated
+	for i := range pods {
+		for _, s := range pods[i].Status.ContainerStatuses {
+			t := s.State.Terminated
+			if s.Name != manifests.IndexEvictionContainerName || t == nil || t.ExitCode != 0 {
+				continue
+			}
+			if latest == nil || latest.FinishedAt.Before(&t.FinishedAt) {
+				latest = t
+			}
+		}
+	}
+	if latest == nil {
+		return current
+	}
+	if slices.ContainsFunc(current, func(s v1beta1.RAGIndexStatus) bool {
+		return s.LastEvictionTime != nil && !s.LastEvictionTime.Before(&latest.FinishedAt)
+	}) {
+		return current
+	}
+	var result indexEvictionResult
+	if err := json.Unmarshal([]byte(latest.Message), &result); err != nil {
+		klog.ErrorS(err, "failed to parse index eviction report", "ragengine", klog.KObj(ragEngineObj))
+		return current
+	}
+
+	ttls := map[string]bool{}
+	for _, policy := range ragEngineObj.Spec.IndexPolicies {
+		ttls[policy.Name] = policy.DocumentTTL != nil
+	}
+	indexes := refreshOnlyIndexStatus(ragEngineObj, current)
+	for _, reported := range result.Indexes {
+		idx := slices.IndexFunc(indexes, func(s v1beta1.RAGIndexStatus) bool { return s.Name == reported.Name })
+		if idx < 0 {
+			indexes = append(indexes, v1beta1.RAGIndexStatus{Name: reported.Name})
+			idx = len(indexes) - 1
+		}
+		indexes[idx].DocumentCount = reported.Documents
+		if ttls[reported.Name] {
+			indexes[idx].LastEvictionTime = latest.FinishedAt.DeepCopy()
+			indexes[idx].EvictedDocuments = reported.Evicted
+		}
+	}
+	slices.SortFunc(indexes, func(a, b v1beta1.RAGIndexStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return indexes
+}
+
+// refreshOnlyIndexStatus returns the refresh status of the indexes that have a refresh
+// schedule, without the results of evictions.
+func refreshOnlyIndexStatus(ragEngineObj *v1beta1.RAGEngine, current []v1beta1.RAGIndexStatus) []v1beta1.RAGIndexStatus {
+	var indexes []v1beta1.RAGIndexStatus
+	for _, s := range current {
+		if slices.ContainsFunc(ragEngineObj.Spec.IndexPolicies, func(p v1beta1.IndexPolicySpec) bool {
+			return p.Name == s.Name && p.RefreshSchedule != ""
+		}) {
+			indexes = append(indexes, v1beta1.RAGIndexStatus{Name: s.Name, LastRefreshTime: s.LastRefreshTime})
+		}
+	}
+	return indexes
+}
+
+// ensureIndexRefreshes asks the AutoIndexers of every index whose refresh schedule is due
+// to refresh, and returns how long to wait until the next refresh is due. Refreshes are
+// measured from the last refresh, or from the creation of the RAGEngine.
+func (c *RAGEngineReconciler) ensureIndexRefreshes(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, now time.Time) (time.Duration, error) {
+	var due []string
+	var requeueAfter time.Duration
+	for _, policy := range ragEngineObj.Spec.IndexPolicies {
+		if policy.RefreshSchedule == "" {
+			continue
+		}
+		schedule, err := cron.ParseStandard(policy.RefreshSchedule)
+		if err != nil {
+			// The webhook rejects invalid schedules; skip ones that predate it.
+			klog.ErrorS(err, "invalid index refresh schedule", "ragengine", klog.KObj(ragEngineObj), "index", policy.Name)
+			continue
+		}
+		last := ragEngineObj.CreationTimestamp.Time
+		if idx := slices.IndexFunc(ragEngineObj.Status.Indexes, func(s v1beta1.RAGIndexStatus) bool { return s.Name == policy.Name }); idx >= 0 &&
+			ragEngineObj.Status.Indexes[idx].LastRefreshTime != nil {
+			last = ragEngineObj.Status.Indexes[idx].LastRefreshTime.Time
+		}
+		next := schedule.Next(last.UTC())
+		if !now.Before(next) {
+			due = append(due, policy.Name)
+			next = schedule.Next(now.UTC())
+		}
+		if wait := next.Sub(now); requeueAfter == 0 || wait < requeueAfter {
+			requeueAfter = wait
+		}
+	}
+	if len(due) == 0 {
+		return requeueAfter, nil
+	}
+
+	if err := c.requestAutoIndexerRefresh(ctx, ragEngineObj, due, now); err != nil {
+		return 0, err
+	}
+	refreshed := metav1.NewTime(now)
+	return requeueAfter, c.updateRAGEngineStatusWith(ctx, ragEngineObj, func(status *v1beta1.RAGEngineStatus) {
+		for _, name := range due {
+			idx := slices.IndexFunc(status.Indexes, func(s v1beta1.RAGIndexStatus) bool { return s.Name == name })
+			if idx < 0 {
+				status.Indexes = append(status.Indexes, v1beta1.RAGIndexStatus{Name: name})
+				idx = len(status.Indexes) - 1
+			}
+			status.Indexes[idx].LastRefreshTime = &refreshed
+		}
+		slices.SortFunc(status.Indexes, func(a, b v1beta1.RAGIndexStatus) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	})
+}
+
+// requestAutoIndexerRefresh sets the refresh annotation on the AutoIndexers of ragEngineObj
+// that write to one of indexNames. Without the AutoIndexer CRD there is nothing to refresh.
+func (c *RAGEngineReconciler) requestAutoIndexerRefresh(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, indexNames []string, now time.Time) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(autoIndexerListGVK)
+	if err := c.Client.List(ctx, list, client.InNamespace(ragEngineObj.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			klog.InfoS("AutoIndexer CRD is not installed, skipping index refresh", "ragengine", klog.KObj(ragEngineObj), "indexes", indexNames)
+			return nil
+		}
+		return fmt.Errorf("failed to list autoindexers: %w", err)
+	}
+	for i := range list.Items {
+		autoIndexer := &list.Items[i]
+		ragEngine, _, _ := unstructured.NestedString(autoIndexer.Object, "spec", "ragEngine")
+		indexName, _, _ := unstructured.NestedString(autoIndexer.Object, "spec", "indexName")
+		if ragEngine != ragEngineObj.Name || !slices.Contains(indexNames, indexName) {
+			continue
+		}
+		patch := client.MergeFrom(autoIndexer.DeepCopy())
+		annotations := autoIndexer.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1beta1.AnnotationRefreshRequestedAt] = now.UTC().Format(time.RFC3339)
+		autoIndexer.SetAnnotations(annotations)
+		if err := c.Client.Patch(ctx, autoIndexer, patch); err != nil {
+			return fmt.Errorf("failed to request refresh of autoindexer %s: %w", autoIndexer.GetName(), err)
+		}
+		klog.InfoS("Requested index refresh", "ragengine", klog.KObj(ragEngineObj), "autoindexer", autoIndexer.GetName(), "index", indexName)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+)
+
+func newEvictionTestPod(name string, finished time.Time, exitCode int32, message string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{v1beta1.LabelRAGEngineIndexEviction: "rag"},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name: manifests.IndexEvictionContainerName,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode:   exitCode,
+				FinishedAt: metav1.NewTime(finished),
+				Message:    message,
+			}},
+		}}},
+	}
+}
+
+func TestIndexStatusFromEvictionPods(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	refreshed := metav1.NewTime(now.Add(-time.Hour))
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default"},
+		Spec: &v1beta1.RAGEngineSpec{IndexPolicies: []v1beta1.IndexPolicySpec{
+			{Name: "docs", DocumentTTL: &metav1.Duration{Duration: 24 * time.Hour}},
+			{Name: "wiki", RefreshSchedule: "@daily"},
+		}},
+	}
+	current := []v1beta1.RAGIndexStatus{
+		{Name: "old", DocumentCount: 3},
+		{Name: "wiki", DocumentCount: 9, LastRefreshTime: &refreshed},
+	}
+	pods := []corev1.Pod{
+		newEvictionTestPod("evict-1", now.Add(-30*time.Minute), 0, `{"indexes": [{"name": "docs", "documents": 1, "evicted": 0}]}`),
+		newEvictionTestPod("evict-2", now, 0, `{"indexes": [{"name": "wiki", "documents": 7, "evicted": 0}, {"name": "docs", "documents": 5, "evicted": 2}]}`),
+		newEvictionTestPod("evict-3", now.Add(time.Minute), 1, "connection refused"),
+	}
+
+	indexes := indexStatusFromEvictionPods(ragEngine, current, pods)
+	require.Len(t, indexes, 2)
+	assert.Equal(t, "docs", indexes[0].Name)
+	assert.Equal(t, int64(5), indexes[0].DocumentCount)
+	assert.Equal(t, int64(2), indexes[0].EvictedDocuments)
+	require.NotNil(t, indexes[0].LastEvictionTime)
+	assert.True(t, indexes[0].LastEvictionTime.Time.Equal(now))
+	// Indexes without a TTL only report their documents; deleted indexes are dropped.
+	assert.Equal(t, v1beta1.RAGIndexStatus{Name: "wiki", DocumentCount: 7, LastRefreshTime: &refreshed}, indexes[1])
+
+	// A report that was already applied is not applied again.
+	indexes[0].DocumentCount = 6
+	assert.Equal(t, indexes, indexStatusFromEvictionPods(ragEngine, indexes, pods))
+	// Without a successful eviction the status is kept.
+	assert.Equal(t, current, indexStatusFromEvictionPods(ragEngine, current, pods[2:]))
+}
+
+func TestEnsureIndexEvictionCronJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{IndexPolicies: []v1beta1.IndexPolicySpec{
+			{Name: "docs", DocumentTTL: &metav1.Duration{Duration: 24 * time.Hour}},
+		}},
+	}
+	pod := newEvictionTestPod("evict-1", time.Now().Truncate(time.Second), 0, `{"indexes": [{"name": "docs", "documents": 5, "evicted": 2}]}`)
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ragEngine.DeepCopy(), &pod).
+		WithStatusSubresource(&v1beta1.RAGEngine{}).
+		Build()
+	reconciler := &RAGEngineReconciler{Client: kubeClient, Scheme: scheme}
+	key := ctrlclient.ObjectKey{Name: "rag-index-eviction", Namespace: "default"}
+	latest := func() *v1beta1.RAGEngine {
+		obj := &v1beta1.RAGEngine{}
+		require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKeyFromObject(ragEngine), obj))
+		return obj
+	}
+
+	require.NoError(t, reconciler.ensureIndexEvictionCronJob(ctx, ragEngine))
+	cronJob := &batchv1.CronJob{}
+	require.NoError(t, kubeClient.Get(ctx, key, cronJob))
+	assert.Equal(t, manifests.IndexEvictionSchedule, cronJob.Spec.Schedule)
+	obj := latest()
+	require.Len(t, obj.Status.Indexes, 1)
+	assert.Equal(t, int64(2), obj.Status.Indexes[0].EvictedDocuments)
+
+	// Removing the TTLs deletes the CronJob and the eviction status.
+	obj.Spec.IndexPolicies = nil
+	require.NoError(t, reconciler.ensureIndexEvictionCronJob(ctx, obj))
+	assert.True(t, apierrors.IsNotFound(kubeClient.Get(ctx, key, &batchv1.CronJob{})))
+	assert.Empty(t, latest().Status.Indexes)
+}
+
+func TestEnsureIndexRefreshes(t *testing.T) {
+	listGVK := autoIndexerListGVK
+	gvk := schema.GroupVersionKind{Group: listGVK.Group, Version: listGVK.Version, Kind: "AutoIndexer"}
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(listGVK, &unstructured.UnstructuredList{})
+	ctx := context.Background()
+
+	newAutoIndexer := func(name, ragEngine, indexName string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"ragEngine": ragEngine, "indexName": indexName},
+		}}
+		u.SetGroupVersionKind(gvk)
+		u.SetName(name)
+		u.SetNamespace("default")
+		return u
+	}
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Spec: &v1beta1.RAGEngineSpec{IndexPolicies: []v1beta1.IndexPolicySpec{
+			{Name: "docs", RefreshSchedule: "0 2 * * *"},
+			{Name: "wiki", RefreshSchedule: "0 */6 * * *"},
+		}},
+	}
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ragEngine.DeepCopy(),
+			newAutoIndexer("docs-git", "rag", "docs"),
+			newAutoIndexer("wiki-git", "rag", "wiki"),
+			newAutoIndexer("other-rag", "other", "docs")).
+		WithStatusSubresource(&v1beta1.RAGEngine{}).
+		Build()
+	reconciler := &RAGEngineReconciler{Client: kubeClient, Scheme: scheme}
+	refreshRequestedAt := func(name string) string {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKey{Name: name, Namespace: "default"}, u))
+		return u.GetAnnotations()[v1beta1.AnnotationRefreshRequestedAt]
+	}
+
+	// At 03:00 the docs refresh at 02:00 is due, the wiki one is due at 06:00.
+	now := created.Add(3 * time.Hour)
+	requeueAfter, err := reconciler.ensureIndexRefreshes(ctx, ragEngine, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Hour, requeueAfter)
+	assert.Equal(t, "2025-01-01T03:00:00Z", refreshRequestedAt("docs-git"))
+	assert.Empty(t, refreshRequestedAt("wiki-git"))
+	assert.Empty(t, refreshRequestedAt("other-rag"))
+
+	obj := &v1beta1.RAGEngine{}
+	require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKeyFromObject(ragEngine), obj))
+	require.Len(t, obj.Status.Indexes, 1)
+	assert.Equal(t, "docs", obj.Status.Indexes[0].Name)
+	assert.True(t, obj.Status.Indexes[0].LastRefreshTime.Time.Equal(now))
+
+	// The docs index is not refreshed again before the next day.
+	requeueAfter, err = reconciler.ensureIndexRefreshes(ctx, obj, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, requeueAfter)
+	assert.Empty(t, refreshRequestedAt("wiki-git"))
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -196,6 +197,84 @@ func ensureGuardrailsPolicyConfigMap(ctx context.Context, ragEngineObj *v1beta1.
 }
 
 func CreatePresetRAG(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, revisionNum string, kubeClient client.Client) (client.Object, error) {
+	depObj, err := generatePresetRAG(ctx, ragEngineObj, revisionNum, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if ragEngineObj.Spec.Sharding != nil {
+		// The router of a sharded RAGEngine neither embeds nor stores documents.
+		depObj.Spec.Template.Spec.Containers[0].Resources = defaultResourceRequirements()
+	}
+
+	err = resources.CreateResource(ctx, depObj, kubeClient)
+	if client.IgnoreAlreadyExists(err) != nil {
+		return nil, err
+	}
+	return depObj, nil
+}
+
+// CreatePresetRAGShard creates the Deployment of the index worker of a shard from depObj,
+// the Deployment of the RAG service returned by generatePresetRAG.
+func CreatePresetRAGShard(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, depObj *appsv1.Deployment, shard int, kubeClient client.Client) (*appsv1.Deployment, error) {
+	shardObj := manifests.GenerateRAGShardDeploymentManifest(ragEngineObj, depObj, shard)
+	container := &shardObj.Spec.Template.Spec.Containers[0]
+	container.Resources = shardResourceRequirements(ragEngineObj, container.Resources)
+
+	err := resources.CreateResource(ctx, shardObj, kubeClient)
+	if client.IgnoreAlreadyExists(err) != nil {
+		return nil, err
+	}
+	return shardObj, nil
+}
+
+// shardResourceRequirements splits the GPUs of the compute nodes between the index workers,
+// which all run the local embedding model, so that every shard fits next to the others.
+// Requirements without GPUs are returned unchanged.
+func shardResourceRequirements(ragEngineObj *v1beta1.RAGEngine, req corev1.ResourceRequirements) corev1.ResourceRequirements {
+	gpu := corev1.ResourceName(nodes.CapacityNvidiaGPU)
+	perNode, ok := req.Requests[gpu]
+	if !ok {
+		return req
+	}
+	nodeCount := 1
+	if compute := ragEngineObj.Spec.Compute; compute != nil && compute.Count != nil && *compute.Count > 1 {
+		nodeCount = *compute.Count
+	}
+	shardsPerNode := (int(ragEngineObj.Spec.Sharding.Shards) + nodeCount - 1) / nodeCount
+	gpus := *resource.NewQuantity(max(perNode.Value()/int64(shardsPerNode), 1), resource.DecimalSI)
+
+	sized := req.DeepCopy()
+	sized.Requests[gpu] = gpus
+	if sized.Limits == nil {
+		sized.Limits = corev1.ResourceList{}
+	}
+	sized.Limits[gpu] = gpus
+	return *sized
+}
+
+// defaultResourceRequirements are the requests of a RAG service that runs no embedding model on a GPU.
+func defaultResourceRequirements() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+}
+
+// cpuEmbeddingResourceRequirements are the requests of a RAG service that runs its embedding
+// model on a dedicated CPU node.
+func cpuEmbeddingResourceRequirements() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+}
+
+// generatePresetRAG returns the Deployment of the RAG service.
+func generatePresetRAG(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, revisionNum string, kubeClient client.Client) (*appsv1.Deployment, error) {
 	var volumes []corev1.Volume
 	var volumeMounts []corev1.VolumeMount
 
@@ -222,8 +301,11 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, revis
 
 	var resourceReq corev1.ResourceRequirements
 
-	if ragEngineObj.Spec.Embedding.Local != nil && ragEngineObj.Spec.Compute != nil && ragEngineObj.Spec.Compute.InstanceType != "" {
-		instanceType := ragEngineObj.Spec.Compute.InstanceType
+	if ragEngineObj.Spec.Embedding.Local != nil && ragEngineObj.EmbeddingOnCPU() && ragEngineObj.InstanceType() != "" {
+		// The embedding model runs on a dedicated CPU node, so request CPU and memory instead of GPUs.
+		resourceReq = cpuEmbeddingResourceRequirements()
+	} else if ragEngineObj.Spec.Embedding.Local != nil && ragEngineObj.InstanceType() != "" {
+		instanceType := ragEngineObj.InstanceType()
 		gpuConfig, err := sku.GetGPUConfigBySKU(instanceType)
 		// If GetGPUConfigBySKU returns error, skip GPU resource allocation (e.g., CPU-only instances)
 		if err == nil && gpuConfig != nil {
@@ -241,12 +323,7 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, revis
 	} else {
 		// If embedding is remote or compute instance type is not specified, do not allocate GPU resources by default
 		// and apply default CPU and memory requests to ensure the pod can be scheduled.
-		resourceReq = corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("500m"),
-				corev1.ResourceMemory: resource.MustParse("512Mi"),
-			},
-		}
+		resourceReq = defaultResourceRequirements()
 	}
 	commands := utils.ShellCmd("python3 main.py")
 
@@ -256,10 +333,8 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *v1beta1.RAGEngine, revis
 
 	depObj := manifests.GenerateRAGDeploymentManifest(ragEngineObj, revisionNum, image, imagePullSecretRefs, commands,
 		containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
-
-	err = resources.CreateResource(ctx, depObj, kubeClient)
-	if client.IgnoreAlreadyExists(err) != nil {
-		return nil, err
-	}
+	manifests.SetIndexAuthVolume(ragEngineObj, &depObj.Spec.Template.Spec)
+	manifests.SetRemoteEmbeddingHeadersVolume(ragEngineObj, &depObj.Spec.Template.Spec)
+	manifests.SetRestoreInitContainer(ragEngineObj, &depObj.Spec.Template.Spec, image)
 	return depObj, nil
 }
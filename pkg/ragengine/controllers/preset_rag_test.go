@@ -229,6 +229,59 @@ func TestGPUConfigLogic(t *testing.T) {
 	}
 }
 
+func TestCPUEmbeddingResources(t *testing.T) {
+	test.RegisterTestModel()
+
+	testcases := map[string]struct {
+		instanceType string
+		accelerator  v1beta1.EmbeddingAccelerator
+	}{
+		"default CPU instance type": {},
+		"explicit CPU instance type": {
+			instanceType: "Standard_D8s_v5",
+		},
+		"CPU accelerator with an unknown model": {
+			instanceType: "Standard_D16s_v5",
+			accelerator:  v1beta1.EmbeddingAcceleratorCPU,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+			mockClient := test.NewClient()
+			mockClient.On("Create", mock.IsType(context.TODO()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+
+			ragEngineObj := test.MockRAGEngineWithPreset.DeepCopy()
+			ragEngineObj.Spec.Compute.InstanceType = tc.instanceType
+			ragEngineObj.Spec.Embedding.Local.Accelerator = tc.accelerator
+			if tc.accelerator == v1beta1.EmbeddingAcceleratorCPU {
+				ragEngineObj.Spec.Embedding.Local.ModelID = "example/large-embedding-model"
+			}
+
+			createdObject, err := CreatePresetRAG(context.TODO(), ragEngineObj, "1", mockClient)
+			if err != nil {
+				t.Fatalf("CreatePresetRAG() unexpected error: %v", err)
+			}
+
+			resourceReq := createdObject.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Resources
+			if _, exists := resourceReq.Requests[corev1.ResourceName(nodes.CapacityNvidiaGPU)]; exists {
+				t.Errorf("GPU requests found in resource requirements of a CPU embedding model")
+			}
+			if _, exists := resourceReq.Limits[corev1.ResourceName(nodes.CapacityNvidiaGPU)]; exists {
+				t.Errorf("GPU limits found in resource requirements of a CPU embedding model")
+			}
+			if cpu := resourceReq.Requests[corev1.ResourceCPU]; cpu.String() != "2" {
+				t.Errorf("CPU request is not expected, got %s, expected 2", cpu.String())
+			}
+			if memory := resourceReq.Requests[corev1.ResourceMemory]; memory.String() != "4Gi" {
+				t.Errorf("memory request is not expected, got %s, expected 4Gi", memory.String())
+			}
+		})
+	}
+}
+
 func TestCreatePresetRAGGuardrailsUsesDefaultTemplate(t *testing.T) {
 	t.Setenv(consts.DefaultReleaseNamespaceEnvVar, "kaito-system")
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
@@ -27,13 +27,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -45,6 +44,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
@@ -52,6 +52,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/utils/nodes"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
@@ -68,6 +69,9 @@ type RAGEngineReconciler struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// NodeClaimCRD tracks whether the NodeClaim CRD is installed. If nil, it is assumed
+	// to be installed.
+	NodeClaimCRD *crdwatch.Watcher
 }
 
 func NewRAGEngineReconciler(client client.Client, scheme *runtime.Scheme, log logr.Logger, Recorder record.EventRecorder) *RAGEngineReconciler {
@@ -125,7 +129,7 @@ func (c *RAGEngineReconciler) ensureFinalizer(ctx context.Context, ragEngineObj
 
 func (c *RAGEngineReconciler) addRAGEngine(ctx context.Context, ragEngineObj *kaitov1beta1.RAGEngine) (reconcile.Result, error) {
 	var err error
-	if ragEngineObj.Spec.Compute != nil && ragEngineObj.Spec.Compute.InstanceType != "" {
+	if ragEngineObj.InstanceType() != "" {
 		err = c.applyRAGEngineResource(ctx, ragEngineObj)
 		if err != nil {
 			if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
@@ -152,7 +156,41 @@ func (c *RAGEngineReconciler) addRAGEngine(ctx context.Context, ragEngineObj *ka
 		}
 		return reconcile.Result{}, err
 	}
-	if err = c.applyRAG(ctx, ragEngineObj); err != nil {
+	err = c.applyRAG(ctx, ragEngineObj)
+	// The restore runs before the RAG service starts, so its progress is reported
+	// whether or not the deployment is ready.
+	if restoreErr := c.syncRestoreStatus(ctx, ragEngineObj); restoreErr != nil {
+		klog.ErrorS(restoreErr, "failed to update restore status", "ragengine", klog.KObj(ragEngineObj))
+	}
+	if err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
+			"ragengineFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	if err = c.ensureBackupCronJob(ctx, ragEngineObj); err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
+			"ragengineFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	if err = c.ensureIndexEvictionCronJob(ctx, ragEngineObj); err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
+			"ragengineFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+	// Refreshes are requested by the controller itself, so it requeues when the next one is due.
+	requeueAfter, err := c.ensureIndexRefreshes(ctx, ragEngineObj, time.Now())
+	if err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
 			"ragengineFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
@@ -166,7 +204,7 @@ func (c *RAGEngineReconciler) addRAGEngine(ctx context.Context, ragEngineObj *ka
 		klog.ErrorS(err, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (c *RAGEngineReconciler) ensureService(ctx context.Context, ragObj *kaitov1beta1.RAGEngine) error {
@@ -206,8 +244,24 @@ func (c *RAGEngineReconciler) applyRAG(ctx context.Context, ragEngineObj *kaitov
 	var err error
 	func() {
 
-		deployment := &appsv1.Deployment{}
+		if err = ensureIndexAuthSecret(ctx, ragEngineObj, c.Client); err != nil {
+			return
+		}
+		if err = ensureIndexAuthServiceAccount(ctx, ragEngineObj, c.Client); err != nil {
+			return
+		}
+		if err = c.ensureConversationMemory(ctx, ragEngineObj); err != nil {
+			return
+		}
+
 		revisionStr := ragEngineObj.Annotations[kaitov1beta1.RAGEngineRevisionAnnotation]
+		if ragEngineObj.Spec.Sharding != nil {
+			if err = c.applyRAGShards(ctx, ragEngineObj, revisionStr); err != nil {
+				return
+			}
+		}
+
+		deployment := &appsv1.Deployment{}
 
 		if err = resources.GetResource(ctx, ragEngineObj.Name, ragEngineObj.Namespace, c.Client, deployment); err == nil {
 			klog.InfoS("An inference workload already exists for ragengine", "ragengine", klog.KObj(ragEngineObj))
@@ -216,8 +270,24 @@ func (c *RAGEngineReconciler) applyRAG(ctx context.Context, ragEngineObj *kaitov
 				envs := manifests.RAGSetEnv(ragEngineObj)
 
 				spec := &deployment.Spec
+				if !managesConversationMemory(ragEngineObj) && manifests.UsesManagedConversationMemory(ragEngineObj, &spec.Template.Spec) {
+					if err = c.deleteConversationMemory(ctx, ragEngineObj); err != nil {
+						return
+					}
+				}
 				// Currently, all CRD changes are only passed through environment variables (env)
 				spec.Template.Spec.Containers[0].Env = envs
+				if ragEngineObj.Spec.Authorization == nil && manifests.HasIndexAuthVolume(&spec.Template.Spec) {
+					if err = deleteIndexAuthSecret(ctx, ragEngineObj, c.Client); err != nil {
+						return
+					}
+					if err = deleteIndexAuthClusterRoleBinding(ctx, ragEngineObj, c.Client); err != nil {
+						return
+					}
+				}
+				manifests.SetIndexAuthVolume(ragEngineObj, &spec.Template.Spec)
+				manifests.SetRemoteEmbeddingHeadersVolume(ragEngineObj, &spec.Template.Spec)
+				manifests.SetRestoreInitContainer(ragEngineObj, &spec.Template.Spec, getImageConfig().GetImage())
 				deployment.Annotations[kaitov1beta1.RAGEngineRevisionAnnotation] = revisionStr
 
 				if err := c.Update(ctx, deployment); err != nil {
@@ -367,8 +437,10 @@ func computeHash(ragEngineObj *kaitov1beta1.RAGEngine) string {
 // applyRAGEngineResource applies RAGEngine resource spec.
 func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEngineObj *kaitov1beta1.RAGEngine) error {
 	// Wait for pending nodeClaims if any before we decide whether to create new node or not.
-	if err := nodeclaim.WaitForPendingNodeClaims(ctx, ragEngineObj, c.Client); err != nil {
-		return err
+	if c.nodeClaimsServed() {
+		if err := nodeclaim.WaitForPendingNodeClaims(ctx, ragEngineObj, c.Client); err != nil {
+			return err
+		}
 	}
 
 	// Find all nodes that match the labelSelector and instanceType, they are not necessarily created by machines/nodeClaims.
@@ -380,6 +452,17 @@ func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEng
 	// RAGEngine requires exactly 1 node
 	var selectedNodes []*corev1.Node
 	if len(validNodes) == 0 {
+		// Without the NodeClaim CRD only existing nodes can be used.
+		if !c.nodeClaimsServed() {
+			msg := "no ready node matches the label selector and instance type, and nodes cannot be auto-provisioned because the NodeClaim CRD is not installed"
+			if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1beta1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
+				"NodeClaimCRDNotInstalled", msg); updateErr != nil {
+				klog.ErrorS(updateErr, "failed to update ragengine status", "ragengine", klog.KObj(ragEngineObj))
+				return updateErr
+			}
+			return fmt.Errorf("%s", msg)
+		}
+
 		// No existing nodes, need to create one
 		klog.InfoS("need to create a new node", "ragengine", klog.KObj(ragEngineObj))
 		if err := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj,
@@ -400,17 +483,24 @@ func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEng
 		}
 		selectedNodes = []*corev1.Node{newNode}
 	} else {
-		// Select the best qualified node from existing nodes
-		selectedNodes = utils.SelectNodes(validNodes, nil, ragEngineObj.Status.WorkerNodes, 1)
+		// Select the best qualified node from existing nodes, preferring one that caches the embedding model
+		var model string
+		if ragEngineObj.Spec.Embedding != nil && ragEngineObj.Spec.Embedding.Local != nil {
+			model = ragEngineObj.Spec.Embedding.Local.ModelID
+		}
+		selectedNodes = utils.SelectNodes(validNodes, nil, ragEngineObj.Status.WorkerNodes, model, 1)
 	}
 
 	// Ensure all gpu plugins are running successfully.
-	instanceType := ragEngineObj.Spec.Compute.InstanceType
+	instanceType := ragEngineObj.InstanceType()
 	knownGPUConfig, err := sku.GetGPUConfigBySKU(instanceType)
 	// If GetGPUConfigBySKU returns error, skip GPU plugin installation (e.g., CPU-only instances)
 	if err != nil {
 		klog.InfoS("Skipping GPU plugin installation, no GPU config found", "ragengine", klog.KObj(ragEngineObj), "instanceType", instanceType)
 		knownGPUConfig = nil
+	} else if ragEngineObj.EmbeddingOnCPU() {
+		klog.InfoS("Skipping GPU plugin installation, the embedding model runs on CPU", "ragengine", klog.KObj(ragEngineObj), "instanceType", instanceType)
+		knownGPUConfig = nil
 	}
 
 	if knownGPUConfig != nil {
@@ -484,7 +574,7 @@ func (c *RAGEngineReconciler) getAllQualifiedNodes(ctx context.Context, ragEngin
 		}
 
 		// match the instanceType
-		if strings.EqualFold(nodeObj.Labels[corev1.LabelInstanceTypeStable], ragEngineObj.Spec.Compute.InstanceType) {
+		if strings.EqualFold(nodeObj.Labels[corev1.LabelInstanceTypeStable], ragEngineObj.InstanceType()) {
 			qualifiedNodes = append(qualifiedNodes, lo.ToPtr(nodeObj))
 		}
 	}
@@ -578,31 +668,9 @@ func (c *RAGEngineReconciler) ensureNodePlugins(ctx context.Context, ragEngineOb
 	}
 }
 
-// isNodeClaimCRDAvailable checks if the Karpenter NodeClaim CRD is installed in the cluster
-func isNodeClaimCRDAvailable(mgr ctrl.Manager) bool {
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
-	if err != nil {
-		klog.ErrorS(err, "failed to create discovery client")
-		return false
-	}
-
-	// Check if karpenter.sh/v1 NodeClaim resource is available
-	gv := schema.GroupVersion{Group: "karpenter.sh", Version: "v1"}
-	resourceList, err := discoveryClient.ServerResourcesForGroupVersion(gv.String())
-	if err != nil {
-		klog.V(4).InfoS("Karpenter v1 resources not available", "error", err)
-		return false
-	}
-
-	for _, resource := range resourceList.APIResources {
-		if resource.Kind == "NodeClaim" {
-			klog.InfoS("Found Karpenter NodeClaim CRD, enabling NodeClaim watching")
-			return true
-		}
-	}
-
-	klog.InfoS("Karpenter NodeClaim CRD not found, disabling NodeClaim watching")
-	return false
+// nodeClaimsServed reports whether the NodeClaim CRD is installed.
+func (c *RAGEngineReconciler) nodeClaimsServed() bool {
+	return c.NodeClaimCRD == nil || c.NodeClaimCRD.Available()
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -612,16 +680,31 @@ func (c *RAGEngineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&kaitov1beta1.RAGEngine{}).
 		Owns(&appsv1.ControllerRevision{}).
-		Owns(&appsv1.Deployment{})
-
-	// Only watch NodeClaim resources if the CRD is actually installed
-	if isNodeClaimCRDAvailable(mgr) {
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&batchv1.CronJob{})
+
+	// Only watch NodeClaim resources if the CRD is actually installed. Otherwise the watch
+	// is added once it is.
+	if c.nodeClaimsServed() {
 		klog.InfoS("Karpenter NodeClaim CRD is available, setting up watch for NodeClaims")
 		bldr = bldr.Watches(&karpenterv1.NodeClaim{}, c.watchNodeClaims(), builder.WithPredicates(nodeclaim.NodeClaimPredicate))
 	}
 
-	return bldr.WithOptions(controller.Options{MaxConcurrentReconciles: 5}).
-		Complete(c)
+	ctrlr, err := bldr.WithOptions(controller.Options{MaxConcurrentReconciles: 5}).
+		Build(c)
+	if err != nil {
+		return err
+	}
+	if !c.nodeClaimsServed() {
+		klog.InfoS("Karpenter NodeClaim CRD not found, NodeClaims are watched once it is installed")
+		c.NodeClaimCRD.OnAvailable(func(context.Context) error {
+			return ctrlr.Watch(source.Kind(mgr.GetCache(), client.Object(&karpenterv1.NodeClaim{}), c.watchNodeClaims(),
+				nodeclaim.NodeClaimPredicate))
+		})
+	}
+	return nil
 }
 
 // watches for nodeClaim with labels indicating RAGEngine name.
@@ -31,9 +31,9 @@ import (
 func (c *RAGEngineReconciler) garbageCollectRAGEngine(ctx context.Context, ragEngineObj *kaitov1beta1.RAGEngine) (ctrl.Result, error) {
 	klog.InfoS("garbageCollectRAGEngine", "ragengine", klog.KObj(ragEngineObj))
 
-	// Only clean up NodeClaims when node auto-provisioning is enabled,
-	// since NodeClaim CRDs may not be installed when it's disabled.
-	if ragEngineObj.Spec.Compute != nil && !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+	// Only clean up NodeClaims when node auto-provisioning is enabled and the
+	// NodeClaim CRD is installed.
+	if ragEngineObj.Spec.Compute != nil && !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] && c.nodeClaimsServed() {
 		// Check if there are any nodeClaims associated with this ragengine.
 		ncList, err := nodeclaim.ListNodeClaim(ctx, ragEngineObj, c.Client)
 		if err != nil {
@@ -51,6 +51,12 @@ func (c *RAGEngineReconciler) garbageCollectRAGEngine(ctx context.Context, ragEn
 		}
 	}
 
+	if ragEngineObj.Spec.Authorization != nil {
+		if err := deleteIndexAuthClusterRoleBinding(ctx, ragEngineObj, c.Client); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	if controllerutil.RemoveFinalizer(ragEngineObj, consts.RAGEngineFinalizer) {
 		if updateErr := c.Update(ctx, ragEngineObj, &client.UpdateOptions{}); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to remove the finalizer from the ragengine",
@@ -17,47 +17,38 @@ import (
 	"context"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
 )
 
 func (c *RAGEngineReconciler) updateRAGEngineStatus(ctx context.Context, name *client.ObjectKey, condition *metav1.Condition, workerNodes []string) error {
-	return retry.OnError(retry.DefaultRetry,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
-		},
-		func() error {
-			// Read the latest version to avoid update conflict.
-			ragObj := &kaitov1beta1.RAGEngine{}
-			if err := c.Client.Get(ctx, *name, ragObj); err != nil {
-				if !apierrors.IsNotFound(err) {
-					return err
-				}
-				return nil
-			}
-			if condition != nil {
-				meta.SetStatusCondition(&ragObj.Status.Conditions, *condition)
-			}
-			if workerNodes != nil {
-				ragObj.Status.WorkerNodes = workerNodes
-			}
-			return c.Client.Status().Update(ctx, ragObj)
-		})
+	return statuspatch.Update(ctx, c.Client, *name, &kaitov1beta1.RAGEngine{}, func(ragObj *kaitov1beta1.RAGEngine) error {
+		if condition != nil {
+			meta.SetStatusCondition(&ragObj.Status.Conditions, *condition)
+		}
+		if workerNodes != nil {
+			ragObj.Status.WorkerNodes = workerNodes
+		}
+		applyRolloutStatus(ragObj)
+		return nil
+	})
 }
 
 func (c *RAGEngineReconciler) updateStatusConditionIfNotMatch(ctx context.Context, ragObj *kaitov1beta1.RAGEngine, cType kaitov1beta1.ConditionType,
 	cStatus metav1.ConditionStatus, cReason, cMessage string) error {
 	if curCondition := meta.FindStatusCondition(ragObj.Status.Conditions, string(cType)); curCondition != nil {
-		if curCondition.Status == cStatus && curCondition.Reason == cReason && curCondition.Message == cMessage {
+		if curCondition.Status == cStatus && curCondition.Reason == cReason && curCondition.Message == cMessage &&
+			curCondition.ObservedGeneration == ragObj.GetGeneration() {
 			// Nothing to change
 			return nil
 		}
@@ -85,3 +76,36 @@ func (c *RAGEngineReconciler) updateStatusNodeListIfNotMatch(ctx context.Context
 	klog.InfoS("updateStatusNodeList", "ragengine", klog.KObj(ragObj))
 	return c.updateRAGEngineStatus(ctx, &client.ObjectKey{Name: ragObj.Name, Namespace: ragObj.Namespace}, nil, nodeNameList)
 }
+
+// applyRolloutStatus sets status.phase, status.health, status.observedGeneration and the
+// Progressing condition of the RAGEngine from its other conditions.
+func applyRolloutStatus(ragObj *kaitov1beta1.RAGEngine) {
+	generation := ragObj.GetGeneration()
+	phase, message := ragEnginePhase(ragObj)
+	ragObj.Status.Phase = phase
+	ragObj.Status.ObservedGeneration = generation
+	ragObj.Status.Health = rollout.Health(generation, phase, message)
+	rollout.SetProgressing(&ragObj.Status.Conditions, generation, phase, message)
+}
+
+func ragEnginePhase(ragObj *kaitov1beta1.RAGEngine) (kaitov1beta1.Phase, string) {
+	conditions := ragObj.Status.Conditions
+	generation := ragObj.GetGeneration()
+	if !ragObj.DeletionTimestamp.IsZero() || meta.IsStatusConditionTrue(conditions, string(kaitov1beta1.RAGEngineConditionTypeDeleting)) {
+		return kaitov1beta1.PhaseDeleting, "ragengine is being deleted"
+	}
+
+	succeeded := rollout.CurrentCondition(conditions, kaitov1beta1.RAGEngineConditionTypeSucceeded, generation)
+	switch {
+	case succeeded != nil && succeeded.Status == metav1.ConditionTrue:
+		return kaitov1beta1.PhaseReady, succeeded.Message
+	case succeeded != nil && strings.EqualFold(succeeded.Reason, "ragengineFailed"):
+		return kaitov1beta1.PhaseFailed, succeeded.Message
+	case rollout.CurrentCondition(conditions, kaitov1beta1.ConditionTypeResourceStatus, generation) == nil:
+		return kaitov1beta1.PhasePending, "waiting for the ragengine resources"
+	case succeeded != nil:
+		return kaitov1beta1.PhaseProgressing, succeeded.Message
+	default:
+		return kaitov1beta1.PhaseProgressing, "ragengine is rolling out the latest spec"
+	}
+}
@@ -21,11 +21,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -47,7 +49,7 @@ func TestUpdateRAGEngineStatus(t *testing.T) {
 		workerNodes := []string{"node1", "node2"}
 
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
 
 		err := reconciler.updateRAGEngineStatus(ctx, &client.ObjectKey{Name: ragengine.Name, Namespace: ragengine.Namespace}, &condition, workerNodes)
 		assert.Nil(t, err)
@@ -114,7 +116,7 @@ func TestUpdateRAGEngineStatus(t *testing.T) {
 		workerNodes := []string{"node1"}
 
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
 
 		err := reconciler.updateRAGEngineStatus(ctx, &client.ObjectKey{Name: ragengine.Name, Namespace: ragengine.Namespace}, &condition, workerNodes)
 		assert.Nil(t, err)
@@ -170,7 +172,7 @@ func TestRAGEngineUpdateStatusConditionIfNotMatch(t *testing.T) {
 			},
 		}
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
 
 		err := reconciler.updateStatusConditionIfNotMatch(ctx, ragengine, conditionType, conditionStatus, conditionReason, conditionMessage)
 		assert.Nil(t, err)
@@ -198,9 +200,51 @@ func TestRAGEngineUpdateStatusConditionIfNotMatch(t *testing.T) {
 			},
 		}
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.RAGEngine{}), mock.Anything).Return(nil)
 
 		err := reconciler.updateStatusConditionIfNotMatch(ctx, ragengine, conditionType, conditionStatus, conditionReason, conditionMessage)
 		assert.Nil(t, err)
 	})
 }
+
+func TestRAGEngineRolloutStatus(t *testing.T) {
+	condition := func(cType kaitov1beta1.ConditionType, status metav1.ConditionStatus, reason string, generation int64) metav1.Condition {
+		return metav1.Condition{Type: string(cType), Status: status, Reason: reason, Message: reason, ObservedGeneration: generation}
+	}
+	resourceReady := condition(kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionTrue, "ragengineResourceStatusSuccess", 3)
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		expected   kaitov1beta1.Phase
+	}{
+		{name: "new", expected: kaitov1beta1.PhasePending},
+		{name: "resources ready", conditions: []metav1.Condition{resourceReady}, expected: kaitov1beta1.PhaseProgressing},
+		{name: "succeeded", conditions: []metav1.Condition{resourceReady,
+			condition(kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionTrue, "ragengineSucceeded", 3)}, expected: kaitov1beta1.PhaseReady},
+		{name: "succeeded for an older generation", conditions: []metav1.Condition{resourceReady,
+			condition(kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionTrue, "ragengineSucceeded", 2)}, expected: kaitov1beta1.PhaseProgressing},
+		{name: "failed", conditions: []metav1.Condition{
+			condition(kaitov1beta1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse, "ragEngineFailed", 3)}, expected: kaitov1beta1.PhaseFailed},
+		{name: "deleting", conditions: []metav1.Condition{
+			condition(kaitov1beta1.RAGEngineConditionTypeDeleting, metav1.ConditionTrue, "ragengineDeleted", 3)}, expected: kaitov1beta1.PhaseDeleting},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ragObj := &kaitov1beta1.RAGEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "rag", Generation: 3},
+				Status:     kaitov1beta1.RAGEngineStatus{Conditions: tt.conditions},
+			}
+			applyRolloutStatus(ragObj)
+			assert.Equal(t, tt.expected, ragObj.Status.Phase)
+			assert.Equal(t, int64(3), ragObj.Status.ObservedGeneration)
+			if assert.NotNil(t, ragObj.Status.Health) {
+				assert.Equal(t, rollout.HealthStateForPhase(tt.expected), ragObj.Status.Health.State)
+				assert.Equal(t, int64(3), ragObj.Status.Health.ObservedGeneration)
+			}
+			progressing := meta.FindStatusCondition(ragObj.Status.Conditions, string(kaitov1beta1.ConditionTypeProgressing))
+			assert.NotNil(t, progressing)
+			assert.Equal(t, string(tt.expected), progressing.Reason)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+// applyRAGShards creates or updates the index worker and Service of every shard of a
+// sharded RAGEngine and waits for the workers to become ready. The shards are applied and
+// waited on in parallel, so a RAGEngine becomes ready once its slowest shard is.
+func (c *RAGEngineReconciler) applyRAGShards(ctx context.Context, ragEngineObj *kaitov1beta1.RAGEngine, revisionStr string) error {
+	// The Deployment of the RAG service is the template of missing index workers. It is
+	// generated once, before the shards are applied concurrently.
+	template := sync.OnceValues(func() (*appsv1.Deployment, error) {
+		return generatePresetRAG(ctx, ragEngineObj, revisionStr, c.Client)
+	})
+
+	shards := int(ragEngineObj.Spec.Sharding.Shards)
+	errs := make([]error, shards)
+	var wg sync.WaitGroup
+	for shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[shard] = c.applyRAGShard(ctx, ragEngineObj, revisionStr, shard, template)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// applyRAGShard creates or updates the index worker and Service of a shard and waits for
+// the worker to become ready.
+func (c *RAGEngineReconciler) applyRAGShard(ctx context.Context, ragEngineObj *kaitov1beta1.RAGEngine, revisionStr string, shard int,
+	template func() (*appsv1.Deployment, error)) error {
+	name := kaitov1beta1.RAGEngineShardName(ragEngineObj.Name, shard)
+
+	svc := &corev1.Service{}
+	if err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := resources.CreateResource(ctx, manifests.GenerateRAGShardServiceManifest(ragEngineObj, shard), c.Client); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to create the service of shard %d: %w", shard, err)
+		}
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := resources.GetResource(ctx, name, ragEngineObj.Namespace, c.Client, deployment)
+	switch {
+	case err == nil:
+		if deployment.Annotations[kaitov1beta1.RAGEngineRevisionAnnotation] != revisionStr {
+			klog.InfoS("updating ragengine shard", "ragengine", klog.KObj(ragEngineObj), "shard", shard)
+			spec := &deployment.Spec.Template.Spec
+			spec.Containers[0].Env = manifests.RAGShardSetEnv(ragEngineObj)
+			manifests.SetRemoteEmbeddingHeadersVolume(ragEngineObj, spec)
+			deployment.Annotations[kaitov1beta1.RAGEngineRevisionAnnotation] = revisionStr
+			if err := c.Update(ctx, deployment); err != nil {
+				return fmt.Errorf("failed to update shard %d: %w", shard, err)
+			}
+		}
+	case apierrors.IsNotFound(err):
+		depObj, err := template()
+		if err != nil {
+			return err
+		}
+		if deployment, err = CreatePresetRAGShard(ctx, ragEngineObj, depObj, shard, c.Client); err != nil {
+			return fmt.Errorf("failed to create shard %d: %w", shard, err)
+		}
+	default:
+		return err
+	}
+
+	if err := resources.CheckResourceStatus(deployment, c.Client, time.Duration(10)*time.Minute); err != nil {
+		return fmt.Errorf("shard %d is not ready: %w", shard, err)
+	}
+	return nil
+}
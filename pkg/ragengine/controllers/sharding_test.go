@@ -0,0 +1,121 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/nodes"
+)
+
+func TestApplyRAGShards(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	ragEngine := &v1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default", UID: "rag-uid"},
+		Spec: &v1beta1.RAGEngineSpec{
+			Embedding: &v1beta1.EmbeddingSpec{Remote: &v1beta1.RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+			Sharding:  &v1beta1.ShardingSpec{Shards: 2},
+		},
+	}
+	// Ready shards of an earlier revision.
+	var objs []ctrlclient.Object
+	for shard := 0; shard < 2; shard++ {
+		objs = append(objs, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        v1beta1.RAGEngineShardName("rag", shard),
+				Namespace:   "default",
+				Annotations: map[string]string{v1beta1.RAGEngineRevisionAnnotation: "1"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "rag"}}}},
+			},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+		})
+	}
+	kubeClient := ctrlclientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	c := &RAGEngineReconciler{Client: kubeClient, Scheme: scheme}
+
+	require.NoError(t, c.applyRAGShards(ctx, ragEngine, "2"))
+
+	for shard := 0; shard < 2; shard++ {
+		name := v1beta1.RAGEngineShardName("rag", shard)
+		svc := &corev1.Service{}
+		require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKey{Name: name, Namespace: "default"}, svc))
+		assert.Equal(t, map[string]string{v1beta1.LabelRAGEngineShard: name}, svc.Spec.Selector)
+
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, kubeClient.Get(ctx, ctrlclient.ObjectKey{Name: name, Namespace: "default"}, deployment))
+		assert.Equal(t, "2", deployment.Annotations[v1beta1.RAGEngineRevisionAnnotation])
+		env := map[string]string{}
+		for _, e := range deployment.Spec.Template.Spec.Containers[0].Env {
+			env[e.Name] = e.Value
+		}
+		assert.Equal(t, "https://embedding.example.com", env["REMOTE_EMBEDDING_URL"])
+		assert.NotContains(t, env, "RAG_SHARD_URLS")
+	}
+}
+
+func TestShardResourceRequirements(t *testing.T) {
+	gpu := corev1.ResourceName(nodes.CapacityNvidiaGPU)
+	gpuRequirements := func(n int64) corev1.ResourceRequirements {
+		q := *resource.NewQuantity(n, resource.DecimalSI)
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{gpu: q}, Limits: corev1.ResourceList{gpu: q}}
+	}
+	newRAGEngine := func(count, shards int) *v1beta1.RAGEngine {
+		return &v1beta1.RAGEngine{Spec: &v1beta1.RAGEngineSpec{
+			Compute:  &v1beta1.ResourceSpec{Count: &count},
+			Sharding: &v1beta1.ShardingSpec{Shards: int32(shards)},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		rag      *v1beta1.RAGEngine
+		req      corev1.ResourceRequirements
+		expected int64
+	}{
+		{name: "shards share the GPUs of a node", rag: newRAGEngine(1, 4), req: gpuRequirements(8), expected: 2},
+		{name: "shards spread over the nodes", rag: newRAGEngine(2, 4), req: gpuRequirements(8), expected: 4},
+		{name: "uneven split rounds down", rag: newRAGEngine(1, 3), req: gpuRequirements(4), expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sized := shardResourceRequirements(tt.rag, tt.req)
+			assert.Equal(t, tt.expected, sized.Requests.Name(gpu, resource.DecimalSI).Value())
+			assert.Equal(t, tt.expected, sized.Limits.Name(gpu, resource.DecimalSI).Value())
+		})
+	}
+
+	cpu := defaultResourceRequirements()
+	assert.Equal(t, cpu, shardResourceRequirements(newRAGEngine(1, 4), cpu))
+}
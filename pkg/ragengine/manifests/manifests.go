@@ -14,12 +14,18 @@
 package manifests
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -31,6 +37,48 @@ const (
 	GuardrailsPolicyMountPath  = "/etc/ragengine/guardrails"
 	GuardrailsPolicyFileName   = kaitov1beta1.GuardrailsPolicyFileName
 	GuardrailsPolicyFilePath   = GuardrailsPolicyMountPath + "/" + GuardrailsPolicyFileName
+
+	IndexAuthVolumeName     = "index-auth-policy"
+	IndexAuthMountPath      = "/etc/ragengine/auth"
+	IndexAuthPolicyFilePath = IndexAuthMountPath + "/" + kaitov1beta1.IndexAuthPolicyFileName
+
+	RemoteEmbeddingHeadersVolumeName = "remote-embedding-headers"
+	RemoteEmbeddingHeadersMountPath  = "/etc/ragengine/embedding-headers"
+
+	RestoreVolumeName        = "restore"
+	RestoreMountPath         = "/mnt/restore"
+	RestoreInitContainerName = "restore"
+	BackupContainerName      = "backup"
+
+	// IndexEvictionContainerName runs the eviction of expired documents.
+	IndexEvictionContainerName = "evict"
+	// IndexEvictionSchedule is how often expired documents are evicted.
+	IndexEvictionSchedule = "*/15 * * * *"
+
+	// ConversationMemoryRedisImage runs the Redis server of the conversation memory.
+	ConversationMemoryRedisImage = "mcr.microsoft.com/mirror/docker/library/redis:7.2"
+	// ConversationMemoryPasswordKey is the key of the Redis password in the memory Secrets.
+	ConversationMemoryPasswordKey = "REDIS_PASSWORD"
+	ConversationMemoryRedisPort   = 6379
+
+	// defaultConversationMemoryMaxTurns and defaultConversationMemoryTTL are the defaults of
+	// spec.conversationMemory.
+	defaultConversationMemoryMaxTurns = 10
+	defaultConversationMemoryTTL      = 24 * time.Hour
+	// redisUser is the redis user of the Redis image.
+	redisUser = 999
+
+	// shardEnvPrefix prefixes the environment that makes a RAG service route to shards.
+	shardEnvPrefix = "RAG_SHARD_"
+
+	// backupScript is the backup entry point in the RAG service image.
+	backupScript = "/app/ragengine/backup/cli.py"
+	// maintenanceScript is the index maintenance entry point in the RAG service image.
+	maintenanceScript = "/app/ragengine/maintenance/cli.py"
+	// jobNameLabel is set by the Job controller on the pods of a Job.
+	jobNameLabel = "batch.kubernetes.io/job-name"
+	// MaintenanceAPIKeyEnvName holds the API key of the backup and index eviction jobs.
+	MaintenanceAPIKeyEnvName = "RAG_API_KEY"
 )
 
 func GenerateRAGDeploymentManifest(ragEngineObj *kaitov1beta1.RAGEngine, revisionNum string, imageName string,
@@ -194,7 +242,7 @@ func RAGSetEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
 		}
 	} else if ragEngineObj.Spec.Embedding.Remote != nil {
 		embeddingType = "remote"
-		// TODO: Model ID Env
+		envs = append(envs, remoteEmbeddingEnv(ragEngineObj.Spec.Embedding.Remote)...)
 	}
 	embeddingTypeEnv := corev1.EnvVar{
 		Name:  "EMBEDDING_TYPE",
@@ -297,9 +345,627 @@ func RAGSetEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
 		}
 	}
 
+	if ragEngineObj.Spec.Authorization != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "INDEX_AUTH_POLICY_PATH",
+			Value: IndexAuthPolicyFilePath,
+		})
+	}
+
+	if b := ragEngineObj.Spec.Backup; b != nil {
+		retain := int32(7)
+		if b.Retain != nil {
+			retain = *b.Retain
+		}
+		envs = append(envs,
+			corev1.EnvVar{Name: "BACKUP_DESTINATION_URL", Value: b.Destination.URL},
+			corev1.EnvVar{Name: "BACKUP_NAME_PREFIX", Value: kaitov1beta1.BackupCronJobName(ragEngineObj.Name) + "-"},
+			corev1.EnvVar{Name: "BACKUP_RETAIN", Value: strconv.Itoa(int(retain))},
+			sasTokenEnv("BACKUP_SAS_TOKEN", b.Destination.CredentialsSecret),
+		)
+	}
+
+	if ragEngineObj.Spec.Restore != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "RESTORE_SNAPSHOT_DIR",
+			Value: RestoreMountPath,
+		})
+	}
+
+	if r := ragEngineObj.Spec.Retrieval; r != nil {
+		mode := r.Mode
+		if mode == "" {
+			mode = kaitov1beta1.RetrievalModeHybrid
+		}
+		envs = append(envs, corev1.EnvVar{Name: "RAG_RETRIEVAL_MODE", Value: strings.ToLower(string(mode))})
+		if r.VectorWeightPercent != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "RAG_RETRIEVAL_VECTOR_WEIGHT",
+				Value: strconv.FormatFloat(float64(*r.VectorWeightPercent)/100, 'f', -1, 64),
+			})
+		}
+	}
+
+	if ragEngineObj.Spec.ConversationMemory != nil {
+		envs = append(envs, conversationMemoryEnv(ragEngineObj)...)
+	}
+
+	if o := ragEngineObj.Spec.ResponseOptions; o != nil {
+		envs = append(envs, corev1.EnvVar{Name: "RAG_INCLUDE_CITATIONS", Value: strconv.FormatBool(o.IncludeCitations)})
+		if o.MaxSourceChunks != nil {
+			envs = append(envs, corev1.EnvVar{Name: "RAG_MAX_SOURCE_CHUNKS", Value: strconv.Itoa(int(*o.MaxSourceChunks))})
+		}
+		if len(o.MetadataFields) > 0 {
+			envs = append(envs, corev1.EnvVar{Name: "RAG_SOURCE_METADATA_FIELDS", Value: strings.Join(o.MetadataFields, ",")})
+		}
+	}
+
+	if HasIndexDocumentTTL(ragEngineObj) {
+		envs = append(envs, corev1.EnvVar{Name: "RAG_INDEX_DOCUMENT_TTLS", Value: indexDocumentTTLs(ragEngineObj)})
+	}
+
+	if ragEngineObj.Spec.Sharding != nil {
+		envs = append(envs, shardRouterEnv(ragEngineObj)...)
+	}
+
 	return envs
 }
 
+// HasIndexDocumentTTL reports whether any index of ragEngineObj evicts expired documents.
+func HasIndexDocumentTTL(ragEngineObj *kaitov1beta1.RAGEngine) bool {
+	return slices.ContainsFunc(ragEngineObj.Spec.IndexPolicies, func(p kaitov1beta1.IndexPolicySpec) bool {
+		return p.DocumentTTL != nil
+	})
+}
+
+// indexDocumentTTLs renders the document TTLs as a JSON object mapping index names to
+// seconds. Index names are arbitrary strings, so they are not split out of a list.
+func indexDocumentTTLs(ragEngineObj *kaitov1beta1.RAGEngine) string {
+	ttls := map[string]int64{}
+	for _, policy := range ragEngineObj.Spec.IndexPolicies {
+		if policy.DocumentTTL != nil {
+			ttls[policy.Name] = int64(policy.DocumentTTL.Seconds())
+		}
+	}
+	// Marshaling a map of strings to integers cannot fail.
+	data, _ := json.Marshal(ttls)
+	return string(data)
+}
+
+// conversationMemoryEnv points the RAG service to the store of the chat history.
+func conversationMemoryEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
+	memory := ragEngineObj.Spec.ConversationMemory
+	maxTurns := int32(defaultConversationMemoryMaxTurns)
+	if memory.MaxTurns != nil {
+		maxTurns = *memory.MaxTurns
+	}
+	ttl := defaultConversationMemoryTTL
+	if memory.TTL != nil {
+		ttl = memory.TTL.Duration
+	}
+	backend := memory.Backend
+	if backend == "" {
+		backend = kaitov1beta1.ConversationMemoryBackendLocal
+	}
+	envs := []corev1.EnvVar{
+		{Name: "CONVERSATION_MEMORY_BACKEND", Value: strings.ToLower(string(backend))},
+		{Name: "CONVERSATION_MEMORY_MAX_TURNS", Value: strconv.Itoa(int(maxTurns))},
+		{Name: "CONVERSATION_MEMORY_TTL_SECONDS", Value: strconv.FormatInt(int64(ttl.Seconds()), 10)},
+	}
+	if backend != kaitov1beta1.ConversationMemoryBackendRedis {
+		return envs
+	}
+
+	redisURL := fmt.Sprintf("redis://%s.%s.svc.cluster.local:%d", kaitov1beta1.ConversationMemoryName(ragEngineObj.Name),
+		ragEngineObj.Namespace, ConversationMemoryRedisPort)
+	passwordSecret := kaitov1beta1.ConversationMemoryName(ragEngineObj.Name)
+	if memory.Redis != nil {
+		redisURL, passwordSecret = memory.Redis.URL, memory.Redis.AccessSecret
+	}
+	envs = append(envs, corev1.EnvVar{Name: "CONVERSATION_MEMORY_REDIS_URL", Value: redisURL})
+	if passwordSecret != "" {
+		envs = append(envs, redisPasswordEnv("CONVERSATION_MEMORY_REDIS_PASSWORD", passwordSecret))
+	}
+	return envs
+}
+
+// UsesManagedConversationMemory reports whether podSpec connects to the Redis server the
+// controller deploys for the conversation memory of ragEngineObj.
+func UsesManagedConversationMemory(ragEngineObj *kaitov1beta1.RAGEngine, podSpec *corev1.PodSpec) bool {
+	for _, container := range podSpec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "CONVERSATION_MEMORY_REDIS_PASSWORD" && env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil &&
+				env.ValueFrom.SecretKeyRef.Name == kaitov1beta1.ConversationMemoryName(ragEngineObj.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func redisPasswordEnv(name, secretName string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  ConversationMemoryPasswordKey,
+			},
+		},
+	}
+}
+
+// GenerateConversationMemoryDeploymentManifest returns the Redis server the controller
+// deploys for the conversation memory when no Redis URL is configured. The history is a
+// cache of recent turns, so Redis keeps it in memory only and evicts the least recently
+// used sessions when it is full.
+func GenerateConversationMemoryDeploymentManifest(ragEngineObj *kaitov1beta1.RAGEngine) *appsv1.Deployment {
+	name := kaitov1beta1.ConversationMemoryName(ragEngineObj.Name)
+	selector := map[string]string{kaitov1beta1.LabelRAGEngineMemory: name}
+	// The password is piped to redis-server as a config file so it does not show up in
+	// the process arguments.
+	script := fmt.Sprintf(`echo "requirepass $%s" | exec redis-server - --port %d --save "" --appendonly no --maxmemory 200mb --maxmemory-policy allkeys-lru`,
+		ConversationMemoryPasswordKey, ConversationMemoryRedisPort)
+
+	return &appsv1.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: ragEngineObj.Namespace,
+			Labels:    map[string]string{kaitov1beta1.LabelRAGEngineName: ragEngineObj.Name},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(ragEngineObj, kaitov1beta1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(1)),
+			Selector: &v1.LabelSelector{MatchLabels: selector},
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: selector},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: lo.ToPtr(false),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   lo.ToPtr(true),
+						RunAsUser:      lo.ToPtr(int64(redisUser)),
+						RunAsGroup:     lo.ToPtr(int64(redisUser)),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Containers: []corev1.Container{{
+						Name:    "redis",
+						Image:   ConversationMemoryRedisImage,
+						Command: []string{"sh", "-c", script},
+						Env:     []corev1.EnvVar{redisPasswordEnv(ConversationMemoryPasswordKey, name)},
+						Ports: []corev1.ContainerPort{{
+							Name:          "redis",
+							ContainerPort: ConversationMemoryRedisPort,
+							Protocol:      corev1.ProtocolTCP,
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(ConversationMemoryRedisPort)},
+							},
+							PeriodSeconds: 10,
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: lo.ToPtr(false),
+							ReadOnlyRootFilesystem:   lo.ToPtr(true),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         "data",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// GenerateConversationMemoryServiceManifest returns the Service the RAG service reaches
+// the Redis server of the conversation memory through.
+func GenerateConversationMemoryServiceManifest(ragEngineObj *kaitov1beta1.RAGEngine) *corev1.Service {
+	name := kaitov1beta1.ConversationMemoryName(ragEngineObj.Name)
+	return &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: ragEngineObj.Namespace,
+			Labels:    map[string]string{kaitov1beta1.LabelRAGEngineName: ragEngineObj.Name},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(ragEngineObj, kaitov1beta1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{{
+				Name:       "redis",
+				Protocol:   corev1.ProtocolTCP,
+				Port:       ConversationMemoryRedisPort,
+				TargetPort: intstr.FromInt32(ConversationMemoryRedisPort),
+			}},
+			Selector: map[string]string{kaitov1beta1.LabelRAGEngineMemory: name},
+		},
+	}
+}
+
+// shardRouterEnv points the router of a sharded RAGEngine to the Services of its index workers.
+func shardRouterEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
+	sharding := ragEngineObj.Spec.Sharding
+	urls := make([]string, sharding.Shards)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("http://%s.%s.svc.cluster.local", kaitov1beta1.RAGEngineShardName(ragEngineObj.Name, i), ragEngineObj.Namespace)
+	}
+	strategy := sharding.Strategy
+	if strategy == "" {
+		strategy = kaitov1beta1.ShardingStrategyHash
+	}
+	envs := []corev1.EnvVar{
+		{Name: "RAG_SHARD_URLS", Value: strings.Join(urls, ",")},
+		{Name: "RAG_SHARD_STRATEGY", Value: strings.ToLower(string(strategy))},
+	}
+	if sharding.MetadataField != "" {
+		envs = append(envs, corev1.EnvVar{Name: "RAG_SHARD_METADATA_FIELD", Value: sharding.MetadataField})
+	}
+	return envs
+}
+
+// RAGShardSetEnv returns the environment of the index workers of a sharded RAGEngine,
+// which is the one of the router without the shard routing.
+func RAGShardSetEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
+	return lo.Reject(RAGSetEnv(ragEngineObj), func(env corev1.EnvVar, _ int) bool {
+		return strings.HasPrefix(env.Name, shardEnvPrefix)
+	})
+}
+
+// GenerateRAGShardDeploymentManifest turns the Deployment of the RAG service into the
+// index worker of a shard. Shard pods are only labeled with LabelRAGEngineShard, so the
+// RAGEngine Service keeps selecting the router alone.
+func GenerateRAGShardDeploymentManifest(ragEngineObj *kaitov1beta1.RAGEngine, depObj *appsv1.Deployment, shard int) *appsv1.Deployment {
+	name := kaitov1beta1.RAGEngineShardName(ragEngineObj.Name, shard)
+	selector := map[string]string{
+		kaitov1beta1.LabelRAGEngineShard: name,
+	}
+	shardObj := depObj.DeepCopy()
+	shardObj.Name = name
+	shardObj.Spec.Selector = &v1.LabelSelector{MatchLabels: selector}
+	shardObj.Spec.Template.Labels = selector
+	shardObj.Spec.Template.Spec.Containers[0].Env = RAGShardSetEnv(ragEngineObj)
+	return shardObj
+}
+
+// GenerateRAGShardServiceManifest returns the Service the router reaches the index worker of a shard through.
+func GenerateRAGShardServiceManifest(ragObj *kaitov1beta1.RAGEngine, shard int) *corev1.Service {
+	name := kaitov1beta1.RAGEngineShardName(ragObj.Name, shard)
+	serviceObj := GenerateRAGServiceManifest(ragObj, name, corev1.ServiceTypeClusterIP)
+	serviceObj.Spec.Selector = map[string]string{
+		kaitov1beta1.LabelRAGEngineShard: name,
+	}
+	return serviceObj
+}
+
+func sasTokenEnv(name, secretName string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  kaitov1beta1.BackupSASTokenKey,
+			},
+		},
+	}
+}
+
+// SetRestoreInitContainer adds an init container that downloads spec.restore.backupName
+// into a volume shared with the RAG container, whose PostStart hook loads the indexes.
+// The init container reports the restored indexes, or the failure, in its termination
+// message. Any restore init container already in podSpec is replaced, and removed when
+// spec.restore is not set, so the function applies to existing Deployments as well.
+func SetRestoreInitContainer(ragEngineObj *kaitov1beta1.RAGEngine, podSpec *corev1.PodSpec, image string) {
+	podSpec.Volumes = slices.DeleteFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == RestoreVolumeName
+	})
+	podSpec.InitContainers = slices.DeleteFunc(podSpec.InitContainers, func(c corev1.Container) bool {
+		return c.Name == RestoreInitContainerName
+	})
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	podSpec.Containers[0].VolumeMounts = slices.DeleteFunc(podSpec.Containers[0].VolumeMounts, func(m corev1.VolumeMount) bool {
+		return m.Name == RestoreVolumeName
+	})
+	r := ragEngineObj.Spec.Restore
+	if r == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         RestoreVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:    RestoreInitContainerName,
+		Image:   image,
+		Command: []string{"python3", backupScript, "restore"},
+		Env: []corev1.EnvVar{
+			{Name: "RESTORE_SOURCE_URL", Value: r.Source.URL},
+			{Name: "RESTORE_BACKUP_NAME", Value: r.BackupName},
+			{Name: "RESTORE_SNAPSHOT_DIR", Value: RestoreMountPath},
+			sasTokenEnv("RESTORE_SAS_TOKEN", r.Source.CredentialsSecret),
+		},
+		VolumeMounts:             []corev1.VolumeMount{{Name: RestoreVolumeName, MountPath: RestoreMountPath}},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      RestoreVolumeName,
+		MountPath: RestoreMountPath,
+		ReadOnly:  true,
+	})
+}
+
+// GenerateBackupCronJobManifest returns the CronJob that asks the RAG service to back up
+// its indexes on spec.backup.schedule. Each backup is named after its Job, so the Jobs of
+// the CronJob tell the controller which backups succeeded.
+func GenerateBackupCronJobManifest(ragEngineObj *kaitov1beta1.RAGEngine, image string) *batchv1.CronJob {
+	labels := map[string]string{
+		kaitov1beta1.LabelRAGEngineName: ragEngineObj.Name,
+	}
+	serviceURL := fmt.Sprintf("http://%s.%s.svc", ragEngineObj.Name, ragEngineObj.Namespace)
+
+	return &batchv1.CronJob{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      kaitov1beta1.BackupCronJobName(ragEngineObj.Name),
+			Namespace: ragEngineObj.Namespace,
+			Labels:    labels,
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(ragEngineObj, kaitov1beta1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   ragEngineObj.Spec.Backup.Schedule,
+			TimeZone:                   lo.ToPtr("Etc/UTC"),
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: lo.ToPtr(int32(3)),
+			FailedJobsHistoryLimit:     lo.ToPtr(int32(1)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: lo.ToPtr(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: v1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers: []corev1.Container{{
+								Name:    BackupContainerName,
+								Image:   image,
+								Command: []string{"python3", backupScript, "trigger"},
+								Env: append([]corev1.EnvVar{
+									{Name: "RAG_SERVICE_URL", Value: serviceURL},
+									{
+										Name: "BACKUP_NAME",
+										ValueFrom: &corev1.EnvVarSource{
+											FieldRef: &corev1.ObjectFieldSelector{
+												FieldPath: fmt.Sprintf("metadata.labels['%s']", jobNameLabel),
+											},
+										},
+									},
+								}, maintenanceAPIKeyEnv(ragEngineObj)...),
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU:    resource.MustParse("50m"),
+										corev1.ResourceMemory: resource.MustParse("64Mi"),
+									},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GenerateIndexEvictionCronJobManifest returns the CronJob that asks the RAG service to
+// evict expired documents. The Jobs report the documents of each index in their
+// termination message, which the controller copies to status.indexes.
+func GenerateIndexEvictionCronJobManifest(ragEngineObj *kaitov1beta1.RAGEngine, image string) *batchv1.CronJob {
+	labels := map[string]string{
+		kaitov1beta1.LabelRAGEngineIndexEviction: ragEngineObj.Name,
+	}
+	serviceURL := fmt.Sprintf("http://%s.%s.svc", ragEngineObj.Name, ragEngineObj.Namespace)
+
+	return &batchv1.CronJob{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      kaitov1beta1.IndexEvictionCronJobName(ragEngineObj.Name),
+			Namespace: ragEngineObj.Namespace,
+			Labels:    labels,
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(ragEngineObj, kaitov1beta1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   IndexEvictionSchedule,
+			TimeZone:                   lo.ToPtr("Etc/UTC"),
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: lo.ToPtr(int32(1)),
+			FailedJobsHistoryLimit:     lo.ToPtr(int32(1)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: lo.ToPtr(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: v1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers: []corev1.Container{{
+								Name:    IndexEvictionContainerName,
+								Image:   image,
+								Command: []string{"python3", maintenanceScript, "evict"},
+								Env: append([]corev1.EnvVar{
+									{Name: "RAG_SERVICE_URL", Value: serviceURL},
+								}, maintenanceAPIKeyEnv(ragEngineObj)...),
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU:    resource.MustParse("50m"),
+										corev1.ResourceMemory: resource.MustParse("64Mi"),
+									},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// maintenanceAPIKeyEnv passes the maintenance API key to the backup and index eviction
+// jobs when per-index authorization is enabled. The key grants the admin access that
+// /backup and /evict require.
+func maintenanceAPIKeyEnv(ragEngineObj *kaitov1beta1.RAGEngine) []corev1.EnvVar {
+	if ragEngineObj.Spec.Authorization == nil {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name: MaintenanceAPIKeyEnvName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: kaitov1beta1.IndexAuthSecretName(ragEngineObj.Name)},
+				Key:                  kaitov1beta1.IndexAuthMaintenanceKeyName,
+			},
+		},
+	}}
+}
+
+// HasIndexAuthVolume reports whether podSpec mounts the index authorization policy.
+func HasIndexAuthVolume(podSpec *corev1.PodSpec) bool {
+	return slices.ContainsFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == IndexAuthVolumeName
+	})
+}
+
+// SetIndexAuthVolume mounts the rendered index authorization policy into the RAG container
+// when spec.authorization is set and removes the mount otherwise. Only the policy file is
+// mounted; the API keys stay in the Secret. The mount is not a subPath so key rotation
+// reaches the running pod. The pods also run as the ServiceAccount that may verify the
+// tokens of service account clients.
+func SetIndexAuthVolume(ragEngineObj *kaitov1beta1.RAGEngine, podSpec *corev1.PodSpec) {
+	serviceAccountName := kaitov1beta1.IndexAuthServiceAccountName(ragEngineObj.Name)
+	if ragEngineObj.Spec.Authorization != nil {
+		podSpec.ServiceAccountName = serviceAccountName
+	} else if podSpec.ServiceAccountName == serviceAccountName {
+		podSpec.ServiceAccountName = ""
+	}
+	podSpec.Volumes = slices.DeleteFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == IndexAuthVolumeName
+	})
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+		return m.Name == IndexAuthVolumeName
+	})
+	if ragEngineObj.Spec.Authorization == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: IndexAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: kaitov1beta1.IndexAuthSecretName(ragEngineObj.Name),
+				Items: []corev1.KeyToPath{{
+					Key:  kaitov1beta1.IndexAuthPolicyFileName,
+					Path: kaitov1beta1.IndexAuthPolicyFileName,
+				}},
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      IndexAuthVolumeName,
+		MountPath: IndexAuthMountPath,
+		ReadOnly:  true,
+	})
+}
+
+// remoteEmbeddingEnv renders the remote embedding settings. Unset settings fall back
+// to the defaults of the RAG service. The access token stays in its Secret.
+func remoteEmbeddingEnv(remote *kaitov1beta1.RemoteEmbeddingSpec) []corev1.EnvVar {
+	apiFormat := remote.APIFormat
+	if apiFormat == "" {
+		apiFormat = kaitov1beta1.RemoteEmbeddingAPIFormatHuggingFace
+	}
+	envs := []corev1.EnvVar{
+		{Name: "REMOTE_EMBEDDING_URL", Value: remote.URL},
+		{Name: "REMOTE_EMBEDDING_API_FORMAT", Value: strings.ToLower(string(apiFormat))},
+	}
+	if remote.Model != "" {
+		envs = append(envs, corev1.EnvVar{Name: "REMOTE_EMBEDDING_MODEL", Value: remote.Model})
+	}
+	if remote.BatchSize != nil {
+		envs = append(envs, corev1.EnvVar{Name: "REMOTE_EMBEDDING_BATCH_SIZE", Value: strconv.Itoa(int(*remote.BatchSize))})
+	}
+	if remote.Timeout != nil {
+		envs = append(envs, corev1.EnvVar{Name: "REMOTE_EMBEDDING_TIMEOUT_SECONDS", Value: strconv.FormatFloat(remote.Timeout.Seconds(), 'f', -1, 64)})
+	}
+	if remote.MaxRetries != nil {
+		envs = append(envs, corev1.EnvVar{Name: "REMOTE_EMBEDDING_MAX_RETRIES", Value: strconv.Itoa(int(*remote.MaxRetries))})
+	}
+	if remote.AccessSecret != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name: "REMOTE_EMBEDDING_ACCESS_SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: remote.AccessSecret},
+					Key:                  "REMOTE_EMBEDDING_ACCESS_SECRET",
+				},
+			},
+		})
+	}
+	if remote.HeadersSecret != "" {
+		envs = append(envs, corev1.EnvVar{Name: "REMOTE_EMBEDDING_HEADERS_DIR", Value: RemoteEmbeddingHeadersMountPath})
+	}
+	return envs
+}
+
+// SetRemoteEmbeddingHeadersVolume mounts the header templates Secret of a remote embedding
+// service into the RAG container and removes the mount when it is no longer set. The mount
+// is not a subPath so header rotation reaches the running pod.
+func SetRemoteEmbeddingHeadersVolume(ragEngineObj *kaitov1beta1.RAGEngine, podSpec *corev1.PodSpec) {
+	podSpec.Volumes = slices.DeleteFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == RemoteEmbeddingHeadersVolumeName
+	})
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+		return m.Name == RemoteEmbeddingHeadersVolumeName
+	})
+	if ragEngineObj.Spec.Embedding == nil || ragEngineObj.Spec.Embedding.Remote == nil || ragEngineObj.Spec.Embedding.Remote.HeadersSecret == "" {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: RemoteEmbeddingHeadersVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: ragEngineObj.Spec.Embedding.Remote.HeadersSecret},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      RemoteEmbeddingHeadersVolumeName,
+		MountPath: RemoteEmbeddingHeadersMountPath,
+		ReadOnly:  true,
+	})
+}
+
 func GenerateRAGServiceManifest(ragObj *kaitov1beta1.RAGEngine, serviceName string, serviceType corev1.ServiceType) *corev1.Service {
 	selector := map[string]string{
 		kaitov1beta1.LabelRAGEngineName: ragObj.Name,
@@ -15,10 +15,13 @@ package manifests
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils/test"
@@ -365,3 +368,503 @@ func TestRAGSetEnv(t *testing.T) {
 		}
 	})
 }
+
+func TestSetIndexAuthVolume(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{
+				Local: &kaitov1beta1.LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"},
+			},
+			Authorization: &kaitov1beta1.IndexAuthorizationSpec{Indexes: map[string]kaitov1beta1.IndexAccessSpec{
+				"team-a": {APIKeys: []string{"team-a-key"}},
+			}},
+		},
+	}
+	podSpec := &v1.PodSpec{Containers: []v1.Container{{Name: "rg"}}}
+
+	// Applying twice must not duplicate the volume.
+	SetIndexAuthVolume(re, podSpec)
+	SetIndexAuthVolume(re, podSpec)
+	if len(podSpec.Volumes) != 1 || len(podSpec.Containers[0].VolumeMounts) != 1 {
+		t.Fatalf("expected one volume and mount, got %v and %v", podSpec.Volumes, podSpec.Containers[0].VolumeMounts)
+	}
+	secret := podSpec.Volumes[0].Secret
+	if secret == nil || secret.SecretName != "rg-api-keys" || len(secret.Items) != 1 || secret.Items[0].Key != kaitov1beta1.IndexAuthPolicyFileName {
+		t.Errorf("expected only the policy file of rg-api-keys to be mounted, got %+v", secret)
+	}
+	if !HasIndexAuthVolume(podSpec) {
+		t.Errorf("expected HasIndexAuthVolume to be true")
+	}
+	if podSpec.ServiceAccountName != "rg-index-auth" {
+		t.Errorf("expected the pods to run as rg-index-auth, got %q", podSpec.ServiceAccountName)
+	}
+
+	found := false
+	for _, e := range RAGSetEnv(re) {
+		if e.Name == "INDEX_AUTH_POLICY_PATH" && e.Value == IndexAuthPolicyFilePath {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected INDEX_AUTH_POLICY_PATH=%s", IndexAuthPolicyFilePath)
+	}
+
+	re.Spec.Authorization = nil
+	SetIndexAuthVolume(re, podSpec)
+	if HasIndexAuthVolume(podSpec) || len(podSpec.Containers[0].VolumeMounts) != 0 {
+		t.Errorf("expected the volume to be removed when authorization is disabled")
+	}
+	if podSpec.ServiceAccountName != "" {
+		t.Errorf("expected the default service account when authorization is disabled, got %q", podSpec.ServiceAccountName)
+	}
+}
+
+func TestRemoteEmbeddingEnvAndHeadersVolume(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{Remote: &kaitov1beta1.RemoteEmbeddingSpec{
+				URL:           "https://api.openai.com/v1/embeddings",
+				APIFormat:     kaitov1beta1.RemoteEmbeddingAPIFormatOpenAI,
+				Model:         "text-embedding-3-small",
+				BatchSize:     ptr.To[int32](64),
+				Timeout:       &metav1.Duration{Duration: 1500 * time.Millisecond},
+				MaxRetries:    ptr.To[int32](5),
+				AccessSecret:  "openai-key",
+				HeadersSecret: "openai-headers",
+			}},
+		},
+	}
+
+	envMap := make(map[string]v1.EnvVar)
+	for _, env := range RAGSetEnv(re) {
+		envMap[env.Name] = env
+	}
+	for name, want := range map[string]string{
+		"EMBEDDING_TYPE":                   "remote",
+		"REMOTE_EMBEDDING_URL":             "https://api.openai.com/v1/embeddings",
+		"REMOTE_EMBEDDING_API_FORMAT":      "openai",
+		"REMOTE_EMBEDDING_MODEL":           "text-embedding-3-small",
+		"REMOTE_EMBEDDING_BATCH_SIZE":      "64",
+		"REMOTE_EMBEDDING_TIMEOUT_SECONDS": "1.5",
+		"REMOTE_EMBEDDING_MAX_RETRIES":     "5",
+		"REMOTE_EMBEDDING_HEADERS_DIR":     RemoteEmbeddingHeadersMountPath,
+	} {
+		if envMap[name].Value != want {
+			t.Errorf("expected %s=%q, got %q", name, want, envMap[name].Value)
+		}
+	}
+	token := envMap["REMOTE_EMBEDDING_ACCESS_SECRET"]
+	if token.Value != "" || token.ValueFrom == nil || token.ValueFrom.SecretKeyRef.Name != "openai-key" {
+		t.Errorf("expected the access token to be read from secret openai-key, got %+v", token)
+	}
+
+	podSpec := &v1.PodSpec{Containers: []v1.Container{{Name: "rg"}}}
+	SetRemoteEmbeddingHeadersVolume(re, podSpec)
+	SetRemoteEmbeddingHeadersVolume(re, podSpec)
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Secret == nil || podSpec.Volumes[0].Secret.SecretName != "openai-headers" {
+		t.Fatalf("expected the headers secret to be mounted once, got %+v", podSpec.Volumes)
+	}
+	if len(podSpec.Containers[0].VolumeMounts) != 1 || podSpec.Containers[0].VolumeMounts[0].MountPath != RemoteEmbeddingHeadersMountPath {
+		t.Errorf("expected one mount at %s, got %+v", RemoteEmbeddingHeadersMountPath, podSpec.Containers[0].VolumeMounts)
+	}
+
+	re.Spec.Embedding.Remote.HeadersSecret = ""
+	SetRemoteEmbeddingHeadersVolume(re, podSpec)
+	if len(podSpec.Volumes) != 0 || len(podSpec.Containers[0].VolumeMounts) != 0 {
+		t.Errorf("expected the headers volume to be removed")
+	}
+}
+
+func TestShardManifests(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{Local: &kaitov1beta1.LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}},
+			Sharding: &kaitov1beta1.ShardingSpec{
+				Shards:        2,
+				Strategy:      kaitov1beta1.ShardingStrategyMetadataField,
+				MetadataField: "tenant",
+			},
+		},
+	}
+
+	envMap := make(map[string]string)
+	for _, env := range RAGSetEnv(re) {
+		envMap[env.Name] = env.Value
+	}
+	for name, want := range map[string]string{
+		"RAG_SHARD_URLS":           "http://rg-shard-0.ns.svc.cluster.local,http://rg-shard-1.ns.svc.cluster.local",
+		"RAG_SHARD_STRATEGY":       "metadatafield",
+		"RAG_SHARD_METADATA_FIELD": "tenant",
+	} {
+		if envMap[name] != want {
+			t.Errorf("expected %s=%q, got %q", name, want, envMap[name])
+		}
+	}
+
+	router := GenerateRAGDeploymentManifest(re, "1", "image", nil, nil, nil, nil, nil, v1.ResourceRequirements{}, nil, nil, nil)
+	shard := GenerateRAGShardDeploymentManifest(re, router, 1)
+	selector := map[string]string{kaitov1beta1.LabelRAGEngineShard: "rg-shard-1"}
+	if shard.Name != "rg-shard-1" {
+		t.Errorf("expected shard deployment rg-shard-1, got %s", shard.Name)
+	}
+	if !reflect.DeepEqual(shard.Spec.Selector.MatchLabels, selector) || !reflect.DeepEqual(shard.Spec.Template.Labels, selector) {
+		t.Errorf("expected shard pods to be selected by %v only, got selector %v and labels %v",
+			selector, shard.Spec.Selector.MatchLabels, shard.Spec.Template.Labels)
+	}
+	for _, env := range shard.Spec.Template.Spec.Containers[0].Env {
+		if strings.HasPrefix(env.Name, "RAG_SHARD_") {
+			t.Errorf("expected shard workers not to route, got %s", env.Name)
+		}
+	}
+	if router.Name != "rg" || router.Spec.Template.Labels[kaitov1beta1.LabelRAGEngineName] != "rg" {
+		t.Errorf("expected the router deployment to be left unchanged")
+	}
+
+	svc := GenerateRAGShardServiceManifest(re, 1)
+	if svc.Name != "rg-shard-1" || svc.Spec.Type != v1.ServiceTypeClusterIP || !reflect.DeepEqual(svc.Spec.Selector, selector) {
+		t.Errorf("unexpected shard service %+v", svc)
+	}
+}
+
+func TestRAGSetEnvRetrieval(t *testing.T) {
+	tests := []struct {
+		name      string
+		retrieval *kaitov1beta1.RetrievalSpec
+		want      map[string]string
+	}{
+		{name: "omitted", want: map[string]string{"RAG_RETRIEVAL_MODE": "", "RAG_RETRIEVAL_VECTOR_WEIGHT": ""}},
+		{
+			name:      "default mode",
+			retrieval: &kaitov1beta1.RetrievalSpec{},
+			want:      map[string]string{"RAG_RETRIEVAL_MODE": "hybrid", "RAG_RETRIEVAL_VECTOR_WEIGHT": ""},
+		},
+		{
+			name:      "keyword",
+			retrieval: &kaitov1beta1.RetrievalSpec{Mode: kaitov1beta1.RetrievalModeKeyword},
+			want:      map[string]string{"RAG_RETRIEVAL_MODE": "keyword"},
+		},
+		{
+			name:      "hybrid with weight",
+			retrieval: &kaitov1beta1.RetrievalSpec{Mode: kaitov1beta1.RetrievalModeHybrid, VectorWeightPercent: ptr.To[int32](55)},
+			want:      map[string]string{"RAG_RETRIEVAL_MODE": "hybrid", "RAG_RETRIEVAL_VECTOR_WEIGHT": "0.55"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := &kaitov1beta1.RAGEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+				Spec: &kaitov1beta1.RAGEngineSpec{
+					Embedding: &kaitov1beta1.EmbeddingSpec{Local: &kaitov1beta1.LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}},
+					Retrieval: tt.retrieval,
+				},
+			}
+			envMap := make(map[string]string)
+			for _, env := range RAGSetEnv(re) {
+				envMap[env.Name] = env.Value
+			}
+			for name, want := range tt.want {
+				if envMap[name] != want {
+					t.Errorf("expected %s=%q, got %q", name, want, envMap[name])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBackupCronJobManifest(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{Remote: &kaitov1beta1.RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+			Backup: &kaitov1beta1.RAGBackupSpec{
+				Schedule: "0 */6 * * *",
+				Destination: kaitov1beta1.BackupLocation{
+					URL:               "https://acct.blob.core.windows.net/backups",
+					CredentialsSecret: "backup-sas",
+				},
+			},
+		},
+	}
+
+	cronJob := GenerateBackupCronJobManifest(re, "registry/kaito-rag-service:0.3.2")
+	if cronJob.Name != "rg-backup" || cronJob.Spec.Schedule != "0 */6 * * *" {
+		t.Errorf("unexpected cronjob %s with schedule %q", cronJob.Name, cronJob.Spec.Schedule)
+	}
+	if !metav1.IsControlledBy(cronJob, re) {
+		t.Errorf("expected cronjob to be controlled by the ragengine")
+	}
+	if cronJob.Spec.JobTemplate.Labels[kaitov1beta1.LabelRAGEngineName] != "rg" {
+		t.Errorf("expected jobs to be labeled with the ragengine name, got %v", cronJob.Spec.JobTemplate.Labels)
+	}
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	env := map[string]v1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	if env["RAG_SERVICE_URL"].Value != "http://rg.ns.svc" {
+		t.Errorf("unexpected RAG_SERVICE_URL %q", env["RAG_SERVICE_URL"].Value)
+	}
+	if ref := env["BACKUP_NAME"].ValueFrom; ref == nil || ref.FieldRef.FieldPath != "metadata.labels['batch.kubernetes.io/job-name']" {
+		t.Errorf("expected BACKUP_NAME to be the job name, got %+v", ref)
+	}
+	for _, e := range container.Env {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			t.Errorf("backup job must not receive the SAS token, got %s", e.Name)
+		}
+	}
+
+	// With per-index authorization the job authenticates with the maintenance API key.
+	withAuth := re.DeepCopy()
+	withAuth.Spec.Authorization = &kaitov1beta1.IndexAuthorizationSpec{Indexes: map[string]kaitov1beta1.IndexAccessSpec{"docs": {APIKeys: []string{"team-a"}}}}
+	authEnv := GenerateBackupCronJobManifest(withAuth, "registry/kaito-rag-service:0.3.2").Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+	if ref := authEnv[len(authEnv)-1].ValueFrom; authEnv[len(authEnv)-1].Name != MaintenanceAPIKeyEnvName || ref == nil ||
+		ref.SecretKeyRef.Name != "rg-api-keys" || ref.SecretKeyRef.Key != kaitov1beta1.IndexAuthMaintenanceKeyName {
+		t.Errorf("expected %s from the maintenance key, got %+v", MaintenanceAPIKeyEnvName, authEnv)
+	}
+
+	envs := map[string]v1.EnvVar{}
+	for _, e := range RAGSetEnv(re) {
+		envs[e.Name] = e
+	}
+	if envs["BACKUP_DESTINATION_URL"].Value != "https://acct.blob.core.windows.net/backups" ||
+		envs["BACKUP_NAME_PREFIX"].Value != "rg-backup-" || envs["BACKUP_RETAIN"].Value != "7" {
+		t.Errorf("unexpected backup env %v", envs)
+	}
+	if ref := envs["BACKUP_SAS_TOKEN"].ValueFrom; ref == nil || ref.SecretKeyRef.Name != "backup-sas" ||
+		ref.SecretKeyRef.Key != kaitov1beta1.BackupSASTokenKey {
+		t.Errorf("expected BACKUP_SAS_TOKEN from secret backup-sas, got %+v", ref)
+	}
+}
+
+func TestSetRestoreInitContainer(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{Remote: &kaitov1beta1.RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+		},
+	}
+	podSpec := &v1.PodSpec{Containers: []v1.Container{{Name: "rg"}}}
+
+	SetRestoreInitContainer(re, podSpec, "registry/kaito-rag-service:0.3.2")
+	if len(podSpec.InitContainers) != 0 || len(podSpec.Volumes) != 0 {
+		t.Fatalf("expected no restore without spec.restore, got %+v", podSpec)
+	}
+
+	re.Spec.Restore = &kaitov1beta1.RAGRestoreSpec{
+		Source:     kaitov1beta1.BackupLocation{URL: "https://acct.blob.core.windows.net/backups", CredentialsSecret: "backup-sas"},
+		BackupName: "old-rg-backup-29348160",
+	}
+	SetRestoreInitContainer(re, podSpec, "registry/kaito-rag-service:0.3.2")
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != RestoreInitContainerName {
+		t.Fatalf("expected the restore init container, got %+v", podSpec.InitContainers)
+	}
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].EmptyDir == nil {
+		t.Errorf("expected an emptyDir restore volume, got %+v", podSpec.Volumes)
+	}
+	mounts := podSpec.Containers[0].VolumeMounts
+	if len(mounts) != 1 || mounts[0].MountPath != RestoreMountPath || !mounts[0].ReadOnly {
+		t.Errorf("expected the restore volume to be mounted read-only in the RAG container, got %+v", mounts)
+	}
+
+	// Applying it again to an existing pod spec replaces the restore init container.
+	re.Spec.Restore.BackupName = "old-rg-backup-29348520"
+	SetRestoreInitContainer(re, podSpec, "registry/kaito-rag-service:0.3.2")
+	if len(podSpec.InitContainers) != 1 || len(podSpec.Volumes) != 1 || len(podSpec.Containers[0].VolumeMounts) != 1 {
+		t.Fatalf("expected a single restore init container, volume and mount, got %+v", podSpec)
+	}
+	if env := podSpec.InitContainers[0].Env[1]; env.Name != "RESTORE_BACKUP_NAME" || env.Value != "old-rg-backup-29348520" {
+		t.Errorf("expected the updated backup name, got %+v", env)
+	}
+
+	found := false
+	for _, e := range RAGSetEnv(re) {
+		if e.Name == "RESTORE_SNAPSHOT_DIR" && e.Value == RestoreMountPath {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected RESTORE_SNAPSHOT_DIR to be set")
+	}
+
+	// Removing spec.restore removes the init container from an existing pod spec.
+	re.Spec.Restore = nil
+	SetRestoreInitContainer(re, podSpec, "registry/kaito-rag-service:0.3.2")
+	if len(podSpec.InitContainers) != 0 || len(podSpec.Volumes) != 0 || len(podSpec.Containers[0].VolumeMounts) != 0 {
+		t.Errorf("expected the restore init container to be removed, got %+v", podSpec)
+	}
+}
+
+func TestConversationMemoryManifests(t *testing.T) {
+	newRAGEngine := func(memory *kaitov1beta1.ConversationMemorySpec) *kaitov1beta1.RAGEngine {
+		return &kaitov1beta1.RAGEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "rag", Namespace: "default"},
+			Spec: &kaitov1beta1.RAGEngineSpec{
+				Embedding:          &kaitov1beta1.EmbeddingSpec{Remote: &kaitov1beta1.RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+				ConversationMemory: memory,
+			},
+		}
+	}
+	envMap := func(envs []v1.EnvVar) map[string]v1.EnvVar {
+		m := map[string]v1.EnvVar{}
+		for _, env := range envs {
+			m[env.Name] = env
+		}
+		return m
+	}
+	expectEnv := func(envs map[string]v1.EnvVar, name, value string) {
+		t.Helper()
+		if got := envs[name].Value; got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	envs := envMap(RAGSetEnv(newRAGEngine(&kaitov1beta1.ConversationMemorySpec{})))
+	expectEnv(envs, "CONVERSATION_MEMORY_BACKEND", "local")
+	expectEnv(envs, "CONVERSATION_MEMORY_MAX_TURNS", "10")
+	expectEnv(envs, "CONVERSATION_MEMORY_TTL_SECONDS", "86400")
+	if _, ok := envs["CONVERSATION_MEMORY_REDIS_URL"]; ok {
+		t.Errorf("the local backend must not set CONVERSATION_MEMORY_REDIS_URL")
+	}
+
+	managed := newRAGEngine(&kaitov1beta1.ConversationMemorySpec{Backend: kaitov1beta1.ConversationMemoryBackendRedis, MaxTurns: ptr.To[int32](4)})
+	envs = envMap(RAGSetEnv(managed))
+	expectEnv(envs, "CONVERSATION_MEMORY_BACKEND", "redis")
+	expectEnv(envs, "CONVERSATION_MEMORY_MAX_TURNS", "4")
+	expectEnv(envs, "CONVERSATION_MEMORY_REDIS_URL", "redis://rag-memory.default.svc.cluster.local:6379")
+	if ref := envs["CONVERSATION_MEMORY_REDIS_PASSWORD"].ValueFrom; ref == nil || ref.SecretKeyRef.Name != "rag-memory" {
+		t.Errorf("CONVERSATION_MEMORY_REDIS_PASSWORD must come from the managed secret, got %+v", ref)
+	}
+
+	external := newRAGEngine(&kaitov1beta1.ConversationMemorySpec{
+		Backend: kaitov1beta1.ConversationMemoryBackendRedis,
+		Redis:   &kaitov1beta1.RedisMemorySpec{URL: "rediss://redis.example.com:6380/1"},
+	})
+	envs = envMap(RAGSetEnv(external))
+	expectEnv(envs, "CONVERSATION_MEMORY_REDIS_URL", "rediss://redis.example.com:6380/1")
+	if _, ok := envs["CONVERSATION_MEMORY_REDIS_PASSWORD"]; ok {
+		t.Errorf("CONVERSATION_MEMORY_REDIS_PASSWORD must not be set without an access secret")
+	}
+
+	if !UsesManagedConversationMemory(managed, &v1.PodSpec{Containers: []v1.Container{{Env: RAGSetEnv(managed)}}}) {
+		t.Errorf("UsesManagedConversationMemory() = false for the managed server")
+	}
+	if UsesManagedConversationMemory(managed, &v1.PodSpec{Containers: []v1.Container{{Env: RAGSetEnv(external)}}}) {
+		t.Errorf("UsesManagedConversationMemory() = true for an external server")
+	}
+
+	deployment := GenerateConversationMemoryDeploymentManifest(managed)
+	if deployment.Name != "rag-memory" {
+		t.Errorf("deployment name = %q, want rag-memory", deployment.Name)
+	}
+	podLabels := deployment.Spec.Template.Labels
+	if _, ok := podLabels[kaitov1beta1.LabelRAGEngineName]; ok {
+		t.Errorf("redis pods must not be selected by the ragengine service")
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if strings.Contains(container.Command[2], "$(") {
+		t.Errorf("the password must not be expanded into the arguments: %q", container.Command[2])
+	}
+	if !*deployment.Spec.Template.Spec.SecurityContext.RunAsNonRoot || *container.SecurityContext.AllowPrivilegeEscalation {
+		t.Errorf("redis must run unprivileged")
+	}
+
+	svc := GenerateConversationMemoryServiceManifest(managed)
+	if !reflect.DeepEqual(svc.Spec.Selector, podLabels) {
+		t.Errorf("service selector = %v, want %v", svc.Spec.Selector, podLabels)
+	}
+	if svc.Spec.Ports[0].Port != ConversationMemoryRedisPort {
+		t.Errorf("service port = %d, want %d", svc.Spec.Ports[0].Port, ConversationMemoryRedisPort)
+	}
+}
+
+func TestRAGSetEnvResponseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *kaitov1beta1.ResponseOptionsSpec
+		want    map[string]string
+	}{
+		{name: "omitted", want: map[string]string{"RAG_INCLUDE_CITATIONS": "", "RAG_MAX_SOURCE_CHUNKS": "", "RAG_SOURCE_METADATA_FIELDS": ""}},
+		{
+			name:    "citations only",
+			options: &kaitov1beta1.ResponseOptionsSpec{IncludeCitations: true},
+			want:    map[string]string{"RAG_INCLUDE_CITATIONS": "true", "RAG_MAX_SOURCE_CHUNKS": "", "RAG_SOURCE_METADATA_FIELDS": ""},
+		},
+		{
+			name:    "all options",
+			options: &kaitov1beta1.ResponseOptionsSpec{MaxSourceChunks: ptr.To[int32](3), MetadataFields: []string{"title", "url"}},
+			want:    map[string]string{"RAG_INCLUDE_CITATIONS": "false", "RAG_MAX_SOURCE_CHUNKS": "3", "RAG_SOURCE_METADATA_FIELDS": "title,url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := &kaitov1beta1.RAGEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+				Spec: &kaitov1beta1.RAGEngineSpec{
+					Embedding:       &kaitov1beta1.EmbeddingSpec{Local: &kaitov1beta1.LocalEmbeddingSpec{ModelID: "BAAI/bge-small-en-v1.5"}},
+					ResponseOptions: tt.options,
+				},
+			}
+			envMap := make(map[string]string)
+			for _, env := range RAGSetEnv(re) {
+				envMap[env.Name] = env.Value
+			}
+			for name, want := range tt.want {
+				if envMap[name] != want {
+					t.Errorf("expected %s=%q, got %q", name, want, envMap[name])
+				}
+			}
+		})
+	}
+}
+
+func TestIndexEvictionManifests(t *testing.T) {
+	re := &kaitov1beta1.RAGEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "rg", Namespace: "ns"},
+		Spec: &kaitov1beta1.RAGEngineSpec{
+			Embedding: &kaitov1beta1.EmbeddingSpec{Remote: &kaitov1beta1.RemoteEmbeddingSpec{URL: "https://embedding.example.com"}},
+			IndexPolicies: []kaitov1beta1.IndexPolicySpec{
+				{Name: "docs", DocumentTTL: &metav1.Duration{Duration: 36 * time.Hour}},
+				{Name: "wiki", RefreshSchedule: "@daily"},
+				{Name: "news, \"daily\"", DocumentTTL: &metav1.Duration{Duration: time.Hour}},
+			},
+		},
+	}
+
+	envs := map[string]string{}
+	for _, e := range RAGSetEnv(re) {
+		envs[e.Name] = e.Value
+	}
+	if want := `{"docs":129600,"news, \"daily\"":3600}`; envs["RAG_INDEX_DOCUMENT_TTLS"] != want {
+		t.Errorf("expected RAG_INDEX_DOCUMENT_TTLS=%s, got %s", want, envs["RAG_INDEX_DOCUMENT_TTLS"])
+	}
+
+	cronJob := GenerateIndexEvictionCronJobManifest(re, "registry/kaito-rag-service:0.3.2")
+	if cronJob.Name != "rg-index-eviction" || cronJob.Spec.Schedule != IndexEvictionSchedule {
+		t.Errorf("unexpected cronjob %s with schedule %q", cronJob.Name, cronJob.Spec.Schedule)
+	}
+	if !metav1.IsControlledBy(cronJob, re) {
+		t.Errorf("expected cronjob to be controlled by the ragengine")
+	}
+	if labels := cronJob.Spec.JobTemplate.Labels; labels[kaitov1beta1.LabelRAGEngineIndexEviction] != "rg" || labels[kaitov1beta1.LabelRAGEngineName] != "" {
+		t.Errorf("expected jobs to be labeled as index evictions only, got %v", labels)
+	}
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	if container.Command[len(container.Command)-1] != "evict" || container.Env[0].Value != "http://rg.ns.svc" {
+		t.Errorf("unexpected eviction container %+v", container)
+	}
+
+	re.Spec.IndexPolicies = []kaitov1beta1.IndexPolicySpec{{Name: "wiki", RefreshSchedule: "@daily"}}
+	if HasIndexDocumentTTL(re) {
+		t.Errorf("expected no document TTL without documentTTL")
+	}
+	for _, e := range RAGSetEnv(re) {
+		if e.Name == "RAG_INDEX_DOCUMENT_TTLS" {
+			t.Errorf("unexpected RAG_INDEX_DOCUMENT_TTLS=%s", e.Value)
+		}
+	}
+}
@@ -20,26 +20,31 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	knativeinjection "knative.dev/pkg/injection"
-	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/resourcesemantics"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/webhookcert"
 )
 
+// ValidationWebhookName is the name of the RAGEngine validating webhook and of its
+// ValidatingWebhookConfiguration.
+const ValidationWebhookName = "validation.ragengine.kaito.sh"
+
 func NewRAGEngineWebhooks() []knativeinjection.ControllerConstructor {
 	return []knativeinjection.ControllerConstructor{
-		certificates.NewController,
+		webhookcert.NewController(ValidationWebhookName),
 		NewRAGEngineCRDValidationWebhook,
 	}
 }
 
 func NewRAGEngineCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
-		"validation.ragengine.kaito.sh",
+		ValidationWebhookName,
 		"/validate/ragengine.kaito.sh",
 		RAGEngineResources,
-		func(ctx context.Context) context.Context { return ctx },
+		k8sclient.Decorator(ctx),
 		true,
 	)
 }
@@ -13,7 +13,11 @@
 
 package sku
 
-import "k8s.io/apimachinery/pkg/api/resource"
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
 
 func NewAwsSKUHandler() CloudSKUHandler {
 	// Reference: https://aws.amazon.com/ec2/instance-types/
@@ -25,6 +29,7 @@ func NewAwsSKUHandler() CloudSKUHandler {
 		{SKU: "p5.48xlarge", GPUCount: 8, GPUMem: resource.MustParse("640Gi"), GPUModel: "NVIDIA H100", NVMeDiskEnabled: true, CUDAComputeCapability: 9.0},
 		{SKU: "p5e.48xlarge", GPUCount: 8, GPUMem: resource.MustParse("1128Gi"), GPUModel: "NVIDIA H200", NVMeDiskEnabled: true, CUDAComputeCapability: 9.0},
 		{SKU: "p5en.48xlarge", GPUCount: 8, GPUMem: resource.MustParse("1128Gi"), GPUModel: "NVIDIA H200", NVMeDiskEnabled: true, CUDAComputeCapability: 9.0},
+		{SKU: "p6e-gb200.36xlarge", GPUCount: 4, GPUMem: resource.MustParse("740Gi"), GPUModel: "NVIDIA GB200", NVMeDiskEnabled: true, CUDAComputeCapability: 10.0, Arch: consts.ArchitectureARM64},
 		{SKU: "g6.xlarge", GPUCount: 1, GPUMem: resource.MustParse("24Gi"), GPUModel: "NVIDIA L4", NVMeDiskEnabled: true, CUDAComputeCapability: 8.9},
 		{SKU: "g6.2xlarge", GPUCount: 1, GPUMem: resource.MustParse("24Gi"), GPUModel: "NVIDIA L4", NVMeDiskEnabled: true, CUDAComputeCapability: 8.9},
 		{SKU: "g6.4xlarge", GPUCount: 1, GPUMem: resource.MustParse("24Gi"), GPUModel: "NVIDIA L4", NVMeDiskEnabled: true, CUDAComputeCapability: 8.9},
@@ -13,7 +13,11 @@
 
 package sku
 
-import "k8s.io/apimachinery/pkg/api/resource"
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
 
 func NewAzureSKUHandler() CloudSKUHandler {
 	// KAITO only supports GPUs with CUDA compute capability >= 8.0 (Ampere and newer).
@@ -32,9 +36,11 @@ func NewAzureSKUHandler() CloudSKUHandler {
 		// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes/gpu-accelerated/ndh100v5-series
 		{SKU: "Standard_ND96isr_H100_v5", GPUCount: 8, GPUMem: resource.MustParse("640Gi"), GPUModel: "NVIDIA H100", NVMeDiskEnabled: true, CUDAComputeCapability: 9.0},
 		// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes/gpu-accelerated/nccadsh100v5-series
-		{SKU: "Standard_NCC40ads_H100_v5", GPUCount: 1, GPUMem: resource.MustParse("94Gi"), GPUModel: "NVIDIA H100", CUDAComputeCapability: 9.0},
+		{SKU: "Standard_NCC40ads_H100_v5", GPUCount: 1, GPUMem: resource.MustParse("94Gi"), GPUModel: "NVIDIA H100", CUDAComputeCapability: 9.0, ConfidentialCompute: true},
 		// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes/gpu-accelerated/nd-h200-v5-series
 		{SKU: "Standard_ND96isr_H200_v5", GPUCount: 8, GPUMem: resource.MustParse("1128Gi"), GPUModel: "NVIDIA H200", NVMeDiskEnabled: true, CUDAComputeCapability: 9.0},
+		// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes/gpu-accelerated/nd-gb200-v6-series
+		{SKU: "Standard_ND128isr_NDR_GB200_v6", GPUCount: 4, GPUMem: resource.MustParse("768Gi"), GPUModel: "NVIDIA GB200", NVMeDiskEnabled: true, CUDAComputeCapability: 10.0, Arch: consts.ArchitectureARM64},
 		{SKU: "Standard_NG32ads_V620_v1", GPUCount: 1, GPUMem: resource.MustParse("32Gi"), GPUModel: "AMD Radeon PRO V620"},
 		{SKU: "Standard_NG32adms_V620_v1", GPUCount: 1, GPUMem: resource.MustParse("32Gi"), GPUModel: "AMD Radeon PRO V620"},
 
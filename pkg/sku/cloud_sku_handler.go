@@ -36,11 +36,25 @@ type GPUConfig struct {
 	CUDAComputeCapability float64 // CUDA compute capability version (e.g., 7.5 for Turing, 8.0 for Ampere)
 	// IsMIG indicates that this config represents a MIG partition (slice) rather than full GPUs.
 	IsMIG bool
+	// Arch is the CPU architecture of the instance, e.g. arm64 for Grace-based GH200 and
+	// GB200 instances. Empty means amd64.
+	Arch string
+	// ConfidentialCompute indicates that the instance runs in a hardware TEE with the GPU
+	// in confidential computing mode, e.g. the Azure NCC H100 v5 series.
+	ConfidentialCompute bool
 }
 
 func (cfg *GPUConfig) String() string {
-	return fmt.Sprintf("SKU: %s, GPUCount: %d, GPUMem: %s, GPUModel: %s, NVMeDiskEnabled: %t, CUDAComputeCapability: %.1f",
-		cfg.SKU, cfg.GPUCount, cfg.GPUMem.String(), cfg.GPUModel, cfg.NVMeDiskEnabled, cfg.CUDAComputeCapability)
+	return fmt.Sprintf("SKU: %s, GPUCount: %d, GPUMem: %s, GPUModel: %s, NVMeDiskEnabled: %t, CUDAComputeCapability: %.1f, Arch: %s",
+		cfg.SKU, cfg.GPUCount, cfg.GPUMem.String(), cfg.GPUModel, cfg.NVMeDiskEnabled, cfg.CUDAComputeCapability, cfg.Architecture())
+}
+
+// Architecture returns the CPU architecture of the instance, as in the kubernetes.io/arch node label.
+func (cfg *GPUConfig) Architecture() string {
+	if cfg.Arch == "" {
+		return consts.ArchitectureAMD64
+	}
+	return cfg.Arch
 }
 
 // SupportsBFloat16 returns true if the GPU supports bfloat16 (requires CUDA compute capability >= 8.0).
@@ -51,6 +51,20 @@ func IsAzureCloudProvider() bool {
 	return os.Getenv("CLOUD_PROVIDER") == consts.AzureCloudName
 }
 
+// defaultCPUInstanceTypes are the instance types provisioned for workloads that need no GPU,
+// such as a small embedding model, by cloud provider.
+var defaultCPUInstanceTypes = map[string]string{
+	consts.AzureCloudName: "Standard_D8s_v5",
+	consts.AWSCloudName:   "m6i.2xlarge",
+}
+
+// DefaultCPUInstanceType returns the instance type provisioned for a workload that needs no
+// GPU on the cloud provider configured via the CLOUD_PROVIDER environment variable, or an
+// empty string when the provider has none, e.g. Arc, which does not provision nodes.
+func DefaultCPUInstanceType() string {
+	return defaultCPUInstanceTypes[os.Getenv("CLOUD_PROVIDER")]
+}
+
 // GetGPUConfigBySKU returns the GPUConfig for the given instance type using
 // the cloud provider configured via the CLOUD_PROVIDER environment variable.
 func GetGPUConfigBySKU(instanceType string) (*GPUConfig, error) {
@@ -115,6 +129,7 @@ func GetGPUConfigFromNodeLabels(node *corev1.Node) (*GPUConfig, error) {
 		GPUMem:                *resource.NewQuantity(gpuMemGiB*consts.GiBToBytes, resource.BinarySI),
 		CUDAComputeCapability: cudaComputeCap,
 		IsMIG:                 isMIGNode(node),
+		Arch:                  node.Labels[corev1.LabelArchStable],
 	}, nil
 }
 
@@ -45,6 +45,18 @@ func TestGetSKUHandler(t *testing.T) {
 	})
 }
 
+func TestDefaultCPUInstanceType(t *testing.T) {
+	for provider, want := range map[string]string{
+		consts.AzureCloudName: "Standard_D8s_v5",
+		consts.AWSCloudName:   "m6i.2xlarge",
+		consts.ArcCloudName:   "",
+		"":                    "",
+	} {
+		t.Setenv("CLOUD_PROVIDER", provider)
+		assert.Equal(t, want, DefaultCPUInstanceType(), "provider %q", provider)
+	}
+}
+
 func TestGetGPUConfigFromNvidiaLabels(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -118,6 +130,31 @@ func TestGetGPUConfigFromNvidiaLabels(t *testing.T) {
 				CUDAComputeCapability: 7.5,
 			},
 		},
+		{
+			name: "GH200 node reports arm64 architecture",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "gpu-node-gh200",
+					Labels: map[string]string{
+						"kubernetes.io/arch":            "arm64",
+						"nvidia.com/gpu.product":        "NVIDIA-GH200-480GB",
+						"nvidia.com/gpu.count":          "1",
+						"nvidia.com/gpu.memory":         "97871",
+						"nvidia.com/cuda.compute.major": "9",
+						"nvidia.com/cuda.compute.minor": "0",
+					},
+				},
+			},
+			wantErr: false,
+			expected: &GPUConfig{
+				SKU:                   "unknown",
+				GPUCount:              1,
+				GPUModel:              "NVIDIA-GH200-480GB",
+				GPUMem:                resource.MustParse("96Gi"),
+				CUDAComputeCapability: 9.0,
+				Arch:                  "arm64",
+			},
+		},
 		{
 			name: "MIG active (mixed/single) sets IsMIG",
 			node: &corev1.Node{
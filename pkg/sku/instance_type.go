@@ -0,0 +1,131 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sku
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// maxSKUSuggestions is the number of near-matches listed for an unknown instance type.
+	maxSKUSuggestions = 3
+)
+
+// azureUncataloguedSKUPrefixes are the Azure VM families accepted outside the SKU catalog,
+// which only lists the GPU SKUs KAITO has validated.
+var azureUncataloguedSKUPrefixes = []string{"Standard_N", "Standard_D"}
+
+// UnsupportedSKUError reports an instance type that is not in the SKU catalog of the
+// cloud provider.
+type UnsupportedSKUError struct {
+	Provider string
+	SKU      string
+	// ListedBy names the other cloud providers whose catalogs list the SKU.
+	ListedBy []string
+	// Suggestions are the catalog SKUs closest to SKU, nearest first.
+	Suggestions []string
+}
+
+func (e *UnsupportedSKUError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Unsupported instance type %s for cloud provider %s", e.SKU, e.Provider)
+	if len(e.ListedBy) > 0 {
+		fmt.Fprintf(&b, "; it is only listed for %s", strings.Join(e.ListedBy, ", "))
+	}
+	if len(e.Suggestions) > 0 {
+		fmt.Fprintf(&b, "; did you mean %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return b.String()
+}
+
+// CheckInstanceType returns an *UnsupportedSKUError when instanceType is not in the
+// catalog of handler. On Azure, N- and D-series SKUs outside the catalog are accepted
+// unless the catalog of another cloud provider lists them, e.g. the Azure Local A2 SKUs.
+func CheckInstanceType(provider string, handler CloudSKUHandler, instanceType string) error {
+	if handler.GetGPUConfigBySKU(instanceType) != nil {
+		return nil
+	}
+	var listedBy []string
+	for _, other := range []string{consts.AzureCloudName, consts.AWSCloudName, consts.ArcCloudName} {
+		if other == provider {
+			continue
+		}
+		if h := GetCloudSKUHandler(other); h != nil && h.GetGPUConfigBySKU(instanceType) != nil {
+			listedBy = append(listedBy, other)
+		}
+	}
+	if provider == consts.AzureCloudName && len(listedBy) == 0 && HasSKUNamePrefix(instanceType, azureUncataloguedSKUPrefixes...) {
+		return nil
+	}
+	return &UnsupportedSKUError{
+		Provider:    provider,
+		SKU:         instanceType,
+		ListedBy:    listedBy,
+		Suggestions: SuggestSKUs(instanceType, handler.GetSupportedSKUs(), maxSKUSuggestions),
+	}
+}
+
+// SuggestSKUs returns up to limit candidates closest to name by case-insensitive edit
+// distance, nearest first. Candidates further than a third of the length of name are
+// not considered near-matches.
+func SuggestSKUs(name string, candidates []string, limit int) []string {
+	type match struct {
+		sku      string
+		distance int
+	}
+	lowerName := strings.ToLower(name)
+	maxDistance := max(len(lowerName)/3, 2)
+	var matches []match
+	for _, candidate := range candidates {
+		if d := editDistance(lowerName, strings.ToLower(candidate)); d <= maxDistance {
+			matches = append(matches, match{sku: candidate, distance: d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.sku, b.sku)
+	})
+	suggestions := make([]string, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		suggestions = append(suggestions, m.sku)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
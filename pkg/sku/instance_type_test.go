@@ -0,0 +1,113 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sku
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestCheckInstanceType(t *testing.T) {
+	tests := []struct {
+		name            string
+		provider        string
+		sku             string
+		wantErr         bool
+		wantListedBy    []string
+		wantSuggestions []string
+	}{
+		{name: "catalog sku", provider: consts.AzureCloudName, sku: "Standard_NC24ads_A100_v4"},
+		{name: "catalog sku in lower case", provider: consts.AzureCloudName, sku: "standard_nc24ads_a100_v4"},
+		{name: "uncatalogued azure N-series sku", provider: consts.AzureCloudName, sku: "Standard_NC8as_T4_v3"},
+		{name: "uncatalogued azure D-series sku", provider: consts.AzureCloudName, sku: "Standard_D4s_v5"},
+		{
+			name:         "arc sku on azure",
+			provider:     consts.AzureCloudName,
+			sku:          "Standard_NC8_A2",
+			wantErr:      true,
+			wantListedBy: []string{consts.ArcCloudName},
+		},
+		{
+			name:            "misspelled aws sku",
+			provider:        consts.AWSCloudName,
+			sku:             "g5.xlarg",
+			wantErr:         true,
+			wantSuggestions: []string{"g5.xlarge", "g5.2xlarge", "g5.4xlarge"},
+		},
+		{
+			name:     "unrelated aws sku",
+			provider: consts.AWSCloudName,
+			sku:      "m7i.metal-48xl",
+			wantErr:  true,
+		},
+		{
+			name:         "azure sku on aws",
+			provider:     consts.AWSCloudName,
+			sku:          "Standard_NC24ads_A100_v4",
+			wantErr:      true,
+			wantListedBy: []string{consts.AzureCloudName},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckInstanceType(tt.provider, GetCloudSKUHandler(tt.provider), tt.sku)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var unsupported *UnsupportedSKUError
+			if !errors.As(err, &unsupported) {
+				t.Fatalf("expected *UnsupportedSKUError, got %v", err)
+			}
+			assert.Equal(t, tt.sku, unsupported.SKU)
+			assert.Equal(t, tt.provider, unsupported.Provider)
+			assert.Equal(t, tt.wantListedBy, unsupported.ListedBy)
+			if tt.wantSuggestions != nil {
+				assert.Equal(t, tt.wantSuggestions, unsupported.Suggestions)
+			}
+		})
+	}
+}
+
+func TestUnsupportedSKUErrorMessage(t *testing.T) {
+	err := &UnsupportedSKUError{
+		Provider:    consts.AzureCloudName,
+		SKU:         "Standard_NC8_A2",
+		ListedBy:    []string{consts.ArcCloudName},
+		Suggestions: []string{"Standard_NV8as_v4"},
+	}
+	assert.Equal(t, "Unsupported instance type Standard_NC8_A2 for cloud provider azure; it is only listed for arc; did you mean Standard_NV8as_v4?", err.Error())
+}
+
+func TestSuggestSKUs(t *testing.T) {
+	candidates := []string{"Standard_NC24ads_A100_v4", "Standard_NC48ads_A100_v4", "Standard_NV36ads_A10_v5"}
+
+	assert.Equal(t, []string{"Standard_NC24ads_A100_v4", "Standard_NC48ads_A100_v4"},
+		SuggestSKUs("standard_nc24ads_a100_v5", candidates, 2))
+	assert.Equal(t, []string{"Standard_NV36ads_A10_v5"}, SuggestSKUs("Standard_NV36ads_A10", candidates, 1))
+	assert.Empty(t, SuggestSKUs("p5.48xlarge", candidates, 3))
+	assert.Empty(t, SuggestSKUs("Standard_NC24ads_A100_v4", candidates, 0))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("", ""))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 1, editDistance("g5.xlarg", "g5.xlarge"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
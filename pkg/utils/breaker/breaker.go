@@ -0,0 +1,258 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package breaker retries calls to external dependencies with jittered backoff and stops
+// calling a dependency that keeps failing, so reconcilers do not hammer an unavailable
+// cloud API or flood the logs with the same error.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// ErrOpen is returned, wrapped, while the breaker of a dependency is open.
+var ErrOpen = errors.New("circuit breaker open")
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failed calls that opens a breaker.
+	DefaultFailureThreshold = 5
+	// DefaultOpenDuration is how long an open breaker rejects calls before letting one through.
+	DefaultOpenDuration = 30 * time.Second
+)
+
+// DefaultBackoff is the retry budget of a single call: up to three attempts spread over
+// about a second, with jitter so reconcilers retrying at once do not stay in lockstep.
+var DefaultBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.5,
+	Steps:    3,
+}
+
+// Breaker guards one external dependency. It is safe for concurrent use.
+type Breaker struct {
+	name             string
+	failureThreshold int
+	openDuration     time.Duration
+	backoff          wait.Backoff
+	now              func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+	lastErr  error
+}
+
+// Option customizes a Breaker.
+type Option func(*Breaker)
+
+// WithFailureThreshold sets the number of consecutive failed calls that opens the breaker.
+func WithFailureThreshold(n int) Option {
+	return func(b *Breaker) { b.failureThreshold = n }
+}
+
+// WithOpenDuration sets how long the breaker stays open.
+func WithOpenDuration(d time.Duration) Option {
+	return func(b *Breaker) { b.openDuration = d }
+}
+
+// WithBackoff sets the retry budget of a single call.
+func WithBackoff(backoff wait.Backoff) Option {
+	return func(b *Breaker) { b.backoff = backoff }
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Breaker{}
+)
+
+// Get returns the breaker of the named dependency, creating it with opts on first use.
+// Breakers are shared process-wide so every caller of a dependency sees the same state.
+func Get(name string, opts ...Option) *Breaker {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if b, ok := registry[name]; ok {
+		return b
+	}
+	b := &Breaker{
+		name:             name,
+		failureThreshold: DefaultFailureThreshold,
+		openDuration:     DefaultOpenDuration,
+		backoff:          DefaultBackoff,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	registry[name] = b
+	return b
+}
+
+// All returns every registered breaker, sorted by name.
+func All() []*Breaker {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	all := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		all = append(all, b)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+	return all
+}
+
+// Name returns the name of the guarded dependency.
+func (b *Breaker) Name() string { return b.name }
+
+// Do calls fn, retrying transient errors within the retry budget. While the breaker is
+// open, fn is not called and an error wrapping ErrOpen is returned. Errors that are not
+// transient are returned as is and do not count as failures of the dependency.
+func (b *Breaker) Do(ctx context.Context, fn func(context.Context) error) error {
+	return b.call(ctx, b.backoff, fn)
+}
+
+// DoOnce is like Do but calls fn at most once, for calls that are not safe to repeat
+// before the caller has observed their effect.
+func (b *Breaker) DoOnce(ctx context.Context, fn func(context.Context) error) error {
+	return b.call(ctx, wait.Backoff{Steps: 1}, fn)
+}
+
+func (b *Breaker) call(ctx context.Context, backoff wait.Backoff, fn func(context.Context) error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		lastErr = fn(ctx)
+		if lastErr == nil {
+			return true, nil
+		}
+		if !IsTransient(lastErr) {
+			return false, lastErr
+		}
+		return false, nil
+	})
+	switch {
+	case err == nil:
+		b.record(nil)
+		return nil
+	case ctx.Err() != nil:
+		// The caller gave up; that says nothing about the dependency.
+		b.release()
+		if lastErr != nil {
+			return lastErr
+		}
+		return err
+	case !IsTransient(lastErr):
+		b.record(nil)
+		return lastErr
+	default:
+		b.record(lastErr)
+		return lastErr
+	}
+}
+
+// allow returns an error while the breaker is open. Once the open duration has passed,
+// a single call is let through to probe the dependency.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.failureThreshold {
+		return nil
+	}
+	if remaining := b.openedAt.Add(b.openDuration).Sub(b.now()); remaining > 0 || b.probing {
+		return fmt.Errorf("%w for %s after %d failures, retrying in %s: %v",
+			ErrOpen, b.name, b.failures, max(remaining, 0).Round(time.Second), b.lastErr)
+	}
+	b.probing = true
+	return nil
+}
+
+// release ends a probe without recording its outcome.
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		if b.failures >= b.failureThreshold {
+			klog.InfoS("Dependency recovered, closing circuit breaker", "dependency", b.name)
+		}
+		b.failures = 0
+		b.lastErr = nil
+		return
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.failureThreshold {
+		if b.failures == b.failureThreshold {
+			klog.ErrorS(err, "Dependency keeps failing, opening circuit breaker", "dependency", b.name, "openDuration", b.openDuration)
+		}
+		b.openedAt = b.now()
+	}
+}
+
+// RetryAfter returns how long until an open breaker lets a call through, or zero if the
+// breaker is closed.
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.failureThreshold {
+		return 0
+	}
+	return max(b.openedAt.Add(b.openDuration).Sub(b.now()), 0)
+}
+
+// Check reports the breaker state as a health check: it fails while the breaker is open.
+// It matches the healthz.Checker signature so it can be served on the readyz endpoint.
+func (b *Breaker) Check(_ *http.Request) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures >= b.failureThreshold {
+		return fmt.Errorf("%s is unavailable after %d consecutive failures: %v", b.name, b.failures, b.lastErr)
+	}
+	return nil
+}
+
+// IsTransient reports whether err is worth retrying: API server overload, internal errors
+// and timeouts, and network errors. Errors caused by the request itself are not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, ErrOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func newTestBreaker(name string) (*Breaker, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := Get(name, WithFailureThreshold(2), WithOpenDuration(time.Minute), WithBackoff(wait.Backoff{Duration: time.Millisecond, Steps: 3}))
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreakerRetriesTransientErrors(t *testing.T) {
+	b, _ := newTestBreaker("test-retry")
+	calls := 0
+	err := b.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return apierrors.NewServiceUnavailable("busy")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.NoError(t, b.Check(nil))
+}
+
+func TestBreakerDoesNotRetryRequestErrors(t *testing.T) {
+	b, _ := newTestBreaker("test-request-error")
+	calls := 0
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "nodeclaims"}, "nc")
+	for range 3 {
+		err := b.Do(context.Background(), func(context.Context) error {
+			calls++
+			return notFound
+		})
+		assert.Equal(t, notFound, err)
+	}
+	assert.Equal(t, 3, calls)
+	assert.NoError(t, b.Check(nil), "request errors must not open the breaker")
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	b, now := newTestBreaker("test-open")
+	calls := 0
+	failing := func(context.Context) error {
+		calls++
+		return apierrors.NewInternalError(errors.New("cloud API down"))
+	}
+
+	assert.Error(t, b.Do(context.Background(), failing))
+	assert.NoError(t, b.Check(nil))
+	assert.Error(t, b.DoOnce(context.Background(), failing))
+	assert.Equal(t, 4, calls)
+	assert.ErrorContains(t, b.Check(nil), "test-open is unavailable after 2 consecutive failures")
+
+	err := b.Do(context.Background(), failing)
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.Equal(t, 4, calls, "an open breaker must not call the dependency")
+	assert.Equal(t, time.Minute, b.RetryAfter())
+
+	*now = now.Add(time.Minute)
+	assert.NoError(t, b.DoOnce(context.Background(), func(context.Context) error { return nil }))
+	assert.NoError(t, b.Check(nil))
+	assert.Zero(t, b.RetryAfter())
+}
+
+func TestGetSharesBreakers(t *testing.T) {
+	assert.Same(t, Get("test-shared"), Get("test-shared"))
+	assert.Contains(t, All(), Get("test-shared"))
+}
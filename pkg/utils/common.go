@@ -98,6 +98,15 @@ func BuildIfElseCmdStr(condition string, trueCmd string, trueCmdParams map[strin
 	return fmt.Sprintf("if %s; then %s; else %s; fi", condition, trueCmdStr, falseCmdStr)
 }
 
+// ShellQuote quotes s as a single word of a POSIX shell command. Words that only
+// contain characters without a meaning to the shell are returned as is.
+func ShellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._-+,:/=@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func ShellCmd(command string) []string {
 	return []string{
 		"/bin/sh",
@@ -137,7 +146,10 @@ func ExtractAndValidateRepoName(image string) error {
 	return nil
 }
 
-func SelectNodes(qualified []*corev1.Node, preferred []string, previous []string, count int) []*corev1.Node {
+// SelectNodes returns up to count of the qualified nodes. Preferred nodes rank first, then
+// the nodes used before, then the nodes that have the weights of model cached on local disk,
+// so a rescheduled workload skips the download.
+func SelectNodes(qualified []*corev1.Node, preferred []string, previous []string, model string, count int) []*corev1.Node {
 
 	sort.Slice(qualified, func(i, j int) bool {
 		iPreferred := Contains(preferred, qualified[i].Name)
@@ -155,24 +167,31 @@ func SelectNodes(qualified []*corev1.Node, preferred []string, previous []string
 				return true
 			} else if !iPrevious && jPrevious {
 				return false
-			} else { // either all are previous, or none is previous
-				var iCreatedByGPUProvisioner, jCreatedByGPUProvisioner bool
-				_, iCreatedByGPUProvisioner = qualified[i].Labels[consts.LabelGPUProvisionerCustom]
-				_, jCreatedByGPUProvisioner = qualified[j].Labels[consts.LabelGPUProvisionerCustom]
-				// Choose node created by gpu-provisioner and karpenter since it is more likely to be empty to use.
-				var iCreatedByKarpenter, jCreatedByKarpenter bool
-				_, iCreatedByKarpenter = qualified[i].Labels[consts.LabelNodePool]
-				_, jCreatedByKarpenter = qualified[j].Labels[consts.LabelNodePool]
-
-				if (iCreatedByGPUProvisioner && !jCreatedByGPUProvisioner) ||
-					(iCreatedByKarpenter && !jCreatedByKarpenter) {
-					return true
-				} else if (!iCreatedByGPUProvisioner && jCreatedByGPUProvisioner) ||
-					(!iCreatedByKarpenter && jCreatedByKarpenter) {
-					return false
-				} else {
-					return qualified[i].Name < qualified[j].Name
-				}
+			}
+
+			// either all are previous, or none is previous
+			iCached := HasCachedModel(qualified[i], model)
+			jCached := HasCachedModel(qualified[j], model)
+			if iCached != jCached {
+				return iCached
+			}
+
+			var iCreatedByGPUProvisioner, jCreatedByGPUProvisioner bool
+			_, iCreatedByGPUProvisioner = qualified[i].Labels[consts.LabelGPUProvisionerCustom]
+			_, jCreatedByGPUProvisioner = qualified[j].Labels[consts.LabelGPUProvisionerCustom]
+			// Choose node created by gpu-provisioner and karpenter since it is more likely to be empty to use.
+			var iCreatedByKarpenter, jCreatedByKarpenter bool
+			_, iCreatedByKarpenter = qualified[i].Labels[consts.LabelNodePool]
+			_, jCreatedByKarpenter = qualified[j].Labels[consts.LabelNodePool]
+
+			if (iCreatedByGPUProvisioner && !jCreatedByGPUProvisioner) ||
+				(iCreatedByKarpenter && !jCreatedByKarpenter) {
+				return true
+			} else if (!iCreatedByGPUProvisioner && jCreatedByGPUProvisioner) ||
+				(!iCreatedByKarpenter && jCreatedByKarpenter) {
+				return false
+			} else {
+				return qualified[i].Name < qualified[j].Name
 			}
 		}
 	})
@@ -184,6 +203,46 @@ func SelectNodes(qualified []*corev1.Node, preferred []string, previous []string
 	return qualified[0:count]
 }
 
+// HasCachedModel reports whether the model cache annotation of node lists model.
+func HasCachedModel(node *corev1.Node, model string) bool {
+	if model == "" {
+		return false
+	}
+	for _, cached := range strings.Split(node.Annotations[consts.AnnotationNodeCachedModels], ",") {
+		if strings.EqualFold(strings.TrimSpace(cached), model) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxCachedModels bounds the model cache annotation of a Node. The disk of a Node only
+// holds a few models, so the oldest entries are dropped first.
+const maxCachedModels = 8
+
+// AddCachedModel adds model to the model cache annotation of node and reports whether the
+// annotation changed.
+func AddCachedModel(node *corev1.Node, model string) bool {
+	if model == "" || HasCachedModel(node, model) {
+		return false
+	}
+	var models []string
+	for _, cached := range strings.Split(node.Annotations[consts.AnnotationNodeCachedModels], ",") {
+		if cached = strings.TrimSpace(cached); cached != "" {
+			models = append(models, cached)
+		}
+	}
+	models = append(models, model)
+	if len(models) > maxCachedModels {
+		models = models[len(models)-maxCachedModels:]
+	}
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[consts.AnnotationNodeCachedModels] = strings.Join(models, ",")
+	return true
+}
+
 // ParseHuggingFaceModelVersion parses the model version in the format of https://huggingface.co/<org>/<model>/commit/<revision>
 // and returns the repoId and revision. If the commit is not specified, it returns an empty string for revision,
 // and the main branch HEAD commit is used.
@@ -14,6 +14,7 @@
 package utils
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -488,6 +489,15 @@ func TestShellCmd(t *testing.T) {
 	assert.Equal(t, []string{"/bin/sh", "-c", "echo hello"}, cmd)
 }
 
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "FLASHINFER", ShellQuote("FLASHINFER"))
+	assert.Equal(t, "/models/a-b_c.json", ShellQuote("/models/a-b_c.json"))
+	assert.Equal(t, "''", ShellQuote(""))
+	assert.Equal(t, `'{"level": 3}'`, ShellQuote(`{"level": 3}`))
+	assert.Equal(t, `'$(id)'`, ShellQuote("$(id)"))
+	assert.Equal(t, `'it'\''s'`, ShellQuote("it's"))
+}
+
 func TestExtractAndValidateRepoName(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -522,7 +532,7 @@ func TestSelectNodes(t *testing.T) {
 			makeNode("node-b", nil),
 			makeNode("node-a", nil),
 		}
-		result := SelectNodes(nodes, []string{"node-b"}, nil, 3)
+		result := SelectNodes(nodes, []string{"node-b"}, nil, "", 3)
 		assert.Len(t, result, 3)
 		assert.Equal(t, "node-b", result[0].Name)
 	})
@@ -533,13 +543,13 @@ func TestSelectNodes(t *testing.T) {
 			makeNode("node-2", nil),
 			makeNode("node-3", nil),
 		}
-		result := SelectNodes(nodes, nil, nil, 2)
+		result := SelectNodes(nodes, nil, nil, "", 2)
 		assert.Len(t, result, 2)
 	})
 
 	t.Run("returns all when count exceeds available", func(t *testing.T) {
 		nodes := []*corev1.Node{makeNode("node-1", nil)}
-		result := SelectNodes(nodes, nil, nil, 5)
+		result := SelectNodes(nodes, nil, nil, "", 5)
 		assert.Len(t, result, 1)
 	})
 
@@ -548,7 +558,7 @@ func TestSelectNodes(t *testing.T) {
 			makeNode("node-prov", map[string]string{consts.LabelGPUProvisionerCustom: "gpu"}),
 			makeNode("node-prev", nil),
 		}
-		result := SelectNodes(nodes, nil, []string{"node-prev"}, 2)
+		result := SelectNodes(nodes, nil, []string{"node-prev"}, "", 2)
 		assert.Len(t, result, 2)
 		assert.Equal(t, "node-prev", result[0].Name)
 	})
@@ -558,22 +568,61 @@ func TestSelectNodes(t *testing.T) {
 			makeNode("plain", nil),
 			makeNode("karpenter", map[string]string{consts.LabelNodePool: "default"}),
 		}
-		result := SelectNodes(nodes, nil, nil, 2)
+		result := SelectNodes(nodes, nil, nil, "", 2)
 		assert.Len(t, result, 2)
 		assert.Equal(t, "karpenter", result[0].Name)
 	})
 
+	t.Run("node caching the model ranked above provisioner node", func(t *testing.T) {
+		nodes := []*corev1.Node{
+			makeNode("node-prov", map[string]string{consts.LabelGPUProvisionerCustom: "gpu"}),
+			makeNode("node-cached", nil),
+		}
+		nodes[1].Annotations = map[string]string{consts.AnnotationNodeCachedModels: "phi-4, Qwen/Qwen3-8B-AWQ"}
+		result := SelectNodes(nodes, nil, nil, "qwen/qwen3-8b-awq", 2)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "node-cached", result[0].Name)
+
+		result = SelectNodes(nodes, nil, nil, "phi-3-mini-4k-instruct", 2)
+		assert.Equal(t, "node-prov", result[0].Name)
+	})
+
+	t.Run("previous node ranked above node caching the model", func(t *testing.T) {
+		nodes := []*corev1.Node{
+			makeNode("node-cached", nil),
+			makeNode("node-prev", nil),
+		}
+		nodes[0].Annotations = map[string]string{consts.AnnotationNodeCachedModels: "phi-4"}
+		result := SelectNodes(nodes, nil, []string{"node-prev"}, "phi-4", 1)
+		assert.Equal(t, "node-prev", result[0].Name)
+	})
+
 	t.Run("alphabetical fallback for equal priority", func(t *testing.T) {
 		nodes := []*corev1.Node{
 			makeNode("node-z", nil),
 			makeNode("node-a", nil),
 		}
-		result := SelectNodes(nodes, nil, nil, 2)
+		result := SelectNodes(nodes, nil, nil, "", 2)
 		assert.Len(t, result, 2)
 		assert.Equal(t, "node-a", result[0].Name)
 	})
 }
 
+func TestAddCachedModel(t *testing.T) {
+	node := &corev1.Node{}
+	assert.True(t, AddCachedModel(node, "phi-4"))
+	assert.Equal(t, "phi-4", node.Annotations[consts.AnnotationNodeCachedModels])
+	assert.False(t, AddCachedModel(node, "Phi-4"))
+	assert.False(t, AddCachedModel(node, ""))
+
+	for i := range maxCachedModels {
+		assert.True(t, AddCachedModel(node, fmt.Sprintf("model-%d", i)))
+	}
+	assert.False(t, HasCachedModel(node, "phi-4"))
+	assert.True(t, HasCachedModel(node, "model-0"))
+	assert.True(t, HasCachedModel(node, fmt.Sprintf("model-%d", maxCachedModels-1)))
+}
+
 func TestGetReleaseNamespace(t *testing.T) {
 	t.Run("reads from env var", func(t *testing.T) {
 		t.Setenv(consts.DefaultReleaseNamespaceEnvVar, "my-namespace")
@@ -65,11 +65,25 @@ const (
 	FeatureFlagModelMirror                        = "ModelMirror"
 	FeatureFlagModelStreaming                     = "ModelStreaming"
 	FeatureFlagEnableBaseImageAutoUpgrade         = "enableBaseImageAutoUpgrade"
+	FeatureFlagNamespaceDeletionProtection        = "namespaceDeletionProtection"
+	FeatureFlagWorkspacePriorityQueue             = "workspacePriorityQueue"
+	FeatureFlagImageVerification                  = "imageVerification"
+	FeatureFlagGPUUtilizationCollection           = "gpuUtilizationCollection"
+	FeatureFlagBatchInference                     = "batchInference"
+	FeatureFlagInferenceMetricsCollection         = "inferenceMetricsCollection"
+	FeatureFlagInferenceProfileCache              = "inferenceProfileCache"
+
+	// CPU architectures of GPU nodes, as in the kubernetes.io/arch node label.
+	ArchitectureAMD64 = "amd64"
+	ArchitectureARM64 = "arm64"
 
 	// Node provisioner types
 	NodeProvisionerAzureGPU  = "azure-gpu-provisioner"
 	NodeProvisionerKarpenter = "karpenter"
 	NodeProvisionerBYO       = "byo"
+	// NodeProvisionerClusterAutoscaler scales pre-created GPU node groups with the cluster
+	// autoscaler; it needs no Karpenter CRDs.
+	NodeProvisionerClusterAutoscaler = "cluster-autoscaler"
 
 	// CSI driver names for model streaming (workspace controller + webhook scope).
 	CSIDriverNameAzureBlob = "blob.csi.azure.com"
@@ -96,6 +110,18 @@ func IsKarpenterProvisioner() bool {
 	return ActiveNodeProvisioner == NodeProvisionerKarpenter
 }
 
+// UsesNodeClaims returns true if the active node provisioner may create Karpenter
+// NodeClaims, so their CRD is expected to be installed. Node provisioner plugins do not
+// create NodeClaims. An unset provisioner is the default azure-gpu-provisioner.
+func UsesNodeClaims() bool {
+	switch ActiveNodeProvisioner {
+	case "", NodeProvisionerAzureGPU, NodeProvisionerKarpenter:
+		return true
+	default:
+		return false
+	}
+}
+
 const (
 	// Nodeclaim related consts
 	KaitoNodePoolName             = "kaito"
@@ -114,6 +140,11 @@ const (
 	ProvisionerName           = "default"
 	LabelGPUProvisionerCustom = "kaito.sh/machine-type"
 
+	// AnnotationNodeCachedModels is set on a Node by the workspace controller once inference
+	// pods of a preset are ready on it, and lists the comma-separated models whose images
+	// are cached on the local disk of the Node.
+	AnnotationNodeCachedModels = "kaito.sh/cached-models"
+
 	// azure gpu sku prefix
 	GpuSkuPrefix = "Standard_N"
 
@@ -154,15 +185,70 @@ const (
 	RoutingSidecarImage = "mcr.microsoft.com/oss/v2/llm-d/llm-d-routing-sidecar"
 	RoutingSidecarTag   = "v0.8.0"
 
-	// PortDecodeVLLM is the port vLLM listens on in decode pods.
-	// The routing sidecar occupies port 5000 (PortInferenceServer), so vLLM
-	// is moved to 5001. The sidecar forwards traffic to this port.
+	// LogForwarderImage is the fluent-bit image used by the optional log
+	// forwarder sidecar (InferenceSpec.Logging.Forwarder).
+	LogForwarderImage = "cr.fluentbit.io/fluent/fluent-bit:3.2.4"
+
+	// LogFormatEnvName tells the inference server which log format to emit
+	// ("text" or "json").
+	LogFormatEnvName = "KAITO_LOG_FORMAT"
+
+	// LogFileEnvName tells the inference server to also write its logs to the given
+	// file, which the log forwarder sidecar tails.
+	LogFileEnvName = "KAITO_LOG_FILE"
+
+	// OTELTracesEndpointEnvName holds the OTLP collector URL of InferenceSpec.Tracing.
+	// The vLLM command line refers to it instead of embedding the URL.
+	OTELTracesEndpointEnvName = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	// MaxModelLenEnvName passes a cached max-model-len profiling result to the
+	// inference server, which uses it instead of --max-model-len=auto.
+	MaxModelLenEnvName = "KAITO_MAX_MODEL_LEN"
+
+	// InferenceProfileCacheConfigMapName is the ConfigMap in the release namespace
+	// that caches the max-model-len profiling results of vLLM workspaces.
+	InferenceProfileCacheConfigMapName = "kaito-inference-profiles"
+
+	// PortDecodeVLLM is the port vLLM listens on in decode pods and in pods
+	// with a response cache, a tier router or an API normalizer. The sidecar
+	// occupies port 5000 (PortInferenceServer), so vLLM is moved to 5001. The
+	// sidecar forwards traffic to this port.
 	PortDecodeVLLM = int32(5001)
 
+	// ResponseCacheContainerName is the name of the response cache sidecar
+	// (InferenceSpec.ResponseCache), which runs from the KAITO base image.
+	ResponseCacheContainerName = "response-cache"
+
+	// ResponseCacheRedisPasswordKey is the key of the Redis password in the
+	// Secret referenced by InferenceSpec.ResponseCache.Redis.PasswordSecret.
+	ResponseCacheRedisPasswordKey = "password"
+
+	// TierRouterContainerName is the name of the sidecar that forwards prompts
+	// longer than the tier limit of a tiered MultiRoleInference workspace to the
+	// next tier. It runs from the KAITO base image.
+	TierRouterContainerName = "tier-router"
+
+	// APINormalizerContainerName is the name of the sidecar that adapts OpenAI API
+	// requests to the inference server (InferenceSpec.APINormalization). It runs from
+	// the KAITO base image.
+	APINormalizerContainerName = "api-normalizer"
+
+	// TokenizerContainerName is the name of the sidecar that serves the tokenizer endpoint
+	// (InferenceSpec.Tokenizer). It runs from the KAITO base image.
+	TokenizerContainerName = "tokenizer"
+
+	// PortTokenizer is the port the tokenizer sidecar listens on. The Service maps
+	// InferenceSpec.Tokenizer.Port to it.
+	PortTokenizer = int32(5002)
+
 	// InferenceRoleEnvName is the environment variable name used to pass the
 	// inference role (prefill/decode) to the model container in P/D disaggregated serving.
 	InferenceRoleEnvName = "KAITO_INFERENCE_ROLE"
 
+	// VLLMHTTPTimeoutKeepAliveEnvName sets how many seconds the vLLM API server keeps an
+	// idle HTTP keep-alive connection open.
+	VLLMHTTPTimeoutKeepAliveEnvName = "VLLM_HTTP_TIMEOUT_KEEP_ALIVE"
+
 	// VLLMUseFlashInferSamplerEnvName toggles vLLM's FlashInfer-based sampler.
 	// KAITO does not support FlashInfer, so it is set to "0" to keep vLLM on the
 	// Torch-native sampling path and avoid runtime JIT kernel compilation, which
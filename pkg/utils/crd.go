@@ -31,6 +31,10 @@ func EnsureKindExists(restConfig *rest.Config, gvk schema.GroupVersionKind) (boo
 	if client.IgnoreNotFound(err) != nil {
 		return false, err
 	}
+	if resources == nil {
+		// The group version is not served at all.
+		return false, nil
+	}
 
 	for _, r := range resources.APIResources {
 		if r.Kind == gvk.Kind {
@@ -0,0 +1,140 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crdwatch tracks whether an optional CRD is installed, so controllers can run
+// without it and enable the features that need it once it appears.
+package crdwatch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+// DefaultInterval is the default interval between two discovery checks.
+const DefaultInterval = time.Minute
+
+// Callback is run once the watched kind is served. It is retried on the next check until
+// it succeeds.
+type Callback func(ctx context.Context) error
+
+// Watcher polls API discovery until a kind is served, then runs the registered callbacks.
+// It never reports the kind as gone again: removing a CRD that is in use requires a
+// controller restart.
+type Watcher struct {
+	GVK      schema.GroupVersionKind
+	Interval time.Duration
+
+	served    func(ctx context.Context) (bool, error)
+	available atomic.Bool
+
+	mu        sync.Mutex
+	callbacks []Callback
+}
+
+// New returns a Watcher that checks the API server behind cfg for gvk.
+func New(cfg *rest.Config, gvk schema.GroupVersionKind) *Watcher {
+	return NewWithCheck(gvk, func(context.Context) (bool, error) {
+		return utils.EnsureKindExists(cfg, gvk)
+	})
+}
+
+// NewWithCheck returns a Watcher that uses served to check whether gvk is served.
+func NewWithCheck(gvk schema.GroupVersionKind, served func(ctx context.Context) (bool, error)) *Watcher {
+	return &Watcher{GVK: gvk, Interval: DefaultInterval, served: served}
+}
+
+// Available reports whether the kind was served at the last check.
+func (w *Watcher) Available() bool {
+	return w.available.Load()
+}
+
+// Check queries discovery for the kind and records the result.
+func (w *Watcher) Check(ctx context.Context) (bool, error) {
+	if w.Available() {
+		return true, nil
+	}
+	ok, err := w.served(ctx)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		klog.InfoS("CRD is installed", "kind", w.GVK.String())
+		w.available.Store(true)
+	}
+	return ok, nil
+}
+
+// OnAvailable registers fn to run once the kind is served. It must be called before the
+// Watcher is started.
+func (w *Watcher) OnAvailable(fn Callback) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// Start implements manager.Runnable. It returns once the kind is served and all callbacks
+// have succeeded.
+func (w *Watcher) Start(ctx context.Context) error {
+	if w.poll(ctx) {
+		return nil
+	}
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if w.poll(ctx) {
+				return nil
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica has to add
+// its own watches once the kind is served.
+func (w *Watcher) NeedLeaderElection() bool { return false }
+
+// poll checks for the kind and runs the pending callbacks once it is served. It reports
+// whether nothing is left to do.
+func (w *Watcher) poll(ctx context.Context) bool {
+	ok, err := w.Check(ctx)
+	if err != nil {
+		klog.ErrorS(err, "failed to check whether the CRD is installed", "kind", w.GVK.String())
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var pending []Callback
+	for _, fn := range w.callbacks {
+		if err := fn(ctx); err != nil {
+			klog.ErrorS(err, "failed to enable the features that need the CRD, retrying", "kind", w.GVK.String())
+			pending = append(pending, fn)
+		}
+	}
+	w.callbacks = pending
+	return len(pending) == 0
+}
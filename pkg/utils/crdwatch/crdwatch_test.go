@@ -0,0 +1,110 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdwatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTestWatcher(served *bool) *Watcher {
+	return &Watcher{
+		GVK:      schema.GroupVersionKind{Group: "karpenter.sh", Version: "v1", Kind: "NodeClaim"},
+		Interval: time.Millisecond,
+		served: func(context.Context) (bool, error) {
+			return *served, nil
+		},
+	}
+}
+
+func TestWatcherCheck(t *testing.T) {
+	served := false
+	w := newTestWatcher(&served)
+
+	ok, err := w.Check(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, w.Available())
+
+	served = true
+	ok, err = w.Check(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, w.Available())
+
+	// The kind is not reported as gone again.
+	served = false
+	ok, err = w.Check(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestWatcherCheckError(t *testing.T) {
+	w := newTestWatcher(nil)
+	w.served = func(context.Context) (bool, error) { return false, errors.New("discovery failed") }
+
+	ok, err := w.Check(context.Background())
+	assert.Error(t, err)
+	assert.False(t, ok)
+	assert.False(t, w.Available())
+}
+
+func TestWatcherStartRunsCallbacksOnceServed(t *testing.T) {
+	served := false
+	w := newTestWatcher(&served)
+	checks := 0
+	w.served = func(context.Context) (bool, error) {
+		checks++
+		return checks >= 3, nil
+	}
+
+	calls, failures := 0, 1
+	w.OnAvailable(func(context.Context) error {
+		calls++
+		if failures > 0 {
+			failures--
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, w.Start(ctx))
+	assert.NoError(t, ctx.Err(), "Start should return once the callbacks succeeded")
+	assert.True(t, w.Available())
+	assert.Equal(t, 2, calls, "a failed callback should be retried")
+	assert.Empty(t, w.callbacks)
+}
+
+func TestWatcherStartStopsOnCancel(t *testing.T) {
+	served := false
+	w := newTestWatcher(&served)
+	called := false
+	w.OnAvailable(func(context.Context) error {
+		called = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, w.Start(ctx))
+	assert.False(t, called)
+	assert.False(t, w.Available())
+}
@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -30,13 +29,16 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
 )
 
 // UpdateStatusConditionIfNotMatch updates the inferenceset status condition if it doesn't match the current values
 func UpdateStatusConditionIfNotMatch(ctx context.Context, c client.Client, iObj *kaitov1beta1.InferenceSet, cType kaitov1beta1.ConditionType,
 	cStatus metav1.ConditionStatus, cReason, cMessage string) error {
 	if curCondition := meta.FindStatusCondition(iObj.Status.Conditions, string(cType)); curCondition != nil {
-		if curCondition.Status == cStatus && curCondition.Reason == cReason && curCondition.Message == cMessage {
+		if curCondition.Status == cStatus && curCondition.Reason == cReason && curCondition.Message == cMessage &&
+			curCondition.ObservedGeneration == iObj.GetGeneration() {
 			// Nothing to change
 			return nil
 		}
@@ -58,26 +60,15 @@ func UpdateStatusConditionIfNotMatch(ctx context.Context, c client.Client, iObj
 
 // UpdateInferenceSetStatus updates the inferenceset status with the provided condition
 func UpdateInferenceSetStatus(ctx context.Context, c client.Client, name *client.ObjectKey, modifyFn func(*kaitov1beta1.InferenceSetStatus) error) error {
-	return retry.OnError(retry.DefaultRetry,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsConflict(err)
-		},
-		func() error {
-			// Read the latest version to avoid update conflict.
-			iObj := &kaitov1beta1.InferenceSet{}
-			if err := c.Get(ctx, *name, iObj); err != nil {
-				if !apierrors.IsNotFound(err) {
-					return err
-				}
-				return nil
+	return statuspatch.Update(ctx, c, *name, &kaitov1beta1.InferenceSet{}, func(iObj *kaitov1beta1.InferenceSet) error {
+		if modifyFn != nil {
+			if err := modifyFn(&iObj.Status); err != nil {
+				return err
 			}
-			if modifyFn != nil {
-				if err := modifyFn(&iObj.Status); err != nil {
-					return err
-				}
-			}
-			return c.Status().Update(ctx, iObj)
-		})
+		}
+		applyRolloutStatus(iObj)
+		return nil
+	})
 }
 
 // UpdateInferenceSetWithRetry gets the latest inferenceset object, applies the modify function, and retries on conflict
@@ -146,3 +137,41 @@ func MarshalInferenceSetFields(iObj *kaitov1beta1.InferenceSet) ([]byte, error)
 
 	return jsonData, nil
 }
+
+// applyRolloutStatus sets status.phase, status.health, status.observedGeneration and the
+// Progressing condition of the InferenceSet from its other conditions.
+func applyRolloutStatus(iObj *kaitov1beta1.InferenceSet) {
+	generation := iObj.GetGeneration()
+	phase, message := inferenceSetPhase(iObj)
+	iObj.Status.Phase = phase
+	iObj.Status.ObservedGeneration = generation
+	iObj.Status.Health = rollout.Health(generation, phase, message)
+	rollout.SetProgressing(&iObj.Status.Conditions, generation, phase, message)
+}
+
+func inferenceSetPhase(iObj *kaitov1beta1.InferenceSet) (kaitov1beta1.Phase, string) {
+	conditions := iObj.Status.Conditions
+	generation := iObj.GetGeneration()
+	if !iObj.DeletionTimestamp.IsZero() || meta.IsStatusConditionTrue(conditions, string(kaitov1beta1.InferenceSetConditionTypeDeleting)) {
+		return kaitov1beta1.PhaseDeleting, "inferenceset is being deleted"
+	}
+	if c := meta.FindStatusCondition(conditions, string(kaitov1beta1.InferenceSetConditionTypeMigration)); c != nil && c.Status == metav1.ConditionTrue {
+		return kaitov1beta1.PhaseProgressing, c.Message
+	}
+
+	ready := rollout.CurrentCondition(conditions, kaitov1beta1.InferenceSetConditionTypeReady, generation)
+	switch {
+	case ready == nil && meta.FindStatusCondition(conditions, string(kaitov1beta1.InferenceSetConditionTypeReady)) == nil:
+		return kaitov1beta1.PhasePending, "inferenceset has not been reconciled yet"
+	case ready == nil:
+		return kaitov1beta1.PhaseProgressing, "inferenceset is rolling out the latest spec"
+	case ready.Status == metav1.ConditionTrue:
+		return kaitov1beta1.PhaseReady, ready.Message
+	case ready.Reason == "inferencesetFailed":
+		return kaitov1beta1.PhaseFailed, ready.Message
+	case iObj.Status.Replicas == 0:
+		return kaitov1beta1.PhasePending, ready.Message
+	default:
+		return kaitov1beta1.PhaseProgressing, ready.Message
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +34,7 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -49,10 +51,11 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 			Status: kaitov1beta1.InferenceSetStatus{
 				Conditions: []metav1.Condition{
 					{
-						Type:    string(kaitov1beta1.ConditionTypeResourceStatus),
-						Status:  metav1.ConditionTrue,
-						Reason:  "ResourcesReady",
-						Message: "All resources are ready",
+						Type:               string(kaitov1beta1.ConditionTypeResourceStatus),
+						Status:             metav1.ConditionTrue,
+						Reason:             "ResourcesReady",
+						Message:            "All resources are ready",
+						ObservedGeneration: 1,
 					},
 				},
 			},
@@ -67,6 +70,54 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 
+	t.Run("Should update when condition was set for an older generation", func(t *testing.T) {
+		mockClient := test.NewClient()
+
+		inferenceset := &kaitov1beta1.InferenceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-inferenceset",
+				Namespace:  "default",
+				Generation: 2,
+			},
+			Status: kaitov1beta1.InferenceSetStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(kaitov1beta1.InferenceSetConditionTypeReady),
+						Status:             metav1.ConditionTrue,
+						Reason:             "inferencesetReady",
+						Message:            "inferenceset is ready",
+						ObservedGeneration: 1,
+					},
+				},
+			},
+		}
+
+		mockClient.On("Get", mock.IsType(context.Background()),
+			client.ObjectKey{Name: "test-inferenceset", Namespace: "default"},
+			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Run(func(args mock.Arguments) {
+			is := args.Get(2).(*kaitov1beta1.InferenceSet)
+			*is = *inferenceset
+		}).Return(nil)
+
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
+			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Run(func(args mock.Arguments) {
+			is := args.Get(1).(*kaitov1beta1.InferenceSet)
+			condition := meta.FindStatusCondition(is.Status.Conditions, string(kaitov1beta1.InferenceSetConditionTypeReady))
+			assert.NotNil(t, condition)
+			assert.Equal(t, int64(2), condition.ObservedGeneration)
+			assert.Equal(t, int64(2), is.Status.ObservedGeneration)
+			assert.Equal(t, kaitov1beta1.PhaseReady, is.Status.Phase)
+			assert.True(t, meta.IsStatusConditionFalse(is.Status.Conditions, string(kaitov1beta1.ConditionTypeProgressing)))
+		}).Return(nil)
+
+		err := UpdateStatusConditionIfNotMatch(context.Background(), mockClient, inferenceset,
+			kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionTrue, "inferencesetReady", "inferenceset is ready")
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		mockClient.StatusMock.AssertExpectations(t)
+	})
+
 	t.Run("Should update when condition status differs", func(t *testing.T) {
 		mockClient := test.NewClient()
 
@@ -96,8 +147,8 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Run(func(args mock.Arguments) {
 			ws := args.Get(1).(*kaitov1beta1.InferenceSet)
 			// Verify the condition was updated
@@ -146,8 +197,8 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(nil)
 
 		ctx := context.Background()
@@ -188,8 +239,8 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(nil)
 
 		ctx := context.Background()
@@ -223,8 +274,8 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Run(func(args mock.Arguments) {
 			ws := args.Get(1).(*kaitov1beta1.InferenceSet)
 			// Verify the condition was added
@@ -311,8 +362,8 @@ func TestUpdateInferenceSetStatus(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Run(func(args mock.Arguments) {
 			ws := args.Get(1).(*kaitov1beta1.InferenceSet)
 			// Verify the condition was set
@@ -411,8 +462,8 @@ func TestUpdateInferenceSetStatus(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(nil)
 
 		ctx := context.Background()
@@ -452,12 +503,12 @@ func TestUpdateInferenceSetStatus(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call to fail first with a retryable error, then succeed
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call to fail first with a retryable error, then succeed
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(
 			apierrors.NewConflict(schema.GroupResource{Group: "kaito.sh", Resource: "inferencesets"}, "test-inferenceset", fmt.Errorf("conflict"))).Once()
 
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(nil).Once()
 
 		ctx := context.Background()
@@ -500,8 +551,8 @@ func TestUpdateInferenceSetStatus(t *testing.T) {
 			*ws = *inferenceset
 		}).Return(nil)
 
-		// Mock the Status().Update call to fail with a non-retryable error
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call to fail with a non-retryable error
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.InferenceSet{}), mock.Anything).Return(fmt.Errorf("permanent error"))
 
 		ctx := context.Background()
@@ -959,3 +1010,56 @@ func TestListWorkspaces(t *testing.T) {
 		})
 	}
 }
+
+func TestInferenceSetPhase(t *testing.T) {
+	condition := func(cType kaitov1beta1.ConditionType, status metav1.ConditionStatus, reason string, generation int64) metav1.Condition {
+		return metav1.Condition{Type: string(cType), Status: status, Reason: reason, Message: reason, ObservedGeneration: generation}
+	}
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		replicas   int
+		deleting   bool
+		expected   kaitov1beta1.Phase
+	}{
+		{name: "new", expected: kaitov1beta1.PhasePending},
+		{name: "ready", conditions: []metav1.Condition{condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionTrue, "inferencesetReady", 2)},
+			replicas: 2, expected: kaitov1beta1.PhaseReady},
+		{name: "ready for an older generation", conditions: []metav1.Condition{condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionTrue, "inferencesetReady", 1)},
+			replicas: 2, expected: kaitov1beta1.PhaseProgressing},
+		{name: "replicas not ready", conditions: []metav1.Condition{condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionFalse, "inferencesetNotReady", 2)},
+			replicas: 2, expected: kaitov1beta1.PhaseProgressing},
+		{name: "no replicas yet", conditions: []metav1.Condition{condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionFalse, "inferencesetNotReady", 2)},
+			expected: kaitov1beta1.PhasePending},
+		{name: "failed", conditions: []metav1.Condition{condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionFalse, "inferencesetFailed", 2)},
+			replicas: 2, expected: kaitov1beta1.PhaseFailed},
+		{name: "migrating", conditions: []metav1.Condition{
+			condition(kaitov1beta1.InferenceSetConditionTypeReady, metav1.ConditionTrue, "inferencesetReady", 2),
+			condition(kaitov1beta1.InferenceSetConditionTypeMigration, metav1.ConditionTrue, "Migrating", 2),
+		}, replicas: 2, expected: kaitov1beta1.PhaseProgressing},
+		{name: "deleting", deleting: true, expected: kaitov1beta1.PhaseDeleting},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iObj := &kaitov1beta1.InferenceSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "is", Generation: 2},
+				Status:     kaitov1beta1.InferenceSetStatus{Conditions: tt.conditions, Replicas: tt.replicas},
+			}
+			if tt.deleting {
+				iObj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			applyRolloutStatus(iObj)
+			assert.Equal(t, tt.expected, iObj.Status.Phase)
+			assert.Equal(t, int64(2), iObj.Status.ObservedGeneration)
+			if assert.NotNil(t, iObj.Status.Health) {
+				assert.Equal(t, rollout.HealthStateForPhase(tt.expected), iObj.Status.Health.State)
+				assert.Equal(t, int64(2), iObj.Status.Health.ObservedGeneration)
+			}
+			progressing := meta.FindStatusCondition(iObj.Status.Conditions, string(kaitov1beta1.ConditionTypeProgressing))
+			assert.NotNil(t, progressing)
+			assert.Equal(t, string(tt.expected), progressing.Reason)
+			assert.Equal(t, int64(2), progressing.ObservedGeneration)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metriclabels defines the labels shared by the controller metrics, so a single
+// fleet dashboard can break every metric down by namespace, model and GPU SKU.
+package metriclabels
+
+import (
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/model"
+)
+
+const (
+	Workspace    = "workspace"
+	Namespace    = "namespace"
+	Preset       = "preset"
+	Runtime      = "runtime"
+	InstanceType = "instance_type"
+
+	// CustomPreset is the preset of workloads that run a custom pod template.
+	CustomPreset = "custom"
+	// NoInstanceType is the instance type of workloads on BYO nodes.
+	NoInstanceType = "none"
+	// Unknown is used for the labels of objects whose workspace no longer exists.
+	Unknown = "unknown"
+)
+
+// Fleet are the label names of metrics aggregated over workspaces. They leave out the
+// workspace name to keep the number of series independent of the number of workspaces.
+var Fleet = []string{Namespace, Preset, Runtime, InstanceType}
+
+// PerWorkspace are the label names of metrics reported for each workspace.
+var PerWorkspace = append([]string{Workspace}, Fleet...)
+
+// FleetValues returns the values of the Fleet labels of ws.
+func FleetValues(ws *kaitov1beta1.Workspace) []string {
+	preset, runtime := CustomPreset, string(kaitov1beta1.GetWorkspaceRuntimeName(ws))
+	switch {
+	case ws.Inference != nil && ws.Inference.Preset != nil:
+		preset = string(ws.Inference.Preset.Name)
+	case ws.Tuning != nil && ws.Tuning.Preset != nil:
+		// Fine-tuning always runs on the transformers runtime.
+		preset, runtime = string(ws.Tuning.Preset.Name), string(model.RuntimeNameHuggingfaceTransformers)
+	}
+	instanceType := ws.EffectiveInstanceType()
+	if instanceType == "" {
+		instanceType = NoInstanceType
+	}
+	return []string{ws.Namespace, preset, runtime, instanceType}
+}
+
+// PerWorkspaceValues returns the values of the PerWorkspace labels of ws.
+func PerWorkspaceValues(ws *kaitov1beta1.Workspace) []string {
+	return append([]string{ws.Name}, FleetValues(ws)...)
+}
+
+// InferenceSetFleetValues returns the values of the Fleet labels of the workspaces the
+// InferenceSet creates.
+func InferenceSetFleetValues(is *kaitov1beta1.InferenceSet) []string {
+	ws := &kaitov1beta1.Workspace{}
+	ws.Namespace = is.Namespace
+	ws.Annotations = is.Spec.Template.Annotations
+	ws.Resource.InstanceType = is.Spec.Template.Resource.InstanceType
+	ws.Inference = &is.Spec.Template.Inference
+	return FleetValues(ws)
+}
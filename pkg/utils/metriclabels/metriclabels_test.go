@@ -0,0 +1,81 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metriclabels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestFleetValues(t *testing.T) {
+	preset := &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}}
+	tests := []struct {
+		name     string
+		ws       *kaitov1beta1.Workspace
+		expected []string
+	}{
+		{
+			name: "inference preset",
+			ws: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+				Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+				Inference:  &kaitov1beta1.InferenceSpec{Preset: preset},
+			},
+			expected: []string{"default", "phi-4", "vllm", "Standard_NC24ads_A100_v4"},
+		},
+		{
+			name: "inference preset on the transformers runtime",
+			ws: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ws",
+					Namespace:   "default",
+					Annotations: map[string]string{kaitov1beta1.AnnotationWorkspaceRuntime: "transformers"},
+				},
+				Resource:  kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+				Inference: &kaitov1beta1.InferenceSpec{Preset: preset},
+			},
+			expected: []string{"default", "phi-4", "transformers", "Standard_NC24ads_A100_v4"},
+		},
+		{
+			name: "tuning preset",
+			ws: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+				Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+				Tuning:     &kaitov1beta1.TuningSpec{Preset: preset},
+			},
+			expected: []string{"default", "phi-4", "transformers", "Standard_NC24ads_A100_v4"},
+		},
+		{
+			name: "custom template on BYO nodes",
+			ws: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team"},
+				Inference:  &kaitov1beta1.InferenceSpec{Template: &corev1.PodTemplateSpec{}},
+			},
+			expected: []string{"team", CustomPreset, "vllm", NoInstanceType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FleetValues(tt.ws))
+			assert.Equal(t, append([]string{"ws"}, tt.expected...), PerWorkspaceValues(tt.ws))
+			assert.Len(t, FleetValues(tt.ws), len(Fleet))
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -41,6 +42,7 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/apis"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/nodes"
 )
@@ -49,6 +51,9 @@ var (
 	// nodeClaimStatusTimeoutInterval is the interval to check the nodeClaim status.
 	nodeClaimStatusTimeoutInterval = 240 * time.Second
 
+	// GVK is the karpenter.sh/v1 NodeClaim kind, whose CRD is absent in clusters without Karpenter.
+	GVK = schema.GroupVersionKind{Group: "karpenter.sh", Version: "v1", Kind: "NodeClaim"}
+
 	WorkspaceSelector, _ = metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
 			{Key: kaitov1beta1.LabelWorkspaceName, Operator: metav1.LabelSelectorOpExists},
@@ -122,6 +127,8 @@ func isRelevantNodeClaim(lbls map[string]string) bool {
 
 type ManifestOptions struct {
 	DefaultNodeImageFamily string
+	// Name overrides the generated NodeClaim name when set.
+	Name string
 }
 
 // GenerateNodeClaimManifest generates a nodeClaim object from the given workspace or RAGEngine.
@@ -140,7 +147,10 @@ func GenerateNodeClaimManifestWithOptions(storageRequirement string, obj client.
 		return nil
 	}
 
-	nodeClaimName := GenerateNodeClaimName(obj)
+	nodeClaimName := options.Name
+	if nodeClaimName == "" {
+		nodeClaimName = GenerateNodeClaimName(obj)
+	}
 
 	nodeClaimLabels := map[string]string{
 		consts.LabelNodePool: consts.KaitoNodePoolName, // Fake nodepool name to prevent Karpenter from scaling up.
@@ -150,6 +160,16 @@ func GenerateNodeClaimManifestWithOptions(storageRequirement string, obj client.
 	if sanitized := kaitov1beta1.SanitizedMatchLabels(labelSelector); len(sanitized) != 0 {
 		nodeClaimLabels = lo.Assign(nodeClaimLabels, sanitized)
 	}
+	// The label is propagated to the node so confidential pods can require it.
+	if ws, ok := obj.(*kaitov1beta1.Workspace); ok && ws.Resource.ConfidentialCompute != nil {
+		nodeClaimLabels[kaitov1beta1.LabelConfidentialCompute] = "true"
+	}
+	// Tiered workspaces label their NodeClaims so the GPU nodes of each tier can be listed.
+	if ws, ok := obj.(*kaitov1beta1.Workspace); ok {
+		if tier, exists := ws.Labels[kaitov1beta1.LabelInferenceTier]; exists {
+			nodeClaimLabels[kaitov1beta1.LabelInferenceTier] = tier
+		}
+	}
 
 	nodeClaimAnnotations := map[string]string{
 		karpenterv1.DoNotDisruptAnnotationKey: "true", // To prevent Karpenter from scaling down.
@@ -167,6 +187,13 @@ func GenerateNodeClaimManifestWithOptions(storageRequirement string, obj client.
 		}
 	}
 
+	// Labels and annotations the workspace propagates come last, so they never replace
+	// the ones above.
+	if ws, ok := obj.(*kaitov1beta1.Workspace); ok {
+		nodeClaimLabels = kaitov1beta1.MergePropagated(nodeClaimLabels, ws.PropagatedLabels(kaitov1beta1.MetadataPropagationTargetNodeClaim))
+		nodeClaimAnnotations = kaitov1beta1.MergePropagated(nodeClaimAnnotations, ws.PropagatedAnnotations(kaitov1beta1.MetadataPropagationTargetNodeClaim))
+	}
+
 	cloudName := os.Getenv("CLOUD_PROVIDER")
 
 	var nodeClassRefKind string
@@ -226,6 +253,25 @@ func GenerateNodeClaimManifestWithOptions(storageRequirement string, obj client.
 		},
 	}
 
+	// Pin the CPU architecture of known non-amd64 GPU SKUs, e.g. Grace-based instances, so that
+	// the provisioner resolves an arm64 node image. amd64 NodeClaims are left as they were.
+	if gpuConfig, err := sku.GetGPUConfigBySKU(instanceType); err == nil && gpuConfig.Architecture() != consts.ArchitectureAMD64 {
+		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, karpenterv1.NodeSelectorRequirementWithMinValues{
+			Key:      corev1.LabelArchStable,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{gpuConfig.Architecture()},
+		})
+	}
+
+	// Pin the availability zones a workspace asks for; the provisioner picks one with capacity.
+	if ws, ok := obj.(*kaitov1beta1.Workspace); ok && len(ws.Resource.Zones) > 0 {
+		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, karpenterv1.NodeSelectorRequirementWithMinValues{
+			Key:      corev1.LabelTopologyZone,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   ws.Resource.Zones,
+		})
+	}
+
 	if cloudName == consts.AzureCloudName {
 		nodeSelector := karpenterv1.NodeSelectorRequirementWithMinValues{
 			Key:      azurev1beta1.LabelSKUName,
@@ -244,9 +290,21 @@ func GenerateNodeClaimManifestWithOptions(storageRequirement string, obj client.
 		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, nodeSelector)
 	}
 
+	// Events expire, so the requirements are also kept on the NodeClaim itself.
+	nodeClaimObj.Annotations[kaitov1beta1.AnnotationNodeClaimRequirements] = DescribeRequirements(nodeClaimObj)
+
 	return nodeClaimObj
 }
 
+// IsOwnedBy reports whether the NodeClaim carries the workspace labels of obj.
+func IsOwnedBy(nc *karpenterv1.NodeClaim, obj client.Object) bool {
+	_, namespace, name, _, nameLabel, namespaceLabel, err := nodes.ExtractObjFields(obj)
+	if err != nil {
+		return false
+	}
+	return nc.Labels[nameLabel] == name && nc.Labels[namespaceLabel] == namespace
+}
+
 // GenerateNodeClaimName generates a nodeClaim name from the given workspace or RAGEngine.
 func GenerateNodeClaimName(obj client.Object) string {
 	// Determine the type of the input object and extract relevant fields
@@ -260,6 +318,45 @@ func GenerateNodeClaimName(obj client.Object) string {
 	return nodeClaimName
 }
 
+// IndexedNodeClaimNames returns count NodeClaim names of the form "<name>-<hash>-<index>"
+// that are not in existing, using the lowest free indexes first. NodeClaims are cluster
+// scoped, so hash is derived from the namespace to keep workspaces of the same name in
+// different namespaces apart.
+func IndexedNodeClaimNames(namespace, name string, existing []*karpenterv1.NodeClaim, count int) []string {
+	used := make(map[string]struct{}, len(existing))
+	for _, nc := range existing {
+		used[nc.Name] = struct{}{}
+	}
+	digest := sha256.Sum256([]byte(namespace))
+	prefix := name + "-" + hex.EncodeToString(digest[:])[:6]
+	names := make([]string, 0, count)
+	for i := 0; len(names) < count; i++ {
+		candidate := fmt.Sprintf("%s-%d", prefix, i)
+		if _, ok := used[candidate]; !ok {
+			names = append(names, candidate)
+		}
+	}
+	return names
+}
+
+// DescribeRequirements summarizes what a NodeClaim asks for, e.g. the instance type, zone
+// and capacity type, in a single line suitable for events.
+func DescribeRequirements(nc *karpenterv1.NodeClaim) string {
+	parts := make([]string, 0, len(nc.Spec.Requirements)+1)
+	for _, req := range nc.Spec.Requirements {
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn, corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			parts = append(parts, fmt.Sprintf("%s %s [%s]", req.Key, req.Operator, strings.Join(req.Values, ",")))
+		default:
+			parts = append(parts, fmt.Sprintf("%s %s", req.Key, req.Operator))
+		}
+	}
+	if storage, ok := nc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		parts = append(parts, "storage="+storage.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
 // CreateNodeClaim creates a nodeClaim object.
 func CreateNodeClaim(ctx context.Context, nodeClaimObj *karpenterv1.NodeClaim, kubeClient client.Client) error {
 	klog.InfoS("CreateNodeClaim", "nodeClaim", klog.KObj(nodeClaimObj))
@@ -21,6 +21,7 @@ import (
 
 	azurev1beta1 "github.com/Azure/karpenter-provider-azure/pkg/apis/v1beta1"
 	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -284,6 +285,11 @@ func TestGenerateNodeClaimManifest(t *testing.T) {
 			assert.Equal(t, nodeClaim.Labels[kaitov1beta1.LabelWorkspaceNamespace], workspace.Namespace, "label must have same workspace namespace as workspace")
 			assert.Equal(t, nodeClaim.Labels[consts.LabelNodePool], consts.KaitoNodePoolName, "label must have same labels as workspace label selector")
 			assert.Equal(t, nodeClaim.Annotations[karpenterv1.DoNotDisruptAnnotationKey], "true", "label must have do not disrupt annotation")
+			// amd64 SKUs must not gain an architecture requirement.
+			_, hasArch := lo.Find(nodeClaim.Spec.Requirements, func(r karpenterv1.NodeSelectorRequirementWithMinValues) bool {
+				return r.Key == corev1.LabelArchStable
+			})
+			assert.Check(t, !hasArch, "NodeClaim must not have an architecture requirement for an amd64 SKU")
 			assert.Equal(t, len(nodeClaim.Spec.Requirements), 4, " NodeClaim must have 4 NodeSelector Requirements")
 			assert.Equal(t, nodeClaim.Spec.Requirements[1].Values[0], workspace.Resource.InstanceType, "NodeClaim must have same instance type as workspace")
 			assert.Equal(t, nodeClaim.Spec.Requirements[2].Key, corev1.LabelOSStable, "NodeClaim must have OS label")
@@ -302,6 +308,143 @@ func TestGenerateNodeClaimManifest(t *testing.T) {
 	}
 }
 
+func TestGenerateNodeClaimManifestArm64(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	workspace.Resource.InstanceType = "Standard_ND128isr_NDR_GB200_v6"
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	archRequirement, hasArch := lo.Find(nodeClaim.Spec.Requirements, func(r karpenterv1.NodeSelectorRequirementWithMinValues) bool {
+		return r.Key == corev1.LabelArchStable
+	})
+	assert.Check(t, hasArch, "NodeClaim must have an architecture requirement")
+	assert.DeepEqual(t, archRequirement.Values, []string{consts.ArchitectureARM64})
+}
+
+func TestGenerateNodeClaimManifestZones(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	hasZone := func(r karpenterv1.NodeSelectorRequirementWithMinValues) bool {
+		return r.Key == corev1.LabelTopologyZone
+	}
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	_, found := lo.Find(nodeClaim.Spec.Requirements, hasZone)
+	assert.Check(t, !found, "NodeClaim must not pin zones unless the workspace lists them")
+
+	workspace.Resource.Zones = []string{"us-west-2a", "us-west-2b"}
+	nodeClaim = GenerateNodeClaimManifest("0", workspace)
+	zoneRequirement, found := lo.Find(nodeClaim.Spec.Requirements, hasZone)
+	assert.Check(t, found, "NodeClaim must have a zone requirement")
+	assert.Equal(t, zoneRequirement.Operator, corev1.NodeSelectorOpIn)
+	assert.DeepEqual(t, zoneRequirement.Values, []string{"us-west-2a", "us-west-2b"})
+}
+
+func TestGenerateNodeClaimManifestConfidentialCompute(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	workspace.Resource.InstanceType = "Standard_NCC40ads_H100_v5"
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	_, found := nodeClaim.Labels[kaitov1beta1.LabelConfidentialCompute]
+	assert.Check(t, !found, "NodeClaim must not be labeled unless confidential compute is requested")
+
+	workspace.Resource.ConfidentialCompute = &kaitov1beta1.ConfidentialComputeSpec{}
+	nodeClaim = GenerateNodeClaimManifest("0", workspace)
+	assert.Equal(t, nodeClaim.Labels[kaitov1beta1.LabelConfidentialCompute], "true")
+}
+
+func TestGenerateNodeClaimManifestInferenceTier(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	_, found := nodeClaim.Labels[kaitov1beta1.LabelInferenceTier]
+	assert.Check(t, !found)
+
+	workspace.Labels = map[string]string{kaitov1beta1.LabelInferenceTier: "long"}
+	nodeClaim = GenerateNodeClaimManifest("0", workspace)
+	assert.Equal(t, nodeClaim.Labels[kaitov1beta1.LabelInferenceTier], "long")
+}
+
+func TestGenerateNodeClaimManifestMetadataPropagation(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	workspace.Labels = map[string]string{"cost-center": "42", consts.LabelNodePool: "other"}
+	workspace.Annotations = map[string]string{"owner": "search"}
+	workspace.MetadataPropagation = &kaitov1beta1.MetadataPropagationSpec{
+		Labels:      []string{"cost-center", consts.LabelNodePool},
+		Annotations: []string{"owner"},
+	}
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	assert.Equal(t, nodeClaim.Labels["cost-center"], "42")
+	assert.Equal(t, nodeClaim.Labels[consts.LabelNodePool], consts.KaitoNodePoolName)
+	assert.Equal(t, nodeClaim.Annotations["owner"], "search")
+
+	workspace.MetadataPropagation.Targets = []kaitov1beta1.MetadataPropagationTarget{kaitov1beta1.MetadataPropagationTargetPod}
+	nodeClaim = GenerateNodeClaimManifest("0", workspace)
+	_, found := nodeClaim.Labels["cost-center"]
+	assert.Check(t, !found)
+}
+
+func TestGenerateNodeClaimManifestName(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	assert.Check(t, strings.HasPrefix(nodeClaim.Name, "ws"))
+
+	nodeClaim = GenerateNodeClaimManifestWithOptions("0", workspace, ManifestOptions{Name: "testWorkspace-0"})
+	assert.Equal(t, nodeClaim.Name, "testWorkspace-0")
+}
+
+func TestIndexedNodeClaimNames(t *testing.T) {
+	prefix := IndexedNodeClaimNames("default", "phi-4", nil, 1)[0]
+	prefix = strings.TrimSuffix(prefix, "-0")
+	assert.Check(t, strings.HasPrefix(prefix, "phi-4-"))
+	assert.Equal(t, len(prefix), len("phi-4-")+6)
+
+	existing := []*karpenterv1.NodeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: prefix + "-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: prefix + "-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ws1a2b3c4d5"}},
+	}
+	assert.DeepEqual(t, IndexedNodeClaimNames("default", "phi-4", existing, 3), []string{prefix + "-1", prefix + "-3", prefix + "-4"})
+
+	// The same workspace name in another namespace gets different NodeClaim names.
+	other := IndexedNodeClaimNames("team-b", "phi-4", nil, 1)[0]
+	assert.Check(t, other != prefix+"-0")
+}
+
+func TestIsOwnedBy(t *testing.T) {
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	nodeClaim := GenerateNodeClaimManifest("0", workspace)
+	assert.Check(t, IsOwnedBy(nodeClaim, workspace))
+
+	other := workspace.DeepCopy()
+	other.Namespace = "team-b"
+	assert.Check(t, !IsOwnedBy(nodeClaim, other))
+}
+
+func TestDescribeRequirements(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+	workspace := test.MockWorkspaceWithPreset.DeepCopy()
+	nodeClaim := GenerateNodeClaimManifest("300Gi", workspace)
+	assert.Equal(t, nodeClaim.Annotations[kaitov1beta1.AnnotationNodeClaimRequirements], DescribeRequirements(nodeClaim))
+
+	nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements,
+		karpenterv1.NodeSelectorRequirementWithMinValues{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"us-west-2a", "us-west-2b"}},
+		karpenterv1.NodeSelectorRequirementWithMinValues{Key: karpenterv1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpExists},
+	)
+
+	description := DescribeRequirements(nodeClaim)
+	assert.Check(t, strings.Contains(description, "node.kubernetes.io/instance-type In ["+workspace.Resource.InstanceType+"]"), description)
+	assert.Check(t, strings.Contains(description, "topology.kubernetes.io/zone In [us-west-2a,us-west-2b]"), description)
+	assert.Check(t, strings.Contains(description, "karpenter.sh/capacity-type Exists"), description)
+	assert.Check(t, strings.HasSuffix(description, "; storage=300Gi"), description)
+}
+
 func TestFirstProvisioningError(t *testing.T) {
 	nc := func(conds ...status.Condition) *karpenterv1.NodeClaim {
 		return &karpenterv1.NodeClaim{Status: karpenterv1.NodeClaimStatus{Conditions: conds}}
@@ -101,7 +101,7 @@ func ExtractObjFields(obj client.Object) (instanceType, namespace, name string,
 	nameLabel, namespaceLabel string, err error) {
 	switch o := obj.(type) {
 	case *kaitov1beta1.Workspace:
-		instanceType = o.Resource.InstanceType
+		instanceType = o.EffectiveInstanceType()
 		namespace = o.Namespace
 		name = o.Name
 		labelSelector = o.Resource.LabelSelector
@@ -109,7 +109,7 @@ func ExtractObjFields(obj client.Object) (instanceType, namespace, name string,
 		namespaceLabel = kaitov1beta1.LabelWorkspaceNamespace
 	case *kaitov1beta1.RAGEngine:
 		if o.Spec.Compute != nil {
-			instanceType = o.Spec.Compute.InstanceType
+			instanceType = o.InstanceType()
 			labelSelector = o.Spec.Compute.LabelSelector
 		}
 		namespace = o.Namespace
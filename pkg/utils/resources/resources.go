@@ -68,6 +68,46 @@ func GetResource(ctx context.Context, name, namespace string, kubeClient client.
 	return err
 }
 
+// AdoptResource makes the controller of desired the controller of existing and copies
+// the labels of desired onto existing. A controller reference to an earlier object of the
+// same kind and name, e.g. a deleted and re-created workspace, is replaced. It reports
+// whether existing changed and fails if existing is controlled by a different object.
+func AdoptResource(existing, desired client.Object) (bool, error) {
+	changed := false
+	if want := metav1.GetControllerOf(desired); want != nil {
+		current := metav1.GetControllerOf(existing)
+		switch {
+		case current == nil:
+			existing.SetOwnerReferences(append(existing.GetOwnerReferences(), *want))
+			changed = true
+		case current.UID == want.UID:
+		case current.Kind == want.Kind && current.Name == want.Name:
+			refs := make([]metav1.OwnerReference, 0, len(existing.GetOwnerReferences()))
+			for _, ref := range existing.GetOwnerReferences() {
+				if ref.UID != current.UID {
+					refs = append(refs, ref)
+				}
+			}
+			existing.SetOwnerReferences(append(refs, *want))
+			changed = true
+		default:
+			return false, fmt.Errorf("%s is already controlled by %s %s", client.ObjectKeyFromObject(existing), current.Kind, current.Name)
+		}
+	}
+	labels := existing.GetLabels()
+	for k, v := range desired.GetLabels() {
+		if cur, ok := labels[k]; !ok || cur != v {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[k] = v
+			changed = true
+		}
+	}
+	existing.SetLabels(labels)
+	return changed, nil
+}
+
 func CheckResourceStatus(obj client.Object, kubeClient client.Client, timeoutDuration time.Duration) error {
 	// Use Context for timeout
 	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -570,3 +571,54 @@ func TestEnsureInferenceConfigMapForceRefresh(t *testing.T) {
 		})
 	}
 }
+
+func TestAdoptResource(t *testing.T) {
+	controller := true
+	ref := func(kind, name, uid string) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "kaito.sh/v1beta1", Kind: kind, Name: name, UID: types.UID(uid), Controller: &controller}
+	}
+	desired := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:            "ws",
+		Labels:          map[string]string{"kaito.sh/workspace": "ws"},
+		OwnerReferences: []metav1.OwnerReference{ref("Workspace", "ws", "new-uid")},
+	}}
+
+	t.Run("unowned object is adopted", func(t *testing.T) {
+		existing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ws", Labels: map[string]string{"app": "x"}}}
+		changed, err := AdoptResource(existing, desired)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, types.UID("new-uid"), metav1.GetControllerOf(existing).UID)
+		assert.Equal(t, map[string]string{"app": "x", "kaito.sh/workspace": "ws"}, existing.Labels)
+	})
+
+	t.Run("stale controller of the same name is replaced", func(t *testing.T) {
+		existing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:            "ws",
+			Labels:          map[string]string{"kaito.sh/workspace": "ws"},
+			OwnerReferences: []metav1.OwnerReference{ref("Workspace", "ws", "old-uid")},
+		}}
+		changed, err := AdoptResource(existing, desired)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Len(t, existing.OwnerReferences, 1)
+		assert.Equal(t, types.UID("new-uid"), existing.OwnerReferences[0].UID)
+	})
+
+	t.Run("owned object is unchanged", func(t *testing.T) {
+		existing := desired.DeepCopy()
+		changed, err := AdoptResource(existing, desired)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("object controlled by another owner is refused", func(t *testing.T) {
+		existing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:            "ws",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{ref("Other", "other", "other-uid")},
+		}}
+		_, err := AdoptResource(existing, desired)
+		assert.ErrorContains(t, err, "already controlled by Other other")
+	})
+}
@@ -0,0 +1,13 @@
+hs = {}
+local health = nil
+if obj.status ~= nil then
+  health = obj.status.health
+end
+if health == nil or health.observedGeneration ~= obj.metadata.generation then
+  hs.status = "Progressing"
+  hs.message = "Waiting for the controller to observe the latest spec"
+  return hs
+end
+hs.status = health.state
+hs.message = health.message
+return hs
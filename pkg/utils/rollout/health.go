@@ -0,0 +1,58 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	_ "embed"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+var (
+	// ArgoCDHealthCheck is the Argo CD custom health check for KAITO objects. It reads
+	// status.health and reports Progressing until the controller observed the current
+	// generation.
+	//go:embed argocd_health.lua
+	ArgoCDHealthCheck string
+
+	// FluxHealthCheckExprs are the Flux Kustomization healthCheckExprs for KAITO objects,
+	// written against the same status.health contract.
+	//go:embed flux_health_check_exprs.yaml
+	FluxHealthCheckExprs string
+)
+
+// HealthStateForPhase maps a phase to the health state reported in status.health.
+func HealthStateForPhase(phase kaitov1beta1.Phase) kaitov1beta1.HealthState {
+	switch phase {
+	case kaitov1beta1.PhaseReady, kaitov1beta1.PhaseSucceeded:
+		return kaitov1beta1.HealthStateHealthy
+	case kaitov1beta1.PhaseFailed:
+		return kaitov1beta1.HealthStateDegraded
+	default:
+		return kaitov1beta1.HealthStateProgressing
+	}
+}
+
+// Health returns the status.health of an object in phase for generation. The message
+// falls back to the phase so that GitOps tools always have something to display.
+func Health(generation int64, phase kaitov1beta1.Phase, message string) *kaitov1beta1.HealthStatus {
+	if message == "" {
+		message = string(phase)
+	}
+	return &kaitov1beta1.HealthStatus{
+		State:              HealthStateForPhase(phase),
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// healthByPhase is the documented status.health contract. A phase added to the API
+// must be added here, or TestHealthContractMatchesCRDs fails.
+var healthByPhase = map[kaitov1beta1.Phase]kaitov1beta1.HealthState{
+	kaitov1beta1.PhasePending:     kaitov1beta1.HealthStateProgressing,
+	kaitov1beta1.PhaseProgressing: kaitov1beta1.HealthStateProgressing,
+	kaitov1beta1.PhaseReady:       kaitov1beta1.HealthStateHealthy,
+	kaitov1beta1.PhaseSucceeded:   kaitov1beta1.HealthStateHealthy,
+	kaitov1beta1.PhaseFailed:      kaitov1beta1.HealthStateDegraded,
+	kaitov1beta1.PhaseDeleting:    kaitov1beta1.HealthStateProgressing,
+}
+
+var healthStates = []kaitov1beta1.HealthState{
+	kaitov1beta1.HealthStateHealthy,
+	kaitov1beta1.HealthStateProgressing,
+	kaitov1beta1.HealthStateDegraded,
+}
+
+func TestHealth(t *testing.T) {
+	for phase, expected := range healthByPhase {
+		t.Run(string(phase), func(t *testing.T) {
+			health := Health(3, phase, "message")
+			assert.Equal(t, expected, health.State)
+			assert.Equal(t, "message", health.Message)
+			assert.Equal(t, int64(3), health.ObservedGeneration)
+
+			// The health and the Progressing condition must never disagree.
+			var conditions []metav1.Condition
+			SetProgressing(&conditions, 3, phase, "message")
+			progressing := meta.IsStatusConditionTrue(conditions, string(kaitov1beta1.ConditionTypeProgressing))
+			assert.Equal(t, progressing, health.State == kaitov1beta1.HealthStateProgressing)
+		})
+	}
+	assert.Equal(t, string(kaitov1beta1.PhasePending), Health(1, kaitov1beta1.PhasePending, "").Message)
+}
+
+func TestHealthContractMatchesCRDs(t *testing.T) {
+	for _, file := range []string{"kaito.sh_workspaces.yaml", "kaito.sh_inferencesets.yaml", "kaito.sh_ragengines.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("..", "..", "..", "config", "crd", "bases", file))
+			require.NoError(t, err)
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			require.NoError(t, yaml.Unmarshal(data, crd))
+
+			for _, version := range crd.Spec.Versions {
+				if version.Name != kaitov1beta1.GroupVersion.Version {
+					continue
+				}
+				status := version.Schema.OpenAPIV3Schema.Properties["status"]
+
+				var phases []string
+				for phase := range healthByPhase {
+					phases = append(phases, string(phase))
+				}
+				assert.ElementsMatch(t, phases, enumValues(t, status.Properties["phase"]))
+
+				health, ok := status.Properties["health"]
+				require.True(t, ok, "status.health is missing")
+				assert.Equal(t, []string{"state"}, health.Required)
+				var states []string
+				for _, state := range healthStates {
+					states = append(states, string(state))
+				}
+				assert.ElementsMatch(t, states, enumValues(t, health.Properties["state"]))
+				assert.Contains(t, health.Properties, "message")
+				assert.Contains(t, health.Properties, "observedGeneration")
+			}
+		})
+	}
+}
+
+func TestFluxHealthCheckExprs(t *testing.T) {
+	var exprs struct {
+		HealthCheckExprs []struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			InProgress string `json:"inProgress"`
+			Failed     string `json:"failed"`
+			Current    string `json:"current"`
+		} `json:"healthCheckExprs"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(FluxHealthCheckExprs), &exprs))
+
+	var kinds []string
+	for _, e := range exprs.HealthCheckExprs {
+		kinds = append(kinds, e.Kind)
+		assert.Equal(t, kaitov1beta1.GroupVersion.String(), e.APIVersion)
+		assert.Contains(t, e.InProgress, "status.health.observedGeneration != metadata.generation")
+		assert.Contains(t, e.InProgress, "'"+string(kaitov1beta1.HealthStateProgressing)+"'")
+		assert.Contains(t, e.Failed, "'"+string(kaitov1beta1.HealthStateDegraded)+"'")
+		assert.Contains(t, e.Current, "'"+string(kaitov1beta1.HealthStateHealthy)+"'")
+	}
+	sort.Strings(kinds)
+	assert.Equal(t, []string{"InferenceSet", "RAGEngine", "Workspace"}, kinds)
+}
+
+func TestArgoCDHealthCheck(t *testing.T) {
+	assert.Contains(t, ArgoCDHealthCheck, "health.observedGeneration ~= obj.metadata.generation")
+	assert.Contains(t, ArgoCDHealthCheck, "hs.status = health.state")
+	assert.Contains(t, ArgoCDHealthCheck, `hs.status = "`+string(kaitov1beta1.HealthStateProgressing)+`"`)
+}
+
+// TestGitOpsDocs keeps the health checks published in the documentation identical to
+// the ones in this package.
+func TestGitOpsDocs(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "website", "docs", "workspace.md"))
+	require.NoError(t, err)
+	docs := string(data)
+	assert.Contains(t, docs, "```lua\n"+ArgoCDHealthCheck+"```")
+	assert.Contains(t, docs, "```yaml\n"+FluxHealthCheckExprs+"```")
+	for phase, state := range healthByPhase {
+		assert.Contains(t, docs, "| `"+string(phase)+"` | `"+string(state)+"` |")
+	}
+}
+
+func enumValues(t *testing.T, schema apiextensionsv1.JSONSchemaProps) []string {
+	t.Helper()
+	var values []string
+	for _, v := range schema.Enum {
+		values = append(values, strings.Trim(string(v.Raw), `"`))
+	}
+	return values
+}
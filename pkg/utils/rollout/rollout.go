@@ -0,0 +1,52 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollout derives the GitOps facing part of a KAITO status: the phase, the
+// observed generation and the Progressing condition.
+package rollout
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// CurrentCondition returns the condition of type cType if it was set for generation or a
+// newer one. Conditions left over from an older generation describe a spec that is being
+// replaced, so they must not be reported as the outcome of the current one.
+func CurrentCondition(conditions []metav1.Condition, cType kaitov1beta1.ConditionType, generation int64) *metav1.Condition {
+	cond := meta.FindStatusCondition(conditions, string(cType))
+	if cond == nil || cond.ObservedGeneration < generation {
+		return nil
+	}
+	return cond
+}
+
+// SetProgressing sets the Progressing condition for phase and generation. The condition
+// is True while the object is pending, progressing or being deleted, and False once it is
+// ready, has succeeded or has failed. The phase is the reason.
+func SetProgressing(conditions *[]metav1.Condition, generation int64, phase kaitov1beta1.Phase, message string) {
+	status := metav1.ConditionTrue
+	switch phase {
+	case kaitov1beta1.PhaseReady, kaitov1beta1.PhaseSucceeded, kaitov1beta1.PhaseFailed:
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.ConditionTypeProgressing),
+		Status:             status,
+		Reason:             string(phase),
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestCurrentCondition(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: string(kaitov1beta1.ConditionTypeResourceStatus), Status: metav1.ConditionTrue, ObservedGeneration: 2},
+		{Type: string(kaitov1beta1.WorkspaceConditionTypeInferenceStatus), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+	}
+	assert.NotNil(t, CurrentCondition(conditions, kaitov1beta1.ConditionTypeResourceStatus, 2))
+	assert.Nil(t, CurrentCondition(conditions, kaitov1beta1.WorkspaceConditionTypeInferenceStatus, 2))
+	assert.Nil(t, CurrentCondition(conditions, kaitov1beta1.WorkspaceConditionTypeSucceeded, 2))
+}
+
+func TestSetProgressing(t *testing.T) {
+	tests := map[kaitov1beta1.Phase]metav1.ConditionStatus{
+		kaitov1beta1.PhasePending:     metav1.ConditionTrue,
+		kaitov1beta1.PhaseProgressing: metav1.ConditionTrue,
+		kaitov1beta1.PhaseDeleting:    metav1.ConditionTrue,
+		kaitov1beta1.PhaseReady:       metav1.ConditionFalse,
+		kaitov1beta1.PhaseSucceeded:   metav1.ConditionFalse,
+		kaitov1beta1.PhaseFailed:      metav1.ConditionFalse,
+	}
+	for phase, expected := range tests {
+		t.Run(string(phase), func(t *testing.T) {
+			var conditions []metav1.Condition
+			SetProgressing(&conditions, 4, phase, "message")
+			cond := meta.FindStatusCondition(conditions, string(kaitov1beta1.ConditionTypeProgressing))
+			if assert.NotNil(t, cond) {
+				assert.Equal(t, expected, cond.Status)
+				assert.Equal(t, string(phase), cond.Reason)
+				assert.Equal(t, "message", cond.Message)
+				assert.Equal(t, int64(4), cond.ObservedGeneration)
+			}
+		})
+	}
+}
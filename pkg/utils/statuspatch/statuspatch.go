@@ -0,0 +1,110 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statuspatch writes the status subresource of KAITO objects with JSON merge
+// patches. A patch carries only the fields that changed and the resourceVersion it was
+// computed from, so it is rejected rather than applied over a concurrent write, and a
+// status that did not change is not written at all.
+package statuspatch
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Results of a status write, reported in the result label of kaito_status_patch_total.
+const (
+	ResultPatched   = "patched"
+	ResultUnchanged = "unchanged"
+	ResultConflict  = "conflict"
+	ResultError     = "error"
+)
+
+var statusPatches = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kaito_status_patch_total",
+		Help: "Number of status writes by object kind and result (patched, unchanged, conflict, error)",
+	},
+	[]string{"kind", "result"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(statusPatches)
+}
+
+// Patch writes the status of obj with its difference to original, which must be the
+// object as it was read. It does not send a request if the status is unchanged, and
+// returns the conflict error if the object was written since it was read.
+func Patch(ctx context.Context, c client.Client, obj, original client.Object) error {
+	kind := kindOf(obj)
+	data, err := client.MergeFrom(original).Data(obj)
+	if err != nil {
+		statusPatches.WithLabelValues(kind, ResultError).Inc()
+		return err
+	}
+	if string(data) == "{}" {
+		statusPatches.WithLabelValues(kind, ResultUnchanged).Inc()
+		return nil
+	}
+
+	err = c.Status().Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	switch {
+	case err == nil:
+		statusPatches.WithLabelValues(kind, ResultPatched).Inc()
+	case apierrors.IsConflict(err):
+		statusPatches.WithLabelValues(kind, ResultConflict).Inc()
+	default:
+		statusPatches.WithLabelValues(kind, ResultError).Inc()
+	}
+	return err
+}
+
+// Update reads the object named key into obj, applies mutate and patches the status with
+// the difference. On a conflict or a transient API server error it reads the object again
+// and reapplies mutate, so mutate must derive the status from the object it is given. An
+// object that no longer exists is not an error.
+func Update[T client.Object](ctx context.Context, c client.Client, key client.ObjectKey, obj T, mutate func(T) error) error {
+	return retry.OnError(retry.DefaultRetry, isRetriable, func() error {
+		// Start every attempt from an empty object, since decoding into obj keeps the
+		// fields that the latest version no longer has.
+		reflect.ValueOf(obj).Elem().SetZero()
+		if err := c.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		original := obj.DeepCopyObject().(client.Object)
+		if err := mutate(obj); err != nil {
+			return err
+		}
+		return Patch(ctx, c, obj, original)
+	})
+}
+
+func isRetriable(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+}
+
+// kindOf returns the Go type name of obj, which is the kind for all KAITO types. It does
+// not need the scheme, so it works with any client.
+func kindOf(obj client.Object) string {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
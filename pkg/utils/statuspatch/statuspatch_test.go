@@ -0,0 +1,130 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuspatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newClient(t *testing.T, funcs interceptor.Funcs) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+	ws := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+	return fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ws).
+		WithStatusSubresource(&kaitov1beta1.Workspace{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func count(result string) float64 {
+	return testutil.ToFloat64(statusPatches.WithLabelValues("Workspace", result))
+}
+
+func TestPatch(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, interceptor.Funcs{})
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+
+	ws := &kaitov1beta1.Workspace{}
+	require.NoError(t, c.Get(ctx, key, ws))
+	original := ws.DeepCopy()
+
+	unchanged := count(ResultUnchanged)
+	require.NoError(t, Patch(ctx, c, ws, original))
+	assert.Equal(t, unchanged+1, count(ResultUnchanged))
+
+	patched := count(ResultPatched)
+	ws.Status.State = kaitov1beta1.WorkspaceStateReady
+	require.NoError(t, Patch(ctx, c, ws, original))
+	assert.Equal(t, patched+1, count(ResultPatched))
+
+	// original is now stale: a second patch computed from it must not be applied.
+	conflicts := count(ResultConflict)
+	stale := original.DeepCopy()
+	stale.Status.WorkerNodes = []string{"node"}
+	err := Patch(ctx, c, stale, original)
+	assert.True(t, apierrors.IsConflict(err), "expected a conflict, got %v", err)
+	assert.Equal(t, conflicts+1, count(ResultConflict))
+
+	latest := &kaitov1beta1.Workspace{}
+	require.NoError(t, c.Get(ctx, key, latest))
+	assert.Equal(t, kaitov1beta1.WorkspaceStateReady, latest.Status.State)
+	assert.Empty(t, latest.Status.WorkerNodes)
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+
+	t.Run("retries on conflict with the latest object", func(t *testing.T) {
+		conflicts := 1
+		c := newClient(t, interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return apierrors.NewConflict(schema.GroupResource{Group: "kaito.sh", Resource: "workspaces"}, obj.GetName(), nil)
+				}
+				return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		calls := 0
+		err := Update(ctx, c, key, &kaitov1beta1.Workspace{}, func(ws *kaitov1beta1.Workspace) error {
+			calls++
+			ws.Status.WorkerNodes = append(ws.Status.WorkerNodes, "node")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		latest := &kaitov1beta1.Workspace{}
+		require.NoError(t, c.Get(ctx, key, latest))
+		assert.Equal(t, []string{"node"}, latest.Status.WorkerNodes)
+	})
+
+	t.Run("unchanged status is not written", func(t *testing.T) {
+		c := newClient(t, interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				t.Fatal("unexpected status patch")
+				return nil
+			},
+		})
+		require.NoError(t, Update(ctx, c, key, &kaitov1beta1.Workspace{}, func(*kaitov1beta1.Workspace) error { return nil }))
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		c := newClient(t, interceptor.Funcs{})
+		missing := client.ObjectKey{Namespace: "default", Name: "missing"}
+		assert.NoError(t, Update(ctx, c, missing, &kaitov1beta1.Workspace{}, func(*kaitov1beta1.Workspace) error {
+			t.Fatal("mutate called for a missing object")
+			return nil
+		}))
+	})
+}
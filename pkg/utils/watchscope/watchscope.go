@@ -0,0 +1,127 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchscope restricts the namespaces a KAITO manager watches, so that a cluster
+// can run one KAITO instance per tenant without an instance seeing the objects of the
+// other tenants. Cluster scoped objects such as nodes and NodeClaims are always watched.
+package watchscope
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+// ParseNamespaces parses the comma separated value of --watch-namespaces.
+func ParseNamespaces(value string) ([]string, error) {
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(msgs, ", "))
+		}
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces, nil
+}
+
+// Resolve returns the namespaces a manager watches: namespaces, the namespaces whose
+// labels match selector, and the release namespace, which holds the configuration of
+// the manager. It returns nil, meaning all namespaces, when neither namespaces nor
+// selector is set. The selector is evaluated once, so namespaces labeled later are
+// only watched after the manager restarts.
+func Resolve(ctx context.Context, reader client.Reader, namespaces []string, selector, releaseNamespace string) ([]string, error) {
+	if len(namespaces) == 0 && selector == "" {
+		return nil, nil
+	}
+	scope := slices.Clone(namespaces)
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector %q: %w", selector, err)
+		}
+		if sel.Empty() {
+			return nil, fmt.Errorf("namespace selector %q selects every namespace", selector)
+		}
+		list := &corev1.NamespaceList{}
+		if err := reader.List(ctx, list, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces matching %q: %w", selector, err)
+		}
+		for _, ns := range list.Items {
+			scope = append(scope, ns.Name)
+		}
+	}
+	if releaseNamespace != "" {
+		scope = append(scope, releaseNamespace)
+	}
+	slices.Sort(scope)
+	return slices.Compact(scope), nil
+}
+
+// DefaultNamespaces returns the cache.Options DefaultNamespaces that restrict a manager
+// cache to namespaces. nil watches all namespaces.
+func DefaultNamespaces(namespaces []string) map[string]cache.Config {
+	if len(namespaces) == 0 {
+		return nil
+	}
+	config := make(map[string]cache.Config, len(namespaces))
+	for _, ns := range namespaces {
+		config[ns] = cache.Config{}
+	}
+	return config
+}
+
+// ManagerNamespaces resolves the --watch-namespaces and --watch-namespace-selector flags of a
+// manager into its cache DefaultNamespaces. The selector is evaluated with a client that
+// bypasses the cache, because the manager does not exist yet.
+func ManagerNamespaces(ctx context.Context, cfg *rest.Config, scheme *runtime.Scheme, namespacesFlag, selector string) (map[string]cache.Config, error) {
+	namespaces, err := ParseNamespaces(namespacesFlag)
+	if err != nil {
+		return nil, err
+	}
+	selector = strings.TrimSpace(selector)
+	if len(namespaces) == 0 && selector == "" {
+		return nil, nil
+	}
+	releaseNamespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		return nil, err
+	}
+	var reader client.Reader
+	if selector != "" {
+		if reader, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+			return nil, err
+		}
+	}
+	scope, err := Resolve(ctx, reader, namespaces, selector, releaseNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return DefaultNamespaces(scope), nil
+}
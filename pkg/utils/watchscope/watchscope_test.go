@@ -0,0 +1,87 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchscope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	namespaces, err := ParseNamespaces(" team-a,team-b,,team-a ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+
+	namespaces, err = ParseNamespaces("")
+	require.NoError(t, err)
+	assert.Empty(t, namespaces)
+
+	_, err = ParseNamespaces("team-a,Team_B")
+	assert.ErrorContains(t, err, "Team_B")
+}
+
+func TestResolve(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	reader := ctrlclientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		namespace("finance-dev", map[string]string{"business-unit": "finance"}),
+		namespace("finance-prod", map[string]string{"business-unit": "finance"}),
+		namespace("retail", map[string]string{"business-unit": "retail"}),
+	).Build()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		selector   string
+		want       []string
+		wantErr    string
+	}{
+		{name: "unrestricted"},
+		{name: "namespaces", namespaces: []string{"team-a"}, want: []string{"kaito-system", "team-a"}},
+		{
+			name:       "selector and namespaces",
+			namespaces: []string{"retail", "finance-dev"},
+			selector:   "business-unit=finance",
+			want:       []string{"finance-dev", "finance-prod", "kaito-system", "retail"},
+		},
+		{name: "selector without matches", selector: "business-unit=hr", want: []string{"kaito-system"}},
+		{name: "invalid selector", selector: "business-unit in (finance", wantErr: "invalid namespace selector"},
+		{name: "empty selector", selector: " ", wantErr: "selects every namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(ctx, reader, tt.namespaces, tt.selector, "kaito-system")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Nil(t, DefaultNamespaces(nil))
+	assert.Len(t, DefaultNamespaces([]string{"kaito-system", "team-a"}), 2)
+}
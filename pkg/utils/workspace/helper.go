@@ -21,8 +21,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
-	"github.com/kaito-project/kaito/pkg/featuregates"
-	"github.com/kaito-project/kaito/pkg/utils/consts"
 	estimatorpkg "github.com/kaito-project/kaito/pkg/workspace/estimator"
 	"github.com/kaito-project/kaito/presets/workspace/models"
 )
@@ -35,9 +33,9 @@ func NodeEstimateRequestFromWorkspace(ctx context.Context, w *kaitov1beta1.Works
 	req := estimatorpkg.NodeEstimateRequest{
 		WorkspaceName: w.Name,
 		ResourceProfile: estimatorpkg.ResourceProfile{
-			InstanceType:                w.Resource.InstanceType,
+			InstanceType:                w.EffectiveInstanceType(),
 			LabelSelector:               w.Resource.LabelSelector,
-			DisableNodeAutoProvisioning: featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning],
+			DisableNodeAutoProvisioning: IsNodeAutoProvisioningDisabled(&w.Resource),
 		},
 	}
 	//nolint:staticcheck //SA1019: deprecate Resource.Count field
@@ -48,6 +46,9 @@ func NodeEstimateRequestFromWorkspace(ctx context.Context, w *kaitov1beta1.Works
 	if w.Resource.Partition != nil && w.Resource.Partition.Mode == kaitov1beta1.PartitionModeMIG {
 		req.ResourceProfile.MIGProfile = w.Resource.Partition.Profile
 	}
+	if w.Inference != nil && w.Inference.StructuredOutputs != nil {
+		req.RuntimeProfile.StructuredOutputsBackend = string(w.Inference.StructuredOutputs.Backend)
+	}
 	if w.Inference != nil && w.Inference.Preset != nil {
 		name := string(w.Inference.Preset.Name)
 		token := ""
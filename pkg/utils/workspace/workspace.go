@@ -16,9 +16,9 @@ package workspace
 import (
 	"context"
 	"reflect"
+	"slices"
 
 	appsv1 "k8s.io/api/apps/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
@@ -27,7 +27,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
 )
 
 const WorkspaceNameSuffixLength = 12
@@ -88,28 +90,22 @@ var (
 	}
 )
 
+// IsNodeAutoProvisioningDisabled reports whether nodes must not be provisioned for the
+// workload, either because node auto-provisioning is disabled controller-wide with the
+// disableNodeAutoProvisioning feature gate or because the workspace opted out through
+// resource.provisioningPolicy.
+func IsNodeAutoProvisioningDisabled(r *kaitov1beta1.ResourceSpec) bool {
+	return featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] || r.OptsOutOfProvisioning()
+}
+
 // UpdateWorkspaceStatus updates the workspace status with the provided condition
 func UpdateWorkspaceStatus(ctx context.Context, c client.Client, name *client.ObjectKey, modifyFn func(*kaitov1beta1.WorkspaceStatus) error) error {
-	return retry.OnError(retry.DefaultRetry,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsConflict(err)
-		},
-		func() error {
-			// Read the latest version to avoid update conflict.
-			wObj := &kaitov1beta1.Workspace{}
-			if err := c.Get(ctx, *name, wObj); err != nil {
-				if !apierrors.IsNotFound(err) {
-					return err
-				}
-				return nil
-			}
-			if modifyFn != nil {
-				if err := modifyFn(&wObj.Status); err != nil {
-					return err
-				}
-			}
-			return c.Status().Update(ctx, wObj)
-		})
+	return statuspatch.Update(ctx, c, *name, &kaitov1beta1.Workspace{}, func(wObj *kaitov1beta1.Workspace) error {
+		if modifyFn != nil {
+			return modifyFn(&wObj.Status)
+		}
+		return nil
+	})
 }
 
 // UpdateWorkspaceWithRetry gets the latest workspace object, applies the modify function, and retries on conflict
@@ -126,6 +122,36 @@ func UpdateWorkspaceWithRetry(ctx context.Context, c client.Client, wObj *kaitov
 	})
 }
 
+// SetPendingOperation records op in status.pendingOperations of wObj, replacing the
+// operation of the same type. The status is not written when op is already recorded.
+func SetPendingOperation(ctx context.Context, c client.Client, wObj *kaitov1beta1.Workspace, op kaitov1beta1.PendingOperation) error {
+	for _, existing := range wObj.Status.PendingOperations {
+		if existing.Type == op.Type && existing.Message == op.Message && existing.NextWindow.Equal(op.NextWindow) {
+			return nil
+		}
+	}
+	return UpdateWorkspaceStatus(ctx, c, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
+		status.PendingOperations = slices.DeleteFunc(status.PendingOperations, func(o kaitov1beta1.PendingOperation) bool {
+			return o.Type == op.Type
+		})
+		status.PendingOperations = append(status.PendingOperations, op)
+		return nil
+	})
+}
+
+// RemovePendingOperation removes the operation of type opType from status.pendingOperations
+// of wObj. The status is not written when no such operation is recorded.
+func RemovePendingOperation(ctx context.Context, c client.Client, wObj *kaitov1beta1.Workspace, opType kaitov1beta1.PendingOperationType) error {
+	isType := func(o kaitov1beta1.PendingOperation) bool { return o.Type == opType }
+	if !slices.ContainsFunc(wObj.Status.PendingOperations, isType) {
+		return nil
+	}
+	return UpdateWorkspaceStatus(ctx, c, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
+		status.PendingOperations = slices.DeleteFunc(status.PendingOperations, isType)
+		return nil
+	})
+}
+
 // GetInferenceContainerImage returns the image of the inference container in the StatefulSet's pod template.
 // The inference container is identified by having the same name as the StatefulSet itself.
 // Falls back to the first container if no name match is found.
@@ -29,6 +29,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -63,8 +65,8 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			*ws = *workspace
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
 			ws := args.Get(1).(*kaitov1beta1.Workspace)
 			// Verify the condition was set
@@ -163,17 +165,14 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			*ws = *workspace
 		}).Return(nil)
 
-		// Mock the Status().Update call
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
-			mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
-
 		ctx := context.Background()
 		key := &client.ObjectKey{Name: "test-workspace", Namespace: "default"}
 		err := UpdateWorkspaceStatus(ctx, mockClient, key, nil)
 
+		// The status is unchanged, so it is not written.
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
-		mockClient.StatusMock.AssertExpectations(t)
+		mockClient.StatusMock.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("Should retry on retryable errors", func(t *testing.T) {
@@ -204,12 +203,12 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			*ws = *workspace
 		}).Return(nil)
 
-		// Mock the Status().Update call to fail first with a retryable error, then succeed
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call to fail first with a retryable error, then succeed
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(
 			apierrors.NewConflict(schema.GroupResource{Group: "kaito.sh", Resource: "workspaces"}, "test-workspace", fmt.Errorf("conflict"))).Once()
 
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil).Once()
 
 		ctx := context.Background()
@@ -252,8 +251,8 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			*ws = *workspace
 		}).Return(nil)
 
-		// Mock the Status().Update call to fail with a non-retryable error
-		mockClient.StatusMock.On("Update", mock.IsType(context.Background()),
+		// Mock the Status().Patch call to fail with a non-retryable error
+		mockClient.StatusMock.On("Patch", mock.IsType(context.Background()),
 			mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(fmt.Errorf("permanent error"))
 
 		ctx := context.Background()
@@ -351,3 +350,27 @@ func TestGetInferenceContainerImage(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNodeAutoProvisioningDisabled(t *testing.T) {
+	tests := []struct {
+		name                        string
+		disableNodeAutoProvisioning bool
+		policy                      kaitov1beta1.ProvisioningPolicy
+		expect                      bool
+	}{
+		{name: "auto provisioning", expect: false},
+		{name: "explicit Auto policy", policy: kaitov1beta1.ProvisioningPolicyAuto, expect: false},
+		{name: "PreferredOnly policy", policy: kaitov1beta1.ProvisioningPolicyPreferredOnly, expect: true},
+		{name: "Never policy", policy: kaitov1beta1.ProvisioningPolicyNever, expect: true},
+		{name: "disabled controller-wide", disableNodeAutoProvisioning: true, policy: kaitov1beta1.ProvisioningPolicyAuto, expect: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = tt.disableNodeAutoProvisioning
+			t.Cleanup(func() {
+				featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = false
+			})
+			assert.Equal(t, tt.expect, IsNodeAutoProvisioningDisabled(&kaitov1beta1.ResourceSpec{ProvisioningPolicy: tt.policy}))
+		})
+	}
+}
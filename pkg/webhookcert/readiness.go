@@ -0,0 +1,113 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhookcert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/webhook/certificates/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readinessDialTimeout bounds the TLS handshake with the local webhook server.
+const readinessDialTimeout = 2 * time.Second
+
+// Readiness reports whether the webhook can admit requests: its certificate has been
+// issued, the webhook configurations trust it, and the server serves a certificate the
+// webhook configurations trust. Until then the API server would reject every request
+// for the validated resources, so the pod must not be Ready. During a rotation the
+// replicas stay Ready, as the bundles trust both the previous and the next CA.
+type Readiness struct {
+	// Reader should not be cached, so the manager does not watch every Secret.
+	Reader      client.Reader
+	Namespace   string
+	ServiceName string
+	SecretName  string
+	Port        int
+	// WebhookNames are the ValidatingWebhookConfigurations served by the webhook.
+	WebhookNames []string
+	// Host is the address the webhook server is dialed at, localhost if empty.
+	Host string
+}
+
+// Check matches the healthz.Checker signature so it can be served on the readyz endpoint.
+func (r *Readiness) Check(req *http.Request) error {
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Reader.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: r.SecretName}, secret); err != nil {
+		return fmt.Errorf("failed to get webhook certificate secret %s: %w", r.SecretName, err)
+	}
+	if len(secret.Data[resources.CACert]) == 0 {
+		return fmt.Errorf("webhook certificate secret %s has not been populated yet", r.SecretName)
+	}
+	serverCert, err := parseKeyPair(secret.Data[resources.ServerCert], secret.Data[resources.ServerKey])
+	if err != nil {
+		return fmt.Errorf("webhook certificate secret %s has an invalid certificate: %w", r.SecretName, err)
+	}
+	serverName := fmt.Sprintf("%s.%s.svc", r.ServiceName, r.Namespace)
+
+	bundles := map[string][]byte{}
+	for _, name := range r.WebhookNames {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := r.Reader.Get(ctx, client.ObjectKey{Name: name}, config); err != nil {
+			return fmt.Errorf("failed to get webhook configuration %s: %w", name, err)
+		}
+		for _, wh := range config.Webhooks {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(wh.ClientConfig.CABundle)
+			if _, err := serverCert.Verify(x509.VerifyOptions{Roots: pool, DNSName: serverName}); err != nil {
+				return fmt.Errorf("webhook %s does not trust the current certificate yet", wh.Name)
+			}
+			bundles[string(wh.ClientConfig.CABundle)] = wh.ClientConfig.CABundle
+		}
+	}
+
+	host := r.Host
+	if host == "" {
+		host = "localhost"
+	}
+	for _, bundle := range bundles {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(bundle)
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: readinessDialTimeout},
+			Config: &tls.Config{
+				RootCAs:    pool,
+				ServerName: serverName,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(r.Port)))
+		if err != nil {
+			return fmt.Errorf("webhook server is not serving a trusted certificate yet: %w", err)
+		}
+		if err := conn.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhookcert
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/webhook/certificates/resources"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReadinessCheck(t *testing.T) {
+	const namespace, service, secretName, webhookName = "kaito-ragengine", "ragengine", "ragengine-webhook-cert", "validation.ragengine.kaito.sh"
+	serverKey, serverCert, caCert, err := resources.CreateCerts(context.Background(), service, namespace, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	otherServerKey, otherServerCert, otherCACert, err := resources.CreateCerts(context.Background(), service, namespace, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	keyPair, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{keyPair}, MinVersion: tls.VersionTLS12})
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, admissionregistrationv1.AddToScheme(scheme))
+	secret := func(key, cert, ca []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+			Data:       map[string][]byte{resources.ServerKey: key, resources.ServerCert: cert, resources.CACert: ca},
+		}
+	}
+	config := func(caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		return &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: webhookName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         webhookName,
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		err     string
+	}{
+		{
+			name: "secret not found",
+			err:  "failed to get webhook certificate secret",
+		},
+		{
+			name:    "certificate not issued",
+			objects: []client.Object{secret(nil, nil, nil), config(nil)},
+			err:     "has not been populated yet",
+		},
+		{
+			name:    "webhook configuration not found",
+			objects: []client.Object{secret(serverKey, serverCert, caCert)},
+			err:     "failed to get webhook configuration",
+		},
+		{
+			name:    "webhook configuration not reconciled",
+			objects: []client.Object{secret(serverKey, serverCert, caCert), config(otherCACert)},
+			err:     "does not trust the current certificate yet",
+		},
+		{
+			name:    "server serving another certificate",
+			objects: []client.Object{secret(otherServerKey, otherServerCert, otherCACert), config(otherCACert)},
+			err:     "is not serving a trusted certificate yet",
+		},
+		{
+			name:    "ready",
+			objects: []client.Object{secret(serverKey, serverCert, caCert), config(caCert)},
+		},
+		{
+			name: "ready while the next certificate is published",
+			objects: []client.Object{
+				secret(otherServerKey, otherServerCert, append(slices.Clone(otherCACert), caCert...)),
+				config(append(slices.Clone(caCert), otherCACert...)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Readiness{
+				Reader:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				Namespace:    namespace,
+				ServiceName:  service,
+				SecretName:   secretName,
+				Port:         port,
+				WebhookNames: []string{webhookName},
+				Host:         "127.0.0.1",
+			}
+			err := r.Check(nil)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhookcert issues and rotates the serving certificate of the KAITO webhooks so
+// that several replicas can serve admission requests while the certificate changes.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	admissionlisters "k8s.io/client-go/listers/admissionregistration/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	vwhinformer "knative.dev/pkg/client/injection/kube/informers/admissionregistration/v1/validatingwebhookconfiguration"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	knativeinjection "knative.dev/pkg/injection"
+	secretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/certificates/resources"
+)
+
+const (
+	// NextServerKey, NextServerCert and NextCACert hold the certificate that replaces the
+	// serving certificate once every webhook configuration trusts its CA.
+	NextServerKey  = "next-server-key.pem"
+	NextServerCert = "next-server-cert.pem"
+	NextCACert     = "next-ca-cert.pem"
+
+	// Validity is how long an issued certificate is valid.
+	Validity = 7 * 24 * time.Hour
+	// RenewBefore is how long before its expiry the serving certificate is replaced.
+	RenewBefore = 2 * 24 * time.Hour
+	// PropagationDelay is how long the next CA is trusted before its certificate is served,
+	// so that every API server and every webhook replica has observed the new bundle.
+	PropagationDelay = 2 * time.Minute
+	// forcePromotionBefore is how close to its expiry the serving certificate is replaced
+	// even if some webhook configuration does not trust the next CA yet.
+	forcePromotionBefore = time.Hour
+)
+
+// NewController returns the constructor of the certificate controller that replaces the
+// knative one. Knative swaps the CA and the serving certificate at once, which fails
+// admission requests until the API servers and the other replicas catch up. This
+// controller rotates in two steps instead: it first adds the next CA to the bundle of
+// trusted CAs, the ca-cert.pem key the webhook configurations are reconciled from, and
+// only serves the next certificate once the named webhook configurations trust it. The
+// previous CA stays in the bundle until it expires, so replicas still serving the
+// previous certificate remain trusted.
+func NewController(webhookNames ...string) knativeinjection.ControllerConstructor {
+	return func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+		secretInformer := secretinformer.Get(ctx)
+		vwhInformer := vwhinformer.Get(ctx)
+		options := webhook.GetOptions(ctx)
+
+		key := types.NamespacedName{Namespace: system.Namespace(), Name: options.SecretName}
+		r := &reconciler{
+			LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+				PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+					enq(bkt, key)
+					return nil
+				},
+			},
+			key:          key,
+			serviceName:  options.ServiceName,
+			webhookNames: webhookNames,
+			client:       kubeclient.Get(ctx),
+			secretLister: secretInformer.Lister(),
+			vwhLister:    vwhInformer.Lister(),
+		}
+
+		const queueName = "WebhookCertificateRotation"
+		c := controller.NewContext(ctx, r, controller.ControllerOptions{WorkQueueName: queueName, Logger: logging.FromContext(ctx).Named(queueName)})
+		// The secret is the only key; changes of the webhook configurations may allow the
+		// next certificate to be promoted.
+		enqueue := func(any) { c.EnqueueKey(key) }
+		secretInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: controller.FilterWithNameAndNamespace(key.Namespace, key.Name),
+			Handler:    controller.HandleAll(enqueue),
+		})
+		vwhInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj any) bool {
+				object, ok := obj.(metav1.Object)
+				return ok && slices.Contains(webhookNames, object.GetName())
+			},
+			Handler: controller.HandleAll(enqueue),
+		})
+		return c
+	}
+}
+
+type reconciler struct {
+	pkgreconciler.LeaderAwareFuncs
+
+	key          types.NamespacedName
+	serviceName  string
+	webhookNames []string
+	client       kubernetes.Interface
+	secretLister corelisters.SecretLister
+	vwhLister    admissionlisters.ValidatingWebhookConfigurationLister
+}
+
+var (
+	_ controller.Reconciler     = (*reconciler)(nil)
+	_ pkgreconciler.LeaderAware = (*reconciler)(nil)
+)
+
+func (r *reconciler) Reconcile(ctx context.Context, key string) error {
+	if !r.IsLeaderFor(r.key) {
+		return controller.NewSkipKey(key)
+	}
+	secret, err := r.secretLister.Secrets(r.key.Namespace).Get(r.key.Name)
+	if apierrors.IsNotFound(err) {
+		// Like knative, the secret is created by the chart and only populated here.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	data, requeueAfter, err := rotate(ctx, secret.Data, r.trusted, time.Now(), r.serviceName, r.key.Namespace)
+	if err != nil {
+		return err
+	}
+	if !equalData(data, secret.Data) {
+		// Replicas race to update the secret; the losers get a conflict and retry.
+		secret = secret.DeepCopy()
+		secret.Data = data
+		if _, err := r.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Infof("Updated webhook certificate secret %s", r.key.Name)
+	}
+	if requeueAfter > 0 {
+		return controller.NewRequeueAfter(requeueAfter)
+	}
+	return nil
+}
+
+// trusted reports whether every webhook of the named configurations trusts ca.
+func (r *reconciler) trusted(ca *x509.Certificate) bool {
+	for _, name := range r.webhookNames {
+		config, err := r.vwhLister.Get(name)
+		if apierrors.IsNotFound(err) {
+			// A configuration that is not installed admits nothing.
+			continue
+		} else if err != nil {
+			return false
+		}
+		for _, wh := range config.Webhooks {
+			if !slices.ContainsFunc(parseCertificates(wh.ClientConfig.CABundle), ca.Equal) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// rotate returns the secret data at now and how long until it changes again. A missing or
+// invalid serving certificate is replaced at once, as there is nothing to keep serving.
+func rotate(ctx context.Context, data map[string][]byte, trusted func(*x509.Certificate) bool, now time.Time, serviceName, namespace string) (map[string][]byte, time.Duration, error) {
+	current := parseCertificates(data[resources.CACert])
+	serverCert, err := parseKeyPair(data[resources.ServerCert], data[resources.ServerKey])
+	if err != nil || !now.Before(serverCert.NotAfter) || !signedByAny(serverCert, current) {
+		serverKey, serverCertPEM, caCert, err := resources.CreateCerts(ctx, serviceName, namespace, now.Add(Validity))
+		if err != nil {
+			return nil, 0, err
+		}
+		return map[string][]byte{
+			resources.ServerKey:  serverKey,
+			resources.ServerCert: serverCertPEM,
+			resources.CACert:     caCert,
+		}, Validity - RenewBefore, nil
+	}
+
+	result := map[string][]byte{
+		resources.ServerKey:  data[resources.ServerKey],
+		resources.ServerCert: data[resources.ServerCert],
+	}
+	next, nextErr := parseKeyPair(data[NextServerCert], data[NextServerKey])
+	nextCA := parseCertificates(data[NextCACert])
+	if nextErr != nil || len(nextCA) != 1 {
+		if now.Before(serverCert.NotAfter.Add(-RenewBefore)) {
+			// Not due yet; drop the CAs of certificates that are no longer served.
+			result[resources.CACert] = encodeCertificates(unexpired(current, now))
+			return result, serverCert.NotAfter.Add(-RenewBefore).Sub(now), nil
+		}
+		// Publish the next CA first and keep serving the current certificate.
+		nextKey, nextCertPEM, nextCAPEM, err := resources.CreateCerts(ctx, serviceName, namespace, now.Add(Validity))
+		if err != nil {
+			return nil, 0, err
+		}
+		result[NextServerKey], result[NextServerCert], result[NextCACert] = nextKey, nextCertPEM, nextCAPEM
+		result[resources.CACert] = encodeCertificates(append(unexpired(current, now), parseCertificates(nextCAPEM)...))
+		return result, PropagationDelay, nil
+	}
+
+	promoteAt := next.NotBefore.Add(PropagationDelay)
+	forced := !now.Before(serverCert.NotAfter.Add(-forcePromotionBefore))
+	if !forced && (now.Before(promoteAt) || !trusted(nextCA[0])) {
+		result[NextServerKey], result[NextServerCert], result[NextCACert] = data[NextServerKey], data[NextServerCert], data[NextCACert]
+		result[resources.CACert] = encodeCertificates(appendMissing(unexpired(current, now), nextCA[0]))
+		return result, max(promoteAt.Sub(now), PropagationDelay), nil
+	}
+	// Serve the next certificate; its CA leads the bundle and the previous CA stays
+	// trusted until it expires.
+	result[resources.ServerKey], result[resources.ServerCert] = data[NextServerKey], data[NextServerCert]
+	bundle := []*x509.Certificate{nextCA[0]}
+	for _, ca := range unexpired(current, now) {
+		bundle = appendMissing(bundle, ca)
+	}
+	result[resources.CACert] = encodeCertificates(bundle)
+	return result, next.NotAfter.Add(-RenewBefore).Sub(now), nil
+}
+
+// parseKeyPair returns the leaf certificate of a PEM encoded key pair.
+func parseKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, error) {
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(keyPair.Certificate[0])
+}
+
+// parseCertificates returns the certificates of a PEM bundle, skipping invalid blocks.
+func parseCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+}
+
+func encodeCertificates(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+func unexpired(certs []*x509.Certificate, now time.Time) []*x509.Certificate {
+	return slices.DeleteFunc(slices.Clone(certs), func(cert *x509.Certificate) bool {
+		return !now.Before(cert.NotAfter)
+	})
+}
+
+func signedByAny(cert *x509.Certificate, cas []*x509.Certificate) bool {
+	return slices.ContainsFunc(cas, func(ca *x509.Certificate) bool {
+		return cert.CheckSignatureFrom(ca) == nil
+	})
+}
+
+func appendMissing(certs []*x509.Certificate, cert *x509.Certificate) []*x509.Certificate {
+	if slices.ContainsFunc(certs, cert.Equal) {
+		return certs
+	}
+	return append(certs, cert)
+}
+
+func equalData(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
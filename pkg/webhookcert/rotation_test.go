@@ -0,0 +1,103 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhookcert
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"knative.dev/pkg/webhook/certificates/resources"
+)
+
+func TestRotate(t *testing.T) {
+	const namespace, service = "kaito-workspace", "kaito-workspace"
+	ctx := context.Background()
+	trustAll := func(*x509.Certificate) bool { return true }
+	trustNone := func(*x509.Certificate) bool { return false }
+	leaf := func(data map[string][]byte, key string) *x509.Certificate {
+		cert, err := parseKeyPair(data[key], data[map[string]string{
+			resources.ServerCert: resources.ServerKey,
+			NextServerCert:       NextServerKey,
+		}[key]])
+		require.NoError(t, err)
+		return cert
+	}
+
+	// An empty secret is populated at once.
+	now := time.Now()
+	issued, requeue, err := rotate(ctx, map[string][]byte{}, trustNone, now, service, namespace)
+	require.NoError(t, err)
+	assert.Len(t, parseCertificates(issued[resources.CACert]), 1)
+	assert.Equal(t, Validity-RenewBefore, requeue)
+	current := leaf(issued, resources.ServerCert)
+
+	// Before renewal is due, nothing changes.
+	data, requeue, err := rotate(ctx, issued, trustNone, now.Add(time.Hour), service, namespace)
+	require.NoError(t, err)
+	assert.True(t, equalData(issued, data))
+	assert.Greater(t, requeue, time.Duration(0))
+
+	// When due, the next CA is added to the bundle while the current certificate is served.
+	renewAt := current.NotAfter.Add(-RenewBefore)
+	published, requeue, err := rotate(ctx, issued, trustNone, renewAt, service, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, issued[resources.ServerCert], published[resources.ServerCert])
+	assert.Len(t, parseCertificates(published[resources.CACert]), 2)
+	assert.NotEmpty(t, published[NextServerCert])
+	assert.Equal(t, PropagationDelay, requeue)
+	next := leaf(published, NextServerCert)
+
+	// The next certificate is held back until the webhook configurations trust its CA.
+	afterDelay := next.NotBefore.Add(PropagationDelay)
+	data, _, err = rotate(ctx, published, trustNone, afterDelay, service, namespace)
+	require.NoError(t, err)
+	assert.True(t, equalData(published, data))
+	data, _, err = rotate(ctx, published, trustAll, next.NotBefore, service, namespace)
+	require.NoError(t, err)
+	assert.True(t, equalData(published, data))
+
+	// Once trusted, it is served and the previous CA stays in the bundle.
+	promoted, _, err := rotate(ctx, published, trustAll, afterDelay, service, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, published[NextServerCert], promoted[resources.ServerCert])
+	assert.NotContains(t, promoted, NextServerCert)
+	bundle := parseCertificates(promoted[resources.CACert])
+	require.Len(t, bundle, 2)
+	assert.True(t, signedByAny(next, bundle[:1]))
+	assert.True(t, signedByAny(current, bundle[1:]))
+
+	// The previous CA is dropped when it expires.
+	data, _, err = rotate(ctx, promoted, trustAll, current.NotAfter, service, namespace)
+	require.NoError(t, err)
+	assert.Len(t, parseCertificates(data[resources.CACert]), 1)
+
+	// Close to expiry the next certificate is served even if it is not trusted everywhere.
+	data, _, err = rotate(ctx, published, trustNone, current.NotAfter.Add(-time.Minute), service, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, published[NextServerCert], data[resources.ServerCert])
+
+	// A certificate that the bundle does not sign is replaced.
+	broken := map[string][]byte{
+		resources.ServerKey:  issued[resources.ServerKey],
+		resources.ServerCert: issued[resources.ServerCert],
+		resources.CACert:     published[NextCACert],
+	}
+	data, _, err = rotate(ctx, broken, trustNone, now, service, namespace)
+	require.NoError(t, err)
+	assert.NotEqual(t, issued[resources.ServerCert], data[resources.ServerCert])
+}
@@ -0,0 +1,216 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadidentity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// defaultS3Region is asked first; S3 redirects to the region of the bucket.
+const defaultS3Region = "us-east-1"
+
+// awsCredentials are the temporary credentials of an assumed role.
+type awsCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+}
+
+var sessionNameInvalid = regexp.MustCompile(`[^A-Za-z0-9+=,.@-]`)
+
+// sessionName names the STS session after the workspace, which shows up in CloudTrail.
+func sessionName(ws *v1beta1.Workspace) string {
+	name := sessionNameInvalid.ReplaceAllString("kaito-"+ws.Namespace+"-"+ws.Name, "-")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// assumeRole exchanges the ServiceAccount token for credentials of the role, as the AWS
+// SDKs do with AWS_WEB_IDENTITY_TOKEN_FILE. The call is not signed.
+func (c *Checker) assumeRole(ctx context.Context, roleARN, session, token string) (*awsCredentials, error) {
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {session},
+		"WebIdentityToken": {token},
+		"DurationSeconds":  {"900"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.STSEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	status, _, body, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach AWS STS: %w", err)
+	}
+	if status != http.StatusOK {
+		var failure struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}
+		_ = xml.Unmarshal(body, &failure)
+		return nil, fmt.Errorf("role %s cannot be assumed with the workspace ServiceAccount, check its trust policy: %s: %s",
+			roleARN, failure.Code, failure.Message)
+	}
+	var result struct {
+		Credentials awsCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil || result.Credentials.AccessKeyID == "" {
+		return nil, fmt.Errorf("unexpected response from AWS STS (HTTP %d)", status)
+	}
+	return &result.Credentials, nil
+}
+
+// listS3 lists at most one object under the S3 URL, which requires s3:ListBucket.
+func (c *Checker) listS3(ctx context.Context, creds *awsCredentials, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	query := url.Values{"list-type": {"2"}, "max-keys": {"1"}}
+	if prefix := strings.TrimPrefix(u.Path, "/"); prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	region := defaultS3Region
+	for attempt := 0; attempt < 2; attempt++ {
+		endpoint := url.URL{Scheme: "https", Host: "s3." + region + ".amazonaws.com", Path: "/" + u.Host, RawQuery: query.Encode()}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return err
+		}
+		signV4(req, creds, region, "s3", time.Now().UTC())
+		status, header, body, err := c.do(req)
+		if err != nil {
+			return fmt.Errorf("failed to reach %s: %w", raw, err)
+		}
+		var failure struct {
+			Code string `xml:"Code"`
+		}
+		_ = xml.Unmarshal(body, &failure)
+		switch {
+		case status == http.StatusOK:
+			return nil
+		case header.Get("x-amz-bucket-region") != "" && header.Get("x-amz-bucket-region") != region:
+			region = header.Get("x-amz-bucket-region")
+		case status == http.StatusForbidden:
+			return fmt.Errorf("identity cannot list %s (%s), allow s3:ListBucket on the bucket", raw, failure.Code)
+		case status == http.StatusNotFound:
+			return fmt.Errorf("bucket of %s does not exist", raw)
+		default:
+			return fmt.Errorf("listing %s failed with HTTP %d (%s)", raw, status, failure.Code)
+		}
+	}
+	return fmt.Errorf("listing %s failed, S3 redirected it to another region twice", raw)
+}
+
+// signV4 signs the request with AWS Signature Version 4 over its host and all headers set
+// on it, with an empty payload.
+func signV4(req *http.Request, creds *awsCredentials, region, service string, now time.Time) {
+	const emptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", emptyPayload)
+	if creds.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayload,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and encodes the query as SigV4 requires.
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, and slashes unless
+// encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9', ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
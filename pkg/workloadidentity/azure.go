@@ -0,0 +1,100 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadidentity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	azureStorageScope = "https://storage.azure.com/.default"
+	azureStorageAPI   = "2023-11-03"
+)
+
+// azureAccessToken exchanges the ServiceAccount token for a Microsoft Entra access token of
+// Azure Storage, as the Azure SDKs do with AZURE_FEDERATED_TOKEN_FILE.
+func (c *Checker) azureAccessToken(ctx context.Context, tenantID, clientID, assertion string) (string, error) {
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {azureStorageScope},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+	}
+	endpoint := strings.TrimSuffix(c.AzureAuthorityHost, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	status, _, body, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Microsoft Entra ID: %w", err)
+	}
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || (status == http.StatusOK && token.AccessToken == "") {
+		return "", fmt.Errorf("unexpected response from Microsoft Entra ID (HTTP %d)", status)
+	}
+	if status != http.StatusOK {
+		// The description names the missing federated credential, e.g. AADSTS70021.
+		description, _, _ := strings.Cut(token.ErrorDescription, "\r\n")
+		return "", fmt.Errorf("identity %s cannot sign in with the workspace ServiceAccount, check its federated credential: %s: %s",
+			clientID, token.Error, description)
+	}
+	return token.AccessToken, nil
+}
+
+// listBlobs lists at most one blob of the container URL, which requires the Storage Blob
+// Data Reader role or an equivalent data action.
+func (c *Checker) listBlobs(ctx context.Context, accessToken, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	container, prefix, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	query := url.Values{"restype": {"container"}, "comp": {"list"}, "maxresults": {"1"}}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	endpoint := url.URL{Scheme: "https", Host: u.Host, Path: "/" + container, RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("x-ms-version", azureStorageAPI)
+	status, header, _, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", raw, err)
+	}
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return fmt.Errorf("identity cannot list %s (%s), grant it the Storage Blob Data Reader role", raw, header.Get("x-ms-error-code"))
+	case http.StatusNotFound:
+		return fmt.Errorf("storage container %s does not exist", raw)
+	default:
+		return fmt.Errorf("listing %s failed with HTTP %d (%s)", raw, status, header.Get("x-ms-error-code"))
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadidentity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+)
+
+const (
+	// successTTL and failureTTL are how long a check result is reused. Failures expire
+	// sooner so that a fixed role assignment is picked up quickly.
+	successTTL = 10 * time.Minute
+	failureTTL = time.Minute
+
+	// checkTokenSeconds is the lifetime of the token requested for a check.
+	checkTokenSeconds = 600
+
+	// maxResponseBytes bounds the responses read from the identity and storage endpoints.
+	maxResponseBytes = 1 << 20
+)
+
+// TokenRequester issues a token of the ServiceAccount for the audience.
+type TokenRequester func(ctx context.Context, namespace, serviceAccount, audience string) (string, error)
+
+// RequestToken issues ServiceAccount tokens through the TokenRequest API of the client-go
+// client carried by ctx.
+//
+// Deprecated: use NewTokenRequester.
+func RequestToken(ctx context.Context, namespace, serviceAccount, audience string) (string, error) {
+	return NewTokenRequester(nil)(ctx, namespace, serviceAccount, audience)
+}
+
+// NewTokenRequester returns a TokenRequester that issues tokens through the TokenRequest
+// API of kubeClient, or of the client-go client carried by the context if kubeClient is nil.
+func NewTokenRequester(kubeClient kubernetes.Interface) TokenRequester {
+	return func(ctx context.Context, namespace, serviceAccount, audience string) (string, error) {
+		if kubeClient == nil {
+			kubeClient = k8sclient.ClientGoClientFromContext(ctx)
+		}
+		if kubeClient == nil {
+			return "", fmt.Errorf("no Kubernetes client to request a token for ServiceAccount %s/%s", namespace, serviceAccount)
+		}
+		return requestToken(ctx, kubeClient, namespace, serviceAccount, audience)
+	}
+}
+
+func requestToken(ctx context.Context, kubeClient kubernetes.Interface, namespace, serviceAccount, audience string) (string, error) {
+	tr, err := kubeClient.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, serviceAccount,
+		&authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{audience},
+			ExpirationSeconds: ptr.To(int64(checkTokenSeconds)),
+		}}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to request a token for ServiceAccount %s/%s: %w", namespace, serviceAccount, err)
+	}
+	return tr.Status.Token, nil
+}
+
+// Checker verifies that the workload identity of a workspace can list each of its storage
+// URLs, the way the workspace pods authenticate: with a token of the workspace
+// ServiceAccount, exchanged for cloud credentials.
+type Checker struct {
+	// Client sends the requests to the identity and storage endpoints.
+	Client *http.Client
+	// AzureAuthorityHost and STSEndpoint are the token exchange endpoints.
+	AzureAuthorityHost string
+	STSEndpoint        string
+
+	requestToken TokenRequester
+	now          func() time.Time
+
+	mu      sync.Mutex
+	results map[string]checkResult
+}
+
+type checkResult struct {
+	identity v1beta1.WorkloadIdentitySpec
+	err      error
+	expires  time.Time
+}
+
+// NewChecker returns a Checker that requests ServiceAccount tokens with requestToken.
+func NewChecker(requestToken TokenRequester) *Checker {
+	return &Checker{
+		Client:             &http.Client{Timeout: 30 * time.Second},
+		AzureAuthorityHost: DefaultAzureAuthorityHost,
+		STSEndpoint:        "https://sts.amazonaws.com/",
+		requestToken:       requestToken,
+		now:                time.Now,
+		results:            map[string]checkResult{},
+	}
+}
+
+// Check returns nil when the workspace has no identity or the identity can list all of its
+// storage URLs, and otherwise an error that says what to fix. Results are cached per identity.
+func (c *Checker) Check(ctx context.Context, ws *v1beta1.Workspace) error {
+	if ws.Identity == nil {
+		return nil
+	}
+	key := ws.Namespace + "/" + ws.Name
+	c.mu.Lock()
+	cached, ok := c.results[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expires) && reflect.DeepEqual(cached.identity, *ws.Identity) {
+		return cached.err
+	}
+
+	err := c.check(ctx, ws)
+	ttl := successTTL
+	if err != nil {
+		ttl = failureTTL
+	}
+	c.mu.Lock()
+	c.results[key] = checkResult{identity: *ws.Identity.DeepCopy(), err: err, expires: c.now().Add(ttl)}
+	c.mu.Unlock()
+	return err
+}
+
+// Forget drops the cached result of a workspace, e.g. after it was deleted.
+func (c *Checker) Forget(ws *v1beta1.Workspace) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.results, ws.Namespace+"/"+ws.Name)
+}
+
+func (c *Checker) check(ctx context.Context, ws *v1beta1.Workspace) error {
+	identity := ws.Identity
+	if len(identity.StorageURLs) == 0 {
+		return nil
+	}
+	token, err := c.requestToken(ctx, ws.Namespace, v1beta1.GetServiceAccountName(ws), Audience(identity.Provider))
+	if err != nil {
+		return err
+	}
+	if identity.Provider == v1beta1.WorkloadIdentityProviderAWS {
+		creds, err := c.assumeRole(ctx, identity.RoleARN, sessionName(ws), token)
+		if err != nil {
+			return err
+		}
+		for _, raw := range identity.StorageURLs {
+			if err := c.listS3(ctx, creds, raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	accessToken, err := c.azureAccessToken(ctx, identity.TenantID, identity.ClientID, token)
+	if err != nil {
+		return err
+	}
+	for _, raw := range identity.StorageURLs {
+		if err := c.listBlobs(ctx, accessToken, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// do sends the request and returns the status and the bounded body of the response.
+func (c *Checker) do(req *http.Request) (int, http.Header, []byte, error) {
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return resp.StatusCode, resp.Header, body, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workloadidentity binds workspace pods to a cloud workload identity, AKS Workload
+// Identity or EKS IRSA, and checks that the identity can reach the workspace storage.
+package workloadidentity
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+const (
+	// AnnotationAzureClientID and AnnotationAzureTenantID bind a ServiceAccount to an Azure identity.
+	AnnotationAzureClientID = "azure.workload.identity/client-id"
+	AnnotationAzureTenantID = "azure.workload.identity/tenant-id"
+	// AnnotationAWSRoleARN binds a ServiceAccount to an IAM role.
+	AnnotationAWSRoleARN = "eks.amazonaws.com/role-arn"
+
+	// AzureAudience and AWSAudience are the audiences the clouds accept in projected tokens.
+	AzureAudience = "api://AzureADTokenExchange"
+	AWSAudience   = "sts.amazonaws.com"
+
+	// DefaultAzureAuthorityHost is the Microsoft Entra endpoint of the public cloud.
+	DefaultAzureAuthorityHost = "https://login.microsoftonline.com/"
+
+	// The token volumes use the names and paths of the Azure and EKS admission webhooks, so a
+	// webhook that also runs in the cluster recognizes the token as already mounted.
+	azureTokenVolumeName = "azure-identity-token"
+	azureTokenDir        = "/var/run/secrets/azure/tokens"
+	azureTokenFile       = "azure-identity-token"
+	awsTokenVolumeName   = "aws-iam-token"
+	awsTokenDir          = "/var/run/secrets/eks.amazonaws.com/serviceaccount"
+	awsTokenFile         = "token"
+
+	// tokenExpirationSeconds is how long a projected token is valid; the kubelet rotates it
+	// well before it expires.
+	tokenExpirationSeconds = 3600
+)
+
+// ServiceAccountAnnotations returns the annotations that bind a ServiceAccount to the identity.
+func ServiceAccountAnnotations(identity *v1beta1.WorkloadIdentitySpec) map[string]string {
+	if identity.Provider == v1beta1.WorkloadIdentityProviderAWS {
+		return map[string]string{AnnotationAWSRoleARN: identity.RoleARN}
+	}
+	return map[string]string{
+		AnnotationAzureClientID: identity.ClientID,
+		AnnotationAzureTenantID: identity.TenantID,
+	}
+}
+
+// Audience returns the audience of the ServiceAccount tokens the identity provider accepts.
+func Audience(provider v1beta1.WorkloadIdentityProvider) string {
+	if provider == v1beta1.WorkloadIdentityProviderAWS {
+		return AWSAudience
+	}
+	return AzureAudience
+}
+
+// TokenVolume returns the projected ServiceAccount token volume of the identity and the
+// read-only mount that exposes it to containers.
+func TokenVolume(identity *v1beta1.WorkloadIdentitySpec) (corev1.Volume, corev1.VolumeMount) {
+	name, dir, file := azureTokenVolumeName, azureTokenDir, azureTokenFile
+	if identity.Provider == v1beta1.WorkloadIdentityProviderAWS {
+		name, dir, file = awsTokenVolumeName, awsTokenDir, awsTokenFile
+	}
+	volume := corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          Audience(identity.Provider),
+						ExpirationSeconds: ptr.To(int64(tokenExpirationSeconds)),
+						Path:              file,
+					},
+				}},
+			},
+		},
+	}
+	return volume, corev1.VolumeMount{Name: name, MountPath: dir, ReadOnly: true}
+}
+
+// Env returns the environment the Azure and AWS SDKs read to authenticate with the projected
+// token, so downloaders pick up the identity without further configuration.
+func Env(identity *v1beta1.WorkloadIdentitySpec) []corev1.EnvVar {
+	if identity.Provider == v1beta1.WorkloadIdentityProviderAWS {
+		return []corev1.EnvVar{
+			{Name: "AWS_ROLE_ARN", Value: identity.RoleARN},
+			{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: path.Join(awsTokenDir, awsTokenFile)},
+		}
+	}
+	return []corev1.EnvVar{
+		{Name: "AZURE_AUTHORITY_HOST", Value: DefaultAzureAuthorityHost},
+		{Name: "AZURE_CLIENT_ID", Value: identity.ClientID},
+		{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: path.Join(azureTokenDir, azureTokenFile)},
+		{Name: "AZURE_TENANT_ID", Value: identity.TenantID},
+	}
+}
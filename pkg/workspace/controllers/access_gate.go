@@ -0,0 +1,109 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+// accessGateRequeueInterval is how often a gated workspace is checked again. Secrets are
+// not watched, so creating or fixing the access secret is picked up on the next check.
+const accessGateRequeueInterval = time.Minute
+
+// modelAccessGate returns why the preset model of wObj cannot be downloaded yet, or an
+// empty string if it can. Gated models need the license acknowledgment and an access
+// secret with a HF_TOKEN key; without them the downloader would crash-loop on 401 errors.
+// The gate only holds back workspaces that have not been deployed yet: workspaces that
+// already have an inference StatefulSet, e.g. gated presets created before the gate
+// existed, keep being reconciled. The status conditions cannot tell them apart, since the
+// InferenceStatus condition is also reported, as pending, for gated workspaces. Changes
+// to acceptLicense are checked by the webhook instead.
+func (c *WorkspaceReconciler) modelAccessGate(ctx context.Context, wObj *kaitov1beta1.Workspace) (string, error) {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return "", nil
+	}
+	message, err := c.modelAccessMessage(ctx, wObj)
+	if message == "" || err != nil {
+		return "", err
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), &appsv1.StatefulSet{}); err == nil {
+		return "", nil
+	} else if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get inference workload %s: %w", wObj.Name, err)
+	}
+	return message, nil
+}
+
+// modelAccessMessage returns why the preset model of wObj cannot be downloaded, whether it
+// has been deployed or not.
+func (c *WorkspaceReconciler) modelAccessMessage(ctx context.Context, wObj *kaitov1beta1.Workspace) (string, error) {
+	preset := wObj.Inference.Preset
+	model, err := models.GetModelByName(ctx, string(preset.Name), preset.PresetOptions.ModelAccessSecret, wObj.Namespace, c.Client)
+	if err != nil {
+		// Reported when the workload is rendered.
+		return "", nil
+	}
+	params := model.GetInferenceParameters()
+	if license := params.GatedLicense; license != "" && preset.PresetOptions.AcceptLicense != license {
+		return fmt.Sprintf("preset %s is gated by the %s license; accept it on HuggingFace and set presetOptions.acceptLicense to %q",
+			preset.Name, license, license), nil
+	}
+	if !params.DownloadAtRuntime || !params.DownloadAuthRequired {
+		return "", nil
+	}
+
+	secretName := preset.PresetOptions.ModelAccessSecret
+	if secretName == "" {
+		return fmt.Sprintf("preset %s requires a presetOptions.modelAccessSecret with a HF_TOKEN key", preset.Name), nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: wObj.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("model access secret %s not found", secretName), nil
+		}
+		return "", fmt.Errorf("failed to get model access secret %s: %w", secretName, err)
+	}
+	if len(secret.Data["HF_TOKEN"]) == 0 {
+		return fmt.Sprintf("model access secret %s has no HF_TOKEN key", secretName), nil
+	}
+	return "", nil
+}
+
+// applyAccessGatedCondition sets AccessGated while message is not empty and removes it
+// once the gate is cleared.
+func applyAccessGatedCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, message string) {
+	if message == "" {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeAccessGated))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeAccessGated),
+		Status:             metav1.ConditionTrue,
+		Reason:             "AccessGated",
+		Message:            message,
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+)
+
+type gatedTestModel struct{}
+
+func (*gatedTestModel) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{Metadata: model.Metadata{
+		DownloadAtRuntime:    true,
+		DownloadAuthRequired: true,
+		GatedLicense:         "test-license",
+	}}
+}
+func (*gatedTestModel) GetTuningParameters() *model.PresetParam { return nil }
+func (*gatedTestModel) SupportDistributedInference() bool       { return false }
+func (*gatedTestModel) SupportTuning() bool                     { return false }
+
+func TestModelAccessGate(t *testing.T) {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{Name: "test-gated-model", Instance: &gatedTestModel{}})
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	newWorkspace := func(options v1beta1.PresetOptions) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+			Inference: &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{
+				PresetMeta:    v1beta1.PresetMeta{Name: "test-gated-model"},
+				PresetOptions: options,
+			}},
+		}
+	}
+	secret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "hf-token", Namespace: "default"}, Data: data}
+	}
+
+	tests := []struct {
+		name    string
+		options v1beta1.PresetOptions
+		secret  *corev1.Secret
+		// deployed marks a workspace that already has an inference StatefulSet.
+		deployed bool
+		// pending marks a workspace whose InferenceStatus condition is pending.
+		pending bool
+		message string
+	}{
+		{
+			name:    "license not accepted",
+			options: v1beta1.PresetOptions{ModelAccessSecret: "hf-token"},
+			secret:  secret(map[string][]byte{"HF_TOKEN": []byte("token")}),
+			message: `gated by the test-license license; accept it on HuggingFace and set presetOptions.acceptLicense to "test-license"`,
+		},
+		{
+			name:    "no access secret",
+			options: v1beta1.PresetOptions{AcceptLicense: "test-license"},
+			message: "requires a presetOptions.modelAccessSecret",
+		},
+		{
+			name:    "access secret not found",
+			options: v1beta1.PresetOptions{AcceptLicense: "test-license", ModelAccessSecret: "hf-token"},
+			message: "model access secret hf-token not found",
+		},
+		{
+			name:    "access secret without token",
+			options: v1beta1.PresetOptions{AcceptLicense: "test-license", ModelAccessSecret: "hf-token"},
+			secret:  secret(map[string][]byte{"token": []byte("token")}),
+			message: "has no HF_TOKEN key",
+		},
+		{
+			name:    "pending workspace without accepted license",
+			options: v1beta1.PresetOptions{ModelAccessSecret: "hf-token"},
+			pending: true,
+			message: "gated by the test-license license",
+		},
+		{
+			name:     "deployed workspace without accepted license",
+			options:  v1beta1.PresetOptions{ModelAccessSecret: "hf-token"},
+			deployed: true,
+		},
+		{
+			name:    "access granted",
+			options: v1beta1.PresetOptions{AcceptLicense: "test-license", ModelAccessSecret: "hf-token"},
+			secret:  secret(map[string][]byte{"HF_TOKEN": []byte("token")}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.secret != nil {
+				builder = builder.WithObjects(tt.secret)
+			}
+			if tt.deployed {
+				builder = builder.WithObjects(&appsv1.StatefulSet{ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"}})
+			}
+			c := &WorkspaceReconciler{Client: builder.Build()}
+			wObj := newWorkspace(tt.options)
+			if tt.pending {
+				meta.SetStatusCondition(&wObj.Status.Conditions, v1.Condition{
+					Type:   string(v1beta1.WorkspaceConditionTypeInferenceStatus),
+					Status: v1.ConditionFalse,
+					Reason: "WorkspaceInferenceStatusPending",
+				})
+			}
+
+			message, err := c.modelAccessGate(context.Background(), wObj)
+			require.NoError(t, err)
+			if tt.message == "" {
+				assert.Empty(t, message)
+			} else {
+				assert.Contains(t, message, tt.message)
+			}
+
+			status := &v1beta1.WorkspaceStatus{}
+			applyAccessGatedCondition(status, wObj, message)
+			cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeAccessGated))
+			if tt.message == "" {
+				assert.Nil(t, cond)
+			} else if assert.NotNil(t, cond) {
+				assert.Equal(t, v1.ConditionTrue, cond.Status)
+				assert.Equal(t, message, cond.Message)
+			}
+		})
+	}
+}
+
+func TestModelAccessGateHoldsBackWorkloadAcrossReconciles(t *testing.T) {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{Name: "test-gated-model", Instance: &gatedTestModel{}})
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	wObj := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Inference: &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{
+			PresetMeta:    v1beta1.PresetMeta{Name: "test-gated-model"},
+			PresetOptions: v1beta1.PresetOptions{ModelAccessSecret: "hf-token"},
+		}},
+	}
+	// After the first reconcile, the status sync reports the inference as pending.
+	meta.SetStatusCondition(&wObj.Status.Conditions, v1.Condition{
+		Type:   string(v1beta1.WorkspaceConditionTypeInferenceStatus),
+		Status: v1.ConditionFalse,
+		Reason: "WorkspaceInferenceStatusPending",
+	})
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(wObj).Build()
+	c := &WorkspaceReconciler{Client: cl, expectations: utils.NewControllerExpectations()}
+
+	for range 2 {
+		result, err := c.addOrUpdateWorkspace(context.Background(), wObj)
+		require.NoError(t, err)
+		assert.Equal(t, accessGateRequeueInterval, result.RequeueAfter)
+	}
+	err := cl.Get(context.Background(), client.ObjectKeyFromObject(wObj), &appsv1.StatefulSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
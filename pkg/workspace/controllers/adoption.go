@@ -0,0 +1,269 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+)
+
+// annotationAdoptedWorkload marks a StatefulSet the controller adopted, so it can be rolled
+// to the rendered spec once the Workspace drops the kaito.sh/adopt-workload annotation.
+const annotationAdoptedWorkload = kaitov1beta1.KAITOPrefix + "adopted"
+
+const (
+	reasonWorkloadInSync  = "InSync"
+	reasonWorkloadDrifted = "Drifted"
+)
+
+// isAdopting reports whether wObj adopts a workload of the given kind and name.
+func isAdopting(wObj *kaitov1beta1.Workspace, kind, name string) bool {
+	k, n, ok := kaitov1beta1.GetAdoptedWorkload(wObj)
+	return ok && k == kind && n == name
+}
+
+// takeOwnership makes wObj the controller of obj. It fails when another controller already
+// owns obj, so two owners never fight over the same workload.
+func takeOwnership(wObj *kaitov1beta1.Workspace, obj metav1.Object) (changed bool, err error) {
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		if owner.UID == wObj.UID {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s is already controlled by %s %s", obj.GetName(), owner.Kind, owner.Name)
+	}
+	obj.SetOwnerReferences(append(obj.GetOwnerReferences(),
+		*metav1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace"))))
+	return true, nil
+}
+
+// reconcileAdoptedStatefulSet handles a StatefulSet named after the Workspace that was not
+// created by KAITO. While the Workspace adopts it, the controller owns it, adds the
+// Workspace labels to the StatefulSet metadata and only reports drift. The pod template is
+// left alone so the adopted pods are not restarted; the Workspace Service selects them by
+// pod name instead, see adoptedServiceSelector. It returns handled=true in that case so the
+// caller leaves the spec alone.
+// It returns released=true when adoption ended and the caller must roll the StatefulSet to
+// the rendered spec.
+func (c *WorkspaceReconciler) reconcileAdoptedStatefulSet(ctx context.Context, wObj *kaitov1beta1.Workspace, existing, desired *appsv1.StatefulSet) (handled, released bool, err error) {
+	_, wasAdopted := existing.Annotations[annotationAdoptedWorkload]
+	if !isAdopting(wObj, kaitov1beta1.AdoptWorkloadKindStatefulSet, existing.Name) {
+		return false, wasAdopted, nil
+	}
+
+	changed, err := takeOwnership(wObj, existing)
+	if err != nil {
+		return true, false, err
+	}
+	if !wasAdopted {
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[annotationAdoptedWorkload] = "true"
+		changed = true
+	}
+	for k, v := range desired.Labels {
+		if existing.Labels[k] != v {
+			if existing.Labels == nil {
+				existing.Labels = map[string]string{}
+			}
+			existing.Labels[k] = v
+			changed = true
+		}
+	}
+	if changed {
+		if err := c.Update(ctx, existing); err != nil {
+			return true, false, fmt.Errorf("failed to adopt StatefulSet %s: %w", existing.Name, err)
+		}
+		c.recordEvent(wObj, corev1.EventTypeNormal, "WorkloadAdopted", fmt.Sprintf("Adopted StatefulSet %s", existing.Name))
+	}
+
+	drift := workloadDrift(&existing.Spec.Template.Spec, &desired.Spec.Template.Spec)
+	return true, false, c.setWorkloadAdoptedCondition(ctx, wObj, existing.Name, drift,
+		fmt.Sprintf("remove the %s annotation to roll it to the rendered spec", kaitov1beta1.AnnotationAdoptWorkload))
+}
+
+// adoptedServiceSelector narrows the selector of the Workspace Service to the pod name
+// label while the Workspace adopts its StatefulSet. The adopted pods keep their own labels,
+// but the StatefulSet, being named after the Workspace, already gives the first pod the pod
+// name the Service selects. The narrow selector is kept until a released StatefulSet has
+// rolled to the rendered template, since the first pod is the last one replaced.
+// existing is the current Service, nil when it does not exist yet.
+func (c *WorkspaceReconciler) adoptedServiceSelector(ctx context.Context, wObj *kaitov1beta1.Workspace, existing, desired *corev1.Service) (bool, error) {
+	if !isAdopting(wObj, kaitov1beta1.AdoptWorkloadKindStatefulSet, wObj.Name) {
+		if existing == nil || !isAdoptedServiceSelector(existing.Spec.Selector) {
+			return false, nil
+		}
+		ss := &appsv1.StatefulSet{}
+		if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, ss); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if statefulSetReady(ss) && ss.Status.CurrentRevision == ss.Status.UpdateRevision {
+			return false, nil
+		}
+	}
+	delete(desired.Spec.Selector, kaitov1beta1.LabelWorkspaceName)
+	return true, nil
+}
+
+// isAdoptedServiceSelector reports whether selector is the pod name only selector of an
+// adopted StatefulSet.
+func isAdoptedServiceSelector(selector map[string]string) bool {
+	_, hasWorkspace := selector[kaitov1beta1.LabelWorkspaceName]
+	_, hasPodName := selector[appsv1.StatefulSetPodNameLabel]
+	return hasPodName && !hasWorkspace
+}
+
+// releaseAdoptedStatefulSet replaces the pod template of a formerly adopted StatefulSet with
+// the rendered one. The selector is immutable, so its labels are kept on the new template.
+func releaseAdoptedStatefulSet(existing, desired *appsv1.StatefulSet) {
+	template := desired.Spec.Template.DeepCopy()
+	if existing.Spec.Selector != nil {
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		for k, v := range existing.Spec.Selector.MatchLabels {
+			template.Labels[k] = v
+		}
+	}
+	existing.Spec.Template = *template
+	delete(existing.Annotations, annotationAdoptedWorkload)
+}
+
+// reconcileAdoptedDeployment takes ownership of the adopted Deployment and keeps it serving
+// while the Workspace StatefulSet comes up. Once the StatefulSet is ready the Deployment is
+// deleted.
+func (c *WorkspaceReconciler) reconcileAdoptedDeployment(ctx context.Context, wObj *kaitov1beta1.Workspace, name string, desired *appsv1.StatefulSet) error {
+	deploy := &appsv1.Deployment{}
+	if err := resources.GetResource(ctx, name, wObj.Namespace, c.Client, deploy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.clearWorkloadAdoptedCondition(ctx, wObj)
+		}
+		return fmt.Errorf("failed to get adopted Deployment %s: %w", name, err)
+	}
+
+	changed, err := takeOwnership(wObj, deploy)
+	if err != nil {
+		return err
+	}
+	if changed {
+		if err := c.Update(ctx, deploy); err != nil {
+			return fmt.Errorf("failed to adopt Deployment %s: %w", name, err)
+		}
+		c.recordEvent(wObj, corev1.EventTypeNormal, "WorkloadAdopted", fmt.Sprintf("Adopted Deployment %s", name))
+	}
+
+	ss := &appsv1.StatefulSet{}
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, ss); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	} else if err == nil && statefulSetReady(ss) {
+		klog.InfoS("Workspace StatefulSet is ready, deleting adopted Deployment", "workspace", klog.KObj(wObj), "deployment", name)
+		if err := c.Delete(ctx, deploy, &client.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &deploy.UID},
+		}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete adopted Deployment %s: %w", name, err)
+		}
+		c.recordEvent(wObj, corev1.EventTypeNormal, "AdoptedWorkloadReplaced",
+			fmt.Sprintf("Deployment %s was replaced by StatefulSet %s", name, ss.Name))
+		return c.clearWorkloadAdoptedCondition(ctx, wObj)
+	}
+
+	drift := workloadDrift(&deploy.Spec.Template.Spec, &desired.Spec.Template.Spec)
+	return c.setWorkloadAdoptedCondition(ctx, wObj, name, drift, "it is replaced once the workspace StatefulSet is ready")
+}
+
+func statefulSetReady(ss *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	return ss.Status.ObservedGeneration >= ss.Generation && ss.Status.ReadyReplicas >= replicas
+}
+
+// workloadDrift lists the fields of the main container that differ between an adopted
+// workload and the spec KAITO renders for the Workspace.
+func workloadDrift(actual, desired *corev1.PodSpec) []string {
+	if len(actual.Containers) == 0 || len(desired.Containers) == 0 {
+		return nil
+	}
+	a, d := &actual.Containers[0], &desired.Containers[0]
+	var drift []string
+	if a.Image != d.Image {
+		drift = append(drift, "image")
+	}
+	if !apiequality.Semantic.DeepEqual(a.Command, d.Command) {
+		drift = append(drift, "command")
+	}
+	if !apiequality.Semantic.DeepEqual(a.Args, d.Args) {
+		drift = append(drift, "args")
+	}
+	if !apiequality.Semantic.DeepEqual(a.Resources, d.Resources) {
+		drift = append(drift, "resources")
+	}
+	return drift
+}
+
+// setWorkloadAdoptedCondition records the drift of the adopted workload and emits an event
+// when the workload starts drifting.
+func (c *WorkspaceReconciler) setWorkloadAdoptedCondition(ctx context.Context, wObj *kaitov1beta1.Workspace, name string, drift []string, hint string) error {
+	cond := metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeWorkloadAdopted),
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonWorkloadInSync,
+		Message:            fmt.Sprintf("Adopted workload %s matches the rendered spec", name),
+		ObservedGeneration: wObj.GetGeneration(),
+	}
+	if len(drift) > 0 {
+		cond.Reason = reasonWorkloadDrifted
+		cond.Message = fmt.Sprintf("Adopted workload %s differs from the rendered spec in: %s; %s", name, strings.Join(drift, ", "), hint)
+	}
+
+	current := meta.FindStatusCondition(wObj.Status.Conditions, cond.Type)
+	if current != nil && current.Reason == cond.Reason && current.Message == cond.Message {
+		return nil
+	}
+	if cond.Reason == reasonWorkloadDrifted {
+		c.recordEvent(wObj, corev1.EventTypeWarning, "AdoptedWorkloadDrifted", cond.Message)
+	}
+	return workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
+		meta.SetStatusCondition(&status.Conditions, cond)
+		return nil
+	})
+}
+
+func (c *WorkspaceReconciler) clearWorkloadAdoptedCondition(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeWorkloadAdopted)) == nil {
+		return nil
+	}
+	return workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeWorkloadAdopted))
+		return nil
+	})
+}
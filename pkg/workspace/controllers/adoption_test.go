@@ -0,0 +1,242 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newAdoptionTestWorkspace(adopt string) *v1beta1.Workspace {
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", UID: "ws-uid"},
+	}
+	if adopt != "" {
+		ws.Annotations = map[string]string{v1beta1.AnnotationAdoptWorkload: adopt}
+	}
+	return ws
+}
+
+func newAdoptionTestPodTemplate(image string, labels map[string]string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: v1.ObjectMeta{Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "vllm", Image: image, Args: []string{"--port=5000"}}},
+		},
+	}
+}
+
+func newAdoptionTestStatefulSet(name, image string, labels map[string]string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &v1.LabelSelector{MatchLabels: labels},
+			Template: newAdoptionTestPodTemplate(image, labels),
+		},
+	}
+}
+
+func newAdoptionTestReconciler(t *testing.T, ws *v1beta1.Workspace, objs ...client.Object) (*WorkspaceReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(append(objs, ws.DeepCopy())...).
+		WithStatusSubresource(&v1beta1.Workspace{}).
+		Build()
+	return &WorkspaceReconciler{Client: cl, Recorder: record.NewFakeRecorder(10)}, cl
+}
+
+func TestReconcileAdoptedStatefulSet(t *testing.T) {
+	userLabels := map[string]string{"app": "vllm"}
+	desired := newAdoptionTestStatefulSet("ws", "kaito/vllm:0.1", map[string]string{v1beta1.LabelWorkspaceName: "ws"})
+	desired.Labels = map[string]string{v1beta1.LabelWorkspaceName: "ws"}
+
+	t.Run("adopts and reports drift", func(t *testing.T) {
+		ws := newAdoptionTestWorkspace("StatefulSet/ws")
+		existing := newAdoptionTestStatefulSet("ws", "vllm/vllm-openai:v0.9.0", userLabels)
+		reconciler, cl := newAdoptionTestReconciler(t, ws, existing)
+
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(existing), existing))
+		handled, released, err := reconciler.reconcileAdoptedStatefulSet(context.Background(), ws, existing, desired)
+		require.NoError(t, err)
+		assert.True(t, handled)
+		assert.False(t, released)
+
+		updated := &appsv1.StatefulSet{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(existing), updated))
+		owner := v1.GetControllerOf(updated)
+		require.NotNil(t, owner)
+		assert.Equal(t, ws.UID, owner.UID)
+		assert.Equal(t, "true", updated.Annotations[annotationAdoptedWorkload])
+		assert.Equal(t, "ws", updated.Labels[v1beta1.LabelWorkspaceName])
+		assert.Equal(t, userLabels, updated.Spec.Template.Labels, "the pod template must not change")
+		assert.Equal(t, "vllm/vllm-openai:v0.9.0", updated.Spec.Template.Spec.Containers[0].Image)
+
+		wsObj := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(ws), wsObj))
+		cond := meta.FindStatusCondition(wsObj.Status.Conditions, string(v1beta1.WorkspaceConditionTypeWorkloadAdopted))
+		require.NotNil(t, cond)
+		assert.Equal(t, reasonWorkloadDrifted, cond.Reason)
+		assert.Contains(t, cond.Message, "image")
+	})
+
+	t.Run("refuses a StatefulSet controlled by another owner", func(t *testing.T) {
+		ws := newAdoptionTestWorkspace("StatefulSet/ws")
+		existing := newAdoptionTestStatefulSet("ws", "vllm/vllm-openai:v0.9.0", userLabels)
+		existing.OwnerReferences = []v1.OwnerReference{{
+			APIVersion: "example.com/v1", Kind: "Other", Name: "other", UID: "other-uid", Controller: ptr.To(true),
+		}}
+		reconciler, _ := newAdoptionTestReconciler(t, ws, existing)
+
+		handled, _, err := reconciler.reconcileAdoptedStatefulSet(context.Background(), ws, existing, desired)
+		assert.True(t, handled)
+		assert.ErrorContains(t, err, "already controlled by Other other")
+	})
+
+	t.Run("releases when the annotation is removed", func(t *testing.T) {
+		ws := newAdoptionTestWorkspace("")
+		existing := newAdoptionTestStatefulSet("ws", "vllm/vllm-openai:v0.9.0", userLabels)
+		existing.Annotations = map[string]string{annotationAdoptedWorkload: "true"}
+		reconciler, _ := newAdoptionTestReconciler(t, ws, existing)
+
+		handled, released, err := reconciler.reconcileAdoptedStatefulSet(context.Background(), ws, existing, desired)
+		require.NoError(t, err)
+		assert.False(t, handled)
+		assert.True(t, released)
+
+		releaseAdoptedStatefulSet(existing, desired)
+		assert.Equal(t, "kaito/vllm:0.1", existing.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "vllm", existing.Spec.Template.Labels["app"], "selector labels must be kept")
+		assert.Equal(t, "ws", existing.Spec.Template.Labels[v1beta1.LabelWorkspaceName])
+		assert.NotContains(t, existing.Annotations, annotationAdoptedWorkload)
+	})
+}
+
+func TestReconcileAdoptedDeployment(t *testing.T) {
+	desired := newAdoptionTestStatefulSet("ws", "kaito/vllm:0.1", map[string]string{v1beta1.LabelWorkspaceName: "ws"})
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: v1.ObjectMeta{Name: "vllm", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "vllm"}},
+				Template: newAdoptionTestPodTemplate("kaito/vllm:0.1", map[string]string{"app": "vllm"}),
+			},
+		}
+	}
+
+	t.Run("keeps the Deployment while the StatefulSet is not ready", func(t *testing.T) {
+		ws := newAdoptionTestWorkspace("Deployment/vllm")
+		reconciler, cl := newAdoptionTestReconciler(t, ws, newDeployment(), desired.DeepCopy())
+
+		require.NoError(t, reconciler.reconcileAdoptedDeployment(context.Background(), ws, "vllm", desired))
+
+		deploy := &appsv1.Deployment{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: "vllm", Namespace: "default"}, deploy))
+		owner := v1.GetControllerOf(deploy)
+		require.NotNil(t, owner)
+		assert.Equal(t, ws.UID, owner.UID)
+
+		wsObj := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(ws), wsObj))
+		cond := meta.FindStatusCondition(wsObj.Status.Conditions, string(v1beta1.WorkspaceConditionTypeWorkloadAdopted))
+		require.NotNil(t, cond)
+		assert.Equal(t, reasonWorkloadInSync, cond.Reason)
+	})
+
+	t.Run("deletes the Deployment once the StatefulSet is ready", func(t *testing.T) {
+		ws := newAdoptionTestWorkspace("Deployment/vllm")
+		ready := desired.DeepCopy()
+		ready.Status.ReadyReplicas = 1
+		reconciler, cl := newAdoptionTestReconciler(t, ws, newDeployment(), ready)
+
+		require.NoError(t, reconciler.reconcileAdoptedDeployment(context.Background(), ws, "vllm", desired))
+
+		err := cl.Get(context.Background(), client.ObjectKey{Name: "vllm", Namespace: "default"}, &appsv1.Deployment{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestWorkloadDrift(t *testing.T) {
+	base := newAdoptionTestPodTemplate("kaito/vllm:0.1", nil).Spec
+	changed := *base.DeepCopy()
+	changed.Containers[0].Image = "vllm/vllm-openai:v0.9.0"
+	changed.Containers[0].Args = []string{"--port=8000"}
+
+	assert.Empty(t, workloadDrift(&base, base.DeepCopy()))
+	assert.Equal(t, []string{"image", "args"}, workloadDrift(&changed, &base))
+}
+
+func TestAdoptedServiceSelector(t *testing.T) {
+	desiredSelector := map[string]string{v1beta1.LabelWorkspaceName: "ws", appsv1.StatefulSetPodNameLabel: "ws-0"}
+	narrowSelector := map[string]string{appsv1.StatefulSetPodNameLabel: "ws-0"}
+	service := func(selector map[string]string) *corev1.Service {
+		return &corev1.Service{Spec: corev1.ServiceSpec{Selector: maps.Clone(selector)}}
+	}
+	released := func(rolledOut bool) *appsv1.StatefulSet {
+		ss := newAdoptionTestStatefulSet("ws", "kaito/vllm:0.1", nil)
+		ss.Status = appsv1.StatefulSetStatus{ReadyReplicas: 1, CurrentRevision: "ws-1", UpdateRevision: "ws-2"}
+		if rolledOut {
+			ss.Status.CurrentRevision = "ws-2"
+		}
+		return ss
+	}
+
+	tests := []struct {
+		name     string
+		adopt    string
+		existing *corev1.Service
+		ss       *appsv1.StatefulSet
+		want     map[string]string
+	}{
+		{name: "not adopting", existing: service(desiredSelector), want: desiredSelector},
+		{name: "adopting before the service exists", adopt: "StatefulSet/ws", want: narrowSelector},
+		{name: "adopting", adopt: "StatefulSet/ws", existing: service(desiredSelector), want: narrowSelector},
+		{name: "adopting a deployment", adopt: "Deployment/ws", existing: service(desiredSelector), want: desiredSelector},
+		{name: "released while rolling out", existing: service(narrowSelector), ss: released(false), want: narrowSelector},
+		{name: "released and rolled out", existing: service(narrowSelector), ss: released(true), want: desiredSelector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newAdoptionTestWorkspace(tt.adopt)
+			var objs []client.Object
+			if tt.ss != nil {
+				objs = append(objs, tt.ss)
+			}
+			reconciler, _ := newAdoptionTestReconciler(t, ws, objs...)
+
+			desired := service(desiredSelector)
+			_, err := reconciler.adoptedServiceSelector(context.Background(), ws, tt.existing, desired)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, desired.Spec.Selector)
+		})
+	}
+}
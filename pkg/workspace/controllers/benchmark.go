@@ -195,7 +195,7 @@ func reconcileBenchmarkResult(ctx context.Context, wObj *kaitov1beta1.Workspace)
 	podName := wObj.Name + benchmarkPodIndexSuffix
 
 	tailLines := benchmarkLogTailLines
-	req := k8sclient.GetGlobalClientGoClient().CoreV1().Pods(wObj.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+	req := k8sclient.ClientGoClientFromContext(ctx).CoreV1().Pods(wObj.Namespace).GetLogs(podName, &corev1.PodLogOptions{
 		TailLines: &tailLines,
 		Container: wObj.Name,
 	})
@@ -0,0 +1,348 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+)
+
+// Cold start phases reported by workspaceColdStartSeconds. Each phase ends at one of the
+// ColdStartStatus timestamps and starts at the previous one, or at the creation of the
+// Workspace for the first phase.
+const (
+	coldStartPhaseNodeClaim  = "nodeclaim_create"
+	coldStartPhaseNodeReady  = "node_ready"
+	coldStartPhaseImagePull  = "image_pull"
+	coldStartPhaseModelLoad  = "model_load"
+	coldStartPhaseFirstToken = "first_token"
+	coldStartPhaseTotal      = "total"
+)
+
+const (
+	// firstTokenWindow is how long after the pods became Ready the first token request is
+	// retried. Servers that never answer it, e.g. with an embedding model, are not retried
+	// afterwards.
+	firstTokenWindow = 10 * time.Minute
+	// firstTokenTimeout bounds each request to the inference server, and maxFirstTokenBytes
+	// the size of its response.
+	firstTokenTimeout  = 30 * time.Second
+	maxFirstTokenBytes = 1 << 20
+)
+
+var workspaceColdStartSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "kaito_workspace_cold_start_seconds",
+		Help:    "Duration of each cold start phase of an inference Workspace, by phase, namespace, preset, runtime and instance type",
+		Buckets: prometheus.ExponentialBuckets(15, 2, 10), // 15s to about 2h
+	},
+	append([]string{"phase"}, metriclabels.Fleet...),
+)
+
+func init() {
+	metrics.Registry.MustRegister(workspaceColdStartSeconds)
+}
+
+// collectColdStart returns the cold start timestamps of wObj with any newly reached stage
+// filled in, or nil if the Workspace is not tracked. Stages already recorded are kept.
+func (c *WorkspaceReconciler) collectColdStart(ctx context.Context, wObj *kaitov1beta1.Workspace) (*kaitov1beta1.ColdStartStatus, error) {
+	if wObj.Inference == nil || !wObj.DeletionTimestamp.IsZero() {
+		return wObj.Status.ColdStart, nil
+	}
+	coldStart := &kaitov1beta1.ColdStartStatus{}
+	if wObj.Status.ColdStart != nil {
+		coldStart = wObj.Status.ColdStart.DeepCopy()
+	}
+	if coldStart.FirstTokenTime != nil ||
+		(coldStart.InferenceReadyTime != nil && time.Since(coldStart.InferenceReadyTime.Time) > firstTokenWindow) {
+		return coldStart, nil
+	}
+
+	if coldStart.NodeClaimsCreatedTime == nil && !workspace.IsNodeAutoProvisioningDisabled(&wObj.Resource) {
+		// NodeClaims are optional here: the CRD is absent with some provisioners.
+		if list, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client); err == nil {
+			for i := range list.Items {
+				t := list.Items[i].CreationTimestamp
+				if coldStart.NodeClaimsCreatedTime == nil || t.Before(coldStart.NodeClaimsCreatedTime) {
+					coldStart.NodeClaimsCreatedTime = &t
+				}
+			}
+		}
+	}
+
+	if coldStart.NodesReadyTime == nil {
+		t, err := c.nodesReadyTime(ctx, wObj)
+		if err != nil {
+			return nil, err
+		}
+		coldStart.NodesReadyTime = notBefore(t, wObj.CreationTimestamp)
+	}
+	if coldStart.NodesReadyTime == nil {
+		return coldStart, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	if coldStart.ImagePulledTime == nil {
+		coldStart.ImagePulledTime = notBefore(containersStartedTime(pods.Items, wObj.Name), *coldStart.NodesReadyTime)
+	}
+	if coldStart.ImagePulledTime != nil && coldStart.InferenceReadyTime == nil {
+		coldStart.InferenceReadyTime = notBefore(podsReadyTime(pods.Items, wObj.Status.TargetNodeCount), *coldStart.ImagePulledTime)
+	}
+	// Only vLLM serves the completions API that works for base and instruct models alike.
+	if coldStart.InferenceReadyTime != nil && kaitov1beta1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameVLLM {
+		coldStart.FirstTokenTime = c.firstTokenTime(ctx, wObj, pods.Items)
+	}
+	return coldStart, nil
+}
+
+// firstTokenTime asks the leader inference server for a single generated token and returns
+// when it was served, or nil if the request failed.
+func (c *WorkspaceReconciler) firstTokenTime(ctx context.Context, wObj *kaitov1beta1.Workspace, pods []corev1.Pod) *metav1.Time {
+	var leader *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if index, ok := pod.Labels[appsv1.PodIndexLabel]; ok && index != "0" {
+			continue
+		}
+		if pod.Status.PodIP != "" && pod.DeletionTimestamp.IsZero() {
+			leader = pod
+			break
+		}
+	}
+	if leader == nil {
+		return nil
+	}
+	if err := c.requestFirstToken(ctx, leader.Status.PodIP); err != nil {
+		klog.V(4).InfoS("First token request failed", "workspace", klog.KObj(wObj), "pod", klog.KObj(leader), "err", err)
+		return nil
+	}
+	now := metav1.Now()
+	return &now
+}
+
+// requestFirstToken looks up the model served on podIP and requests a one-token completion.
+func (c *WorkspaceReconciler) requestFirstToken(ctx context.Context, podIP string) error {
+	ctx, cancel := context.WithTimeout(ctx, firstTokenTimeout)
+	defer cancel()
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL := "http://" + net.JoinHostPort(podIP, strconv.Itoa(int(consts.PortInferenceServer)))
+	do := func(method, path string, body []byte, out any) error {
+		req, err := http.NewRequestWithContext(ctx, method, baseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s %s returned status %d", method, path, resp.StatusCode)
+		}
+		return json.NewDecoder(io.LimitReader(resp.Body, maxFirstTokenBytes)).Decode(out)
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := do(http.MethodGet, "/v1/models", nil, &models); err != nil {
+		return err
+	}
+	if len(models.Data) == 0 {
+		return fmt.Errorf("no model is served")
+	}
+	body, err := json.Marshal(map[string]any{"model": models.Data[0].ID, "prompt": "Hello", "max_tokens": 1})
+	if err != nil {
+		return err
+	}
+	var completion struct {
+		Choices []json.RawMessage `json:"choices"`
+	}
+	if err := do(http.MethodPost, "/v1/completions", body, &completion); err != nil {
+		return err
+	}
+	if len(completion.Choices) == 0 {
+		return fmt.Errorf("the completion has no choices")
+	}
+	return nil
+}
+
+// nodesReadyTime returns when the last worker node became Ready, or nil while fewer than
+// the target number of nodes are Ready.
+func (c *WorkspaceReconciler) nodesReadyTime(ctx context.Context, wObj *kaitov1beta1.Workspace) (*metav1.Time, error) {
+	if len(wObj.Status.WorkerNodes) == 0 || int32(len(wObj.Status.WorkerNodes)) < wObj.Status.TargetNodeCount {
+		return nil, nil
+	}
+	var latest *metav1.Time
+	for _, name := range wObj.Status.WorkerNodes {
+		node := &corev1.Node{}
+		if err := c.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		ready := false
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				ready = true
+				if latest == nil || latest.Before(&cond.LastTransitionTime) {
+					latest = cond.LastTransitionTime.DeepCopy()
+				}
+			}
+		}
+		if !ready {
+			return nil, nil
+		}
+	}
+	return latest, nil
+}
+
+// containersStartedTime returns when the inference container had first started on every
+// pod, or nil while it is still waiting on any of them.
+func containersStartedTime(pods []corev1.Pod, containerName string) *metav1.Time {
+	var latest *metav1.Time
+	for i := range pods {
+		var started *metav1.Time
+		for _, cs := range pods[i].Status.ContainerStatuses {
+			if cs.Name != containerName {
+				continue
+			}
+			// After a restart the first start is only kept in the last termination state.
+			if t := cs.LastTerminationState.Terminated; t != nil && !t.StartedAt.IsZero() {
+				started = t.StartedAt.DeepCopy()
+			} else if r := cs.State.Running; r != nil {
+				started = r.StartedAt.DeepCopy()
+			} else if t := cs.State.Terminated; t != nil && !t.StartedAt.IsZero() {
+				started = t.StartedAt.DeepCopy()
+			}
+		}
+		if started == nil {
+			return nil
+		}
+		if latest == nil || latest.Before(started) {
+			latest = started
+		}
+	}
+	return latest
+}
+
+// podsReadyTime returns when the last of at least minPods pods became Ready, or nil while
+// any pod is not Ready.
+func podsReadyTime(pods []corev1.Pod, minPods int32) *metav1.Time {
+	if len(pods) == 0 || int32(len(pods)) < minPods {
+		return nil
+	}
+	var latest *metav1.Time
+	for i := range pods {
+		ready := false
+		for _, cond := range pods[i].Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				ready = true
+				if latest == nil || latest.Before(&cond.LastTransitionTime) {
+					latest = cond.LastTransitionTime.DeepCopy()
+				}
+			}
+		}
+		if !ready {
+			return nil
+		}
+	}
+	return latest
+}
+
+// notBefore clamps t to floor, since nodes and pods reused from an earlier workload may
+// have become ready before this stage started.
+func notBefore(t *metav1.Time, floor metav1.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	if t.Before(&floor) {
+		return &floor
+	}
+	return t
+}
+
+// observeColdStart reports the phases that ended between the previous and current cold
+// start status. Workspaces already serving when first tracked, e.g. after a controller
+// upgrade, are not reported since their timestamps were not observed live.
+func observeColdStart(wObj *kaitov1beta1.Workspace, previous, current *kaitov1beta1.ColdStartStatus) {
+	if current == nil || (previous == nil && current.InferenceReadyTime != nil) {
+		return
+	}
+	if previous == nil {
+		previous = &kaitov1beta1.ColdStartStatus{}
+	}
+	labels := metriclabels.FleetValues(wObj)
+
+	start := wObj.CreationTimestamp
+	phases := []struct {
+		name          string
+		before, after *metav1.Time
+	}{
+		{coldStartPhaseNodeClaim, previous.NodeClaimsCreatedTime, current.NodeClaimsCreatedTime},
+		{coldStartPhaseNodeReady, previous.NodesReadyTime, current.NodesReadyTime},
+		{coldStartPhaseImagePull, previous.ImagePulledTime, current.ImagePulledTime},
+		{coldStartPhaseModelLoad, previous.InferenceReadyTime, current.InferenceReadyTime},
+		{coldStartPhaseFirstToken, previous.FirstTokenTime, current.FirstTokenTime},
+	}
+	for _, phase := range phases {
+		if phase.after == nil {
+			continue
+		}
+		if phase.before == nil {
+			seconds := phase.after.Sub(start.Time).Seconds()
+			workspaceColdStartSeconds.WithLabelValues(append([]string{phase.name}, labels...)...).Observe(max(seconds, 0))
+		}
+		start = *phase.after
+	}
+	if previous.InferenceReadyTime == nil && current.InferenceReadyTime != nil {
+		total := current.InferenceReadyTime.Sub(wObj.CreationTimestamp.Time).Seconds()
+		workspaceColdStartSeconds.WithLabelValues(append([]string{coldStartPhaseTotal}, labels...)...).Observe(total)
+		klog.InfoS("Workspace cold start completed", "workspace", klog.KObj(wObj), "seconds", total)
+	}
+}
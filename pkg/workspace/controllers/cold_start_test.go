@@ -0,0 +1,157 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestCollectColdStart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	created := v1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	at := func(minutes int) v1.Time { return v1.NewTime(created.Add(time.Duration(minutes) * time.Minute)) }
+
+	node := &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: at(5)},
+		}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "ws-0", Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "ws"}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "ws",
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: at(9)}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: at(8)}},
+			}},
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: at(20)}},
+		},
+	}
+	newWorkspace := func(status v1beta1.WorkspaceStatus) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", CreationTimestamp: created},
+			Resource:   v1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4", ProvisioningPolicy: v1beta1.ProvisioningPolicyNever},
+			Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "test-cold-start"}}},
+			Status:     status,
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("nodes not ready", func(t *testing.T) {
+		c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+		coldStart, err := c.collectColdStart(ctx, newWorkspace(v1beta1.WorkspaceStatus{}))
+		require.NoError(t, err)
+		assert.Equal(t, &v1beta1.ColdStartStatus{}, coldStart)
+	})
+
+	t.Run("all stages reached", func(t *testing.T) {
+		c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, pod).Build()}
+		wObj := newWorkspace(v1beta1.WorkspaceStatus{
+			WorkerNodes:     []string{"node-1"},
+			TargetNodeCount: 1,
+			ColdStart:       &v1beta1.ColdStartStatus{},
+		})
+		coldStart, err := c.collectColdStart(ctx, wObj)
+		require.NoError(t, err)
+		assert.True(t, coldStart.NodesReadyTime.Equal(&v1.Time{Time: at(5).Time}))
+		assert.True(t, coldStart.ImagePulledTime.Equal(&v1.Time{Time: at(8).Time}))
+		assert.True(t, coldStart.InferenceReadyTime.Equal(&v1.Time{Time: at(20).Time}))
+
+		before := testutil.CollectAndCount(workspaceColdStartSeconds)
+		observeColdStart(wObj, wObj.Status.ColdStart, coldStart)
+		assert.Equal(t, before+4, testutil.CollectAndCount(workspaceColdStartSeconds))
+
+		// Once recorded, timestamps are kept as is.
+		wObj.Status.ColdStart = coldStart
+		again, err := c.collectColdStart(ctx, wObj)
+		require.NoError(t, err)
+		assert.Equal(t, coldStart, again)
+	})
+
+	t.Run("first token served", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/models":
+				_, _ = w.Write([]byte(`{"data":[{"id":"test-model"}]}`))
+			case "/v1/completions":
+				var req map[string]any
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "test-model", req["model"])
+				assert.EqualValues(t, 1, req["max_tokens"])
+				_, _ = w.Write([]byte(`{"choices":[{"text":" world"}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+		httpClient := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+			},
+		}}
+
+		servingPod := pod.DeepCopy()
+		servingPod.Status.PodIP = "10.0.0.1"
+		c := &WorkspaceReconciler{
+			Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, servingPod).Build(),
+			httpClient: httpClient,
+		}
+		wObj := newWorkspace(v1beta1.WorkspaceStatus{
+			WorkerNodes:     []string{"node-1"},
+			TargetNodeCount: 1,
+			ColdStart:       &v1beta1.ColdStartStatus{},
+		})
+		wObj.Inference.Preset.Name = "test-cold-start-first-token"
+		coldStart, err := c.collectColdStart(ctx, wObj)
+		require.NoError(t, err)
+		require.NotNil(t, coldStart.FirstTokenTime)
+		assert.False(t, coldStart.FirstTokenTime.Before(coldStart.InferenceReadyTime))
+
+		before := testutil.CollectAndCount(workspaceColdStartSeconds)
+		observeColdStart(wObj, wObj.Status.ColdStart, coldStart)
+		assert.Equal(t, before+5, testutil.CollectAndCount(workspaceColdStartSeconds))
+	})
+
+	t.Run("already serving when first tracked", func(t *testing.T) {
+		c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, pod).Build()}
+		wObj := newWorkspace(v1beta1.WorkspaceStatus{WorkerNodes: []string{"node-1"}, TargetNodeCount: 1})
+		wObj.Inference.Preset.Name = "test-cold-start-upgraded"
+		coldStart, err := c.collectColdStart(ctx, wObj)
+		require.NoError(t, err)
+		assert.NotNil(t, coldStart.InferenceReadyTime)
+
+		before := testutil.CollectAndCount(workspaceColdStartSeconds)
+		observeColdStart(wObj, nil, coldStart)
+		assert.Equal(t, before, testutil.CollectAndCount(workspaceColdStartSeconds))
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+const (
+	// defaultNodeDiskSize matches the OS disk size NodeClaims get when the model does not
+	// declare a disk storage requirement.
+	defaultNodeDiskSize = "1024Gi"
+
+	diskReasonEphemeralStorageLimit = "EphemeralStorageLimitExceeded"
+	diskReasonNodeDiskPressure      = "NodeDiskPressure"
+	diskReasonNoSpaceLeftOnDevice   = "NoSpaceLeftOnDevice"
+)
+
+// diskPressure is evidence that an inference pod of the workspace ran out of disk.
+type diskPressure struct {
+	reason  string
+	message string
+}
+
+// collectDiskPressure looks for inference pods that were evicted for exhausting their disk
+// or whose main container failed with "no space left on device", and returns the
+// DiskTooSmall reason and a message recommending the storage size to use. It returns nil
+// when no pod ran out of disk.
+func (c *WorkspaceReconciler) collectDiskPressure(ctx context.Context, wObj *kaitov1beta1.Workspace) (*diskPressure, error) {
+	if wObj.Inference == nil {
+		return nil, nil
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	for i := range pods.Items {
+		reason, detail := podDiskPressure(&pods.Items[i], wObj.Name)
+		if reason == "" {
+			continue
+		}
+		return &diskPressure{
+			reason:  reason,
+			message: fmt.Sprintf("Pod %s ran out of disk: %s. %s", pods.Items[i].Name, detail, c.recommendStorage(ctx, wObj, reason)),
+		}, nil
+	}
+	return nil, nil
+}
+
+// podDiskPressure returns the DiskTooSmall reason and the kubelet or container message
+// when the pod ran out of disk.
+func podDiskPressure(pod *corev1.Pod, containerName string) (reason, detail string) {
+	if pod.Status.Reason == "Evicted" {
+		msg := pod.Status.Message
+		switch {
+		case strings.Contains(msg, "ephemeral local storage usage exceeds") ||
+			strings.Contains(msg, "exceeded its local ephemeral storage limit"):
+			return diskReasonEphemeralStorageLimit, strings.TrimSpace(msg)
+		case strings.Contains(msg, "low on resource: ephemeral-storage"):
+			return diskReasonNodeDiskPressure, strings.TrimSpace(msg)
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != containerName {
+			continue
+		}
+		for _, terminated := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if terminated != nil && strings.Contains(strings.ToLower(terminated.Message), "no space left on device") {
+				return diskReasonNoSpaceLeftOnDevice, fmt.Sprintf("container %s exited with \"no space left on device\"", cs.Name)
+			}
+		}
+	}
+	return "", ""
+}
+
+// recommendStorage suggests doubling the storage size that ran out.
+func (c *WorkspaceReconciler) recommendStorage(ctx context.Context, wObj *kaitov1beta1.Workspace, reason string) string {
+	storage := wObj.Resource.Storage
+	if reason == diskReasonEphemeralStorageLimit && storage != nil && storage.EphemeralStorage != nil {
+		return fmt.Sprintf("Set resource.storage.ephemeralStorage to at least %s.", doubleToGi(*storage.EphemeralStorage))
+	}
+	if workspace.IsNodeAutoProvisioningDisabled(&wObj.Resource) {
+		return "Free up disk space on the nodes or add nodes with larger disks."
+	}
+	current := c.currentNodeDiskSize(ctx, wObj)
+	return fmt.Sprintf("Set resource.storage.nodeDiskSize to at least %s (currently %s) and delete the NodeClaims of the workspace so that its nodes are replaced.",
+		doubleToGi(current), current.String())
+}
+
+// currentNodeDiskSize returns the OS disk size the workspace's NodeClaims are created with.
+func (c *WorkspaceReconciler) currentNodeDiskSize(ctx context.Context, wObj *kaitov1beta1.Workspace) resource.Quantity {
+	if storage := wObj.Resource.Storage; storage != nil && storage.NodeDiskSize != nil {
+		return *storage.NodeDiskSize
+	}
+	if preset := wObj.Inference.Preset; preset != nil && preset.Name != "" {
+		model, err := models.GetModelByName(ctx, string(preset.Name), preset.PresetOptions.ModelAccessSecret, wObj.Namespace, c.Client)
+		if err != nil {
+			klog.ErrorS(err, "failed to get model by name when recommending a disk size", "workspace", klog.KObj(wObj))
+		} else if size, err := resource.ParseQuantity(model.GetInferenceParameters().DiskStorageRequirement); err == nil {
+			return size
+		}
+	}
+	return resource.MustParse(defaultNodeDiskSize)
+}
+
+// doubleToGi returns twice the quantity, rounded up to a whole number of GiB.
+func doubleToGi(q resource.Quantity) string {
+	const gi = int64(1) << 30
+	return fmt.Sprintf("%dGi", (2*q.Value()+gi-1)/gi)
+}
+
+// applyDiskTooSmallCondition sets DiskTooSmall while there is evidence that pods ran out
+// of disk. Evicted pods are soon replaced, so the condition is only removed once the spec
+// has changed since it was set, e.g. after the storage sizes were raised.
+func applyDiskTooSmallCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, pressure *diskPressure) {
+	if pressure != nil {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               string(kaitov1beta1.WorkspaceConditionTypeDiskTooSmall),
+			Status:             metav1.ConditionTrue,
+			Reason:             pressure.reason,
+			Message:            pressure.message,
+			ObservedGeneration: wObj.GetGeneration(),
+		})
+		return
+	}
+	if cond := meta.FindStatusCondition(status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeDiskTooSmall)); cond != nil &&
+		cond.ObservedGeneration != wObj.GetGeneration() {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeDiskTooSmall))
+	}
+}
+
+// syncEphemeralStorage copies the ephemeral-storage request and limit from desired, leaving
+// the other resources of an existing container untouched.
+func syncEphemeralStorage(existing, desired *corev1.ResourceRequirements) {
+	for _, lists := range []struct{ existing, desired *corev1.ResourceList }{
+		{&existing.Requests, &desired.Requests},
+		{&existing.Limits, &desired.Limits},
+	} {
+		q, ok := (*lists.desired)[corev1.ResourceEphemeralStorage]
+		if !ok {
+			delete(*lists.existing, corev1.ResourceEphemeralStorage)
+			continue
+		}
+		if *lists.existing == nil {
+			*lists.existing = corev1.ResourceList{}
+		}
+		(*lists.existing)[corev1.ResourceEphemeralStorage] = q
+	}
+}
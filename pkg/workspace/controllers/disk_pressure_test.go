@@ -0,0 +1,184 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestPodDiskPressure(t *testing.T) {
+	terminated := func(message string) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: message}}
+	}
+	tests := []struct {
+		name         string
+		status       corev1.PodStatus
+		expectReason string
+	}{
+		{
+			name:   "healthy pod",
+			status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			name: "evicted for exceeding the ephemeral storage limit",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+				Message: "Pod ephemeral local storage usage exceeds the total limit of containers 100Gi. "},
+			expectReason: diskReasonEphemeralStorageLimit,
+		},
+		{
+			name: "evicted for node disk pressure",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+				Message: "The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi, available: 2Gi."},
+			expectReason: diskReasonNodeDiskPressure,
+		},
+		{
+			name: "evicted for memory pressure",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+				Message: "The node was low on resource: memory."},
+		},
+		{
+			name: "main container restarted after running out of space",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "ws",
+				LastTerminationState: terminated("OSError: [Errno 28] No space left on device"),
+			}}},
+			expectReason: diskReasonNoSpaceLeftOnDevice,
+		},
+		{
+			name: "sidecar ran out of space",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "sidecar",
+				State: terminated("no space left on device"),
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, detail := podDiskPressure(&corev1.Pod{Status: tt.status}, "ws")
+			assert.Equal(t, tt.expectReason, reason)
+			assert.Equal(t, tt.expectReason == "", detail == "")
+		})
+	}
+}
+
+func TestCollectDiskPressure(t *testing.T) {
+	evicted := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "ws-0", Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "ws"}},
+		Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+			Message: "Pod ephemeral local storage usage exceeds the total limit of containers 100Gi."},
+	}
+	tests := []struct {
+		name          string
+		storage       *v1beta1.WorkloadStorageSpec
+		policy        v1beta1.ProvisioningPolicy
+		expectMessage string
+	}{
+		{
+			name:          "ephemeral storage limit",
+			storage:       &v1beta1.WorkloadStorageSpec{EphemeralStorage: ptr.To(resource.MustParse("100Gi"))},
+			expectMessage: "Set resource.storage.ephemeralStorage to at least 200Gi.",
+		},
+		{
+			name:          "node disk",
+			storage:       &v1beta1.WorkloadStorageSpec{NodeDiskSize: ptr.To(resource.MustParse("300Gi"))},
+			expectMessage: "Set resource.storage.nodeDiskSize to at least 600Gi (currently 300Gi)",
+		},
+		{
+			name:          "bring your own nodes",
+			policy:        v1beta1.ProvisioningPolicyNever,
+			expectMessage: "Free up disk space on the nodes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(evicted.DeepCopy()).Build()
+			reconciler := &WorkspaceReconciler{Client: cl}
+			ws := &v1beta1.Workspace{
+				ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+				Resource:   v1beta1.ResourceSpec{Storage: tt.storage, ProvisioningPolicy: tt.policy},
+				Inference:  &v1beta1.InferenceSpec{},
+			}
+
+			pressure, err := reconciler.collectDiskPressure(context.Background(), ws)
+			require.NoError(t, err)
+			require.NotNil(t, pressure)
+			assert.Equal(t, diskReasonEphemeralStorageLimit, pressure.reason)
+			assert.Contains(t, pressure.message, "Pod ws-0 ran out of disk")
+			assert.Contains(t, pressure.message, tt.expectMessage)
+		})
+	}
+}
+
+func TestApplyDiskTooSmallCondition(t *testing.T) {
+	ws := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "ws", Generation: 1}}
+	status := &v1beta1.WorkspaceStatus{}
+	condType := string(v1beta1.WorkspaceConditionTypeDiskTooSmall)
+
+	applyDiskTooSmallCondition(status, ws, &diskPressure{reason: diskReasonNodeDiskPressure, message: "out of disk"})
+	cond := meta.FindStatusCondition(status.Conditions, condType)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, diskReasonNodeDiskPressure, cond.Reason)
+
+	// The evicted pod was replaced but the spec is unchanged: keep reporting.
+	applyDiskTooSmallCondition(status, ws, nil)
+	assert.NotNil(t, meta.FindStatusCondition(status.Conditions, condType))
+
+	// The storage sizes were raised: clear the condition.
+	ws.Generation = 2
+	applyDiskTooSmallCondition(status, ws, nil)
+	assert.Nil(t, meta.FindStatusCondition(status.Conditions, condType))
+}
+
+func TestDoubleToGi(t *testing.T) {
+	assert.Equal(t, "200Gi", doubleToGi(resource.MustParse("100Gi")))
+	assert.Equal(t, "2048Gi", doubleToGi(resource.MustParse("1Ti")))
+	assert.Equal(t, "1Gi", doubleToGi(resource.MustParse("100Mi")))
+}
+
+func TestSyncEphemeralStorage(t *testing.T) {
+	existing := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceName("nvidia.com/gpu"): resource.MustParse("1"),
+			corev1.ResourceEphemeralStorage:       resource.MustParse("50Gi"),
+		},
+	}
+	syncEphemeralStorage(existing, &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")},
+	})
+	assert.Equal(t, resource.MustParse("100Gi"), existing.Requests[corev1.ResourceEphemeralStorage])
+	assert.Equal(t, resource.MustParse("100Gi"), existing.Limits[corev1.ResourceEphemeralStorage])
+	assert.Equal(t, resource.MustParse("1"), existing.Limits[corev1.ResourceName("nvidia.com/gpu")])
+
+	syncEphemeralStorage(existing, &corev1.ResourceRequirements{})
+	assert.NotContains(t, existing.Requests, corev1.ResourceEphemeralStorage)
+	assert.NotContains(t, existing.Limits, corev1.ResourceEphemeralStorage)
+	assert.Equal(t, resource.MustParse("1"), existing.Limits[corev1.ResourceName("nvidia.com/gpu")])
+}
@@ -0,0 +1,118 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// podGroupObject returns an empty PodGroup of the given kind, to watch the PodGroups.
+func podGroupObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	pg := &unstructured.Unstructured{}
+	pg.SetGroupVersionKind(gvk)
+	return pg
+}
+
+// ensurePodGroup creates the PodGroup of a gang scheduled workspace before its workload,
+// so no pod of the group is scheduled until the scheduler can place all minMember pods.
+func (c *WorkspaceReconciler) ensurePodGroup(ctx context.Context, wObj *kaitov1beta1.Workspace, minMember int32) error {
+	desired := manifests.GeneratePodGroupManifest(wObj, minMember)
+	if desired == nil {
+		return nil
+	}
+	scheduler, _, _ := kaitov1beta1.GetGangScheduler(wObj)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	switch {
+	case meta.IsNoMatchError(err):
+		return fmt.Errorf("gang scheduler %s is not installed, PodGroup CRD %s not found: %w",
+			scheduler, desired.GroupVersionKind().GroupKind(), err)
+	case apierrors.IsNotFound(err):
+		klog.InfoS("Creating PodGroup", "workspace", klog.KObj(wObj), "scheduler", scheduler, "minMember", minMember)
+		return c.Create(ctx, desired)
+	case err != nil:
+		return fmt.Errorf("failed to get PodGroup %s: %w", desired.GetName(), err)
+	}
+
+	current, _, _ := unstructured.NestedInt64(existing.Object, "spec", "minMember")
+	if current == int64(minMember) {
+		return nil
+	}
+	if err := unstructured.SetNestedField(existing.Object, int64(minMember), "spec", "minMember"); err != nil {
+		return err
+	}
+	return c.Update(ctx, existing)
+}
+
+// gangAdmissionSnapshot is nil for workspaces that are not gang scheduled.
+type gangAdmissionSnapshot struct {
+	status  metav1.ConditionStatus
+	reason  string
+	message string
+}
+
+func (c *WorkspaceReconciler) collectGangAdmission(ctx context.Context, wObj *kaitov1beta1.Workspace) (*gangAdmissionSnapshot, error) {
+	scheduler, _, ok := kaitov1beta1.GetGangScheduler(wObj)
+	if !ok {
+		return nil, nil
+	}
+	pg := &unstructured.Unstructured{}
+	pg.SetGroupVersionKind(manifests.PodGroupGVK(scheduler))
+	if err := c.Get(ctx, client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, pg); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return &gangAdmissionSnapshot{metav1.ConditionFalse, "PodGroupNotFound",
+				fmt.Sprintf("%s PodGroup %s has not been created", scheduler, wObj.Name)}, nil
+		}
+		return nil, err
+	}
+	phase, _, _ := unstructured.NestedString(pg.Object, "status", "phase")
+	if manifests.IsPodGroupAdmitted(scheduler, phase) {
+		return &gangAdmissionSnapshot{metav1.ConditionTrue, "Admitted",
+			fmt.Sprintf("all pods of PodGroup %s were admitted by %s", wObj.Name, scheduler)}, nil
+	}
+	if phase == "" {
+		phase = "Pending"
+	}
+	minMember, _, _ := unstructured.NestedInt64(pg.Object, "spec", "minMember")
+	return &gangAdmissionSnapshot{metav1.ConditionFalse, "PodGroup" + phase,
+		fmt.Sprintf("PodGroup %s is %s, waiting for %s to admit %d pods together", wObj.Name, phase, scheduler, minMember)}, nil
+}
+
+func applyGangAdmittedCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, snapshot *gangAdmissionSnapshot) {
+	if snapshot == nil {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeGangAdmitted))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeGangAdmitted),
+		Status:             snapshot.status,
+		Reason:             snapshot.reason,
+		Message:            snapshot.message,
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestGangScheduling(t *testing.T) {
+	gvk := manifests.PodGroupGVK(v1beta1.GangSchedulerCoscheduling)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+	newReconciler := func() *WorkspaceReconciler {
+		return &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithRESTMapper(mapper).Build()}
+	}
+	ws := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{
+		Name:        "ws",
+		Namespace:   "default",
+		Annotations: map[string]string{v1beta1.AnnotationGangScheduler: v1beta1.GangSchedulerCoscheduling},
+	}}
+	ctx := context.Background()
+
+	t.Run("not gang scheduled", func(t *testing.T) {
+		c := newReconciler()
+		plain := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"}}
+		require.NoError(t, c.ensurePodGroup(ctx, plain, 2))
+		snapshot, err := c.collectGangAdmission(ctx, plain)
+		require.NoError(t, err)
+		assert.Nil(t, snapshot)
+	})
+
+	t.Run("pod group lifecycle", func(t *testing.T) {
+		c := newReconciler()
+		snapshot, err := c.collectGangAdmission(ctx, ws)
+		require.NoError(t, err)
+		assert.Equal(t, "PodGroupNotFound", snapshot.reason)
+
+		require.NoError(t, c.ensurePodGroup(ctx, ws, 2))
+		snapshot, err = c.collectGangAdmission(ctx, ws)
+		require.NoError(t, err)
+		assert.Equal(t, v1.ConditionFalse, snapshot.status)
+		assert.Equal(t, "PodGroupPending", snapshot.reason)
+
+		require.NoError(t, c.ensurePodGroup(ctx, ws, 4))
+		pg := &unstructured.Unstructured{}
+		pg.SetGroupVersionKind(gvk)
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "ws", Namespace: "default"}, pg))
+		minMember, _, _ := unstructured.NestedInt64(pg.Object, "spec", "minMember")
+		assert.Equal(t, int64(4), minMember)
+
+		require.NoError(t, unstructured.SetNestedField(pg.Object, "Scheduled", "status", "phase"))
+		require.NoError(t, c.Update(ctx, pg))
+		snapshot, err = c.collectGangAdmission(ctx, ws)
+		require.NoError(t, err)
+		assert.Equal(t, v1.ConditionTrue, snapshot.status)
+
+		status := &v1beta1.WorkspaceStatus{}
+		applyGangAdmittedCondition(status, ws, snapshot)
+		cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeGangAdmitted))
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, "Admitted", cond.Reason)
+		}
+		applyGangAdmittedCondition(status, ws, nil)
+		assert.Empty(t, status.Conditions)
+	})
+
+	t.Run("scheduler not installed", func(t *testing.T) {
+		c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return &meta.NoKindMatchError{GroupKind: gvk.GroupKind()}
+				},
+			}).Build()}
+		err := c.ensurePodGroup(ctx, ws, 2)
+		assert.ErrorContains(t, err, "gang scheduler coscheduling is not installed")
+	})
+}
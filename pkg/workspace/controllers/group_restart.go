@@ -0,0 +1,75 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// recreateGroupOnPodRestart implements the RecreateGroupOnPodRestart policy of multi-node
+// inference. The leader and workers form one Ray cluster and cannot recover from a single
+// restarted member, so once the inference container of any pod has restarted, all pods
+// of the StatefulSet are deleted and recreated together.
+func (c *WorkspaceReconciler) recreateGroupOnPodRestart(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if wObj.Inference == nil || wObj.Status.TargetNodeCount <= 1 ||
+		wObj.Inference.Distributed.GetRestartPolicy() != kaitov1beta1.DistributedRestartPolicyRecreateGroupOnPodRestart {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	var group []*corev1.Pod
+	restarted := ""
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if ref := metav1.GetControllerOf(pod); ref == nil || ref.Kind != "StatefulSet" || ref.Name != wObj.Name {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			// The group is already being recreated.
+			return nil
+		}
+		group = append(group, pod)
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == wObj.Name && cs.RestartCount > 0 {
+				restarted = pod.Name
+			}
+		}
+	}
+	if restarted == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Pod %s restarted, recreating all %d pods of the multi-node inference group", restarted, len(group))
+	klog.InfoS(msg, "workspace", klog.KObj(wObj))
+	c.recordEvent(wObj, corev1.EventTypeNormal, "RecreateGroup", msg)
+	for _, pod := range group {
+		if err := c.Delete(ctx, pod, client.Preconditions{UID: ptr.To(pod.UID)}); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestRecreateGroupOnPodRestart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	newPod := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{v1beta1.LabelWorkspaceName: "ws"},
+				OwnerReferences: []v1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "StatefulSet", Name: "ws", UID: "sts-uid", Controller: ptr.To(true),
+				}},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "ws", RestartCount: restarts}}},
+		}
+	}
+	newWorkspace := func(nodes int32, policy v1beta1.DistributedRestartPolicy) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+			Inference: &v1beta1.InferenceSpec{
+				Distributed: &v1beta1.DistributedInferenceSpec{RestartPolicy: policy},
+			},
+			Status: v1beta1.WorkspaceStatus{TargetNodeCount: nodes},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		workspace *v1beta1.Workspace
+		restarts  int32
+		remaining int
+	}{
+		{name: "no restarts", workspace: newWorkspace(2, ""), remaining: 2},
+		{name: "worker restarted", workspace: newWorkspace(2, ""), restarts: 1, remaining: 0},
+		{name: "restart policy none", workspace: newWorkspace(2, v1beta1.DistributedRestartPolicyNone), restarts: 1, remaining: 2},
+		{name: "single node", workspace: newWorkspace(1, ""), restarts: 1, remaining: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(newPod("ws-0", 0), newPod("ws-1", tt.restarts)).Build()}
+
+			require.NoError(t, c.recreateGroupOnPodRestart(context.Background(), tt.workspace))
+
+			pods := &corev1.PodList{}
+			require.NoError(t, c.List(context.Background(), pods, client.InNamespace("default")))
+			assert.Len(t, pods.Items, tt.remaining)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+// imageVerificationRequeueInterval is how often a Workspace with an unverifiable image is
+// checked again, e.g. after the signature has been published.
+const imageVerificationRequeueInterval = time.Minute
+
+// presetImages returns the preset images the workloads of wObj run: the base image, and the
+// image of the model weights for presets that do not download them at runtime.
+func (c *WorkspaceReconciler) presetImages(ctx context.Context, wObj *kaitov1beta1.Workspace) []string {
+	var preset *kaitov1beta1.PresetSpec
+	switch {
+	case wObj.Tuning != nil && wObj.Tuning.Preset != nil:
+		preset = wObj.Tuning.Preset
+	case wObj.Inference != nil && wObj.Inference.Preset != nil:
+		preset = wObj.Inference.Preset
+	default:
+		return nil
+	}
+	images := []string{inference.GetWorkspaceBaseImageName(wObj)}
+
+	model, err := models.GetModelByName(ctx, string(preset.Name), preset.PresetOptions.ModelAccessSecret, wObj.Namespace, c.Client)
+	if err != nil {
+		// Reported when the workload is rendered.
+		return images
+	}
+	params := model.GetInferenceParameters()
+	if wObj.Tuning != nil {
+		params = model.GetTuningParameters()
+	}
+	if params != nil && !params.DownloadAtRuntime {
+		images = append(images, utils.GetPresetImageName(params.Registry, params.Name, params.Tag))
+	}
+	return images
+}
+
+// imageVerificationMessage verifies the preset images of wObj and returns why one of them
+// is refused, or an empty string when all verify or verification is off. Verified images
+// are pinned to their digests when the workloads are rendered.
+func (c *WorkspaceReconciler) imageVerificationMessage(ctx context.Context, wObj *kaitov1beta1.Workspace) string {
+	verifier := imageverify.Default()
+	if verifier == nil {
+		return ""
+	}
+	creds := c.imagePullCredentials(ctx, wObj)
+	for _, image := range c.presetImages(ctx, wObj) {
+		if _, err := verifier.Verify(ctx, image, creds); err != nil {
+			return fmt.Sprintf("image %s failed verification: %v", image, err)
+		}
+	}
+	return ""
+}
+
+// applyImageVerificationCondition sets ImageVerificationFailed while message is not empty
+// and removes it once the images verify.
+func applyImageVerificationCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, message string) {
+	if message == "" {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeImageVerificationFailed))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeImageVerificationFailed),
+		Status:             metav1.ConditionTrue,
+		Reason:             "ImageVerificationFailed",
+		Message:            message,
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
+
+// imagePullCredentials returns the registry logins of the image pull secrets of wObj, so
+// images in private registries can be verified. Secrets that cannot be read are skipped
+// and the registry is read anonymously.
+func (c *WorkspaceReconciler) imagePullCredentials(ctx context.Context, wObj *kaitov1beta1.Workspace) imageverify.Credentials {
+	var names []string
+	if wObj.Tuning != nil && wObj.Tuning.Preset != nil {
+		names = append(names, wObj.Tuning.Preset.PresetOptions.ImagePullSecrets...)
+	}
+	if wObj.Inference != nil {
+		if wObj.Inference.Preset != nil {
+			names = append(names, wObj.Inference.Preset.PresetOptions.ImagePullSecrets...)
+		}
+		for _, adapter := range wObj.Inference.Adapters {
+			if adapter.Source != nil {
+				names = append(names, adapter.Source.ImagePullSecrets...)
+			}
+		}
+	}
+
+	creds := imageverify.Credentials{}
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := c.Client.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: name}, secret); err != nil {
+			klog.V(4).InfoS("Skipping image pull secret for image verification", "workspace", klog.KObj(wObj), "secret", name, "err", err)
+			continue
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			data = secret.Data[corev1.DockerConfigKey]
+		}
+		logins, err := imageverify.ParseDockerConfig(data)
+		if err != nil {
+			klog.V(4).InfoS("Skipping image pull secret for image verification", "workspace", klog.KObj(wObj), "secret", name, "err", err)
+			continue
+		}
+		creds.Merge(logins)
+	}
+	return creds
+}
@@ -0,0 +1,88 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+type bakedWeightsTestModel struct{}
+
+func (*bakedWeightsTestModel) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{Metadata: model.Metadata{Name: "baked", Registry: "myregistry.azurecr.io", Tag: "1.0"}}
+}
+func (*bakedWeightsTestModel) GetTuningParameters() *model.PresetParam { return nil }
+func (*bakedWeightsTestModel) SupportDistributedInference() bool       { return false }
+func (*bakedWeightsTestModel) SupportTuning() bool                     { return false }
+
+func TestImageVerificationMessage(t *testing.T) {
+	ctx := context.Background()
+	plugin.KaitoModelRegister.Register(&plugin.Registration{Name: "test-baked-model", Instance: &bakedWeightsTestModel{}})
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", Generation: 2},
+		Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "test-baked-model"}}},
+	}
+	r := &WorkspaceReconciler{}
+
+	base := models.MustGet("base")
+	baseImage := utils.GetPresetImageName(base.Registry, base.Name, base.Tag)
+	assert.Equal(t, []string{baseImage, "myregistry.azurecr.io/kaito-baked:1.0"}, r.presetImages(ctx, ws))
+	assert.Nil(t, r.presetImages(ctx, &v1beta1.Workspace{Inference: &v1beta1.InferenceSpec{}}))
+
+	imageverify.SetDefault(nil)
+	assert.Empty(t, r.imageVerificationMessage(ctx, ws))
+
+	// No rule matches the preset images, so they are refused without contacting a registry.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	policy, err := imageverify.ParsePolicy([]byte(fmt.Sprintf("rules:\n- images: example.com/*\n  keys: [%q]\n",
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))))
+	require.NoError(t, err)
+	imageverify.SetDefault(imageverify.NewVerifier(policy, imageverify.NewRegistry()))
+	defer imageverify.SetDefault(nil)
+	message := r.imageVerificationMessage(ctx, ws)
+	assert.Contains(t, message, "image "+baseImage+" failed verification")
+	assert.Contains(t, message, "no image verification rule matches")
+
+	status := &v1beta1.WorkspaceStatus{}
+	applyImageVerificationCondition(status, ws, message)
+	cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeImageVerificationFailed))
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+
+	applyImageVerificationCondition(status, ws, "")
+	assert.Nil(t, meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeImageVerificationFailed)))
+}
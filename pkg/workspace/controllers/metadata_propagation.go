@@ -0,0 +1,88 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// propagatedPodMetadata returns the labels and annotations the workspace propagates to its
+// pods. They are part of the revision of the workspace, so that changing a propagated
+// label of the workspace rolls out the pods.
+func propagatedPodMetadata(w *kaitov1beta1.Workspace) map[string]map[string]string {
+	return map[string]map[string]string{
+		"labels":      w.PropagatedLabels(kaitov1beta1.MetadataPropagationTargetPod),
+		"annotations": w.PropagatedAnnotations(kaitov1beta1.MetadataPropagationTargetPod),
+	}
+}
+
+// applyPropagatedMetadata makes the labels and annotations that existing got through
+// metadataPropagation match desired and reports whether existing changed. Keys recorded as
+// propagated on existing but no longer on desired are removed; other keys are left alone.
+func applyPropagatedMetadata(existing, desired *metav1.ObjectMeta) bool {
+	changed := syncPropagated(&existing.Labels, desired.Labels,
+		existing.Annotations[kaitov1beta1.AnnotationPropagatedLabels], desired.Annotations[kaitov1beta1.AnnotationPropagatedLabels])
+	changed = syncPropagated(&existing.Annotations, desired.Annotations,
+		existing.Annotations[kaitov1beta1.AnnotationPropagatedAnnotations], desired.Annotations[kaitov1beta1.AnnotationPropagatedAnnotations]) || changed
+	for _, record := range []string{kaitov1beta1.AnnotationPropagatedLabels, kaitov1beta1.AnnotationPropagatedAnnotations} {
+		changed = syncKey(&existing.Annotations, desired.Annotations, record) || changed
+	}
+	return changed
+}
+
+// syncPropagated copies the keys of wantKeys from desired to *existing and deletes the keys
+// of haveKeys that are not in wantKeys. Both key lists are comma-separated.
+func syncPropagated(existing *map[string]string, desired map[string]string, haveKeys, wantKeys string) bool {
+	want := splitKeys(wantKeys)
+	changed := false
+	for key := range splitKeys(haveKeys).Difference(want) {
+		if _, exists := (*existing)[key]; exists {
+			delete(*existing, key)
+			changed = true
+		}
+	}
+	for key := range want {
+		changed = syncKey(existing, desired, key) || changed
+	}
+	return changed
+}
+
+// syncKey makes key of *existing match desired, deleting it when desired does not set it.
+func syncKey(existing *map[string]string, desired map[string]string, key string) bool {
+	value, ok := desired[key]
+	current, exists := (*existing)[key]
+	switch {
+	case ok && (!exists || current != value):
+		if *existing == nil {
+			*existing = map[string]string{}
+		}
+		(*existing)[key] = value
+		return true
+	case !ok && exists:
+		delete(*existing, key)
+		return true
+	}
+	return false
+}
+
+func splitKeys(keys string) sets.Set[string] {
+	set := sets.New(strings.Split(keys, ",")...)
+	set.Delete("")
+	return set
+}
@@ -0,0 +1,80 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestApplyPropagatedMetadata(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Namespace:   "default",
+			Labels:      map[string]string{"cost-center": "42", "team": "search"},
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+		},
+		MetadataPropagation: &kaitov1beta1.MetadataPropagationSpec{
+			Labels:      []string{"cost-center", "team"},
+			Annotations: []string{"sidecar.istio.io/inject"},
+		},
+	}
+	existing := manifests.GenerateHeadlessServiceManifest(&kaitov1beta1.Workspace{ObjectMeta: ws.ObjectMeta})
+	existing.Labels["owner"] = "kept"
+
+	assert.True(t, applyPropagatedMetadata(&existing.ObjectMeta, &manifests.GenerateHeadlessServiceManifest(ws).ObjectMeta))
+	assert.Equal(t, "42", existing.Labels["cost-center"])
+	assert.Equal(t, "search", existing.Labels["team"])
+	assert.Equal(t, "true", existing.Annotations["sidecar.istio.io/inject"])
+	assert.False(t, applyPropagatedMetadata(&existing.ObjectMeta, &manifests.GenerateHeadlessServiceManifest(ws).ObjectMeta), "a second pass must not report changes")
+
+	// Keys no longer propagated are removed, keys set by others are kept.
+	ws.Labels["cost-center"] = "43"
+	ws.MetadataPropagation = &kaitov1beta1.MetadataPropagationSpec{Labels: []string{"cost-center"}}
+	assert.True(t, applyPropagatedMetadata(&existing.ObjectMeta, &manifests.GenerateHeadlessServiceManifest(ws).ObjectMeta))
+	assert.Equal(t, "43", existing.Labels["cost-center"])
+	assert.NotContains(t, existing.Labels, "team")
+	assert.NotContains(t, existing.Annotations, "sidecar.istio.io/inject")
+	assert.NotContains(t, existing.Annotations, kaitov1beta1.AnnotationPropagatedAnnotations)
+	assert.Equal(t, "kept", existing.Labels["owner"])
+
+	ws.MetadataPropagation = nil
+	assert.True(t, applyPropagatedMetadata(&existing.ObjectMeta, &manifests.GenerateHeadlessServiceManifest(ws).ObjectMeta))
+	assert.NotContains(t, existing.Labels, "cost-center")
+	assert.Empty(t, existing.Annotations)
+	assert.Equal(t, map[string]string{kaitov1beta1.LabelWorkspaceName: "ws", "owner": "kept"}, existing.Labels)
+}
+
+func TestComputeHashMetadataPropagation(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cost-center": "42"}}}
+	base := ComputeHash(ws)
+
+	ws.MetadataPropagation = &kaitov1beta1.MetadataPropagationSpec{Labels: []string{"cost-center"}}
+	propagated := ComputeHash(ws)
+	assert.NotEqual(t, base, propagated)
+
+	ws.Labels["cost-center"] = "43"
+	assert.NotEqual(t, propagated, ComputeHash(ws), "changing a label propagated to the pods rolls them out")
+
+	ws.MetadataPropagation.Targets = []kaitov1beta1.MetadataPropagationTarget{kaitov1beta1.MetadataPropagationTargetService}
+	withoutPods := ComputeHash(ws)
+	ws.Labels["cost-center"] = "44"
+	assert.Equal(t, withoutPods, ComputeHash(ws), "labels propagated to Services only do not roll out the pods")
+}
@@ -15,6 +15,7 @@ package controllers
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -25,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
 )
 
 var (
@@ -33,7 +35,7 @@ var (
 			Name: "kaito_workspace_count",
 			Help: "Number of Workspaces in a certain phase (succeeded, error, pending, deleting)",
 		},
-		[]string{"phase"},
+		append([]string{"phase"}, metriclabels.Fleet...),
 	)
 
 	workspacePresetCount = prometheus.NewGaugeVec(
@@ -41,7 +43,7 @@ var (
 			Name: "kaito_workspace_preset_count",
 			Help: "Number of Workspaces using each preset model, by preset name",
 		},
-		[]string{"preset_name"},
+		metriclabels.Fleet,
 	)
 
 	workspacePVCAllocatedBytes = prometheus.NewGaugeVec(
@@ -49,7 +51,7 @@ var (
 			Name: "kaito_workspace_pvc_allocated_bytes",
 			Help: "Allocated (requested) PVC storage in bytes per PVC associated with a workspace",
 		},
-		[]string{"workspace_name", "workspace_namespace", "pvc_name"},
+		append(slices.Clone(metriclabels.PerWorkspace), "pvc_name"),
 	)
 
 	workspacePVCCount = prometheus.NewGaugeVec(
@@ -57,15 +59,37 @@ var (
 			Name: "kaito_workspace_pvc_count",
 			Help: "Number of PVCs associated with each workspace",
 		},
-		[]string{"workspace_name", "workspace_namespace"},
+		metriclabels.PerWorkspace,
+	)
+
+	workspaceProvisioningErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kaito_workspace_provisioning_errors_total",
+			Help: "Number of failed node provisioning attempts of Workspaces, by reason",
+		},
+		append(slices.Clone(metriclabels.Fleet), "reason"),
+	)
+
+	workspaceReconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kaito_workspace_reconcile_errors_total",
+			Help: "Number of Workspace reconciles that returned an error",
+		},
+		metriclabels.Fleet,
 	)
 )
 
+// provisioningErrorReasonProvisionFailed is the reason of provisioning errors returned by
+// the node provisioner, as opposed to NodeClaims that timed out with a Karpenter reason.
+const provisioningErrorReasonProvisionFailed = "ProvisionNodesFailed"
+
 func init() {
 	metrics.Registry.MustRegister(workspacePhaseCount)
 	metrics.Registry.MustRegister(workspacePresetCount)
 	metrics.Registry.MustRegister(workspacePVCAllocatedBytes)
 	metrics.Registry.MustRegister(workspacePVCCount)
+	metrics.Registry.MustRegister(workspaceProvisioningErrors)
+	metrics.Registry.MustRegister(workspaceReconcileErrors)
 }
 
 func monitorWorkspaces(ctx context.Context, k8sClient client.Client) {
@@ -86,42 +110,46 @@ func monitorWorkspaces(ctx context.Context, k8sClient client.Client) {
 				continue
 			}
 
-			phaseCounts := map[string]float64{
-				"succeeded": 0,
-				"error":     0,
-				"pending":   0,
-				"deleting":  0,
-			}
-			presetCounts := map[string]float64{}
-
-			for _, ws := range wsList.Items {
-				phase := DetermineWorkspacePhase(&ws)
-				if _, ok := phaseCounts[phase]; !ok {
-					phaseCounts[phase] = 0
-				}
-				phaseCounts[phase]++
-
-				if name := getWorkspacePresetName(&ws); name != "" {
-					presetCounts[name]++
-				}
-			}
-
-			for phase, count := range phaseCounts {
-				workspacePhaseCount.WithLabelValues(phase).Set(count)
-			}
-
-			// Reset before re-setting so to remove stale keys
-			workspacePresetCount.Reset()
-			for preset, count := range presetCounts {
-				workspacePresetCount.WithLabelValues(preset).Set(count)
-			}
+			setWorkspaceCounts(wsList.Items)
+			collectPVCMetrics(ctx, k8sClient, wsList.Items)
+		}
+	}
+}
 
-			collectPVCMetrics(ctx, k8sClient)
+// setWorkspaceCounts replaces the phase and preset counts with those of workspaces. The
+// gauges are reset first so label combinations without workspaces left are dropped.
+func setWorkspaceCounts(workspaces []kaitov1beta1.Workspace) {
+	type key struct{ phase, namespace, preset, runtime, instanceType string }
+	phaseCounts := map[key]float64{}
+	presetCounts := map[key]float64{}
+	for i := range workspaces {
+		ws := &workspaces[i]
+		values := metriclabels.FleetValues(ws)
+		fleet := key{namespace: values[0], preset: values[1], runtime: values[2], instanceType: values[3]}
+		phased := fleet
+		phased.phase = DetermineWorkspacePhase(ws)
+		phaseCounts[phased]++
+		if getWorkspacePresetName(ws) != "" {
+			presetCounts[fleet]++
 		}
 	}
+
+	workspacePhaseCount.Reset()
+	for k, count := range phaseCounts {
+		workspacePhaseCount.WithLabelValues(k.phase, k.namespace, k.preset, k.runtime, k.instanceType).Set(count)
+	}
+	workspacePresetCount.Reset()
+	for k, count := range presetCounts {
+		workspacePresetCount.WithLabelValues(k.namespace, k.preset, k.runtime, k.instanceType).Set(count)
+	}
+}
+
+// observeProvisioningError counts a failed node provisioning attempt of ws.
+func observeProvisioningError(ws *kaitov1beta1.Workspace, reason string) {
+	workspaceProvisioningErrors.WithLabelValues(append(metriclabels.FleetValues(ws), reason)...).Inc()
 }
 
-func collectPVCMetrics(ctx context.Context, k8sClient client.Client) {
+func collectPVCMetrics(ctx context.Context, k8sClient client.Client, workspaces []kaitov1beta1.Workspace) {
 	var pvcList corev1.PersistentVolumeClaimList
 	if err := k8sClient.List(ctx, &pvcList, client.HasLabels{kaitov1beta1.LabelWorkspaceName}); err != nil {
 		klog.Errorf("failed to list PVCs for volume metrics: %v", err)
@@ -133,17 +161,23 @@ func collectPVCMetrics(ctx context.Context, k8sClient client.Client) {
 	workspacePVCAllocatedBytes.Reset()
 	workspacePVCCount.Reset()
 
-	pvcCounts := map[string]map[string]float64{} // namespace -> workspace name -> count
+	byKey := make(map[client.ObjectKey]*kaitov1beta1.Workspace, len(workspaces))
+	for i := range workspaces {
+		byKey[client.ObjectKeyFromObject(&workspaces[i])] = &workspaces[i]
+	}
+	labelValues := func(namespace, name string) []string {
+		if ws, ok := byKey[client.ObjectKey{Namespace: namespace, Name: name}]; ok {
+			return metriclabels.PerWorkspaceValues(ws)
+		}
+		return []string{name, namespace, metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown}
+	}
+
+	pvcCounts := map[client.ObjectKey]float64{}
 
 	for i := range pvcList.Items {
 		pvc := &pvcList.Items[i]
 		wsName := pvc.Labels[kaitov1beta1.LabelWorkspaceName]
-		wsNamespace := pvc.Namespace
-
-		if pvcCounts[wsNamespace] == nil {
-			pvcCounts[wsNamespace] = map[string]float64{}
-		}
-		pvcCounts[wsNamespace][wsName]++
+		pvcCounts[client.ObjectKey{Namespace: pvc.Namespace, Name: wsName}]++
 
 		// Prefer actual allocated capacity from status; fall back to spec request
 		var storageBytes float64
@@ -153,13 +187,11 @@ func collectPVCMetrics(ctx context.Context, k8sClient client.Client) {
 			storageBytes = float64(requested.Value())
 		}
 
-		workspacePVCAllocatedBytes.WithLabelValues(wsName, wsNamespace, pvc.Name).Set(storageBytes)
+		workspacePVCAllocatedBytes.WithLabelValues(append(labelValues(pvc.Namespace, wsName), pvc.Name)...).Set(storageBytes)
 	}
 
-	for ns, workspaces := range pvcCounts {
-		for wsName, count := range workspaces {
-			workspacePVCCount.WithLabelValues(wsName, ns).Set(count)
-		}
+	for key, count := range pvcCounts {
+		workspacePVCCount.WithLabelValues(labelValues(key.Namespace, key.Name)...).Set(count)
 	}
 }
 
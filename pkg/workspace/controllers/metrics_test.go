@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -203,11 +205,19 @@ func TestDetermineWorkspacePhase(t *testing.T) {
 func TestCollectPVCMetrics(t *testing.T) {
 	tests := []struct {
 		name       string
+		workspaces []kaitov1beta1.Workspace
 		setupMocks func(c *test.MockClient)
 		validate   func(t *testing.T)
 	}{
 		{
 			name: "PVC with workspace label and status capacity emits allocated bytes",
+			workspaces: []kaitov1beta1.Workspace{{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws1", Namespace: "default"},
+				Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+				Inference: &kaitov1beta1.InferenceSpec{
+					Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+				},
+			}},
 			setupMocks: func(c *test.MockClient) {
 				pvc := &corev1.PersistentVolumeClaim{
 					ObjectMeta: metav1.ObjectMeta{
@@ -236,10 +246,10 @@ func TestCollectPVCMetrics(t *testing.T) {
 			},
 			validate: func(t *testing.T) {
 				// 100Gi = 107374182400 bytes
-				allocatedBytes := gaugeValue(workspacePVCAllocatedBytes, "ws1", "default", "model-weights-volume-ws1-0")
+				allocatedBytes := gaugeValue(workspacePVCAllocatedBytes, "ws1", "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4", "model-weights-volume-ws1-0")
 				assert.Equal(t, float64(107374182400), allocatedBytes, "allocated bytes should be 100Gi in bytes")
 
-				pvcCount := gaugeValue(workspacePVCCount, "ws1", "default")
+				pvcCount := gaugeValue(workspacePVCCount, "ws1", "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4")
 				assert.Equal(t, float64(1), pvcCount, "PVC count should be 1")
 			},
 		},
@@ -269,10 +279,10 @@ func TestCollectPVCMetrics(t *testing.T) {
 			},
 			validate: func(t *testing.T) {
 				// 200Gi = 214748364800 bytes
-				allocatedBytes := gaugeValue(workspacePVCAllocatedBytes, "ws2", "gpu-ns", "model-weights-volume-ws2-0")
+				allocatedBytes := gaugeValue(workspacePVCAllocatedBytes, "ws2", "gpu-ns", metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown, "model-weights-volume-ws2-0")
 				assert.Equal(t, float64(214748364800), allocatedBytes, "allocated bytes should be 200Gi in bytes (from spec request fallback)")
 
-				pvcCount := gaugeValue(workspacePVCCount, "ws2", "gpu-ns")
+				pvcCount := gaugeValue(workspacePVCCount, "ws2", "gpu-ns", metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown)
 				assert.Equal(t, float64(1), pvcCount, "PVC count should be 1")
 			},
 		},
@@ -313,13 +323,13 @@ func TestCollectPVCMetrics(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PersistentVolumeClaimList{}), mock.Anything).Return(nil)
 			},
 			validate: func(t *testing.T) {
-				pvcCount := gaugeValue(workspacePVCCount, "ws3", "default")
+				pvcCount := gaugeValue(workspacePVCCount, "ws3", "default", metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown)
 				assert.Equal(t, float64(2), pvcCount, "PVC count should be 2")
 
-				allocatedBytes0 := gaugeValue(workspacePVCAllocatedBytes, "ws3", "default", "model-weights-volume-ws3-0")
+				allocatedBytes0 := gaugeValue(workspacePVCAllocatedBytes, "ws3", "default", metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown, "model-weights-volume-ws3-0")
 				assert.Equal(t, float64(107374182400), allocatedBytes0, "PVC 0 allocated bytes should be 100Gi")
 
-				allocatedBytes1 := gaugeValue(workspacePVCAllocatedBytes, "ws3", "default", "model-weights-volume-ws3-1")
+				allocatedBytes1 := gaugeValue(workspacePVCAllocatedBytes, "ws3", "default", metriclabels.Unknown, metriclabels.Unknown, metriclabels.Unknown, "model-weights-volume-ws3-1")
 				assert.Equal(t, float64(107374182400), allocatedBytes1, "PVC 1 allocated bytes should be 100Gi")
 			},
 		},
@@ -344,9 +354,56 @@ func TestCollectPVCMetrics(t *testing.T) {
 			mockClient := test.NewClient()
 			tt.setupMocks(mockClient)
 
-			collectPVCMetrics(context.Background(), mockClient)
+			collectPVCMetrics(context.Background(), mockClient, tt.workspaces)
 
 			tt.validate(t)
 		})
 	}
 }
+
+func TestSetWorkspaceCounts(t *testing.T) {
+	preset := func(name, ns, instanceType string) kaitov1beta1.Workspace {
+		return kaitov1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Resource:   kaitov1beta1.ResourceSpec{InstanceType: instanceType},
+			Inference: &kaitov1beta1.InferenceSpec{
+				Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+			},
+		}
+	}
+	custom := kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "default"},
+		Inference:  &kaitov1beta1.InferenceSpec{Template: &corev1.PodTemplateSpec{}},
+	}
+
+	setWorkspaceCounts([]kaitov1beta1.Workspace{
+		preset("a", "default", "Standard_NC24ads_A100_v4"),
+		preset("b", "default", "Standard_NC24ads_A100_v4"),
+		preset("c", "team", "Standard_NC24ads_A100_v4"),
+		custom,
+	})
+	assert.Equal(t, float64(2), gaugeValue(workspacePhaseCount, "pending", "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4"))
+	assert.Equal(t, float64(1), gaugeValue(workspacePhaseCount, "pending", "team", "phi-4", "vllm", "Standard_NC24ads_A100_v4"))
+	assert.Equal(t, float64(1), gaugeValue(workspacePhaseCount, "pending", "default", metriclabels.CustomPreset, "vllm", metriclabels.NoInstanceType))
+	assert.Equal(t, float64(2), gaugeValue(workspacePresetCount, "default", "phi-4", "vllm", "Standard_NC24ads_A100_v4"))
+	assert.Equal(t, 2, gaugeCount(workspacePresetCount), "custom workspaces have no preset count")
+
+	// Label sets whose workspaces are gone are dropped.
+	setWorkspaceCounts([]kaitov1beta1.Workspace{preset("c", "team", "Standard_NC24ads_A100_v4")})
+	assert.Equal(t, 1, gaugeCount(workspacePhaseCount))
+	assert.Equal(t, 1, gaugeCount(workspacePresetCount))
+}
+
+func TestObserveProvisioningError(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource:   kaitov1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Tuning: &kaitov1beta1.TuningSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-3-mini-128k-instruct"}},
+		},
+	}
+	counter := workspaceProvisioningErrors.WithLabelValues("default", "phi-3-mini-128k-instruct", "transformers", "Standard_NC24ads_A100_v4", provisioningErrorReasonProvisionFailed)
+	before := testutil.ToFloat64(counter)
+	observeProvisioningError(ws, provisioningErrorReasonProvisionFailed)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
@@ -0,0 +1,67 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+// recordCachedModel adds the preset of wObj to the model cache annotation of the nodes its
+// ready inference pods run on, so that later workloads of the preset prefer these nodes.
+func (c *WorkspaceReconciler) recordCachedModel(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return nil
+	}
+	model := string(wObj.Inference.Preset.Name)
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	nodeNames := sets.New[string]()
+	for i := range pods.Items {
+		if pod := &pods.Items[i]; pod.Spec.NodeName != "" && isPodReady(pod) {
+			nodeNames.Insert(pod.Spec.NodeName)
+		}
+	}
+
+	for _, name := range sets.List(nodeNames) {
+		node := &corev1.Node{}
+		if err := c.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if !utils.AddCachedModel(node, model) {
+			continue
+		}
+		if err := c.Patch(ctx, node, patch); err != nil {
+			return fmt.Errorf("failed to record cached model on node %s: %w", name, err)
+		}
+		klog.InfoS("Recorded cached model on node", "workspace", klog.KObj(wObj), "node", name, "model", model)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestRecordCachedModel(t *testing.T) {
+	ctx := context.Background()
+	pod := func(name, nodeName string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "ws"}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+	}
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-4"}}},
+	}
+	ready := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-ready", Annotations: map[string]string{consts.AnnotationNodeCachedModels: "qwen3-8b"}}}
+	starting := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-starting"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ws, ready, starting, pod("ws-0", "node-ready", corev1.ConditionTrue), pod("ws-1", "node-starting", corev1.ConditionFalse)).
+		Build()
+	r := &WorkspaceReconciler{Client: cl}
+
+	require.NoError(t, r.recordCachedModel(ctx, ws))
+
+	got := &corev1.Node{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ready), got))
+	assert.Equal(t, "qwen3-8b,phi-4", got.Annotations[consts.AnnotationNodeCachedModels])
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(starting), got))
+	assert.Empty(t, got.Annotations[consts.AnnotationNodeCachedModels])
+
+	// Recording again leaves the annotation alone.
+	require.NoError(t, r.recordCachedModel(ctx, ws))
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ready), got))
+	assert.Equal(t, "qwen3-8b,phi-4", got.Annotations[consts.AnnotationNodeCachedModels])
+}
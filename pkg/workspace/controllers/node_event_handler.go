@@ -0,0 +1,134 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// nodeChangePredicate passes node creations and deletions, and only the updates that can
+// change the placement or readiness of a workspace: labels, capacity, allocatable resources,
+// schedulability, deletion and the status of the node conditions. Kubelet heartbeats only
+// move the timestamps of the conditions and are dropped.
+var nodeChangePredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return nodeChanged(oldNode, newNode)
+	},
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+func nodeChanged(oldNode, newNode *corev1.Node) bool {
+	if !apiequality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!apiequality.Semantic.DeepEqual(oldNode.Status.Capacity, newNode.Status.Capacity) ||
+		!apiequality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) ||
+		oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+		oldNode.DeletionTimestamp.IsZero() != newNode.DeletionTimestamp.IsZero() {
+		return true
+	}
+	return !maps.Equal(nodeConditionStatuses(oldNode), nodeConditionStatuses(newNode))
+}
+
+func nodeConditionStatuses(node *corev1.Node) map[corev1.NodeConditionType]corev1.ConditionStatus {
+	statuses := make(map[corev1.NodeConditionType]corev1.ConditionStatus, len(node.Status.Conditions))
+	for _, c := range node.Status.Conditions {
+		statuses[c.Type] = c.Status
+	}
+	return statuses
+}
+
+// enqueueWorkspacesForNode returns a handler that enqueues the workspaces a node can affect:
+// the workspace a NodeClaim created the node for, workspaces that run on or prefer the node,
+// and workspaces whose label selector matches the node. For updates, both the old and the
+// new labels are matched, so a node that stops matching a selector is seen too.
+func enqueueWorkspacesForNode(kubeClient client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, o client.Object) []reconcile.Request {
+			node, ok := o.(*corev1.Node)
+			if !ok {
+				return nil
+			}
+
+			wsList := &kaitov1beta1.WorkspaceList{}
+			if err := kubeClient.List(ctx, wsList); err != nil {
+				klog.ErrorS(err, "failed to list workspaces for node watch", "node", node.Name)
+				return nil
+			}
+
+			owner := getWorkspaceKeyForNode(node)
+			var requests []reconcile.Request
+			for i := range wsList.Items {
+				ws := &wsList.Items[i]
+				key := client.ObjectKeyFromObject(ws)
+				if (owner != nil && *owner == key) || workspaceSelectsNode(ws, node) {
+					requests = append(requests, reconcile.Request{NamespacedName: key})
+				}
+			}
+			return requests
+		})
+}
+
+// getWorkspaceKeyForNode returns the workspace of a node created by a NodeClaim. The node
+// provisioners copy the NodeClaim labels to the node.
+func getWorkspaceKeyForNode(node *corev1.Node) *client.ObjectKey {
+	for _, keys := range [][2]string{
+		{kaitov1beta1.LabelWorkspaceName, kaitov1beta1.LabelWorkspaceNamespace},
+		{consts.KarpenterWorkspaceNameKey, consts.KarpenterWorkspaceNamespaceKey},
+	} {
+		name, namespace := node.Labels[keys[0]], node.Labels[keys[1]]
+		if name != "" && namespace != "" {
+			return &client.ObjectKey{Namespace: namespace, Name: name}
+		}
+	}
+	return nil
+}
+
+func workspaceSelectsNode(ws *kaitov1beta1.Workspace, node *corev1.Node) bool {
+	if slices.Contains(ws.Status.WorkerNodes, node.Name) || slices.Contains(ws.Resource.PreferredNodes, node.Name) {
+		return true
+	}
+	if ws.Resource.LabelSelector == nil {
+		return false
+	}
+	// An empty selector matches every node and would enqueue the workspace on all node
+	// changes; such workspaces still see the nodes they run on through WorkerNodes.
+	selector, err := metav1.LabelSelectorAsSelector(ws.Resource.LabelSelector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(node.Labels))
+}
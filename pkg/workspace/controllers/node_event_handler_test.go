@@ -0,0 +1,173 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestNodeChanged(t *testing.T) {
+	base := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"apps": "gpu"}},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastHeartbeatTime: metav1.Now()},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		modify   func(*corev1.Node)
+		expected bool
+	}{
+		{
+			name: "heartbeat",
+			modify: func(n *corev1.Node) {
+				n.ResourceVersion = "2"
+				n.Status.Conditions[0].LastHeartbeatTime = metav1.NewTime(time.Now().Add(time.Minute))
+			},
+		},
+		{
+			name:     "ready condition changed",
+			modify:   func(n *corev1.Node) { n.Status.Conditions[0].Status = corev1.ConditionFalse },
+			expected: true,
+		},
+		{
+			name: "condition added",
+			modify: func(n *corev1.Node) {
+				n.Status.Conditions = append(n.Status.Conditions, corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue})
+			},
+			expected: true,
+		},
+		{
+			name:     "labels changed",
+			modify:   func(n *corev1.Node) { n.Labels["apps"] = "cpu" },
+			expected: true,
+		},
+		{
+			name:     "gpu capacity reported",
+			modify:   func(n *corev1.Node) { n.Status.Capacity["nvidia.com/gpu"] = resource.MustParse("2") },
+			expected: true,
+		},
+		{
+			name:     "cordoned",
+			modify:   func(n *corev1.Node) { n.Spec.Unschedulable = true },
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base.DeepCopy()
+			tt.modify(updated)
+			assert.Equal(t, tt.expected, nodeChangePredicate.Update(event.UpdateEvent{ObjectOld: base, ObjectNew: updated}))
+		})
+	}
+	assert.True(t, nodeChangePredicate.Create(event.CreateEvent{Object: base}))
+	assert.True(t, nodeChangePredicate.Delete(event.DeleteEvent{Object: base}))
+	assert.False(t, nodeChangePredicate.Generic(event.GenericEvent{Object: base}))
+}
+
+func TestEnqueueWorkspacesForNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+
+	workspace := func(name string, resource kaitov1beta1.ResourceSpec, workerNodes ...string) *kaitov1beta1.Workspace {
+		return &kaitov1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Resource:   resource,
+			Status:     kaitov1beta1.WorkspaceStatus{WorkerNodes: workerNodes},
+		}
+	}
+	selector := func(l map[string]string) *metav1.LabelSelector { return &metav1.LabelSelector{MatchLabels: l} }
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		workspace("gpu", kaitov1beta1.ResourceSpec{LabelSelector: selector(map[string]string{"apps": "gpu"})}),
+		workspace("cpu", kaitov1beta1.ResourceSpec{LabelSelector: selector(map[string]string{"apps": "cpu"})}),
+		workspace("preferred", kaitov1beta1.ResourceSpec{LabelSelector: selector(map[string]string{"apps": "none"}), PreferredNodes: []string{"node-a"}}),
+		workspace("running", kaitov1beta1.ResourceSpec{LabelSelector: selector(map[string]string{"apps": "none"})}, "node-a"),
+		workspace("provisioned", kaitov1beta1.ResourceSpec{LabelSelector: selector(map[string]string{"apps": "none"})}),
+		workspace("empty", kaitov1beta1.ResourceSpec{LabelSelector: &metav1.LabelSelector{}}),
+	).Build()
+
+	node := func(name string, l map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: l}}
+	}
+	names := func(q workqueue.TypedRateLimitingInterface[reconcile.Request]) []string {
+		var result []string
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			result = append(result, item.Name)
+			q.Done(item)
+		}
+		sort.Strings(result)
+		return result
+	}
+	newQueue := func() workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	}
+
+	h := enqueueWorkspacesForNode(kubeClient)
+	ctx := context.Background()
+
+	t.Run("label selector, preferred and worker nodes", func(t *testing.T) {
+		q := newQueue()
+		defer q.ShutDown()
+		h.Create(ctx, event.CreateEvent{Object: node("node-a", map[string]string{"apps": "gpu"})}, q)
+		assert.Equal(t, []string{"gpu", "preferred", "running"}, names(q))
+	})
+
+	t.Run("node created for a workspace", func(t *testing.T) {
+		q := newQueue()
+		defer q.ShutDown()
+		h.Create(ctx, event.CreateEvent{Object: node("node-b", map[string]string{
+			consts.KarpenterWorkspaceNameKey:      "provisioned",
+			consts.KarpenterWorkspaceNamespaceKey: "default",
+		})}, q)
+		assert.Equal(t, []string{"provisioned"}, names(q))
+	})
+
+	t.Run("old and new labels", func(t *testing.T) {
+		q := newQueue()
+		defer q.ShutDown()
+		h.Update(ctx, event.UpdateEvent{
+			ObjectOld: node("node-c", map[string]string{"apps": "gpu"}),
+			ObjectNew: node("node-c", map[string]string{"apps": "cpu"}),
+		}, q)
+		assert.Equal(t, []string{"cpu", "gpu"}, names(q))
+	})
+
+	t.Run("unrelated node", func(t *testing.T) {
+		q := newQueue()
+		defer q.ShutDown()
+		h.Delete(ctx, event.DeleteEvent{Object: node("node-d", map[string]string{"apps": "other"})}, q)
+		assert.Empty(t, names(q))
+	})
+}
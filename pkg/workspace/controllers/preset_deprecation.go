@@ -0,0 +1,59 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+// presetDeprecationMessage returns the deprecation notice of the preset of wObj, or an empty
+// string if the preset is not deprecated.
+func presetDeprecationMessage(wObj *kaitov1beta1.Workspace, now time.Time) string {
+	var preset *kaitov1beta1.PresetSpec
+	switch {
+	case wObj.Tuning != nil && wObj.Tuning.Preset != nil:
+		preset = wObj.Tuning.Preset
+	case wObj.Inference != nil && wObj.Inference.Preset != nil:
+		preset = wObj.Inference.Preset
+	default:
+		return ""
+	}
+	m, deprecated := models.DeprecatedPreset(string(preset.Name))
+	if !deprecated {
+		return ""
+	}
+	return m.DeprecationMessage(string(preset.Name), now)
+}
+
+// applyDeprecatedCondition sets Deprecated while message is not empty and removes it once
+// the workspace moves to another preset.
+func applyDeprecatedCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, message string) {
+	if message == "" {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeDeprecated))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeDeprecated),
+		Status:             metav1.ConditionTrue,
+		Reason:             "PresetDeprecated",
+		Message:            message,
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
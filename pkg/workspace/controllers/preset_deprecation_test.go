@@ -0,0 +1,51 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestPresetDeprecation(t *testing.T) {
+	now := time.Now()
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Generation: 3},
+		Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-2"}}},
+	}
+	message := presetDeprecationMessage(ws, now)
+	assert.Equal(t, "preset phi-2 is deprecated", message)
+
+	status := &v1beta1.WorkspaceStatus{}
+	applyDeprecatedCondition(status, ws, message)
+	cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeDeprecated))
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, "PresetDeprecated", cond.Reason)
+	assert.Equal(t, int64(3), cond.ObservedGeneration)
+
+	ws.Inference.Preset.Name = "phi-4"
+	assert.Empty(t, presetDeprecationMessage(ws, now))
+	applyDeprecatedCondition(status, ws, "")
+	assert.Nil(t, meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeDeprecated)))
+
+	assert.Empty(t, presetDeprecationMessage(&v1beta1.Workspace{Inference: &v1beta1.InferenceSpec{}}, now))
+}
@@ -0,0 +1,101 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+const (
+	// newWorkspacePriority is the queue priority of workspaces that have never been ready.
+	// It is above the default priority of events (0) and the low priority that
+	// controller-runtime gives to the initial list and to resyncs (handler.LowPriority).
+	newWorkspacePriority = 100
+
+	// priorityLookupTimeout bounds the cache read that decides the priority of a request.
+	// The cache only blocks until it has synced once after the manager starts.
+	priorityLookupTimeout = time.Second
+)
+
+// workspacePriorityQueue is the controller-runtime priority queue with one addition: every
+// request for a workspace that has never been ready is queued with newWorkspacePriority.
+// This covers events as well as the requeues of the reconciler, so a new workspace keeps
+// its place ahead of the periodic resyncs of steady-state workspaces until it is ready.
+type workspacePriorityQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+	reader client.Reader
+}
+
+// newWorkspacePriorityQueue returns the controller.Options.NewQueue of the workspace
+// controller. reader should be the manager cache.
+func newWorkspacePriorityQueue(reader client.Reader, log logr.Logger) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return &workspacePriorityQueue{
+			PriorityQueue: priorityqueue.New(controllerName, func(o *priorityqueue.Opts[reconcile.Request]) {
+				o.Log = log.WithValues("controller", controllerName)
+				o.RateLimiter = rateLimiter
+			}),
+			reader: reader,
+		}
+	}
+}
+
+func (q *workspacePriorityQueue) Add(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{}, item)
+}
+
+func (q *workspacePriorityQueue) AddAfter(item reconcile.Request, after time.Duration) {
+	q.AddWithOpts(priorityqueue.AddOpts{After: after}, item)
+}
+
+func (q *workspacePriorityQueue) AddRateLimited(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{RateLimited: true}, item)
+}
+
+func (q *workspacePriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...reconcile.Request) {
+	for _, item := range items {
+		opts := o
+		if ptr.Deref(opts.Priority, 0) < newWorkspacePriority && q.awaitingFirstReady(item) {
+			opts.Priority = ptr.To(newWorkspacePriority)
+		}
+		q.PriorityQueue.AddWithOpts(opts, item)
+	}
+}
+
+// awaitingFirstReady reports whether the workspace of req exists and has never been
+// ready. Workspaces that are being deleted or have failed keep the priority of the event,
+// so that a failing workspace cannot starve the others.
+func (q *workspacePriorityQueue) awaitingFirstReady(req reconcile.Request) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), priorityLookupTimeout)
+	defer cancel()
+	wObj := &kaitov1beta1.Workspace{}
+	if err := q.reader.Get(ctx, req.NamespacedName, wObj); err != nil {
+		return false
+	}
+	if !wObj.DeletionTimestamp.IsZero() || wObj.Status.Phase == kaitov1beta1.PhaseFailed {
+		return false
+	}
+	return !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeSucceeded))
+}
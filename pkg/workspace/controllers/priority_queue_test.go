@@ -0,0 +1,96 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestWorkspacePriorityQueue(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kaitov1beta1.AddToScheme(scheme))
+
+	succeeded := metav1.Condition{Type: string(kaitov1beta1.WorkspaceConditionTypeSucceeded), Status: metav1.ConditionTrue, Reason: "workspaceSucceeded"}
+	notSucceeded := metav1.Condition{Type: string(kaitov1beta1.WorkspaceConditionTypeSucceeded), Status: metav1.ConditionFalse, Reason: "workspacePending"}
+	workspace := func(name string, status kaitov1beta1.WorkspaceStatus) *kaitov1beta1.Workspace {
+		return &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Status: status}
+	}
+	deleting := workspace("deleting", kaitov1beta1.WorkspaceStatus{})
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{"kaito.sh/test"}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		workspace("new", kaitov1beta1.WorkspaceStatus{}),
+		workspace("provisioning", kaitov1beta1.WorkspaceStatus{Conditions: []metav1.Condition{notSucceeded}}),
+		workspace("ready", kaitov1beta1.WorkspaceStatus{Conditions: []metav1.Condition{succeeded}}),
+		workspace("failed", kaitov1beta1.WorkspaceStatus{Phase: kaitov1beta1.PhaseFailed, Conditions: []metav1.Condition{notSucceeded}}),
+		deleting,
+	).Build()
+
+	tests := []struct {
+		name     string
+		priority *int
+		expected int
+	}{
+		{name: "new", expected: newWorkspacePriority},
+		{name: "provisioning", priority: ptr.To(handler.LowPriority), expected: newWorkspacePriority},
+		{name: "ready", expected: 0},
+		{name: "ready", priority: ptr.To(handler.LowPriority), expected: handler.LowPriority},
+		{name: "failed", priority: ptr.To(handler.LowPriority), expected: handler.LowPriority},
+		{name: "deleting", expected: 0},
+		{name: "missing", expected: 0},
+		{name: "new", priority: ptr.To(newWorkspacePriority + 1), expected: newWorkspacePriority + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newWorkspacePriorityQueue(reader, logr.Discard())("workspace-test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: tt.name}}
+			q.(priorityqueue.PriorityQueue[reconcile.Request]).AddWithOpts(priorityqueue.AddOpts{Priority: tt.priority}, req)
+			item, priority, shutdown := q.(priorityqueue.PriorityQueue[reconcile.Request]).GetWithPriority()
+			require.False(t, shutdown)
+			assert.Equal(t, req, item)
+			assert.Equal(t, tt.expected, priority)
+		})
+	}
+
+	t.Run("new workspaces are reconciled before resyncs", func(t *testing.T) {
+		q := newWorkspacePriorityQueue(reader, logr.Discard())("workspace-test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+
+		ready := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "ready"}}
+		fresh := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "new"}}
+		q.(priorityqueue.PriorityQueue[reconcile.Request]).AddWithOpts(priorityqueue.AddOpts{Priority: ptr.To(handler.LowPriority)}, ready)
+		q.Add(fresh)
+		first, _ := q.Get()
+		assert.Equal(t, fresh, first)
+	})
+}
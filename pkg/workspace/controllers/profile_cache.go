@@ -0,0 +1,139 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+const (
+	// profileResultTag is the log line tag emitted by inference_api.py once vLLM has
+	// resolved its engine config.
+	profileResultTag = "KAITO_PROFILE_RESULT"
+
+	// profileLogLimitBytes caps how much of the leader pod log is read. The result is
+	// logged at startup, so it is read from the head of the log.
+	profileLogLimitBytes = int64(8 << 20) // 8 MiB
+)
+
+// profileResultPayload mirrors the KAITO_PROFILE_RESULT JSON emitted by inference_api.py.
+type profileResultPayload struct {
+	MaxModelLen int `json:"max_model_len"`
+}
+
+// parseProfileResult returns the max-model-len of the last KAITO_PROFILE_RESULT line:
+//
+//	KAITO_PROFILE_RESULT <RFC3339-timestamp> <JSON-payload>
+func parseProfileResult(r io.Reader) (int, error) {
+	var lastPayload string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxScanTokenSize)
+	for scanner.Scan() {
+		if p := extractTagPayload(scanner.Text(), profileResultTag); p != "" {
+			lastPayload = p
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("scanning pod logs: %w", err)
+	}
+	if lastPayload == "" {
+		return 0, fmt.Errorf("no %s line found in pod logs", profileResultTag)
+	}
+	var payload profileResultPayload
+	if err := json.Unmarshal([]byte(lastPayload), &payload); err != nil {
+		return 0, fmt.Errorf("parsing profile result JSON %q: %w", lastPayload, err)
+	}
+	if payload.MaxModelLen <= 0 {
+		return 0, fmt.Errorf("invalid max_model_len %d in profile result", payload.MaxModelLen)
+	}
+	return payload.MaxModelLen, nil
+}
+
+// recordInferenceProfile caches the max-model-len vLLM profiled for wObj, so new pods of
+// workspaces with the same model, instance type and runtime version skip profiling. It
+// reads the log of the leader pod once it is ready on the current StatefulSet revision,
+// and reads the log of each pod at most once.
+func (c *WorkspaceReconciler) recordInferenceProfile(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	key := inference.ProfileCacheKey(wObj)
+	if key == "" {
+		return nil
+	}
+	entry, err := inference.LookupProfile(ctx, c.Client, key)
+	if err != nil || entry != nil {
+		return err
+	}
+
+	ss := &appsv1.StatefulSet{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(wObj), ss); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	pod := &corev1.Pod{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Name + benchmarkPodIndexSuffix}, pod); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// A pod of an older revision may have been profiled with other settings.
+	if ss.Status.UpdateRevision == "" || pod.Labels[appsv1.StatefulSetRevisionLabel] != ss.Status.UpdateRevision || !isPodReady(pod) {
+		return nil
+	}
+	if _, loaded := c.profiledPods.LoadOrStore(pod.UID, struct{}{}); loaded {
+		return nil
+	}
+
+	limit := profileLogLimitBytes
+	stream, err := k8sclient.ClientGoClientFromContext(ctx).CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  wObj.Name,
+		LimitBytes: &limit,
+	}).Stream(ctx)
+	if err != nil {
+		c.profiledPods.Delete(pod.UID)
+		return fmt.Errorf("streaming logs for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	defer stream.Close()
+
+	maxModelLen, err := parseProfileResult(io.LimitReader(stream, profileLogLimitBytes))
+	if err != nil {
+		// Images that predate the profile result never log it; the pod is not read again.
+		klog.V(4).InfoS("No inference profile in pod logs", "workspace", klog.KObj(wObj), "pod", pod.Name, "err", err)
+		return nil
+	}
+	if err := inference.RecordProfile(ctx, c.Client, key, inference.NewProfileCacheEntry(wObj, maxModelLen)); err != nil {
+		c.profiledPods.Delete(pod.UID)
+		return fmt.Errorf("recording inference profile %q: %w", key, err)
+	}
+	klog.InfoS("Inference profile cached", "workspace", klog.KObj(wObj), "key", key, "maxModelLen", maxModelLen)
+	return nil
+}
+
+// isPodReady reports whether the pod has the Ready condition.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
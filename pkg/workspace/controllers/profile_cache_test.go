@@ -0,0 +1,137 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+func TestParseProfileResult(t *testing.T) {
+	tests := map[string]struct {
+		logs              string
+		expectErr         bool
+		expectMaxModelLen int
+	}{
+		"single result line": {
+			logs:              "INFO vllm starting\nKAITO_PROFILE_RESULT 2026-01-01T00:00:00Z {\"max_model_len\": 32768}\nINFO ready\n",
+			expectMaxModelLen: 32768,
+		},
+		"takes last of multiple result lines": {
+			logs: "KAITO_PROFILE_RESULT 2026-01-01T00:00:00Z {\"max_model_len\": 8192}\n" +
+				"KAITO_PROFILE_RESULT 2026-01-01T00:05:00Z {\"max_model_len\": 16384}\n",
+			expectMaxModelLen: 16384,
+		},
+		"tag not present": {
+			logs:      "no profile here\n",
+			expectErr: true,
+		},
+		"malformed json payload": {
+			logs:      "KAITO_PROFILE_RESULT 2026-01-01T00:00:00Z {not-json}\n",
+			expectErr: true,
+		},
+		"non-positive max model len": {
+			logs:      "KAITO_PROFILE_RESULT 2026-01-01T00:00:00Z {\"max_model_len\": 0}\n",
+			expectErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			maxModelLen, err := parseProfileResult(strings.NewReader(tc.logs))
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectMaxModelLen, maxModelLen)
+		})
+	}
+}
+
+func TestRecordInferenceProfile(t *testing.T) {
+	for _, gate := range []string{consts.FeatureFlagInferenceProfileCache, consts.FeatureFlagVLLM} {
+		original := featuregates.FeatureGates[gate]
+		featuregates.FeatureGates[gate] = true
+		t.Cleanup(func() { featuregates.FeatureGates[gate] = original })
+	}
+	t.Setenv(consts.DefaultReleaseNamespaceEnvVar, "kaito-system")
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	wObj := &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource:   v1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "test-model"}}},
+		Status:     v1beta1.WorkspaceStatus{TargetNodeCount: 1},
+	}
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Status:     appsv1.StatefulSetStatus{UpdateRevision: "ws-2"},
+	}
+	newPod := func(revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ws-0", Namespace: "default", UID: types.UID("pod-uid"),
+				Labels: map[string]string{appsv1.StatefulSetRevisionLabel: revision},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+	}
+	ctx := k8sclient.WithClientGoClient(context.Background(), kubefake.NewClientset())
+
+	tests := map[string]struct {
+		objs       []client.Object
+		expectRead bool
+	}{
+		"no pod":              {objs: []client.Object{ss}},
+		"pod not ready":       {objs: []client.Object{ss, newPod("ws-2", corev1.ConditionFalse)}},
+		"pod of old revision": {objs: []client.Object{ss, newPod("ws-1", corev1.ConditionTrue)}},
+		"ready current pod":   {objs: []client.Object{ss, newPod("ws-2", corev1.ConditionTrue)}, expectRead: true},
+		"profile cached": {
+			objs: []client.Object{ss, newPod("ws-2", corev1.ConditionTrue), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: consts.InferenceProfileCacheConfigMapName, Namespace: "kaito-system"},
+				Data:       map[string]string{inference.ProfileCacheKey(wObj): `{"maxModelLen": 4096}`},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objs...).Build()}
+			// The fake log stream has no profile result, so nothing is recorded either way.
+			assert.NoError(t, c.recordInferenceProfile(ctx, wObj))
+			_, read := c.profiledPods.Load(types.UID("pod-uid"))
+			assert.Equal(t, tc.expectRead, read)
+		})
+	}
+}
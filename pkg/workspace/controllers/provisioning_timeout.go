@@ -0,0 +1,117 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+)
+
+// applyProvisioningTimeoutCondition sets NodeClaimProvisionTimeout once the NodeClaimReady
+// condition has been False for longer than resource.provisioningTimeout, and removes it
+// otherwise. The reason and message are copied from NodeClaimReady, which carries the
+// provisioning error reported by Karpenter.
+func applyProvisioningTimeoutCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, now time.Time) {
+	timeout := wObj.Resource.ProvisioningTimeout
+	nodeClaimCond := meta.FindStatusCondition(status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimStatus))
+	if timeout == nil || nodeClaimCond == nil || nodeClaimCond.Status != metav1.ConditionFalse ||
+		now.Sub(nodeClaimCond.LastTransitionTime.Time) < timeout.Duration {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout),
+		Status:             metav1.ConditionTrue,
+		Reason:             nodeClaimCond.Reason,
+		Message:            fmt.Sprintf("NodeClaims were not ready within %s%s: %s", timeout.Duration, zonesSuffix(wObj.Resource.Zones), nodeClaimCond.Message),
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
+
+// handleProvisioningTimeout is called while the workspace's NodeClaims are not ready. Before
+// the timeout expires it requeues for the remaining time, since no watch fires when it does.
+// After expiry it emits a warning event and, if a fallback instance type is left, deletes
+// the pending nodes and records that instance type in status.activeInstanceType; the spec is
+// left untouched. The target node count and NodeClaimReady condition are reset so the
+// estimate and the timeout start over.
+func (c *WorkspaceReconciler) handleProvisioningTimeout(ctx context.Context, wObj *kaitov1beta1.Workspace) (*reconcile.Result, error) {
+	timeout := wObj.Resource.ProvisioningTimeout
+	if timeout == nil {
+		return &reconcile.Result{}, nil
+	}
+
+	timeoutCond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout))
+	if timeoutCond == nil || timeoutCond.Status != metav1.ConditionTrue {
+		remaining := timeout.Duration
+		if nodeClaimCond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimStatus)); nodeClaimCond != nil && nodeClaimCond.Status == metav1.ConditionFalse {
+			remaining = time.Until(nodeClaimCond.LastTransitionTime.Add(timeout.Duration))
+		}
+		return &reconcile.Result{RequeueAfter: max(remaining, time.Second)}, nil
+	}
+
+	current := wObj.EffectiveInstanceType()
+	next, ok := wObj.Resource.NextFallbackInstanceType(current)
+	if !ok {
+		c.recordEvent(wObj, corev1.EventTypeWarning, "NodeClaimProvisionTimeout", fmt.Sprintf("%s; no fallback instance type left", timeoutCond.Message))
+		return &reconcile.Result{}, nil
+	}
+
+	c.recordEvent(wObj, corev1.EventTypeWarning, "NodeClaimProvisionTimeout",
+		fmt.Sprintf("%s; falling back from instance type %s to %s", timeoutCond.Message, current, next))
+	klog.InfoS("Provisioning timed out, falling back to the next instance type",
+		"workspace", klog.KObj(wObj), "instanceType", current, "fallbackInstanceType", next)
+
+	if err := c.nodeProvisioner.DeleteNodes(ctx, wObj); err != nil {
+		return &reconcile.Result{}, fmt.Errorf("failed to delete nodes before instance type fallback: %w", err)
+	}
+	if err := workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
+		status.ActiveInstanceType = next
+		status.TargetNodeCount = 0
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimStatus))
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout))
+		return nil
+	}); err != nil {
+		return &reconcile.Result{}, fmt.Errorf("failed to switch workspace to fallback instance type %s: %w", next, err)
+	}
+	c.recordEvent(wObj, corev1.EventTypeNormal, "InstanceTypeFallback",
+		fmt.Sprintf("Switched instance type from %s to %s", current, next))
+	return &reconcile.Result{}, nil
+}
+
+func (c *WorkspaceReconciler) recordEvent(wObj *kaitov1beta1.Workspace, eventType, reason, message string) {
+	if c.Recorder != nil {
+		c.Recorder.Event(wObj, eventType, reason, message)
+	}
+}
+
+// zonesSuffix names the zones a workspace is pinned to, so a capacity failure shows where it
+// was looked for.
+func zonesSuffix(zones []string) string {
+	if len(zones) == 0 {
+		return ""
+	}
+	return " in zones " + strings.Join(zones, ", ")
+}
@@ -0,0 +1,220 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	byoprovisioner "github.com/kaito-project/kaito/pkg/nodeprovision/byo-provisioner"
+)
+
+// deleteRecordingProvisioner records DeleteNodes calls.
+type deleteRecordingProvisioner struct {
+	byoprovisioner.BYOProvisioner
+	deleted []string
+}
+
+func (p *deleteRecordingProvisioner) DeleteNodes(_ context.Context, ws *v1beta1.Workspace) error {
+	p.deleted = append(p.deleted, ws.Name)
+	return nil
+}
+
+func newTimeoutTestWorkspace(timeout time.Duration, fallbacks []string, conditions ...v1.Condition) *v1beta1.Workspace {
+	return &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Resource: v1beta1.ResourceSpec{
+			InstanceType:          "Standard_NC24ads_A100_v4",
+			ProvisioningTimeout:   &v1.Duration{Duration: timeout},
+			FallbackInstanceTypes: fallbacks,
+		},
+		Status: v1beta1.WorkspaceStatus{TargetNodeCount: 2, Conditions: conditions},
+	}
+}
+
+func TestApplyProvisioningTimeoutCondition(t *testing.T) {
+	now := time.Now()
+	nodeClaimNotReady := func(since time.Duration) v1.Condition {
+		return v1.Condition{
+			Type:               string(v1beta1.ConditionTypeNodeClaimStatus),
+			Status:             v1.ConditionFalse,
+			Reason:             "LaunchFailed",
+			Message:            "insufficient quota",
+			LastTransitionTime: v1.NewTime(now.Add(-since)),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		workspace     *v1beta1.Workspace
+		expectTimeout bool
+	}{
+		{
+			name:      "no timeout configured",
+			workspace: &v1beta1.Workspace{Status: v1beta1.WorkspaceStatus{Conditions: []v1.Condition{nodeClaimNotReady(time.Hour)}}},
+		},
+		{
+			name:      "timeout not expired",
+			workspace: newTimeoutTestWorkspace(time.Hour, nil, nodeClaimNotReady(time.Minute)),
+		},
+		{
+			name:          "timeout expired",
+			workspace:     newTimeoutTestWorkspace(10*time.Minute, nil, nodeClaimNotReady(time.Hour)),
+			expectTimeout: true,
+		},
+		{
+			name: "nodeclaims ready clears the condition",
+			workspace: newTimeoutTestWorkspace(10*time.Minute, nil,
+				v1.Condition{Type: string(v1beta1.ConditionTypeNodeClaimStatus), Status: v1.ConditionTrue, Reason: "NodeClaimsReady"},
+				v1.Condition{Type: string(v1beta1.ConditionTypeNodeClaimProvisionTimeout), Status: v1.ConditionTrue, Reason: "LaunchFailed"},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.workspace.Status.DeepCopy()
+			applyProvisioningTimeoutCondition(status, tt.workspace, now)
+			cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.ConditionTypeNodeClaimProvisionTimeout))
+			if !tt.expectTimeout {
+				assert.Nil(t, cond)
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, v1.ConditionTrue, cond.Status)
+			assert.Equal(t, "LaunchFailed", cond.Reason)
+			assert.Contains(t, cond.Message, "insufficient quota")
+		})
+	}
+}
+
+func TestApplyProvisioningTimeoutConditionZones(t *testing.T) {
+	now := time.Now()
+	wObj := newTimeoutTestWorkspace(10*time.Minute, nil, v1.Condition{
+		Type:               string(v1beta1.ConditionTypeNodeClaimStatus),
+		Status:             v1.ConditionFalse,
+		Reason:             "LaunchFailed",
+		Message:            "insufficient capacity",
+		LastTransitionTime: v1.NewTime(now.Add(-time.Hour)),
+	})
+	wObj.Resource.Zones = []string{"eastus-1", "eastus-2"}
+
+	status := wObj.Status.DeepCopy()
+	applyProvisioningTimeoutCondition(status, wObj, now)
+	cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.ConditionTypeNodeClaimProvisionTimeout))
+	require.NotNil(t, cond)
+	assert.Equal(t, "NodeClaims were not ready within 10m0s in zones eastus-1, eastus-2: insufficient capacity", cond.Message)
+}
+
+func TestHandleProvisioningTimeout(t *testing.T) {
+	timedOut := v1.Condition{
+		Type:    string(v1beta1.ConditionTypeNodeClaimProvisionTimeout),
+		Status:  v1.ConditionTrue,
+		Reason:  "LaunchFailed",
+		Message: "NodeClaims were not ready within 10m0s: insufficient quota",
+	}
+	nodeClaimNotReady := v1.Condition{
+		Type:               string(v1beta1.ConditionTypeNodeClaimStatus),
+		Status:             v1.ConditionFalse,
+		Reason:             "LaunchFailed",
+		LastTransitionTime: v1.NewTime(time.Now().Add(-time.Minute)),
+	}
+
+	tests := []struct {
+		name                 string
+		workspace            *v1beta1.Workspace
+		expectRequeue        bool
+		expectActive         string
+		expectDeleted        bool
+		expectEventSubstring string
+	}{
+		{
+			name:          "waits for the remaining timeout",
+			workspace:     newTimeoutTestWorkspace(10*time.Minute, []string{"Standard_NC48ads_A100_v4"}, nodeClaimNotReady),
+			expectRequeue: true,
+		},
+		{
+			name:                 "falls back to the next instance type",
+			workspace:            newTimeoutTestWorkspace(10*time.Minute, []string{"Standard_NC48ads_A100_v4"}, nodeClaimNotReady, timedOut),
+			expectActive:         "Standard_NC48ads_A100_v4",
+			expectDeleted:        true,
+			expectEventSubstring: "falling back from instance type Standard_NC24ads_A100_v4 to Standard_NC48ads_A100_v4",
+		},
+		{
+			name: "falls back from the active fallback",
+			workspace: func() *v1beta1.Workspace {
+				w := newTimeoutTestWorkspace(10*time.Minute, []string{"Standard_NC48ads_A100_v4", "Standard_NC96ads_A100_v4"}, nodeClaimNotReady, timedOut)
+				w.Status.ActiveInstanceType = "Standard_NC48ads_A100_v4"
+				return w
+			}(),
+			expectActive:         "Standard_NC96ads_A100_v4",
+			expectDeleted:        true,
+			expectEventSubstring: "falling back from instance type Standard_NC48ads_A100_v4 to Standard_NC96ads_A100_v4",
+		},
+		{
+			name:                 "no fallback left",
+			workspace:            newTimeoutTestWorkspace(10*time.Minute, nil, nodeClaimNotReady, timedOut),
+			expectEventSubstring: "no fallback instance type left",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1beta1.AddToScheme(scheme))
+			require.NoError(t, corev1.AddToScheme(scheme))
+			cl := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(tt.workspace.DeepCopy()).
+				WithStatusSubresource(&v1beta1.Workspace{}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			provisioner := &deleteRecordingProvisioner{}
+			reconciler := &WorkspaceReconciler{Client: cl, Recorder: recorder, nodeProvisioner: provisioner}
+
+			result, err := reconciler.handleProvisioningTimeout(context.Background(), tt.workspace)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expectRequeue, result.RequeueAfter > 0)
+			assert.Equal(t, tt.expectDeleted, len(provisioner.deleted) == 1)
+
+			updated := &v1beta1.Workspace{}
+			require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(tt.workspace), updated))
+			assert.Equal(t, "Standard_NC24ads_A100_v4", updated.Resource.InstanceType, "the spec must not be mutated")
+			assert.Equal(t, tt.expectActive, updated.Status.ActiveInstanceType)
+			if tt.expectDeleted {
+				assert.Zero(t, updated.Status.TargetNodeCount)
+				assert.Nil(t, meta.FindStatusCondition(updated.Status.Conditions, string(v1beta1.ConditionTypeNodeClaimStatus)))
+			}
+
+			if tt.expectEventSubstring == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.NotEmpty(t, recorder.Events)
+			assert.Contains(t, <-recorder.Events, tt.expectEventSubstring)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// maxReplicaErrorLength bounds ReplicaStatus.LastError so that crash messages do not bloat
+// the Workspace status.
+const maxReplicaErrorLength = 512
+
+// collectReplicaStatuses returns the status of each inference pod of wObj, sorted by pod
+// name, or nil for workspaces that do not run inference.
+func (c *WorkspaceReconciler) collectReplicaStatuses(ctx context.Context, wObj *kaitov1beta1.Workspace) ([]kaitov1beta1.ReplicaStatus, error) {
+	if wObj.Inference == nil {
+		return nil, nil
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+	replicas := make([]kaitov1beta1.ReplicaStatus, 0, len(pods.Items))
+	for i := range pods.Items {
+		replicas = append(replicas, replicaStatus(&pods.Items[i], wObj.Name))
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].PodName < replicas[j].PodName })
+	return replicas, nil
+}
+
+// replicaStatus summarizes pod, whose inference container is named containerName.
+func replicaStatus(pod *corev1.Pod, containerName string) kaitov1beta1.ReplicaStatus {
+	replica := kaitov1beta1.ReplicaStatus{
+		PodName:  pod.Name,
+		NodeName: pod.Spec.NodeName,
+	}
+	for _, cond := range pod.Status.Conditions {
+		switch {
+		case cond.Type == corev1.PodReady:
+			replica.Ready = cond.Status == corev1.ConditionTrue
+		case cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse:
+			replica.LastError = formatReplicaError(cond.Reason, cond.Message)
+		case cond.Type == corev1.PodResizePending && cond.Reason == corev1.PodReasonInfeasible && replica.LastError == "":
+			replica.LastError = formatReplicaError("ResizeInfeasible", cond.Message)
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		replica.LastError = formatReplicaError(pod.Status.Reason, pod.Status.Message)
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != containerName {
+			continue
+		}
+		replica.Restarts = cs.RestartCount
+		if replica.LastError != "" {
+			break
+		}
+		// A container waiting to be created is not an error; any other waiting reason
+		// (CrashLoopBackOff, ImagePullBackOff, ...) is.
+		if waiting := cs.State.Waiting; waiting != nil && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+			replica.LastError = formatReplicaError(waiting.Reason, waiting.Message)
+		} else if last := cs.LastTerminationState.Terminated; last != nil && last.ExitCode != 0 {
+			replica.LastError = formatReplicaError(fmt.Sprintf("%s (exit code %d)", last.Reason, last.ExitCode), last.Message)
+		}
+	}
+	return replica
+}
+
+// formatReplicaError joins reason and message and truncates the result to
+// maxReplicaErrorLength bytes.
+func formatReplicaError(reason, message string) string {
+	msg := strings.TrimSpace(reason)
+	if message = strings.TrimSpace(message); message != "" {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += message
+	}
+	if len(msg) > maxReplicaErrorLength {
+		msg = strings.ToValidUTF8(msg[:maxReplicaErrorLength-3], "") + "..."
+	}
+	return msg
+}
@@ -0,0 +1,176 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestReplicaStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		pod    *corev1.Pod
+		expect v1beta1.ReplicaStatus
+	}{
+		{
+			name: "ready pod",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "ws", RestartCount: 1},
+						{Name: "sidecar", RestartCount: 5},
+					},
+				},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1", Ready: true, Restarts: 1},
+		},
+		{
+			name: "unschedulable pod",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse,
+						Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu."}},
+				},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-1", LastError: "Unschedulable: 0/3 nodes are available: 3 Insufficient nvidia.com/gpu."},
+		},
+		{
+			name: "container being created",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "ws",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+				}}},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1"},
+		},
+		{
+			name: "crash looping container",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "ws",
+					RestartCount: 4,
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason: "CrashLoopBackOff", Message: "back-off 1m20s restarting failed container"}},
+				}}},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1", Restarts: 4,
+				LastError: "CrashLoopBackOff: back-off 1m20s restarting failed container"},
+		},
+		{
+			name: "restarted after a failed exit",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:                 "ws",
+					RestartCount:         1,
+					State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, Message: "CUDA out of memory"}},
+				}}},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1", Restarts: 1,
+				LastError: "Error (exit code 1): CUDA out of memory"},
+		},
+		{
+			name: "infeasible resize",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+						{Type: corev1.PodResizePending, Status: corev1.ConditionTrue, Reason: corev1.PodReasonInfeasible,
+							Message: "Node didn't have enough capacity: cpu, requested: 128000, capacity: 96000"},
+					},
+				},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1", Ready: true,
+				LastError: "ResizeInfeasible: Node didn't have enough capacity: cpu, requested: 128000, capacity: 96000"},
+		},
+		{
+			name: "evicted pod",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "ws-0"},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+				Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted",
+					Message: "The node was low on resource: ephemeral-storage."},
+			},
+			expect: v1beta1.ReplicaStatus{PodName: "ws-0", NodeName: "node-1",
+				LastError: "Evicted: The node was low on resource: ephemeral-storage."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, replicaStatus(tt.pod, "ws"))
+		})
+	}
+}
+
+func TestFormatReplicaError(t *testing.T) {
+	assert.Equal(t, "OOMKilled", formatReplicaError("OOMKilled", ""))
+	assert.Equal(t, "boom", formatReplicaError("", " boom\n"))
+	long := formatReplicaError("Error", strings.Repeat("x", 2*maxReplicaErrorLength))
+	assert.Len(t, long, maxReplicaErrorLength)
+	assert.True(t, strings.HasSuffix(long, "..."))
+}
+
+func TestCollectReplicaStatuses(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := func(name, workspace string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default",
+			Labels: map[string]string{v1beta1.LabelWorkspaceName: workspace}}}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(pod("ws-1", "ws"), pod("ws-0", "ws"), pod("other-0", "other")).
+		Build()
+	reconciler := &WorkspaceReconciler{Client: cl}
+
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Inference:  &v1beta1.InferenceSpec{},
+	}
+	replicas, err := reconciler.collectReplicaStatuses(context.Background(), ws)
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta1.ReplicaStatus{{PodName: "ws-0"}, {PodName: "ws-1"}}, replicas)
+
+	ws.Inference = nil
+	ws.Tuning = &v1beta1.TuningSpec{}
+	replicas, err = reconciler.collectReplicaStatuses(context.Background(), ws)
+	require.NoError(t, err)
+	assert.Nil(t, replicas)
+}
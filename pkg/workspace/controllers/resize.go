@@ -0,0 +1,221 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// computeResources are the requests that resource.compute sets and that can be resized in place.
+var computeResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// inPlaceResizeAnnotation is set on a StatefulSet whose rollout is held while the pods
+// resized in place are moved to its new revision. It records the partition of the rolling
+// update to restore afterwards, empty when none was set.
+const inPlaceResizeAnnotation = kaitov1beta1.KAITOPrefix + "in-place-resize"
+
+// resizeInferencePods resizes the inference container of the running pods of wObj to the
+// CPU and memory requests of desired, so that the model stays loaded. It reports false
+// when the requests must be rolled out through the StatefulSet template instead: the
+// policy is Recreate, existing does not request CPU and memory yet (adding them would
+// change the QoS class of the pods), or the cluster does not support in-place pod resize.
+//
+// The StatefulSet template is updated with the requests as well, with its rollout held by
+// holdRollout so the resized pods are not recreated.
+func (c *WorkspaceReconciler) resizeInferencePods(ctx context.Context, wObj *kaitov1beta1.Workspace, existing, desired *corev1.Container) (bool, error) {
+	compute := wObj.Resource.Compute
+	if compute == nil || compute.ResizePolicy == kaitov1beta1.ResizePolicyRecreate ||
+		!hasComputeRequests(existing) || !hasComputeRequests(desired) {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return false, fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" {
+			continue
+		}
+		resized := pod.DeepCopy()
+		if !setComputeRequests(resized, desired) {
+			continue
+		}
+		err := c.SubResource("resize").Update(ctx, resized)
+		if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+			// Servers without the resize subresource return NotFound for it as well.
+			if getErr := c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{}); getErr == nil {
+				klog.InfoS("In-place pod resize is not supported, rolling out the new requests", "workspace", klog.KObj(wObj))
+				return false, nil
+			}
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to resize pod %s: %w", pod.Name, err)
+		}
+		c.recordEvent(wObj, corev1.EventTypeNormal, "PodResized",
+			fmt.Sprintf("Resized pod %s in place to cpu=%s memory=%s", pod.Name, compute.CPU.String(), compute.Memory.String()))
+	}
+	return true, nil
+}
+
+// hasComputeRequests reports whether container requests both CPU and memory.
+func hasComputeRequests(container *corev1.Container) bool {
+	for _, name := range computeResources {
+		if _, ok := container.Resources.Requests[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// setComputeRequests copies the CPU and memory requests of desired to the container of
+// pod with the same name, and reports whether they changed.
+func setComputeRequests(pod *corev1.Pod, desired *corev1.Container) bool {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name != desired.Name {
+			continue
+		}
+		changed := false
+		for _, name := range computeResources {
+			q := desired.Resources.Requests[name]
+			if current, ok := container.Resources.Requests[name]; ok && current.Cmp(q) == 0 {
+				continue
+			}
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = corev1.ResourceList{}
+			}
+			container.Resources.Requests[name] = q.DeepCopy()
+			changed = true
+		}
+		return changed
+	}
+	return false
+}
+
+// syncComputeRequests copies the CPU and memory requests of desired to existing, removing
+// those desired does not set.
+func syncComputeRequests(existing, desired *corev1.ResourceRequirements) {
+	for _, name := range computeResources {
+		q, ok := desired.Requests[name]
+		if !ok {
+			delete(existing.Requests, name)
+			continue
+		}
+		if existing.Requests == nil {
+			existing.Requests = corev1.ResourceList{}
+		}
+		existing.Requests[name] = q
+	}
+}
+
+// onlyComputeRequestsChanged reports whether updated differs from original in nothing but
+// the CPU and memory requests of the inference container, which is the first container.
+func onlyComputeRequestsChanged(original, updated *corev1.PodTemplateSpec) bool {
+	if len(original.Spec.Containers) == 0 || len(updated.Spec.Containers) == 0 {
+		return false
+	}
+	withOriginalRequests := updated.DeepCopy()
+	syncComputeRequests(&withOriginalRequests.Spec.Containers[0].Resources, &original.Spec.Containers[0].Resources)
+	return !apiequality.Semantic.DeepEqual(original, updated) && apiequality.Semantic.DeepEqual(original, withOriginalRequests)
+}
+
+// holdRollout sets the partition of the rolling update of sts to its replicas, so the
+// StatefulSet controller records the new revision of the template without recreating any
+// pod. completeInPlaceResize then moves the resized pods to that revision and releases it.
+// StatefulSets updated on delete are left alone, since they never recreate pods.
+func holdRollout(sts *appsv1.StatefulSet) {
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return
+	}
+	if _, held := sts.Annotations[inPlaceResizeAnnotation]; held {
+		return
+	}
+	rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil {
+		rollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+	}
+	partition := ""
+	if rollingUpdate.Partition != nil {
+		partition = strconv.Itoa(int(*rollingUpdate.Partition))
+	}
+	rollingUpdate.Partition = ptr.To(ptr.Deref(sts.Spec.Replicas, 1))
+	sts.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	sts.Spec.UpdateStrategy.RollingUpdate = rollingUpdate
+	if sts.Annotations == nil {
+		sts.Annotations = map[string]string{}
+	}
+	sts.Annotations[inPlaceResizeAnnotation] = partition
+}
+
+// completeInPlaceResize finishes the rollout held by holdRollout once the StatefulSet
+// controller has recorded the new revision: pods of the previous revision that already run
+// with the new requests are labeled with the new revision, so they are kept, and the
+// partition is restored. Pods that were not resized are then recreated as usual.
+func (c *WorkspaceReconciler) completeInPlaceResize(ctx context.Context, wObj *kaitov1beta1.Workspace, sts *appsv1.StatefulSet) error {
+	partition, held := sts.Annotations[inPlaceResizeAnnotation]
+	if !held || sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" {
+		return nil
+	}
+
+	if sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+			client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+			return fmt.Errorf("failed to list pods of workspace %s: %w", wObj.Name, err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.DeletionTimestamp != nil || pod.Labels[appsv1.ControllerRevisionHashLabelKey] != sts.Status.CurrentRevision ||
+				setComputeRequests(pod.DeepCopy(), &sts.Spec.Template.Spec.Containers[0]) {
+				continue
+			}
+			patch := client.MergeFrom(pod.DeepCopy())
+			pod.Labels[appsv1.ControllerRevisionHashLabelKey] = sts.Status.UpdateRevision
+			if err := c.Patch(ctx, pod, patch); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to move pod %s to revision %s: %w", pod.Name, sts.Status.UpdateRevision, err)
+			}
+		}
+	}
+
+	sts.Spec.UpdateStrategy.RollingUpdate.Partition = nil
+	if partition != "" {
+		value, err := strconv.Atoi(partition)
+		if err != nil {
+			return fmt.Errorf("invalid %s annotation %q: %w", inPlaceResizeAnnotation, partition, err)
+		}
+		sts.Spec.UpdateStrategy.RollingUpdate.Partition = ptr.To(int32(value))
+	}
+	delete(sts.Annotations, inPlaceResizeAnnotation)
+	if err := c.Update(ctx, sts); err != nil {
+		return fmt.Errorf("failed to release the rollout of StatefulSet %s: %w", sts.Name, err)
+	}
+	klog.InfoS("Moved pods resized in place to the new StatefulSet revision", "workspace", klog.KObj(wObj), "revision", sts.Status.UpdateRevision)
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func computeContainer(cpu, memory string) *corev1.Container {
+	container := &corev1.Container{Name: "ws"}
+	if cpu != "" {
+		container.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+	return container
+}
+
+func TestResizeInferencePods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := func(name, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default",
+				Labels: map[string]string{v1beta1.LabelWorkspaceName: "ws"}},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{*computeContainer(cpu, "16Gi")}},
+		}
+	}
+	newWorkspace := func(policy v1beta1.ResizePolicy) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"},
+			Resource: v1beta1.ResourceSpec{Compute: &v1beta1.WorkloadComputeSpec{
+				CPU: resource.MustParse("8"), Memory: resource.MustParse("16Gi"), ResizePolicy: policy}},
+			Inference: &v1beta1.InferenceSpec{},
+		}
+	}
+	newReconciler := func(resizeErr error, resized *[]string) *WorkspaceReconciler {
+		cl := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(pod("ws-0", "node-1", "4"), pod("ws-1", "node-2", "8"), pod("ws-2", "", "4")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(_ context.Context, _ client.Client, subResource string, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+					assert.Equal(t, "resize", subResource)
+					if resizeErr != nil {
+						return resizeErr
+					}
+					p := obj.(*corev1.Pod)
+					assert.Equal(t, resource.MustParse("8"), p.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+					*resized = append(*resized, p.Name)
+					return nil
+				},
+			}).Build()
+		return &WorkspaceReconciler{Client: cl}
+	}
+	existing, desired := computeContainer("4", "16Gi"), computeContainer("8", "16Gi")
+
+	t.Run("resizes running pods in place", func(t *testing.T) {
+		var resized []string
+		inPlace, err := newReconciler(nil, &resized).resizeInferencePods(context.Background(), newWorkspace(v1beta1.ResizePolicyInPlace), existing, desired)
+		require.NoError(t, err)
+		assert.True(t, inPlace)
+		assert.Equal(t, []string{"ws-0"}, resized)
+	})
+
+	t.Run("recreate policy", func(t *testing.T) {
+		var resized []string
+		inPlace, err := newReconciler(nil, &resized).resizeInferencePods(context.Background(), newWorkspace(v1beta1.ResizePolicyRecreate), existing, desired)
+		require.NoError(t, err)
+		assert.False(t, inPlace)
+		assert.Empty(t, resized)
+	})
+
+	t.Run("template without requests", func(t *testing.T) {
+		var resized []string
+		inPlace, err := newReconciler(nil, &resized).resizeInferencePods(context.Background(), newWorkspace(v1beta1.ResizePolicyInPlace), computeContainer("", ""), desired)
+		require.NoError(t, err)
+		assert.False(t, inPlace)
+		assert.Empty(t, resized)
+	})
+
+	t.Run("resize subresource not supported", func(t *testing.T) {
+		var resized []string
+		notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods/resize"}, "")
+		inPlace, err := newReconciler(notFound, &resized).resizeInferencePods(context.Background(), newWorkspace(v1beta1.ResizePolicyInPlace), existing, desired)
+		require.NoError(t, err)
+		assert.False(t, inPlace)
+	})
+
+	t.Run("resize fails", func(t *testing.T) {
+		var resized []string
+		_, err := newReconciler(apierrors.NewForbidden(schema.GroupResource{Resource: "pods/resize"}, "ws-0", nil), &resized).
+			resizeInferencePods(context.Background(), newWorkspace(v1beta1.ResizePolicyInPlace), existing, desired)
+		assert.ErrorContains(t, err, "failed to resize pod ws-0")
+	})
+}
+
+func TestSyncComputeRequests(t *testing.T) {
+	existing := computeContainer("4", "16Gi").Resources
+	existing.Requests[corev1.ResourceEphemeralStorage] = resource.MustParse("100Gi")
+
+	syncComputeRequests(&existing, &computeContainer("8", "32Gi").Resources)
+	assert.Equal(t, resource.MustParse("8"), existing.Requests[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("32Gi"), existing.Requests[corev1.ResourceMemory])
+
+	syncComputeRequests(&existing, &computeContainer("", "").Resources)
+	assert.NotContains(t, existing.Requests, corev1.ResourceCPU)
+	assert.NotContains(t, existing.Requests, corev1.ResourceMemory)
+	assert.Equal(t, resource.MustParse("100Gi"), existing.Requests[corev1.ResourceEphemeralStorage])
+}
+
+func TestOnlyComputeRequestsChanged(t *testing.T) {
+	template := func(cpu, image string) *corev1.PodTemplateSpec {
+		container := computeContainer(cpu, "16Gi")
+		container.Image = image
+		return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{*container}}}
+	}
+	assert.True(t, onlyComputeRequestsChanged(template("4", "vllm:1"), template("8", "vllm:1")))
+	assert.False(t, onlyComputeRequestsChanged(template("4", "vllm:1"), template("4", "vllm:1")))
+	assert.False(t, onlyComputeRequestsChanged(template("4", "vllm:1"), template("8", "vllm:2")))
+}
+
+func TestCompleteInPlaceResize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	pod := func(name, revision, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+				v1beta1.LabelWorkspaceName:            "ws",
+				appsv1.ControllerRevisionHashLabelKey: revision,
+			}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{*computeContainer(cpu, "16Gi")}},
+		}
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{*computeContainer("4", "16Gi")}}},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, CurrentRevision: "ws-old", UpdateRevision: "ws-old"},
+	}
+	holdRollout(sts)
+	assert.Equal(t, ptr.To[int32](2), sts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	assert.Equal(t, "", sts.Annotations[inPlaceResizeAnnotation])
+
+	sts.Spec.Template.Spec.Containers[0] = *computeContainer("8", "16Gi")
+	sts.Status.UpdateRevision = "ws-new"
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(sts, pod("ws-0", "ws-old", "8"), pod("ws-1", "ws-old", "4")).Build()
+	c := &WorkspaceReconciler{Client: cl}
+	wObj := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default"}}
+
+	existing := &appsv1.StatefulSet{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(sts), existing))
+	existing.Status = sts.Status
+	require.NoError(t, c.completeInPlaceResize(context.Background(), wObj, existing))
+
+	for name, revision := range map[string]string{"ws-0": "ws-new", "ws-1": "ws-old"} {
+		p := &corev1.Pod{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, p))
+		assert.Equal(t, revision, p.Labels[appsv1.ControllerRevisionHashLabelKey], "pod %s", name)
+	}
+	updated := &appsv1.StatefulSet{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(sts), updated))
+	assert.Nil(t, updated.Spec.UpdateStrategy.RollingUpdate.Partition)
+	assert.NotContains(t, updated.Annotations, inPlaceResizeAnnotation)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
+)
+
+// applyRolloutStatus sets status.phase, status.health, status.observedGeneration and the
+// Progressing condition from the other conditions of the workspace. It runs on every status
+// update of the reconciler, after the spec of the current generation has been applied.
+func applyRolloutStatus(wObj *kaitov1beta1.Workspace) {
+	generation := wObj.GetGeneration()
+	phase, message := workspacePhase(wObj)
+	wObj.Status.Phase = phase
+	wObj.Status.ObservedGeneration = generation
+	wObj.Status.Health = rollout.Health(generation, phase, message)
+	rollout.SetProgressing(&wObj.Status.Conditions, generation, phase, message)
+}
+
+func workspacePhase(wObj *kaitov1beta1.Workspace) (kaitov1beta1.Phase, string) {
+	status := &wObj.Status
+	generation := wObj.GetGeneration()
+	if !wObj.DeletionTimestamp.IsZero() {
+		return kaitov1beta1.PhaseDeleting, "workspace is being deleted"
+	}
+	if c := rollout.CurrentCondition(status.Conditions, kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout, generation); c != nil && c.Status == metav1.ConditionTrue {
+		return kaitov1beta1.PhaseFailed, c.Message
+	}
+	if c := rollout.CurrentCondition(status.Conditions, kaitov1beta1.WorkspaceConditionTypeImageVerificationFailed, generation); c != nil && c.Status == metav1.ConditionTrue {
+		return kaitov1beta1.PhaseFailed, c.Message
+	}
+	if c := rollout.CurrentCondition(status.Conditions, kaitov1beta1.WorkspaceConditionTypeAccessGated, generation); c != nil && c.Status == metav1.ConditionTrue {
+		return kaitov1beta1.PhasePending, c.Message
+	}
+	if c := rollout.CurrentCondition(status.Conditions, kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated, generation); c != nil && c.Status == metav1.ConditionTrue {
+		return kaitov1beta1.PhasePending, c.Message
+	}
+
+	succeeded := rollout.CurrentCondition(status.Conditions, kaitov1beta1.WorkspaceConditionTypeSucceeded, generation)
+	if succeeded == nil {
+		if meta.FindStatusCondition(status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeSucceeded)) == nil {
+			return kaitov1beta1.PhasePending, "workspace has not been reconciled yet"
+		}
+		return kaitov1beta1.PhaseProgressing, "workspace is rolling out the latest spec"
+	}
+	switch {
+	case status.State == kaitov1beta1.WorkspaceStateFailed:
+		return kaitov1beta1.PhaseFailed, succeeded.Message
+	case succeeded.Status == metav1.ConditionTrue && status.State == kaitov1beta1.WorkspaceStateSucceeded:
+		return kaitov1beta1.PhaseSucceeded, succeeded.Message
+	case succeeded.Status == metav1.ConditionTrue:
+		return kaitov1beta1.PhaseReady, succeeded.Message
+	case status.State == kaitov1beta1.WorkspaceStatePending:
+		return kaitov1beta1.PhasePending, succeeded.Message
+	default:
+		return kaitov1beta1.PhaseProgressing, succeeded.Message
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/rollout"
+)
+
+func TestApplyRolloutStatus(t *testing.T) {
+	condition := func(cType kaitov1beta1.ConditionType, status metav1.ConditionStatus, generation int64) metav1.Condition {
+		return metav1.Condition{Type: string(cType), Status: status, Reason: "Test", Message: string(cType), ObservedGeneration: generation}
+	}
+	tests := []struct {
+		name       string
+		state      kaitov1beta1.WorkspaceState
+		conditions []metav1.Condition
+		deleting   bool
+		expected   kaitov1beta1.Phase
+		progress   metav1.ConditionStatus
+	}{
+		{name: "new workspace", expected: kaitov1beta1.PhasePending, progress: metav1.ConditionTrue},
+		{
+			name:       "waiting for nodes",
+			state:      kaitov1beta1.WorkspaceStatePending,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse, 2)},
+			expected:   kaitov1beta1.PhasePending,
+			progress:   metav1.ConditionTrue,
+		},
+		{
+			name:       "inference ready",
+			state:      kaitov1beta1.WorkspaceStateReady,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue, 2)},
+			expected:   kaitov1beta1.PhaseReady,
+			progress:   metav1.ConditionFalse,
+		},
+		{
+			name:       "ready for an older generation",
+			state:      kaitov1beta1.WorkspaceStateReady,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue, 1)},
+			expected:   kaitov1beta1.PhaseProgressing,
+			progress:   metav1.ConditionTrue,
+		},
+		{
+			name:       "inference not ready after a restart",
+			state:      kaitov1beta1.WorkspaceStateNotReady,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse, 2)},
+			expected:   kaitov1beta1.PhaseProgressing,
+			progress:   metav1.ConditionTrue,
+		},
+		{
+			name:       "tuning succeeded",
+			state:      kaitov1beta1.WorkspaceStateSucceeded,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue, 2)},
+			expected:   kaitov1beta1.PhaseSucceeded,
+			progress:   metav1.ConditionFalse,
+		},
+		{
+			name:       "tuning failed",
+			state:      kaitov1beta1.WorkspaceStateFailed,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse, 2)},
+			expected:   kaitov1beta1.PhaseFailed,
+			progress:   metav1.ConditionFalse,
+		},
+		{
+			name:  "provisioning timed out",
+			state: kaitov1beta1.WorkspaceStatePending,
+			conditions: []metav1.Condition{
+				condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse, 2),
+				condition(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout, metav1.ConditionTrue, 2),
+			},
+			expected: kaitov1beta1.PhaseFailed,
+			progress: metav1.ConditionFalse,
+		},
+		{
+			name:       "model access gated",
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeAccessGated, metav1.ConditionTrue, 2)},
+			expected:   kaitov1beta1.PhasePending,
+			progress:   metav1.ConditionTrue,
+		},
+		{
+			name:       "workload identity gated",
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated, metav1.ConditionTrue, 2)},
+			expected:   kaitov1beta1.PhasePending,
+			progress:   metav1.ConditionTrue,
+		},
+		{
+			name:       "image verification failed",
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeImageVerificationFailed, metav1.ConditionTrue, 2)},
+			expected:   kaitov1beta1.PhaseFailed,
+			progress:   metav1.ConditionFalse,
+		},
+		{
+			name:       "deleting",
+			state:      kaitov1beta1.WorkspaceStateReady,
+			conditions: []metav1.Condition{condition(kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue, 2)},
+			deleting:   true,
+			expected:   kaitov1beta1.PhaseDeleting,
+			progress:   metav1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wObj := &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Generation: 2},
+				Status:     kaitov1beta1.WorkspaceStatus{State: tt.state, Conditions: tt.conditions},
+			}
+			if tt.deleting {
+				wObj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			applyRolloutStatus(wObj)
+			assert.Equal(t, tt.expected, wObj.Status.Phase)
+			assert.Equal(t, int64(2), wObj.Status.ObservedGeneration)
+			if assert.NotNil(t, wObj.Status.Health) {
+				assert.Equal(t, rollout.HealthStateForPhase(tt.expected), wObj.Status.Health.State)
+				assert.Equal(t, int64(2), wObj.Status.Health.ObservedGeneration)
+			}
+			progressing := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.ConditionTypeProgressing))
+			if assert.NotNil(t, progressing) {
+				assert.Equal(t, tt.progress, progressing.Status)
+				assert.Equal(t, string(tt.expected), progressing.Reason)
+				assert.Equal(t, int64(2), progressing.ObservedGeneration)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workloadidentity"
+)
+
+// identityAnnotations are the ServiceAccount annotations the controller manages for a
+// workload identity.
+var identityAnnotations = []string{
+	workloadidentity.AnnotationAzureClientID,
+	workloadidentity.AnnotationAzureTenantID,
+	workloadidentity.AnnotationAWSRoleARN,
+}
+
+// ensureServiceAccount makes sure the ServiceAccount the workspace pods run as exists. The
+// generated ServiceAccount carries the identity annotations and, when spec.serviceAccount
+// asks for read access, a Role bound to it; without one its API token is not mounted. A
+// ServiceAccount of the same name that the workspace does not own is used as is, and a Role
+// or RoleBinding of the same name that it does not own is never taken over.
+func (c *WorkspaceReconciler) ensureServiceAccount(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if !kaitov1beta1.ServiceAccountGenerated(wObj) {
+		name := kaitov1beta1.GetServiceAccountName(wObj)
+		if err := c.Client.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: name}, &corev1.ServiceAccount{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("ServiceAccount %s/%s of the workspace does not exist", wObj.Namespace, name)
+			}
+			return fmt.Errorf("failed to get ServiceAccount %s/%s: %w", wObj.Namespace, name, err)
+		}
+		if name == wObj.Name {
+			return nil
+		}
+		// The workspace switched to an existing ServiceAccount; drop the generated one.
+		for _, obj := range []client.Object{&rbacv1.RoleBinding{}, &rbacv1.Role{}, &corev1.ServiceAccount{}} {
+			if err := c.deleteOwned(ctx, wObj, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rules := serviceAccountRules(wObj.ServiceAccount)
+	if err := c.ensureGeneratedServiceAccount(ctx, wObj, len(rules) > 0); err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		for _, obj := range []client.Object{&rbacv1.RoleBinding{}, &rbacv1.Role{}} {
+			if err := c.deleteOwned(ctx, wObj, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return c.ensureServiceAccountRole(ctx, wObj, rules)
+}
+
+// serviceAccountRules returns the read-only rules spec grants the generated ServiceAccount.
+func serviceAccountRules(spec *kaitov1beta1.ServiceAccountSpec) []rbacv1.PolicyRule {
+	if spec == nil {
+		return nil
+	}
+	var rules []rbacv1.PolicyRule
+	for _, grant := range []struct {
+		resource string
+		names    []string
+	}{{"configmaps", spec.ReadConfigMaps}, {"secrets", spec.ReadSecrets}} {
+		if len(grant.names) == 0 {
+			continue
+		}
+		names := slices.Compact(slices.Sorted(slices.Values(grant.names)))
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{grant.resource},
+			ResourceNames: names,
+			Verbs:         []string{"get", "watch"},
+		})
+	}
+	return rules
+}
+
+func (c *WorkspaceReconciler) ensureGeneratedServiceAccount(ctx context.Context, wObj *kaitov1beta1.Workspace, automountToken bool) error {
+	var annotations map[string]string
+	if wObj.Identity != nil {
+		annotations = workloadidentity.ServiceAccountAnnotations(wObj.Identity)
+	}
+	sa := &corev1.ServiceAccount{}
+	err := c.Client.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Name}, sa)
+	if apierrors.IsNotFound(err) {
+		sa = &corev1.ServiceAccount{
+			ObjectMeta:                   workspaceOwnedMeta(wObj, annotations),
+			AutomountServiceAccountToken: ptr.To(automountToken),
+		}
+		if err := controllerutil.SetControllerReference(wObj, sa, c.Client.Scheme()); err != nil {
+			return err
+		}
+		if err := c.Client.Create(ctx, sa); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create ServiceAccount %s: %w", klog.KObj(sa), err)
+		}
+		klog.InfoS("Created workspace ServiceAccount", "serviceAccount", klog.KObj(sa), "workspace", klog.KObj(wObj))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ServiceAccount %s/%s: %w", wObj.Namespace, wObj.Name, err)
+	}
+	if !metav1.IsControlledBy(sa, wObj) {
+		// A ServiceAccount named after the workspace that was created beforehand, e.g. for a
+		// workload identity, is used as is, like one set in spec.serviceAccount.name.
+		for _, key := range slices.Sorted(maps.Keys(annotations)) {
+			if sa.Annotations[key] != annotations[key] {
+				c.recordEvent(wObj, corev1.EventTypeWarning, "ServiceAccountNotManaged",
+					fmt.Sprintf("ServiceAccount %s is not owned by the workspace, so its %s annotation is not set; set it yourself", klog.KObj(sa), key))
+			}
+		}
+		klog.V(4).InfoS("Using existing ServiceAccount not owned by the workspace", "serviceAccount", klog.KObj(sa), "workspace", klog.KObj(wObj))
+		return nil
+	}
+
+	// Drop the annotations of a removed identity or of the other provider.
+	desired := maps.Clone(sa.Annotations)
+	if desired == nil {
+		desired = map[string]string{}
+	}
+	for _, key := range identityAnnotations {
+		delete(desired, key)
+	}
+	maps.Copy(desired, annotations)
+	if maps.Equal(desired, sa.Annotations) && ptr.Deref(sa.AutomountServiceAccountToken, true) == automountToken {
+		return nil
+	}
+	patch := client.MergeFrom(sa.DeepCopy())
+	sa.Annotations = desired
+	sa.AutomountServiceAccountToken = ptr.To(automountToken)
+	if err := c.Client.Patch(ctx, sa, patch); err != nil {
+		return fmt.Errorf("failed to update ServiceAccount %s: %w", klog.KObj(sa), err)
+	}
+	return nil
+}
+
+// ensureServiceAccountRole grants rules to the generated ServiceAccount through a Role and
+// RoleBinding named after the workspace.
+func (c *WorkspaceReconciler) ensureServiceAccountRole(ctx context.Context, wObj *kaitov1beta1.Workspace, rules []rbacv1.PolicyRule) error {
+	role := &rbacv1.Role{ObjectMeta: workspaceOwnedMeta(wObj, nil), Rules: rules}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: workspaceOwnedMeta(wObj, nil),
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: wObj.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: wObj.Name, Namespace: wObj.Namespace}},
+	}
+	for _, desired := range []client.Object{role, binding} {
+		if err := controllerutil.SetControllerReference(wObj, desired, c.Client.Scheme()); err != nil {
+			return err
+		}
+		existing := desired.DeepCopyObject().(client.Object)
+		err := c.Client.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+		if apierrors.IsNotFound(err) {
+			if err := c.Client.Create(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed to create %T %s: %w", desired, klog.KObj(desired), err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %T %s: %w", desired, klog.KObj(desired), err)
+		}
+		if !metav1.IsControlledBy(existing, wObj) {
+			return fmt.Errorf("%T %s already exists and is not owned by the workspace", desired, klog.KObj(desired))
+		}
+		switch existing := existing.(type) {
+		case *rbacv1.Role:
+			if equality.Semantic.DeepEqual(existing.Rules, role.Rules) {
+				continue
+			}
+			existing.Rules = role.Rules
+		case *rbacv1.RoleBinding:
+			if equality.Semantic.DeepEqual(existing.Subjects, binding.Subjects) {
+				continue
+			}
+			existing.Subjects = binding.Subjects
+		}
+		if err := c.Client.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update %T %s: %w", desired, klog.KObj(desired), err)
+		}
+	}
+	return nil
+}
+
+// deleteOwned deletes the object of obj's type named after the workspace if the workspace owns it.
+func (c *WorkspaceReconciler) deleteOwned(ctx context.Context, wObj *kaitov1beta1.Workspace, obj client.Object) error {
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Name}, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, wObj) {
+		return nil
+	}
+	if err := c.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %T %s: %w", obj, klog.KObj(obj), err)
+	}
+	return nil
+}
+
+func workspaceOwnedMeta(wObj *kaitov1beta1.Workspace, annotations map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        wObj.Name,
+		Namespace:   wObj.Namespace,
+		Labels:      map[string]string{kaitov1beta1.LabelWorkspaceName: wObj.Name},
+		Annotations: annotations,
+	}
+}
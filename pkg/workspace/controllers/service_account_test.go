@@ -0,0 +1,129 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workloadidentity"
+)
+
+func serviceAccountTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, rbacv1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestEnsureServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	wObj := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", UID: "uid"}}
+	c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(serviceAccountTestScheme(t)).Build()}
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+
+	// By default the generated ServiceAccount has no permissions and no API token.
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	sa := &corev1.ServiceAccount{}
+	require.NoError(t, c.Client.Get(ctx, key, sa))
+	assert.True(t, v1.IsControlledBy(sa, wObj))
+	assert.Equal(t, ptr.To(false), sa.AutomountServiceAccountToken)
+	assert.True(t, apierrors.IsNotFound(c.Client.Get(ctx, key, &rbacv1.Role{})))
+
+	// Read access adds a Role for exactly the named objects and mounts the token.
+	wObj.ServiceAccount = &v1beta1.ServiceAccountSpec{ReadConfigMaps: []string{"adapters", "adapters"}, ReadSecrets: []string{"hf-token"}}
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	role := &rbacv1.Role{}
+	require.NoError(t, c.Client.Get(ctx, key, role))
+	assert.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"adapters"}, Verbs: []string{"get", "watch"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"hf-token"}, Verbs: []string{"get", "watch"}},
+	}, role.Rules)
+	binding := &rbacv1.RoleBinding{}
+	require.NoError(t, c.Client.Get(ctx, key, binding))
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "ws", Namespace: "default"}}, binding.Subjects)
+	require.NoError(t, c.Client.Get(ctx, key, sa))
+	assert.Equal(t, ptr.To(true), sa.AutomountServiceAccountToken)
+
+	wObj.ServiceAccount.ReadSecrets = nil
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	require.NoError(t, c.Client.Get(ctx, key, role))
+	assert.Len(t, role.Rules, 1)
+
+	// Dropping read access removes the Role again.
+	wObj.ServiceAccount = nil
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	assert.True(t, apierrors.IsNotFound(c.Client.Get(ctx, key, &rbacv1.Role{})))
+	assert.True(t, apierrors.IsNotFound(c.Client.Get(ctx, key, &rbacv1.RoleBinding{})))
+
+	// Switching to an existing ServiceAccount requires it and drops the generated one.
+	wObj.ServiceAccount = &v1beta1.ServiceAccountSpec{Name: "inference"}
+	assert.ErrorContains(t, c.ensureServiceAccount(ctx, wObj), "does not exist")
+	require.NoError(t, c.Client.Create(ctx, &corev1.ServiceAccount{ObjectMeta: v1.ObjectMeta{Name: "inference", Namespace: "default"}}))
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	assert.True(t, apierrors.IsNotFound(c.Client.Get(ctx, key, &corev1.ServiceAccount{})))
+}
+
+func TestEnsureServiceAccountIdentity(t *testing.T) {
+	ctx := context.Background()
+	wObj := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", UID: "uid"},
+		Identity: &v1beta1.WorkloadIdentitySpec{
+			Provider: v1beta1.WorkloadIdentityProviderAzure,
+			ClientID: "00000000-0000-0000-0000-000000000001",
+			TenantID: "00000000-0000-0000-0000-000000000002",
+		},
+	}
+	c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(serviceAccountTestScheme(t)).Build()}
+
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	sa := &corev1.ServiceAccount{}
+	require.NoError(t, c.Client.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws"}, sa))
+	assert.Equal(t, wObj.Identity.ClientID, sa.Annotations[workloadidentity.AnnotationAzureClientID])
+	assert.Equal(t, wObj.Identity.TenantID, sa.Annotations[workloadidentity.AnnotationAzureTenantID])
+
+	// Switching providers replaces the annotations and keeps unrelated ones.
+	sa.Annotations["example.com/keep"] = "true"
+	require.NoError(t, c.Client.Update(ctx, sa))
+	wObj.Identity = &v1beta1.WorkloadIdentitySpec{Provider: v1beta1.WorkloadIdentityProviderAWS, RoleARN: "arn:aws:iam::123456789012:role/kaito"}
+	require.NoError(t, c.ensureServiceAccount(ctx, wObj))
+	require.NoError(t, c.Client.Get(ctx, client.ObjectKey{Namespace: "default", Name: "ws"}, sa))
+	assert.Equal(t, map[string]string{
+		workloadidentity.AnnotationAWSRoleARN: wObj.Identity.RoleARN,
+		"example.com/keep":                    "true",
+	}, sa.Annotations)
+
+	// A ServiceAccount the workspace does not own is used as is, not taken over.
+	foreign := &corev1.ServiceAccount{ObjectMeta: v1.ObjectMeta{Name: "other", Namespace: "default"}}
+	c = &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(serviceAccountTestScheme(t)).WithObjects(foreign).Build()}
+	other := wObj.DeepCopy()
+	other.Name = "other"
+	require.NoError(t, c.ensureServiceAccount(ctx, other))
+	require.NoError(t, c.Client.Get(ctx, client.ObjectKey{Namespace: "default", Name: "other"}, sa))
+	assert.Empty(t, sa.Annotations)
+	assert.Empty(t, sa.OwnerReferences)
+}
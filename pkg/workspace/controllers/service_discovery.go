@@ -0,0 +1,94 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// externalDNSAnnotations are the Service annotations managed through inference.service.dns.
+var externalDNSAnnotations = []string{kaitov1beta1.AnnotationExternalDNSHostname, kaitov1beta1.AnnotationExternalDNSTTL}
+
+// applyServiceDNS makes the ExternalDNS annotations of existing match desired and reports
+// whether existing changed. Unlike the other annotations, they are removed when desired
+// no longer sets them, so that ExternalDNS deletes the record.
+func applyServiceDNS(existing, desired *corev1.Service) bool {
+	changed := false
+	for _, key := range externalDNSAnnotations {
+		value, ok := desired.Annotations[key]
+		current, exists := existing.Annotations[key]
+		switch {
+		case ok && (!exists || current != value):
+			if existing.Annotations == nil {
+				existing.Annotations = map[string]string{}
+			}
+			existing.Annotations[key] = value
+			changed = true
+		case !ok && exists:
+			delete(existing.Annotations, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ensureServiceExport creates the ServiceExport of the inference Service when
+// inference.service.dns.export is set, and deletes the one it created once export is
+// turned off.
+func (c *WorkspaceReconciler) ensureServiceExport(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if wObj.Inference == nil || wObj.Inference.Service == nil {
+		return nil
+	}
+	desired := manifests.GenerateServiceExportManifest(wObj)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(manifests.ServiceExportGVK)
+	err := c.Get(ctx, client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, existing)
+	switch {
+	case meta.IsNoMatchError(err):
+		if desired == nil {
+			return nil
+		}
+		return fmt.Errorf("multi-cluster services are not installed, ServiceExport CRD %s not found: %w",
+			manifests.ServiceExportGVK.GroupKind(), err)
+	case apierrors.IsNotFound(err):
+		if desired == nil {
+			return nil
+		}
+		klog.InfoS("Creating ServiceExport", "workspace", klog.KObj(wObj))
+		return c.Create(ctx, desired)
+	case err != nil:
+		return fmt.Errorf("failed to get ServiceExport %s: %w", wObj.Name, err)
+	}
+
+	if desired == nil && metav1.IsControlledBy(existing, wObj) {
+		klog.InfoS("Deleting ServiceExport", "workspace", klog.KObj(wObj))
+		if err := c.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ServiceExport %s: %w", wObj.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestApplyServiceDNS(t *testing.T) {
+	existing := &corev1.Service{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{"cloud-provider": "kept"}}}
+	desired := &corev1.Service{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{
+		v1beta1.AnnotationExternalDNSHostname: "phi-4.models.example.com",
+		v1beta1.AnnotationExternalDNSTTL:      "60",
+	}}}
+
+	assert.True(t, applyServiceDNS(existing, desired))
+	assert.Equal(t, "phi-4.models.example.com", existing.Annotations[v1beta1.AnnotationExternalDNSHostname])
+	assert.Equal(t, "60", existing.Annotations[v1beta1.AnnotationExternalDNSTTL])
+	assert.False(t, applyServiceDNS(existing, desired), "a second pass must not report changes")
+
+	delete(desired.Annotations, v1beta1.AnnotationExternalDNSTTL)
+	desired.Annotations[v1beta1.AnnotationExternalDNSHostname] = "phi-4.example.com"
+	assert.True(t, applyServiceDNS(existing, desired))
+	assert.Equal(t, "phi-4.example.com", existing.Annotations[v1beta1.AnnotationExternalDNSHostname])
+	assert.NotContains(t, existing.Annotations, v1beta1.AnnotationExternalDNSTTL)
+	assert.Equal(t, "kept", existing.Annotations["cloud-provider"])
+}
+
+func TestEnsureServiceExport(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(manifests.ServiceExportGVK, meta.RESTScopeNamespace)
+	c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithRESTMapper(mapper).Build()}
+	ws := &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", UID: "ws-uid"},
+		Inference: &v1beta1.InferenceSpec{Service: &v1beta1.EndpointServiceSpec{
+			DNS: &v1beta1.ServiceDNSSpec{Export: true},
+		}},
+	}
+	ctx := context.Background()
+	getExport := func() error {
+		export := &unstructured.Unstructured{}
+		export.SetGroupVersionKind(manifests.ServiceExportGVK)
+		return c.Get(ctx, client.ObjectKey{Name: "ws", Namespace: "default"}, export)
+	}
+
+	require.NoError(t, c.ensureServiceExport(ctx, ws))
+	require.NoError(t, getExport())
+	require.NoError(t, c.ensureServiceExport(ctx, ws), "an existing export is kept")
+
+	ws.Inference.Service.DNS.Export = false
+	require.NoError(t, c.ensureServiceExport(ctx, ws))
+	assert.True(t, apierrors.IsNotFound(getExport()))
+
+	t.Run("multi-cluster services not installed", func(t *testing.T) {
+		c := &WorkspaceReconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return &meta.NoKindMatchError{GroupKind: manifests.ServiceExportGVK.GroupKind()}
+				},
+			}).Build()}
+		assert.NoError(t, c.ensureServiceExport(ctx, ws))
+
+		ws := ws.DeepCopy()
+		ws.Inference.Service.DNS.Export = true
+		assert.ErrorContains(t, c.ensureServiceExport(ctx, ws), "multi-cluster services are not installed")
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/pkg/workspace/resource"
+)
+
+// simulationEnabled reports whether the workspace asks for a provisioning plan instead of
+// nodes and workloads.
+func simulationEnabled(wObj *kaitov1beta1.Workspace) bool {
+	return wObj.Annotations[kaitov1beta1.AnnotationSimulate] == "true"
+}
+
+// simulateProvisioning computes the NodeClaims the controller would create for the workspace
+// without creating them. It uses the same node count as the NodeClaim provisioning.
+func (c *WorkspaceReconciler) simulateProvisioning(ctx context.Context, wObj *kaitov1beta1.Workspace) (*kaitov1beta1.ProvisioningPlan, error) {
+	readyNodes, err := nodeprovision.GetReadyNodes(ctx, c.Client, c.nodeProvisioner, wObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready nodes: %w", err)
+	}
+	ncList := &karpenterv1.NodeClaimList{}
+	if consts.UsesNodeClaims() && c.nodeClaimsServed() {
+		if ncList, err = nodeclaim.ListNodeClaim(ctx, wObj, c.Client); err != nil {
+			return nil, fmt.Errorf("failed to list NodeClaims: %w", err)
+		}
+	}
+
+	plan := &kaitov1beta1.ProvisioningPlan{
+		TargetNodeCount:    wObj.Status.TargetNodeCount,
+		ReadyNodes:         int32(len(readyNodes)),
+		ExistingNodeClaims: int32(len(ncList.Items)),
+	}
+	if workspace.IsNodeAutoProvisioningDisabled(&wObj.Resource) {
+		missing := max(0, plan.TargetNodeCount-plan.ReadyNodes)
+		plan.Message = fmt.Sprintf("node auto-provisioning is disabled, %d of %d nodes match the workspace", plan.ReadyNodes, plan.TargetNodeCount)
+		if missing > 0 {
+			plan.Message += fmt.Sprintf(", %d more must be added", missing)
+		}
+		return plan, nil
+	}
+
+	plan.InstanceType = wObj.EffectiveInstanceType()
+	plan.NodeClaimsToCreate = int32(max(0, resource.NumNodeClaimsNeeded(wObj, readyNodes)-len(ncList.Items)))
+	plan.Message = fmt.Sprintf("would create %d NodeClaims of instance type %s", plan.NodeClaimsToCreate, plan.InstanceType)
+	if gpuConfig, err := sku.GetGPUConfigBySKU(plan.InstanceType); err == nil && gpuConfig != nil && gpuConfig.GPUCount > 0 {
+		plan.GPUsToCreate = plan.NodeClaimsToCreate * int32(gpuConfig.GPUCount)
+		plan.Message += fmt.Sprintf(" with %d GPUs in total", plan.GPUsToCreate)
+	}
+	plan.Message += fmt.Sprintf(", %d ready nodes and %d NodeClaims exist for %d target nodes", plan.ReadyNodes, plan.ExistingNodeClaims, plan.TargetNodeCount)
+	return plan, nil
+}
+
+// applyProvisioningPlan records plan in status, keeping the generated time of an unchanged
+// plan so the status is not rewritten on every reconcile. A nil plan removes it.
+func applyProvisioningPlan(status *kaitov1beta1.WorkspaceStatus, plan *kaitov1beta1.ProvisioningPlan, now metav1.Time) {
+	if plan == nil {
+		status.ProvisioningPlan = nil
+		return
+	}
+	plan = plan.DeepCopy()
+	plan.GeneratedTime = now
+	if previous := status.ProvisioningPlan; previous != nil {
+		plan.GeneratedTime = previous.GeneratedTime
+		if *previous == *plan {
+			return
+		}
+		plan.GeneratedTime = now
+	}
+	status.ProvisioningPlan = plan
+}
@@ -0,0 +1,144 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	byoprovisioner "github.com/kaito-project/kaito/pkg/nodeprovision/byo-provisioner"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	karpenterutils "github.com/kaito-project/kaito/pkg/utils/karpenter"
+)
+
+func TestSimulateProvisioning(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	readyNode := &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: "byo-node", Labels: map[string]string{"apps": "phi-4"}},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+	}
+	existingClaim := &karpenterv1.NodeClaim{ObjectMeta: v1.ObjectMeta{Name: "ws1234", Labels: map[string]string{
+		v1beta1.LabelWorkspaceName:      "ws",
+		v1beta1.LabelWorkspaceNamespace: "default",
+	}}}
+
+	tests := []struct {
+		name               string
+		policy             v1beta1.ProvisioningPolicy
+		expectInstanceType string
+		expectToCreate     int32
+		expectGPUs         int32
+		expectMessage      string
+	}{
+		{
+			name:               "auto-provisioning",
+			expectInstanceType: "Standard_NC24ads_A100_v4",
+			expectToCreate:     2,
+			expectGPUs:         2,
+			expectMessage:      "would create 2 NodeClaims of instance type Standard_NC24ads_A100_v4 with 2 GPUs in total, 1 ready nodes and 1 NodeClaims exist for 4 target nodes",
+		},
+		{
+			name:          "auto-provisioning disabled",
+			policy:        v1beta1.ProvisioningPolicyNever,
+			expectMessage: "node auto-provisioning is disabled, 1 of 4 nodes match the workspace, 3 more must be added",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, karpenterutils.KarpenterSchemeBuilder.AddToScheme(scheme))
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(readyNode, existingClaim).Build()
+			reconciler := &WorkspaceReconciler{Client: cl, nodeProvisioner: byoprovisioner.NewBYOProvisioner(cl)}
+
+			ws := &v1beta1.Workspace{
+				ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", Annotations: map[string]string{v1beta1.AnnotationSimulate: "true"}},
+				Resource: v1beta1.ResourceSpec{
+					InstanceType:       "Standard_NC24ads_A100_v4",
+					LabelSelector:      &v1.LabelSelector{MatchLabels: map[string]string{"apps": "phi-4"}},
+					ProvisioningPolicy: tt.policy,
+				},
+				Status: v1beta1.WorkspaceStatus{TargetNodeCount: 4},
+			}
+			require.True(t, simulationEnabled(ws))
+
+			plan, err := reconciler.simulateProvisioning(context.Background(), ws)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectInstanceType, plan.InstanceType)
+			assert.Equal(t, int32(4), plan.TargetNodeCount)
+			assert.Equal(t, int32(1), plan.ReadyNodes)
+			assert.Equal(t, int32(1), plan.ExistingNodeClaims)
+			assert.Equal(t, tt.expectToCreate, plan.NodeClaimsToCreate)
+			assert.Equal(t, tt.expectGPUs, plan.GPUsToCreate)
+			assert.Equal(t, tt.expectMessage, plan.Message)
+		})
+	}
+}
+
+// provisionRecordingProvisioner records ProvisionNodes calls.
+type provisionRecordingProvisioner struct {
+	byoprovisioner.BYOProvisioner
+	provisioned []string
+}
+
+func (p *provisionRecordingProvisioner) ProvisionNodes(_ context.Context, ws *v1beta1.Workspace) error {
+	p.provisioned = append(p.provisioned, ws.Name)
+	return nil
+}
+
+func TestSimulationSkipsProvisioning(t *testing.T) {
+	provisioner := &provisionRecordingProvisioner{}
+	reconciler := &WorkspaceReconciler{expectations: utils.NewControllerExpectations(), nodeProvisioner: provisioner}
+
+	ws := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "ws", Namespace: "default", Annotations: map[string]string{v1beta1.AnnotationSimulate: "true"}}}
+	result, err := reconciler.addOrUpdateWorkspace(context.Background(), ws)
+	require.NoError(t, err)
+	assert.Zero(t, result)
+	assert.Empty(t, provisioner.provisioned)
+}
+
+func TestApplyProvisioningPlan(t *testing.T) {
+	earlier := v1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	now := v1.NewTime(time.Now().Truncate(time.Second))
+	plan := &v1beta1.ProvisioningPlan{TargetNodeCount: 2, NodeClaimsToCreate: 2, Message: "would create 2 NodeClaims"}
+
+	status := &v1beta1.WorkspaceStatus{}
+	applyProvisioningPlan(status, plan, earlier)
+	assert.Equal(t, earlier, status.ProvisioningPlan.GeneratedTime)
+	assert.True(t, plan.GeneratedTime.IsZero(), "the computed plan is not modified")
+
+	applyProvisioningPlan(status, plan, now)
+	assert.Equal(t, earlier, status.ProvisioningPlan.GeneratedTime, "an unchanged plan keeps its time")
+
+	changed := plan.DeepCopy()
+	changed.NodeClaimsToCreate = 1
+	applyProvisioningPlan(status, changed, now)
+	assert.Equal(t, now, status.ProvisioningPlan.GeneratedTime)
+	assert.Equal(t, int32(1), status.ProvisioningPlan.NodeClaimsToCreate)
+
+	applyProvisioningPlan(status, nil, now)
+	assert.Nil(t, status.ProvisioningPlan)
+}
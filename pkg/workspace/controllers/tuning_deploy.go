@@ -0,0 +1,198 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+)
+
+// Reasons of the AdapterDeployed condition.
+const (
+	adapterDeployedReasonCreated        = "WorkspaceCreated"
+	adapterDeployedReasonUpdated        = "WorkspaceUpdated"
+	adapterDeployedReasonNotInference   = "NotInferenceWorkspace"
+	adapterDeployedReasonPresetMismatch = "PresetMismatch"
+)
+
+// deployTunedAdapter serves the output adapter of a succeeded tuning Workspace from the
+// inference Workspace named in tuning.deploy. It runs once per generation of the tuning
+// Workspace, so later edits of the inference Workspace are not overwritten.
+func (c *WorkspaceReconciler) deployTunedAdapter(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if wObj.Tuning == nil || wObj.Tuning.Deploy == nil || wObj.Tuning.Preset == nil || wObj.Tuning.Output == nil {
+		return nil
+	}
+	if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeAdapterDeployed)); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == wObj.Generation {
+		return nil
+	}
+
+	if succeeded, err := c.tuningJobSucceeded(ctx, wObj); err != nil || !succeeded {
+		return err
+	}
+
+	adapter := tunedAdapter(wObj)
+	target := &kaitov1beta1.Workspace{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Tuning.Deploy.Workspace}, target)
+	switch {
+	case apierrors.IsNotFound(err):
+		target = newTunedInferenceWorkspace(wObj, adapter)
+		if err := c.Create(ctx, target); err != nil {
+			return fmt.Errorf("failed to create inference workspace %s: %w", target.Name, err)
+		}
+		return c.setAdapterDeployed(ctx, wObj, metav1.ConditionTrue, adapterDeployedReasonCreated,
+			fmt.Sprintf("created workspace %s to serve adapter %s", target.Name, adapter.Source.Name))
+	case err != nil:
+		return err
+	}
+
+	if target.Inference == nil || target.Inference.Preset == nil {
+		return c.setAdapterDeployed(ctx, wObj, metav1.ConditionFalse, adapterDeployedReasonNotInference,
+			fmt.Sprintf("workspace %s does not serve a preset model", target.Name))
+	}
+	if target.Inference.Preset.Name != wObj.Tuning.Preset.Name {
+		return c.setAdapterDeployed(ctx, wObj, metav1.ConditionFalse, adapterDeployedReasonPresetMismatch,
+			fmt.Sprintf("workspace %s serves preset %s, but the adapter was tuned for %s", target.Name, target.Inference.Preset.Name, wObj.Tuning.Preset.Name))
+	}
+
+	original := target.DeepCopy()
+	target.Inference.Adapters = upsertAdapter(target.Inference.Adapters, adapter)
+	setDatasetLineageLabel(target, wObj)
+	if err := c.Patch(ctx, target, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to add adapter %s to workspace %s: %w", adapter.Source.Name, target.Name, err)
+	}
+	return c.setAdapterDeployed(ctx, wObj, metav1.ConditionTrue, adapterDeployedReasonUpdated,
+		fmt.Sprintf("added adapter %s to workspace %s", adapter.Source.Name, target.Name))
+}
+
+// tuningJobSucceeded reports whether the tuning Job of the current revision of wObj succeeded.
+// Only that Job produced the adapter for this spec. The outcome of a Job deleted by
+// tuning.retention is taken from the status.
+func (c *WorkspaceReconciler) tuningJobSucceeded(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	job := &batchv1.Job{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(wObj), job); err != nil {
+		if apierrors.IsNotFound(err) {
+			return tuningJobFinished(wObj) && wObj.Status.State == kaitov1beta1.WorkspaceStateSucceeded, nil
+		}
+		return false, err
+	}
+	return job.Status.Succeeded > 0 && job.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation] == wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation], nil
+}
+
+func (c *WorkspaceReconciler) setAdapterDeployed(ctx context.Context, wObj *kaitov1beta1.Workspace, status metav1.ConditionStatus, reason, message string) error {
+	klog.InfoS("Tuned adapter deployment", "workspace", klog.KObj(wObj), "reason", reason, "message", message)
+	eventType := corev1.EventTypeNormal
+	if status != metav1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+	c.recordEvent(wObj, eventType, reason, message)
+	return workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace},
+		func(s *kaitov1beta1.WorkspaceStatus) error {
+			setWorkspaceCondition(s, wObj.Generation, func(m string) string { return m },
+				kaitov1beta1.WorkspaceConditionTypeAdapterDeployed, status, reason, message)
+			return nil
+		})
+}
+
+// tunedAdapter returns the adapter that serves the output of the tuning Workspace.
+func tunedAdapter(wObj *kaitov1beta1.Workspace) kaitov1beta1.AdapterSpec {
+	deploy, output := wObj.Tuning.Deploy, wObj.Tuning.Output
+	name := deploy.AdapterName
+	if name == "" {
+		name = wObj.Name
+	}
+	source := &kaitov1beta1.DataSource{Name: name}
+	if output.Volume != nil {
+		source.Volume = output.Volume.DeepCopy()
+	} else {
+		source.Image = output.Image
+		if output.ImagePushSecret != "" {
+			source.ImagePullSecrets = []string{output.ImagePushSecret}
+		}
+	}
+	strength := "1.0"
+	if deploy.Strength != nil {
+		strength = *deploy.Strength
+	}
+	return kaitov1beta1.AdapterSpec{Source: source, Strength: &strength}
+}
+
+// upsertAdapter replaces the adapter with the same name, or appends it.
+func upsertAdapter(adapters []kaitov1beta1.AdapterSpec, adapter kaitov1beta1.AdapterSpec) []kaitov1beta1.AdapterSpec {
+	for i := range adapters {
+		if adapters[i].Source != nil && adapters[i].Source.Name == adapter.Source.Name {
+			adapters[i] = adapter
+			return adapters
+		}
+	}
+	return append(adapters, adapter)
+}
+
+// newTunedInferenceWorkspace returns an inference Workspace that serves the tuned preset
+// with the adapter on the resources of the tuning Workspace, which controls it.
+func newTunedInferenceWorkspace(wObj *kaitov1beta1.Workspace, adapter kaitov1beta1.AdapterSpec) *kaitov1beta1.Workspace {
+	resource := *wObj.Resource.DeepCopy()
+	// Nodes are chosen again for the new workspace.
+	resource.PreferredNodes = nil
+	target := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wObj.Tuning.Deploy.Workspace,
+			Namespace: wObj.Namespace,
+			Labels:    map[string]string{kaitov1beta1.LabelTunedBy: wObj.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Resource: resource,
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset:   wObj.Tuning.Preset.DeepCopy(),
+			Adapters: []kaitov1beta1.AdapterSpec{adapter},
+		},
+	}
+	setDatasetLineageLabel(target, wObj)
+	return target
+}
+
+// setDatasetLineageLabel marks target as serving an adapter trained on the dataset recorded
+// in the lineage of the tuning Workspace, so it can be found by the dataset digest. The
+// label value is the tuning Workspace name when it fits in a label value.
+func setDatasetLineageLabel(target, tuning *kaitov1beta1.Workspace) {
+	if tuning.Status.Lineage == nil {
+		return
+	}
+	key, ok := kaitov1beta1.DatasetLineageLabel(tuning.Status.Lineage.DatasetDigest)
+	if !ok {
+		return
+	}
+	value := tuning.Name
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		value = ""
+	}
+	if target.Labels == nil {
+		target.Labels = map[string]string{}
+	}
+	target.Labels[key] = value
+}
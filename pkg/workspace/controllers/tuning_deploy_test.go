@@ -0,0 +1,204 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newTuningDeployWorkspace(output *v1beta1.DataDestination) *v1beta1.Workspace {
+	return &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "phi-tuning", Namespace: "default", Generation: 1,
+			Annotations: map[string]string{v1beta1.WorkspaceRevisionAnnotation: "1"},
+		},
+		Resource: v1beta1.ResourceSpec{
+			InstanceType:   "Standard_NC24ads_A100_v4",
+			LabelSelector:  &v1.LabelSelector{MatchLabels: map[string]string{"apps": "phi"}},
+			PreferredNodes: []string{"node-1"},
+		},
+		Tuning: &v1beta1.TuningSpec{
+			Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-3-mini-128k-instruct"}},
+			Method: v1beta1.TuningMethodLora,
+			Output: output,
+			Deploy: &v1beta1.TuningDeploySpec{Workspace: "phi-serving"},
+		},
+	}
+}
+
+func newTuningDeployJob(succeeded int32, revision string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "phi-tuning", Namespace: "default",
+			Annotations: map[string]string{v1beta1.WorkspaceRevisionAnnotation: revision},
+		},
+		Status: batchv1.JobStatus{Succeeded: succeeded},
+	}
+}
+
+func newTuningDeployReconciler(t *testing.T, objs ...client.Object) (*WorkspaceReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1beta1.Workspace{}).
+		Build()
+	return &WorkspaceReconciler{Client: cl, Recorder: record.NewFakeRecorder(10)}, cl
+}
+
+func adapterDeployedCondition(t *testing.T, cl client.Client) *v1.Condition {
+	ws := &v1beta1.Workspace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "phi-tuning"}, ws))
+	return meta.FindStatusCondition(ws.Status.Conditions, string(v1beta1.WorkspaceConditionTypeAdapterDeployed))
+}
+
+func TestDeployTunedAdapter(t *testing.T) {
+	ctx := context.Background()
+	imageOutput := &v1beta1.DataDestination{Image: "myregistry.azurecr.io/adapters/phi-support:v1", ImagePushSecret: "acr-push"}
+
+	t.Run("waits for the job to succeed", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(0, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+		assert.Nil(t, adapterDeployedCondition(t, cl))
+	})
+
+	t.Run("ignores a job of an older revision", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "0"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+		assert.Nil(t, adapterDeployedCondition(t, cl))
+	})
+
+	t.Run("creates the inference workspace", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+
+		created := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "phi-serving"}, created))
+		assert.Equal(t, "phi-tuning", created.Labels[v1beta1.LabelTunedBy])
+		owner := v1.GetControllerOf(created)
+		require.NotNil(t, owner)
+		assert.Equal(t, "Workspace", owner.Kind)
+		assert.Equal(t, "phi-tuning", owner.Name)
+		assert.Equal(t, "Standard_NC24ads_A100_v4", created.Resource.InstanceType)
+		assert.Empty(t, created.Resource.PreferredNodes)
+		require.NotNil(t, created.Inference)
+		assert.Equal(t, v1beta1.ModelName("phi-3-mini-128k-instruct"), created.Inference.Preset.Name)
+		assert.Equal(t, []v1beta1.AdapterSpec{{
+			Source: &v1beta1.DataSource{
+				Name:             "phi-tuning",
+				Image:            "myregistry.azurecr.io/adapters/phi-support:v1",
+				ImagePullSecrets: []string{"acr-push"},
+			},
+			Strength: ptr.To("1.0"),
+		}}, created.Inference.Adapters)
+
+		cond := adapterDeployedCondition(t, cl)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionTrue, cond.Status)
+		assert.Equal(t, adapterDeployedReasonCreated, cond.Reason)
+	})
+
+	t.Run("labels the inference workspace with the dataset lineage", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		ws.Status.Lineage = &v1beta1.LineageStatus{DatasetDigest: "sha256:" + strings.Repeat("0f", 32)}
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+
+		created := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "phi-serving"}, created))
+		key, _ := v1beta1.DatasetLineageLabel(ws.Status.Lineage.DatasetDigest)
+		assert.Equal(t, "phi-tuning", created.Labels[key])
+	})
+
+	t.Run("replaces the adapter of an existing workspace", func(t *testing.T) {
+		volume := &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "results"}}
+		ws := newTuningDeployWorkspace(&v1beta1.DataDestination{Volume: volume})
+		ws.Tuning.Deploy.AdapterName = "support"
+		ws.Tuning.Deploy.Strength = ptr.To("0.5")
+		serving := &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "phi-serving", Namespace: "default"},
+			Inference: &v1beta1.InferenceSpec{
+				Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-3-mini-128k-instruct"}},
+				Adapters: []v1beta1.AdapterSpec{
+					{Source: &v1beta1.DataSource{Name: "other", Image: "myregistry.azurecr.io/adapters/other:v1"}},
+					{Source: &v1beta1.DataSource{Name: "support", Image: "myregistry.azurecr.io/adapters/support:v0"}},
+				},
+			},
+		}
+		r, cl := newTuningDeployReconciler(t, ws, serving, newTuningDeployJob(1, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+
+		updated := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "phi-serving"}, updated))
+		require.Len(t, updated.Inference.Adapters, 2)
+		assert.Equal(t, "other", updated.Inference.Adapters[0].Source.Name)
+		assert.Equal(t, v1beta1.AdapterSpec{Source: &v1beta1.DataSource{Name: "support", Volume: volume}, Strength: ptr.To("0.5")}, updated.Inference.Adapters[1])
+		assert.Empty(t, updated.Labels[v1beta1.LabelTunedBy])
+		assert.Empty(t, updated.OwnerReferences)
+		assert.Equal(t, adapterDeployedReasonUpdated, adapterDeployedCondition(t, cl).Reason)
+	})
+
+	t.Run("rejects a workspace with another preset", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		serving := &v1beta1.Workspace{
+			ObjectMeta: v1.ObjectMeta{Name: "phi-serving", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-4"}}},
+		}
+		r, cl := newTuningDeployReconciler(t, ws, serving, newTuningDeployJob(1, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+
+		cond := adapterDeployedCondition(t, cl)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionFalse, cond.Status)
+		assert.Equal(t, adapterDeployedReasonPresetMismatch, cond.Reason)
+		unchanged := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "phi-serving"}, unchanged))
+		assert.Empty(t, unchanged.Inference.Adapters)
+	})
+
+	t.Run("runs once per generation", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(imageOutput)
+		ws.Status.Conditions = []v1.Condition{{
+			Type: string(v1beta1.WorkspaceConditionTypeAdapterDeployed), Status: v1.ConditionTrue, ObservedGeneration: 1,
+			Reason: adapterDeployedReasonCreated,
+		}}
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"))
+		require.NoError(t, r.deployTunedAdapter(ctx, ws))
+		err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "phi-serving"}, &v1beta1.Workspace{})
+		assert.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+// tuningLineageTag is the log line tag emitted by fine_tuning.py after the adapter is saved.
+const tuningLineageTag = "KAITO_TUNING_LINEAGE"
+
+// tuningLineagePayload mirrors the JSON emitted by lineage.py. Unknown digests are omitted.
+type tuningLineagePayload struct {
+	DatasetDigest    string `json:"dataset_digest"`
+	ParametersDigest string `json:"parameters_digest"`
+	OutputDigest     string `json:"output_digest"`
+}
+
+// parseTuningLineage returns the digests reported by the last well-formed
+// KAITO_TUNING_LINEAGE line of r, or nil if there is none.
+//
+// Log line format (emitted by lineage.py):
+//
+//	KAITO_TUNING_LINEAGE <RFC3339-timestamp> <JSON-payload>
+func parseTuningLineage(r io.Reader) (*kaitov1beta1.LineageStatus, error) {
+	var lineage *kaitov1beta1.LineageStatus
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxScanTokenSize)
+	for scanner.Scan() {
+		if l := parseTuningLineageLine(scanner.Text()); l != nil {
+			lineage = l
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning pod logs: %w", err)
+	}
+	return lineage, nil
+}
+
+// parseTuningLineageLine parses one lineage line. The digests come from the training
+// container, so malformed ones are dropped.
+func parseTuningLineageLine(line string) *kaitov1beta1.LineageStatus {
+	idx := strings.Index(line, tuningLineageTag)
+	if idx == -1 {
+		return nil
+	}
+	timestamp, payloadJSON, ok := strings.Cut(strings.TrimSpace(line[idx+len(tuningLineageTag):]), " ")
+	if !ok {
+		return nil
+	}
+	reported, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil
+	}
+	var payload tuningLineagePayload
+	if err := json.Unmarshal([]byte(strings.TrimSpace(payloadJSON)), &payload); err != nil {
+		return nil
+	}
+
+	digest := func(d string) string {
+		if kaitov1beta1.IsSHA256Digest(d) {
+			return d
+		}
+		return ""
+	}
+	lineage := &kaitov1beta1.LineageStatus{
+		DatasetDigest:    digest(payload.DatasetDigest),
+		ParametersDigest: digest(payload.ParametersDigest),
+		OutputDigest:     digest(payload.OutputDigest),
+		RecordedTime:     &metav1.Time{Time: reported},
+	}
+	if lineage.DatasetDigest == "" && lineage.ParametersDigest == "" && lineage.OutputDigest == "" {
+		return nil
+	}
+	return lineage
+}
+
+// recordTuningLineage records the lineage of the output of the current revision of a tuning
+// Workspace in its status and on its ControllerRevision once the tuning Job has succeeded.
+// A lineage is only recorded once the log of the tuning pod has been read, since a recorded
+// revision is not looked at again. Digests are left empty when the trainer did not log them,
+// e.g. with an older preset image, or when the tuning pod is gone.
+func (c *WorkspaceReconciler) recordTuningLineage(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	if wObj.Tuning == nil || wObj.Tuning.Preset == nil {
+		return nil
+	}
+	revision := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
+	if l := wObj.Status.Lineage; l != nil && l.Revision == revision {
+		return nil
+	}
+	if succeeded, err := c.tuningJobSucceeded(ctx, wObj); err != nil || !succeeded {
+		return err
+	}
+
+	lineage := &kaitov1beta1.LineageStatus{}
+	if err := c.readTuningPodLog(ctx, wObj, func(r io.Reader) error {
+		l, err := parseTuningLineage(r)
+		if l != nil {
+			lineage = l
+		}
+		return err
+	}); err != nil {
+		if !errors.Is(err, errNoTuningPod) {
+			return fmt.Errorf("failed to read the tuning lineage: %w", err)
+		}
+		klog.InfoS("The tuning pod is gone, recording lineage without digests", "workspace", klog.KObj(wObj))
+	}
+	lineage.Revision = revision
+	lineage.BaseModel = string(wObj.Tuning.Preset.Name)
+	model, err := models.GetModelByName(ctx, string(wObj.Tuning.Preset.Name), "", wObj.Namespace, c.Client)
+	if err != nil {
+		return err
+	}
+	if params := model.GetTuningParameters(); params != nil && params.Version != "" {
+		lineage.BaseModel = params.Version
+	}
+
+	if err := c.annotateRevisionLineage(ctx, wObj, lineage); err != nil {
+		return err
+	}
+	if err := workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace},
+		func(s *kaitov1beta1.WorkspaceStatus) error {
+			s.Lineage = lineage
+			return nil
+		}); err != nil {
+		return err
+	}
+	klog.InfoS("Recorded tuning lineage", "workspace", klog.KObj(wObj), "revision", revision,
+		"dataset", lineage.DatasetDigest, "output", lineage.OutputDigest)
+	wObj.Status.Lineage = lineage
+	return nil
+}
+
+// annotateRevisionLineage stores lineage on the ControllerRevision of lineage.Revision, so the
+// trail outlives later revisions of the Workspace status. A pruned revision is skipped.
+func (c *WorkspaceReconciler) annotateRevisionLineage(ctx context.Context, wObj *kaitov1beta1.Workspace, lineage *kaitov1beta1.LineageStatus) error {
+	revisionNum, err := strconv.ParseInt(lineage.Revision, 10, 64)
+	if err != nil {
+		return nil
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(wObj.Namespace), client.MatchingLabels{WorkspaceNameLabel: wObj.Name}); err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+	data, err := json.Marshal(lineage)
+	if err != nil {
+		return err
+	}
+	for i := range revisions.Items {
+		rev := &revisions.Items[i]
+		if rev.Revision != revisionNum {
+			continue
+		}
+		original := rev.DeepCopy()
+		if rev.Annotations == nil {
+			rev.Annotations = map[string]string{}
+		}
+		rev.Annotations[kaitov1beta1.AnnotationLineage] = string(data)
+		if err := c.Patch(ctx, rev, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to annotate revision %s with lineage: %w", rev.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+)
+
+func TestParseTuningLineageLine(t *testing.T) {
+	dataset := "sha256:" + strings.Repeat("a1", 32)
+	output := "sha256:" + strings.Repeat("b2", 32)
+
+	tests := []struct {
+		name string
+		line string
+		want *v1beta1.LineageStatus
+	}{
+		{
+			name: "all digests",
+			line: `KAITO_TUNING_LINEAGE 2026-01-02T03:04:05Z {"dataset_digest":"` + dataset + `","parameters_digest":"` + dataset + `","output_digest":"` + output + `"}`,
+			want: &v1beta1.LineageStatus{DatasetDigest: dataset, ParametersDigest: dataset, OutputDigest: output},
+		},
+		{
+			name: "malformed digests are dropped",
+			line: `KAITO_TUNING_LINEAGE 2026-01-02T03:04:05Z {"dataset_digest":"sha256:XYZ","output_digest":"` + output + `"}`,
+			want: &v1beta1.LineageStatus{OutputDigest: output},
+		},
+		{
+			name: "no valid digest",
+			line: `KAITO_TUNING_LINEAGE 2026-01-02T03:04:05Z {"dataset_digest":"md5:abc"}`,
+		},
+		{
+			name: "bad timestamp",
+			line: `KAITO_TUNING_LINEAGE yesterday {"output_digest":"` + output + `"}`,
+		},
+		{
+			name: "other line",
+			line: `KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTuningLineageLine(tt.line)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, "2026-01-02T03:04:05Z", got.RecordedTime.UTC().Format("2006-01-02T15:04:05Z"))
+			got.RecordedTime = nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTuningLineage(t *testing.T) {
+	output := "sha256:" + strings.Repeat("c3", 32)
+	logs := strings.Join([]string{
+		`KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":10}`,
+		`KAITO_TUNING_LINEAGE 2026-01-02T03:04:06Z {"output_digest":"` + output + `"}`,
+		`Fine-Tuning completed`,
+	}, "\n")
+	lineage, err := parseTuningLineage(strings.NewReader(logs))
+	require.NoError(t, err)
+	require.NotNil(t, lineage)
+	assert.Equal(t, output, lineage.OutputDigest)
+
+	lineage, err = parseTuningLineage(strings.NewReader("no lineage here"))
+	require.NoError(t, err)
+	assert.Nil(t, lineage)
+}
+
+func TestRecordTuningLineage(t *testing.T) {
+	ctx := context.Background()
+	k8sclient.SetGlobalClientGoClient(kubefake.NewClientset())
+	output := &v1beta1.DataDestination{Image: "myregistry.azurecr.io/adapters/phi-support:v1", ImagePushSecret: "acr-push"}
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: v1.ObjectMeta{Name: "phi-tuning-abcde", Namespace: "default", Labels: map[string]string{WorkspaceNameLabel: "phi-tuning"}},
+		Revision:   1,
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "phi-tuning-x7k2p", Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "phi-tuning"}},
+	}
+
+	t.Run("waits for the job to succeed", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(output)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(0, "1"), revision.DeepCopy())
+		require.NoError(t, r.recordTuningLineage(ctx, ws))
+
+		got := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), got))
+		assert.Nil(t, got.Status.Lineage)
+	})
+
+	t.Run("records the lineage of the current revision", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(output)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"), revision.DeepCopy(), pod.DeepCopy())
+		require.NoError(t, r.recordTuningLineage(ctx, ws))
+
+		got := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), got))
+		require.NotNil(t, got.Status.Lineage)
+		assert.Equal(t, "1", got.Status.Lineage.Revision)
+		assert.Contains(t, got.Status.Lineage.BaseModel, "huggingface.co")
+		assert.Equal(t, got.Status.Lineage, ws.Status.Lineage)
+
+		rev := &appsv1.ControllerRevision{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(revision), rev))
+		recorded := &v1beta1.LineageStatus{}
+		require.NoError(t, json.Unmarshal([]byte(rev.Annotations[v1beta1.AnnotationLineage]), recorded))
+		assert.Equal(t, got.Status.Lineage.BaseModel, recorded.BaseModel)
+	})
+
+	t.Run("retries when the pod log cannot be read", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(output)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"), revision.DeepCopy(), pod.DeepCopy())
+		r.Client = interceptor.NewClient(cl.(client.WithWatch), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*corev1.PodList); ok {
+					return errors.New("connection refused")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		})
+		require.Error(t, r.recordTuningLineage(ctx, ws))
+
+		got := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), got))
+		assert.Nil(t, got.Status.Lineage)
+	})
+
+	t.Run("records the lineage without digests once the pod is gone", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(output)
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"), revision.DeepCopy())
+		require.NoError(t, r.recordTuningLineage(ctx, ws))
+
+		got := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), got))
+		require.NotNil(t, got.Status.Lineage)
+		assert.Equal(t, "1", got.Status.Lineage.Revision)
+		assert.Empty(t, got.Status.Lineage.DatasetDigest)
+	})
+
+	t.Run("skips a revision that is already recorded", func(t *testing.T) {
+		ws := newTuningDeployWorkspace(output)
+		ws.Status.Lineage = &v1beta1.LineageStatus{Revision: "1", BaseModel: "recorded"}
+		r, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(1, "1"), revision.DeepCopy())
+		require.NoError(t, r.recordTuningLineage(ctx, ws))
+
+		rev := &appsv1.ControllerRevision{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(revision), rev))
+		assert.Empty(t, rev.Annotations[v1beta1.AnnotationLineage])
+	})
+}
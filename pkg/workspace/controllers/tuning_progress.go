@@ -0,0 +1,184 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+)
+
+const (
+	// tuningProgressTag is the log line tag emitted by the progress callback of fine_tuning.py.
+	tuningProgressTag = "KAITO_TUNING_PROGRESS"
+
+	// tuningProgressLogTailLines limits how many lines are read from the tail of the
+	// tuning pod log. The trainer logs a progress line at every logging step.
+	tuningProgressLogTailLines = int64(200)
+
+	// tuningProgressInterval is how often the progress of a running tuning Job is refreshed.
+	tuningProgressInterval = 30 * time.Second
+
+	// maxTuningETA bounds the estimated remaining time taken from the pod log.
+	maxTuningETA = 365 * 24 * time.Hour
+)
+
+// tuningProgressPayload mirrors the JSON emitted by progress.py. Unknown values are omitted.
+type tuningProgressPayload struct {
+	Epoch          *float64 `json:"epoch"`
+	Step           int64    `json:"step"`
+	TotalSteps     int64    `json:"total_steps"`
+	Loss           *float64 `json:"loss"`
+	ETASeconds     *int64   `json:"eta_seconds"`
+	GPUUtilization *int32   `json:"gpu_utilization"`
+}
+
+// parseTuningProgress returns the progress reported by the last well-formed
+// KAITO_TUNING_PROGRESS line of r, or nil if there is none.
+//
+// Log line format (emitted by progress.py):
+//
+//	KAITO_TUNING_PROGRESS <RFC3339-timestamp> <JSON-payload>
+func parseTuningProgress(r io.Reader) (*kaitov1beta1.TuningStatus, error) {
+	var progress *kaitov1beta1.TuningStatus
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxScanTokenSize)
+	for scanner.Scan() {
+		if p := parseTuningProgressLine(scanner.Text()); p != nil {
+			progress = p
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning pod logs: %w", err)
+	}
+	return progress, nil
+}
+
+// parseTuningProgressLine parses one progress line. The values come from the training
+// container, so malformed or out of range ones are dropped.
+func parseTuningProgressLine(line string) *kaitov1beta1.TuningStatus {
+	idx := strings.Index(line, tuningProgressTag)
+	if idx == -1 {
+		return nil
+	}
+	timestamp, payloadJSON, ok := strings.Cut(strings.TrimSpace(line[idx+len(tuningProgressTag):]), " ")
+	if !ok {
+		return nil
+	}
+	reported, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil
+	}
+	var payload tuningProgressPayload
+	if err := json.Unmarshal([]byte(strings.TrimSpace(payloadJSON)), &payload); err != nil {
+		return nil
+	}
+	if payload.Step < 0 || payload.TotalSteps < 0 {
+		return nil
+	}
+
+	progress := &kaitov1beta1.TuningStatus{
+		Step:           payload.Step,
+		TotalSteps:     payload.TotalSteps,
+		LastUpdateTime: &metav1.Time{Time: reported},
+	}
+	if e := payload.Epoch; e != nil && *e >= 0 && !math.IsInf(*e, 0) {
+		progress.Epoch = strconv.FormatFloat(*e, 'f', 2, 64)
+	}
+	if l := payload.Loss; l != nil && !math.IsInf(*l, 0) {
+		progress.Loss = strconv.FormatFloat(*l, 'g', 4, 64)
+	}
+	if eta := payload.ETASeconds; eta != nil && *eta >= 0 && time.Duration(*eta)*time.Second <= maxTuningETA {
+		progress.EstimatedCompletionTime = &metav1.Time{Time: reported.Add(time.Duration(*eta) * time.Second)}
+	}
+	if g := payload.GPUUtilization; g != nil && *g >= 0 && *g <= 100 {
+		progress.GPUUtilization = ptr.To(*g)
+	}
+	return progress
+}
+
+// collectTuningProgress reads the progress of the newest tuning pod of wObj. It returns
+// nil when no progress has been logged yet or the log cannot be read; the status then
+// keeps the last recorded progress.
+func (c *WorkspaceReconciler) collectTuningProgress(ctx context.Context, wObj *kaitov1beta1.Workspace) *kaitov1beta1.TuningStatus {
+	var progress *kaitov1beta1.TuningStatus
+	if err := c.readTuningPodLog(ctx, wObj, func(r io.Reader) (err error) {
+		progress, err = parseTuningProgress(r)
+		return err
+	}); err != nil {
+		klog.V(4).InfoS("failed to read the tuning progress", "workspace", klog.KObj(wObj), "err", err)
+	}
+	return progress
+}
+
+// errNoTuningPod is returned by readTuningPodLog when the tuning Workspace has no pod.
+var errNoTuningPod = errors.New("no tuning pod found")
+
+// readTuningPodLog passes the tail of the log of the newest tuning pod of wObj to parse.
+func (c *WorkspaceReconciler) readTuningPodLog(ctx context.Context, wObj *kaitov1beta1.Workspace, parse func(io.Reader) error) error {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return fmt.Errorf("failed to list tuning pods: %w", err)
+	}
+	var newest *corev1.Pod
+	for i := range pods.Items {
+		if newest == nil || newest.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			newest = &pods.Items[i]
+		}
+	}
+	if newest == nil {
+		return errNoTuningPod
+	}
+
+	tailLines := tuningProgressLogTailLines
+	stream, err := k8sclient.ClientGoClientFromContext(ctx).CoreV1().Pods(wObj.Namespace).GetLogs(newest.Name, &corev1.PodLogOptions{
+		TailLines: &tailLines,
+		Container: wObj.Name,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the log of tuning pod %s: %w", newest.Name, err)
+	}
+	defer stream.Close()
+
+	if err := parse(io.LimitReader(stream, maxLogReadBytes)); err != nil {
+		return fmt.Errorf("failed to parse the log of tuning pod %s: %w", newest.Name, err)
+	}
+	return nil
+}
+
+// tuningProgressResult requeues a running tuning Workspace so that its progress is refreshed.
+func tuningProgressResult(wObj *kaitov1beta1.Workspace) reconcile.Result {
+	if wObj.Status.State == kaitov1beta1.WorkspaceStateRunning {
+		return reconcile.Result{RequeueAfter: tuningProgressInterval}
+	}
+	return reconcile.Result{}
+}
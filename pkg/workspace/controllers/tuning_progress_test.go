@@ -0,0 +1,110 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestParseTuningProgress(t *testing.T) {
+	reported := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("last line wins", func(t *testing.T) {
+		log := strings.Join([]string{
+			"INFO loading model",
+			`KAITO_TUNING_PROGRESS 2026-01-02T03:00:00Z {"epoch":0.1,"step":1,"total_steps":100}`,
+			`{'loss': 1.234, 'epoch': 0.25}`,
+			`KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"epoch":0.25,"step":25,"total_steps":100,"loss":1.23456,"eta_seconds":600,"gpu_utilization":87}`,
+			"KAITO_TUNING_PROGRESS truncated",
+		}, "\n")
+		progress, err := parseTuningProgress(strings.NewReader(log))
+		require.NoError(t, err)
+		assert.Equal(t, &kaitov1beta1.TuningStatus{
+			Epoch:                   "0.25",
+			Step:                    25,
+			TotalSteps:              100,
+			Loss:                    "1.235",
+			EstimatedCompletionTime: &metav1.Time{Time: reported.Add(10 * time.Minute)},
+			GPUUtilization:          ptr.To(int32(87)),
+			LastUpdateTime:          &metav1.Time{Time: reported},
+		}, progress)
+	})
+
+	t.Run("no progress", func(t *testing.T) {
+		progress, err := parseTuningProgress(strings.NewReader("INFO loading model\n"))
+		require.NoError(t, err)
+		assert.Nil(t, progress)
+	})
+}
+
+func TestParseTuningProgressLine(t *testing.T) {
+	tests := map[string]struct {
+		line  string
+		check func(t *testing.T, p *kaitov1beta1.TuningStatus)
+	}{
+		"bad timestamp": {
+			line:  `KAITO_TUNING_PROGRESS yesterday {"step":1}`,
+			check: func(t *testing.T, p *kaitov1beta1.TuningStatus) { assert.Nil(t, p) },
+		},
+		"bad json": {
+			line:  `KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":`,
+			check: func(t *testing.T, p *kaitov1beta1.TuningStatus) { assert.Nil(t, p) },
+		},
+		"negative step": {
+			line:  `KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":-1}`,
+			check: func(t *testing.T, p *kaitov1beta1.TuningStatus) { assert.Nil(t, p) },
+		},
+		"out of range values are dropped": {
+			line: `KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":1,"epoch":-1,"eta_seconds":99999999999,"gpu_utilization":250}`,
+			check: func(t *testing.T, p *kaitov1beta1.TuningStatus) {
+				require.NotNil(t, p)
+				assert.Equal(t, int64(1), p.Step)
+				assert.Empty(t, p.Epoch)
+				assert.Nil(t, p.EstimatedCompletionTime)
+				assert.Nil(t, p.GPUUtilization)
+			},
+		},
+		"prefixed by the log format": {
+			line: `12:00:00 stdout F KAITO_TUNING_PROGRESS 2026-01-02T03:04:05Z {"step":3,"total_steps":9}`,
+			check: func(t *testing.T, p *kaitov1beta1.TuningStatus) {
+				require.NotNil(t, p)
+				assert.Equal(t, int64(3), p.Step)
+				assert.Equal(t, int64(9), p.TotalSteps)
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.check(t, parseTuningProgressLine(tc.line))
+		})
+	}
+}
+
+func TestTuningProgressResult(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{Status: kaitov1beta1.WorkspaceStatus{State: kaitov1beta1.WorkspaceStateRunning}}
+	assert.Equal(t, reconcile.Result{RequeueAfter: tuningProgressInterval}, tuningProgressResult(ws))
+
+	ws.Status.State = kaitov1beta1.WorkspaceStateSucceeded
+	assert.Equal(t, reconcile.Result{}, tuningProgressResult(ws))
+}
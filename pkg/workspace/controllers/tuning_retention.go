@@ -0,0 +1,107 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// tuningJobFinished reports whether the status of wObj records that the tuning Job of the
+// current revision succeeded or failed. Such a Job is not recreated once it is deleted.
+func tuningJobFinished(wObj *kaitov1beta1.Workspace) bool {
+	revision := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
+	return revision != "" && wObj.Status.FinishedTuningRevision == revision
+}
+
+// prunedTuningSnapshot returns the status snapshot of a tuning Job that no longer exists,
+// from the outcome recorded in the status.
+func prunedTuningSnapshot(wObj *kaitov1beta1.Workspace) *tuningStatusSnapshot {
+	if !tuningJobFinished(wObj) {
+		return &tuningStatusSnapshot{}
+	}
+	snapshot := &tuningStatusSnapshot{finishedRevision: wObj.Status.FinishedTuningRevision}
+	switch wObj.Status.State {
+	case kaitov1beta1.WorkspaceStateSucceeded:
+		snapshot.started, snapshot.succeeded = true, true
+	case kaitov1beta1.WorkspaceStateFailed:
+		snapshot.failed = true
+	}
+	return snapshot
+}
+
+// jobFinishedTime returns when job succeeded or failed, or false if it is still running.
+func jobFinishedTime(job *batchv1.Job) (time.Time, bool) {
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// pruneTuningJob deletes the finished tuning Job of wObj and its pods once
+// tuning.retention.ttlSecondsAfterFinished has passed. The Job is only deleted after its
+// outcome, and the lineage of a successful run, are recorded in the status. It returns how
+// long to wait before the Job can be deleted, or 0 if there is nothing to delete.
+func (c *WorkspaceReconciler) pruneTuningJob(ctx context.Context, wObj *kaitov1beta1.Workspace) (time.Duration, error) {
+	retention := wObj.Tuning.Retention
+	if retention == nil || retention.TTLSecondsAfterFinished == nil {
+		return 0, nil
+	}
+	job := &batchv1.Job{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(wObj), job); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	revision := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
+	if job.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation] != revision {
+		return 0, nil
+	}
+	finished, ok := jobFinishedTime(job)
+	if !ok {
+		return 0, nil
+	}
+	remaining := time.Until(finished.Add(time.Duration(*retention.TTLSecondsAfterFinished) * time.Second))
+	recorded := tuningJobFinished(wObj) &&
+		(wObj.Status.State != kaitov1beta1.WorkspaceStateSucceeded || (wObj.Status.Lineage != nil && wObj.Status.Lineage.Revision == revision))
+	if !recorded {
+		// The status of this reconcile records the outcome; check again shortly.
+		return max(remaining, time.Second), nil
+	}
+	if remaining > 0 {
+		return remaining, nil
+	}
+
+	klog.InfoS("Deleting finished tuning job", "workspace", klog.KObj(wObj), "job", klog.KObj(job))
+	propagation := metav1.DeletePropagationBackground
+	if err := c.Delete(ctx, job, &client.DeleteOptions{
+		PropagationPolicy: &propagation,
+		Preconditions:     &metav1.Preconditions{UID: &job.UID},
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to delete finished tuning job: %w", err)
+	}
+	c.recordEvent(wObj, corev1.EventTypeNormal, "TuningJobDeleted",
+		fmt.Sprintf("deleted tuning job %s %d seconds after it finished", job.Name, *retention.TTLSecondsAfterFinished))
+	return 0, nil
+}
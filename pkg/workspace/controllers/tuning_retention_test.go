@@ -0,0 +1,167 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newFinishedTuningJob(revision string, finished time.Time, jobCondition batchv1.JobConditionType) *batchv1.Job {
+	job := newTuningDeployJob(0, revision)
+	job.UID = "job-uid"
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type: jobCondition, Status: corev1.ConditionTrue, LastTransitionTime: v1.NewTime(finished),
+	}}
+	if jobCondition == batchv1.JobComplete {
+		job.Status.Succeeded = 1
+	} else {
+		job.Status.Failed = 1
+	}
+	return job
+}
+
+func TestPruneTuningJob(t *testing.T) {
+	ctx := context.Background()
+	newWorkspace := func(ttl *int32, finishedRevision string, state v1beta1.WorkspaceState) *v1beta1.Workspace {
+		ws := newTuningDeployWorkspace(&v1beta1.DataDestination{Image: "registry.example.com/adapter:v1"})
+		ws.Tuning.Retention = &v1beta1.TuningRetentionSpec{TTLSecondsAfterFinished: ttl}
+		ws.Status.FinishedTuningRevision = finishedRevision
+		ws.Status.State = state
+		if state == v1beta1.WorkspaceStateSucceeded {
+			ws.Status.Lineage = &v1beta1.LineageStatus{Revision: finishedRevision}
+		}
+		return ws
+	}
+	jobExists := func(t *testing.T, cl client.Client) bool {
+		t.Helper()
+		err := cl.Get(ctx, client.ObjectKey{Name: "phi-tuning", Namespace: "default"}, &batchv1.Job{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("no retention", func(t *testing.T) {
+		ws := newWorkspace(nil, "1", v1beta1.WorkspaceStateSucceeded)
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now().Add(-time.Hour), batchv1.JobComplete))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Zero(t, after)
+		assert.True(t, jobExists(t, cl))
+	})
+
+	t.Run("running job", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](0), "", v1beta1.WorkspaceStateRunning)
+		c, cl := newTuningDeployReconciler(t, ws, newTuningDeployJob(0, "1"))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Zero(t, after)
+		assert.True(t, jobExists(t, cl))
+	})
+
+	t.Run("outcome not recorded yet", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](0), "", v1beta1.WorkspaceStateRunning)
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now().Add(-time.Hour), batchv1.JobComplete))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, after)
+		assert.True(t, jobExists(t, cl))
+	})
+
+	t.Run("ttl not passed", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](3600), "1", v1beta1.WorkspaceStateSucceeded)
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now(), batchv1.JobComplete))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Greater(t, after, 59*time.Minute)
+		assert.True(t, jobExists(t, cl))
+	})
+
+	t.Run("succeeded job deleted", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](60), "1", v1beta1.WorkspaceStateSucceeded)
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now().Add(-time.Hour), batchv1.JobComplete))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Zero(t, after)
+		assert.False(t, jobExists(t, cl))
+
+		// The outcome is kept and the deployment of the adapter still sees the success.
+		succeeded, err := c.tuningJobSucceeded(ctx, ws)
+		require.NoError(t, err)
+		assert.True(t, succeeded)
+		snapshot := prunedTuningSnapshot(ws)
+		assert.True(t, snapshot.succeeded)
+		assert.Equal(t, "1", snapshot.finishedRevision)
+	})
+
+	t.Run("failed job deleted", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](0), "1", v1beta1.WorkspaceStateFailed)
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now(), batchv1.JobFailed))
+		_, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.False(t, jobExists(t, cl))
+		assert.True(t, prunedTuningSnapshot(ws).failed)
+	})
+
+	t.Run("job of an older revision", func(t *testing.T) {
+		ws := newWorkspace(ptr.To[int32](0), "1", v1beta1.WorkspaceStateSucceeded)
+		ws.Annotations[v1beta1.WorkspaceRevisionAnnotation] = "2"
+		c, cl := newTuningDeployReconciler(t, ws, newFinishedTuningJob("1", time.Now().Add(-time.Hour), batchv1.JobComplete))
+		after, err := c.pruneTuningJob(ctx, ws)
+		require.NoError(t, err)
+		assert.Zero(t, after)
+		assert.True(t, jobExists(t, cl))
+		assert.Equal(t, &tuningStatusSnapshot{}, prunedTuningSnapshot(ws))
+	})
+}
+
+func TestApplyTuningWorkspaceStatusRecordsFinishedRevision(t *testing.T) {
+	status := &v1beta1.WorkspaceStatus{}
+	applyTuningWorkspaceStatus(status, 1, func(m string) string { return m },
+		&tuningStatusSnapshot{started: true, succeeded: true, finishedRevision: "3"})
+	assert.Equal(t, "3", status.FinishedTuningRevision)
+	assert.Equal(t, v1beta1.WorkspaceStateSucceeded, status.State)
+
+	// A recreated Job that has not finished keeps the revision of the last finished one.
+	applyTuningWorkspaceStatus(status, 2, func(m string) string { return m }, &tuningStatusSnapshot{})
+	assert.Equal(t, "3", status.FinishedTuningRevision)
+}
+
+func TestRevisionTuningIgnoresRetention(t *testing.T) {
+	ws := newTuningDeployWorkspace(&v1beta1.DataDestination{Image: "registry.example.com/adapter:v1"})
+	hash, err := marshalSelectedFields(ws)
+	require.NoError(t, err)
+	computed := ComputeHash(ws)
+
+	ws.Tuning.Retention = &v1beta1.TuningRetentionSpec{TTLSecondsAfterFinished: ptr.To[int32](60), KeepLastN: ptr.To[int32](1)}
+	withRetention, err := marshalSelectedFields(ws)
+	require.NoError(t, err)
+	assert.Equal(t, hash, withRetention)
+	assert.Equal(t, computed, ComputeHash(ws))
+	assert.NotNil(t, ws.Tuning.Retention)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// workloadIdentityRequeueInterval is how often a Workspace whose identity lacks permissions
+// is checked again, e.g. after a role assignment has propagated.
+const workloadIdentityRequeueInterval = time.Minute
+
+// workloadIdentityMessage checks that the identity of wObj can read its storage URLs and
+// returns why it cannot, or an empty string when it can or the workspace has no identity.
+func (c *WorkspaceReconciler) workloadIdentityMessage(ctx context.Context, wObj *kaitov1beta1.Workspace) string {
+	if wObj.Identity == nil || c.identityChecker == nil {
+		return ""
+	}
+	if err := c.identityChecker.Check(ctx, wObj); err != nil {
+		return fmt.Sprintf("workload identity check failed: %v", err)
+	}
+	return ""
+}
+
+// applyWorkloadIdentityCondition sets WorkloadIdentityGated while message is not empty and
+// removes it once the identity has the permissions it needs.
+func applyWorkloadIdentityCondition(status *kaitov1beta1.WorkspaceStatus, wObj *kaitov1beta1.Workspace, message string) {
+	if message == "" {
+		meta.RemoveStatusCondition(&status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated))
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated),
+		Status:             metav1.ConditionTrue,
+		Reason:             "PermissionCheckFailed",
+		Message:            message,
+		ObservedGeneration: wObj.GetGeneration(),
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestApplyWorkloadIdentityCondition(t *testing.T) {
+	wObj := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Generation: 3}}
+	status := &v1beta1.WorkspaceStatus{}
+	assert.Empty(t, (&WorkspaceReconciler{}).workloadIdentityMessage(context.Background(), wObj))
+
+	applyWorkloadIdentityCondition(status, wObj, "workload identity check failed: denied")
+	cond := meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeWorkloadIdentityGated))
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, int64(3), cond.ObservedGeneration)
+
+	applyWorkloadIdentityCondition(status, wObj, "")
+	assert.Nil(t, meta.FindStatusCondition(status.Conditions, string(v1beta1.WorkspaceConditionTypeWorkloadIdentityGated)))
+}
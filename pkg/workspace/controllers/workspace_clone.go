@@ -0,0 +1,242 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// Reasons of the events recorded for a clone request.
+const (
+	cloneReasonCloned = "WorkspaceCloned"
+	cloneReasonFailed = "CloneFailed"
+)
+
+// clonedAnnotations are the annotations copied to a clone: the runtime and its pins, and
+// the settings the source was validated with.
+var clonedAnnotations = []string{
+	kaitov1beta1.AnnotationWorkspaceRuntime,
+	kaitov1beta1.AnnotationPerformanceMode,
+	kaitov1beta1.AnnotationRuntimeChannel,
+	kaitov1beta1.AnnotationRuntimeUpgradePaused,
+	kaitov1beta1.AnnotationBypassResourceChecks,
+	kaitov1beta1.AnnotationNodeImageFamily,
+	kaitov1beta1.AnnotationNodeClassName,
+}
+
+// controllerLabels are the labels that other controllers set on a Workspace. They are not
+// copied, as the clone is not managed by them.
+var controllerLabels = []string{
+	consts.WorkspaceCreatedByInferenceSetLabel,
+	kaitov1alpha1.LabelUpgradeToVersion,
+	kaitov1beta1.LabelTunedBy,
+}
+
+// cloneWorkspace handles the kaito.sh/clone-to annotation of wObj. The clone is created once;
+// the annotation is removed afterwards, and also when the request cannot be served, so that
+// it is not retried forever. Transient errors are returned and retried.
+func (c *WorkspaceReconciler) cloneWorkspace(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	req, ok, err := kaitov1beta1.GetCloneRequest(wObj)
+	if !ok {
+		return nil
+	}
+	if err != nil {
+		return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed, err.Error())
+	}
+	if wObj.Inference == nil {
+		return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed, "only inference workspaces can be cloned")
+	}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = wObj.Namespace
+	}
+	target := client.ObjectKey{Namespace: namespace, Name: req.Name}
+
+	if namespace != wObj.Namespace {
+		allowed, err := c.cloneAllowed(ctx, wObj.Namespace, namespace)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed,
+				fmt.Sprintf("namespace %s does not allow clones from namespace %s, see the %s annotation", namespace, wObj.Namespace, kaitov1beta1.AnnotationAllowCloneFrom))
+		}
+	}
+
+	existing := &kaitov1beta1.Workspace{}
+	err = c.Get(ctx, target, existing)
+	switch {
+	case err == nil:
+		if existing.Annotations[kaitov1beta1.AnnotationClonedFrom] == clonedFrom(wObj) {
+			return c.finishClone(ctx, wObj, corev1.EventTypeNormal, cloneReasonCloned, fmt.Sprintf("workspace %s already exists", target))
+		}
+		return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed, fmt.Sprintf("workspace %s already exists and is not a clone of this workspace", target))
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+
+	if namespace != wObj.Namespace && wObj.Inference.Config != "" {
+		if message, err := c.copyInferenceConfig(ctx, wObj, namespace); err != nil {
+			return err
+		} else if message != "" {
+			return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed, message)
+		}
+	}
+
+	clone := newClonedWorkspace(wObj, req, namespace)
+	if err := c.Create(ctx, clone); err != nil {
+		// Requests rejected by the API server, e.g. by the webhook, do not succeed on retry.
+		if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) || apierrors.IsBadRequest(err) || apierrors.IsAlreadyExists(err) {
+			return c.finishClone(ctx, wObj, corev1.EventTypeWarning, cloneReasonFailed, fmt.Sprintf("failed to create workspace %s: %v", target, err))
+		}
+		return fmt.Errorf("failed to create workspace %s: %w", target, err)
+	}
+	return c.finishClone(ctx, wObj, corev1.EventTypeNormal, cloneReasonCloned, fmt.Sprintf("created workspace %s", target))
+}
+
+// cloneAllowed reports whether the target namespace lists the source namespace in its
+// kaito.sh/allow-clone-from annotation. Without the opt-in, anyone who may annotate a
+// Workspace could have the controller create workloads in any namespace.
+func (c *WorkspaceReconciler) cloneAllowed(ctx context.Context, source, target string) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: target}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, allowed := range strings.Split(ns.Annotations[kaitov1beta1.AnnotationAllowCloneFrom], ",") {
+		if strings.TrimSpace(allowed) == source {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// copyInferenceConfig copies the inference ConfigMap of wObj into the namespace of the
+// clone. An existing ConfigMap with the same data is reused. The returned message is set
+// when the copy cannot be made.
+func (c *WorkspaceReconciler) copyInferenceConfig(ctx context.Context, wObj *kaitov1beta1.Workspace, namespace string) (string, error) {
+	source := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: wObj.Namespace, Name: wObj.Inference.Config}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("inference ConfigMap %s not found", wObj.Inference.Config), nil
+		}
+		return "", err
+	}
+
+	existing := &corev1.ConfigMap{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.Name}, existing)
+	switch {
+	case err == nil:
+		if apiequality.Semantic.DeepEqual(existing.Data, source.Data) {
+			return "", nil
+		}
+		return fmt.Sprintf("ConfigMap %s/%s already exists with other data", namespace, source.Name), nil
+	case !apierrors.IsNotFound(err):
+		return "", err
+	}
+
+	klog.InfoS("Copying inference ConfigMap for clone", "workspace", klog.KObj(wObj), "configmap", source.Name, "namespace", namespace)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Namespace:   namespace,
+			Annotations: map[string]string{kaitov1beta1.AnnotationClonedFrom: clonedFrom(wObj)},
+		},
+		Data: source.Data,
+	}
+	if err := c.Create(ctx, cm); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to copy ConfigMap %s to namespace %s: %w", source.Name, namespace, err)
+	}
+	return "", nil
+}
+
+// finishClone records the outcome of a clone request and removes the annotation.
+func (c *WorkspaceReconciler) finishClone(ctx context.Context, wObj *kaitov1beta1.Workspace, eventType, reason, message string) error {
+	klog.InfoS("Workspace clone request", "workspace", klog.KObj(wObj), "reason", reason, "message", message)
+	c.recordEvent(wObj, eventType, reason, message)
+	original := wObj.DeepCopy()
+	delete(wObj.Annotations, kaitov1beta1.AnnotationCloneTo)
+	if err := c.Patch(ctx, wObj, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to remove the %s annotation: %w", kaitov1beta1.AnnotationCloneTo, err)
+	}
+	return nil
+}
+
+func clonedFrom(wObj *kaitov1beta1.Workspace) string {
+	return wObj.Namespace + "/" + wObj.Name
+}
+
+// newClonedWorkspace returns the clone of wObj requested by req. Nodes are chosen again for
+// the clone, and its Service gets no external DNS name, which would clash with the source.
+func newClonedWorkspace(wObj *kaitov1beta1.Workspace, req *kaitov1beta1.WorkspaceCloneRequest, namespace string) *kaitov1beta1.Workspace {
+	clone := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        req.Name,
+			Namespace:   namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{kaitov1beta1.AnnotationClonedFrom: clonedFrom(wObj)},
+		},
+		Resource:          *wObj.Resource.DeepCopy(),
+		Inference:         wObj.Inference.DeepCopy(),
+		Identity:          wObj.Identity.DeepCopy(),
+		ServiceAccount:    wObj.ServiceAccount.DeepCopy(),
+		MaintenanceWindow: wObj.MaintenanceWindow.DeepCopy(),
+	}
+	for key, value := range wObj.Labels {
+		if slices.Contains(controllerLabels, key) {
+			continue
+		}
+		// The dataset lineage only holds for the adapters of the source.
+		if req.Adapters != nil && strings.HasPrefix(key, kaitov1beta1.LabelDatasetLineagePrefix) {
+			continue
+		}
+		clone.Labels[key] = value
+	}
+	for _, key := range clonedAnnotations {
+		if value, ok := wObj.Annotations[key]; ok {
+			clone.Annotations[key] = value
+		}
+	}
+
+	clone.Resource.PreferredNodes = nil
+	if req.InstanceType != "" {
+		clone.Resource.InstanceType = req.InstanceType
+	}
+	if req.Adapters != nil {
+		clone.Inference.Adapters = nil
+		for i := range *req.Adapters {
+			clone.Inference.Adapters = append(clone.Inference.Adapters, *(*req.Adapters)[i].DeepCopy())
+		}
+	}
+	if clone.Inference.Service != nil {
+		clone.Inference.Service.DNS = nil
+	}
+	return clone
+}
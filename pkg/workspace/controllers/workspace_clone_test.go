@@ -0,0 +1,174 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func newCloneSourceWorkspace(request string) *v1beta1.Workspace {
+	return &v1beta1.Workspace{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "phi-staging", Namespace: "staging",
+			Labels: map[string]string{"team": "support", consts.WorkspaceCreatedByInferenceSetLabel: "phi"},
+			Annotations: map[string]string{
+				v1beta1.AnnotationCloneTo:              request,
+				v1beta1.AnnotationRuntimeChannel:       v1beta1.RuntimeChannelPinned,
+				v1beta1.AnnotationAdoptWorkload:        "StatefulSet/phi-staging",
+				v1beta1.WorkspaceRevisionAnnotation:    "3",
+				v1beta1.AnnotationRuntimeUpgradePaused: "true",
+			},
+		},
+		Resource: v1beta1.ResourceSpec{
+			InstanceType:   "Standard_NC24ads_A100_v4",
+			LabelSelector:  &v1.LabelSelector{MatchLabels: map[string]string{"apps": "phi"}},
+			PreferredNodes: []string{"node-1"},
+		},
+		Inference: &v1beta1.InferenceSpec{
+			Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-4-mini-instruct"}},
+			Config: "phi-config",
+			Adapters: []v1beta1.AdapterSpec{
+				{Source: &v1beta1.DataSource{Name: "support-v1", Image: "myregistry.azurecr.io/adapters/support:v1"}},
+			},
+			Service: &v1beta1.EndpointServiceSpec{DNS: &v1beta1.ServiceDNSSpec{Hostname: "phi.staging.example.com"}},
+		},
+	}
+}
+
+func newCloneReconciler(t *testing.T, objs ...client.Object) (*WorkspaceReconciler, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewFakeRecorder(10)
+	return &WorkspaceReconciler{Client: cl, Recorder: recorder}, cl, recorder
+}
+
+func cloneConfigMap(namespace, value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "phi-config", Namespace: namespace},
+		Data:       map[string]string{"inference_config.yaml": value},
+	}
+}
+
+func assertCloneAnnotationRemoved(t *testing.T, cl client.Client) {
+	source := &v1beta1.Workspace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Namespace: "staging", Name: "phi-staging"}, source))
+	assert.NotContains(t, source.Annotations, v1beta1.AnnotationCloneTo)
+}
+
+func TestCloneWorkspace(t *testing.T) {
+	ctx := context.Background()
+	prod := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{
+		Name:        "prod",
+		Annotations: map[string]string{v1beta1.AnnotationAllowCloneFrom: "dev, staging"},
+	}}
+
+	t.Run("clones into another namespace with overrides", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-prod","namespace":"prod","instanceType":"Standard_NC48ads_A100_v4",` +
+			`"adapters":[{"source":{"name":"support-v2","image":"myregistry.azurecr.io/adapters/support:v2"}}]}`)
+		r, cl, recorder := newCloneReconciler(t, ws, prod, cloneConfigMap("staging", "vllm: {}"))
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+
+		clone := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "prod", Name: "phi-prod"}, clone))
+		assert.Equal(t, "staging/phi-staging", clone.Annotations[v1beta1.AnnotationClonedFrom])
+		assert.Equal(t, v1beta1.RuntimeChannelPinned, clone.Annotations[v1beta1.AnnotationRuntimeChannel])
+		assert.Equal(t, "true", clone.Annotations[v1beta1.AnnotationRuntimeUpgradePaused])
+		assert.NotContains(t, clone.Annotations, v1beta1.AnnotationCloneTo)
+		assert.NotContains(t, clone.Annotations, v1beta1.AnnotationAdoptWorkload)
+		assert.NotContains(t, clone.Annotations, v1beta1.WorkspaceRevisionAnnotation)
+		assert.Equal(t, map[string]string{"team": "support"}, clone.Labels)
+		assert.Equal(t, "Standard_NC48ads_A100_v4", clone.Resource.InstanceType)
+		assert.Empty(t, clone.Resource.PreferredNodes)
+		assert.Equal(t, "phi-config", clone.Inference.Config)
+		require.Len(t, clone.Inference.Adapters, 1)
+		assert.Equal(t, "support-v2", clone.Inference.Adapters[0].Source.Name)
+		assert.Nil(t, clone.Inference.Service.DNS)
+
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "prod", Name: "phi-config"}, cm))
+		assert.Equal(t, "vllm: {}", cm.Data["inference_config.yaml"])
+		assertCloneAnnotationRemoved(t, cl)
+		assert.Contains(t, <-recorder.Events, cloneReasonCloned)
+	})
+
+	t.Run("keeps the adapters when not overridden", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-canary"}`)
+		r, cl, _ := newCloneReconciler(t, ws)
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+
+		clone := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "staging", Name: "phi-canary"}, clone))
+		assert.Equal(t, ws.Inference.Adapters, clone.Inference.Adapters)
+		assert.Equal(t, ws.Resource.InstanceType, clone.Resource.InstanceType)
+	})
+
+	t.Run("requires the target namespace to allow the clone", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-prod","namespace":"other"}`)
+		other := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "other"}}
+		r, cl, recorder := newCloneReconciler(t, ws, other)
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+
+		err := cl.Get(ctx, client.ObjectKey{Namespace: "other", Name: "phi-prod"}, &v1beta1.Workspace{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assertCloneAnnotationRemoved(t, cl)
+		assert.Contains(t, <-recorder.Events, "does not allow clones")
+	})
+
+	t.Run("does not overwrite a different ConfigMap", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-prod","namespace":"prod"}`)
+		r, cl, recorder := newCloneReconciler(t, ws, prod, cloneConfigMap("staging", "vllm: {}"), cloneConfigMap("prod", "vllm: {max-model-len: 1024}"))
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+
+		err := cl.Get(ctx, client.ObjectKey{Namespace: "prod", Name: "phi-prod"}, &v1beta1.Workspace{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Contains(t, <-recorder.Events, "already exists with other data")
+	})
+
+	t.Run("does not touch an unrelated workspace", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-other"}`)
+		other := &v1beta1.Workspace{ObjectMeta: v1.ObjectMeta{Name: "phi-other", Namespace: "staging"}, Resource: v1beta1.ResourceSpec{InstanceType: "Standard_NC6s_v3"}}
+		r, cl, recorder := newCloneReconciler(t, ws, other)
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+
+		existing := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "staging", Name: "phi-other"}, existing))
+		assert.Equal(t, "Standard_NC6s_v3", existing.Resource.InstanceType)
+		assertCloneAnnotationRemoved(t, cl)
+		assert.Contains(t, <-recorder.Events, "is not a clone of this workspace")
+	})
+
+	t.Run("rejects a malformed request", func(t *testing.T) {
+		ws := newCloneSourceWorkspace(`{"name":"phi-prod","replicas":2}`)
+		r, cl, recorder := newCloneReconciler(t, ws)
+		require.NoError(t, r.cloneWorkspace(ctx, ws))
+		assertCloneAnnotationRemoved(t, cl)
+		assert.Contains(t, <-recorder.Events, cloneReasonFailed)
+	})
+}
@@ -18,11 +18,16 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -36,8 +41,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,20 +50,30 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/api/v1beta1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/breaker"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/crdwatch"
+	"github.com/kaito-project/kaito/pkg/utils/metriclabels"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/statuspatch"
 	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/pkg/workloadidentity"
 	"github.com/kaito-project/kaito/pkg/workspace/estimator"
 	"github.com/kaito-project/kaito/pkg/workspace/estimator/nodesestimator"
 	"github.com/kaito-project/kaito/pkg/workspace/inference"
@@ -95,11 +110,26 @@ type WorkspaceReconciler struct {
 	expectations    *utils.ControllerExpectations
 	Estimator       estimator.NodesEstimator
 	nodeProvisioner nodeprovision.NodeProvisioner
+	identityChecker *workloadidentity.Checker
+	// kubeClient reads pod logs and requests ServiceAccount tokens.
+	kubeClient kubernetes.Interface
+	// NodeClaimCRD tracks whether the NodeClaim CRD is installed. If nil, it is assumed
+	// to be installed whenever the node provisioner uses NodeClaims.
+	NodeClaimCRD *crdwatch.Watcher
+	// PodGroupCRDs track whether the PodGroup CRDs of the gang schedulers are installed.
+	// The PodGroups of a kind are only watched once its CRD is.
+	PodGroupCRDs []*crdwatch.Watcher
+	// profiledPods holds the UIDs of the pods whose logs were read for an inference profile.
+	profiledPods sync.Map
+	// httpClient sends the cold start first token request. If nil, http.DefaultClient is used.
+	httpClient *http.Client
 }
 
 func NewWorkspaceReconciler(client client.Client, scheme *runtime.Scheme, log logr.Logger, Recorder record.EventRecorder,
-	provisioner nodeprovision.NodeProvisioner) *WorkspaceReconciler {
+	provisioner nodeprovision.NodeProvisioner, kubeClient kubernetes.Interface) *WorkspaceReconciler {
 	expectations := utils.NewControllerExpectations()
+	// Create the estimator breaker up front so its health is served on the readyz endpoint.
+	breaker.Get(estimator.BreakerName)
 
 	return &WorkspaceReconciler{
 		Client:          client,
@@ -110,10 +140,13 @@ func NewWorkspaceReconciler(client client.Client, scheme *runtime.Scheme, log lo
 		expectations:    expectations,
 		Estimator:       &nodesestimator.NodeEstimator{},
 		nodeProvisioner: provisioner,
+		identityChecker: workloadidentity.NewChecker(workloadidentity.NewTokenRequester(kubeClient)),
+		kubeClient:      kubeClient,
 	}
 }
 
 func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+	ctx = k8sclient.WithClientGoClient(ctx, c.kubeClient)
 	workspaceObj := &kaitov1beta1.Workspace{}
 	if err = c.Client.Get(ctx, req.NamespacedName, workspaceObj); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -125,6 +158,9 @@ func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 	}
 
 	defer func() {
+		if err != nil {
+			workspaceReconcileErrors.WithLabelValues(metriclabels.FleetValues(workspaceObj)...).Inc()
+		}
 		if syncErr := c.syncWorkspaceStatus(ctx, req.NamespacedName, err); syncErr != nil {
 			klog.ErrorS(syncErr, "failed to sync workspace status", "workspace", req.NamespacedName)
 			if err == nil {
@@ -144,12 +180,22 @@ func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 		return c.deleteWorkspace(ctx, workspaceObj)
 	}
 
+	// Nothing is changed while reconciliation is paused, neither the nodes nor the workload.
+	// The annotation change that resumes it triggers the next reconcile.
+	if kaitov1beta1.ReconcilePaused(workspaceObj) {
+		klog.InfoS("Reconciliation is paused", "workspace", req.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
 	if err = c.syncControllerRevision(ctx, workspaceObj); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	// update targetNodeCount for the workspace
 	if err = c.UpdateWorkspaceTargetNodeCount(ctx, workspaceObj); err != nil {
+		if result, err := circuitOpenResult(estimator.BreakerName, err); err == nil {
+			return *result, nil
+		}
 		return reconcile.Result{}, err
 	}
 
@@ -313,28 +359,45 @@ func (c *WorkspaceReconciler) reconcileNodes(ctx context.Context, wObj *kaitov1b
 	if err := c.guardTargetNodeCount(wObj); err != nil {
 		return &reconcile.Result{}, err
 	}
+	// Refuse to provision an instance type the cloud provider does not offer.
+	if err := c.guardInstanceType(wObj); err != nil {
+		return &reconcile.Result{}, err
+	}
 
 	// Provision nodes via the NodeProvisioner interface.
-	// GpuProvisioner creates NodeClaims; BYOProvisioner (BYO mode) is a no-op.
+	// GpuProvisioner creates NodeClaims; BYOProvisioner (BYO mode) only labels opted-in preferred nodes.
 	if err := c.nodeProvisioner.ProvisionNodes(ctx, wObj); err != nil {
-		return &reconcile.Result{}, err
+		if !errors.Is(err, breaker.ErrOpen) {
+			observeProvisioningError(wObj, provisioningErrorReasonProvisionFailed)
+		}
+		return circuitOpenResult(nodeprovision.BreakerName, err)
 	}
 
 	// Check if nodes are ready.
 	ready, needRequeue, err := c.nodeProvisioner.EnsureNodesReady(ctx, wObj)
 	if err != nil {
-		return &reconcile.Result{}, err
+		return circuitOpenResult(nodeprovision.BreakerName, err)
 	}
 	if !ready {
 		if needRequeue {
 			return &reconcile.Result{RequeueAfter: 2 * time.Second}, nil
 		}
-		return &reconcile.Result{}, nil
+		return c.handleProvisioningTimeout(ctx, wObj)
 	}
 
 	return nil, nil
 }
 
+// circuitOpenResult requeues quietly once the named breaker has opened; the failures that
+// opened it were already logged. Other errors are returned as is.
+func circuitOpenResult(name string, err error) (*reconcile.Result, error) {
+	if errors.Is(err, breaker.ErrOpen) {
+		klog.V(4).InfoS("Skipping call to unavailable dependency", "dependency", name, "reason", err.Error())
+		return &reconcile.Result{RequeueAfter: breaker.Get(name).RetryAfter() + time.Second}, nil
+	}
+	return &reconcile.Result{}, err
+}
+
 func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *kaitov1beta1.Workspace) (reconcile.Result, error) {
 	workspaceKey := client.ObjectKeyFromObject(wObj).String()
 	if !c.expectations.SatisfiedExpectations(c.Log, workspaceKey) {
@@ -343,6 +406,53 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 		return reconcile.Result{}, nil
 	}
 
+	// In simulation mode syncWorkspaceStatus records the provisioning plan; nothing is created.
+	if simulationEnabled(wObj) {
+		klog.InfoS("Workspace is in simulation mode, skipping provisioning", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, nil
+	}
+
+	// A clone only needs the spec of the source, so it does not wait for its nodes.
+	if err := c.cloneWorkspace(ctx, wObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Do not provision GPU nodes for a model that cannot be downloaded yet.
+	gateMessage, err := c.modelAccessGate(ctx, wObj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if gateMessage != "" {
+		klog.InfoS("Model access is gated", "workspace", klog.KObj(wObj), "message", gateMessage)
+		if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeAccessGated)); cond == nil || cond.Message != gateMessage {
+			c.recordEvent(wObj, corev1.EventTypeWarning, "AccessGated", gateMessage)
+		}
+		return reconcile.Result{RequeueAfter: accessGateRequeueInterval}, nil
+	}
+
+	// Do not provision nodes for, or roll out, preset images that fail verification.
+	if message := c.imageVerificationMessage(ctx, wObj); message != "" {
+		klog.InfoS("Preset image verification failed", "workspace", klog.KObj(wObj), "message", message)
+		if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeImageVerificationFailed)); cond == nil || cond.Message != message {
+			c.recordEvent(wObj, corev1.EventTypeWarning, "ImageVerificationFailed", message)
+		}
+		return reconcile.Result{RequeueAfter: imageVerificationRequeueInterval}, nil
+	}
+
+	// The ServiceAccount carries the identity, so it is created before the identity is checked.
+	if err := c.ensureServiceAccount(ctx, wObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Do not provision nodes for a workspace whose identity cannot read its storage.
+	if message := c.workloadIdentityMessage(ctx, wObj); message != "" {
+		klog.InfoS("Workload identity is gated", "workspace", klog.KObj(wObj), "message", message)
+		if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated)); cond == nil || cond.Message != message {
+			c.recordEvent(wObj, corev1.EventTypeWarning, string(kaitov1beta1.WorkspaceConditionTypeWorkloadIdentityGated), message)
+		}
+		return reconcile.Result{RequeueAfter: workloadIdentityRequeueInterval}, nil
+	}
+
 	// Ensure ModelMirror CR exists (starts download in parallel with node provisioning).
 	if modelstreaming.ModelStreamingEnabled(wObj) && wObj.Inference != nil && wObj.Inference.Preset != nil {
 		if err := c.ensureModelMirror(ctx, wObj); err != nil {
@@ -365,13 +475,43 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 		if err := c.applyTuning(ctx, wObj); err != nil {
 			return reconcile.Result{}, err
 		}
-	} else if wObj.Inference != nil {
+		if err := c.recordTuningLineage(ctx, wObj); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := c.deployTunedAdapter(ctx, wObj); err != nil {
+			return reconcile.Result{}, err
+		}
+		pruneAfter, err := c.pruneTuningJob(ctx, wObj)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		result := tuningProgressResult(wObj)
+		if pruneAfter > 0 && (result.RequeueAfter == 0 || pruneAfter < result.RequeueAfter) {
+			result.RequeueAfter = pruneAfter
+		}
+		return result, nil
+	}
+	if wObj.Inference != nil {
 		if err := c.ensureService(ctx, wObj); err != nil {
 			return reconcile.Result{}, err
 		}
-		if err := c.applyInference(ctx, wObj); err != nil {
+		rolloutErr := c.applyInference(ctx, wObj)
+		if rolloutErr != nil && !errors.Is(rolloutErr, errRolloutDeferred) {
+			return reconcile.Result{}, rolloutErr
+		}
+		if err := c.recreateGroupOnPodRestart(ctx, wObj); err != nil {
 			return reconcile.Result{}, err
 		}
+		// The cache only saves a profiling run, so a failure does not fail the reconcile.
+		if err := c.recordInferenceProfile(ctx, wObj); err != nil {
+			klog.ErrorS(err, "Failed to cache the inference profile", "workspace", klog.KObj(wObj))
+		}
+		if err := c.recordCachedModel(ctx, wObj); err != nil {
+			klog.ErrorS(err, "Failed to record the cached model on the worker nodes", "workspace", klog.KObj(wObj))
+		}
+		if rolloutErr != nil {
+			return reconcile.Result{RequeueAfter: time.Until(wObj.MaintenanceWindow.NextOpen(time.Now()))}, nil
+		}
 	}
 
 	return reconcile.Result{}, nil
@@ -379,6 +519,9 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 
 func (c *WorkspaceReconciler) deleteWorkspace(ctx context.Context, wObj *kaitov1beta1.Workspace) (reconcile.Result, error) {
 	klog.InfoS("deleteWorkspace", "workspace", klog.KObj(wObj))
+	if c.identityChecker != nil {
+		c.identityChecker.Forget(wObj)
+	}
 	return c.garbageCollectWorkspace(ctx, wObj)
 }
 func (c *WorkspaceReconciler) syncControllerRevision(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
@@ -469,11 +612,33 @@ func (c *WorkspaceReconciler) syncControllerRevision(ctx context.Context, wObj *
 	return nil
 }
 
+// revisionTuning returns the tuning spec that makes up the revision of a workspace. The
+// retention only affects the cleanup of a run, so changing it does not restart tuning.
+func revisionTuning(t *kaitov1beta1.TuningSpec) *kaitov1beta1.TuningSpec {
+	if t == nil || t.Retention == nil {
+		return t
+	}
+	t = t.DeepCopy()
+	t.Retention = nil
+	return t
+}
+
 func marshalSelectedFields(wObj *kaitov1beta1.Workspace) ([]byte, error) {
 	partialMap := map[string]interface{}{
 		"resource":  wObj.Resource,
 		"inference": wObj.Inference,
-		"tuning":    wObj.Tuning,
+		"tuning":    revisionTuning(wObj.Tuning),
+	}
+
+	// Added only when set, so the revisions of existing workspaces do not change.
+	if wObj.Identity != nil {
+		partialMap["identity"] = wObj.Identity
+	}
+	if wObj.ServiceAccount != nil {
+		partialMap["serviceAccount"] = wObj.ServiceAccount
+	}
+	if wObj.MetadataPropagation != nil {
+		partialMap["metadataPropagation"] = propagatedPodMetadata(wObj)
 	}
 
 	jsonData, err := json.Marshal(partialMap)
@@ -489,7 +654,17 @@ func ComputeHash(w *kaitov1beta1.Workspace) string {
 	encoder := json.NewEncoder(hasher)
 	encoder.Encode(w.Resource)
 	encoder.Encode(w.Inference)
-	encoder.Encode(w.Tuning)
+	encoder.Encode(revisionTuning(w.Tuning))
+	// Hashed only when set, so the hashes of existing workspaces do not change.
+	if w.Identity != nil {
+		encoder.Encode(w.Identity)
+	}
+	if w.ServiceAccount != nil {
+		encoder.Encode(w.ServiceAccount)
+	}
+	if w.MetadataPropagation != nil {
+		encoder.Encode(propagatedPodMetadata(w))
+	}
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
@@ -505,29 +680,148 @@ func (c *WorkspaceReconciler) ensureService(ctx context.Context, wObj *kaitov1be
 	}
 
 	serviceObj := manifests.GenerateServiceManifest(wObj, serviceType)
-	if err := resources.GetResource(ctx, serviceObj.Name, serviceObj.Namespace, c.Client, &corev1.Service{}); err != nil {
+	existingService := &corev1.Service{}
+	if err := resources.GetResource(ctx, serviceObj.Name, serviceObj.Namespace, c.Client, existingService); err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
+		if _, err := c.adoptedServiceSelector(ctx, wObj, nil, serviceObj); err != nil {
+			return err
+		}
 		if err := resources.CreateResource(ctx, serviceObj, c.Client); err != nil {
 			return err
 		}
+	} else {
+		// Services created before the workspace was deleted and re-created, or by older
+		// versions, may lack ownership; adopt them so they are garbage collected.
+		adopted, err := resources.AdoptResource(existingService, serviceObj)
+		if err != nil {
+			return err
+		}
+		// The options also apply once inference.service is removed, which restores the defaults.
+		optionsChanged := applyServiceOptions(existingService, serviceObj)
+		if wObj.Inference.Service != nil {
+			optionsChanged = applyServiceDNS(existingService, serviceObj) || optionsChanged
+		}
+		// Annotations are also pruned once inference.service is removed altogether.
+		optionsChanged = applyServiceAnnotations(existingService, serviceObj) || optionsChanged
+		optionsChanged = syncTokenizerPort(existingService, serviceObj) || optionsChanged
+		narrowed, err := c.adoptedServiceSelector(ctx, wObj, existingService, serviceObj)
+		if err != nil {
+			return err
+		}
+		if (narrowed || isAdoptedServiceSelector(existingService.Spec.Selector)) &&
+			!maps.Equal(existingService.Spec.Selector, serviceObj.Spec.Selector) {
+			existingService.Spec.Selector = serviceObj.Spec.Selector
+			optionsChanged = true
+		}
+		if optionsChanged {
+			klog.InfoS("Updating inference service options", "workspace", klog.KObj(wObj), "service", serviceObj.Name)
+		}
+		propagated := applyPropagatedMetadata(&existingService.ObjectMeta, &serviceObj.ObjectMeta)
+		if adopted || optionsChanged || propagated {
+			if err := c.Update(ctx, existingService); err != nil {
+				return fmt.Errorf("failed to update service %s: %w", serviceObj.Name, err)
+			}
+		}
+	}
+
+	if err := c.ensureServiceExport(ctx, wObj); err != nil {
+		return err
 	}
 
 	// headless service for worker pod to discover the leader pod
 	headlessService := manifests.GenerateHeadlessServiceManifest(wObj)
-	if err := resources.GetResource(ctx, headlessService.Name, headlessService.Namespace, c.Client, &corev1.Service{}); err != nil {
+	existingHeadless := &corev1.Service{}
+	if err := resources.GetResource(ctx, headlessService.Name, headlessService.Namespace, c.Client, existingHeadless); err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
 		if err := resources.CreateResource(ctx, headlessService, c.Client); err != nil {
 			return err
 		}
+	} else if adopted, err := resources.AdoptResource(existingHeadless, headlessService); err != nil {
+		return err
+	} else if propagated := applyPropagatedMetadata(&existingHeadless.ObjectMeta, &headlessService.ObjectMeta); adopted || propagated {
+		if err := c.Update(ctx, existingHeadless); err != nil {
+			return fmt.Errorf("failed to update service %s: %w", headlessService.Name, err)
+		}
 	}
 
 	return nil
 }
 
+// syncTokenizerPort adds, updates or removes the tokenizer port of existing to match desired
+// and reports whether existing changed. The node port allocated to the port is kept.
+func syncTokenizerPort(existing, desired *corev1.Service) bool {
+	isTokenizer := func(p corev1.ServicePort) bool { return p.Name == manifests.TokenizerServicePortName }
+	i := slices.IndexFunc(existing.Spec.Ports, isTokenizer)
+	j := slices.IndexFunc(desired.Spec.Ports, isTokenizer)
+	switch {
+	case i < 0 && j < 0:
+		return false
+	case j < 0:
+		existing.Spec.Ports = slices.Delete(existing.Spec.Ports, i, i+1)
+		return true
+	case i < 0:
+		existing.Spec.Ports = append(existing.Spec.Ports, desired.Spec.Ports[j])
+		return true
+	}
+	current, want := &existing.Spec.Ports[i], desired.Spec.Ports[j]
+	if current.Port == want.Port && current.TargetPort == want.TargetPort {
+		return false
+	}
+	current.Port, current.TargetPort = want.Port, want.TargetPort
+	return true
+}
+
+// applyServiceOptions copies the fields set through inference.service from desired to
+// existing and reports whether existing changed. Fields and annotations that are not part
+// of inference.service are left alone, so values set by the cloud provider are kept.
+func applyServiceOptions(existing, desired *corev1.Service) bool {
+	changed := false
+	if existing.Spec.Type != desired.Spec.Type {
+		existing.Spec.Type = desired.Spec.Type
+		// Fields only allowed for the previous type must be cleared, or the update is rejected.
+		if existing.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			existing.Spec.AllocateLoadBalancerNodePorts = nil
+			existing.Spec.LoadBalancerClass = nil
+			existing.Spec.HealthCheckNodePort = 0
+		}
+		if existing.Spec.Type == corev1.ServiceTypeClusterIP {
+			existing.Spec.ExternalTrafficPolicy = ""
+			for i := range existing.Spec.Ports {
+				existing.Spec.Ports[i].NodePort = 0
+			}
+		}
+		changed = true
+	}
+	if desired.Spec.IPFamilyPolicy != nil && !apiequality.Semantic.DeepEqual(existing.Spec.IPFamilyPolicy, desired.Spec.IPFamilyPolicy) {
+		existing.Spec.IPFamilyPolicy = desired.Spec.IPFamilyPolicy
+		changed = true
+	}
+	// The traffic policies follow inference.service, so removing them restores the defaults.
+	desiredInternalPolicy := ptr.Deref(desired.Spec.InternalTrafficPolicy, corev1.ServiceInternalTrafficPolicyCluster)
+	if ptr.Deref(existing.Spec.InternalTrafficPolicy, corev1.ServiceInternalTrafficPolicyCluster) != desiredInternalPolicy {
+		existing.Spec.InternalTrafficPolicy = ptr.To(desiredInternalPolicy)
+		changed = true
+	}
+	if !apiequality.Semantic.DeepEqual(existing.Spec.TrafficDistribution, desired.Spec.TrafficDistribution) {
+		existing.Spec.TrafficDistribution = desired.Spec.TrafficDistribution
+		changed = true
+	}
+	return changed
+}
+
+// applyServiceAnnotations syncs the annotations set from inference.service.annotations,
+// removing the ones KAITO set before that are no longer in the spec. Other annotations,
+// e.g. those added by cloud providers, are kept.
+func applyServiceAnnotations(existing, desired *corev1.Service) bool {
+	changed := syncPropagated(&existing.Annotations, desired.Annotations,
+		existing.Annotations[kaitov1beta1.AnnotationServiceAnnotations], desired.Annotations[kaitov1beta1.AnnotationServiceAnnotations])
+	return syncKey(&existing.Annotations, desired.Annotations, kaitov1beta1.AnnotationServiceAnnotations) || changed
+}
+
 func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
 	if wObj.Tuning == nil || wObj.Tuning.Preset == nil {
 		return nil
@@ -541,9 +835,17 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1beta
 	}
 	revisionNum := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
 
+	if err := c.ensurePodGroup(ctx, wObj, 1); err != nil {
+		return err
+	}
+
 	existingObj := &batchv1.Job{}
 	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err != nil {
 		if apierrors.IsNotFound(err) {
+			// A finished Job deleted by tuning.retention is not run again.
+			if tuningJobFinished(wObj) {
+				return nil
+			}
 			_, err = tuning.CreatePresetTuning(ctx, wObj, revisionNum, model, c.Client)
 			return err
 		}
@@ -564,13 +866,19 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1beta
 	return err
 }
 
+// errRolloutDeferred is returned by applyInference when the rollout of a new revision waits
+// for the maintenance window of the workspace.
+var errRolloutDeferred = errors.New("rollout deferred to the maintenance window")
+
 // applyInference applies inference spec.
 func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
 	// From v0.8.0 onwards, StatefulSet is the default workload for all workspaces.
 	// This block purges existing Deployments and migrates them to StatefulSets later.
 	// WARNING: This migration will cause a few minutes of service downtime.
+	// An adopted Deployment is left running until the StatefulSet is ready instead.
 	existingDeploy := appsv1.Deployment{}
-	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, &existingDeploy); err == nil {
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, &existingDeploy); err == nil &&
+		!isAdopting(wObj, kaitov1beta1.AdoptWorkloadKindDeployment, wObj.Name) {
 		c.Recorder.Eventf(wObj, "Warning", "WorkloadMigration",
 			"Migrating inference workload from Deployment to StatefulSet, this will cause a few minutes of downtime.")
 		klog.InfoS("Delete existing deployment workload for workspace", "workspace", klog.KObj(wObj))
@@ -582,7 +890,7 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 		if err != nil && !apierrors.IsNotFound(err) {
 			return fmt.Errorf("failed to delete old inference deployment: %w", err)
 		}
-	} else if !apierrors.IsNotFound(err) {
+	} else if err != nil && !apierrors.IsNotFound(err) {
 		return fmt.Errorf("failed to get existing inference deployment: %w", err)
 	}
 
@@ -618,6 +926,21 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 		return fmt.Errorf("failed to generate statefulset workload for inference")
 	}
 
+	if err := c.ensurePodGroup(ctx, wObj, ptr.Deref(desiredStatefulSet.Spec.Replicas, 1)); err != nil {
+		return err
+	}
+
+	adoptKind, adoptName, adopting := kaitov1beta1.GetAdoptedWorkload(wObj)
+	if adopting && adoptKind == kaitov1beta1.AdoptWorkloadKindDeployment {
+		if err := c.reconcileAdoptedDeployment(ctx, wObj, adoptName, desiredStatefulSet); err != nil {
+			return err
+		}
+	} else if !adopting {
+		if err := c.clearWorkloadAdoptedCondition(ctx, wObj); err != nil {
+			return err
+		}
+	}
+
 	existingObj := &appsv1.StatefulSet{}
 	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -626,7 +949,21 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 		return err
 	}
 
+	adopted, releasedAdoption, err := c.reconcileAdoptedStatefulSet(ctx, wObj, existingObj, desiredStatefulSet)
+	if adopted || err != nil {
+		return err
+	}
+
 	klog.InfoS("An inference workload already exists for workspace", "workspace", klog.KObj(wObj))
+	if err := c.completeInPlaceResize(ctx, wObj, existingObj); err != nil {
+		return err
+	}
+	inPlaceResize, err := c.resizeInferencePods(ctx, wObj,
+		&existingObj.Spec.Template.Spec.Containers[0], &desiredStatefulSet.Spec.Template.Spec.Containers[0])
+	if err != nil {
+		return err
+	}
+
 	annotations := existingObj.GetAnnotations()
 	if annotations == nil {
 		annotations = make(map[string]string)
@@ -637,11 +974,30 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 
 	// If the current workload revision matches the one in Workspace and no upgrade is pending,
 	// we do not need to update it.
-	if ok && currentRevisionStr == revisionStr && !baseImageUpgrade {
-		return nil
+	if ok && currentRevisionStr == revisionStr && !baseImageUpgrade && !releasedAdoption {
+		return workspace.RemovePendingOperation(ctx, c.Client, wObj, kaitov1beta1.PendingOperationRollout)
+	}
+
+	// Rolling the StatefulSet restarts the inference pods, so it waits for the maintenance
+	// window. Releasing an adopted workload was asked for explicitly and is not deferred.
+	if now := time.Now(); !releasedAdoption && !wObj.MaintenanceWindow.IsOpen(now) {
+		nextWindow := metav1.NewTime(wObj.MaintenanceWindow.NextOpen(now))
+		klog.InfoS("Deferring inference workload rollout to the maintenance window", "workspace", klog.KObj(wObj), "nextWindow", nextWindow)
+		if err := workspace.SetPendingOperation(ctx, c.Client, wObj, kaitov1beta1.PendingOperation{
+			Type:       kaitov1beta1.PendingOperationRollout,
+			Message:    fmt.Sprintf("rollout of revision %s to StatefulSet %s", revisionStr, existingObj.Name),
+			NextWindow: &nextWindow,
+		}); err != nil {
+			return err
+		}
+		return errRolloutDeferred
 	}
 
-	if baseImageUpgrade {
+	if releasedAdoption {
+		releaseAdoptedStatefulSet(existingObj, desiredStatefulSet)
+		c.recordEvent(wObj, corev1.EventTypeNormal, "AdoptedWorkloadReleased",
+			fmt.Sprintf("Rolling StatefulSet %s to the rendered spec", existingObj.Name))
+	} else if baseImageUpgrade {
 		// On base image upgrade, update all mutable fields of the StatefulSet
 		// https://github.com/kubernetes/kubernetes/blob/master/pkg/apis/apps/validation/validation.go#L268C1-L269C1
 		existingObj.Spec.Template = desiredStatefulSet.Spec.Template
@@ -653,14 +1009,19 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 	} else {
 		// Selectively update the pod spec fields that are relevant to inference,
 		// and leave the rest unchanged in case user has customized them.
-		desiredPodSpec := desiredStatefulSet.Spec.Template.Spec
-		spec := &existingObj.Spec.Template.Spec
-		spec.Containers[0].Env = desiredPodSpec.Containers[0].Env
-		spec.Containers[0].VolumeMounts = desiredPodSpec.Containers[0].VolumeMounts
-		spec.InitContainers = desiredPodSpec.InitContainers
-		spec.Volumes = desiredPodSpec.Volumes
+		original := existingObj.Spec.Template.DeepCopy()
+		syncInferencePodSpec(&existingObj.Spec.Template.Spec, &desiredStatefulSet.Spec.Template.Spec)
+		applyPropagatedMetadata(&existingObj.Spec.Template.ObjectMeta, &desiredStatefulSet.Spec.Template.ObjectMeta)
+		// The pods already run with the new requests, so only the template is updated.
+		if inPlaceResize && onlyComputeRequestsChanged(original, &existingObj.Spec.Template) {
+			holdRollout(existingObj)
+		}
 	}
 
+	annotations = existingObj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
 	annotations[kaitov1beta1.WorkspaceRevisionAnnotation] = revisionStr
 	existingObj.SetAnnotations(annotations)
 
@@ -668,6 +1029,9 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 	if err := c.Update(ctx, existingObj); err != nil {
 		return err
 	}
+	if err := workspace.RemovePendingOperation(ctx, c.Client, wObj, kaitov1beta1.PendingOperationRollout); err != nil {
+		return err
+	}
 
 	// After a base image auto-upgrade, clear the recorded benchmark result and reset
 	// the BenchmarkCompleted condition so the post-load benchmark re-runs against the
@@ -681,14 +1045,75 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 	return nil
 }
 
-// shouldUpgradeBaseImage checks if an auto-upgrade has been requested via the upgrade label
-// and the image hasn't been updated yet. The label value must match the controller's
-// current desired base image tag to prevent stale labels from triggering upgrades.
+// syncInferencePodSpec copies the pod spec fields that are rendered from the inference spec
+// from desired to spec. The other fields are left unchanged in case the user customized them.
+func syncInferencePodSpec(spec, desired *corev1.PodSpec) {
+	spec.Containers[0].Command = desired.Containers[0].Command
+	spec.Containers[0].Args = desired.Containers[0].Args
+	spec.Containers[0].ImagePullPolicy = desired.Containers[0].ImagePullPolicy
+	// The sidecars in front of the inference server move it to another port.
+	spec.Containers[0].Ports = desired.Containers[0].Ports
+	spec.Containers[0].Env = desired.Containers[0].Env
+	spec.Containers[0].VolumeMounts = desired.Containers[0].VolumeMounts
+	spec.Containers[0].TerminationMessagePolicy = desired.Containers[0].TerminationMessagePolicy
+	spec.Containers[0].Lifecycle = desired.Containers[0].Lifecycle
+	spec.Containers[0].StartupProbe = desired.Containers[0].StartupProbe
+	spec.Containers[0].LivenessProbe = desired.Containers[0].LivenessProbe
+	spec.Containers[0].ReadinessProbe = desired.Containers[0].ReadinessProbe
+	syncEphemeralStorage(&spec.Containers[0].Resources, &desired.Containers[0].Resources)
+	syncComputeRequests(&spec.Containers[0].Resources, &desired.Containers[0].Resources)
+	spec.InitContainers = desired.InitContainers
+	spec.Volumes = desired.Volumes
+	spec.ServiceAccountName = desired.ServiceAccountName
+	spec.TerminationGracePeriodSeconds = desired.TerminationGracePeriodSeconds
+	spec.RuntimeClassName = desired.RuntimeClassName
+	spec.Affinity = desired.Affinity
+	spec.Tolerations = desired.Tolerations
+	syncContainerByName(spec, desired, manifests.LogForwarderContainerName)
+	syncContainerByName(spec, desired, consts.ResponseCacheContainerName)
+	syncContainerByName(spec, desired, consts.TokenizerContainerName)
+	// apiNormalization cannot be set or unset, so the sidecar is only tuned here.
+	syncContainerByName(spec, desired, consts.APINormalizerContainerName)
+}
+
+// syncContainerByName makes the container called name in spec match the one in desired:
+// it is replaced when present in both, appended when only desired has it, and removed
+// when desired no longer renders it. Used for KAITO-managed sidecars whose presence is
+// driven by the Workspace spec.
+func syncContainerByName(spec, desired *corev1.PodSpec, name string) {
+	desiredIdx := -1
+	for i := range desired.Containers {
+		if desired.Containers[i].Name == name {
+			desiredIdx = i
+			break
+		}
+	}
+
+	containers := make([]corev1.Container, 0, len(spec.Containers)+1)
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != name {
+			containers = append(containers, spec.Containers[i])
+		}
+	}
+	if desiredIdx >= 0 {
+		containers = append(containers, desired.Containers[desiredIdx])
+	}
+	spec.Containers = containers
+}
+
+// shouldUpgradeBaseImage checks if an auto-upgrade has been requested via the upgrade label,
+// or the workspace pins a base image digest, and the image hasn't been updated yet. The
+// label value must match the controller's current desired base image tag to prevent stale
+// labels from triggering upgrades.
 func shouldUpgradeBaseImage(wObj *kaitov1beta1.Workspace, existingObj, desiredStatefulSet *appsv1.StatefulSet) bool {
+	if workspace.GetInferenceContainerImage(existingObj) == workspace.GetInferenceContainerImage(desiredStatefulSet) {
+		return false
+	}
+	if _, ok := kaitov1beta1.GetPinnedRuntimeDigest(wObj); ok {
+		return true
+	}
 	upgradeVersion := wObj.Labels[kaitov1alpha1.LabelUpgradeToVersion]
-	return upgradeVersion != "" &&
-		upgradeVersion == inference.GetBaseImageTag() &&
-		workspace.GetInferenceContainerImage(existingObj) != workspace.GetInferenceContainerImage(desiredStatefulSet)
+	return upgradeVersion != "" && upgradeVersion == inference.GetBaseImageTag()
 }
 
 func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types.NamespacedName, reconcileErr error) error {
@@ -700,6 +1125,17 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 		return err
 	}
 
+	if reason, message := kaitov1beta1.PauseTransition(wObj.Status.Conditions, wObj); reason != "" {
+		c.recordEvent(wObj, corev1.EventTypeNormal, reason, message)
+	}
+	// The status of a paused workspace is kept as it was, apart from the Paused condition.
+	if wObj.DeletionTimestamp.IsZero() && kaitov1beta1.ReconcilePaused(wObj) {
+		return c.updateWorkspaceStatusIfChanged(ctx, key, func(status *kaitov1beta1.WorkspaceStatus) error {
+			kaitov1beta1.ApplyPausedCondition(&status.Conditions, wObj)
+			return nil
+		})
+	}
+
 	nodeSnapshot, err := c.collectNodeStatusSnapshot(ctx, wObj)
 	if err != nil {
 		return err
@@ -715,6 +1151,56 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 		return err
 	}
 
+	accessGateMessage, err := c.modelAccessGate(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	imageVerificationMessage := c.imageVerificationMessage(ctx, wObj)
+	deprecationMessage := presetDeprecationMessage(wObj, time.Now())
+	if deprecationMessage != "" {
+		if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeDeprecated)); cond == nil || cond.Message != deprecationMessage {
+			c.recordEvent(wObj, corev1.EventTypeWarning, "PresetDeprecated", deprecationMessage)
+		}
+	}
+	var workloadIdentityMessage string
+	if wObj.DeletionTimestamp.IsZero() {
+		workloadIdentityMessage = c.workloadIdentityMessage(ctx, wObj)
+	}
+	var provisioningPlan *kaitov1beta1.ProvisioningPlan
+	if wObj.DeletionTimestamp.IsZero() && simulationEnabled(wObj) {
+		if provisioningPlan, err = c.simulateProvisioning(ctx, wObj); err != nil {
+			return err
+		}
+		if previous := wObj.Status.ProvisioningPlan; previous == nil || previous.Message != provisioningPlan.Message {
+			c.recordEvent(wObj, corev1.EventTypeNormal, "ProvisioningSimulated", provisioningPlan.Message)
+		}
+	}
+
+	gangSnapshot, err := c.collectGangAdmission(ctx, wObj)
+	if err != nil {
+		return err
+	}
+
+	coldStart, err := c.collectColdStart(ctx, wObj)
+	if err != nil {
+		return err
+	}
+
+	replicas, err := c.collectReplicaStatuses(ctx, wObj)
+	if err != nil {
+		return err
+	}
+
+	pressure, err := c.collectDiskPressure(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	if pressure != nil {
+		if cond := meta.FindStatusCondition(wObj.Status.Conditions, string(kaitov1beta1.WorkspaceConditionTypeDiskTooSmall)); cond == nil || cond.Message != pressure.message {
+			c.recordEvent(wObj, corev1.EventTypeWarning, string(kaitov1beta1.WorkspaceConditionTypeDiskTooSmall), pressure.message)
+		}
+	}
+
 	// benchmarkApplicable gates the benchmark on the *running* pod: it requires both
 	// that the workspace should benchmark and that the StatefulSet actually
 	// carries the benchmark startup probe. Legacy workspaces created before the
@@ -723,14 +1209,22 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 
 	appendReconcileErrMessage := buildReconcileErrMessageAppender(reconcileErr)
 
-	return c.updateWorkspaceStatusIfChanged(ctx, key, func(status *kaitov1beta1.WorkspaceStatus) error {
+	var provisionTimeout *metav1.Condition
+	err = c.updateWorkspaceStatusIfChanged(ctx, key, func(status *kaitov1beta1.WorkspaceStatus) error {
+		provisionTimeout = nil
+		kaitov1beta1.ApplyPausedCondition(&status.Conditions, wObj)
 		if !wObj.DeletionTimestamp.IsZero() {
+			reason, message := "workspaceDeleted", "workspace is being deleted"
+			if status.TeardownStep != "" {
+				reason, message = string(status.TeardownStep), fmt.Sprintf("workspace is being deleted, teardown step %s", status.TeardownStep)
+			}
 			setWorkspaceCondition(status, wObj.GetGeneration(), appendReconcileErrMessage,
-				kaitov1beta1.WorkspaceConditionTypeDeleting, metav1.ConditionTrue, "workspaceDeleted", "workspace is being deleted")
+				kaitov1beta1.WorkspaceConditionTypeDeleting, metav1.ConditionTrue, reason, message)
 			return nil
 		}
 
 		status.WorkerNodes = nodeSnapshot.workerNodeNames
+		status.Zones = nodeSnapshot.zones
 
 		// Merge node conditions from provisioner: set returned conditions,
 		// remove any known node condition type that was not returned.
@@ -747,6 +1241,8 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 				meta.RemoveStatusCondition(&status.Conditions, t)
 			}
 		}
+		applyProvisioningTimeoutCondition(status, wObj, time.Now())
+		provisionTimeout = meta.FindStatusCondition(status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout))
 
 		// Extract ResourceStatus condition status for downstream use.
 		resourceConditionStatus := metav1.ConditionFalse
@@ -754,12 +1250,20 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 			resourceConditionStatus = rc.Status
 		}
 
+		applyGangAdmittedCondition(status, wObj, gangSnapshot)
+		applyImageVerificationCondition(status, wObj, imageVerificationMessage)
+		applyDeprecatedCondition(status, wObj, deprecationMessage)
+		applyWorkloadIdentityCondition(status, wObj, workloadIdentityMessage)
+		applyProvisioningPlan(status, provisioningPlan, metav1.Now())
+
 		if wObj.Tuning != nil {
 			applyTuningWorkspaceStatus(status, wObj.GetGeneration(), appendReconcileErrMessage, tuningSnapshot)
 			return nil
 		}
 
 		if wObj.Inference != nil {
+			applyAccessGatedCondition(status, wObj, accessGateMessage)
+			applyDiskTooSmallCondition(status, wObj, pressure)
 			if modelstreaming.ModelStreamingEnabled(wObj) && wObj.Inference.Preset != nil {
 
 				modelID := modelstreaming.ResolveHFModelID(wObj)
@@ -798,15 +1302,26 @@ func (c *WorkspaceReconciler) syncWorkspaceStatus(ctx context.Context, key types
 			}
 
 			applyInferenceWorkspaceStatus(ctx, status, wObj, appendReconcileErrMessage, inferenceReady, resourceConditionStatus, benchmarkApplicable, infFailReason, infFailMsg)
+			status.ColdStart = coldStart
+			status.Replicas = replicas
 			return nil
 		}
 
 		return nil
 	})
+	if err == nil && wObj.Inference != nil {
+		observeColdStart(wObj, wObj.Status.ColdStart, coldStart)
+	}
+	// Count each timeout once, when the condition is first set.
+	if err == nil && provisionTimeout != nil && !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1beta1.ConditionTypeNodeClaimProvisionTimeout)) {
+		observeProvisioningError(wObj, provisionTimeout.Reason)
+	}
+	return err
 }
 
 type nodeStatusSnapshot struct {
 	workerNodeNames []string
+	zones           []kaitov1beta1.ZoneNodeCount
 	conditions      []metav1.Condition
 }
 
@@ -835,6 +1350,7 @@ func (c *WorkspaceReconciler) collectNodeStatusSnapshot(ctx context.Context, wOb
 		snapshot.workerNodeNames = append(snapshot.workerNodeNames, nodeList.Items[i].Name)
 	}
 	sort.Strings(snapshot.workerNodeNames)
+	snapshot.zones = countNodesPerZone(nodeList.Items)
 
 	// Delegate status condition collection to the NodeProvisioner.
 	snapshot.conditions, err = c.nodeProvisioner.CollectNodeStatusInfo(ctx, wObj)
@@ -845,6 +1361,26 @@ func (c *WorkspaceReconciler) collectNodeStatusSnapshot(ctx context.Context, wOb
 	return snapshot, nil
 }
 
+// countNodesPerZone counts the nodes per topology.kubernetes.io/zone label, sorted by zone.
+// Nodes without the label, e.g. BYO nodes of some on-premises clusters, are not counted.
+func countNodesPerZone(nodeList []corev1.Node) []kaitov1beta1.ZoneNodeCount {
+	counts := map[string]int32{}
+	for i := range nodeList {
+		if zone := nodeList[i].Labels[corev1.LabelTopologyZone]; zone != "" {
+			counts[zone]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	zones := make([]kaitov1beta1.ZoneNodeCount, 0, len(counts))
+	for zone, n := range counts {
+		zones = append(zones, kaitov1beta1.ZoneNodeCount{Zone: zone, Nodes: n})
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Zone < zones[j].Zone })
+	return zones
+}
+
 // collectInferenceReadyStatus reports whether the inference workload is ready and
 // whether its StatefulSet carries the benchmark startup probe (false for legacy,
 // pre-benchmark-feature workspaces).
@@ -931,6 +1467,10 @@ type tuningStatusSnapshot struct {
 	failed    bool
 	active    int32
 	ready     int32
+	// progress is the training progress logged by the tuning pod, nil if unknown.
+	progress *kaitov1beta1.TuningStatus
+	// finishedRevision is the revision of the Job once it succeeded or failed.
+	finishedRevision string
 }
 
 func (c *WorkspaceReconciler) collectTuningStatusSnapshot(ctx context.Context, wObj *kaitov1beta1.Workspace) (*tuningStatusSnapshot, error) {
@@ -942,7 +1482,7 @@ func (c *WorkspaceReconciler) collectTuningStatusSnapshot(ctx context.Context, w
 	job := &batchv1.Job{}
 	if err := c.Get(ctx, types.NamespacedName{Name: wObj.Name, Namespace: wObj.Namespace}, job); err != nil {
 		if apierrors.IsNotFound(err) {
-			return snapshot, nil
+			return prunedTuningSnapshot(wObj), nil
 		}
 		return nil, err
 	}
@@ -954,6 +1494,14 @@ func (c *WorkspaceReconciler) collectTuningStatusSnapshot(ctx context.Context, w
 	snapshot.failed = job.Status.Failed > 0
 	snapshot.succeeded = job.Status.Succeeded > 0
 	snapshot.started = snapshot.succeeded || snapshot.ready > 0 || snapshot.active > 0
+	if snapshot.succeeded || snapshot.failed {
+		snapshot.finishedRevision = job.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
+	}
+
+	// The final progress line is read once more after the Job finishes.
+	if snapshot.started && wObj.Status.State != kaitov1beta1.WorkspaceStateSucceeded && wObj.Status.State != kaitov1beta1.WorkspaceStateFailed {
+		snapshot.progress = c.collectTuningProgress(ctx, wObj)
+	}
 
 	return snapshot, nil
 }
@@ -979,6 +1527,16 @@ func setWorkspaceCondition(status *kaitov1beta1.WorkspaceStatus, generation int6
 }
 
 func applyTuningWorkspaceStatus(status *kaitov1beta1.WorkspaceStatus, generation int64, appendMessage func(string) string, snapshot *tuningStatusSnapshot) {
+	if snapshot.progress != nil {
+		status.Tuning = snapshot.progress
+	} else if !snapshot.started && !snapshot.failed {
+		// A Job recreated for a new revision starts over.
+		status.Tuning = nil
+	}
+	if snapshot.finishedRevision != "" {
+		status.FinishedTuningRevision = snapshot.finishedRevision
+	}
+
 	if snapshot.failed {
 		setWorkspaceCondition(status, generation, appendMessage,
 			kaitov1beta1.WorkspaceConditionTypeTuningJobStatus, metav1.ConditionFalse, "WorkspaceTuningJobStatusFailed", "tuning job failed")
@@ -1107,38 +1665,22 @@ func RuntimeMetadataConfig(runtimeName pkgmodel.RuntimeName, presetName string)
 }
 
 func (c *WorkspaceReconciler) updateWorkspaceStatusIfChanged(ctx context.Context, key types.NamespacedName, modifyFn func(*kaitov1beta1.WorkspaceStatus) error) error {
-	return retry.OnError(retry.DefaultRetry,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsConflict(err)
-		},
-		func() error {
-			wObj := &kaitov1beta1.Workspace{}
-			if err := c.Get(ctx, key, wObj); err != nil {
-				if apierrors.IsNotFound(err) {
-					return nil
-				}
+	return statuspatch.Update(ctx, c.Client, key, &kaitov1beta1.Workspace{}, func(wObj *kaitov1beta1.Workspace) error {
+		originalStatus := *wObj.Status.DeepCopy()
+		if modifyFn != nil {
+			if err := modifyFn(&wObj.Status); err != nil {
 				return err
 			}
+		}
+		applyRolloutStatus(wObj)
 
-			originalStatus := *wObj.Status.DeepCopy()
-			if modifyFn != nil {
-				if err := modifyFn(&wObj.Status); err != nil {
-					return err
-				}
-			}
-
-			if apiequality.Semantic.DeepEqual(originalStatus, wObj.Status) {
-				return nil
-			}
-
-			if klog.V(4).Enabled() {
-				klog.InfoS("Workspace status changed",
-					"workspace", key.String(),
-					"changes", formatWorkspaceStatusChanges(originalStatus, wObj.Status))
-			}
-
-			return c.Status().Update(ctx, wObj)
-		})
+		if klog.V(4).Enabled() && !apiequality.Semantic.DeepEqual(originalStatus, wObj.Status) {
+			klog.InfoS("Workspace status changed",
+				"workspace", key.String(),
+				"changes", formatWorkspaceStatusChanges(originalStatus, wObj.Status))
+		}
+		return nil
+	})
 }
 
 func formatWorkspaceStatusChanges(oldStatus, newStatus kaitov1beta1.WorkspaceStatus) string {
@@ -1151,6 +1693,9 @@ func formatWorkspaceStatusChanges(oldStatus, newStatus kaitov1beta1.WorkspaceSta
 	if oldStatus.State != newStatus.State {
 		changes = append(changes, fmt.Sprintf("state: %q -> %q", oldStatus.State, newStatus.State))
 	}
+	if oldStatus.Phase != newStatus.Phase {
+		changes = append(changes, fmt.Sprintf("phase: %q -> %q", oldStatus.Phase, newStatus.Phase))
+	}
 
 	oldConditionByType := make(map[string]metav1.Condition, len(oldStatus.Conditions))
 	newConditionByType := make(map[string]metav1.Condition, len(newStatus.Conditions))
@@ -1225,7 +1770,7 @@ func (c *WorkspaceReconciler) UpdateWorkspaceTargetNodeCount(ctx context.Context
 					wObj.Name, wObj.Inference.Config, cmErr)
 			} else if configData, exists := configMap.Data["inference_config.yaml"]; exists {
 				if contextSize, found := utils.ParseExplicitMaxModelLen(configData); found {
-					req.RuntimeProfile = estimator.RuntimeProfile{ContextSize: contextSize}
+					req.RuntimeProfile.ContextSize = contextSize
 				}
 			}
 		}
@@ -1233,7 +1778,11 @@ func (c *WorkspaceReconciler) UpdateWorkspaceTargetNodeCount(ctx context.Context
 		if err := workspace.UpdateWorkspaceStatus(ctx, c.Client, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, func(status *kaitov1beta1.WorkspaceStatus) error {
 			if wObj.Inference != nil {
 				if v1beta1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameVLLM {
-					targetNodeCount, err = c.Estimator.EstimateNodeCount(ctx, req, c.Client)
+					err = breaker.Get(estimator.BreakerName).Do(ctx, func(ctx context.Context) error {
+						var err error
+						targetNodeCount, err = c.Estimator.EstimateNodeCount(ctx, req, c.Client)
+						return err
+					})
 					if err != nil {
 						return fmt.Errorf("failed to calculate target node count: %w", err)
 					}
@@ -1275,6 +1824,38 @@ func (c *WorkspaceReconciler) guardTargetNodeCount(wObj *kaitov1beta1.Workspace)
 	return fmt.Errorf("%s", msg)
 }
 
+// guardInstanceType blocks provisioning when the instance type or a fallback instance
+// type is not in the SKU catalog of the cloud provider. The webhook rejects such
+// workspaces, but ones admitted by an older webhook would otherwise fail in the node
+// provisioner with a less helpful error.
+func (c *WorkspaceReconciler) guardInstanceType(wObj *kaitov1beta1.Workspace) error {
+	if workspace.IsNodeAutoProvisioningDisabled(&wObj.Resource) {
+		return nil
+	}
+	handler := sku.DefaultSKUHandler
+	if handler == nil {
+		h, err := sku.GetSKUHandler()
+		if err != nil {
+			return nil
+		}
+		handler = h
+	}
+	provider := os.Getenv("CLOUD_PROVIDER")
+	for _, instanceType := range append([]string{wObj.Resource.InstanceType}, wObj.Resource.FallbackInstanceTypes...) {
+		if instanceType == "" {
+			continue
+		}
+		if err := sku.CheckInstanceType(provider, handler, instanceType); err != nil {
+			msg := fmt.Sprintf("%v; node provisioning halted", err)
+			if c.Recorder != nil {
+				c.Recorder.Eventf(wObj, corev1.EventTypeWarning, "UnsupportedInstanceType", msg)
+			}
+			return fmt.Errorf("%s", msg)
+		}
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	c.Recorder = mgr.GetEventRecorderFor("Workspace")
@@ -1287,18 +1868,48 @@ func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{})
 
-	// Only watch NodeClaim resources if node auto-provisioning is enabled
-	if !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
-		bldr = bldr.Watches(&karpenterv1.NodeClaim{},
-			&nodeClaimEventHandler{
-				logger:         c.klogger,
-				expectations:   c.expectations,
-				enqueueHandler: enqueueWorkspaceForNodeClaim,
-			},
+	// Only watch NodeClaim resources if the node provisioner creates them; their CRD is
+	// absent in BYO and cluster autoscaler clusters. If the CRD is not installed yet, the
+	// watch is added once it is.
+	nodeClaimHandler := &nodeClaimEventHandler{
+		logger:         c.klogger,
+		expectations:   c.expectations,
+		enqueueHandler: enqueueWorkspaceForNodeClaim,
+	}
+	watchNodeClaims := !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] && consts.UsesNodeClaims()
+	deferNodeClaimWatch := watchNodeClaims && !c.nodeClaimsServed()
+	if watchNodeClaims && !deferNodeClaimWatch {
+		bldr = bldr.Watches(&karpenterv1.NodeClaim{}, nodeClaimHandler,
 			builder.WithPredicates(nodeclaim.NodeClaimPredicate),
 		)
 	}
 
+	// Watch the pods of workspaces to keep the per-replica status up to date.
+	bldr = bldr.Watches(&corev1.Pod{}, enqueueWorkspaceForPod,
+		builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			_, ok := o.GetLabels()[kaitov1beta1.LabelWorkspaceName]
+			return ok
+		})),
+	)
+
+	// Watch nodes to react to readiness, capacity and label changes of the nodes that
+	// workspaces run on or select, without reconciling on kubelet heartbeats.
+	bldr = bldr.Watches(&corev1.Node{}, enqueueWorkspacesForNode(c.Client),
+		builder.WithPredicates(nodeChangePredicate),
+	)
+
+	// Watch the PodGroups of gang scheduled workspaces to reflect their admission. Their
+	// CRDs come with the gang schedulers, so a kind that is not installed yet is watched
+	// once it is.
+	var deferredPodGroupCRDs []*crdwatch.Watcher
+	for _, crd := range c.PodGroupCRDs {
+		if crd.Available() {
+			bldr = bldr.Owns(podGroupObject(crd.GVK))
+		} else {
+			deferredPodGroupCRDs = append(deferredPodGroupCRDs, crd)
+		}
+	}
+
 	// Watch ModelMirror CRs to immediately reconcile workspaces when downloads complete.
 	if featuregates.FeatureGates[consts.FeatureFlagModelStreaming] {
 		bldr = bldr.Watches(&kaitov1alpha1.ModelMirror{},
@@ -1306,9 +1917,38 @@ func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		)
 	}
 
-	bldr = bldr.WithOptions(controller.Options{MaxConcurrentReconciles: 5})
+	options := controller.Options{MaxConcurrentReconciles: 5}
+	if featuregates.FeatureGates[consts.FeatureFlagWorkspacePriorityQueue] {
+		options.UsePriorityQueue = ptr.To(true)
+		options.NewQueue = newWorkspacePriorityQueue(mgr.GetCache(), mgr.GetLogger())
+	}
+	bldr = bldr.WithOptions(options)
 
 	go monitorWorkspaces(context.Background(), c.Client)
 
-	return bldr.Complete(c)
+	ctrlr, err := bldr.Build(c)
+	if err != nil {
+		return err
+	}
+	if deferNodeClaimWatch {
+		klog.InfoS("NodeClaim CRD is not installed, NodeClaims are watched once it is")
+		c.NodeClaimCRD.OnAvailable(func(context.Context) error {
+			return ctrlr.Watch(source.Kind(mgr.GetCache(), client.Object(&karpenterv1.NodeClaim{}), nodeClaimHandler,
+				nodeclaim.NodeClaimPredicate))
+		})
+	}
+	for _, crd := range deferredPodGroupCRDs {
+		klog.InfoS("PodGroup CRD is not installed, PodGroups are watched once it is", "kind", crd.GVK.String())
+		gvk := crd.GVK
+		crd.OnAvailable(func(context.Context) error {
+			return ctrlr.Watch(source.Kind(mgr.GetCache(), client.Object(podGroupObject(gvk)),
+				handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &kaitov1beta1.Workspace{}, handler.OnlyControllerOwner())))
+		})
+	}
+	return nil
+}
+
+// nodeClaimsServed reports whether the NodeClaim CRD is installed.
+func (c *WorkspaceReconciler) nodeClaimsServed() bool {
+	return c.NodeClaimCRD == nil || c.NodeClaimCRD.Available()
 }
@@ -19,13 +19,16 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -35,8 +38,13 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/api/v1beta1"
@@ -52,6 +60,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/workspace/inference"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming/registry"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 )
 
 func TestSelectWorkspaceNodes(t *testing.T) {
@@ -315,7 +324,7 @@ func TestSelectWorkspaceNodes(t *testing.T) {
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
-			selectedNodes := utils.SelectNodes(tc.qualified, tc.preferred, tc.previous, tc.count)
+			selectedNodes := utils.SelectNodes(tc.qualified, tc.preferred, tc.previous, "", tc.count)
 
 			selectedNodesArray := []string{}
 
@@ -340,10 +349,65 @@ func TestEnsureService(t *testing.T) {
 		expectedError error
 		workspace     *v1beta1.Workspace
 	}{
-		"Existing service is found for workspace": {
+		"Existing unowned services are adopted": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace-headless", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(s *corev1.Service) bool {
+					return v1.GetControllerOf(s) != nil && s.Labels[v1beta1.LabelWorkspaceName] == "testWorkspace"
+				}), mock.Anything).Return(nil).Twice()
+			},
+			expectedError: nil,
+			workspace:     test.MockWorkspaceDistributedModel,
+		},
+		"Tokenizer port is added to the service of a workspace without service options": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).
+					Run(func(args mock.Arguments) {
+						svc := args.Get(2).(*corev1.Service)
+						svc.Name = "testWorkspace"
+						svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt32(consts.PortInferenceServer)}}
+					}).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace-headless", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).
+					Run(func(args mock.Arguments) { args.Get(2).(*corev1.Service).Name = "testWorkspace-headless" }).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(s *corev1.Service) bool {
+					return s.Name == "testWorkspace" &&
+						slices.ContainsFunc(s.Spec.Ports, func(p corev1.ServicePort) bool { return p.Name == manifests.TokenizerServicePortName })
+				}), mock.Anything).Return(nil).Once()
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(s *corev1.Service) bool {
+					return s.Name == "testWorkspace-headless"
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+			workspace: func() *v1beta1.Workspace {
+				ws := test.MockWorkspaceDistributedModel.DeepCopy()
+				ws.Inference.Tokenizer = &v1beta1.TokenizerEndpointSpec{}
+				return ws
+			}(),
+		},
+		"Service options are restored to the defaults once inference.service is removed": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).
+					Run(func(args mock.Arguments) {
+						svc := args.Get(2).(*corev1.Service)
+						svc.Name = "testWorkspace"
+						svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+						svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+						svc.Spec.InternalTrafficPolicy = ptr.To(corev1.ServiceInternalTrafficPolicyLocal)
+						svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080, TargetPort: intstr.FromInt32(consts.PortInferenceServer)}}
+					}).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), types.NamespacedName{Name: "testWorkspace-headless", Namespace: "kaito"}, mock.IsType(&corev1.Service{}), mock.Anything).
+					Run(func(args mock.Arguments) { args.Get(2).(*corev1.Service).Name = "testWorkspace-headless" }).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(s *corev1.Service) bool {
+					return s.Name == "testWorkspace" &&
+						s.Spec.Type == corev1.ServiceTypeClusterIP &&
+						s.Spec.ExternalTrafficPolicy == "" &&
+						ptr.Deref(s.Spec.InternalTrafficPolicy, "") == corev1.ServiceInternalTrafficPolicyCluster &&
+						s.Spec.Ports[0].NodePort == 0
+				}), mock.Anything).Return(nil).Once()
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(s *corev1.Service) bool {
+					return s.Name == "testWorkspace-headless"
+				}), mock.Anything).Return(nil)
 			},
 			expectedError: nil,
 			workspace:     test.MockWorkspaceDistributedModel,
@@ -400,6 +464,100 @@ func TestEnsureService(t *testing.T) {
 
 }
 
+func TestApplyServiceOptions(t *testing.T) {
+	dualStack := corev1.IPFamilyPolicyPreferDualStack
+	existing := &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{"cloud-provider": "kept"}},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyCluster,
+			Ports:                 []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}},
+			IPFamilies:            []corev1.IPFamily{corev1.IPv4Protocol},
+		},
+	}
+	desired := &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{"service.beta.kubernetes.io/azure-load-balancer-internal": "true"}},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: &dualStack,
+			IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+		},
+	}
+
+	assert.True(t, applyServiceOptions(existing, desired))
+	assert.Equal(t, corev1.ServiceTypeClusterIP, existing.Spec.Type)
+	assert.Empty(t, existing.Spec.ExternalTrafficPolicy)
+	assert.Zero(t, existing.Spec.Ports[0].NodePort)
+	assert.Equal(t, &dualStack, existing.Spec.IPFamilyPolicy)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, existing.Spec.IPFamilies, "ipFamilies of an existing service are immutable")
+
+	assert.False(t, applyServiceOptions(existing, desired), "a second pass must not report changes")
+
+	t.Run("annotations follow the spec", func(t *testing.T) {
+		const internal = "service.beta.kubernetes.io/azure-load-balancer-internal"
+		existing := &corev1.Service{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{"cloud-provider": "kept"}}}
+		desired := &corev1.Service{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{
+			internal:                             "true",
+			v1beta1.AnnotationServiceAnnotations: internal,
+		}}}
+
+		assert.True(t, applyServiceAnnotations(existing, desired))
+		assert.Equal(t, "true", existing.Annotations[internal])
+		assert.Equal(t, internal, existing.Annotations[v1beta1.AnnotationServiceAnnotations])
+		assert.False(t, applyServiceAnnotations(existing, desired))
+
+		// Removing the annotation from the spec removes it from the service.
+		desired.Annotations = nil
+		assert.True(t, applyServiceAnnotations(existing, desired))
+		assert.Equal(t, map[string]string{"cloud-provider": "kept"}, existing.Annotations)
+	})
+
+	t.Run("traffic policies follow the spec", func(t *testing.T) {
+		existing := &corev1.Service{Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeClusterIP,
+			InternalTrafficPolicy: ptr.To(corev1.ServiceInternalTrafficPolicyCluster),
+		}}
+		desired := &corev1.Service{Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeClusterIP,
+			InternalTrafficPolicy: ptr.To(corev1.ServiceInternalTrafficPolicyLocal),
+			TrafficDistribution:   ptr.To(corev1.ServiceTrafficDistributionPreferSameZone),
+		}}
+
+		assert.True(t, applyServiceOptions(existing, desired))
+		assert.Equal(t, ptr.To(corev1.ServiceInternalTrafficPolicyLocal), existing.Spec.InternalTrafficPolicy)
+		assert.Equal(t, ptr.To(corev1.ServiceTrafficDistributionPreferSameZone), existing.Spec.TrafficDistribution)
+		assert.False(t, applyServiceOptions(existing, desired))
+
+		// Removing the options restores the defaults.
+		desired.Spec.InternalTrafficPolicy = nil
+		desired.Spec.TrafficDistribution = nil
+		assert.True(t, applyServiceOptions(existing, desired))
+		assert.Equal(t, ptr.To(corev1.ServiceInternalTrafficPolicyCluster), existing.Spec.InternalTrafficPolicy)
+		assert.Nil(t, existing.Spec.TrafficDistribution)
+	})
+
+	t.Run("tokenizer port follows the spec", func(t *testing.T) {
+		http := corev1.ServicePort{Name: "http", Port: 80, NodePort: 30080}
+		tokenizer := corev1.ServicePort{Name: manifests.TokenizerServicePortName, Port: 8080, TargetPort: intstr.FromInt32(consts.PortTokenizer)}
+		existing := &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{http}}}
+		desired := &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, Ports: []corev1.ServicePort{http, tokenizer}}}
+
+		assert.True(t, syncTokenizerPort(existing, desired))
+		assert.Equal(t, []corev1.ServicePort{http, tokenizer}, existing.Spec.Ports)
+		existing.Spec.Ports[1].NodePort = 30808
+		assert.False(t, syncTokenizerPort(existing, desired))
+
+		desired.Spec.Ports[1].Port = 9000
+		assert.True(t, syncTokenizerPort(existing, desired))
+		assert.Equal(t, int32(9000), existing.Spec.Ports[1].Port)
+		assert.Equal(t, int32(30808), existing.Spec.Ports[1].NodePort, "the node port is kept")
+
+		desired.Spec.Ports = []corev1.ServicePort{http}
+		assert.True(t, syncTokenizerPort(existing, desired))
+		assert.Equal(t, []corev1.ServicePort{http}, existing.Spec.Ports)
+	})
+}
+
 func TestApplyInferenceWithPreset(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
@@ -413,7 +571,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(errors.New("Failed to get resource"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceDistributedModel,
 			expectedError: errors.New("Failed to get resource"),
@@ -454,7 +612,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceWithPreset,
 			expectedError: nil,
@@ -488,7 +646,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 				c.On("Update", mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceDistributedModel,
 			expectedError: nil,
@@ -509,7 +667,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceWithPreset,
 			expectedError: nil,
@@ -554,6 +712,45 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 	}
 }
 
+func TestApplyInferenceUpdatesImagePull(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	t.Setenv("RELEASE_NAMESPACE", "kaito")
+
+	wObj := test.MockWorkspaceWithPreset.DeepCopy()
+	wObj.Status.TargetNodeCount = 1
+	wObj.Inference.ImagePull = &v1beta1.ImagePullSpec{
+		Policy:   corev1.PullAlways,
+		LazyPull: &v1beta1.LazyPullSpec{RuntimeClassName: "runc-stargz"},
+	}
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(test.NotFoundError())
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&storagev1.StorageClass{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
+			*ss = *test.MockStatefulSetUpdated.DeepCopy()
+			// Other tests update the shared mock, so the revision of the workload is reset.
+			ss.Annotations = map[string]string{v1beta1.WorkspaceRevisionAnnotation: "1"}
+		}).
+		Return(nil)
+	var updated *appsv1.StatefulSet
+	mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything).
+		Run(func(args mock.Arguments) { updated = args.Get(1).(*appsv1.StatefulSet) }).
+		Return(nil)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+	mockClient.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+
+	reconciler := &WorkspaceReconciler{Client: mockClient, Scheme: test.NewTestScheme(), Estimator: &nodesestimator.NodeEstimator{}}
+	require.NoError(t, reconciler.applyInference(context.Background(), wObj))
+
+	require.NotNil(t, updated)
+	podSpec := updated.Spec.Template.Spec
+	assert.Equal(t, corev1.PullAlways, podSpec.Containers[0].ImagePullPolicy)
+	assert.Equal(t, ptr.To("runc-stargz"), podSpec.RuntimeClassName)
+}
+
 func TestApplyInferenceWithTemplate(t *testing.T) {
 	testcases := map[string]struct {
 		callMocks     func(c *test.MockClient)
@@ -566,7 +763,7 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(errors.New("Failed to create deployment"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceWithInferenceTemplate,
 			expectedError: errors.New("Failed to create deployment"),
@@ -577,7 +774,7 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 			},
 			workspace:     *test.MockWorkspaceWithInferenceTemplate,
 			expectedError: nil,
@@ -867,7 +1064,7 @@ func TestUpdateWorkspaceTargetNodeCount(t *testing.T) {
 						ws.ObjectMeta = v1.ObjectMeta{Name: "test-workspace", Namespace: "default"}
 						ws.Status = v1beta1.WorkspaceStatus{TargetNodeCount: 0}
 					}).Return(nil).Once()
-				c.StatusMock.On("Update", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+				c.StatusMock.On("Patch", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
 					Run(func(args mock.Arguments) {
 						ws := args.Get(1).(*v1beta1.Workspace)
 						*updatedTarget = ws.Status.TargetNodeCount
@@ -890,7 +1087,7 @@ func TestUpdateWorkspaceTargetNodeCount(t *testing.T) {
 						ws.ObjectMeta = v1.ObjectMeta{Name: "test-workspace", Namespace: "default"}
 						ws.Status = v1beta1.WorkspaceStatus{TargetNodeCount: 0}
 					}).Return(nil).Once()
-				c.StatusMock.On("Update", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+				c.StatusMock.On("Patch", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
 					Run(func(args mock.Arguments) {
 						ws := args.Get(1).(*v1beta1.Workspace)
 						*updatedTarget = ws.Status.TargetNodeCount
@@ -917,6 +1114,30 @@ func TestUpdateWorkspaceTargetNodeCount(t *testing.T) {
 			expectedError:  true,
 			expectedTarget: 0,
 		},
+		"should retry transient estimator errors": {
+			workspace: &v1beta1.Workspace{
+				ObjectMeta: v1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+				Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "test-preset"}}},
+				Status:     v1beta1.WorkspaceStatus{TargetNodeCount: 0},
+			},
+			setupMocks: func(c *test.MockClient, e *mockEstimator, updatedTarget *int32) {
+				e.On("EstimateNodeCount", mock.Anything, mock.IsType(estimator.NodeEstimateRequest{}), mock.Anything).Return(int32(0), context.DeadlineExceeded).Once()
+				e.On("EstimateNodeCount", mock.Anything, mock.IsType(estimator.NodeEstimateRequest{}), mock.Anything).Return(int32(2), nil).Once()
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+					Run(func(args mock.Arguments) {
+						ws := args.Get(2).(*v1beta1.Workspace)
+						ws.ObjectMeta = v1.ObjectMeta{Name: "test-workspace", Namespace: "default"}
+						ws.Status = v1beta1.WorkspaceStatus{TargetNodeCount: 0}
+					}).Return(nil).Once()
+				c.StatusMock.On("Patch", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+					Run(func(args mock.Arguments) {
+						ws := args.Get(1).(*v1beta1.Workspace)
+						*updatedTarget = ws.Status.TargetNodeCount
+					}).Return(nil)
+			},
+			expectedError:  false,
+			expectedTarget: 2,
+		},
 		"should persist over-limit estimate without error (guard lives in reconcileNodes)": {
 			workspace: &v1beta1.Workspace{
 				ObjectMeta: v1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
@@ -931,7 +1152,7 @@ func TestUpdateWorkspaceTargetNodeCount(t *testing.T) {
 						ws.ObjectMeta = v1.ObjectMeta{Name: "test-workspace", Namespace: "default"}
 						ws.Status = v1beta1.WorkspaceStatus{TargetNodeCount: 0}
 					}).Return(nil).Once()
-				c.StatusMock.On("Update", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+				c.StatusMock.On("Patch", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
 					Run(func(args mock.Arguments) {
 						ws := args.Get(1).(*v1beta1.Workspace)
 						*updatedTarget = ws.Status.TargetNodeCount
@@ -1013,6 +1234,48 @@ func TestGuardTargetNodeCount(t *testing.T) {
 	}
 }
 
+func TestGuardInstanceType(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	tests := map[string]struct {
+		resource    v1beta1.ResourceSpec
+		expectError bool
+	}{
+		"catalog instance type => allowed": {
+			resource: v1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		},
+		"instance type of another cloud provider => blocked": {
+			resource:    v1beta1.ResourceSpec{InstanceType: "Standard_NC8_A2"},
+			expectError: true,
+		},
+		"unknown fallback instance type => blocked": {
+			resource:    v1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4", FallbackInstanceTypes: []string{"Unknown_SKU"}},
+			expectError: true,
+		},
+		"auto-provisioning disabled => not checked": {
+			resource: v1beta1.ResourceSpec{InstanceType: "Standard_NC8_A2", ProvisioningPolicy: v1beta1.ProvisioningPolicyNever},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			reconciler := &WorkspaceReconciler{Recorder: recorder}
+			err := reconciler.guardInstanceType(&v1beta1.Workspace{
+				ObjectMeta: v1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+				Resource:   tt.resource,
+			})
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, <-recorder.Events, "UnsupportedInstanceType")
+			} else {
+				assert.NoError(t, err)
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
 func TestEnsureModelMirror_StaticWithPartialSASFails(t *testing.T) {
 	ws := &v1beta1.Workspace{
 		ObjectMeta: v1.ObjectMeta{
@@ -1312,6 +1575,8 @@ func TestSyncWorkspaceStatus(t *testing.T) {
 			mockClient.On("List", mock.Anything, mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil).Twice()
 
 			if ws.Inference != nil {
+				// collectDiskPressure looks for pods that ran out of disk.
+				mockClient.On("List", mock.Anything, mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil).Maybe()
 				if tc.statefulSetNotFound {
 					mockClient.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).
 						Return(apierrors.NewNotFound(appsv1.Resource("StatefulSet"), ws.Name)).Once()
@@ -1321,6 +1586,8 @@ func TestSyncWorkspaceStatus(t *testing.T) {
 			}
 
 			if ws.Tuning != nil {
+				// collectTuningProgress looks for the pod of a running tuning job.
+				mockClient.On("List", mock.Anything, mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil).Maybe()
 				if tc.jobNotFound {
 					mockClient.On("Get", mock.Anything, mock.Anything, mock.IsType(&batchv1.Job{}), mock.Anything).
 						Return(apierrors.NewNotFound(batchv1.Resource("Job"), ws.Name)).Once()
@@ -1330,7 +1597,7 @@ func TestSyncWorkspaceStatus(t *testing.T) {
 			}
 
 			var synced *v1beta1.Workspace
-			mockClient.StatusMock.On("Update", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
+			mockClient.StatusMock.On("Patch", mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
 				synced = args.Get(1).(*v1beta1.Workspace).DeepCopy()
 			}).Return(nil).Once()
 
@@ -1600,6 +1867,22 @@ func TestApplyTuningWorkspaceStatus(t *testing.T) {
 		assert.NotNil(t, tuningCondition)
 		assert.Equal(t, v1.ConditionTrue, tuningCondition.Status)
 	})
+
+	t.Run("progress is recorded and kept until the job restarts", func(t *testing.T) {
+		status := &v1beta1.WorkspaceStatus{}
+		progress := &v1beta1.TuningStatus{Step: 10, TotalSteps: 100, Loss: "1.5"}
+		applyTuningWorkspaceStatus(status, 1, buildReconcileErrMessageAppender(nil), &tuningStatusSnapshot{started: true, active: 1, progress: progress})
+		assert.Equal(t, progress, status.Tuning)
+
+		applyTuningWorkspaceStatus(status, 1, buildReconcileErrMessageAppender(nil), &tuningStatusSnapshot{started: true, active: 1})
+		assert.Equal(t, progress, status.Tuning)
+
+		applyTuningWorkspaceStatus(status, 1, buildReconcileErrMessageAppender(nil), &tuningStatusSnapshot{failed: true})
+		assert.Equal(t, progress, status.Tuning)
+
+		applyTuningWorkspaceStatus(status, 2, buildReconcileErrMessageAppender(nil), &tuningStatusSnapshot{})
+		assert.Nil(t, status.Tuning)
+	})
 }
 
 func TestSetWorkspaceCondition(t *testing.T) {
@@ -1768,6 +2051,117 @@ func TestSetWorkspaceCondition(t *testing.T) {
 	}
 }
 
+func TestSyncInferencePodSpec(t *testing.T) {
+	podSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "testWorkspace",
+			Image:   "base:0.1.0",
+			Command: []string{"/bin/sh", "-c", "python3 /workspace/vllm/inference_api.py"},
+		}}}
+	}
+	tests := []struct {
+		name   string
+		change func(spec *corev1.PodSpec)
+	}{
+		{
+			name: "command and args",
+			change: func(spec *corev1.PodSpec) {
+				spec.Containers[0].Command = []string{"/bin/sh", "-c", "python3 /workspace/vllm/inference_api.py --tool-call-parser=llama3_json"}
+				spec.Containers[0].Args = []string{"--enable-auto-tool-choice"}
+			},
+		},
+		{
+			name: "termination grace period and preStop hook",
+			change: func(spec *corev1.PodSpec) {
+				spec.TerminationGracePeriodSeconds = ptr.To(int64(300))
+				spec.Containers[0].Lifecycle = &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{
+					Exec: &corev1.ExecAction{Command: []string{"python3", "-c", "pass", "5000", "/unload", "30", "270"}},
+				}}
+			},
+		},
+		{
+			name: "probes",
+			change: func(spec *corev1.PodSpec) {
+				probe := func(failureThreshold int32) *corev1.Probe {
+					return &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt32(5000)},
+						},
+						PeriodSeconds:    10,
+						FailureThreshold: failureThreshold,
+					}
+				}
+				spec.Containers[0].StartupProbe = probe(360)
+				spec.Containers[0].LivenessProbe = probe(6)
+				spec.Containers[0].ReadinessProbe = probe(3)
+			},
+		},
+		{
+			name: "image pull policy and runtime class",
+			change: func(spec *corev1.PodSpec) {
+				spec.Containers[0].ImagePullPolicy = corev1.PullAlways
+				spec.RuntimeClassName = ptr.To("runc-stargz")
+			},
+		},
+		{
+			name: "affinity and tolerations",
+			change: func(spec *corev1.PodSpec) {
+				spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						TopologyKey:   corev1.LabelHostname,
+						LabelSelector: &v1.LabelSelector{MatchLabels: map[string]string{v1beta1.LabelWorkspaceName: "testWorkspace"}},
+					}},
+				}}
+				spec.Tolerations = []corev1.Toleration{{Key: "sku", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+			},
+		},
+		{
+			name: "response cache added",
+			change: func(spec *corev1.PodSpec) {
+				spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: consts.PortDecodeVLLM, Protocol: corev1.ProtocolTCP}}
+				spec.Containers = append(spec.Containers, corev1.Container{
+					Name:    consts.ResponseCacheContainerName,
+					Image:   "base:0.1.0",
+					Command: []string{"python3", "/workspace/vllm/response_cache.py", "--port=5000", "--upstream-port=5001"},
+					Ports:   []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer, Name: "cache", Protocol: corev1.ProtocolTCP}},
+				})
+			},
+		},
+		{
+			name: "tokenizer added",
+			change: func(spec *corev1.PodSpec) {
+				spec.Containers = append(spec.Containers, corev1.Container{
+					Name:    consts.TokenizerContainerName,
+					Image:   "base:0.1.0",
+					Command: []string{"python3", "/workspace/vllm/tokenizer_server.py", "--port=5002", "--upstream-port=5000"},
+					Ports:   []corev1.ContainerPort{{ContainerPort: consts.PortTokenizer, Name: "tokenizer", Protocol: corev1.ProtocolTCP}},
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, desired := podSpec(), podSpec()
+			tt.change(desired)
+			syncInferencePodSpec(existing, desired)
+			assert.Equal(t, desired, existing)
+		})
+	}
+}
+
+func TestSyncInferencePodSpecRemovesResponseCache(t *testing.T) {
+	existing := &corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "testWorkspace", Ports: []corev1.ContainerPort{{ContainerPort: consts.PortDecodeVLLM, Protocol: corev1.ProtocolTCP}}},
+		{Name: consts.ResponseCacheContainerName, Ports: []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer, Name: "cache", Protocol: corev1.ProtocolTCP}}},
+	}}
+	desired := &corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "testWorkspace", Ports: []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer, Protocol: corev1.ProtocolTCP}}},
+	}}
+
+	syncInferencePodSpec(existing, desired)
+	assert.Equal(t, desired, existing)
+}
+
 func TestShouldUpgradeBaseImage(t *testing.T) {
 	baseTag := inference.GetBaseImageTag()
 	baseImage := "mcr.microsoft.com/aks/kaito/kaito-base:" + baseTag
@@ -1993,6 +2387,38 @@ func TestShouldUpgradeBaseImage(t *testing.T) {
 			},
 			expect: false,
 		},
+		{
+			name: "upgrade to a pinned digest without upgrade label",
+			ws: &v1beta1.Workspace{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "my-workspace",
+					Annotations: map[string]string{
+						v1beta1.AnnotationRuntimeChannel: "pinned@sha256:" + strings.Repeat("a", 64),
+					},
+				},
+			},
+			existing: &appsv1.StatefulSet{
+				ObjectMeta: v1.ObjectMeta{Name: "my-workspace"},
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "my-workspace", Image: baseImage}},
+						},
+					},
+				},
+			},
+			desired: &appsv1.StatefulSet{
+				ObjectMeta: v1.ObjectMeta{Name: "my-workspace"},
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "my-workspace", Image: "mcr.microsoft.com/aks/kaito/kaito-base@sha256:" + strings.Repeat("a", 64)}},
+						},
+					},
+				},
+			},
+			expect: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2002,3 +2428,53 @@ func TestShouldUpgradeBaseImage(t *testing.T) {
 		})
 	}
 }
+
+func TestCountNodesPerZone(t *testing.T) {
+	node := func(name, zone string) corev1.Node {
+		n := corev1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if zone != "" {
+			n.Labels[corev1.LabelTopologyZone] = zone
+		}
+		return n
+	}
+
+	assert.Nil(t, countNodesPerZone(nil))
+	assert.Nil(t, countNodesPerZone([]corev1.Node{node("byo", "")}))
+	assert.Equal(t, []v1beta1.ZoneNodeCount{
+		{Zone: "eastus-1", Nodes: 2},
+		{Zone: "eastus-3", Nodes: 1},
+	}, countNodesPerZone([]corev1.Node{
+		node("a", "eastus-3"),
+		node("b", "eastus-1"),
+		node("byo", ""),
+		node("c", "eastus-1"),
+	}))
+}
+
+func TestReconcilePaused(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ws := test.MockWorkspaceDistributedModel.DeepCopy()
+	ws.Generation = 3
+	ws.Annotations = map[string]string{v1beta1.AnnotationReconcile: v1beta1.ReconcileDisabled}
+	ws.Status.TargetNodeCount = 2
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).WithStatusSubresource(&v1beta1.Workspace{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	// No node provisioner is set: a paused workspace must not provision anything.
+	reconciler := &WorkspaceReconciler{Client: cl, Recorder: recorder}
+
+	_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ws)})
+	require.NoError(t, err)
+
+	got := &v1beta1.Workspace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(ws), got))
+	assert.Equal(t, int32(2), got.Status.TargetNodeCount, "the status is left as it is")
+	assert.Nil(t, meta.FindStatusCondition(got.Status.Conditions, string(v1beta1.ConditionTypeNodeStatus)))
+	paused := meta.FindStatusCondition(got.Status.Conditions, string(v1beta1.ConditionTypePaused))
+	require.NotNil(t, paused)
+	assert.Equal(t, v1.ConditionTrue, paused.Status)
+	assert.Equal(t, int64(3), paused.ObservedGeneration)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "ReconcilePaused")
+}
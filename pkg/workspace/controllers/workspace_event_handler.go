@@ -96,6 +96,20 @@ var enqueueWorkspaceForNodeClaim = handler.EnqueueRequestsFromMapFunc(
 		}
 	})
 
+// enqueueWorkspaceForPod enqueues the workspace named by the workspace label of a pod.
+var enqueueWorkspaceForPod = handler.EnqueueRequestsFromMapFunc(
+	func(ctx context.Context, o client.Object) []reconcile.Request {
+		name := o.GetLabels()[kaitov1beta1.LabelWorkspaceName]
+		if name == "" {
+			return nil
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: client.ObjectKey{Namespace: o.GetNamespace(), Name: name},
+			},
+		}
+	})
+
 // enqueueWorkspacesForModelMirror returns a handler that enqueues all workspaces
 // whose expected ModelMirror CR name matches the changed CR.
 func enqueueWorkspacesForModelMirror(kubeClient client.Client) handler.EventHandler {
@@ -15,26 +15,76 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/workspace"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 )
 
-// garbageCollectWorkspace remove finalizer associated with workspace object.
+const (
+	// teardownRequeueInterval is how often a deleting Workspace is reconciled while a
+	// teardown step waits.
+	teardownRequeueInterval = 2 * time.Second
+
+	// teardownDrainTimeout bounds how long after the deletion of a Workspace its inference
+	// pods may take to drain before the workloads are deleted anyway.
+	teardownDrainTimeout = 5 * time.Minute
+)
+
+// teardownStep is a step of deleting a Workspace. run reports whether the step has
+// finished; the next step does not start before it has.
+type teardownStep struct {
+	name kaitov1beta1.TeardownStep
+	run  func(c *WorkspaceReconciler, ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error)
+}
+
+// teardownSteps are the steps of deleting a Workspace, in order. Clients are detached and
+// drained before the Services go away, and the nodes are released last.
+var teardownSteps = []teardownStep{
+	{kaitov1beta1.TeardownStepGatewayDetach, (*WorkspaceReconciler).detachGateway},
+	{kaitov1beta1.TeardownStepDrain, (*WorkspaceReconciler).drainWorkloads},
+	{kaitov1beta1.TeardownStepWorkloadDelete, (*WorkspaceReconciler).deleteWorkloads},
+	{kaitov1beta1.TeardownStepNodeClaimDelete, (*WorkspaceReconciler).deleteNodes},
+	{kaitov1beta1.TeardownStepAuxiliaryCleanup, (*WorkspaceReconciler).cleanupAuxiliary},
+}
+
+// garbageCollectWorkspace tears the workspace down step by step, recording the current
+// step in its status, and removes its finalizer once every step has finished. A step
+// that has finished is not run again, so the teardown never goes back.
 func (c *WorkspaceReconciler) garbageCollectWorkspace(ctx context.Context, wObj *kaitov1beta1.Workspace) (ctrl.Result, error) {
 	klog.InfoS("garbageCollectWorkspace", "workspace", klog.KObj(wObj))
 
-	// DeleteNodes via the NodeProvisioner interface.
-	// KarpenterProvisioner deletes the NodePool; GpuProvisioner deletes NodeClaims;
-	// BYOProvisioner (BYO mode) is a no-op.
-	if err := c.nodeProvisioner.DeleteNodes(ctx, wObj); err != nil {
-		return ctrl.Result{}, err
+	first := slices.IndexFunc(teardownSteps, func(s teardownStep) bool { return s.name == wObj.Status.TeardownStep })
+	for _, step := range teardownSteps[max(first, 0):] {
+		if err := c.setTeardownStep(ctx, wObj, step.name); err != nil {
+			return ctrl.Result{}, err
+		}
+		done, err := step.run(c, ctx, wObj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			klog.V(4).InfoS("Waiting for teardown step", "workspace", klog.KObj(wObj), "step", step.name)
+			return ctrl.Result{RequeueAfter: teardownRequeueInterval}, nil
+		}
 	}
 
 	updateErr := workspace.UpdateWorkspaceWithRetry(ctx, c.Client, wObj, func(ws *kaitov1beta1.Workspace) error {
@@ -53,3 +103,193 @@ func (c *WorkspaceReconciler) garbageCollectWorkspace(ctx context.Context, wObj
 
 	return ctrl.Result{}, nil
 }
+
+// setTeardownStep records step in the status of the workspace when it is not already there.
+func (c *WorkspaceReconciler) setTeardownStep(ctx context.Context, wObj *kaitov1beta1.Workspace, step kaitov1beta1.TeardownStep) error {
+	if wObj.Status.TeardownStep == step {
+		return nil
+	}
+	klog.InfoS("Workspace teardown step", "workspace", klog.KObj(wObj), "step", step)
+	if err := c.updateWorkspaceStatusIfChanged(ctx, client.ObjectKeyFromObject(wObj), func(status *kaitov1beta1.WorkspaceStatus) error {
+		status.TeardownStep = step
+		return nil
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to record teardown step %s: %w", step, err)
+	}
+	wObj.Status.TeardownStep = step
+	return nil
+}
+
+// detachGateway sets the weight of the HTTPRoute backendRefs to the Service of the
+// workspace to zero. It waits one requeue after changing a route, so that the Gateway
+// has stopped sending new requests before the pods drain.
+func (c *WorkspaceReconciler) detachGateway(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(manifests.HTTPRouteGVK.GroupVersion().WithKind(manifests.HTTPRouteGVK.Kind + "List"))
+	if err := c.List(ctx, routes, client.InNamespace(wObj.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	detached := false
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		changed, err := manifests.DetachServiceBackend(route, wObj.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to detach HTTPRoute %s: %w", route.GetName(), err)
+		}
+		if !changed {
+			continue
+		}
+		klog.InfoS("Detaching workspace Service from HTTPRoute", "workspace", klog.KObj(wObj), "httproute", route.GetName())
+		if err := c.Update(ctx, route); err != nil {
+			return false, fmt.Errorf("failed to update HTTPRoute %s: %w", route.GetName(), err)
+		}
+		detached = true
+	}
+	return !detached, nil
+}
+
+// drainWorkloads scales the inference workloads of the workspace to zero and waits for
+// their pods to exit, which gives the model server its termination grace period to
+// finish the requests in flight while the Service still routes to it.
+func (c *WorkspaceReconciler) drainWorkloads(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	if wObj.Inference == nil {
+		return true, nil
+	}
+
+	for _, list := range []client.ObjectList{&appsv1.StatefulSetList{}, &appsv1.DeploymentList{}} {
+		objs, err := c.controlledObjects(ctx, wObj, list)
+		if err != nil {
+			return false, err
+		}
+		for _, obj := range objs {
+			if err := c.scaleToZero(ctx, obj); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace), client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return false, fmt.Errorf("failed to list pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return true, nil
+	}
+	if wObj.DeletionTimestamp != nil && time.Since(wObj.DeletionTimestamp.Time) > teardownDrainTimeout {
+		klog.InfoS("Pods did not drain in time, deleting the workloads", "workspace", klog.KObj(wObj), "pods", len(pods.Items))
+		return true, nil
+	}
+	return false, nil
+}
+
+// scaleToZero sets the replicas of a StatefulSet or Deployment to zero.
+func (c *WorkspaceReconciler) scaleToZero(ctx context.Context, obj client.Object) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	switch o := obj.(type) {
+	case *appsv1.StatefulSet:
+		if o.Spec.Replicas != nil && *o.Spec.Replicas == 0 {
+			return nil
+		}
+		o.Spec.Replicas = ptr.To[int32](0)
+	case *appsv1.Deployment:
+		if o.Spec.Replicas != nil && *o.Spec.Replicas == 0 {
+			return nil
+		}
+		o.Spec.Replicas = ptr.To[int32](0)
+	default:
+		return nil
+	}
+	if err := c.Patch(ctx, obj, patch); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to scale %s to zero: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// deleteWorkloads deletes the StatefulSets, Deployments, Jobs and Services the workspace
+// controls, and waits until they are gone.
+func (c *WorkspaceReconciler) deleteWorkloads(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	remaining := 0
+	for _, list := range []client.ObjectList{&appsv1.StatefulSetList{}, &appsv1.DeploymentList{}, &batchv1.JobList{}, &corev1.ServiceList{}} {
+		n, err := c.deleteControlled(ctx, wObj, list)
+		if err != nil {
+			return false, err
+		}
+		remaining += n
+	}
+	return remaining == 0, nil
+}
+
+// deleteNodes deletes the nodes of the workspace via the NodeProvisioner interface.
+// KarpenterProvisioner deletes the NodePool; GpuProvisioner deletes NodeClaims;
+// BYOProvisioner (BYO mode) is a no-op.
+func (c *WorkspaceReconciler) deleteNodes(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	if err := c.nodeProvisioner.DeleteNodes(ctx, wObj); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// cleanupAuxiliary deletes the ControllerRevisions of the workspace.
+func (c *WorkspaceReconciler) cleanupAuxiliary(ctx context.Context, wObj *kaitov1beta1.Workspace) (bool, error) {
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(wObj.Namespace), client.MatchingLabels{WorkspaceNameLabel: wObj.Name}); err != nil {
+		return false, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	for i := range revisions.Items {
+		if err := c.Delete(ctx, &revisions.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete revision %s: %w", revisions.Items[i].Name, err)
+		}
+	}
+	return true, nil
+}
+
+// controlledObjects returns the objects of list in the namespace of the workspace that
+// the workspace controls.
+func (c *WorkspaceReconciler) controlledObjects(ctx context.Context, wObj *kaitov1beta1.Workspace, list client.ObjectList) ([]client.Object, error) {
+	if err := c.List(ctx, list, client.InNamespace(wObj.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list %T: %w", list, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	var objs []client.Object
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok && metav1.IsControlledBy(obj, wObj) {
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// deleteControlled deletes the objects of list that the workspace controls, and returns
+// how many of them still exist.
+func (c *WorkspaceReconciler) deleteControlled(ctx context.Context, wObj *kaitov1beta1.Workspace, list client.ObjectList) (int, error) {
+	objs, err := c.controlledObjects(ctx, wObj, list)
+	if err != nil {
+		return 0, err
+	}
+	remaining := 0
+	for _, obj := range objs {
+		remaining++
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		klog.InfoS("Deleting workspace workload", "workspace", klog.KObj(wObj), "kind", fmt.Sprintf("%T", obj), "name", obj.GetName())
+		if err := c.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if apierrors.IsNotFound(err) {
+				remaining--
+				continue
+			}
+			return 0, fmt.Errorf("failed to delete %s: %w", types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, err)
+		}
+	}
+	return remaining, nil
+}
@@ -17,10 +17,22 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gotest.tools/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	"github.com/kaito-project/kaito/api/v1beta1"
@@ -30,6 +42,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	"github.com/kaito-project/kaito/pkg/workspace/resource"
 )
 
@@ -43,7 +56,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 			disableNodeAutoProvisioning: true,
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				// No List or Delete calls for NodeClaims expected
 			},
@@ -52,7 +65,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Fails to delete workspace because associated nodeClaims cannot be retrieved": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(errors.New("failed to list nodeClaims"))
 			},
@@ -61,7 +74,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Fails to delete workspace because associated nodeClaims cannot be deleted": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 
 				nodeClaimList := test.MockNodeClaimList
@@ -82,7 +95,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Delete workspace with associated nodeClaim objects because finalizer cannot be removed from workspace": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(errors.New("failed to update workspace"))
 
 				nodeClaimList := test.MockNodeClaimList
@@ -102,7 +115,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Successfully deletes workspace with associated nodeClaim objects and removes finalizer associated with workspace": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
@@ -124,7 +137,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Delete workspace with nodeClaim objects because finalizer cannot be removed from workspace": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(errors.New("failed to update workspace"))
 
 				nodeClaimList := test.MockNodeClaimList
@@ -144,7 +157,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		"Successfully deletes workspace with machine and nodeClaim objects and removes finalizer associated with workspace": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Patch", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
 
 				nodeClaimList := test.MockNodeClaimList
@@ -167,6 +180,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
 			tc.callMocks(mockClient)
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything).Return(nil)
 
 			// Set the feature gate for this test case
 			featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = tc.disableNodeAutoProvisioning
@@ -189,14 +203,138 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 			}
 			ctx := context.Background()
 
-			test.MockWorkspaceDistributedModel.SetFinalizers([]string{consts.WorkspaceFinalizer})
+			// The steps before the nodes are deleted are covered by TestGarbageCollectWorkspaceTeardownOrder.
+			ws := test.MockWorkspaceDistributedModel.DeepCopy()
+			ws.SetFinalizers([]string{consts.WorkspaceFinalizer})
+			ws.Status.TeardownStep = v1beta1.TeardownStepNodeClaimDelete
 
-			_, err := reconciler.garbageCollectWorkspace(ctx, test.MockWorkspaceDistributedModel)
+			_, err := reconciler.garbageCollectWorkspace(ctx, ws)
 			if tc.expectedError == nil {
-				assert.Check(t, err == nil, "Not expected to return error")
+				assert.NoError(t, err, "Not expected to return error")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
 		})
 	}
 }
+
+func newTeardownTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for gvk := range scheme.AllKnownTypes() {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	mapper.Add(manifests.HTTPRouteGVK, meta.RESTScopeNamespace)
+	return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithObjects(objs...).WithStatusSubresource(&v1beta1.Workspace{}).Build()
+}
+
+func TestGarbageCollectWorkspaceTeardownOrder(t *testing.T) {
+	ctx := context.Background()
+	ws := &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "phi", Namespace: "default", UID: "ws-uid",
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Finalizers:        []string{consts.WorkspaceFinalizer},
+		},
+		Inference: &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-4"}}},
+	}
+	owner := []metav1.OwnerReference{*metav1.NewControllerRef(ws, v1beta1.GroupVersion.WithKind("Workspace"))}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", OwnerReferences: owner},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "phi-0", Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "phi"}}}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", OwnerReferences: owner}}
+	otherService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+	revision := &appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: "phi-abc", Namespace: "default", Labels: map[string]string{WorkspaceNameLabel: "phi"}}}
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+		"spec": map[string]any{"rules": []any{map[string]any{
+			"backendRefs": []any{map[string]any{"name": "phi", "port": int64(80)}},
+		}}},
+	}}
+	cl := newTeardownTestClient(t, ws, statefulSet, pod, service, otherService, revision, route)
+	provisioner := &deleteRecordingProvisioner{}
+	reconciler := &WorkspaceReconciler{Client: cl, nodeProvisioner: provisioner}
+
+	reconcileOnce := func(expectStep v1beta1.TeardownStep) {
+		t.Helper()
+		current := &v1beta1.Workspace{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), current))
+		result, err := reconciler.garbageCollectWorkspace(ctx, current)
+		require.NoError(t, err)
+		assert.Equal(t, teardownRequeueInterval, result.RequeueAfter)
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), current))
+		assert.Equal(t, expectStep, current.Status.TeardownStep)
+	}
+	exists := func(obj client.Object) bool {
+		err := cl.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// The route stops sending requests to the Service before anything is scaled down.
+	reconcileOnce(v1beta1.TeardownStepGatewayDetach)
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(manifests.HTTPRouteGVK)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(route), current))
+	rules, _, _ := unstructured.NestedSlice(current.Object, "spec", "rules")
+	refs, _, _ := unstructured.NestedSlice(rules[0].(map[string]any), "backendRefs")
+	assert.Equal(t, int64(0), refs[0].(map[string]any)["weight"])
+	require.True(t, exists(statefulSet))
+	assert.Equal(t, int32(1), *statefulSet.Spec.Replicas)
+
+	// The workload is scaled to zero while the Service keeps routing to the draining pod.
+	reconcileOnce(v1beta1.TeardownStepDrain)
+	require.True(t, exists(statefulSet))
+	assert.Equal(t, int32(0), *statefulSet.Spec.Replicas)
+	assert.True(t, exists(service))
+
+	// Once the pod is gone, the workloads and Services are deleted, but not the nodes.
+	require.NoError(t, cl.Delete(ctx, pod))
+	reconcileOnce(v1beta1.TeardownStepWorkloadDelete)
+	assert.False(t, exists(statefulSet))
+	assert.False(t, exists(service))
+	assert.True(t, exists(otherService), "Services the workspace does not control are left alone")
+	assert.Empty(t, provisioner.deleted)
+
+	// Finally the nodes and revisions go, and the finalizer is removed.
+	latest := &v1beta1.Workspace{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(ws), latest))
+	result, err := reconciler.garbageCollectWorkspace(ctx, latest)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, []string{"phi"}, provisioner.deleted)
+	assert.False(t, exists(revision))
+	assert.False(t, exists(&v1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default"}}))
+}
+
+func TestDrainWorkloadsTimeout(t *testing.T) {
+	ws := &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "phi", Namespace: "default",
+			DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-teardownDrainTimeout - time.Minute)},
+			Finalizers:        []string{consts.WorkspaceFinalizer},
+		},
+		Inference: &v1beta1.InferenceSpec{},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "phi-0", Namespace: "default", Labels: map[string]string{v1beta1.LabelWorkspaceName: "phi"}}}
+	reconciler := &WorkspaceReconciler{Client: newTeardownTestClient(t, ws, pod)}
+
+	done, err := reconciler.drainWorkloads(context.Background(), ws)
+	require.NoError(t, err)
+	assert.True(t, done, "a pod that does not drain in time does not block the teardown")
+
+	ws.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	done, err = reconciler.drainWorkloads(context.Background(), ws)
+	require.NoError(t, err)
+	assert.False(t, done)
+}
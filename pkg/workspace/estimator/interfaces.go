@@ -26,6 +26,9 @@ type RuntimeProfile struct {
 	// ContextSize is the model context window length (max-model-len).
 	// A zero value signals that the estimator should apply its built-in default.
 	ContextSize int
+	// StructuredOutputsBackend is the guided decoding backend vLLM is started with.
+	// An empty string means guided decoding is not configured.
+	StructuredOutputsBackend string
 }
 
 // ModelProfile identifies the model to be served.
@@ -64,6 +67,10 @@ type NodeEstimateRequest struct {
 	RuntimeProfile RuntimeProfile
 }
 
+// BreakerName names the circuit breaker guarding node estimation, which looks up the GPU
+// configuration of the SKU and the metadata of the model.
+const BreakerName = "sku-lookup"
+
 // NodesEstimator is an interface for estimating the number of nodes required for an inference workload.
 type NodesEstimator interface {
 	// Name a human-readable identifier for this estimator implementation.
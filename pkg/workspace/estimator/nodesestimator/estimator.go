@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
@@ -31,32 +32,18 @@ import (
 	"github.com/kaito-project/kaito/presets/workspace/models"
 )
 
-const (
-	// gpuMemoryUtilization mirrors the --gpu-memory-utilization value vLLM is
-	// launched with (see buildVLLMInferenceCommand in pkg/model/interface.go).
-	// vLLM treats it as the hard cap on the fraction of total GPU memory used for
-	// weights + activations + CUDA graphs + KV cache, so the estimator must use
-	// the same value to predict the per-GPU memory budget vLLM will actually have.
-	gpuMemoryUtilization = 0.84
-
-	// weightExpansionFactor accounts for the ~2% expansion of model weights once
-	// loaded by vLLM relative to the on-disk safetensor size.
-	weightExpansionFactor = 1.02
-
-	// baseOverheadGiB is the model-independent part of vLLM's fixed per-GPU
-	// overhead: non-torch allocations such as the CUDA context and NCCL buffers
-	// (~0.6 GiB) plus a baseline for small-model activations and CUDA graphs
-	// (~1.7 GiB). Larger models add to this via overheadWeightFactor below.
-	baseOverheadGiB = 2.3
-
-	// overheadWeightFactor scales the runtime overhead with the per-GPU model
-	// weight share. Peak activation memory and CUDA graph capture both grow with
-	// hidden size / layer count and are sharded across TP ranks the same way
-	// weights are, so the per-GPU weight share is a good proxy for them. vLLM
-	// measures these empirically in determine_available_memory() and
-	// profile_cudagraph_memory(). We approximate at best effort here.
-	overheadWeightFactor = 0.05
-)
+// structuredOutputsOverheadGiB is the extra per-GPU memory reserved for a guided
+// decoding backend. xgrammar and guidance apply packed token bitmasks, while
+// outlines and lm-format-enforcer build full-vocabulary float masks per sequence
+// in the logits processor, which regularly pushed small SKUs into OOM. "auto"
+// may fall back to any of them, so it reserves the larger amount.
+var structuredOutputsOverheadGiB = map[string]float64{
+	string(kaitov1beta1.StructuredOutputsBackendAuto):             1.0,
+	string(kaitov1beta1.StructuredOutputsBackendXGrammar):         0.25,
+	string(kaitov1beta1.StructuredOutputsBackendGuidance):         0.25,
+	string(kaitov1beta1.StructuredOutputsBackendOutlines):         1.0,
+	string(kaitov1beta1.StructuredOutputsBackendLMFormatEnforcer): 1.0,
+}
 
 // NodeEstimator estimates node count based on SKU memory and model memory requirement
 type NodeEstimator struct {
@@ -134,33 +121,33 @@ func (c *NodeEstimator) EstimateNodeCount(ctx context.Context, req estimator.Nod
 		maxModelLen = req.RuntimeProfile.ContextSize
 	}
 
-	klog.Infof("[NodeEstimator] workspace=%s maxModelLen=%d", req.WorkspaceName, maxModelLen)
+	klog.Infof("[NodeEstimator] workspace=%s maxModelLen=%d structuredOutputsBackend=%q", req.WorkspaceName, maxModelLen, req.RuntimeProfile.StructuredOutputsBackend)
 
 	// If GPU memory information is available, calculate the optimal node count
 	if !gpuConfig.GPUMem.IsZero() && gpuConfig.GPUCount > 0 {
 		inferParams := model.GetInferenceParameters()
 		totalGPUMemRequired := resource.MustParse(inferParams.TotalSafeTensorFileSize)
-		modelSize := float64(totalGPUMemRequired.Value()) * weightExpansionFactor // vllm model size is about 102% of HuggingFace size
+		modelSize := float64(totalGPUMemRequired.Value()) * pkgmodel.WeightExpansionFactor // vllm model size is about 102% of HuggingFace size
 		gpuMemPerGPU := float64(gpuConfig.GPUMem.Value() / int64(gpuConfig.GPUCount))
-		availGPUMem := gpuMemPerGPU * gpuMemoryUtilization // utilization is set to default 0.84
+		availGPUMem := gpuMemPerGPU * pkgmodel.GPUMemoryUtilization // utilization is set to default 0.84
 
 		// Overhead: a fixed base plus the KV cache for the
 		// context length, plus a term that scales with the per-GPU model weight
-		// share (overheadWeightFactor). For the tensor-parallel (sharded)
-		// case the weight-scaled term folds into the (1 + overheadWeightFactor)
+		// share (OverheadWeightFactor). For the tensor-parallel (sharded)
+		// case the weight-scaled term folds into the (1 + pkgmodel.OverheadWeightFactor)
 		// divisor below, keeping the solve non-circular.
-		baseOverhead := baseOverheadGiB * float64(consts.GiBToBytes)
+		baseOverhead := (pkgmodel.BaseOverheadGiB + structuredOutputsOverheadGiB[req.RuntimeProfile.StructuredOutputsBackend]) * float64(consts.GiBToBytes)
 		kvCache := float64(maxModelLen*inferParams.BytesPerToken) / float64(gpuConfig.GPUCount)
 		fixedReserve := baseOverhead + kvCache
 
 		if availGPUMem <= fixedReserve {
 			return 0, fmt.Errorf("GPU memory %.0f bytes is too small, needs at least %.1f GB overhead (base: %.1fGB + KV Cache: %.1f GB)",
-				gpuMemPerGPU, fixedReserve/float64(consts.GiBToBytes), baseOverheadGiB, kvCache/float64(consts.GiBToBytes))
+				gpuMemPerGPU, fixedReserve/float64(consts.GiBToBytes), baseOverhead/float64(consts.GiBToBytes), kvCache/float64(consts.GiBToBytes))
 		}
 
 		// Per-GPU memory available for model weights. The weight-scaled overhead
-		// (overheadWeightFactor x per-GPU weight) folds into the (1 + factor) divisor.
-		availMemPerGPU := (availGPUMem - fixedReserve) / (1 + overheadWeightFactor)
+		// (OverheadWeightFactor x per-GPU weight) folds into the (1 + factor) divisor.
+		availMemPerGPU := (availGPUMem - fixedReserve) / (1 + pkgmodel.OverheadWeightFactor)
 		minGPUs := int(modelSize/availMemPerGPU) + 1
 		nodeCountPerReplica = (minGPUs + gpuConfig.GPUCount - 1) / gpuConfig.GPUCount
 
@@ -171,7 +158,7 @@ func (c *NodeEstimator) EstimateNodeCount(ctx context.Context, req estimator.Nod
 		// runtime overhead must fit one slice. Report the slice-specific shortfall
 		// instead of scaling to multiple GPUs/nodes.
 		if gpuConfig.IsMIG && nodeCountPerReplica > 1 {
-			overhead := fixedReserve + overheadWeightFactor*modelSize
+			overhead := fixedReserve + pkgmodel.OverheadWeightFactor*modelSize
 			sliceGiB := gpuMemPerGPU / float64(consts.GiBToBytes)
 			return 0, fmt.Errorf("model needs %.1fGB (weights %.1fGB + overhead %.1fGB) but MIG profile %s only provides %.0fGB (%.1fGB available after vLLM gpu-memory-utilization)",
 				(modelSize+overhead)/float64(consts.GiBToBytes),
@@ -765,3 +765,61 @@ func TestNodeEstimator_EstimateNodeCount_MIG_ContextSize(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int32(1), count)
 }
+
+// TestNodeEstimator_EstimateNodeCount_StructuredOutputs checks that the guided
+// decoding backend memory is reserved on top of the base overhead.
+func TestNodeEstimator_EstimateNodeCount_StructuredOutputs(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	ctx := context.Background()
+	calculator := &NodeEstimator{}
+
+	origMIG := featuregates.FeatureGates[consts.FeatureFlagEnableMIG]
+	origNAP := featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning]
+	featuregates.FeatureGates[consts.FeatureFlagEnableMIG] = true
+	featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = true
+	defer func() {
+		featuregates.FeatureGates[consts.FeatureFlagEnableMIG] = origMIG
+		featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] = origNAP
+	}()
+
+	// A 14GB slice leaves 14 * 0.84 = 11.76 GiB. test-model needs ~10.87 GiB, and
+	// ~11.92 GiB once outlines reserves its extra 1 GiB.
+	tests := []struct {
+		name          string
+		backend       kaitov1beta1.StructuredOutputsBackend
+		expectedError bool
+	}{
+		{name: "no structured outputs", backend: ""},
+		{name: "xgrammar fits", backend: kaitov1beta1.StructuredOutputsBackendXGrammar},
+		{name: "outlines does not fit", backend: kaitov1beta1.StructuredOutputsBackendOutlines, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-so-workspace", Namespace: "default"},
+				Resource: kaitov1beta1.ResourceSpec{
+					Partition: &kaitov1beta1.PartitionSpec{Mode: kaitov1beta1.PartitionModeMIG, Profile: "1g.14gb"},
+				},
+				Inference: &kaitov1beta1.InferenceSpec{
+					Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "test-model"}},
+				},
+			}
+			if tt.backend != "" {
+				workspace.Inference.StructuredOutputs = &kaitov1beta1.StructuredOutputsSpec{Backend: tt.backend}
+			}
+
+			req, reqErr := workspaceutil.NodeEstimateRequestFromWorkspace(ctx, workspace, nil)
+			require.NoError(t, reqErr)
+			assert.Equal(t, string(tt.backend), req.RuntimeProfile.StructuredOutputsBackend)
+			count, err := calculator.EstimateNodeCount(ctx, req, nil)
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "only provides")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), count)
+		})
+	}
+}
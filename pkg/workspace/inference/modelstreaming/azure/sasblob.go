@@ -131,6 +131,7 @@ func (s *SASBlobProvider) ensureScriptConfigMap(ctx *generator.WorkspaceGenerato
 			ObjectMeta: metav1.ObjectMeta{
 				Name:            name,
 				Namespace:       ctx.Workspace.Namespace,
+				Labels:          map[string]string{v1beta1.LabelWorkspaceName: ctx.Workspace.Name},
 				OwnerReferences: []metav1.OwnerReference{ownerRef},
 			},
 			Data: desired,
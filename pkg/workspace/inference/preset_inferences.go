@@ -17,7 +17,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"path"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/samber/lo"
@@ -27,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +38,7 @@ import (
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/imageverify"
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
 	"github.com/kaito-project/kaito/pkg/sku"
@@ -42,6 +47,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/generator"
 	"github.com/kaito-project/kaito/pkg/utils/mig"
 	"github.com/kaito-project/kaito/pkg/utils/nodes"
+	"github.com/kaito-project/kaito/pkg/utils/workspace"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming"
 	"github.com/kaito-project/kaito/pkg/workspace/inference/modelstreaming/registry"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
@@ -170,6 +176,10 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 	if err != nil {
 		return nil, fmt.Errorf("failed to get GPU config: %w", err)
 	}
+	if base := metadata.MustGet("base"); !base.SupportsArchitecture(gpuConfig.Architecture()) {
+		return nil, fmt.Errorf("the KAITO base image is not published for %s nodes; supported architectures: %s",
+			gpuConfig.Architecture(), strings.Join(base.ImagePlatforms(), ", "))
+	}
 
 	// Set the target node count for the inference workload
 	numNodes := int(workspaceObj.Status.TargetNodeCount)
@@ -194,6 +204,7 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 	podOpts := []generator.TypedManifestModifier[generator.WorkspaceGeneratorContext, corev1.PodSpec]{
 		GenerateInferencePodSpec(gpuConfig, numNodes, streamingModelPath, streamingLoadFormat),
 		SetProvisionerNodeSelector,
+		SetCachedModelAffinity,
 		SetHFToken,
 	}
 
@@ -204,7 +215,7 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 		podOpts = append(podOpts, SetModelDownloadInfo)
 	}
 
-	podOpts = append(podOpts, SetAdapterPuller)
+	podOpts = append(podOpts, SetAdapterPuller, SetLogging, SetShutdown, SetEphemeralStorage, SetCompute, SetResponseCache, SetAPINormalizer, SetTierRouter, SetTracing, SetTokenizer, SetRuntimeEnv, SetCachedProfile)
 
 	// Use StatefulSet for all use cases to ensure consistent pod identity and storage management
 	// For multi-node distributed inference with vLLM, we need StatefulSet to ensure pods are
@@ -212,11 +223,13 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 	// https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-identity
 	distributed := shouldUseDistributedInference(gctx, numNodes)
 	if distributed {
-		podOpts = append(podOpts, SetDistributedInferenceProbe)
+		podOpts = append(podOpts, SetDistributedInferenceProbe, SetDistributedGroupEnv(numNodes))
 	}
 	if v1beta1.ShouldRunBenchmark(workspaceObj) {
 		podOpts = append(podOpts, SetBenchmarkConfig(distributed))
 	}
+	// The overrides apply to the probes of any of the layouts above.
+	podOpts = append(podOpts, SetProbes)
 
 	ssOpts := []generator.TypedManifestModifier[generator.WorkspaceGeneratorContext, appsv1.StatefulSet]{
 		manifests.GenerateStatefulSetManifest(revisionNum, numNodes),
@@ -245,12 +258,20 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 		})
 	}
 
+	podOpts = append(podOpts, manifests.SetConfidentialCompute(workspaceObj.Resource.ConfidentialCompute), manifests.SetPlacement, SetImagePull)
+
+	// Applied last so the proxy settings and the identity reach every container added above.
+	if preset := workspaceObj.Inference.Preset; preset != nil {
+		podOpts = append(podOpts, manifests.SetProxy(preset.Proxy))
+	}
+	podOpts = append(podOpts, manifests.SetWorkloadIdentity, manifests.SetServiceAccount)
+
 	podSpec, err := generator.GenerateManifest(gctx, podOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	ssOpts = append(ssOpts, manifests.SetStatefulSetPodSpec(podSpec))
+	ssOpts = append(ssOpts, manifests.SetStatefulSetPodSpec(podSpec), manifests.SetStatefulSetGangScheduling)
 
 	return generator.GenerateManifest(gctx, ssOpts...)
 }
@@ -262,7 +283,7 @@ func getGPUConfig(ctx *generator.WorkspaceGeneratorContext) (*sku.GPUConfig, err
 		return utils.GetMIGGPUConfig(ctx.Workspace.Resource.Partition.Profile)
 	}
 
-	if featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+	if workspace.IsNodeAutoProvisioningDisabled(&ctx.Workspace.Resource) {
 		// NAP is disabled (BYO scenario) - prefer to get GPU config from matching nodes with nvidia.com labels
 		// Only try to find matching nodes if we have a labelSelector and if WorkerNodes is not already populated
 		readyNodes, err := nodeprovision.GetReadyNodes(ctx.Ctx, ctx.KubeClient, ctx.NodeProvisioner, ctx.Workspace)
@@ -276,7 +297,7 @@ func getGPUConfig(ctx *generator.WorkspaceGeneratorContext) (*sku.GPUConfig, err
 		return sku.GetGPUConfigFromNodeLabels(readyNodes[0])
 	} else {
 		// NAP is enabled - try to get GPU config from known SKU
-		gpuConfig, err := sku.GetGPUConfigBySKU(ctx.Workspace.Resource.InstanceType)
+		gpuConfig, err := sku.GetGPUConfigBySKU(ctx.Workspace.EffectiveInstanceType())
 		if err != nil {
 			return nil, err
 		}
@@ -434,11 +455,28 @@ func buildBenchmarkStartupProbe(timeout time.Duration, wObj *v1beta1.Workspace,
 	}
 }
 
+// GetBaseImageName returns the base image of this controller version.
 func GetBaseImageName() string {
 	presetObj := metadata.MustGet("base")
 	return utils.GetPresetImageName(presetObj.Registry, presetObj.Name, presetObj.Tag)
 }
 
+// GetWorkspaceBaseImageName returns the base image wObj runs: the digest of the base image
+// repository named by a "pinned@sha256:<digest>" runtime channel, or GetBaseImageName.
+func GetWorkspaceBaseImageName(wObj *v1beta1.Workspace) string {
+	if digest, ok := v1beta1.GetPinnedRuntimeDigest(wObj); ok {
+		presetObj := metadata.MustGet("base")
+		return strings.TrimSuffix(utils.GetPresetImageName(presetObj.Registry, presetObj.Name, ""), ":") + "@" + digest
+	}
+	return GetBaseImageName()
+}
+
+// pinnedBaseImage returns the base image the workloads of wObj run, pinned to its verified
+// digest when image verification is enabled.
+func pinnedBaseImage(wObj *v1beta1.Workspace) string {
+	return imageverify.Pin(GetWorkspaceBaseImageName(wObj))
+}
+
 // GetBaseImageTag returns just the tag portion of the base image reference.
 func GetBaseImageTag() string {
 	presetObj := metadata.MustGet("base")
@@ -543,15 +581,12 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, numNodes int, streamingM
 			maxModelLen = pkgmodel.MaxModelLenAuto
 		}
 
-		// When the routing sidecar is needed, vLLM moves to PortDecodeVLLM (5001)
-		// so the sidecar can occupy PortInferenceServer (5000).
+		// When the routing sidecar or the response cache is needed, vLLM moves to
+		// PortDecodeVLLM (5001) so the sidecar can occupy PortInferenceServer (5000).
 		isSidecarNeeded := needsRoutingSidecar(ctx.Workspace)
-		var vllmPort int32
-		if isSidecarNeeded {
-			vllmPort = consts.PortDecodeVLLM
-		}
+		vllmPort := inferenceServerPort(ctx.Workspace)
 
-		commands := inferenceParam.GetInferenceCommand(pkgmodel.RuntimeContext{
+		rc := pkgmodel.RuntimeContext{
 			RuntimeName:          runtimeName,
 			GPUConfig:            gpuConfig,
 			ConfigVolume:         cmVolumeMountRef,
@@ -567,7 +602,22 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, numNodes int, streamingM
 				StreamingModelPath:  streamingModelPath,
 				StreamingLoadFormat: streamingLoadFormat,
 			},
-		})
+		}
+		if tc := ctx.Workspace.Inference.ToolCalling; tc != nil {
+			rc.ToolCallingDisabled = tc.Disabled
+			rc.ToolCallParser = tc.Parser
+			rc.ChatTemplate = tc.ChatTemplate
+		}
+		if so := ctx.Workspace.Inference.StructuredOutputs; so != nil {
+			rc.StructuredOutputsBackend = string(so.Backend)
+		}
+		if ctx.Workspace.Inference.Tracing != nil {
+			rc.OTLPTracesEndpoint = "$" + consts.OTELTracesEndpointEnvName
+		}
+		if ro := ctx.Workspace.Inference.RuntimeOverrides; ro != nil {
+			rc.ExtraArgs = ro.ExtraArgs
+		}
+		commands := inferenceParam.GetInferenceCommand(rc)
 
 		// Only set nodeAffinity when the user supplied selector labels.
 		// An empty MatchExpressions list is rejected by the Kubernetes API server.
@@ -627,12 +677,20 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, numNodes int, streamingM
 					Value: "0",
 				})
 			}
+			// Keep idle client connections open between streamed requests for as long
+			// as the user asks, so that they are not closed before the load balancer's.
+			if keepAlive := serviceKeepAlive(ctx.Workspace); keepAlive > 0 {
+				mainContainerEnv = append(mainContainerEnv, corev1.EnvVar{
+					Name:  consts.VLLMHTTPTimeoutKeepAliveEnvName,
+					Value: strconv.Itoa(int(math.Ceil(keepAlive.Seconds()))),
+				})
+			}
 		}
 
 		spec.Containers = []corev1.Container{
 			{
 				Name:           ctx.Workspace.Name,
-				Image:          GetBaseImageName(),
+				Image:          pinnedBaseImage(ctx.Workspace),
 				Command:        commands,
 				Resources:      resourceReq,
 				Ports:          append([]corev1.ContainerPort(nil), containerPorts...),
@@ -641,6 +699,9 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, numNodes int, streamingM
 				ReadinessProbe: buildProbeWithPort(defaultReadinessProbe, vllmPort),
 				VolumeMounts:   volumeMounts,
 				Env:            mainContainerEnv,
+				// Surfaces errors such as "no space left on device" in the container
+				// status, where the controller reports them as DiskTooSmall.
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 			},
 		}
 
@@ -815,11 +876,8 @@ func SetDistributedInferenceProbe(ctx *generator.WorkspaceGeneratorContext, spec
 		readinessTimeout = defaultStartupProbeTimeout
 	}
 
-	// Determine vLLM port: decode pods use PortDecodeVLLM, others use default.
-	var vllmPort int32
-	if needsRoutingSidecar(ctx.Workspace) {
-		vllmPort = consts.PortDecodeVLLM
-	}
+	// Determine vLLM port: pods fronted by a sidecar use PortDecodeVLLM, others use default.
+	vllmPort := inferenceServerPort(ctx.Workspace)
 
 	// 60 seconds initial delay for liveness probe to allow workers to join the cluster
 	livenessProbe := getDistributedInferenceProbe(probeTypeLiveness, ctx.Workspace, 60, 10, 5, 1, vllmPort)
@@ -845,11 +903,348 @@ func SetDistributedInferenceProbe(ctx *generator.WorkspaceGeneratorContext, spec
 	return nil
 }
 
+// SetDistributedGroupEnv tells the inference container of each multi-node pod where the leader is
+// and how large the group is, like LeaderWorkerSet does, so the runtime does not have to
+// derive it from the pod name. VLLM_HOST_IP makes Ray and vLLM advertise the pod IP, and
+// the socket interface, if set, pins NCCL and Gloo to it.
+func SetDistributedGroupEnv(numNodes int) func(*generator.WorkspaceGeneratorContext, *corev1.PodSpec) error {
+	return func(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+		env := []corev1.EnvVar{
+			{Name: "KAITO_LEADER_ADDRESS", Value: utils.GetRayLeaderHost(ctx.Workspace.ObjectMeta)},
+			{Name: "KAITO_GROUP_SIZE", Value: strconv.Itoa(numNodes)},
+			{
+				Name: "VLLM_HOST_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
+			},
+		}
+		if d := ctx.Workspace.Inference.Distributed; d != nil && d.SocketInterface != "" {
+			env = append(env,
+				corev1.EnvVar{Name: "NCCL_SOCKET_IFNAME", Value: d.SocketInterface},
+				corev1.EnvVar{Name: "GLOO_SOCKET_IFNAME", Value: d.SocketInterface},
+			)
+		}
+		for i := range spec.Containers {
+			if spec.Containers[i].Name == ctx.Workspace.Name {
+				spec.Containers[i].Env = append(spec.Containers[i].Env, env...)
+				break
+			}
+		}
+		return nil
+	}
+}
+
+// SetLogging applies InferenceSpec.Logging: it tells the main inference container which
+// log format to emit and, when a forwarder is configured, has it also write its logs to a
+// volume shared with the fluent-bit sidecar that ships them to the configured destination.
+func SetLogging(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.Logging == nil {
+		return nil
+	}
+	logging := ctx.Workspace.Inference.Logging
+
+	var main *corev1.Container
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == ctx.Workspace.Name {
+			main = &spec.Containers[i]
+			break
+		}
+	}
+
+	if logging.Format != "" && main != nil {
+		main.Env = append(main.Env, corev1.EnvVar{
+			Name:  consts.LogFormatEnvName,
+			Value: string(logging.Format),
+		})
+	}
+
+	if logging.Forwarder != nil && main != nil {
+		main.Env = append(main.Env, corev1.EnvVar{
+			Name:  consts.LogFileEnvName,
+			Value: path.Join(manifests.LogForwarderLogDir, "inference.log"),
+		})
+		main.VolumeMounts = append(main.VolumeMounts, corev1.VolumeMount{
+			Name:      manifests.LogForwarderLogsVolume,
+			MountPath: manifests.LogForwarderLogDir,
+		})
+		container, volumes := manifests.GenerateLogForwarderContainer(logging.Forwarder, ctx.Workspace.Name)
+		spec.Containers = append(spec.Containers, container)
+		spec.Volumes = append(spec.Volumes, volumes...)
+	}
+	return nil
+}
+
+// SetTracing applies InferenceSpec.Tracing: it configures the OpenTelemetry SDK of the
+// main inference container through the standard OTEL_* env vars. Spans are sampled
+// with the parent's decision when the request carries a traceparent header, so traces
+// started at the gateway continue into the model server.
+func SetTracing(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.Tracing == nil {
+		return nil
+	}
+	tracing := ctx.Workspace.Inference.Tracing
+
+	serviceName := tracing.ServiceName
+	if serviceName == "" {
+		serviceName = ctx.Workspace.Name
+	}
+	protocol := tracing.Protocol
+	if protocol == "" {
+		protocol = v1beta1.OTLPProtocolGRPC
+	}
+	sampleRatio := "1"
+	if tracing.SampleRatio != nil {
+		sampleRatio = *tracing.SampleRatio
+	}
+	env := []corev1.EnvVar{
+		{Name: "OTEL_SERVICE_NAME", Value: serviceName},
+		{Name: consts.OTELTracesEndpointEnvName, Value: tracing.Endpoint},
+		{Name: "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", Value: string(protocol)},
+		{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+		{Name: "OTEL_TRACES_SAMPLER_ARG", Value: sampleRatio},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: fmt.Sprintf("k8s.namespace.name=%s,kaito.sh/workspace=%s", ctx.Workspace.Namespace, ctx.Workspace.Name)},
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == ctx.Workspace.Name {
+			spec.Containers[i].Env = append(spec.Containers[i].Env, env...)
+			break
+		}
+	}
+	return nil
+}
+
+// SetRuntimeEnv applies the env vars of InferenceSpec.RuntimeOverrides to the main
+// inference container. Env vars already set by KAITO keep their value.
+func SetRuntimeEnv(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.RuntimeOverrides == nil {
+		return nil
+	}
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		if c.Name != ctx.Workspace.Name {
+			continue
+		}
+		for _, env := range ctx.Workspace.Inference.RuntimeOverrides.Env {
+			if slices.ContainsFunc(c.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }) {
+				continue
+			}
+			c.Env = append(c.Env, corev1.EnvVar{Name: env.Name, Value: env.Value})
+		}
+		break
+	}
+	return nil
+}
+
+// preStopScript drains the inference server and calls its unload endpoint. The
+// arguments are passed as argv, so they are never interpreted by a shell.
+const preStopScript = `import sys, time, urllib.request
+port, path, drain, timeout = sys.argv[1], sys.argv[2], float(sys.argv[3]), float(sys.argv[4])
+time.sleep(drain)
+if path:
+    try:
+        urllib.request.urlopen(urllib.request.Request("http://127.0.0.1:" + port + path, method="POST"), timeout=timeout)
+    except Exception as e:
+        print("model unload failed:", e, file=sys.stderr)
+`
+
+// SetEphemeralStorage applies resource.storage.ephemeralStorage as the ephemeral-storage
+// request and limit of the main inference container.
+func SetEphemeralStorage(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	storage := ctx.Workspace.Resource.Storage
+	if storage == nil || storage.EphemeralStorage == nil {
+		return nil
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		resources := &spec.Containers[i].Resources
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		resources.Requests[corev1.ResourceEphemeralStorage] = storage.EphemeralStorage.DeepCopy()
+		resources.Limits[corev1.ResourceEphemeralStorage] = storage.EphemeralStorage.DeepCopy()
+	}
+	return nil
+}
+
+// SetCompute applies resource.compute as the CPU and memory requests of the main
+// inference container.
+func SetCompute(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	compute := ctx.Workspace.Resource.Compute
+	if compute == nil {
+		return nil
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		resources := &spec.Containers[i].Resources
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[corev1.ResourceCPU] = compute.CPU.DeepCopy()
+		resources.Requests[corev1.ResourceMemory] = compute.Memory.DeepCopy()
+	}
+	return nil
+}
+
+// SetProbes applies InferenceSpec.Probes to the probes of the main inference container.
+// It must be appended after the modifiers that set the probes. When the period of the
+// startup probe is changed without its failure threshold, the threshold is derived from
+// the readiness timeout of the model so the startup window stays the same. The timeout
+// of the benchmark startup probe covers the benchmark run and is kept.
+func SetProbes(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.Probes == nil {
+		return nil
+	}
+	probes := ctx.Workspace.Inference.Probes
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		if c.Name != ctx.Workspace.Name {
+			continue
+		}
+		if s := probes.Startup; s != nil && c.StartupProbe != nil {
+			startup := *s
+			if startup.PeriodSeconds != nil && startup.FailureThreshold == nil {
+				readinessTimeout := ctx.Model.GetInferenceParameters().ReadinessTimeout
+				if readinessTimeout <= 0 {
+					readinessTimeout = defaultStartupProbeTimeout
+				}
+				startup.FailureThreshold = ptr.To(int32(math.Ceil(readinessTimeout.Seconds() / float64(*startup.PeriodSeconds))))
+			}
+			if v1beta1.ShouldRunBenchmark(ctx.Workspace) {
+				startup.TimeoutSeconds = nil
+			}
+			applyProbeSpec(c.StartupProbe, &startup)
+		}
+		applyProbeSpec(c.LivenessProbe, probes.Liveness)
+		applyProbeSpec(c.ReadinessProbe, probes.Readiness)
+		break
+	}
+	return nil
+}
+
+// applyProbeSpec overrides the fields of probe that are set in s.
+func applyProbeSpec(probe *corev1.Probe, s *v1beta1.ProbeSpec) {
+	if probe == nil || s == nil {
+		return
+	}
+	if s.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *s.InitialDelaySeconds
+	}
+	if s.PeriodSeconds != nil {
+		probe.PeriodSeconds = *s.PeriodSeconds
+	}
+	if s.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *s.TimeoutSeconds
+	}
+	if s.FailureThreshold != nil {
+		probe.FailureThreshold = *s.FailureThreshold
+	}
+}
+
+// SetShutdown applies InferenceSpec.Shutdown: it sets the termination grace period of
+// the pods and adds the preStop hook that drains and unloads the main inference container.
+func SetShutdown(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.Shutdown == nil {
+		return nil
+	}
+	shutdown := ctx.Workspace.Inference.Shutdown
+
+	gracePeriod := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	if shutdown.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *shutdown.TerminationGracePeriodSeconds
+		spec.TerminationGracePeriodSeconds = ptr.To(gracePeriod)
+	}
+	preStop := shutdown.PreStop
+	if preStop == nil || (preStop.DrainSeconds == 0 && preStop.UnloadPath == "") {
+		return nil
+	}
+
+	port := consts.PortInferenceServer
+	if p := inferenceServerPort(ctx.Workspace); p > 0 {
+		port = p
+	}
+	// The unload call may use what is left of the grace period after draining.
+	timeout := max(gracePeriod-int64(preStop.DrainSeconds), 1)
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		if spec.Containers[i].Lifecycle == nil {
+			spec.Containers[i].Lifecycle = &corev1.Lifecycle{}
+		}
+		spec.Containers[i].Lifecycle.PreStop = &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"python3", "-c", preStopScript,
+					strconv.Itoa(int(port)),
+					preStop.UnloadPath,
+					strconv.Itoa(int(preStop.DrainSeconds)),
+					strconv.FormatInt(timeout, 10),
+				},
+			},
+		}
+		break
+	}
+	return nil
+}
+
 func SetDefaultModelWeightsVolume(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
 	spec.Volumes = append(spec.Volumes, utils.DefaultModelWeightsVolume)
 	return nil
 }
 
+// SetCachedModelAffinity makes the scheduler prefer the existing nodes that cache the preset
+// when the workspace runs on nodes it does not provision. The nodes are ranked by
+// utils.SelectNodes, so the preferred and previous nodes of the workspace still come first.
+func SetCachedModelAffinity(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	ws := ctx.Workspace
+	if !workspace.IsNodeAutoProvisioningDisabled(&ws.Resource) || ws.Inference == nil || ws.Inference.Preset == nil {
+		return nil
+	}
+	readyNodes, err := nodeprovision.GetReadyNodes(ctx.Ctx, ctx.KubeClient, ctx.NodeProvisioner, ws)
+	if err != nil {
+		return fmt.Errorf("failed to list ready nodes: %w", err)
+	}
+	model := string(ws.Inference.Preset.Name)
+	count := max(1, int(ws.Status.TargetNodeCount))
+	var cached []string
+	//nolint:staticcheck //SA1019: deprecate Resource.PreferredNodes field
+	for _, node := range utils.SelectNodes(readyNodes, ws.Resource.PreferredNodes, ws.Status.WorkerNodes, model, count) {
+		if utils.HasCachedModel(node, model) {
+			cached = append(cached, node.Name)
+		}
+	}
+	if len(cached) == 0 {
+		return nil
+	}
+
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.PreferredSchedulingTerm{
+			Weight: 100,
+			Preference: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      metav1.ObjectNameField,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   cached,
+				}},
+			},
+		})
+	return nil
+}
+
 // SetProvisionerNodeSelector appends provisioner-specific node selector
 // requirements (e.g. kaito.sh/workspace, kaito.sh/workspacenamespace) to the
 // pod's required node affinity, isolating pods to the nodes the provisioner
@@ -978,6 +1373,320 @@ func injectRoutingSidecar(spec *corev1.PodSpec) {
 	})
 }
 
+// inferenceServerPort returns the port vLLM listens on, or 0 for PortInferenceServer.
+// A sidecar that fronts vLLM, the routing sidecar, the response cache, the API
+// normalizer or the tier router, takes PortInferenceServer and vLLM moves to PortDecodeVLLM.
+func inferenceServerPort(ws *v1beta1.Workspace) int32 {
+	if needsRoutingSidecar(ws) || needsTierRouter(ws) ||
+		(ws.Inference != nil && (ws.Inference.ResponseCache != nil || ws.Inference.APINormalization != nil)) {
+		return consts.PortDecodeVLLM
+	}
+	return 0
+}
+
+// defaultResponseCacheTTL and defaultResponseCacheMaxEntries apply when
+// InferenceSpec.ResponseCache leaves them unset.
+const (
+	defaultResponseCacheTTL        = 10 * time.Minute
+	defaultResponseCacheMaxEntries = int32(1000)
+)
+
+// SetResponseCache applies InferenceSpec.ResponseCache: it moves the main inference
+// container to PortDecodeVLLM and adds the response cache sidecar on PortInferenceServer,
+// so the Service and the probes of the other pods keep targeting the same port.
+func SetResponseCache(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.ResponseCache == nil {
+		return nil
+	}
+	rc := ctx.Workspace.Inference.ResponseCache
+
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		for j := range spec.Containers[i].Ports {
+			if spec.Containers[i].Ports[j].ContainerPort == consts.PortInferenceServer {
+				spec.Containers[i].Ports[j].ContainerPort = consts.PortDecodeVLLM
+			}
+		}
+	}
+
+	backend := rc.Backend
+	if backend == "" {
+		backend = v1beta1.ResponseCacheBackendMemory
+	}
+	keyPolicy := rc.KeyPolicy
+	if keyPolicy == "" {
+		keyPolicy = v1beta1.ResponseCacheKeyPolicyExactPrompt
+	}
+	ttl := defaultResponseCacheTTL
+	if rc.TTL != nil {
+		ttl = rc.TTL.Duration
+	}
+	args := []string{
+		fmt.Sprintf("--port=%d", consts.PortInferenceServer),
+		fmt.Sprintf("--upstream-port=%d", consts.PortDecodeVLLM),
+		"--backend=" + string(backend),
+		"--key-policy=" + string(keyPolicy),
+		"--ttl-seconds=" + strconv.FormatInt(int64(ttl.Seconds()), 10),
+	}
+	for _, h := range rc.IdentityHeaders {
+		args = append(args, "--identity-header="+strings.ToLower(h))
+	}
+	var env []corev1.EnvVar
+	switch backend {
+	case v1beta1.ResponseCacheBackendMemory:
+		maxEntries := defaultResponseCacheMaxEntries
+		if rc.MaxEntries != nil {
+			maxEntries = *rc.MaxEntries
+		}
+		args = append(args, fmt.Sprintf("--max-entries=%d", maxEntries))
+	case v1beta1.ResponseCacheBackendRedis:
+		if rc.Redis == nil {
+			return fmt.Errorf("responseCache.redis must be set for the Redis backend")
+		}
+		args = append(args, "--redis-address="+rc.Redis.Address)
+		if rc.Redis.TLS {
+			args = append(args, "--redis-tls")
+		}
+		if rc.Redis.PasswordSecret != "" {
+			env = append(env, corev1.EnvVar{
+				Name: "REDIS_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: rc.Redis.PasswordSecret},
+						Key:                  consts.ResponseCacheRedisPasswordKey,
+					},
+				},
+			})
+		}
+	}
+
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:    consts.ResponseCacheContainerName,
+		Image:   pinnedBaseImage(ctx.Workspace),
+		Command: append([]string{"python3", "/workspace/vllm/response_cache.py"}, args...),
+		Env:     env,
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: consts.PortInferenceServer, Name: "cache", Protocol: corev1.ProtocolTCP},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(consts.PortInferenceServer)},
+			},
+			PeriodSeconds: 10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+	})
+	return nil
+}
+
+// SetAPINormalizer adds the API normalization sidecar. Like the response cache, the
+// sidecar takes PortInferenceServer and vLLM moves to PortDecodeVLLM.
+func SetAPINormalizer(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.APINormalization == nil {
+		return nil
+	}
+	norm := ctx.Workspace.Inference.APINormalization
+
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		for j := range spec.Containers[i].Ports {
+			if spec.Containers[i].Ports[j].ContainerPort == consts.PortInferenceServer {
+				spec.Containers[i].Ports[j].ContainerPort = consts.PortDecodeVLLM
+			}
+		}
+	}
+
+	translate := norm.Translate
+	if translate == "" {
+		translate = v1beta1.APITranslationNone
+	}
+	maxTokensField := norm.MaxTokensField
+	if maxTokensField == "" {
+		maxTokensField = v1beta1.MaxTokensFieldMaxTokens
+	}
+	args := []string{
+		fmt.Sprintf("--port=%d", consts.PortInferenceServer),
+		fmt.Sprintf("--upstream-port=%d", consts.PortDecodeVLLM),
+		"--translate=" + string(translate),
+		"--max-tokens-field=" + string(maxTokensField),
+	}
+	if norm.DefaultModel != "" {
+		args = append(args, "--default-model="+norm.DefaultModel)
+	}
+	// The adapters are passed along with the header, so that the sidecar only selects the
+	// adapters of the workspace. Changing the adapters rolls out new pods anyway.
+	if norm.AdapterHeader != "" {
+		args = append(args, "--adapter-header="+strings.ToLower(norm.AdapterHeader))
+		adapters := make([]string, 0, len(ctx.Workspace.Inference.Adapters))
+		for _, adapter := range ctx.Workspace.Inference.Adapters {
+			if adapter.Source != nil && adapter.Source.Name != "" {
+				adapters = append(adapters, adapter.Source.Name)
+			}
+		}
+		args = append(args, "--adapters="+strings.Join(adapters, ","))
+	}
+
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:    consts.APINormalizerContainerName,
+		Image:   pinnedBaseImage(ctx.Workspace),
+		Command: append([]string{"python3", "/workspace/vllm/api_normalizer.py"}, args...),
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: consts.PortInferenceServer, Name: "api-normalizer", Protocol: corev1.ProtocolTCP},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(consts.PortInferenceServer)},
+			},
+			PeriodSeconds: 10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+	})
+	return nil
+}
+
+// SetTokenizer adds the tokenizer sidecar. It listens on PortTokenizer and forwards
+// /tokenize and /detokenize directly to vLLM, bypassing the sidecars in front of it.
+func SetTokenizer(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.Tokenizer == nil {
+		return nil
+	}
+	upstreamPort := inferenceServerPort(ctx.Workspace)
+	if upstreamPort == 0 {
+		upstreamPort = consts.PortInferenceServer
+	}
+
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:  consts.TokenizerContainerName,
+		Image: pinnedBaseImage(ctx.Workspace),
+		Command: []string{
+			"python3", "/workspace/vllm/tokenizer_server.py",
+			fmt.Sprintf("--port=%d", consts.PortTokenizer),
+			fmt.Sprintf("--upstream-port=%d", upstreamPort),
+		},
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: consts.PortTokenizer, Name: "tokenizer", Protocol: corev1.ProtocolTCP},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(consts.PortTokenizer)},
+			},
+			PeriodSeconds: 10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	})
+	return nil
+}
+
+// SetImagePull applies the image pull settings of the workspace. The pull policy is set on
+// every container that runs the inference image; sidecars with their own images keep the
+// default.
+func SetImagePull(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if ctx.Workspace.Inference == nil || ctx.Workspace.Inference.ImagePull == nil {
+		return nil
+	}
+	imagePull := ctx.Workspace.Inference.ImagePull
+	if imagePull.Policy != "" {
+		image := pinnedBaseImage(ctx.Workspace)
+		for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+			for i := range containers {
+				if containers[i].Image == image {
+					containers[i].ImagePullPolicy = imagePull.Policy
+				}
+			}
+		}
+	}
+	if imagePull.LazyPull != nil {
+		spec.RuntimeClassName = ptr.To(imagePull.LazyPull.RuntimeClassName)
+	}
+	return nil
+}
+
+// needsTierRouter returns true if the workspace is a tier of a heterogeneous
+// MultiRoleInference that forwards long prompts to the next tier.
+func needsTierRouter(ws *v1beta1.Workspace) bool {
+	_, ok := ws.Annotations[v1beta1.AnnotationTierMaxPromptTokens]
+	return ok && v1beta1.GetWorkspaceRuntimeName(ws) == pkgmodel.RuntimeNameVLLM
+}
+
+// SetTierRouter adds the tier router sidecar to tiered workspaces. Like the response
+// cache, the sidecar takes PortInferenceServer and vLLM moves to PortDecodeVLLM. Prompts
+// of at most AnnotationTierMaxPromptTokens tokens are served locally; longer ones are
+// forwarded to the Service named by AnnotationTierOverflowService.
+func SetTierRouter(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if !needsTierRouter(ctx.Workspace) {
+		return nil
+	}
+	annotations := ctx.Workspace.Annotations
+	maxPromptTokens, err := strconv.ParseInt(annotations[v1beta1.AnnotationTierMaxPromptTokens], 10, 32)
+	if err != nil || maxPromptTokens < 1 {
+		return fmt.Errorf("invalid %s annotation %q", v1beta1.AnnotationTierMaxPromptTokens, annotations[v1beta1.AnnotationTierMaxPromptTokens])
+	}
+	// The annotation becomes part of the sidecar command line.
+	overflowService := annotations[v1beta1.AnnotationTierOverflowService]
+	if errs := validation.IsDNS1035Label(overflowService); len(errs) > 0 {
+		return fmt.Errorf("invalid %s annotation %q: %s", v1beta1.AnnotationTierOverflowService, overflowService, strings.Join(errs, ", "))
+	}
+
+	for i := range spec.Containers {
+		if spec.Containers[i].Name != ctx.Workspace.Name {
+			continue
+		}
+		for j := range spec.Containers[i].Ports {
+			if spec.Containers[i].Ports[j].ContainerPort == consts.PortInferenceServer {
+				spec.Containers[i].Ports[j].ContainerPort = consts.PortDecodeVLLM
+			}
+		}
+	}
+
+	overflowURL := fmt.Sprintf("http://%s.%s.svc:%d", overflowService, ctx.Workspace.Namespace, consts.PortInferenceServer)
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:  consts.TierRouterContainerName,
+		Image: pinnedBaseImage(ctx.Workspace),
+		Command: []string{
+			"python3", "/workspace/vllm/tier_router.py",
+			fmt.Sprintf("--port=%d", consts.PortInferenceServer),
+			fmt.Sprintf("--upstream-port=%d", consts.PortDecodeVLLM),
+			fmt.Sprintf("--max-prompt-tokens=%d", maxPromptTokens),
+			"--overflow-url=" + overflowURL,
+		},
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: consts.PortInferenceServer, Name: "tier-router", Protocol: corev1.ProtocolTCP},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(consts.PortInferenceServer)},
+			},
+			PeriodSeconds: 10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+	})
+	return nil
+}
+
 // needsRoutingSidecar returns true if the workspace requires the llm-d routing sidecar.
 func needsRoutingSidecar(ws *v1beta1.Workspace) bool {
 	role, ok := ws.Labels[v1beta1.LabelInferenceRole]
@@ -986,3 +1695,12 @@ func needsRoutingSidecar(ws *v1beta1.Workspace) bool {
 	}
 	return v1beta1.GetWorkspaceRuntimeName(ws) == pkgmodel.RuntimeNameVLLM
 }
+
+// serviceKeepAlive returns inference.service.timeouts.keepAlive of the workspace, or zero when unset.
+func serviceKeepAlive(wObj *v1beta1.Workspace) time.Duration {
+	if wObj.Inference == nil || wObj.Inference.Service == nil || wObj.Inference.Service.Timeouts == nil ||
+		wObj.Inference.Service.Timeouts.KeepAlive == nil {
+		return 0
+	}
+	return wObj.Inference.Service.Timeouts.KeepAlive.Duration
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -42,6 +43,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/test"
 	workspaceutil "github.com/kaito-project/kaito/pkg/utils/workspace"
 	"github.com/kaito-project/kaito/pkg/workspace/estimator/nodesestimator"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	metadata "github.com/kaito-project/kaito/presets/workspace/models"
 )
 
@@ -86,6 +88,7 @@ func TestGeneratePresetInference(t *testing.T) {
 		expectedCmd        string
 		hasAdapters        bool
 		inferenceConfig    string
+		service            *v1beta1.EndpointServiceSpec
 		expectedModelImage string
 		expectedVolume     string
 		expectedEnvVars    []corev1.EnvVar
@@ -123,6 +126,25 @@ func TestGeneratePresetInference(t *testing.T) {
 			expectedEnvVars: []corev1.EnvVar{flashInferSamplerEnvVar},
 		},
 
+		"test-model/vllm-with-keep-alive": {
+			workspace: test.MockWorkspaceWithPresetVLLM,
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&storagev1.StorageClass{}), mock.Anything).Return(nil)
+			},
+			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
+			service: &v1beta1.EndpointServiceSpec{
+				Timeouts: &v1beta1.ServiceTimeoutsSpec{KeepAlive: &metav1.Duration{Duration: 90500 * time.Millisecond}},
+			},
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --gpu-memory-utilization=0.84 --max-model-len=auto --tensor-parallel-size=1 --served-model-name=mymodel",
+			expectedEnvVars: []corev1.EnvVar{flashInferSamplerEnvVar, {
+				Name:  consts.VLLMHTTPTimeoutKeepAliveEnvName,
+				Value: "91",
+			}},
+		},
+
 		"test-model-no-parallel/vllm": {
 			workspace: test.MockWorkspaceWithPresetVLLM,
 			nodeCount: 1,
@@ -258,6 +280,17 @@ func TestGeneratePresetInference(t *testing.T) {
 						FieldPath: fmt.Sprintf("metadata.labels['%s']", appsv1.PodIndexLabel),
 					},
 				},
+			}, {
+				Name:  "KAITO_LEADER_ADDRESS",
+				Value: "testWorkspace-0.testWorkspace-headless.kaito.svc.cluster.local",
+			}, {
+				Name:  "KAITO_GROUP_SIZE",
+				Value: "4",
+			}, {
+				Name: "VLLM_HOST_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
 			}},
 		},
 
@@ -295,6 +328,17 @@ func TestGeneratePresetInference(t *testing.T) {
 						FieldPath: fmt.Sprintf("metadata.labels['%s']", appsv1.PodIndexLabel),
 					},
 				},
+			}, {
+				Name:  "KAITO_LEADER_ADDRESS",
+				Value: "testWorkspace-0.testWorkspace-headless.kaito.svc.cluster.local",
+			}, {
+				Name:  "KAITO_GROUP_SIZE",
+				Value: "4",
+			}, {
+				Name: "VLLM_HOST_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
 			}},
 		},
 
@@ -374,6 +418,7 @@ func TestGeneratePresetInference(t *testing.T) {
 			// Always assign (including the zero value) so prior test cases don't leak
 			// a non-empty Config into later runs through the shared MockWorkspace.
 			workspace.Inference.Config = tc.inferenceConfig
+			workspace.Inference.Service = tc.service
 
 			model := plugin.KaitoModelRegister.MustGet(tc.modelName)
 
@@ -403,15 +448,12 @@ func TestGeneratePresetInference(t *testing.T) {
 						break
 					}
 				}
-				expectedPullerCmd := []string{
-					"oras",
-					"pull",
-					tc.expectedModelImage,
-					"-o",
+				expectedPullerArgs := []string{
 					utils.DefaultWeightsVolumePath,
+					tc.expectedModelImage,
 				}
-				if !reflect.DeepEqual(pullerContainer.Command, expectedPullerCmd) {
-					t.Errorf("%s: Puller command is not expected, got %v, expect %v", k, pullerContainer.Command, expectedPullerCmd)
+				if len(pullerContainer.Args) < 2 || !reflect.DeepEqual(pullerContainer.Args[2:], expectedPullerArgs) {
+					t.Errorf("%s: Puller arguments are not expected, got %v, expect %v", k, pullerContainer.Args, expectedPullerArgs)
 				}
 			}
 			if image != baseImageName {
@@ -1813,3 +1855,692 @@ func TestSetProvisionerNodeSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCachedModelAffinity(t *testing.T) {
+	readyNode := func(name, cachedModels string) corev1.Node {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}
+		if cachedModels != "" {
+			node.Annotations = map[string]string{consts.AnnotationNodeCachedModels: cachedModels}
+		}
+		return node
+	}
+	newWorkspace := func(policy v1beta1.ProvisioningPolicy) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+			Resource:   v1beta1.ResourceSpec{LabelSelector: &metav1.LabelSelector{}, ProvisioningPolicy: policy},
+			Inference:  &v1beta1.InferenceSpec{Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "phi-4"}}},
+			Status:     v1beta1.WorkspaceStatus{TargetNodeCount: 1},
+		}
+	}
+
+	t.Run("prefers the node that caches the preset", func(t *testing.T) {
+		mockClient := test.NewClient()
+		mockClient.On("List", mock.Anything, mock.IsType(&corev1.NodeList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*corev1.NodeList).Items = []corev1.Node{
+					readyNode("node-a", ""),
+					readyNode("node-b", "qwen3-8b,phi-4"),
+				}
+			}).Return(nil)
+		gctx := &generator.WorkspaceGeneratorContext{Ctx: context.TODO(), KubeClient: mockClient, Workspace: newWorkspace(v1beta1.ProvisioningPolicyNever)}
+		spec := &corev1.PodSpec{}
+
+		assert.NoError(t, SetCachedModelAffinity(gctx, spec))
+		if assert.NotNil(t, spec.Affinity) && assert.NotNil(t, spec.Affinity.NodeAffinity) {
+			preferred := spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			assert.Equal(t, []corev1.PreferredSchedulingTerm{{
+				Weight: 100,
+				Preference: corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: metav1.ObjectNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-b"}},
+				}},
+			}}, preferred)
+		}
+	})
+
+	t.Run("no node caches the preset", func(t *testing.T) {
+		mockClient := test.NewClient()
+		mockClient.On("List", mock.Anything, mock.IsType(&corev1.NodeList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*corev1.NodeList).Items = []corev1.Node{readyNode("node-a", "qwen3-8b")}
+			}).Return(nil)
+		gctx := &generator.WorkspaceGeneratorContext{Ctx: context.TODO(), KubeClient: mockClient, Workspace: newWorkspace(v1beta1.ProvisioningPolicyNever)}
+		spec := &corev1.PodSpec{}
+
+		assert.NoError(t, SetCachedModelAffinity(gctx, spec))
+		assert.Nil(t, spec.Affinity)
+	})
+
+	t.Run("provisioned nodes are not listed", func(t *testing.T) {
+		mockClient := test.NewClient()
+		gctx := &generator.WorkspaceGeneratorContext{Ctx: context.TODO(), KubeClient: mockClient, Workspace: newWorkspace("")}
+		spec := &corev1.PodSpec{}
+
+		assert.NoError(t, SetCachedModelAffinity(gctx, spec))
+		assert.Nil(t, spec.Affinity)
+		mockClient.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSetLogging(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-workspace"}},
+		}
+	}
+	newWorkspace := func(logging *v1beta1.LoggingSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{Logging: logging},
+		}
+	}
+
+	t.Run("no logging config", func(t *testing.T) {
+		spec := newSpec()
+		err := SetLogging(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec)
+		assert.NoError(t, err)
+		assert.Len(t, spec.Containers, 1)
+		assert.Empty(t, spec.Containers[0].Env)
+		assert.Empty(t, spec.Volumes)
+	})
+
+	t.Run("json format only", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.LoggingSpec{Format: v1beta1.LogFormatJSON})
+		err := SetLogging(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec)
+		assert.NoError(t, err)
+		assert.Len(t, spec.Containers, 1)
+		assert.Contains(t, spec.Containers[0].Env, corev1.EnvVar{Name: consts.LogFormatEnvName, Value: "json"})
+		assert.Empty(t, spec.Volumes)
+	})
+
+	t.Run("forwarder sidecar", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.LoggingSpec{
+			Forwarder: &v1beta1.LogForwarderSpec{DestinationSecret: "log-dest"},
+		})
+		err := SetLogging(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec)
+		assert.NoError(t, err)
+		if assert.Len(t, spec.Containers, 2) {
+			assert.Equal(t, manifests.LogForwarderContainerName, spec.Containers[1].Name)
+		}
+		assert.Contains(t, spec.Containers[0].Env, corev1.EnvVar{Name: consts.LogFileEnvName, Value: "/var/log/kaito/inference.log"})
+		assert.Contains(t, spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: manifests.LogForwarderLogsVolume, MountPath: manifests.LogForwarderLogDir})
+		assert.Len(t, spec.Volumes, 2)
+	})
+}
+
+func TestSetResponseCache(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-workspace", Ports: []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer}}}},
+		}
+	}
+	newWorkspace := func(cache *v1beta1.ResponseCacheSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{ResponseCache: cache},
+		}
+	}
+
+	t.Run("no response cache", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(nil)
+		assert.NoError(t, SetResponseCache(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Len(t, spec.Containers, 1)
+		assert.Equal(t, consts.PortInferenceServer, spec.Containers[0].Ports[0].ContainerPort)
+		assert.Zero(t, inferenceServerPort(ws))
+	})
+
+	t.Run("memory backend defaults", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ResponseCacheSpec{})
+		assert.NoError(t, SetResponseCache(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, consts.PortDecodeVLLM, inferenceServerPort(ws))
+		assert.Equal(t, consts.PortDecodeVLLM, spec.Containers[0].Ports[0].ContainerPort)
+		if assert.Len(t, spec.Containers, 2) {
+			sidecar := spec.Containers[1]
+			assert.Equal(t, consts.ResponseCacheContainerName, sidecar.Name)
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/response_cache.py",
+				"--port=5000", "--upstream-port=5001", "--backend=Memory", "--key-policy=ExactPrompt", "--ttl-seconds=600", "--max-entries=1000",
+			}, sidecar.Command)
+			assert.Equal(t, consts.PortInferenceServer, sidecar.Ports[0].ContainerPort)
+			assert.Empty(t, sidecar.Env)
+		}
+	})
+
+	t.Run("redis backend", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ResponseCacheSpec{
+			Backend:         v1beta1.ResponseCacheBackendRedis,
+			KeyPolicy:       v1beta1.ResponseCacheKeyPolicyNormalizedPrompt,
+			TTL:             &metav1.Duration{Duration: time.Hour},
+			Redis:           &v1beta1.RedisCacheSpec{Address: "redis.cache.svc:6380", PasswordSecret: "redis-auth", TLS: true},
+			IdentityHeaders: []string{"X-Tenant-ID"},
+		})
+		assert.NoError(t, SetResponseCache(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		if assert.Len(t, spec.Containers, 2) {
+			sidecar := spec.Containers[1]
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/response_cache.py",
+				"--port=5000", "--upstream-port=5001", "--backend=Redis", "--key-policy=NormalizedPrompt", "--ttl-seconds=3600",
+				"--identity-header=x-tenant-id",
+				"--redis-address=redis.cache.svc:6380", "--redis-tls",
+			}, sidecar.Command)
+			if assert.Len(t, sidecar.Env, 1) {
+				assert.Equal(t, "REDIS_PASSWORD", sidecar.Env[0].Name)
+				assert.Equal(t, "redis-auth", sidecar.Env[0].ValueFrom.SecretKeyRef.Name)
+				assert.Equal(t, consts.ResponseCacheRedisPasswordKey, sidecar.Env[0].ValueFrom.SecretKeyRef.Key)
+			}
+		}
+	})
+}
+
+func TestSetShutdown(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-workspace"}, {Name: "sidecar"}},
+		}
+	}
+	newWorkspace := func(shutdown *v1beta1.ShutdownSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{Shutdown: shutdown},
+		}
+	}
+
+	t.Run("no shutdown config", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetShutdown(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.Nil(t, spec.TerminationGracePeriodSeconds)
+		assert.Nil(t, spec.Containers[0].Lifecycle)
+	})
+
+	t.Run("grace period only", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ShutdownSpec{TerminationGracePeriodSeconds: ptr.To(int64(300))})
+		assert.NoError(t, SetShutdown(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, ptr.To(int64(300)), spec.TerminationGracePeriodSeconds)
+		assert.Nil(t, spec.Containers[0].Lifecycle)
+	})
+
+	t.Run("drain and unload", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ShutdownSpec{
+			TerminationGracePeriodSeconds: ptr.To(int64(120)),
+			PreStop:                       &v1beta1.PreStopSpec{DrainSeconds: 20, UnloadPath: "/sleep?level=1"},
+		})
+		assert.NoError(t, SetShutdown(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		if assert.NotNil(t, spec.Containers[0].Lifecycle) {
+			cmd := spec.Containers[0].Lifecycle.PreStop.Exec.Command
+			assert.Equal(t, []string{"python3", "-c"}, cmd[:2])
+			assert.Equal(t, []string{"5000", "/sleep?level=1", "20", "100"}, cmd[3:])
+		}
+		assert.Nil(t, spec.Containers[1].Lifecycle)
+	})
+}
+
+func TestSetProbes(t *testing.T) {
+	test.RegisterTestModel()
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:           "test-workspace",
+					StartupProbe:   buildStartupProbe(30 * time.Minute),
+					LivenessProbe:  buildProbeWithPort(defaultLivenessProbe, 0),
+					ReadinessProbe: buildProbeWithPort(defaultReadinessProbe, 0),
+				},
+				{Name: "sidecar", ReadinessProbe: &corev1.Probe{PeriodSeconds: 5}},
+			},
+		}
+	}
+	newContext := func(probes *v1beta1.ProbesSpec) *generator.WorkspaceGeneratorContext {
+		return &generator.WorkspaceGeneratorContext{
+			Workspace: &v1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+				Inference:  &v1beta1.InferenceSpec{Probes: probes},
+			},
+			Model: plugin.KaitoModelRegister.MustGet("test-model"),
+		}
+	}
+
+	t.Run("no probe config", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetProbes(newContext(nil), spec))
+		assert.Equal(t, newSpec(), spec)
+	})
+
+	t.Run("overrides only the fields that are set", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetProbes(newContext(&v1beta1.ProbesSpec{
+			Liveness:  &v1beta1.ProbeSpec{PeriodSeconds: ptr.To(int32(30)), FailureThreshold: ptr.To(int32(6))},
+			Readiness: &v1beta1.ProbeSpec{InitialDelaySeconds: ptr.To(int32(0)), TimeoutSeconds: ptr.To(int32(5))},
+		}), spec))
+		liveness := spec.Containers[0].LivenessProbe
+		assert.Equal(t, int32(30), liveness.PeriodSeconds)
+		assert.Equal(t, int32(6), liveness.FailureThreshold)
+		assert.Equal(t, defaultLivenessProbe.HTTPGet, liveness.HTTPGet)
+		readiness := spec.Containers[0].ReadinessProbe
+		assert.Equal(t, int32(0), readiness.InitialDelaySeconds)
+		assert.Equal(t, int32(5), readiness.TimeoutSeconds)
+		assert.Equal(t, int32(10), readiness.PeriodSeconds)
+		assert.Equal(t, buildStartupProbe(30*time.Minute), spec.Containers[0].StartupProbe)
+		assert.Equal(t, int32(5), spec.Containers[1].ReadinessProbe.PeriodSeconds, "sidecars keep their probes")
+	})
+
+	t.Run("startup period keeps the readiness timeout of the model", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetProbes(newContext(&v1beta1.ProbesSpec{
+			Startup: &v1beta1.ProbeSpec{PeriodSeconds: ptr.To(int32(20))},
+		}), spec))
+		assert.Equal(t, int32(20), spec.Containers[0].StartupProbe.PeriodSeconds)
+		assert.Equal(t, int32(90), spec.Containers[0].StartupProbe.FailureThreshold)
+	})
+
+	t.Run("explicit startup failure threshold", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetProbes(newContext(&v1beta1.ProbesSpec{
+			Startup: &v1beta1.ProbeSpec{PeriodSeconds: ptr.To(int32(20)), FailureThreshold: ptr.To(int32(360))},
+		}), spec))
+		assert.Equal(t, int32(360), spec.Containers[0].StartupProbe.FailureThreshold)
+	})
+}
+
+func TestSetEphemeralStorage(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "test-workspace", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				}},
+				{Name: "sidecar"},
+			},
+		}
+	}
+	newWorkspace := func(storage *v1beta1.WorkloadStorageSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Resource:   v1beta1.ResourceSpec{Storage: storage},
+			Inference:  &v1beta1.InferenceSpec{},
+		}
+	}
+
+	t.Run("no storage config", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetEphemeralStorage(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.NotContains(t, spec.Containers[0].Resources.Limits, corev1.ResourceEphemeralStorage)
+		assert.Nil(t, spec.Containers[0].Resources.Requests)
+	})
+
+	t.Run("ephemeral storage on main container only", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.WorkloadStorageSpec{EphemeralStorage: ptr.To(resource.MustParse("100Gi"))})
+		assert.NoError(t, SetEphemeralStorage(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		main := spec.Containers[0].Resources
+		assert.Equal(t, resource.MustParse("100Gi"), main.Requests[corev1.ResourceEphemeralStorage])
+		assert.Equal(t, resource.MustParse("100Gi"), main.Limits[corev1.ResourceEphemeralStorage])
+		assert.Equal(t, resource.MustParse("1"), main.Limits["nvidia.com/gpu"])
+		assert.Nil(t, spec.Containers[1].Resources.Limits)
+	})
+}
+
+func TestSetCompute(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "test-workspace", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				}},
+				{Name: "sidecar"},
+			},
+		}
+	}
+	newWorkspace := func(compute *v1beta1.WorkloadComputeSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Resource:   v1beta1.ResourceSpec{Compute: compute},
+			Inference:  &v1beta1.InferenceSpec{},
+		}
+	}
+
+	t.Run("no compute config", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetCompute(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.Nil(t, spec.Containers[0].Resources.Requests)
+	})
+
+	t.Run("requests on main container only", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.WorkloadComputeSpec{CPU: resource.MustParse("4"), Memory: resource.MustParse("16Gi")})
+		assert.NoError(t, SetCompute(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		main := spec.Containers[0].Resources
+		assert.Equal(t, resource.MustParse("4"), main.Requests[corev1.ResourceCPU])
+		assert.Equal(t, resource.MustParse("16Gi"), main.Requests[corev1.ResourceMemory])
+		assert.NotContains(t, main.Limits, corev1.ResourceCPU)
+		assert.Nil(t, spec.Containers[1].Resources.Requests)
+	})
+}
+
+func TestSetDistributedGroupEnv(t *testing.T) {
+	ws := &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Inference: &v1beta1.InferenceSpec{
+			Distributed: &v1beta1.DistributedInferenceSpec{SocketInterface: "eth0"},
+		},
+	}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "test-workspace"}, {Name: "sidecar"}}}
+	assert.NoError(t, SetDistributedGroupEnv(3)(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+
+	env := map[string]corev1.EnvVar{}
+	for _, e := range spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	assert.Equal(t, "3", env["KAITO_GROUP_SIZE"].Value)
+	assert.Equal(t, "test-workspace-0.test-workspace-headless.default.svc.cluster.local", env["KAITO_LEADER_ADDRESS"].Value)
+	assert.Equal(t, "status.podIP", env["VLLM_HOST_IP"].ValueFrom.FieldRef.FieldPath)
+	assert.Equal(t, "eth0", env["NCCL_SOCKET_IFNAME"].Value)
+	assert.Equal(t, "eth0", env["GLOO_SOCKET_IFNAME"].Value)
+	assert.Empty(t, spec.Containers[1].Env)
+}
+
+func TestSetTokenizer(t *testing.T) {
+	newWorkspace := func(tokenizer *v1beta1.TokenizerEndpointSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{Tokenizer: tokenizer},
+		}
+	}
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{Containers: []corev1.Container{{Name: "test-workspace"}}}
+	}
+
+	t.Run("no tokenizer", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetTokenizer(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.Len(t, spec.Containers, 1)
+	})
+
+	t.Run("forwards to vLLM", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetTokenizer(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(&v1beta1.TokenizerEndpointSpec{})}, spec))
+		if assert.Len(t, spec.Containers, 2) {
+			sidecar := spec.Containers[1]
+			assert.Equal(t, consts.TokenizerContainerName, sidecar.Name)
+			assert.Equal(t, []string{"python3", "/workspace/vllm/tokenizer_server.py", "--port=5002", "--upstream-port=5000"}, sidecar.Command)
+			assert.Equal(t, consts.PortTokenizer, sidecar.Ports[0].ContainerPort)
+		}
+	})
+
+	t.Run("bypasses the response cache", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.TokenizerEndpointSpec{Port: 9000})
+		ws.Inference.ResponseCache = &v1beta1.ResponseCacheSpec{}
+		assert.NoError(t, SetTokenizer(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		if assert.Len(t, spec.Containers, 2) {
+			assert.Contains(t, spec.Containers[1].Command, "--upstream-port=5001")
+		}
+	})
+}
+
+func TestSetImagePull(t *testing.T) {
+	newWorkspace := func(imagePull *v1beta1.ImagePullSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{ImagePull: imagePull},
+		}
+	}
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "model-puller", Image: "puller:v1"}},
+			Containers: []corev1.Container{
+				{Name: "test-workspace", Image: GetBaseImageName()},
+				{Name: consts.TokenizerContainerName, Image: GetBaseImageName()},
+				{Name: "log-forwarder", Image: "fluent-bit:v1"},
+			},
+		}
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetImagePull(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.Equal(t, newSpec(), spec)
+	})
+
+	t.Run("pull policy of the inference image", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ImagePullSpec{Policy: corev1.PullAlways})
+		assert.NoError(t, SetImagePull(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, corev1.PullAlways, spec.Containers[0].ImagePullPolicy)
+		assert.Equal(t, corev1.PullAlways, spec.Containers[1].ImagePullPolicy)
+		assert.Empty(t, spec.Containers[2].ImagePullPolicy)
+		assert.Empty(t, spec.InitContainers[0].ImagePullPolicy)
+		assert.Nil(t, spec.RuntimeClassName)
+	})
+
+	t.Run("lazy pull", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.ImagePullSpec{LazyPull: &v1beta1.LazyPullSpec{RuntimeClassName: "runc-stargz"}})
+		assert.NoError(t, SetImagePull(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, ptr.To("runc-stargz"), spec.RuntimeClassName)
+		assert.Empty(t, spec.Containers[0].ImagePullPolicy)
+	})
+}
+
+func TestSetRuntimeEnv(t *testing.T) {
+	ws := &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Inference: &v1beta1.InferenceSpec{RuntimeOverrides: &v1beta1.RuntimeOverridesSpec{
+			Env: []v1beta1.RuntimeEnvVar{
+				{Name: "VLLM_ATTENTION_BACKEND", Value: "FLASHINFER"},
+				{Name: consts.VLLMUseDeepGEMMEnvName, Value: "1"},
+			},
+		}},
+	}
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "test-workspace", Env: []corev1.EnvVar{{Name: consts.VLLMUseDeepGEMMEnvName, Value: "0"}}},
+			{Name: "log-forwarder"},
+		},
+	}
+
+	assert.NoError(t, SetRuntimeEnv(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: consts.VLLMUseDeepGEMMEnvName, Value: "0"},
+		{Name: "VLLM_ATTENTION_BACKEND", Value: "FLASHINFER"},
+	}, spec.Containers[0].Env)
+	assert.Empty(t, spec.Containers[1].Env)
+}
+
+func TestSetAPINormalizer(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-workspace", Ports: []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer}}}},
+		}
+	}
+	newWorkspace := func(norm *v1beta1.APINormalizationSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{APINormalization: norm},
+		}
+	}
+
+	t.Run("no API normalization", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(nil)
+		assert.NoError(t, SetAPINormalizer(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Len(t, spec.Containers, 1)
+		assert.Zero(t, inferenceServerPort(ws))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.APINormalizationSpec{})
+		assert.NoError(t, SetAPINormalizer(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, consts.PortDecodeVLLM, inferenceServerPort(ws))
+		assert.Equal(t, consts.PortDecodeVLLM, spec.Containers[0].Ports[0].ContainerPort)
+		if assert.Len(t, spec.Containers, 2) {
+			sidecar := spec.Containers[1]
+			assert.Equal(t, consts.APINormalizerContainerName, sidecar.Name)
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/api_normalizer.py",
+				"--port=5000", "--upstream-port=5001", "--translate=None", "--max-tokens-field=max_tokens",
+			}, sidecar.Command)
+			assert.Equal(t, consts.PortInferenceServer, sidecar.Ports[0].ContainerPort)
+		}
+	})
+
+	t.Run("translation and default model", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.APINormalizationSpec{
+			Translate:      v1beta1.APITranslationChatToCompletions,
+			MaxTokensField: v1beta1.MaxTokensFieldMaxCompletionTokens,
+			DefaultModel:   "phi-4",
+		})
+		assert.NoError(t, SetAPINormalizer(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		if assert.Len(t, spec.Containers, 2) {
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/api_normalizer.py",
+				"--port=5000", "--upstream-port=5001", "--translate=ChatToCompletions",
+				"--max-tokens-field=max_completion_tokens", "--default-model=phi-4",
+			}, spec.Containers[1].Command)
+		}
+	})
+
+	t.Run("adapter header", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.APINormalizationSpec{AdapterHeader: "X-Adapter"})
+		ws.Inference.Adapters = []v1beta1.AdapterSpec{
+			{Source: &v1beta1.DataSource{Name: "marketing-v2", Image: "registry.example.com/marketing:v2"}},
+			{Source: &v1beta1.DataSource{Name: "support-v1", Image: "registry.example.com/support:v1"}},
+		}
+		assert.NoError(t, SetAPINormalizer(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		if assert.Len(t, spec.Containers, 2) {
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/api_normalizer.py",
+				"--port=5000", "--upstream-port=5001", "--translate=None", "--max-tokens-field=max_tokens",
+				"--adapter-header=x-adapter", "--adapters=marketing-v2,support-v1",
+			}, spec.Containers[1].Command)
+		}
+	})
+}
+
+func TestSetTracing(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-workspace"}, {Name: consts.APINormalizerContainerName}},
+		}
+	}
+	newWorkspace := func(tracing *v1beta1.TracingSpec) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			Inference:  &v1beta1.InferenceSpec{Tracing: tracing},
+		}
+	}
+
+	t.Run("no tracing", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetTracing(&generator.WorkspaceGeneratorContext{Workspace: newWorkspace(nil)}, spec))
+		assert.Empty(t, spec.Containers[0].Env)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.TracingSpec{Endpoint: "http://otel-collector.monitoring:4317"})
+		assert.NoError(t, SetTracing(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "OTEL_SERVICE_NAME", Value: "test-workspace"},
+			{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://otel-collector.monitoring:4317"},
+			{Name: "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", Value: "grpc"},
+			{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+			{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "1"},
+			{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "k8s.namespace.name=default,kaito.sh/workspace=test-workspace"},
+		}, spec.Containers[0].Env)
+		assert.Empty(t, spec.Containers[1].Env)
+	})
+
+	t.Run("all settings", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(&v1beta1.TracingSpec{
+			Endpoint:    "https://collector.example.com:4318/v1/traces",
+			Protocol:    v1beta1.OTLPProtocolHTTPProtobuf,
+			SampleRatio: ptr.To("0.25"),
+			ServiceName: "phi-4-prod",
+		})
+		assert.NoError(t, SetTracing(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		env := map[string]string{}
+		for _, e := range spec.Containers[0].Env {
+			env[e.Name] = e.Value
+		}
+		assert.Equal(t, "phi-4-prod", env["OTEL_SERVICE_NAME"])
+		assert.Equal(t, "http/protobuf", env["OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"])
+		assert.Equal(t, "0.25", env["OTEL_TRACES_SAMPLER_ARG"])
+	})
+}
+
+func TestSetTierRouter(t *testing.T) {
+	newSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "phi-short-abcde", Ports: []corev1.ContainerPort{{ContainerPort: consts.PortInferenceServer}}}},
+		}
+	}
+	newWorkspace := func(annotations map[string]string) *v1beta1.Workspace {
+		return &v1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "phi-short-abcde", Namespace: "default", Annotations: annotations},
+			Inference:  &v1beta1.InferenceSpec{},
+		}
+	}
+
+	t.Run("untiered workspace", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(nil)
+		assert.NoError(t, SetTierRouter(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Len(t, spec.Containers, 1)
+		assert.Zero(t, inferenceServerPort(ws))
+	})
+
+	t.Run("tiered workspace", func(t *testing.T) {
+		spec := newSpec()
+		ws := newWorkspace(map[string]string{
+			v1beta1.AnnotationTierMaxPromptTokens: "4096",
+			v1beta1.AnnotationTierOverflowService: "phi-long",
+		})
+		assert.NoError(t, SetTierRouter(&generator.WorkspaceGeneratorContext{Workspace: ws}, spec))
+		assert.Equal(t, consts.PortDecodeVLLM, inferenceServerPort(ws))
+		assert.Equal(t, consts.PortDecodeVLLM, spec.Containers[0].Ports[0].ContainerPort)
+		if assert.Len(t, spec.Containers, 2) {
+			sidecar := spec.Containers[1]
+			assert.Equal(t, consts.TierRouterContainerName, sidecar.Name)
+			assert.Equal(t, []string{
+				"python3", "/workspace/vllm/tier_router.py",
+				"--port=5000", "--upstream-port=5001", "--max-prompt-tokens=4096",
+				"--overflow-url=http://phi-long.default.svc:5000",
+			}, sidecar.Command)
+			assert.Equal(t, consts.PortInferenceServer, sidecar.Ports[0].ContainerPort)
+		}
+	})
+
+	t.Run("invalid overflow service", func(t *testing.T) {
+		ws := newWorkspace(map[string]string{
+			v1beta1.AnnotationTierMaxPromptTokens: "4096",
+			v1beta1.AnnotationTierOverflowService: "phi-long --debug",
+		})
+		assert.ErrorContains(t, SetTierRouter(&generator.WorkspaceGeneratorContext{Workspace: ws}, newSpec()), v1beta1.AnnotationTierOverflowService)
+	})
+}
+
+func TestGetWorkspaceBaseImageName(t *testing.T) {
+	t.Setenv("PRESET_REGISTRY_NAME", "localhost:5000/kaito")
+	digest := "sha256:" + strings.Repeat("a", 64)
+	wObj := &v1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+
+	assert.Equal(t, GetBaseImageName(), GetWorkspaceBaseImageName(wObj))
+
+	wObj.Annotations[v1beta1.AnnotationRuntimeChannel] = v1beta1.RuntimeChannelPinned + "@" + digest
+	assert.Equal(t, "localhost:5000/kaito/kaito-base@"+digest, GetWorkspaceBaseImageName(wObj))
+}
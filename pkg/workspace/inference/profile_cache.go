@@ -0,0 +1,202 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+)
+
+// maxProfileKeyPrefixLength keeps profile cache keys below the 253 character limit of
+// ConfigMap keys, leaving room for the hash suffix.
+const maxProfileKeyPrefixLength = 200
+
+var invalidProfileKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
+
+// ProfileCacheEntry is a cached max-model-len profiling result. It is stored as JSON
+// in the profile cache ConfigMap.
+type ProfileCacheEntry struct {
+	MaxModelLen     int         `json:"maxModelLen"`
+	Model           string      `json:"model"`
+	InstanceType    string      `json:"instanceType"`
+	Nodes           int32       `json:"nodes"`
+	PerformanceMode string      `json:"performanceMode"`
+	RuntimeVersion  string      `json:"runtimeVersion"`
+	ProfiledAt      metav1.Time `json:"profiledAt"`
+}
+
+// NewProfileCacheEntry returns the cache entry of a max-model-len profiled for wObj.
+func NewProfileCacheEntry(wObj *v1beta1.Workspace, maxModelLen int) ProfileCacheEntry {
+	return ProfileCacheEntry{
+		MaxModelLen:     maxModelLen,
+		Model:           string(wObj.Inference.Preset.Name),
+		InstanceType:    wObj.EffectiveInstanceType(),
+		Nodes:           wObj.Status.TargetNodeCount,
+		PerformanceMode: v1beta1.GetPerformanceMode(wObj),
+		RuntimeVersion:  GetBaseRuntimeVersion(pkgmodel.RuntimeNameVLLM),
+		ProfiledAt:      metav1.Now(),
+	}
+}
+
+// ProfileCacheKey returns the profile cache key of wObj: its model, instance type, node
+// count, performance mode and vLLM version. It returns "" when the cache is disabled or
+// when the workspace sets anything else that changes the memory vLLM has for the KV
+// cache, since a result profiled for another workspace could then not fit.
+func ProfileCacheKey(wObj *v1beta1.Workspace) string {
+	if !featuregates.FeatureGates[consts.FeatureFlagInferenceProfileCache] {
+		return ""
+	}
+	inf := wObj.Inference
+	if inf == nil || inf.Preset == nil || inf.Template != nil ||
+		v1beta1.GetWorkspaceRuntimeName(wObj) != pkgmodel.RuntimeNameVLLM {
+		return ""
+	}
+	if inf.Preset.Image != "" || inf.Config != "" || len(inf.Adapters) > 0 ||
+		(inf.RuntimeOverrides != nil && len(inf.RuntimeOverrides.ExtraArgs) > 0) {
+		return ""
+	}
+	// Without an instance type (BYO nodes) or with a GPU partition the SKU alone does
+	// not describe the GPU memory.
+	if wObj.EffectiveInstanceType() == "" || wObj.Resource.Partition != nil || wObj.Status.TargetNodeCount < 1 {
+		return ""
+	}
+	runtimeVersion := GetBaseRuntimeVersion(pkgmodel.RuntimeNameVLLM)
+	if runtimeVersion == "" {
+		return ""
+	}
+
+	identity := strings.Join([]string{
+		string(inf.Preset.Name),
+		wObj.EffectiveInstanceType(),
+		fmt.Sprintf("%dn", wObj.Status.TargetNodeCount),
+		v1beta1.GetPerformanceMode(wObj),
+		"vllm-" + runtimeVersion,
+	}, ".")
+	// Model names may hold characters that are invalid in ConfigMap keys ("org/model"),
+	// so the readable prefix is sanitized and a hash of the identity keeps keys unique.
+	prefix := invalidProfileKeyChars.ReplaceAllString(identity, "-")
+	if len(prefix) > maxProfileKeyPrefixLength {
+		prefix = prefix[:maxProfileKeyPrefixLength]
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return prefix + "-" + hex.EncodeToString(sum[:4])
+}
+
+// LookupProfile returns the cached profiling result stored under key, or nil when the
+// cache holds none.
+func LookupProfile(ctx context.Context, c client.Client, key string) (*ProfileCacheEntry, error) {
+	namespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: consts.InferenceProfileCacheConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting profile cache: %w", err)
+	}
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := &ProfileCacheEntry{}
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, fmt.Errorf("parsing profile cache entry %q: %w", key, err)
+	}
+	if entry.MaxModelLen <= 0 {
+		return nil, fmt.Errorf("profile cache entry %q has an invalid maxModelLen %d", key, entry.MaxModelLen)
+	}
+	return entry, nil
+}
+
+// RecordProfile stores entry under key, creating the profile cache ConfigMap when it
+// does not exist yet.
+func RecordProfile(ctx context.Context, c client.Client, key string, entry ProfileCacheEntry) error {
+	namespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	err = c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: consts.InferenceProfileCacheConfigMapName}, cm)
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: consts.InferenceProfileCacheConfigMapName},
+			Data:       map[string]string{key: string(data)},
+		}
+		return c.Create(ctx, cm)
+	}
+	if err != nil {
+		return fmt.Errorf("getting profile cache: %w", err)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = string(data)
+	return c.Update(ctx, cm)
+}
+
+// SetCachedProfile passes the cached max-model-len of the workspace to the main
+// inference container, so vLLM starts with it instead of profiling the context length
+// again. A failed cache read only costs the profiling run, so it does not fail the
+// rollout.
+func SetCachedProfile(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	key := ProfileCacheKey(ctx.Workspace)
+	if key == "" {
+		return nil
+	}
+	entry, err := LookupProfile(ctx.Ctx, ctx.KubeClient, key)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read the inference profile cache", "workspace", klog.KObj(ctx.Workspace))
+		return nil
+	}
+	if entry == nil {
+		return nil
+	}
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		if c.Name != ctx.Workspace.Name {
+			continue
+		}
+		// A value set through the runtime overrides wins.
+		if !slices.ContainsFunc(c.Env, func(e corev1.EnvVar) bool { return e.Name == consts.MaxModelLenEnvName }) {
+			c.Env = append(c.Env, corev1.EnvVar{Name: consts.MaxModelLenEnvName, Value: fmt.Sprint(entry.MaxModelLen)})
+		}
+		break
+	}
+	return nil
+}
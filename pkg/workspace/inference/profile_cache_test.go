@@ -0,0 +1,195 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+)
+
+func enableProfileCache(t *testing.T) {
+	t.Helper()
+	for _, gate := range []string{consts.FeatureFlagInferenceProfileCache, consts.FeatureFlagVLLM} {
+		original := featuregates.FeatureGates[gate]
+		featuregates.FeatureGates[gate] = true
+		t.Cleanup(func() { featuregates.FeatureGates[gate] = original })
+	}
+	t.Setenv(consts.DefaultReleaseNamespaceEnvVar, "kaito-system")
+}
+
+func newProfileWorkspace() *v1beta1.Workspace {
+	return &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Resource:   v1beta1.ResourceSpec{InstanceType: "Standard_NC24ads_A100_v4"},
+		Inference: &v1beta1.InferenceSpec{
+			Preset: &v1beta1.PresetSpec{PresetMeta: v1beta1.PresetMeta{Name: "Qwen/Qwen3-8B"}},
+		},
+		Status: v1beta1.WorkspaceStatus{TargetNodeCount: 1},
+	}
+}
+
+func newProfileClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestProfileCacheKey(t *testing.T) {
+	t.Run("gate disabled", func(t *testing.T) {
+		original := featuregates.FeatureGates[consts.FeatureFlagInferenceProfileCache]
+		featuregates.FeatureGates[consts.FeatureFlagInferenceProfileCache] = false
+		defer func() { featuregates.FeatureGates[consts.FeatureFlagInferenceProfileCache] = original }()
+		assert.Empty(t, ProfileCacheKey(newProfileWorkspace()))
+	})
+
+	enableProfileCache(t)
+
+	key := ProfileCacheKey(newProfileWorkspace())
+	assert.True(t, strings.HasPrefix(key, "Qwen-Qwen3-8B.Standard_NC24ads_A100_v4.1n.balanced.vllm-"), key)
+	assert.Regexp(t, `^[-._a-zA-Z0-9]+$`, key)
+	assert.Equal(t, key, ProfileCacheKey(newProfileWorkspace()), "the key is stable")
+
+	differs := map[string]func(*v1beta1.Workspace){
+		"instance type": func(w *v1beta1.Workspace) { w.Resource.InstanceType = "Standard_NC48ads_A100_v4" },
+		"node count":    func(w *v1beta1.Workspace) { w.Status.TargetNodeCount = 2 },
+		"performance mode": func(w *v1beta1.Workspace) {
+			w.Annotations = map[string]string{v1beta1.AnnotationPerformanceMode: "throughput"}
+		},
+		// Sanitizing maps both names to the same prefix; the hash keeps the keys apart.
+		"model": func(w *v1beta1.Workspace) { w.Inference.Preset.Name = "Qwen-Qwen3-8B" },
+	}
+	for name, mutate := range differs {
+		t.Run(name+" changes the key", func(t *testing.T) {
+			w := newProfileWorkspace()
+			mutate(w)
+			other := ProfileCacheKey(w)
+			assert.NotEmpty(t, other)
+			assert.NotEqual(t, key, other)
+		})
+	}
+
+	notCacheable := map[string]func(*v1beta1.Workspace){
+		"custom config":    func(w *v1beta1.Workspace) { w.Inference.Config = "my-config" },
+		"adapters":         func(w *v1beta1.Workspace) { w.Inference.Adapters = []v1beta1.AdapterSpec{{}} },
+		"private image":    func(w *v1beta1.Workspace) { w.Inference.Preset.Image = "myregistry/model:1" },
+		"no instance type": func(w *v1beta1.Workspace) { w.Resource.InstanceType = "" },
+		"no target nodes":  func(w *v1beta1.Workspace) { w.Status.TargetNodeCount = 0 },
+		"extra args": func(w *v1beta1.Workspace) {
+			w.Inference.RuntimeOverrides = &v1beta1.RuntimeOverridesSpec{ExtraArgs: map[string]string{"kv-cache-dtype": "fp8"}}
+		},
+		"transformers runtime": func(w *v1beta1.Workspace) {
+			w.Annotations = map[string]string{v1beta1.AnnotationWorkspaceRuntime: "transformers"}
+		},
+	}
+	for name, mutate := range notCacheable {
+		t.Run(name+" is not cacheable", func(t *testing.T) {
+			w := newProfileWorkspace()
+			mutate(w)
+			assert.Empty(t, ProfileCacheKey(w))
+		})
+	}
+}
+
+func TestRecordAndLookupProfile(t *testing.T) {
+	enableProfileCache(t)
+	ctx := context.Background()
+	c := newProfileClient()
+
+	entry, err := LookupProfile(ctx, c, "key-a")
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "no cache ConfigMap")
+
+	wObj := newProfileWorkspace()
+	assert.NoError(t, RecordProfile(ctx, c, "key-a", NewProfileCacheEntry(wObj, 32768)))
+	assert.NoError(t, RecordProfile(ctx, c, "key-b", NewProfileCacheEntry(wObj, 8192)))
+
+	entry, err = LookupProfile(ctx, c, "key-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 32768, entry.MaxModelLen)
+	assert.Equal(t, "Qwen/Qwen3-8B", entry.Model)
+	assert.Equal(t, "Standard_NC24ads_A100_v4", entry.InstanceType)
+	entry, err = LookupProfile(ctx, c, "key-b")
+	assert.NoError(t, err)
+	assert.Equal(t, 8192, entry.MaxModelLen)
+
+	entry, err = LookupProfile(ctx, c, "key-c")
+	assert.NoError(t, err)
+	assert.Nil(t, entry, "missing key")
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "kaito-system", Name: consts.InferenceProfileCacheConfigMapName}, cm))
+	cm.Data["key-c"] = `{"maxModelLen": 0}`
+	assert.NoError(t, c.Update(ctx, cm))
+	_, err = LookupProfile(ctx, c, "key-c")
+	assert.Error(t, err, "invalid entry")
+}
+
+func TestSetCachedProfile(t *testing.T) {
+	enableProfileCache(t)
+	wObj := newProfileWorkspace()
+	key := ProfileCacheKey(wObj)
+	data, _ := json.Marshal(NewProfileCacheEntry(wObj, 32768))
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kaito-system", Name: consts.InferenceProfileCacheConfigMapName},
+		Data:       map[string]string{key: string(data)},
+	}
+	newSpec := func(env ...corev1.EnvVar) *corev1.PodSpec {
+		return &corev1.PodSpec{Containers: []corev1.Container{{Name: "sidecar"}, {Name: "test-workspace", Env: env}}}
+	}
+	newContext := func(w *v1beta1.Workspace, objs ...client.Object) *generator.WorkspaceGeneratorContext {
+		return &generator.WorkspaceGeneratorContext{Ctx: context.Background(), Workspace: w, KubeClient: newProfileClient(objs...)}
+	}
+
+	t.Run("cache hit", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetCachedProfile(newContext(wObj, cm), spec))
+		assert.Empty(t, spec.Containers[0].Env)
+		assert.Equal(t, []corev1.EnvVar{{Name: consts.MaxModelLenEnvName, Value: "32768"}}, spec.Containers[1].Env)
+	})
+
+	t.Run("cache miss", func(t *testing.T) {
+		spec := newSpec()
+		assert.NoError(t, SetCachedProfile(newContext(wObj), spec))
+		assert.Equal(t, newSpec(), spec)
+	})
+
+	t.Run("not cacheable", func(t *testing.T) {
+		w := newProfileWorkspace()
+		w.Inference.Config = "my-config"
+		spec := newSpec()
+		assert.NoError(t, SetCachedProfile(newContext(w, cm), spec))
+		assert.Equal(t, newSpec(), spec)
+	})
+
+	t.Run("runtime override wins", func(t *testing.T) {
+		override := corev1.EnvVar{Name: consts.MaxModelLenEnvName, Value: "4096"}
+		spec := newSpec(override)
+		assert.NoError(t, SetCachedProfile(newContext(wObj, cm), spec))
+		assert.Equal(t, []corev1.EnvVar{override}, spec.Containers[1].Env)
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/nodeprovision"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 )
@@ -32,6 +33,9 @@ func CreateTemplateInference(ctx context.Context, workspaceObj *kaitov1beta1.Wor
 	if err := ApplyProvisionerNodeSelector(ctx, provisioner, workspaceObj, &ssObj.Spec.Template.Spec); err != nil {
 		return nil, err
 	}
+	if err := manifests.SetPlacement(&generator.WorkspaceGeneratorContext{Ctx: ctx, Workspace: workspaceObj}, &ssObj.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
 	err := resources.CreateResource(ctx, client.Object(ssObj), kubeClient)
 	if client.IgnoreAlreadyExists(err) != nil {
 		return nil, err
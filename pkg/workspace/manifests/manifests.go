@@ -17,7 +17,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"net"
+	"os"
 	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
@@ -26,6 +34,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
@@ -33,10 +42,12 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/generator"
+	"github.com/kaito-project/kaito/pkg/workloadidentity"
 	"github.com/kaito-project/kaito/pkg/workspace/image"
 )
 
@@ -46,10 +57,11 @@ func GenerateHeadlessServiceManifest(workspaceObj *kaitov1beta1.Workspace) *core
 		kaitov1beta1.LabelWorkspaceName: workspaceObj.Name,
 	}
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
 			Namespace: workspaceObj.Namespace,
+			Labels:    map[string]string{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(workspaceObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
 			},
@@ -61,8 +73,13 @@ func GenerateHeadlessServiceManifest(workspaceObj *kaitov1beta1.Workspace) *core
 			PublishNotReadyAddresses: true,
 		},
 	}
+	setPropagatedServiceMetadata(workspaceObj, svc)
+	return svc
 }
 
+// TokenizerServicePortName is the name of the Service port of the tokenizer endpoint.
+const TokenizerServicePortName = "tokenizer"
+
 func GenerateServiceManifest(workspaceObj *kaitov1beta1.Workspace, serviceType corev1.ServiceType) *corev1.Service {
 	selector := map[string]string{
 		kaitov1beta1.LabelWorkspaceName: workspaceObj.Name,
@@ -76,10 +93,11 @@ func GenerateServiceManifest(workspaceObj *kaitov1beta1.Workspace, serviceType c
 	// listens directly on 5000.
 	httpTargetPort := consts.PortInferenceServer
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workspaceObj.Name,
 			Namespace: workspaceObj.Namespace,
+			Labels:    map[string]string{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(workspaceObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
 			},
@@ -113,6 +131,104 @@ func GenerateServiceManifest(workspaceObj *kaitov1beta1.Workspace, serviceType c
 			PublishNotReadyAddresses: true,
 		},
 	}
+	if workspaceObj.Inference != nil && workspaceObj.Inference.Tokenizer != nil {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:       TokenizerServicePortName,
+			Protocol:   corev1.ProtocolTCP,
+			Port:       workspaceObj.Inference.Tokenizer.GetPort(),
+			TargetPort: intstr.FromInt32(consts.PortTokenizer),
+		})
+	}
+	if workspaceObj.Inference != nil && workspaceObj.Inference.Service != nil {
+		opts := workspaceObj.Inference.Service
+		if opts.Type != "" {
+			svc.Spec.Type = opts.Type
+		}
+		svc.Spec.IPFamilyPolicy = opts.IPFamilyPolicy
+		svc.Spec.IPFamilies = opts.IPFamilies
+		svc.Spec.InternalTrafficPolicy = opts.InternalTrafficPolicy
+		svc.Spec.TrafficDistribution = opts.TrafficDistribution
+		if len(opts.Annotations) > 0 {
+			svc.Annotations = maps.Clone(opts.Annotations)
+		}
+		if dns := opts.DNS; dns != nil && dns.Hostname != "" {
+			if svc.Annotations == nil {
+				svc.Annotations = map[string]string{}
+			}
+			svc.Annotations[kaitov1beta1.AnnotationExternalDNSHostname] = dns.Hostname
+			if dns.TTL != nil {
+				svc.Annotations[kaitov1beta1.AnnotationExternalDNSTTL] = strconv.Itoa(int(*dns.TTL))
+			}
+		}
+		managed := slices.Collect(maps.Keys(opts.Annotations))
+		if timeouts := opts.Timeouts; timeouts != nil && timeouts.Idle != nil && svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			if key, value := loadBalancerIdleTimeoutAnnotation(os.Getenv("CLOUD_PROVIDER"), timeouts.Idle.Duration); key != "" {
+				if svc.Annotations == nil {
+					svc.Annotations = map[string]string{}
+				}
+				svc.Annotations[key] = value
+				managed = append(managed, key)
+			}
+		}
+		if len(managed) > 0 {
+			svc.Annotations[kaitov1beta1.AnnotationServiceAnnotations] = strings.Join(slices.Sorted(slices.Values(managed)), ",")
+		}
+	}
+	setPropagatedServiceMetadata(workspaceObj, svc)
+	return svc
+}
+
+// setPropagatedMetadata adds labels and annotations propagated from the workspace to meta,
+// and records their keys so that keys which are no longer propagated can be removed from
+// an existing object. Keys already set on meta win and are not recorded.
+func setPropagatedMetadata(meta *metav1.ObjectMeta, labels, annotations map[string]string) {
+	labels, annotations = maps.Clone(labels), maps.Clone(annotations)
+	maps.DeleteFunc(labels, func(key, _ string) bool { _, exists := meta.Labels[key]; return exists })
+	maps.DeleteFunc(annotations, func(key, _ string) bool { _, exists := meta.Annotations[key]; return exists })
+	// The maps are copied before they are changed, since the labels of a pod template
+	// are often shared with the selector of its workload.
+	records := map[string]string{}
+	if len(labels) > 0 {
+		records[kaitov1beta1.AnnotationPropagatedLabels] = strings.Join(slices.Sorted(maps.Keys(labels)), ",")
+		meta.Labels = kaitov1beta1.MergePropagated(maps.Clone(meta.Labels), labels)
+	}
+	if len(annotations) > 0 {
+		records[kaitov1beta1.AnnotationPropagatedAnnotations] = strings.Join(slices.Sorted(maps.Keys(annotations)), ",")
+	}
+	if len(records) > 0 {
+		meta.Annotations = kaitov1beta1.MergePropagated(kaitov1beta1.MergePropagated(maps.Clone(meta.Annotations), annotations), records)
+	}
+}
+
+// setPropagatedServiceMetadata adds the labels and annotations the workspace propagates to
+// its Services to svc.
+func setPropagatedServiceMetadata(wObj *kaitov1beta1.Workspace, svc *corev1.Service) {
+	setPropagatedMetadata(&svc.ObjectMeta,
+		wObj.PropagatedLabels(kaitov1beta1.MetadataPropagationTargetService),
+		wObj.PropagatedAnnotations(kaitov1beta1.MetadataPropagationTargetService))
+}
+
+// setPropagatedPodMetadata adds the labels and annotations the workspace propagates to its
+// pods to a pod template.
+func setPropagatedPodMetadata(wObj *kaitov1beta1.Workspace, template *corev1.PodTemplateSpec) {
+	setPropagatedMetadata(&template.ObjectMeta,
+		wObj.PropagatedLabels(kaitov1beta1.MetadataPropagationTargetPod),
+		wObj.PropagatedAnnotations(kaitov1beta1.MetadataPropagationTargetPod))
+}
+
+// loadBalancerIdleTimeoutAnnotation returns the annotation that sets the idle timeout of the
+// load balancer of cloud, or an empty key if the cloud has none. Azure takes whole minutes
+// between 4 and 100, AWS whole seconds.
+func loadBalancerIdleTimeoutAnnotation(cloud string, idle time.Duration) (string, string) {
+	switch cloud {
+	case consts.AzureCloudName:
+		minutes := min(max(int64(math.Ceil(idle.Minutes())), 4), 100)
+		return kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout, strconv.FormatInt(minutes, 10)
+	case consts.AWSCloudName:
+		return kaitov1beta1.AnnotationAWSLoadBalancerIdleTimeout, strconv.FormatInt(int64(math.Ceil(idle.Seconds())), 10)
+	default:
+		return "", ""
+	}
 }
 
 func GenerateStatefulSetManifest(revisionNum string, replicas int) func(*generator.WorkspaceGeneratorContext, *appsv1.StatefulSet) error {
@@ -126,13 +242,16 @@ func GenerateStatefulSetManifest(revisionNum string, replicas int) func(*generat
 				klog.Infof("Adding label %s=%s to statefulset selector", consts.WorkspaceCreatedByInferenceSetLabel, createdBy)
 				selector[consts.WorkspaceCreatedByInferenceSetLabel] = createdBy
 			}
-			// Propagate MRI parent and inference-role labels to pod templates for InferencePool endpoint selection.
+			// Propagate MRI parent, inference-role and inference-tier labels to pod templates for InferencePool endpoint selection.
 			if parent, exists := ctx.Workspace.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent]; exists {
 				selector[kaitov1alpha1.LabelMultiRoleInferenceParent] = parent
 			}
 			if role, exists := ctx.Workspace.Labels[kaitov1alpha1.LabelInferenceRole]; exists {
 				selector[kaitov1alpha1.LabelInferenceRole] = role
 			}
+			if tier, exists := ctx.Workspace.Labels[kaitov1alpha1.LabelInferenceTier]; exists {
+				selector[kaitov1alpha1.LabelInferenceTier] = tier
+			}
 		}
 		labelselector := &metav1.LabelSelector{
 			MatchLabels: selector,
@@ -162,6 +281,7 @@ func GenerateStatefulSetManifest(revisionNum string, replicas int) func(*generat
 				},
 			},
 		}
+		setPropagatedPodMetadata(ctx.Workspace, &ss.Spec.Template)
 
 		ss.Spec.ServiceName = fmt.Sprintf("%s-headless", ctx.Workspace.Name)
 		return nil
@@ -207,6 +327,7 @@ func GenerateTuningJobManifest(revisionNum string) func(*generator.WorkspaceGene
 				},
 			},
 		}
+		setPropagatedPodMetadata(ctx.Workspace, &j.Spec.Template)
 		return nil
 	}
 }
@@ -221,6 +342,278 @@ func SetJobPodSpec(podSpec *corev1.PodSpec) func(*generator.WorkspaceGeneratorCo
 	}
 }
 
+const (
+	proxyCAVolumeName = "proxy-ca-bundle"
+	proxyCAMountPath  = "/etc/kaito/proxy-ca"
+	proxyCAKey        = "ca.crt"
+)
+
+// defaultNoProxy lists the destinations that always bypass the proxy: the loopback
+// addresses used by sidecars and probes, and in-cluster services.
+var defaultNoProxy = []string{"localhost", "127.0.0.1", "::1", ".svc", ".svc.cluster.local", ".cluster.local"}
+
+// ClusterCIDRs are the pod and service CIDRs of the cluster, set once at startup from
+// controller flags. They bypass the proxy along with defaultNoProxy, since pods and
+// services are also reached by IP, e.g. the leader of a multi-node inference group.
+var ClusterCIDRs []string
+
+// ParseCIDRs parses a comma separated list of CIDRs such as "10.244.0.0/16,fd00:10::/108".
+func ParseCIDRs(value string) ([]string, error) {
+	var cidrs []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+		cidrs = append(cidrs, entry)
+	}
+	return cidrs, nil
+}
+
+// SetProxy passes the proxy settings to every container and init container of the pod,
+// both in upper and lower case since download tools disagree on which one they read.
+// Variables already set on a container are left alone. If a CA bundle secret is given,
+// it is mounted and used as the trust store.
+func SetProxy(proxy *kaitov1beta1.ProxySpec) func(*generator.WorkspaceGeneratorContext, *corev1.PodSpec) error {
+	return func(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+		if proxy == nil {
+			return nil
+		}
+		var env []corev1.EnvVar
+		for name, value := range map[string]string{"HTTP_PROXY": proxy.HTTPProxy, "HTTPS_PROXY": proxy.HTTPSProxy} {
+			if value != "" {
+				env = append(env, corev1.EnvVar{Name: name, Value: value}, corev1.EnvVar{Name: strings.ToLower(name), Value: value})
+			}
+		}
+		noProxy := slices.Concat(defaultNoProxy, ClusterCIDRs)
+		for _, entry := range strings.Split(proxy.NoProxy, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" && !slices.Contains(noProxy, entry) {
+				noProxy = append(noProxy, entry)
+			}
+		}
+		env = append(env,
+			corev1.EnvVar{Name: "NO_PROXY", Value: strings.Join(noProxy, ",")},
+			corev1.EnvVar{Name: "no_proxy", Value: strings.Join(noProxy, ",")},
+		)
+
+		var mounts []corev1.VolumeMount
+		if proxy.CABundleSecret != "" {
+			caFile := path.Join(proxyCAMountPath, proxyCAKey)
+			for _, name := range []string{"SSL_CERT_FILE", "REQUESTS_CA_BUNDLE", "CURL_CA_BUNDLE"} {
+				env = append(env, corev1.EnvVar{Name: name, Value: caFile})
+			}
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name: proxyCAVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: proxy.CABundleSecret,
+						Items:      []corev1.KeyToPath{{Key: proxyCAKey, Path: proxyCAKey}},
+					},
+				},
+			})
+			mounts = append(mounts, corev1.VolumeMount{Name: proxyCAVolumeName, MountPath: proxyCAMountPath, ReadOnly: true})
+		}
+		// Sort for a stable pod template, so the workload is not rolled on every reconcile.
+		slices.SortStableFunc(env, func(a, b corev1.EnvVar) int { return strings.Compare(a.Name, b.Name) })
+
+		apply := func(c *corev1.Container) {
+			for _, e := range env {
+				if !slices.ContainsFunc(c.Env, func(existing corev1.EnvVar) bool { return existing.Name == e.Name }) {
+					c.Env = append(c.Env, e)
+				}
+			}
+			c.VolumeMounts = append(c.VolumeMounts, mounts...)
+		}
+		for i := range spec.InitContainers {
+			apply(&spec.InitContainers[i])
+		}
+		for i := range spec.Containers {
+			apply(&spec.Containers[i])
+		}
+		return nil
+	}
+}
+
+// SetServiceAccount runs the pod as the ServiceAccount of the workspace unless an earlier
+// modifier, such as model streaming, already picked one.
+func SetServiceAccount(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if spec.ServiceAccountName == "" {
+		spec.ServiceAccountName = kaitov1beta1.GetServiceAccountName(ctx.Workspace)
+	}
+	return nil
+}
+
+// SetWorkloadIdentity runs the pod as the ServiceAccount generated for the workspace identity
+// and mounts a projected token of it into every container and init container, together with
+// the environment the Azure and AWS SDKs read to exchange it for cloud credentials.
+func SetWorkloadIdentity(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	identity := ctx.Workspace.Identity
+	if identity == nil {
+		return nil
+	}
+	saName := kaitov1beta1.GetServiceAccountName(ctx.Workspace)
+	if spec.ServiceAccountName != "" && spec.ServiceAccountName != saName {
+		return fmt.Errorf("workload identity needs ServiceAccount %s, but the pod already runs as %s", saName, spec.ServiceAccountName)
+	}
+	spec.ServiceAccountName = saName
+
+	volume, mount := workloadidentity.TokenVolume(identity)
+	spec.Volumes = append(spec.Volumes, volume)
+	env := workloadidentity.Env(identity)
+	apply := func(c *corev1.Container) {
+		for _, e := range env {
+			if !slices.ContainsFunc(c.Env, func(existing corev1.EnvVar) bool { return existing.Name == e.Name }) {
+				c.Env = append(c.Env, e)
+			}
+		}
+		c.VolumeMounts = append(c.VolumeMounts, mount)
+	}
+	for i := range spec.InitContainers {
+		apply(&spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		apply(&spec.Containers[i])
+	}
+	return nil
+}
+
+// AttestationEndpointEnvName passes the attestation service URL to the attestation init container.
+const AttestationEndpointEnvName = "KAITO_ATTESTATION_ENDPOINT"
+
+// SetConfidentialCompute pins the pod to confidential compute nodes and runs it with the
+// configured RuntimeClass. If attestation is configured, an init container requesting the
+// same GPUs as the main container attests the TEE before any other container starts.
+func SetConfidentialCompute(cc *kaitov1beta1.ConfidentialComputeSpec) func(*generator.WorkspaceGeneratorContext, *corev1.PodSpec) error {
+	return func(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+		if cc == nil {
+			return nil
+		}
+		if spec.Affinity == nil {
+			spec.Affinity = &corev1.Affinity{}
+		}
+		if spec.Affinity.NodeAffinity == nil {
+			spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		nodeSel := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if nodeSel == nil || len(nodeSel.NodeSelectorTerms) == 0 {
+			nodeSel = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+			spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nodeSel
+		}
+		// Node selector terms are ORed, so the requirement must be added to each of them.
+		for i := range nodeSel.NodeSelectorTerms {
+			nodeSel.NodeSelectorTerms[i].MatchExpressions = append(nodeSel.NodeSelectorTerms[i].MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      kaitov1beta1.LabelConfidentialCompute,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"true"},
+			})
+		}
+		if cc.RuntimeClassName != nil {
+			spec.RuntimeClassName = ptr.To(*cc.RuntimeClassName)
+		}
+
+		if cc.Attestation == nil || len(spec.Containers) == 0 {
+			return nil
+		}
+		attestation := corev1.Container{
+			Name:  "attestation",
+			Image: cc.Attestation.Image,
+			Env:   []corev1.EnvVar{{Name: AttestationEndpointEnvName, Value: cc.Attestation.Endpoint}},
+		}
+		// Init containers run one at a time, so sharing the GPUs of the main container does not
+		// raise the pod's request, and lets the attestation client collect GPU evidence.
+		mainContainer := spec.Containers[0]
+		if gpus, ok := mainContainer.Resources.Limits[corev1.ResourceName(consts.NvidiaGPU)]; ok {
+			attestation.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceName(consts.NvidiaGPU): gpus},
+				Limits:   corev1.ResourceList{corev1.ResourceName(consts.NvidiaGPU): gpus},
+			}
+		}
+		spec.InitContainers = append([]corev1.Container{attestation}, spec.InitContainers...)
+		return nil
+	}
+}
+
+// podTemplateGenerationLabel is set by the DaemonSet controller on its pods only, so the
+// Dedicated anti-affinity can exempt node agents such as the GPU device plugin.
+const podTemplateGenerationLabel = "pod-template-generation"
+
+// SetPlacement adds the pod anti-affinity of the placement policy of the workspace. Dedicated
+// keeps pods of other workloads off the nodes of the workspace, in any namespace; since the
+// scheduler also enforces the required anti-affinity of running pods against incoming pods,
+// it works in both directions. SpreadAcrossHosts keeps the pods of the workspace on distinct
+// nodes.
+func SetPlacement(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	placement := ctx.Workspace.Resource.Placement
+	if placement == nil {
+		return nil
+	}
+	// The replicas of an InferenceSet are one workload, so they share nodes and spread together.
+	ownerKey, owner := kaitov1beta1.LabelWorkspaceName, ctx.Workspace.Name
+	if createdBy, ok := ctx.Workspace.Labels[consts.WorkspaceCreatedByInferenceSetLabel]; ok {
+		ownerKey, owner = consts.WorkspaceCreatedByInferenceSetLabel, createdBy
+	}
+	var terms []corev1.PodAffinityTerm
+	if placement.Policy == kaitov1beta1.PlacementPolicyDedicated {
+		exempt := []metav1.LabelSelectorRequirement{{
+			Key:      podTemplateGenerationLabel,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		}}
+		// The requirements of a selector are ANDed, so a pod carrying any of the allowed labels
+		// does not match it.
+		for _, key := range slices.Sorted(maps.Keys(placement.AllowColocationWith)) {
+			exempt = append(exempt, metav1.LabelSelectorRequirement{
+				Key:      key,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{placement.AllowColocationWith[key]},
+			})
+		}
+		// Pod affinity terms cannot match on label and namespace together, so pods of other
+		// namespaces and pods of other workloads of the same namespace need separate terms.
+		terms = append(terms,
+			corev1.PodAffinityTerm{
+				TopologyKey:   corev1.LabelHostname,
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: exempt},
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{ctx.Workspace.Namespace},
+				}}},
+			},
+			corev1.PodAffinityTerm{
+				TopologyKey: corev1.LabelHostname,
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: append([]metav1.LabelSelectorRequirement{{
+					Key:      ownerKey,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{owner},
+				}}, exempt...)},
+				Namespaces: []string{ctx.Workspace.Namespace},
+			},
+		)
+	}
+	if placement.SpreadAcrossHosts {
+		terms = append(terms, corev1.PodAffinityTerm{
+			TopologyKey:   corev1.LabelHostname,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{ownerKey: owner}},
+			Namespaces:    []string{ctx.Workspace.Namespace},
+		})
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.PodAntiAffinity == nil {
+		spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	anti := spec.Affinity.PodAntiAffinity
+	anti.RequiredDuringSchedulingIgnoredDuringExecution = append(anti.RequiredDuringSchedulingIgnoredDuringExecution, terms...)
+	return nil
+}
+
 func GeneratePullerContainers(wObj *kaitov1beta1.Workspace, adapters []kaitov1beta1.AdapterSpec, volumeMounts []corev1.VolumeMount) ([]corev1.Container, []corev1.EnvVar, []corev1.Volume) {
 	size := len(adapters)
 
@@ -257,6 +650,89 @@ func GeneratePullerContainers(wObj *kaitov1beta1.Workspace, adapters []kaitov1be
 	return initContainers, envVars, volumes
 }
 
+const (
+	// LogForwarderContainerName is the name of the fluent-bit sidecar container.
+	LogForwarderContainerName = "log-forwarder"
+	// LogForwarderLogsVolume is the emptyDir shared by the inference container, which
+	// writes its log file there, and the fluent-bit sidecar, which tails it.
+	LogForwarderLogsVolume = "log-forwarder-logs"
+	// LogForwarderLogDir is where LogForwarderLogsVolume is mounted in both containers.
+	LogForwarderLogDir = "/var/log/kaito"
+
+	logForwarderOutputVolume = "log-forwarder-output"
+	logForwarderOutputPath   = "/fluent-bit/etc/output"
+	logForwarderOutputKey    = "output.conf"
+)
+
+// GenerateLogForwarderContainer returns a fluent-bit sidecar that tails the log files the
+// container named containerName writes to LogForwarderLogDir and ships them to the outputs
+// defined in the forwarder's destination Secret, along with the volumes the sidecar requires.
+// The logs are shared through an emptyDir, so the sidecar needs no access to the node.
+func GenerateLogForwarderContainer(forwarder *kaitov1beta1.LogForwarderSpec, containerName string) (corev1.Container, []corev1.Volume) {
+	image := consts.LogForwarderImage
+	if forwarder.Image != "" {
+		image = forwarder.Image
+	}
+
+	container := corev1.Container{
+		Name:  LogForwarderContainerName,
+		Image: image,
+		Args: []string{
+			"-i", "tail",
+			"-p", "path=" + path.Join(LogForwarderLogDir, "*.log"),
+			"-p", "tag=kaito." + containerName,
+			"-c", path.Join(logForwarderOutputPath, logForwarderOutputKey),
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      LogForwarderLogsVolume,
+				MountPath: LogForwarderLogDir,
+				ReadOnly:  true,
+			},
+			{
+				Name:      logForwarderOutputVolume,
+				MountPath: logForwarderOutputPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: LogForwarderLogsVolume,
+			VolumeSource: corev1.VolumeSource{
+				// The inference server rotates its log file at 32Mi and keeps one backup.
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr.To(resource.MustParse("128Mi"))},
+			},
+		},
+		{
+			Name: logForwarderOutputVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: forwarder.DestinationSecret,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  logForwarderOutputKey,
+							Path: logForwarderOutputKey,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return container, volumes
+}
+
 func GenerateManifestWithPodTemplate(workspaceObj *kaitov1beta1.Workspace, tolerations []corev1.Toleration) *appsv1.StatefulSet {
 	selectorLabels := kaitov1beta1.SanitizedMatchLabels(workspaceObj.Resource.LabelSelector)
 	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(selectorLabels))
@@ -287,7 +763,7 @@ func GenerateManifestWithPodTemplate(workspaceObj *kaitov1beta1.Workspace, toler
 			templateCopy.ObjectMeta.Labels[consts.WorkspaceCreatedByInferenceSetLabel] = createdBy
 			labelselector.MatchLabels[consts.WorkspaceCreatedByInferenceSetLabel] = createdBy
 		}
-		// Propagate MRI parent and inference-role labels to pod templates for InferencePool endpoint selection.
+		// Propagate MRI parent, inference-role and inference-tier labels to pod templates for InferencePool endpoint selection.
 		if parent, exists := workspaceObj.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent]; exists {
 			templateCopy.ObjectMeta.Labels[kaitov1alpha1.LabelMultiRoleInferenceParent] = parent
 			labelselector.MatchLabels[kaitov1alpha1.LabelMultiRoleInferenceParent] = parent
@@ -296,8 +772,14 @@ func GenerateManifestWithPodTemplate(workspaceObj *kaitov1beta1.Workspace, toler
 			templateCopy.ObjectMeta.Labels[kaitov1alpha1.LabelInferenceRole] = role
 			labelselector.MatchLabels[kaitov1alpha1.LabelInferenceRole] = role
 		}
+		if tier, exists := workspaceObj.Labels[kaitov1alpha1.LabelInferenceTier]; exists {
+			templateCopy.ObjectMeta.Labels[kaitov1alpha1.LabelInferenceTier] = tier
+			labelselector.MatchLabels[kaitov1alpha1.LabelInferenceTier] = tier
+		}
 	}
 
+	setPropagatedPodMetadata(workspaceObj, templateCopy)
+
 	// Overwrite affinity. Only set node affinity when there are user-defined
 	// node requirements; an empty MatchExpressions list is rejected by the
 	// Kubernetes API server.
@@ -340,26 +822,28 @@ func GenerateManifestWithPodTemplate(workspaceObj *kaitov1beta1.Workspace, toler
 	}
 }
 
+// GetModelImageName returns the image of the model weights.
 func GetModelImageName(presetObj *pkgmodel.PresetParam) string {
 	return utils.GetPresetImageName(presetObj.Registry, presetObj.Name, presetObj.Tag)
 }
 
-// GenerateModelPullerContainer creates an init container that pulls model images using ORAS
+// GenerateModelPullerContainer creates an init container that pulls the model weights image
+// into the weights volume using ORAS. It tries the registry mirrors before the registry of
+// the preset and retries them with backoff.
 func GenerateModelPullerContainer(ctx context.Context, workspaceObj *kaitov1beta1.Workspace, presetObj *pkgmodel.PresetParam) []corev1.Container {
 	if presetObj.DownloadAtRuntime {
 		// If the preset is set to download at runtime, we don't need to pull the model weights.
 		return nil
 	}
 
+	args := []string{modelPullerScript, "model-weights-downloader", utils.DefaultWeightsVolumePath}
 	puller := corev1.Container{
-		Name:  "model-weights-downloader",
-		Image: utils.DefaultORASToolImage,
-		Command: []string{
-			"oras",
-			"pull",
-			GetModelImageName(presetObj),
-			"-o",
-			utils.DefaultWeightsVolumePath,
+		Name:    "model-weights-downloader",
+		Image:   utils.DefaultORASToolImage,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    append(args, modelPullerSources(imageverify.Pin(GetModelImageName(presetObj)))...),
+		Env: []corev1.EnvVar{
+			{Name: ModelPullerAttemptsEnvName, Value: strconv.Itoa(ModelPullerDefaults.Attempts)},
 		},
 		VolumeMounts: []corev1.VolumeMount{
 			{
@@ -378,6 +862,7 @@ func GenerateInferencePoolOCIRepository(inferenceSetObj *kaitov1beta1.InferenceS
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      utils.InferencePoolName(inferenceSetObj.Name),
 			Namespace: inferenceSetObj.Namespace,
+			Labels:    map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: inferenceSetObj.Name},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(inferenceSetObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
 			},
@@ -432,6 +917,9 @@ func GenerateInferencePoolHelmRelease(inferenceSetObj *kaitov1beta1.InferenceSet
 			},
 		},
 	}
+	if tc := inferenceSetObj.Spec.TrafficClasses; tc != nil {
+		helmValues["inferenceExtension"].(map[string]any)["env"] = trafficClassesEPPEnv(tc)
+	}
 	rawHelmValues, err := json.Marshal(helmValues)
 	if err != nil {
 		return nil, err
@@ -441,6 +929,7 @@ func GenerateInferencePoolHelmRelease(inferenceSetObj *kaitov1beta1.InferenceSet
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      utils.InferencePoolName(inferenceSetObj.Name),
 			Namespace: inferenceSetObj.Namespace,
+			Labels:    map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: inferenceSetObj.Name},
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(inferenceSetObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
 			},
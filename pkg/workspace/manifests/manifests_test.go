@@ -17,12 +17,17 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
@@ -30,6 +35,7 @@ import (
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -324,3 +330,426 @@ func TestGeneratePullerContainers(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateLogForwarderContainer(t *testing.T) {
+	t.Run("default image", func(t *testing.T) {
+		container, volumes := GenerateLogForwarderContainer(&kaitov1beta1.LogForwarderSpec{DestinationSecret: "log-dest"}, "my-ws")
+
+		assert.Equal(t, LogForwarderContainerName, container.Name)
+		assert.Equal(t, consts.LogForwarderImage, container.Image)
+		assert.Contains(t, container.Args, "path=/var/log/kaito/*.log")
+		assert.Contains(t, container.Args, "tag=kaito.my-ws")
+		assert.Contains(t, container.Args, "/fluent-bit/etc/output/output.conf")
+		for _, m := range container.VolumeMounts {
+			assert.True(t, m.ReadOnly, "volume mount %s should be read-only", m.Name)
+		}
+
+		if assert.Len(t, volumes, 2) {
+			assert.Nil(t, volumes[0].HostPath, "the sidecar must not mount node paths")
+			assert.NotNil(t, volumes[0].EmptyDir)
+			if assert.NotNil(t, volumes[1].Secret) {
+				assert.Equal(t, "log-dest", volumes[1].Secret.SecretName)
+				assert.Equal(t, "output.conf", volumes[1].Secret.Items[0].Key)
+			}
+		}
+	})
+
+	t.Run("image override", func(t *testing.T) {
+		container, _ := GenerateLogForwarderContainer(&kaitov1beta1.LogForwarderSpec{
+			DestinationSecret: "log-dest",
+			Image:             "myregistry.io/fluent-bit:3.0.0",
+		}, "my-ws")
+		assert.Equal(t, "myregistry.io/fluent-bit:3.0.0", container.Image)
+	})
+}
+
+func TestGenerateServiceManifestOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		svc := GenerateServiceManifest(test.MockWorkspaceDistributedModel, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, corev1.ServiceTypeClusterIP, svc.Spec.Type)
+		assert.Nil(t, svc.Spec.IPFamilyPolicy)
+		assert.Empty(t, svc.Annotations)
+	})
+
+	t.Run("service options override the default type", func(t *testing.T) {
+		ws := test.MockWorkspaceDistributedModel.DeepCopy()
+		policy := corev1.IPFamilyPolicyRequireDualStack
+		ws.Inference.Service = &kaitov1beta1.EndpointServiceSpec{
+			Type:           corev1.ServiceTypeLoadBalancer,
+			IPFamilyPolicy: &policy,
+			IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			Annotations:    map[string]string{"service.beta.kubernetes.io/azure-load-balancer-internal": "true"},
+		}
+
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+		assert.Equal(t, &policy, svc.Spec.IPFamilyPolicy)
+		assert.Equal(t, []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, svc.Spec.IPFamilies)
+		assert.Equal(t, "true", svc.Annotations["service.beta.kubernetes.io/azure-load-balancer-internal"])
+	})
+
+	t.Run("traffic policies", func(t *testing.T) {
+		ws := test.MockWorkspaceDistributedModel.DeepCopy()
+		ws.Inference.Service = &kaitov1beta1.EndpointServiceSpec{
+			InternalTrafficPolicy: ptr.To(corev1.ServiceInternalTrafficPolicyLocal),
+			TrafficDistribution:   ptr.To(corev1.ServiceTrafficDistributionPreferSameZone),
+		}
+
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, ptr.To(corev1.ServiceInternalTrafficPolicyLocal), svc.Spec.InternalTrafficPolicy)
+		assert.Equal(t, ptr.To(corev1.ServiceTrafficDistributionPreferSameZone), svc.Spec.TrafficDistribution)
+	})
+
+	t.Run("dns hostname sets the ExternalDNS annotations", func(t *testing.T) {
+		ws := test.MockWorkspaceDistributedModel.DeepCopy()
+		ws.Inference.Service = &kaitov1beta1.EndpointServiceSpec{
+			DNS: &kaitov1beta1.ServiceDNSSpec{Hostname: "phi-4.models.example.com", TTL: ptr.To(int32(60))},
+		}
+
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, "phi-4.models.example.com", svc.Annotations[kaitov1beta1.AnnotationExternalDNSHostname])
+		assert.Equal(t, "60", svc.Annotations[kaitov1beta1.AnnotationExternalDNSTTL])
+
+		ws.Inference.Service.DNS = &kaitov1beta1.ServiceDNSSpec{Export: true}
+		svc = GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Empty(t, svc.Annotations)
+	})
+
+	t.Run("tokenizer port", func(t *testing.T) {
+		ws := test.MockWorkspaceDistributedModel.DeepCopy()
+		ws.Inference.Tokenizer = &kaitov1beta1.TokenizerEndpointSpec{Port: 9000}
+
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		port := svc.Spec.Ports[len(svc.Spec.Ports)-1]
+		assert.Equal(t, TokenizerServicePortName, port.Name)
+		assert.Equal(t, int32(9000), port.Port)
+		assert.Equal(t, consts.PortTokenizer, port.TargetPort.IntVal)
+	})
+
+	t.Run("idle timeout sets the load balancer annotation of the cloud", func(t *testing.T) {
+		ws := test.MockWorkspaceDistributedModel.DeepCopy()
+		ws.Inference.Service = &kaitov1beta1.EndpointServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Timeouts: &kaitov1beta1.ServiceTimeoutsSpec{Idle: &metav1.Duration{Duration: 90 * time.Second}},
+		}
+
+		t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, map[string]string{
+			kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout: "4",
+			kaitov1beta1.AnnotationServiceAnnotations:           kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout,
+		}, svc.Annotations)
+
+		t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+		svc = GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Equal(t, map[string]string{
+			kaitov1beta1.AnnotationAWSLoadBalancerIdleTimeout: "90",
+			kaitov1beta1.AnnotationServiceAnnotations:         kaitov1beta1.AnnotationAWSLoadBalancerIdleTimeout,
+		}, svc.Annotations)
+
+		ws.Inference.Service.Type = corev1.ServiceTypeClusterIP
+		svc = GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.Empty(t, svc.Annotations, "only load balancers have an idle timeout")
+	})
+}
+
+func TestMetadataPropagation(t *testing.T) {
+	ws := test.MockWorkspaceDistributedModel.DeepCopy()
+	ws.Labels = map[string]string{"cost-center": "42", kaitov1beta1.LabelWorkspaceName: "other", "app": "phi"}
+	ws.Annotations = map[string]string{"sidecar.istio.io/inject": "true"}
+	ws.MetadataPropagation = &kaitov1beta1.MetadataPropagationSpec{
+		Labels:      []string{"cost-center", kaitov1beta1.LabelWorkspaceName},
+		Annotations: []string{"sidecar.istio.io/*"},
+	}
+	gctx := &generator.WorkspaceGeneratorContext{Workspace: ws}
+
+	t.Run("statefulset pods", func(t *testing.T) {
+		ss := &appsv1.StatefulSet{}
+		assert.NoError(t, GenerateStatefulSetManifest("1", 1)(gctx, ss))
+		assert.Equal(t, "42", ss.Spec.Template.Labels["cost-center"])
+		assert.Equal(t, ws.Name, ss.Spec.Template.Labels[kaitov1beta1.LabelWorkspaceName], "KAITO labels win")
+		assert.NotContains(t, ss.Spec.Template.Labels, "app")
+		assert.NotContains(t, ss.Spec.Selector.MatchLabels, "cost-center", "the selector is immutable")
+		assert.Equal(t, "true", ss.Spec.Template.Annotations["sidecar.istio.io/inject"])
+		assert.Equal(t, "cost-center", ss.Spec.Template.Annotations[kaitov1beta1.AnnotationPropagatedLabels])
+	})
+
+	t.Run("tuning job pods", func(t *testing.T) {
+		j := &batchv1.Job{}
+		assert.NoError(t, GenerateTuningJobManifest("1")(gctx, j))
+		assert.Equal(t, "42", j.Spec.Template.Labels["cost-center"])
+		assert.NotContains(t, j.Labels, "cost-center")
+	})
+
+	t.Run("services", func(t *testing.T) {
+		for _, svc := range []*corev1.Service{GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP), GenerateHeadlessServiceManifest(ws)} {
+			assert.Equal(t, "42", svc.Labels["cost-center"])
+			assert.Equal(t, ws.Name, svc.Labels[kaitov1beta1.LabelWorkspaceName])
+			assert.Equal(t, "true", svc.Annotations["sidecar.istio.io/inject"])
+			assert.Equal(t, "cost-center", svc.Annotations[kaitov1beta1.AnnotationPropagatedLabels], "keys set by KAITO are not recorded")
+			assert.Equal(t, "sidecar.istio.io/inject", svc.Annotations[kaitov1beta1.AnnotationPropagatedAnnotations])
+		}
+	})
+
+	t.Run("targets", func(t *testing.T) {
+		ws := ws.DeepCopy()
+		ws.MetadataPropagation.Targets = []kaitov1beta1.MetadataPropagationTarget{kaitov1beta1.MetadataPropagationTargetNodeClaim}
+		svc := GenerateServiceManifest(ws, corev1.ServiceTypeClusterIP)
+		assert.NotContains(t, svc.Labels, "cost-center")
+		assert.Empty(t, svc.Annotations)
+	})
+}
+
+func TestLoadBalancerIdleTimeoutAnnotation(t *testing.T) {
+	tests := []struct {
+		cloud string
+		idle  time.Duration
+		key   string
+		value string
+	}{
+		{consts.AzureCloudName, 30 * time.Minute, kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout, "30"},
+		{consts.AzureCloudName, 61 * time.Second, kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout, "4"},
+		{consts.AzureCloudName, 5*time.Minute + time.Second, kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout, "6"},
+		{consts.AzureCloudName, 3 * time.Hour, kaitov1beta1.AnnotationAzureLoadBalancerIdleTimeout, "100"},
+		{consts.AWSCloudName, 1500 * time.Millisecond, kaitov1beta1.AnnotationAWSLoadBalancerIdleTimeout, "2"},
+		{"", time.Minute, "", ""},
+	}
+	for _, tt := range tests {
+		key, value := loadBalancerIdleTimeoutAnnotation(tt.cloud, tt.idle)
+		assert.Equal(t, tt.key, key, "cloud %q, idle %s", tt.cloud, tt.idle)
+		assert.Equal(t, tt.value, value, "cloud %q, idle %s", tt.cloud, tt.idle)
+	}
+}
+
+func TestSetProxy(t *testing.T) {
+	envOf := func(c corev1.Container) map[string]string {
+		env := map[string]string{}
+		for _, e := range c.Env {
+			env[e.Name] = e.Value
+		}
+		return env
+	}
+
+	t.Run("nil proxy", func(t *testing.T) {
+		spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+		assert.NoError(t, SetProxy(nil)(nil, spec))
+		assert.Empty(t, spec.Containers[0].Env)
+	})
+
+	t.Run("proxy and CA bundle", func(t *testing.T) {
+		spec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "model-weights-downloader"}},
+			Containers: []corev1.Container{{
+				Name: "main",
+				Env:  []corev1.EnvVar{{Name: "NO_PROXY", Value: "custom"}},
+			}},
+		}
+		proxy := &kaitov1beta1.ProxySpec{
+			HTTPSProxy:     "http://[fd00::1]:3128",
+			NoProxy:        "10.0.0.0/8,localhost",
+			CABundleSecret: "proxy-ca",
+		}
+		assert.NoError(t, SetProxy(proxy)(nil, spec))
+
+		initEnv := envOf(spec.InitContainers[0])
+		assert.Equal(t, "http://[fd00::1]:3128", initEnv["HTTPS_PROXY"])
+		assert.Equal(t, "http://[fd00::1]:3128", initEnv["https_proxy"])
+		assert.NotContains(t, initEnv, "HTTP_PROXY")
+		assert.Equal(t, "localhost,127.0.0.1,::1,.svc,.svc.cluster.local,.cluster.local,10.0.0.0/8", initEnv["NO_PROXY"])
+		assert.Equal(t, "/etc/kaito/proxy-ca/ca.crt", initEnv["SSL_CERT_FILE"])
+		assert.Equal(t, "/etc/kaito/proxy-ca/ca.crt", initEnv["REQUESTS_CA_BUNDLE"])
+
+		// Variables set by the container itself are kept.
+		mainEnv := envOf(spec.Containers[0])
+		assert.Equal(t, "custom", mainEnv["NO_PROXY"])
+		assert.Equal(t, "http://[fd00::1]:3128", mainEnv["HTTPS_PROXY"])
+
+		assert.Len(t, spec.Volumes, 1)
+		assert.Equal(t, "proxy-ca", spec.Volumes[0].Secret.SecretName)
+		for _, c := range append(spec.InitContainers, spec.Containers...) {
+			assert.Equal(t, []corev1.VolumeMount{{Name: "proxy-ca-bundle", MountPath: "/etc/kaito/proxy-ca", ReadOnly: true}}, c.VolumeMounts)
+		}
+	})
+
+	t.Run("cluster CIDRs", func(t *testing.T) {
+		ClusterCIDRs = []string{"10.244.0.0/16", "10.0.0.0/16"}
+		t.Cleanup(func() { ClusterCIDRs = nil })
+		spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+		assert.NoError(t, SetProxy(&kaitov1beta1.ProxySpec{HTTPSProxy: "http://proxy:3128", NoProxy: "10.0.0.0/16,.corp.example.com"})(nil, spec))
+		assert.Equal(t, "localhost,127.0.0.1,::1,.svc,.svc.cluster.local,.cluster.local,10.244.0.0/16,10.0.0.0/16,.corp.example.com",
+			envOf(spec.Containers[0])["NO_PROXY"])
+		assert.Len(t, defaultNoProxy, 6, "the defaults are not modified")
+	})
+}
+
+func TestParseCIDRs(t *testing.T) {
+	cidrs, err := ParseCIDRs(" 10.244.0.0/16, fd00:10::/108,,")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.244.0.0/16", "fd00:10::/108"}, cidrs)
+
+	cidrs, err = ParseCIDRs("")
+	assert.NoError(t, err)
+	assert.Empty(t, cidrs)
+
+	_, err = ParseCIDRs("10.244.0.0/16,10.0.0.0")
+	assert.ErrorContains(t, err, `invalid CIDR "10.0.0.0"`)
+}
+
+func TestSetServiceAccount(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+	gctx := &generator.WorkspaceGeneratorContext{Workspace: ws}
+
+	spec := &corev1.PodSpec{}
+	assert.NoError(t, SetServiceAccount(gctx, spec))
+	assert.Equal(t, "ws", spec.ServiceAccountName)
+
+	ws.ServiceAccount = &kaitov1beta1.ServiceAccountSpec{Name: "inference"}
+	spec = &corev1.PodSpec{}
+	assert.NoError(t, SetServiceAccount(gctx, spec))
+	assert.Equal(t, "inference", spec.ServiceAccountName)
+
+	spec = &corev1.PodSpec{ServiceAccountName: "model-streaming"}
+	assert.NoError(t, SetServiceAccount(gctx, spec))
+	assert.Equal(t, "model-streaming", spec.ServiceAccountName)
+}
+
+func TestSetWorkloadIdentity(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"}}
+	gctx := &generator.WorkspaceGeneratorContext{Workspace: ws}
+
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+	assert.NoError(t, SetWorkloadIdentity(gctx, spec))
+	assert.Empty(t, spec.ServiceAccountName)
+	assert.Empty(t, spec.Volumes)
+
+	ws.Identity = &kaitov1beta1.WorkloadIdentitySpec{Provider: kaitov1beta1.WorkloadIdentityProviderAWS, RoleARN: "arn:aws:iam::123456789012:role/kaito"}
+	spec = &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "model-weights-downloader"}},
+		Containers:     []corev1.Container{{Name: "main", Env: []corev1.EnvVar{{Name: "AWS_ROLE_ARN", Value: "custom"}}}},
+	}
+	assert.NoError(t, SetWorkloadIdentity(gctx, spec))
+	assert.Equal(t, "ws", spec.ServiceAccountName)
+	if assert.Len(t, spec.Volumes, 1) {
+		token := spec.Volumes[0].Projected.Sources[0].ServiceAccountToken
+		assert.Equal(t, "sts.amazonaws.com", token.Audience)
+	}
+	mount := corev1.VolumeMount{Name: "aws-iam-token", MountPath: "/var/run/secrets/eks.amazonaws.com/serviceaccount", ReadOnly: true}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		assert.Equal(t, []corev1.VolumeMount{mount}, c.VolumeMounts)
+		assert.Contains(t, c.Env, corev1.EnvVar{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"})
+	}
+	assert.Equal(t, "arn:aws:iam::123456789012:role/kaito", spec.InitContainers[0].Env[0].Value)
+	assert.Equal(t, "custom", spec.Containers[0].Env[0].Value, "variables set by the container itself are kept")
+
+	spec = &corev1.PodSpec{ServiceAccountName: "model-streaming"}
+	assert.ErrorContains(t, SetWorkloadIdentity(gctx, spec), "already runs as model-streaming")
+}
+
+func TestSetConfidentialCompute(t *testing.T) {
+	t.Run("nil spec", func(t *testing.T) {
+		spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+		assert.NoError(t, SetConfidentialCompute(nil)(nil, spec))
+		assert.Nil(t, spec.Affinity)
+		assert.Empty(t, spec.InitContainers)
+	})
+
+	t.Run("runtime class and attestation", func(t *testing.T) {
+		gpus := corev1.ResourceList{corev1.ResourceName(consts.NvidiaGPU): resource.MustParse("1")}
+		spec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "model-weights-downloader"}},
+			Containers:     []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{Requests: gpus, Limits: gpus}}},
+			Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "apps", Operator: corev1.NodeSelectorOpIn, Values: []string{"phi-4"}}},
+				}}},
+			}},
+		}
+		cc := &kaitov1beta1.ConfidentialComputeSpec{
+			RuntimeClassName: ptr.To("kata-cc"),
+			Attestation: &kaitov1beta1.AttestationSpec{
+				Image:    "mcr.microsoft.com/attestation-client:1.0.0",
+				Endpoint: "https://example.attest.azure.net",
+			},
+		}
+		assert.NoError(t, SetConfidentialCompute(cc)(nil, spec))
+
+		assert.Equal(t, "kata-cc", *spec.RuntimeClassName)
+		assert.Equal(t, []corev1.NodeSelectorRequirement{
+			{Key: "apps", Operator: corev1.NodeSelectorOpIn, Values: []string{"phi-4"}},
+			{Key: kaitov1beta1.LabelConfidentialCompute, Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+		}, spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+		assert.Len(t, spec.InitContainers, 2)
+		attestation := spec.InitContainers[0]
+		assert.Equal(t, "attestation", attestation.Name)
+		assert.Equal(t, "mcr.microsoft.com/attestation-client:1.0.0", attestation.Image)
+		assert.Equal(t, []corev1.EnvVar{{Name: AttestationEndpointEnvName, Value: "https://example.attest.azure.net"}}, attestation.Env)
+		assert.Equal(t, gpus, attestation.Resources.Limits)
+		assert.Equal(t, gpus, attestation.Resources.Requests)
+	})
+}
+
+func TestSetPlacement(t *testing.T) {
+	newCtx := func(placement *kaitov1beta1.PlacementSpec, labels map[string]string) *generator.WorkspaceGeneratorContext {
+		return &generator.WorkspaceGeneratorContext{Workspace: &kaitov1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "phi-4", Namespace: "team-a", Labels: labels},
+			Resource:   kaitov1beta1.ResourceSpec{Placement: placement},
+		}}
+	}
+
+	t.Run("no placement", func(t *testing.T) {
+		spec := &corev1.PodSpec{}
+		assert.NoError(t, SetPlacement(newCtx(nil, nil), spec))
+		assert.Nil(t, spec.Affinity)
+	})
+
+	t.Run("shared without spread", func(t *testing.T) {
+		spec := &corev1.PodSpec{}
+		assert.NoError(t, SetPlacement(newCtx(&kaitov1beta1.PlacementSpec{Policy: kaitov1beta1.PlacementPolicyShared}, nil), spec))
+		assert.Nil(t, spec.Affinity)
+	})
+
+	t.Run("dedicated with allowed labels", func(t *testing.T) {
+		spec := &corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}}
+		placement := &kaitov1beta1.PlacementSpec{
+			Policy:              kaitov1beta1.PlacementPolicyDedicated,
+			AllowColocationWith: map[string]string{"team": "batch", "priority": "low"},
+		}
+		assert.NoError(t, SetPlacement(newCtx(placement, nil), spec))
+
+		exempt := []metav1.LabelSelectorRequirement{
+			{Key: podTemplateGenerationLabel, Operator: metav1.LabelSelectorOpDoesNotExist},
+			{Key: "priority", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"low"}},
+			{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"batch"}},
+		}
+		assert.NotNil(t, spec.Affinity.NodeAffinity)
+		assert.Equal(t, []corev1.PodAffinityTerm{
+			{
+				TopologyKey:   corev1.LabelHostname,
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: exempt},
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: corev1.LabelMetadataName, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"team-a"}},
+				}},
+			},
+			{
+				TopologyKey: corev1.LabelHostname,
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: append([]metav1.LabelSelectorRequirement{
+					{Key: kaitov1beta1.LabelWorkspaceName, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"phi-4"}},
+				}, exempt...)},
+				Namespaces: []string{"team-a"},
+			},
+		}, spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	})
+
+	t.Run("spread across hosts of an InferenceSet", func(t *testing.T) {
+		spec := &corev1.PodSpec{}
+		labels := map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: "phi-4-set"}
+		assert.NoError(t, SetPlacement(newCtx(&kaitov1beta1.PlacementSpec{SpreadAcrossHosts: true}, labels), spec))
+
+		assert.Equal(t, []corev1.PodAffinityTerm{{
+			TopologyKey:   corev1.LabelHostname,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: "phi-4-set"}},
+			Namespaces:    []string{"team-a"},
+		}}, spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	})
+}
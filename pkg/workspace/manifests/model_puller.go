@@ -0,0 +1,73 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+//go:embed model_puller.sh
+var modelPullerScript string
+
+// ModelPullerAttemptsEnvName is the env var that holds how many times the model weights
+// downloader tries all of its sources.
+const ModelPullerAttemptsEnvName = "MODEL_PULLER_ATTEMPTS"
+
+// ModelPullerDefaults holds the cluster-wide settings of the model weights downloader,
+// set once at startup from controller flags.
+var ModelPullerDefaults = struct {
+	// RegistryMirrors are registries, optionally with a repository prefix, that serve copies
+	// of the preset images. They are tried in order before the registry of the preset.
+	RegistryMirrors []string
+	// Attempts is how many times the downloader tries all of its sources before it fails
+	// and the kubelet restarts it.
+	Attempts int
+}{Attempts: 5}
+
+// ParseRegistryMirrors parses a comma separated list of registry mirrors such as
+// "myregistry.azurecr.io,mirror.example.com/kaito".
+func ParseRegistryMirrors(value string) ([]string, error) {
+	var mirrors []string
+	for _, mirror := range strings.Split(value, ",") {
+		mirror = strings.TrimSuffix(strings.TrimSpace(mirror), "/")
+		if mirror == "" {
+			continue
+		}
+		named, err := reference.ParseNamed(mirror + "/model")
+		if err != nil || reference.Domain(named) != strings.SplitN(mirror, "/", 2)[0] {
+			return nil, fmt.Errorf("invalid registry mirror %q", mirror)
+		}
+		mirrors = append(mirrors, mirror)
+	}
+	return mirrors, nil
+}
+
+// modelPullerSources returns the references the downloader tries for image: the image on
+// every registry mirror, then the image itself.
+func modelPullerSources(image string) []string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return []string{image}
+	}
+	suffix := strings.TrimPrefix(reference.TagNameOnly(named).String(), named.Name())
+	sources := make([]string, 0, len(ModelPullerDefaults.RegistryMirrors)+1)
+	for _, mirror := range ModelPullerDefaults.RegistryMirrors {
+		sources = append(sources, mirror+"/"+reference.Path(named)+suffix)
+	}
+	return append(sources, image)
+}
@@ -0,0 +1,45 @@
+#!/bin/sh
+
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Pulls the model weights artifact into the weights volume with oras.
+#
+# Usage: model_puller.sh <output-dir> <reference>...
+#
+# The references are tried in order, so the registry mirrors come before the registry of
+# the preset. A round over all references is retried with a growing backoff until
+# MODEL_PULLER_ATTEMPTS rounds failed.
+
+OUT_DIR="${1}"
+shift
+
+ATTEMPTS="${MODEL_PULLER_ATTEMPTS:-5}"
+
+attempt=1
+while :; do
+    for ref in "$@"; do
+        echo "Pulling ${ref} (attempt ${attempt}/${ATTEMPTS})"
+        if oras pull "${ref}" -o "${OUT_DIR}"; then
+            exit 0
+        fi
+    done
+    if [ "${attempt}" -ge "${ATTEMPTS}" ]; then
+        break
+    fi
+    sleep $((attempt * 10))
+    attempt=$((attempt + 1))
+done
+
+echo "Failed to pull the model weights from: $*" >&2
+exit 1
@@ -0,0 +1,74 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+func TestParseRegistryMirrors(t *testing.T) {
+	mirrors, err := ParseRegistryMirrors(" myregistry.azurecr.io , mirror.example.com:5000/kaito/,")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myregistry.azurecr.io", "mirror.example.com:5000/kaito"}, mirrors)
+
+	mirrors, err = ParseRegistryMirrors("")
+	assert.NoError(t, err)
+	assert.Empty(t, mirrors)
+
+	for _, invalid := range []string{"kaito", "https://myregistry.azurecr.io", "myregistry.azurecr.io/$(id)", "myregistry.azurecr.io:tag"} {
+		_, err := ParseRegistryMirrors(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGenerateModelPullerContainer(t *testing.T) {
+	defer func() { ModelPullerDefaults.RegistryMirrors = nil }()
+	ModelPullerDefaults.RegistryMirrors = []string{"myregistry.azurecr.io", "mirror.example.com/kaito"}
+
+	preset := &pkgmodel.PresetParam{Metadata: pkgmodel.Metadata{Name: "phi-4", Registry: "mcr.microsoft.com/aks/kaito", Tag: "0.2.0"}}
+	containers := GenerateModelPullerContainer(context.TODO(), nil, preset)
+	assert.Len(t, containers, 1)
+	assert.Equal(t, utils.DefaultORASToolImage, containers[0].Image)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, containers[0].Command)
+	assert.Equal(t, []corev1.EnvVar{{Name: ModelPullerAttemptsEnvName, Value: "5"}}, containers[0].Env)
+	assert.Equal(t, []string{
+		modelPullerScript,
+		"model-weights-downloader",
+		utils.DefaultWeightsVolumePath,
+		"myregistry.azurecr.io/aks/kaito/kaito-phi-4:0.2.0",
+		"mirror.example.com/kaito/aks/kaito/kaito-phi-4:0.2.0",
+		"mcr.microsoft.com/aks/kaito/kaito-phi-4:0.2.0",
+	}, containers[0].Args)
+
+	preset.DownloadAtRuntime = true
+	assert.Empty(t, GenerateModelPullerContainer(context.TODO(), nil, preset))
+}
+
+func TestModelPullerSourcesKeepDigest(t *testing.T) {
+	defer func() { ModelPullerDefaults.RegistryMirrors = nil }()
+	ModelPullerDefaults.RegistryMirrors = []string{"myregistry.azurecr.io"}
+
+	image := "mcr.microsoft.com/aks/kaito/kaito-phi-4:0.2.0@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	assert.Equal(t, []string{
+		"myregistry.azurecr.io/aks/kaito/kaito-phi-4:0.2.0@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		image,
+	}, modelPullerSources(image))
+}
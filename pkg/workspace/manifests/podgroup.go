@@ -0,0 +1,104 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+)
+
+const (
+	// coschedulingPodGroupLabel joins a pod to a scheduler-plugins PodGroup.
+	coschedulingPodGroupLabel = "scheduling.x-k8s.io/pod-group"
+	// volcanoPodGroupAnnotation joins a pod to a Volcano PodGroup.
+	volcanoPodGroupAnnotation = "scheduling.k8s.io/group-name"
+)
+
+// PodGroupGVK returns the PodGroup kind of the given gang scheduler.
+func PodGroupGVK(scheduler string) schema.GroupVersionKind {
+	if scheduler == kaitov1beta1.GangSchedulerVolcano {
+		return schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"}
+	}
+	return schema.GroupVersionKind{Group: "scheduling.x-k8s.io", Version: "v1alpha1", Kind: "PodGroup"}
+}
+
+// GeneratePodGroupManifest returns the PodGroup that admits the minMember pods of the
+// workspace together, or nil if the workspace is not gang scheduled. The PodGroup is
+// named after the workspace and owned by it.
+func GeneratePodGroupManifest(workspaceObj *kaitov1beta1.Workspace, minMember int32) *unstructured.Unstructured {
+	scheduler, _, ok := kaitov1beta1.GetGangScheduler(workspaceObj)
+	if !ok {
+		return nil
+	}
+	pg := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"minMember": int64(minMember)},
+	}}
+	pg.SetGroupVersionKind(PodGroupGVK(scheduler))
+	pg.SetName(workspaceObj.Name)
+	pg.SetNamespace(workspaceObj.Namespace)
+	pg.SetLabels(map[string]string{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name})
+	pg.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(workspaceObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+	})
+	return pg
+}
+
+// IsPodGroupAdmitted reports whether a PodGroup in the given phase has had all of its
+// minimum members scheduled.
+func IsPodGroupAdmitted(scheduler, phase string) bool {
+	if scheduler == kaitov1beta1.GangSchedulerVolcano {
+		return phase == "Running" || phase == "Completed"
+	}
+	return phase == "Scheduled" || phase == "Running" || phase == "Finished"
+}
+
+// setPodTemplateGangScheduling hands the pods to the gang scheduler and joins them to
+// the PodGroup of the workspace.
+func setPodTemplateGangScheduling(workspaceObj *kaitov1beta1.Workspace, template *corev1.PodTemplateSpec) {
+	scheduler, schedulerName, ok := kaitov1beta1.GetGangScheduler(workspaceObj)
+	if !ok {
+		return
+	}
+	template.Spec.SchedulerName = schedulerName
+	if scheduler == kaitov1beta1.GangSchedulerVolcano {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[volcanoPodGroupAnnotation] = workspaceObj.Name
+		return
+	}
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
+	template.Labels[coschedulingPodGroupLabel] = workspaceObj.Name
+}
+
+// SetStatefulSetGangScheduling must be applied after the pod spec is set.
+func SetStatefulSetGangScheduling(ctx *generator.WorkspaceGeneratorContext, ss *appsv1.StatefulSet) error {
+	setPodTemplateGangScheduling(ctx.Workspace, &ss.Spec.Template)
+	return nil
+}
+
+// SetJobGangScheduling must be applied after the pod spec is set.
+func SetJobGangScheduling(ctx *generator.WorkspaceGeneratorContext, j *batchv1.Job) error {
+	setPodTemplateGangScheduling(ctx.Workspace, &j.Spec.Template)
+	return nil
+}
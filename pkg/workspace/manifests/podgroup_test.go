@@ -0,0 +1,86 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+)
+
+func gangWorkspace(annotations map[string]string) *kaitov1beta1.Workspace {
+	return &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", Annotations: annotations},
+	}
+}
+
+func TestGeneratePodGroupManifest(t *testing.T) {
+	assert.Nil(t, GeneratePodGroupManifest(gangWorkspace(nil), 2))
+
+	pg := GeneratePodGroupManifest(gangWorkspace(map[string]string{
+		kaitov1beta1.AnnotationGangScheduler: kaitov1beta1.GangSchedulerCoscheduling,
+	}), 3)
+	assert.Equal(t, "scheduling.x-k8s.io/v1alpha1", pg.GetAPIVersion())
+	assert.Equal(t, "ws", pg.GetName())
+	assert.Len(t, pg.GetOwnerReferences(), 1)
+	minMember, _, _ := unstructured.NestedInt64(pg.Object, "spec", "minMember")
+	assert.Equal(t, int64(3), minMember)
+
+	pg = GeneratePodGroupManifest(gangWorkspace(map[string]string{
+		kaitov1beta1.AnnotationGangScheduler: kaitov1beta1.GangSchedulerVolcano,
+	}), 1)
+	assert.Equal(t, "scheduling.volcano.sh/v1beta1", pg.GetAPIVersion())
+}
+
+func TestSetGangScheduling(t *testing.T) {
+	t.Run("not gang scheduled", func(t *testing.T) {
+		ss := &appsv1.StatefulSet{}
+		assert.NoError(t, SetStatefulSetGangScheduling(&generator.WorkspaceGeneratorContext{Workspace: gangWorkspace(nil)}, ss))
+		assert.Empty(t, ss.Spec.Template.Spec.SchedulerName)
+		assert.Empty(t, ss.Spec.Template.Labels)
+	})
+
+	t.Run("coscheduling statefulset", func(t *testing.T) {
+		ss := &appsv1.StatefulSet{}
+		ws := gangWorkspace(map[string]string{kaitov1beta1.AnnotationGangScheduler: kaitov1beta1.GangSchedulerCoscheduling})
+		assert.NoError(t, SetStatefulSetGangScheduling(&generator.WorkspaceGeneratorContext{Workspace: ws}, ss))
+		assert.Equal(t, "scheduler-plugins-scheduler", ss.Spec.Template.Spec.SchedulerName)
+		assert.Equal(t, "ws", ss.Spec.Template.Labels[coschedulingPodGroupLabel])
+	})
+
+	t.Run("volcano job with scheduler name", func(t *testing.T) {
+		j := &batchv1.Job{}
+		ws := gangWorkspace(map[string]string{
+			kaitov1beta1.AnnotationGangScheduler: kaitov1beta1.GangSchedulerVolcano,
+			kaitov1beta1.AnnotationSchedulerName: "volcano-gpu",
+		})
+		assert.NoError(t, SetJobGangScheduling(&generator.WorkspaceGeneratorContext{Workspace: ws}, j))
+		assert.Equal(t, "volcano-gpu", j.Spec.Template.Spec.SchedulerName)
+		assert.Equal(t, "ws", j.Spec.Template.Annotations[volcanoPodGroupAnnotation])
+	})
+}
+
+func TestIsPodGroupAdmitted(t *testing.T) {
+	assert.True(t, IsPodGroupAdmitted(kaitov1beta1.GangSchedulerCoscheduling, "Scheduled"))
+	assert.False(t, IsPodGroupAdmitted(kaitov1beta1.GangSchedulerCoscheduling, "Pending"))
+	assert.True(t, IsPodGroupAdmitted(kaitov1beta1.GangSchedulerVolcano, "Running"))
+	assert.False(t, IsPodGroupAdmitted(kaitov1beta1.GangSchedulerVolcano, "Inqueue"))
+}
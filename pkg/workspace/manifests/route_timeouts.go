@@ -0,0 +1,126 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// GatewayDuration formats d as a Gateway API Duration, e.g. 1h30m or 500ms. Fractions of a
+// millisecond are dropped.
+func GatewayDuration(d time.Duration) string {
+	var b strings.Builder
+	for _, u := range []struct {
+		unit   time.Duration
+		suffix string
+	}{{time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}, {time.Millisecond, "ms"}} {
+		if n := d / u.unit; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.suffix)
+			d -= n * u.unit
+		}
+	}
+	if b.Len() == 0 {
+		return "0s"
+	}
+	return b.String()
+}
+
+// SetRequestTimeout sets timeouts.request of the rules of an HTTPRoute that send requests to
+// the InferencePool poolName. Other timeouts of the rules are left alone. It returns the
+// number of rules that send requests to the pool and whether the route changed.
+func SetRequestTimeout(route *unstructured.Unstructured, poolName, timeout string) (int, bool, error) {
+	rules, found, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	matched, changed := 0, false
+	for i, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok || !routesToPool(rule, route.GetNamespace(), poolName) {
+			continue
+		}
+		matched++
+		if current, _, _ := unstructured.NestedString(rule, "timeouts", "request"); current == timeout {
+			continue
+		}
+		if err := unstructured.SetNestedField(rule, timeout, "timeouts", "request"); err != nil {
+			return 0, false, err
+		}
+		rules[i] = rule
+		changed = true
+	}
+	if !changed {
+		return matched, false, nil
+	}
+	return matched, true, unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+}
+
+// DetachServiceBackend sets the weight of the backendRefs of an HTTPRoute that send requests
+// to the Service serviceName to zero, so that the Gateway sends no new requests to it while
+// the requests in flight complete. It returns whether the route changed.
+func DetachServiceBackend(route *unstructured.Unstructured, serviceName string) (bool, error) {
+	rules, found, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil || !found {
+		return false, err
+	}
+
+	changed := false
+	for i, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		ruleChanged := false
+		for j, b := range backendRefs {
+			ref, ok := b.(map[string]any)
+			if !ok || !isServiceRef(ref, route.GetNamespace(), serviceName) {
+				continue
+			}
+			if weight, found, _ := unstructured.NestedInt64(ref, "weight"); found && weight == 0 {
+				continue
+			}
+			ref["weight"] = int64(0)
+			backendRefs[j] = ref
+			ruleChanged = true
+		}
+		if !ruleChanged {
+			continue
+		}
+		if err := unstructured.SetNestedSlice(rule, backendRefs, "backendRefs"); err != nil {
+			return false, err
+		}
+		rules[i] = rule
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+}
+
+// isServiceRef reports whether the backendRef ref of a route in namespace is the Service
+// serviceName. The group and kind of a backendRef default to the core Service.
+func isServiceRef(ref map[string]any, namespace, serviceName string) bool {
+	group, _, _ := unstructured.NestedString(ref, "group")
+	kind, _, _ := unstructured.NestedString(ref, "kind")
+	name, _, _ := unstructured.NestedString(ref, "name")
+	ns, _, _ := unstructured.NestedString(ref, "namespace")
+	return group == "" && (kind == "" || kind == "Service") && name == serviceName && (ns == "" || ns == namespace)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGatewayDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                  "0s",
+		500 * time.Millisecond:             "500ms",
+		90 * time.Second:                   "1m30s",
+		time.Hour + 1500*time.Millisecond:  "1h1s500ms",
+		48 * time.Hour:                     "48h",
+		time.Second + 500*time.Microsecond: "1s",
+	}
+	for d, want := range tests {
+		assert.Equal(t, want, GatewayDuration(d), d.String())
+	}
+}
+
+func TestSetRequestTimeout(t *testing.T) {
+	newRoute := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+			"spec": map[string]any{"rules": []any{
+				map[string]any{
+					"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "phi-inferencepool"}},
+					"timeouts":    map[string]any{"backendRequest": "30s"},
+				},
+				map[string]any{
+					"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "other-inferencepool"}},
+				},
+			}},
+		}}
+	}
+	timeoutsOf := func(route *unstructured.Unstructured, i int) map[string]any {
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		timeouts, _, _ := unstructured.NestedMap(rules[i].(map[string]any), "timeouts")
+		return timeouts
+	}
+
+	route := newRoute()
+	matched, changed, err := SetRequestTimeout(route, "phi-inferencepool", "10m")
+	require.NoError(t, err)
+	assert.Equal(t, 1, matched)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]any{"request": "10m", "backendRequest": "30s"}, timeoutsOf(route, 0), "other timeouts are kept")
+	assert.Empty(t, timeoutsOf(route, 1), "rules to other pools are left alone")
+
+	_, changed, err = SetRequestTimeout(route, "phi-inferencepool", "10m")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	_, changed, err = SetRequestTimeout(route, "phi-inferencepool", "0s")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "0s", timeoutsOf(route, 0)["request"])
+
+	matched, changed, err = SetRequestTimeout(newRoute(), "missing-inferencepool", "10m")
+	require.NoError(t, err)
+	assert.Zero(t, matched)
+	assert.False(t, changed)
+}
+
+func TestDetachServiceBackend(t *testing.T) {
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+		"spec": map[string]any{"rules": []any{
+			map[string]any{"backendRefs": []any{
+				map[string]any{"name": "phi", "port": int64(80), "weight": int64(90)},
+				map[string]any{"name": "phi-v2", "port": int64(80), "weight": int64(10)},
+			}},
+			map[string]any{"backendRefs": []any{
+				map[string]any{"kind": "Service", "name": "phi", "namespace": "default", "port": int64(80)},
+			}},
+			map[string]any{"backendRefs": []any{
+				map[string]any{"name": "phi", "namespace": "other", "port": int64(80)},
+				map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "phi"},
+			}},
+		}},
+	}}
+	weightsOf := func(i int) []any {
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		refs, _, _ := unstructured.NestedSlice(rules[i].(map[string]any), "backendRefs")
+		weights := make([]any, 0, len(refs))
+		for _, r := range refs {
+			weights = append(weights, r.(map[string]any)["weight"])
+		}
+		return weights
+	}
+
+	changed, err := DetachServiceBackend(route, "phi")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []any{int64(0), int64(10)}, weightsOf(0), "other Services keep their weight")
+	assert.Equal(t, []any{int64(0)}, weightsOf(1))
+	assert.Equal(t, []any{nil, nil}, weightsOf(2), "Services in other namespaces and InferencePools are left alone")
+
+	changed, err = DetachServiceBackend(route, "phi")
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
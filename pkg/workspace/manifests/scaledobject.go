@@ -0,0 +1,112 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// ScaledObjectGVK is the KEDA ScaledObject kind.
+var ScaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+
+// defaultServiceBusMessageCount is the KEDA default target of the azure-servicebus trigger.
+const defaultServiceBusMessageCount = 5
+
+// GenerateScaledObjectManifest returns the KEDA ScaledObject that scales the InferenceSet
+// through its scale subresource, or nil if autoscaling is not configured. The ScaledObject
+// is named after the InferenceSet and owned by it.
+func GenerateScaledObjectManifest(iObj *kaitov1beta1.InferenceSet) *unstructured.Unstructured {
+	a := iObj.Spec.Autoscaling
+	if a == nil {
+		return nil
+	}
+
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": kaitov1beta1.GroupVersion.String(),
+			"kind":       "InferenceSet",
+			"name":       iObj.Name,
+		},
+		"maxReplicaCount": int64(a.MaxReplicas),
+	}
+	if a.MinReplicas != nil {
+		spec["minReplicaCount"] = int64(*a.MinReplicas)
+	}
+	if a.PollingIntervalSeconds != nil {
+		spec["pollingInterval"] = int64(*a.PollingIntervalSeconds)
+	}
+	if a.CooldownSeconds != nil {
+		spec["cooldownPeriod"] = int64(*a.CooldownSeconds)
+	}
+
+	triggers := make([]interface{}, 0, len(a.Triggers))
+	for _, t := range a.Triggers {
+		switch {
+		case t.Prometheus != nil:
+			metadata := map[string]interface{}{
+				"serverAddress": t.Prometheus.ServerAddress,
+				"query":         t.Prometheus.Query,
+				"threshold":     t.Prometheus.Threshold,
+			}
+			if t.Prometheus.ActivationThreshold != "" {
+				metadata["activationThreshold"] = t.Prometheus.ActivationThreshold
+			}
+			triggers = append(triggers, scaledObjectTrigger("prometheus", metadata, t.Prometheus.AuthenticationRef))
+		case t.AzureServiceBus != nil:
+			sb := t.AzureServiceBus
+			messageCount := int32(defaultServiceBusMessageCount)
+			if sb.MessageCount != nil {
+				messageCount = *sb.MessageCount
+			}
+			metadata := map[string]interface{}{
+				"namespace":    sb.Namespace,
+				"messageCount": strconv.Itoa(int(messageCount)),
+			}
+			if sb.QueueName != "" {
+				metadata["queueName"] = sb.QueueName
+			} else {
+				metadata["topicName"] = sb.TopicName
+				metadata["subscriptionName"] = sb.SubscriptionName
+			}
+			triggers = append(triggers, scaledObjectTrigger("azure-servicebus", metadata, sb.AuthenticationRef))
+		}
+	}
+	spec["triggers"] = triggers
+
+	so := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	so.SetGroupVersionKind(ScaledObjectGVK)
+	so.SetName(iObj.Name)
+	so.SetNamespace(iObj.Namespace)
+	so.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(iObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
+	})
+	return so
+}
+
+func scaledObjectTrigger(triggerType string, metadata map[string]interface{}, authenticationRef string) map[string]interface{} {
+	trigger := map[string]interface{}{
+		"type":     triggerType,
+		"metadata": metadata,
+	}
+	if authenticationRef != "" {
+		trigger["authenticationRef"] = map[string]interface{}{"name": authenticationRef}
+	}
+	return trigger
+}
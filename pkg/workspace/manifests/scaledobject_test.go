@@ -0,0 +1,91 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestGenerateScaledObjectManifest(t *testing.T) {
+	iObj := &kaitov1beta1.InferenceSet{ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default"}}
+	assert.Nil(t, GenerateScaledObjectManifest(iObj))
+
+	iObj.Spec.Autoscaling = &kaitov1beta1.InferenceSetAutoscalingSpec{
+		MinReplicas:     ptr.To[int32](0),
+		MaxReplicas:     4,
+		CooldownSeconds: ptr.To[int32](600),
+		Triggers: []kaitov1beta1.ScaleTrigger{
+			{Prometheus: &kaitov1beta1.PrometheusScaleTrigger{
+				ServerAddress: "http://prometheus.monitoring:9090",
+				Query:         "sum(vllm:num_requests_waiting)",
+				Threshold:     "10",
+			}},
+			{AzureServiceBus: &kaitov1beta1.AzureServiceBusScaleTrigger{
+				Namespace:         "kaito-jobs",
+				TopicName:         "prompts",
+				SubscriptionName:  "phi",
+				AuthenticationRef: "servicebus-auth",
+			}},
+		},
+	}
+	so := GenerateScaledObjectManifest(iObj)
+	assert.Equal(t, "keda.sh/v1alpha1", so.GetAPIVersion())
+	assert.Equal(t, "ScaledObject", so.GetKind())
+	assert.Equal(t, "phi", so.GetName())
+	assert.Equal(t, "default", so.GetNamespace())
+	if assert.Len(t, so.GetOwnerReferences(), 1) {
+		assert.Equal(t, "InferenceSet", so.GetOwnerReferences()[0].Kind)
+		assert.True(t, *so.GetOwnerReferences()[0].Controller)
+	}
+
+	target, _, _ := unstructured.NestedStringMap(so.Object, "spec", "scaleTargetRef")
+	assert.Equal(t, map[string]string{"apiVersion": "kaito.sh/v1beta1", "kind": "InferenceSet", "name": "phi"}, target)
+	minReplicas, _, _ := unstructured.NestedInt64(so.Object, "spec", "minReplicaCount")
+	assert.Equal(t, int64(0), minReplicas)
+	maxReplicas, _, _ := unstructured.NestedInt64(so.Object, "spec", "maxReplicaCount")
+	assert.Equal(t, int64(4), maxReplicas)
+	cooldown, _, _ := unstructured.NestedInt64(so.Object, "spec", "cooldownPeriod")
+	assert.Equal(t, int64(600), cooldown)
+	_, found, _ := unstructured.NestedInt64(so.Object, "spec", "pollingInterval")
+	assert.False(t, found)
+
+	triggers, _, _ := unstructured.NestedSlice(so.Object, "spec", "triggers")
+	if assert.Len(t, triggers, 2) {
+		prom := triggers[0].(map[string]interface{})
+		assert.Equal(t, "prometheus", prom["type"])
+		assert.Equal(t, map[string]interface{}{
+			"serverAddress": "http://prometheus.monitoring:9090",
+			"query":         "sum(vllm:num_requests_waiting)",
+			"threshold":     "10",
+		}, prom["metadata"])
+		assert.NotContains(t, prom, "authenticationRef")
+
+		sb := triggers[1].(map[string]interface{})
+		assert.Equal(t, "azure-servicebus", sb["type"])
+		assert.Equal(t, map[string]interface{}{
+			"namespace":        "kaito-jobs",
+			"topicName":        "prompts",
+			"subscriptionName": "phi",
+			"messageCount":     "5",
+		}, sb["metadata"])
+		assert.Equal(t, map[string]interface{}{"name": "servicebus-auth"}, sb["authenticationRef"])
+	}
+}
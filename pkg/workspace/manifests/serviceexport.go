@@ -0,0 +1,45 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// ServiceExportGVK is the multi-cluster services kind that exports a Service to the
+// other clusters of its ClusterSet.
+var ServiceExportGVK = schema.GroupVersionKind{Group: "multicluster.x-k8s.io", Version: "v1alpha1", Kind: "ServiceExport"}
+
+// GenerateServiceExportManifest returns the ServiceExport of the inference Service of the
+// workspace, or nil if inference.service.dns.export is not set. A ServiceExport must have
+// the name of the Service it exports.
+func GenerateServiceExportManifest(workspaceObj *kaitov1beta1.Workspace) *unstructured.Unstructured {
+	if workspaceObj.Inference == nil || workspaceObj.Inference.Service == nil ||
+		workspaceObj.Inference.Service.DNS == nil || !workspaceObj.Inference.Service.DNS.Export {
+		return nil
+	}
+	export := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	export.SetGroupVersionKind(ServiceExportGVK)
+	export.SetName(workspaceObj.Name)
+	export.SetNamespace(workspaceObj.Namespace)
+	export.SetLabels(map[string]string{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name})
+	export.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(workspaceObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+	})
+	return export
+}
@@ -0,0 +1,42 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestGenerateServiceExportManifest(t *testing.T) {
+	ws := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Inference:  &kaitov1beta1.InferenceSpec{},
+	}
+	assert.Nil(t, GenerateServiceExportManifest(ws))
+
+	ws.Inference.Service = &kaitov1beta1.EndpointServiceSpec{DNS: &kaitov1beta1.ServiceDNSSpec{Hostname: "phi-4.models.example.com"}}
+	assert.Nil(t, GenerateServiceExportManifest(ws))
+
+	ws.Inference.Service.DNS.Export = true
+	export := GenerateServiceExportManifest(ws)
+	assert.Equal(t, "multicluster.x-k8s.io/v1alpha1", export.GetAPIVersion())
+	assert.Equal(t, "ServiceExport", export.GetKind())
+	assert.Equal(t, "ws", export.GetName())
+	assert.Equal(t, "default", export.GetNamespace())
+	assert.Len(t, export.GetOwnerReferences(), 1)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gaiev1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// HTTPRouteGVK is the Gateway API HTTPRoute kind.
+var HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// shadowServicePort is the port of the Service that receives mirrored requests.
+const shadowServicePort = 80
+
+// ShadowServiceName returns the name of the Service that receives the requests mirrored
+// from the InferenceSet.
+func ShadowServiceName(iObj *kaitov1beta1.InferenceSet) string {
+	return iObj.Name + "-shadow"
+}
+
+// GenerateShadowServiceManifest returns the Service over the serving pods of the candidate
+// InferenceSet of spec.shadowTo, which the HTTPRoutes of iObj mirror requests to. Like the
+// InferencePool, it selects the leader pod of each candidate Workspace. The Service is
+// owned by iObj, not by the candidate, so it goes away with the shadowing.
+func GenerateShadowServiceManifest(iObj *kaitov1beta1.InferenceSet) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ShadowServiceName(iObj),
+			Namespace: iObj.Namespace,
+			Labels:    map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: iObj.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(iObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				consts.WorkspaceCreatedByInferenceSetLabel: iObj.Spec.ShadowTo.InferenceSet,
+				appsv1.PodIndexLabel:                       "0",
+			},
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Protocol:   corev1.ProtocolTCP,
+				Port:       shadowServicePort,
+				TargetPort: intstr.FromInt32(consts.PortInferenceServer),
+			}},
+		},
+	}
+}
+
+// SetRequestMirror makes the rules of an HTTPRoute that send requests to the InferencePool
+// poolName mirror percent of them to the Service serviceName. A percent of 0 removes the
+// mirror. Only the RequestMirror filter pointing at serviceName is managed, so other
+// filters of the rules are left alone. It returns the number of rules that send requests
+// to the pool and whether the route changed.
+func SetRequestMirror(route *unstructured.Unstructured, poolName, serviceName string, percent int32) (int, bool, error) {
+	rules, found, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	matched, changed := 0, false
+	for i, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok || !routesToPool(rule, route.GetNamespace(), poolName) {
+			continue
+		}
+		matched++
+
+		filters, _, err := unstructured.NestedSlice(rule, "filters")
+		if err != nil {
+			return 0, false, err
+		}
+		kept := make([]any, 0, len(filters)+1)
+		var existing map[string]any
+		for _, f := range filters {
+			if filter, ok := f.(map[string]any); ok && isMirrorTo(filter, serviceName) {
+				existing = filter
+				continue
+			}
+			kept = append(kept, f)
+		}
+		if percent > 0 {
+			want := map[string]any{
+				"type": "RequestMirror",
+				"requestMirror": map[string]any{
+					"backendRef": map[string]any{"name": serviceName, "port": int64(shadowServicePort)},
+					"percent":    int64(percent),
+				},
+			}
+			if existing != nil && mirrorEqual(existing, want) {
+				continue
+			}
+			kept = append(kept, want)
+		} else if existing == nil {
+			continue
+		}
+		changed = true
+		if len(kept) == 0 {
+			delete(rule, "filters")
+		} else {
+			rule["filters"] = kept
+		}
+		rules[i] = rule
+	}
+	if !changed {
+		return matched, false, nil
+	}
+	return matched, true, unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+}
+
+// routesToPool reports whether an HTTPRoute rule has a backendRef to the InferencePool
+// poolName in the namespace of the route.
+func routesToPool(rule map[string]any, namespace, poolName string) bool {
+	backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+	for _, b := range backendRefs {
+		ref, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(ref, "group")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		name, _, _ := unstructured.NestedString(ref, "name")
+		ns, _, _ := unstructured.NestedString(ref, "namespace")
+		if group == gaiev1.GroupName && kind == "InferencePool" && name == poolName && (ns == "" || ns == namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMirrorTo reports whether filter is a RequestMirror filter to the Service serviceName.
+func isMirrorTo(filter map[string]any, serviceName string) bool {
+	if t, _, _ := unstructured.NestedString(filter, "type"); t != "RequestMirror" {
+		return false
+	}
+	name, _, _ := unstructured.NestedString(filter, "requestMirror", "backendRef", "name")
+	kind, _, _ := unstructured.NestedString(filter, "requestMirror", "backendRef", "kind")
+	return name == serviceName && (kind == "" || kind == "Service")
+}
+
+// mirrorEqual compares the port and percent of two RequestMirror filters to the same Service.
+func mirrorEqual(a, b map[string]any) bool {
+	aPort, _, _ := unstructured.NestedInt64(a, "requestMirror", "backendRef", "port")
+	bPort, _, _ := unstructured.NestedInt64(b, "requestMirror", "backendRef", "port")
+	aPercent, _, _ := unstructured.NestedInt64(a, "requestMirror", "percent")
+	bPercent, _, _ := unstructured.NestedInt64(b, "requestMirror", "percent")
+	return aPort == bPort && aPercent == bPercent
+}
@@ -0,0 +1,111 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+func TestGenerateShadowServiceManifest(t *testing.T) {
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec:       kaitov1beta1.InferenceSetSpec{ShadowTo: &kaitov1beta1.ShadowSpec{InferenceSet: "phi-candidate", Percent: 10}},
+	}
+	svc := GenerateShadowServiceManifest(iObj)
+	assert.Equal(t, "phi-shadow", svc.Name)
+	assert.Equal(t, "default", svc.Namespace)
+	assert.Equal(t, map[string]string{
+		consts.WorkspaceCreatedByInferenceSetLabel: "phi-candidate",
+		appsv1.PodIndexLabel:                       "0",
+	}, svc.Spec.Selector)
+	if assert.Len(t, svc.Spec.Ports, 1) {
+		assert.Equal(t, int32(80), svc.Spec.Ports[0].Port)
+		assert.Equal(t, int32(consts.PortInferenceServer), svc.Spec.Ports[0].TargetPort.IntVal)
+	}
+	assert.True(t, metav1.IsControlledBy(svc, iObj))
+}
+
+func TestSetRequestMirror(t *testing.T) {
+	newRoute := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": "llm", "namespace": "default"},
+			"spec": map[string]any{"rules": []any{
+				map[string]any{
+					"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "phi-inferencepool"}},
+					"filters": []any{map[string]any{
+						"type":                  "RequestHeaderModifier",
+						"requestHeaderModifier": map[string]any{"set": []any{map[string]any{"name": "x-route", "value": "llm"}}},
+					}},
+				},
+				map[string]any{
+					"backendRefs": []any{map[string]any{"group": "inference.networking.k8s.io", "kind": "InferencePool", "name": "other-inferencepool"}},
+				},
+			}},
+		}}
+	}
+	filtersOf := func(route *unstructured.Unstructured, i int) []any {
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		filters, _, _ := unstructured.NestedSlice(rules[i].(map[string]any), "filters")
+		return filters
+	}
+	percentOf := func(route *unstructured.Unstructured) int64 {
+		for _, f := range filtersOf(route, 0) {
+			if isMirrorTo(f.(map[string]any), "phi-shadow") {
+				percent, _, _ := unstructured.NestedInt64(f.(map[string]any), "requestMirror", "percent")
+				return percent
+			}
+		}
+		return 0
+	}
+
+	route := newRoute()
+	matched, changed, err := SetRequestMirror(route, "phi-inferencepool", "phi-shadow", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, matched)
+	assert.True(t, changed)
+	assert.Len(t, filtersOf(route, 0), 2, "other filters are kept")
+	assert.Equal(t, int64(10), percentOf(route))
+	assert.Empty(t, filtersOf(route, 1), "rules to other pools are left alone")
+
+	_, changed, err = SetRequestMirror(route, "phi-inferencepool", "phi-shadow", 10)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	_, changed, err = SetRequestMirror(route, "phi-inferencepool", "phi-shadow", 25)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, filtersOf(route, 0), 2)
+	assert.Equal(t, int64(25), percentOf(route))
+
+	_, changed, err = SetRequestMirror(route, "phi-inferencepool", "phi-shadow", 0)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, filtersOf(newRoute(), 0), filtersOf(route, 0))
+
+	matched, changed, err = SetRequestMirror(newRoute(), "missing-inferencepool", "missing-shadow", 10)
+	require.NoError(t, err)
+	assert.Zero(t, matched)
+	assert.False(t, changed)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gaiev1 "sigs.k8s.io/gateway-api-inference-extension/api/v1"
+	gaiev1alpha2 "sigs.k8s.io/gateway-api-inference-extension/apix/v1alpha2"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+// Traffic classes of an InferenceSet.
+const (
+	TrafficClassInteractive = "interactive"
+	TrafficClassBatch       = "batch"
+)
+
+// Defaults of the EPP saturation thresholds, matching the defaults of the pinned EPP.
+const (
+	defaultMaxWaitingRequestsPerReplica = int32(5)
+	defaultMaxKVCacheUtilizationPercent = int32(80)
+)
+
+// InferenceObjectiveName returns the name of the InferenceObjective of a traffic class, which
+// clients pass in the x-gateway-inference-objective header.
+func InferenceObjectiveName(iObj *kaitov1beta1.InferenceSet, class string) string {
+	return iObj.Name + "-" + class
+}
+
+// GenerateTrafficClassObjectives returns the InferenceObjectives of the interactive and batch
+// traffic classes of iObj. They reference the InferencePool of iObj and are owned by iObj.
+func GenerateTrafficClassObjectives(iObj *kaitov1beta1.InferenceSet) []*gaiev1alpha2.InferenceObjective {
+	tc := iObj.Spec.TrafficClasses
+	priorities := []struct {
+		class    string
+		priority int32
+	}{
+		{TrafficClassInteractive, tc.InteractivePriority()},
+		{TrafficClassBatch, tc.BatchPriority()},
+	}
+	objectives := make([]*gaiev1alpha2.InferenceObjective, 0, len(priorities))
+	for _, p := range priorities {
+		priority := int(p.priority)
+		objectives = append(objectives, &gaiev1alpha2.InferenceObjective{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      InferenceObjectiveName(iObj, p.class),
+				Namespace: iObj.Namespace,
+				Labels:    map[string]string{consts.WorkspaceCreatedByInferenceSetLabel: iObj.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(iObj, kaitov1beta1.GroupVersion.WithKind("InferenceSet")),
+				},
+			},
+			Spec: gaiev1alpha2.InferenceObjectiveSpec{
+				Priority: &priority,
+				PoolRef: gaiev1alpha2.PoolObjectReference{
+					Group: gaiev1alpha2.Group(gaiev1.SchemeGroupVersion.Group),
+					Kind:  "InferencePool",
+					Name:  gaiev1alpha2.ObjectName(utils.InferencePoolName(iObj.Name)),
+				},
+			},
+		})
+	}
+	return objectives
+}
+
+// trafficClassesEPPEnv returns the EPP environment that enables the flow control layer, which
+// queues requests per InferenceObjective priority once the model servers are saturated. The
+// pinned EPP reads its saturation thresholds from the environment only.
+func trafficClassesEPPEnv(tc *kaitov1beta1.TrafficClassesSpec) []map[string]string {
+	queueDepth := defaultMaxWaitingRequestsPerReplica
+	if tc.MaxWaitingRequestsPerReplica != nil {
+		queueDepth = *tc.MaxWaitingRequestsPerReplica
+	}
+	kvCachePercent := defaultMaxKVCacheUtilizationPercent
+	if tc.MaxKVCacheUtilizationPercent != nil {
+		kvCachePercent = *tc.MaxKVCacheUtilizationPercent
+	}
+	return []map[string]string{
+		{"name": "ENABLE_EXPERIMENTAL_FLOW_CONTROL_LAYER", "value": "true"},
+		{"name": "SD_QUEUE_DEPTH_THRESHOLD", "value": strconv.Itoa(int(queueDepth))},
+		{"name": "SD_KV_CACHE_UTIL_THRESHOLD", "value": strconv.FormatFloat(float64(kvCachePercent)/100, 'f', 2, 64)},
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+)
+
+func TestGenerateTrafficClassObjectives(t *testing.T) {
+	iObj := &kaitov1beta1.InferenceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "phi", Namespace: "default", UID: "uid"},
+		Spec: kaitov1beta1.InferenceSetSpec{
+			TrafficClasses: &kaitov1beta1.TrafficClassesSpec{Batch: &kaitov1beta1.TrafficClass{Priority: -50}},
+		},
+	}
+
+	objectives := GenerateTrafficClassObjectives(iObj)
+	require.Len(t, objectives, 2)
+
+	assert.Equal(t, "phi-interactive", objectives[0].Name)
+	assert.Equal(t, ptr.To(10), objectives[0].Spec.Priority)
+	assert.Equal(t, "phi-batch", objectives[1].Name)
+	assert.Equal(t, ptr.To(-50), objectives[1].Spec.Priority)
+	for _, o := range objectives {
+		assert.Equal(t, "default", o.Namespace)
+		assert.Equal(t, "phi", o.Labels[consts.WorkspaceCreatedByInferenceSetLabel])
+		assert.True(t, metav1.IsControlledBy(o, iObj))
+		assert.EqualValues(t, "inference.networking.k8s.io", o.Spec.PoolRef.Group)
+		assert.EqualValues(t, "InferencePool", o.Spec.PoolRef.Kind)
+		assert.EqualValues(t, "phi-inferencepool", o.Spec.PoolRef.Name)
+	}
+}
+
+func TestGenerateInferencePoolHelmReleaseTrafficClasses(t *testing.T) {
+	envOf := func(iObj *kaitov1beta1.InferenceSet) map[string]string {
+		helmRelease, err := GenerateInferencePoolHelmRelease(iObj)
+		require.NoError(t, err)
+		var vals struct {
+			InferenceExtension struct {
+				Env []map[string]string `json:"env"`
+			} `json:"inferenceExtension"`
+		}
+		require.NoError(t, json.Unmarshal(helmRelease.Spec.Values.Raw, &vals))
+		env := map[string]string{}
+		for _, e := range vals.InferenceExtension.Env {
+			env[e["name"]] = e["value"]
+		}
+		return env
+	}
+
+	iObj := test.MockInferenceSetWithPreset.DeepCopy()
+	assert.Empty(t, envOf(iObj))
+
+	iObj.Spec.TrafficClasses = &kaitov1beta1.TrafficClassesSpec{}
+	assert.Equal(t, map[string]string{
+		"ENABLE_EXPERIMENTAL_FLOW_CONTROL_LAYER": "true",
+		"SD_QUEUE_DEPTH_THRESHOLD":               "5",
+		"SD_KV_CACHE_UTIL_THRESHOLD":             "0.80",
+	}, envOf(iObj))
+
+	iObj.Spec.TrafficClasses.MaxWaitingRequestsPerReplica = ptr.To(int32(12))
+	iObj.Spec.TrafficClasses.MaxKVCacheUtilizationPercent = ptr.To(int32(95))
+	env := envOf(iObj)
+	assert.Equal(t, "12", env["SD_QUEUE_DEPTH_THRESHOLD"])
+	assert.Equal(t, "0.95", env["SD_KV_CACHE_UTIL_THRESHOLD"])
+}
@@ -40,7 +40,7 @@ func NewNodeManager(c client.Client) *NodeManager {
 // CheckIfNodePluginsReady is used for ensuring node label(accelerator:nvidia) and GPU capacity on all auto-provisioned nodes for the workspace.
 func (c *NodeManager) CheckIfNodePluginsReady(ctx context.Context, wObj *kaitov1beta1.Workspace, existingNodeClaims []*karpenterv1.NodeClaim) (bool, error) {
 	// ensure Nvidia device plugins are ready for the workspace when instance type is known.
-	knownGPUConfig, _ := sku.GetGPUConfigBySKU(wObj.Resource.InstanceType)
+	knownGPUConfig, _ := sku.GetGPUConfigBySKU(wObj.EffectiveInstanceType())
 	if knownGPUConfig != nil {
 		if areReady, err := c.checkNodePlugin(ctx, wObj, existingNodeClaims); err != nil {
 			return false, err
@@ -81,8 +81,8 @@ func (c *NodeManager) checkNodePlugin(ctx context.Context, wObj *kaitov1beta1.Wo
 			return false, nil
 		}
 
-		if node.Labels[corev1.LabelInstanceTypeStable] != wObj.Resource.InstanceType {
-			klog.Infof("node plugins not ready, %s instance type label %s does not match workspace instance type %s", node.Name, node.Labels[corev1.LabelInstanceTypeStable], wObj.Resource.InstanceType)
+		if node.Labels[corev1.LabelInstanceTypeStable] != wObj.EffectiveInstanceType() {
+			klog.Infof("node plugins not ready, %s instance type label %s does not match workspace instance type %s", node.Name, node.Labels[corev1.LabelInstanceTypeStable], wObj.EffectiveInstanceType())
 			return false, nil
 		}
 	}
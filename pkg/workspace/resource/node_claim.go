@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -54,6 +55,13 @@ func (c *NodeClaimManager) SetDefaultNodeImageFamily(defaultNodeImageFamily stri
 
 // GetNumNodeClaimsNeeded calculates how many NodeClaims are needed to meet the target node count for the workspace.
 func (c *NodeClaimManager) GetNumNodeClaimsNeeded(ctx context.Context, wObj *kaitov1beta1.Workspace, readyNodes []*corev1.Node) int {
+	return NumNodeClaimsNeeded(wObj, readyNodes)
+}
+
+// NumNodeClaimsNeeded returns how many NodeClaims the workspace needs in total, existing ones
+// included, given its ready nodes. Ready nodes without a NodeClaim, e.g. BYO nodes, count
+// toward the target node count.
+func NumNodeClaimsNeeded(wObj *kaitov1beta1.Workspace, readyNodes []*corev1.Node) int {
 	targetNodeCount := int(wObj.Status.TargetNodeCount)
 
 	// Count ready nodes that do NOT have a corresponding NodeClaim
@@ -103,21 +111,49 @@ func (c *NodeClaimManager) CreateUpNodeClaims(ctx context.Context, wObj *kaitov1
 		return nil
 	}
 
+	// Names are generated per NodeClaim unless the workspace asks for indexed names.
+	names := make([]string, nodesToCreate)
+	if wObj.Annotations[kaitov1beta1.AnnotationNodeClaimNaming] == kaitov1beta1.NodeClaimNamingWorkspaceIndex {
+		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return fmt.Errorf("failed to get existing NodeClaims: %w", err)
+		}
+		existing := make([]*karpenterv1.NodeClaim, 0, len(ncList.Items))
+		for i := range ncList.Items {
+			existing = append(existing, &ncList.Items[i])
+		}
+		names = nodeclaim.IndexedNodeClaimNames(wObj.Namespace, wObj.Name, existing, nodesToCreate)
+	}
+
 	klog.InfoS("Creating additional NodeClaims", "workspace", workspaceKey, "toCreate", nodesToCreate)
 	c.expectations.ExpectCreations(c.logger, workspaceKey, nodesToCreate)
 
 	nodeOSDiskSize := c.determineNodeOSDiskSize(ctx, wObj)
 
-	for range nodesToCreate {
+	for i := range nodesToCreate {
 		var nodeClaim *karpenterv1.NodeClaim
 
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			nodeClaim = nodeclaim.GenerateNodeClaimManifestWithOptions(nodeOSDiskSize, wObj, nodeclaim.ManifestOptions{
 				DefaultNodeImageFamily: c.defaultNodeImageFamily,
+				Name:                   names[i],
 			})
 			return c.Client.Create(ctx, nodeClaim)
 		})
 
+		if apierrors.IsAlreadyExists(err) && names[i] != "" {
+			// An indexed name may already be taken, either by a NodeClaim of this workspace
+			// the cache has not seen yet or by an unrelated one. Neither is created here.
+			c.expectations.CreationObserved(c.logger, workspaceKey)
+			current := &karpenterv1.NodeClaim{}
+			if getErr := c.Client.Get(ctx, client.ObjectKey{Name: names[i]}, current); getErr == nil && nodeclaim.IsOwnedBy(current, wObj) {
+				klog.InfoS("NodeClaim already exists", "nodeClaim", names[i], "workspace", workspaceKey)
+				continue
+			}
+			c.recorder.Eventf(wObj, "Warning", "NodeClaimNameConflict", "NodeClaim %s already exists and does not belong to workspace %s", names[i], workspaceKey)
+			continue
+		}
+
 		if err != nil {
 			// Failed to create, decrement expectations
 			c.expectations.CreationObserved(c.logger, workspaceKey)
@@ -128,7 +164,7 @@ func (c *NodeClaimManager) CreateUpNodeClaims(ctx context.Context, wObj *kaitov1
 		klog.InfoS("NodeClaim created successfully", "nodeClaim", nodeClaim.Name, "workspace", workspaceKey)
 
 		c.recorder.Eventf(wObj, "Normal", "NodeClaimCreated",
-			"Successfully created NodeClaim %s for workspace %s", nodeClaim.Name, workspaceKey)
+			"Successfully created NodeClaim %s for workspace %s with requirements: %s", nodeClaim.Name, workspaceKey, nodeclaim.DescribeRequirements(nodeClaim))
 	}
 	return nil
 }
@@ -162,6 +198,9 @@ func (c *NodeClaimManager) EnsureNodeClaimsReady(ctx context.Context, wObj *kait
 
 // determineNodeOSDiskSize returns the appropriate OS disk size for the workspace
 func (c *NodeClaimManager) determineNodeOSDiskSize(ctx context.Context, wObj *kaitov1beta1.Workspace) string {
+	if storage := wObj.Resource.Storage; storage != nil && storage.NodeDiskSize != nil {
+		return storage.NodeDiskSize.String()
+	}
 	var nodeOSDiskSize string
 	if wObj.Inference != nil && wObj.Inference.Preset != nil && wObj.Inference.Preset.Name != "" {
 		presetName := string(wObj.Inference.Preset.Name)
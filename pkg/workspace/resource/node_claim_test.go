@@ -23,8 +23,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
@@ -32,6 +36,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -306,6 +311,8 @@ func setupWorkspaceStatusMock(mockClient *test.MockClient, workspace *kaitov1bet
 }
 
 func TestCreateNodeClaims(t *testing.T) {
+	indexedPrefix := strings.TrimSuffix(nodeclaim.IndexedNodeClaimNames("default", "test-workspace", nil, 1)[0], "-0")
+
 	// Helper function to setup common mocks
 	setupBaseMocks := func(mockClient *test.MockClient) {
 		_ = mockClient
@@ -418,6 +425,83 @@ func TestCreateNodeClaims(t *testing.T) {
 			expectedEvents: []string{"NodeClaimCreated", "NodeClaimCreationFailed"},
 			presetWithDisk: false,
 		},
+		{
+			name: "indexed names skip existing nodeclaims",
+			workspace: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default",
+					Annotations: map[string]string{kaitov1beta1.AnnotationNodeClaimNaming: kaitov1beta1.NodeClaimNamingWorkspaceIndex}},
+				Resource: kaitov1beta1.ResourceSpec{
+					LabelSelector: &metav1.LabelSelector{},
+				},
+			},
+			nodesToCreate: 2,
+			setupMocks: func(mockClient *test.MockClient) {
+				setupBaseMocks(mockClient)
+				mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Run(func(args mock.Arguments) {
+					list := args.Get(1).(*karpenterv1.NodeClaimList)
+					list.Items = []karpenterv1.NodeClaim{{ObjectMeta: metav1.ObjectMeta{Name: indexedPrefix + "-0"}}}
+				}).Return(nil)
+				mockClient.On("Create", mock.IsType(context.Background()), mock.MatchedBy(func(nc *karpenterv1.NodeClaim) bool {
+					return nc.Name == indexedPrefix+"-1"
+				}), mock.Anything).Return(nil).Once()
+				mockClient.On("Create", mock.IsType(context.Background()), mock.MatchedBy(func(nc *karpenterv1.NodeClaim) bool {
+					return nc.Name == indexedPrefix+"-2"
+				}), mock.Anything).Return(nil).Once()
+			},
+			expectedError:  "",
+			expectedEvents: []string{"NodeClaimCreated", "NodeClaimCreated"},
+			presetWithDisk: false,
+		},
+		{
+			name: "indexed name taken by another workspace",
+			workspace: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default",
+					Annotations: map[string]string{kaitov1beta1.AnnotationNodeClaimNaming: kaitov1beta1.NodeClaimNamingWorkspaceIndex}},
+				Resource: kaitov1beta1.ResourceSpec{
+					LabelSelector: &metav1.LabelSelector{},
+				},
+			},
+			nodesToCreate: 1,
+			setupMocks: func(mockClient *test.MockClient) {
+				setupBaseMocks(mockClient)
+				mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+				mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).
+					Return(apierrors.NewAlreadyExists(schema.GroupResource{Group: "karpenter.sh", Resource: "nodeclaims"}, indexedPrefix+"-0")).Once()
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).Run(func(args mock.Arguments) {
+					nc := args.Get(2).(*karpenterv1.NodeClaim)
+					nc.Name = indexedPrefix + "-0"
+					nc.Labels = map[string]string{kaitov1beta1.LabelWorkspaceName: "test-workspace", kaitov1beta1.LabelWorkspaceNamespace: "other"}
+				}).Return(nil)
+			},
+			expectedError:  "",
+			expectedEvents: []string{"NodeClaimNameConflict"},
+			presetWithDisk: false,
+		},
+		{
+			name: "indexed name already created for this workspace",
+			workspace: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default",
+					Annotations: map[string]string{kaitov1beta1.AnnotationNodeClaimNaming: kaitov1beta1.NodeClaimNamingWorkspaceIndex}},
+				Resource: kaitov1beta1.ResourceSpec{
+					LabelSelector: &metav1.LabelSelector{},
+				},
+			},
+			nodesToCreate: 1,
+			setupMocks: func(mockClient *test.MockClient) {
+				setupBaseMocks(mockClient)
+				mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+				mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).
+					Return(apierrors.NewAlreadyExists(schema.GroupResource{Group: "karpenter.sh", Resource: "nodeclaims"}, indexedPrefix+"-0")).Once()
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).Run(func(args mock.Arguments) {
+					nc := args.Get(2).(*karpenterv1.NodeClaim)
+					nc.Name = indexedPrefix + "-0"
+					nc.Labels = map[string]string{kaitov1beta1.LabelWorkspaceName: "test-workspace", kaitov1beta1.LabelWorkspaceNamespace: "default"}
+				}).Return(nil)
+			},
+			expectedError:  "",
+			expectedEvents: []string{},
+			presetWithDisk: false,
+		},
 		{
 			name: "zero nodes to create",
 			workspace: &kaitov1beta1.Workspace{
@@ -846,6 +930,24 @@ func TestDetermineNodeOSDiskSize(t *testing.T) {
 			},
 			expectedDiskSize: "1024Gi",
 		},
+		{
+			name: "Should return the node disk size from the storage spec when set",
+			workspace: &kaitov1beta1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+				Resource: kaitov1beta1.ResourceSpec{
+					LabelSelector: &metav1.LabelSelector{},
+					Storage: &kaitov1beta1.WorkloadStorageSpec{
+						NodeDiskSize: ptr.To(resource.MustParse("200Gi")),
+					},
+				},
+				Inference: &kaitov1beta1.InferenceSpec{
+					Preset: &kaitov1beta1.PresetSpec{
+						PresetMeta: kaitov1beta1.PresetMeta{Name: "test-model"},
+					},
+				},
+			},
+			expectedDiskSize: "200Gi",
+		},
 	}
 
 	for _, tt := range tests {
@@ -18,6 +18,7 @@ import (
 	_ "embed"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/imageverify"
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
@@ -43,6 +45,10 @@ import (
 const (
 	DefaultBaseDir          = "/mnt"
 	DefaultOutputVolumePath = "/mnt/output"
+
+	// KeepLastCheckpointsEnvName tells the trainer how many intermediate checkpoints to
+	// keep in the output directory once tuning succeeds.
+	KeepLastCheckpointsEnvName = "KEEP_LAST_CHECKPOINTS"
 )
 
 var (
@@ -145,7 +151,7 @@ func GetTrainingOutputDir(ctx context.Context, configMap *corev1.ConfigMap) (str
 func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspace, revisionNum string,
 	model pkgmodel.Model, kubeClient client.Client) (client.Object, error) {
 
-	gpuConfig, err := sku.GetGPUConfigBySKU(workspaceObj.Resource.InstanceType)
+	gpuConfig, err := sku.GetGPUConfigBySKU(workspaceObj.EffectiveInstanceType())
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +169,11 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 		SetTrainingResultVolume,
 		SetTrainingInput,
 		SetTrainingOutputImagePush,
+		manifests.SetConfidentialCompute(workspaceObj.Resource.ConfidentialCompute),
+		manifests.SetPlacement,
+		manifests.SetProxy(workspaceObj.Tuning.Preset.Proxy),
+		manifests.SetWorkloadIdentity,
+		manifests.SetServiceAccount,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate pod spec: %w", err)
@@ -171,6 +182,7 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 	jobObj, err := generator.GenerateManifest(gctx,
 		manifests.GenerateTuningJobManifest(revisionNum),
 		manifests.SetJobPodSpec(podSpec),
+		manifests.SetJobGangScheduling,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate job manifest: %w", err)
@@ -226,6 +238,12 @@ func GenerateBasicTuningPodSpec(skuNumGPUs int) func(*generator.WorkspaceGenerat
 			Name:  "PYTORCH_CUDA_ALLOC_CONF",
 			Value: "expandable_segments:True",
 		})
+		if retention := ctx.Workspace.Tuning.Retention; retention != nil && retention.KeepLastN != nil {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  KeepLastCheckpointsEnvName,
+				Value: strconv.Itoa(int(*retention.KeepLastN)),
+			})
+		}
 
 		// tuning commands
 		tuningParam := ctx.Model.GetTuningParameters().DeepCopy()
@@ -238,7 +256,7 @@ func GenerateBasicTuningPodSpec(skuNumGPUs int) func(*generator.WorkspaceGenerat
 		spec.Containers = []corev1.Container{
 			{
 				Name:         ctx.Workspace.Name,
-				Image:        GetTuningImageInfo(),
+				Image:        imageverify.Pin(GetTuningImageInfo()),
 				Command:      commands,
 				Resources:    resourceRequirements,
 				Ports:        containerPorts,
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -640,6 +641,27 @@ func TestGenerateBasicTuningPodSpec_NodeAffinity(t *testing.T) {
 	}
 }
 
+func TestGenerateBasicTuningPodSpec_KeepLastCheckpoints(t *testing.T) {
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Tuning: &kaitov1beta1.TuningSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "test-model"}},
+		},
+	}
+	gctx := &generator.WorkspaceGeneratorContext{Ctx: context.Background(), Workspace: workspace, Model: &mockModel{}}
+
+	podSpec := &corev1.PodSpec{}
+	assert.NoError(t, GenerateBasicTuningPodSpec(1)(gctx, podSpec))
+	for _, env := range podSpec.Containers[0].Env {
+		assert.NotEqual(t, KeepLastCheckpointsEnvName, env.Name)
+	}
+
+	workspace.Tuning.Retention = &kaitov1beta1.TuningRetentionSpec{KeepLastN: ptr.To[int32](2)}
+	podSpec = &corev1.PodSpec{}
+	assert.NoError(t, GenerateBasicTuningPodSpec(1)(gctx, podSpec))
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: KeepLastCheckpointsEnvName, Value: "2"})
+}
+
 func TestDefaultTolerations(t *testing.T) {
 	testcases := map[string]struct {
 		cloudProvider string
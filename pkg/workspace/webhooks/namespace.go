@@ -0,0 +1,122 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+)
+
+var namespaceGVK = corev1.SchemeGroupVersion.WithKind("Namespace")
+
+// NewNamespaceDeletionValidationWebhook rejects the deletion of namespaces whose workspaces
+// or RAGEngines still own NodeClaims. The controller releases NodeClaims from the
+// finalizers of their owners, which races namespace teardown; deleting the owners first
+// guarantees that no cloud node is stranded.
+func NewNamespaceDeletionValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	kubeClient := k8sclient.FromContext(ctx)
+	return validation.NewAdmissionController(ctx,
+		NamespaceValidationWebhookName,
+		"/validate/namespace.kaito.sh",
+		NamespaceResources,
+		k8sclient.Decorator(ctx),
+		false,
+		map[schema.GroupVersionKind]validation.Callback{
+			namespaceGVK: validation.NewCallback(func(ctx context.Context, ns *unstructured.Unstructured) error {
+				return validateNamespaceDeletion(ctx, kubeClient, ns)
+			}, webhook.Delete),
+		},
+	)
+}
+
+var NamespaceResources = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+	namespaceGVK: &deletionGuardedNamespace{},
+}
+
+// validateNamespaceDeletion returns an error listing the NodeClaims that KAITO still
+// manages for resources in the namespace.
+func validateNamespaceDeletion(ctx context.Context, kubeClient client.Client, ns *unstructured.Unstructured) error {
+	if kubeClient == nil || ns.GetAnnotations()[kaitov1beta1.AnnotationAllowNamespaceDeletion] == "true" {
+		return nil
+	}
+
+	var owned []string
+	for _, label := range []string{kaitov1beta1.LabelWorkspaceNamespace, kaitov1beta1.LabelRAGEngineNamespace} {
+		nodeClaims := &karpenterv1.NodeClaimList{}
+		if err := kubeClient.List(ctx, nodeClaims, client.MatchingLabels{label: ns.GetName()}); err != nil {
+			if meta.IsNoMatchError(err) {
+				// No node provisioner is installed, so there is nothing to strand.
+				return nil
+			}
+			return fmt.Errorf("failed to list NodeClaims of namespace %s: %w", ns.GetName(), err)
+		}
+		for _, nc := range nodeClaims.Items {
+			owned = append(owned, nc.Name)
+		}
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+
+	sort.Strings(owned)
+	klog.InfoS("Rejecting namespace deletion", "namespace", ns.GetName(), "nodeClaims", owned)
+	return fmt.Errorf("namespace %s has workspaces or RAGEngines that still own NodeClaims (%s); "+
+		"delete them and wait for their nodes to be released before deleting the namespace, "+
+		"or annotate the namespace with %s=true to skip this check",
+		ns.GetName(), strings.Join(owned, ", "), kaitov1beta1.AnnotationAllowNamespaceDeletion)
+}
+
+// deletionGuardedNamespace registers namespaces with the validation admission controller.
+// Only deletions are intercepted, and they are checked by the callback of
+// NewNamespaceDeletionValidationWebhook.
+type deletionGuardedNamespace struct {
+	corev1.Namespace
+}
+
+func (n *deletionGuardedNamespace) SetDefaults(context.Context) {}
+
+func (n *deletionGuardedNamespace) Validate(context.Context) *apis.FieldError { return nil }
+
+func (n *deletionGuardedNamespace) SupportedVerbs() []admissionregistrationv1.OperationType {
+	return []admissionregistrationv1.OperationType{admissionregistrationv1.Delete}
+}
+
+func (n *deletionGuardedNamespace) SupportedSubResources() []string {
+	return []string{""}
+}
+
+func (n *deletionGuardedNamespace) DeepCopyObject() runtime.Object {
+	return &deletionGuardedNamespace{Namespace: *n.Namespace.DeepCopy()}
+}
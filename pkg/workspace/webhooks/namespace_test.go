@@ -0,0 +1,72 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	karpenterutils "github.com/kaito-project/kaito/pkg/utils/karpenter"
+)
+
+func TestValidateNamespaceDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, karpenterutils.KarpenterSchemeBuilder.AddToScheme(scheme))
+
+	nodeClaim := func(name, label, namespace string) *karpenterv1.NodeClaim {
+		return &karpenterv1.NodeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{label: namespace}}}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		nodeClaim("ws-nc", kaitov1beta1.LabelWorkspaceNamespace, "team-a"),
+		nodeClaim("rag-nc", kaitov1beta1.LabelRAGEngineNamespace, "team-a"),
+		nodeClaim("other-nc", kaitov1beta1.LabelWorkspaceNamespace, "team-b"),
+	).Build()
+
+	namespace := func(name string, annotations map[string]string) *unstructured.Unstructured {
+		ns := &unstructured.Unstructured{}
+		ns.SetName(name)
+		ns.SetAnnotations(annotations)
+		return ns
+	}
+
+	err := validateNamespaceDeletion(context.Background(), cl, namespace("team-a", nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rag-nc, ws-nc")
+	assert.NotContains(t, err.Error(), "other-nc")
+
+	assert.NoError(t, validateNamespaceDeletion(context.Background(), cl, namespace("team-c", nil)))
+	assert.NoError(t, validateNamespaceDeletion(context.Background(), cl,
+		namespace("team-a", map[string]string{kaitov1beta1.AnnotationAllowNamespaceDeletion: "true"})))
+
+	// Without the NodeClaim CRD there are no cloud resources to protect.
+	noCRD := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "karpenter.sh", Kind: "NodeClaim"}}
+		},
+	}).Build()
+	assert.NoError(t, validateNamespaceDeletion(context.Background(), noCRD, namespace("team-a", nil)))
+}
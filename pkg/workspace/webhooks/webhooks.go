@@ -20,19 +20,31 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	knativeinjection "knative.dev/pkg/injection"
-	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/resourcesemantics"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/webhookcert"
+)
+
+// Names of the validating webhooks, which are also the names of their
+// ValidatingWebhookConfigurations.
+const (
+	WorkspaceValidationWebhookName          = "validation.workspace.kaito.sh"
+	InferenceSetValidationWebhookName       = "validation.inferenceset.kaito.sh"
+	MultiRoleInferenceValidationWebhookName = "validation.multiroleinference.kaito.sh"
+	ModelMirrorValidationWebhookName        = "validation.modelmirror.kaito.sh"
+	BatchInferenceValidationWebhookName     = "validation.batchinference.kaito.sh"
+	NamespaceValidationWebhookName          = "validation.namespace.kaito.sh"
 )
 
 func NewControllerWebhooks() []knativeinjection.ControllerConstructor {
 	constructor := []knativeinjection.ControllerConstructor{
-		certificates.NewController,
+		webhookcert.NewController(ValidationWebhookNames()...),
 		NewWorkspaceCRDValidationWebhook,
 	}
 
@@ -45,36 +57,68 @@ func NewControllerWebhooks() []knativeinjection.ControllerConstructor {
 	if featuregates.FeatureGates[consts.FeatureFlagModelMirror] {
 		constructor = append(constructor, NewModelMirrorCRDValidationWebhook)
 	}
+	if featuregates.FeatureGates[consts.FeatureFlagBatchInference] {
+		constructor = append(constructor, NewBatchInferenceCRDValidationWebhook)
+	}
+	if namespaceDeletionProtectionEnabled() {
+		constructor = append(constructor, NewNamespaceDeletionValidationWebhook)
+	}
 
 	return constructor
 }
 
+// ValidationWebhookNames returns the names of the webhooks enabled by the feature gates.
+func ValidationWebhookNames() []string {
+	names := []string{WorkspaceValidationWebhookName}
+	if featuregates.FeatureGates[consts.FeatureFlagEnableInferenceSetController] {
+		names = append(names, InferenceSetValidationWebhookName)
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagEnableMultiRoleInferenceController] {
+		names = append(names, MultiRoleInferenceValidationWebhookName)
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagModelMirror] {
+		names = append(names, ModelMirrorValidationWebhookName)
+	}
+	if featuregates.FeatureGates[consts.FeatureFlagBatchInference] {
+		names = append(names, BatchInferenceValidationWebhookName)
+	}
+	if namespaceDeletionProtectionEnabled() {
+		names = append(names, NamespaceValidationWebhookName)
+	}
+	return names
+}
+
+func namespaceDeletionProtectionEnabled() bool {
+	return featuregates.FeatureGates[consts.FeatureFlagNamespaceDeletionProtection] &&
+		!featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning]
+}
+
 func NewWorkspaceCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
-		"validation.workspace.kaito.sh",
+		WorkspaceValidationWebhookName,
 		"/validate/workspace.kaito.sh",
 		WorkspaceResources,
-		func(ctx context.Context) context.Context { return ctx },
+		k8sclient.Decorator(ctx),
 		true,
 	)
 }
 
 func NewInferenceSetCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
-		"validation.inferenceset.kaito.sh",
+		InferenceSetValidationWebhookName,
 		"/validate/inferenceset.kaito.sh",
 		InferenceSetResources,
-		func(ctx context.Context) context.Context { return ctx },
+		k8sclient.Decorator(ctx),
 		true,
 	)
 }
 
 func NewMultiRoleInferenceCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
-		"validation.multiroleinference.kaito.sh",
+		MultiRoleInferenceValidationWebhookName,
 		"/validate/multiroleinference.kaito.sh",
 		MultiRoleInferenceResources,
-		func(ctx context.Context) context.Context { return ctx },
+		k8sclient.Decorator(ctx),
 		true,
 	)
 }
@@ -95,10 +139,10 @@ var MultiRoleInferenceResources = map[schema.GroupVersionKind]resourcesemantics.
 
 func NewModelMirrorCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
-		"validation.modelmirror.kaito.sh",
+		ModelMirrorValidationWebhookName,
 		"/validate/modelmirror.kaito.sh",
 		ModelMirrorResources,
-		func(ctx context.Context) context.Context { return ctx },
+		k8sclient.Decorator(ctx),
 		true,
 	)
 }
@@ -106,3 +150,17 @@ func NewModelMirrorCRDValidationWebhook(ctx context.Context, _ configmap.Watcher
 var ModelMirrorResources = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	kaitov1alpha1.GroupVersion.WithKind("ModelMirror"): &kaitov1alpha1.ModelMirror{},
 }
+
+func NewBatchInferenceCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return validation.NewAdmissionController(ctx,
+		BatchInferenceValidationWebhookName,
+		"/validate/batchinference.kaito.sh",
+		BatchInferenceResources,
+		k8sclient.Decorator(ctx),
+		true,
+	)
+}
+
+var BatchInferenceResources = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+	kaitov1alpha1.GroupVersion.WithKind("BatchInference"): &kaitov1alpha1.BatchInference{},
+}
@@ -0,0 +1,292 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Per-index authorization for the RAG service.
+
+The controller renders the policy from spec.authorization of the RAGEngine into
+a mounted Secret file:
+
+    {"indexes": {"<index>": {"apiKeySHA256": ["<hex>"], "serviceAccounts": ["<ns>/<name>"]}},
+     "admins": {"apiKeySHA256": ["<hex>"], "serviceAccounts": ["<ns>/<name>"]}}
+
+Only SHA-256 digests of the API keys are mounted. Service account tokens are
+verified with a Kubernetes TokenReview. The file is reloaded when it changes, so
+key rotation does not require a restart. Indexes missing from the policy cannot
+be accessed. Requests that do not name an index, such as /backup, are denied
+unless the credential is listed under "admins". Admins may access every index.
+
+The lifecycle hooks running in the same container authenticate with an internal
+token that the service generates at startup and writes to a file readable only
+by the container user.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import hmac
+import json
+import logging
+import os
+import secrets
+import threading
+import time
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass, field
+
+import httpx
+from fastapi import HTTPException
+
+logger = logging.getLogger(__name__)
+
+SERVICE_ACCOUNT_DIR = "/var/run/secrets/kubernetes.io/serviceaccount"
+SERVICE_ACCOUNT_PREFIX = "system:serviceaccount:"
+TOKEN_REVIEW_CACHE_SECONDS = 60.0
+
+# TokenReviewer maps a bearer token to "<namespace>/<name>" of the service
+# account it belongs to, or None when the token is not a valid service account token.
+TokenReviewer = Callable[[str], Awaitable[str | None]]
+
+
+@dataclass(frozen=True)
+class IndexAccess:
+    api_key_digests: frozenset[str] = field(default_factory=frozenset)
+    service_accounts: frozenset[str] = field(default_factory=frozenset)
+
+
+def _digest(token: str) -> str:
+    return hashlib.sha256(token.encode()).hexdigest()
+
+
+@dataclass(frozen=True)
+class Policy:
+    indexes: dict[str, IndexAccess] = field(default_factory=dict)
+    admins: IndexAccess = field(default_factory=IndexAccess)
+
+
+def _parse_access(access: dict | None) -> IndexAccess:
+    access = access or {}
+    if not isinstance(access, dict):
+        raise ValueError("access entries must be objects")
+    return IndexAccess(
+        api_key_digests=frozenset(access.get("apiKeySHA256") or []),
+        service_accounts=frozenset(access.get("serviceAccounts") or []),
+    )
+
+
+def parse_policy(raw: str) -> Policy:
+    """Parse the rendered policy file."""
+    data = json.loads(raw)
+    indexes = data.get("indexes") or {}
+    if not isinstance(indexes, dict):
+        raise ValueError("'indexes' must be an object")
+    return Policy(
+        indexes={name: _parse_access(access) for name, access in indexes.items()},
+        admins=_parse_access(data.get("admins")),
+    )
+
+
+class KubernetesTokenReviewer:
+    """Verifies service account tokens with the TokenReview API.
+
+    The controller runs the RAG pod as a service account bound to the
+    system:auth-delegator ClusterRole, which allows creating tokenreviews.
+    """
+
+    def __init__(self, sa_dir: str = SERVICE_ACCOUNT_DIR) -> None:
+        self._sa_dir = sa_dir
+        self._cache: dict[str, tuple[float, str | None]] = {}
+        self._lock = threading.Lock()
+
+    async def __call__(self, token: str) -> str | None:
+        key = _digest(token)
+        now = time.monotonic()
+        with self._lock:
+            cached = self._cache.get(key)
+        if cached and now - cached[0] < TOKEN_REVIEW_CACHE_SECONDS:
+            return cached[1]
+
+        identity = await self._review(token)
+        with self._lock:
+            self._cache[key] = (now, identity)
+            # Drop expired entries so the cache stays bounded by the active clients.
+            for k in [
+                k
+                for k, (ts, _) in self._cache.items()
+                if now - ts >= TOKEN_REVIEW_CACHE_SECONDS
+            ]:
+                del self._cache[k]
+        return identity
+
+    async def _review(self, token: str) -> str | None:
+        host = os.getenv("KUBERNETES_SERVICE_HOST")
+        port = os.getenv("KUBERNETES_SERVICE_PORT", "443")
+        if not host:
+            logger.warning("TokenReview unavailable: not running in a cluster")
+            return None
+        try:
+            with open(os.path.join(self._sa_dir, "token")) as f:
+                own_token = f.read().strip()
+            async with httpx.AsyncClient(
+                verify=os.path.join(self._sa_dir, "ca.crt"), timeout=5.0
+            ) as client:
+                resp = await client.post(
+                    f"https://{host}:{port}/apis/authentication.k8s.io/v1/tokenreviews",
+                    headers={"Authorization": f"Bearer {own_token}"},
+                    json={
+                        "apiVersion": "authentication.k8s.io/v1",
+                        "kind": "TokenReview",
+                        "spec": {"token": token},
+                    },
+                )
+            resp.raise_for_status()
+            status = resp.json().get("status") or {}
+        except Exception:
+            logger.error("TokenReview failed", exc_info=True)
+            return None
+
+        username = (status.get("user") or {}).get("username", "")
+        if not status.get("authenticated") or not username.startswith(
+            SERVICE_ACCOUNT_PREFIX
+        ):
+            return None
+        namespace, _, name = username[len(SERVICE_ACCOUNT_PREFIX) :].partition(":")
+        return f"{namespace}/{name}" if namespace and name else None
+
+
+class IndexAuthorizer:
+    """Checks bearer credentials against the per-index policy file."""
+
+    def __init__(
+        self, policy_path: str, token_reviewer: TokenReviewer | None = None
+    ) -> None:
+        self._policy_path = policy_path
+        self._token_reviewer = token_reviewer or KubernetesTokenReviewer()
+        self._lock = threading.Lock()
+        self._mtime: float | None = None
+        self._policy = Policy()
+        self._internal_digest: str | None = None
+
+    @property
+    def enabled(self) -> bool:
+        return bool(self._policy_path)
+
+    def write_internal_token(self, path: str) -> None:
+        """Generate the internal token that grants access to every index and
+        write it to path for the lifecycle hooks."""
+        token = secrets.token_urlsafe(32)
+        fd = os.open(path, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0o600)
+        with os.fdopen(fd, "w") as f:
+            f.write(token)
+        self._internal_digest = _digest(token)
+
+    def _current_policy(self) -> Policy:
+        try:
+            mtime = os.stat(self._policy_path).st_mtime
+        except OSError:
+            logger.error(
+                "Index authorization policy %s is missing, denying all index access",
+                self._policy_path,
+            )
+            return Policy()
+        with self._lock:
+            if mtime != self._mtime:
+                try:
+                    with open(self._policy_path) as f:
+                        self._policy = parse_policy(f.read())
+                    logger.info("Loaded index authorization policy")
+                except Exception:
+                    # Keep serving with the last valid policy.
+                    logger.error(
+                        "Failed to load index authorization policy", exc_info=True
+                    )
+                self._mtime = mtime
+            return self._policy
+
+    async def _identity(
+        self, authorization: str | None, policy: Policy
+    ) -> tuple[str | None, str | None]:
+        """Return (api key digest, service account) for the bearer credential."""
+        scheme, _, token = (authorization or "").partition(" ")
+        token = token.strip()
+        if scheme.lower() != "bearer" or not token:
+            raise HTTPException(
+                status_code=401,
+                detail="Missing bearer credential",
+                headers={"WWW-Authenticate": "Bearer"},
+            )
+        digest = _digest(token)
+        if self._internal_digest and hmac.compare_digest(self._internal_digest, digest):
+            return digest, None
+        for access in [policy.admins, *policy.indexes.values()]:
+            for allowed in access.api_key_digests:
+                if hmac.compare_digest(allowed, digest):
+                    return digest, None
+        service_account = await self._token_reviewer(token)
+        if service_account is None:
+            raise HTTPException(
+                status_code=401,
+                detail="Invalid bearer credential",
+                headers={"WWW-Authenticate": "Bearer"},
+            )
+        return None, service_account
+
+    @staticmethod
+    def _granted(
+        access: IndexAccess | None, digest: str | None, sa: str | None
+    ) -> bool:
+        if access is None:
+            return False
+        if digest is not None:
+            return digest in access.api_key_digests
+        return sa in access.service_accounts
+
+    def _allows(
+        self, policy: Policy, index_name: str | None, digest: str | None, sa: str | None
+    ) -> bool:
+        if digest is not None and digest == self._internal_digest:
+            return True
+        if self._granted(policy.admins, digest, sa):
+            return True
+        if index_name is None:
+            return False
+        return self._granted(policy.indexes.get(index_name), digest, sa)
+
+    async def authorize(self, index_name: str | None, authorization: str | None) -> None:
+        """Raise 401 for missing or unknown credentials and 403 when the
+        credential is not allowed for index_name. Requests that do not name an
+        index are only allowed for admins."""
+        if not self.enabled:
+            return
+        policy = self._current_policy()
+        digest, sa = await self._identity(authorization, policy)
+        if self._allows(policy, index_name, digest, sa):
+            return
+        if index_name is None:
+            raise HTTPException(
+                status_code=403,
+                detail="Requests that do not name an index require an admin credential",
+            )
+        raise HTTPException(
+            status_code=403,
+            detail=f"Access to index '{index_name}' is not allowed",
+        )
+
+    async def filter_indexes(
+        self, index_names: list[str], authorization: str | None
+    ) -> list[str]:
+        """Return the subset of index_names the credential may access."""
+        if not self.enabled:
+            return index_names
+        policy = self._current_policy()
+        digest, sa = await self._identity(authorization, policy)
+        return [n for n in index_names if self._allows(policy, n, digest, sa)]
@@ -0,0 +1,59 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Packing of index snapshots into backup archives.
+
+A snapshot directory has the layout written by the PreStop lifecycle hook: one
+directory per index and a metadata.json listing the index names.
+"""
+
+from __future__ import annotations
+
+import json
+import tarfile
+from pathlib import Path
+
+METADATA_FILE = "metadata.json"
+
+
+def pack_snapshot(snapshot_dir: str, archive_path: str) -> None:
+    with tarfile.open(archive_path, "w:gz") as tar:
+        for entry in sorted(Path(snapshot_dir).iterdir()):
+            tar.add(entry, arcname=entry.name)
+
+
+def unpack_snapshot(archive_path: str, snapshot_dir: str) -> list[str]:
+    """Extract a backup archive and return the index names it contains.
+
+    Archives come from object storage, so members that would escape snapshot_dir,
+    links and special files are rejected.
+    """
+    Path(snapshot_dir).mkdir(parents=True, exist_ok=True)
+    with tarfile.open(archive_path, "r:gz") as tar:
+        tar.extractall(snapshot_dir, filter="data")
+    return read_index_names(snapshot_dir)
+
+
+def read_index_names(snapshot_dir: str) -> list[str]:
+    """Return the index names listed in the snapshot metadata. Names that are not a
+    single path component are rejected."""
+    with open(Path(snapshot_dir) / METADATA_FILE) as f:
+        names = json.load(f).get("index_names") or []
+    for name in names:
+        if (
+            not isinstance(name, str)
+            or name in ("", ".", "..")
+            or Path(name).name != name
+        ):
+            raise ValueError(f"invalid index name {name!r} in snapshot metadata")
+    return names
@@ -0,0 +1,108 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Entry point for RAGEngine backup jobs.
+
+Usage:
+    python3 cli.py trigger   # run by the backup CronJob
+    python3 cli.py restore   # run by the restore init container
+
+trigger asks the RAG service to upload a backup named $BACKUP_NAME. restore
+downloads $RESTORE_BACKUP_NAME into $RESTORE_SNAPSHOT_DIR and reports the
+restored indexes in the container termination message, which the controller
+copies to status.restore.
+"""
+
+import json
+import os
+import sys
+import tempfile
+
+import httpx
+
+from ragengine.backup import BlobStore, is_valid_backup_name, unpack_snapshot
+
+SERVICE_ACCOUNT_TOKEN = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+TERMINATION_LOG = "/dev/termination-log"
+
+
+def auth_headers() -> dict[str, str]:
+    """Return the Authorization header of the job when per-index authorization is enabled.
+
+    The controller passes the maintenance API key in RAG_API_KEY; it grants the admin
+    access that /backup and /evict require. The service account token is a fallback.
+    """
+    token = os.environ.get("RAG_API_KEY", "")
+    if not token:
+        try:
+            with open(SERVICE_ACCOUNT_TOKEN) as f:
+                token = f.read().strip()
+        except OSError:
+            return {}
+    return {"Authorization": f"Bearer {token}"}
+
+
+def _write_termination_message(message: str) -> None:
+    try:
+        with open(TERMINATION_LOG, "w") as f:
+            f.write(message)
+    except OSError:
+        pass
+
+
+def trigger() -> int:
+    name = os.environ["BACKUP_NAME"]
+    url = f"{os.environ['RAG_SERVICE_URL'].rstrip('/')}/backup"
+    resp = httpx.post(
+        url, params={"name": name}, headers=auth_headers(), timeout=3600.0
+    )
+    if resp.status_code != 200:
+        print(f"Backup {name} failed: {resp.status_code} {resp.text}")
+        return 1
+    print(f"Backup {name} completed: {resp.text}")
+    return 0
+
+
+def restore() -> int:
+    name = os.environ["RESTORE_BACKUP_NAME"]
+    snapshot_dir = os.environ["RESTORE_SNAPSHOT_DIR"]
+    try:
+        if not is_valid_backup_name(name):
+            raise ValueError(f"invalid backup name {name!r}")
+        store = BlobStore(
+            os.environ["RESTORE_SOURCE_URL"], os.environ["RESTORE_SAS_TOKEN"]
+        )
+        with tempfile.TemporaryDirectory() as tmp:
+            archive = os.path.join(tmp, "backup.tar.gz")
+            print(f"Downloading backup {name}")
+            store.download(name, archive)
+            index_names = unpack_snapshot(archive, snapshot_dir)
+    except Exception as e:
+        print(f"Restore of backup {name} failed: {e}")
+        _write_termination_message(f"Restore of backup {name} failed: {e}")
+        return 1
+    print(f"Restored {len(index_names)} indexes from backup {name}")
+    _write_termination_message(json.dumps({"indexes": index_names}))
+    return 0
+
+
+def main():
+    commands = {"trigger": trigger, "restore": restore}
+    if len(sys.argv) != 2 or sys.argv[1] not in commands:
+        print("Usage: cli.py [trigger|restore]")
+        sys.exit(1)
+    sys.exit(commands[sys.argv[1]]())
+
+
+if __name__ == "__main__":
+    main()
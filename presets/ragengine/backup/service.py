@@ -0,0 +1,62 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Backups taken by the RAG service on request of the backup CronJob."""
+
+from __future__ import annotations
+
+import asyncio
+import json
+import logging
+import os
+import tempfile
+from datetime import datetime, timezone
+
+from .archive import METADATA_FILE, pack_snapshot
+from .store import BlobStore
+
+logger = logging.getLogger(__name__)
+
+
+async def create_backup(
+    rag_ops, store: BlobStore, name: str, name_prefix: str, retain: int
+) -> list[str]:
+    """Persist every index, upload them as backup name and prune old backups.
+
+    Returns the names of the backed up indexes. Only backups starting with
+    name_prefix are pruned, so several RAGEngines can share a destination.
+    """
+    index_names = rag_ops.list_indexes()
+    with tempfile.TemporaryDirectory() as tmp:
+        snapshot_dir = os.path.join(tmp, "snapshot")
+        os.makedirs(snapshot_dir)
+        for index_name in index_names:
+            await rag_ops.persist(index_name, os.path.join(snapshot_dir, index_name))
+        with open(os.path.join(snapshot_dir, METADATA_FILE), "w") as f:
+            json.dump(
+                {
+                    "timestamp": datetime.now(timezone.utc).isoformat(),
+                    "backup": name,
+                    "index_names": index_names,
+                    "version": 1,
+                },
+                f,
+            )
+        archive = os.path.join(tmp, "backup.tar.gz")
+        await asyncio.to_thread(pack_snapshot, snapshot_dir, archive)
+        await asyncio.to_thread(store.upload, name, archive)
+
+    deleted = await asyncio.to_thread(store.prune, name_prefix, retain)
+    for old in deleted:
+        logger.info("Deleted old backup %s", old)
+    return index_names
@@ -0,0 +1,159 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Minimal Azure Blob Storage client for RAGEngine backups.
+
+Backups are stored as "<container url>/<prefix>/<backup name>.tar.gz" and accessed
+with a SAS token, so no storage SDK is needed.
+"""
+
+from __future__ import annotations
+
+import base64
+import re
+import xml.etree.ElementTree as ET
+from datetime import datetime
+from email.utils import parsedate_to_datetime
+from urllib.parse import quote, urlsplit
+
+import httpx
+
+API_VERSION = "2021-08-06"
+BACKUP_SUFFIX = ".tar.gz"
+# Archives are uploaded in blocks so their size is not limited by a single request.
+BLOCK_SIZE = 8 * 1024 * 1024
+
+# Backup names become blob and file names, so only DNS subdomain characters are allowed.
+_BACKUP_NAME_RE = re.compile(r"^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$")
+
+
+class BlobStoreError(Exception):
+    """A failed storage request. The message never contains the SAS token."""
+
+
+def is_valid_backup_name(name: str) -> bool:
+    return len(name) <= 253 and bool(_BACKUP_NAME_RE.match(name)) and ".." not in name
+
+
+class BlobStore:
+    """Reads and writes backup archives under a container URL prefix."""
+
+    def __init__(
+        self, url: str, sas_token: str, client: httpx.Client | None = None
+    ) -> None:
+        parts = urlsplit(url)
+        container, _, prefix = parts.path.strip("/").partition("/")
+        if parts.scheme != "https" or not parts.netloc or not container:
+            raise ValueError("backup URL must be https://<host>/<container>[/<prefix>]")
+        self._container_url = f"https://{parts.netloc}/{container}"
+        self._prefix = f"{prefix.strip('/')}/" if prefix.strip("/") else ""
+        self._sas = sas_token.strip().lstrip("?")
+        self._client = client or httpx.Client(timeout=60.0)
+
+    def _blob_url(self, name: str, query: str = "") -> str:
+        if not is_valid_backup_name(name):
+            raise ValueError(f"invalid backup name {name!r}")
+        blob = quote(f"{self._prefix}{name}{BACKUP_SUFFIX}")
+        return f"{self._container_url}/{blob}?{query}{'&' if query else ''}{self._sas}"
+
+    def _request(self, method: str, url: str, **kwargs) -> httpx.Response:
+        headers = {"x-ms-version": API_VERSION, **kwargs.pop("headers", {})}
+        try:
+            resp = self._client.request(method, url, headers=headers, **kwargs)
+        except httpx.HTTPError as e:
+            raise BlobStoreError(
+                f"{method} {url.split('?')[0]} failed: {type(e).__name__}"
+            ) from None
+        _check(method, url, resp)
+        return resp
+
+    def upload(self, name: str, path: str) -> None:
+        """Upload the archive at path as backup name."""
+        block_ids = []
+        with open(path, "rb") as f:
+            while chunk := f.read(BLOCK_SIZE):
+                block_id = base64.b64encode(f"{len(block_ids):08d}".encode()).decode()
+                self._request(
+                    "PUT",
+                    self._blob_url(name, f"comp=block&blockid={quote(block_id)}"),
+                    content=chunk,
+                )
+                block_ids.append(block_id)
+        body = "".join(f"<Latest>{b}</Latest>" for b in block_ids)
+        self._request(
+            "PUT",
+            self._blob_url(name, "comp=blocklist"),
+            content=f'<?xml version="1.0" encoding="utf-8"?><BlockList>{body}</BlockList>',
+            headers={"Content-Type": "application/xml"},
+        )
+
+    def download(self, name: str, path: str) -> None:
+        """Download backup name to path."""
+        url = self._blob_url(name)
+        try:
+            with self._client.stream(
+                "GET", url, headers={"x-ms-version": API_VERSION}
+            ) as resp:
+                _check("GET", url, resp)
+                with open(path, "wb") as f:
+                    for chunk in resp.iter_bytes():
+                        f.write(chunk)
+        except httpx.HTTPError as e:
+            raise BlobStoreError(
+                f"GET {url.split('?')[0]} failed: {type(e).__name__}"
+            ) from None
+
+    def list(self, name_prefix: str = "") -> list[tuple[str, datetime]]:
+        """Return (name, last modified) of the backups starting with name_prefix."""
+        backups = []
+        marker = ""
+        while True:
+            prefix = quote(self._prefix + name_prefix)
+            query = f"restype=container&comp=list&prefix={prefix}"
+            if marker:
+                query += f"&marker={quote(marker)}"
+            resp = self._request("GET", f"{self._container_url}?{query}&{self._sas}")
+            root = ET.fromstring(resp.content)
+            for blob in root.iter("Blob"):
+                blob_name = blob.findtext("Name", "")[len(self._prefix) :]
+                modified = blob.findtext("Properties/Last-Modified")
+                if blob_name.endswith(BACKUP_SUFFIX) and modified:
+                    backups.append(
+                        (
+                            blob_name[: -len(BACKUP_SUFFIX)],
+                            parsedate_to_datetime(modified),
+                        )
+                    )
+            marker = root.findtext("NextMarker") or ""
+            if not marker:
+                return backups
+
+    def delete(self, name: str) -> None:
+        self._request("DELETE", self._blob_url(name))
+
+    def prune(self, name_prefix: str, retain: int) -> list[str]:
+        """Delete all but the newest retain backups starting with name_prefix."""
+        backups = sorted(self.list(name_prefix), key=lambda b: b[1], reverse=True)
+        deleted = []
+        for name, _ in backups[retain:]:
+            if is_valid_backup_name(name):
+                self.delete(name)
+                deleted.append(name)
+        return deleted
+
+
+def _check(method: str, url: str, resp: httpx.Response) -> None:
+    if resp.status_code >= 400:
+        raise BlobStoreError(
+            f"{method} {url.split('?')[0]} failed with status {resp.status_code}"
+        )
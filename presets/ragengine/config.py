@@ -18,6 +18,7 @@
 # and exposed to the pod. For example, `LLM_INFERENCE_URL` is specified in the CR and
 # passed to the pod via environment variables.
 
+import json
 import os
 
 """
@@ -26,8 +27,8 @@ import os
 
 # Embedding configuration
 EMBEDDING_SOURCE_TYPE = os.getenv(
-    "EMBEDDING_SOURCE_TYPE", "local"
-)  # Determines local or remote embedding source
+    "EMBEDDING_SOURCE_TYPE", os.getenv("EMBEDDING_TYPE", "local")
+)  # Determines local or remote embedding source; the controller sets EMBEDDING_TYPE
 
 # Local embedding model
 LOCAL_EMBEDDING_MODEL_ID = os.getenv(
@@ -41,6 +42,16 @@ REMOTE_EMBEDDING_URL = os.getenv(
 REMOTE_EMBEDDING_ACCESS_SECRET = os.getenv(
     "REMOTE_EMBEDDING_ACCESS_SECRET", "default-access-secret"
 )
+# "huggingface" posts {"inputs": [...]}; "openai" posts {"model": ..., "input": [...]}
+REMOTE_EMBEDDING_API_FORMAT = os.getenv("REMOTE_EMBEDDING_API_FORMAT", "huggingface")
+REMOTE_EMBEDDING_MODEL = os.getenv("REMOTE_EMBEDDING_MODEL", "")
+REMOTE_EMBEDDING_BATCH_SIZE = int(os.getenv("REMOTE_EMBEDDING_BATCH_SIZE", 32))
+REMOTE_EMBEDDING_TIMEOUT_SECONDS = float(
+    os.getenv("REMOTE_EMBEDDING_TIMEOUT_SECONDS", 60)
+)
+REMOTE_EMBEDDING_MAX_RETRIES = int(os.getenv("REMOTE_EMBEDDING_MAX_RETRIES", 3))
+# Directory of header templates mounted from spec.embedding.remote.headersSecret.
+REMOTE_EMBEDDING_HEADERS_DIR = os.getenv("REMOTE_EMBEDDING_HEADERS_DIR", "")
 
 """
 =========================================================================
@@ -74,6 +85,33 @@ def _parse_bool_env(name: str, default: str = "false") -> bool:
     return os.getenv(name, default).lower() == "true"
 
 
+# Per-index authorization policy rendered by the controller from spec.authorization.
+# Authorization is disabled when the path is empty.
+INDEX_AUTH_POLICY_PATH = os.getenv("INDEX_AUTH_POLICY_PATH", "")
+# Internal token used by the lifecycle hooks while authorization is enabled.
+INDEX_AUTH_INTERNAL_TOKEN_PATH = os.getenv(
+    "INDEX_AUTH_INTERNAL_TOKEN_PATH", "/tmp/ragengine-internal-token"
+)
+
+# Scheduled backups configured by the controller from spec.backup.
+# Backups are disabled when the destination is empty.
+BACKUP_DESTINATION_URL = os.getenv("BACKUP_DESTINATION_URL", "")
+BACKUP_SAS_TOKEN = os.getenv("BACKUP_SAS_TOKEN", "")
+BACKUP_NAME_PREFIX = os.getenv("BACKUP_NAME_PREFIX", "")
+BACKUP_RETAIN = int(os.getenv("BACKUP_RETAIN", 7))
+
+# Conversation memory configured by the controller from spec.conversationMemory.
+# Chat completions are stateless when the backend is empty.
+CONVERSATION_MEMORY_BACKEND = os.getenv("CONVERSATION_MEMORY_BACKEND", "")
+CONVERSATION_MEMORY_MAX_TURNS = int(os.getenv("CONVERSATION_MEMORY_MAX_TURNS", 10))
+CONVERSATION_MEMORY_TTL_SECONDS = float(
+    os.getenv("CONVERSATION_MEMORY_TTL_SECONDS", 86400)
+)
+CONVERSATION_MEMORY_REDIS_URL = os.getenv("CONVERSATION_MEMORY_REDIS_URL", "")
+CONVERSATION_MEMORY_REDIS_PASSWORD = os.getenv(
+    "CONVERSATION_MEMORY_REDIS_PASSWORD", ""
+)
+
 OUTPUT_GUARDRAILS_ENABLED = _parse_bool_env("OUTPUT_GUARDRAILS_ENABLED")
 OUTPUT_GUARDRAILS_POLICY_PATH = os.getenv("OUTPUT_GUARDRAILS_POLICY_PATH", "")
 OUTPUT_GUARDRAILS_HOT_RELOAD_ENABLED = (
@@ -96,6 +134,16 @@ DEFAULT_VECTOR_DB_PERSIST_DIR = os.getenv("DEFAULT_VECTOR_DB_PERSIST_DIR", "stor
 VECTOR_DB_URL = os.getenv("VECTOR_DB_URL", None)  # None = in-memory/local mode
 VECTOR_DB_ACCESS_SECRET = os.getenv("VECTOR_DB_ACCESS_SECRET", None)
 
+# Sharding (injected from CRD spec.sharding into the router pod of a sharded RAGEngine)
+# RAG_SHARD_URLS lists the index workers; when set, this pod routes documents to them
+# and fans queries out instead of holding indexes itself.
+RAG_SHARD_URLS = [
+    url.strip() for url in os.getenv("RAG_SHARD_URLS", "").split(",") if url.strip()
+]
+RAG_SHARD_STRATEGY = os.getenv("RAG_SHARD_STRATEGY", "hash")  # hash or metadatafield
+RAG_SHARD_METADATA_FIELD = os.getenv("RAG_SHARD_METADATA_FIELD", "")
+RAG_SHARD_TIMEOUT_SECONDS = float(os.getenv("RAG_SHARD_TIMEOUT_SECONDS", 300))
+
 """
 =========================================================================
 """
@@ -112,5 +160,27 @@ RAG_DEFAULT_CONTEXT_TOKEN_FILL_RATIO = float(
 RAG_DOCUMENT_NODE_TOKEN_APPROXIMATION = float(
     os.getenv("RAG_DOCUMENT_NODE_TOKEN_APPROXIMATION", 500)
 )
+# Retrieval mode for /retrieve (injected from CRD spec.retrieval): "vector", "keyword"
+# or "hybrid". In hybrid mode RAG_RETRIEVAL_VECTOR_WEIGHT is the share of the fused
+# score given to vector similarity; the rest goes to BM25 keyword relevance.
+RAG_RETRIEVAL_MODE = os.getenv("RAG_RETRIEVAL_MODE", "hybrid").lower()
+RAG_RETRIEVAL_VECTOR_WEIGHT = float(os.getenv("RAG_RETRIEVAL_VECTOR_WEIGHT", 0.7))
+# Source attribution (injected from CRD spec.responseOptions).
+# RAG_INCLUDE_CITATIONS numbers the context chunks and asks the model to cite them;
+# RAG_MAX_SOURCE_CHUNKS bounds the chunks used as context (0 = no bound);
+# RAG_SOURCE_METADATA_FIELDS limits the metadata returned with sources (empty = all).
+RAG_INCLUDE_CITATIONS = _parse_bool_env("RAG_INCLUDE_CITATIONS")
+RAG_MAX_SOURCE_CHUNKS = int(os.getenv("RAG_MAX_SOURCE_CHUNKS", 0))
+RAG_SOURCE_METADATA_FIELDS = [
+    field
+    for field in os.getenv("RAG_SOURCE_METADATA_FIELDS", "").split(",")
+    if field.strip()
+]
 # Maximum top_k value for retrieve to prevent excessive memory usage and latency
 RAG_MAX_TOP_K = int(os.getenv("RAG_MAX_TOP_K", 300))
+# Document TTLs in seconds per index (injected from CRD spec.indexPolicies), e.g.
+# {"docs": 86400}. /evict removes the documents written longer ago than their TTL.
+RAG_INDEX_DOCUMENT_TTLS = {
+    name: float(ttl)
+    for name, ttl in json.loads(os.getenv("RAG_INDEX_DOCUMENT_TTLS") or "{}").items()
+}
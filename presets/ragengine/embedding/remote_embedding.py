@@ -11,7 +11,13 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
+import asyncio
 import json
+import logging
+import os
+import re
+import time
+import uuid
 from typing import Any
 
 import requests
@@ -20,41 +26,193 @@ from ragengine.metrics.helpers import record_embedding_metrics
 
 from .base import BaseEmbeddingModel
 
+logger = logging.getLogger(__name__)
+
+API_FORMAT_HUGGINGFACE = "huggingface"
+API_FORMAT_OPENAI = "openai"
+
+# RFC 7230 token characters allowed in a header name.
+_HEADER_NAME_RE = re.compile(r"^[!#$%&'*+\-.^_`|~0-9A-Za-z]+$")
+_MAX_BACKOFF_SECONDS = 8.0
+
+
+def render_headers(headers_dir: str, api_key: str, request_id: str) -> dict[str, str]:
+    """
+    Renders the header templates mounted from the headers secret. Every file in
+    headers_dir is a header: the file name is the header name and the content is
+    its value, in which {access_token} and {request_id} are substituted.
+    """
+    headers: dict[str, str] = {}
+    if not headers_dir:
+        return headers
+    for name in sorted(os.listdir(headers_dir)):
+        # Secret volumes contain hidden bookkeeping entries such as ..data.
+        if name.startswith("."):
+            continue
+        path = os.path.join(headers_dir, name)
+        if not os.path.isfile(path):
+            continue
+        if not _HEADER_NAME_RE.match(name):
+            raise ValueError(f"Invalid header name in headers secret: {name!r}")
+        with open(path, encoding="utf-8") as f:
+            template = f.read().strip()
+        value = template.replace("{access_token}", api_key or "").replace(
+            "{request_id}", request_id
+        )
+        if "\r" in value or "\n" in value:
+            raise ValueError(f"Header {name} must not contain line breaks")
+        headers[name] = value
+    return headers
+
+
+def build_payload(api_format: str, model: str, texts: list[str]) -> dict[str, Any]:
+    """Builds the request body of an embedding call for the given API format."""
+    if api_format == API_FORMAT_OPENAI:
+        return {"model": model, "input": texts}
+    return {"inputs": texts}
+
+
+def parse_response(api_format: str, body: Any, count: int) -> list[list[float]]:
+    """Extracts one embedding per input from the response body of an embedding call."""
+    if api_format == API_FORMAT_OPENAI:
+        if not isinstance(body, dict) or not isinstance(body.get("data"), list):
+            raise ValueError("Unexpected response format. Expected a 'data' list.")
+        data = sorted(body["data"], key=lambda item: item.get("index", 0))
+        embeddings = [item.get("embedding") for item in data]
+    else:
+        if not isinstance(body, list):
+            raise ValueError("Unexpected response format. Expected a list.")
+        embeddings = body
+        # A single input may be answered with a bare vector.
+        if count == 1 and embeddings and not isinstance(embeddings[0], list):
+            embeddings = [embeddings]
+    if len(embeddings) != count or not all(isinstance(e, list) for e in embeddings):
+        raise ValueError(
+            f"Unexpected response format. Expected {count} embeddings, got {len(embeddings)}."
+        )
+    return embeddings
+
 
 class RemoteEmbeddingModel(BaseEmbeddingModel):
-    def __init__(self, model_url: str, api_key: str, /, **data: Any):
+    def __init__(
+        self,
+        model_url: str,
+        api_key: str,
+        /,
+        api_format: str = API_FORMAT_HUGGINGFACE,
+        model: str = "",
+        batch_size: int = 32,
+        timeout: float = 60,
+        max_retries: int = 3,
+        headers_dir: str = "",
+        **data: Any,
+    ):
         """
         Initialize the RemoteEmbeddingModel.
 
         Args:
             model_url (str): The URL of the embedding model API endpoint.
             api_key (str): The API key for accessing the API.
+            api_format (str): "huggingface" or "openai" (/v1/embeddings).
+            model (str): The model sent in OpenAI requests.
+            batch_size (int): The maximum number of texts sent in one request.
+            timeout (float): The timeout of one request in seconds.
+            max_retries (int): Retries of a request that failed with a connection
+                error, 429 or 5xx.
+            headers_dir (str): Directory of header templates sent with every request.
         """
+        data.setdefault("embed_batch_size", batch_size)
         super().__init__(**data)
+        api_format = (api_format or API_FORMAT_HUGGINGFACE).lower()
+        if api_format not in (API_FORMAT_HUGGINGFACE, API_FORMAT_OPENAI):
+            raise ValueError(f"Unsupported remote embedding API format: {api_format}")
+        if api_format == API_FORMAT_OPENAI and not model:
+            raise ValueError("A model is required for the openai API format")
         self.model_url = model_url
         self.api_key = api_key
+        self.api_format = api_format
+        self.model = model
+        self.batch_size = max(1, batch_size)
+        self.timeout = timeout
+        self.max_retries = max(0, max_retries)
+        self.headers_dir = headers_dir
 
-    @record_embedding_metrics
-    def _get_text_embedding(self, text: str):
-        """Returns the text embedding for a given input string."""
+    def _headers(self) -> dict[str, str]:
         headers = {
             "Authorization": f"Bearer {self.api_key}",
             "Content-Type": "application/json",
         }
-        payload = {"inputs": text}
-
-        try:
-            response = requests.post(
-                self.model_url, headers=headers, data=json.dumps(payload)
-            )
-            response.raise_for_status()  # Raise an HTTPError for bad responses
-            embedding = response.json()  # Assumes the API returns JSON
-            if isinstance(embedding, list):
-                return embedding
-            else:
-                raise ValueError("Unexpected response format. Expected a list.")
-        except requests.exceptions.RequestException as e:
-            raise RuntimeError(f"Failed to get embedding from remote model: {e}")
+        # Templates override the defaults, e.g. to send an api-key header instead;
+        # an empty template drops the header.
+        rendered = render_headers(self.headers_dir, self.api_key, str(uuid.uuid4()))
+        lowered = {k.lower() for k in rendered}
+        headers = {k: v for k, v in headers.items() if k.lower() not in lowered}
+        headers.update({k: v for k, v in rendered.items() if v})
+        return headers
+
+    def _post(self, payload: dict[str, Any]) -> Any:
+        """Posts the payload, retrying connection errors, 429 and 5xx with backoff."""
+        body = json.dumps(payload)
+        for attempt in range(self.max_retries + 1):
+            retry_after = None
+            try:
+                response = requests.post(
+                    self.model_url,
+                    headers=self._headers(),
+                    data=body,
+                    timeout=self.timeout,
+                )
+                if response.status_code == 429 or response.status_code >= 500:
+                    if attempt < self.max_retries:
+                        retry_after = response.headers.get("Retry-After")
+                        raise requests.exceptions.HTTPError(
+                            f"{response.status_code} from remote embedding endpoint",
+                            response=response,
+                        )
+                response.raise_for_status()  # Raise an HTTPError for bad responses
+                return response.json()  # Assumes the API returns JSON
+            except (
+                requests.exceptions.ConnectionError,
+                requests.exceptions.Timeout,
+                requests.exceptions.HTTPError,
+            ) as e:
+                retryable = e.response is None or (
+                    e.response.status_code == 429 or e.response.status_code >= 500
+                )
+                if not retryable or attempt >= self.max_retries:
+                    raise RuntimeError(
+                        f"Failed to get embedding from remote model: {e}"
+                    ) from e
+                delay = min(_MAX_BACKOFF_SECONDS, 0.5 * 2**attempt)
+                if retry_after and retry_after.isdigit():
+                    delay = min(_MAX_BACKOFF_SECONDS, float(retry_after))
+                logger.warning(
+                    f"Remote embedding request failed ({e}), retrying in {delay}s"
+                )
+                time.sleep(delay)
+            except requests.exceptions.RequestException as e:
+                raise RuntimeError(
+                    f"Failed to get embedding from remote model: {e}"
+                ) from e
+
+    @record_embedding_metrics
+    def _embed_batch(self, texts: list[str]) -> list[list[float]]:
+        body = self._post(build_payload(self.api_format, self.model, texts))
+        return parse_response(self.api_format, body, len(texts))
+
+    def _get_text_embeddings(self, texts: list[str]) -> list[list[float]]:
+        """Returns the embeddings of the texts, batch_size texts per request."""
+        embeddings: list[list[float]] = []
+        for i in range(0, len(texts), self.batch_size):
+            embeddings.extend(self._embed_batch(texts[i : i + self.batch_size]))
+        return embeddings
+
+    async def _aget_text_embeddings(self, texts: list[str]) -> list[list[float]]:
+        return await asyncio.to_thread(self._get_text_embeddings, texts)
+
+    def _get_text_embedding(self, text: str):
+        """Returns the text embedding for a given input string."""
+        return self._get_text_embeddings([text])[0]
 
     def _get_query_embedding(self, query: str):
         return self._get_text_embedding(query)
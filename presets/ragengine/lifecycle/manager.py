@@ -26,6 +26,20 @@ from pathlib import Path
 
 import requests
 
+# Written by the RAG service when per-index authorization is enabled.
+INTERNAL_TOKEN_PATH = os.getenv(
+    "INDEX_AUTH_INTERNAL_TOKEN_PATH", "/tmp/ragengine-internal-token"
+)
+
+
+def _auth_headers() -> dict[str, str]:
+    """Return the Authorization header for the internal token, if any."""
+    try:
+        with open(INTERNAL_TOKEN_PATH) as f:
+            return {"Authorization": f"Bearer {f.read().strip()}"}
+    except OSError:
+        return {}
+
 
 def wait_for_service(
     service_url: str = "http://localhost:5000/indexes", timeout: int = 60
@@ -45,7 +59,7 @@ def wait_for_service(
 
     for attempt in range(max_attempts):
         try:
-            response = requests.get(service_url, timeout=2)
+            response = requests.get(service_url, headers=_auth_headers(), timeout=2)
             if response.status_code == 200:
                 print(f"Service ready after {attempt * 2}s")
                 return True
@@ -70,7 +84,9 @@ def get_indexes(service_url: str = "http://localhost:5000") -> list[str]:
         List of index names
     """
     try:
-        response = requests.get(f"{service_url}/indexes", timeout=5)
+        response = requests.get(
+            f"{service_url}/indexes", headers=_auth_headers(), timeout=5
+        )
         return response.json()
     except Exception as e:
         print(f"Failed to get indexes: {e}")
@@ -93,7 +109,7 @@ def load_index(
     """
     try:
         url = f"{service_url}/load/{index_name}?path={path}&overwrite=true"
-        response = requests.post(url, timeout=30)
+        response = requests.post(url, headers=_auth_headers(), timeout=30)
         return response.status_code == 200
     except Exception as e:
         print(f"Failed to load index {index_name}: {e}")
@@ -116,13 +132,26 @@ def persist_index(
     """
     try:
         url = f"{service_url}/persist/{index_name}?path={path}"
-        response = requests.post(url, timeout=30)
+        response = requests.post(url, headers=_auth_headers(), timeout=30)
         return response.status_code == 200
     except Exception as e:
         print(f"Failed to persist index {index_name}: {e}")
         return False
 
 
+def restored_snapshot() -> Path | None:
+    """
+    Return the snapshot downloaded by the restore init container, if any.
+
+    Returns:
+        Path of the restored snapshot, or None when spec.restore is not set
+    """
+    restore_dir = os.getenv("RESTORE_SNAPSHOT_DIR")
+    if not restore_dir or not (Path(restore_dir) / "metadata.json").exists():
+        return None
+    return Path(restore_dir)
+
+
 def poststart_handler(base_dir: str | None = None) -> int:
     """
     PostStart lifecycle hook handler.
@@ -136,6 +165,11 @@ def poststart_handler(base_dir: str | None = None) -> int:
     """
     print("=== PostStart Handler Started ===")
 
+    # The router of a sharded RAGEngine holds no indexes; its index workers do.
+    if os.getenv("RAG_SHARD_URLS"):
+        print("Sharded RAGEngine router, nothing to do")
+        return 0
+
     # Get base directory
     if base_dir is None:
         base_dir = os.getenv("DEFAULT_VECTOR_DB_PERSIST_DIR", "/mnt/vector-db")
@@ -152,28 +186,32 @@ def poststart_handler(base_dir: str | None = None) -> int:
     if not latest_link.exists() or not latest_link.is_symlink():
         # LATEST link doesn't exist, try to find the most recent snapshot
         snapshots_dir = base / "systemsnapshots"
-        if not snapshots_dir.exists():
-            print("No previous snapshots found")
-            return 0
-
-        # Find all snapshot directories
-        all_snapshots = sorted(
-            [d for d in snapshots_dir.iterdir() if d.is_dir()],
-            key=lambda x: x.name,
-            reverse=True,
+        all_snapshots = (
+            sorted(
+                [d for d in snapshots_dir.iterdir() if d.is_dir()],
+                key=lambda x: x.name,
+                reverse=True,
+            )
+            if snapshots_dir.exists()
+            else []
         )
 
-        if not all_snapshots:
-            print("No previous snapshots found")
-            return 0
-
-        # Use the most recent snapshot
-        latest = all_snapshots[0]
-        print(f"LATEST link missing, using most recent snapshot: {latest.name}")
+        if all_snapshots:
+            # Use the most recent snapshot
+            latest = all_snapshots[0]
+            print(f"LATEST link missing, using most recent snapshot: {latest.name}")
 
-        # Recreate LATEST symlink
-        latest_link.symlink_to(latest.relative_to(base))
-        print(f"✓ Recreated LATEST link -> {latest.name}")
+            # Recreate LATEST symlink
+            latest_link.symlink_to(latest.relative_to(base))
+            print(f"✓ Recreated LATEST link -> {latest.name}")
+        else:
+            # A RAGEngine created from a backup starts from the restored snapshot
+            # until it has written one of its own.
+            latest = restored_snapshot()
+            if latest is None:
+                print("No previous snapshots found")
+                return 0
+            print(f"Using snapshot restored from backup: {latest}")
     else:
         # LATEST link exists, resolve it
         latest = latest_link.resolve()
@@ -195,6 +233,9 @@ def poststart_handler(base_dir: str | None = None) -> int:
         # Load each index
         loaded_count = 0
         for index_name in index_names:
+            if Path(index_name).name != index_name or index_name in ("", ".", ".."):
+                print(f"✗ Skipping invalid index name: {index_name!r}")
+                continue
             index_path = str(latest / index_name)
             print(f"Loading index: {index_name} from {index_path}")
 
@@ -233,6 +274,11 @@ def prestop_handler(base_dir: str | None = None, keep_snapshots: int = 5) -> int
     """
     print("=== PreStop Handler Started ===")
 
+    # The router of a sharded RAGEngine holds no indexes; its index workers do.
+    if os.getenv("RAG_SHARD_URLS"):
+        print("Sharded RAGEngine router, nothing to do")
+        return 0
+
     # Get base directory
     if base_dir is None:
         base_dir = os.getenv("DEFAULT_VECTOR_DB_PERSIST_DIR", "/mnt/vector-db")
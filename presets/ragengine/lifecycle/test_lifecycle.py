@@ -177,6 +177,24 @@ class TestLifecycleManager(unittest.TestCase):
         self.assertEqual(result, 0)
         self.assertEqual(mock_load.call_count, 2)
 
+    @patch("manager.wait_for_service")
+    @patch("manager.load_index")
+    def test_poststart_with_restored_snapshot(self, mock_load, mock_wait):
+        """Test poststart_handler falls back to the snapshot restored from a backup."""
+        mock_wait.return_value = True
+        mock_load.return_value = True
+
+        restore_dir = self.base_path / "restore"
+        restore_dir.mkdir()
+        with open(restore_dir / "metadata.json", "w") as f:
+            json.dump({"index_names": ["index1", "../escape"]}, f)
+
+        with patch.dict(os.environ, {"RESTORE_SNAPSHOT_DIR": str(restore_dir)}):
+            result = poststart_handler(base_dir=str(self.base_path / "data"))
+
+        self.assertEqual(result, 0)
+        mock_load.assert_called_once_with("index1", str(restore_dir / "index1"))
+
     @patch("manager.wait_for_service")
     def test_poststart_service_not_ready(self, mock_wait):
         """Test poststart_handler when service doesn't become ready."""
@@ -12,6 +12,8 @@
 # limitations under the License.
 
 
+import asyncio
+import inspect
 import json
 import logging
 import os
@@ -19,7 +21,7 @@ import time
 from urllib.parse import unquote
 
 import nest_asyncio
-from fastapi import FastAPI, HTTPException, Query, Request  # noqa: E402
+from fastapi import Depends, FastAPI, HTTPException, Query, Request  # noqa: E402
 from prometheus_client import CONTENT_TYPE_LATEST, generate_latest  # noqa: E402
 from starlette.responses import Response, StreamingResponse  # noqa: E402
 
@@ -43,14 +45,40 @@ from models import (  # noqa: E402
     UpdateDocumentResponse,
 )
 from vector_store_manager.manager import VectorStoreManager  # noqa: E402
+from vector_store_manager.sharded import ShardedVectorStoreManager  # noqa: E402
 
+from ragengine.auth import IndexAuthorizer  # noqa: E402
+from ragengine.backup import BlobStore, is_valid_backup_name  # noqa: E402
+from ragengine.backup.service import create_backup  # noqa: E402
 from ragengine.config import (  # noqa: E402
+    BACKUP_DESTINATION_URL,
+    BACKUP_NAME_PREFIX,
+    BACKUP_RETAIN,
+    BACKUP_SAS_TOKEN,
+    CONVERSATION_MEMORY_BACKEND,
+    CONVERSATION_MEMORY_MAX_TURNS,
+    CONVERSATION_MEMORY_REDIS_PASSWORD,
+    CONVERSATION_MEMORY_REDIS_URL,
+    CONVERSATION_MEMORY_TTL_SECONDS,
     DEFAULT_VECTOR_DB_PERSIST_DIR,
     EMBEDDING_SOURCE_TYPE,
+    INDEX_AUTH_INTERNAL_TOKEN_PATH,
+    INDEX_AUTH_POLICY_PATH,
     LOCAL_EMBEDDING_MODEL_ID,
     OUTPUT_GUARDRAILS_HOT_RELOAD_ENABLED,
     OUTPUT_GUARDRAILS_POLICY_PATH,
+    RAG_INDEX_DOCUMENT_TTLS,
+    RAG_SHARD_METADATA_FIELD,
+    RAG_SHARD_STRATEGY,
+    RAG_SHARD_TIMEOUT_SECONDS,
+    RAG_SHARD_URLS,
     REMOTE_EMBEDDING_ACCESS_SECRET,
+    REMOTE_EMBEDDING_API_FORMAT,
+    REMOTE_EMBEDDING_BATCH_SIZE,
+    REMOTE_EMBEDDING_HEADERS_DIR,
+    REMOTE_EMBEDDING_MAX_RETRIES,
+    REMOTE_EMBEDDING_MODEL,
+    REMOTE_EMBEDDING_TIMEOUT_SECONDS,
     REMOTE_EMBEDDING_URL,
     VECTOR_DB_ACCESS_SECRET,
     VECTOR_DB_TYPE,
@@ -60,6 +88,10 @@ from ragengine.guardrails import (  # noqa: E402
     GuardrailsReloader,
     OutputGuardrailsError,
 )
+from ragengine.memory import (  # noqa: E402
+    create_conversation_memory,
+    is_valid_session_id,
+)
 from ragengine.metrics.prometheus_metrics import (  # noqa: E402
     MODE_LOCAL,
     MODE_REMOTE,
@@ -93,6 +125,11 @@ from ragengine.streaming.guardrails import (  # noqa: E402
     apply_streaming_guardrails,
     raise_if_streaming_guardrails_unsupported,
 )
+from ragengine.streaming.openai import (  # noqa: E402
+    OpenAIChatChunkParseStatus,
+    parse_openai_chat_sse_event,
+)
+from ragengine.streaming.sse import SSEFramer  # noqa: E402
 
 # Import Prometheus client for metrics collection
 
@@ -137,40 +174,86 @@ async def track_requests(request: Request, call_next):
         e2e_request_total.labels(status=status).inc()
 
 
-# Initialize embedding model
-if EMBEDDING_SOURCE_TYPE.lower() == MODE_LOCAL:
+# Initialize embedding model. The router of a sharded RAGEngine holds no indexes;
+# its index workers embed the documents.
+if RAG_SHARD_URLS:
+    embedding_manager = None
+elif EMBEDDING_SOURCE_TYPE.lower() == MODE_LOCAL:
     embedding_manager = LocalHuggingFaceEmbedding(LOCAL_EMBEDDING_MODEL_ID)
 elif EMBEDDING_SOURCE_TYPE.lower() == MODE_REMOTE:
     embedding_manager = RemoteEmbeddingModel(
-        REMOTE_EMBEDDING_URL, REMOTE_EMBEDDING_ACCESS_SECRET
+        REMOTE_EMBEDDING_URL,
+        REMOTE_EMBEDDING_ACCESS_SECRET,
+        api_format=REMOTE_EMBEDDING_API_FORMAT,
+        model=REMOTE_EMBEDDING_MODEL,
+        batch_size=REMOTE_EMBEDDING_BATCH_SIZE,
+        timeout=REMOTE_EMBEDDING_TIMEOUT_SECONDS,
+        max_retries=REMOTE_EMBEDDING_MAX_RETRIES,
+        headers_dir=REMOTE_EMBEDDING_HEADERS_DIR,
     )
 else:
     raise ValueError("Invalid Embedding Type Specified (Must be Local or Remote)")
 
-# Initialize vector store based on configured backend (VECTOR_DB_TYPE from CRD)
-if VECTOR_DB_TYPE.lower() == "faiss":
-    from vector_store.faiss_store import FaissVectorStoreHandler
-
-    vector_store_handler = FaissVectorStoreHandler(embedding_manager)
-elif VECTOR_DB_TYPE.lower() == "qdrant":
-    from vector_store.qdrant_store import QdrantVectorStoreHandler
-
-    vector_store_handler = QdrantVectorStoreHandler(
-        embedding_manager,
-        vector_db_url=VECTOR_DB_URL,
-        vector_db_access_secret=VECTOR_DB_ACCESS_SECRET,
+# Initialize RAG operations; a router forwards them to the index workers.
+if RAG_SHARD_URLS:
+    rag_ops = ShardedVectorStoreManager(
+        RAG_SHARD_URLS,
+        strategy=RAG_SHARD_STRATEGY,
+        metadata_field=RAG_SHARD_METADATA_FIELD,
+        timeout=RAG_SHARD_TIMEOUT_SECONDS,
     )
 else:
-    raise ValueError(
-        f"Unsupported VECTOR_DB_TYPE: '{VECTOR_DB_TYPE}'. "
-        "Supported values: 'faiss', 'qdrant'"
-    )
+    # Initialize vector store based on configured backend (VECTOR_DB_TYPE from CRD)
+    if VECTOR_DB_TYPE.lower() == "faiss":
+        from vector_store.faiss_store import FaissVectorStoreHandler
+
+        vector_store_handler = FaissVectorStoreHandler(embedding_manager)
+    elif VECTOR_DB_TYPE.lower() == "qdrant":
+        from vector_store.qdrant_store import QdrantVectorStoreHandler
+
+        vector_store_handler = QdrantVectorStoreHandler(
+            embedding_manager,
+            vector_db_url=VECTOR_DB_URL,
+            vector_db_access_secret=VECTOR_DB_ACCESS_SECRET,
+        )
+    else:
+        raise ValueError(
+            f"Unsupported VECTOR_DB_TYPE: '{VECTOR_DB_TYPE}'. "
+            "Supported values: 'faiss', 'qdrant'"
+        )
+
+    rag_ops = VectorStoreManager(vector_store_handler)
 
-# Initialize RAG operations
-rag_ops = VectorStoreManager(vector_store_handler)
 guardrails_reloader = GuardrailsReloader(
     policy_path=OUTPUT_GUARDRAILS_POLICY_PATH,
 )
+index_authorizer = IndexAuthorizer(INDEX_AUTH_POLICY_PATH)
+if index_authorizer.enabled:
+    index_authorizer.write_internal_token(INDEX_AUTH_INTERNAL_TOKEN_PATH)
+conversation_memory = create_conversation_memory(
+    CONVERSATION_MEMORY_BACKEND,
+    CONVERSATION_MEMORY_MAX_TURNS,
+    CONVERSATION_MEMORY_TTL_SECONDS,
+    redis_url=CONVERSATION_MEMORY_REDIS_URL,
+    redis_password=CONVERSATION_MEMORY_REDIS_PASSWORD,
+)
+
+
+async def require_index_access(request: Request) -> None:
+    """Authorize the request for the index named in the path or in the JSON body."""
+    if not index_authorizer.enabled:
+        return
+    index_name = request.path_params.get("index_name")
+    if index_name is None:
+        try:
+            body = await request.json()
+        except Exception:
+            body = None
+        if isinstance(body, dict) and body.get("index_name") is not None:
+            index_name = str(body["index_name"])
+    else:
+        index_name = unquote(index_name)
+    await index_authorizer.authorize(index_name, request.headers.get("authorization"))
 
 
 @app.on_event("startup")
@@ -219,7 +302,7 @@ async def metrics():
 )
 def health_check():
     try:
-        if embedding_manager is None:
+        if embedding_manager is None and not RAG_SHARD_URLS:
             raise HTTPException(
                 status_code=500, detail="Embedding manager not initialized"
             )
@@ -239,6 +322,7 @@ def health_check():
 @app.post(
     "/index",
     operation_id="create_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     response_model=list[Document],
     summary="Index Documents",
@@ -297,6 +381,7 @@ async def index_documents(request: IndexRequest):
 @app.post(
     "/v1/chat/completions",
     operation_id="chat",
+    dependencies=[Depends(require_index_access)],
     tags=["Chat"],
     response_model=ChatCompletionResponse,
     summary="OpenAI-Compatible Chat Completions API",
@@ -318,6 +403,10 @@ async def index_documents(request: IndexRequest):
     }
     ```
 
+    When conversation memory is enabled, add a "session_id" to continue a
+    conversation: the earlier turns of the session are replayed before the new
+    messages, so each request only carries the new messages.
+
     ## Response Example:
     ```json
     {
@@ -367,6 +456,22 @@ async def chat_completions(request: dict):
                 status_code=400,
                 detail="n > 1 is not supported.",
             )
+        session_id = request.pop("session_id", None)
+        new_messages = request.get("messages") or []
+        if session_id is not None:
+            if conversation_memory is None:
+                raise HTTPException(
+                    status_code=400,
+                    detail="session_id requires conversation memory to be enabled in the RAGEngine spec.",
+                )
+            if not is_valid_session_id(session_id):
+                raise HTTPException(
+                    status_code=400,
+                    detail="session_id must be 1-128 characters of letters, digits, '.', '_' or '-'.",
+                )
+            request["messages"] = await conversation_memory.with_history(
+                request.get("index_name"), session_id, new_messages
+            )
         if request.get("stream") is True:
             if guardrails.enabled:
                 raise_if_streaming_guardrails_unsupported(guardrails)
@@ -377,6 +482,10 @@ async def chat_completions(request: dict):
                     guardrails,
                     request,
                 )
+            if session_id is not None:
+                response = _record_streamed_answer(
+                    response, request.get("index_name"), session_id, new_messages
+                )
             status = STATUS_SUCCESS
             return StreamingResponse(
                 response,
@@ -388,6 +497,13 @@ async def chat_completions(request: dict):
             )
         response = await rag_ops.chat_completion(request)
         response = guardrails.guard_response(response, request)
+        if session_id is not None:
+            await conversation_memory.record(
+                request.get("index_name"),
+                session_id,
+                new_messages,
+                _answer_content(response),
+            )
         status = STATUS_SUCCESS
         return response
     except HTTPException as http_exc:
@@ -407,6 +523,41 @@ async def chat_completions(request: dict):
         rag_chat_latency.labels(status=status).observe(time.perf_counter() - start_time)
 
 
+def _answer_content(response) -> str | None:
+    """Return the assistant message of the first choice of a chat completion."""
+    if hasattr(response, "model_dump"):
+        response = response.model_dump()
+    try:
+        return response["choices"][0]["message"]["content"]
+    except (KeyError, IndexError, TypeError):
+        return None
+
+
+async def _record_streamed_answer(chunks, index_name, session_id, new_messages):
+    """Pass a chat completion stream through and record the answer once it ends."""
+    framer = SSEFramer()
+    answer = []
+    completed = False
+    async for chunk in chunks:
+        text = chunk.decode() if isinstance(chunk, bytes) else chunk
+        for event in framer.feed(text):
+            result = parse_openai_chat_sse_event(event)
+            if result.status == OpenAIChatChunkParseStatus.DONE:
+                completed = True
+            elif result.status == OpenAIChatChunkParseStatus.PARSED:
+                answer.extend(
+                    choice.content
+                    for choice in result.parsed_choices
+                    if choice.choice_index == 0 and choice.content is not None
+                )
+        yield chunk
+    # Interrupted streams are not recorded, so a partial answer is never replayed.
+    if completed:
+        await conversation_memory.record(
+            index_name, session_id, new_messages, "".join(answer)
+        )
+
+
 @app.get(
     "/indexes",
     operation_id="list_indexes",
@@ -425,14 +576,21 @@ async def chat_completions(request: dict):
     ```
     """,
 )
-def list_indexes():
+async def list_indexes(request: Request):
     start_time = time.perf_counter()
     status = STATUS_FAILURE  # Default status
 
     try:
-        result = rag_ops.list_indexes()
+        indexes = rag_ops.list_indexes()
+        if inspect.isawaitable(indexes):
+            indexes = await indexes  # The router asks the index workers.
+        result = await index_authorizer.filter_indexes(
+            indexes, request.headers.get("authorization")
+        )
         status = STATUS_SUCCESS
         return result
+    except HTTPException as http_exc:
+        raise http_exc
     except Exception as e:
         logger.error("List indexes failed", exc_info=True)
         raise HTTPException(status_code=500, detail=str(e))
@@ -447,6 +605,7 @@ def list_indexes():
 @app.get(
     "/indexes/{index_name}/documents",
     operation_id="list_documents_in_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     response_model=ListDocumentsResponse,
     summary="List Documents in an Index",
@@ -549,6 +708,7 @@ async def list_documents_in_index(
 @app.post(
     "/indexes/{index_name}/documents",
     operation_id="update_documents_in_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     response_model=UpdateDocumentResponse,
     summary="Update documents in an Index",
@@ -600,6 +760,7 @@ async def update_documents_in_index(
 @app.post(
     "/indexes/{index_name}/documents/delete",
     operation_id="delete_documents_in_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     response_model=DeleteDocumentResponse,
     summary="Delete documents in an Index",
@@ -650,6 +811,7 @@ async def delete_documents_in_index(
 @app.post(
     "/persist/{index_name}",
     operation_id="persist_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     summary="Persist Index Data to Disk",
     description="""
@@ -706,6 +868,7 @@ async def persist_index(
 @app.post(
     "/load/{index_name}",
     operation_id="load_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     summary="Load Index Data from Disk",
     description="""
@@ -758,6 +921,7 @@ async def load_index(
 @app.post(
     "/retrieve",
     operation_id="retrieve_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     response_model=RetrieveResponse,
     summary="Retrieve Relevant Documents",
@@ -828,6 +992,7 @@ async def retrieve_from_index(request: RetrieveRequest):
 @app.delete(
     "/indexes/{index_name}",
     operation_id="delete_index",
+    dependencies=[Depends(require_index_access)],
     tags=["Index"],
     summary="Delete the Index",
     description="""
@@ -872,10 +1037,118 @@ async def delete_index(index_name: str):
         )
 
 
+backup_lock = asyncio.Lock()
+
+
+@app.post(
+    "/backup",
+    operation_id="backup_indexes",
+    dependencies=[Depends(require_index_access)],
+    tags=["Index"],
+    summary="Back Up All Indexes",
+    description="""
+    Persist all indexes and upload them to the backup destination configured in
+    spec.backup of the RAGEngine. Called by the backup CronJob.
+
+    ## Request Example:
+    ```
+    POST /backup?name=example-rag-backup-29348160
+    ```
+
+    ## Response Example:
+    ```json
+    {
+      "backup": "example-rag-backup-29348160",
+      "index_names": ["example_index"]
+    }
+    ```
+    """,
+)
+async def backup_indexes(
+    name: str = Query(..., description="Name of the backup to create"),
+):
+    if not BACKUP_DESTINATION_URL:
+        raise HTTPException(status_code=400, detail="Backups are not configured")
+    if not is_valid_backup_name(name):
+        raise HTTPException(status_code=422, detail=f"Invalid backup name {name!r}")
+    if backup_lock.locked():
+        raise HTTPException(status_code=409, detail="A backup is already running")
+
+    async with backup_lock:
+        try:
+            store = BlobStore(BACKUP_DESTINATION_URL, BACKUP_SAS_TOKEN)
+            index_names = await create_backup(
+                rag_ops, store, name, BACKUP_NAME_PREFIX, BACKUP_RETAIN
+            )
+        except HTTPException as http_exc:
+            raise http_exc
+        except Exception as e:
+            logger.error("Backup %s failed", name, exc_info=True)
+            raise HTTPException(status_code=500, detail=f"Backup failed: {str(e)}")
+    return {"backup": name, "index_names": index_names}
+
+
+eviction_lock = asyncio.Lock()
+
+
+@app.post(
+    "/evict",
+    operation_id="evict_expired_documents",
+    dependencies=[Depends(require_index_access)],
+    tags=["Index"],
+    summary="Evict Expired Documents",
+    description="""
+    Delete the documents that are older than the document TTL of their index, as
+    configured in spec.indexPolicies of the RAGEngine, and report the documents of
+    every index. Called by the index eviction CronJob.
+
+    ## Request Example:
+    ```
+    POST /evict
+    ```
+
+    ## Response Example:
+    ```json
+    {
+      "indexes": [
+        {"name": "docs", "documents": 120, "evicted": 4},
+        {"name": "wiki", "documents": 38, "evicted": 0}
+      ]
+    }
+    ```
+    """,
+)
+async def evict_expired_documents():
+    if eviction_lock.locked():
+        raise HTTPException(status_code=409, detail="An eviction is already running")
+
+    async with eviction_lock:
+        indexes = []
+        for index_name in rag_ops.list_indexes():
+            try:
+                indexes.append(
+                    await rag_ops.evict_expired_documents(
+                        index_name, RAG_INDEX_DOCUMENT_TTLS.get(index_name)
+                    )
+                )
+            except HTTPException as http_exc:
+                # The index was deleted while evicting the others.
+                if http_exc.status_code != 404:
+                    raise
+            except Exception as e:
+                logger.error("Eviction from index %s failed", index_name, exc_info=True)
+                raise HTTPException(
+                    status_code=500, detail=f"Eviction failed: {str(e)}"
+                )
+    return {"indexes": indexes}
+
+
 @app.on_event("shutdown")
 async def shutdown_event():
     """Ensure the client is properly closed when the server shuts down."""
     await rag_ops.shutdown()
+    if conversation_memory is not None:
+        await conversation_memory.close()
 
 
 if __name__ == "__main__":
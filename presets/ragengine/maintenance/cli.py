@@ -0,0 +1,73 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""Entry point for RAGEngine index maintenance jobs.
+
+Usage:
+    python3 cli.py evict   # run by the index eviction CronJob
+
+evict asks the RAG service to evict the documents that outlived the document TTL
+of their index and reports the documents of every index in the container
+termination message, which the controller copies to status.indexes.
+"""
+
+import json
+import os
+import sys
+
+import httpx
+
+from ragengine.backup.cli import _write_termination_message, auth_headers
+
+# The termination message is limited to 4096 bytes; larger reports are cut down to
+# the indexes that fit.
+MAX_TERMINATION_MESSAGE = 4096
+
+
+def _report(indexes: list[dict]) -> str:
+    indexes = sorted(indexes, key=lambda i: (-i.get("evicted", 0), i.get("name", "")))
+    while True:
+        message = json.dumps({"indexes": indexes})
+        if len(message.encode()) <= MAX_TERMINATION_MESSAGE or not indexes:
+            return message
+        indexes = indexes[:-1]
+
+
+def evict() -> int:
+    url = f"{os.environ['RAG_SERVICE_URL'].rstrip('/')}/evict"
+    resp = httpx.post(url, headers=auth_headers(), timeout=3600.0)
+    if resp.status_code != 200:
+        print(f"Eviction failed: {resp.status_code} {resp.text}")
+        _write_termination_message(f"Eviction failed: {resp.status_code}")
+        return 1
+    indexes = resp.json().get("indexes") or []
+    for index in indexes:
+        print(
+            f"Index {index.get('name')}: evicted {index.get('evicted')}, "
+            f"{index.get('documents')} documents left"
+        )
+    _write_termination_message(_report(indexes))
+    return 0
+
+
+def main():
+    commands = {"evict": evict}
+    if len(sys.argv) != 2 or sys.argv[1] not in commands:
+        print("Usage: cli.py [evict]")
+        sys.exit(1)
+    sys.exit(commands[sys.argv[1]]())
+
+
+if __name__ == "__main__":
+    main()
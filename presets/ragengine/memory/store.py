@@ -0,0 +1,235 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Conversation memory of /v1/chat/completions.
+
+Clients opt in by sending a "session_id" with a chat completion request. The
+turns of a session, the new messages of a request and the answer to them, are
+stored per index and replayed before the new messages of the next request, so
+clients only send what is new. Only the last max_turns turns are kept, and a
+session is forgotten after ttl_seconds without activity.
+
+The controller configures the store from spec.conversationMemory of the
+RAGEngine: "local" keeps the history in this process next to the in-memory
+indexes and loses it on restart, "redis" keeps it in a Redis server that
+survives restarts and is shared by all replicas.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import re
+import time
+from collections import OrderedDict
+from collections.abc import Callable
+from typing import Any, Protocol
+
+logger = logging.getLogger(__name__)
+
+# Session ids end up in Redis keys, so they are restricted to a safe alphabet.
+# ":" is excluded so "<session>:<index>" keys are unambiguous.
+_SESSION_ID_RE = re.compile(r"^[A-Za-z0-9._-]{1,128}$")
+# Redis key prefix of the turns of a session.
+REDIS_KEY_PREFIX = "kaito:memory:"
+# Bounds the number of sessions the local store keeps; the least recently used
+# sessions are dropped first.
+DEFAULT_LOCAL_MAX_SESSIONS = 10000
+# Roles that set up the conversation rather than being part of a turn.
+_PREAMBLE_ROLES = ("system", "developer")
+
+Turn = list[dict[str, Any]]
+
+
+def is_valid_session_id(session_id: Any) -> bool:
+    return isinstance(session_id, str) and bool(_SESSION_ID_RE.match(session_id))
+
+
+def _session_key(index_name: str | None, session_id: str) -> str:
+    return f"{session_id}:{index_name or ''}"
+
+
+class MemoryStore(Protocol):
+    async def turns(self, key: str) -> list[Turn]: ...
+
+    async def append(self, key: str, turn: Turn) -> None: ...
+
+    async def close(self) -> None: ...
+
+
+class LocalMemoryStore:
+    """Keeps the turns of each session in process memory."""
+
+    def __init__(
+        self,
+        max_turns: int,
+        ttl_seconds: float,
+        max_sessions: int = DEFAULT_LOCAL_MAX_SESSIONS,
+        clock: Callable[[], float] = time.monotonic,
+    ):
+        self.max_turns = max_turns
+        self.ttl_seconds = ttl_seconds
+        self.max_sessions = max_sessions
+        self._clock = clock
+        # key -> (expiry, turns), least recently used first
+        self._sessions: OrderedDict[str, tuple[float, list[Turn]]] = OrderedDict()
+
+    async def turns(self, key: str) -> list[Turn]:
+        entry = self._sessions.get(key)
+        if entry is None:
+            return []
+        expiry, turns = entry
+        if expiry <= self._clock():
+            del self._sessions[key]
+            return []
+        self._sessions.move_to_end(key)
+        return list(turns)
+
+    async def append(self, key: str, turn: Turn) -> None:
+        turns = await self.turns(key)
+        turns = (turns + [turn])[-self.max_turns :]
+        self._sessions[key] = (self._clock() + self.ttl_seconds, turns)
+        self._sessions.move_to_end(key)
+        while len(self._sessions) > self.max_sessions:
+            self._sessions.popitem(last=False)
+
+    async def close(self) -> None:
+        self._sessions.clear()
+
+
+class RedisMemoryStore:
+    """Keeps the turns of each session in a Redis list, one JSON entry per turn."""
+
+    def __init__(
+        self,
+        url: str,
+        password: str | None,
+        max_turns: int,
+        ttl_seconds: float,
+        client: Any = None,
+    ):
+        if client is None:
+            import redis.asyncio as redis
+
+            client = redis.from_url(url, password=password or None)
+        self._client = client
+        self.max_turns = max_turns
+        self.ttl_seconds = max(1, int(ttl_seconds))
+
+    async def turns(self, key: str) -> list[Turn]:
+        raw = await self._client.lrange(REDIS_KEY_PREFIX + key, 0, -1)
+        turns = []
+        for entry in raw:
+            try:
+                turns.append(json.loads(entry))
+            except (TypeError, ValueError):
+                logger.warning("Skipping malformed conversation memory entry")
+        return turns[-self.max_turns :]
+
+    async def append(self, key: str, turn: Turn) -> None:
+        redis_key = REDIS_KEY_PREFIX + key
+        async with self._client.pipeline(transaction=True) as pipe:
+            pipe.rpush(redis_key, json.dumps(turn))
+            pipe.ltrim(redis_key, -self.max_turns, -1)
+            pipe.expire(redis_key, self.ttl_seconds)
+            await pipe.execute()
+
+    async def close(self) -> None:
+        await self._client.aclose()
+
+
+def split_preamble(
+    messages: list[dict[str, Any]],
+) -> tuple[list[dict[str, Any]], list[dict[str, Any]]]:
+    """Split messages into the leading system/developer messages and the rest."""
+    i = 0
+    while i < len(messages) and messages[i].get("role") in _PREAMBLE_ROLES:
+        i += 1
+    return messages[:i], messages[i:]
+
+
+class ConversationMemory:
+    """Replays and records the turns of chat completion sessions.
+
+    Store failures are logged and the request is served without history, so an
+    unavailable Redis server degrades chats to stateless ones instead of
+    failing them.
+    """
+
+    def __init__(self, store: MemoryStore):
+        self.store = store
+
+    async def with_history(
+        self, index_name: str | None, session_id: str, messages: list[dict[str, Any]]
+    ) -> list[dict[str, Any]]:
+        """Return messages with the stored turns inserted after the preamble."""
+        try:
+            turns = await self.store.turns(_session_key(index_name, session_id))
+        except Exception as e:
+            logger.warning(f"Failed to load conversation memory: {e}")
+            return messages
+        preamble, rest = split_preamble(messages)
+        history = [message for turn in turns for message in turn]
+        return preamble + history + rest
+
+    async def record(
+        self,
+        index_name: str | None,
+        session_id: str,
+        messages: list[dict[str, Any]],
+        answer: str | None,
+    ) -> None:
+        """Store the new messages of a request and the answer to them as a turn."""
+        _, turn = split_preamble(messages)
+        turn = [m for m in turn if m.get("role") not in _PREAMBLE_ROLES]
+        if answer is not None:
+            turn.append({"role": "assistant", "content": answer})
+        if not turn:
+            return
+        try:
+            await self.store.append(_session_key(index_name, session_id), turn)
+        except Exception as e:
+            logger.warning(f"Failed to record conversation memory: {e}")
+
+    async def close(self) -> None:
+        await self.store.close()
+
+
+def create_conversation_memory(
+    backend: str,
+    max_turns: int,
+    ttl_seconds: float,
+    redis_url: str = "",
+    redis_password: str = "",
+) -> ConversationMemory | None:
+    """Create the conversation memory configured by the controller.
+
+    Returns None when conversation memory is disabled.
+    """
+    backend = backend.lower()
+    if not backend:
+        return None
+    if backend == "local":
+        return ConversationMemory(LocalMemoryStore(max_turns, ttl_seconds))
+    if backend == "redis":
+        if not redis_url:
+            raise ValueError(
+                "CONVERSATION_MEMORY_REDIS_URL is required for the redis backend"
+            )
+        return ConversationMemory(
+            RedisMemoryStore(redis_url, redis_password, max_turns, ttl_seconds)
+        )
+    raise ValueError(
+        f"Unsupported CONVERSATION_MEMORY_BACKEND: '{backend}'. "
+        "Supported values: 'local', 'redis'"
+    )
@@ -69,6 +69,8 @@ class NodeWithScore(BaseModel):
     sparse_score: float | None = None
     source: str | None = None  # "both", "dense_only", "sparse_only"
     metadata: dict | None = None
+    # Number the answer cites this chunk with, when citations are enabled.
+    citation: int | None = None
 
 
 class RetrieveRequest(BaseModel):
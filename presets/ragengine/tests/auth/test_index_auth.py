@@ -0,0 +1,153 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import asyncio
+import hashlib
+import json
+import os
+import sys
+
+import pytest
+from fastapi import HTTPException
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.auth.index_auth import IndexAuthorizer
+
+
+def _sha(key: str) -> str:
+    return hashlib.sha256(key.encode()).hexdigest()
+
+
+async def _reviewer(token: str):
+    return {"sa-token": "team-b/indexer", "admin-sa-token": "ops/backup"}.get(token)
+
+
+@pytest.fixture
+def policy_file(tmp_path):
+    path = tmp_path / "policy.json"
+    path.write_text(
+        json.dumps(
+            {
+                "indexes": {
+                    "team-a": {"apiKeySHA256": [_sha("key-a")]},
+                    "team-b": {"serviceAccounts": ["team-b/indexer"]},
+                },
+                "admins": {
+                    "apiKeySHA256": [_sha("key-admin")],
+                    "serviceAccounts": ["ops/backup"],
+                },
+            }
+        )
+    )
+    return path
+
+
+def _status(coro) -> int:
+    try:
+        asyncio.run(coro)
+    except HTTPException as e:
+        return e.status_code
+    return 200
+
+
+def test_disabled_allows_everything():
+    authorizer = IndexAuthorizer("", token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", None)) == 200
+
+
+def test_api_key_access(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 200
+    assert _status(authorizer.authorize("team-b", "Bearer key-a")) == 403
+    assert _status(authorizer.authorize("unlisted", "Bearer key-a")) == 403
+    assert _status(authorizer.authorize("team-a", None)) == 401
+    assert _status(authorizer.authorize("team-a", "Bearer wrong")) == 401
+
+
+def test_service_account_access(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-b", "Bearer sa-token")) == 200
+    assert _status(authorizer.authorize("team-a", "Bearer sa-token")) == 403
+
+
+def test_request_without_index_needs_admin(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize(None, "Bearer key-a")) == 403
+    assert _status(authorizer.authorize(None, "Bearer sa-token")) == 403
+    assert _status(authorizer.authorize(None, "Bearer wrong")) == 401
+    assert _status(authorizer.authorize(None, "Bearer key-admin")) == 200
+    assert _status(authorizer.authorize(None, "Bearer admin-sa-token")) == 200
+
+
+def test_admins_access_every_index(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", "Bearer key-admin")) == 200
+    assert _status(authorizer.authorize("unlisted", "Bearer admin-sa-token")) == 200
+
+
+def test_request_without_index_denied_without_admins(tmp_path):
+    path = tmp_path / "policy.json"
+    path.write_text(
+        json.dumps({"indexes": {"team-a": {"apiKeySHA256": [_sha("key-a")]}}})
+    )
+    authorizer = IndexAuthorizer(str(path), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize(None, "Bearer key-a")) == 403
+
+
+def test_filter_indexes(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    names = ["team-a", "team-b", "unlisted"]
+    assert asyncio.run(authorizer.filter_indexes(names, "Bearer key-a")) == ["team-a"]
+    assert asyncio.run(authorizer.filter_indexes(names, "Bearer sa-token")) == [
+        "team-b"
+    ]
+
+
+def test_policy_reload(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 200
+
+    policy_file.write_text(
+        json.dumps({"indexes": {"team-a": {"apiKeySHA256": [_sha("key-a2")]}}})
+    )
+    os.utime(policy_file, (0, 1))
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 401
+    assert _status(authorizer.authorize("team-a", "Bearer key-a2")) == 200
+
+
+def test_invalid_policy_keeps_last_valid(policy_file):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 200
+
+    policy_file.write_text("{not json")
+    os.utime(policy_file, (0, 2))
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 200
+
+
+def test_missing_policy_denies(tmp_path):
+    authorizer = IndexAuthorizer(str(tmp_path / "absent.json"), token_reviewer=_reviewer)
+    assert _status(authorizer.authorize("team-a", "Bearer key-a")) == 401
+
+
+def test_internal_token_allows_every_index(policy_file, tmp_path):
+    authorizer = IndexAuthorizer(str(policy_file), token_reviewer=_reviewer)
+    token_path = tmp_path / "internal-token"
+    authorizer.write_internal_token(str(token_path))
+    token = token_path.read_text()
+
+    assert oct(token_path.stat().st_mode & 0o777) == "0o600"
+    assert _status(authorizer.authorize("unlisted", f"Bearer {token}")) == 200
+    assert asyncio.run(
+        authorizer.filter_indexes(["team-a", "unlisted"], f"Bearer {token}")
+    ) == ["team-a", "unlisted"]
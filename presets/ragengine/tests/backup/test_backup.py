@@ -0,0 +1,157 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import io
+import json
+import os
+import sys
+import tarfile
+from datetime import datetime, timezone
+
+import pytest
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.backup import store as store_module
+from ragengine.backup.archive import pack_snapshot, unpack_snapshot
+from ragengine.backup.store import BlobStore, BlobStoreError, is_valid_backup_name
+
+CONTAINER = "https://acct.blob.core.windows.net/backups"
+SAS = "sv=2021&sig=secret"
+
+
+class FakeResponse:
+    def __init__(self, status_code=201, content=b""):
+        self.status_code = status_code
+        self.content = content
+
+
+class FakeClient:
+    """Records requests and answers them from a list of canned responses."""
+
+    def __init__(self, responses=None):
+        self.requests = []
+        self._responses = list(responses or [])
+
+    def request(self, method, url, headers=None, content=None):
+        self.requests.append((method, url, content))
+        return self._responses.pop(0) if self._responses else FakeResponse()
+
+
+def _list_response(blobs, next_marker=""):
+    items = "".join(
+        f"<Blob><Name>{name}</Name><Properties><Last-Modified>{modified}</Last-Modified></Properties></Blob>"
+        for name, modified in blobs
+    )
+    xml = f"<EnumerationResults><Blobs>{items}</Blobs><NextMarker>{next_marker}</NextMarker></EnumerationResults>"
+    return FakeResponse(200, xml.encode())
+
+
+def test_is_valid_backup_name():
+    assert is_valid_backup_name("rag-backup-29348160")
+    for name in ["", "../etc", "a/b", "UPPER", "-a", "a..b", "a?sig=x"]:
+        assert not is_valid_backup_name(name), name
+
+
+def test_upload_in_blocks(tmp_path, monkeypatch):
+    monkeypatch.setattr(store_module, "BLOCK_SIZE", 4)
+    archive = tmp_path / "backup.tar.gz"
+    archive.write_bytes(b"0123456789")
+    client = FakeClient()
+
+    store = BlobStore(f"{CONTAINER}/team/", "?" + SAS, client)
+    store.upload("rag-backup-1", str(archive))
+
+    assert [r[2] for r in client.requests[:3]] == [b"0123", b"4567", b"89"]
+    for method, url, _ in client.requests:
+        assert method == "PUT"
+        assert url.startswith(f"{CONTAINER}/team/rag-backup-1.tar.gz?comp=")
+        assert url.endswith("&" + SAS)
+    assert client.requests[3][2].count("<Latest>") == 3
+
+
+def test_prune_keeps_newest_backups():
+    blobs = [
+        ("team/rag-backup-1.tar.gz", "Mon, 12 Oct 2026 00:00:00 GMT"),
+        ("team/rag-backup-3.tar.gz", "Wed, 14 Oct 2026 00:00:00 GMT"),
+    ]
+    more = [("team/rag-backup-2.tar.gz", "Tue, 13 Oct 2026 00:00:00 GMT")]
+    client = FakeClient([_list_response(blobs, "m1"), _list_response(more)])
+    store = BlobStore(f"{CONTAINER}/team", SAS, client)
+
+    assert store.prune("rag-backup-", 2) == ["rag-backup-1"]
+    assert "prefix=team/rag-backup-" in client.requests[0][1]
+    assert "marker=m1" in client.requests[1][1]
+    assert client.requests[2][0] == "DELETE"
+    assert client.requests[2][1].startswith(f"{CONTAINER}/team/rag-backup-1.tar.gz?")
+
+
+def test_list_parses_last_modified():
+    blobs = [("rag-backup-1.tar.gz", "Mon, 12 Oct 2026 00:00:00 GMT")]
+    client = FakeClient([_list_response(blobs)])
+    assert BlobStore(CONTAINER, SAS, client).list() == [
+        ("rag-backup-1", datetime(2026, 10, 12, tzinfo=timezone.utc))
+    ]
+
+
+def test_errors_do_not_leak_sas_token():
+    client = FakeClient([FakeResponse(403)])
+    with pytest.raises(BlobStoreError) as exc:
+        BlobStore(CONTAINER, SAS, client).delete("rag-backup-1")
+    assert "403" in str(exc.value)
+    assert "sig=" not in str(exc.value)
+
+
+def test_rejects_invalid_names_and_urls():
+    with pytest.raises(ValueError):
+        BlobStore("http://acct.blob.core.windows.net/backups", SAS, FakeClient())
+    with pytest.raises(ValueError):
+        BlobStore(CONTAINER, SAS, FakeClient()).delete("../other")
+
+
+def test_pack_and_unpack_snapshot(tmp_path):
+    snapshot = tmp_path / "snapshot"
+    (snapshot / "index1").mkdir(parents=True)
+    (snapshot / "index1" / "docstore.json").write_text("{}")
+    (snapshot / "metadata.json").write_text(json.dumps({"index_names": ["index1"]}))
+    archive = tmp_path / "backup.tar.gz"
+
+    pack_snapshot(str(snapshot), str(archive))
+    restored = tmp_path / "restored"
+
+    assert unpack_snapshot(str(archive), str(restored)) == ["index1"]
+    assert (restored / "index1" / "docstore.json").read_text() == "{}"
+
+
+def _archive_with(tmp_path, members):
+    archive = tmp_path / "backup.tar.gz"
+    with tarfile.open(archive, "w:gz") as tar:
+        for name, data in members.items():
+            info = tarfile.TarInfo(name)
+            info.size = len(data)
+            tar.addfile(info, io.BytesIO(data))
+    return archive
+
+
+def test_unpack_rejects_path_traversal(tmp_path):
+    archive = _archive_with(tmp_path, {"../evil": b"x", "metadata.json": b"{}"})
+    with pytest.raises(tarfile.TarError):
+        unpack_snapshot(str(archive), str(tmp_path / "restored"))
+    assert not (tmp_path / "evil").exists()
+
+
+def test_unpack_rejects_invalid_index_names(tmp_path):
+    metadata = json.dumps({"index_names": ["../../etc"]}).encode()
+    archive = _archive_with(tmp_path, {"metadata.json": metadata})
+    with pytest.raises(ValueError):
+        unpack_snapshot(str(archive), str(tmp_path / "restored"))
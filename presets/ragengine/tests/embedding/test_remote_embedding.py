@@ -0,0 +1,156 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+import os
+import sys
+
+import pytest
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.embedding import remote_embedding
+from ragengine.embedding.remote_embedding import (
+    RemoteEmbeddingModel,
+    render_headers,
+)
+
+
+class _Response:
+    def __init__(self, status_code, body, headers=None):
+        self.status_code = status_code
+        self._body = body
+        self.headers = headers or {}
+
+    def json(self):
+        return self._body
+
+    def raise_for_status(self):
+        if self.status_code >= 400:
+            raise remote_embedding.requests.exceptions.HTTPError(
+                f"{self.status_code}", response=self
+            )
+
+
+@pytest.fixture
+def calls(monkeypatch):
+    """Records the requests and replays the queued responses."""
+    recorded = {"requests": [], "responses": []}
+
+    def post(url, headers, data, timeout):
+        recorded["requests"].append(
+            {"url": url, "headers": headers, "body": json.loads(data), "timeout": timeout}
+        )
+        return recorded["responses"].pop(0)
+
+    monkeypatch.setattr(remote_embedding.requests, "post", post)
+    monkeypatch.setattr(remote_embedding.time, "sleep", lambda _: None)
+    return recorded
+
+
+def test_openai_batches_and_orders_by_index(calls):
+    model = RemoteEmbeddingModel(
+        "https://api.example.com/v1/embeddings",
+        "sk-test",
+        api_format="openai",
+        model="text-embedding-3-small",
+        batch_size=2,
+        timeout=5,
+    )
+    calls["responses"] = [
+        _Response(200, {"data": [{"index": 1, "embedding": [2.0]}, {"index": 0, "embedding": [1.0]}]}),
+        _Response(200, {"data": [{"index": 0, "embedding": [3.0]}]}),
+    ]
+
+    assert model._get_text_embeddings(["a", "b", "c"]) == [[1.0], [2.0], [3.0]]
+    assert [r["body"] for r in calls["requests"]] == [
+        {"model": "text-embedding-3-small", "input": ["a", "b"]},
+        {"model": "text-embedding-3-small", "input": ["c"]},
+    ]
+    assert calls["requests"][0]["timeout"] == 5
+    assert calls["requests"][0]["headers"]["Authorization"] == "Bearer sk-test"
+
+
+def test_huggingface_single_text(calls):
+    model = RemoteEmbeddingModel("https://hf.example.com", "hf-token")
+    calls["responses"] = [_Response(200, [0.1, 0.2])]
+
+    assert model._get_text_embedding("hello") == [0.1, 0.2]
+    assert calls["requests"][0]["body"] == {"inputs": ["hello"]}
+
+
+def test_retries_on_throttling_and_server_errors(calls):
+    model = RemoteEmbeddingModel("https://hf.example.com", "t", max_retries=2)
+    calls["responses"] = [
+        _Response(429, {}, {"Retry-After": "1"}),
+        _Response(503, {}),
+        _Response(200, [[1.0]]),
+    ]
+
+    assert model._get_text_embedding("x") == [1.0]
+    assert len(calls["requests"]) == 3
+
+
+def test_does_not_retry_client_errors(calls):
+    model = RemoteEmbeddingModel("https://hf.example.com", "t", max_retries=3)
+    calls["responses"] = [_Response(400, {})]
+
+    with pytest.raises(RuntimeError):
+        model._get_text_embedding("x")
+    assert len(calls["requests"]) == 1
+
+
+def test_gives_up_after_max_retries(calls):
+    model = RemoteEmbeddingModel("https://hf.example.com", "t", max_retries=1)
+    calls["responses"] = [_Response(500, {}), _Response(500, {})]
+
+    with pytest.raises(RuntimeError):
+        model._get_text_embedding("x")
+    assert len(calls["requests"]) == 2
+
+
+def test_header_templates_override_authorization(calls, tmp_path):
+    (tmp_path / "api-key").write_text("{access_token}\n")
+    (tmp_path / "x-ms-client-request-id").write_text("{request_id}")
+    (tmp_path / "Authorization").write_text("")
+    (tmp_path / "..data").mkdir()
+    model = RemoteEmbeddingModel(
+        "https://aoai.example.com/openai/deployments/emb/embeddings",
+        "azure-key",
+        api_format="openai",
+        model="emb",
+        headers_dir=str(tmp_path),
+    )
+    calls["responses"] = [_Response(200, {"data": [{"index": 0, "embedding": [1.0]}]})]
+
+    model._get_text_embedding("x")
+    headers = calls["requests"][0]["headers"]
+    assert headers["api-key"] == "azure-key"
+    assert headers["x-ms-client-request-id"]
+    assert "Authorization" not in headers
+
+
+def test_render_headers_rejects_invalid_headers(tmp_path):
+    (tmp_path / "bad header").write_text("v")
+    with pytest.raises(ValueError):
+        render_headers(str(tmp_path), "k", "id")
+
+    os.remove(tmp_path / "bad header")
+    (tmp_path / "x-token").write_text("{access_token}")
+    with pytest.raises(ValueError):
+        render_headers(str(tmp_path), "k\r\nX-Injected: 1", "id")
+
+
+def test_openai_requires_model():
+    with pytest.raises(ValueError):
+        RemoteEmbeddingModel("https://api.example.com/v1/embeddings", "k", api_format="openai")
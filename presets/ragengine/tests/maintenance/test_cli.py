@@ -0,0 +1,95 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import json
+import os
+import sys
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.maintenance import cli
+
+
+def test_report_lists_all_indexes():
+    indexes = [
+        {"name": "wiki", "documents": 3, "evicted": 0},
+        {"name": "docs", "documents": 5, "evicted": 2},
+    ]
+    report = json.loads(cli._report(indexes))
+    assert [i["name"] for i in report["indexes"]] == ["docs", "wiki"]
+
+
+def test_report_fits_termination_message():
+    indexes = [
+        {"name": f"index-{i:04d}", "documents": i, "evicted": 0} for i in range(500)
+    ]
+    indexes.append({"name": "evicted", "documents": 1, "evicted": 9})
+    message = cli._report(indexes)
+    assert len(message.encode()) <= cli.MAX_TERMINATION_MESSAGE
+    report = json.loads(message)
+    # Indexes with evictions are kept first.
+    assert report["indexes"][0]["name"] == "evicted"
+    assert len(report["indexes"]) < len(indexes)
+
+
+def test_evict_writes_report(monkeypatch, tmp_path):
+    class FakeResponse:
+        status_code = 200
+        text = ""
+
+        def json(self):
+            return {"indexes": [{"name": "docs", "documents": 5, "evicted": 2}]}
+
+    requests = []
+
+    def fake_post(url, headers=None, timeout=None):
+        requests.append(url)
+        return FakeResponse()
+
+    termination_log = tmp_path / "termination-log"
+    monkeypatch.setenv("RAG_SERVICE_URL", "http://rag.default.svc/")
+    monkeypatch.setattr(cli.httpx, "post", fake_post)
+    monkeypatch.setattr(
+        cli,
+        "_write_termination_message",
+        lambda message: termination_log.write_text(message),
+    )
+
+    assert cli.evict() == 0
+    assert requests == ["http://rag.default.svc/evict"]
+    report = json.loads(termination_log.read_text())
+    assert report == {"indexes": [{"name": "docs", "documents": 5, "evicted": 2}]}
+
+
+def test_evict_authenticates_with_maintenance_key(monkeypatch):
+    class FakeResponse:
+        status_code = 200
+        text = ""
+
+        def json(self):
+            return {"indexes": []}
+
+    headers_sent = []
+
+    def fake_post(url, headers=None, timeout=None):
+        headers_sent.append(headers)
+        return FakeResponse()
+
+    monkeypatch.setenv("RAG_SERVICE_URL", "http://rag.default.svc")
+    monkeypatch.setenv("RAG_API_KEY", "maintenance-key")
+    monkeypatch.setattr(cli.httpx, "post", fake_post)
+    monkeypatch.setattr(cli, "_write_termination_message", lambda message: None)
+
+    assert cli.evict() == 0
+    assert headers_sent == [{"Authorization": "Bearer maintenance-key"}]
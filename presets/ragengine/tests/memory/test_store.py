@@ -0,0 +1,209 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import asyncio
+import os
+import sys
+
+import pytest
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.memory import (
+    ConversationMemory,
+    LocalMemoryStore,
+    RedisMemoryStore,
+    create_conversation_memory,
+    is_valid_session_id,
+)
+
+
+class _Clock:
+    def __init__(self):
+        self.now = 0.0
+
+    def __call__(self) -> float:
+        return self.now
+
+
+class _FakePipeline:
+    def __init__(self, redis):
+        self.redis = redis
+        self.ops = []
+
+    async def __aenter__(self):
+        return self
+
+    async def __aexit__(self, *exc):
+        return False
+
+    def rpush(self, key, value):
+        self.ops.append(lambda: self.redis.lists.setdefault(key, []).append(value))
+
+    def ltrim(self, key, start, end):
+        def trim():
+            self.redis.lists[key] = self.redis.lists[key][start:]
+
+        assert end == -1
+        self.ops.append(trim)
+
+    def expire(self, key, seconds):
+        self.ops.append(lambda: self.redis.expiry.__setitem__(key, seconds))
+
+    async def execute(self):
+        for op in self.ops:
+            op()
+
+
+class _FakeRedis:
+    def __init__(self):
+        self.lists = {}
+        self.expiry = {}
+
+    async def lrange(self, key, start, end):
+        return list(self.lists.get(key, []))
+
+    def pipeline(self, transaction=True):
+        return _FakePipeline(self)
+
+    async def aclose(self):
+        pass
+
+
+class _BrokenStore:
+    async def turns(self, key):
+        raise ConnectionError("redis is down")
+
+    async def append(self, key, turn):
+        raise ConnectionError("redis is down")
+
+    async def close(self):
+        pass
+
+
+def _user(content):
+    return {"role": "user", "content": content}
+
+
+def _assistant(content):
+    return {"role": "assistant", "content": content}
+
+
+def test_session_id_validation():
+    assert is_valid_session_id("user-42.chat_1")
+    for session_id in ["", "a" * 129, "a:b", "a b", "../x", 42, None]:
+        assert not is_valid_session_id(session_id)
+
+
+def test_history_is_replayed_after_the_preamble():
+    memory = ConversationMemory(LocalMemoryStore(max_turns=10, ttl_seconds=60))
+    system = {"role": "system", "content": "Be brief."}
+
+    async def run():
+        await memory.record(
+            "docs", "s1", [system, _user("What is KAITO?")], "An operator."
+        )
+        return await memory.with_history(
+            "docs", "s1", [system, _user("Who maintains it?")]
+        )
+
+    assert asyncio.run(run()) == [
+        system,
+        _user("What is KAITO?"),
+        _assistant("An operator."),
+        _user("Who maintains it?"),
+    ]
+
+
+def test_sessions_are_scoped_to_the_index():
+    memory = ConversationMemory(LocalMemoryStore(max_turns=10, ttl_seconds=60))
+
+    async def run():
+        await memory.record("docs", "s1", [_user("hi")], "hello")
+        return await memory.with_history("other", "s1", [_user("hi")])
+
+    assert asyncio.run(run()) == [_user("hi")]
+
+
+def test_local_store_keeps_the_last_turns():
+    store = LocalMemoryStore(max_turns=2, ttl_seconds=60)
+
+    async def run():
+        for i in range(3):
+            await store.append("k", [_user(str(i))])
+        return await store.turns("k")
+
+    assert asyncio.run(run()) == [[_user("1")], [_user("2")]]
+
+
+def test_local_store_expires_idle_sessions():
+    clock = _Clock()
+    store = LocalMemoryStore(max_turns=10, ttl_seconds=60, clock=clock)
+
+    async def run():
+        await store.append("k", [_user("hi")])
+        clock.now = 59
+        assert await store.turns("k") == [[_user("hi")]]
+        clock.now = 61
+        return await store.turns("k")
+
+    assert asyncio.run(run()) == []
+
+
+def test_local_store_evicts_least_recently_used_sessions():
+    store = LocalMemoryStore(max_turns=10, ttl_seconds=60, max_sessions=2)
+
+    async def run():
+        await store.append("a", [_user("a")])
+        await store.append("b", [_user("b")])
+        await store.turns("a")
+        await store.append("c", [_user("c")])
+        return [await store.turns(k) for k in ("a", "b", "c")]
+
+    assert asyncio.run(run()) == [[[_user("a")]], [], [[_user("c")]]]
+
+
+def test_redis_store():
+    redis = _FakeRedis()
+    store = RedisMemoryStore(
+        "redis://unused", None, max_turns=2, ttl_seconds=3600, client=redis
+    )
+
+    async def run():
+        for i in range(3):
+            await store.append("s1:docs", [_user(str(i))])
+        return await store.turns("s1:docs")
+
+    assert asyncio.run(run()) == [[_user("1")], [_user("2")]]
+    assert redis.expiry == {"kaito:memory:s1:docs": 3600}
+
+
+def test_store_failures_degrade_to_stateless_chats():
+    memory = ConversationMemory(_BrokenStore())
+
+    async def run():
+        await memory.record("docs", "s1", [_user("hi")], "hello")
+        return await memory.with_history("docs", "s1", [_user("hi")])
+
+    assert asyncio.run(run()) == [_user("hi")]
+
+
+def test_create_conversation_memory():
+    assert create_conversation_memory("", 10, 60) is None
+    memory = create_conversation_memory("local", 10, 60)
+    assert isinstance(memory.store, LocalMemoryStore)
+    with pytest.raises(ValueError, match="CONVERSATION_MEMORY_REDIS_URL"):
+        create_conversation_memory("redis", 10, 60)
+    with pytest.raises(ValueError, match="Unsupported"):
+        create_conversation_memory("postgres", 10, 60)
@@ -471,6 +471,41 @@ func main() {}"""
         await vector_store_manager.persist("test_index", DEFAULT_VECTOR_DB_PERSIST_DIR)
         assert os.path.exists(DEFAULT_VECTOR_DB_PERSIST_DIR)
 
+    @pytest.mark.asyncio
+    async def test_evict_expired_documents(self, vector_store_manager):
+        index_name = "eviction_index"
+        documents = [
+            Document(text="Stale document", metadata={"type": "text"}),
+            Document(text="Fresh document", metadata={"type": "text"}),
+        ]
+        stale_id, fresh_id = await vector_store_manager.index_documents(
+            index_name, documents
+        )
+        vector_store_manager.document_times[index_name][stale_id] -= 7200
+
+        result = await vector_store_manager.evict_expired_documents(index_name, 3600)
+        assert result == {"name": index_name, "documents": 1, "evicted": 1}
+        assert not await vector_store_manager.document_exists(
+            index_name, documents[0], stale_id
+        )
+        assert await vector_store_manager.document_exists(
+            index_name, documents[1], fresh_id
+        )
+
+        # Without a TTL the documents are only counted.
+        result = await vector_store_manager.evict_expired_documents(index_name, None)
+        assert result == {"name": index_name, "documents": 1, "evicted": 0}
+
+        # Write times are persisted with the index.
+        await vector_store_manager.persist(index_name, DEFAULT_VECTOR_DB_PERSIST_DIR)
+        await vector_store_manager.load(
+            "loaded_eviction_index", DEFAULT_VECTOR_DB_PERSIST_DIR, overwrite=True
+        )
+        assert (
+            vector_store_manager.document_times["loaded_eviction_index"]
+            == vector_store_manager.document_times[index_name]
+        )
+
     @pytest.mark.asyncio
     async def test_delete_index(self, vector_store_manager):
         one_mb = b"\x00" * 1024
@@ -0,0 +1,111 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""
+Unit tests for retrieval modes of the HybridRetriever.
+"""
+
+from unittest.mock import AsyncMock, MagicMock, patch
+
+import pytest
+from llama_index.core.schema import NodeWithScore, TextNode
+
+from ragengine.vector_store.retriever.hybrid_retriever import (
+    HybridRetriever,
+    retrieval_weights,
+)
+
+
+def _nodes(*ids):
+    return [
+        NodeWithScore(node=TextNode(id_=nid, text=nid), score=1.0 / (i + 1))
+        for i, nid in enumerate(ids)
+    ]
+
+
+def _retriever(vector_weight, text_weight, vector_ids, keyword_ids):
+    index = MagicMock()
+    index.as_retriever.return_value.aretrieve = AsyncMock(
+        return_value=_nodes(*vector_ids)
+    )
+    bm25 = MagicMock()
+    bm25.aretrieve = AsyncMock(return_value=_nodes(*keyword_ids))
+    retriever = HybridRetriever(
+        index=index,
+        max_results=2,
+        vector_weight=vector_weight,
+        text_weight=text_weight,
+    )
+    return retriever, index, bm25
+
+
+@pytest.mark.parametrize(
+    "mode,weight,expected",
+    [
+        ("vector", 0.5, (1.0, 0.0)),
+        ("keyword", 0.5, (0.0, 1.0)),
+        ("hybrid", 0.7, (0.7, pytest.approx(0.3))),
+        ("hybrid", 1.5, (1.0, 0.0)),
+        ("HYBRID", 0.25, (0.25, 0.75)),
+    ],
+)
+def test_retrieval_weights(mode, weight, expected):
+    assert retrieval_weights(mode, weight) == expected
+
+
+@pytest.mark.asyncio
+async def test_vector_mode_skips_bm25():
+    retriever, index, bm25 = _retriever(1.0, 0.0, ["a", "b", "c"], ["x"])
+    with patch.object(
+        retriever, "_build_bm25_retriever", return_value=bm25
+    ) as build_bm25:
+        nodes = await retriever.aretrieve("query")
+
+    build_bm25.assert_not_called()
+    assert [n.node.node_id for n in nodes] == ["a", "b"]
+
+
+@pytest.mark.asyncio
+async def test_keyword_mode_skips_vector_search():
+    retriever, index, bm25 = _retriever(0.0, 1.0, ["a"], ["x", "y", "z"])
+    with patch.object(retriever, "_build_bm25_retriever", return_value=bm25):
+        nodes = await retriever.aretrieve("ERR-1234")
+
+    index.as_retriever.assert_not_called()
+    assert [n.node.node_id for n in nodes] == ["x", "y"]
+
+
+@pytest.mark.asyncio
+async def test_keyword_mode_falls_back_to_vector_without_bm25():
+    retriever, index, _ = _retriever(0.0, 1.0, ["a", "b"], [])
+    with patch.object(retriever, "_build_bm25_retriever", return_value=None):
+        nodes = await retriever.aretrieve("ERR-1234")
+
+    assert [n.node.node_id for n in nodes] == ["a", "b"]
+
+
+@pytest.mark.asyncio
+async def test_hybrid_mode_fuses_both():
+    retriever, index, bm25 = _retriever(0.5, 0.5, ["a", "b"], ["b", "c"])
+    with patch.object(retriever, "_build_bm25_retriever", return_value=bm25):
+        nodes = await retriever.aretrieve("query")
+
+    # b is ranked by both searches: 0.5 * 0.5 + 0.5 * 1.0
+    assert nodes[0].node.node_id == "b"
+    assert nodes[0].score == pytest.approx(0.75)
+
+
+def test_zero_weights_rejected():
+    with pytest.raises(ValueError):
+        HybridRetriever(index=MagicMock(), vector_weight=0.0, text_weight=0.0)
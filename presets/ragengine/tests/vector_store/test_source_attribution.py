@@ -0,0 +1,66 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+from llama_index.core.schema import NodeWithScore, QueryBundle, TextNode
+
+from ragengine.vector_store.node_processors.source_attribution_node_processor import (
+    CITATION_METADATA_KEY,
+    SourceAttributionProcessor,
+    source_metadata,
+)
+
+
+def _nodes(count):
+    return [
+        NodeWithScore(
+            node=TextNode(id_=f"n{i}", text=f"chunk {i}", metadata={"title": f"t{i}"}),
+            score=float(i),
+        )
+        for i in range(count)
+    ]
+
+
+def test_max_sources_bounds_the_chunks():
+    nodes = _nodes(5)
+    result = SourceAttributionProcessor(max_sources=2).postprocess_nodes(
+        nodes, QueryBundle("q")
+    )
+    assert [n.node.node_id for n in result] == ["n0", "n1"]
+    assert CITATION_METADATA_KEY not in result[0].node.metadata
+
+
+def test_citations_number_copies_of_the_chunks():
+    nodes = _nodes(3)
+    result = SourceAttributionProcessor(citations=True).postprocess_nodes(
+        nodes, QueryBundle("q")
+    )
+    assert [n.node.metadata[CITATION_METADATA_KEY] for n in result] == [1, 2, 3]
+    assert [n.node.node_id for n in result] == ["n0", "n1", "n2"]
+    assert "citation: 1" in result[0].node.get_content(metadata_mode="llm")
+    # The indexed nodes are left untouched.
+    assert all(CITATION_METADATA_KEY not in n.node.metadata for n in nodes)
+
+
+def test_source_metadata():
+    metadata = {"title": "Guide", "url": "https://example.com", "owner": "team-a"}
+    assert source_metadata(metadata, []) == metadata
+    assert source_metadata(metadata, ["title", "url"]) == {
+        "title": "Guide",
+        "url": "https://example.com",
+    }
+    assert source_metadata({CITATION_METADATA_KEY: 1, "title": "Guide"}, []) == {
+        "title": "Guide"
+    }
+    assert source_metadata(metadata, ["missing"]) is None
+    assert source_metadata(None, ["title"]) is None
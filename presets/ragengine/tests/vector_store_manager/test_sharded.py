@@ -0,0 +1,170 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import asyncio
+import os
+import sys
+
+import pytest
+from fastapi import HTTPException
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "../../..")))
+
+from ragengine.models import Document
+from ragengine.vector_store_manager.sharded import ShardedVectorStoreManager
+
+
+class _Response:
+    def __init__(self, status_code, body=None):
+        self.status_code = status_code
+        self._body = body
+        self.text = str(body)
+
+    def json(self):
+        return self._body
+
+
+class _Shard:
+    """In-memory stand-in for the RAG service of one index worker."""
+
+    def __init__(self):
+        self.indexes: dict[str, dict[str, Document]] = {}
+
+    def handle(self, method, path, params, body):
+        if method == "POST" and path == "/index":
+            docs = self.indexes.setdefault(body["index_name"], {})
+            out = []
+            for d in body["documents"]:
+                doc = Document(**d)
+                doc.doc_id = f"id-{doc.text}"
+                docs[doc.doc_id] = doc
+                out.append(doc.model_dump())
+            return _Response(200, out)
+        if method == "GET" and path == "/indexes":
+            return _Response(200, list(self.indexes))
+        if method == "POST" and path == "/retrieve":
+            docs = self.indexes.get(body["index_name"])
+            if docs is None:
+                return _Response(404, {"detail": "no index"})
+            results = sorted(
+                (
+                    {"doc_id": d.doc_id, "node_id": d.doc_id, "text": d.text, "score": float(len(d.text))}
+                    for d in docs.values()
+                ),
+                key=lambda r: r["score"],
+                reverse=True,
+            )[: body["max_node_count"]]
+            return _Response(200, {"query": body["query"], "results": results, "count": len(results)})
+        name = path.split("/")[2]
+        docs = self.indexes.get(name)
+        if docs is None:
+            return _Response(404, {"detail": "no index"})
+        if method == "GET":
+            page = list(docs.values())[params["offset"] : params["offset"] + params["limit"]]
+            return _Response(
+                200,
+                {"documents": [d.model_dump() for d in page], "count": len(page), "total_items": len(docs)},
+            )
+        if method == "POST" and path.endswith("/documents/delete"):
+            deleted = [i for i in body["doc_ids"] if docs.pop(i, None)]
+            return _Response(200, {"deleted_doc_ids": deleted, "not_found_doc_ids": []})
+        if method == "DELETE":
+            del self.indexes[name]
+            return _Response(200, {})
+        return _Response(500, {"detail": "unexpected"})
+
+
+class _Client:
+    def __init__(self, shards):
+        self.shards = shards
+
+    async def request(self, method, url, params=None, json=None):
+        shard, path = url.split("/", 3)[2], "/" + url.split("/", 3)[3]
+        return self.shards[shard].handle(method, path, params, json)
+
+    async def aclose(self):
+        pass
+
+
+def _manager(strategy="hash", metadata_field=""):
+    shards = {f"shard-{i}": _Shard() for i in range(3)}
+    manager = ShardedVectorStoreManager(
+        [f"http://shard-{i}" for i in range(3)],
+        strategy=strategy,
+        metadata_field=metadata_field,
+        client=_Client(shards),
+    )
+    return manager, shards
+
+
+def test_index_routes_each_document_to_one_shard():
+    manager, shards = _manager()
+    docs = [Document(text=f"doc {i}") for i in range(12)]
+
+    doc_ids = asyncio.run(manager.index("idx", docs))
+
+    assert doc_ids == [f"id-doc {i}" for i in range(12)]
+    stored = [d for s in shards.values() for d in s.indexes.get("idx", {})]
+    assert sorted(stored) == sorted(doc_ids)
+    assert sum(1 for s in shards.values() if s.indexes.get("idx")) > 1
+    # Routing is stable.
+    for doc in docs:
+        shard = shards[f"shard-{manager.shard_for(doc)}"]
+        assert f"id-{doc.text}" in shard.indexes["idx"]
+
+
+def test_metadata_field_keeps_values_together():
+    manager, _ = _manager("MetadataField", "tenant")
+    a = [Document(text=f"a{i}", metadata={"tenant": "a"}) for i in range(5)]
+    assert len({manager.shard_for(d) for d in a}) == 1
+
+
+def test_retrieve_merges_best_results():
+    manager, _ = _manager()
+    asyncio.run(manager.index("idx", [Document(text="x" * n) for n in range(1, 10)]))
+
+    result = asyncio.run(manager.retrieve("idx", "query", max_node_count=3))
+
+    assert [r["text"] for r in result["results"]] == ["x" * 9, "x" * 8, "x" * 7]
+    assert result["count"] == 3
+
+
+def test_missing_index_is_not_found():
+    manager, _ = _manager()
+    with pytest.raises(HTTPException) as exc:
+        asyncio.run(manager.retrieve("missing", "query"))
+    assert exc.value.status_code == 404
+
+
+def test_list_documents_pages_across_shards():
+    manager, _ = _manager()
+    asyncio.run(manager.index("idx", [Document(text=f"doc {i}") for i in range(10)]))
+
+    seen = []
+    for offset in range(0, 10, 4):
+        page = asyncio.run(manager.list_documents_in_index("idx", 4, offset, 100, None))
+        assert page["total_items"] == 10
+        seen.extend(d["doc_id"] for d in page["documents"])
+    assert sorted(seen) == sorted(f"id-doc {i}" for i in range(10))
+
+
+def test_delete_documents_and_index():
+    manager, shards = _manager()
+    asyncio.run(manager.index("idx", [Document(text=f"doc {i}") for i in range(6)]))
+    assert asyncio.run(manager.list_indexes()) == ["idx"]
+
+    result = asyncio.run(manager.delete_documents("idx", ["id-doc 1", "id-unknown"]))
+    assert result == {"deleted_doc_ids": ["id-doc 1"], "not_found_doc_ids": ["id-unknown"]}
+
+    asyncio.run(manager.delete_index("idx"))
+    assert asyncio.run(manager.list_indexes()) == []
@@ -14,6 +14,7 @@
 
 import asyncio
 import hashlib
+import json
 import logging
 import os
 import time
@@ -35,8 +36,13 @@ from pydantic import ValidationError
 from ragengine.config import (
     RAG_DEFAULT_CONTEXT_TOKEN_FILL_RATIO,
     RAG_DOCUMENT_NODE_TOKEN_APPROXIMATION,
+    RAG_INCLUDE_CITATIONS,
+    RAG_MAX_SOURCE_CHUNKS,
     RAG_MAX_TOP_K,
+    RAG_RETRIEVAL_MODE,
+    RAG_RETRIEVAL_VECTOR_WEIGHT,
     RAG_SIMILARITY_THRESHOLD,
+    RAG_SOURCE_METADATA_FIELDS,
 )
 from ragengine.embedding.base import BaseEmbeddingModel
 from ragengine.inference.inference import Inference
@@ -49,13 +55,26 @@ from ragengine.models import (
 from ragengine.vector_store.node_processors.contex_selection_node_processor import (
     ContextSelectionProcessor,
 )
-from ragengine.vector_store.retriever.hybrid_retriever import HybridRetriever
+from ragengine.vector_store.node_processors.source_attribution_node_processor import (
+    CITATION_METADATA_KEY,
+    CITATION_SYSTEM_PROMPT,
+    SourceAttributionProcessor,
+    source_metadata,
+)
+from ragengine.vector_store.retriever.hybrid_retriever import (
+    HybridRetriever,
+    retrieval_weights,
+)
 from ragengine.vector_store.transformers.custom_transformer import CustomTransformer
 
 # Configure logging
 logging.basicConfig(level=logging.INFO)
 logger = logging.getLogger(__name__)
 
+# DOCUMENT_TIMES_FILE is written next to a persisted index and records when each of its
+# documents was last written, so document TTLs survive restarts and backups.
+DOCUMENT_TIMES_FILE = "document_times.json"
+
 
 class BaseVectorStore(ABC):
     # Whether to use async indexing in VectorStoreIndex.from_documents.
@@ -78,6 +97,9 @@ class BaseVectorStore(ABC):
         self.use_rwlock = use_rwlock
         self.rwlock = aiorwlock.RWLock() if self.use_rwlock else None
         self.custom_transformer = CustomTransformer()
+        # When each document of an index was last written, in seconds since the epoch.
+        # Kept out of the document metadata so hashes and listed metadata are unchanged.
+        self.document_times: dict[str, dict[str, float]] = {}
 
     @staticmethod
     def generate_doc_id(text: str) -> str:
@@ -175,6 +197,7 @@ class BaseVectorStore(ABC):
                 )
                 index.set_index_id(index_name)
                 self.index_map[index_name] = index
+            self._record_document_times(index_name, indexed_doc_ids)
         return indexed_doc_ids
 
     async def chat_completion(self, request: dict) -> ChatCompletionResponse:
@@ -398,8 +421,13 @@ class BaseVectorStore(ABC):
                     llm=self.llm,
                     max_tokens=max_tokens,
                     similarity_threshold=RAG_SIMILARITY_THRESHOLD,
-                )
+                ),
+                SourceAttributionProcessor(
+                    max_sources=RAG_MAX_SOURCE_CHUNKS,
+                    citations=RAG_INCLUDE_CITATIONS,
+                ),
             ],
+            system_prompt=CITATION_SYSTEM_PROMPT if RAG_INCLUDE_CITATIONS else None,
         )
 
         logger.info("Processing chat completion request with prompt.")
@@ -478,7 +506,10 @@ class BaseVectorStore(ABC):
                         "node_id": source_node.node_id,
                         "text": source_node.text,
                         "score": source_node.score,
-                        "metadata": source_node.metadata,
+                        "metadata": source_metadata(
+                            source_node.metadata, RAG_SOURCE_METADATA_FIELDS
+                        ),
+                        "citation": source_node.metadata.get(CITATION_METADATA_KEY),
                     }
                     for source_node in chat_result.source_nodes
                 ],
@@ -525,6 +556,7 @@ class BaseVectorStore(ABC):
                     )
             else:
                 await asyncio.to_thread(self.index_map[index_name].insert, llama_doc)
+            self._record_document_times(index_name, [doc_id])
         except Exception:
             op_status = "error"
             raise
@@ -583,6 +615,7 @@ class BaseVectorStore(ABC):
                     return_exceptions=True,
                 )
 
+            self._forget_document_times(index_name, found_docs)
             return {"deleted_doc_ids": found_docs, "not_found_doc_ids": not_found_docs}
         except NotImplementedError as e:
             op_status = "error"
@@ -644,6 +677,9 @@ class BaseVectorStore(ABC):
                     updated_docs.append(doc)
                 else:
                     unchanged_docs.append(doc)
+            self._record_document_times(
+                index_name, [doc.doc_id for doc in updated_docs]
+            )
             return {
                 "updated_documents": updated_docs,
                 "unchanged_documents": unchanged_docs,
@@ -764,6 +800,7 @@ class BaseVectorStore(ABC):
                 del self.index_map[index_name]
         else:
             del self.index_map[index_name]
+        self.document_times.pop(index_name, None)
 
         logger.info(f"Index {index_name} deleted successfully.")
 
@@ -781,6 +818,77 @@ class BaseVectorStore(ABC):
                 total_count += 1
         return filtered_docs, total_count
 
+    def _record_document_times(self, index_name: str, doc_ids: list[str]):
+        """Remember that doc_ids were written to index_name now."""
+        now = time.time()
+        times = self.document_times.setdefault(index_name, {})
+        for doc_id in doc_ids:
+            times[doc_id] = now
+
+    def _forget_document_times(self, index_name: str, doc_ids: list[str]):
+        times = self.document_times.get(index_name, {})
+        for doc_id in doc_ids:
+            times.pop(doc_id, None)
+
+    @staticmethod
+    def _load_document_times(path: str) -> dict[str, float]:
+        """Read the document times persisted with an index, if any."""
+        try:
+            with open(os.path.join(path, DOCUMENT_TIMES_FILE)) as f:
+                times = json.load(f)
+        except (OSError, ValueError):
+            return {}
+        if not isinstance(times, dict):
+            return {}
+        return {
+            str(doc_id): float(t)
+            for doc_id, t in times.items()
+            if isinstance(t, int | float)
+        }
+
+    async def _document_ids(self, index_name: str) -> list[str]:
+        """Return the ids of the documents in an index."""
+        if self.use_rwlock:
+            async with self.rwlock.reader_lock:
+                return list(self.index_map[index_name].ref_doc_info)
+        return list(self.index_map[index_name].ref_doc_info)
+
+    async def evict_expired_documents(
+        self, index_name: str, ttl_seconds: float | None, now: float | None = None
+    ) -> dict[str, Any]:
+        """
+        Delete the documents of an index that were written more than ttl_seconds ago
+        and report how many documents were evicted and remain. Without a TTL nothing is
+        evicted. Documents whose write time is unknown, e.g. because they were indexed
+        by an earlier version, are treated as written now.
+        """
+        if index_name not in self.index_map:
+            raise HTTPException(
+                status_code=404, detail=f"No such index: '{index_name}' exists."
+            )
+        now = time.time() if now is None else now
+        doc_ids = await self._document_ids(index_name)
+        times = self.document_times.setdefault(index_name, {})
+        expired = []
+        for doc_id in doc_ids:
+            written = times.setdefault(doc_id, now)
+            if ttl_seconds is not None and written <= now - ttl_seconds:
+                expired.append(doc_id)
+        # Forget documents that left the index without going through this store.
+        for doc_id in set(times) - set(doc_ids):
+            del times[doc_id]
+
+        if expired:
+            logger.info(
+                f"Evicting {len(expired)} expired documents from index {index_name}."
+            )
+            await self.delete_documents(index_name, expired)
+        return {
+            "name": index_name,
+            "documents": len(doc_ids) - len(expired),
+            "evicted": len(expired),
+        }
+
     async def document_exists(
         self, index_name: str, doc: Document, doc_id: str
     ) -> bool:
@@ -817,6 +925,8 @@ class BaseVectorStore(ABC):
             # Persist the specific index
             storage_context = self.index_map[index_name].storage_context
             await asyncio.to_thread(storage_context.persist, path)
+            with open(os.path.join(path, DOCUMENT_TIMES_FILE), "w") as f:
+                json.dump(self.document_times.get(index_name, {}), f)
             logger.info(f"Successfully persisted index {index_name}.")
         except Exception as e:
             logger.error(f"Failed to persist index {index_name}. Error: {str(e)}")
@@ -876,6 +986,7 @@ class BaseVectorStore(ABC):
                 show_progress=True,
             )
             self.index_map[index_name] = loaded_index
+            self.document_times[index_name] = self._load_document_times(path)
             logger.info(f"Successfully loaded index {index_name}.")
         except Exception as e:
             logger.error(f"Failed to load index {index_name}. Error: {str(e)}")
@@ -921,9 +1032,16 @@ class BaseVectorStore(ABC):
                     vector_store_query_mode="hybrid",
                 )
             else:
+                # RAG_RETRIEVAL_MODE selects vector-only, keyword-only (BM25)
+                # or weighted hybrid retrieval.
+                vector_weight, text_weight = retrieval_weights(
+                    RAG_RETRIEVAL_MODE, RAG_RETRIEVAL_VECTOR_WEIGHT
+                )
                 retriever = HybridRetriever(
                     index=self.index_map[index_name],
                     max_results=top_k,
+                    vector_weight=vector_weight,
+                    text_weight=text_weight,
                     metadata_filter=metadata_filter,
                 )
 
@@ -953,7 +1071,9 @@ class BaseVectorStore(ABC):
                         "node_id": node.node.node_id,
                         "text": node.node.get_content(),
                         "score": score,
-                        "metadata": node.node.metadata if node.node.metadata else None,
+                        "metadata": source_metadata(
+                            node.node.metadata, RAG_SOURCE_METADATA_FIELDS
+                        ),
                     }
                 )
 
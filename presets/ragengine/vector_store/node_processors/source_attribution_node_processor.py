@@ -0,0 +1,89 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import logging
+
+from llama_index.core.bridge.pydantic import PrivateAttr
+from llama_index.core.postprocessor.types import BaseNodePostprocessor
+from llama_index.core.schema import NodeWithScore, QueryBundle
+
+# Metadata key holding the citation number of a context chunk. The chat engine
+# renders node metadata into the context, so the model sees "citation: <n>" above
+# each chunk.
+CITATION_METADATA_KEY = "citation"
+
+CITATION_SYSTEM_PROMPT = (
+    "Each context chunk starts with a citation number. When you use information "
+    "from a chunk, cite it with its number in square brackets, for example [1]. "
+    "Only cite chunks that support your answer."
+)
+
+logger = logging.getLogger(__name__)
+
+
+class SourceAttributionProcessor(BaseNodePostprocessor):
+    """
+    Source attribution processor.
+    This processor bounds the number of context chunks and numbers them for citations.
+    It runs after context selection, so the numbers match the chunks the model sees.
+    """
+
+    _max_sources: int = PrivateAttr()
+    _citations: bool = PrivateAttr()
+
+    def __init__(self, max_sources: int = 0, citations: bool = False) -> None:
+        super().__init__()
+        self._max_sources = max_sources
+        self._citations = citations
+
+    @classmethod
+    def class_name(cls) -> str:
+        return "SourceAttributionProcessor"
+
+    def _postprocess_nodes(
+        self,
+        nodes: list[NodeWithScore],
+        query_bundle: QueryBundle | None = None,
+    ) -> list[NodeWithScore]:
+        if self._max_sources > 0 and len(nodes) > self._max_sources:
+            logger.info(
+                f"Keeping {self._max_sources} source chunks out of {len(nodes)}."
+            )
+            nodes = nodes[: self._max_sources]
+        if not self._citations:
+            return nodes
+        # The nodes are copied so the citation numbers never reach the index.
+        return [
+            NodeWithScore(
+                node=node.node.model_copy(
+                    update={
+                        "metadata": {
+                            **node.node.metadata,
+                            CITATION_METADATA_KEY: citation,
+                        }
+                    }
+                ),
+                score=node.score,
+            )
+            for citation, node in enumerate(nodes, start=1)
+        ]
+
+
+def source_metadata(metadata: dict | None, fields: list[str]) -> dict | None:
+    """Return the metadata of a source, limited to the configured fields if any."""
+    if not metadata:
+        return None
+    metadata = {k: v for k, v in metadata.items() if k != CITATION_METADATA_KEY}
+    if fields:
+        metadata = {k: v for k, v in metadata.items() if k in fields}
+    return metadata or None
@@ -33,12 +33,21 @@ from qdrant_client.http.models import (
     MatchValue,
 )
 
-from ragengine.config import RAG_MAX_TOP_K
+from ragengine.config import (
+    RAG_MAX_TOP_K,
+    RAG_RETRIEVAL_MODE,
+    RAG_RETRIEVAL_VECTOR_WEIGHT,
+    RAG_SOURCE_METADATA_FIELDS,
+)
 from ragengine.embedding.base import BaseEmbeddingModel
 from ragengine.models import (
     Document,
     ListDocumentsResponse,
 )
+from ragengine.vector_store.node_processors.source_attribution_node_processor import (
+    source_metadata,
+)
+from ragengine.vector_store.retriever.hybrid_retriever import retrieval_weights
 
 from .base import BaseVectorStore
 
@@ -161,9 +170,25 @@ class QdrantVectorStoreHandler(BaseVectorStore):
 
             vector_store._hybrid_fusion_fn = _instrumented_fusion
 
+            # Map RAG_RETRIEVAL_MODE onto Qdrant's dense ("default"), sparse
+            # and hybrid query modes; alpha is the weight of the dense scores.
+            vector_weight, text_weight = retrieval_weights(
+                RAG_RETRIEVAL_MODE, RAG_RETRIEVAL_VECTOR_WEIGHT
+            )
+            if text_weight == 0:
+                query_mode = "default"
+                retriever_kwargs = {}
+            elif vector_weight == 0:
+                query_mode = "sparse"
+                retriever_kwargs = {"sparse_top_k": top_k}
+            else:
+                query_mode = "hybrid"
+                retriever_kwargs = {"alpha": vector_weight}
+
             retriever = self.index_map[index_name].as_retriever(
                 similarity_top_k=top_k,
-                vector_store_query_mode="hybrid",
+                vector_store_query_mode=query_mode,
+                **retriever_kwargs,
             )
 
             start_time = time.time()
@@ -186,6 +211,12 @@ class QdrantVectorStoreHandler(BaseVectorStore):
                     _captured_sparse.ids, _captured_sparse.similarities
                 ):
                     sparse_scores[nid] = score
+            # Fusion only runs in hybrid mode; otherwise every node comes from
+            # the single search that ran.
+            if query_mode != "hybrid":
+                single = dense_scores if query_mode == "default" else sparse_scores
+                for node in source_nodes:
+                    single[node.node.node_id] = node.score
 
             logger.info(
                 f"Hybrid retrieve for '{index_name}' completed in {elapsed:.3f}s, "
@@ -217,7 +248,9 @@ class QdrantVectorStoreHandler(BaseVectorStore):
                         "dense_score": dense_scores.get(nid),
                         "sparse_score": sparse_scores.get(nid),
                         "source": source,
-                        "metadata": node.node.metadata if node.node.metadata else None,
+                        "metadata": source_metadata(
+                            node.node.metadata, RAG_SOURCE_METADATA_FIELDS
+                        ),
                     }
                 )
 
@@ -375,6 +408,28 @@ class QdrantVectorStoreHandler(BaseVectorStore):
             documents=docs, count=len(docs), total_items=total_count
         )
 
+    # --- document ids (eviction) ---
+
+    async def _document_ids(self, index_name: str) -> list[str]:
+        """Collect the doc_ids of all points in Qdrant (bypasses empty docstore)."""
+        collection = self._get_collection_name(index_name)
+        doc_ids: dict[str, None] = {}
+        offset = None
+        while True:
+            records, offset = await self.aclient.scroll(
+                collection_name=collection,
+                limit=1000,
+                offset=offset,
+                with_payload=[QDRANT_DOC_ID_KEY],
+                with_vectors=False,
+            )
+            for record in records:
+                doc_id = (record.payload or {}).get(QDRANT_DOC_ID_KEY)
+                if doc_id:
+                    doc_ids[doc_id] = None
+            if offset is None:
+                return list(doc_ids)
+
     # --- document_exists ---
 
     async def document_exists(
@@ -446,6 +501,7 @@ class QdrantVectorStoreHandler(BaseVectorStore):
                         )
                     ),
                 )
+            self._forget_document_times(index_name, found)
             return {"deleted_doc_ids": found, "not_found_doc_ids": not_found}
         except Exception as e:
             op_status = "error"
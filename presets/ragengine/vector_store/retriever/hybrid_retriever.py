@@ -18,6 +18,8 @@ Uses weighted score fusion:
 
 where textScore is derived from BM25 rank via: 1 / (1 + rank).
 Default weights: vector=0.7, text=0.3 (normalized to sum to 1.0).
+A zero weight skips that search entirely, so the same retriever also serves
+vector-only and keyword-only retrieval.
 """
 
 import logging
@@ -57,6 +59,23 @@ def _build_metadata_filters(
     )
 
 
+def retrieval_weights(mode: str, vector_weight: float) -> tuple[float, float]:
+    """Return the (vector_weight, text_weight) pair for a retrieval mode.
+
+    mode is one of "vector", "keyword" or "hybrid"; vector_weight only applies
+    to "hybrid" and is clamped to [0, 1].
+    """
+    mode = (mode or "hybrid").lower()
+    if mode == "vector":
+        return 1.0, 0.0
+    if mode == "keyword":
+        return 0.0, 1.0
+    if mode != "hybrid":
+        logger.warning(f"Unknown retrieval mode '{mode}', using hybrid retrieval")
+    vector_weight = min(max(vector_weight, 0.0), 1.0)
+    return vector_weight, 1.0 - vector_weight
+
+
 class HybridRetriever(BaseRetriever):
     """Retriever that fuses vector similarity and BM25 keyword scores.
 
@@ -89,6 +108,8 @@ class HybridRetriever(BaseRetriever):
             metadata_filter: Optional {key: value} dict for metadata filtering.
         """
         total = vector_weight + text_weight
+        if total <= 0:
+            raise ValueError("vector_weight and text_weight cannot both be zero")
         self._vector_weight = vector_weight / total
         self._text_weight = text_weight / total
 
@@ -165,19 +186,51 @@ class HybridRetriever(BaseRetriever):
         scored.sort(key=lambda x: x.score if x.score is not None else 0.0, reverse=True)
         return scored[: self._max_results]
 
-    def _retrieve(self, query_bundle: QueryBundle) -> list[NodeWithScore]:
-        """Synchronous hybrid retrieval (falls back to vector-only if BM25 unavailable)."""
-        pool_size = self._candidate_pool_size
+    def _filter_keyword_nodes(
+        self, keyword_nodes: list[NodeWithScore]
+    ) -> list[NodeWithScore]:
+        """Apply the metadata filter to BM25 results (BM25 doesn't support native filtering)."""
+        if not self._metadata_filter:
+            return keyword_nodes
+        return [
+            n
+            for n in keyword_nodes
+            if all(
+                (n.node.metadata or {}).get(k) == v
+                for k, v in self._metadata_filter.items()
+            )
+        ]
 
-        # Vector retriever
-        vector_retriever = self._index.as_retriever(
-            similarity_top_k=pool_size,
+    def _vector_retriever(self) -> BaseRetriever:
+        return self._index.as_retriever(
+            similarity_top_k=self._candidate_pool_size,
             filters=self._llama_filters,
         )
-        vector_nodes = vector_retriever.retrieve(query_bundle)
 
+    def _keyword_retriever(self) -> "BM25Retriever | None":
+        """Build the BM25 retriever, or return None when keyword scores are unused."""
+        if self._text_weight == 0:
+            return None
+        bm25_retriever = self._build_bm25_retriever(self._candidate_pool_size)
+        if bm25_retriever is None and self._vector_weight == 0:
+            logger.warning(
+                "Keyword retrieval requested but BM25 is unavailable — "
+                "falling back to vector-only retrieval"
+            )
+        return bm25_retriever
+
+    def _retrieve(self, query_bundle: QueryBundle) -> list[NodeWithScore]:
+        """Synchronous hybrid retrieval (falls back to vector-only if BM25 unavailable)."""
         # BM25 retriever (fresh each time — fast, avoids stale cache)
-        bm25_retriever = self._build_bm25_retriever(pool_size)
+        bm25_retriever = self._keyword_retriever()
+        if bm25_retriever is not None and self._vector_weight == 0:
+            keyword_nodes = self._filter_keyword_nodes(
+                bm25_retriever.retrieve(query_bundle)
+            )
+            logger.info(f"Keyword-only retrieve: {len(keyword_nodes)} candidates")
+            return keyword_nodes[: self._max_results]
+
+        vector_nodes = self._vector_retriever().retrieve(query_bundle)
         if bm25_retriever is None:
             logger.info(f"Vector-only retrieve: {len(vector_nodes)} candidates")
             return vector_nodes[: self._max_results]
@@ -189,30 +242,21 @@ class HybridRetriever(BaseRetriever):
             f"{len(keyword_nodes)} BM25 candidates"
         )
 
-        # Apply metadata filter to keyword results (BM25 doesn't support native filtering)
-        if self._metadata_filter:
-            keyword_nodes = [
-                n
-                for n in keyword_nodes
-                if all(
-                    (n.node.metadata or {}).get(k) == v
-                    for k, v in self._metadata_filter.items()
-                )
-            ]
-
-        return self._fuse(vector_nodes, keyword_nodes)
+        return self._fuse(vector_nodes, self._filter_keyword_nodes(keyword_nodes))
 
     async def _aretrieve(self, query_bundle: QueryBundle) -> list[NodeWithScore]:
         """Async hybrid retrieval (falls back to vector-only if BM25 unavailable)."""
-        pool_size = self._candidate_pool_size
-
-        vector_retriever = self._index.as_retriever(
-            similarity_top_k=pool_size,
-            filters=self._llama_filters,
-        )
-        vector_nodes = await vector_retriever.aretrieve(query_bundle)
+        bm25_retriever = self._keyword_retriever()
+        if bm25_retriever is not None and self._vector_weight == 0:
+            keyword_nodes = self._filter_keyword_nodes(
+                await bm25_retriever.aretrieve(query_bundle)
+            )
+            logger.info(
+                f"Keyword-only retrieve (async): {len(keyword_nodes)} candidates"
+            )
+            return keyword_nodes[: self._max_results]
 
-        bm25_retriever = self._build_bm25_retriever(pool_size)
+        vector_nodes = await self._vector_retriever().aretrieve(query_bundle)
         if bm25_retriever is None:
             logger.info(f"Vector-only retrieve (async): {len(vector_nodes)} candidates")
             return vector_nodes[: self._max_results]
@@ -224,14 +268,4 @@ class HybridRetriever(BaseRetriever):
             f"{len(keyword_nodes)} BM25 candidates"
         )
 
-        if self._metadata_filter:
-            keyword_nodes = [
-                n
-                for n in keyword_nodes
-                if all(
-                    (n.node.metadata or {}).get(k) == v
-                    for k, v in self._metadata_filter.items()
-                )
-            ]
-
-        return self._fuse(vector_nodes, keyword_nodes)
+        return self._fuse(vector_nodes, self._filter_keyword_nodes(keyword_nodes))
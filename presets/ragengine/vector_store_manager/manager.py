@@ -65,6 +65,12 @@ class VectorStoreManager:
         """Delete an index."""
         return await self.vector_store.delete_index(index_name)
 
+    async def evict_expired_documents(
+        self, index_name: str, ttl_seconds: float | None
+    ) -> dict:
+        """Evict the documents of an index that are older than ttl_seconds."""
+        return await self.vector_store.evict_expired_documents(index_name, ttl_seconds)
+
     async def retrieve(
         self,
         index_name: str,
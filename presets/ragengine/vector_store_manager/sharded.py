@@ -0,0 +1,280 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""
+Router of a sharded RAGEngine. Every index is split across the index workers
+("shards"): documents are routed to one shard, queries are fanned out to all of
+them and the results are merged.
+"""
+
+import asyncio
+import hashlib
+import json
+import logging
+from typing import Any
+from urllib.parse import quote
+
+import httpx
+from fastapi import HTTPException
+
+from ragengine.models import Document
+
+logger = logging.getLogger(__name__)
+
+SHARD_STRATEGY_HASH = "hash"
+SHARD_STRATEGY_METADATA_FIELD = "metadatafield"
+
+
+def doc_id_for(text: str) -> str:
+    """Returns the ID a shard assigns to a document, see BaseVectorStore.generate_doc_id."""
+    return hashlib.sha256(text.encode("utf-8")).hexdigest()
+
+
+def _bucket(key: str, shards: int) -> int:
+    return int(hashlib.sha256(key.encode("utf-8")).hexdigest()[:16], 16) % shards
+
+
+class ShardedVectorStoreManager:
+    def __init__(
+        self,
+        shard_urls: list[str],
+        strategy: str = SHARD_STRATEGY_HASH,
+        metadata_field: str = "",
+        timeout: float = 300,
+        client: httpx.AsyncClient | None = None,
+    ):
+        if not shard_urls:
+            raise ValueError("At least one shard URL is required")
+        strategy = (strategy or SHARD_STRATEGY_HASH).lower()
+        if strategy not in (SHARD_STRATEGY_HASH, SHARD_STRATEGY_METADATA_FIELD):
+            raise ValueError(f"Unsupported sharding strategy: {strategy}")
+        if strategy == SHARD_STRATEGY_METADATA_FIELD and not metadata_field:
+            raise ValueError("A metadata field is required for the metadatafield strategy")
+        self.shard_urls = [url.rstrip("/") for url in shard_urls]
+        self.strategy = strategy
+        self.metadata_field = metadata_field
+        self.client = client or httpx.AsyncClient(timeout=timeout)
+
+    def shard_for(self, document: Document) -> int:
+        """Returns the shard a document is indexed on."""
+        if self.strategy == SHARD_STRATEGY_METADATA_FIELD:
+            value = (document.metadata or {}).get(self.metadata_field)
+            if value is not None:
+                return _bucket(str(value), len(self.shard_urls))
+        return _bucket(doc_id_for(document.text), len(self.shard_urls))
+
+    async def _request(self, shard: int, method: str, path: str, **kwargs) -> Any:
+        """Sends a request to a shard. Returns None if the shard does not have the index."""
+        try:
+            response = await self.client.request(
+                method, self.shard_urls[shard] + path, **kwargs
+            )
+        except httpx.HTTPError as e:
+            raise HTTPException(
+                status_code=502, detail=f"Shard {shard} is unavailable: {e}"
+            )
+        if response.status_code == 404:
+            return None
+        if response.status_code >= 400:
+            try:
+                detail = response.json().get("detail", response.text)
+            except ValueError:
+                detail = response.text
+            raise HTTPException(
+                status_code=response.status_code, detail=f"Shard {shard}: {detail}"
+            )
+        return response.json()
+
+    async def _fan_out(
+        self, index_name: str, method: str, path: str, **kwargs
+    ) -> list[Any]:
+        """Sends the request to every shard and returns the responses of the shards
+        that have the index. Raises 404 if no shard has it."""
+        results = await asyncio.gather(
+            *(
+                self._request(shard, method, path, **kwargs)
+                for shard in range(len(self.shard_urls))
+            )
+        )
+        found = [result for result in results if result is not None]
+        if not found:
+            raise HTTPException(
+                status_code=404, detail=f"No such index: '{index_name}' exists."
+            )
+        return found
+
+    @staticmethod
+    def _index_path(index_name: str) -> str:
+        return "/indexes/" + quote(index_name, safe="")
+
+    async def index(self, index_name: str, documents: list[Document]) -> list[str]:
+        """Index new documents, each on the shard it is routed to."""
+        batches: dict[int, list[int]] = {}
+        for i, document in enumerate(documents):
+            batches.setdefault(self.shard_for(document), []).append(i)
+
+        async def index_batch(shard: int, positions: list[int]) -> None:
+            body = {
+                "index_name": index_name,
+                "documents": [documents[i].model_dump() for i in positions],
+            }
+            indexed = await self._request(shard, "POST", "/index", json=body)
+            for i, document in zip(positions, indexed or [], strict=False):
+                doc_ids[i] = document["doc_id"]
+
+        doc_ids: list[str | None] = [None] * len(documents)
+        await asyncio.gather(
+            *(index_batch(shard, positions) for shard, positions in batches.items())
+        )
+        return doc_ids
+
+    async def chat_completion(self, request: dict):
+        raise HTTPException(
+            status_code=501,
+            detail="Chat completions are not supported by sharded RAGEngines; use /retrieve.",
+        )
+
+    async def list_indexes(self) -> list[str]:
+        """List the indexes of all shards."""
+        results = await asyncio.gather(
+            *(
+                self._request(shard, "GET", "/indexes")
+                for shard in range(len(self.shard_urls))
+            )
+        )
+        return sorted({name for result in results for name in result or []})
+
+    async def list_documents_in_index(
+        self,
+        index_name: str,
+        limit: int,
+        offset: int,
+        max_text_length: int,
+        metadata_filter: dict,
+    ) -> dict:
+        """List documents as if the shards were concatenated in order."""
+        params: dict[str, Any] = {}
+        if max_text_length is not None:
+            params["max_text_length"] = max_text_length
+        if metadata_filter:
+            params["metadata_filter"] = json.dumps(metadata_filter)
+
+        documents: list[dict] = []
+        total, found = 0, False
+        for shard in range(len(self.shard_urls)):
+            page = await self._request(
+                shard,
+                "GET",
+                self._index_path(index_name) + "/documents",
+                params={
+                    **params,
+                    "limit": max(1, limit - len(documents)),
+                    "offset": max(0, offset - total),
+                },
+            )
+            if page is None:
+                continue
+            found = True
+            documents.extend(page["documents"][: limit - len(documents)])
+            total += page["total_items"]
+        if not found:
+            raise HTTPException(
+                status_code=404, detail=f"No such index: '{index_name}' exists."
+            )
+        return {"documents": documents, "count": len(documents), "total_items": total}
+
+    async def update_documents(self, index_name: str, documents: list[Document]):
+        """Update documents on the shards that hold them."""
+        body = {"documents": [document.model_dump() for document in documents]}
+        results = await self._fan_out(
+            index_name,
+            "POST",
+            self._index_path(index_name) + "/documents",
+            json=body,
+        )
+        updated = {
+            d["doc_id"]: d for result in results for d in result["updated_documents"]
+        }
+        unchanged = {
+            d["doc_id"]: d for result in results for d in result["unchanged_documents"]
+        }
+        not_found = [
+            document.model_dump()
+            for document in documents
+            if document.doc_id not in updated and document.doc_id not in unchanged
+        ]
+        return {
+            "updated_documents": list(updated.values()),
+            "unchanged_documents": list(unchanged.values()),
+            "not_found_documents": not_found,
+        }
+
+    async def delete_documents(self, index_name: str, doc_ids: list[str]) -> dict:
+        """Delete documents from the shards that hold them."""
+        results = await self._fan_out(
+            index_name,
+            "POST",
+            self._index_path(index_name) + "/documents/delete",
+            json={"doc_ids": doc_ids},
+        )
+        deleted = {doc_id for result in results for doc_id in result["deleted_doc_ids"]}
+        return {
+            "deleted_doc_ids": [doc_id for doc_id in doc_ids if doc_id in deleted],
+            "not_found_doc_ids": [doc_id for doc_id in doc_ids if doc_id not in deleted],
+        }
+
+    async def persist(self, index_name: str, path: str) -> None:
+        """Persist the index on every shard, each to its own volume."""
+        await self._fan_out(
+            index_name,
+            "POST",
+            "/persist/" + quote(index_name, safe=""),
+            params={"path": path},
+        )
+
+    async def load(self, index_name: str, path: str, overwrite: bool) -> None:
+        """Load the index on every shard that has it persisted."""
+        await self._fan_out(
+            index_name,
+            "POST",
+            "/load/" + quote(index_name, safe=""),
+            params={"path": path, "overwrite": str(overwrite).lower()},
+        )
+
+    async def delete_index(self, index_name: str) -> None:
+        """Delete the index on every shard."""
+        await self._fan_out(index_name, "DELETE", self._index_path(index_name))
+
+    async def retrieve(
+        self,
+        index_name: str,
+        query: str,
+        max_node_count: int = 5,
+        metadata_filter: dict | None = None,
+    ):
+        """Retrieve the best matches of every shard and keep the overall best ones."""
+        body = {
+            "index_name": index_name,
+            "query": query,
+            "max_node_count": max_node_count,
+            "metadata_filter": metadata_filter,
+        }
+        results = await self._fan_out(index_name, "POST", "/retrieve", json=body)
+        nodes = [node for result in results for node in result["results"]]
+        nodes.sort(key=lambda node: node.get("score") or 0.0, reverse=True)
+        nodes = nodes[:max_node_count]
+        return {"query": query, "results": nodes, "count": len(nodes)}
+
+    async def shutdown(self):
+        """Shutdown the manager."""
+        await self.client.aclose()
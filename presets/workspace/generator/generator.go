@@ -783,6 +783,11 @@ func (g *Generator) loadFromCatalog() bool {
 
 	// Populate fields that FetchModelMetadata would have set
 	g.Param.Metadata.ModelFileSize = entry.ModelFileSize
+	// Gated models can only be downloaded with a token of an account that accepted the license.
+	if entry.Gated {
+		g.Param.Metadata.DownloadAuthRequired = true
+		g.Param.Metadata.GatedLicense = entry.License
+	}
 	g.Param.VLLM.ModelRunParams = make(map[string]string)
 
 	if entry.LoadFormat != "" {
@@ -29,6 +29,7 @@ type CatalogEntry struct {
 	Name              string   `yaml:"name"`
 	Description       string   `yaml:"description,omitempty"`
 	License           string   `yaml:"license,omitempty"`
+	Gated             bool     `yaml:"gated,omitempty"`
 	PipelineTag       string   `yaml:"pipelineTag,omitempty"`
 	BaseModel         []string `yaml:"baseModel,omitempty"`
 	ModelFileSize     string   `yaml:"modelFileSize"`
@@ -72,17 +73,22 @@ var optionalKeyMap = map[string][]string{
 }
 
 // fetchModelInfo fetches the model info from the HuggingFace API
-// and returns license, pipeline_tag, and base_model.
-func fetchModelInfo(g *Generator, repo string) (license, pipelineTag string, baseModel []string) {
+// and returns license, pipeline_tag, base_model and whether the repo is gated.
+func fetchModelInfo(g *Generator, repo string) (license, pipelineTag string, baseModel []string, gated bool) {
 	url := fmt.Sprintf("%s/api/models/%s", HuggingFaceWebsite, repo)
 	body, err := g.fetchURL(url)
 	if err != nil {
-		return "", "", nil
+		return "", "", nil, false
 	}
 
 	var info map[string]interface{}
 	if err := json.Unmarshal(body, &info); err != nil {
-		return "", "", nil
+		return "", "", nil, false
+	}
+
+	// gated is false for open repos and "auto" or "manual" for gated ones.
+	if v, ok := info["gated"].(string); ok && v != "" {
+		gated = true
 	}
 
 	// pipeline_tag: prefer top-level, fall back to cardData
@@ -123,15 +129,15 @@ func fetchModelInfo(g *Generator, repo string) (license, pipelineTag string, bas
 		}
 	}
 
-	return license, pipelineTag, baseModel
+	return license, pipelineTag, baseModel, gated
 }
 
 // FetchCatalogEntry fetches a CatalogEntry for a model repo from HuggingFace.
 func FetchCatalogEntry(repo, token string) (*CatalogEntry, error) {
 	g := NewGenerator(repo, token)
 
-	// Fetch model info (license, pipeline, base_model)
-	license, pipelineTag, baseModel := fetchModelInfo(g, repo)
+	// Fetch model info (license, pipeline, base_model, gated)
+	license, pipelineTag, baseModel, gated := fetchModelInfo(g, repo)
 
 	if err := g.FetchModelMetadata(); err != nil {
 		return nil, err
@@ -143,6 +149,7 @@ func FetchCatalogEntry(repo, token string) (*CatalogEntry, error) {
 		Name:          repo,
 		Description:   fmt.Sprintf("%s/%s", HuggingFaceWebsite, repo),
 		License:       license,
+		Gated:         gated,
 		PipelineTag:   pipelineTag,
 		BaseModel:     baseModel,
 		ModelFileSize: g.Param.Metadata.ModelFileSize,
@@ -110,6 +110,9 @@ func catalogFields(e *generator.CatalogEntry) map[string]string {
 	if e.License != "" {
 		m["license"] = e.License
 	}
+	if e.Gated {
+		m["gated"] = "true"
+	}
 	if e.PipelineTag != "" {
 		m["pipelineTag"] = e.PipelineTag
 	}
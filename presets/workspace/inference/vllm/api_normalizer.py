@@ -0,0 +1,382 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""API normalization sidecar for the KAITO vLLM preset.
+
+Listens on the inference port and forwards every request to vLLM. Completion
+and chat completion requests are normalized first: the output token limit is
+renamed to the field the server expects, and requests without a model get the
+default one. A request header may select one of the LoRA adapters of the
+workspace instead of the model in the body. Optionally, one endpoint is served
+through the other, and the responses, including streamed ones, are converted
+back to the format the client asked for.
+"""
+
+import argparse
+import json
+import logging
+import time
+
+logger = logging.getLogger(__name__)
+
+COMPLETIONS_PATH = "/v1/completions"
+CHAT_COMPLETIONS_PATH = "/v1/chat/completions"
+
+TRANSLATE_NONE = "None"
+TRANSLATE_CHAT_TO_COMPLETIONS = "ChatToCompletions"
+TRANSLATE_COMPLETIONS_TO_CHAT = "CompletionsToChat"
+
+MAX_TOKENS_FIELDS = ("max_tokens", "max_completion_tokens")
+
+# Parameters that only exist for one of the endpoints and are dropped on translation.
+CHAT_ONLY_PARAMS = ("messages", "tools", "tool_choice", "parallel_tool_calls", "logprobs", "top_logprobs")
+COMPLETIONS_ONLY_PARAMS = ("prompt", "echo", "suffix", "best_of", "logprobs")
+
+# Headers that describe a single hop and must not be forwarded.
+HOP_BY_HOP_HEADERS = {
+    "connection",
+    "keep-alive",
+    "proxy-authenticate",
+    "proxy-authorization",
+    "te",
+    "trailer",
+    "transfer-encoding",
+    "upgrade",
+    "host",
+    "content-length",
+}
+
+
+class TranslationError(ValueError):
+    """A request that cannot be served through the other endpoint."""
+
+
+class AdapterNotFoundError(ValueError):
+    """A request whose adapter header names an adapter the workspace does not serve."""
+
+
+def upstream_path(path: str, translate: str) -> str:
+    """Return the vLLM endpoint that serves a request sent to path."""
+    if translate == TRANSLATE_CHAT_TO_COMPLETIONS and path == CHAT_COMPLETIONS_PATH:
+        return COMPLETIONS_PATH
+    if translate == TRANSLATE_COMPLETIONS_TO_CHAT and path == COMPLETIONS_PATH:
+        return CHAT_COMPLETIONS_PATH
+    return path
+
+
+def normalize_request(body: dict, max_tokens_field: str, default_model: str | None) -> dict:
+    """Rename the output token limit and fill in the model."""
+    body = dict(body)
+    limit = body.get(max_tokens_field)
+    for field in MAX_TOKENS_FIELDS:
+        if field != max_tokens_field and field in body:
+            value = body.pop(field)
+            if limit is None:
+                limit = value
+    if limit is not None:
+        body[max_tokens_field] = limit
+    if not body.get("model") and default_model:
+        body["model"] = default_model
+    return body
+
+
+def select_adapter(body: dict, adapter: str | None, adapters) -> dict:
+    """Set the adapter named in the adapter header as the model of the request."""
+    if not adapter:
+        return body
+    if adapter not in adapters:
+        raise AdapterNotFoundError(f"adapter {adapter!r} is not served by this workspace")
+    body = dict(body)
+    body["model"] = adapter
+    return body
+
+
+def _content_text(content) -> str:
+    if isinstance(content, str):
+        return content
+    if isinstance(content, list):
+        parts = []
+        for part in content:
+            if isinstance(part, dict) and part.get("type", "text") == "text" and isinstance(part.get("text"), str):
+                parts.append(part["text"])
+            elif isinstance(part, str):
+                parts.append(part)
+            else:
+                raise TranslationError("only text content can be sent to /v1/completions")
+        return "".join(parts)
+    if content is None:
+        return ""
+    raise TranslationError("message content must be a string or a list of text parts")
+
+
+def render_messages(messages) -> str:
+    """Render chat messages as a plain prompt for models without a chat template."""
+    if not isinstance(messages, list) or not messages:
+        raise TranslationError("messages must be a non-empty list")
+    lines = []
+    for message in messages:
+        if not isinstance(message, dict):
+            raise TranslationError("each message must be an object")
+        lines.append(f"{message.get('role', 'user')}: {_content_text(message.get('content'))}")
+    lines.append("assistant:")
+    return "\n".join(lines)
+
+
+def chat_to_completion_request(body: dict) -> dict:
+    prompt = render_messages(body.get("messages"))
+    request = {k: v for k, v in body.items() if k not in CHAT_ONLY_PARAMS}
+    request["prompt"] = prompt
+    return request
+
+
+def completion_to_chat_request(body: dict) -> dict:
+    prompt = body.get("prompt")
+    if isinstance(prompt, list) and len(prompt) == 1 and isinstance(prompt[0], str):
+        prompt = prompt[0]
+    if not isinstance(prompt, str):
+        raise TranslationError("prompt must be a single string to be sent to /v1/chat/completions")
+    request = {k: v for k, v in body.items() if k not in COMPLETIONS_ONLY_PARAMS}
+    request["messages"] = [{"role": "user", "content": prompt}]
+    return request
+
+
+def _envelope(resp: dict, obj: str) -> dict:
+    return {
+        "id": resp.get("id", ""),
+        "object": obj,
+        "created": resp.get("created", int(time.time())),
+        "model": resp.get("model", ""),
+    }
+
+
+def completion_to_chat_response(resp: dict, stream: bool = False) -> dict:
+    """Convert a completion response, or one streamed chunk, to the chat format."""
+    out = _envelope(resp, "chat.completion.chunk" if stream else "chat.completion")
+    choices = []
+    for choice in resp.get("choices", []):
+        converted = {"index": choice.get("index", 0), "finish_reason": choice.get("finish_reason")}
+        if stream:
+            converted["delta"] = {"content": choice.get("text", "")}
+        else:
+            converted["message"] = {"role": "assistant", "content": choice.get("text", "")}
+        choices.append(converted)
+    out["choices"] = choices
+    if resp.get("usage") is not None:
+        out["usage"] = resp["usage"]
+    return out
+
+
+def chat_to_completion_response(resp: dict, stream: bool = False) -> dict:
+    """Convert a chat response, or one streamed chunk, to the completion format."""
+    out = _envelope(resp, "text_completion")
+    choices = []
+    for choice in resp.get("choices", []):
+        source = choice.get("delta" if stream else "message") or {}
+        choices.append(
+            {
+                "index": choice.get("index", 0),
+                "text": source.get("content") or "",
+                "logprobs": None,
+                "finish_reason": choice.get("finish_reason"),
+            }
+        )
+    out["choices"] = choices
+    if resp.get("usage") is not None:
+        out["usage"] = resp["usage"]
+    return out
+
+
+def response_converter(path: str, translate: str):
+    """Return the function that converts upstream responses for a request sent to path."""
+    upstream = upstream_path(path, translate)
+    if upstream == path:
+        return None
+    if upstream == COMPLETIONS_PATH:
+        return completion_to_chat_response
+    return chat_to_completion_response
+
+
+def convert_sse_line(line: str, convert) -> str:
+    """Convert one line of a server-sent event stream."""
+    if not line.startswith("data:"):
+        return line
+    payload = line[len("data:") :].strip()
+    if payload == "[DONE]":
+        return line
+    try:
+        chunk = json.loads(payload)
+    except ValueError:
+        return line
+    if not isinstance(chunk, dict) or "choices" not in chunk:
+        return line
+    return "data: " + json.dumps(convert(chunk, stream=True))
+
+
+def error_body(message: str, code: int = 400) -> bytes:
+    return json.dumps({"error": {"message": message, "type": "invalid_request_error", "code": code}}).encode()
+
+
+class ModelResolver:
+    """Returns the default model, asking the inference server once if none is configured."""
+
+    def __init__(self, client, default_model: str | None):
+        self._client = client
+        self._model = default_model
+
+    async def get(self) -> str | None:
+        if self._model:
+            return self._model
+        try:
+            resp = await self._client.get("/v1/models")
+            models = resp.json().get("data", [])
+            if resp.status_code == 200 and models:
+                self._model = models[0].get("id")
+        except Exception as e:  # the server may still be loading
+            logger.warning("listing the served models failed: %s", e)
+        return self._model
+
+
+def build_app(
+    translate: str,
+    max_tokens_field: str,
+    default_model: str | None,
+    upstream: str,
+    adapter_header: str | None = None,
+    adapters=(),
+):
+    import httpx
+    from starlette.applications import Starlette
+    from starlette.background import BackgroundTask
+    from starlette.requests import Request
+    from starlette.responses import Response, StreamingResponse
+    from starlette.routing import Route
+
+    client = httpx.AsyncClient(base_url=upstream, timeout=None)
+    models = ModelResolver(client, default_model)
+
+    def forward_headers(request: Request) -> dict:
+        return {k: v for k, v in request.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    def response_headers(resp) -> dict:
+        return {k: v for k, v in resp.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    async def prepare(path: str, raw_body: bytes, adapter: str | None) -> tuple[str, bytes]:
+        try:
+            body = json.loads(raw_body)
+        except (ValueError, UnicodeDecodeError):
+            return path, raw_body
+        if not isinstance(body, dict):
+            return path, raw_body
+        body = select_adapter(body, adapter, adapters)
+        body = normalize_request(body, max_tokens_field, None if body.get("model") else await models.get())
+        target = upstream_path(path, translate)
+        if target == COMPLETIONS_PATH and path != target:
+            body = chat_to_completion_request(body)
+        elif target == CHAT_COMPLETIONS_PATH and path != target:
+            body = completion_to_chat_request(body)
+        return target, json.dumps(body).encode()
+
+    async def proxy(request: Request) -> Response:
+        raw_body = await request.body()
+        path = request.url.path
+        convert = None
+        if request.method == "POST" and path in (COMPLETIONS_PATH, CHAT_COMPLETIONS_PATH):
+            adapter = request.headers.get(adapter_header) if adapter_header else None
+            try:
+                target, raw_body = await prepare(path, raw_body, adapter)
+            except AdapterNotFoundError as e:
+                return Response(error_body(str(e), 404), status_code=404, media_type="application/json")
+            except TranslationError as e:
+                return Response(error_body(str(e)), status_code=400, media_type="application/json")
+            convert = response_converter(path, translate)
+            path = target
+
+        upstream_request = client.build_request(
+            request.method,
+            path,
+            params=request.query_params,
+            headers=forward_headers(request),
+            content=raw_body,
+        )
+        resp = await client.send(upstream_request, stream=True)
+        headers = response_headers(resp)
+        if convert is None or resp.status_code != 200:
+            return StreamingResponse(
+                resp.aiter_raw(),
+                status_code=resp.status_code,
+                headers=headers,
+                background=BackgroundTask(resp.aclose),
+            )
+
+        if resp.headers.get("content-type", "").startswith("text/event-stream"):
+
+            async def events():
+                async for line in resp.aiter_lines():
+                    yield convert_sse_line(line, convert) + "\n"
+
+            return StreamingResponse(
+                events(),
+                status_code=resp.status_code,
+                headers=headers,
+                background=BackgroundTask(resp.aclose),
+            )
+
+        body = await resp.aread()
+        await resp.aclose()
+        try:
+            body = json.dumps(convert(json.loads(body))).encode()
+        except (ValueError, AttributeError) as e:
+            logger.warning("converting the response failed: %s", e)
+        return Response(body, status_code=resp.status_code, media_type="application/json")
+
+    methods = ["GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"]
+    return Starlette(
+        routes=[Route("/{path:path}", proxy, methods=methods)],
+        on_shutdown=[client.aclose],
+    )
+
+
+def parse_args(argv=None):
+    parser = argparse.ArgumentParser(description="KAITO API normalization sidecar")
+    parser.add_argument("--port", type=int, default=5000)
+    parser.add_argument("--upstream-port", type=int, default=5001)
+    parser.add_argument(
+        "--translate",
+        choices=[TRANSLATE_NONE, TRANSLATE_CHAT_TO_COMPLETIONS, TRANSLATE_COMPLETIONS_TO_CHAT],
+        default=TRANSLATE_NONE,
+    )
+    parser.add_argument("--max-tokens-field", choices=MAX_TOKENS_FIELDS, default="max_tokens")
+    parser.add_argument("--default-model", default="")
+    parser.add_argument("--adapter-header", default="", help="Request header that selects a LoRA adapter.")
+    parser.add_argument("--adapters", default="", help="Comma-separated names of the adapters the header can select.")
+    return parser.parse_args(argv)
+
+
+def main(argv=None):
+    import uvicorn
+
+    logging.basicConfig(level=logging.INFO)
+    args = parse_args(argv)
+    app = build_app(
+        args.translate,
+        args.max_tokens_field,
+        args.default_model or None,
+        f"http://127.0.0.1:{args.upstream_port}",
+        args.adapter_header or None,
+        frozenset(a for a in args.adapters.split(",") if a),
+    )
+    uvicorn.run(app, host="0.0.0.0", port=args.port, log_level="warning")
+
+
+if __name__ == "__main__":
+    main()
@@ -13,7 +13,10 @@
 
 import argparse
 import collections
+import importlib.util
+import json
 import logging
+import logging.handlers
 import os
 import socket
 import subprocess
@@ -45,6 +48,56 @@ logging.basicConfig(
     datefmt="%m-%d %H:%M:%S",
 )
 
+
+class JSONLogFormatter(logging.Formatter):
+    """Formats log records as single-line JSON objects for log shippers."""
+
+    def format(self, record: logging.LogRecord) -> str:
+        entry = {
+            "time": self.formatTime(record, "%Y-%m-%dT%H:%M:%S%z"),
+            "level": record.levelname,
+            "logger": record.name,
+            "location": f"{record.filename}:{record.lineno}",
+            "message": record.getMessage(),
+        }
+        if record.exc_info:
+            entry["exception"] = self.formatException(record.exc_info)
+        return json.dumps(entry)
+
+
+def configure_log_format(log_format: str) -> None:
+    """Switch the root and vLLM loggers to JSON output when requested by KAITO."""
+    if log_format != "json":
+        return
+    formatter = JSONLogFormatter()
+    for name in ("", "vllm"):
+        for handler in logging.getLogger(name).handlers:
+            handler.setFormatter(formatter)
+
+
+def configure_log_file(log_file: str) -> None:
+    """Also write the root and vLLM logs to log_file for the KAITO log forwarder sidecar.
+
+    The file lives on a volume shared with the sidecar, so it is rotated to stay small.
+    """
+    if not log_file:
+        return
+    root_handlers = logging.getLogger().handlers
+    handler = logging.handlers.RotatingFileHandler(
+        log_file, maxBytes=32 * 1024 * 1024, backupCount=1
+    )
+    if root_handlers:
+        handler.setFormatter(root_handlers[0].formatter)
+    logging.getLogger().addHandler(handler)
+    # vLLM configures its logger not to propagate to the root logger.
+    vllm_logger = logging.getLogger("vllm")
+    if not vllm_logger.propagate:
+        vllm_logger.addHandler(handler)
+
+
+configure_log_format(os.environ.get("KAITO_LOG_FORMAT", "text").lower())
+configure_log_file(os.environ.get("KAITO_LOG_FILE", ""))
+
 # Prometheus metrics for model download monitoring.
 # Explicitly registered in vLLM's prometheus registry so they appear at the
 # /metrics endpoint served by vLLM's FastAPI app.
@@ -130,6 +183,10 @@ class KAITOArgumentParser(argparse.ArgumentParser):
         kaito_args = args[0]
         runtime_args = args[1]  # Remaining args
 
+        # The cached value is applied before the KAITO config is merged, so an
+        # explicit max-model-len in the config file still wins.
+        apply_cached_max_model_len(runtime_args)
+
         # Load KAITO config
         if kaito_args.kaito_config_file:
             file_config = KaitoConfig.from_yaml(kaito_args.kaito_config_file)
@@ -496,6 +553,50 @@ def get_max_gpu_memory_utilization(device_index: int = 0) -> float:
     return gpu_memory_utilization
 
 
+def apply_cached_max_model_len(runtime_args: list[str]) -> None:
+    """Replace --max-model-len=auto with the cached profiling result.
+
+    The controller sets KAITO_MAX_MODEL_LEN from its profile cache, which holds the
+    max-model-len vLLM resolved for the same model, instance type and runtime
+    version. The cached value is only used when the command line asks vLLM to
+    profile the context length; an explicit value is kept.
+    """
+    cached = os.environ.get("KAITO_MAX_MODEL_LEN", "")
+    if not cached:
+        return
+    if not cached.isdigit() or int(cached) <= 0:
+        logger.warning("Ignoring invalid KAITO_MAX_MODEL_LEN %r", cached)
+        return
+
+    value = None
+    for i, arg in enumerate(runtime_args):
+        name, sep, arg_value = arg.partition("=")
+        if name.replace("_", "-") != "--max-model-len":
+            continue
+        if sep:
+            value = arg_value
+        elif i + 1 < len(runtime_args):
+            value = runtime_args[i + 1]
+    if value != "auto":
+        return
+
+    logger.info("Using cached max-model-len %s instead of profiling it", cached)
+    runtime_args.append(f"--max-model-len={cached}")
+
+
+def report_profile_result(engine_client) -> None:
+    """Log the max-model-len vLLM resolved, for the controller's profile cache.
+
+    The line is printed rather than logged so its format does not depend on
+    KAITO_LOG_FORMAT:
+
+        KAITO_PROFILE_RESULT <RFC3339-timestamp> <JSON-payload>
+    """
+    payload = {"max_model_len": engine_client.vllm_config.model_config.max_model_len}
+    ts = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime())
+    print(f"KAITO_PROFILE_RESULT {ts} {json.dumps(payload)}", flush=True)
+
+
 def set_kv_transfer_config_if_applicable(args: argparse.Namespace) -> None:
     """
     Set KV transfer config and optionally enable KV cache offloading to CPU RAM.
@@ -520,6 +621,13 @@ def set_kv_transfer_config_if_applicable(args: argparse.Namespace) -> None:
         )
         return
 
+    # The arm64 image is built without LMCache, which publishes no aarch64 cu129 wheels.
+    if importlib.util.find_spec("lmcache") is None:
+        logger.warning(
+            "LMCache is not installed in this image, do not use KV cache offload to CPU RAM."
+        )
+        return
+
     os.environ["LMCACHE_CHUNK_SIZE"] = "256"
     os.environ["LMCACHE_LOCAL_CPU"] = "True"
     available_memory_gb = (
@@ -632,6 +740,8 @@ if __name__ == "__main__":
 
         _wrap_build_and_serve(_stop_pre_download_metrics)
 
+    _wrap_build_and_serve(report_profile_result)
+
     # Install the queue-depth rate limit guard via vLLM's built-in --middleware
     # extension point. vLLM imports the dotted path and registers it on its
     # FastAPI app during build_app — no monkey-patching needed on our side.
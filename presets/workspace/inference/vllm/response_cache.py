@@ -0,0 +1,331 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Response cache sidecar for the KAITO vLLM preset.
+
+Listens on the inference port and forwards every request to vLLM. Successful
+non-streaming completion and chat completion responses are cached, either in
+an in-process LRU or in Redis, and repeated requests of the same caller are
+answered from the cache. Hit and miss counters are appended to the vLLM ``/metrics`` output so
+they are scraped together with the engine metrics.
+"""
+
+import argparse
+import hashlib
+import json
+import logging
+import os
+import time
+from collections import OrderedDict
+
+from prometheus_client import CollectorRegistry, Counter, generate_latest
+
+logger = logging.getLogger(__name__)
+
+CACHEABLE_PATHS = ("/v1/completions", "/v1/chat/completions")
+
+# Requests and responses larger than this are forwarded but never cached.
+MAX_CACHED_BYTES = 8 * 1024 * 1024
+
+KEY_POLICY_EXACT = "ExactPrompt"
+KEY_POLICY_NORMALIZED = "NormalizedPrompt"
+
+# The caller identity headers that are always part of the cache key, so that a response
+# is never served to a caller with other credentials.
+DEFAULT_IDENTITY_HEADERS = ("authorization",)
+
+# Headers that describe a single hop and must not be forwarded.
+HOP_BY_HOP_HEADERS = {
+    "connection",
+    "keep-alive",
+    "proxy-authenticate",
+    "proxy-authorization",
+    "te",
+    "trailer",
+    "transfer-encoding",
+    "upgrade",
+    "host",
+    "content-length",
+}
+
+registry = CollectorRegistry()
+
+kaito_response_cache_requests_total = Counter(
+    "kaito_response_cache_requests_total",
+    "Cacheable requests seen by the KAITO response cache, by result (hit, miss or bypass)",
+    ["result"],
+    registry=registry,
+)
+
+kaito_response_cache_errors_total = Counter(
+    "kaito_response_cache_errors_total",
+    "Cache backend errors; the request is forwarded to the inference server instead",
+    registry=registry,
+)
+
+
+def _normalize_text(text: str) -> str:
+    return " ".join(text.split())
+
+
+def _normalize_content(content):
+    if isinstance(content, str):
+        return _normalize_text(content)
+    if isinstance(content, list):
+        parts = []
+        for part in content:
+            if isinstance(part, dict) and isinstance(part.get("text"), str):
+                part = dict(part, text=_normalize_text(part["text"]))
+            elif isinstance(part, str):
+                part = _normalize_text(part)
+            parts.append(part)
+        return parts
+    return content
+
+
+def _normalize_request(body: dict) -> dict:
+    body = dict(body)
+    if "prompt" in body:
+        body["prompt"] = _normalize_content(body["prompt"])
+    if isinstance(body.get("messages"), list):
+        messages = []
+        for message in body["messages"]:
+            if isinstance(message, dict) and "content" in message:
+                message = dict(message, content=_normalize_content(message["content"]))
+            messages.append(message)
+        body["messages"] = messages
+    return body
+
+
+def request_identity(headers, identity_headers) -> str:
+    """Return a digest of the caller identity headers of a request.
+
+    headers is a Starlette Headers or a mapping of lower case names to lists of values.
+    """
+    names = sorted({h.lower() for h in (*DEFAULT_IDENTITY_HEADERS, *identity_headers)})
+    h = hashlib.sha256()
+    for name in names:
+        for value in headers.getlist(name) if hasattr(headers, "getlist") else headers.get(name, []):
+            h.update(f"{name}:{value}\n".encode())
+    return h.hexdigest()
+
+
+def cache_key(path: str, raw_body: bytes, key_policy: str, identity: str = "") -> str | None:
+    """Return the cache key of a request, or None if it must not be cached.
+
+    identity is the digest of the caller identity headers from request_identity.
+    """
+    if path not in CACHEABLE_PATHS or len(raw_body) > MAX_CACHED_BYTES:
+        return None
+    try:
+        body = json.loads(raw_body)
+    except (ValueError, UnicodeDecodeError):
+        return None
+    if not isinstance(body, dict) or body.get("stream"):
+        return None
+    if key_policy == KEY_POLICY_NORMALIZED:
+        body = _normalize_request(body)
+    canonical = json.dumps(body, sort_keys=True, separators=(",", ":"), ensure_ascii=False)
+    digest = hashlib.sha256(f"{identity}\n{path}\n{canonical}".encode()).hexdigest()
+    return f"kaito:response-cache:{digest}"
+
+
+def wants_bypass(cache_control: str) -> bool:
+    """Clients can skip the cache with Cache-Control: no-cache or no-store."""
+    directives = {d.strip().lower() for d in cache_control.split(",")}
+    return bool(directives & {"no-cache", "no-store"})
+
+
+class MemoryCache:
+    """LRU cache with a TTL, local to the sidecar process."""
+
+    def __init__(self, max_entries: int, ttl_seconds: float, clock=time.monotonic):
+        self._entries: OrderedDict[str, tuple[float, bytes]] = OrderedDict()
+        self._max_entries = max_entries
+        self._ttl = ttl_seconds
+        self._clock = clock
+
+    async def get(self, key: str) -> bytes | None:
+        entry = self._entries.get(key)
+        if entry is None:
+            return None
+        expires, value = entry
+        if expires <= self._clock():
+            del self._entries[key]
+            return None
+        self._entries.move_to_end(key)
+        return value
+
+    async def set(self, key: str, value: bytes) -> None:
+        self._entries[key] = (self._clock() + self._ttl, value)
+        self._entries.move_to_end(key)
+        while len(self._entries) > self._max_entries:
+            self._entries.popitem(last=False)
+
+    def __len__(self) -> int:
+        return len(self._entries)
+
+
+class RedisCache:
+    """Cache shared by all pods of the workspace."""
+
+    def __init__(self, address: str, password: str | None, tls: bool, ttl_seconds: float):
+        import redis.asyncio as redis
+
+        host, _, port = address.rpartition(":")
+        self._client = redis.Redis(
+            host=host.strip("[]"),
+            port=int(port),
+            password=password or None,
+            ssl=tls,
+            socket_timeout=1,
+            socket_connect_timeout=1,
+        )
+        self._ttl = max(int(ttl_seconds), 1)
+
+    async def get(self, key: str) -> bytes | None:
+        return await self._client.get(key)
+
+    async def set(self, key: str, value: bytes) -> None:
+        await self._client.set(key, value, ex=self._ttl)
+
+
+def encode_entry(content_type: str, body: bytes) -> bytes:
+    return content_type.encode() + b"\n" + body
+
+
+def decode_entry(value: bytes) -> tuple[str, bytes]:
+    content_type, _, body = value.partition(b"\n")
+    return content_type.decode(), body
+
+
+def build_app(cache, key_policy: str, upstream: str, identity_headers=()):
+    import httpx
+    from starlette.applications import Starlette
+    from starlette.background import BackgroundTask
+    from starlette.requests import Request
+    from starlette.responses import Response, StreamingResponse
+    from starlette.routing import Route
+
+    client = httpx.AsyncClient(base_url=upstream, timeout=None)
+
+    def forward_headers(request: Request) -> dict:
+        return {k: v for k, v in request.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    def response_headers(resp) -> dict:
+        return {k: v for k, v in resp.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    async def metrics(request: Request) -> Response:
+        resp = await client.get("/metrics", headers=forward_headers(request))
+        body = resp.content + b"\n" + generate_latest(registry)
+        return Response(body, status_code=resp.status_code, media_type=resp.headers.get("content-type"))
+
+    async def proxy(request: Request) -> Response:
+        raw_body = await request.body()
+        path = request.url.path
+        key = None
+        if request.method == "POST":
+            key = cache_key(path, raw_body, key_policy, request_identity(request.headers, identity_headers))
+        result = None
+        if key is not None:
+            if wants_bypass(request.headers.get("cache-control", "")):
+                key, result = None, "bypass"
+            else:
+                try:
+                    cached = await cache.get(key)
+                except Exception as e:  # the cache must never fail a request
+                    logger.warning("response cache lookup failed: %s", e)
+                    kaito_response_cache_errors_total.inc()
+                    cached = None
+                if cached is not None:
+                    kaito_response_cache_requests_total.labels(result="hit").inc()
+                    content_type, body = decode_entry(cached)
+                    return Response(body, media_type=content_type, headers={"X-Kaito-Cache": "HIT"})
+                result = "miss"
+        if result is not None:
+            kaito_response_cache_requests_total.labels(result=result).inc()
+
+        upstream_request = client.build_request(
+            request.method,
+            path,
+            params=request.query_params,
+            headers=forward_headers(request),
+            content=raw_body,
+        )
+        resp = await client.send(upstream_request, stream=True)
+        headers = response_headers(resp)
+        if result is not None:
+            headers["X-Kaito-Cache"] = result.upper()
+        if key is None or resp.status_code != 200:
+            return StreamingResponse(
+                resp.aiter_raw(),
+                status_code=resp.status_code,
+                headers=headers,
+                background=BackgroundTask(resp.aclose),
+            )
+
+        body = await resp.aread()
+        await resp.aclose()
+        if len(body) <= MAX_CACHED_BYTES:
+            try:
+                await cache.set(key, encode_entry(resp.headers.get("content-type", "application/json"), body))
+            except Exception as e:
+                logger.warning("response cache store failed: %s", e)
+                kaito_response_cache_errors_total.inc()
+        return Response(body, status_code=resp.status_code, headers=headers)
+
+    methods = ["GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"]
+    return Starlette(
+        routes=[
+            Route("/metrics", metrics, methods=["GET"]),
+            Route("/{path:path}", proxy, methods=methods),
+        ],
+        on_shutdown=[client.aclose],
+    )
+
+
+def parse_args(argv=None):
+    parser = argparse.ArgumentParser(description="KAITO response cache sidecar")
+    parser.add_argument("--port", type=int, default=5000)
+    parser.add_argument("--upstream-port", type=int, default=5001)
+    parser.add_argument("--backend", choices=["Memory", "Redis"], default="Memory")
+    parser.add_argument("--key-policy", choices=[KEY_POLICY_EXACT, KEY_POLICY_NORMALIZED], default=KEY_POLICY_EXACT)
+    parser.add_argument("--ttl-seconds", type=float, default=600)
+    parser.add_argument("--max-entries", type=int, default=1000)
+    parser.add_argument("--redis-address", default="")
+    parser.add_argument("--redis-tls", action="store_true")
+    parser.add_argument(
+        "--identity-header",
+        action="append",
+        default=[],
+        help="Request header that identifies the caller, in addition to Authorization. Can be repeated.",
+    )
+    return parser.parse_args(argv)
+
+
+def main(argv=None):
+    import uvicorn
+
+    logging.basicConfig(level=logging.INFO)
+    args = parse_args(argv)
+    if args.backend == "Redis":
+        # The password comes from a Secret, so it is not exposed in the pod spec args.
+        cache = RedisCache(args.redis_address, os.environ.get("REDIS_PASSWORD"), args.redis_tls, args.ttl_seconds)
+    else:
+        cache = MemoryCache(args.max_entries, args.ttl_seconds)
+    app = build_app(cache, args.key_policy, f"http://127.0.0.1:{args.upstream_port}", args.identity_header)
+    uvicorn.run(app, host="0.0.0.0", port=args.port, log_level="warning")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,155 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Unit tests for the API normalization sidecar request and response translation."""
+
+import json
+import sys
+from pathlib import Path
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
+
+import api_normalizer  # noqa: E402
+
+
+def test_normalize_request_renames_max_tokens():
+    body = api_normalizer.normalize_request({"prompt": "hi", "max_completion_tokens": 16}, "max_tokens", None)
+    assert body == {"prompt": "hi", "max_tokens": 16}
+
+    body = api_normalizer.normalize_request({"prompt": "hi", "max_tokens": 16}, "max_completion_tokens", None)
+    assert body == {"prompt": "hi", "max_completion_tokens": 16}
+
+
+def test_normalize_request_prefers_the_expected_field():
+    body = api_normalizer.normalize_request({"max_tokens": 8, "max_completion_tokens": 16}, "max_tokens", None)
+    assert body == {"max_tokens": 8}
+
+
+def test_normalize_request_fills_in_missing_model():
+    assert api_normalizer.normalize_request({"prompt": "hi"}, "max_tokens", "phi-4")["model"] == "phi-4"
+    assert api_normalizer.normalize_request({"model": "", "prompt": "hi"}, "max_tokens", "phi-4")["model"] == "phi-4"
+    assert api_normalizer.normalize_request({"model": "mine"}, "max_tokens", "phi-4")["model"] == "mine"
+    assert "model" not in api_normalizer.normalize_request({"prompt": "hi"}, "max_tokens", None)
+
+
+def test_select_adapter():
+    body = {"model": "phi-4", "prompt": "hi"}
+    assert api_normalizer.select_adapter(body, None, {"marketing-v2"}) is body
+    assert api_normalizer.select_adapter(body, "", {"marketing-v2"}) is body
+
+    selected = api_normalizer.select_adapter(body, "marketing-v2", {"marketing-v2"})
+    assert selected == {"model": "marketing-v2", "prompt": "hi"}
+    assert body["model"] == "phi-4"
+
+    with pytest.raises(api_normalizer.AdapterNotFoundError):
+        api_normalizer.select_adapter(body, "unknown", {"marketing-v2"})
+
+
+def test_upstream_path():
+    t = api_normalizer
+    assert t.upstream_path(t.CHAT_COMPLETIONS_PATH, t.TRANSLATE_CHAT_TO_COMPLETIONS) == t.COMPLETIONS_PATH
+    assert t.upstream_path(t.COMPLETIONS_PATH, t.TRANSLATE_CHAT_TO_COMPLETIONS) == t.COMPLETIONS_PATH
+    assert t.upstream_path(t.COMPLETIONS_PATH, t.TRANSLATE_COMPLETIONS_TO_CHAT) == t.CHAT_COMPLETIONS_PATH
+    assert t.upstream_path(t.CHAT_COMPLETIONS_PATH, t.TRANSLATE_NONE) == t.CHAT_COMPLETIONS_PATH
+    assert t.response_converter(t.CHAT_COMPLETIONS_PATH, t.TRANSLATE_NONE) is None
+
+
+def test_chat_to_completion_request():
+    body = {
+        "model": "m",
+        "temperature": 0.2,
+        "tools": [],
+        "messages": [
+            {"role": "system", "content": "Be brief."},
+            {"role": "user", "content": [{"type": "text", "text": "Hello"}]},
+        ],
+    }
+    request = api_normalizer.chat_to_completion_request(body)
+    assert request == {"model": "m", "temperature": 0.2, "prompt": "system: Be brief.\nuser: Hello\nassistant:"}
+
+
+def test_chat_to_completion_request_rejects_images():
+    body = {"messages": [{"role": "user", "content": [{"type": "image_url", "image_url": {"url": "x"}}]}]}
+    with pytest.raises(api_normalizer.TranslationError):
+        api_normalizer.chat_to_completion_request(body)
+    with pytest.raises(api_normalizer.TranslationError):
+        api_normalizer.chat_to_completion_request({"messages": []})
+
+
+def test_completion_to_chat_request():
+    request = api_normalizer.completion_to_chat_request({"model": "m", "prompt": ["Hi"], "echo": True, "max_tokens": 4})
+    assert request == {"model": "m", "max_tokens": 4, "messages": [{"role": "user", "content": "Hi"}]}
+
+    with pytest.raises(api_normalizer.TranslationError):
+        api_normalizer.completion_to_chat_request({"prompt": ["a", "b"]})
+    with pytest.raises(api_normalizer.TranslationError):
+        api_normalizer.completion_to_chat_request({"prompt": [1, 2, 3]})
+
+
+def test_completion_to_chat_response():
+    resp = {
+        "id": "cmpl-1",
+        "created": 1,
+        "model": "m",
+        "choices": [{"index": 0, "text": "Hi there", "finish_reason": "stop"}],
+        "usage": {"total_tokens": 3},
+    }
+    out = api_normalizer.completion_to_chat_response(resp)
+    assert out["object"] == "chat.completion"
+    assert out["choices"] == [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hi there"}}]
+    assert out["usage"] == {"total_tokens": 3}
+
+    chunk = api_normalizer.completion_to_chat_response(resp, stream=True)
+    assert chunk["object"] == "chat.completion.chunk"
+    assert chunk["choices"][0]["delta"] == {"content": "Hi there"}
+
+
+def test_chat_to_completion_response():
+    resp = {
+        "id": "chat-1",
+        "created": 1,
+        "model": "m",
+        "choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "length"}],
+    }
+    out = api_normalizer.chat_to_completion_response(resp)
+    assert out["object"] == "text_completion"
+    assert out["choices"] == [{"index": 0, "text": "Hi", "logprobs": None, "finish_reason": "length"}]
+    assert "usage" not in out
+
+    chunk = {"id": "chat-1", "choices": [{"index": 0, "delta": {"role": "assistant"}, "finish_reason": None}]}
+    assert api_normalizer.chat_to_completion_response(chunk, stream=True)["choices"][0]["text"] == ""
+
+
+def test_convert_sse_line():
+    convert = api_normalizer.completion_to_chat_response
+    line = "data: " + json.dumps({"id": "c", "created": 1, "model": "m", "choices": [{"index": 0, "text": "a"}]})
+    converted = json.loads(api_normalizer.convert_sse_line(line, convert)[len("data: ") :])
+    assert converted["object"] == "chat.completion.chunk"
+    assert converted["choices"][0]["delta"] == {"content": "a"}
+
+    assert api_normalizer.convert_sse_line("data: [DONE]", convert) == "data: [DONE]"
+    assert api_normalizer.convert_sse_line("", convert) == ""
+    assert api_normalizer.convert_sse_line("data: not json", convert) == "data: not json"
+
+
+def test_parse_args_defaults():
+    args = api_normalizer.parse_args([])
+    assert args.port == 5000
+    assert args.upstream_port == 5001
+    assert args.translate == api_normalizer.TRANSLATE_NONE
+    assert args.max_tokens_field == "max_tokens"
+    assert args.default_model == ""
+    assert args.adapter_header == ""
+    assert args.adapters == ""
@@ -73,7 +73,10 @@ class TestSetKvTransferConfig:
     def test_lmcache_default_when_offload_enabled_no_role(self):
         """When CPU offload enabled but no role, should default to LMCacheConnectorV1."""
         args = _make_args(kaito_kv_cache_cpu_memory_utilization=0.5)
-        with patch.dict(os.environ, {}, clear=True):
+        with (
+            patch.dict(os.environ, {}, clear=True),
+            patch("importlib.util.find_spec", return_value=object()),
+        ):
             os.environ.pop("KAITO_INFERENCE_ROLE", None)
             set_kv_transfer_config_if_applicable(args)
         assert args.kv_transfer_config == {
@@ -81,10 +84,24 @@ class TestSetKvTransferConfig:
             "kv_role": "kv_both",
         }
 
+    def test_no_offload_when_lmcache_missing(self):
+        """When LMCache is not installed (arm64 image), CPU offload is skipped."""
+        args = _make_args(kaito_kv_cache_cpu_memory_utilization=0.5)
+        with (
+            patch.dict(os.environ, {}, clear=True),
+            patch("importlib.util.find_spec", return_value=None),
+        ):
+            set_kv_transfer_config_if_applicable(args)
+            assert "LMCACHE_LOCAL_CPU" not in os.environ
+        assert args.kv_transfer_config is None
+
     def test_nixl_not_overridden_by_offload(self):
         """When role is set AND offload enabled, NixlConnector should not be overwritten."""
         args = _make_args(kaito_kv_cache_cpu_memory_utilization=0.5)
-        with patch.dict(os.environ, {"KAITO_INFERENCE_ROLE": "decode"}, clear=True):
+        with (
+            patch.dict(os.environ, {"KAITO_INFERENCE_ROLE": "decode"}, clear=True),
+            patch("importlib.util.find_spec", return_value=object()),
+        ):
             set_kv_transfer_config_if_applicable(args)
         assert args.kv_transfer_config["kv_connector"] == "NixlConnector"
 
@@ -0,0 +1,75 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Unit tests for the max-model-len profile cache hooks."""
+
+import json
+import os
+import sys
+from pathlib import Path
+from types import SimpleNamespace
+from unittest.mock import patch
+
+# Add parent directory to sys.path for inference_api imports
+parent_dir = str(Path(__file__).resolve().parent.parent)
+sys.path.insert(0, parent_dir)
+
+from inference_api import (  # noqa: E402, I001
+    apply_cached_max_model_len,
+    report_profile_result,
+)
+
+
+class TestApplyCachedMaxModelLen:
+    """Tests for apply_cached_max_model_len()."""
+
+    def test_replaces_auto(self):
+        args = ["--model=m", "--max-model-len=auto"]
+        with patch.dict(os.environ, {"KAITO_MAX_MODEL_LEN": "32768"}):
+            apply_cached_max_model_len(args)
+        assert args == ["--model=m", "--max-model-len=auto", "--max-model-len=32768"]
+
+    def test_replaces_auto_in_separate_argument(self):
+        args = ["--max_model_len", "auto"]
+        with patch.dict(os.environ, {"KAITO_MAX_MODEL_LEN": "4096"}):
+            apply_cached_max_model_len(args)
+        assert args[-1] == "--max-model-len=4096"
+
+    def test_keeps_explicit_value(self):
+        args = ["--max-model-len=auto", "--max-model-len=8192"]
+        with patch.dict(os.environ, {"KAITO_MAX_MODEL_LEN": "32768"}):
+            apply_cached_max_model_len(args)
+        assert args == ["--max-model-len=auto", "--max-model-len=8192"]
+
+    def test_no_cached_value(self):
+        args = ["--max-model-len=auto"]
+        with patch.dict(os.environ, {}, clear=True):
+            apply_cached_max_model_len(args)
+        assert args == ["--max-model-len=auto"]
+
+    def test_ignores_invalid_value(self):
+        for cached in ("0", "-1", "abc"):
+            args = ["--max-model-len=auto"]
+            with patch.dict(os.environ, {"KAITO_MAX_MODEL_LEN": cached}):
+                apply_cached_max_model_len(args)
+            assert args == ["--max-model-len=auto"]
+
+
+def test_report_profile_result(capsys):
+    engine_client = SimpleNamespace(
+        vllm_config=SimpleNamespace(model_config=SimpleNamespace(max_model_len=16384))
+    )
+    report_profile_result(engine_client)
+    tag, _ts, payload = capsys.readouterr().out.strip().split(" ", 2)
+    assert tag == "KAITO_PROFILE_RESULT"
+    assert json.loads(payload) == {"max_model_len": 16384}
@@ -0,0 +1,114 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Unit tests for the response cache sidecar keying and in-memory backend."""
+
+import asyncio
+import json
+import sys
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
+
+import response_cache  # noqa: E402
+
+
+def _key(body, path="/v1/chat/completions", policy=response_cache.KEY_POLICY_EXACT):
+    return response_cache.cache_key(path, json.dumps(body).encode(), policy)
+
+
+def test_cache_key_ignores_json_key_order():
+    a = response_cache.cache_key("/v1/completions", b'{"model":"m","prompt":"hi"}', "ExactPrompt")
+    b = response_cache.cache_key("/v1/completions", b'{"prompt":"hi","model":"m"}', "ExactPrompt")
+    assert a is not None
+    assert a == b
+
+
+def test_cache_key_depends_on_path_and_parameters():
+    body = {"model": "m", "prompt": "hi"}
+    assert _key(body, path="/v1/completions") != _key(body, path="/v1/chat/completions")
+    assert _key(body) != _key(dict(body, temperature=0.5))
+
+
+def test_cache_key_skips_uncacheable_requests():
+    assert _key({"model": "m", "prompt": "hi", "stream": True}) is None
+    assert _key({"model": "m", "input": "hi"}, path="/v1/embeddings") is None
+    assert response_cache.cache_key("/v1/completions", b"not json", "ExactPrompt") is None
+    assert response_cache.cache_key("/v1/completions", b"[1, 2]", "ExactPrompt") is None
+
+
+def test_cache_key_depends_on_caller_identity():
+    body = b'{"model":"m","prompt":"hi"}'
+
+    def key(headers, identity_headers=()):
+        identity = response_cache.request_identity(headers, identity_headers)
+        return response_cache.cache_key("/v1/completions", body, "ExactPrompt", identity)
+
+    alice = {"authorization": ["Bearer alice"]}
+    bob = {"authorization": ["Bearer bob"]}
+    assert key(alice) == key(alice)
+    assert key(alice) != key(bob)
+    assert key(alice) != key({})
+
+    # Configured identity headers are matched case-insensitively.
+    tenant_a = {"x-tenant": ["a"]}
+    tenant_b = {"x-tenant": ["b"]}
+    assert key(tenant_a) == key(tenant_b)
+    assert key(tenant_a, ["X-Tenant"]) != key(tenant_b, ["X-Tenant"])
+
+
+def test_normalized_prompt_policy():
+    messy = {
+        "model": "m",
+        "messages": [{"role": "user", "content": [{"type": "text", "text": "  What is\n\nKAITO? "}]}],
+    }
+    clean = {
+        "model": "m",
+        "messages": [{"role": "user", "content": [{"type": "text", "text": "What is KAITO?"}]}],
+    }
+    assert _key(messy) != _key(clean)
+    normalized = response_cache.KEY_POLICY_NORMALIZED
+    assert _key(messy, policy=normalized) == _key(clean, policy=normalized)
+    assert _key({"model": "m", "prompt": " a  b "}, policy=normalized) == _key({"model": "m", "prompt": "a b"}, policy=normalized)
+
+
+def test_wants_bypass():
+    assert response_cache.wants_bypass("no-cache")
+    assert response_cache.wants_bypass("max-age=0, No-Store")
+    assert not response_cache.wants_bypass("")
+    assert not response_cache.wants_bypass("max-age=60")
+
+
+def test_memory_cache_lru_and_ttl():
+    now = [0.0]
+    cache = response_cache.MemoryCache(max_entries=2, ttl_seconds=10, clock=lambda: now[0])
+
+    async def run():
+        await cache.set("a", b"1")
+        await cache.set("b", b"2")
+        assert await cache.get("a") == b"1"  # a is now the most recently used
+        await cache.set("c", b"3")
+        assert await cache.get("b") is None
+        assert await cache.get("a") == b"1"
+        assert len(cache) == 2
+
+        now[0] = 10
+        assert await cache.get("a") is None
+        assert len(cache) == 1
+
+    asyncio.run(run())
+
+
+def test_entry_round_trip():
+    value = response_cache.encode_entry("application/json", b'{"a":\n1}')
+    assert response_cache.decode_entry(value) == ("application/json", b'{"a":\n1}')
@@ -0,0 +1,60 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Unit tests for the tier router request classification."""
+
+import json
+import sys
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
+
+import tier_router  # noqa: E402
+
+
+def _tokenize(body, path="/v1/completions"):
+    return tier_router.tokenize_request(path, json.dumps(body).encode())
+
+
+def test_tokenize_request_completions():
+    assert _tokenize({"model": "m", "prompt": "hi"}) == {"model": "m", "prompt": "hi"}
+    assert _tokenize({"model": "m", "prompt": [1, 2, 3]}) == {"model": "m", "prompt": [1, 2, 3]}
+    # A batch is routed by its longest prompt.
+    assert _tokenize({"model": "m", "prompt": ["a", "abc", "ab"]}) == {"model": "m", "prompt": "abc"}
+
+
+def test_tokenize_request_chat():
+    messages = [{"role": "user", "content": "hi"}]
+    assert _tokenize({"model": "m", "messages": messages}, path="/v1/chat/completions") == {
+        "model": "m",
+        "messages": messages,
+    }
+
+
+def test_tokenize_request_skips_other_requests():
+    assert _tokenize({"model": "m", "input": "hi"}, path="/v1/embeddings") is None
+    assert _tokenize({"model": "m"}) is None
+    assert _tokenize({"model": "m", "messages": "hi"}, path="/v1/chat/completions") is None
+    assert tier_router.tokenize_request("/v1/completions", b"not json") is None
+    assert tier_router.tokenize_request("/v1/completions", b"[1, 2]") is None
+
+
+def test_may_exceed():
+    assert not tier_router.may_exceed(b"x" * 2048, 4096)
+    assert tier_router.may_exceed(b"x" * 2049, 4096)
+
+
+def test_hops():
+    assert tier_router.hops({}) == 0
+    assert tier_router.hops({tier_router.HOPS_HEADER: "2"}) == 2
+    assert tier_router.hops({tier_router.HOPS_HEADER: "many"}) == tier_router.MAX_HOPS
@@ -0,0 +1,44 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""Unit tests for the tokenizer sidecar request checks."""
+
+import sys
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
+
+import tokenizer_server  # noqa: E402
+
+
+def test_check_request_accepts_json_objects():
+    assert tokenizer_server.check_request(b'{"model": "m", "prompt": "hi"}', 1024) is None
+    assert tokenizer_server.check_request(b'{"messages": [{"role": "user", "content": "hi"}]}', 1024) is None
+
+
+def test_check_request_rejects_other_bodies():
+    assert tokenizer_server.check_request(b"not json", 1024)[0] == 400
+    assert tokenizer_server.check_request(b"[1, 2]", 1024)[0] == 400
+    assert tokenizer_server.check_request(b"\xff", 1024)[0] == 400
+    assert tokenizer_server.check_request(b'{"prompt": "' + b"x" * 1024 + b'"}', 1024)[0] == 413
+
+
+def test_forward_headers():
+    headers = {"Authorization": "Bearer t", "Content-Type": "application/json", "X-Forwarded-For": "10.0.0.1", "Host": "x"}
+    assert tokenizer_server.forward_headers(headers) == {"Authorization": "Bearer t", "Content-Type": "application/json"}
+
+
+def test_parse_args():
+    args = tokenizer_server.parse_args(["--port=5002", "--upstream-port=5001"])
+    assert (args.port, args.upstream_port, args.max_body_bytes) == (5002, 5001, tokenizer_server.DEFAULT_MAX_BODY_BYTES)
@@ -0,0 +1,209 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Tier router sidecar for tiered MultiRoleInference workspaces.
+
+Listens on the inference port in front of vLLM. Completion and chat completion
+requests whose prompt is longer than the tier limit are forwarded to the
+Service of the next tier, which serves longer prompts on larger GPUs; all
+other requests are served by the local vLLM. Prompt length is measured with
+the vLLM ``/tokenize`` endpoint, so it matches what the model would see.
+Routing counters are appended to the vLLM ``/metrics`` output.
+"""
+
+import argparse
+import json
+import logging
+
+from prometheus_client import CollectorRegistry, Counter, generate_latest
+
+logger = logging.getLogger(__name__)
+
+ROUTED_PATHS = ("/v1/completions", "/v1/chat/completions")
+
+# Set on forwarded requests. A request is never forwarded more often than there
+# can be tiers, so a misconfigured overflow Service cannot create a loop.
+HOPS_HEADER = "x-kaito-tier-hops"
+MAX_HOPS = 3
+
+# Headers that describe a single hop and must not be forwarded.
+HOP_BY_HOP_HEADERS = {
+    "connection",
+    "keep-alive",
+    "proxy-authenticate",
+    "proxy-authorization",
+    "te",
+    "trailer",
+    "transfer-encoding",
+    "upgrade",
+    "host",
+    "content-length",
+}
+
+registry = CollectorRegistry()
+
+kaito_tier_router_requests_total = Counter(
+    "kaito_tier_router_requests_total",
+    "Requests seen by the KAITO tier router, by destination (local or overflow)",
+    ["destination"],
+    registry=registry,
+)
+
+kaito_tier_router_errors_total = Counter(
+    "kaito_tier_router_errors_total",
+    "Prompt length lookups that failed; the request is served locally instead",
+    registry=registry,
+)
+
+
+def tokenize_request(path: str, raw_body: bytes) -> dict | None:
+    """Return the vLLM /tokenize request for a routed request, or None if it is not routed."""
+    if path not in ROUTED_PATHS:
+        return None
+    try:
+        body = json.loads(raw_body)
+    except (ValueError, UnicodeDecodeError):
+        return None
+    if not isinstance(body, dict):
+        return None
+    request = {"model": body.get("model")} if "model" in body else {}
+    if path == "/v1/chat/completions":
+        if not isinstance(body.get("messages"), list):
+            return None
+        request["messages"] = body["messages"]
+        return request
+    prompt = body.get("prompt")
+    if isinstance(prompt, list) and prompt and not all(isinstance(p, int) for p in prompt):
+        # A batch of prompts is as long as its longest prompt.
+        prompt = max(prompt, key=len)
+    if not isinstance(prompt, (str, list)):
+        return None
+    request["prompt"] = prompt
+    return request
+
+
+def may_exceed(raw_body: bytes, max_prompt_tokens: int) -> bool:
+    """Cheap pre-check: a prompt has fewer tokens than half the bytes of the request
+    encoding it, so short requests skip the /tokenize round trip."""
+    return len(raw_body) > max_prompt_tokens // 2
+
+
+def hops(headers) -> int:
+    try:
+        return int(headers.get(HOPS_HEADER, "0"))
+    except ValueError:
+        return MAX_HOPS
+
+
+def build_app(max_prompt_tokens: int, upstream: str, overflow: str):
+    import httpx
+    from starlette.applications import Starlette
+    from starlette.background import BackgroundTask
+    from starlette.requests import Request
+    from starlette.responses import Response, StreamingResponse
+    from starlette.routing import Route
+
+    local = httpx.AsyncClient(base_url=upstream, timeout=None)
+    remote = httpx.AsyncClient(base_url=overflow, timeout=None)
+
+    def forward_headers(request: Request) -> dict:
+        return {k: v for k, v in request.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    def response_headers(resp) -> dict:
+        return {k: v for k, v in resp.headers.items() if k.lower() not in HOP_BY_HOP_HEADERS}
+
+    async def prompt_tokens(tokenize: dict, headers: dict) -> int | None:
+        if isinstance(tokenize.get("prompt"), list) and all(isinstance(p, int) for p in tokenize["prompt"]):
+            return len(tokenize["prompt"])
+        try:
+            resp = await local.post("/tokenize", json=tokenize, headers=headers)
+            resp.raise_for_status()
+            return int(resp.json()["count"])
+        except Exception as e:  # routing must never fail a request
+            logger.warning("tier router prompt length lookup failed: %s", e)
+            kaito_tier_router_errors_total.inc()
+            return None
+
+    async def metrics(request: Request) -> Response:
+        resp = await local.get("/metrics", headers=forward_headers(request))
+        body = resp.content + b"\n" + generate_latest(registry)
+        return Response(body, status_code=resp.status_code, media_type=resp.headers.get("content-type"))
+
+    async def proxy(request: Request) -> Response:
+        raw_body = await request.body()
+        path = request.url.path
+        headers = forward_headers(request)
+        client, destination = local, None
+
+        tokenize = tokenize_request(path, raw_body) if request.method == "POST" else None
+        if tokenize is not None:
+            destination = "local"
+            if may_exceed(raw_body, max_prompt_tokens) and hops(request.headers) < MAX_HOPS:
+                count = await prompt_tokens(tokenize, {k: v for k, v in headers.items() if k.lower() == "authorization"})
+                if count is not None and count > max_prompt_tokens:
+                    client, destination = remote, "overflow"
+                    headers[HOPS_HEADER] = str(hops(request.headers) + 1)
+            kaito_tier_router_requests_total.labels(destination=destination).inc()
+
+        upstream_request = client.build_request(
+            request.method,
+            path,
+            params=request.query_params,
+            headers=headers,
+            content=raw_body,
+        )
+        resp = await client.send(upstream_request, stream=True)
+        out_headers = response_headers(resp)
+        if destination is not None:
+            out_headers["X-Kaito-Tier-Route"] = destination
+        return StreamingResponse(
+            resp.aiter_raw(),
+            status_code=resp.status_code,
+            headers=out_headers,
+            background=BackgroundTask(resp.aclose),
+        )
+
+    async def shutdown():
+        await local.aclose()
+        await remote.aclose()
+
+    methods = ["GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"]
+    return Starlette(
+        routes=[
+            Route("/metrics", metrics, methods=["GET"]),
+            Route("/{path:path}", proxy, methods=methods),
+        ],
+        on_shutdown=[shutdown],
+    )
+
+
+def parse_args(argv=None):
+    parser = argparse.ArgumentParser(description="KAITO tier router sidecar")
+    parser.add_argument("--port", type=int, default=5000)
+    parser.add_argument("--upstream-port", type=int, default=5001)
+    parser.add_argument("--max-prompt-tokens", type=int, required=True)
+    parser.add_argument("--overflow-url", required=True)
+    return parser.parse_args(argv)
+
+
+def main(argv=None):
+    import uvicorn
+
+    logging.basicConfig(level=logging.INFO)
+    args = parse_args(argv)
+    app = build_app(args.max_prompt_tokens, f"http://127.0.0.1:{args.upstream_port}", args.overflow_url)
+    uvicorn.run(app, host="0.0.0.0", port=args.port, log_level="warning")
+
+
+if __name__ == "__main__":
+    main()
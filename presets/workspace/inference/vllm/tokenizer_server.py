@@ -0,0 +1,123 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""Tokenizer sidecar for workspaces with inference.tokenizer set.
+
+Listens on its own port of the workspace Service and answers POST ``/tokenize``
+and ``/detokenize`` by forwarding them to the vLLM server in the pod, so token
+counts match what the model would see, including the chat template. All other
+requests are rejected, so clients that are only allowed to reach this port
+cannot run generations. ``/health`` reports the health of vLLM.
+"""
+
+import argparse
+import json
+import logging
+
+logger = logging.getLogger(__name__)
+
+FORWARDED_PATHS = ("/tokenize", "/detokenize")
+
+# Only these request headers are passed to vLLM.
+FORWARDED_HEADERS = ("authorization", "content-type")
+
+# Requests to count tokens for are prompts, not uploads; larger bodies are rejected
+# before they reach vLLM.
+DEFAULT_MAX_BODY_BYTES = 4 * 1024 * 1024
+
+
+def check_request(raw_body: bytes, max_body_bytes: int) -> tuple[int, str] | None:
+    """Return the status and error message a request is rejected with, or None if it is forwarded."""
+    if len(raw_body) > max_body_bytes:
+        return 413, f"request body exceeds {max_body_bytes} bytes"
+    try:
+        body = json.loads(raw_body)
+    except (ValueError, UnicodeDecodeError):
+        return 400, "request body must be a JSON object"
+    if not isinstance(body, dict):
+        return 400, "request body must be a JSON object"
+    return None
+
+
+def forward_headers(headers) -> dict:
+    return {k: v for k, v in headers.items() if k.lower() in FORWARDED_HEADERS}
+
+
+def build_app(upstream: str, max_body_bytes: int = DEFAULT_MAX_BODY_BYTES):
+    import httpx
+    from starlette.applications import Starlette
+    from starlette.requests import Request
+    from starlette.responses import JSONResponse, Response
+    from starlette.routing import Route
+
+    client = httpx.AsyncClient(base_url=upstream, timeout=httpx.Timeout(30.0))
+
+    def error(status: int, message: str) -> JSONResponse:
+        return JSONResponse({"error": {"message": message, "code": status}}, status_code=status)
+
+    async def forward(request: Request) -> Response:
+        raw_body = await request.body()
+        rejected = check_request(raw_body, max_body_bytes)
+        if rejected is not None:
+            return error(*rejected)
+        try:
+            resp = await client.post(request.url.path, content=raw_body, headers=forward_headers(request.headers))
+        except httpx.HTTPError as e:
+            logger.warning("tokenizer request to the inference server failed: %s", e)
+            return error(503, "the inference server is not available")
+        return Response(resp.content, status_code=resp.status_code, media_type=resp.headers.get("content-type"))
+
+    async def health(request: Request) -> Response:
+        try:
+            resp = await client.get("/health")
+        except httpx.HTTPError:
+            return Response(status_code=503)
+        return Response(status_code=200 if resp.status_code == 200 else 503)
+
+    async def not_found(request: Request) -> Response:
+        return error(404, f"only {', '.join(FORWARDED_PATHS)} are served on this port")
+
+    async def shutdown():
+        await client.aclose()
+
+    methods = ["GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"]
+    return Starlette(
+        routes=[Route(path, forward, methods=["POST"]) for path in FORWARDED_PATHS]
+        + [
+            Route("/health", health, methods=["GET"]),
+            Route("/{path:path}", not_found, methods=methods),
+        ],
+        on_shutdown=[shutdown],
+    )
+
+
+def parse_args(argv=None):
+    parser = argparse.ArgumentParser(description="KAITO tokenizer sidecar")
+    parser.add_argument("--port", type=int, default=5002)
+    parser.add_argument("--upstream-port", type=int, default=5000)
+    parser.add_argument("--max-body-bytes", type=int, default=DEFAULT_MAX_BODY_BYTES)
+    return parser.parse_args(argv)
+
+
+def main(argv=None):
+    import uvicorn
+
+    logging.basicConfig(level=logging.INFO)
+    args = parse_args(argv)
+    app = build_app(f"http://127.0.0.1:{args.upstream_port}", args.max_body_bytes)
+    uvicorn.run(app, host="0.0.0.0", port=args.port, log_level="warning")
+
+
+if __name__ == "__main__":
+    main()
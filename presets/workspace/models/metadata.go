@@ -15,12 +15,14 @@ package models
 
 import (
 	_ "embed"
+	"strings"
 	"sync"
 
 	"gopkg.in/yaml.v2"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
 )
 
 const (
@@ -78,3 +80,21 @@ func Get(name string) (model.Metadata, bool) {
 
 	return *(m.(*model.Metadata)), true
 }
+
+// DeprecatedPreset returns the metadata of the preset name if it is deprecated. A
+// HuggingFace model ID resolves to the legacy preset it replaced, so a deprecation of
+// the legacy preset applies to both names.
+func DeprecatedPreset(name string) (model.Metadata, bool) {
+	name = strings.ToLower(name)
+	if m, ok := Get(name); ok {
+		return m, m.Deprecated
+	}
+	for legacy, hfName := range plugin.LegacyBuiltinToCatalog {
+		if strings.EqualFold(hfName, name) {
+			if m, ok := Get(legacy); ok {
+				return m, m.Deprecated
+			}
+		}
+	}
+	return model.Metadata{}, false
+}
@@ -12,3 +12,26 @@
 // limitations under the License.
 
 package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecatedPreset(t *testing.T) {
+	m, deprecated := DeprecatedPreset("Phi-2")
+	assert.True(t, deprecated)
+	assert.Equal(t, "phi-2", m.Name)
+
+	for _, name := range []string{"phi-4", "microsoft/phi-4", "Qwen/Qwen3-0.6B", "unknown"} {
+		_, deprecated = DeprecatedPreset(name)
+		assert.False(t, deprecated, name)
+	}
+}
+
+func TestBaseImagePlatforms(t *testing.T) {
+	base := MustGet("base")
+	assert.True(t, base.SupportsArchitecture("amd64"))
+	assert.True(t, base.SupportsArchitecture("arm64"))
+}
@@ -0,0 +1,134 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/presets/workspace/generator"
+)
+
+// presetContextLen is the max-model-len MinGPUMemory of a listed preset is estimated for. It
+// is the context the node estimator falls back to when a workspace does not set one.
+const presetContextLen = 2048
+
+// Preset describes a curated model of the embedded model catalog.
+type Preset struct {
+	// Name is the HuggingFace model ID to set as the preset name of a workspace.
+	Name string `json:"name"`
+	// Aliases are the legacy short names that resolve to the model.
+	Aliases []string `json:"aliases,omitempty"`
+	// Runtimes are the inference runtimes the model can be served with.
+	Runtimes []model.RuntimeName `json:"runtimes"`
+	// MinGPUMemory is the GPU memory needed to serve the model on a single GPU with a context
+	// of 2048 tokens, e.g. "22Gi". Models that do not fit one GPU need more in total, since
+	// every GPU adds its own runtime overhead. Empty when the model size is not known.
+	MinGPUMemory string `json:"minGPUMemory,omitempty"`
+	// ModelTokenLimit is the largest context the model supports.
+	ModelTokenLimit int `json:"modelTokenLimit,omitempty"`
+	// ImageTag is the tag of the image the model is served with.
+	ImageTag string `json:"imageTag"`
+	// DownloadAuthRequired reports whether downloading the weights needs a HuggingFace token.
+	DownloadAuthRequired bool `json:"downloadAuthRequired,omitempty"`
+	// Tuning reports whether the model can be fine-tuned.
+	Tuning bool `json:"tuning,omitempty"`
+	// Deprecated reports whether the model is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// EndOfLifeDate is the date from which new workspaces can no longer use a deprecated model.
+	EndOfLifeDate string `json:"endOfLifeDate,omitempty"`
+	// Replacement is the preset that replaces a deprecated model.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// ListPresets returns the curated models of the embedded model catalog, sorted by name.
+// The list is computed once, from the catalog alone, without contacting HuggingFace.
+var ListPresets = sync.OnceValues(listPresets)
+
+func listPresets() ([]Preset, error) {
+	catalog := generator.ModelCatalog{}
+	if err := yaml.Unmarshal(modelCatalogYAML, &catalog); err != nil {
+		return nil, err
+	}
+	aliases := map[string][]string{}
+	for alias, name := range plugin.LegacyBuiltinToCatalog {
+		aliases[strings.ToLower(name)] = append(aliases[strings.ToLower(name)], alias)
+	}
+
+	imageTag := MustGet("base").Tag
+	presets := make([]Preset, 0, len(catalog.Models))
+	for _, entry := range catalog.Models {
+		param, err := generator.GeneratePreset(entry.Name, "", modelCatalogYAML)
+		if err != nil {
+			return nil, err
+		}
+		m := newVLLMCompatibleModel(param)
+		params := m.GetInferenceParameters()
+
+		p := Preset{
+			Name:                 entry.Name,
+			Aliases:              aliases[strings.ToLower(entry.Name)],
+			Runtimes:             []model.RuntimeName{model.RuntimeNameVLLM},
+			ModelTokenLimit:      params.ModelTokenLimit,
+			ImageTag:             imageTag,
+			DownloadAuthRequired: params.DownloadAuthRequired,
+			Tuning:               m.SupportTuning(),
+		}
+		sort.Strings(p.Aliases)
+		if params.Transformers.BaseCommand != "" {
+			p.Runtimes = append(p.Runtimes, model.RuntimeNameHuggingfaceTransformers)
+		}
+		if size, err := resource.ParseQuantity(params.TotalSafeTensorFileSize); err == nil && !size.IsZero() {
+			p.MinGPUMemory = formatGiB(model.MinGPUMemory(float64(size.Value()), params.BytesPerToken, presetContextLen))
+		}
+		if metadata, deprecated := DeprecatedPreset(entry.Name); deprecated {
+			p.Deprecated = true
+			p.EndOfLifeDate = metadata.EndOfLifeDate
+			p.Replacement = metadata.Replacement
+		}
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool { return strings.ToLower(presets[i].Name) < strings.ToLower(presets[j].Name) })
+	return presets, nil
+}
+
+// formatGiB rounds bytes up to a whole number of GiB.
+func formatGiB(bytes float64) string {
+	gib := int64(bytes/(1<<30)) + 1
+	return resource.NewQuantity(gib<<30, resource.BinarySI).String()
+}
+
+// PresetsHandler serves the curated presets as JSON, so UIs and the CLI can offer them
+// without reading the source.
+func PresetsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		presets, err := ListPresets()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(presets); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaito-project/kaito/pkg/model"
+)
+
+func TestListPresets(t *testing.T) {
+	presets, err := ListPresets()
+	require.NoError(t, err)
+	require.NotEmpty(t, presets)
+
+	var phi4 *Preset
+	for i := range presets {
+		if i > 0 {
+			assert.LessOrEqual(t, strings.ToLower(presets[i-1].Name), strings.ToLower(presets[i].Name))
+		}
+		assert.Contains(t, presets[i].Runtimes, model.RuntimeNameVLLM, presets[i].Name)
+		assert.Equal(t, MustGet("base").Tag, presets[i].ImageTag)
+		if presets[i].Name == "microsoft/phi-4" {
+			phi4 = &presets[i]
+		}
+	}
+	require.NotNil(t, phi4)
+	assert.Equal(t, []string{"phi-4"}, phi4.Aliases)
+	assert.Equal(t, []model.RuntimeName{model.RuntimeNameVLLM, model.RuntimeNameHuggingfaceTransformers}, phi4.Runtimes)
+	assert.Equal(t, "39Gi", phi4.MinGPUMemory)
+	assert.Equal(t, 16384, phi4.ModelTokenLimit)
+	assert.True(t, phi4.Tuning)
+	assert.False(t, phi4.Deprecated)
+}
+
+func TestPresetsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	PresetsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/presets", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var presets []Preset
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &presets))
+	want, err := ListPresets()
+	require.NoError(t, err)
+	assert.Equal(t, want, presets)
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -75,10 +76,7 @@ func registerModel(hfModelCardID string, param *model.PresetParam) model.Model {
 		return nil
 	}
 
-	model := &vLLMCompatibleModel{
-		model:              param.Metadata,
-		generatedRunParams: param.VLLM.ModelRunParams,
-	}
+	model := newVLLMCompatibleModel(param)
 	r := &plugin.Registration{
 		Name:     hfModelCardID,
 		Instance: model,
@@ -162,6 +160,13 @@ type vLLMCompatibleModel struct {
 	generatedRunParams map[string]string // vLLM run params produced by the generator
 }
 
+func newVLLMCompatibleModel(param *model.PresetParam) *vLLMCompatibleModel {
+	return &vLLMCompatibleModel{
+		model:              param.Metadata,
+		generatedRunParams: param.VLLM.ModelRunParams,
+	}
+}
+
 func (m *vLLMCompatibleModel) GetInferenceParameters() *model.PresetParam {
 	metaData := &model.Metadata{
 		Name:                 m.model.Name,
@@ -170,6 +175,7 @@ func (m *vLLMCompatibleModel) GetInferenceParameters() *model.PresetParam {
 		Runtime:              "tfs",
 		DownloadAtRuntime:    true,
 		DownloadAuthRequired: m.model.DownloadAuthRequired,
+		GatedLicense:         m.model.GatedLicense,
 		Architectures:        m.model.Architectures,
 		QuantMethod:          m.model.QuantMethod,
 		QuantBits:            m.model.QuantBits,
@@ -202,7 +208,7 @@ func (m *vLLMCompatibleModel) GetInferenceParameters() *model.PresetParam {
 		runParamsVLLM["enable-auto-tool-choice"] = ""
 	}
 	if m.model.ChatTemplate != "" {
-		runParamsVLLM["chat-template"] = "/workspace/chat_templates/" + m.model.ChatTemplate
+		runParamsVLLM["chat-template"] = path.Join(model.ChatTemplateDir, m.model.ChatTemplate)
 	}
 	if m.model.AllowRemoteFiles {
 		runParamsVLLM["allow-remote-files"] = ""
@@ -978,6 +978,18 @@ func TestGetModelByNameWithToken_ShortNameRedirectsToCatalog(t *testing.T) {
 	}
 }
 
+func TestGetModelByName_GatedCatalogModel(t *testing.T) {
+	result, err := GetModelByNameWithToken(context.Background(), "meta-llama/Llama-3.1-8B-Instruct", "")
+	assert.NoError(t, err)
+	params := result.GetInferenceParameters()
+	assert.True(t, params.DownloadAuthRequired)
+	assert.Equal(t, "llama3.1", params.GatedLicense)
+
+	result, err = GetModelByNameWithToken(context.Background(), "mistralai/Mistral-7B-v0.3", "")
+	assert.NoError(t, err)
+	assert.Empty(t, result.GetInferenceParameters().GatedLicense)
+}
+
 // TestCatalogModelsHaveMTBenchScores ensures every model in model_catalog.yaml
 // has a corresponding score entry in model_catalog_mtbench_scores.md.
 func TestCatalogModelsHaveMTBenchScores(t *testing.T) {
@@ -22,6 +22,7 @@ class DatasetManager:
     def __init__(self, config):
         self.config = config
         self.dataset = None
+        self.dataset_path = None
         self.dataset_text_field = (
             None  # Set this field if dataset consists of singular text column
         )
@@ -56,6 +57,7 @@ class DatasetManager:
             if not dataset_path:
                 raise ValueError("Unable to find a valid dataset file.")
 
+        self.dataset_path = dataset_path
         file_ext = (
             self.config.dataset_extension
             if self.config.dataset_extension
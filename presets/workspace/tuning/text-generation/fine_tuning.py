@@ -13,6 +13,7 @@
 
 import logging
 import os
+import time
 from dataclasses import asdict
 from datetime import datetime
 
@@ -20,8 +21,16 @@ import torch
 from accelerate import PartialState
 from cli import DatasetConfig, ExtDataCollator, ExtLoraConfig, ModelConfig
 from dataset import DatasetManager
+from lineage import digest_path, lineage_line, lineage_payload
 from parser import load_chat_template, parse_configs
 from peft import LoraConfig, get_peft_model, prepare_model_for_kbit_training
+from progress import (
+    estimate_remaining_seconds,
+    gpu_utilization,
+    progress_line,
+    progress_payload,
+)
+from retention import keep_last_checkpoints, prune_checkpoints
 from transformers import (
     AutoModelForCausalLM,
     AutoTokenizer,
@@ -131,6 +140,31 @@ class EmptyCacheCallback(TrainerCallback):
         return control
 
 
+class ProgressCallback(TrainerCallback):
+    """Logs a progress line for the workspace controller at every logging step."""
+
+    def on_train_begin(self, args, state: TrainerState, control: TrainerControl, **kwargs):
+        self.start_time = time.monotonic()
+        self.start_step = state.global_step
+        return control
+
+    def on_log(self, args, state: TrainerState, control: TrainerControl, logs=None, **kwargs):
+        if not state.is_world_process_zero:
+            return control
+        loss = (logs or {}).get("loss")
+        if loss is None and state.log_history:
+            loss = next((h["loss"] for h in reversed(state.log_history) if "loss" in h), None)
+        # Steps restored from a checkpoint do not count towards the pace.
+        eta = estimate_remaining_seconds(
+            state.global_step - self.start_step,
+            state.max_steps - self.start_step,
+            time.monotonic() - self.start_time,
+        )
+        payload = progress_payload(state.epoch, state.global_step, state.max_steps, loss, eta, gpu_utilization(torch))
+        print(progress_line(payload), flush=True)
+        return control
+
+
 empty_cache_callback = EmptyCacheCallback()
 
 ta_args.dataset_text_field = dm.dataset_text_field
@@ -148,7 +182,7 @@ trainer = SFTTrainer(
     eval_dataset=eval_dataset,
     args=ta_args,
     data_collator=dc_args,
-    callbacks=[empty_cache_callback],
+    callbacks=[empty_cache_callback, ProgressCallback()],
     # metrics = "tensorboard" or "wandb" # TODO
 )
 trainer.train()
@@ -156,6 +190,22 @@ os.makedirs(ta_args.output_dir, exist_ok=True)
 # only save the adapter weights
 trainer.model.save_pretrained(ta_args.output_dir)
 
+keep_checkpoints = keep_last_checkpoints()
+if keep_checkpoints is not None and dist_state.is_main_process:
+    pruned = prune_checkpoints(ta_args.output_dir, keep_checkpoints)
+    if pruned:
+        logger.info(f"Deleted intermediate checkpoints {', '.join(pruned)}")
+
+if dist_state.is_main_process:
+    # Digests of the inputs and the saved adapter, recorded by the controller as lineage.
+    # Checkpoints in subdirectories are not part of the adapter.
+    payload = lineage_payload(
+        digest_path(dm.dataset_path),
+        digest_path(CONFIG_YAML),
+        digest_path(ta_args.output_dir, recursive=False),
+    )
+    print(lineage_line(payload), flush=True)
+
 # Write file to signify training completion
 timestamp = datetime.now().strftime("%Y-%m-%d-%H-%M-%S")
 logger.info("Fine-Tuning completed\n")
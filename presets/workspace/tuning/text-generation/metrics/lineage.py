@@ -0,0 +1,80 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+
+"""Lineage line read by the KAITO workspace controller.
+
+After the adapter is saved, the trainer logs one line on stdout:
+
+    KAITO_TUNING_LINEAGE <RFC3339 timestamp> <JSON payload>
+
+The payload holds the sha256 digests of the dataset, the tuning configuration and
+the output adapter. The controller records them in status.lineage of the Workspace.
+"""
+
+import hashlib
+import json
+import os
+from datetime import datetime, timezone
+
+LINEAGE_TAG = "KAITO_TUNING_LINEAGE"
+
+_CHUNK_SIZE = 1 << 20
+
+
+def _file_digest(path: str) -> str:
+    h = hashlib.sha256()
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(_CHUNK_SIZE), b""):
+            h.update(chunk)
+    return h.hexdigest()
+
+
+def digest_path(path: str, recursive: bool = True, exclude: tuple = ()):
+    """Return the sha256 digest of a file, or of the files in a directory, or None if path is missing.
+
+    The digest of a directory covers the relative path and the content digest of every
+    file, sorted by path, so it does not depend on the order the files were written in.
+    """
+    if os.path.isfile(path):
+        return "sha256:" + _file_digest(path)
+    if not os.path.isdir(path):
+        return None
+    files = []
+    for root, dirs, names in os.walk(path):
+        if not recursive:
+            dirs.clear()
+        for name in names:
+            rel = os.path.relpath(os.path.join(root, name), path).replace(os.sep, "/")
+            if rel not in exclude:
+                files.append(rel)
+    h = hashlib.sha256()
+    for rel in sorted(files):
+        h.update(f"{rel}\0{_file_digest(os.path.join(path, rel))}\n".encode())
+    return "sha256:" + h.hexdigest()
+
+
+def lineage_payload(dataset_digest, parameters_digest, output_digest) -> dict:
+    payload = {
+        "dataset_digest": dataset_digest,
+        "parameters_digest": parameters_digest,
+        "output_digest": output_digest,
+    }
+    return {k: v for k, v in payload.items() if v is not None}
+
+
+def lineage_line(payload: dict, now: datetime | None = None) -> str:
+    now = now or datetime.now(timezone.utc)
+    timestamp = now.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+    return f"{LINEAGE_TAG} {timestamp} {json.dumps(payload, separators=(',', ':'))}"
@@ -0,0 +1,73 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""Training progress lines read by the KAITO workspace controller.
+
+The trainer logs one line per logging step on stdout:
+
+    KAITO_TUNING_PROGRESS <RFC3339 timestamp> <JSON payload>
+
+The controller tails the log of the tuning pod and copies the last line into
+status.tuning of the Workspace.
+"""
+
+import json
+import math
+from datetime import datetime, timezone
+
+PROGRESS_TAG = "KAITO_TUNING_PROGRESS"
+
+
+def _finite(value):
+    if isinstance(value, (int, float)) and not isinstance(value, bool) and math.isfinite(value):
+        return value
+    return None
+
+
+def estimate_remaining_seconds(step: int, max_steps: int, elapsed_seconds: float):
+    """Return the seconds left at the average pace so far, or None before the first step."""
+    if step <= 0 or max_steps <= 0 or elapsed_seconds <= 0:
+        return None
+    return max(0, round(elapsed_seconds / step * max(0, max_steps - step)))
+
+
+def gpu_utilization(torch_module):
+    """Return the average utilization of the visible GPUs in percent, or None if unknown."""
+    try:
+        if not torch_module.cuda.is_available():
+            return None
+        values = [torch_module.cuda.utilization(i) for i in range(torch_module.cuda.device_count())]
+    except Exception:  # utilization needs pynvml, which may be missing
+        return None
+    if not values:
+        return None
+    return round(sum(values) / len(values))
+
+
+def progress_payload(epoch, step, max_steps, loss, eta_seconds, gpu_percent) -> dict:
+    payload = {
+        "epoch": _finite(epoch),
+        "step": step,
+        "total_steps": max_steps,
+        "loss": _finite(loss),
+        "eta_seconds": eta_seconds,
+        "gpu_utilization": gpu_percent,
+    }
+    return {k: v for k, v in payload.items() if v is not None}
+
+
+def progress_line(payload: dict, now: datetime | None = None) -> str:
+    now = now or datetime.now(timezone.utc)
+    timestamp = now.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+    return f"{PROGRESS_TAG} {timestamp} {json.dumps(payload, allow_nan=False, separators=(',', ':'))}"
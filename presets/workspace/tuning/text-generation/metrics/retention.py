@@ -0,0 +1,61 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+"""Retention of the intermediate checkpoints of a tuning run.
+
+The trainer saves checkpoints as checkpoint-<step> directories in the output directory.
+Once the adapter is saved, only the last KEEP_LAST_CHECKPOINTS of them are kept, so the
+output volume or image does not grow with every run. All checkpoints are kept when the
+variable is not set.
+"""
+
+import os
+import re
+import shutil
+
+KEEP_LAST_CHECKPOINTS_ENV = "KEEP_LAST_CHECKPOINTS"
+
+_CHECKPOINT_RE = re.compile(r"^checkpoint-(\d+)$")
+
+
+def keep_last_checkpoints(environ=os.environ):
+    """Return the number of checkpoints to keep, or None to keep all of them."""
+    value = environ.get(KEEP_LAST_CHECKPOINTS_ENV, "")
+    if not value:
+        return None
+    keep = int(value)
+    if keep < 0:
+        raise ValueError(f"{KEEP_LAST_CHECKPOINTS_ENV} must not be negative, got {keep}")
+    return keep
+
+
+def prune_checkpoints(output_dir: str, keep: int) -> list:
+    """Delete all but the last keep checkpoint directories of output_dir.
+
+    Checkpoints are ordered by their step. Returns the names of the deleted directories.
+    """
+    if not os.path.isdir(output_dir):
+        return []
+    checkpoints = []
+    for name in os.listdir(output_dir):
+        match = _CHECKPOINT_RE.match(name)
+        path = os.path.join(output_dir, name)
+        # Symlinks are skipped so that nothing outside the output directory is deleted.
+        if match and os.path.isdir(path) and not os.path.islink(path):
+            checkpoints.append((int(match.group(1)), name))
+    checkpoints.sort()
+    stale = checkpoints[: max(len(checkpoints) - keep, 0)]
+    for _, name in stale:
+        shutil.rmtree(os.path.join(output_dir, name))
+    return [name for _, name in stale]
@@ -0,0 +1,54 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import hashlib
+import json
+from datetime import datetime, timezone
+
+from lineage import digest_path, lineage_line, lineage_payload
+
+
+def test_digest_path_file(tmp_path):
+    data = tmp_path / "train.jsonl"
+    data.write_bytes(b'{"text": "hello"}\n')
+    assert digest_path(str(data)) == "sha256:" + hashlib.sha256(b'{"text": "hello"}\n').hexdigest()
+    assert digest_path(str(tmp_path / "missing")) is None
+
+
+def test_digest_path_directory(tmp_path):
+    (tmp_path / "adapter_config.json").write_text("{}")
+    (tmp_path / "adapter_model.safetensors").write_bytes(b"weights")
+    digest = digest_path(str(tmp_path), recursive=False)
+    assert digest.startswith("sha256:")
+
+    # Checkpoints, the completion marker and write order do not change the digest of the adapter.
+    (tmp_path / "checkpoint-10").mkdir()
+    (tmp_path / "checkpoint-10" / "optimizer.pt").write_bytes(b"state")
+    (tmp_path / "fine_tuning_completed.txt").write_text("done")
+    assert digest_path(str(tmp_path), recursive=False, exclude=("fine_tuning_completed.txt",)) == digest
+    assert digest_path(str(tmp_path)) != digest
+
+    (tmp_path / "adapter_model.safetensors").write_bytes(b"other weights")
+    assert digest_path(str(tmp_path), recursive=False, exclude=("fine_tuning_completed.txt",)) != digest
+
+
+def test_lineage_line():
+    now = datetime(2026, 1, 2, 3, 4, 5, tzinfo=timezone.utc)
+    payload = lineage_payload("sha256:aa", None, "sha256:bb")
+    assert payload == {"dataset_digest": "sha256:aa", "output_digest": "sha256:bb"}
+
+    tag, timestamp, body = lineage_line(payload, now).split(" ", 2)
+    assert tag == "KAITO_TUNING_LINEAGE"
+    assert timestamp == "2026-01-02T03:04:05Z"
+    assert json.loads(body) == payload
@@ -0,0 +1,74 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+import json
+from datetime import datetime, timezone
+
+from progress import (
+    estimate_remaining_seconds,
+    gpu_utilization,
+    progress_line,
+    progress_payload,
+)
+
+
+def test_estimate_remaining_seconds():
+    assert estimate_remaining_seconds(10, 100, 60.0) == 540
+    assert estimate_remaining_seconds(100, 100, 600.0) == 0
+    assert estimate_remaining_seconds(0, 100, 60.0) is None
+    assert estimate_remaining_seconds(10, 0, 60.0) is None
+
+
+def test_progress_payload_drops_unknown_values():
+    payload = progress_payload(1.25, 10, 100, float("nan"), None, 87)
+    assert payload == {"epoch": 1.25, "step": 10, "total_steps": 100, "gpu_utilization": 87}
+
+
+def test_progress_line():
+    now = datetime(2026, 1, 2, 3, 4, 5, tzinfo=timezone.utc)
+    line = progress_line(progress_payload(0.5, 5, 20, 1.5, 30, None), now)
+    tag, timestamp, payload = line.split(" ", 2)
+    assert tag == "KAITO_TUNING_PROGRESS"
+    assert timestamp == "2026-01-02T03:04:05Z"
+    assert json.loads(payload) == {"epoch": 0.5, "step": 5, "total_steps": 20, "loss": 1.5, "eta_seconds": 30}
+
+
+def test_gpu_utilization():
+    class Cuda:
+        def __init__(self, values):
+            self.values = values
+
+        def is_available(self):
+            return bool(self.values)
+
+        def device_count(self):
+            return len(self.values)
+
+        def utilization(self, i):
+            return self.values[i]
+
+    class Torch:
+        def __init__(self, values):
+            self.cuda = Cuda(values)
+
+    assert gpu_utilization(Torch([80, 90])) == 85
+    assert gpu_utilization(Torch([])) is None
+
+    class Broken(Cuda):
+        def utilization(self, i):
+            raise ModuleNotFoundError("pynvml")
+
+    broken = Torch([1])
+    broken.cuda = Broken([1])
+    assert gpu_utilization(broken) is None
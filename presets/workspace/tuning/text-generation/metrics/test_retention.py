@@ -0,0 +1,56 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+
+import pytest
+from retention import keep_last_checkpoints, prune_checkpoints
+
+
+def test_keep_last_checkpoints():
+    assert keep_last_checkpoints({}) is None
+    assert keep_last_checkpoints({"KEEP_LAST_CHECKPOINTS": ""}) is None
+    assert keep_last_checkpoints({"KEEP_LAST_CHECKPOINTS": "2"}) == 2
+    with pytest.raises(ValueError):
+        keep_last_checkpoints({"KEEP_LAST_CHECKPOINTS": "-1"})
+
+
+def test_prune_checkpoints(tmp_path):
+    for step in (10, 100, 20):
+        (tmp_path / f"checkpoint-{step}").mkdir()
+        (tmp_path / f"checkpoint-{step}" / "optimizer.pt").write_bytes(b"state")
+    (tmp_path / "adapter_model.safetensors").write_bytes(b"weights")
+    (tmp_path / "checkpoint-notes").mkdir()
+
+    # Checkpoints are ordered by step, not by name.
+    assert prune_checkpoints(str(tmp_path), 1) == ["checkpoint-10", "checkpoint-20"]
+    assert sorted(p.name for p in tmp_path.iterdir()) == [
+        "adapter_model.safetensors",
+        "checkpoint-100",
+        "checkpoint-notes",
+    ]
+
+    assert prune_checkpoints(str(tmp_path), 0) == ["checkpoint-100"]
+    assert (tmp_path / "adapter_model.safetensors").exists()
+
+
+def test_prune_checkpoints_skips_symlinks(tmp_path):
+    outside = tmp_path / "outside"
+    outside.mkdir()
+    output = tmp_path / "output"
+    output.mkdir()
+    (output / "checkpoint-5").symlink_to(outside)
+
+    assert prune_checkpoints(str(output), 0) == []
+    assert outside.exists()
+    assert prune_checkpoints(str(tmp_path / "missing"), 0) == []
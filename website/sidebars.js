@@ -44,6 +44,7 @@ const sidebars = {
                 'workspace',
                 'multi-node-inference',
                 'model-mirror-streaming',
+                'batch-inference',
                 'memory-estimator',
                 'keda-autoscaler-inference',
                 'multi-gpu-instance',
@@ -76,6 +77,7 @@ const sidebars = {
                 'aikit',
                 'gateway-api-inference-extension',
                 'prefill-decode-disaggregation',
+                'go-client',
             ],
         },
         {
@@ -85,6 +87,7 @@ const sidebars = {
             items: [
                 'monitoring',
                 'kaito-oom-prevention',
+                'admission-policies',
             ],
         },
         {